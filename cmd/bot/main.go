@@ -2,14 +2,31 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/massimo-ua/quill/internal/config"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
 	"github.com/massimo-ua/quill/internal/providers/chat/slack"
+	"github.com/massimo-ua/quill/internal/providers/coordination"
+	"github.com/massimo-ua/quill/internal/providers/docstore/github"
+	"github.com/massimo-ua/quill/internal/providers/featureflags"
+	"github.com/massimo-ua/quill/internal/providers/llm"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/massimo-ua/quill/internal/providers/storage/redis"
+	"github.com/massimo-ua/quill/internal/providers/storage/sqlstore"
 )
 
+// apiShutdownTimeout is how long the REST API waits for the requests in flight when the bot stops
+const apiShutdownTimeout = 10 * time.Second
+
 func main() {
 	// Create a context that will be canceled on SIGINT or SIGTERM
 	ctx, cancel := context.WithCancel(context.Background())
@@ -24,24 +41,203 @@ func main() {
 		cancel()
 	}()
 
-	// Get configuration from environment variables
-	botToken := os.Getenv("SLACK_BOT_TOKEN")
-	appToken := os.Getenv("SLACK_APP_TOKEN")
-	signingSecret := os.Getenv("SLACK_SIGNING_SECRET")
+	// Load the configuration file, or the Slack settings from environment variables without one.
+	// Every invalid setting is reported at once.
+	configPath := flag.String("config", os.Getenv("QUILL_CONFIG"), "configuration file (default $QUILL_CONFIG), see quillctl config init")
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+	} else {
+		cfg, err = config.FromEnv()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Open the state store with the bundled driver of its dialect, and apply the pending schema migrations when
+	// the configuration asks to. It keeps the processing state in place of the in-memory stores.
+	var stateStore *sqlstore.StateStore
+	if cfg.Storage != nil {
+		dialect, err := sqlstore.ParseDialect(cfg.Storage.Dialect)
+		if err != nil {
+			log.Fatalf("Invalid storage dialect: %v", err)
+		}
+		db, err := sqlstore.Open(dialect, cfg.Storage.DSN)
+		if err != nil {
+			log.Fatalf("Failed to open the state store: %v", err)
+		}
+		defer db.Close()
+		stateStore, err = sqlstore.NewStateStore(db, dialect)
+		if err != nil {
+			log.Fatalf("Failed to create the state store: %v", err)
+		}
+
+		if cfg.Storage.AutoMigrate {
+			migrator, err := sqlstore.NewMigrator(db, dialect)
+			if err != nil {
+				log.Fatalf("Failed to load the schema migrations: %v", err)
+			}
+			applied, err := migrator.Up(ctx)
+			if err != nil {
+				log.Fatalf("Failed to migrate the state store: %v", err)
+			}
+			log.Printf("Applied %d schema migrations", len(applied))
+		}
+	}
+
+	// Elect the replica connecting to Slack when the configuration asks to, the others stand by. The leader gives up
+	// its lock when the bot stops.
+	var coordinator ports.WorkCoordinator
+	var leader *coordination.PostgresLeader
+	if cfg.Coordination != nil {
+		lockDB, err := sqlstore.Open(sqlstore.Postgres, cfg.LeaderDSN())
+		if err != nil {
+			log.Fatalf("Failed to open the leader election database: %v", err)
+		}
+		defer lockDB.Close()
+		leader, err = coordination.NewPostgresLeader(lockDB, *cfg.LeaderConfig())
+		if err != nil {
+			log.Fatalf("Failed to create the leader election: %v", err)
+		}
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			_ = leader.Run(ctx)
+		}()
+		defer func() { <-stopped }()
+		coordinator = leader
+	}
 
-	if botToken == "" || appToken == "" {
-		log.Fatal("SLACK_BOT_TOKEN and SLACK_APP_TOKEN must be set")
+	// Keep the handled events, the profiles of message authors, the thread mappings and where backfilling each
+	// channel resumes in Redis when the configuration asks to, so replicas share them and restarts keep them,
+	// otherwise in memory
+	slackConfig := cfg.SlackConfig()
+	if redisConfig := cfg.RedisConfig(); redisConfig != nil {
+		cache, err := redis.NewClient(redisConfig)
+		if err != nil {
+			log.Fatalf("Invalid Redis configuration: %v", err)
+		}
+		defer cache.Close()
+		if err := cache.Ping(ctx); err != nil {
+			log.Fatalf("Failed to connect to the cache: %v", err)
+		}
+		slackConfig.Dedup = redis.NewDedupStore(cache)
+		slackConfig.UserProfiles = redis.NewUserProfileCache(cache)
+		slackConfig.ThreadMappings = redis.NewThreadMappingCache(cache)
+		slackConfig.Cursors = redis.NewBackfillCursorStore(cache)
+	} else {
+		var profileTTL time.Duration
+		if cfg.Cache != nil {
+			profileTTL = cfg.Cache.ProfileTTL
+		}
+		slackConfig.Dedup = memory.NewDedupStore(0)
+		slackConfig.UserProfiles = memory.NewUserProfileCache(profileTTL)
+		slackConfig.ThreadMappings = memory.NewThreadMappingCache()
 	}
 
 	// Create Slack client
-	config := slack.NewConfig(botToken, appToken, signingSecret, true)
-	factory := slack.NewFactory(config)
+	factory := slack.NewFactory(slackConfig)
 	
 	chatProvider, err := factory.CreateChatProvider()
 	if err != nil {
 		log.Fatalf("Failed to create chat provider: %v", err)
 	}
 
+	// Document the messages with the AI agent in the documentation repository when the configuration has both,
+	// otherwise the received messages are only logged
+	var bot *services.BotService
+	if cfg.LLM != nil && cfg.GitHub != nil {
+		ai, err := llm.NewLLMProvider(ctx, cfg.LLMConfig())
+		if err != nil {
+			log.Fatalf("Failed to create the AI agent: %v", err)
+		}
+		docStore, err := github.NewGitHubDocumentStoreProvider(cfg.GitHubConfig())
+		if err != nil {
+			log.Fatalf("Failed to create the document store: %v", err)
+		}
+		repositories, err := github.NewDocumentStoreFactory(cfg.GitHubConfig())
+		if err != nil {
+			log.Fatalf("Failed to create the document store factory: %v", err)
+		}
+
+		var flagSource ports.FeatureFlagSource
+		if sourceConfig := cfg.FeatureFlagSourceConfig(); sourceConfig != nil {
+			client, err := featureflags.NewClient(sourceConfig)
+			if err != nil {
+				log.Fatalf("Failed to create the feature flag source: %v", err)
+			}
+			flagSource = client
+		}
+		flags := services.NewFeatureFlagService(cfg.FeatureFlags(), flagSource, 0)
+
+		state := memoryStores(slackConfig.UserProfiles)
+		if stateStore != nil {
+			state = stateStores(stateStore, slackConfig.UserProfiles)
+		}
+		wired := newBotServices(chatProvider, ai, services.NewDocStoreResolver(docStore, repositories), flags, coordinator, state)
+		bot = wired.bot
+
+		// The reference graph is kept in memory, record the documents in it before any is written
+		recorded, err := wired.reconciler.RebuildGraph(ctx)
+		if err != nil {
+			log.Printf("Failed to rebuild the reference graph: %v", err)
+		}
+		log.Printf("Recorded %d documents in the reference graph", recorded)
+
+		// Post the batched confirmations and those held during quiet hours
+		go func() {
+			if err := wired.notifications.Run(ctx, 0); err != nil && ctx.Err() == nil {
+				log.Printf("Confirmations stopped: %v", err)
+			}
+		}()
+
+		// Serve the REST API when the configuration asks to, it stops taking requests when the bot stops
+		if apiConfig := cfg.APIConfig(); apiConfig != nil {
+			server, err := newAPIServer(apiConfig, wired, stateStore)
+			if err != nil {
+				log.Fatalf("Failed to create the REST API: %v", err)
+			}
+			httpServer := &http.Server{Addr: cfg.APIListen(), Handler: server.Handler()}
+			go func() {
+				log.Printf("Serving the REST API on %s", httpServer.Addr)
+				if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Fatalf("REST API stopped: %v", err)
+				}
+			}()
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), apiShutdownTimeout)
+				defer cancel()
+				if err := httpServer.Shutdown(shutdownCtx); err != nil {
+					log.Printf("Failed to stop the REST API: %v", err)
+				}
+			}()
+		}
+	} else {
+		log.Println("No llm or github section configured, received messages are logged but not documented")
+	}
+
+	// Slack delivers each event to one of the connected replicas, so only the leader connects: a standby waits until
+	// it is elected, and a leader losing its lock stops so it comes back as a standby
+	if leader != nil {
+		log.Println("Standing by until elected leader")
+		select {
+		case <-leader.Elected():
+		case <-ctx.Done():
+			return
+		}
+		go func() {
+			select {
+			case <-leader.Deposed():
+				log.Println("Lost leadership, shutting down")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
 	// Start listening for messages
 	messageCh, err := chatProvider.ListenForMessages(ctx)
 	if err != nil {
@@ -55,8 +251,13 @@ func main() {
 			log.Println("Context canceled, shutting down...")
 			return
 		case msg := <-messageCh:
+			if bot != nil {
+				if err := bot.ProcessMessage(ctx, msg); err != nil {
+					log.Printf("Failed to process message %s: %v", msg.ID(), err)
+				}
+				continue
+			}
 			log.Printf("Received message from %s: %s", msg.Sender(), msg.Content().Text())
-			// Here we would normally forward to the domain services for processing
 		}
 	}
 }
\ No newline at end of file
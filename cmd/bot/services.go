@@ -0,0 +1,221 @@
+package main
+
+import (
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/api"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/massimo-ua/quill/internal/providers/storage/sqlstore"
+)
+
+// stores are the repositories and queues the domain services keep their state in
+type stores struct {
+	messages          ports.MessageRepository
+	threads           ports.ThreadRepository
+	deadLetters       ports.DeadLetterQueue
+	audit             ports.AuditLog
+	outbox            ports.ReplyOutbox
+	pendingDuplicates ports.PendingDuplicateStore
+	pendingUpdates    ports.PendingUpdateStore
+	approvalRequests  ports.ApprovalRequestStore
+	profiles          ports.UserProfileCache
+}
+
+// memoryStores keeps the whole state in memory, it is lost when the bot stops. The profiles of message authors
+// are the cache of the chat provider.
+func memoryStores(profiles ports.UserProfileCache) stores {
+	return stores{
+		messages:          memory.NewMessageRepository(),
+		threads:           memory.NewThreadRepository(),
+		deadLetters:       memory.NewDeadLetterQueue(),
+		audit:             memory.NewAuditLog(),
+		outbox:            memory.NewReplyOutbox(),
+		pendingDuplicates: memory.NewPendingDuplicateStore(),
+		pendingUpdates:    memory.NewPendingUpdateStore(),
+		approvalRequests:  memory.NewApprovalRequestStore(),
+		profiles:          profiles,
+	}
+}
+
+// stateStores keeps the state the state store holds in its database, and the rest in memory
+func stateStores(state *sqlstore.StateStore, profiles ports.UserProfileCache) stores {
+	return stores{
+		messages:          state.Messages(),
+		threads:           state.Threads(),
+		deadLetters:       state.DeadLetters(),
+		audit:             state.AuditLog(),
+		outbox:            state.ReplyOutbox(),
+		pendingDuplicates: state.PendingDuplicates(),
+		pendingUpdates:    state.PendingUpdates(),
+		approvalRequests:  state.ApprovalRequests(),
+		profiles:          profiles,
+	}
+}
+
+// botServices are the domain services the bot processes messages with
+type botServices struct {
+	bot           *services.BotService
+	notifications *services.NotificationService
+	reconciler    *services.ReconciliationService
+	docs          *services.DocumentationService
+	projects      *services.ProjectService
+	stats         *services.StatsService
+	calibration   *services.CalibrationService
+	erasure       *services.ErasureService
+	reprocess     *services.ReprocessService
+	feeds         *services.FeedService
+	events        *services.EventBus
+}
+
+// newBotServices wires the domain services together. The coordinator is optional, with it each replica processes
+// and reminds the channels it owns only.
+func newBotServices(
+	chat ports.ChatAccessProvider,
+	ai ports.AiAgentProvider,
+	docStores *services.DocStoreResolver,
+	flags *services.FeatureFlagService,
+	coordinator ports.WorkCoordinator,
+	state stores,
+) *botServices {
+	var timeouts services.StageTimeouts
+	projectRepo := memory.NewProjectRepository()
+	index := memory.NewDocumentIndex()
+	corrections := memory.NewCorrectionStore()
+
+	projects := services.NewProjectService(docStores, projectRepo)
+	var glossary *services.GlossaryService
+	if definer, ok := ai.(ports.TermDefiner); ok {
+		glossary = services.NewGlossaryService(definer)
+	}
+	var images *services.ImageAnalysis
+	if describer, ok := ai.(ports.ImageDescriber); ok {
+		if fetcher, ok := chat.(ports.AttachmentFetcher); ok {
+			images = services.NewImageAnalysis(describer, fetcher, domain.AssetLimits{})
+		}
+	}
+	detector, _ := ai.(ports.RiskDetector)
+	canvases, _ := chat.(ports.CanvasPublisher)
+	graph := services.NewReferenceGraphService()
+	threads := services.NewThreadService(state.threads, ai)
+	docs := services.NewDocumentationService(docStores, projectRepo, ai, graph, index, nil, images, glossary, nil, threads, domain.DefaultDeploymentPatterns(), services.NewRiskRegisterService(detector), canvases)
+
+	commands := services.NewCommandService(chat)
+	services.RegisterProjectCommands(commands, projects)
+	services.RegisterRelationCommands(commands, docs)
+	services.RegisterSharingCommands(commands, docs, services.NewSharedIndex(index, projectRepo))
+	services.RegisterKnowledgeCommands(commands, services.NewKnowledgeService(docs, index, state.messages, ai), docs, flags)
+	services.RegisterFeatureFlagCommands(commands, flags, docs)
+	stats := services.NewStatsService(state.messages, index)
+	services.RegisterStatsCommands(commands, stats)
+	services.RegisterTagCommands(commands, docs)
+	services.RegisterRiskCommands(commands, docs)
+	feedback := services.NewFeedbackService(corrections, state.messages, docs)
+	services.RegisterFeedbackCommands(commands, feedback)
+
+	events := services.NewEventBus()
+	tracker := services.NewMessageTracker(state.messages, 0, events)
+	services.RegisterStatusCommands(commands, tracker)
+	moderator, _ := ai.(ports.ContentModerator)
+	moderation := services.NewModerationService(moderator, domain.ModerationPolicy{}, memory.NewModerationQueue(), state.audit)
+	notifications := services.NewNotificationService(chat, projects, state.outbox, timeouts)
+	triageQueue := memory.NewTriageQueue()
+	triage := services.NewTriageService(triageQueue, chat, coordinator, threads, notifications)
+	snoozes := services.NewSnoozeService(memory.NewSnoozeStore(), state.messages)
+	services.RegisterSnoozeCommands(commands, snoozes)
+	services.RegisterOptOut(chat, snoozes)
+	writer, _ := ai.(ports.PostmortemWriter)
+	incidents := services.NewIncidentService(memory.NewIncidentStore(), state.messages, docs, tracker, writer)
+	services.RegisterIncidentCommands(commands, incidents)
+	secretary, _ := ai.(ports.MeetingNotesWriter)
+	notes := services.NewMeetingNotesService(memory.NewNotesSessionStore(), state.messages, docs, tracker, secretary)
+	services.RegisterMeetingNotesCommands(commands, notes)
+	okrs := services.NewOKRService(memory.NewKeyResultUpdateStore(), docs)
+	services.RegisterOKRCommands(commands, okrs)
+	standupStore := memory.NewStandupStore()
+	standups := services.NewStandupService(standupStore, projectRepo, chat, state.messages, docs, tracker, coordinator)
+
+	bot := services.NewBotService(
+		chat,
+		docStores.Default(),
+		ai,
+		projects,
+		docs,
+		services.NewReferenceResolver(index, state.messages, ai, flags),
+		services.NewDuplicateDetector(index, ai),
+		commands,
+		tracker,
+		feedback,
+		timeouts,
+		coordinator,
+		moderation,
+		triage,
+		snoozes,
+		threads,
+		incidents,
+		notes,
+		standups,
+		okrs,
+		services.NewAuthorizationService(docs, state.audit, flags, state.approvalRequests),
+		notifications,
+		state.pendingDuplicates,
+		state.pendingUpdates,
+	)
+	services.RegisterModerationCommands(commands, moderation, bot)
+	services.RegisterTriage(chat, bot)
+	reviews := services.NewDocumentReviewService(docs, index, state.messages, projectRepo, chat, state.audit, coordinator)
+	services.RegisterOwnershipCommands(commands, reviews)
+	services.RegisterDocumentReview(chat, reviews)
+	services.RegisterHome(chat, services.NewHomeService(state.messages, projectRepo, index, graph, docs, bot))
+
+	erasure := services.NewErasureService(state.messages, corrections, state.audit, docs, index)
+	services.RegisterIncidentErasure(erasure)
+	services.RegisterMeetingNotesErasure(erasure)
+	services.RegisterDeadLetterErasure(erasure, state.deadLetters)
+	services.RegisterThreadErasure(erasure, state.threads)
+	services.RegisterUserProfileErasure(erasure, state.profiles)
+	services.RegisterTriageErasure(erasure, triageQueue)
+	services.RegisterStandupErasure(erasure, standupStore)
+	services.RegisterPendingDuplicateErasure(erasure, state.pendingDuplicates)
+	services.RegisterPendingUpdateErasure(erasure, state.pendingUpdates)
+	services.RegisterApprovalRequestErasure(erasure, state.approvalRequests)
+
+	return &botServices{
+		bot:           bot,
+		notifications: notifications,
+		reconciler:    services.NewReconciliationService(docStores, index, graph, projectRepo, ai, chat),
+		docs:          docs,
+		projects:      projects,
+		stats:         stats,
+		calibration:   services.NewCalibrationService(state.messages, corrections),
+		erasure:       erasure,
+		reprocess:     services.NewReprocessService(state.messages, bot, docs, state.audit),
+		feeds:         services.NewFeedService(index, projectRepo),
+		events:        events,
+	}
+}
+
+// newAPIServer creates the REST API of the services. Without a state store the state cannot be backed up and the
+// schema cannot be migrated through it.
+func newAPIServer(config *api.Config, wired *botServices, state *sqlstore.StateStore) (*api.Server, error) {
+	var backups api.StateBackup
+	var migrator api.SchemaMigrator
+	if state != nil {
+		backups = state
+		migrator = state.Migrator()
+	}
+	return api.NewServer(
+		config,
+		wired.stats,
+		wired.calibration,
+		wired.erasure,
+		wired.docs,
+		wired.reprocess,
+		wired.feeds,
+		wired.projects,
+		wired.events,
+		nil,
+		backups,
+		migrator,
+	)
+}
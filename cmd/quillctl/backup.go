@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/api"
+)
+
+func runBackup(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	apiURL := fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API")
+	token := fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)")
+	output := fs.String("out", "quill-"+time.Now().UTC().Format("20060102-150405")+".snapshot.json", "file to write the snapshot to, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *token == "" {
+		return errors.New("-token or QUILL_API_TOKEN is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	resp, err := requestState(ctx, *apiURL, *token, http.MethodGet, "backup", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if *output == "-" {
+		_, err := io.Copy(out, resp.Body)
+		return err
+	}
+
+	// The snapshot is written next to the file and renamed, so an interrupted backup leaves no partial file
+	tmp, err := os.CreateTemp(filepath.Dir(*output), ".quill-snapshot-*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	size, err := io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), *output); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "Backed up the state to %s (%d bytes)\n", *output, size)
+	return err
+}
+
+func runRestore(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	apiURL := fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API")
+	token := fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)")
+	confirm := fs.Bool("yes", false, "confirm that the snapshot replaces the whole state of the bot")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || strings.TrimSpace(fs.Arg(0)) == "" {
+		return errors.New("usage: quillctl restore [flags] <snapshot-file>")
+	}
+	if *token == "" {
+		return errors.New("-token or QUILL_API_TOKEN is required")
+	}
+	if !*confirm {
+		return errors.New("restoring replaces every message, thread, dead letter and audit entry the bot keeps, pass -yes to go ahead")
+	}
+
+	snapshot, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer snapshot.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	resp, err := requestState(ctx, *apiURL, *token, http.MethodPost, "restore", snapshot)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var restored api.RestoreResponse
+	if err := json.NewDecoder(resp.Body).Decode(&restored); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	tables := make([]string, 0, len(restored.Tables))
+	for table := range restored.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	fmt.Fprintf(out, "Restored the state from %s\n\n", fs.Arg(0))
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS")
+	for _, table := range tables {
+		fmt.Fprintf(w, "%s\t%d\n", table, restored.Tables[table])
+	}
+	return w.Flush()
+}
+
+// requestState calls the backup or restore endpoint of the bot's REST API, and returns the response when it
+// succeeded
+func requestState(ctx context.Context, apiURL, token, method, endpoint string, body io.Reader) (*http.Response, error) {
+	target, err := url.JoinPath(apiURL, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Snapshots hold every message the bot kept, which can take a while to copy
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the API: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
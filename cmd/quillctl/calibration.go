@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/api"
+)
+
+func runCalibration(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("calibration", flag.ContinueOnError)
+	apiURL := fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API")
+	token := fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)")
+	bins := fs.Int("bins", 10, "number of confidence bins in the calibration report")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		return errors.New("-token or QUILL_API_TOKEN is required")
+	}
+	if *bins <= 0 {
+		return errors.New("-bins must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := fetchCalibration(ctx, *apiURL, *token, *bins)
+	if err != nil {
+		return err
+	}
+	return writeCalibrationReport(out, report)
+}
+
+// fetchCalibration reads the calibration report of the models from the bot's REST API
+func fetchCalibration(ctx context.Context, apiURL, token string, bins int) (*api.CalibrationResponse, error) {
+	endpoint, err := url.JoinPath(apiURL, "calibration")
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?bins="+strconv.Itoa(bins), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calibration: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var report api.CalibrationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode calibration: %w", err)
+	}
+	return &report, nil
+}
+
+// writeCalibrationReport prints the override rate and suggested threshold of every model, and its confidence bins
+func writeCalibrationReport(out io.Writer, report *api.CalibrationResponse) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "MODEL\tANALYZED\tOVERRIDDEN\tOVERRIDE RATE\tMEAN CONFIDENCE\tSUGGESTED THRESHOLD (<= %.0f%% OVERRIDES)\n", report.MaxOverrideRate*100)
+	for _, m := range report.Models {
+		threshold := "-"
+		if m.SuggestedThreshold != nil {
+			threshold = fmt.Sprintf("%.2f", *m.SuggestedThreshold)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.3f\t%.3f\t%s\n", m.Model, m.Analyzed, m.Overridden, m.OverrideRate, m.Confidence, threshold)
+	}
+
+	for _, m := range report.Models {
+		fmt.Fprintf(w, "\n== %s\n", m.Model)
+		fmt.Fprintln(w, "CONFIDENCE\tCOUNT\tMEAN CONFIDENCE\tOVERRIDE RATE")
+		for _, bin := range m.Bins {
+			fmt.Fprintf(w, "%.2f-%.2f\t%d\t%.3f\t%.3f\n", bin.Lower, bin.Upper, bin.Count, bin.Confidence, bin.OverrideRate)
+		}
+	}
+	return w.Flush()
+}
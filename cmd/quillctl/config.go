@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/massimo-ua/quill/internal/config"
+)
+
+const configUsage = `Usage: quillctl config <command> [flags]
+
+Commands:
+  init   write the documented example configuration
+  check  validate a configuration file and list every problem
+`
+
+func runConfig(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing config command\n\n" + configUsage)
+	}
+	switch args[0] {
+	case "init":
+		return runConfigInit(args[1:], out)
+	case "check":
+		return runConfigCheck(args[1:], out)
+	default:
+		return fmt.Errorf("unknown config command %q\n\n%s", args[0], configUsage)
+	}
+}
+
+func runConfigInit(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("config init", flag.ContinueOnError)
+	path := fs.String("out", "config.example.yaml", "file to write the example configuration to, - for standard output")
+	force := fs.Bool("force", false, "overwrite the file when it exists")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "-" {
+		_, err := out.Write(config.Example)
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !*force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(*path, flags, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s exists, pass -force to overwrite it", *path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(config.Example); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Wrote %s, copy it to config.yaml and start the bot with -config config.yaml\n", *path)
+	return nil
+}
+
+func runConfigCheck(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("config check", flag.ContinueOnError)
+	path := fs.String("config", envOr("QUILL_CONFIG", "config.yaml"), "configuration file to check")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*path)
+	if err != nil {
+		return err
+	}
+
+	sections := []string{"slack"}
+	if cfg.LLM != nil {
+		sections = append(sections, "llm ("+cfg.LLM.Provider+")")
+	}
+	if cfg.GitHub != nil {
+		sections = append(sections, "github")
+	}
+	if cfg.API != nil {
+		sections = append(sections, "api")
+	}
+	if len(cfg.Features) > 0 || cfg.FeatureFlagSource != nil {
+		sections = append(sections, "features")
+	}
+	fmt.Fprintf(out, "%s is valid, configured: %v\n", *path, sections)
+	return nil
+}
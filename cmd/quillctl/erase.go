@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/api"
+)
+
+func runErase(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("erase", flag.ContinueOnError)
+	apiURL := fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API")
+	token := fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)")
+	user := fs.String("user", "", "identity of the person, like a Slack user ID")
+	pseudonymize := fs.Bool("pseudonymize", false, "replace the person's identity with a pseudonym instead of erasing their data")
+	requestedBy := fs.String("by", os.Getenv("USER"), "who asks for the erasure, recorded in the audit log")
+	asJSON := fs.Bool("json", false, "print the deletion report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		return errors.New("-token or QUILL_API_TOKEN is required")
+	}
+	if strings.TrimSpace(*user) == "" {
+		return errors.New("-user is required")
+	}
+	mode := "erase"
+	if *pseudonymize {
+		mode = "pseudonymize"
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := requestErasure(ctx, *apiURL, *token, api.ErasureRequest{Identity: *user, Mode: mode, RequestedBy: *requestedBy})
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return writeDeletionReport(out, report)
+}
+
+// requestErasure asks the bot's REST API to erase or pseudonymize the data of a person
+func requestErasure(ctx context.Context, apiURL, token string, erasure api.ErasureRequest) (*api.DeletionReportResponse, error) {
+	endpoint, err := url.JoinPath(apiURL, "erasures")
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL: %w", err)
+	}
+	body, err := json.Marshal(erasure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	// Erasing rewrites every document naming the person, which can take a while on large repositories
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request erasure: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var report api.DeletionReportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode deletion report: %w", err)
+	}
+	return &report, nil
+}
+
+// writeDeletionReport prints what the erasure changed
+func writeDeletionReport(out io.Writer, report *api.DeletionReportResponse) error {
+	verb := "Erased"
+	if report.Mode == "pseudonymize" {
+		verb = "Pseudonymized"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s the data of %s (pseudonym %s) at %s\n", verb, report.Identity, report.Pseudonym, report.CompletedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Messages:      %d\n", report.Messages)
+	fmt.Fprintf(&b, "Corrections:   %d\n", report.Corrections)
+	fmt.Fprintf(&b, "Audit entries: %d\n", report.AuditEntries)
+	fmt.Fprintf(&b, "Documents:     %d\n", len(report.Documents))
+	for _, path := range report.Documents {
+		fmt.Fprintf(&b, "  %s\n", path)
+	}
+	if len(report.ReadOnlyDocuments) > 0 {
+		fmt.Fprintf(&b, "Left as they are, their project is archived: %d\n", len(report.ReadOnlyDocuments))
+		for _, path := range report.ReadOnlyDocuments {
+			fmt.Fprintf(&b, "  %s\n", path)
+		}
+	}
+	stores := make([]string, 0, len(report.Records))
+	for store := range report.Records {
+		stores = append(stores, store)
+	}
+	sort.Strings(stores)
+	for _, store := range stores {
+		fmt.Fprintf(&b, "%-14s %d\n", store+":", report.Records[store])
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/llm"
+	"github.com/massimo-ua/quill/internal/providers/llm/ollama"
+	"github.com/massimo-ua/quill/internal/providers/llm/openai"
+)
+
+// providerFlags collects the repeated -provider flag
+type providerFlags []string
+
+func (p *providerFlags) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *providerFlags) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+func runEval(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	dataset := fs.String("dataset", "", "JSON Lines file of labeled messages: {\"content\": ..., \"type\": ..., \"category\": ...}")
+	bins := fs.Int("bins", 10, "number of confidence bins in the calibration report")
+	ollamaURL := fs.String("ollama-url", envOr("OLLAMA_URL", "http://localhost:11434"), "Ollama server URL")
+	var providers providerFlags
+	fs.Var(&providers, "provider", "provider to evaluate as type:model, like openai:gpt-4 or ollama:llama3 (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dataset == "" {
+		return errors.New("-dataset is required")
+	}
+	if len(providers) == 0 {
+		return errors.New("at least one -provider is required")
+	}
+
+	messages, err := loadDataset(*dataset)
+	if err != nil {
+		return err
+	}
+	service, err := services.NewEvaluationService(messages)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	evaluated := make([]services.EvaluatedProvider, 0, len(providers))
+	for _, spec := range providers {
+		provider, err := newEvalProvider(ctx, spec, *ollamaURL)
+		if err != nil {
+			return err
+		}
+		evaluated = append(evaluated, services.EvaluatedProvider{Name: spec, Provider: provider})
+	}
+
+	evaluations, err := service.Evaluate(ctx, evaluated...)
+	if err != nil {
+		return err
+	}
+	return writeEvalReport(out, evaluations, *bins)
+}
+
+// newEvalProvider creates the provider described by a type:model spec
+func newEvalProvider(ctx context.Context, spec, ollamaURL string) (ports.AiAgentProvider, error) {
+	providerType, model, ok := strings.Cut(spec, ":")
+	if !ok || model == "" {
+		return nil, fmt.Errorf("provider %q is not in the form type:model", spec)
+	}
+
+	cfg := &llm.Config{Type: llm.ProviderType(providerType)}
+	switch cfg.Type {
+	case llm.ProviderTypeOpenAI:
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return nil, errors.New("OPENAI_API_KEY must be set to evaluate OpenAI models")
+		}
+		cfg.OpenAI = openai.NewDefaultConfig(apiKey, model)
+	case llm.ProviderTypeOllama:
+		cfg.Ollama = ollama.NewDefaultConfig(ollamaURL, model)
+	}
+
+	// Sampling noise would blur the comparison between configurations
+	if cfg.OpenAI != nil {
+		cfg.OpenAI.Temperature = 0
+	}
+	if cfg.Ollama != nil {
+		cfg.Ollama.Temperature = 0
+	}
+
+	return llm.NewLLMProvider(ctx, cfg)
+}
+
+// loadDataset reads labeled messages from a JSON Lines file, blank lines are skipped
+func loadDataset(path string) ([]domain.LabeledMessage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer f.Close()
+
+	var messages []domain.LabeledMessage
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var message domain.LabeledMessage
+		if err := json.Unmarshal([]byte(text), &message); err != nil {
+			return nil, fmt.Errorf("dataset line %d: %w", line, err)
+		}
+		messages = append(messages, message)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return messages, nil
+}
+
+// writeEvalReport prints the accuracy, per label metrics and calibration of every evaluation
+func writeEvalReport(out io.Writer, evaluations []*domain.Evaluation, bins int) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(w, "PROVIDER\tMESSAGES\tFAILURES\tACCURACY\tCALIBRATION ERROR")
+	for _, e := range evaluations {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.3f\t%.3f\n", e.Name(), e.Total(), e.Failures(), e.Accuracy(), e.CalibrationError(bins))
+	}
+
+	for _, e := range evaluations {
+		fmt.Fprintf(w, "\n== %s\n", e.Name())
+		writeLabelMetrics(w, "TYPE", e.TypeMetrics())
+		writeLabelMetrics(w, "CATEGORY", e.CategoryMetrics())
+
+		fmt.Fprintln(w, "\nCONFIDENCE\tCOUNT\tMEAN CONFIDENCE\tACCURACY")
+		for _, bin := range e.Calibration(bins) {
+			fmt.Fprintf(w, "%.2f-%.2f\t%d\t%.3f\t%.3f\n", bin.Lower, bin.Upper, bin.Count, bin.Confidence, bin.Accuracy)
+		}
+	}
+	return w.Flush()
+}
+
+func writeLabelMetrics(w io.Writer, title string, metrics []domain.LabelMetrics) {
+	fmt.Fprintf(w, "\n%s\tSUPPORT\tPRECISION\tRECALL\tF1\n", title)
+	for _, m := range metrics {
+		fmt.Fprintf(w, "%s\t%d\t%.3f\t%.3f\t%.3f\n", m.Label, m.Support, m.Precision, m.Recall, m.F1())
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+func runExport(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "obsidian", "format to export the documentation to: obsidian")
+	repoDir := flags.String("repo-dir", ".", "checkout of the documentation repository")
+	outDir := flags.String("out", "", "directory to write the export to, like a folder of an Obsidian vault")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *outDir == "" {
+		return errors.New("-out is required")
+	}
+	if *format != "obsidian" {
+		return fmt.Errorf("-format: unknown format %q", *format)
+	}
+
+	files, err := readDocs(*repoDir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no documents found in %s", filepath.Join(*repoDir, "docs"))
+	}
+
+	vault := domain.ExportObsidianVault(files)
+	for _, path := range vault.Paths() {
+		target := filepath.Join(*outDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, vault.Files[path], 0o644); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(out, "Exported %d notes and %d attachments to %s, %d links to documents left out were unlinked\n",
+		vault.Notes, vault.Attachments, *outDir, vault.Unlinked)
+	return err
+}
+
+// readDocs reads the files of the docs directory of a repository checkout, keyed by their path in the repository
+func readDocs(repoDir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(filepath.Join(repoDir, "docs"), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(repoDir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the documentation: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/docstore/github"
+	"github.com/massimo-ua/quill/internal/providers/wiki/confluence"
+	"github.com/massimo-ua/quill/internal/providers/wiki/notion"
+)
+
+func runImport(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := fs.String("from", "", "knowledge base to import: notion or confluence")
+	category := fs.String("category", domain.CategoryOther.String(), "category whose directory the pages go to")
+	asJSON := fs.Bool("json", false, "print the import report as JSON")
+
+	repo := fs.String("repo", "", "documentation repository, like acme/docs")
+	branch := fs.String("branch", "main", "branch of the documentation repository")
+	githubToken := fs.String("github-token", os.Getenv("GITHUB_TOKEN"), "GitHub token allowed to push to the repository (default $GITHUB_TOKEN)")
+	githubURL := fs.String("github-url", "", "API URL of a GitHub Enterprise server")
+	committerName := fs.String("committer-name", "Quill", "name of the committer of the import")
+	committerEmail := fs.String("committer-email", "quill@users.noreply.github.com", "email of the committer of the import")
+
+	notionToken := fs.String("notion-token", os.Getenv("NOTION_TOKEN"), "secret of the Notion integration (default $NOTION_TOKEN)")
+	roots := fs.String("root", "", "comma-separated IDs of the Notion pages to import with the pages nested in them")
+
+	confluenceURL := fs.String("confluence-url", "", "address of the Confluence site, like https://acme.atlassian.net/wiki")
+	space := fs.String("space", "", "key of the Confluence space to import")
+	confluenceEmail := fs.String("confluence-email", os.Getenv("CONFLUENCE_EMAIL"), "email of the Confluence Cloud account (default $CONFLUENCE_EMAIL)")
+	confluenceToken := fs.String("confluence-token", os.Getenv("CONFLUENCE_API_TOKEN"), "API token of the Confluence Cloud account (default $CONFLUENCE_API_TOKEN)")
+	confluencePAT := fs.String("confluence-pat", os.Getenv("CONFLUENCE_PAT"), "personal access token of Confluence Data Center (default $CONFLUENCE_PAT)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	importCategory, err := domain.NewCategory(*category)
+	if err != nil || importCategory.IsUnknown() {
+		return fmt.Errorf("-category: unknown category %q", *category)
+	}
+
+	var wiki ports.Wiki
+	switch domain.WikiSource(*from) {
+	case domain.WikiSourceNotion:
+		wiki, err = notion.NewFactory(notion.NewConfig(*notionToken, strings.Split(*roots, ",")...)).CreateWiki()
+	case domain.WikiSourceConfluence:
+		config := confluence.NewConfig(*confluenceURL, *space, *confluenceEmail, *confluenceToken)
+		config.PersonalAccessToken = *confluencePAT
+		wiki, err = confluence.NewFactory(config).CreateWiki()
+	default:
+		return errors.New("-from must be notion or confluence")
+	}
+	if err != nil {
+		return err
+	}
+
+	owner, name, ok := strings.Cut(*repo, "/")
+	if !ok {
+		return errors.New("-repo must name the repository as owner/name")
+	}
+	store, err := github.NewGitHubDocumentStoreProvider(&github.Config{
+		Token:          *githubToken,
+		Owner:          owner,
+		Repo:           name,
+		Branch:         *branch,
+		CommitterName:  *committerName,
+		CommitterEmail: *committerEmail,
+		BaseURL:        *githubURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := services.NewWikiImportService(store).Import(ctx, wiki, importCategory)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return writeImportReport(out, report)
+}
+
+// writeImportReport prints the documents an import created and updated, and the pages that failed
+func writeImportReport(out io.Writer, report *domain.WikiImportReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Imported %d %s pages at %s\n", report.Pages, report.Source, report.CompletedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Created:   %d\n", len(report.Created))
+	for _, path := range report.Created {
+		fmt.Fprintf(&b, "  %s\n", path)
+	}
+	fmt.Fprintf(&b, "Updated:   %d\n", len(report.Updated))
+	for _, path := range report.Updated {
+		fmt.Fprintf(&b, "  %s\n", path)
+	}
+	fmt.Fprintf(&b, "Unchanged: %d\n", report.Unchanged)
+	fmt.Fprintf(&b, "Failed:    %d\n", len(report.Failures))
+	for _, failure := range report.Failures {
+		fmt.Fprintf(&b, "  %s (%s): %s\n", failure.Title, failure.PageID, failure.Error)
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,75 @@
+// Command quillctl runs maintenance tasks for a Quill deployment
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: quillctl <command> [flags]
+
+Commands:
+  backup       write a snapshot of the messages, threads, dead letters and audit log kept by a running bot
+  eval         compare how AI agent configurations analyze a labeled message set
+  config       write the example configuration, or check a configuration file
+  calibration  report how often people corrected each model's analyses per confidence, from a running bot
+  erase        erase or pseudonymize the data stored about a person, and print the deletion report
+  export       export a checkout of the documentation repository as an Obsidian vault
+  import       import the pages of a Notion workspace or a Confluence space into the documentation repository
+  migrate      list, apply or roll back the migrations of the database schema of a running bot
+  project      list the project templates, or create a project from one
+  queue        inspect the message queue and its workers, and retry or discard dead-lettered messages
+  reprocess    run documented messages through the current prompt and model, and update their documents
+  restore      replace the state of a running bot with a snapshot written by backup
+  verify       check the provenance signature of generated documents
+
+Run "quillctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "backup":
+		err = runBackup(os.Args[2:], os.Stdout)
+	case "eval":
+		err = runEval(os.Args[2:], os.Stdout)
+	case "config":
+		err = runConfig(os.Args[2:], os.Stdout)
+	case "calibration":
+		err = runCalibration(os.Args[2:], os.Stdout)
+	case "erase":
+		err = runErase(os.Args[2:], os.Stdout)
+	case "export":
+		err = runExport(os.Args[2:], os.Stdout)
+	case "import":
+		err = runImport(os.Args[2:], os.Stdout)
+	case "migrate":
+		err = runMigrate(os.Args[2:], os.Stdout)
+	case "project":
+		err = runProject(os.Args[2:], os.Stdout)
+	case "queue":
+		err = runQueue(os.Args[2:], os.Stdout)
+	case "reprocess":
+		err = runReprocess(os.Args[2:], os.Stdout)
+	case "restore":
+		err = runRestore(os.Args[2:], os.Stdout)
+	case "verify":
+		err = runVerify(os.Args[2:], os.Stdout)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "quillctl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	"github.com/massimo-ua/quill/internal/providers/api"
+)
+
+const migrateUsage = `Usage: quillctl migrate <command> [flags]
+
+Commands:
+  status  list the migrations of the database schema, with whether they were applied
+  up      apply the pending migrations
+  down    roll back the last applied migrations, one unless -steps asks for more
+`
+
+func newMigrateFlags(name string) (*flag.FlagSet, queueFlags) {
+	fs := flag.NewFlagSet("migrate "+name, flag.ContinueOnError)
+	return fs, queueFlags{
+		apiURL: fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API"),
+		token:  fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)"),
+		asJSON: fs.Bool("json", false, "print the response as JSON"),
+	}
+}
+
+func runMigrate(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing migrate command\n\n" + migrateUsage)
+	}
+	switch args[0] {
+	case "status":
+		return runMigrateStatus(args[1:], out)
+	case "up":
+		return runMigrateUp(args[1:], out)
+	case "down":
+		return runMigrateDown(args[1:], out)
+	default:
+		return fmt.Errorf("unknown migrate command %q\n\n%s", args[0], migrateUsage)
+	}
+}
+
+func runMigrateStatus(args []string, out io.Writer) error {
+	fs, flags := newMigrateFlags("status")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var migrations []api.MigrationResponse
+	if err := requestQueue(flags, http.MethodGet, "migrations", nil, &migrations); err != nil {
+		return err
+	}
+	if *flags.asJSON {
+		return writeQueueJSON(out, migrations)
+	}
+	return writeMigrations(out, migrations)
+}
+
+func runMigrateUp(args []string, out io.Writer) error {
+	fs, flags := newMigrateFlags("up")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var applied []api.MigrationResponse
+	if err := requestQueue(flags, http.MethodPost, "migrations/up", nil, &applied); err != nil {
+		return err
+	}
+	if *flags.asJSON {
+		return writeQueueJSON(out, applied)
+	}
+	if len(applied) == 0 {
+		_, err := fmt.Fprintln(out, "The schema is up to date")
+		return err
+	}
+	fmt.Fprintf(out, "Applied %d migrations\n\n", len(applied))
+	return writeMigrations(out, applied)
+}
+
+func runMigrateDown(args []string, out io.Writer) error {
+	fs, flags := newMigrateFlags("down")
+	steps := fs.Int("steps", 1, "how many of the last applied migrations to roll back")
+	confirm := fs.Bool("yes", false, "confirm that rolling back may drop tables and the data they hold")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *steps <= 0 {
+		return errors.New("-steps must be positive")
+	}
+	if !*confirm {
+		return errors.New("rolling back a migration may drop tables and the data they hold, pass -yes to go ahead")
+	}
+
+	var rolledBack []api.MigrationResponse
+	body := api.MigrateDownRequest{Steps: *steps}
+	if err := requestQueue(flags, http.MethodPost, "migrations/down", body, &rolledBack); err != nil {
+		return err
+	}
+	if *flags.asJSON {
+		return writeQueueJSON(out, rolledBack)
+	}
+	if len(rolledBack) == 0 {
+		_, err := fmt.Fprintln(out, "No migration is applied")
+		return err
+	}
+	fmt.Fprintf(out, "Rolled back %d migrations\n\n", len(rolledBack))
+	return writeMigrations(out, rolledBack)
+}
+
+// writeMigrations prints migrations as a table with their state: applied, pending, or dirty when one failed
+// halfway
+func writeMigrations(out io.Writer, migrations []api.MigrationResponse) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE")
+	for _, migration := range migrations {
+		state := "pending"
+		switch {
+		case migration.Dirty:
+			state = "dirty"
+		case migration.Applied:
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", migration.Version, migration.Name, state)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/api"
+)
+
+const projectUsage = `Usage: quillctl project <command> [flags]
+
+Commands:
+  templates  list the project templates and the settings they pre-populate
+  create     create a project from a template through the bot's REST API
+`
+
+func runProject(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing project command\n\n" + projectUsage)
+	}
+	switch args[0] {
+	case "templates":
+		return runProjectTemplates(args[1:], out)
+	case "create":
+		return runProjectCreate(args[1:], out)
+	default:
+		return fmt.Errorf("unknown project command %q\n\n%s", args[0], projectUsage)
+	}
+}
+
+func runProjectTemplates(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("project templates", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the documentation and standup settings of the templates as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	templates := domain.ProjectTemplates()
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(templates)
+	}
+	return writeProjectTemplates(out, templates)
+}
+
+// writeProjectTemplates prints the templates with their path scheme, taxonomy and digest schedules
+func writeProjectTemplates(out io.Writer, templates []domain.ProjectTemplate) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TEMPLATE\tPATHS\tTAXONOMY\tOUTLINES\tDIGESTS")
+	for _, template := range templates {
+		docConfig := template.Documentation
+		categories := make([]string, 0, len(docConfig.Categories()))
+		for _, category := range docConfig.Categories() {
+			categories = append(categories, category.String())
+		}
+		outlines := make([]string, 0, len(docConfig.DocumentTemplates))
+		for msgType := range docConfig.DocumentTemplates {
+			outlines = append(outlines, msgType.String())
+		}
+		sort.Strings(outlines)
+
+		digests := []string{string(docConfig.StatusRollup) + " rollup"}
+		if docConfig.DigestCanvas {
+			digests = append(digests, "canvas")
+		}
+		if template.Standup.PromptAt != "" {
+			digests = append(digests, "standup "+template.Standup.PromptAt)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", template.Name, docConfig.PathScheme, strings.Join(categories, ","),
+			strings.Join(outlines, ","), strings.Join(digests, ", "))
+	}
+	return w.Flush()
+}
+
+func runProjectCreate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("project create", flag.ContinueOnError)
+	apiURL := fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API")
+	token := fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)")
+	name := fs.String("name", "", "name of the project")
+	template := fs.String("template", "", "template of the project: "+strings.Join(domain.ProjectTemplateNames(), ", "))
+	description := fs.String("description", "", "description of the project")
+	channel := fs.String("channel", "", "ID of the chat channel to bind the project to")
+	createdBy := fs.String("by", os.Getenv("USER"), "who creates the project, logged with it")
+	var goals, kpis []string
+	fs.Func("goal", "business goal of the project, repeat for several", func(goal string) error {
+		goals = append(goals, goal)
+		return nil
+	})
+	fs.Func("kpi", "KPI of the project, repeat for several", func(kpi string) error {
+		kpis = append(kpis, kpi)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		return errors.New("-token or QUILL_API_TOKEN is required")
+	}
+	// The draft is checked here too, so mistakes are reported before anything is sent
+	if _, err := domain.NewProjectDraft(*name, *template, *description, goals, kpis, *channel, *createdBy); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	project, err := requestProject(ctx, *apiURL, *token, api.ProjectRequest{
+		Name:        *name,
+		Template:    *template,
+		Description: *description,
+		Goals:       goals,
+		KPIs:        kpis,
+		Channel:     *channel,
+		CreatedBy:   *createdBy,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Created project %s (%s) from the %s template, documented under %s\n", project.Name, project.ID, project.Template, project.Path)
+	if len(project.Channels) > 0 {
+		fmt.Fprintf(out, "Bound to %s\n", strings.Join(project.Channels, ", "))
+	}
+	return nil
+}
+
+// requestProject asks the bot's REST API to create a project from a template
+func requestProject(ctx context.Context, apiURL, token string, project api.ProjectRequest) (*api.ProjectResponse, error) {
+	endpoint, err := url.JoinPath(apiURL, "projects")
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL: %w", err)
+	}
+	body, err := json.Marshal(project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var created api.ProjectResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode project: %w", err)
+	}
+	return &created, nil
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/api"
+)
+
+const queueUsage = `Usage: quillctl queue <command> [flags]
+
+Commands:
+  status   show the depth of the message queue, the number of dead letters and what the workers do
+  list     list the messages the workers failed on
+  show     show a dead-lettered message with its text, by ID
+  retry    queue a dead-lettered message again, by ID
+  discard  drop a dead-lettered message for good, by ID
+`
+
+// queueFlags are the flags every queue command takes
+type queueFlags struct {
+	apiURL *string
+	token  *string
+	asJSON *bool
+}
+
+func newQueueFlags(name string) (*flag.FlagSet, queueFlags) {
+	fs := flag.NewFlagSet("queue "+name, flag.ContinueOnError)
+	return fs, queueFlags{
+		apiURL: fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API"),
+		token:  fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)"),
+		asJSON: fs.Bool("json", false, "print the response as JSON"),
+	}
+}
+
+func runQueue(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing queue command\n\n" + queueUsage)
+	}
+	switch args[0] {
+	case "status":
+		return runQueueStatus(args[1:], out)
+	case "list":
+		return runQueueList(args[1:], out)
+	case "show":
+		return runQueueShow(args[1:], out)
+	case "retry", "discard":
+		return runQueueAction(args[0], args[1:], out)
+	default:
+		return fmt.Errorf("unknown queue command %q\n\n%s", args[0], queueUsage)
+	}
+}
+
+func runQueueStatus(args []string, out io.Writer) error {
+	fs, flags := newQueueFlags("status")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var status api.QueueStatusResponse
+	if err := requestQueue(flags, http.MethodGet, "queue", nil, &status); err != nil {
+		return err
+	}
+	if *flags.asJSON {
+		return writeQueueJSON(out, status)
+	}
+
+	depth := "unknown"
+	if status.Depth != nil {
+		depth = fmt.Sprint(*status.Depth)
+	}
+	fmt.Fprintf(out, "Queued:       %s\n", depth)
+	fmt.Fprintf(out, "Dead letters: %d\n", status.DeadLetters)
+	fmt.Fprintf(out, "Workers:      %d busy of %d\n", status.Busy, len(status.Workers))
+	if len(status.Workers) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nWORKER\tSTATE\tSINCE\tPROCESSED\tFAILED")
+	for _, worker := range status.Workers {
+		state := "idle"
+		if worker.MessageID != "" {
+			state = "processing " + worker.MessageID
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\n", worker.Worker, state, worker.Since.UTC().Format(time.RFC3339), worker.Processed, worker.Failed)
+	}
+	return w.Flush()
+}
+
+func runQueueList(args []string, out io.Writer) error {
+	fs, flags := newQueueFlags("list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var letters []api.DeadLetterResponse
+	if err := requestQueue(flags, http.MethodGet, "dead-letters", nil, &letters); err != nil {
+		return err
+	}
+	if *flags.asJSON {
+		return writeQueueJSON(out, letters)
+	}
+	if len(letters) == 0 {
+		_, err := fmt.Fprintln(out, "No dead letters")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCHANNEL\tATTEMPTS\tFAILED AT\tERROR")
+	for _, letter := range letters {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", letter.ID, letter.ChannelID, letter.Attempts, letter.FailedAt.UTC().Format(time.RFC3339), letter.Error)
+	}
+	return w.Flush()
+}
+
+func runQueueShow(args []string, out io.Writer) error {
+	fs, flags := newQueueFlags("show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := deadLetterID(fs)
+	if err != nil {
+		return err
+	}
+
+	var letter api.DeadLetterResponse
+	if err := requestQueue(flags, http.MethodGet, "dead-letters/"+url.PathEscape(id), nil, &letter); err != nil {
+		return err
+	}
+	if *flags.asJSON {
+		return writeQueueJSON(out, letter)
+	}
+
+	fmt.Fprintf(out, "Message:   %s\n", letter.ID)
+	fmt.Fprintf(out, "Channel:   %s\n", letter.ChannelID)
+	fmt.Fprintf(out, "Sender:    %s\n", letter.Sender)
+	fmt.Fprintf(out, "Posted at: %s\n", letter.PostedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "Attempts:  %d, the last at %s\n", letter.Attempts, letter.FailedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "Error:     %s\n", letter.Error)
+	_, err = fmt.Fprintf(out, "\n%s\n", letter.Text)
+	return err
+}
+
+// runQueueAction retries or discards a dead letter
+func runQueueAction(action string, args []string, out io.Writer) error {
+	fs, flags := newQueueFlags(action)
+	requestedBy := fs.String("by", os.Getenv("USER"), "who "+action+"s the message, recorded in the audit log")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := deadLetterID(fs)
+	if err != nil {
+		return err
+	}
+
+	var letter api.DeadLetterResponse
+	body := api.DeadLetterActionRequest{RequestedBy: *requestedBy}
+	if err := requestQueue(flags, http.MethodPost, "dead-letters/"+url.PathEscape(id)+"/"+action, body, &letter); err != nil {
+		return err
+	}
+	if *flags.asJSON {
+		return writeQueueJSON(out, letter)
+	}
+	if action == "retry" {
+		fmt.Fprintf(out, "Queued message %s again, attempt %d\n", letter.ID, letter.Attempts+1)
+	} else {
+		fmt.Fprintf(out, "Discarded message %s after %d attempts\n", letter.ID, letter.Attempts)
+	}
+	return nil
+}
+
+// deadLetterID returns the message ID a command names after its flags
+func deadLetterID(fs *flag.FlagSet) (string, error) {
+	if fs.NArg() != 1 || strings.TrimSpace(fs.Arg(0)) == "" {
+		return "", fmt.Errorf("usage: quillctl %s [flags] <message-id>", fs.Name())
+	}
+	return fs.Arg(0), nil
+}
+
+// requestQueue calls an endpoint of the bot's REST API inspecting the queue, and decodes its response
+func requestQueue(flags queueFlags, method, endpoint string, body interface{}, response interface{}) error {
+	if *flags.token == "" {
+		return errors.New("-token or QUILL_API_TOKEN is required")
+	}
+	target, err := url.JoinPath(*flags.apiURL, endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid API URL: %w", err)
+	}
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	req, err := http.NewRequestWithContext(ctx, method, target, payload)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+*flags.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func writeQueueJSON(out io.Writer, body interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(body)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/api"
+)
+
+func runReprocess(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("reprocess", flag.ContinueOnError)
+	apiURL := fs.String("url", envOr("QUILL_API_URL", "http://localhost:8080"), "URL of the bot's REST API")
+	token := fs.String("token", os.Getenv("QUILL_API_TOKEN"), "bearer token of the REST API (default $QUILL_API_TOKEN)")
+	since := fs.String("since", "", "reprocess the messages posted since a date, like 2024-06-01, or an RFC 3339 time")
+	category := fs.String("category", "", "reprocess the messages of a category, like development")
+	dryRun := fs.Bool("dry-run", false, "report what would change without storing anything")
+	showDiff := fs.Bool("diff", false, "print the diff of every updated document")
+	requestedBy := fs.String("by", os.Getenv("USER"), "who asks for the reprocessing, recorded in the audit log")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		return errors.New("-token or QUILL_API_TOKEN is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := requestReprocess(ctx, *apiURL, *token, api.ReprocessRequest{
+		Since:       *since,
+		Category:    *category,
+		DryRun:      *dryRun,
+		RequestedBy: *requestedBy,
+	})
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return writeReprocessReport(out, report, *showDiff)
+}
+
+// requestReprocess asks the bot's REST API to run the selected messages through the current prompt and model
+func requestReprocess(ctx context.Context, apiURL, token string, reprocess api.ReprocessRequest) (*api.ReprocessReportResponse, error) {
+	endpoint, err := url.JoinPath(apiURL, "reprocess")
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL: %w", err)
+	}
+	body, err := json.Marshal(reprocess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	// Every selected message is analyzed and documented again, which takes a while for months of messages
+	client := &http.Client{Timeout: time.Hour}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request reprocessing: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var report api.ReprocessReportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode reprocess report: %w", err)
+	}
+	return &report, nil
+}
+
+// writeReprocessReport prints the documents the reprocessing updated, and their diffs when asked for
+func writeReprocessReport(out io.Writer, report *api.ReprocessReportResponse, showDiff bool) error {
+	var b strings.Builder
+	verb := "Reprocessed"
+	if report.DryRun {
+		verb = "Dry run of reprocessing"
+	}
+	fmt.Fprintf(&b, "%s %d messages at %s\n", verb, report.Messages, report.CompletedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Updated documents: %d\n", len(report.Documents))
+	for _, doc := range report.Documents {
+		fmt.Fprintf(&b, "  %s v%d (+%d -%d)\n", doc.Path, doc.Version, doc.Added, doc.Removed)
+	}
+	fmt.Fprintf(&b, "Unchanged:         %d\n", report.Unchanged)
+	fmt.Fprintf(&b, "Failed:            %d\n", len(report.Failures))
+	for _, failure := range report.Failures {
+		fmt.Fprintf(&b, "  %s: %s\n", failure.MessageID, failure.Error)
+	}
+	if showDiff {
+		for _, doc := range report.Documents {
+			fmt.Fprintf(&b, "\n%s", doc.Diff)
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
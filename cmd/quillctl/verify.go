@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+var errUnverified = errors.New("some documents failed verification")
+
+func runVerify(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	key := fs.String("key", os.Getenv("QUILL_PROVENANCE_KEY"), "provenance key the bot signs documents with (default $QUILL_PROVENANCE_KEY)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return errors.New("pass the documents to verify")
+	}
+	signer, err := domain.NewProvenanceSigner([]byte(*key))
+	if err != nil {
+		return fmt.Errorf("-key or QUILL_PROVENANCE_KEY: %w", err)
+	}
+
+	failed := false
+	for _, path := range fs.Args() {
+		status := "ok"
+		content, err := os.ReadFile(path)
+		if err == nil {
+			err = signer.Verify(string(content))
+		}
+		switch {
+		case errors.Is(err, domain.ErrUnsigned) && !generated(string(content)):
+			// Tables of contents and other documents the bot does not generate from messages are not signed
+			status, err = "skipped", nil
+		case errors.Is(err, domain.ErrUnsigned):
+			status = "UNSIGNED"
+		case errors.Is(err, domain.ErrInvalidSignature):
+			status = "TAMPERED"
+		case err != nil:
+			status = "error: " + err.Error()
+		}
+		if err != nil {
+			failed = true
+		}
+		fmt.Fprintf(out, "%s\t%s\n", status, path)
+	}
+	if failed {
+		return errUnverified
+	}
+	return nil
+}
+
+// generated checks if a document names the messages it was generated from
+func generated(content string) bool {
+	fm, _, err := domain.ParseFrontMatter(content)
+	return err == nil && domain.HasProvenance(fm)
+}
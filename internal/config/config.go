@@ -0,0 +1,478 @@
+// Package config loads the settings of a Quill deployment from a YAML file and validates them, reporting every
+// invalid setting at once rather than the first one found
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/api"
+	"github.com/massimo-ua/quill/internal/providers/chat/slack"
+	"github.com/massimo-ua/quill/internal/providers/coordination"
+	"github.com/massimo-ua/quill/internal/providers/docstore/github"
+	"github.com/massimo-ua/quill/internal/providers/featureflags"
+	"github.com/massimo-ua/quill/internal/providers/llm"
+	"github.com/massimo-ua/quill/internal/providers/llm/fixture"
+	"github.com/massimo-ua/quill/internal/providers/llm/ollama"
+	"github.com/massimo-ua/quill/internal/providers/llm/openai"
+	"github.com/massimo-ua/quill/internal/providers/storage/redis"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// Example is the documented example configuration quillctl config init writes
+//
+//go:embed example.yaml
+var Example []byte
+
+// Config contains the settings of a Quill deployment. Only the Slack settings are required, the other sections
+// turn their part of the bot on when present.
+type Config struct {
+	Slack  Slack   `yaml:"slack"`
+	LLM    *LLM    `yaml:"llm"`
+	GitHub *GitHub `yaml:"github"`
+	API    *API    `yaml:"api"`
+	// Storage keeps the processing state in a SQL database rather than in memory
+	Storage *Storage `yaml:"storage"`
+	// Cache picks where the dedup store, the user profiles and the thread mappings are kept, in memory without it
+	Cache *Cache `yaml:"cache"`
+	// Coordination elects the replica connecting to Slack, run a single replica without it
+	Coordination *Coordination `yaml:"coordination"`
+	// Features holds the flags of experimental features by name, FeatureFlagSource fetches flags overriding them
+	Features          map[string]FeatureFlag `yaml:"features"`
+	FeatureFlagSource *FeatureFlagSource     `yaml:"featureFlagSource"`
+}
+
+// Slack contains the settings of the Slack app
+type Slack struct {
+	BotToken      string `yaml:"botToken" validate:"required"`
+	AppToken      string `yaml:"appToken" validate:"required"`
+	SigningSecret string `yaml:"signingSecret"`
+	Debug         bool   `yaml:"debug"`
+	// IgnoredUsers and IgnoredBots list the users and bots whose messages are never processed
+	IgnoredUsers []string `yaml:"ignoredUsers"`
+	IgnoredBots  []string `yaml:"ignoredBots"`
+	// BackfillOnReconnect fetches the messages posted while the socket was disconnected, up to
+	// MaxBackfillMessages per channel (0 uses the Slack provider's default)
+	BackfillOnReconnect bool `yaml:"backfillOnReconnect"`
+	MaxBackfillMessages int  `yaml:"maxBackfillMessages" validate:"min=0"`
+}
+
+// LLM contains the settings of the AI agent, the section of the chosen provider is required
+type LLM struct {
+	Provider string   `yaml:"provider" validate:"required,oneof=openai ollama fixture"`
+	OpenAI   *OpenAI  `yaml:"openai"`
+	Ollama   *Ollama  `yaml:"ollama"`
+	Fixture  *Fixture `yaml:"fixture"`
+}
+
+// OpenAI contains the settings of the OpenAI provider
+type OpenAI struct {
+	APIKey  string `yaml:"apiKey" validate:"required"`
+	Model   string `yaml:"model" validate:"required"`
+	BaseURL string `yaml:"baseURL" validate:"url"`
+	// Temperature defaults to 0.7 when not set
+	Temperature *float64 `yaml:"temperature" validate:"min=0,max=2"`
+	// MaxTokens defaults to 1024 when not set
+	MaxTokens      int    `yaml:"maxTokens" validate:"min=0"`
+	EmbeddingModel string `yaml:"embeddingModel"`
+}
+
+// Ollama contains the settings of the Ollama provider
+type Ollama struct {
+	ServerURL string `yaml:"serverURL" validate:"required,url"`
+	Model     string `yaml:"model" validate:"required"`
+	// Temperature defaults to 0.7 when not set
+	Temperature *float64 `yaml:"temperature" validate:"min=0,max=2"`
+	// MaxTokens defaults to 1024 when not set
+	MaxTokens      int    `yaml:"maxTokens" validate:"min=0"`
+	EmbeddingModel string `yaml:"embeddingModel"`
+	VerifyModel    bool   `yaml:"verifyModel"`
+	// AutoPull pulls missing models at startup, within PullTimeout each, like 45m (default: 30m)
+	AutoPull    bool          `yaml:"autoPull"`
+	PullTimeout time.Duration `yaml:"pullTimeout" validate:"min=0"`
+}
+
+// Fixture contains the settings of the fixture provider answering from canned responses
+type Fixture struct {
+	Path string `yaml:"path" validate:"required"`
+}
+
+// GitHub contains the settings of the documentation repository
+type GitHub struct {
+	Token          string `yaml:"token" validate:"required"`
+	Owner          string `yaml:"owner" validate:"required"`
+	Repo           string `yaml:"repo" validate:"required"`
+	Branch         string `yaml:"branch"`
+	BasePath       string `yaml:"basePath"`
+	CommitterName  string `yaml:"committerName" validate:"required"`
+	CommitterEmail string `yaml:"committerEmail" validate:"required"`
+	BaseURL        string `yaml:"baseURL" validate:"url"`
+	LFSThreshold   int    `yaml:"lfsThreshold" validate:"min=0"`
+	WebhookSecret  string `yaml:"webhookSecret"`
+}
+
+// API contains the settings of the REST API
+type API struct {
+	// Listen is the address the API is served on (default: :8080)
+	Listen     string   `yaml:"listen"`
+	Tokens     []string `yaml:"tokens" validate:"required"`
+	FeedTokens []string `yaml:"feedTokens"`
+	// TopContributors, MaxOverrideRate, FeedSize and EventKeepAlive use the API's defaults when not set
+	TopContributors int           `yaml:"topContributors" validate:"min=0"`
+	MaxOverrideRate float64       `yaml:"maxOverrideRate" validate:"min=0,max=1"`
+	FeedSize        int           `yaml:"feedSize" validate:"min=0"`
+	EventKeepAlive  time.Duration `yaml:"eventKeepAlive" validate:"min=0"`
+}
+
+// Storage contains the settings of the SQL database of the state store
+type Storage struct {
+	Dialect string `yaml:"dialect" validate:"required,oneof=postgres sqlite"`
+	// Driver is ignored, the bot bundles the drivers of both dialects. Configurations naming one still load.
+	Driver string `yaml:"driver"`
+	// DSN is the path of the SQLite database file, or the URL of the Postgres database
+	DSN string `yaml:"dsn" validate:"required"`
+	// AutoMigrate applies the pending schema migrations at startup, otherwise quillctl migrate up does
+	AutoMigrate bool `yaml:"autoMigrate"`
+}
+
+// Cache contains the settings of the dedup store, the user profile cache and the thread mapping cache
+type Cache struct {
+	// Backend is memory, each replica keeping its own, or redis, shared by every replica
+	Backend string `yaml:"backend" validate:"required,oneof=memory redis"`
+	// ProfileTTL is how long user profiles are cached, like 30m (default: 1h)
+	ProfileTTL time.Duration `yaml:"profileTTL" validate:"min=0"`
+	Redis      *Redis        `yaml:"redis"`
+}
+
+// Redis contains the settings of the Redis server of the caches
+type Redis struct {
+	// URL is the server, like redis://:password@host:6379/0; rediss:// connects over TLS
+	URL string `yaml:"url" validate:"required"`
+	// KeyPrefix starts every key, so the bot can share a database (default: quill:)
+	KeyPrefix string `yaml:"keyPrefix"`
+	// DedupTTL is how long the keys of handled events are remembered (default: 24h)
+	DedupTTL time.Duration `yaml:"dedupTTL" validate:"min=0"`
+}
+
+// Coordination contains the settings splitting the work between replicas
+type Coordination struct {
+	// Mode is leader: one replica elected with a Postgres advisory lock connects to Slack and processes everything
+	// while the others stand by. Slack delivers each event to one connected replica only, so channels cannot be split
+	// between replicas that all connect.
+	Mode string `yaml:"mode" validate:"required,oneof=leader"`
+	// LockKey is the advisory lock the replicas compete for
+	LockKey int64 `yaml:"lockKey"`
+	// RetryInterval is how often standby replicas try to take the lock, like 10s (default: 5s)
+	RetryInterval time.Duration `yaml:"retryInterval" validate:"min=0"`
+	// DSN is the URL of the Postgres database holding the lock, the storage DSN when not set
+	DSN string `yaml:"dsn"`
+}
+
+// FeatureFlag decides which projects an experimental feature is on for, see domain.FeatureFlag
+type FeatureFlag struct {
+	Enabled  bool     `yaml:"enabled"`
+	Projects []string `yaml:"projects"`
+	Rollout  int      `yaml:"rollout" validate:"min=0,max=100"`
+}
+
+// FeatureFlagSource contains the settings of the remote feature flag source
+type FeatureFlagSource struct {
+	URL   string `yaml:"url" validate:"required,url"`
+	Token string `yaml:"token"`
+	// Refresh is how long fetched flags are used before they are fetched again, like 30s (default: 1m)
+	Refresh time.Duration `yaml:"refresh" validate:"min=0"`
+}
+
+// Load reads and validates the configuration file at path
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse reads and validates a configuration. References to environment variables like ${SLACK_BOT_TOKEN} are
+// replaced with their values first, so secrets can stay out of the file. Unknown settings are rejected, they are
+// usually typos.
+func Parse(data []byte) (*Config, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(os.ExpandEnv(string(data)))))
+	decoder.KnownFields(true)
+
+	var cfg Config
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// FromEnv creates the configuration of deployments without a configuration file, reading the Slack settings
+// from the SLACK_BOT_TOKEN, SLACK_APP_TOKEN and SLACK_SIGNING_SECRET environment variables
+func FromEnv() (*Config, error) {
+	cfg := &Config{Slack: Slack{
+		BotToken:      os.Getenv("SLACK_BOT_TOKEN"),
+		AppToken:      os.Getenv("SLACK_APP_TOKEN"),
+		SigningSecret: os.Getenv("SLACK_SIGNING_SECRET"),
+		Debug:         true,
+	}}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks every setting and returns ValidationErrors listing all invalid ones
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	validateStruct(reflect.ValueOf(c).Elem(), "", &errs)
+
+	for _, name := range sortedKeys(c.Features) {
+		field := "features." + name
+		if !domain.Feature(name).IsValid() {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("is not a known feature, use one of %s", featureNames())})
+			continue
+		}
+		validateStruct(reflect.ValueOf(c.Features[name]), field, &errs)
+		for i, project := range c.Features[name].Projects {
+			if strings.TrimSpace(project) == "" {
+				errs = append(errs, FieldError{Field: fmt.Sprintf("%s.projects[%d]", field, i), Message: "cannot be blank"})
+			}
+		}
+	}
+
+	if c.LLM != nil {
+		missing := ""
+		switch llm.ProviderType(c.LLM.Provider) {
+		case llm.ProviderTypeOpenAI:
+			if c.LLM.OpenAI == nil {
+				missing = "openai"
+			}
+		case llm.ProviderTypeOllama:
+			if c.LLM.Ollama == nil {
+				missing = "ollama"
+			}
+		case llm.ProviderTypeFixture:
+			if c.LLM.Fixture == nil {
+				missing = "fixture"
+			}
+		}
+		if missing != "" {
+			errs = append(errs, FieldError{Field: "llm." + missing, Message: "is required by the " + missing + " provider"})
+		}
+	}
+
+	if c.Cache != nil {
+		if c.Cache.Backend == "redis" && c.Cache.Redis == nil {
+			errs = append(errs, FieldError{Field: "cache.redis", Message: "is required by the redis backend"})
+		}
+		if c.Cache.Redis != nil && strings.TrimSpace(c.Cache.Redis.URL) != "" {
+			if err := redis.NewDefaultConfig(c.Cache.Redis.URL).Validate(); err != nil {
+				errs = append(errs, FieldError{Field: "cache.redis.url", Message: "must look like redis://host:6379/0 or rediss://host:6379/0"})
+			}
+		}
+	}
+
+	if c.Coordination != nil {
+		switch c.Coordination.Mode {
+		case "leader":
+			if c.LeaderDSN() == "" {
+				errs = append(errs, FieldError{Field: "coordination.dsn", Message: "is required by the leader mode without a postgres storage"})
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// SlackConfig returns the settings of the Slack provider
+func (c *Config) SlackConfig() *slack.Config {
+	cfg := slack.NewConfig(c.Slack.BotToken, c.Slack.AppToken, c.Slack.SigningSecret, c.Slack.Debug)
+	cfg.IgnoredUserIDs = c.Slack.IgnoredUsers
+	cfg.IgnoredBotIDs = c.Slack.IgnoredBots
+	cfg.BackfillOnReconnect = c.Slack.BackfillOnReconnect
+	cfg.MaxBackfillMessages = c.Slack.MaxBackfillMessages
+	return cfg
+}
+
+// LLMConfig returns the settings of the AI agent, nil when the configuration has none
+func (c *Config) LLMConfig() *llm.Config {
+	if c.LLM == nil {
+		return nil
+	}
+
+	cfg := &llm.Config{Type: llm.ProviderType(c.LLM.Provider)}
+	if o := c.LLM.OpenAI; o != nil {
+		cfg.OpenAI = openai.NewDefaultConfig(o.APIKey, o.Model)
+		cfg.OpenAI.BaseURL = o.BaseURL
+		cfg.OpenAI.EmbeddingModel = o.EmbeddingModel
+		if o.Temperature != nil {
+			cfg.OpenAI.Temperature = *o.Temperature
+		}
+		if o.MaxTokens > 0 {
+			cfg.OpenAI.MaxTokens = o.MaxTokens
+		}
+	}
+	if o := c.LLM.Ollama; o != nil {
+		cfg.Ollama = ollama.NewDefaultConfig(o.ServerURL, o.Model)
+		cfg.Ollama.EmbeddingModel = o.EmbeddingModel
+		cfg.Ollama.VerifyModel = o.VerifyModel
+		cfg.Ollama.AutoPull = o.AutoPull
+		if o.PullTimeout > 0 {
+			cfg.Ollama.PullTimeout = o.PullTimeout
+		}
+		if o.Temperature != nil {
+			cfg.Ollama.Temperature = *o.Temperature
+		}
+		if o.MaxTokens > 0 {
+			cfg.Ollama.MaxTokens = o.MaxTokens
+		}
+	}
+	if f := c.LLM.Fixture; f != nil {
+		cfg.Fixture = &fixture.Config{Path: f.Path}
+	}
+	return cfg
+}
+
+// GitHubConfig returns the settings of the documentation repository, nil when the configuration has none
+func (c *Config) GitHubConfig() *github.Config {
+	if c.GitHub == nil {
+		return nil
+	}
+	return &github.Config{
+		Token:          c.GitHub.Token,
+		Owner:          c.GitHub.Owner,
+		Repo:           c.GitHub.Repo,
+		Branch:         c.GitHub.Branch,
+		BasePath:       c.GitHub.BasePath,
+		CommitterName:  c.GitHub.CommitterName,
+		CommitterEmail: c.GitHub.CommitterEmail,
+		BaseURL:        c.GitHub.BaseURL,
+		LFSThreshold:   c.GitHub.LFSThreshold,
+		WebhookSecret:  c.GitHub.WebhookSecret,
+	}
+}
+
+// APIConfig returns the settings of the REST API, nil when the configuration has none
+func (c *Config) APIConfig() *api.Config {
+	if c.API == nil {
+		return nil
+	}
+	cfg := &api.Config{
+		Tokens:          c.API.Tokens,
+		FeedTokens:      c.API.FeedTokens,
+		TopContributors: api.DefaultTopContributors,
+		MaxOverrideRate: api.DefaultMaxOverrideRate,
+		FeedSize:        c.API.FeedSize,
+		EventKeepAlive:  c.API.EventKeepAlive,
+	}
+	if c.API.TopContributors > 0 {
+		cfg.TopContributors = c.API.TopContributors
+	}
+	if c.API.MaxOverrideRate > 0 {
+		cfg.MaxOverrideRate = c.API.MaxOverrideRate
+	}
+	return cfg
+}
+
+// DefaultAPIListen is the address the REST API is served on when the configuration does not tell
+const DefaultAPIListen = ":8080"
+
+// APIListen returns the address the REST API is served on, empty when the configuration has no api section
+func (c *Config) APIListen() string {
+	if c.API == nil {
+		return ""
+	}
+	if strings.TrimSpace(c.API.Listen) == "" {
+		return DefaultAPIListen
+	}
+	return c.API.Listen
+}
+
+// RedisConfig returns the settings of the Redis server of the caches, nil unless the caches are kept in Redis
+func (c *Config) RedisConfig() *redis.Config {
+	if c.Cache == nil || c.Cache.Backend != "redis" || c.Cache.Redis == nil {
+		return nil
+	}
+	cfg := redis.NewDefaultConfig(c.Cache.Redis.URL)
+	if c.Cache.Redis.KeyPrefix != "" {
+		cfg.KeyPrefix = c.Cache.Redis.KeyPrefix
+	}
+	if c.Cache.Redis.DedupTTL > 0 {
+		cfg.DedupTTL = c.Cache.Redis.DedupTTL
+	}
+	if c.Cache.ProfileTTL > 0 {
+		cfg.ProfileTTL = c.Cache.ProfileTTL
+	}
+	return cfg
+}
+
+// LeaderConfig returns the settings of the leader election, nil unless the replicas elect a leader
+func (c *Config) LeaderConfig() *coordination.LeaderConfig {
+	if c.Coordination == nil || c.Coordination.Mode != "leader" {
+		return nil
+	}
+	return &coordination.LeaderConfig{LockKey: c.Coordination.LockKey, RetryInterval: c.Coordination.RetryInterval}
+}
+
+// LeaderDSN returns the URL of the Postgres database of the leader election: its own, or the storage's when that
+// is Postgres
+func (c *Config) LeaderDSN() string {
+	if c.Coordination != nil && strings.TrimSpace(c.Coordination.DSN) != "" {
+		return c.Coordination.DSN
+	}
+	if c.Storage != nil && c.Storage.Dialect == "postgres" {
+		return c.Storage.DSN
+	}
+	return ""
+}
+
+// FeatureFlags returns the configured flags of the experimental features
+func (c *Config) FeatureFlags() domain.FeatureFlags {
+	flags := make(domain.FeatureFlags, len(c.Features))
+	for name, flag := range c.Features {
+		flags[domain.Feature(name)] = domain.FeatureFlag{Enabled: flag.Enabled, Projects: flag.Projects, Rollout: flag.Rollout}
+	}
+	return flags
+}
+
+// FeatureFlagSourceConfig returns the settings of the remote feature flag source, nil when the configuration has none
+func (c *Config) FeatureFlagSourceConfig() *featureflags.Config {
+	if c.FeatureFlagSource == nil {
+		return nil
+	}
+	return &featureflags.Config{URL: c.FeatureFlagSource.URL, Token: c.FeatureFlagSource.Token}
+}
+
+func sortedKeys(features map[string]FeatureFlag) []string {
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func featureNames() string {
+	names := make([]string, 0, len(domain.Features()))
+	for _, feature := range domain.Features() {
+		names = append(names, feature.String())
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/coordination"
+	"github.com/massimo-ua/quill/internal/providers/llm/ollama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setExampleEnv(t *testing.T) {
+	t.Setenv("SLACK_BOT_TOKEN", "xoxb-1")
+	t.Setenv("SLACK_APP_TOKEN", "xapp-1")
+	t.Setenv("SLACK_SIGNING_SECRET", "")
+	t.Setenv("OPENAI_API_KEY", "sk-1")
+	t.Setenv("GITHUB_TOKEN", "ghp-1")
+	t.Setenv("GITHUB_WEBHOOK_SECRET", "")
+	t.Setenv("QUILL_API_TOKEN", "api-1")
+}
+
+func TestParse_Example(t *testing.T) {
+	setExampleEnv(t)
+
+	cfg, err := Parse(Example)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-1", cfg.Slack.BotToken)
+
+	slackConfig := cfg.SlackConfig()
+	assert.Equal(t, "xapp-1", slackConfig.AppToken)
+	assert.True(t, slackConfig.BackfillOnReconnect)
+
+	llmConfig := cfg.LLMConfig()
+	require.NotNil(t, llmConfig.OpenAI)
+	assert.Nil(t, llmConfig.Ollama)
+	assert.Equal(t, "sk-1", llmConfig.OpenAI.APIKey)
+	assert.Equal(t, 0.2, llmConfig.OpenAI.Temperature)
+	assert.NoError(t, llmConfig.OpenAI.Validate())
+
+	githubConfig := cfg.GitHubConfig()
+	assert.Equal(t, "acme", githubConfig.Owner)
+	assert.NoError(t, githubConfig.Validate())
+
+	apiConfig := cfg.APIConfig()
+	assert.Equal(t, []string{"api-1"}, apiConfig.Tokens)
+	assert.Equal(t, 15*time.Second, apiConfig.EventKeepAlive)
+	assert.NoError(t, apiConfig.Validate())
+	assert.Equal(t, ":8080", cfg.APIListen())
+
+	assert.Equal(t, domain.FeatureFlags{domain.FeatureApprovals: {Projects: []string{"Billing"}, Rollout: 25}}, cfg.FeatureFlags())
+	assert.Nil(t, cfg.FeatureFlagSourceConfig())
+	assert.Nil(t, cfg.Storage)
+	assert.Nil(t, cfg.RedisConfig())
+}
+
+func TestParse_FeatureFlags(t *testing.T) {
+	cfg, err := Parse([]byte(`
+slack: {botToken: xoxb-1, appToken: xapp-1}
+features:
+  questions: {projects: [Billing]}
+featureFlagSource:
+  url: https://flags.example.com/quill.json
+  refresh: 30s
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.FeatureFlagSource.Refresh)
+	assert.Equal(t, "https://flags.example.com/quill.json", cfg.FeatureFlagSourceConfig().URL)
+	assert.False(t, cfg.FeatureFlags().Enabled(domain.FeatureQuestions, nil))
+
+	_, err = Parse([]byte(`
+slack: {botToken: xoxb-1, appToken: xapp-1}
+features:
+  telepathy: {enabled: true}
+  approvals: {rollout: 120, projects: [" "]}
+featureFlagSource: {url: flags.json}
+`))
+	var validation ValidationErrors
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, ValidationErrors{
+		{Field: "featureFlagSource.url", Message: `must be an http or https URL, got "flags.json"`},
+		{Field: "features.approvals.rollout", Message: "must be at most 100, got 120"},
+		{Field: "features.approvals.projects[0]", Message: "cannot be blank"},
+		{Field: "features.telepathy", Message: "is not a known feature, use one of embedding-references, approvals, questions"},
+	}, validation)
+}
+
+func TestParse_AggregatesErrors(t *testing.T) {
+	_, err := Parse([]byte(`
+slack:
+  botToken: xoxb-1
+llm:
+  provider: gpt
+  ollama:
+    serverURL: localhost:11434
+    model: llama3
+    temperature: 3
+github:
+  token: ghp-1
+  owner: acme
+  repo: docs
+  committerName: Quill
+  lfsThreshold: -1
+api:
+  tokens: ["api-1", " "]
+  maxOverrideRate: 1.5
+`))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+
+	var validation ValidationErrors
+	require.True(t, errors.As(err, &validation))
+	fields := make(map[string]string, len(validation))
+	for _, fieldErr := range validation {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"slack.appToken":         "is required",
+		"llm.provider":           `must be one of openai, ollama, fixture, got "gpt"`,
+		"llm.ollama.serverURL":   `must be an http or https URL, got "localhost:11434"`,
+		"llm.ollama.temperature": "must be at most 2, got 3",
+		"github.committerEmail":  "is required",
+		"github.lfsThreshold":    "must be at least 0, got -1",
+		"api.tokens[1]":          "cannot be blank",
+		"api.maxOverrideRate":    "must be at most 1, got 1.5",
+	}, fields)
+	assert.Contains(t, err.Error(), "invalid configuration, 8 problems:\n  - slack.appToken is required\n")
+}
+
+func TestParse_Storage(t *testing.T) {
+	cfg, err := Parse([]byte(`
+slack: {botToken: xoxb-1, appToken: xapp-1}
+storage:
+  dialect: sqlite
+  dsn: /var/lib/quill/state.db
+  autoMigrate: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &Storage{Dialect: "sqlite", DSN: "/var/lib/quill/state.db", AutoMigrate: true}, cfg.Storage)
+
+	// The drivers are bundled, configurations naming one still load
+	cfg, err = Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\nstorage: {dialect: postgres, driver: pgx, dsn: postgres://db/quill}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://db/quill", cfg.Storage.DSN)
+
+	_, err = Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\nstorage: {dialect: mysql}\n"))
+	var validation ValidationErrors
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, ValidationErrors{
+		{Field: "storage.dialect", Message: `must be one of postgres, sqlite, got "mysql"`},
+		{Field: "storage.dsn", Message: "is required"},
+	}, validation)
+}
+
+func TestParse_Cache(t *testing.T) {
+	cfg, err := Parse([]byte(`
+slack: {botToken: xoxb-1, appToken: xapp-1}
+cache:
+  backend: redis
+  profileTTL: 30m
+  redis:
+    url: rediss://:secret@redis.internal:6380/2
+    dedupTTL: 2h
+`))
+	require.NoError(t, err)
+	redisConfig := cfg.RedisConfig()
+	require.NotNil(t, redisConfig)
+	assert.Equal(t, "rediss://:secret@redis.internal:6380/2", redisConfig.URL)
+	assert.Equal(t, "quill:", redisConfig.KeyPrefix)
+	assert.Equal(t, 2*time.Hour, redisConfig.DedupTTL)
+	assert.Equal(t, 30*time.Minute, redisConfig.ProfileTTL)
+
+	cfg, err = Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\ncache: {backend: memory}\n"))
+	require.NoError(t, err)
+	assert.Nil(t, cfg.RedisConfig())
+
+	_, err = Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\ncache: {backend: redis}\n"))
+	var validation ValidationErrors
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, ValidationErrors{{Field: "cache.redis", Message: "is required by the redis backend"}}, validation)
+
+	_, err = Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\ncache: {backend: redis, redis: {url: 'http://redis:6379'}}\n"))
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, ValidationErrors{{Field: "cache.redis.url", Message: "must look like redis://host:6379/0 or rediss://host:6379/0"}}, validation)
+}
+
+func TestParse_Coordination(t *testing.T) {
+	// The leader election uses the storage database unless it has its own
+	cfg, err := Parse([]byte(`
+slack: {botToken: xoxb-1, appToken: xapp-1}
+storage: {dialect: postgres, dsn: postgres://db/quill}
+coordination: {mode: leader, lockKey: 7311, retryInterval: 10s}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &coordination.LeaderConfig{LockKey: 7311, RetryInterval: 10 * time.Second}, cfg.LeaderConfig())
+	assert.Equal(t, "postgres://db/quill", cfg.LeaderDSN())
+
+	_, err = Parse([]byte(`
+slack: {botToken: xoxb-1, appToken: xapp-1}
+storage: {dialect: sqlite, dsn: /var/lib/quill/state.db}
+coordination: {mode: leader}
+`))
+	var validation ValidationErrors
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, ValidationErrors{{Field: "coordination.dsn", Message: "is required by the leader mode without a postgres storage"}}, validation)
+
+	// Slack delivers each event to one of the connected replicas, so channels are not split by shard
+	_, err = Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\ncoordination: {mode: shard}\n"))
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, ValidationErrors{{Field: "coordination.mode", Message: `must be one of leader, got "shard"`}}, validation)
+}
+
+func TestParse_OllamaPull(t *testing.T) {
+	cfg, err := Parse([]byte(`
+slack: {botToken: xoxb-1, appToken: xapp-1}
+llm:
+  provider: ollama
+  ollama: {serverURL: "http://ollama:11434", model: llama3, verifyModel: true, autoPull: true, pullTimeout: 45m}
+`))
+	require.NoError(t, err)
+	ollamaConfig := cfg.LLMConfig().Ollama
+	assert.True(t, ollamaConfig.AutoPull)
+	assert.Equal(t, 45*time.Minute, ollamaConfig.PullTimeout)
+
+	cfg, err = Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\nllm: {provider: ollama, ollama: {serverURL: 'http://ollama:11434', model: llama3}}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, ollama.DefaultPullTimeout, cfg.LLMConfig().Ollama.PullTimeout)
+}
+
+func TestParse_ProviderSectionRequired(t *testing.T) {
+	_, err := Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1}\nllm: {provider: openai}\n"))
+
+	var validation ValidationErrors
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, ValidationErrors{{Field: "llm.openai", Message: "is required by the openai provider"}}, validation)
+}
+
+func TestParse_UnknownSetting(t *testing.T) {
+	_, err := Parse([]byte("slack: {botToken: xoxb-1, appToken: xapp-1, botTokn: xoxb-2}\n"))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), "botTokn")
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("slack:\n  botToken: xoxb-1\n  appToken: xapp-1\n"), 0o600))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Nil(t, cfg.LLMConfig())
+	assert.Nil(t, cfg.GitHubConfig())
+	assert.Nil(t, cfg.APIConfig())
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("SLACK_BOT_TOKEN", "")
+	t.Setenv("SLACK_APP_TOKEN", "")
+
+	_, err := FromEnv()
+	var validation ValidationErrors
+	require.True(t, errors.As(err, &validation))
+	assert.Len(t, validation, 2)
+
+	t.Setenv("SLACK_BOT_TOKEN", "xoxb-1")
+	t.Setenv("SLACK_APP_TOKEN", "xapp-1")
+	cfg, err := FromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-1", cfg.SlackConfig().BotToken)
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a setting breaking one of its validation rules
+type FieldError struct {
+	// Field is the path of the setting in the configuration file, like llm.openai.apiKey
+	Field string
+	// Message says what is wrong with the setting
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationErrors lists every setting of a configuration that is invalid, so they can be fixed at once
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = "  - " + err.Error()
+	}
+	problems := "problems"
+	if len(e) == 1 {
+		problems = "problem"
+	}
+	return fmt.Sprintf("%s, %d %s:\n%s", ErrInvalidConfig, len(e), problems, strings.Join(lines, "\n"))
+}
+
+// Is makes errors.Is(err, ErrInvalidConfig) hold for validation errors
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+// validateStruct checks the fields of a struct against the rules of their validate tags and adds every failure
+// to errs. Fields are named by their yaml tags, nested structs and pointers to structs are checked too.
+//
+// The rules are:
+//   - required: strings cannot be blank, slices and pointers cannot be empty, and slices cannot hold blank strings
+//   - min=N and max=N: numbers must be within the bounds
+//   - oneof=a b c: strings, when set, must be one of the values
+//   - url: strings, when set, must be http or https URLs
+func validateStruct(v reflect.Value, path string, errs *ValidationErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := fieldName(field, path)
+		value := v.Field(i)
+		rules := parseRules(field.Tag.Get("validate"))
+
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				if _, ok := rules["required"]; ok {
+					*errs = append(*errs, FieldError{Field: name, Message: "is required"})
+				}
+				continue
+			}
+			value = value.Elem()
+		}
+
+		switch value.Kind() {
+		case reflect.Struct:
+			validateStruct(value, name, errs)
+		case reflect.Slice:
+			validateSlice(value, name, rules, errs)
+		default:
+			if msg := checkValue(value, rules); msg != "" {
+				*errs = append(*errs, FieldError{Field: name, Message: msg})
+			}
+		}
+	}
+}
+
+func validateSlice(value reflect.Value, name string, rules map[string]string, errs *ValidationErrors) {
+	if _, ok := rules["required"]; ok && value.Len() == 0 {
+		*errs = append(*errs, FieldError{Field: name, Message: "is required"})
+		return
+	}
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i)
+		itemName := fmt.Sprintf("%s[%d]", name, i)
+		switch item.Kind() {
+		case reflect.Struct:
+			validateStruct(item, itemName, errs)
+		case reflect.String:
+			if _, ok := rules["required"]; ok && strings.TrimSpace(item.String()) == "" {
+				*errs = append(*errs, FieldError{Field: itemName, Message: "cannot be blank"})
+			}
+		}
+	}
+}
+
+// checkValue returns what is wrong with a string, number or bool, or an empty string when it is fine
+func checkValue(value reflect.Value, rules map[string]string) string {
+	switch value.Kind() {
+	case reflect.String:
+		s := strings.TrimSpace(value.String())
+		if s == "" {
+			if _, ok := rules["required"]; ok {
+				return "is required"
+			}
+			return ""
+		}
+		if oneOf, ok := rules["oneof"]; ok {
+			allowed := strings.Fields(oneOf)
+			for _, a := range allowed {
+				if s == a {
+					return ""
+				}
+			}
+			return fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), s)
+		}
+		if _, ok := rules["url"]; ok {
+			if u, err := url.ParseRequestURI(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Sprintf("must be an http or https URL, got %q", s)
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return checkBounds(float64(value.Int()), rules)
+	case reflect.Float32, reflect.Float64:
+		return checkBounds(value.Float(), rules)
+	}
+	return ""
+}
+
+func checkBounds(n float64, rules map[string]string) string {
+	if bound, ok := rules["min"]; ok && n < mustParseBound(bound) {
+		return fmt.Sprintf("must be at least %s, got %s", bound, formatNumber(n))
+	}
+	if bound, ok := rules["max"]; ok && n > mustParseBound(bound) {
+		return fmt.Sprintf("must be at most %s, got %s", bound, formatNumber(n))
+	}
+	return ""
+}
+
+// parseRules splits a validate tag like "required,min=0,max=1" into its rules and their arguments
+func parseRules(tag string) map[string]string {
+	rules := make(map[string]string)
+	if tag == "" {
+		return rules
+	}
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required", "min", "max", "oneof", "url":
+			rules[name] = arg
+		default:
+			panic(fmt.Sprintf("unknown validation rule %q", name))
+		}
+	}
+	return rules
+}
+
+func fieldName(field reflect.StructField, path string) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		name = field.Name
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func mustParseBound(bound string) float64 {
+	n, err := strconv.ParseFloat(bound, 64)
+	if err != nil {
+		panic(fmt.Sprintf("invalid validation bound %q", bound))
+	}
+	return n
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
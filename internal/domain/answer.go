@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrEmptyQuestion = errors.New("question cannot be empty")
+	ErrEmptyAnswer   = errors.New("answer cannot be empty")
+)
+
+// AnswerSource is a stored document used to ground an answer
+type AnswerSource struct {
+	path    string
+	title   string
+	content string
+}
+
+// NewAnswerSource creates a new AnswerSource instance
+func NewAnswerSource(path, title, content string) (*AnswerSource, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, ErrEmptyDocumentPath
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = titleFromPath(path)
+	}
+
+	return &AnswerSource{
+		path:    path,
+		title:   title,
+		content: strings.TrimSpace(content),
+	}, nil
+}
+
+// Path returns the document path in the document store
+func (s *AnswerSource) Path() string {
+	return s.path
+}
+
+// Title returns the document title
+func (s *AnswerSource) Title() string {
+	return s.title
+}
+
+// Content returns the document text given to the AI agent
+func (s *AnswerSource) Content() string {
+	return s.content
+}
+
+// Answer is a response to a question grounded in stored documents
+type Answer struct {
+	question string
+	text     string
+	sources  []*AnswerSource
+}
+
+// NewAnswer creates a new Answer instance
+func NewAnswer(question, text string, sources []*AnswerSource) (*Answer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, ErrEmptyQuestion
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrEmptyAnswer
+	}
+
+	s := make([]*AnswerSource, len(sources))
+	copy(s, sources)
+
+	return &Answer{
+		question: question,
+		text:     text,
+		sources:  s,
+	}, nil
+}
+
+// Question returns the question that was answered
+func (a *Answer) Question() string {
+	return a.question
+}
+
+// Text returns the answer text
+func (a *Answer) Text() string {
+	return a.text
+}
+
+// Sources returns the documents the answer is based on
+func (a *Answer) Sources() []*AnswerSource {
+	sources := make([]*AnswerSource, len(a.sources))
+	copy(sources, a.sources)
+	return sources
+}
+
+// HasSources checks if the answer is backed by any document
+func (a *Answer) HasSources() bool {
+	return len(a.sources) > 0
+}
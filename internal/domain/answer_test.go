@@ -0,0 +1,36 @@
+package domain
+
+import "testing"
+
+func TestNewAnswer(t *testing.T) {
+	source, err := NewAnswerSource("docs/development/adopt-postgres.md", "", "We use Postgres.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source.Title() != "adopt postgres" {
+		t.Errorf("Title() = %v, want title derived from path", source.Title())
+	}
+
+	tests := []struct {
+		name      string
+		question  string
+		text      string
+		wantError error
+	}{
+		{name: "valid answer", question: "Which database?", text: "Postgres [1]."},
+		{name: "empty question", question: " ", text: "Postgres", wantError: ErrEmptyQuestion},
+		{name: "empty answer", question: "Which database?", text: "", wantError: ErrEmptyAnswer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer, err := NewAnswer(tt.question, tt.text, []*AnswerSource{source})
+			if err != tt.wantError {
+				t.Fatalf("expected error %v, got %v", tt.wantError, err)
+			}
+			if err == nil && !answer.HasSources() {
+				t.Error("expected answer to have sources")
+			}
+		})
+	}
+}
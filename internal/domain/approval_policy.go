@@ -0,0 +1,249 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidApprovalPolicy = errors.New("invalid approval policy")
+	ErrInvalidApproval       = errors.New("invalid approval")
+)
+
+// ApprovalPolicy requires decisions to be approved by people other than their author before they are documented
+type ApprovalPolicy struct {
+	// Categories are the categories of the decisions the policy applies to, all decisions when empty
+	Categories []Category `json:"categories,omitempty"`
+	// Approvals is how many people must approve the decision, defaults to 1
+	Approvals int `json:"approvals,omitempty"`
+	// Role limits the approvals that count to the people of the role, anyone's approval counts when empty
+	Role string `json:"role,omitempty"`
+}
+
+// Validate ensures the policy can be met
+func (p ApprovalPolicy) Validate() error {
+	if p.Approvals < 0 {
+		return fmt.Errorf("%w: approvals cannot be negative", ErrInvalidApprovalPolicy)
+	}
+	for _, category := range p.Categories {
+		if !category.IsValid() || category == CategoryUnknown {
+			return fmt.Errorf("%w: unknown category %q", ErrInvalidApprovalPolicy, category)
+		}
+	}
+	if p.Role != strings.TrimSpace(p.Role) {
+		return fmt.Errorf("%w: role %q cannot start or end with spaces", ErrInvalidApprovalPolicy, p.Role)
+	}
+	return nil
+}
+
+// Required returns how many approvals the policy needs
+func (p ApprovalPolicy) Required() int {
+	if p.Approvals <= 0 {
+		return 1
+	}
+	return p.Approvals
+}
+
+// Applies checks if a message needs the policy's approvals, only decisions do
+func (p ApprovalPolicy) Applies(msg *Message) bool {
+	if msg == nil || !msg.Type().IsDecision() {
+		return false
+	}
+	if len(p.Categories) == 0 {
+		return true
+	}
+	for _, category := range p.Categories {
+		if category == msg.Category() {
+			return true
+		}
+	}
+	return false
+}
+
+// describe tells what the policy needs in a chat reply, like "2 approvals from an architect"
+func (p ApprovalPolicy) describe(count int) string {
+	noun := "approvals"
+	if count == 1 {
+		noun = "approval"
+	}
+	if p.Role == "" {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %s from %s", count, noun, withArticle(p.Role))
+}
+
+func (p ApprovalPolicy) clone() ApprovalPolicy {
+	p.Categories = append([]Category(nil), p.Categories...)
+	return p
+}
+
+// ApprovalRequest is a decision waiting for the approvals its project's policies require before it is documented
+type ApprovalRequest struct {
+	msg         *Message
+	policies    []ApprovalPolicy
+	roles       map[string][]string
+	approvers   []string
+	requestedAt time.Time
+}
+
+// NewApprovalRequest creates an ApprovalRequest for the policies of the documentation config that apply to the
+// message. It returns nil when none does.
+func NewApprovalRequest(msg *Message, config DocumentationConfig, at time.Time) *ApprovalRequest {
+	var policies []ApprovalPolicy
+	for _, policy := range config.ApprovalPolicies {
+		if policy.Applies(msg) {
+			policies = append(policies, policy.clone())
+		}
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	return &ApprovalRequest{
+		msg:         msg,
+		policies:    policies,
+		roles:       cloneRoles(config.Roles),
+		requestedAt: at,
+	}
+}
+
+// Message returns the decision waiting for approval
+func (r *ApprovalRequest) Message() *Message {
+	return r.msg
+}
+
+// RequestedAt returns when the decision started waiting for approval
+func (r *ApprovalRequest) RequestedAt() time.Time {
+	return r.requestedAt
+}
+
+// Approvers returns who approved the decision so far, in the order they did
+func (r *ApprovalRequest) Approvers() []string {
+	return append([]string(nil), r.approvers...)
+}
+
+// Approve records the approval of a person. Authors cannot approve their own decisions, and people approve once.
+func (r *ApprovalRequest) Approve(actor string) error {
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return fmt.Errorf("%w: approver must have a name", ErrInvalidApproval)
+	}
+	if actor == r.msg.Sender() {
+		return fmt.Errorf("%w: %s cannot approve their own decision", ErrInvalidApproval, actor)
+	}
+	for _, approver := range r.approvers {
+		if approver == actor {
+			return fmt.Errorf("%w: %s already approved this decision", ErrInvalidApproval, actor)
+		}
+	}
+	r.approvers = append(r.approvers, actor)
+	return nil
+}
+
+// Satisfied checks if every policy got the approvals it requires
+func (r *ApprovalRequest) Satisfied() bool {
+	for _, policy := range r.policies {
+		if r.counted(policy) < policy.Required() {
+			return false
+		}
+	}
+	return true
+}
+
+// Progress returns the approvals counted towards the policies out of those they require, like "1/2"
+func (r *ApprovalRequest) Progress() string {
+	counted, required := 0, 0
+	for _, policy := range r.policies {
+		counted += min(r.counted(policy), policy.Required())
+		required += policy.Required()
+	}
+	return fmt.Sprintf("%d/%d", counted, required)
+}
+
+// Missing describes the approvals the decision still needs, empty once it is satisfied
+func (r *ApprovalRequest) Missing() string {
+	var missing []string
+	for _, policy := range r.policies {
+		if left := policy.Required() - r.counted(policy); left > 0 {
+			missing = append(missing, policy.describe(left))
+		}
+	}
+	return strings.Join(missing, " and ")
+}
+
+// counted returns how many of the approvals count towards a policy
+func (r *ApprovalRequest) counted(policy ApprovalPolicy) int {
+	if policy.Role == "" {
+		return len(r.approvers)
+	}
+	count := 0
+	for _, approver := range r.approvers {
+		if hasRole(r.roles, policy.Role, approver) {
+			count++
+		}
+	}
+	return count
+}
+
+// Erase removes the person from the approvers of the decision, or replaces them with their pseudonym, and
+// pseudonymizes the decision when they sent it. It reports if the person was named.
+func (r *ApprovalRequest) Erase(request *ErasureRequest) bool {
+	identity := request.Identity()
+	changed := request.ApplyToMessage(r.msg)
+
+	approvers := make([]string, 0, len(r.approvers))
+	for _, approver := range r.approvers {
+		switch {
+		case approver != identity:
+			approvers = append(approvers, approver)
+		case request.Mode() == ErasurePseudonymize:
+			approvers = append(approvers, request.Pseudonym())
+			changed = true
+		default:
+			changed = true
+		}
+	}
+	r.approvers = approvers
+
+	// The copied roles follow the approvers, so pseudonymized approvals keep counting
+	if request.Mode() == ErasurePseudonymize {
+		for _, members := range r.roles {
+			for i, member := range members {
+				if member == identity {
+					members[i] = request.Pseudonym()
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// hasRole checks if a person is one of the members of a role
+func hasRole(roles map[string][]string, role, person string) bool {
+	for _, member := range roles[role] {
+		if member == person {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneRoles(roles map[string][]string) map[string][]string {
+	if roles == nil {
+		return nil
+	}
+	cloned := make(map[string][]string, len(roles))
+	for role, members := range roles {
+		cloned[role] = append([]string(nil), members...)
+	}
+	return cloned
+}
+
+// withArticle prefixes a noun with "a" or "an"
+func withArticle(noun string) string {
+	if strings.ContainsRune("aeiouAEIOU", rune(noun[0])) {
+		return "an " + noun
+	}
+	return "a " + noun
+}
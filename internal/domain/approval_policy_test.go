@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalPolicy_Applies(t *testing.T) {
+	decision, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+	require.NoError(t, err)
+	idea, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("We could use Postgres"), MessageTypeIdea, CategoryDevelopment, nil)
+	require.NoError(t, err)
+
+	assert.True(t, ApprovalPolicy{}.Applies(decision))
+	assert.True(t, ApprovalPolicy{Categories: []Category{CategoryProduct, CategoryDevelopment}}.Applies(decision))
+	assert.False(t, ApprovalPolicy{Categories: []Category{CategoryProduct}}.Applies(decision))
+	assert.False(t, ApprovalPolicy{}.Applies(idea), "only decisions need approval")
+
+	assert.Equal(t, 1, ApprovalPolicy{}.Required())
+	assert.Equal(t, 3, ApprovalPolicy{Approvals: 3}.Required())
+}
+
+func TestNewApprovalRequest(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+	require.NoError(t, err)
+	at := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, NewApprovalRequest(msg, DocumentationConfig{}, at))
+	assert.Nil(t, NewApprovalRequest(msg, DocumentationConfig{ApprovalPolicies: []ApprovalPolicy{{Categories: []Category{CategoryProduct}}}}, at))
+
+	request := NewApprovalRequest(msg, DocumentationConfig{ApprovalPolicies: []ApprovalPolicy{{}}}, at)
+	require.NotNil(t, request)
+	assert.Same(t, msg, request.Message())
+	assert.Equal(t, at, request.RequestedAt())
+}
+
+func TestApprovalRequest_Approve(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+	require.NoError(t, err)
+	config := DocumentationConfig{
+		ApprovalPolicies: []ApprovalPolicy{{Approvals: 2}, {Role: "architect"}},
+		Roles:            map[string][]string{"architect": {"carol"}},
+	}
+	request := NewApprovalRequest(msg, config, time.Now())
+	require.NotNil(t, request)
+
+	assert.False(t, request.Satisfied())
+	assert.Equal(t, "0/3", request.Progress())
+	assert.Equal(t, "2 approvals and 1 approval from an architect", request.Missing())
+
+	assert.ErrorIs(t, request.Approve("alice"), ErrInvalidApproval, "authors cannot approve their own decisions")
+	require.NoError(t, request.Approve("bob"))
+	assert.ErrorIs(t, request.Approve("bob"), ErrInvalidApproval, "people approve once")
+	assert.Equal(t, "1/3", request.Progress())
+	assert.Equal(t, "1 approval and 1 approval from an architect", request.Missing())
+
+	require.NoError(t, request.Approve("dave"))
+	assert.False(t, request.Satisfied(), "the architect has not approved yet")
+	assert.Equal(t, "1 approval from an architect", request.Missing())
+
+	require.NoError(t, request.Approve("carol"))
+	assert.True(t, request.Satisfied())
+	assert.Equal(t, "3/3", request.Progress())
+	assert.Empty(t, request.Missing())
+	assert.Equal(t, []string{"bob", "dave", "carol"}, request.Approvers())
+}
+
+func TestApprovalRequest_Erase(t *testing.T) {
+	config := DocumentationConfig{
+		ApprovalPolicies: []ApprovalPolicy{{Approvals: 2}, {Role: "architect"}},
+		Roles:            map[string][]string{"architect": {"carol"}},
+	}
+	newRequest := func(t *testing.T, sender string) *ApprovalRequest {
+		msg, err := NewMessage(common.GenerateID(), sender, MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+		require.NoError(t, err)
+		request := NewApprovalRequest(msg, config, time.Now())
+		require.NoError(t, request.Approve("carol"))
+		return request
+	}
+
+	t.Run("erase", func(t *testing.T) {
+		request := newRequest(t, "alice")
+		erasure, err := NewErasureRequest("carol", ErasureErase, "dpo")
+		require.NoError(t, err)
+
+		assert.True(t, request.Erase(erasure))
+		assert.Empty(t, request.Approvers())
+		assert.Equal(t, "0/3", request.Progress())
+		assert.False(t, request.Erase(erasure), "the person is no longer named")
+	})
+
+	t.Run("pseudonymize", func(t *testing.T) {
+		request := newRequest(t, "alice")
+		erasure, err := NewErasureRequest("carol", ErasurePseudonymize, "dpo")
+		require.NoError(t, err)
+
+		assert.True(t, request.Erase(erasure))
+		assert.Equal(t, "alice", request.Message().Sender())
+		assert.Equal(t, []string{erasure.Pseudonym()}, request.Approvers())
+		assert.Equal(t, "2/3", request.Progress(), "the pseudonymized approval keeps counting")
+
+		author, err := NewErasureRequest("alice", ErasurePseudonymize, "dpo")
+		require.NoError(t, err)
+		assert.True(t, request.Erase(author))
+		assert.Equal(t, author.Pseudonym(), request.Message().Sender())
+	})
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+var ErrAssetTooLarge = errors.New("asset is too large to store")
+
+const (
+	// DefaultMaxAssetBytes is the largest file stored with a document
+	DefaultMaxAssetBytes = 1 << 20
+	// DefaultMaxImageDimension is the longest side, in pixels, of an image stored with a document
+	DefaultMaxImageDimension = 2048
+	// minImageDimension is the longest side images are downscaled to at most, smaller ones are unreadable
+	minImageDimension = 256
+	// jpegQuality is the quality downscaled JPEG images are encoded with
+	jpegQuality = 85
+)
+
+// AssetLimits bound the files stored with documents, keeping the repository small. Images above the limits
+// are downscaled until they fit, other files above them are not stored.
+type AssetLimits struct {
+	// MaxBytes is the largest file stored, defaults to 1MB
+	MaxBytes int `json:"maxBytes,omitempty"`
+	// MaxDimension is the longest side of a stored image in pixels, defaults to 2048
+	MaxDimension int `json:"maxDimension,omitempty"`
+}
+
+// Validate ensures the limits are usable
+func (l AssetLimits) Validate() error {
+	if l.MaxBytes < 0 || l.MaxDimension < 0 {
+		return fmt.Errorf("asset limits cannot be negative")
+	}
+	if l.MaxDimension > 0 && l.MaxDimension < minImageDimension {
+		return fmt.Errorf("images cannot be limited below %d pixels", minImageDimension)
+	}
+	return nil
+}
+
+func (l AssetLimits) maxBytes() int {
+	if l.MaxBytes <= 0 {
+		return DefaultMaxAssetBytes
+	}
+	return l.MaxBytes
+}
+
+func (l AssetLimits) maxDimension() int {
+	if l.MaxDimension <= 0 {
+		return DefaultMaxImageDimension
+	}
+	return l.MaxDimension
+}
+
+// FitImage returns an image within the limits, downscaling PNG, JPEG and GIF images that are larger and keeping
+// their format. Images already within the limits are returned as they are. It fails with ErrAssetTooLarge when
+// an image cannot be downscaled enough, or is in another format.
+func FitImage(data []byte, limits AssetLimits) ([]byte, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if len(data) > limits.maxBytes() {
+			return nil, fmt.Errorf("%w: %d bytes", ErrAssetTooLarge, len(data))
+		}
+		// Images the standard library cannot read are stored as they are
+		return data, nil
+	}
+
+	longest := max(config.Width, config.Height)
+	if len(data) <= limits.maxBytes() && longest <= limits.maxDimension() {
+		return data, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s image: %w", format, err)
+	}
+	for side := min(longest, limits.maxDimension()); side >= minImageDimension; side = side * 3 / 4 {
+		fitted, err := encodeImage(downscale(src, side), format)
+		if err != nil {
+			return nil, err
+		}
+		if len(fitted) <= limits.maxBytes() {
+			return fitted, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d bytes even at %d pixels", ErrAssetTooLarge, len(data), minImageDimension)
+}
+
+// downscale shrinks an image so its longest side is at most the given size, averaging the pixels each
+// pixel of the result covers
+func downscale(src image.Image, side int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if max(width, height) <= side {
+		return src
+	}
+	dstWidth, dstHeight := max(1, width*side/max(width, height)), max(1, height*side/max(width, height))
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := y*height/dstHeight, max((y+1)*height/dstHeight, y*height/dstHeight+1)
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := x*width/dstWidth, max((x+1)*width/dstWidth, x*width/dstWidth+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBAModel.Convert(src.At(bounds.Min.X+sx, bounds.Min.Y+sy)).(color.NRGBA)
+					r, g, b, a = r+uint64(c.R), g+uint64(c.G), b+uint64(c.B), a+uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
+
+// encodeImage encodes an image in the format it was read in
+func encodeImage(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		return nil, fmt.Errorf("%w: %s images cannot be downscaled", ErrAssetTooLarge, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s image: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
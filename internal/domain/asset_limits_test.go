@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noisyImage returns an image that compresses poorly, like a photo
+func noisyImage(width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	random := rand.New(rand.NewSource(1))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(random.Intn(256)), G: uint8(random.Intn(256)), B: uint8(random.Intn(256)), A: 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestFitImage_KeepsImagesWithinLimits(t *testing.T) {
+	data := encodePNG(t, noisyImage(64, 32))
+
+	fitted, err := FitImage(data, AssetLimits{})
+
+	require.NoError(t, err)
+	assert.Equal(t, data, fitted)
+}
+
+func TestFitImage_DownscalesLargeImages(t *testing.T) {
+	data := encodePNG(t, noisyImage(1200, 600))
+
+	fitted, err := FitImage(data, AssetLimits{MaxDimension: 400})
+
+	require.NoError(t, err)
+	config, format, err := image.DecodeConfig(bytes.NewReader(fitted))
+	require.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, 400, config.Width)
+	assert.Equal(t, 200, config.Height)
+}
+
+func TestFitImage_ShrinksImagesToMaxBytes(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, noisyImage(1000, 1000), &jpeg.Options{Quality: 100}))
+	limits := AssetLimits{MaxBytes: 100 << 10}
+	require.Greater(t, buf.Len(), limits.MaxBytes)
+
+	fitted, err := FitImage(buf.Bytes(), limits)
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(fitted), limits.MaxBytes)
+	config, format, err := image.DecodeConfig(bytes.NewReader(fitted))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Less(t, config.Width, 1000)
+}
+
+func TestFitImage_TooLarge(t *testing.T) {
+	_, err := FitImage(encodePNG(t, noisyImage(600, 600)), AssetLimits{MaxBytes: 1 << 10})
+	assert.ErrorIs(t, err, ErrAssetTooLarge)
+
+	// Files the standard library cannot read are kept up to the byte limit
+	unreadable := bytes.Repeat([]byte("x"), 2<<10)
+	fitted, err := FitImage(unreadable, AssetLimits{})
+	require.NoError(t, err)
+	assert.Equal(t, unreadable, fitted)
+	_, err = FitImage(unreadable, AssetLimits{MaxBytes: 1 << 10})
+	assert.ErrorIs(t, err, ErrAssetTooLarge)
+}
+
+func TestAssetLimits_Validate(t *testing.T) {
+	assert.NoError(t, AssetLimits{}.Validate())
+	assert.NoError(t, AssetLimits{MaxBytes: 512 << 10, MaxDimension: 1024}.Validate())
+	assert.Error(t, AssetLimits{MaxBytes: -1}.Validate())
+	assert.Error(t, AssetLimits{MaxDimension: 100}.Validate())
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidAttachment = errors.New("attachment needs a media type and an address")
+
+// Attachment is a file shared with a message, like a screenshot or a whiteboard photo. It is known by the
+// address the chat provider serves it from; the file itself is only fetched when it is needed.
+type Attachment struct {
+	name     string
+	mimeType string
+	url      string
+}
+
+// NewAttachment creates an Attachment. A missing name becomes "attachment".
+func NewAttachment(name, mimeType, url string) (*Attachment, error) {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	url = strings.TrimSpace(url)
+	if mimeType == "" || url == "" {
+		return nil, ErrInvalidAttachment
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "attachment"
+	}
+	return &Attachment{name: name, mimeType: mimeType, url: url}, nil
+}
+
+// Name returns the file name of the attachment
+func (a *Attachment) Name() string {
+	return a.name
+}
+
+// MimeType returns the media type of the attachment, like "image/png"
+func (a *Attachment) MimeType() string {
+	return a.mimeType
+}
+
+// URL returns the address the chat provider serves the attachment from
+func (a *Attachment) URL() string {
+	return a.url
+}
+
+// IsImage reports whether the attachment is an image a vision model can read
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.mimeType, "image/")
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAttachment(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileName  string
+		mimeType  string
+		url       string
+		wantName  string
+		wantImage bool
+		wantErr   error
+	}{
+		{name: "image", fileName: "whiteboard.jpg", mimeType: "image/jpeg", url: "https://files.example.com/1", wantName: "whiteboard.jpg", wantImage: true},
+		{name: "upper case media type", fileName: "Screenshot.PNG", mimeType: " IMAGE/PNG", url: "https://files.example.com/2", wantName: "Screenshot.PNG", wantImage: true},
+		{name: "document", fileName: "plan.pdf", mimeType: "application/pdf", url: "https://files.example.com/3", wantName: "plan.pdf"},
+		{name: "unnamed", mimeType: "image/gif", url: "https://files.example.com/4", wantName: "attachment", wantImage: true},
+		{name: "missing media type", fileName: "x.png", url: "https://files.example.com/5", wantErr: ErrInvalidAttachment},
+		{name: "missing address", fileName: "x.png", mimeType: "image/png", wantErr: ErrInvalidAttachment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachment, err := NewAttachment(tt.fileName, tt.mimeType, tt.url)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, attachment.Name())
+			assert.Equal(t, tt.wantImage, attachment.IsImage())
+		})
+	}
+}
+
+func TestMessage_Images(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("See the diagram"), MessageTypeIdea, CategoryProduct, nil)
+	require.NoError(t, err)
+
+	diagram, _ := NewAttachment("diagram.png", "image/png", "https://files.example.com/diagram.png")
+	notes, _ := NewAttachment("notes.pdf", "application/pdf", "https://files.example.com/notes.pdf")
+	msg.AddAttachments(diagram, nil, notes)
+
+	assert.Len(t, msg.Attachments(), 2)
+	assert.Equal(t, []*Attachment{diagram}, msg.Images())
+}
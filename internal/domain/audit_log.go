@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+var ErrInvalidAuditEntry = errors.New("invalid audit entry")
+
+// AuditAction is a change made by a person that the audit log keeps track of
+type AuditAction string
+
+const (
+	// AuditActionRecategorize files a document under another category
+	AuditActionRecategorize AuditAction = "recategorize"
+	// AuditActionReview reconfirms or supersedes a document flagged for review
+	AuditActionReview AuditAction = "review"
+	// AuditActionErase erases or pseudonymizes the data stored about a person, the subject is their pseudonym
+	AuditActionErase AuditAction = "erase"
+	// AuditActionPurge deletes or anonymizes a raw message kept longer than the retention period
+	AuditActionPurge AuditAction = "purge"
+	// AuditActionModerate approves or rejects a message moderation held for review
+	AuditActionModerate AuditAction = "moderate"
+	// AuditActionReprocess runs documented messages through the current prompt again, the subject is the selection
+	AuditActionReprocess AuditAction = "reprocess"
+	// AuditActionApprove approves or rejects a decision held by an approval policy, from and to are its progress
+	AuditActionApprove AuditAction = "approve"
+	// AuditActionReassign gives a document another owner, the subject is its path
+	AuditActionReassign AuditAction = "reassign"
+	// AuditActionRetry queues a dead-lettered message again, the subject is its ID and from the error it failed with
+	AuditActionRetry AuditAction = "retry"
+	// AuditActionDiscard drops a dead-lettered message for good, the subject is its ID and from the error it failed with
+	AuditActionDiscard AuditAction = "discard"
+)
+
+// String returns the audit action
+func (a AuditAction) String() string {
+	return string(a)
+}
+
+// AuditEntry records who changed what, and from which value to which
+type AuditEntry struct {
+	id      common.ID
+	action  AuditAction
+	actor   string
+	subject string
+	from    string
+	to      string
+	at      time.Time
+	// correlationID ties the entry to the logs of the message or API request that made the change
+	correlationID CorrelationID
+}
+
+// NewAuditEntry creates an AuditEntry of a change made now
+func NewAuditEntry(action AuditAction, actor, subject, from, to string) (*AuditEntry, error) {
+	if strings.TrimSpace(string(action)) == "" {
+		return nil, ErrInvalidAuditEntry
+	}
+	actor = strings.TrimSpace(actor)
+	subject = strings.TrimSpace(subject)
+	if actor == "" || subject == "" {
+		return nil, ErrInvalidAuditEntry
+	}
+
+	return &AuditEntry{
+		id:      common.GenerateID(),
+		action:  action,
+		actor:   actor,
+		subject: subject,
+		from:    from,
+		to:      to,
+		at:      time.Now().UTC(),
+	}, nil
+}
+
+// RestoreAuditEntry recreates a stored audit entry
+func RestoreAuditEntry(id common.ID, action AuditAction, actor, subject, from, to string, at time.Time) (*AuditEntry, error) {
+	if strings.TrimSpace(string(action)) == "" || strings.TrimSpace(actor) == "" || strings.TrimSpace(subject) == "" {
+		return nil, ErrInvalidAuditEntry
+	}
+	return &AuditEntry{
+		id:      id,
+		action:  action,
+		actor:   actor,
+		subject: subject,
+		from:    from,
+		to:      to,
+		at:      at,
+	}, nil
+}
+
+// ID returns the entry's identifier
+func (e *AuditEntry) ID() common.ID {
+	return e.id
+}
+
+// Action returns what was done
+func (e *AuditEntry) Action() AuditAction {
+	return e.action
+}
+
+// Actor returns who made the change
+func (e *AuditEntry) Actor() string {
+	return e.actor
+}
+
+// Subject returns what was changed, like a document path
+func (e *AuditEntry) Subject() string {
+	return e.subject
+}
+
+// From returns the value before the change
+func (e *AuditEntry) From() string {
+	return e.from
+}
+
+// To returns the value after the change
+func (e *AuditEntry) To() string {
+	return e.to
+}
+
+// At returns when the change was made
+func (e *AuditEntry) At() time.Time {
+	return e.at
+}
+
+// CorrelationID returns the correlation ID of the message or API request that made the change, empty when
+// it was made otherwise
+func (e *AuditEntry) CorrelationID() CorrelationID {
+	return e.correlationID
+}
+
+// SetCorrelationID records the correlation ID of the message or API request that made the change
+func (e *AuditEntry) SetCorrelationID(id CorrelationID) {
+	e.correlationID = id
+}
+
+// WithActor returns a copy of the entry made by another actor, for erasing the identity of the original one
+func (e *AuditEntry) WithActor(actor string) *AuditEntry {
+	entry := *e
+	entry.actor = actor
+	return &entry
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "strings"
+
+// BacklinksHeading starts the section listing what references a document, the bot rewrites it on each write
+const BacklinksHeading = "## Backlinks"
+
+// StripBacklinks removes the Backlinks section of a document, keeping the sections that follow it
+func StripBacklinks(content string) string {
+	idx := strings.Index(content, BacklinksHeading)
+	if idx < 0 {
+		return content
+	}
+
+	rest := content[idx+len(BacklinksHeading):]
+	if next := strings.Index(rest, "\n## "); next >= 0 {
+		return content[:idx] + strings.TrimLeft(rest[next:], "\n")
+	}
+	return strings.TrimRight(content[:idx], "\n") + "\n"
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestStripBacklinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no backlinks",
+			content: "# Adopt Postgres\n\nThe team will use Postgres.\n",
+			want:    "# Adopt Postgres\n\nThe team will use Postgres.\n",
+		},
+		{
+			name:    "trailing section",
+			content: "# Adopt Postgres\n\nThe team will use Postgres.\n\n## Backlinks\n\n- [docs/a.md](/docs/a.md)\n",
+			want:    "# Adopt Postgres\n\nThe team will use Postgres.\n",
+		},
+		{
+			name:    "section followed by another",
+			content: "# Adopt Postgres\n\n## Backlinks\n\n- message:m1\n\n## Images\n\n![schema](schema.png)\n",
+			want:    "# Adopt Postgres\n\n## Images\n\n![schema](schema.png)\n",
+		},
+		{
+			name:    "empty section",
+			content: "# Adopt Postgres\n\n## Backlinks",
+			want:    "# Adopt Postgres\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripBacklinks(tt.content); got != tt.want {
+				t.Errorf("StripBacklinks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// CommandPrefix is the prefix that turns a message into a bot command
+	CommandPrefix = "/quill"
+)
+
+var (
+	ErrNotACommand  = errors.New("message is not a command")
+	ErrEmptyCommand = errors.New("command name is required")
+)
+
+// Command represents a bot command such as "/quill tags"
+type Command struct {
+	name string
+	args []string
+}
+
+// IsCommand checks if the text is a bot command
+func IsCommand(text string) bool {
+	fields := strings.Fields(text)
+	return len(fields) > 0 && strings.EqualFold(fields[0], CommandPrefix)
+}
+
+// ParseCommand creates a Command from message text
+func ParseCommand(text string) (*Command, error) {
+	if !IsCommand(text) {
+		return nil, ErrNotACommand
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		return nil, ErrEmptyCommand
+	}
+
+	return &Command{
+		name: strings.ToLower(fields[1]),
+		args: fields[2:],
+	}, nil
+}
+
+// Name returns the command name
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Args returns the command arguments
+func (c *Command) Args() []string {
+	args := make([]string, len(c.args))
+	copy(args, c.args)
+	return args
+}
+
+// Arg returns the argument at position i or an empty string
+func (c *Command) Arg(i int) string {
+	if i < 0 || i >= len(c.args) {
+		return ""
+	}
+	return c.args[i]
+}
+
+// ArgCount returns the number of arguments
+func (c *Command) ArgCount() int {
+	return len(c.args)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantName  string
+		wantArgs  []string
+		wantError error
+	}{
+		{
+			name:     "command without arguments",
+			input:    "/quill tags",
+			wantName: "tags",
+			wantArgs: []string{},
+		},
+		{
+			name:     "command with arguments",
+			input:    "  /Quill TAG add docs/idea.md postgres ",
+			wantName: "tag",
+			wantArgs: []string{"add", "docs/idea.md", "postgres"},
+		},
+		{
+			name:      "regular message",
+			input:     "we should use /quill more",
+			wantError: ErrNotACommand,
+		},
+		{
+			name:      "prefix only",
+			input:     "/quill",
+			wantError: ErrEmptyCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := ParseCommand(tt.input)
+			if tt.wantError != nil {
+				if err != tt.wantError {
+					t.Errorf("expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cmd.Name() != tt.wantName {
+				t.Errorf("Name() = %v, want %v", cmd.Name(), tt.wantName)
+			}
+			if !reflect.DeepEqual(cmd.Args(), tt.wantArgs) {
+				t.Errorf("Args() = %v, want %v", cmd.Args(), tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// UnknownModel groups the messages analysed before the model was recorded
+const UnknownModel = "unknown"
+
+// OverrideBin compares the confidence a model reported with how often people overrode its analysis
+type OverrideBin struct {
+	// Lower and Upper bound the confidence scores in the bin, Upper is exclusive except for the last bin
+	Lower, Upper float64
+	Count        int
+	// Overridden counts the analyses people corrected
+	Overridden int
+	// Confidence is the mean reported confidence
+	Confidence float64
+}
+
+// OverrideRate returns the share of analyses in the bin people corrected
+func (b OverrideBin) OverrideRate() float64 {
+	if b.Count == 0 {
+		return 0
+	}
+	return float64(b.Overridden) / float64(b.Count)
+}
+
+// ModelCalibration is the calibration of one provider and model on the messages it analysed in production
+type ModelCalibration struct {
+	// Model is the provider and model, like "ollama:llama3"
+	Model      string
+	Analyzed   int
+	Overridden int
+	// Confidence is the mean reported confidence
+	Confidence float64
+	// Bins are the equally wide confidence bins holding analyses, lowest first
+	Bins []OverrideBin
+}
+
+// OverrideRate returns the share of the model's analyses people corrected
+func (m ModelCalibration) OverrideRate() float64 {
+	if m.Analyzed == 0 {
+		return 0
+	}
+	return float64(m.Overridden) / float64(m.Analyzed)
+}
+
+// SuggestedThreshold returns the lowest bin bound at which the analyses from that confidence up were
+// overridden at most maxOverrideRate of the time, a candidate for AutoDetectionConfig.MinConfidence.
+// It reports false when no bin meets the rate.
+func (m ModelCalibration) SuggestedThreshold(maxOverrideRate float64) (float64, bool) {
+	count, overridden := 0, 0
+	threshold, found := 0.0, false
+	for i := len(m.Bins) - 1; i >= 0; i-- {
+		count += m.Bins[i].Count
+		overridden += m.Bins[i].Overridden
+		if float64(overridden)/float64(count) <= maxOverrideRate {
+			threshold, found = m.Bins[i].Lower, true
+		}
+	}
+	return threshold, found
+}
+
+// NewCalibrationReport compares the confidence of every analysed message with the corrections people made
+// to it, per model. Messages without confidence were not analysed and are left out.
+func NewCalibrationReport(messages []*Message, corrections []*Correction, bins int) []ModelCalibration {
+	if bins <= 0 {
+		return nil
+	}
+
+	corrected := make(map[string]bool, len(corrections))
+	for _, c := range corrections {
+		corrected[c.MessageID()] = true
+	}
+
+	type modelBins struct {
+		bins       []OverrideBin
+		analyzed   int
+		overridden int
+		confidence float64
+	}
+	models := make(map[string]*modelBins)
+	for _, msg := range messages {
+		if msg == nil || msg.Confidence() <= 0 {
+			continue
+		}
+		name := msg.Model()
+		if name == "" {
+			name = UnknownModel
+		}
+		m, ok := models[name]
+		if !ok {
+			m = &modelBins{bins: make([]OverrideBin, bins)}
+			for i := range m.bins {
+				m.bins[i].Lower = float64(i) / float64(bins)
+				m.bins[i].Upper = float64(i+1) / float64(bins)
+			}
+			models[name] = m
+		}
+
+		i := int(math.Min(msg.Confidence()*float64(bins), float64(bins-1)))
+		m.bins[i].Count++
+		m.bins[i].Confidence += msg.Confidence()
+		m.analyzed++
+		m.confidence += msg.Confidence()
+		if corrected[msg.ID().String()] {
+			m.bins[i].Overridden++
+			m.overridden++
+		}
+	}
+
+	report := make([]ModelCalibration, 0, len(models))
+	for name, m := range models {
+		calibration := ModelCalibration{
+			Model:      name,
+			Analyzed:   m.analyzed,
+			Overridden: m.overridden,
+			Confidence: m.confidence / float64(m.analyzed),
+		}
+		for _, bin := range m.bins {
+			if bin.Count == 0 {
+				continue
+			}
+			bin.Confidence /= float64(bin.Count)
+			calibration.Bins = append(calibration.Bins, bin)
+		}
+		report = append(report, calibration)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Model < report[j].Model
+	})
+	return report
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCalibrationReport(t *testing.T) {
+	analysed := func(model string, confidence float64) *Message {
+		msg, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+		require.NoError(t, err)
+		msg.RecordModel(model)
+		msg.RecordConfidence(confidence)
+		return msg
+	}
+	correct := func(msg *Message) *Correction {
+		c, err := NewCorrection(msg, CorrectionFieldType, "decision", "idea", "U0001")
+		require.NoError(t, err)
+		return c
+	}
+
+	low := analysed("ollama:llama3", 0.55)
+	lowToo := analysed("ollama:llama3", 0.6)
+	high := analysed("ollama:llama3", 0.9)
+	certain := analysed("ollama:llama3", 1)
+	legacy := analysed("", 0.8)
+	notAnalysed := analysed("ollama:llama3", 0)
+
+	report := NewCalibrationReport(
+		[]*Message{low, lowToo, high, certain, legacy, notAnalysed, nil},
+		[]*Correction{correct(low), correct(low), correct(lowToo), correct(legacy)},
+		5,
+	)
+
+	require.Len(t, report, 2)
+	llama := report[0]
+	assert.Equal(t, "ollama:llama3", llama.Model)
+	assert.Equal(t, 4, llama.Analyzed)
+	assert.Equal(t, 2, llama.Overridden)
+	assert.InDelta(t, 0.5, llama.OverrideRate(), 0.0001)
+	assert.InDelta(t, 0.7625, llama.Confidence, 0.0001)
+
+	require.Len(t, llama.Bins, 3)
+	assert.InDelta(t, 0.4, llama.Bins[0].Lower, 0.0001)
+	assert.Equal(t, 1, llama.Bins[0].Count)
+	assert.InDelta(t, 1.0, llama.Bins[0].OverrideRate(), 0.0001)
+	assert.Equal(t, 1, llama.Bins[1].Overridden)
+	assert.Equal(t, 2, llama.Bins[2].Count, "a confidence of 1 falls in the last bin")
+	assert.Zero(t, llama.Bins[2].OverrideRate())
+
+	threshold, ok := llama.SuggestedThreshold(0.1)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.8, threshold, 0.0001)
+	threshold, ok = llama.SuggestedThreshold(0.5)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.4, threshold, 0.0001)
+
+	assert.Equal(t, UnknownModel, report[1].Model)
+	_, ok = report[1].SuggestedThreshold(0.1)
+	assert.False(t, ok)
+}
+
+func TestNewCalibrationReport_NoBins(t *testing.T) {
+	assert.Nil(t, NewCalibrationReport(nil, nil, 0))
+	assert.Zero(t, ModelCalibration{}.OverrideRate())
+	assert.Zero(t, OverrideBin{}.OverrideRate())
+}
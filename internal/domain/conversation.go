@@ -0,0 +1,95 @@
+package domain
+
+import "strings"
+
+// ConversationTurn is a question asked in a thread together with the answer it received
+type ConversationTurn struct {
+	question string
+	answer   string
+	sources  []string
+}
+
+// NewConversationTurn creates a new ConversationTurn instance
+func NewConversationTurn(question, answer string, sources []string) (*ConversationTurn, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, ErrEmptyQuestion
+	}
+
+	s := make([]string, len(sources))
+	copy(s, sources)
+
+	return &ConversationTurn{
+		question: question,
+		answer:   strings.TrimSpace(answer),
+		sources:  s,
+	}, nil
+}
+
+// Question returns the question that was asked
+func (t *ConversationTurn) Question() string {
+	return t.question
+}
+
+// Answer returns the answer given, if any
+func (t *ConversationTurn) Answer() string {
+	return t.answer
+}
+
+// Sources returns the paths of the documents the answer was based on
+func (t *ConversationTurn) Sources() []string {
+	sources := make([]string, len(t.sources))
+	copy(sources, t.sources)
+	return sources
+}
+
+// Conversation is the short-term memory of questions asked in a thread
+type Conversation struct {
+	turns []*ConversationTurn
+}
+
+// NewConversation creates a Conversation keeping only the most recent turns
+func NewConversation(turns []*ConversationTurn, limit int) *Conversation {
+	if limit > 0 && len(turns) > limit {
+		turns = turns[len(turns)-limit:]
+	}
+
+	t := make([]*ConversationTurn, len(turns))
+	copy(t, turns)
+	return &Conversation{turns: t}
+}
+
+// Turns returns the conversation turns, oldest first
+func (c *Conversation) Turns() []*ConversationTurn {
+	turns := make([]*ConversationTurn, len(c.turns))
+	copy(turns, c.turns)
+	return turns
+}
+
+// IsEmpty checks if nothing was asked yet
+func (c *Conversation) IsEmpty() bool {
+	return len(c.turns) == 0
+}
+
+// Sources returns the distinct document paths selected during the conversation, most recent first
+func (c *Conversation) Sources() []string {
+	seen := make(map[string]bool)
+	var sources []string
+	for i := len(c.turns) - 1; i >= 0; i-- {
+		for _, source := range c.turns[i].sources {
+			if !seen[source] {
+				seen[source] = true
+				sources = append(sources, source)
+			}
+		}
+	}
+	return sources
+}
+
+// LastQuestion returns the most recent question, or an empty string
+func (c *Conversation) LastQuestion() string {
+	if len(c.turns) == 0 {
+		return ""
+	}
+	return c.turns[len(c.turns)-1].question
+}
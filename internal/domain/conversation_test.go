@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConversation(t *testing.T) {
+	first, err := NewConversationTurn("Which database do we use?", "Postgres [1]", []string{"docs/db.md"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := NewConversationTurn("What about the web app?", "Same [1]", []string{"docs/web.md", "docs/db.md"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	third, err := NewConversationTurn("And the mobile app?", "", []string{"docs/mobile.md"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		turns            []*ConversationTurn
+		limit            int
+		wantTurns        int
+		wantSources      []string
+		wantLastQuestion string
+	}{
+		{
+			name:             "empty conversation",
+			limit:            5,
+			wantTurns:        0,
+			wantLastQuestion: "",
+		},
+		{
+			name:             "sources are distinct and most recent first",
+			turns:            []*ConversationTurn{first, second},
+			limit:            5,
+			wantTurns:        2,
+			wantSources:      []string{"docs/web.md", "docs/db.md"},
+			wantLastQuestion: "What about the web app?",
+		},
+		{
+			name:             "older turns are forgotten",
+			turns:            []*ConversationTurn{first, second, third},
+			limit:            1,
+			wantTurns:        1,
+			wantSources:      []string{"docs/mobile.md"},
+			wantLastQuestion: "And the mobile app?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := NewConversation(tt.turns, tt.limit)
+			if len(conv.Turns()) != tt.wantTurns {
+				t.Errorf("Turns() = %d, want %d", len(conv.Turns()), tt.wantTurns)
+			}
+			if !reflect.DeepEqual(conv.Sources(), tt.wantSources) {
+				t.Errorf("Sources() = %v, want %v", conv.Sources(), tt.wantSources)
+			}
+			if conv.LastQuestion() != tt.wantLastQuestion {
+				t.Errorf("LastQuestion() = %v, want %v", conv.LastQuestion(), tt.wantLastQuestion)
+			}
+		})
+	}
+
+	if _, err := NewConversationTurn("  ", "", nil); err != ErrEmptyQuestion {
+		t.Errorf("expected ErrEmptyQuestion, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCorrection = errors.New("invalid correction")
+
+// CorrectionField is the part of a message analysis a person corrected
+type CorrectionField string
+
+const (
+	// CorrectionFieldCategory is a category override
+	CorrectionFieldCategory CorrectionField = "category"
+	// CorrectionFieldType is a message type override
+	CorrectionFieldType CorrectionField = "type"
+	// CorrectionFieldDiscard is a capture that should not have been documented, its correct type is unknown
+	CorrectionFieldDiscard CorrectionField = "discard"
+)
+
+// String returns the corrected field
+func (f CorrectionField) String() string {
+	return string(f)
+}
+
+// Correction is a labeled example: the analysis got a field of a message wrong and a person fixed it
+type Correction struct {
+	messageID string
+	channelID string
+	content   string
+	field     CorrectionField
+	from      string
+	to        string
+	actor     string
+	at        time.Time
+}
+
+// NewCorrection creates a Correction of a message's analysis made now
+func NewCorrection(msg *Message, field CorrectionField, from, to, actor string) (*Correction, error) {
+	if msg == nil {
+		return nil, ErrInvalidCorrection
+	}
+	if strings.TrimSpace(string(field)) == "" || from == to {
+		return nil, ErrInvalidCorrection
+	}
+
+	return &Correction{
+		messageID: msg.ID().String(),
+		channelID: msg.ChannelID(),
+		content:   msg.Content().Text(),
+		field:     field,
+		from:      from,
+		to:        to,
+		actor:     strings.TrimSpace(actor),
+		at:        time.Now().UTC(),
+	}, nil
+}
+
+// MessageID returns the ID of the corrected message
+func (c *Correction) MessageID() string {
+	return c.messageID
+}
+
+// ChannelID returns the channel the message was posted in
+func (c *Correction) ChannelID() string {
+	return c.channelID
+}
+
+// Content returns the text of the corrected message
+func (c *Correction) Content() string {
+	return c.content
+}
+
+// Field returns what was corrected
+func (c *Correction) Field() CorrectionField {
+	return c.field
+}
+
+// From returns the value the analysis chose
+func (c *Correction) From() string {
+	return c.from
+}
+
+// To returns the value the person chose
+func (c *Correction) To() string {
+	return c.to
+}
+
+// Actor returns who made the correction
+func (c *Correction) Actor() string {
+	return c.actor
+}
+
+// At returns when the correction was made
+func (c *Correction) At() time.Time {
+	return c.at
+}
+
+// WithActor returns a copy of the correction made by another actor, for erasing the identity of the original one
+func (c *Correction) WithActor(actor string) *Correction {
+	correction := *c
+	correction.actor = actor
+	return &correction
+}
+
+// SelectCorrectionExamples picks the corrections most worth showing when analyzing the content:
+// those of the most similar messages first, the most recent first among equally similar ones.
+func SelectCorrectionExamples(corrections []*Correction, content string, limit int) []*Correction {
+	type scored struct {
+		correction *Correction
+		score      float64
+	}
+
+	candidates := make([]scored, 0, len(corrections))
+	for _, c := range corrections {
+		candidates = append(candidates, scored{correction: c, score: TextSimilarity(content, c.content)})
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].score != candidates[b].score {
+			return candidates[a].score > candidates[b].score
+		}
+		return candidates[a].correction.at.After(candidates[b].correction.at)
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	examples := make([]*Correction, 0, len(candidates))
+	for _, c := range candidates {
+		examples = append(examples, c.correction)
+	}
+	return examples
+}
+
+// RenderCorrectionExamples describes corrections as guidance for analyzing new messages
+func RenderCorrectionExamples(examples []*Correction) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("People on this team corrected earlier analyses. Follow their judgement for similar messages:\n")
+	for _, c := range examples {
+		content := strings.Join(strings.Fields(c.content), " ")
+		switch c.field {
+		case CorrectionFieldDiscard:
+			b.WriteString(fmt.Sprintf("- %q was not worth documenting, its Type is \"unknown\"\n", content))
+		case CorrectionFieldType:
+			b.WriteString(fmt.Sprintf("- %q is not of Type %q but %q\n", content, c.from, c.to))
+		default:
+			b.WriteString(fmt.Sprintf("- %q is not in Category %q but %q\n", content, c.from, c.to))
+		}
+	}
+	return b.String()
+}
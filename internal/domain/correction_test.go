@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+func correctionFor(t *testing.T, text string, field CorrectionField, from, to string, at time.Time) *Correction {
+	t.Helper()
+	content, err := NewMessageContent(text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg, err := NewMessage(common.GenerateID(), "alice", content, MessageTypeIdea, CategoryOther, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c, err := NewCorrection(msg, field, from, to, "U0001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.at = at
+	return c
+}
+
+func TestNewCorrection(t *testing.T) {
+	content, _ := NewMessageContent("We should cache the pricing page")
+	msg, err := NewMessage(common.GenerateID(), "alice", content, MessageTypeIdea, CategoryOther, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		msg     *Message
+		field   CorrectionField
+		from    string
+		to      string
+		wantErr error
+	}{
+		{name: "category override", msg: msg, field: CorrectionFieldCategory, from: "other", to: "product"},
+		{name: "discard", msg: msg, field: CorrectionFieldDiscard, from: "idea", to: "unknown"},
+		{name: "missing message", field: CorrectionFieldType, from: "idea", to: "decision", wantErr: ErrInvalidCorrection},
+		{name: "nothing changed", msg: msg, field: CorrectionFieldType, from: "idea", to: "idea", wantErr: ErrInvalidCorrection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCorrection(tt.msg, tt.field, tt.from, tt.to, "U0001")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewCorrection() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (c.Content() != "We should cache the pricing page" || c.MessageID() != msg.ID().String()) {
+				t.Errorf("NewCorrection() did not keep the message: %+v", c)
+			}
+		})
+	}
+}
+
+func TestSelectCorrectionExamples(t *testing.T) {
+	now := time.Now()
+	old := correctionFor(t, "Lunch at noon", CorrectionFieldDiscard, "status", "unknown", now.Add(-2*time.Hour))
+	recent := correctionFor(t, "Standup moved to 10am", CorrectionFieldDiscard, "status", "unknown", now.Add(-time.Hour))
+	similar := correctionFor(t, "Pricing page redesign for enterprise", CorrectionFieldCategory, "development", "product", now.Add(-3*time.Hour))
+
+	got := SelectCorrectionExamples([]*Correction{old, recent, similar}, "Enterprise pricing page needs a redesign", 2)
+
+	want := []*Correction{similar, recent}
+	if len(got) != len(want) {
+		t.Fatalf("SelectCorrectionExamples() returned %d examples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("example %d = %q, want %q", i, got[i].Content(), want[i].Content())
+		}
+	}
+}
+
+func TestRenderCorrectionExamples(t *testing.T) {
+	if got := RenderCorrectionExamples(nil); got != "" {
+		t.Errorf("RenderCorrectionExamples(nil) = %q, want empty", got)
+	}
+
+	got := RenderCorrectionExamples([]*Correction{
+		correctionFor(t, "Pricing  page\nredesign", CorrectionFieldCategory, "development", "product", time.Now()),
+		correctionFor(t, "Ship it on Friday", CorrectionFieldType, "status", "decision", time.Now()),
+		correctionFor(t, "Lunch at noon", CorrectionFieldDiscard, "status", "unknown", time.Now()),
+	})
+
+	for _, want := range []string{
+		`- "Pricing page redesign" is not in Category "development" but "product"`,
+		`- "Ship it on Friday" is not of Type "status" but "decision"`,
+		`- "Lunch at noon" was not worth documenting, its Type is "unknown"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderCorrectionExamples() = %q, want it to contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidCorrelationID = errors.New("correlation ID must look like QX-3F9A1C0B7E2D")
+
+// correlationPrefix starts every correlation ID, so references quoted by people are easy to tell apart
+const correlationPrefix = "QX-"
+
+// shortCorrelationLength is how many characters of a correlation ID replies show, the prefix included
+const shortCorrelationLength = 7
+
+var correlationPattern = regexp.MustCompile(`^QX-[0-9A-F]{12}$`)
+
+// CorrelationID ties together what happened while a message or an API request was processed: the log lines,
+// the processing events, the audit entries and the reply reporting a failure. Replies show its short form,
+// like QX-3F9A, which operators search the logs and the audit log for.
+type CorrelationID string
+
+// NewCorrelationID creates a random correlation ID
+func NewCorrelationID() CorrelationID {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return CorrelationID(correlationPrefix + strings.ToUpper(hex.EncodeToString(b)))
+}
+
+// ParseCorrelationID reads a correlation ID passed on, like in the X-Request-ID header of an API request
+func ParseCorrelationID(value string) (CorrelationID, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if !correlationPattern.MatchString(value) {
+		return "", ErrInvalidCorrelationID
+	}
+	return CorrelationID(value), nil
+}
+
+// String returns the correlation ID
+func (id CorrelationID) String() string {
+	return string(id)
+}
+
+// Short returns the start of the correlation ID people are shown, like QX-3F9A
+func (id CorrelationID) Short() string {
+	if len(id) <= shortCorrelationLength {
+		return string(id)
+	}
+	return string(id[:shortCorrelationLength])
+}
+
+// correlationKey is the context key of the correlation ID
+type correlationKey struct{}
+
+// ContextWithCorrelationID returns a context carrying a correlation ID, an empty one leaves the context as it is
+func ContextWithCorrelationID(ctx context.Context, id CorrelationID) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFrom returns the correlation ID a context carries, empty when it carries none
+func CorrelationIDFrom(ctx context.Context) CorrelationID {
+	id, _ := ctx.Value(correlationKey{}).(CorrelationID)
+	return id
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCorrelationID(t *testing.T) {
+	id := NewCorrelationID()
+	assert.Regexp(t, `^QX-[0-9A-F]{12}$`, id.String())
+	assert.Equal(t, id.String()[:7], id.Short())
+	assert.NotEqual(t, id, NewCorrelationID())
+
+	parsed, err := ParseCorrelationID(" " + string(id) + " ")
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+}
+
+func TestParseCorrelationID(t *testing.T) {
+	id, err := ParseCorrelationID("qx-3f9a1c0b7e2d")
+	require.NoError(t, err)
+	assert.Equal(t, CorrelationID("QX-3F9A1C0B7E2D"), id)
+	assert.Equal(t, "QX-3F9A", id.Short())
+
+	for _, value := range []string{"", "QX-3F9A", "3F9A1C0B7E2D", "QX-3F9A1C0B7E2Z", "req-123"} {
+		_, err := ParseCorrelationID(value)
+		assert.ErrorIs(t, err, ErrInvalidCorrelationID, value)
+	}
+}
+
+func TestContextWithCorrelationID(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, CorrelationIDFrom(ctx))
+	assert.Equal(t, ctx, ContextWithCorrelationID(ctx, ""))
+
+	id := NewCorrelationID()
+	assert.Equal(t, id, CorrelationIDFrom(ContextWithCorrelationID(ctx, id)))
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLocalOnly indicates that content of a local-only project was about to be sent to a cloud provider
+var ErrLocalOnly = errors.New("local-only project content cannot be sent to cloud providers")
+
+// DataLocation tells where a provider processes or keeps the content it is given
+type DataLocation string
+
+const (
+	// DataLocationLocal providers run on infrastructure the deployment controls, like Ollama
+	DataLocationLocal DataLocation = "local"
+	// DataLocationCloud providers are third-party services, like OpenAI or GitHub
+	DataLocationCloud DataLocation = "cloud"
+)
+
+// String returns the location
+func (l DataLocation) String() string {
+	return string(l)
+}
+
+// ResidencyDecision is the outcome of checking if content may be sent to a provider
+type ResidencyDecision struct {
+	// Subject is what the content belongs to, like a channel or a repository
+	Subject string
+	// Purpose is what the provider is used for, like "analysis" or "storage"
+	Purpose string
+	// Location is where the provider keeps the content
+	Location DataLocation
+	// LocalOnly is the policy of the project the content belongs to
+	LocalOnly bool
+}
+
+// Allowed checks if the content may be sent, local-only content goes to local providers only
+func (d ResidencyDecision) Allowed() bool {
+	return !d.LocalOnly || d.Location == DataLocationLocal
+}
+
+// Err returns ErrLocalOnly when the content may not be sent
+func (d ResidencyDecision) Err() error {
+	if d.Allowed() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s of %s uses a %s provider", ErrLocalOnly, d.Purpose, d.Subject, d.Location)
+}
+
+// String describes the decision for logs
+func (d ResidencyDecision) String() string {
+	outcome := "allowed"
+	if !d.Allowed() {
+		outcome = "refused"
+	}
+	return fmt.Sprintf("%s of %s on a %s provider %s", d.Purpose, d.Subject, d.Location, outcome)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResidencyDecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision ResidencyDecision
+		want     bool
+	}{
+		{name: "cloud without policy", decision: ResidencyDecision{Location: DataLocationCloud}, want: true},
+		{name: "local for local-only", decision: ResidencyDecision{Location: DataLocationLocal, LocalOnly: true}, want: true},
+		{name: "cloud for local-only", decision: ResidencyDecision{Location: DataLocationCloud, LocalOnly: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.decision.Allowed(); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+			if err := tt.decision.Err(); errors.Is(err, ErrLocalOnly) == tt.want {
+				t.Errorf("Err() = %v, want ErrLocalOnly %v", err, !tt.want)
+			}
+		})
+	}
+}
+
+func TestResidencyDecision_String(t *testing.T) {
+	decision := ResidencyDecision{Subject: "C0001", Purpose: "analysis", Location: DataLocationCloud, LocalOnly: true}
+
+	if got, want := decision.String(), "analysis of C0001 on a cloud provider refused"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDeadLetter = errors.New("invalid dead letter")
+
+// DeadLetter is a queued message processing failed on, set aside until an operator retries or discards it
+type DeadLetter struct {
+	queued   *QueuedMessage
+	err      string
+	failedAt time.Time
+}
+
+// NewDeadLetter sets aside a queued message that failed with err
+func NewDeadLetter(queued *QueuedMessage, err string, failedAt time.Time) (*DeadLetter, error) {
+	if queued == nil {
+		return nil, ErrInvalidDeadLetter
+	}
+	err = strings.TrimSpace(err)
+	if err == "" {
+		return nil, ErrInvalidDeadLetter
+	}
+	return &DeadLetter{
+		queued:   queued,
+		err:      err,
+		failedAt: failedAt.UTC(),
+	}, nil
+}
+
+// ID returns the ID of the failed message, dead letters are looked up by it
+func (d *DeadLetter) ID() string {
+	return d.queued.Message().ID().String()
+}
+
+// Queued returns the failed message with the chat provider's route to it
+func (d *DeadLetter) Queued() *QueuedMessage {
+	return d.queued
+}
+
+// Attempts returns how many times processing the message failed: once, and once more per retry
+func (d *DeadLetter) Attempts() int {
+	return d.queued.Retries() + 1
+}
+
+// Error returns why processing the message failed the last time
+func (d *DeadLetter) Error() string {
+	return d.err
+}
+
+// FailedAt returns when processing the message failed the last time
+func (d *DeadLetter) FailedAt() time.Time {
+	return d.failedAt
+}
+
+// WorkerStatus is what a queue worker is doing
+type WorkerStatus struct {
+	// Worker numbers the workers of a pool from 1
+	Worker int
+	// MessageID is the message the worker processes, empty when it is idle
+	MessageID string
+	// Since is when the worker started processing the message, or became idle
+	Since     time.Time
+	Processed int
+	Failed    int
+}
+
+// Busy reports if the worker is processing a message
+func (s WorkerStatus) Busy() bool {
+	return s.MessageID != ""
+}
+
+// QueueStatus is the state of the message queue and its workers, for operators recovering from an incident
+type QueueStatus struct {
+	// Depth is how many messages wait in the queue, when the queue can tell
+	Depth      int
+	DepthKnown bool
+	// DeadLetters is how many failed messages wait for an operator
+	DeadLetters int
+	// Workers lists the workers of the instance serving the status, none when it runs no workers
+	Workers []WorkerStatus
+}
+
+// Busy returns how many workers are processing a message
+func (s QueueStatus) Busy() int {
+	busy := 0
+	for _, worker := range s.Workers {
+		if worker.Busy() {
+			busy++
+		}
+	}
+	return busy
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeadLetter(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+	queued, err := NewQueuedMessage(msg, nil)
+	require.NoError(t, err)
+	at := time.Date(2024, 6, 3, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	letter, err := NewDeadLetter(queued, " analysis timed out ", at)
+	require.NoError(t, err)
+	assert.Equal(t, msg.ID().String(), letter.ID())
+	assert.Equal(t, "analysis timed out", letter.Error())
+	assert.Equal(t, 1, letter.Attempts())
+	assert.Equal(t, time.UTC, letter.FailedAt().Location())
+
+	retried, err := NewDeadLetter(queued.Retry().Retry(), "analysis timed out", at)
+	require.NoError(t, err)
+	assert.Equal(t, 3, retried.Attempts())
+
+	_, err = NewDeadLetter(nil, "analysis timed out", at)
+	assert.ErrorIs(t, err, ErrInvalidDeadLetter)
+	_, err = NewDeadLetter(queued, " ", at)
+	assert.ErrorIs(t, err, ErrInvalidDeadLetter)
+}
+
+func TestQueueStatus_Busy(t *testing.T) {
+	status := QueueStatus{Workers: []WorkerStatus{{Worker: 1, MessageID: "01J0ZK"}, {Worker: 2}}}
+	assert.Equal(t, 1, status.Busy())
+	assert.True(t, status.Workers[0].Busy())
+	assert.False(t, status.Workers[1].Busy())
+}
@@ -0,0 +1,417 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidDeploymentPattern = errors.New("invalid deployment pattern")
+)
+
+// DeploymentStatus is the outcome a deployment notification reports
+type DeploymentStatus string
+
+const (
+	// DeploymentStarted reports a deployment in progress
+	DeploymentStarted DeploymentStatus = "started"
+	// DeploymentSucceeded reports a deployment that went out
+	DeploymentSucceeded DeploymentStatus = "succeeded"
+	// DeploymentFailed reports a deployment that failed, or an application left unhealthy by it
+	DeploymentFailed DeploymentStatus = "failed"
+	// DeploymentCancelled reports a deployment someone stopped
+	DeploymentCancelled DeploymentStatus = "cancelled"
+	// DeploymentRolledBack reports a deployment that was undone
+	DeploymentRolledBack DeploymentStatus = "rolled_back"
+)
+
+// deploymentStatusWords maps the words CI/CD tools report outcomes with to a DeploymentStatus
+var deploymentStatusWords = map[string]DeploymentStatus{
+	"started":        DeploymentStarted,
+	"starting":       DeploymentStarted,
+	"running":        DeploymentStarted,
+	"in progress":    DeploymentStarted,
+	"progressing":    DeploymentStarted,
+	"succeeded":      DeploymentSucceeded,
+	"success":        DeploymentSucceeded,
+	"successful":     DeploymentSucceeded,
+	"completed":      DeploymentSucceeded,
+	"deployed":       DeploymentSucceeded,
+	"synced":         DeploymentSucceeded,
+	"healthy":        DeploymentSucceeded,
+	"back to normal": DeploymentSucceeded,
+	"failed":         DeploymentFailed,
+	"failure":        DeploymentFailed,
+	"error":          DeploymentFailed,
+	"errored":        DeploymentFailed,
+	"degraded":       DeploymentFailed,
+	"unstable":       DeploymentFailed,
+	"still failing":  DeploymentFailed,
+	"cancelled":      DeploymentCancelled,
+	"canceled":       DeploymentCancelled,
+	"aborted":        DeploymentCancelled,
+	"rolled back":    DeploymentRolledBack,
+	"rolled_back":    DeploymentRolledBack,
+	"rollback":       DeploymentRolledBack,
+}
+
+// ParseDeploymentStatus reads the outcome of a deployment as CI/CD tools word it, like "Success" or "aborted"
+func ParseDeploymentStatus(word string) (DeploymentStatus, bool) {
+	status, ok := deploymentStatusWords[strings.ToLower(strings.Join(strings.Fields(word), " "))]
+	return status, ok
+}
+
+// String returns the status as it is written in front matter
+func (s DeploymentStatus) String() string {
+	return string(s)
+}
+
+// DeploymentNotice is a deployment or release notification a CI/CD bot posted in chat, like GitHub Actions,
+// Argo CD or Jenkins do
+type DeploymentNotice struct {
+	// Pattern is the name of the pattern that recognized the notification
+	Pattern string
+	// Source is the tool that posted the notification, like "Argo CD"
+	Source      string
+	Application string
+	Environment string
+	Version     string
+	Status      DeploymentStatus
+	// Actor is who triggered the deployment, empty when the notification does not tell
+	Actor string
+	// URL links to the run of the deployment
+	URL string
+	// Text is the notification as it was posted
+	Text string
+}
+
+// Title returns the title of the status document of the notification, like "Deployed checkout v1.4.2 to production"
+func (n *DeploymentNotice) Title() string {
+	what := strings.TrimSpace(n.Application + " " + n.Version)
+	to := ""
+	if n.Environment != "" {
+		to = " to " + n.Environment
+	}
+	switch n.Status {
+	case DeploymentStarted:
+		return "Deploying " + what + to
+	case DeploymentFailed:
+		return "Failed to deploy " + what + to
+	case DeploymentCancelled:
+		return "Cancelled the deployment of " + what + to
+	case DeploymentRolledBack:
+		if n.Environment != "" {
+			to = " on " + n.Environment
+		}
+		return "Rolled back " + what + to
+	default:
+		return "Deployed " + what + to
+	}
+}
+
+// Document renders the status document of the notification: the deployment as a table, followed by the
+// notification as it was posted
+func (n *DeploymentNotice) Document() string {
+	return "# " + n.Title() + "\n\n" + n.details()
+}
+
+// RollupEntry renders the notification for the entry of a status rollup, which has a heading of its own
+func (n *DeploymentNotice) RollupEntry() string {
+	return "**" + n.Title() + "**\n\n" + n.details()
+}
+
+// details renders the deployment as a table and quotes the notification
+func (n *DeploymentNotice) details() string {
+	var b strings.Builder
+	b.WriteString("| Field | Value |\n| --- | --- |\n")
+	row := func(field, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "| %s | %s |\n", field, tableCell(value))
+		}
+	}
+	row("Application", n.Application)
+	row("Environment", n.Environment)
+	row("Version", n.Version)
+	row("Status", n.Status.String())
+	row("Triggered by", n.Actor)
+	row("Reported by", n.Source)
+	if n.URL != "" {
+		row("Run", "["+n.URL+"]("+n.URL+")")
+	}
+
+	b.WriteString("\n")
+	for _, line := range strings.Split(strings.TrimSpace(n.Text), "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// Record writes the deployment to the front matter of its document
+func (n *DeploymentNotice) Record(fm *FrontMatter) {
+	fm.Set("application", n.Application)
+	if n.Environment != "" {
+		fm.Set("environment", n.Environment)
+	}
+	if n.Version != "" {
+		fm.Set("version_deployed", n.Version)
+	}
+	fm.Set("deployment_status", n.Status.String())
+	if n.URL != "" {
+		fm.Set("deployment_link", n.URL)
+	}
+	fm.Set("reported_by", n.Source)
+}
+
+// Analysis returns the analysis of the notification: a status update of operations, tagged with the application
+// and environment. Notifications are parsed rather than analyzed, so it is certain.
+func (n *DeploymentNotice) Analysis() *MessageAnalysisResult {
+	tags := []string{"deployment", n.Application}
+	if n.Environment != "" {
+		tags = append(tags, n.Environment)
+	}
+	analysis, _ := NewMessageAnalysisResult(MessageTypeStatus, CategoryOperations, nil, 1, tags)
+	analysis.StampModel("deployment-pattern:" + n.Pattern)
+	return analysis
+}
+
+// DeploymentPattern recognizes the notifications of a CI/CD tool. Its expression names what it captures with
+// the groups application, environment, version, status, actor and url; application is required, and so is
+// status unless the pattern only matches one outcome.
+type DeploymentPattern struct {
+	name       string
+	source     string
+	expression *regexp.Regexp
+	status     DeploymentStatus
+}
+
+// NewDeploymentPattern creates a DeploymentPattern. The status is the outcome of the notifications the
+// expression matches when it has no status group, empty otherwise.
+func NewDeploymentPattern(name, source, expression string, status DeploymentStatus) (*DeploymentPattern, error) {
+	name, source = strings.TrimSpace(name), strings.TrimSpace(source)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name cannot be empty", ErrInvalidDeploymentPattern)
+	}
+	if source == "" {
+		source = name
+	}
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidDeploymentPattern, name, err)
+	}
+	if re.SubexpIndex("application") < 0 {
+		return nil, fmt.Errorf("%w: %s does not capture the application", ErrInvalidDeploymentPattern, name)
+	}
+	if re.SubexpIndex("status") < 0 && status == "" {
+		return nil, fmt.Errorf("%w: %s neither captures nor sets the status", ErrInvalidDeploymentPattern, name)
+	}
+	return &DeploymentPattern{name: name, source: source, expression: re, status: status}, nil
+}
+
+// MustNewDeploymentPattern is like NewDeploymentPattern but panics on error
+func MustNewDeploymentPattern(name, source, expression string, status DeploymentStatus) *DeploymentPattern {
+	pattern, err := NewDeploymentPattern(name, source, expression, status)
+	if err != nil {
+		panic(err)
+	}
+	return pattern
+}
+
+// Name returns the name of the pattern
+func (p *DeploymentPattern) Name() string {
+	return p.name
+}
+
+// Source returns the tool whose notifications the pattern recognizes
+func (p *DeploymentPattern) Source() string {
+	return p.source
+}
+
+// match reads a notification, nil when the text is not one of the pattern's or its status is unknown
+func (p *DeploymentPattern) match(text string) *DeploymentNotice {
+	groups := p.expression.FindStringSubmatch(text)
+	if groups == nil {
+		return nil
+	}
+	captured := func(name string) string {
+		if i := p.expression.SubexpIndex(name); i >= 0 {
+			return strings.Trim(strings.TrimSpace(groups[i]), "*_`")
+		}
+		return ""
+	}
+
+	notice := &DeploymentNotice{
+		Pattern:     p.name,
+		Source:      p.source,
+		Application: captured("application"),
+		Environment: captured("environment"),
+		Version:     captured("version"),
+		Status:      p.status,
+		Actor:       captured("actor"),
+		URL:         captured("url"),
+		Text:        text,
+	}
+	if word := captured("status"); word != "" {
+		status, ok := ParseDeploymentStatus(word)
+		if !ok {
+			return nil
+		}
+		notice.Status = status
+	}
+	if notice.Application == "" || notice.Status == "" {
+		return nil
+	}
+	notice.fillFields(text)
+	return notice
+}
+
+var (
+	// chatLinkPattern matches links as Slack writes them, like <https://ci.example.com/42|Open>
+	chatLinkPattern = regexp.MustCompile(`<(https?://[^|>\s]+)(?:\|([^>]*))?>`)
+	// deploymentFieldPattern matches the fields notifications list line by line, like "Environment: production"
+	deploymentFieldPattern = regexp.MustCompile(`(?im)^\s*[*_]*([a-z][a-z ]*?)[*_]*\s*:\s*[*_]*(.+?)[*_]*\s*$`)
+	// runURLPattern matches the first link of a notification
+	runURLPattern = regexp.MustCompile(`https?://[^\s<>|()]+`)
+)
+
+// deploymentFields maps the names of the fields notifications list to what they tell
+var deploymentFields = map[string]string{
+	"environment":  "environment",
+	"env":          "environment",
+	"version":      "version",
+	"revision":     "version",
+	"tag":          "version",
+	"release":      "version",
+	"actor":        "actor",
+	"author":       "actor",
+	"triggered by": "actor",
+	"deployed by":  "actor",
+	"initiated by": "actor",
+}
+
+// fillFields completes the notice with the fields the notification lists, like "Environment: production", and
+// the first link in it, without overriding what the pattern captured
+func (n *DeploymentNotice) fillFields(text string) {
+	for _, field := range deploymentFieldPattern.FindAllStringSubmatch(text, -1) {
+		value := strings.TrimSpace(field[2])
+		switch deploymentFields[strings.ToLower(field[1])] {
+		case "environment":
+			n.Environment = firstNonEmpty(n.Environment, value)
+		case "version":
+			n.Version = firstNonEmpty(n.Version, value)
+		case "actor":
+			n.Actor = firstNonEmpty(n.Actor, value)
+		}
+	}
+	if n.URL == "" {
+		n.URL = runURLPattern.FindString(text)
+	}
+}
+
+// firstNonEmpty returns the first of the values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// DeploymentPatterns is the registry of the patterns a workspace recognizes deployment notifications with.
+// Patterns are tried in the order they were registered, the first match wins.
+type DeploymentPatterns struct {
+	patterns []*DeploymentPattern
+}
+
+// NewDeploymentPatterns creates a registry of the patterns, without the built-in ones
+func NewDeploymentPatterns(patterns ...*DeploymentPattern) *DeploymentPatterns {
+	r := &DeploymentPatterns{}
+	for _, pattern := range patterns {
+		r.Register(pattern)
+	}
+	return r
+}
+
+// DefaultDeploymentPatterns creates a registry of the built-in patterns for GitHub Actions, Argo CD and Jenkins,
+// after the workspace's own patterns so these take precedence
+func DefaultDeploymentPatterns(custom ...*DeploymentPattern) *DeploymentPatterns {
+	return NewDeploymentPatterns(append(custom, builtinDeploymentPatterns()...)...)
+}
+
+// Register adds a pattern to the registry, tried after the ones registered before it. A pattern registered
+// again under the same name replaces the earlier one.
+func (r *DeploymentPatterns) Register(pattern *DeploymentPattern) {
+	if pattern == nil {
+		return
+	}
+	for i, existing := range r.patterns {
+		if existing.name == pattern.name {
+			r.patterns[i] = pattern
+			return
+		}
+	}
+	r.patterns = append(r.patterns, pattern)
+}
+
+// Patterns returns the registered patterns in the order they are tried
+func (r *DeploymentPatterns) Patterns() []*DeploymentPattern {
+	patterns := make([]*DeploymentPattern, len(r.patterns))
+	copy(patterns, r.patterns)
+	return patterns
+}
+
+// Match reads a deployment notification from the text of a message. Links written the way Slack writes them
+// are read as their label followed by the URL.
+func (r *DeploymentPatterns) Match(text string) (*DeploymentNotice, bool) {
+	if r == nil {
+		return nil, false
+	}
+	text = strings.TrimSpace(chatLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		match := chatLinkPattern.FindStringSubmatch(link)
+		if match[2] == "" {
+			return match[1]
+		}
+		return match[2] + " (" + match[1] + ")"
+	}))
+	for _, pattern := range r.patterns {
+		if notice := pattern.match(text); notice != nil {
+			return notice, true
+		}
+	}
+	return nil, false
+}
+
+// builtinDeploymentPatterns returns the patterns of the notifications GitHub Actions, Argo CD and Jenkins post
+// with their usual Slack integrations
+func builtinDeploymentPatterns() []*DeploymentPattern {
+	const (
+		statusWord = `succeeded|successful|success|failed|failure|started|in progress|cancell?ed|canceled|completed`
+		repository = `[\w.-]+/[\w.-]+`
+	)
+	return []*DeploymentPattern{
+		// GitHub deployment statuses, like "Deployment succeeded for acme/checkout@v1.4.2 to production by alice"
+		MustNewDeploymentPattern("github-deployment", "GitHub Actions",
+			`(?i)\bdeployment\s+(?P<status>`+statusWord+`)\s+(?:for|of)\s+(?P<application>`+repository+`)`+
+				`(?:@(?P<version>\S+))?\s+(?:to|in)\s+(?P<environment>[\w.-]+)(?:\s+by\s+@?(?P<actor>[\w.-]+))?`, ""),
+		// Workflow runs of deployment workflows, like "Success: alice's workflow (Deploy) in acme/checkout"
+		MustNewDeploymentPattern("github-workflow", "GitHub Actions",
+			`(?im)^\W*(?P<status>`+statusWord+`):\s+@?(?P<actor>[\w.-]+)'s\s+workflow\s+\([^)]*(?:deploy|release)[^)]*\)`+
+				`\s+in\s+(?P<application>`+repository+`)`, ""),
+		// Argo CD notification templates, like "Application checkout is now running new version of deployments manifests."
+		MustNewDeploymentPattern("argocd-deployed", "Argo CD",
+			`(?i)\bapplication\s+(?P<application>[\w.-]+)\s+is\s+now\s+running\s+new\s+version`, DeploymentSucceeded),
+		MustNewDeploymentPattern("argocd-synced", "Argo CD",
+			`(?i)\bapplication\s+(?P<application>[\w.-]+)\s+has\s+been\s+successfully\s+synced`, DeploymentSucceeded),
+		MustNewDeploymentPattern("argocd-sync", "Argo CD",
+			`(?i)\bsync\s+operation\s+of\s+application\s+(?P<application>[\w.-]+)\s+has\s+(?P<status>failed|started|succeeded)`, ""),
+		MustNewDeploymentPattern("argocd-degraded", "Argo CD",
+			`(?i)\bapplication\s+(?P<application>[\w.-]+)\s+has\s+(?P<status>degraded)`, ""),
+		// Jenkins Slack plugin builds of deployment jobs, like "checkout-deploy - #42 Success after 2 min 3 sec (Open)"
+		MustNewDeploymentPattern("jenkins", "Jenkins",
+			`(?im)^\W*(?P<application>[\w./-]*(?:deploy|release)[\w./-]*)\s+-\s+(?P<version>#\d+)\s+`+
+				`(?P<status>started|success|failure|aborted|back to normal|unstable|still failing)\b`+
+				`(?:.*?\bby\s+(?:user\s+)?(?P<actor>[\w.@-]+))?`, ""),
+	}
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentPatterns_Match(t *testing.T) {
+	patterns := DefaultDeploymentPatterns()
+
+	tests := []struct {
+		name string
+		text string
+		want DeploymentNotice
+	}{
+		{
+			name: "GitHub deployment",
+			text: "Deployment succeeded for acme/checkout@v1.4.2 to production by alice",
+			want: DeploymentNotice{Pattern: "github-deployment", Source: "GitHub Actions", Application: "acme/checkout",
+				Environment: "production", Version: "v1.4.2", Status: DeploymentSucceeded, Actor: "alice"},
+		},
+		{
+			name: "GitHub workflow with fields",
+			text: "Failure: alice's workflow (Deploy to production) in acme/checkout\n" +
+				"<https://github.com/acme/checkout/actions/runs/42|Run #42>\nEnvironment: staging\nRef: main",
+			want: DeploymentNotice{Pattern: "github-workflow", Source: "GitHub Actions", Application: "acme/checkout",
+				Environment: "staging", Status: DeploymentFailed, Actor: "alice", URL: "https://github.com/acme/checkout/actions/runs/42"},
+		},
+		{
+			name: "Argo CD deployed",
+			text: "Application checkout is now running new version of deployments manifests.\nRevision: 3f2a9c1",
+			want: DeploymentNotice{Pattern: "argocd-deployed", Source: "Argo CD", Application: "checkout",
+				Version: "3f2a9c1", Status: DeploymentSucceeded},
+		},
+		{
+			name: "Argo CD degraded",
+			text: "Application checkout has degraded.",
+			want: DeploymentNotice{Pattern: "argocd-degraded", Source: "Argo CD", Application: "checkout", Status: DeploymentFailed},
+		},
+		{
+			name: "Jenkins",
+			text: "checkout-deploy - #42 Started by user alice (<https://ci.example.com/job/checkout-deploy/42/|Open>)",
+			want: DeploymentNotice{Pattern: "jenkins", Source: "Jenkins", Application: "checkout-deploy", Version: "#42",
+				Status: DeploymentStarted, Actor: "alice", URL: "https://ci.example.com/job/checkout-deploy/42/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notice, ok := patterns.Match(tt.text)
+			require.True(t, ok)
+			notice.Text = ""
+			assert.Equal(t, tt.want, *notice)
+		})
+	}
+
+	for _, text := range []string{
+		"We should deploy checkout to production tomorrow",
+		"checkout-tests - #12 Failure after 2 min",
+		"Success: alice's workflow (Lint) in acme/checkout",
+	} {
+		_, ok := patterns.Match(text)
+		assert.False(t, ok, text)
+	}
+}
+
+func TestDeploymentPatterns_CustomPatternsComeFirst(t *testing.T) {
+	custom := MustNewDeploymentPattern("spinnaker", "Spinnaker",
+		`(?i)pipeline (?P<application>\S+) to (?P<environment>\S+) (?P<status>succeeded|failed)`, "")
+	patterns := DefaultDeploymentPatterns(custom)
+
+	notice, ok := patterns.Match("Pipeline checkout to production succeeded")
+	require.True(t, ok)
+	assert.Equal(t, "Spinnaker", notice.Source)
+	assert.Equal(t, "production", notice.Environment)
+	assert.Equal(t, "spinnaker", patterns.Patterns()[0].Name())
+
+	// Registering a pattern under a taken name replaces it
+	patterns.Register(MustNewDeploymentPattern("spinnaker", "Spinnaker", `(?i)rollback of (?P<application>\S+)`, DeploymentRolledBack))
+	notice, ok = patterns.Match("Rollback of checkout")
+	require.True(t, ok)
+	assert.Equal(t, DeploymentRolledBack, notice.Status)
+	assert.Len(t, patterns.Patterns(), len(DefaultDeploymentPatterns().Patterns())+1)
+}
+
+func TestNewDeploymentPattern_Validation(t *testing.T) {
+	_, err := NewDeploymentPattern("", "", `(?P<application>\S+) (?P<status>\S+)`, "")
+	assert.ErrorIs(t, err, ErrInvalidDeploymentPattern)
+	_, err = NewDeploymentPattern("broken", "", `(?P<application>`, "")
+	assert.ErrorIs(t, err, ErrInvalidDeploymentPattern)
+	_, err = NewDeploymentPattern("no-app", "", `deployed (?P<status>\S+)`, "")
+	assert.ErrorIs(t, err, ErrInvalidDeploymentPattern)
+	_, err = NewDeploymentPattern("no-status", "", `deployed (?P<application>\S+)`, "")
+	assert.ErrorIs(t, err, ErrInvalidDeploymentPattern)
+
+	pattern, err := NewDeploymentPattern("deployed", "", `deployed (?P<application>\S+)`, DeploymentSucceeded)
+	require.NoError(t, err)
+	assert.Equal(t, "deployed", pattern.Source())
+}
+
+func TestDeploymentNotice_Document(t *testing.T) {
+	notice := &DeploymentNotice{
+		Pattern:     "github-deployment",
+		Source:      "GitHub Actions",
+		Application: "acme/checkout",
+		Environment: "production",
+		Version:     "v1.4.2",
+		Status:      DeploymentFailed,
+		Actor:       "alice",
+		URL:         "https://github.com/acme/checkout/actions/runs/42",
+		Text:        "Deployment failed for acme/checkout@v1.4.2 to production by alice\n\nLogs attached",
+	}
+
+	assert.Equal(t, "# Failed to deploy acme/checkout v1.4.2 to production\n\n"+
+		"| Field | Value |\n| --- | --- |\n"+
+		"| Application | acme/checkout |\n"+
+		"| Environment | production |\n"+
+		"| Version | v1.4.2 |\n"+
+		"| Status | failed |\n"+
+		"| Triggered by | alice |\n"+
+		"| Reported by | GitHub Actions |\n"+
+		"| Run | [https://github.com/acme/checkout/actions/runs/42](https://github.com/acme/checkout/actions/runs/42) |\n\n"+
+		"> Deployment failed for acme/checkout@v1.4.2 to production by alice\n>\n> Logs attached\n", notice.Document())
+
+	assert.True(t, strings.HasPrefix(notice.RollupEntry(), "**Failed to deploy acme/checkout v1.4.2 to production**\n\n| Field | Value |\n"))
+
+	fm := NewFrontMatter()
+	notice.Record(fm)
+	assert.Equal(t, "acme/checkout", fm.Get("application"))
+	assert.Equal(t, "failed", fm.Get("deployment_status"))
+	assert.Equal(t, "v1.4.2", fm.Get("version_deployed"))
+
+	analysis := notice.Analysis()
+	assert.Equal(t, MessageTypeStatus, analysis.MessageType())
+	assert.Equal(t, CategoryOperations, analysis.Category())
+	assert.Equal(t, 1.0, analysis.ConfidenceScore())
+	assert.Equal(t, []string{"deployment", "acme/checkout", "production"}, analysis.SuggestedTags())
+	assert.Equal(t, "deployment-pattern:github-deployment", analysis.Model())
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is how many unchanged lines surround each change of a unified diff
+const diffContext = 3
+
+// diffLine is a line of a diff: kept (' '), added ('+') or removed ('-')
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// DocumentDiff is the line by line change an update makes to a document
+type DocumentDiff struct {
+	path    string
+	lines   []diffLine
+	added   int
+	removed int
+}
+
+// NewDocumentDiff compares the content of a document before and after an update
+func NewDocumentDiff(path string, before, after []byte) *DocumentDiff {
+	d := &DocumentDiff{path: path, lines: diffLines(splitLines(string(before)), splitLines(string(after)))}
+	for _, line := range d.lines {
+		switch line.op {
+		case '+':
+			d.added++
+		case '-':
+			d.removed++
+		}
+	}
+	return d
+}
+
+// Path returns the path of the document
+func (d *DocumentDiff) Path() string {
+	return d.path
+}
+
+// Added returns how many lines the update adds
+func (d *DocumentDiff) Added() int {
+	return d.added
+}
+
+// Removed returns how many lines the update removes
+func (d *DocumentDiff) Removed() int {
+	return d.removed
+}
+
+// IsEmpty checks if the update leaves the document as it is
+func (d *DocumentDiff) IsEmpty() bool {
+	return d.added == 0 && d.removed == 0
+}
+
+// Summary counts the changed lines, as in "3 lines added, 1 removed"
+func (d *DocumentDiff) Summary() string {
+	if d.IsEmpty() {
+		return "no changes"
+	}
+	noun := "lines"
+	if d.added == 1 {
+		noun = "line"
+	}
+	return fmt.Sprintf("%d %s added, %d removed", d.added, noun, d.removed)
+}
+
+// Unified renders the diff in the unified format of diff -u and git diff
+func (d *DocumentDiff) Unified() string {
+	if d.IsEmpty() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", d.path, d.path))
+	for _, h := range d.hunks() {
+		b.WriteString(h)
+	}
+	return b.String()
+}
+
+// Preview renders the unified diff cut to at most maxLines lines, for chat messages
+func (d *DocumentDiff) Preview(maxLines int) string {
+	unified := strings.TrimSuffix(d.Unified(), "\n")
+	lines := strings.Split(unified, "\n")
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return unified
+	}
+	return fmt.Sprintf("%s\n… %d more lines", strings.Join(lines[:maxLines], "\n"), len(lines)-maxLines)
+}
+
+// hunks groups the changes with their surrounding lines, changes close to each other share a hunk
+func (d *DocumentDiff) hunks() []string {
+	var hunks []string
+	for start := 0; start < len(d.lines); {
+		first := start
+		for first < len(d.lines) && d.lines[first].op == ' ' {
+			first++
+		}
+		if first == len(d.lines) {
+			break
+		}
+
+		// The hunk ends once more unchanged lines follow a change than two contexts can cover
+		last, kept := first, 0
+		for i := first; i < len(d.lines) && kept <= 2*diffContext; i++ {
+			if d.lines[i].op == ' ' {
+				kept++
+				continue
+			}
+			last, kept = i, 0
+		}
+
+		from, to := max(first-diffContext, 0), min(last+diffContext+1, len(d.lines))
+		hunks = append(hunks, d.hunk(from, to))
+		start = to
+	}
+	return hunks
+}
+
+// hunk renders the lines in [from, to) with the header locating them in both versions
+func (d *DocumentDiff) hunk(from, to int) string {
+	oldStart, newStart := 1, 1
+	for _, line := range d.lines[:from] {
+		if line.op != '+' {
+			oldStart++
+		}
+		if line.op != '-' {
+			newStart++
+		}
+	}
+
+	var body strings.Builder
+	oldCount, newCount := 0, 0
+	for _, line := range d.lines[from:to] {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+		body.WriteByte(line.op)
+		body.WriteString(line.text)
+		body.WriteByte('\n')
+	}
+	// An empty side starts at the line before the hunk, as diff -u writes it
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@\n%s", oldStart, oldCount, newStart, newCount, body.String())
+}
+
+// splitLines splits text into lines without their line breaks
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the lines removed from the first text and added by the second around their longest
+// common subsequence, removals first
+func diffLines(before, after []string) []diffLine {
+	matches := matchLines(before, after)
+	lines := make([]diffLine, 0, max(len(before), len(after)))
+	j := 0
+	for i, line := range before {
+		if matches[i] < 0 {
+			lines = append(lines, diffLine{op: '-', text: line})
+			continue
+		}
+		for ; j < matches[i]; j++ {
+			lines = append(lines, diffLine{op: '+', text: after[j]})
+		}
+		lines = append(lines, diffLine{op: ' ', text: line})
+		j++
+	}
+	for ; j < len(after); j++ {
+		lines = append(lines, diffLine{op: '+', text: after[j]})
+	}
+	return lines
+}
+
+// matchLines finds the longest common subsequence of two texts. It returns, for each line of the first
+// text, the index of the matching line of the second one, or -1 when the line is not kept.
+func matchLines(before, after []string) []int {
+	// common[i][j] is the length of the longest common subsequence of before[i:] and after[j:]
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	matches := make([]int, len(before))
+	for i := range matches {
+		matches[i] = -1
+	}
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			matches[i] = j
+			i, j = i+1, j+1
+		case common[i+1][j] >= common[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func TestDocumentDiff_Unified(t *testing.T) {
+	before := "# Billing\n\nWe use MySQL.\nIt is hosted by us.\n"
+	after := "# Billing\n\nWe use Postgres.\nIt is hosted by us.\n\n## Addendum\n"
+
+	diff := NewDocumentDiff("docs/billing.md", []byte(before), []byte(after))
+
+	assert.Equal(t, 3, diff.Added())
+	assert.Equal(t, 1, diff.Removed())
+	assert.Equal(t, "3 lines added, 1 removed", diff.Summary())
+	assert.Equal(t, "--- a/docs/billing.md\n+++ b/docs/billing.md\n@@ -1,4 +1,6 @@\n"+
+		" # Billing\n \n-We use MySQL.\n+We use Postgres.\n It is hosted by us.\n+\n+## Addendum\n", diff.Unified())
+}
+
+func TestDocumentDiff_SplitsDistantChanges(t *testing.T) {
+	before := numberedLines(30)
+	after := append([]string(nil), before...)
+	after[1] = "changed 2"
+	after[25] = "changed 26"
+
+	diff := NewDocumentDiff("doc.md", []byte(strings.Join(before, "\n")), []byte(strings.Join(after, "\n")))
+
+	unified := diff.Unified()
+	assert.Equal(t, 2, strings.Count(unified, "@@ -"))
+	assert.Contains(t, unified, "@@ -1,5 +1,5 @@\n line 1\n-line 2\n+changed 2\n line 3\n")
+	assert.Contains(t, unified, "@@ -23,7 +23,7 @@\n line 23\n")
+	assert.NotContains(t, unified, "line 10")
+}
+
+func TestDocumentDiff_NewAndUnchangedDocuments(t *testing.T) {
+	created := NewDocumentDiff("doc.md", nil, []byte("one\n"))
+	assert.Equal(t, "--- a/doc.md\n+++ b/doc.md\n@@ -0,0 +1,1 @@\n+one\n", created.Unified())
+	assert.Equal(t, "1 line added, 0 removed", created.Summary())
+
+	same := NewDocumentDiff("doc.md", []byte("one\n"), []byte("one\n"))
+	assert.True(t, same.IsEmpty())
+	assert.Equal(t, "no changes", same.Summary())
+	assert.Empty(t, same.Unified())
+}
+
+func TestDocumentDiff_Preview(t *testing.T) {
+	diff := NewDocumentDiff("doc.md", nil, []byte(strings.Join(numberedLines(10), "\n")))
+
+	preview := diff.Preview(5)
+
+	assert.Equal(t, "--- a/doc.md\n+++ b/doc.md\n@@ -0,0 +1,10 @@\n+line 1\n+line 2\n… 8 more lines", preview)
+	assert.Equal(t, strings.TrimSuffix(diff.Unified(), "\n"), diff.Preview(0))
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultFeedSize is how many documents a feed lists when not told otherwise
+const DefaultFeedSize = 50
+
+// DocumentFeed lists the documents of a project created or updated most recently, so people can follow the
+// project in a feed reader
+type DocumentFeed struct {
+	project   *Project
+	documents []*IndexedDocument
+}
+
+// NewDocumentFeed creates the feed of a project from the indexed documents, keeping the size most recently
+// updated documents of the project. A size of zero or less keeps DefaultFeedSize of them.
+func NewDocumentFeed(project *Project, docs []*IndexedDocument, size int) *DocumentFeed {
+	if size <= 0 {
+		size = DefaultFeedSize
+	}
+
+	var documents []*IndexedDocument
+	for _, doc := range docs {
+		if doc.Project().Equals(project.ID()) {
+			documents = append(documents, doc)
+		}
+	}
+	sort.SliceStable(documents, func(i, j int) bool {
+		if !documents[i].UpdatedAt().Equal(documents[j].UpdatedAt()) {
+			return documents[i].UpdatedAt().After(documents[j].UpdatedAt())
+		}
+		return documents[i].Path() < documents[j].Path()
+	})
+	if len(documents) > size {
+		documents = documents[:size]
+	}
+
+	return &DocumentFeed{
+		project:   project,
+		documents: documents,
+	}
+}
+
+// Project returns the project the feed follows
+func (f *DocumentFeed) Project() *Project {
+	return f.project
+}
+
+// Documents returns the documents of the feed, most recently updated first
+func (f *DocumentFeed) Documents() []*IndexedDocument {
+	documents := make([]*IndexedDocument, len(f.documents))
+	copy(documents, f.documents)
+	return documents
+}
+
+// UpdatedAt returns when the feed last changed: the last update of its documents, or of the project when it
+// has none yet
+func (f *DocumentFeed) UpdatedAt() time.Time {
+	if len(f.documents) == 0 {
+		return f.project.UpdatedAt()
+	}
+	return f.documents[0].UpdatedAt()
+}
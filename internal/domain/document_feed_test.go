@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentFeed(t *testing.T) {
+	project := MustNewProject("Billing", "Invoices and payments", []string{"Ship invoicing"})
+	other := MustNewProject("Search", "Full-text search", []string{"Ship search"})
+
+	document := func(docPath string, project *Project) *IndexedDocument {
+		doc, err := NewIndexedDocument(docPath, "", "", MessageTypeDecision, CategoryDevelopment)
+		require.NoError(t, err)
+		doc.SetProject(project.ID())
+		return doc
+	}
+	postgres := document("docs/development/adopt-postgres.md", project)
+	redis := document("docs/development/use-redis.md", project)
+	elastic := document("docs/development/use-elastic.md", other)
+	time.Sleep(time.Millisecond)
+	postgres.Touch()
+
+	feed := NewDocumentFeed(project, []*IndexedDocument{redis, elastic, postgres}, 0)
+	assert.Equal(t, project, feed.Project())
+	assert.Equal(t, []*IndexedDocument{postgres, redis}, feed.Documents())
+	assert.Equal(t, postgres.UpdatedAt(), feed.UpdatedAt())
+
+	assert.Equal(t, []*IndexedDocument{postgres}, NewDocumentFeed(project, []*IndexedDocument{redis, postgres}, 1).Documents())
+
+	empty := NewDocumentFeed(other, nil, 10)
+	assert.Empty(t, empty.Documents())
+	assert.Equal(t, other.UpdatedAt(), empty.UpdatedAt())
+}
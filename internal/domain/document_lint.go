@@ -0,0 +1,338 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var ErrDocumentLint = errors.New("generated document failed linting")
+
+// LintRule names what a lint issue is about
+type LintRule string
+
+const (
+	// LintRuleEmpty reports a document with nothing left to store
+	LintRuleEmpty LintRule = "empty"
+	// LintRuleFrontMatter reports front matter the model wrote itself, Quill writes its own
+	LintRuleFrontMatter LintRule = "front-matter"
+	// LintRulePromptArtifact reports text the model addressed to the reader of the prompt, like "As an AI"
+	LintRulePromptArtifact LintRule = "prompt-artifact"
+	// LintRuleHeading reports a missing title or headings skipping levels
+	LintRuleHeading LintRule = "heading"
+	// LintRuleLink reports links going nowhere
+	LintRuleLink LintRule = "link"
+)
+
+// LintIssue is a problem found in a generated document
+type LintIssue struct {
+	Rule LintRule
+	// Line is the line of the generated document the issue is on, zero for the whole document
+	Line    int
+	Message string
+}
+
+// String returns the issue, like "line 3: heading: ..."
+func (i LintIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", i.Line, i.Rule, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Rule, i.Message)
+}
+
+// LintReport tells which issues of a document were fixed and which remain
+type LintReport struct {
+	Fixed     []LintIssue
+	Remaining []LintIssue
+}
+
+// OK checks if the document can be stored
+func (r LintReport) OK() bool {
+	return len(r.Remaining) == 0
+}
+
+// Err returns the remaining issues as an error, nil when there are none
+func (r LintReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+	issues := make([]string, len(r.Remaining))
+	for i, issue := range r.Remaining {
+		issues[i] = issue.String()
+	}
+	return fmt.Errorf("%w: %s", ErrDocumentLint, strings.Join(issues, "; "))
+}
+
+var (
+	// headingPattern matches ATX headings, like "## Context"
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(\S.*?)\s*#*\s*$`)
+	// linkPattern matches inline links and images, like [text](target "title")
+	linkPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*([^)\s]*)(?:\s+"[^"]*")?\s*\)`)
+	// aiArtifactPattern matches the model talking about itself
+	aiArtifactPattern = regexp.MustCompile(`(?i)\bas an ai\b|\bas an? (?:ai |large )?language model\b`)
+	// preamblePattern matches the model answering the prompt before the document starts
+	preamblePattern = regexp.MustCompile(`(?i)^(?:sure|certainly|of course|absolutely)\b|^here(?: is|'s| are)\b`)
+	// closingPattern matches the model offering further help after the document
+	closingPattern = regexp.MustCompile(`(?i)^(?:i hope this helps|hope this helps|let me know if|please let me know|feel free to)\b`)
+	// placeholderPattern matches placeholders the model left for someone to fill in, like [Insert date here]
+	placeholderPattern = regexp.MustCompile(`(?i)\[(?:insert|add|your|todo)\b[^\]]*\]`)
+)
+
+// placeholderTargets are link targets the model writes when it has no real one
+var placeholderTargets = map[string]bool{"": true, "#": true, "link": true, "url": true, "<link>": true, "<url>": true}
+
+// LintDocument checks a generated Markdown document before it is stored, and fixes what it can:
+// a document wrapped in a code block is unwrapped, front matter the model wrote and lines it addressed
+// to the prompt are removed, extra titles and headings skipping levels are moved to the right level,
+// and links going nowhere are replaced by their text. Relative links are kept when linkExists finds
+// their target, a nil linkExists keeps them all. Missing titles, placeholders and documents left empty
+// cannot be fixed, the document has to be generated again.
+func LintDocument(markdown string, linkExists func(target string) bool) (string, LintReport) {
+	var report LintReport
+	fixed := func(line int, rule LintRule, format string, args ...interface{}) {
+		report.Fixed = append(report.Fixed, LintIssue{Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+	remaining := func(line int, rule LintRule, format string, args ...interface{}) {
+		report.Remaining = append(report.Remaining, LintIssue{Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	lines := strings.Split(strings.ReplaceAll(strings.TrimPrefix(markdown, "\ufeff"), "\r\n", "\n"), "\n")
+	numbers := make([]int, len(lines))
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+	lines, numbers = trimBlankLines(lines, numbers)
+
+	// The model answers the prompt around the document, which it sometimes wraps in a code block
+	for len(lines) > 0 && isPromptArtifact(lines[0], true) {
+		fixed(numbers[0], LintRulePromptArtifact, "removed %q", strings.TrimSpace(lines[0]))
+		lines, numbers = trimBlankLines(lines[1:], numbers[1:])
+	}
+	for len(lines) > 0 && isPromptArtifact(lines[len(lines)-1], false) {
+		last := len(lines) - 1
+		fixed(numbers[last], LintRulePromptArtifact, "removed %q", strings.TrimSpace(lines[last]))
+		lines, numbers = trimBlankLines(lines[:last], numbers[:last])
+	}
+	if len(lines) >= 2 && isDocumentFence(lines[0]) && isFence(lines[len(lines)-1]) && balancedFences(lines[1:len(lines)-1]) {
+		fixed(numbers[0], LintRulePromptArtifact, "the document was wrapped in a code block")
+		lines, numbers = trimBlankLines(lines[1:len(lines)-1], numbers[1:len(numbers)-1])
+	}
+
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == frontMatterDelimiter {
+		end := 0
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+				end = i
+				break
+			}
+		}
+		if _, _, err := ParseFrontMatter(strings.Join(lines, "\n")); err != nil || end == 0 {
+			fixed(numbers[0], LintRuleFrontMatter, "removed malformed front matter")
+		} else {
+			fixed(numbers[0], LintRuleFrontMatter, "removed the front matter the model wrote")
+		}
+		lines, numbers = trimBlankLines(lines[end+1:], numbers[end+1:])
+	}
+
+	// Drop the lines the model addressed to the prompt, outside of code blocks
+	kept, keptNumbers := make([]string, 0, len(lines)), make([]int, 0, len(lines))
+	fence, started := "", false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" || isFence(trimmed) {
+			fence = toggleFence(fence, trimmed)
+			kept, keptNumbers = append(kept, line), append(keptNumbers, numbers[i])
+			started = true
+			continue
+		}
+		if isPromptArtifact(trimmed, !started) {
+			fixed(numbers[i], LintRulePromptArtifact, "removed %q", trimmed)
+			continue
+		}
+		if placeholderPattern.MatchString(trimmed) {
+			remaining(numbers[i], LintRulePromptArtifact, "placeholder %q left to fill in", placeholderPattern.FindString(trimmed))
+		}
+		if trimmed != "" {
+			started = true
+		}
+		kept, keptNumbers = append(kept, line), append(keptNumbers, numbers[i])
+	}
+	lines, numbers = trimBlankLines(kept, keptNumbers)
+	if len(lines) == 0 {
+		remaining(0, LintRuleEmpty, "the document is empty")
+		return "", report
+	}
+
+	// Headings: a single title first, and no skipped levels
+	anchors := make(map[string]bool)
+	fence = ""
+	previous, titled := 0, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" || isFence(trimmed) {
+			fence = toggleFence(fence, trimmed)
+			continue
+		}
+		match := headingPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+		level, text := len(match[1]), match[2]
+		switch {
+		case level == 1 && titled:
+			fixed(numbers[i], LintRuleHeading, "demoted the extra title %q", text)
+			level = 2
+		case level == 1:
+			titled = true
+		case previous == 0:
+			remaining(numbers[i], LintRuleHeading, "the document has no title, it opens with %q", text)
+			titled = true
+		case level > previous+1:
+			fixed(numbers[i], LintRuleHeading, "moved %q from level %d to %d", text, level, previous+1)
+			level = previous + 1
+		}
+		previous = level
+		lines[i] = strings.Repeat("#", level) + " " + text
+		anchors[headingAnchor(text)] = true
+	}
+	if !titled {
+		remaining(0, LintRuleHeading, "the document has no title")
+	}
+
+	// Links: replace the ones going nowhere by their text
+	fence = ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" || isFence(trimmed) {
+			fence = toggleFence(fence, trimmed)
+			continue
+		}
+		lines[i] = linkPattern.ReplaceAllStringFunc(line, func(link string) string {
+			match := linkPattern.FindStringSubmatch(link)
+			image, text, target := match[1] == "!", match[2], match[3]
+			if image {
+				return link
+			}
+			if reason := brokenLink(target, anchors, linkExists); reason != "" {
+				fixed(numbers[i], LintRuleLink, "unlinked %q, %s", text, reason)
+				return text
+			}
+			return link
+		})
+	}
+
+	return strings.Join(lines, "\n"), report
+}
+
+// brokenLink returns why a link target goes nowhere, empty when it is fine
+func brokenLink(target string, anchors map[string]bool, linkExists func(string) bool) string {
+	if placeholderTargets[strings.ToLower(target)] {
+		return "it has no target"
+	}
+	if strings.HasPrefix(target, "#") {
+		if !anchors[strings.ToLower(target[1:])] {
+			return fmt.Sprintf("the document has no heading %q", target)
+		}
+		return ""
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "the target is not a valid URL"
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		if parsed.Host == "" {
+			return "the URL has no host"
+		}
+		return ""
+	case "mailto":
+		return ""
+	case "":
+		if linkExists != nil && !linkExists(parsed.Path) {
+			return fmt.Sprintf("no document %q exists", parsed.Path)
+		}
+		return ""
+	default:
+		return fmt.Sprintf("unsupported scheme %q", parsed.Scheme)
+	}
+}
+
+// isPromptArtifact checks if a line was addressed to the reader of the prompt rather than written for the
+// document. Answers to the prompt, like "Sure!", only count before the document starts.
+func isPromptArtifact(line string, beforeDocument bool) bool {
+	trimmed := strings.TrimSpace(line)
+	if aiArtifactPattern.MatchString(trimmed) || closingPattern.MatchString(trimmed) {
+		return true
+	}
+	return beforeDocument && !headingPattern.MatchString(trimmed) && preamblePattern.MatchString(trimmed)
+}
+
+// headingAnchor returns the anchor a heading is linked by, like "next-steps" for "Next Steps"
+func headingAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// isFence checks if a line opens or closes a code block
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// isDocumentFence checks if a line opens a code block of Markdown or of no language
+func isDocumentFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, fence) {
+			language := strings.ToLower(strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])))
+			return language == "" || language == "markdown" || language == "md"
+		}
+	}
+	return false
+}
+
+// toggleFence returns the fence of the code block a line leaves open, given the fence open before it
+func toggleFence(open, line string) string {
+	if open == "" {
+		return line[:3]
+	}
+	if strings.HasPrefix(line, open) && strings.TrimSpace(strings.TrimLeft(line, open[:1])) == "" {
+		return ""
+	}
+	return open
+}
+
+// balancedFences checks if every code block opened in the lines is closed
+func balancedFences(lines []string) bool {
+	open := ""
+	for _, line := range lines {
+		if trimmed := strings.TrimSpace(line); open != "" || isFence(trimmed) {
+			open = toggleFence(open, trimmed)
+		}
+	}
+	return open == ""
+}
+
+// trimBlankLines removes the blank lines around the lines, with their line numbers
+func trimBlankLines(lines []string, numbers []int) ([]string, []int) {
+	start, end := 0, len(lines)
+	for start < end && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return lines[start:end], numbers[start:end]
+}
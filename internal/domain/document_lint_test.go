@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLintDocument(t *testing.T) {
+	exists := func(target string) bool {
+		return target == "use-postgres.md"
+	}
+
+	tests := []struct {
+		name      string
+		markdown  string
+		want      string
+		fixed     []LintRule
+		remaining []LintRule
+	}{
+		{
+			name:     "clean document",
+			markdown: "# Adopt Postgres\n\nThe team will use Postgres.\n\n## Context\n\nSee [the decision](use-postgres.md).",
+			want:     "# Adopt Postgres\n\nThe team will use Postgres.\n\n## Context\n\nSee [the decision](use-postgres.md).",
+		},
+		{
+			name:     "wrapped in a code block",
+			markdown: "```markdown\n# Adopt Postgres\n\n```sql\nSELECT 1;\n```\n```",
+			want:     "# Adopt Postgres\n\n```sql\nSELECT 1;\n```",
+			fixed:    []LintRule{LintRulePromptArtifact},
+		},
+		{
+			name:     "answer around a wrapped document",
+			markdown: "Here is the documentation:\n\n```md\n# Adopt Postgres\n```\n\nHope this helps!",
+			want:     "# Adopt Postgres",
+			fixed:    []LintRule{LintRulePromptArtifact, LintRulePromptArtifact, LintRulePromptArtifact},
+		},
+		{
+			name:     "code blocks are not a wrapper",
+			markdown: "```go\nfmt.Println()\n```",
+			want:     "```go\nfmt.Println()\n```",
+			// A document without headings has no title
+			remaining: []LintRule{LintRuleHeading},
+		},
+		{
+			name:     "front matter written by the model",
+			markdown: "---\ntitle: Adopt Postgres\n---\n\n# Adopt Postgres\n\nThe team will use Postgres.",
+			want:     "# Adopt Postgres\n\nThe team will use Postgres.",
+			fixed:    []LintRule{LintRuleFrontMatter},
+		},
+		{
+			name:     "malformed front matter",
+			markdown: "---\n# Adopt Postgres\n\nThe team will use Postgres.",
+			want:     "# Adopt Postgres\n\nThe team will use Postgres.",
+			fixed:    []LintRule{LintRuleFrontMatter},
+		},
+		{
+			name: "prompt artifacts",
+			markdown: "Sure! Here is the documentation you asked for:\n\n# Adopt Postgres\n\nAs an AI language model, I cannot attend meetings.\n" +
+				"The team will use Postgres.\n\nI hope this helps!",
+			want:  "# Adopt Postgres\n\nThe team will use Postgres.",
+			fixed: []LintRule{LintRulePromptArtifact, LintRulePromptArtifact, LintRulePromptArtifact},
+		},
+		{
+			name:     "artifacts in code blocks are kept",
+			markdown: "# Prompts\n\n```\nAs an AI, answer briefly.\n```",
+			want:     "# Prompts\n\n```\nAs an AI, answer briefly.\n```",
+		},
+		{
+			name:      "placeholders",
+			markdown:  "# Adopt Postgres\n\nDecided on [Insert date here].",
+			want:      "# Adopt Postgres\n\nDecided on [Insert date here].",
+			remaining: []LintRule{LintRulePromptArtifact},
+		},
+		{
+			name:     "headings skipping levels",
+			markdown: "# Adopt Postgres\n\n### Context\n\n# Consequences\n\n#### Costs",
+			want:     "# Adopt Postgres\n\n## Context\n\n## Consequences\n\n### Costs",
+			fixed:    []LintRule{LintRuleHeading, LintRuleHeading, LintRuleHeading},
+		},
+		{
+			name:      "no title",
+			markdown:  "## Context\n\nThe team will use Postgres.",
+			want:      "## Context\n\nThe team will use Postgres.",
+			remaining: []LintRule{LintRuleHeading},
+		},
+		{
+			name: "broken links",
+			markdown: "# Adopt Postgres\n\nSee [the ADR](), [the docs](link), [notes](notes.md), [context](#context), " +
+				"[costs](#costs), [site](https://postgresql.org) and ![diagram](diagram.png).\n\n## Context",
+			want: "# Adopt Postgres\n\nSee the ADR, the docs, notes, [context](#context), " +
+				"costs, [site](https://postgresql.org) and ![diagram](diagram.png).\n\n## Context",
+			fixed: []LintRule{LintRuleLink, LintRuleLink, LintRuleLink, LintRuleLink},
+		},
+		{
+			name:      "nothing but artifacts",
+			markdown:  "Sure, I can help with that.",
+			fixed:     []LintRule{LintRulePromptArtifact},
+			remaining: []LintRule{LintRuleEmpty},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report := LintDocument(tt.markdown, exists)
+			if got != tt.want {
+				t.Errorf("LintDocument() = %q, want %q", got, tt.want)
+			}
+			if rules := lintRules(report.Fixed); !equalRules(rules, tt.fixed) {
+				t.Errorf("LintDocument() fixed %v (%v), want %v", rules, report.Fixed, tt.fixed)
+			}
+			if rules := lintRules(report.Remaining); !equalRules(rules, tt.remaining) {
+				t.Errorf("LintDocument() left %v (%v), want %v", rules, report.Remaining, tt.remaining)
+			}
+			if report.OK() != (len(tt.remaining) == 0) {
+				t.Errorf("OK() = %v, want %v", report.OK(), len(tt.remaining) == 0)
+			}
+		})
+	}
+}
+
+func TestLintReport_Err(t *testing.T) {
+	if err := (LintReport{Fixed: []LintIssue{{Rule: LintRuleLink}}}).Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+
+	report := LintReport{Remaining: []LintIssue{{Rule: LintRuleHeading, Line: 1, Message: "the document has no title"}}}
+	err := report.Err()
+	if !errors.Is(err, ErrDocumentLint) {
+		t.Fatalf("Err() = %v, want %v", err, ErrDocumentLint)
+	}
+	if want := "generated document failed linting: line 1: heading: the document has no title"; err.Error() != want {
+		t.Errorf("Err() = %q, want %q", err.Error(), want)
+	}
+}
+
+func lintRules(issues []LintIssue) []LintRule {
+	var rules []LintRule
+	for _, issue := range issues {
+		rules = append(rules, issue.Rule)
+	}
+	return rules
+}
+
+func equalRules(a, b []LintRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
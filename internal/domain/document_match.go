@@ -0,0 +1,32 @@
+package domain
+
+// DocumentMatch is an indexed document together with how closely it matches a query
+type DocumentMatch struct {
+	document *IndexedDocument
+	score    float64
+}
+
+// NewDocumentMatch creates a new DocumentMatch instance
+func NewDocumentMatch(document *IndexedDocument, score float64) (*DocumentMatch, error) {
+	if document == nil {
+		return nil, ErrEmptyDocumentPath
+	}
+	if score < 0 || score > 1 {
+		return nil, ErrInvalidConfidenceScore
+	}
+
+	return &DocumentMatch{
+		document: document,
+		score:    score,
+	}, nil
+}
+
+// Document returns the matched document
+func (m *DocumentMatch) Document() *IndexedDocument {
+	return m.document
+}
+
+// Score returns the similarity score between 0 and 1
+func (m *DocumentMatch) Score() float64 {
+	return m.score
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"strings"
+)
+
+// DocumentMerge is the three-way merge of two edits made to the same version of a document: the bot's
+// update and the changes people made to the document since the bot read it
+type DocumentMerge struct {
+	lines     []string
+	conflicts int
+	newline   bool
+}
+
+// MergeDocuments merges the bot's update and the document as people left it, both edits of the base version.
+// Changes made on one side only are kept. Where both sides changed the same lines differently the update
+// wins and the conflict is counted, so it can be proposed for review rather than committed.
+func MergeDocuments(base, update, current []byte) *DocumentMerge {
+	baseLines := splitLines(string(base))
+	updateLines, currentLines := splitLines(string(update)), splitLines(string(current))
+	inUpdate, inCurrent := matchLines(baseLines, updateLines), matchLines(baseLines, currentLines)
+
+	m := &DocumentMerge{newline: strings.HasSuffix(string(update), "\n") || len(update) == 0}
+	i, u, c := 0, 0, 0
+	for {
+		// The next stable line is a line of the base both sides kept
+		next := i
+		for next < len(baseLines) && (inUpdate[next] < 0 || inCurrent[next] < 0) {
+			next++
+		}
+		if next == i && next < len(baseLines) && inUpdate[next] == u && inCurrent[next] == c {
+			m.lines = append(m.lines, baseLines[i])
+			i, u, c = i+1, u+1, c+1
+			continue
+		}
+
+		uEnd, cEnd := len(updateLines), len(currentLines)
+		if next < len(baseLines) {
+			uEnd, cEnd = inUpdate[next], inCurrent[next]
+		}
+		m.mergeChunk(baseLines[i:next], updateLines[u:uEnd], currentLines[c:cEnd])
+		if next == len(baseLines) {
+			break
+		}
+		i, u, c = next, uEnd, cEnd
+	}
+	return m
+}
+
+// mergeChunk merges lines changed between two stable lines
+func (m *DocumentMerge) mergeChunk(base, update, current []string) {
+	switch {
+	case equalLines(update, base):
+		m.lines = append(m.lines, current...)
+	case equalLines(current, base), equalLines(update, current):
+		m.lines = append(m.lines, update...)
+	default:
+		m.conflicts++
+		m.lines = append(m.lines, update...)
+	}
+}
+
+// Content returns the merged document, conflicting lines taken from the update
+func (m *DocumentMerge) Content() []byte {
+	content := strings.Join(m.lines, "\n")
+	if m.newline && len(m.lines) > 0 {
+		content += "\n"
+	}
+	return []byte(content)
+}
+
+// Conflicts returns how many places both sides changed differently
+func (m *DocumentMerge) Conflicts() int {
+	return m.conflicts
+}
+
+// HasConflicts checks if the merge took the update's side anywhere people changed the same lines
+func (m *DocumentMerge) HasConflicts() bool {
+	return m.conflicts > 0
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
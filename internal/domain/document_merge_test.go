@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeDocuments(t *testing.T) {
+	base := "# Billing\n\nWe use Postgres.\nIt runs in eu-west-1.\n"
+
+	tests := []struct {
+		name      string
+		update    string
+		current   string
+		want      string
+		conflicts int
+	}{
+		{
+			name:    "keeps edits made on both sides",
+			update:  base + "\n## Addendum\n\nInvoices are monthly.\n",
+			current: "# Billing\n\nWe use Postgres 16.\nIt runs in eu-west-1.\n",
+			want:    "# Billing\n\nWe use Postgres 16.\nIt runs in eu-west-1.\n\n## Addendum\n\nInvoices are monthly.\n",
+		},
+		{
+			name:    "keeps lines people removed out",
+			update:  base + "More.\n",
+			current: "# Billing\n\nIt runs in eu-west-1.\n",
+			want:    "# Billing\n\nIt runs in eu-west-1.\nMore.\n",
+		},
+		{
+			name:    "same edit on both sides",
+			update:  "# Billing\n\nWe use MySQL.\nIt runs in eu-west-1.\n",
+			current: "# Billing\n\nWe use MySQL.\nIt runs in eu-west-1.\n",
+			want:    "# Billing\n\nWe use MySQL.\nIt runs in eu-west-1.\n",
+		},
+		{
+			name:      "conflicting edits take the update",
+			update:    "# Billing\n\nWe use MySQL.\nIt runs in eu-west-1.\n",
+			current:   "# Billing\n\nWe use SQLite.\nIt runs in eu-west-1.\n",
+			want:      "# Billing\n\nWe use MySQL.\nIt runs in eu-west-1.\n",
+			conflicts: 1,
+		},
+		{
+			name:      "both sides appending conflict",
+			update:    base + "Bot note.\n",
+			current:   base + "Human note.\n",
+			want:      base + "Bot note.\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merge := MergeDocuments([]byte(base), []byte(tt.update), []byte(tt.current))
+
+			assert.Equal(t, tt.want, string(merge.Content()))
+			assert.Equal(t, tt.conflicts, merge.Conflicts())
+			assert.Equal(t, tt.conflicts > 0, merge.HasConflicts())
+		})
+	}
+}
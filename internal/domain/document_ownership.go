@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OwnerOf returns the owner recorded in a document's front matter, the username of the person who answers for
+// the document. It is empty for documents nobody owns, like weekly rollups gathering many people's updates.
+func OwnerOf(fm *FrontMatter) string {
+	if fm == nil {
+		return ""
+	}
+	return strings.TrimSpace(fm.Get("owner"))
+}
+
+// AssignOwner records the username of the person owning a document in its front matter. Owners are named like
+// users are, by the name their messages are sent by.
+func AssignOwner(fm *FrontMatter, owner string) error {
+	owner = strings.TrimPrefix(strings.TrimSpace(owner), "@")
+	if owner == "" || strings.ContainsAny(owner, " \t\n") {
+		return fmt.Errorf("%w: %q cannot own a document", ErrInvalidUsername, owner)
+	}
+	fm.Set("owner", owner)
+	return nil
+}
+
+// RenderOwnedReviews returns the digest telling an owner which of their documents wait for review
+func RenderOwnedReviews(owner string, docs []*IndexedDocument, link func(path string) string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🕰️ %d %s owned by %s %s for review. Reconfirm or supersede %s:\n",
+		len(docs), pluralize(len(docs), "document", "documents"), owner, pluralize(len(docs), "waits", "wait"),
+		pluralize(len(docs), "it", "them"))
+	for _, doc := range docs {
+		fmt.Fprintf(&b, "• *%s* (%s, %s)\n", doc.Title(), doc.Category(), link(doc.Path()))
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignOwner(t *testing.T) {
+	tests := []struct {
+		name      string
+		owner     string
+		wantOwner string
+		wantErr   bool
+	}{
+		{name: "username", owner: "alice", wantOwner: "alice"},
+		{name: "mention", owner: " @bob ", wantOwner: "bob"},
+		{name: "empty", owner: "@", wantErr: true},
+		{name: "several words", owner: "alice and bob", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := NewFrontMatter()
+			err := AssignOwner(fm, tt.owner)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidUsername)
+				assert.Empty(t, OwnerOf(fm))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOwner, OwnerOf(fm))
+		})
+	}
+
+	assert.Empty(t, OwnerOf(nil))
+}
+
+func TestIndexedDocument_SetFrontMatter(t *testing.T) {
+	doc, err := NewIndexedDocument("docs/development/adopt-postgres.md", "Adopt Postgres", "", MessageTypeDecision, CategoryDevelopment)
+	require.NoError(t, err)
+	assert.Equal(t, DocumentStatusActive, doc.Status())
+
+	fm := NewFrontMatter()
+	require.NoError(t, AssignOwner(fm, "alice"))
+	fm.Set("status", string(DocumentStatusNeedsReview))
+	doc.SetFrontMatter(fm)
+
+	assert.Equal(t, "alice", doc.Owner())
+	assert.Equal(t, DocumentStatusNeedsReview, doc.Status())
+	moved, err := doc.Moved("docs/operations/adopt-postgres.md", CategoryOperations)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", moved.Owner(), "the owner stays with the document when it moves")
+}
+
+func TestRenderOwnedReviews(t *testing.T) {
+	postgres, err := NewIndexedDocument("docs/development/adopt-postgres.md", "Adopt Postgres", "", MessageTypeDecision, CategoryDevelopment)
+	require.NoError(t, err)
+	oncall, err := NewIndexedDocument("docs/operations/oncall.md", "Weekly on-call rotation", "", MessageTypeDecision, CategoryOperations)
+	require.NoError(t, err)
+	link := func(path string) string { return "https://docs.example.com/" + path }
+
+	assert.Equal(t, "🕰️ 1 document owned by alice waits for review. Reconfirm or supersede it:\n"+
+		"• *Adopt Postgres* (development, https://docs.example.com/docs/development/adopt-postgres.md)\n",
+		RenderOwnedReviews("alice", []*IndexedDocument{postgres}, link))
+	assert.Contains(t, RenderOwnedReviews("alice", []*IndexedDocument{postgres, oncall}, link),
+		"🕰️ 2 documents owned by alice wait for review. Reconfirm or supersede them:\n")
+}
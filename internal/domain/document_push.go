@@ -0,0 +1,91 @@
+package domain
+
+// DocumentChangeKind tells what a push did to a document
+type DocumentChangeKind string
+
+const (
+	// DocumentAdded is a document the push created
+	DocumentAdded DocumentChangeKind = "added"
+	// DocumentModified is a document the push edited
+	DocumentModified DocumentChangeKind = "modified"
+	// DocumentRemoved is a document the push deleted
+	DocumentRemoved DocumentChangeKind = "removed"
+)
+
+// DocumentChange is the net change a push made to a document, over all its commits
+type DocumentChange struct {
+	// Path is the path of the document in the store
+	Path string
+	// Kind is what the push did to the document
+	Kind DocumentChangeKind
+	// Author is who made the last change people made to the document, empty when only the bot changed it
+	Author string
+	// Commit is the ID of the last commit changing the document
+	Commit string
+	// ByBot tells that every commit changing the document was the bot's
+	ByBot bool
+}
+
+// DocumentPush is a push of commits to a document repository, as its change notification tells it
+type DocumentPush struct {
+	// Repository is the owner/name repository pushed to, empty for the default document store
+	Repository string
+	// Branch is the branch pushed to, empty for the branch documents are written to by default
+	Branch  string
+	changes []*DocumentChange
+	byPath  map[string]*DocumentChange
+}
+
+// NewDocumentPush creates a push without commits to a repository and branch
+func NewDocumentPush(repository, branch string) *DocumentPush {
+	return &DocumentPush{
+		Repository: repository,
+		Branch:     branch,
+		byPath:     make(map[string]*DocumentChange),
+	}
+}
+
+// Record adds the files a commit added, modified and removed, commits in the order they were made
+func (p *DocumentPush) Record(commit, author string, byBot bool, added, modified, removed []string) {
+	for _, path := range added {
+		p.record(path, DocumentAdded, commit, author, byBot)
+	}
+	for _, path := range modified {
+		p.record(path, DocumentModified, commit, author, byBot)
+	}
+	for _, path := range removed {
+		p.record(path, DocumentRemoved, commit, author, byBot)
+	}
+}
+
+func (p *DocumentPush) record(path string, kind DocumentChangeKind, commit, author string, byBot bool) {
+	change, ok := p.byPath[path]
+	if !ok {
+		change = &DocumentChange{Path: path, Kind: kind, ByBot: true}
+		p.byPath[path] = change
+		p.changes = append(p.changes, change)
+	}
+
+	switch {
+	case change.Kind == DocumentAdded && kind == DocumentModified:
+		// Still a document the push created
+	case change.Kind == DocumentRemoved && kind == DocumentAdded:
+		change.Kind = DocumentModified
+	default:
+		change.Kind = kind
+	}
+	change.Commit = commit
+	if !byBot {
+		change.Author = author
+		change.ByBot = false
+	}
+}
+
+// Changes returns the change made to each document, in the order the documents were first changed
+func (p *DocumentPush) Changes() []DocumentChange {
+	changes := make([]DocumentChange, len(p.changes))
+	for i, change := range p.changes {
+		changes[i] = *change
+	}
+	return changes
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentPush_Changes(t *testing.T) {
+	push := NewDocumentPush("", "main")
+	push.Record("c1", "quill", true, []string{"docs/a.md", "docs/b.md"}, nil, []string{"docs/c.md"})
+	push.Record("c2", "alice", false, []string{"docs/c.md"}, []string{"docs/a.md"}, []string{"docs/b.md"})
+	push.Record("c3", "quill", true, nil, []string{"docs/a.md"}, nil)
+
+	assert.Equal(t, []DocumentChange{
+		{Path: "docs/a.md", Kind: DocumentAdded, Author: "alice", Commit: "c3"},
+		{Path: "docs/b.md", Kind: DocumentRemoved, Author: "alice", Commit: "c2"},
+		{Path: "docs/c.md", Kind: DocumentModified, Author: "alice", Commit: "c2"},
+	}, push.Changes())
+}
+
+func TestDocumentPush_BotOnlyChanges(t *testing.T) {
+	push := NewDocumentPush("team/handbook", "docs")
+	push.Record("c1", "quill", true, nil, []string{"docs/a.md"}, nil)
+
+	changes := push.Changes()
+
+	assert.Len(t, changes, 1)
+	assert.True(t, changes[0].ByBot)
+	assert.Empty(t, changes[0].Author)
+}
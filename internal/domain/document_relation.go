@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidDocumentRelation = errors.New("invalid document relation")
+
+// Relation is how a newer decision relates to an older one
+type Relation string
+
+const (
+	// RelationSupersedes replaces the older decision entirely
+	RelationSupersedes Relation = "supersedes"
+	// RelationAmends changes part of the older decision, the rest still holds
+	RelationAmends Relation = "amends"
+)
+
+// ParseRelation returns the relation named by s, like "supersedes" or "amends"
+func ParseRelation(s string) (Relation, error) {
+	relation := Relation(strings.ToLower(strings.TrimSpace(s)))
+	if !relation.IsValid() {
+		return "", fmt.Errorf("%w: unknown relation %q, use supersedes or amends", ErrInvalidDocumentRelation, s)
+	}
+	return relation, nil
+}
+
+// IsValid checks if the relation is known
+func (r Relation) IsValid() bool {
+	return r == RelationSupersedes || r == RelationAmends
+}
+
+// String returns the relation
+func (r Relation) String() string {
+	return string(r)
+}
+
+// Inverse returns the relation as seen from the older document, like "superseded by"
+func (r Relation) Inverse() string {
+	if r == RelationAmends {
+		return "amended by"
+	}
+	return "superseded by"
+}
+
+// Status returns the status of the older document once the relation is recorded
+func (r Relation) Status() DocumentStatus {
+	if r == RelationAmends {
+		return DocumentStatusAmended
+	}
+	return DocumentStatusSuperseded
+}
+
+// FrontMatterKey returns the front matter list of the newer document holding the older ones
+func (r Relation) FrontMatterKey() string {
+	return r.String()
+}
+
+// InverseFrontMatterKey returns the front matter list of the older document holding the newer ones
+func (r Relation) InverseFrontMatterKey() string {
+	return strings.ReplaceAll(r.Inverse(), " ", "_")
+}
+
+// DocumentRelation records that the document at from supersedes or amends the document at to
+type DocumentRelation struct {
+	from     string
+	to       string
+	relation Relation
+}
+
+// NewDocumentRelation creates a DocumentRelation between two documents
+func NewDocumentRelation(from string, relation Relation, to string) (*DocumentRelation, error) {
+	from = strings.TrimSpace(from)
+	to = strings.TrimSpace(to)
+	if from == "" || to == "" {
+		return nil, ErrEmptyDocumentPath
+	}
+	if from == to {
+		return nil, fmt.Errorf("%w: a document cannot relate to itself", ErrInvalidDocumentRelation)
+	}
+	if !relation.IsValid() {
+		return nil, fmt.Errorf("%w: unknown relation %q", ErrInvalidDocumentRelation, relation)
+	}
+
+	return &DocumentRelation{
+		from:     from,
+		to:       to,
+		relation: relation,
+	}, nil
+}
+
+// From returns the path of the newer document
+func (r *DocumentRelation) From() string {
+	return r.from
+}
+
+// To returns the path of the older document
+func (r *DocumentRelation) To() string {
+	return r.to
+}
+
+// Relation returns how the newer document relates to the older one
+func (r *DocumentRelation) Relation() Relation {
+	return r.relation
+}
+
+// String describes the relation, like "docs/b.md supersedes docs/a.md"
+func (r *DocumentRelation) String() string {
+	return fmt.Sprintf("%s %s %s", r.from, r.relation, r.to)
+}
+
+// Banner returns the note placed at the top of the older document, linking to the newer one
+func (r *DocumentRelation) Banner(title string) string {
+	if strings.TrimSpace(title) == "" {
+		title = filepath.Base(r.from)
+	}
+	link, err := filepath.Rel(filepath.Dir(r.to), r.from)
+	if err != nil {
+		link = "/" + r.from
+	}
+	return fmt.Sprintf("> ⚠️ This decision was %s [%s](%s).", r.relation.Inverse(), title, filepath.ToSlash(link))
+}
+
+// AddBanner places a banner below the top heading of a Markdown body, or at its top when it has none.
+// A body already carrying the banner is returned unchanged.
+func AddBanner(body, banner string) string {
+	if strings.Contains(body, banner) {
+		return body
+	}
+
+	trimmed := strings.TrimLeft(body, "\n")
+	if strings.HasPrefix(trimmed, "# ") {
+		heading, rest, _ := strings.Cut(trimmed, "\n")
+		return heading + "\n\n" + banner + "\n\n" + strings.TrimLeft(rest, "\n")
+	}
+	return banner + "\n\n" + trimmed
+}
+
+// SortRelations orders relations by their documents to keep results deterministic
+func SortRelations(relations []*DocumentRelation) {
+	sort.Slice(relations, func(i, j int) bool {
+		return relations[i].String() < relations[j].String()
+	})
+}
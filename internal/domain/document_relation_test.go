@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewDocumentRelation(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		relation Relation
+		to       string
+		wantErr  error
+	}{
+		{name: "supersedes", from: "docs/development/use-postgres.md", relation: RelationSupersedes, to: "docs/development/use-mysql.md"},
+		{name: "amends", from: "docs/development/use-postgres.md", relation: RelationAmends, to: "docs/development/use-mysql.md"},
+		{name: "empty path", from: " ", relation: RelationSupersedes, to: "docs/development/use-mysql.md", wantErr: ErrEmptyDocumentPath},
+		{name: "same document", from: "docs/development/use-mysql.md", relation: RelationSupersedes, to: "docs/development/use-mysql.md", wantErr: ErrInvalidDocumentRelation},
+		{name: "unknown relation", from: "docs/development/use-postgres.md", relation: "replaces", to: "docs/development/use-mysql.md", wantErr: ErrInvalidDocumentRelation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel, err := NewDocumentRelation(tt.from, tt.relation, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewDocumentRelation() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (rel.From() != tt.from || rel.To() != tt.to || rel.Relation() != tt.relation) {
+				t.Errorf("NewDocumentRelation() = %v", rel)
+			}
+		})
+	}
+}
+
+func TestParseRelation(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Relation
+		wantErr bool
+	}{
+		{input: "supersedes", want: RelationSupersedes},
+		{input: " Amends ", want: RelationAmends},
+		{input: "replaces", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRelation(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRelation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRelation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelation_FrontMatterKeys(t *testing.T) {
+	if RelationSupersedes.InverseFrontMatterKey() != "superseded_by" || RelationAmends.InverseFrontMatterKey() != "amended_by" {
+		t.Errorf("unexpected inverse keys %q and %q", RelationSupersedes.InverseFrontMatterKey(), RelationAmends.InverseFrontMatterKey())
+	}
+	if RelationSupersedes.Status() != DocumentStatusSuperseded || RelationAmends.Status() != DocumentStatusAmended {
+		t.Error("unexpected statuses of older documents")
+	}
+}
+
+func TestDocumentRelation_Banner(t *testing.T) {
+	rel, err := NewDocumentRelation("docs/2024/06/02/use-postgres.md", RelationSupersedes, "docs/development/use-mysql.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	banner := rel.Banner("Use Postgres")
+	want := "> ⚠️ This decision was superseded by [Use Postgres](../2024/06/02/use-postgres.md)."
+	if banner != want {
+		t.Errorf("Banner() = %q, want %q", banner, want)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "below the heading",
+			body: "# Use MySQL\n\nWe use MySQL.\n",
+			want: "# Use MySQL\n\n" + want + "\n\nWe use MySQL.\n",
+		},
+		{
+			name: "without a heading",
+			body: "We use MySQL.\n",
+			want: want + "\n\nWe use MySQL.\n",
+		},
+		{
+			name: "already there",
+			body: "# Use MySQL\n\n" + want + "\n\nWe use MySQL.\n",
+			want: "# Use MySQL\n\n" + want + "\n\nWe use MySQL.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddBanner(tt.body, banner); got != tt.want {
+				t.Errorf("AddBanner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDocumentReview = errors.New("invalid document review")
+
+// DefaultReviewAfter is how long a decision can go without changes or discussion before it is flagged for review
+const DefaultReviewAfter = 180 * 24 * time.Hour
+
+// DocumentStatus tells whether a document still describes how things are, kept in its front matter
+type DocumentStatus string
+
+const (
+	// DocumentStatusActive is the status of current documents, including those without a status
+	DocumentStatusActive DocumentStatus = "active"
+	// DocumentStatusNeedsReview marks documents people were asked to reconfirm or supersede
+	DocumentStatusNeedsReview DocumentStatus = "needs-review"
+	// DocumentStatusSuperseded marks documents that no longer apply
+	DocumentStatusSuperseded DocumentStatus = "superseded"
+	// DocumentStatusAmended marks documents partly changed by a newer one, the rest still applies
+	DocumentStatusAmended DocumentStatus = "amended"
+)
+
+// String returns the document status
+func (s DocumentStatus) String() string {
+	return string(s)
+}
+
+// IsCurrent checks if a document with the status still applies, at least in part
+func (s DocumentStatus) IsCurrent() bool {
+	return s == DocumentStatusActive || s == DocumentStatusAmended
+}
+
+// DocumentStatusOf returns the status recorded in a document's front matter
+func DocumentStatusOf(fm *FrontMatter) DocumentStatus {
+	if fm == nil {
+		return DocumentStatusActive
+	}
+	status := DocumentStatus(strings.TrimSpace(fm.Get("status")))
+	if status == "" {
+		return DocumentStatusActive
+	}
+	return status
+}
+
+// ReviewedAt returns when a document was last reviewed, zero when it never was
+func ReviewedAt(fm *FrontMatter) time.Time {
+	if fm == nil {
+		return time.Time{}
+	}
+	at, err := time.Parse(time.RFC3339, fm.Get("reviewed_at"))
+	if err != nil {
+		return time.Time{}
+	}
+	return at
+}
+
+// FlagForReview marks a document as waiting for people to reconfirm or supersede it
+func FlagForReview(fm *FrontMatter, at time.Time) {
+	fm.Set("status", DocumentStatusNeedsReview.String())
+	fm.Set("review_requested_at", at.UTC().Format(time.RFC3339))
+}
+
+// DueForReview checks if a document whose last change or discussion happened at lastActivity
+// has gone without any for longer than reviewAfter
+func DueForReview(lastActivity, now time.Time, reviewAfter time.Duration) bool {
+	if reviewAfter <= 0 {
+		reviewAfter = DefaultReviewAfter
+	}
+	return now.Sub(lastActivity) > reviewAfter
+}
+
+// ReviewVerdict is what people decided about a document flagged for review
+type ReviewVerdict string
+
+const (
+	// ReviewReconfirm keeps the document as it is
+	ReviewReconfirm ReviewVerdict = "reconfirm"
+	// ReviewSupersede retires the document
+	ReviewSupersede ReviewVerdict = "supersede"
+)
+
+// String returns the review verdict
+func (v ReviewVerdict) String() string {
+	return string(v)
+}
+
+// Status returns the status of a document given the verdict
+func (v ReviewVerdict) Status() DocumentStatus {
+	if v == ReviewSupersede {
+		return DocumentStatusSuperseded
+	}
+	return DocumentStatusActive
+}
+
+// DocumentReview is the verdict of a person on a document flagged for review
+type DocumentReview struct {
+	path    string
+	verdict ReviewVerdict
+	actor   string
+}
+
+// NewDocumentReview creates a DocumentReview of the document at path
+func NewDocumentReview(path string, verdict ReviewVerdict, actor string) (*DocumentReview, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, ErrEmptyDocumentPath
+	}
+	if verdict != ReviewReconfirm && verdict != ReviewSupersede {
+		return nil, fmt.Errorf("%w: unknown verdict %q", ErrInvalidDocumentReview, verdict)
+	}
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return nil, fmt.Errorf("%w: the reviewer is unknown", ErrInvalidDocumentReview)
+	}
+
+	return &DocumentReview{
+		path:    path,
+		verdict: verdict,
+		actor:   actor,
+	}, nil
+}
+
+// Path returns the path of the reviewed document
+func (r *DocumentReview) Path() string {
+	return r.path
+}
+
+// Verdict returns what the reviewer decided
+func (r *DocumentReview) Verdict() ReviewVerdict {
+	return r.verdict
+}
+
+// Actor returns who reviewed the document
+func (r *DocumentReview) Actor() string {
+	return r.actor
+}
+
+// Apply records the review in a document's front matter
+func (r *DocumentReview) Apply(fm *FrontMatter, at time.Time) {
+	fm.Set("status", r.verdict.Status().String())
+	fm.Set("reviewed_at", at.UTC().Format(time.RFC3339))
+	fm.Set("reviewed_by", r.actor)
+	fm.Delete("review_requested_at")
+}
+
+// RenderReviewReminder returns the message asking people to review a stale document
+func RenderReviewReminder(title, path string, lastActivity, now time.Time) string {
+	if strings.TrimSpace(title) == "" {
+		title = path
+	}
+	return fmt.Sprintf(
+		"🕰️ The decision *%s* (`%s`) has not changed or been discussed in %s. Does it still hold? Reconfirm it, or mark it superseded.",
+		title, path, formatWeeks(now.Sub(lastActivity)),
+	)
+}
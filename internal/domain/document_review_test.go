@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDocumentReview(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		verdict ReviewVerdict
+		actor   string
+		wantErr error
+	}{
+		{name: "reconfirm", path: "docs/development/queue.md", verdict: ReviewReconfirm, actor: "U0001"},
+		{name: "supersede", path: "docs/development/queue.md", verdict: ReviewSupersede, actor: "U0001"},
+		{name: "empty path", path: " ", verdict: ReviewReconfirm, actor: "U0001", wantErr: ErrEmptyDocumentPath},
+		{name: "unknown verdict", path: "docs/development/queue.md", verdict: "ignore", actor: "U0001", wantErr: ErrInvalidDocumentReview},
+		{name: "missing actor", path: "docs/development/queue.md", verdict: ReviewReconfirm, actor: "", wantErr: ErrInvalidDocumentReview},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review, err := NewDocumentReview(tt.path, tt.verdict, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewDocumentReview() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (review.Path() != tt.path || review.Verdict() != tt.verdict || review.Actor() != tt.actor) {
+				t.Errorf("NewDocumentReview() = %+v", review)
+			}
+		})
+	}
+}
+
+func TestDocumentReview_Apply(t *testing.T) {
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		verdict ReviewVerdict
+		want    DocumentStatus
+	}{
+		{name: "reconfirm", verdict: ReviewReconfirm, want: DocumentStatusActive},
+		{name: "supersede", verdict: ReviewSupersede, want: DocumentStatusSuperseded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := NewFrontMatter()
+			FlagForReview(fm, at.Add(-time.Hour))
+			if got := DocumentStatusOf(fm); got != DocumentStatusNeedsReview {
+				t.Fatalf("DocumentStatusOf() after flagging = %q", got)
+			}
+
+			review, err := NewDocumentReview("docs/development/queue.md", tt.verdict, "U0001")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			review.Apply(fm, at)
+
+			if got := DocumentStatusOf(fm); got != tt.want {
+				t.Errorf("DocumentStatusOf() = %q, want %q", got, tt.want)
+			}
+			if got := ReviewedAt(fm); !got.Equal(at) {
+				t.Errorf("ReviewedAt() = %v, want %v", got, at)
+			}
+			if fm.Get("reviewed_by") != "U0001" || fm.Has("review_requested_at") {
+				t.Errorf("front matter = %q", fm.Render())
+			}
+		})
+	}
+}
+
+func TestDocumentStatusOf_DefaultsToActive(t *testing.T) {
+	if got := DocumentStatusOf(NewFrontMatter()); got != DocumentStatusActive {
+		t.Errorf("DocumentStatusOf() = %q, want %q", got, DocumentStatusActive)
+	}
+	if got := DocumentStatusOf(nil); got != DocumentStatusActive {
+		t.Errorf("DocumentStatusOf(nil) = %q, want %q", got, DocumentStatusActive)
+	}
+	if !ReviewedAt(NewFrontMatter()).IsZero() {
+		t.Error("ReviewedAt() of a document never reviewed should be zero")
+	}
+}
+
+func TestDueForReview(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		lastActivity time.Time
+		reviewAfter  time.Duration
+		want         bool
+	}{
+		{name: "recent", lastActivity: now.AddDate(0, -1, 0), want: false},
+		{name: "older than the default period", lastActivity: now.AddDate(0, -7, 0), want: true},
+		{name: "older than a custom period", lastActivity: now.AddDate(0, -1, 0), reviewAfter: 14 * 24 * time.Hour, want: true},
+		{name: "exactly the period", lastActivity: now.Add(-DefaultReviewAfter), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DueForReview(tt.lastActivity, now, tt.reviewAfter); got != tt.want {
+				t.Errorf("DueForReview() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderReviewReminder(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	got := RenderReviewReminder("Use a queue for billing", "docs/development/queue.md", now.AddDate(0, 0, -196), now)
+
+	for _, want := range []string{"*Use a queue for billing*", "`docs/development/queue.md`", "28 weeks", "Reconfirm"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderReviewReminder() = %q, want it to contain %q", got, want)
+		}
+	}
+	if untitled := RenderReviewReminder("", "docs/development/queue.md", now.AddDate(0, 0, -196), now); !strings.Contains(untitled, "*docs/development/queue.md*") {
+		t.Errorf("RenderReviewReminder() without a title = %q", untitled)
+	}
+}
@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrInvalidErasure = errors.New("invalid erasure request")
+
+// ErasedIdentity replaces the identity of an erased person in the records that are kept, like the audit log
+const ErasedIdentity = "erased"
+
+// personFrontMatterKeys are the front matter keys naming people in every document, the documents of features like
+// incidents name people under keys of their own
+var personFrontMatterKeys = []string{"author", "authors", "reviewed_by", "owner", "reported_by"}
+
+// ErasureMode is how the data of a person is removed
+type ErasureMode string
+
+const (
+	// ErasureErase deletes the person's messages and removes their name from documents and records
+	ErasureErase ErasureMode = "erase"
+	// ErasurePseudonymize keeps the data but replaces the person's identity with a pseudonym
+	ErasurePseudonymize ErasureMode = "pseudonymize"
+)
+
+// ParseErasureMode returns the mode named by s, like "erase" or "pseudonymize"
+func ParseErasureMode(s string) (ErasureMode, error) {
+	mode := ErasureMode(strings.ToLower(strings.TrimSpace(s)))
+	if !mode.IsValid() {
+		return "", fmt.Errorf("%w: mode %q, use erase or pseudonymize", ErrInvalidErasure, s)
+	}
+	return mode, nil
+}
+
+// IsValid checks if the mode is known
+func (m ErasureMode) IsValid() bool {
+	return m == ErasureErase || m == ErasurePseudonymize
+}
+
+// String returns the mode
+func (m ErasureMode) String() string {
+	return string(m)
+}
+
+// Pseudonym returns the pseudonym replacing an identity, like "user-3f2a9c1b7d4e". The same identity
+// always gets the same pseudonym, so the records of a person stay linked without naming them.
+func Pseudonym(identity string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(identity)))
+	return "user-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// ErasureRequest asks for the data stored about a person to be erased or pseudonymized
+type ErasureRequest struct {
+	identity    string
+	mode        ErasureMode
+	requestedBy string
+}
+
+// NewErasureRequest creates an ErasureRequest for the identity the chat platform knows the person by
+func NewErasureRequest(identity string, mode ErasureMode, requestedBy string) (*ErasureRequest, error) {
+	identity = strings.TrimSpace(identity)
+	requestedBy = strings.TrimSpace(requestedBy)
+	if identity == "" || identity == ErasedIdentity {
+		return nil, fmt.Errorf("%w: identity is required", ErrInvalidErasure)
+	}
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("%w: mode %q, use erase or pseudonymize", ErrInvalidErasure, mode)
+	}
+	if requestedBy == "" {
+		return nil, fmt.Errorf("%w: requester is required", ErrInvalidErasure)
+	}
+	return &ErasureRequest{identity: identity, mode: mode, requestedBy: requestedBy}, nil
+}
+
+// Identity returns the identity of the person
+func (r *ErasureRequest) Identity() string {
+	return r.identity
+}
+
+// Mode returns how the data is removed
+func (r *ErasureRequest) Mode() ErasureMode {
+	return r.mode
+}
+
+// RequestedBy returns who asked for the erasure
+func (r *ErasureRequest) RequestedBy() string {
+	return r.requestedBy
+}
+
+// Pseudonym returns the pseudonym of the person, it is also how the erasure refers to them in the audit log
+func (r *ErasureRequest) Pseudonym() string {
+	return Pseudonym(r.identity)
+}
+
+// Replacement returns what takes the place of the identity in the records that are kept
+func (r *ErasureRequest) Replacement() string {
+	if r.mode == ErasurePseudonymize {
+		return r.Pseudonym()
+	}
+	return ErasedIdentity
+}
+
+// ApplyToMessage pseudonymizes a message the person sent, and reports if they sent it. Erased messages are
+// deleted by the stores keeping them rather than changed.
+func (r *ErasureRequest) ApplyToMessage(msg *Message) bool {
+	if msg == nil || msg.Sender() != r.identity {
+		return false
+	}
+	if r.mode == ErasurePseudonymize {
+		msg.Pseudonymize(r.Pseudonym())
+	}
+	return true
+}
+
+// ApplyTo removes the person from the fields of the front matter naming people, and from the fields of the
+// other keys, and reports if any did. Erasing deletes the person from the fields, and the fields left empty,
+// pseudonymizing replaces them with the pseudonym.
+func (r *ErasureRequest) ApplyTo(fm *FrontMatter, keys ...string) bool {
+	changed := false
+	for _, key := range append(append([]string(nil), personFrontMatterKeys...), keys...) {
+		if list := fm.GetList(key); len(list) > 0 {
+			if !containsString(list, r.identity) {
+				continue
+			}
+			kept := make([]string, 0, len(list))
+			for _, person := range list {
+				if person != r.identity {
+					kept = append(kept, person)
+				} else if r.mode == ErasurePseudonymize {
+					kept = append(kept, r.Pseudonym())
+				}
+			}
+			if len(kept) == 0 {
+				fm.Delete(key)
+			} else {
+				fm.SetList(key, kept)
+			}
+			changed = true
+			continue
+		}
+		if fm.Get(key) != r.identity {
+			continue
+		}
+		if r.mode == ErasurePseudonymize {
+			fm.Set(key, r.Pseudonym())
+		} else {
+			fm.Delete(key)
+		}
+		changed = true
+	}
+	return changed
+}
+
+// ApplyToBody replaces the person's name in a document body where the formats put names, and reports if any
+// did. A format is the part of a line naming a person, with %s where the name goes, like " by %s\n" for a line
+// ending with the name. Both modes keep the lines, naming the person with the replacement.
+func (r *ErasureRequest) ApplyToBody(body string, formats ...string) (string, bool) {
+	lines := strings.SplitAfter(body, "\n")
+	changed := false
+	for i, line := range lines {
+		// The last line is matched as if it ended with a newline too
+		ended := strings.HasSuffix(line, "\n")
+		if !ended {
+			line += "\n"
+		}
+		for _, format := range formats {
+			named := fmt.Sprintf(format, r.identity)
+			if strings.Contains(line, named) {
+				line = strings.ReplaceAll(line, named, fmt.Sprintf(format, r.Replacement()))
+				changed = true
+			}
+		}
+		if !ended {
+			line = strings.TrimSuffix(line, "\n")
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, ""), changed
+}
+
+// DeletionReport tells what an erasure changed, for the person who asked for it
+type DeletionReport struct {
+	Identity  string
+	Mode      ErasureMode
+	Pseudonym string
+	// Messages counts the messages the person sent that were deleted or pseudonymized
+	Messages int
+	// Corrections counts the corrections of the person's messages that were deleted, and those they made
+	Corrections int
+	// AuditEntries counts the audit entries the person was the actor of
+	AuditEntries int
+	// Documents are the paths of the documents naming the person in their front matter or body
+	Documents []string
+	// ReadOnlyDocuments are the paths of the documents naming the person that were left as they are, since their
+	// project is archived. They are erased once the project is resumed and the erasure run again.
+	ReadOnlyDocuments []string
+	// Records counts the records naming the person that were erased or pseudonymized, by the store keeping them
+	Records     map[string]int
+	CompletedAt time.Time
+}
+
+// String renders the report as plain text
+func (r DeletionReport) String() string {
+	var b strings.Builder
+	verb := "Erased"
+	if r.Mode == ErasurePseudonymize {
+		verb = "Pseudonymized"
+	}
+	b.WriteString(fmt.Sprintf("%s the data of %s (pseudonym %s) at %s\n", verb, r.Identity, r.Pseudonym, r.CompletedAt.UTC().Format(time.RFC3339)))
+	b.WriteString(fmt.Sprintf("Messages: %d\n", r.Messages))
+	b.WriteString(fmt.Sprintf("Corrections: %d\n", r.Corrections))
+	b.WriteString(fmt.Sprintf("Audit entries: %d\n", r.AuditEntries))
+	b.WriteString(fmt.Sprintf("Documents: %d\n", len(r.Documents)))
+	for _, path := range r.Documents {
+		b.WriteString("  " + path + "\n")
+	}
+	if len(r.ReadOnlyDocuments) > 0 {
+		b.WriteString(fmt.Sprintf("Documents of archived projects left as they are: %d\n", len(r.ReadOnlyDocuments)))
+		for _, path := range r.ReadOnlyDocuments {
+			b.WriteString("  " + path + "\n")
+		}
+	}
+	stores := make([]string, 0, len(r.Records))
+	for store := range r.Records {
+		stores = append(stores, store)
+	}
+	sort.Strings(stores)
+	for _, store := range stores {
+		b.WriteString(fmt.Sprintf("%s: %d\n", store, r.Records[store]))
+	}
+	return b.String()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
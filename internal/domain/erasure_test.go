@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewErasureRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		identity    string
+		mode        ErasureMode
+		requestedBy string
+		wantErr     bool
+	}{
+		{name: "erase", identity: " U0001 ", mode: ErasureErase, requestedBy: "dpo"},
+		{name: "pseudonymize", identity: "U0001", mode: ErasurePseudonymize, requestedBy: "dpo"},
+		{name: "no identity", identity: " ", mode: ErasureErase, requestedBy: "dpo", wantErr: true},
+		{name: "erased identity", identity: ErasedIdentity, mode: ErasureErase, requestedBy: "dpo", wantErr: true},
+		{name: "unknown mode", identity: "U0001", mode: "forget", requestedBy: "dpo", wantErr: true},
+		{name: "no requester", identity: "U0001", mode: ErasureErase, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := NewErasureRequest(tt.identity, tt.mode, tt.requestedBy)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidErasure)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "U0001", request.Identity())
+			assert.Equal(t, tt.mode, request.Mode())
+		})
+	}
+}
+
+func TestPseudonym(t *testing.T) {
+	assert.Equal(t, Pseudonym("U0001"), Pseudonym(" U0001"))
+	assert.NotEqual(t, Pseudonym("U0001"), Pseudonym("U0002"))
+	assert.True(t, strings.HasPrefix(Pseudonym("U0001"), "user-"))
+	assert.Len(t, Pseudonym("U0001"), len("user-")+12)
+}
+
+func TestErasureRequest_ApplyTo(t *testing.T) {
+	newFrontMatter := func() *FrontMatter {
+		fm := NewFrontMatter()
+		fm.Set("type", "decision")
+		fm.Set("reviewed_by", "U0001")
+		fm.SetList("authors", []string{"U0002", "U0001"})
+		return fm
+	}
+
+	t.Run("erase", func(t *testing.T) {
+		request, err := NewErasureRequest("U0001", ErasureErase, "dpo")
+		require.NoError(t, err)
+		fm := newFrontMatter()
+
+		assert.True(t, request.ApplyTo(fm))
+		assert.False(t, fm.Has("reviewed_by"))
+		assert.Equal(t, []string{"U0002"}, fm.GetList("authors"))
+		assert.Equal(t, "decision", fm.Get("type"))
+		assert.False(t, request.ApplyTo(fm))
+	})
+
+	t.Run("pseudonymize", func(t *testing.T) {
+		request, err := NewErasureRequest("U0001", ErasurePseudonymize, "dpo")
+		require.NoError(t, err)
+		fm := newFrontMatter()
+
+		assert.True(t, request.ApplyTo(fm))
+		assert.Equal(t, request.Pseudonym(), fm.Get("reviewed_by"))
+		assert.Equal(t, []string{"U0002", request.Pseudonym()}, fm.GetList("authors"))
+	})
+
+	t.Run("keys of features", func(t *testing.T) {
+		request, err := NewErasureRequest("U0001", ErasureErase, "dpo")
+		require.NoError(t, err)
+		fm := NewFrontMatter()
+		fm.Set("owner", "U0001")
+		fm.Set("incident_commander", "U0001")
+		fm.SetList("attendees", []string{"U0001", "U0002"})
+
+		assert.True(t, request.ApplyTo(fm, IncidentPersonKeys()...))
+		assert.False(t, fm.Has("owner"))
+		assert.False(t, fm.Has("incident_commander"))
+		assert.Equal(t, []string{"U0001", "U0002"}, fm.GetList("attendees"), "keys not given are kept")
+		assert.True(t, request.ApplyTo(fm, MeetingNotesPersonKeys()...))
+		assert.Equal(t, []string{"U0002"}, fm.GetList("attendees"))
+		fm.SetList("answered_by", []string{"U0001"})
+		assert.True(t, request.ApplyTo(fm, StandupPersonKeys()...))
+		assert.False(t, fm.Has("answered_by"), "lists left empty are removed")
+	})
+
+	t.Run("not named", func(t *testing.T) {
+		request, err := NewErasureRequest("U0003", ErasureErase, "dpo")
+		require.NoError(t, err)
+
+		assert.False(t, request.ApplyTo(newFrontMatter()))
+	})
+}
+
+func TestErasureRequest_ApplyToMessage(t *testing.T) {
+	newMessage := func(sender string) *Message {
+		msg, err := NewMessage(common.GenerateID(), sender, MustNewMessageContent("We decided to use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+		require.NoError(t, err)
+		return msg
+	}
+	erase, err := NewErasureRequest("U0001", ErasureErase, "dpo")
+	require.NoError(t, err)
+	pseudonymize, err := NewErasureRequest("U0001", ErasurePseudonymize, "dpo")
+	require.NoError(t, err)
+
+	erased := newMessage("U0001")
+	assert.True(t, erase.ApplyToMessage(erased))
+	assert.Equal(t, "U0001", erased.Sender(), "erased messages are deleted by their stores")
+	pseudonymized := newMessage("U0001")
+	assert.True(t, pseudonymize.ApplyToMessage(pseudonymized))
+	assert.Equal(t, pseudonymize.Pseudonym(), pseudonymized.Sender())
+	assert.False(t, pseudonymize.ApplyToMessage(newMessage("U0002")))
+	assert.False(t, pseudonymize.ApplyToMessage(nil))
+}
+
+func TestErasureRequest_ApplyToBody(t *testing.T) {
+	erase, err := NewErasureRequest("U0001", ErasureErase, "dpo")
+	require.NoError(t, err)
+	pseudonymize, err := NewErasureRequest("U0001", ErasurePseudonymize, "dpo")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		request     *ErasureRequest
+		body        string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "erase",
+			request:     erase,
+			body:        "## Mon 2026-10-12 09:00 UTC by U0001\n\nShipped the importer\n",
+			want:        "## Mon 2026-10-12 09:00 UTC by " + ErasedIdentity + "\n\nShipped the importer\n",
+			wantChanged: true,
+		},
+		{
+			name:        "pseudonymize",
+			request:     pseudonymize,
+			body:        "## Mon 2026-10-12 09:00 UTC by U0001\n",
+			want:        "## Mon 2026-10-12 09:00 UTC by " + pseudonymize.Pseudonym() + "\n",
+			wantChanged: true,
+		},
+		{
+			name:        "last line without a newline",
+			request:     erase,
+			body:        "# Rollup\n\n## Mon 2026-10-12 09:00 UTC by U0001",
+			want:        "# Rollup\n\n## Mon 2026-10-12 09:00 UTC by " + ErasedIdentity,
+			wantChanged: true,
+		},
+		{
+			name:    "another person",
+			request: erase,
+			body:    "## Mon 2026-10-12 09:00 UTC by U00012\n",
+			want:    "## Mon 2026-10-12 09:00 UTC by U00012\n",
+		},
+		{
+			name:    "named outside the formats",
+			request: erase,
+			body:    "Paired with U0001 on the importer\n",
+			want:    "Paired with U0001 on the importer\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := tt.request.ApplyToBody(tt.body, StatusRollupBodyNames()...)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
+
+func TestDeletionReport_String(t *testing.T) {
+	report := DeletionReport{
+		Identity:     "U0001",
+		Mode:         ErasurePseudonymize,
+		Pseudonym:    Pseudonym("U0001"),
+		Messages:     3,
+		Corrections:  1,
+		AuditEntries: 2,
+		Documents:    []string{"docs/development/use-postgres.md"},
+		Records:      map[string]int{"threads": 2, "dead letters": 1},
+		CompletedAt:  time.Date(2024, 2, 14, 9, 0, 0, 0, time.UTC),
+	}
+
+	text := report.String()
+
+	assert.Contains(t, text, "Pseudonymized the data of U0001 (pseudonym "+Pseudonym("U0001")+") at 2024-02-14T09:00:00Z")
+	assert.Contains(t, text, "Messages: 3")
+	assert.Contains(t, text, "Audit entries: 2")
+	assert.Contains(t, text, "Documents: 1\n  docs/development/use-postgres.md\ndead letters: 1\nthreads: 2\n")
+	assert.NotContains(t, text, "archived projects")
+
+	report.ReadOnlyDocuments = []string{"docs/development/use-mysql.md"}
+	assert.Contains(t, report.String(), "Documents: 1\n  docs/development/use-postgres.md\nDocuments of archived projects left as they are: 1\n  docs/development/use-mysql.md\n")
+}
@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidLabeledMessage = errors.New("invalid labeled message")
+
+// LabeledMessage is a message with the type and category a person assigned to it,
+// used to measure how well an AI agent analyzes messages
+type LabeledMessage struct {
+	// Content is the message text
+	Content string `json:"content"`
+	// Type is the expected message type
+	Type MessageType `json:"type"`
+	// Category is the expected category
+	Category Category `json:"category"`
+}
+
+// Validate ensures the labeled message can be evaluated
+func (m LabeledMessage) Validate() error {
+	if strings.TrimSpace(m.Content) == "" {
+		return fmt.Errorf("%w: content cannot be empty", ErrInvalidLabeledMessage)
+	}
+	if !m.Type.IsValid() {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidLabeledMessage, m.Type)
+	}
+	if !m.Category.IsValid() {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidLabeledMessage, m.Category)
+	}
+	return nil
+}
+
+// LabelMetrics are the precision and recall of predicting one label
+type LabelMetrics struct {
+	Label     string
+	Precision float64
+	Recall    float64
+	// Support is how many messages carry the label
+	Support int
+}
+
+// F1 returns the harmonic mean of precision and recall
+func (m LabelMetrics) F1() float64 {
+	if m.Precision+m.Recall == 0 {
+		return 0
+	}
+	return 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+}
+
+// CalibrationBin compares the confidence an AI agent reported with how often it was right
+type CalibrationBin struct {
+	// Lower and Upper bound the confidence scores in the bin, Upper is exclusive except for the last bin
+	Lower, Upper float64
+	Count        int
+	// Confidence is the mean reported confidence
+	Confidence float64
+	// Accuracy is the share of analyses that got both type and category right
+	Accuracy float64
+}
+
+// evaluatedMessage is the outcome of analyzing one labeled message
+type evaluatedMessage struct {
+	label       LabeledMessage
+	messageType MessageType
+	category    Category
+	confidence  float64
+}
+
+// Evaluation measures the analyses of one AI agent configuration against labeled messages
+type Evaluation struct {
+	name     string
+	results  []evaluatedMessage
+	failures int
+}
+
+// NewEvaluation creates an empty evaluation of the named configuration
+func NewEvaluation(name string) *Evaluation {
+	return &Evaluation{name: name}
+}
+
+// Name returns the name of the evaluated configuration
+func (e *Evaluation) Name() string {
+	return e.name
+}
+
+// Record adds the analysis of a labeled message, a nil result counts as a failure
+func (e *Evaluation) Record(label LabeledMessage, result *MessageAnalysisResult) {
+	if result == nil {
+		e.RecordFailure()
+		return
+	}
+	e.results = append(e.results, evaluatedMessage{
+		label:       label,
+		messageType: result.MessageType(),
+		category:    result.Category(),
+		confidence:  result.ConfidenceScore(),
+	})
+}
+
+// RecordFailure counts a labeled message the AI agent failed to analyze
+func (e *Evaluation) RecordFailure() {
+	e.failures++
+}
+
+// Total returns how many labeled messages were evaluated, failures included
+func (e *Evaluation) Total() int {
+	return len(e.results) + e.failures
+}
+
+// Failures returns how many labeled messages could not be analyzed
+func (e *Evaluation) Failures() int {
+	return e.failures
+}
+
+// Accuracy returns the share of labeled messages whose type and category were both right.
+// Failed analyses count as wrong.
+func (e *Evaluation) Accuracy() float64 {
+	if e.Total() == 0 {
+		return 0
+	}
+	correct := 0
+	for _, r := range e.results {
+		if r.correct() {
+			correct++
+		}
+	}
+	return float64(correct) / float64(e.Total())
+}
+
+// TypeMetrics returns the precision and recall of every message type that was expected or predicted
+func (e *Evaluation) TypeMetrics() []LabelMetrics {
+	return e.labelMetrics(func(r evaluatedMessage) (string, string) {
+		return r.label.Type.String(), r.messageType.String()
+	})
+}
+
+// CategoryMetrics returns the precision and recall of every category that was expected or predicted
+func (e *Evaluation) CategoryMetrics() []LabelMetrics {
+	return e.labelMetrics(func(r evaluatedMessage) (string, string) {
+		return r.label.Category.String(), r.category.String()
+	})
+}
+
+func (e *Evaluation) labelMetrics(labels func(evaluatedMessage) (expected, predicted string)) []LabelMetrics {
+	truePositives := make(map[string]int)
+	expectedCounts := make(map[string]int)
+	predictedCounts := make(map[string]int)
+
+	for _, r := range e.results {
+		expected, predicted := labels(r)
+		expectedCounts[expected]++
+		predictedCounts[predicted]++
+		if expected == predicted {
+			truePositives[expected]++
+		}
+	}
+
+	names := make([]string, 0, len(expectedCounts)+len(predictedCounts))
+	for name := range expectedCounts {
+		names = append(names, name)
+	}
+	for name := range predictedCounts {
+		if _, ok := expectedCounts[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	metrics := make([]LabelMetrics, 0, len(names))
+	for _, name := range names {
+		m := LabelMetrics{Label: name, Support: expectedCounts[name]}
+		if predictedCounts[name] > 0 {
+			m.Precision = float64(truePositives[name]) / float64(predictedCounts[name])
+		}
+		if expectedCounts[name] > 0 {
+			m.Recall = float64(truePositives[name]) / float64(expectedCounts[name])
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// Calibration groups the analyses into equally wide confidence bins, empty bins are left out
+func (e *Evaluation) Calibration(bins int) []CalibrationBin {
+	if bins <= 0 {
+		return nil
+	}
+
+	calibration := make([]CalibrationBin, bins)
+	correct := make([]int, bins)
+	for i := range calibration {
+		calibration[i].Lower = float64(i) / float64(bins)
+		calibration[i].Upper = float64(i+1) / float64(bins)
+	}
+
+	for _, r := range e.results {
+		i := int(math.Min(r.confidence*float64(bins), float64(bins-1)))
+		calibration[i].Count++
+		calibration[i].Confidence += r.confidence
+		if r.correct() {
+			correct[i]++
+		}
+	}
+
+	filled := make([]CalibrationBin, 0, bins)
+	for i, bin := range calibration {
+		if bin.Count == 0 {
+			continue
+		}
+		bin.Confidence /= float64(bin.Count)
+		bin.Accuracy = float64(correct[i]) / float64(bin.Count)
+		filled = append(filled, bin)
+	}
+	return filled
+}
+
+// CalibrationError returns the expected calibration error over the bins, the gap between reported
+// confidence and accuracy weighted by how many analyses fall in each bin. Zero is perfectly calibrated.
+func (e *Evaluation) CalibrationError(bins int) float64 {
+	if len(e.results) == 0 {
+		return 0
+	}
+	var gap float64
+	for _, bin := range e.Calibration(bins) {
+		gap += float64(bin.Count) * math.Abs(bin.Confidence-bin.Accuracy)
+	}
+	return gap / float64(len(e.results))
+}
+
+func (r evaluatedMessage) correct() bool {
+	return r.messageType == r.label.Type && r.category == r.label.Category
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func mustAnalysis(t *testing.T, messageType MessageType, category Category, confidence float64) *MessageAnalysisResult {
+	t.Helper()
+	result, err := NewMessageAnalysisResult(messageType, category, nil, confidence, nil)
+	if err != nil {
+		t.Fatalf("NewMessageAnalysisResult() error = %v", err)
+	}
+	return result
+}
+
+func TestLabeledMessage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		message LabeledMessage
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			message: LabeledMessage{Content: "Let's use Postgres", Type: MessageTypeDecision, Category: CategoryDevelopment},
+		},
+		{
+			name:    "empty content",
+			message: LabeledMessage{Content: " ", Type: MessageTypeDecision, Category: CategoryDevelopment},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			message: LabeledMessage{Content: "Let's use Postgres", Type: "rumor", Category: CategoryDevelopment},
+			wantErr: true,
+		},
+		{
+			name:    "unknown category",
+			message: LabeledMessage{Content: "Let's use Postgres", Type: MessageTypeDecision, Category: "sales"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.message.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidLabeledMessage) {
+				t.Errorf("Validate() error = %v, want ErrInvalidLabeledMessage", err)
+			}
+		})
+	}
+}
+
+func TestEvaluation_Metrics(t *testing.T) {
+	decision := LabeledMessage{Content: "Let's use Postgres", Type: MessageTypeDecision, Category: CategoryDevelopment}
+	idea := LabeledMessage{Content: "What if we cached builds?", Type: MessageTypeIdea, Category: CategoryDevelopment}
+
+	e := NewEvaluation("ollama")
+	e.Record(decision, mustAnalysis(t, MessageTypeDecision, CategoryDevelopment, 0.9))
+	e.Record(decision, mustAnalysis(t, MessageTypeIdea, CategoryDevelopment, 0.8))
+	e.Record(idea, mustAnalysis(t, MessageTypeIdea, CategoryOperations, 0.3))
+	e.Record(idea, nil)
+
+	if got := e.Total(); got != 4 {
+		t.Errorf("Total() = %d, want 4", got)
+	}
+	if got := e.Failures(); got != 1 {
+		t.Errorf("Failures() = %d, want 1", got)
+	}
+	if got := e.Accuracy(); got != 0.25 {
+		t.Errorf("Accuracy() = %v, want 0.25", got)
+	}
+
+	want := []LabelMetrics{
+		{Label: "decision", Precision: 1, Recall: 0.5, Support: 2},
+		{Label: "idea", Precision: 0.5, Recall: 1, Support: 1},
+	}
+	got := e.TypeMetrics()
+	if len(got) != len(want) {
+		t.Fatalf("TypeMetrics() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TypeMetrics()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	categories := e.CategoryMetrics()
+	if len(categories) != 2 || categories[0].Label != "development" || categories[1].Label != "operations" {
+		t.Fatalf("CategoryMetrics() = %+v, want development and operations", categories)
+	}
+	if categories[1].Support != 0 || categories[1].Precision != 0 {
+		t.Errorf("CategoryMetrics() operations = %+v, want a wrong prediction without support", categories[1])
+	}
+}
+
+func TestEvaluation_Calibration(t *testing.T) {
+	decision := LabeledMessage{Content: "Let's use Postgres", Type: MessageTypeDecision, Category: CategoryDevelopment}
+
+	e := NewEvaluation("openai")
+	e.Record(decision, mustAnalysis(t, MessageTypeDecision, CategoryDevelopment, 1))
+	e.Record(decision, mustAnalysis(t, MessageTypeIdea, CategoryDevelopment, 0.9))
+	e.Record(decision, mustAnalysis(t, MessageTypeDecision, CategoryDevelopment, 0.2))
+
+	bins := e.Calibration(2)
+	if len(bins) != 2 {
+		t.Fatalf("Calibration() = %+v, want 2 bins", bins)
+	}
+	if bins[0].Count != 1 || bins[0].Accuracy != 1 {
+		t.Errorf("Calibration() low bin = %+v, want one correct analysis", bins[0])
+	}
+	if bins[1].Count != 2 || bins[1].Accuracy != 0.5 || math.Abs(bins[1].Confidence-0.95) > 1e-9 {
+		t.Errorf("Calibration() high bin = %+v, want two analyses at 0.95 confidence, half correct", bins[1])
+	}
+
+	// (1*|0.2-1| + 2*|0.95-0.5|) / 3
+	if got := e.CalibrationError(2); math.Abs(got-(0.8+0.9)/3) > 1e-9 {
+		t.Errorf("CalibrationError() = %v, want %v", got, (0.8+0.9)/3)
+	}
+	if got := NewEvaluation("empty").CalibrationError(10); got != 0 {
+		t.Errorf("CalibrationError() of an empty evaluation = %v, want 0", got)
+	}
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidFeatureFlag = errors.New("invalid feature flag")
+
+// Feature is an experimental behavior rolled out gradually with a feature flag
+type Feature string
+
+const (
+	// FeatureEmbeddingReferences matches references against documents by embedding similarity, not only by title
+	FeatureEmbeddingReferences Feature = "embedding-references"
+	// FeatureApprovals holds decisions until the approval policies of their project are met
+	FeatureApprovals Feature = "approvals"
+	// FeatureQuestions answers questions about the documentation with the ask command
+	FeatureQuestions Feature = "questions"
+)
+
+// Features returns the features that can be flagged
+func Features() []Feature {
+	return []Feature{FeatureEmbeddingReferences, FeatureApprovals, FeatureQuestions}
+}
+
+// IsValid checks if the feature can be flagged
+func (f Feature) IsValid() bool {
+	for _, feature := range Features() {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Feature) String() string {
+	return string(f)
+}
+
+// FeatureFlag decides which projects of the workspace a feature is on for. It is on for every project when
+// Enabled, otherwise for the projects listed by ID or name and for a stable Rollout percentage of the others.
+type FeatureFlag struct {
+	Enabled  bool     `json:"enabled"`
+	Projects []string `json:"projects,omitempty"`
+	// Rollout is the percentage of projects, between 0 and 100, the feature is on for
+	Rollout int `json:"rollout,omitempty"`
+}
+
+// Validate ensures the flag is usable
+func (f FeatureFlag) Validate() error {
+	if f.Rollout < 0 || f.Rollout > 100 {
+		return fmt.Errorf("%w: rollout must be between 0 and 100", ErrInvalidFeatureFlag)
+	}
+	for _, project := range f.Projects {
+		if strings.TrimSpace(project) == "" {
+			return fmt.Errorf("%w: projects cannot be blank", ErrInvalidFeatureFlag)
+		}
+	}
+	return nil
+}
+
+// EnabledFor checks if the feature is on for a project. Messages outside projects only get it when Enabled.
+func (f FeatureFlag) EnabledFor(feature Feature, project *Project) bool {
+	if f.Enabled {
+		return true
+	}
+	if project == nil {
+		return false
+	}
+	for _, listed := range f.Projects {
+		if listed == project.ID().String() || strings.EqualFold(listed, project.Name()) {
+			return true
+		}
+	}
+	return f.Rollout > 0 && rolloutBucket(feature, project) < f.Rollout
+}
+
+// rolloutBucket places a project in one of 100 buckets, the same one every time, so raising the rollout only
+// adds projects. The feature is part of the key so each feature rolls out to different projects first.
+func rolloutBucket(feature Feature, project *Project) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(feature.String() + ":" + project.ID().String()))
+	return int(h.Sum32() % 100)
+}
+
+// FeatureFlags holds the flags of the features rolling out. Features without a flag are on.
+type FeatureFlags map[Feature]FeatureFlag
+
+// Validate ensures every flag is usable and flags a known feature
+func (f FeatureFlags) Validate() error {
+	for _, feature := range f.features() {
+		if !feature.IsValid() {
+			return fmt.Errorf("%w: unknown feature %q", ErrInvalidFeatureFlag, feature)
+		}
+		if err := f[feature].Validate(); err != nil {
+			return fmt.Errorf("%s: %w", feature, err)
+		}
+	}
+	return nil
+}
+
+// Enabled checks if a feature is on for a project, nil for messages outside projects
+func (f FeatureFlags) Enabled(feature Feature, project *Project) bool {
+	flag, ok := f[feature]
+	if !ok {
+		return true
+	}
+	return flag.EnabledFor(feature, project)
+}
+
+// Merge returns the flags with the flags of overrides replacing those of the same features
+func (f FeatureFlags) Merge(overrides FeatureFlags) FeatureFlags {
+	merged := make(FeatureFlags, len(f)+len(overrides))
+	for feature, flag := range f {
+		merged[feature] = flag
+	}
+	for feature, flag := range overrides {
+		merged[feature] = flag
+	}
+	return merged
+}
+
+// features returns the flagged features in a stable order
+func (f FeatureFlags) features() []Feature {
+	features := make([]Feature, 0, len(f))
+	for feature := range f {
+		features = append(features, feature)
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
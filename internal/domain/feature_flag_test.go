@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureFlag_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    FeatureFlag
+		wantErr bool
+	}{
+		{name: "enabled", flag: FeatureFlag{Enabled: true}},
+		{name: "projects and rollout", flag: FeatureFlag{Projects: []string{"Billing"}, Rollout: 25}},
+		{name: "rollout above 100", flag: FeatureFlag{Rollout: 101}, wantErr: true},
+		{name: "negative rollout", flag: FeatureFlag{Rollout: -1}, wantErr: true},
+		{name: "blank project", flag: FeatureFlag{Projects: []string{" "}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.flag.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidFeatureFlag)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFeatureFlags_Validate(t *testing.T) {
+	assert.NoError(t, FeatureFlags{FeatureQuestions: {Rollout: 50}}.Validate())
+	assert.ErrorIs(t, FeatureFlags{"telepathy": {}}.Validate(), ErrInvalidFeatureFlag)
+	assert.ErrorIs(t, FeatureFlags{FeatureApprovals: {Rollout: 200}}.Validate(), ErrInvalidFeatureFlag)
+}
+
+func TestFeatureFlags_Enabled(t *testing.T) {
+	billing := MustNewProject("Billing", "", []string{"Ship invoicing"})
+	search := MustNewProject("Search", "", []string{"Ship search"})
+
+	tests := []struct {
+		name    string
+		flags   FeatureFlags
+		project *Project
+		want    bool
+	}{
+		{name: "no flag", flags: FeatureFlags{}, project: billing, want: true},
+		{name: "no flag outside projects", flags: nil, want: true},
+		{name: "enabled for the workspace", flags: FeatureFlags{FeatureQuestions: {Enabled: true}}, project: billing, want: true},
+		{name: "off", flags: FeatureFlags{FeatureQuestions: {}}, project: billing},
+		{name: "listed by name", flags: FeatureFlags{FeatureQuestions: {Projects: []string{"billing"}}}, project: billing, want: true},
+		{name: "listed by ID", flags: FeatureFlags{FeatureQuestions: {Projects: []string{billing.ID().String()}}}, project: billing, want: true},
+		{name: "other project listed", flags: FeatureFlags{FeatureQuestions: {Projects: []string{"Billing"}}}, project: search},
+		{name: "listed outside projects", flags: FeatureFlags{FeatureQuestions: {Projects: []string{"Billing"}}}},
+		{name: "full rollout", flags: FeatureFlags{FeatureQuestions: {Rollout: 100}}, project: search, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.flags.Enabled(FeatureQuestions, tt.project))
+		})
+	}
+}
+
+func TestFeatureFlag_RolloutIsStable(t *testing.T) {
+	projects := make([]*Project, 200)
+	for i := range projects {
+		projects[i] = MustNewProject(fmt.Sprintf("Project %d", i), "", []string{"Ship it"})
+	}
+
+	enabled := func(rollout int) map[string]bool {
+		on := make(map[string]bool)
+		for _, project := range projects {
+			if (FeatureFlag{Rollout: rollout}).EnabledFor(FeatureApprovals, project) {
+				on[project.ID().String()] = true
+			}
+		}
+		return on
+	}
+
+	quarter := enabled(25)
+	half := enabled(50)
+	assert.InDelta(t, 50, len(quarter), 25)
+	assert.Greater(t, len(half), len(quarter))
+	for id := range quarter {
+		assert.True(t, half[id], "raising the rollout keeps the projects that had the feature")
+	}
+	assert.Equal(t, quarter, enabled(25))
+}
+
+func TestFeatureFlags_Merge(t *testing.T) {
+	flags := FeatureFlags{FeatureQuestions: {}, FeatureApprovals: {Enabled: true}}
+	merged := flags.Merge(FeatureFlags{FeatureQuestions: {Enabled: true}})
+
+	assert.True(t, merged.Enabled(FeatureQuestions, nil))
+	assert.True(t, merged.Enabled(FeatureApprovals, nil))
+	assert.False(t, flags.Enabled(FeatureQuestions, nil))
+}
@@ -0,0 +1,36 @@
+package domain
+
+// FieldCipher encrypts the fields of persisted records holding chat content and user identifiers.
+// Repositories given one store those fields encrypted, so a database dump does not reveal them.
+type FieldCipher interface {
+	// Encrypt returns the value encrypted with the current key
+	Encrypt(plaintext string) (string, error)
+
+	// Decrypt returns the plaintext of a value encrypted with any known key. Values that were stored before
+	// encryption was turned on are returned unchanged.
+	Decrypt(value string) (string, error)
+}
+
+// Encrypted returns a copy of the message with its content and sender encrypted
+func (dto MessageDTO) Encrypted(cipher FieldCipher) (MessageDTO, error) {
+	return dto.transform(cipher.Encrypt)
+}
+
+// Decrypted returns a copy of the message with its content and sender decrypted
+func (dto MessageDTO) Decrypted(cipher FieldCipher) (MessageDTO, error) {
+	return dto.transform(cipher.Decrypt)
+}
+
+func (dto MessageDTO) transform(apply func(string) (string, error)) (MessageDTO, error) {
+	content, err := apply(dto.Content)
+	if err != nil {
+		return MessageDTO{}, err
+	}
+	sender, err := apply(dto.Sender)
+	if err != nil {
+		return MessageDTO{}, err
+	}
+	dto.Content = content
+	dto.Sender = sender
+	return dto, nil
+}
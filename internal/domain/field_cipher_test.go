@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTampered = errors.New("tampered")
+
+// reversingCipher stands in for a real cipher, reversing values behind a prefix
+type reversingCipher struct{}
+
+func (reversingCipher) Encrypt(plaintext string) (string, error) {
+	return "enc:" + reverse(plaintext), nil
+}
+
+func (reversingCipher) Decrypt(value string) (string, error) {
+	sealed, ok := strings.CutPrefix(value, "enc:")
+	if !ok {
+		return "", errTampered
+	}
+	return reverse(sealed), nil
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func TestMessageDTO_Encrypted(t *testing.T) {
+	dto := MessageDTO{ID: "01HPZ", ChannelID: "C0001", Sender: "U0001", Content: "We will use Postgres", Type: "decision"}
+
+	encrypted, err := dto.Encrypted(reversingCipher{})
+	require.NoError(t, err)
+	assert.Equal(t, "enc:1000U", encrypted.Sender)
+	assert.NotContains(t, encrypted.Content, "Postgres")
+	assert.Equal(t, "C0001", encrypted.ChannelID)
+	assert.Equal(t, "U0001", dto.Sender, "the original is left unchanged")
+
+	decrypted, err := encrypted.Decrypted(reversingCipher{})
+	require.NoError(t, err)
+	assert.Equal(t, dto, decrypted)
+
+	_, err = dto.Decrypted(reversingCipher{})
+	assert.ErrorIs(t, err, errTampered)
+}
@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const frontMatterDelimiter = "---"
+
+var (
+	ErrInvalidFrontMatter = errors.New("invalid front matter")
+)
+
+// FrontMatter is the metadata block at the top of a Markdown document.
+// It supports the YAML subset the bot writes: scalar strings and flow-style string lists.
+type FrontMatter struct {
+	keys   []string
+	values map[string]string
+	lists  map[string][]string
+}
+
+// NewFrontMatter creates an empty FrontMatter
+func NewFrontMatter() *FrontMatter {
+	return &FrontMatter{
+		values: make(map[string]string),
+		lists:  make(map[string][]string),
+	}
+}
+
+// ParseFrontMatter splits a document into its front matter and body.
+// Documents without front matter return an empty FrontMatter and the whole content as body.
+func ParseFrontMatter(content string) (*FrontMatter, string, error) {
+	fm := NewFrontMatter()
+
+	lines := strings.Split(strings.TrimPrefix(content, "\ufeff"), "\n")
+	if strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return fm, content, nil
+	}
+
+	closing := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+			closing = i
+			break
+		}
+	}
+	if closing < 0 {
+		return nil, "", ErrInvalidFrontMatter
+	}
+
+	body := strings.TrimPrefix(strings.Join(lines[closing+1:], "\n"), "\n")
+
+	for _, line := range lines[1:closing] {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, "", ErrInvalidFrontMatter
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		if key == "" {
+			return nil, "", ErrInvalidFrontMatter
+		}
+
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			fm.SetList(key, parseFlowList(value[1:len(value)-1]))
+			continue
+		}
+		fm.Set(key, unquote(value))
+	}
+
+	return fm, body, nil
+}
+
+// Get returns a scalar value
+func (fm *FrontMatter) Get(key string) string {
+	return fm.values[key]
+}
+
+// GetList returns a list value
+func (fm *FrontMatter) GetList(key string) []string {
+	list := make([]string, len(fm.lists[key]))
+	copy(list, fm.lists[key])
+	return list
+}
+
+// Has checks if the key is present
+func (fm *FrontMatter) Has(key string) bool {
+	_, isValue := fm.values[key]
+	_, isList := fm.lists[key]
+	return isValue || isList
+}
+
+// Keys returns the keys in insertion order
+func (fm *FrontMatter) Keys() []string {
+	keys := make([]string, len(fm.keys))
+	copy(keys, fm.keys)
+	return keys
+}
+
+// Set sets a scalar value
+func (fm *FrontMatter) Set(key, value string) {
+	fm.remember(key)
+	delete(fm.lists, key)
+	fm.values[key] = value
+}
+
+// SetList sets a list value
+func (fm *FrontMatter) SetList(key string, values []string) {
+	fm.remember(key)
+	delete(fm.values, key)
+	list := make([]string, len(values))
+	copy(list, values)
+	fm.lists[key] = list
+}
+
+// Delete removes a key
+func (fm *FrontMatter) Delete(key string) {
+	delete(fm.values, key)
+	delete(fm.lists, key)
+	for i, k := range fm.keys {
+		if k == key {
+			fm.keys = append(fm.keys[:i], fm.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// IsEmpty checks if the front matter has no keys
+func (fm *FrontMatter) IsEmpty() bool {
+	return len(fm.keys) == 0
+}
+
+// Render returns the front matter block including delimiters
+func (fm *FrontMatter) Render() string {
+	var b strings.Builder
+	b.WriteString(frontMatterDelimiter + "\n")
+	for _, key := range fm.keys {
+		b.WriteString(key)
+		b.WriteString(": ")
+		if list, ok := fm.lists[key]; ok {
+			quoted := make([]string, len(list))
+			for i, item := range list {
+				quoted[i] = quote(item)
+			}
+			b.WriteString("[" + strings.Join(quoted, ", ") + "]")
+		} else {
+			b.WriteString(quote(fm.values[key]))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(frontMatterDelimiter + "\n")
+	return b.String()
+}
+
+// Apply returns the body prefixed with the front matter
+func (fm *FrontMatter) Apply(body string) string {
+	if fm.IsEmpty() {
+		return body
+	}
+	return fm.Render() + "\n" + strings.TrimLeft(body, "\n")
+}
+
+func (fm *FrontMatter) remember(key string) {
+	if !fm.Has(key) {
+		fm.keys = append(fm.keys, key)
+	}
+}
+
+// quote wraps values that YAML would otherwise misinterpret
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, ":#[]{},\"'\n") || strings.TrimSpace(value) != value {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"') {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+// parseFlowList parses the inside of a YAML flow sequence, honoring quoted items
+func parseFlowList(inner string) []string {
+	var items []string
+	var current strings.Builder
+	inQuotes := byte(0)
+	escaped := false
+
+	flush := func() {
+		item := strings.TrimSpace(current.String())
+		if item != "" {
+			items = append(items, unquote(item))
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes == '"' && c == '\\':
+			escaped = true
+		case inQuotes != 0 && c == inQuotes:
+			inQuotes = 0
+		case inQuotes == 0 && (c == '"' || c == '\''):
+			inQuotes = c
+		case inQuotes == 0 && c == ',':
+			flush()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	flush()
+
+	return items
+}
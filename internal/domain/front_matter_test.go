@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantKeys  []string
+		wantBody  string
+		wantError bool
+	}{
+		{
+			name:     "no front matter",
+			content:  "# Title\n\nBody",
+			wantKeys: []string{},
+			wantBody: "# Title\n\nBody",
+		},
+		{
+			name:     "scalar and list values",
+			content:  "---\ntype: idea\ntags: [postgres, \"data, model\"]\n---\n\n# Title\n",
+			wantKeys: []string{"type", "tags"},
+			wantBody: "# Title\n",
+		},
+		{
+			name:     "quoted values",
+			content:  "---\ntitle: \"Adopt: Postgres\"\nowner: 'jane'\n---\nBody",
+			wantKeys: []string{"title", "owner"},
+			wantBody: "Body",
+		},
+		{
+			name:      "unterminated front matter",
+			content:   "---\ntype: idea\n# Title",
+			wantError: true,
+		},
+		{
+			name:      "line without key",
+			content:   "---\njust text\n---\nBody",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := ParseFrontMatter(tt.content)
+			if tt.wantError {
+				if err != ErrInvalidFrontMatter {
+					t.Errorf("expected error %v, got %v", ErrInvalidFrontMatter, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(fm.Keys(), tt.wantKeys) {
+				t.Errorf("Keys() = %v, want %v", fm.Keys(), tt.wantKeys)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestFrontMatter_RoundTrip(t *testing.T) {
+	fm := NewFrontMatter()
+	fm.Set("type", "decision")
+	fm.Set("title", "Adopt: Postgres")
+	fm.SetList("tags", []string{"postgres", "data, model"})
+
+	content := fm.Apply("# Adopt Postgres\n")
+
+	parsed, body, err := ParseFrontMatter(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body != "# Adopt Postgres\n" {
+		t.Errorf("body = %q", body)
+	}
+	if parsed.Get("title") != "Adopt: Postgres" {
+		t.Errorf("Get(title) = %q", parsed.Get("title"))
+	}
+	if !reflect.DeepEqual(parsed.GetList("tags"), []string{"postgres", "data, model"}) {
+		t.Errorf("GetList(tags) = %v", parsed.GetList("tags"))
+	}
+	if !reflect.DeepEqual(parsed.Keys(), []string{"type", "title", "tags"}) {
+		t.Errorf("Keys() = %v", parsed.Keys())
+	}
+}
+
+func TestFrontMatter_Delete(t *testing.T) {
+	fm := NewFrontMatter()
+	fm.Set("type", "idea")
+	fm.SetList("tags", []string{"a"})
+
+	fm.Delete("type")
+
+	if fm.Has("type") {
+		t.Error("expected key to be deleted")
+	}
+	if !reflect.DeepEqual(fm.Keys(), []string{"tags"}) {
+		t.Errorf("Keys() = %v", fm.Keys())
+	}
+}
@@ -0,0 +1,306 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GlossaryFile defines the domain terms used across the documentation
+const GlossaryFile = docsRoot + "/GLOSSARY.md"
+
+var (
+	ErrInvalidGlossaryEntry = errors.New("invalid glossary entry")
+
+	// acronymPattern matches acronyms like SLA, K8S or OKRs, the plural is dropped
+	acronymPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9]*[A-Z][A-Z0-9]*)s?\b`)
+	// expansionPattern matches an acronym introduced with its expansion, like "Service Level Agreement (SLA)"
+	expansionPattern = regexp.MustCompile(`((?:[A-Z][\w-]*\s+){1,5}[A-Z][\w-]*)\s+\(([A-Z][A-Z0-9]*[A-Z][A-Z0-9]*)\)`)
+	// protectedSpanPattern matches the parts of a line terms must not be linked in: code, links and URLs
+	protectedSpanPattern = regexp.MustCompile("`[^`]*`|!?\\[[^\\]]*\\]\\([^)]*\\)|<[^>]+>|https?://\\S+")
+	// glossaryLinkPattern matches the document links of a glossary entry
+	glossaryLinkPattern = regexp.MustCompile(`\]\(([^)]+)\)`)
+)
+
+// chatAcronyms are acronyms of chat jargon and words written in capitals for emphasis, not terms of the team's domain
+var chatAcronyms = map[string]bool{
+	"AFAIK": true, "AM": true, "ASAP": true, "BTW": true, "EOD": true, "EOW": true, "ETA": true, "FYI": true,
+	"IMO": true, "IMHO": true, "LGTM": true, "NB": true, "OK": true, "OOO": true, "PM": true, "PS": true,
+	"TBD": true, "TL": true, "DR": true, "TLDR": true, "TODO": true, "WIP": true,
+	"ALL": true, "AND": true, "DO": true, "DONE": true, "MUST": true, "NEW": true, "NO": true, "NOT": true,
+	"NOTE": true, "OR": true, "THE": true, "YES": true,
+}
+
+const (
+	glossaryUsedIn   = "Used in:"
+	glossaryMaxLinks = 10
+)
+
+// DetectedTerm is a domain term found in a message, with the expansion the message gave for it if any
+type DetectedTerm struct {
+	Term      string
+	Expansion string
+}
+
+// DetectTerms finds the acronyms used in a text, in the order they first appear. Acronyms of chat jargon
+// like FYI or ASAP are skipped.
+func DetectTerms(text string) []DetectedTerm {
+	expansions := make(map[string]string)
+	for _, match := range expansionPattern.FindAllStringSubmatch(text, -1) {
+		expansions[match[2]] = strings.Join(strings.Fields(match[1]), " ")
+	}
+
+	seen := make(map[string]bool)
+	var terms []DetectedTerm
+	for _, match := range acronymPattern.FindAllStringSubmatch(text, -1) {
+		term := match[1]
+		if len(term) > 6 || chatAcronyms[term] || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, DetectedTerm{Term: term, Expansion: expansions[term]})
+	}
+	return terms
+}
+
+// GlossaryEntry is a term of the glossary with its definition and the documents using it
+type GlossaryEntry struct {
+	term       string
+	definition string
+	documents  []string
+}
+
+// NewGlossaryEntry creates a GlossaryEntry. The definition is kept on a single line.
+func NewGlossaryEntry(term, definition string) (*GlossaryEntry, error) {
+	term = strings.TrimSpace(term)
+	definition = strings.Join(strings.Fields(definition), " ")
+	if term == "" || strings.ContainsAny(term, "\n#[]") {
+		return nil, fmt.Errorf("%w: invalid term %q", ErrInvalidGlossaryEntry, term)
+	}
+	if definition == "" {
+		return nil, fmt.Errorf("%w: %s has no definition", ErrInvalidGlossaryEntry, term)
+	}
+	return &GlossaryEntry{term: term, definition: definition}, nil
+}
+
+// Term returns the term
+func (e *GlossaryEntry) Term() string {
+	return e.term
+}
+
+// Definition returns the definition of the term
+func (e *GlossaryEntry) Definition() string {
+	return e.definition
+}
+
+// Documents returns the paths of the documents using the term, newest first
+func (e *GlossaryEntry) Documents() []string {
+	return append([]string(nil), e.documents...)
+}
+
+// AddDocument records a document using the term, only the newest documents are kept
+func (e *GlossaryEntry) AddDocument(docPath string) bool {
+	for _, existing := range e.documents {
+		if existing == docPath {
+			return false
+		}
+	}
+	e.documents = append([]string{docPath}, e.documents...)
+	if len(e.documents) > glossaryMaxLinks {
+		e.documents = e.documents[:glossaryMaxLinks]
+	}
+	return true
+}
+
+// Anchor returns the heading anchor of the entry in the glossary, as GitHub renders it
+func (e *GlossaryEntry) Anchor() string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(e.term) {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteRune('-')
+		case r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r >= 0x80:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// pattern matches the term as a word, with an optional plural. Acronyms are matched in capitals only,
+// other terms regardless of case.
+func (e *GlossaryEntry) pattern() *regexp.Regexp {
+	pattern := `\b` + regexp.QuoteMeta(e.term) + `s?\b`
+	if e.term != strings.ToUpper(e.term) {
+		pattern = `(?i)` + pattern
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// Glossary is the set of domain terms of a documentation repository
+type Glossary struct {
+	entries map[string]*GlossaryEntry
+}
+
+// NewGlossary creates an empty Glossary
+func NewGlossary() *Glossary {
+	return &Glossary{entries: make(map[string]*GlossaryEntry)}
+}
+
+// ParseGlossary reads a glossary rendered by Render. Definitions edited by hand are kept, entries
+// without a definition are skipped.
+func ParseGlossary(content string) *Glossary {
+	g := NewGlossary()
+
+	var term string
+	var definition []string
+	var documents []string
+	flush := func() {
+		entry, err := NewGlossaryEntry(term, strings.Join(definition, " "))
+		if err == nil {
+			for i := len(documents) - 1; i >= 0; i-- {
+				entry.AddDocument(documents[i])
+			}
+			g.Add(entry)
+		}
+		term, definition, documents = "", nil, nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			flush()
+			term = strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))
+		case term == "" || trimmed == "":
+		case strings.HasPrefix(trimmed, glossaryUsedIn):
+			for _, match := range glossaryLinkPattern.FindAllStringSubmatch(trimmed, -1) {
+				documents = append(documents, path.Join(path.Dir(GlossaryFile), match[1]))
+			}
+		default:
+			definition = append(definition, trimmed)
+		}
+	}
+	flush()
+	return g
+}
+
+// Entry returns the entry of a term, terms are matched regardless of case
+func (g *Glossary) Entry(term string) (*GlossaryEntry, bool) {
+	entry, ok := g.entries[strings.ToLower(strings.TrimSpace(term))]
+	return entry, ok
+}
+
+// Add adds an entry, replacing the entry of the same term
+func (g *Glossary) Add(entry *GlossaryEntry) {
+	g.entries[strings.ToLower(entry.term)] = entry
+}
+
+// Entries returns the entries sorted by term
+func (g *Glossary) Entries() []*GlossaryEntry {
+	entries := make([]*GlossaryEntry, 0, len(g.entries))
+	for _, entry := range g.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].term) < strings.ToLower(entries[j].term)
+	})
+	return entries
+}
+
+// Mentions returns the entries of the terms a text uses
+func (g *Glossary) Mentions(text string) []*GlossaryEntry {
+	var mentioned []*GlossaryEntry
+	for _, entry := range g.Entries() {
+		if entry.pattern().MatchString(text) {
+			mentioned = append(mentioned, entry)
+		}
+	}
+	return mentioned
+}
+
+// Render renders the GLOSSARY.md, one section per term with the documents using it
+func (g *Glossary) Render() string {
+	var b strings.Builder
+	b.WriteString("# Glossary\n\n")
+	b.WriteString("_This glossary is maintained by Quill. Definitions edited by hand are kept._\n")
+
+	for _, entry := range g.Entries() {
+		b.WriteString(fmt.Sprintf("\n## %s\n\n%s\n", entry.term, entry.definition))
+		if len(entry.documents) == 0 {
+			continue
+		}
+		links := make([]string, 0, len(entry.documents))
+		for _, doc := range entry.documents {
+			links = append(links, fmt.Sprintf("[%s](%s)", path.Base(doc), relativeLink(path.Dir(GlossaryFile), doc)))
+		}
+		b.WriteString(fmt.Sprintf("\n%s %s\n", glossaryUsedIn, strings.Join(links, ", ")))
+	}
+	return b.String()
+}
+
+// LinkTerms links the first occurrence of each glossary term in a Markdown document to its glossary entry.
+// Headings, code, existing links and URLs are left alone.
+func LinkTerms(markdown, docPath string, g *Glossary) string {
+	if g == nil || len(g.entries) == 0 {
+		return markdown
+	}
+
+	patterns := make(map[string]*regexp.Regexp, len(g.entries))
+	for key, entry := range g.entries {
+		patterns[key] = entry.pattern()
+	}
+	glossaryLink, err := filepath.Rel(filepath.Dir(docPath), GlossaryFile)
+	if err != nil {
+		glossaryLink = "/" + GlossaryFile
+	}
+	glossaryLink = filepath.ToSlash(glossaryLink)
+
+	lines := strings.Split(markdown, "\n")
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		for _, entry := range g.Entries() {
+			key := strings.ToLower(entry.term)
+			pattern, ok := patterns[key]
+			if !ok {
+				continue
+			}
+			linked, found := linkFirst(lines[i], pattern, fmt.Sprintf("%s#%s", glossaryLink, entry.Anchor()))
+			if found {
+				lines[i] = linked
+				delete(patterns, key)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkFirst links the first match of the pattern outside of the protected spans of a line
+func linkFirst(line string, pattern *regexp.Regexp, target string) (string, bool) {
+	protected := protectedSpanPattern.FindAllStringIndex(line, -1)
+	for _, match := range pattern.FindAllStringIndex(line, -1) {
+		inside := false
+		for _, span := range protected {
+			if match[0] < span[1] && match[1] > span[0] {
+				inside = true
+				break
+			}
+		}
+		if inside {
+			continue
+		}
+		return line[:match[0]] + "[" + line[match[0]:match[1]] + "](" + target + ")" + line[match[1]:], true
+	}
+	return line, false
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []DetectedTerm
+	}{
+		{
+			name: "acronyms in order",
+			text: "The SLA of the API is 99.9%, the API gateway reports it",
+			want: []DetectedTerm{{Term: "SLA"}, {Term: "API"}},
+		},
+		{
+			name: "expansion",
+			text: "We track Monthly Recurring Revenue (MRR) and our OKRs",
+			want: []DetectedTerm{{Term: "MRR", Expansion: "Monthly Recurring Revenue"}, {Term: "OKR"}},
+		},
+		{
+			name: "chat jargon and emphasis",
+			text: "FYI this is NOT done, ETA is EOD, LGTM otherwise",
+		},
+		{
+			name: "long capitals and single letters",
+			text: "A RELEASE is planned for Q3 on K8S",
+			want: []DetectedTerm{{Term: "K8S"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectTerms(tt.text))
+		})
+	}
+}
+
+func TestNewGlossaryEntry(t *testing.T) {
+	entry, err := NewGlossaryEntry(" SLA ", "Service level agreement,\n the uptime promised to customers.")
+	require.NoError(t, err)
+	assert.Equal(t, "SLA", entry.Term())
+	assert.Equal(t, "Service level agreement, the uptime promised to customers.", entry.Definition())
+
+	_, err = NewGlossaryEntry("", "Nothing")
+	assert.ErrorIs(t, err, ErrInvalidGlossaryEntry)
+	_, err = NewGlossaryEntry("## SLA", "Heading")
+	assert.ErrorIs(t, err, ErrInvalidGlossaryEntry)
+	_, err = NewGlossaryEntry("SLA", "  ")
+	assert.ErrorIs(t, err, ErrInvalidGlossaryEntry)
+}
+
+func TestGlossaryEntry_Anchor(t *testing.T) {
+	for term, want := range map[string]string{
+		"SLA":            "sla",
+		"Event Sourcing": "event-sourcing",
+		"CI/CD":          "cicd",
+		"snake_case":     "snake_case",
+	} {
+		entry, err := NewGlossaryEntry(term, "A definition")
+		require.NoError(t, err)
+		assert.Equal(t, want, entry.Anchor(), term)
+	}
+}
+
+func TestGlossaryEntry_AddDocument(t *testing.T) {
+	entry, err := NewGlossaryEntry("SLA", "Service level agreement")
+	require.NoError(t, err)
+
+	assert.True(t, entry.AddDocument("docs/operations/a.md"))
+	assert.False(t, entry.AddDocument("docs/operations/a.md"))
+	for i := 0; i < glossaryMaxLinks; i++ {
+		entry.AddDocument("docs/operations/" + string(rune('b'+i)) + ".md")
+	}
+	assert.Len(t, entry.Documents(), glossaryMaxLinks)
+	assert.Equal(t, "docs/operations/k.md", entry.Documents()[0])
+	assert.NotContains(t, entry.Documents(), "docs/operations/a.md")
+}
+
+func TestGlossary_RenderAndParse(t *testing.T) {
+	g := NewGlossary()
+	sla, _ := NewGlossaryEntry("SLA", "Service level agreement, the uptime promised to customers.")
+	sla.AddDocument("docs/operations/uptime.md")
+	sla.AddDocument("projects/billing/operations/invoices.md")
+	api, _ := NewGlossaryEntry("API", "The public HTTP interface.")
+	g.Add(sla)
+	g.Add(api)
+
+	rendered := g.Render()
+	assert.Equal(t, "# Glossary\n\n"+
+		"_This glossary is maintained by Quill. Definitions edited by hand are kept._\n"+
+		"\n## API\n\nThe public HTTP interface.\n"+
+		"\n## SLA\n\nService level agreement, the uptime promised to customers.\n"+
+		"\nUsed in: [invoices.md](../projects/billing/operations/invoices.md), [uptime.md](operations/uptime.md)\n", rendered)
+
+	edited := rendered + "\n## Churn\n\nCustomers cancelling,\nper month.\n\n## Draft\n"
+	parsed := ParseGlossary(edited)
+	require.Len(t, parsed.Entries(), 3)
+	entry, ok := parsed.Entry("sla")
+	require.True(t, ok)
+	assert.Equal(t, sla.Definition(), entry.Definition())
+	assert.Equal(t, sla.Documents(), entry.Documents())
+	churn, ok := parsed.Entry("Churn")
+	require.True(t, ok)
+	assert.Equal(t, "Customers cancelling, per month.", churn.Definition())
+	_, ok = parsed.Entry("Draft")
+	assert.False(t, ok)
+}
+
+func TestLinkTerms(t *testing.T) {
+	g := NewGlossary()
+	for term, definition := range map[string]string{"SLA": "Service level agreement", "Churn": "Customers cancelling"} {
+		entry, err := NewGlossaryEntry(term, definition)
+		require.NoError(t, err)
+		g.Add(entry)
+	}
+
+	doc := "# SLA for billing\n\n" +
+		"See `SLA` and [the SLA](https://example.com/SLA).\n" +
+		"```\nSLA = 99.9\n```\n" +
+		"Our SLAs cap churn, the SLA is reviewed monthly.\n" +
+		"Churn is reported weekly."
+
+	got := LinkTerms(doc, "docs/operations/uptime.md", g)
+	assert.Equal(t, "# SLA for billing\n\n"+
+		"See `SLA` and [the SLA](https://example.com/SLA).\n"+
+		"```\nSLA = 99.9\n```\n"+
+		"Our [SLAs](../GLOSSARY.md#sla) cap [churn](../GLOSSARY.md#churn), the SLA is reviewed monthly.\n"+
+		"Churn is reported weekly.", got)
+
+	assert.Equal(t, doc, LinkTerms(doc, "docs/operations/uptime.md", NewGlossary()))
+	assert.Equal(t, doc, LinkTerms(doc, "docs/operations/uptime.md", nil))
+}
+
+func TestGlossary_Mentions(t *testing.T) {
+	g := NewGlossary()
+	for term, definition := range map[string]string{"SLA": "Service level agreement", "Churn": "Customers cancelling", "API": "Public interface"} {
+		entry, err := NewGlossaryEntry(term, definition)
+		require.NoError(t, err)
+		g.Add(entry)
+	}
+
+	var terms []string
+	for _, entry := range g.Mentions("Monthly churn breaks our SLAs, the rapid fix is to sla-proof it") {
+		terms = append(terms, entry.Term())
+	}
+	assert.Equal(t, []string{"Churn", "SLA"}, terms)
+}
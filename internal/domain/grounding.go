@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMinGrounding is the share of a generated document's claims that must be found in its source for the
+// document to be committed without review
+const DefaultMinGrounding = 0.5
+
+const (
+	// minClaimTokens is how many meaningful words a sentence needs to be checked as a claim, shorter ones
+	// are headings of lists and connecting phrases. Sentences with numbers are always checked.
+	minClaimTokens = 3
+	// minClaimSupport is the share of a claim's words that must appear in the source for it to be supported
+	minClaimSupport = 0.5
+	// maxReviewClaims is how many unsupported claims a review request quotes
+	maxReviewClaims = 5
+)
+
+var (
+	// numberPattern matches the figures of a claim, like 3, 99.9% or 1,000
+	numberPattern = regexp.MustCompile(`\d+(?:[.,]\d+)*%?`)
+	// sentenceEndPattern splits paragraphs into sentences
+	sentenceEndPattern = regexp.MustCompile(`[.!?]+(?:\s+|$)`)
+	// listMarkerPattern matches the markers of list items, like "- ", "* " and "1. "
+	listMarkerPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+)
+
+// fillerWords say nothing about what was discussed, the model adds them freely when writing prose
+var fillerWords = map[string]bool{
+	"is": true, "are": true, "was": true, "were": true, "be": true, "been": true, "will": true, "would": true,
+	"should": true, "can": true, "could": true, "this": true, "that": true, "these": true, "those": true,
+	"it": true, "its": true, "we": true, "our": true, "they": true, "their": true, "as": true, "by": true,
+	"at": true, "not": true, "no": true, "has": true, "have": true, "had": true, "which": true, "also": true,
+	"so": true, "all": true, "more": true, "any": true, "into": true, "than": true, "then": true, "there": true,
+	"when": true, "what": true, "who": true, "document": true, "documentation": true,
+}
+
+// GroundingReport tells how much of a generated document is supported by the messages it was written from
+type GroundingReport struct {
+	// Claims counts the sentences of the document that were checked
+	Claims int
+	// Unsupported are the claims the source does not back, in the order of the document
+	Unsupported []string
+}
+
+// Score returns the share of the claims the source supports, 1 for documents without claims
+func (r GroundingReport) Score() float64 {
+	if r.Claims == 0 {
+		return 1
+	}
+	return float64(r.Claims-len(r.Unsupported)) / float64(r.Claims)
+}
+
+// CheckGrounding compares the claims of a generated Markdown document with the sources it was written from.
+// A claim is supported when the sources contain every figure it quotes and at least half of its words,
+// as the model rewords what it was told but should not invent numbers, names or facts. Headings, code
+// blocks and front matter are not checked.
+func CheckGrounding(document string, sources ...string) GroundingReport {
+	source := strings.Join(sources, "\n")
+	words := tokenSet(Tokenize(source))
+	figures := tokenSet(numberPattern.FindAllString(source, -1))
+
+	_, body, err := ParseFrontMatter(document)
+	if err != nil {
+		body = document
+	}
+
+	var report GroundingReport
+	fence := ""
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" || isFence(trimmed) {
+			fence = toggleFence(fence, trimmed)
+			continue
+		}
+		if trimmed == "" || headingPattern.MatchString(trimmed) || strings.HasPrefix(trimmed, "|") {
+			continue
+		}
+		trimmed = listMarkerPattern.ReplaceAllString(strings.TrimLeft(trimmed, "> "), "")
+
+		for _, sentence := range sentenceEndPattern.Split(trimmed, -1) {
+			sentence = strings.TrimSpace(sentence)
+			checked, supported := checkClaim(sentence, words, figures)
+			if !checked {
+				continue
+			}
+			report.Claims++
+			if !supported {
+				report.Unsupported = append(report.Unsupported, sentence)
+			}
+		}
+	}
+	return report
+}
+
+// checkClaim checks a sentence against the words and figures of the source. It reports false for sentences
+// too short to be a claim.
+func checkClaim(sentence string, words, figures map[string]bool) (checked bool, supported bool) {
+	claimed := numberPattern.FindAllString(sentence, -1)
+	var tokens []string
+	for _, token := range Tokenize(numberPattern.ReplaceAllString(sentence, " ")) {
+		if len(token) > 1 && !fillerWords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(claimed) == 0 && len(tokens) < minClaimTokens {
+		return false, false
+	}
+
+	for _, figure := range claimed {
+		if !figures[figure] {
+			return true, false
+		}
+	}
+	if len(tokens) == 0 {
+		return true, true
+	}
+	found := 0
+	for _, token := range tokens {
+		if sourceHasWord(words, token) {
+			found++
+		}
+	}
+	return true, float64(found)/float64(len(tokens)) >= minClaimSupport
+}
+
+// sourceHasWord checks if the source has a word, or another form of it sharing the first five letters,
+// like "decided" and "decides"
+func sourceHasWord(words map[string]bool, word string) bool {
+	if words[word] {
+		return true
+	}
+	const stem = 5
+	if len(word) < stem {
+		return false
+	}
+	for candidate := range words {
+		if len(candidate) >= stem && candidate[:stem] == word[:stem] {
+			return true
+		}
+	}
+	return false
+}
+
+// FlagUngrounded marks a document whose claims the source does not support as waiting for people to
+// reconfirm or supersede it, noting its grounding score and the unsupported claims in its front matter
+func FlagUngrounded(fm *FrontMatter, report GroundingReport, at time.Time) {
+	FlagForReview(fm, at)
+	fm.Set("grounding", strconv.FormatFloat(report.Score(), 'f', 2, 64))
+	fm.SetList("unsupported_claims", report.Unsupported)
+}
+
+// RenderGroundingReview returns the message asking people to check the unsupported claims of a document
+func RenderGroundingReview(path string, report GroundingReport) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(
+		"🔍 The document `%s` says things I could not find in the discussion (%.0f%% of its claims are supported). "+
+			"Check them, then reconfirm the document or mark it superseded:\n",
+		path, report.Score()*100,
+	))
+	for i, claim := range report.Unsupported {
+		if i == maxReviewClaims {
+			b.WriteString(fmt.Sprintf("…and %d more\n", len(report.Unsupported)-maxReviewClaims))
+			break
+		}
+		b.WriteString(fmt.Sprintf("• %s\n", claim))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckGrounding(t *testing.T) {
+	source := "We decided to move billing to Postgres, invoices outgrew MySQL. Migration takes 3 weeks and starts in March."
+
+	tests := []struct {
+		name        string
+		document    string
+		claims      int
+		unsupported []string
+	}{
+		{
+			name:     "supported claims",
+			document: "# Adopt Postgres\n\nThe team decided billing moves to Postgres.\n\n## Context\n\n- Invoices outgrew MySQL.\n- The migration takes 3 weeks.",
+			claims:   3,
+		},
+		{
+			name:        "invented figures",
+			document:    "# Adopt Postgres\n\nThe migration takes 6 weeks.",
+			claims:      1,
+			unsupported: []string{"The migration takes 6 weeks"},
+		},
+		{
+			name:        "invented facts",
+			document:    "# Adopt Postgres\n\nBilling moves to Postgres. Kubernetes operators manage replicated clusters across regions.",
+			claims:      2,
+			unsupported: []string{"Kubernetes operators manage replicated clusters across regions"},
+		},
+		{
+			name:     "headings, code and front matter are not claims",
+			document: "---\ntype: decision\n---\n\n# Deploy Kubernetes operators everywhere\n\n```sql\nCREATE TABLE invoices (id serial);\n```",
+		},
+		{
+			name:     "short phrases are not claims",
+			document: "# Adopt Postgres\n\nSee below.\n\n## Next steps",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckGrounding(tt.document, source)
+			if report.Claims != tt.claims {
+				t.Errorf("CheckGrounding() claims = %d, want %d", report.Claims, tt.claims)
+			}
+			if strings.Join(report.Unsupported, "|") != strings.Join(tt.unsupported, "|") {
+				t.Errorf("CheckGrounding() unsupported = %q, want %q", report.Unsupported, tt.unsupported)
+			}
+		})
+	}
+}
+
+func TestGroundingReport_Score(t *testing.T) {
+	if got := (GroundingReport{}).Score(); got != 1 {
+		t.Errorf("Score() = %v, want 1 for documents without claims", got)
+	}
+	if got := (GroundingReport{Claims: 4, Unsupported: []string{"a"}}).Score(); got != 0.75 {
+		t.Errorf("Score() = %v, want 0.75", got)
+	}
+}
+
+func TestFlagUngrounded(t *testing.T) {
+	fm := NewFrontMatter()
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	FlagUngrounded(fm, GroundingReport{Claims: 3, Unsupported: []string{"The migration takes 6 weeks", "Costs drop, by half"}}, at)
+
+	if DocumentStatusOf(fm) != DocumentStatusNeedsReview {
+		t.Errorf("status = %q, want %q", DocumentStatusOf(fm), DocumentStatusNeedsReview)
+	}
+	if got := fm.Get("grounding"); got != "0.33" {
+		t.Errorf("grounding = %q, want 0.33", got)
+	}
+
+	parsed, _, err := ParseFrontMatter(fm.Apply("# Adopt Postgres"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := parsed.GetList("unsupported_claims"); len(got) != 2 || got[1] != "Costs drop, by half" {
+		t.Errorf("unsupported_claims = %q", got)
+	}
+}
+
+func TestRenderGroundingReview(t *testing.T) {
+	report := GroundingReport{Claims: 8, Unsupported: []string{"a", "b", "c", "d", "e", "f", "g"}}
+
+	got := RenderGroundingReview("docs/development/adopt-postgres.md", report)
+
+	if !strings.HasPrefix(got, "🔍 The document `docs/development/adopt-postgres.md` says things I could not find in the discussion (12% of its claims are supported).") {
+		t.Errorf("RenderGroundingReview() = %q", got)
+	}
+	if !strings.HasSuffix(got, "• e\n…and 2 more") {
+		t.Errorf("RenderGroundingReview() = %q, want the first five claims", got)
+	}
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHomeViewer = errors.New("home viewer must have a name")
+
+// ApprovalKind tells what waits for someone's approval
+type ApprovalKind string
+
+const (
+	// ApprovalModeration is a message moderation held until people approve or reject it
+	ApprovalModeration ApprovalKind = "moderation"
+	// ApprovalUpdate is a document update waiting for someone to apply its diff in the thread
+	ApprovalUpdate ApprovalKind = "update"
+	// ApprovalDecision is a decision an approval policy holds until enough people approve it in the thread
+	ApprovalDecision ApprovalKind = "decision"
+)
+
+// HomeViewer is the person a home page is shown to, with the channels they are a member of
+type HomeViewer struct {
+	// Name is the name the person's messages are sent by
+	Name     string
+	Channels []string
+}
+
+// NewHomeViewer creates a HomeViewer, channels are deduplicated
+func NewHomeViewer(name string, channels []string) (HomeViewer, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return HomeViewer{}, ErrInvalidHomeViewer
+	}
+
+	seen := make(map[string]bool)
+	var unique []string
+	for _, channel := range channels {
+		channel = strings.TrimSpace(channel)
+		if channel != "" && !seen[channel] {
+			seen[channel] = true
+			unique = append(unique, channel)
+		}
+	}
+	return HomeViewer{Name: name, Channels: unique}, nil
+}
+
+// InChannel checks if the viewer is a member of the channel
+func (v HomeViewer) InChannel(channelID string) bool {
+	for _, channel := range v.Channels {
+		if channel == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// HomeCapture is a document generated from one of the viewer's messages
+type HomeCapture struct {
+	Title      string
+	Link       string
+	Type       MessageType
+	Category   Category
+	CapturedAt time.Time
+}
+
+// PendingApproval is something waiting in one of the viewer's channels for someone to approve it
+type PendingApproval struct {
+	Kind      ApprovalKind
+	MessageID string
+	ChannelID string
+	// Subject is the text of a held message, or the link of the document an update changes
+	Subject string
+	// Reason is why moderation held the message, or the approvals a decision still needs
+	Reason string
+	Since  time.Time
+}
+
+// HomeReview is a document the viewer owns that waits for them to reconfirm or supersede it
+type HomeReview struct {
+	Title    string
+	Link     string
+	Category Category
+}
+
+// HomeProject is a project bound to some of the viewer's channels
+type HomeProject struct {
+	Name   string
+	Status ProjectStatus
+	// Channels are the project's channels the viewer is a member of
+	Channels []string
+	// Repository is the owner/name of the repository the project documents into, empty for the default one
+	Repository string
+}
+
+// Home is what a person sees on their home page in chat: their latest captures, the documents they own pending
+// review, what waits for approval in their channels and the projects their channels are bound to
+type Home struct {
+	Captures  []HomeCapture
+	Reviews   []HomeReview
+	Approvals []PendingApproval
+	Projects  []HomeProject
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHomeViewer(t *testing.T) {
+	viewer, err := NewHomeViewer(" alice ", []string{"C0001", "", "C0002", "C0001"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "alice", viewer.Name)
+	assert.Equal(t, []string{"C0001", "C0002"}, viewer.Channels)
+	assert.True(t, viewer.InChannel("C0002"))
+	assert.False(t, viewer.InChannel("C0003"))
+
+	_, err = NewHomeViewer(" ", nil)
+	assert.ErrorIs(t, err, ErrInvalidHomeViewer)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// idempotencyKeyLength is how many hex characters of the digest an idempotency key keeps
+const idempotencyKeyLength = 32
+
+// IdempotencyKey derives the key of the document generated from a message. It is the same for every delivery
+// of the message, so a retried or replayed message is recognised as one already documented, and changes with
+// the prompt version so a message analysed with a newer prompt is documented again.
+func IdempotencyKey(threadID common.ID, messageTS, promptVersion string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{threadID.String(), messageTS, promptVersion}, "\x00")))
+	return hex.EncodeToString(sum[:])[:idempotencyKeyLength]
+}
+
+// MessageIdempotencyKey derives the idempotency key of a message from its thread, its chat timestamp and the
+// prompt version it was analysed with. Messages without a chat timestamp are keyed by their ID.
+func MessageIdempotencyKey(msg *Message) string {
+	messageTS := msg.SourceTimestamp()
+	if messageTS == "" {
+		messageTS = msg.ID().String()
+	}
+	return IdempotencyKey(msg.ThreadID(), messageTS, msg.PromptVersion())
+}
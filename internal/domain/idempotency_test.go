@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	thread := common.GenerateID()
+	key := IdempotencyKey(thread, "1717243200.000100", "v1")
+
+	assert.Len(t, key, idempotencyKeyLength)
+	assert.Equal(t, key, IdempotencyKey(thread, "1717243200.000100", "v1"))
+	assert.NotEqual(t, key, IdempotencyKey(common.GenerateID(), "1717243200.000100", "v1"))
+	assert.NotEqual(t, key, IdempotencyKey(thread, "1717243200.000200", "v1"))
+	assert.NotEqual(t, key, IdempotencyKey(thread, "1717243200.000100", "v2"))
+	// Parts are separated, so moving characters from one to the next changes the key
+	assert.NotEqual(t, IdempotencyKey(thread, "12", "3"), IdempotencyKey(thread, "1", "23"))
+}
+
+func TestMessageIdempotencyKey(t *testing.T) {
+	thread := common.GenerateID()
+	newMessage := func() *Message {
+		msg, err := NewMessage(thread, "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+		require.NoError(t, err)
+		msg.RecordPromptVersion("v1")
+		return msg
+	}
+
+	t.Run("deliveries of a chat message share the key", func(t *testing.T) {
+		first, redelivered := newMessage(), newMessage()
+		first.SetSourceTimestamp("1717243200.000100")
+		redelivered.SetSourceTimestamp("1717243200.000100")
+
+		assert.Equal(t, MessageIdempotencyKey(first), MessageIdempotencyKey(redelivered))
+		assert.Equal(t, IdempotencyKey(thread, "1717243200.000100", "v1"), MessageIdempotencyKey(first))
+	})
+
+	t.Run("messages without a chat timestamp are keyed by their ID", func(t *testing.T) {
+		msg, other := newMessage(), newMessage()
+
+		assert.Equal(t, IdempotencyKey(thread, msg.ID().String(), "v1"), MessageIdempotencyKey(msg))
+		assert.NotEqual(t, MessageIdempotencyKey(msg), MessageIdempotencyKey(other))
+	})
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidImageAsset = errors.New("image asset needs the contents of an image attachment")
+
+// AssetsDir is the directory of the document store holding the files shared with documented messages
+const AssetsDir = "docs/assets"
+
+// assetHashLength is how many hex digits of the SHA-256 of a file name it in the assets directory
+const assetHashLength = 32
+
+// ImageAsset is an image shared with a message, stored next to the message's document together with
+// what a vision model read from it
+type ImageAsset struct {
+	name        string
+	path        string
+	data        []byte
+	description string
+}
+
+// NewImageAsset creates the asset of an image attachment of a message. The description may be empty
+// when the image could not be read.
+func NewImageAsset(attachment *Attachment, data []byte, description string) (*ImageAsset, error) {
+	if attachment == nil || !attachment.IsImage() || len(data) == 0 {
+		return nil, ErrInvalidImageAsset
+	}
+	return &ImageAsset{
+		name:        attachment.Name(),
+		path:        AssetPath(data, attachment.Name()),
+		data:        data,
+		description: strings.TrimSpace(description),
+	}, nil
+}
+
+// AssetPath returns where a file is stored, named by the hash of its contents so a file shared with several
+// messages is stored once, like docs/assets/<sha256>.jpg. The extension is taken from the name it was shared with.
+func AssetPath(data []byte, name string) string {
+	sum := sha256.Sum256(data)
+	return path.Join(AssetsDir, hex.EncodeToString(sum[:])[:assetHashLength]+strings.ToLower(path.Ext(name)))
+}
+
+// Name returns the file name the image was shared with
+func (a *ImageAsset) Name() string {
+	return a.name
+}
+
+// Path returns where the image is stored
+func (a *ImageAsset) Path() string {
+	return a.path
+}
+
+// Data returns the image file
+func (a *ImageAsset) Data() []byte {
+	return a.data
+}
+
+// Description returns the text and diagrams a vision model read from the image, if any
+func (a *ImageAsset) Description() string {
+	return a.description
+}
+
+// DescribeImages renders the descriptions of images as text for generating documentation from a message
+func DescribeImages(images []*ImageAsset) string {
+	var b strings.Builder
+	for _, image := range images {
+		if image.description == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(fmt.Sprintf("Attached image %s shows:\n%s", image.name, image.description))
+	}
+	return b.String()
+}
+
+// RenderImageSection renders the Images section of a document, embedding each image by its path
+// relative to the document and quoting its description
+func RenderImageSection(docPath string, images []*ImageAsset) string {
+	if len(images) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Images\n")
+	for _, image := range images {
+		link, err := filepath.Rel(filepath.Dir(docPath), image.path)
+		if err != nil {
+			link = "/" + image.path
+		}
+		b.WriteString(fmt.Sprintf("\n![%s](%s)\n", image.name, filepath.ToSlash(link)))
+		if image.description != "" {
+			b.WriteString("\n")
+			for _, line := range strings.Split(image.description, "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+		}
+	}
+	return b.String()
+}
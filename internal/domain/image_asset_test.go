@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetPath(t *testing.T) {
+	// The first 32 hex digits of the SHA-256 of "whiteboard"
+	assert.Equal(t, "docs/assets/8460e24969d53e382b52b1caebde8dc4.jpg", AssetPath([]byte("whiteboard"), "Whiteboard Photo.JPG"))
+	assert.Equal(t, AssetPath([]byte("whiteboard"), "photo.jpg"), AssetPath([]byte("whiteboard"), "Whiteboard Photo.JPG"))
+	assert.NotEqual(t, AssetPath([]byte("whiteboard"), "photo.jpg"), AssetPath([]byte("blurry"), "photo.jpg"))
+	assert.Equal(t, "docs/assets/8460e24969d53e382b52b1caebde8dc4", AssetPath([]byte("whiteboard"), "whiteboard"))
+}
+
+func TestNewImageAsset(t *testing.T) {
+	image, _ := NewAttachment("schema.png", "image/png", "https://files.example.com/schema.png")
+	pdf, _ := NewAttachment("plan.pdf", "application/pdf", "https://files.example.com/plan.pdf")
+
+	asset, err := NewImageAsset(image, []byte("png"), " users -> orders \n")
+	require.NoError(t, err)
+	assert.Equal(t, "users -> orders", asset.Description())
+	assert.Equal(t, AssetPath([]byte("png"), "schema.png"), asset.Path())
+
+	_, err = NewImageAsset(pdf, []byte("pdf"), "")
+	assert.ErrorIs(t, err, ErrInvalidImageAsset)
+	_, err = NewImageAsset(image, nil, "")
+	assert.ErrorIs(t, err, ErrInvalidImageAsset)
+}
+
+func TestRenderImageSection(t *testing.T) {
+	schema, _ := NewAttachment("schema.png", "image/png", "https://files.example.com/schema.png")
+	photo, _ := NewAttachment("board.jpg", "image/jpeg", "https://files.example.com/board.jpg")
+	described, err := NewImageAsset(schema, []byte("png"), "Text: users, orders\n\nDiagram: users has many orders")
+	require.NoError(t, err)
+	unread, err := NewImageAsset(photo, []byte("jpg"), "")
+	require.NoError(t, err)
+
+	section := RenderImageSection("docs/development/schema.md", []*ImageAsset{described, unread})
+
+	assert.Equal(t, "## Images\n"+
+		"\n![schema.png](../"+strings.TrimPrefix(described.Path(), "docs/")+")\n"+
+		"\n> Text: users, orders\n>\n> Diagram: users has many orders\n"+
+		"\n![board.jpg](../"+strings.TrimPrefix(unread.Path(), "docs/")+")\n", section)
+	assert.Empty(t, RenderImageSection("docs/development/schema.md", nil))
+
+	assert.Equal(t, "Attached image schema.png shows:\nText: users, orders\n\nDiagram: users has many orders",
+		DescribeImages([]*ImageAsset{described, unread}))
+}
@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// incidentsDir is the directory of docs/ incident reports are stored in
+const incidentsDir = "incidents"
+
+var (
+	ErrInvalidIncident  = errors.New("invalid incident")
+	ErrIncidentResolved = errors.New("incident already resolved")
+)
+
+// Incident is a thread people declared an incident in. Until it is resolved every message of the thread is
+// captured for its timeline, whatever the analysis would make of it.
+type Incident struct {
+	threadID   string
+	channelID  string
+	title      string
+	commander  string
+	startedAt  time.Time
+	resolvedBy string
+	resolvedAt time.Time
+}
+
+// NewIncident starts an incident in a thread, declared by the commander at startedAt
+func NewIncident(threadID, channelID, title, commander string, startedAt time.Time) (*Incident, error) {
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" {
+		return nil, fmt.Errorf("%w: the thread is required", ErrInvalidIncident)
+	}
+	commander = strings.TrimSpace(commander)
+	if commander == "" {
+		return nil, fmt.Errorf("%w: who declared it is unknown", ErrInvalidIncident)
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = "Incident of " + startedAt.UTC().Format("2006-01-02 15:04 UTC")
+	}
+
+	return &Incident{
+		threadID:  threadID,
+		channelID: channelID,
+		title:     title,
+		commander: commander,
+		startedAt: startedAt.UTC(),
+	}, nil
+}
+
+// ThreadID returns the ID of the thread the incident is handled in
+func (i *Incident) ThreadID() string {
+	return i.threadID
+}
+
+// ChannelID returns the channel of the incident's thread
+func (i *Incident) ChannelID() string {
+	return i.channelID
+}
+
+// Title returns the title of the incident
+func (i *Incident) Title() string {
+	return i.title
+}
+
+// Commander returns who declared the incident
+func (i *Incident) Commander() string {
+	return i.commander
+}
+
+// StartedAt returns when the incident was declared
+func (i *Incident) StartedAt() time.Time {
+	return i.startedAt
+}
+
+// ResolvedBy returns who resolved the incident, empty while it is open
+func (i *Incident) ResolvedBy() string {
+	return i.resolvedBy
+}
+
+// ResolvedAt returns when the incident was resolved, zero while it is open
+func (i *Incident) ResolvedAt() time.Time {
+	return i.resolvedAt
+}
+
+// Resolved checks if the incident was resolved
+func (i *Incident) Resolved() bool {
+	return !i.resolvedAt.IsZero()
+}
+
+// Resolve ends the incident
+func (i *Incident) Resolve(actor string, at time.Time) error {
+	if i.Resolved() {
+		return fmt.Errorf("%w by %s", ErrIncidentResolved, i.resolvedBy)
+	}
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return fmt.Errorf("%w: who resolved it is unknown", ErrInvalidIncident)
+	}
+	i.resolvedBy = actor
+	i.resolvedAt = at.UTC()
+	if i.resolvedAt.Before(i.startedAt) {
+		i.resolvedAt = i.startedAt
+	}
+	return nil
+}
+
+// Duration returns how long the incident lasted, zero while it is open
+func (i *Incident) Duration() time.Duration {
+	if !i.Resolved() {
+		return 0
+	}
+	return i.resolvedAt.Sub(i.startedAt).Round(time.Minute)
+}
+
+// Path returns where the report of the incident is stored, docs/incidents/<yyyy-mm-dd>-<slugified-title>.md
+func (i *Incident) Path() string {
+	name := "incident-" + i.startedAt.Format("150405")
+	if slug := Slugify(i.title); slug != "" {
+		name = slug
+	}
+	return path.Join(docsRoot, incidentsDir, i.startedAt.Format("2006-01-02")+"-"+name+".md")
+}
+
+// IncidentPersonKeys returns the front matter keys of incident reports naming people
+func IncidentPersonKeys() []string {
+	return []string{"incident_commander", "resolved_by"}
+}
+
+// IncidentBodyNames returns where incident reports name people in their body, for ErasureRequest.ApplyToBody:
+// who declared and resolved the incident, and the actors of its timeline
+func IncidentBodyNames() []string {
+	return []string{"Declared by %s on ", ", resolved by %s on ", "** %s: "}
+}
+
+// Record adds the incident to the front matter of its report
+func (i *Incident) Record(fm *FrontMatter) {
+	fm.Set("type", MessageTypeStatus.String())
+	fm.Set("category", CategoryOperations.String())
+	fm.Set("created_at", i.resolvedAt.Format(time.RFC3339))
+	fm.Set("thread", i.threadID)
+	fm.Set("incident_commander", i.commander)
+	fm.Set("incident_started_at", i.startedAt.Format(time.RFC3339))
+	if i.Resolved() {
+		fm.Set("incident_resolved_at", i.resolvedAt.Format(time.RFC3339))
+		fm.Set("resolved_by", i.resolvedBy)
+	}
+	fm.SetList("tags", []string{"incident", "postmortem"})
+}
+
+// IncidentEvent is an entry of the timeline of an incident
+type IncidentEvent struct {
+	At    time.Time
+	Actor string
+	Text  string
+}
+
+// NewIncidentTimeline returns the timeline of an incident: the messages of its thread, oldest first, with
+// when it was declared and resolved. Messages posted after it was resolved are left out.
+func NewIncidentTimeline(incident *Incident, messages []*Message) []IncidentEvent {
+	timeline := []IncidentEvent{{
+		At:    incident.startedAt,
+		Actor: incident.commander,
+		Text:  "Declared the incident",
+	}}
+	for _, msg := range messages {
+		if incident.Resolved() && msg.Timestamp().After(incident.resolvedAt) {
+			continue
+		}
+		text := strings.Join(strings.Fields(msg.Content().Text()), " ")
+		if text == "" {
+			continue
+		}
+		timeline = append(timeline, IncidentEvent{At: msg.Timestamp().UTC(), Actor: msg.Sender(), Text: text})
+	}
+	if incident.Resolved() {
+		timeline = append(timeline, IncidentEvent{
+			At:    incident.resolvedAt,
+			Actor: incident.resolvedBy,
+			Text:  "Resolved the incident",
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+	return timeline
+}
+
+// RenderIncidentTimeline renders a timeline as a Markdown list
+func RenderIncidentTimeline(timeline []IncidentEvent) string {
+	var b strings.Builder
+	for _, event := range timeline {
+		b.WriteString(fmt.Sprintf("- **%s** %s: %s\n", event.At.UTC().Format("2006-01-02 15:04:05"), event.Actor, event.Text))
+	}
+	return b.String()
+}
+
+// incidentPostmortemTemplate is the postmortem of a report when none was drafted, with the sections to write
+const incidentPostmortemTemplate = `### Summary
+
+_What happened, in a sentence or two._
+
+### Impact
+
+_Who and what was affected, and for how long._
+
+### Root cause
+
+_Why it happened._
+
+### Resolution
+
+_What ended the incident._
+
+### Action items
+
+- [ ] _What keeps it from happening again, and who does it._`
+
+// RenderIncidentReport renders the report of a resolved incident: who declared and resolved it, its timeline
+// and the postmortem draft. Without a draft, the report has the sections of a postmortem for people to write.
+func RenderIncidentReport(incident *Incident, timeline []IncidentEvent, postmortem string) string {
+	postmortem = strings.TrimSpace(postmortem)
+	if postmortem == "" {
+		postmortem = incidentPostmortemTemplate
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Incident: %s\n\n", incident.title))
+	b.WriteString(fmt.Sprintf("Declared by %s on %s", incident.commander, incident.startedAt.Format("2006-01-02 15:04 UTC")))
+	if incident.Resolved() {
+		b.WriteString(fmt.Sprintf(", resolved by %s on %s after %s", incident.resolvedBy,
+			incident.resolvedAt.Format("2006-01-02 15:04 UTC"), incident.Duration()))
+	}
+	b.WriteString(".\n\n## Timeline\n\n")
+	b.WriteString(RenderIncidentTimeline(timeline))
+	b.WriteString("\n## Postmortem draft\n\n")
+	b.WriteString(postmortem)
+	b.WriteString("\n")
+	return b.String()
+}
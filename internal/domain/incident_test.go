@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIncident(t *testing.T) {
+	startedAt := time.Date(2024, 6, 10, 14, 2, 0, 0, time.UTC)
+
+	incident, err := NewIncident("T1", "C1", " Payments API down ", "alice", startedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "Payments API down", incident.Title())
+	assert.Equal(t, "docs/incidents/2024-06-10-payments-api-down.md", incident.Path())
+	assert.False(t, incident.Resolved())
+	assert.Zero(t, incident.Duration())
+
+	untitled, err := NewIncident("T1", "C1", "", "alice", startedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "Incident of 2024-06-10 14:02 UTC", untitled.Title())
+	assert.Equal(t, "docs/incidents/2024-06-10-incident-of-2024-06-10-14-02-utc.md", untitled.Path())
+
+	for _, tt := range []struct{ threadID, commander string }{
+		{threadID: "", commander: "alice"},
+		{threadID: "T1", commander: " "},
+	} {
+		_, err := NewIncident(tt.threadID, "C1", "Outage", tt.commander, startedAt)
+		assert.ErrorIs(t, err, ErrInvalidIncident, tt)
+	}
+}
+
+func TestIncident_Resolve(t *testing.T) {
+	startedAt := time.Date(2024, 6, 10, 14, 2, 0, 0, time.UTC)
+	incident, err := NewIncident("T1", "C1", "Payments API down", "alice", startedAt)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, incident.Resolve("", startedAt.Add(time.Hour)), ErrInvalidIncident)
+	require.NoError(t, incident.Resolve("bob", startedAt.Add(85*time.Minute)))
+	assert.True(t, incident.Resolved())
+	assert.Equal(t, "bob", incident.ResolvedBy())
+	assert.Equal(t, 85*time.Minute, incident.Duration())
+	assert.ErrorIs(t, incident.Resolve("carol", startedAt.Add(2*time.Hour)), ErrIncidentResolved)
+
+	fm := NewFrontMatter()
+	incident.Record(fm)
+	assert.Equal(t, "status", fm.Get("type"))
+	assert.Equal(t, "operations", fm.Get("category"))
+	assert.Equal(t, "alice", fm.Get("incident_commander"))
+	assert.Equal(t, "2024-06-10T14:02:00Z", fm.Get("incident_started_at"))
+	assert.Equal(t, "2024-06-10T15:27:00Z", fm.Get("incident_resolved_at"))
+	assert.Equal(t, "bob", fm.Get("resolved_by"))
+	assert.Equal(t, []string{"incident", "postmortem"}, fm.GetList("tags"))
+}
+
+func TestNewIncidentTimeline(t *testing.T) {
+	startedAt := time.Now().UTC()
+	incident, err := NewIncident("T1", "C1", "Payments API down", "alice", startedAt.Add(-time.Minute))
+	require.NoError(t, err)
+
+	message := func(sender, text string) *Message {
+		msg, err := NewMessage(common.GenerateID(), sender, MustNewMessageContent(text), MessageTypeUnknown, CategoryUnknown, nil)
+		require.NoError(t, err)
+		return msg
+	}
+	alert := message("pagerduty", "Payments API\nerror rate above 5%")
+	time.Sleep(time.Millisecond)
+	fix := message("bob", "Rolled back to v1.4.1")
+	require.NoError(t, incident.Resolve("bob", time.Now()))
+	time.Sleep(time.Millisecond)
+	late := message("carol", "Thanks all")
+
+	timeline := NewIncidentTimeline(incident, []*Message{fix, late, alert})
+	require.Len(t, timeline, 4)
+	assert.Equal(t, IncidentEvent{At: incident.StartedAt(), Actor: "alice", Text: "Declared the incident"}, timeline[0])
+	assert.Equal(t, "Payments API error rate above 5%", timeline[1].Text)
+	assert.Equal(t, "bob", timeline[2].Actor)
+	assert.Equal(t, IncidentEvent{At: incident.ResolvedAt(), Actor: "bob", Text: "Resolved the incident"}, timeline[3])
+}
+
+func TestRenderIncidentReport(t *testing.T) {
+	startedAt := time.Date(2024, 6, 10, 14, 2, 0, 0, time.UTC)
+	incident, err := NewIncident("T1", "C1", "Payments API down", "alice", startedAt)
+	require.NoError(t, err)
+	require.NoError(t, incident.Resolve("bob", startedAt.Add(85*time.Minute)))
+	timeline := []IncidentEvent{
+		{At: startedAt, Actor: "alice", Text: "Declared the incident"},
+		{At: startedAt.Add(85 * time.Minute), Actor: "bob", Text: "Resolved the incident"},
+	}
+
+	report := RenderIncidentReport(incident, timeline, "")
+	assert.True(t, strings.HasPrefix(report, "# Incident: Payments API down\n\n"))
+	assert.Contains(t, report, "Declared by alice on 2024-06-10 14:02 UTC, resolved by bob on 2024-06-10 15:27 UTC after 1h25m0s.\n")
+	assert.Contains(t, report, "## Timeline\n\n- **2024-06-10 14:02:00** alice: Declared the incident\n- **2024-06-10 15:27:00** bob: Resolved the incident\n")
+	assert.Contains(t, report, "## Postmortem draft\n\n### Summary\n")
+	assert.Contains(t, report, "### Action items\n")
+
+	drafted := RenderIncidentReport(incident, timeline, "\n### Summary\n\nThe payments API failed after a deploy.\n")
+	assert.True(t, strings.HasSuffix(drafted, "## Postmortem draft\n\n### Summary\n\nThe payments API failed after a deploy.\n"))
+}
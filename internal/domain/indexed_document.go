@@ -0,0 +1,286 @@
+package domain
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+var (
+	ErrEmptyDocumentPath = errors.New("document path cannot be empty")
+)
+
+// IndexedDocument describes a stored document in the document index
+type IndexedDocument struct {
+	path        string
+	title       string
+	summary     string
+	messageType MessageType
+	category    Category
+	tags        []Tag
+	embedding   []float64
+	repository  string
+	branch      string
+	project     common.ID
+	visibility  Visibility
+	// idempotencyKey is the key of the message the document was generated from, see IdempotencyKey
+	idempotencyKey string
+	owner          string
+	status         DocumentStatus
+	createdAt      time.Time
+	updatedAt      time.Time
+}
+
+// NewIndexedDocument creates a new IndexedDocument instance
+func NewIndexedDocument(docPath, title, summary string, msgType MessageType, category Category) (*IndexedDocument, error) {
+	docPath = strings.TrimSpace(docPath)
+	if docPath == "" {
+		return nil, ErrEmptyDocumentPath
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = titleFromPath(docPath)
+	}
+
+	now := time.Now()
+	return &IndexedDocument{
+		path:        docPath,
+		title:       title,
+		summary:     strings.TrimSpace(summary),
+		messageType: msgType,
+		category:    category,
+		createdAt:   now,
+		updatedAt:   now,
+	}, nil
+}
+
+// Path returns the document path in the document store
+func (d *IndexedDocument) Path() string {
+	return d.path
+}
+
+// Title returns the document title
+func (d *IndexedDocument) Title() string {
+	return d.title
+}
+
+// Summary returns a short summary of the document
+func (d *IndexedDocument) Summary() string {
+	return d.summary
+}
+
+// Type returns the message type the document was generated from
+func (d *IndexedDocument) Type() MessageType {
+	return d.messageType
+}
+
+// Category returns the document category
+func (d *IndexedDocument) Category() Category {
+	return d.category
+}
+
+// Tags returns the document tags
+func (d *IndexedDocument) Tags() []Tag {
+	tags := make([]Tag, len(d.tags))
+	copy(tags, d.tags)
+	return tags
+}
+
+// HasTag checks if the document has the given tag
+func (d *IndexedDocument) HasTag(tag Tag) bool {
+	for _, t := range d.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces the document tags
+func (d *IndexedDocument) SetTags(tags []Tag) {
+	d.tags = make([]Tag, len(tags))
+	copy(d.tags, tags)
+	d.updatedAt = time.Now()
+}
+
+// Embedding returns the document embedding vector, if any
+func (d *IndexedDocument) Embedding() []float64 {
+	embedding := make([]float64, len(d.embedding))
+	copy(embedding, d.embedding)
+	return embedding
+}
+
+// HasEmbedding checks if the document has an embedding vector
+func (d *IndexedDocument) HasEmbedding() bool {
+	return len(d.embedding) > 0
+}
+
+// Repository returns the owner/name of the repository holding the document, empty for the default one
+func (d *IndexedDocument) Repository() string {
+	return d.repository
+}
+
+// Branch returns the branch holding the document, empty for the repository's configured branch
+func (d *IndexedDocument) Branch() string {
+	return d.branch
+}
+
+// SetLocation records the repository and branch the document was stored in
+func (d *IndexedDocument) SetLocation(repository, branch string) {
+	d.repository = strings.TrimSpace(repository)
+	d.branch = strings.TrimSpace(branch)
+}
+
+// Project returns the ID of the project the document was written for, empty for messages outside of projects
+func (d *IndexedDocument) Project() common.ID {
+	return d.project
+}
+
+// SetProject records the project the document was written for
+func (d *IndexedDocument) SetProject(id common.ID) {
+	d.project = id
+}
+
+// Visibility returns the visibility the document was marked with, empty when it follows its project's default
+func (d *IndexedDocument) Visibility() Visibility {
+	return d.visibility
+}
+
+// SetVisibility marks the document as internal to its project or shared with every project
+func (d *IndexedDocument) SetVisibility(visibility Visibility) {
+	d.visibility = visibility
+}
+
+// IdempotencyKey returns the idempotency key of the message the document was generated from, empty for
+// documents people wrote
+func (d *IndexedDocument) IdempotencyKey() string {
+	return d.idempotencyKey
+}
+
+// SetIdempotencyKey records the idempotency key of the message the document was generated from
+func (d *IndexedDocument) SetIdempotencyKey(key string) {
+	d.idempotencyKey = strings.TrimSpace(key)
+}
+
+// Owner returns the username of the person owning the document, empty when nobody does
+func (d *IndexedDocument) Owner() string {
+	return d.owner
+}
+
+// SetOwner records who owns the document
+func (d *IndexedDocument) SetOwner(owner string) {
+	d.owner = strings.TrimSpace(owner)
+}
+
+// Status returns the status of the document's front matter, active when it has none
+func (d *IndexedDocument) Status() DocumentStatus {
+	if d.status == "" {
+		return DocumentStatusActive
+	}
+	return d.status
+}
+
+// SetStatus records the status of the document's front matter
+func (d *IndexedDocument) SetStatus(status DocumentStatus) {
+	d.status = status
+}
+
+// SetFrontMatter records the owner and status of the document's front matter
+func (d *IndexedDocument) SetFrontMatter(fm *FrontMatter) {
+	d.SetOwner(OwnerOf(fm))
+	d.SetStatus(DocumentStatusOf(fm))
+}
+
+// Refresh replaces the title and summary with the ones of the document as it is now, like after people edited
+// it, and reports whether they changed. The embedding of a changed document is dropped, it no longer matches.
+func (d *IndexedDocument) Refresh(title, summary string) bool {
+	title, summary = strings.TrimSpace(title), strings.TrimSpace(summary)
+	if title == "" {
+		title = titleFromPath(d.path)
+	}
+	if title == d.title && summary == d.summary {
+		return false
+	}
+	d.title, d.summary, d.embedding = title, summary, nil
+	d.updatedAt = time.Now()
+	return true
+}
+
+// Touch records that the document content changed, like when an entry is added to it
+func (d *IndexedDocument) Touch() {
+	d.updatedAt = time.Now()
+}
+
+// Moved returns a copy of the entry for the document moved to another path and category
+func (d *IndexedDocument) Moved(docPath string, category Category) (*IndexedDocument, error) {
+	docPath = strings.TrimSpace(docPath)
+	if docPath == "" {
+		return nil, ErrEmptyDocumentPath
+	}
+
+	moved := *d
+	moved.path = docPath
+	moved.category = category
+	moved.tags = d.Tags()
+	moved.embedding = d.Embedding()
+	moved.updatedAt = time.Now()
+	return &moved, nil
+}
+
+// CreatedAt returns the time the document was indexed
+func (d *IndexedDocument) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+// UpdatedAt returns the time the document was last updated
+func (d *IndexedDocument) UpdatedAt() time.Time {
+	return d.updatedAt
+}
+
+// SetEmbedding stores the embedding vector of the document
+func (d *IndexedDocument) SetEmbedding(embedding []float64) {
+	d.embedding = make([]float64, len(embedding))
+	copy(d.embedding, embedding)
+	d.updatedAt = time.Now()
+}
+
+// SearchText returns the text used to match the document against queries
+func (d *IndexedDocument) SearchText() string {
+	return d.title + " " + d.summary
+}
+
+// TitleFromMarkdown extracts the first top-level heading from a Markdown document
+func TitleFromMarkdown(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		}
+	}
+	return ""
+}
+
+// SummaryFromMarkdown returns the first paragraph of a Markdown document that is not a heading
+func SummaryFromMarkdown(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		if len(line) > 200 {
+			line = line[:200]
+		}
+		return line
+	}
+	return ""
+}
+
+// titleFromPath builds a readable title from a document file name
+func titleFromPath(docPath string) string {
+	name := strings.TrimSuffix(path.Base(docPath), path.Ext(docPath))
+	return strings.NewReplacer("-", " ", "_", " ").Replace(name)
+}
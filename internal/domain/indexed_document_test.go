@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewIndexedDocument(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		title     string
+		wantTitle string
+		wantError bool
+	}{
+		{
+			name:      "uses given title",
+			path:      "docs/development/decision-20240601-120000.md",
+			title:     "Adopt Postgres",
+			wantTitle: "Adopt Postgres",
+		},
+		{
+			name:      "derives title from path",
+			path:      "docs/development/adopt-postgres.md",
+			title:     "  ",
+			wantTitle: "adopt postgres",
+		},
+		{
+			name:      "empty path",
+			path:      " ",
+			title:     "Adopt Postgres",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewIndexedDocument(tt.path, tt.title, "", MessageTypeDecision, CategoryDevelopment)
+			if tt.wantError {
+				if err != ErrEmptyDocumentPath {
+					t.Errorf("expected error %v, got %v", ErrEmptyDocumentPath, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if doc.Title() != tt.wantTitle {
+				t.Errorf("Title() = %q, want %q", doc.Title(), tt.wantTitle)
+			}
+			if doc.HasEmbedding() {
+				t.Error("expected no embedding")
+			}
+		})
+	}
+}
+
+func TestIndexedDocument_SetLocation(t *testing.T) {
+	doc, err := NewIndexedDocument("docs/development/adopt-postgres.md", "", "", MessageTypeDecision, CategoryDevelopment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Repository() != "" || doc.Branch() != "" {
+		t.Errorf("expected the default location, got %q@%q", doc.Repository(), doc.Branch())
+	}
+
+	doc.SetLocation(" team/handbook ", "docs")
+	if doc.Repository() != "team/handbook" {
+		t.Errorf("Repository() = %q, want %q", doc.Repository(), "team/handbook")
+	}
+	if doc.Branch() != "docs" {
+		t.Errorf("Branch() = %q, want %q", doc.Branch(), "docs")
+	}
+}
+
+func TestIndexedDocument_Moved(t *testing.T) {
+	doc, err := NewIndexedDocument("docs/other/pricing.md", "Pricing", "", MessageTypeIdea, CategoryOther)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc.SetTags([]Tag{"billing"})
+	doc.SetLocation("team/handbook", "docs")
+
+	moved, err := doc.Moved("docs/product/pricing.md", CategoryProduct)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moved.Path() != "docs/product/pricing.md" || moved.Category() != CategoryProduct {
+		t.Errorf("Moved() = %q in %s, want docs/product/pricing.md in product", moved.Path(), moved.Category())
+	}
+	if moved.Title() != "Pricing" || !moved.HasTag("billing") || moved.Repository() != "team/handbook" {
+		t.Errorf("Moved() lost the document's details: %+v", moved)
+	}
+	if doc.Path() != "docs/other/pricing.md" || doc.Category() != CategoryOther {
+		t.Errorf("Moved() changed the original entry to %q in %s", doc.Path(), doc.Category())
+	}
+
+	if _, err := doc.Moved(" ", CategoryProduct); !errors.Is(err, ErrEmptyDocumentPath) {
+		t.Errorf("Moved() error = %v, want %v", err, ErrEmptyDocumentPath)
+	}
+}
+
+func TestIndexedDocument_Refresh(t *testing.T) {
+	doc, err := NewIndexedDocument("docs/development/adopt-postgres.md", "Adopt Postgres", "We use Postgres.", MessageTypeDecision, CategoryDevelopment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc.SetEmbedding([]float64{0.1, 0.2})
+
+	if doc.Refresh("Adopt Postgres", " We use Postgres. ") {
+		t.Error("Refresh() = true for the same title and summary")
+	}
+	if !doc.HasEmbedding() {
+		t.Error("Refresh() dropped the embedding of an unchanged document")
+	}
+
+	if !doc.Refresh("Adopt Postgres 16", "We use Postgres 16.") {
+		t.Error("Refresh() = false for a new title")
+	}
+	if doc.Title() != "Adopt Postgres 16" || doc.Summary() != "We use Postgres 16." {
+		t.Errorf("Refresh() left %q: %q", doc.Title(), doc.Summary())
+	}
+	if doc.HasEmbedding() {
+		t.Error("Refresh() kept the embedding of the old content")
+	}
+
+	doc.Refresh("", "")
+	if doc.Title() != "adopt postgres" {
+		t.Errorf("Title() = %q, want the title derived from the path", doc.Title())
+	}
+}
+
+func TestTitleFromMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "first level one heading",
+			content: "## Context\n# Adopt Postgres\n\nWe decided...",
+			want:    "Adopt Postgres",
+		},
+		{
+			name:    "no heading",
+			content: "We decided to adopt Postgres",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TitleFromMarkdown(tt.content); got != tt.want {
+				t.Errorf("TitleFromMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummaryFromMarkdown(t *testing.T) {
+	content := "# Adopt Postgres\n\n## Context\n\nWe need a relational store.\n\nMore text."
+	if got := SummaryFromMarkdown(content); got != "We need a relational store." {
+		t.Errorf("SummaryFromMarkdown() = %q", got)
+	}
+}
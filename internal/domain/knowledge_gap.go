@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultGapStaleness is how long a KPI may go without status updates before it is reported as a gap
+const DefaultGapStaleness = 3 * 7 * 24 * time.Hour
+
+// GapKind names what a knowledge gap is missing
+type GapKind string
+
+const (
+	// GapMilestoneDecisions is an upcoming milestone without recorded decisions
+	GapMilestoneDecisions GapKind = "milestone"
+	// GapKPIStatus is a KPI without recent status updates
+	GapKPIStatus GapKind = "kpi"
+	// GapGoalDocuments is a business goal no document relates to
+	GapGoalDocuments GapKind = "goal"
+)
+
+// KnowledgeGap is something a project set out to do that its documentation does not cover
+type KnowledgeGap struct {
+	kind        GapKind
+	subject     string
+	description string
+}
+
+// Kind returns what the gap is missing
+func (g KnowledgeGap) Kind() GapKind {
+	return g.kind
+}
+
+// Subject returns the milestone, KPI or goal the gap is about
+func (g KnowledgeGap) Subject() string {
+	return g.subject
+}
+
+// Description returns the gap as a sentence, like "No decisions recorded for the Payments milestone"
+func (g KnowledgeGap) Description() string {
+	return g.description
+}
+
+// GapEvidence is a document of a project as the gap analysis sees it. The text is what the document is
+// matched on, its title, summary and tags, or its content when it was read.
+type GapEvidence struct {
+	Type      MessageType
+	Text      string
+	UpdatedAt time.Time
+}
+
+// EvidenceFromIndex returns the evidence of an indexed document, matched on its title, summary and tags
+func EvidenceFromIndex(doc *IndexedDocument) GapEvidence {
+	text := doc.SearchText()
+	for _, tag := range doc.Tags() {
+		text += " " + strings.ReplaceAll(string(tag), "-", " ")
+	}
+	return GapEvidence{Type: doc.Type(), Text: text, UpdatedAt: doc.UpdatedAt()}
+}
+
+// AnalyzeKnowledgeGaps compares the goals, KPIs and milestones of a project with its documents:
+//   - upcoming milestones need a decision mentioning them
+//   - KPIs need a status update mentioning them within the staleness window, once the project is older than it
+//   - business goals need any document relating to them
+func AnalyzeKnowledgeGaps(project *Project, evidence []GapEvidence, now time.Time, staleAfter time.Duration) []KnowledgeGap {
+	if staleAfter <= 0 {
+		staleAfter = DefaultGapStaleness
+	}
+	var gaps []KnowledgeGap
+
+	for _, milestone := range project.Milestones() {
+		if !milestone.Deadline().IsZero() && milestone.Deadline().Before(now) {
+			continue
+		}
+		if !hasEvidence(evidence, milestone.Name(), func(e GapEvidence) bool { return e.Type.IsDecision() }) {
+			description := fmt.Sprintf("No decisions recorded for the %s milestone", milestone.Name())
+			if !milestone.Deadline().IsZero() {
+				description += fmt.Sprintf(", due %s", milestone.Deadline().UTC().Format("2006-01-02"))
+			}
+			gaps = append(gaps, KnowledgeGap{kind: GapMilestoneDecisions, subject: milestone.Name(), description: description})
+		}
+	}
+
+	since := now.Add(-staleAfter)
+	if project.CreatedAt().Before(since) {
+		for _, kpi := range project.KPIs() {
+			recent := func(e GapEvidence) bool { return e.Type.IsStatus() && !e.UpdatedAt.Before(since) }
+			if !hasEvidence(evidence, kpi, recent) {
+				gaps = append(gaps, KnowledgeGap{
+					kind:        GapKPIStatus,
+					subject:     kpi,
+					description: fmt.Sprintf("KPI %q has no status updates in %s", kpi, formatWeeks(staleAfter)),
+				})
+			}
+		}
+	}
+
+	for _, goal := range project.Goals() {
+		if !hasEvidence(evidence, goal, func(GapEvidence) bool { return true }) {
+			gaps = append(gaps, KnowledgeGap{
+				kind:        GapGoalDocuments,
+				subject:     goal,
+				description: fmt.Sprintf("No documents relate to the goal %q", goal),
+			})
+		}
+	}
+	return gaps
+}
+
+// RenderKnowledgeGaps renders the gaps of a project as a chat message
+func RenderKnowledgeGaps(project *Project, gaps []KnowledgeGap) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Knowledge gaps in %s:\n", project.Name()))
+	for _, gap := range gaps {
+		b.WriteString(fmt.Sprintf("• %s\n", gap.description))
+	}
+	b.WriteString("Capture a decision or status update in this channel to close them.")
+	return b.String()
+}
+
+// hasEvidence checks if a document accepted by the filter mentions the subject
+func hasEvidence(evidence []GapEvidence, subject string, accept func(GapEvidence) bool) bool {
+	for _, e := range evidence {
+		if accept(e) && mentionsSubject(e.Text, subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsSubject checks if a text shares the words of a subject, two of them for subjects of several words
+func mentionsSubject(text, subject string) bool {
+	wanted := tokenSet(Tokenize(subject))
+	if len(wanted) == 0 {
+		return false
+	}
+	required := 2
+	if len(wanted) < required {
+		required = len(wanted)
+	}
+
+	shared := 0
+	for token := range tokenSet(Tokenize(text)) {
+		if wanted[token] {
+			shared++
+		}
+	}
+	return shared >= required
+}
+
+// formatWeeks formats a duration as whole weeks, or days when shorter than two weeks
+func formatWeeks(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days < 14 {
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return fmt.Sprintf("%d weeks", days/7)
+}
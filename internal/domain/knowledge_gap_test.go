@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeKnowledgeGaps(t *testing.T) {
+	now := time.Date(2024, 6, 24, 9, 0, 0, 0, time.UTC)
+	newProject := func(createdAt time.Time) *Project {
+		project, err := NewProject("Checkout", "New checkout flow", []string{"Increase checkout conversion", "Support wallet payments"})
+		require.NoError(t, err)
+		require.NoError(t, project.AddKPI("Checkout conversion rate"))
+		require.NoError(t, project.AddKPI("Payment error rate"))
+		require.NoError(t, project.AddMilestone("Payments", now.Add(30*24*time.Hour)))
+		require.NoError(t, project.AddMilestone("Beta", now.Add(-24*time.Hour)))
+		require.NoError(t, project.AddMilestone("Launch", time.Time{}))
+
+		dto := project.ToDTO()
+		dto.CreatedAt = createdAt
+		project, err = ProjectFromDTO(dto)
+		require.NoError(t, err)
+		return project
+	}
+
+	describe := func(gaps []KnowledgeGap) []string {
+		var descriptions []string
+		for _, gap := range gaps {
+			descriptions = append(descriptions, gap.Description())
+		}
+		return descriptions
+	}
+
+	t.Run("undocumented project", func(t *testing.T) {
+		gaps := AnalyzeKnowledgeGaps(newProject(now.Add(-60*24*time.Hour)), nil, now, 0)
+		assert.Equal(t, []string{
+			"No decisions recorded for the Payments milestone, due 2024-07-24",
+			"No decisions recorded for the Launch milestone",
+			`KPI "Checkout conversion rate" has no status updates in 3 weeks`,
+			`KPI "Payment error rate" has no status updates in 3 weeks`,
+			`No documents relate to the goal "Increase checkout conversion"`,
+			`No documents relate to the goal "Support wallet payments"`,
+		}, describe(gaps))
+		assert.Equal(t, GapMilestoneDecisions, gaps[0].Kind())
+		assert.Equal(t, "Payments", gaps[0].Subject())
+	})
+
+	t.Run("covered project", func(t *testing.T) {
+		evidence := []GapEvidence{
+			{Type: MessageTypeDecision, Text: "Use Stripe for payments", UpdatedAt: now.Add(-40 * 24 * time.Hour)},
+			{Type: MessageTypeDecision, Text: "Launch plan approved, wallet support included", UpdatedAt: now.Add(-10 * 24 * time.Hour)},
+			{Type: MessageTypeStatus, Text: "Checkout conversion is up 2%", UpdatedAt: now.Add(-2 * 24 * time.Hour)},
+			{Type: MessageTypeStatus, Text: "Payment error rate below 1%", UpdatedAt: now.Add(-30 * 24 * time.Hour)},
+		}
+
+		gaps := AnalyzeKnowledgeGaps(newProject(now.Add(-60*24*time.Hour)), evidence, now, 0)
+		assert.Equal(t, []string{
+			`KPI "Payment error rate" has no status updates in 3 weeks`,
+		}, describe(gaps))
+	})
+
+	t.Run("new project has no stale KPIs yet", func(t *testing.T) {
+		gaps := AnalyzeKnowledgeGaps(newProject(now.Add(-24*time.Hour)), nil, now, 0)
+		for _, gap := range gaps {
+			assert.NotEqual(t, GapKPIStatus, gap.Kind())
+		}
+	})
+
+	t.Run("custom staleness", func(t *testing.T) {
+		gaps := AnalyzeKnowledgeGaps(newProject(now.Add(-60*24*time.Hour)), nil, now, 7*24*time.Hour)
+		assert.Contains(t, describe(gaps), `KPI "Payment error rate" has no status updates in 7 days`)
+	})
+}
+
+func TestEvidenceFromIndex(t *testing.T) {
+	doc, err := NewIndexedDocument("docs/product/wallets.md", "Wallets", "Apple Pay first", MessageTypeDecision, CategoryProduct)
+	require.NoError(t, err)
+	doc.SetTags([]Tag{"wallet-payments"})
+
+	evidence := EvidenceFromIndex(doc)
+	assert.Equal(t, MessageTypeDecision, evidence.Type)
+	assert.Equal(t, "Wallets Apple Pay first wallet payments", evidence.Text)
+	assert.True(t, mentionsSubject(evidence.Text, "Support wallet payments"))
+}
+
+func TestRenderKnowledgeGaps(t *testing.T) {
+	project := MustNewProject("Checkout", "New checkout flow", []string{"Increase conversion"})
+	gaps := []KnowledgeGap{
+		{kind: GapMilestoneDecisions, subject: "Payments", description: "No decisions recorded for the Payments milestone"},
+		{kind: GapGoalDocuments, subject: "Increase conversion", description: `No documents relate to the goal "Increase conversion"`},
+	}
+
+	assert.Equal(t, "Knowledge gaps in Checkout:\n"+
+		"• No decisions recorded for the Payments milestone\n"+
+		"• No documents relate to the goal \"Increase conversion\"\n"+
+		"Capture a decision or status update in this channel to close them.", RenderKnowledgeGaps(project, gaps))
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidMeeting = errors.New("invalid meeting")
+)
+
+// Meeting is a calendar event during which a discussion may have happened
+type Meeting struct {
+	id    string
+	title string
+	start time.Time
+	end   time.Time
+	link  string
+}
+
+// NewMeeting creates a new Meeting instance, the link to the event in its calendar is optional
+func NewMeeting(id, title string, start, end time.Time, link string) (*Meeting, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("%w: missing event ID", ErrInvalidMeeting)
+	}
+	if start.IsZero() || !end.After(start) {
+		return nil, fmt.Errorf("%w: the meeting must end after it starts", ErrInvalidMeeting)
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		// Calendars allow events without a title, private events hide theirs
+		title = "Untitled meeting"
+	}
+
+	return &Meeting{
+		id:    strings.TrimSpace(id),
+		title: title,
+		start: start,
+		end:   end,
+		link:  strings.TrimSpace(link),
+	}, nil
+}
+
+// ID returns the event's identifier in its calendar
+func (m *Meeting) ID() string {
+	return m.id
+}
+
+// Title returns the meeting's title
+func (m *Meeting) Title() string {
+	return m.title
+}
+
+// Start returns when the meeting starts
+func (m *Meeting) Start() time.Time {
+	return m.start
+}
+
+// End returns when the meeting ends
+func (m *Meeting) End() time.Time {
+	return m.end
+}
+
+// Link returns the address of the event in its calendar, or an empty string
+func (m *Meeting) Link() string {
+	return m.link
+}
+
+// String describes the meeting like "Architecture review (2025-06-02 14:00 UTC)"
+func (m *Meeting) String() string {
+	return fmt.Sprintf("%s (%s)", m.title, m.start.UTC().Format("2006-01-02 15:04 UTC"))
+}
+
+// overlap returns how long the meeting overlaps the period from to to, a single instant counts as
+// a nanosecond when the meeting contains it
+func (m *Meeting) overlap(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		if !from.Before(m.start) && from.Before(m.end) {
+			return time.Nanosecond
+		}
+		return 0
+	}
+
+	start, end := m.start, m.end
+	if from.After(start) {
+		start = from
+	}
+	if to.Before(end) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// OriginatingMeeting picks the meeting a discussion from from to to happened during: the one overlapping
+// the discussion the longest, the shorter one when meetings overlap it equally, like a review during an
+// all-day workshop. It returns nil when no meeting overlaps the discussion.
+func OriginatingMeeting(meetings []*Meeting, from, to time.Time) *Meeting {
+	var best *Meeting
+	var bestOverlap time.Duration
+	for _, m := range meetings {
+		overlap := m.overlap(from, to)
+		if overlap == 0 {
+			continue
+		}
+		if best == nil || overlap > bestOverlap || (overlap == bestOverlap && m.end.Sub(m.start) < best.end.Sub(best.start)) {
+			best, bestOverlap = m, overlap
+		}
+	}
+	return best
+}
@@ -0,0 +1,394 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// meetingsDir is the directory of docs/ meeting notes are stored in
+const meetingsDir = "meetings"
+
+var (
+	ErrInvalidNotesSession = errors.New("invalid notes session")
+	ErrNotesSessionClosed  = errors.New("notes session already closed")
+)
+
+// NotesSession is a thread taken as the notes of a meeting, like the thread of a huddle. Until it is closed every
+// message of the thread is collected for the notes.
+type NotesSession struct {
+	threadID  string
+	channelID string
+	title     string
+	startedBy string
+	startedAt time.Time
+	closedBy  string
+	closedAt  time.Time
+}
+
+// NewNotesSession starts taking the notes of a meeting in a thread
+func NewNotesSession(threadID, channelID, title, startedBy string, startedAt time.Time) (*NotesSession, error) {
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" {
+		return nil, fmt.Errorf("%w: the thread is required", ErrInvalidNotesSession)
+	}
+	startedBy = strings.TrimSpace(startedBy)
+	if startedBy == "" {
+		return nil, fmt.Errorf("%w: who started it is unknown", ErrInvalidNotesSession)
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = "Meeting of " + startedAt.UTC().Format("2006-01-02 15:04 UTC")
+	}
+
+	return &NotesSession{
+		threadID:  threadID,
+		channelID: channelID,
+		title:     title,
+		startedBy: startedBy,
+		startedAt: startedAt.UTC(),
+	}, nil
+}
+
+// ThreadID returns the ID of the thread the notes are taken in
+func (s *NotesSession) ThreadID() string {
+	return s.threadID
+}
+
+// ChannelID returns the channel of the notes' thread
+func (s *NotesSession) ChannelID() string {
+	return s.channelID
+}
+
+// Title returns the title of the meeting
+func (s *NotesSession) Title() string {
+	return s.title
+}
+
+// StartedBy returns who started taking the notes
+func (s *NotesSession) StartedBy() string {
+	return s.startedBy
+}
+
+// StartedAt returns when the notes were started
+func (s *NotesSession) StartedAt() time.Time {
+	return s.startedAt
+}
+
+// ClosedBy returns who closed the notes, empty while they are open
+func (s *NotesSession) ClosedBy() string {
+	return s.closedBy
+}
+
+// ClosedAt returns when the notes were closed, zero while they are open
+func (s *NotesSession) ClosedAt() time.Time {
+	return s.closedAt
+}
+
+// Closed checks if the notes were closed
+func (s *NotesSession) Closed() bool {
+	return !s.closedAt.IsZero()
+}
+
+// Close stops collecting the messages of the thread
+func (s *NotesSession) Close(actor string, at time.Time) error {
+	if s.Closed() {
+		return fmt.Errorf("%w by %s", ErrNotesSessionClosed, s.closedBy)
+	}
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return fmt.Errorf("%w: who closed it is unknown", ErrInvalidNotesSession)
+	}
+	s.closedBy = actor
+	s.closedAt = at.UTC()
+	if s.closedAt.Before(s.startedAt) {
+		s.closedAt = s.startedAt
+	}
+	return nil
+}
+
+// Path returns where the notes are stored, docs/meetings/<yyyy-mm-dd>-<slugified-title>.md
+func (s *NotesSession) Path() string {
+	name := "meeting-" + s.startedAt.Format("150405")
+	if slug := Slugify(s.title); slug != "" {
+		name = slug
+	}
+	return path.Join(docsRoot, meetingsDir, s.startedAt.Format("2006-01-02")+"-"+name+".md")
+}
+
+// Messages returns the messages of the thread posted while the notes were open, the earlier messages of the
+// thread included. Bot commands are left out.
+func (s *NotesSession) Messages(messages []*Message) []*Message {
+	var kept []*Message
+	for _, msg := range messages {
+		if s.Closed() && msg.Timestamp().After(s.closedAt) {
+			continue
+		}
+		if text := msg.Content().Text(); strings.TrimSpace(text) == "" || IsCommand(text) {
+			continue
+		}
+		kept = append(kept, msg)
+	}
+	return kept
+}
+
+// MeetingTranscript renders messages as the lines of a transcript, the input notes are drawn from
+func MeetingTranscript(messages []*Message) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		text := strings.Join(strings.Fields(msg.Content().Text()), " ")
+		b.WriteString(fmt.Sprintf("- **%s** %s: %s\n", msg.Timestamp().UTC().Format("15:04"), msg.Sender(), text))
+	}
+	return b.String()
+}
+
+// MeetingSummary is what the notes of a meeting say, as a model drew it from the transcript
+type MeetingSummary struct {
+	Agenda      []string          `json:"agenda"`
+	Decisions   []MeetingDecision `json:"decisions"`
+	ActionItems []ActionItem      `json:"action_items"`
+}
+
+// MeetingDecision is a decision taken in a meeting
+type MeetingDecision struct {
+	Title    string `json:"title"`
+	Context  string `json:"context"`
+	Decision string `json:"decision"`
+	Category string `json:"category"`
+}
+
+// ActionItem is a task someone took on in a meeting
+type ActionItem struct {
+	Task  string `json:"task"`
+	Owner string `json:"owner"`
+	Due   string `json:"due"`
+}
+
+// MeetingNotes are the structured notes of a closed notes session: who attended, the agenda, the decisions
+// and the action items
+type MeetingNotes struct {
+	session    *NotesSession
+	attendees  []string
+	summary    MeetingSummary
+	transcript string
+}
+
+// NewMeetingNotes creates the notes of a session from the messages of its thread and the summary drawn from
+// them. Without a summary the notes keep the transcript. Decisions and action items without text are dropped.
+func NewMeetingNotes(session *NotesSession, messages []*Message, summary *MeetingSummary) *MeetingNotes {
+	messages = session.Messages(messages)
+	notes := &MeetingNotes{session: session, attendees: []string{session.startedBy}}
+	seen := map[string]bool{session.startedBy: true}
+	for _, msg := range messages {
+		if !seen[msg.Sender()] {
+			seen[msg.Sender()] = true
+			notes.attendees = append(notes.attendees, msg.Sender())
+		}
+	}
+
+	if summary == nil {
+		notes.transcript = MeetingTranscript(messages)
+		return notes
+	}
+	for _, item := range summary.Agenda {
+		if item = strings.TrimSpace(item); item != "" {
+			notes.summary.Agenda = append(notes.summary.Agenda, item)
+		}
+	}
+	for _, decision := range summary.Decisions {
+		decision.Title = strings.TrimSpace(decision.Title)
+		decision.Decision = strings.TrimSpace(decision.Decision)
+		if decision.Title == "" || decision.Decision == "" {
+			continue
+		}
+		decision.Context = strings.TrimSpace(decision.Context)
+		notes.summary.Decisions = append(notes.summary.Decisions, decision)
+	}
+	for _, item := range summary.ActionItems {
+		item.Task = strings.TrimSpace(item.Task)
+		if item.Task == "" {
+			continue
+		}
+		item.Owner = strings.TrimSpace(item.Owner)
+		item.Due = strings.TrimSpace(item.Due)
+		notes.summary.ActionItems = append(notes.summary.ActionItems, item)
+	}
+	return notes
+}
+
+// Session returns the session the notes were taken in
+func (n *MeetingNotes) Session() *NotesSession {
+	return n.session
+}
+
+// Attendees returns who took part in the thread, the person who started the notes first
+func (n *MeetingNotes) Attendees() []string {
+	attendees := make([]string, len(n.attendees))
+	copy(attendees, n.attendees)
+	return attendees
+}
+
+// Agenda returns the topics discussed
+func (n *MeetingNotes) Agenda() []string {
+	agenda := make([]string, len(n.summary.Agenda))
+	copy(agenda, n.summary.Agenda)
+	return agenda
+}
+
+// Decisions returns the decisions taken
+func (n *MeetingNotes) Decisions() []MeetingDecision {
+	decisions := make([]MeetingDecision, len(n.summary.Decisions))
+	copy(decisions, n.summary.Decisions)
+	return decisions
+}
+
+// ActionItems returns the tasks people took on
+func (n *MeetingNotes) ActionItems() []ActionItem {
+	items := make([]ActionItem, len(n.summary.ActionItems))
+	copy(items, n.summary.ActionItems)
+	return items
+}
+
+// DecisionRecords returns a decision record for each decision, to be stored next to the notes at notesPath
+func (n *MeetingNotes) DecisionRecords(notesPath string) []*DecisionRecord {
+	records := make([]*DecisionRecord, 0, len(n.summary.Decisions))
+	for _, decision := range n.summary.Decisions {
+		records = append(records, &DecisionRecord{decision: decision, meeting: n.session, notesPath: notesPath})
+	}
+	return records
+}
+
+// Render renders the notes stored at notesPath, linking each decision to its record at the path of the same
+// index in recordPaths. Decisions without a record are listed with their text.
+func (n *MeetingNotes) Render(notesPath string, recordPaths []string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n\n", n.session.title))
+	b.WriteString(fmt.Sprintf("Notes taken by %s on %s", n.session.startedBy, n.session.startedAt.Format("2006-01-02 15:04 UTC")))
+	if n.session.Closed() {
+		b.WriteString(fmt.Sprintf(", closed by %s at %s", n.session.closedBy, n.session.closedAt.Format("15:04 UTC")))
+	}
+	b.WriteString(".\n\n## Attendees\n\n")
+	for _, attendee := range n.attendees {
+		b.WriteString(fmt.Sprintf("- %s\n", attendee))
+	}
+
+	b.WriteString("\n## Agenda\n\n")
+	for i, item := range n.summary.Agenda {
+		b.WriteString(fmt.Sprintf("%d. %s\n", i+1, item))
+	}
+	if len(n.summary.Agenda) == 0 {
+		b.WriteString("_No agenda recorded._\n")
+	}
+
+	b.WriteString("\n## Decisions\n\n")
+	for i, decision := range n.summary.Decisions {
+		title := decision.Title
+		if i < len(recordPaths) && recordPaths[i] != "" {
+			if link, err := filepath.Rel(path.Dir(notesPath), recordPaths[i]); err == nil {
+				title = fmt.Sprintf("[%s](%s)", decision.Title, filepath.ToSlash(link))
+			}
+		}
+		b.WriteString(fmt.Sprintf("- **%s**: %s\n", title, decision.Decision))
+	}
+	if len(n.summary.Decisions) == 0 {
+		b.WriteString("_No decisions recorded._\n")
+	}
+
+	b.WriteString("\n## Action items\n\n")
+	for _, item := range n.summary.ActionItems {
+		b.WriteString("- [ ] ")
+		if item.Owner != "" {
+			b.WriteString(fmt.Sprintf("**%s**: ", item.Owner))
+		}
+		b.WriteString(item.Task)
+		if item.Due != "" {
+			b.WriteString(fmt.Sprintf(" (due %s)", item.Due))
+		}
+		b.WriteString("\n")
+	}
+	if len(n.summary.ActionItems) == 0 {
+		b.WriteString("_No action items recorded._\n")
+	}
+
+	if n.transcript != "" {
+		b.WriteString("\n## Discussion\n\n")
+		b.WriteString(n.transcript)
+	}
+	return b.String()
+}
+
+// MeetingNotesPersonKeys returns the front matter keys of meeting notes naming people
+func MeetingNotesPersonKeys() []string {
+	return []string{"attendees"}
+}
+
+// MeetingNotesBodyNames returns where meeting notes name people in their body, for ErasureRequest.ApplyToBody:
+// who took and closed the notes, the attendees and the speakers of the discussion
+func MeetingNotesBodyNames() []string {
+	return []string{"Notes taken by %s on ", ", closed by %s at ", "- %s\n", "** %s: "}
+}
+
+// Record adds the meeting to the front matter of its notes
+func (n *MeetingNotes) Record(fm *FrontMatter) {
+	fm.Set("type", "meeting_notes")
+	fm.Set("created_at", n.session.closedAt.Format(time.RFC3339))
+	fm.Set("thread", n.session.threadID)
+	fm.Set("meeting_started_at", n.session.startedAt.Format(time.RFC3339))
+	fm.SetList("attendees", n.attendees)
+	fm.SetList("tags", []string{"meeting-notes"})
+}
+
+// DecisionRecord is the architecture decision record of a decision taken in a meeting, documented as a
+// decision of its own next to the notes
+type DecisionRecord struct {
+	decision  MeetingDecision
+	meeting   *NotesSession
+	notesPath string
+}
+
+// Title returns the title of the decision
+func (r *DecisionRecord) Title() string {
+	return r.decision.Title
+}
+
+// Category returns the category of the decision, CategoryOther when the summary did not tell a valid one
+func (r *DecisionRecord) Category() Category {
+	category, err := NewCategory(r.decision.Category)
+	if err != nil || category.IsUnknown() {
+		return CategoryOther
+	}
+	return category
+}
+
+// Text returns the decision as one line of text, the content of the message it is documented from
+func (r *DecisionRecord) Text() string {
+	return r.decision.Title + ": " + r.decision.Decision
+}
+
+// Document renders the record with the status, context and decision sections of an ADR
+func (r *DecisionRecord) Document() string {
+	return "# " + r.decision.Title + "\n\n" + r.body()
+}
+
+// RollupEntry renders the record as an entry of a rollup
+func (r *DecisionRecord) RollupEntry() string {
+	return "**" + r.decision.Title + "**\n\n" + r.body()
+}
+
+func (r *DecisionRecord) body() string {
+	context := r.decision.Context
+	if context == "" {
+		context = "_Not recorded in the meeting notes._"
+	}
+	return fmt.Sprintf("## Status\n\nAccepted\n\n## Context\n\n%s\n\n## Decision\n\n%s\n\nDecided in the meeting *%s* on %s.\n",
+		context, r.decision.Decision, r.meeting.title, r.meeting.startedAt.Format("2006-01-02"))
+}
+
+// Record adds the meeting the decision was taken in to the front matter of its record
+func (r *DecisionRecord) Record(fm *FrontMatter) {
+	fm.Set("status", DocumentStatusActive.String())
+	fm.Set("meeting_notes", r.notesPath)
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotesSession(t *testing.T) {
+	startedAt := time.Date(2024, 6, 10, 14, 2, 0, 0, time.UTC)
+
+	session, err := NewNotesSession("T1", "C1", " Billing sync ", "alice", startedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "Billing sync", session.Title())
+	assert.Equal(t, "docs/meetings/2024-06-10-billing-sync.md", session.Path())
+	assert.False(t, session.Closed())
+
+	untitled, err := NewNotesSession("T1", "C1", "", "alice", startedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "Meeting of 2024-06-10 14:02 UTC", untitled.Title())
+
+	require.NoError(t, session.Close("bob", startedAt.Add(30*time.Minute)))
+	assert.True(t, session.Closed())
+	assert.Equal(t, "bob", session.ClosedBy())
+	assert.ErrorIs(t, session.Close("carol", startedAt.Add(time.Hour)), ErrNotesSessionClosed)
+
+	for _, tt := range []struct{ threadID, startedBy string }{
+		{threadID: "", startedBy: "alice"},
+		{threadID: "T1", startedBy: " "},
+	} {
+		_, err := NewNotesSession(tt.threadID, "C1", "Sync", tt.startedBy, startedAt)
+		assert.ErrorIs(t, err, ErrInvalidNotesSession, tt)
+	}
+}
+
+func TestNewMeetingNotes(t *testing.T) {
+	session, err := NewNotesSession("T1", "C1", "Billing sync", "alice", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	message := func(sender, text string) *Message {
+		msg, err := NewMessage(common.GenerateID(), sender, MustNewMessageContent(text), MessageTypeUnknown, CategoryUnknown, nil)
+		require.NoError(t, err)
+		return msg
+	}
+	messages := []*Message{
+		message("bob", "Invoices are late because of the PDF renderer"),
+		message("carol", "Let's move rendering to a queue"),
+	}
+	require.NoError(t, session.Close("alice", time.Now()))
+	time.Sleep(time.Millisecond)
+	messages = append(messages, message("dave", "Sorry I missed it"))
+
+	notes := NewMeetingNotes(session, messages, &MeetingSummary{
+		Agenda: []string{"Late invoices", " "},
+		Decisions: []MeetingDecision{
+			{Title: "Render invoices in a queue", Context: "Invoices are late", Decision: "Move PDF rendering to a worker queue", Category: "development"},
+			{Title: "", Decision: "Dropped"},
+		},
+		ActionItems: []ActionItem{{Task: "Set up the queue", Owner: "carol", Due: "Friday"}, {Task: " "}},
+	})
+	assert.Equal(t, []string{"alice", "bob", "carol"}, notes.Attendees())
+	assert.Equal(t, []string{"Late invoices"}, notes.Agenda())
+	require.Len(t, notes.Decisions(), 1)
+	require.Len(t, notes.ActionItems(), 1)
+
+	rendered := notes.Render(session.Path(), []string{"docs/development/2024-06-10-render-invoices-in-a-queue.md"})
+	assert.True(t, strings.HasPrefix(rendered, "# Billing sync\n\nNotes taken by alice on "))
+	assert.Contains(t, rendered, "## Attendees\n\n- alice\n- bob\n- carol\n")
+	assert.Contains(t, rendered, "## Agenda\n\n1. Late invoices\n")
+	assert.Contains(t, rendered, "- **[Render invoices in a queue](../development/2024-06-10-render-invoices-in-a-queue.md)**: Move PDF rendering to a worker queue\n")
+	assert.Contains(t, rendered, "## Action items\n\n- [ ] **carol**: Set up the queue (due Friday)\n")
+	assert.NotContains(t, rendered, "## Discussion")
+
+	records := notes.DecisionRecords(session.Path())
+	require.Len(t, records, 1)
+	assert.Equal(t, CategoryDevelopment, records[0].Category())
+	assert.Equal(t, "Render invoices in a queue: Move PDF rendering to a worker queue", records[0].Text())
+	assert.Contains(t, records[0].Document(), "# Render invoices in a queue\n\n## Status\n\nAccepted\n\n## Context\n\nInvoices are late\n\n## Decision\n\nMove PDF rendering to a worker queue\n")
+	fm := NewFrontMatter()
+	records[0].Record(fm)
+	assert.Equal(t, "docs/meetings/"+session.StartedAt().Format("2006-01-02")+"-billing-sync.md", fm.Get("meeting_notes"))
+}
+
+func TestNewMeetingNotes_WithoutSummary(t *testing.T) {
+	session, err := NewNotesSession("T1", "C1", "Billing sync", "alice", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	msg, err := NewMessage(common.GenerateID(), "bob", MustNewMessageContent("Invoices are\nlate"), MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+
+	notes := NewMeetingNotes(session, []*Message{msg}, nil)
+	rendered := notes.Render(session.Path(), nil)
+	assert.Contains(t, rendered, "_No decisions recorded._\n")
+	assert.Contains(t, rendered, "## Discussion\n\n- **"+msg.Timestamp().UTC().Format("15:04")+"** bob: Invoices are late\n")
+	assert.Empty(t, notes.DecisionRecords(session.Path()))
+
+	fm := NewFrontMatter()
+	notes.Record(fm)
+	assert.Equal(t, []string{"alice", "bob"}, fm.GetList("attendees"))
+	assert.Equal(t, "meeting_notes", fm.Get("type"))
+}
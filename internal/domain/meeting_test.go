@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMeeting(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		id        string
+		title     string
+		end       time.Time
+		wantTitle string
+		wantErr   bool
+	}{
+		{name: "valid meeting", id: "evt-1", title: " Architecture review ", end: start.Add(time.Hour), wantTitle: "Architecture review"},
+		{name: "untitled meeting", id: "evt-1", end: start.Add(time.Hour), wantTitle: "Untitled meeting"},
+		{name: "missing ID", id: " ", title: "Review", end: start.Add(time.Hour), wantErr: true},
+		{name: "ends before it starts", id: "evt-1", title: "Review", end: start.Add(-time.Hour), wantErr: true},
+		{name: "no duration", id: "evt-1", title: "Review", end: start, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meeting, err := NewMeeting(tt.id, tt.title, start, tt.end, "")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidMeeting)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, meeting.Title())
+		})
+	}
+}
+
+func TestMeeting_String(t *testing.T) {
+	start := time.Date(2025, 6, 2, 16, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	meeting, err := NewMeeting("evt-1", "Architecture review", start, start.Add(time.Hour), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Architecture review (2025-06-02 14:00 UTC)", meeting.String())
+}
+
+func TestOriginatingMeeting(t *testing.T) {
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	meeting := func(id string, from, to time.Time) *Meeting {
+		m, err := NewMeeting(id, id, from, to, "")
+		require.NoError(t, err)
+		return m
+	}
+
+	standup := meeting("standup", at(9, 0), at(9, 15))
+	review := meeting("review", at(14, 0), at(15, 0))
+	allHands := meeting("all-hands", at(14, 0), at(16, 0))
+	retro := meeting("retro", at(15, 0), at(16, 0))
+	meetings := []*Meeting{standup, review, allHands, retro}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     *Meeting
+	}{
+		{name: "single message during a meeting", from: at(9, 5), to: at(9, 5), want: standup},
+		{name: "message when the meeting ends", from: at(9, 15), to: at(9, 15), want: nil},
+		{name: "thread between meetings", from: at(11, 0), to: at(12, 0), want: nil},
+		{name: "longest overlap wins", from: at(14, 50), to: at(15, 40), want: allHands},
+		{name: "equal overlap picks the shorter meeting", from: at(14, 30), to: at(14, 45), want: review},
+		{name: "thread reaching into the next meeting", from: at(14, 10), to: at(15, 5), want: allHands},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OriginatingMeeting(meetings, tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidMermaid = errors.New("invalid Mermaid diagram")
+
+var (
+	// flowchartLinkPattern matches the links between flowchart nodes, like -->, ---, -.->, ==> and <-->
+	flowchartLinkPattern = regexp.MustCompile(`^(?:<|o|x)?(?:-{2,}|={2,}|-\.+-|~{3,})(?:>|o|x)?`)
+	// flowchartTextLinkPattern matches links with their label inline, like -- yes --> or -. maybe .->
+	flowchartTextLinkPattern = regexp.MustCompile(`^(?:--|==|-\.)\s+[^|]+?\s+(?:-{2,}>|-{3,}|={2,}>|={3,}|\.+->|\.+-)`)
+
+	sequenceParticipantPattern = regexp.MustCompile(`^(?:create\s+)?(?:participant|actor)\s+[^\s:]+(?:\s+as\s+\S.*)?$`)
+	sequenceNotePattern        = regexp.MustCompile(`^[Nn]ote\s+(?:left of|right of|over)\s+[^:,]+(?:,\s*[^:,]+)?\s*:.*$`)
+	sequenceMessagePattern     = regexp.MustCompile(`^[^-+>:;,]+?\s*(?:-->>|->>|-->|->|--x|-x|--\)|-\))\s*[+-]?\s*[^-+>:;,]+?\s*:.*$`)
+	sequenceActivationPattern  = regexp.MustCompile(`^(?:activate|deactivate|destroy)\s+\S+$`)
+)
+
+// flowchartShapes maps the openings of flowchart node shapes to the closings they accept, longest first
+var flowchartShapes = []struct {
+	open  string
+	close []string
+}{
+	{"(((", []string{")))"}},
+	{"((", []string{"))"}},
+	{"([", []string{"])"}},
+	{"[[", []string{"]]"}},
+	{"[(", []string{")]"}},
+	{"[/", []string{"/]", `\]`}},
+	{`[\`, []string{`\]`, "/]"}},
+	{"{{", []string{"}}"}},
+	{"[", []string{"]"}},
+	{"(", []string{")"}},
+	{"{", []string{"}"}},
+	{">", []string{"]"}},
+}
+
+// ValidateMermaid checks that a flowchart or sequence diagram parses. Other diagram types are rejected,
+// as they cannot be checked.
+func ValidateMermaid(source string) error {
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		header := strings.TrimSpace(line)
+		if header == "" || strings.HasPrefix(header, "%%") {
+			continue
+		}
+
+		fields := strings.Fields(header)
+		switch fields[0] {
+		case "flowchart", "graph":
+			if len(fields) > 2 || (len(fields) == 2 && !isFlowchartDirection(fields[1])) {
+				return mermaidError(i, "unknown flowchart direction %q", strings.Join(fields[1:], " "))
+			}
+			return validateFlowchart(lines, i+1)
+		case "sequenceDiagram":
+			if len(fields) > 1 {
+				return mermaidError(i, "unexpected %q after sequenceDiagram", strings.Join(fields[1:], " "))
+			}
+			return validateSequence(lines, i+1)
+		default:
+			return mermaidError(i, "unsupported diagram type %q, only flowcharts and sequence diagrams are checked", fields[0])
+		}
+	}
+	return fmt.Errorf("%w: the diagram is empty", ErrInvalidMermaid)
+}
+
+// DropInvalidMermaid removes the Mermaid code blocks of a Markdown document that do not parse, so a broken
+// diagram never reaches the repository. It returns the document and the reasons the blocks were dropped.
+func DropInvalidMermaid(markdown string) (string, []error) {
+	lines := strings.Split(markdown, "\n")
+	kept := make([]string, 0, len(lines))
+	var dropped []error
+
+	for i := 0; i < len(lines); i++ {
+		fence, ok := mermaidFence(lines[i])
+		if !ok {
+			kept = append(kept, lines[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != fence {
+			end++
+		}
+		if end == len(lines) {
+			dropped = append(dropped, fmt.Errorf("%w: the code block is not closed", ErrInvalidMermaid))
+			break
+		}
+
+		if err := ValidateMermaid(strings.Join(lines[i+1:end], "\n")); err != nil {
+			dropped = append(dropped, err)
+			// Do not leave the blank line the block was separated by twice
+			if len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" && end+1 < len(lines) && strings.TrimSpace(lines[end+1]) == "" {
+				end++
+			}
+		} else {
+			kept = append(kept, lines[i:end+1]...)
+		}
+		i = end
+	}
+	return strings.Join(kept, "\n"), dropped
+}
+
+// mermaidFence returns the fence closing the Mermaid code block a line opens
+func mermaidFence(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimPrefix(trimmed, fence)) == "mermaid" {
+			return fence, true
+		}
+	}
+	return "", false
+}
+
+func validateFlowchart(lines []string, start int) error {
+	subgraphs := 0
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSuffix(strings.TrimSpace(lines[i]), ";")
+		if line == "" || strings.HasPrefix(line, "%%") {
+			continue
+		}
+
+		keyword := strings.Fields(line)[0]
+		switch keyword {
+		case "subgraph":
+			if len(strings.Fields(line)) < 2 {
+				return mermaidError(i, "subgraph needs a name")
+			}
+			subgraphs++
+			continue
+		case "end":
+			if line != "end" {
+				break
+			}
+			if subgraphs == 0 {
+				return mermaidError(i, "end without subgraph")
+			}
+			subgraphs--
+			continue
+		case "direction":
+			if len(strings.Fields(line)) != 2 || !isFlowchartDirection(strings.Fields(line)[1]) {
+				return mermaidError(i, "unknown direction")
+			}
+			continue
+		case "classDef", "class", "style", "linkStyle", "click":
+			continue
+		}
+
+		if err := parseFlowchartStatement(line); err != nil {
+			return mermaidError(i, "%v", err)
+		}
+	}
+	if subgraphs > 0 {
+		return mermaidError(len(lines)-1, "subgraph is not closed with end")
+	}
+	return nil
+}
+
+// flowchartParser reads a statement of nodes joined by links and &
+type flowchartParser struct {
+	s   string
+	pos int
+}
+
+func parseFlowchartStatement(statement string) error {
+	p := &flowchartParser{s: statement}
+	if err := p.node(); err != nil {
+		return err
+	}
+	for {
+		p.skipSpace()
+		if p.pos == len(p.s) {
+			return nil
+		}
+		if p.s[p.pos] == '&' {
+			p.pos++
+			p.skipSpace()
+			if err := p.node(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.link(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if err := p.node(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *flowchartParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// node reads a node ID with an optional shape and class, like A, db[(Postgres)] or api["API (v2)"]:::service
+func (p *flowchartParser) node() error {
+	start := p.pos
+	for p.pos < len(p.s) {
+		r := rune(p.s[p.pos])
+		if r >= 0x80 || unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if p.pos == start {
+		return fmt.Errorf("expected a node at %q", p.s[start:])
+	}
+	if id := p.s[start:p.pos]; id == "end" {
+		return fmt.Errorf("end cannot be a node ID")
+	}
+
+	if err := p.shape(); err != nil {
+		return err
+	}
+	if strings.HasPrefix(p.s[p.pos:], ":::") {
+		p.pos += 3
+		start := p.pos
+		for p.pos < len(p.s) && (unicode.IsLetter(rune(p.s[p.pos])) || unicode.IsDigit(rune(p.s[p.pos])) || p.s[p.pos] == '_' || p.s[p.pos] == '-') {
+			p.pos++
+		}
+		if p.pos == start {
+			return fmt.Errorf("expected a class name after :::")
+		}
+	}
+	return nil
+}
+
+// shape reads the optional shape of a node and checks its label. Unquoted labels cannot hold brackets or quotes.
+func (p *flowchartParser) shape() error {
+	rest := p.s[p.pos:]
+	for _, shape := range flowchartShapes {
+		if !strings.HasPrefix(rest, shape.open) {
+			continue
+		}
+		label := rest[len(shape.open):]
+
+		if strings.HasPrefix(strings.TrimSpace(label), `"`) {
+			trimmed := strings.TrimSpace(label)
+			closing := strings.Index(trimmed[1:], `"`)
+			if closing < 0 {
+				return fmt.Errorf("unterminated quote in node label")
+			}
+			after := strings.TrimLeft(trimmed[closing+2:], " ")
+			for _, close := range shape.close {
+				if strings.HasPrefix(after, close) {
+					p.pos = len(p.s) - len(after) + len(close)
+					return nil
+				}
+			}
+			return fmt.Errorf("node label is not closed with %s", shape.close[0])
+		}
+
+		end := -1
+		var close string
+		for _, candidate := range shape.close {
+			if idx := strings.Index(label, candidate); idx >= 0 && (end < 0 || idx < end) {
+				end, close = idx, candidate
+			}
+		}
+		if end < 0 {
+			return fmt.Errorf("node label is not closed with %s", shape.close[0])
+		}
+		if strings.ContainsAny(label[:end], `[]{}()"`) {
+			return fmt.Errorf("node label %q needs quotes around brackets", label[:end])
+		}
+		p.pos += len(shape.open) + end + len(close)
+		return nil
+	}
+	return nil
+}
+
+// link reads a link between nodes with its optional label, like -->|yes| or -- yes -->
+func (p *flowchartParser) link() error {
+	rest := p.s[p.pos:]
+	if m := flowchartTextLinkPattern.FindString(rest); m != "" {
+		p.pos += len(m)
+		return nil
+	}
+
+	m := flowchartLinkPattern.FindString(rest)
+	if m == "" {
+		return fmt.Errorf("expected a link at %q", rest)
+	}
+	p.pos += len(m)
+
+	if p.pos < len(p.s) && p.s[p.pos] == '|' {
+		closing := strings.IndexByte(p.s[p.pos+1:], '|')
+		if closing < 0 {
+			return fmt.Errorf("link label is not closed with |")
+		}
+		p.pos += closing + 2
+	}
+	return nil
+}
+
+func validateSequence(lines []string, start int) error {
+	blocks := 0
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSuffix(strings.TrimSpace(lines[i]), ";")
+		if line == "" || strings.HasPrefix(line, "%%") {
+			continue
+		}
+
+		keyword := strings.Fields(line)[0]
+		switch keyword {
+		case "autonumber", "title":
+			continue
+		case "loop", "alt", "opt", "par", "critical", "break", "rect", "box":
+			blocks++
+			continue
+		case "else", "and", "option":
+			if blocks == 0 {
+				return mermaidError(i, "%s outside of a block", keyword)
+			}
+			continue
+		case "end":
+			if blocks == 0 {
+				return mermaidError(i, "end without a block")
+			}
+			blocks--
+			continue
+		}
+
+		switch {
+		case sequenceParticipantPattern.MatchString(line),
+			sequenceNotePattern.MatchString(line),
+			sequenceActivationPattern.MatchString(line),
+			sequenceMessagePattern.MatchString(line):
+		default:
+			return mermaidError(i, "cannot parse %q", line)
+		}
+	}
+	if blocks > 0 {
+		return mermaidError(len(lines)-1, "block is not closed with end")
+	}
+	return nil
+}
+
+func isFlowchartDirection(direction string) bool {
+	switch direction {
+	case "TB", "TD", "BT", "RL", "LR":
+		return true
+	}
+	return false
+}
+
+func mermaidError(line int, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidMermaid, line+1, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMermaid(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{
+			name: "flowchart",
+			source: `flowchart LR
+    %% request path
+    client[Client] -->|HTTPS| api("API (v2)")
+    api --> db[(Postgres)]
+    api -.-> cache{{Redis}}
+    api -- on failure --> queue>Retry queue]
+    db & cache --> metrics((Metrics)):::muted
+    classDef muted fill:#eee`,
+		},
+		{
+			name: "graph with subgraph",
+			source: `graph TD
+    subgraph Billing
+        direction LR
+        invoice[Invoice] ==> payment[Payment];
+    end
+    payment --> ledger["Ledger (read only)"]`,
+		},
+		{
+			name: "sequence diagram",
+			source: `sequenceDiagram
+    autonumber
+    participant U as User
+    actor Admin
+    U->>+API: POST /orders
+    alt stock available
+        API->>DB: insert order
+        DB-->>API: order id
+    else out of stock
+        API--xU: 409
+    end
+    Note over U,API: retried by the client
+    loop every minute
+        Worker-)API: poll
+    end
+    API-->>-U: 201 Created`,
+		},
+		{name: "empty", source: "\n  \n", wantErr: true},
+		{name: "unsupported type", source: "pie title Pets\n  \"Dogs\" : 3", wantErr: true},
+		{name: "unknown direction", source: "flowchart sideways\n  A --> B", wantErr: true},
+		{name: "unquoted brackets in label", source: "flowchart TD\n  A[Call save()] --> B", wantErr: true},
+		{name: "unclosed shape", source: "flowchart TD\n  A[Start --> B", wantErr: true},
+		{name: "unterminated quote", source: "flowchart TD\n  A[\"Start] --> B", wantErr: true},
+		{name: "missing target", source: "flowchart TD\n  A -->", wantErr: true},
+		{name: "unknown link", source: "flowchart TD\n  A => B", wantErr: true},
+		{name: "unclosed link label", source: "flowchart TD\n  A -->|yes B", wantErr: true},
+		{name: "unclosed subgraph", source: "flowchart TD\n  subgraph API\n  A --> B", wantErr: true},
+		{name: "stray end", source: "flowchart TD\n  A --> B\n  end", wantErr: true},
+		{name: "message without text", source: "sequenceDiagram\n  A->>B", wantErr: true},
+		{name: "unclosed block", source: "sequenceDiagram\n  loop forever\n  A->>B: ping", wantErr: true},
+		{name: "else outside block", source: "sequenceDiagram\n  else\n  A->>B: ping", wantErr: true},
+		{name: "prose", source: "sequenceDiagram\n  the user asks the API", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMermaid(tt.source)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidMermaid)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateMermaid_ReportsLine(t *testing.T) {
+	err := ValidateMermaid("flowchart TD\n  A --> B\n  B --> C[Done (ok)]")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+}
+
+func TestDropInvalidMermaid(t *testing.T) {
+	markdown := "# Decision\n\n```mermaid\nflowchart LR\n  A --> B\n```\n\nText\n\n```mermaid\nflowchart LR\n  A --> B[Call f()]\n```\n\n## Consequences\n\n```go\nA --> B\n```"
+
+	got, dropped := DropInvalidMermaid(markdown)
+	require.Len(t, dropped, 1)
+	assert.ErrorIs(t, dropped[0], ErrInvalidMermaid)
+	assert.Equal(t, "# Decision\n\n```mermaid\nflowchart LR\n  A --> B\n```\n\nText\n\n## Consequences\n\n```go\nA --> B\n```", got)
+
+	got, dropped = DropInvalidMermaid("# Notes\n\n```mermaid\nsequenceDiagram\n  A->>B: hi")
+	require.Len(t, dropped, 1)
+	assert.Equal(t, "# Notes\n", got)
+
+	got, dropped = DropInvalidMermaid("# Notes")
+	assert.Empty(t, dropped)
+	assert.Equal(t, "# Notes", got)
+}
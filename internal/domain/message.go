@@ -7,6 +7,12 @@ import (
 	"github.com/massimo-ua/quill/internal/domain/common"
 )
 
+// AnonymizedSender replaces the sender of anonymized messages
+const AnonymizedSender = "anonymized"
+
+// anonymizedContent replaces the text of anonymized messages
+const anonymizedContent = "[removed by the retention policy]"
+
 var (
 	ErrInvalidSender = errors.New("invalid sender")
 	ErrNoContent     = errors.New("message must have content")
@@ -17,12 +23,29 @@ var (
 type Message struct {
 	id          common.ID
 	threadID    common.ID
+	channelID   string
 	sender      string
 	content     *MessageContent
 	messageType MessageType
 	category    Category
 	references  []*Reference
-	timestamp   time.Time
+	tags        []Tag
+	attachments []*Attachment
+	typeFixed   bool
+	// categoryFixed is set when the category was picked by a person, like when capturing the message
+	categoryFixed bool
+	// promptVersion is the version of the analysis prompt the type and category came from
+	promptVersion string
+	// model is the provider and model that analysed the message, like "ollama:llama3"
+	model      string
+	confidence float64
+	anonymized bool
+	states     []MessageStateChange
+	timestamp  time.Time
+	// sourceTimestamp is the chat's own ID of the message, like the timestamp of a Slack message
+	sourceTimestamp string
+	// correlationID ties together the logs, events and audit entries of processing the message
+	correlationID CorrelationID
 }
 
 // NewMessage creates a new Message instance
@@ -44,6 +67,7 @@ func NewMessage(
 		return nil, ErrNoType
 	}
 
+	now := time.Now()
 	return &Message{
 		id:          common.GenerateID(),
 		threadID:    threadID,
@@ -52,7 +76,8 @@ func NewMessage(
 		messageType: messageType,
 		category:    category,
 		references:  references,
-		timestamp:   time.Now(),
+		states:      []MessageStateChange{{state: MessageStatePending, at: now}},
+		timestamp:   now,
 	}, nil
 }
 
@@ -66,6 +91,44 @@ func (m *Message) ThreadID() common.ID {
 	return m.threadID
 }
 
+// ChannelID returns the chat channel the message was posted in, if known
+func (m *Message) ChannelID() string {
+	return m.channelID
+}
+
+// SetChannelID records the chat channel the message was posted in
+func (m *Message) SetChannelID(channelID string) {
+	m.channelID = channelID
+}
+
+// SourceTimestamp returns the chat's own ID of the message, like the timestamp of a Slack message, empty when
+// the chat provider does not keep it
+func (m *Message) SourceTimestamp() string {
+	return m.sourceTimestamp
+}
+
+// SetSourceTimestamp records the chat's own ID of the message
+func (m *Message) SetSourceTimestamp(ts string) {
+	m.sourceTimestamp = ts
+}
+
+// CorrelationID returns the ID tying together what happened while the message was processed, empty before
+func (m *Message) CorrelationID() CorrelationID {
+	return m.correlationID
+}
+
+// SetCorrelationID records the correlation ID of processing the message
+func (m *Message) SetCorrelationID(id CorrelationID) {
+	m.correlationID = id
+}
+
+// SetTimestamp records when the message was posted in the chat, a zero time keeps the current one
+func (m *Message) SetTimestamp(at time.Time) {
+	if !at.IsZero() {
+		m.timestamp = at
+	}
+}
+
 // Sender returns the message sender
 func (m *Message) Sender() string {
 	return m.sender
@@ -93,6 +156,60 @@ func (m *Message) References() []*Reference {
 	return refs
 }
 
+// Tags returns the message tags
+func (m *Message) Tags() []Tag {
+	tags := make([]Tag, len(m.tags))
+	copy(tags, m.tags)
+	return tags
+}
+
+// AddTags adds tags to the message, ignoring duplicates
+func (m *Message) AddTags(tags ...Tag) {
+	for _, tag := range tags {
+		if tag == "" || m.HasTag(tag) {
+			continue
+		}
+		m.tags = append(m.tags, tag)
+	}
+}
+
+// HasTag checks if the message has the given tag
+func (m *Message) HasTag(tag Tag) bool {
+	for _, t := range m.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Attachments returns the files shared with the message
+func (m *Message) Attachments() []*Attachment {
+	attachments := make([]*Attachment, len(m.attachments))
+	copy(attachments, m.attachments)
+	return attachments
+}
+
+// Images returns the image attachments of the message
+func (m *Message) Images() []*Attachment {
+	var images []*Attachment
+	for _, attachment := range m.attachments {
+		if attachment.IsImage() {
+			images = append(images, attachment)
+		}
+	}
+	return images
+}
+
+// AddAttachments adds files shared with the message, skipping nil entries
+func (m *Message) AddAttachments(attachments ...*Attachment) {
+	for _, attachment := range attachments {
+		if attachment != nil {
+			m.attachments = append(m.attachments, attachment)
+		}
+	}
+}
+
 // Timestamp returns the message timestamp
 func (m *Message) Timestamp() time.Time {
 	return m.timestamp
@@ -105,14 +222,190 @@ func (m *Message) AddReference(ref *Reference) {
 	}
 }
 
+// ReplaceReferences replaces all message references, skipping nil entries
+func (m *Message) ReplaceReferences(refs []*Reference) {
+	m.references = make([]*Reference, 0, len(refs))
+	for _, ref := range refs {
+		m.AddReference(ref)
+	}
+}
+
+// LinkedReferences returns the references of the message that were not left unresolved
+func (m *Message) LinkedReferences() []*Reference {
+	var refs []*Reference
+	for _, ref := range m.references {
+		if !ref.IsUnresolved() {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// UnresolvedReferences returns the references of the message no target matched
+func (m *Message) UnresolvedReferences() []*Reference {
+	var refs []*Reference
+	for _, ref := range m.references {
+		if ref.IsUnresolved() {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
 // HasReferences checks if the message has any references
 func (m *Message) HasReferences() bool {
 	return len(m.references) > 0
 }
 
-// UpdateCategory updates the message category
+// UpdateType updates the message type unless it was fixed by the source
+func (m *Message) UpdateType(messageType MessageType) {
+	if messageType.IsValid() && !m.typeFixed {
+		m.messageType = messageType
+	}
+}
+
+// FixType sets a type that analysis must not override, e.g. for trusted bot notifications
+func (m *Message) FixType(messageType MessageType) {
+	if messageType.IsValid() {
+		m.messageType = messageType
+		m.typeFixed = true
+	}
+}
+
+// HasFixedType checks if the message type was fixed by the source
+func (m *Message) HasFixedType() bool {
+	return m.typeFixed
+}
+
+// PromptVersion returns the version of the analysis prompt the message was analyzed with, if known
+func (m *Message) PromptVersion() string {
+	return m.promptVersion
+}
+
+// RecordPromptVersion records the version of the analysis prompt the message was analyzed with
+func (m *Message) RecordPromptVersion(version string) {
+	m.promptVersion = version
+}
+
+// Model returns the provider and model that analysed the message, empty when it is not known
+func (m *Message) Model() string {
+	return m.model
+}
+
+// RecordModel records the provider and model that analysed the message
+func (m *Message) RecordModel(model string) {
+	m.model = model
+}
+
+// Confidence returns how confident the analysis of the message was, zero when it was not analyzed
+func (m *Message) Confidence() float64 {
+	return m.confidence
+}
+
+// RecordConfidence records how confident the analysis of the message was
+func (m *Message) RecordConfidence(confidence float64) {
+	m.confidence = confidence
+}
+
+// IsAnonymized checks if the raw content of the message was removed
+func (m *Message) IsAnonymized() bool {
+	return m.anonymized
+}
+
+// Anonymize removes the text, sender, attachments and references of the message. Its type, category,
+// analysis and state history are kept for statistics.
+func (m *Message) Anonymize() {
+	m.sender = AnonymizedSender
+	m.content = MustNewMessageContent(anonymizedContent)
+	m.attachments = nil
+	m.references = nil
+	m.anonymized = true
+}
+
+// Pseudonymize replaces the sender of the message with a pseudonym, keeping its content
+func (m *Message) Pseudonymize(pseudonym string) {
+	m.sender = pseudonym
+}
+
+// UpdateCategory updates the message category, unless it was fixed
 func (m *Message) UpdateCategory(category Category) {
+	if category.IsValid() && !m.categoryFixed {
+		m.category = category
+	}
+}
+
+// FixCategory sets a category that analysis must not override, e.g. one picked when capturing the message
+func (m *Message) FixCategory(category Category) {
 	if category.IsValid() {
 		m.category = category
+		m.categoryFixed = true
+	}
+}
+
+// HasFixedCategory checks if the message category was fixed
+func (m *Message) HasFixedCategory() bool {
+	return m.categoryFixed
+}
+
+// State returns the message's processing state
+func (m *Message) State() MessageState {
+	return m.lastStateChange().state
+}
+
+// StateChangedAt returns when the message entered its current state
+func (m *Message) StateChangedAt() time.Time {
+	return m.lastStateChange().at
+}
+
+// StateReason returns why the message entered its current state, e.g. the failure
+func (m *Message) StateReason() string {
+	return m.lastStateChange().reason
+}
+
+// StateHistory returns every state the message went through, oldest first
+func (m *Message) StateHistory() []MessageStateChange {
+	history := make([]MessageStateChange, len(m.states))
+	copy(history, m.states)
+	return history
+}
+
+// TransitionTo moves the message to another processing state
+func (m *Message) TransitionTo(state MessageState, reason string) error {
+	if !state.IsValid() {
+		return ErrInvalidMessageState
+	}
+	if !m.State().CanTransitionTo(state) {
+		return ErrInvalidMessageStateTransition
+	}
+
+	m.states = append(m.states, MessageStateChange{state: state, at: time.Now(), reason: reason})
+	return nil
+}
+
+// StartAnalysis marks the message as being analysed
+func (m *Message) StartAnalysis() error {
+	return m.TransitionTo(MessageStateAnalyzing, "")
+}
+
+// MarkDocumented marks the message as written to the documentation
+func (m *Message) MarkDocumented() error {
+	return m.TransitionTo(MessageStateDocumented, "")
+}
+
+// MarkIgnored marks the message as processed with nothing to document
+func (m *Message) MarkIgnored(reason string) error {
+	return m.TransitionTo(MessageStateIgnored, reason)
+}
+
+// MarkFailed marks the message as failed so it can be retried
+func (m *Message) MarkFailed(reason string) error {
+	return m.TransitionTo(MessageStateFailed, reason)
+}
+
+// lastStateChange returns the current state, messages created outside NewMessage are pending
+func (m *Message) lastStateChange() MessageStateChange {
+	if len(m.states) == 0 {
+		return MessageStateChange{state: MessageStatePending, at: m.timestamp}
 	}
+	return m.states[len(m.states)-1]
 }
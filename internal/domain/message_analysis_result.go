@@ -7,6 +7,7 @@ import (
 var (
 	ErrInvalidConfidenceScore = errors.New("confidence score must be between 0 and 1")
 	ErrInvalidAnalysisResult  = errors.New("invalid analysis result")
+	ErrUnknownPromptVersion   = errors.New("unknown prompt version")
 )
 
 // Analysis represents the raw AI analysis result
@@ -24,6 +25,8 @@ type MessageAnalysisResult struct {
 	references      []*Reference
 	confidenceScore float64
 	suggestedTags   []string
+	promptVersion   string
+	model           string
 }
 
 // NewMessageAnalysisResult creates a new MessageAnalysisResult instance
@@ -80,6 +83,26 @@ func (r *MessageAnalysisResult) SuggestedTags() []string {
 	return tags
 }
 
+// PromptVersion returns the version of the analysis prompt the result was produced with, if known
+func (r *MessageAnalysisResult) PromptVersion() string {
+	return r.promptVersion
+}
+
+// StampPromptVersion records the version of the analysis prompt the result was produced with
+func (r *MessageAnalysisResult) StampPromptVersion(version string) {
+	r.promptVersion = version
+}
+
+// Model returns the provider and model the result was produced by, like "ollama:llama3", if known
+func (r *MessageAnalysisResult) Model() string {
+	return r.model
+}
+
+// StampModel records the provider and model the result was produced by
+func (r *MessageAnalysisResult) StampModel(model string) {
+	r.model = model
+}
+
 // IsHighConfidence checks if the analysis has high confidence (>= 0.8)
 func (r *MessageAnalysisResult) IsHighConfidence() bool {
 	return r.confidenceScore >= 0.8
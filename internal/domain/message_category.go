@@ -40,6 +40,16 @@ var (
 	}
 )
 
+// DocumentCategories lists the categories documentation is filed under
+var DocumentCategories = []Category{
+	CategoryDevelopment,
+	CategoryProduct,
+	CategoryOperations,
+	CategoryQualityAssurance,
+	CategoryDataAnalysis,
+	CategoryOther,
+}
+
 // NewCategory creates a new Category instance from a string
 func NewCategory(c string) (Category, error) {
 	category := Category(strings.ToLower(strings.TrimSpace(c)))
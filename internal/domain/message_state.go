@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidMessageState indicates that the message state is not recognized
+	ErrInvalidMessageState = errors.New("invalid message state")
+	// ErrInvalidMessageStateTransition indicates that the message cannot move to the requested state
+	ErrInvalidMessageStateTransition = errors.New("invalid message state transition")
+)
+
+// MessageState represents where a message is in the documentation pipeline
+type MessageState string
+
+const (
+	// MessageStatePending is a received message that has not been analysed yet
+	MessageStatePending MessageState = "pending"
+	// MessageStateAnalyzing is a message being analysed or waiting for a follow-up before it is documented
+	MessageStateAnalyzing MessageState = "analyzing"
+	// MessageStateDocumented is a message whose content was written to the documentation
+	MessageStateDocumented MessageState = "documented"
+	// MessageStateIgnored is a message that was processed but had nothing to document
+	MessageStateIgnored MessageState = "ignored"
+	// MessageStateFailed is a message whose processing failed and may be retried
+	MessageStateFailed MessageState = "failed"
+)
+
+// messageStateTransitions lists the states each state can move to
+var messageStateTransitions = map[MessageState][]MessageState{
+	MessageStatePending:    {MessageStateAnalyzing, MessageStateIgnored, MessageStateFailed},
+	MessageStateAnalyzing:  {MessageStateDocumented, MessageStateIgnored, MessageStateFailed},
+	MessageStateFailed:     {MessageStateAnalyzing, MessageStateIgnored},
+	MessageStateDocumented: {},
+	MessageStateIgnored:    {},
+}
+
+// MessageStates lists all message states in pipeline order
+var MessageStates = []MessageState{
+	MessageStatePending,
+	MessageStateAnalyzing,
+	MessageStateDocumented,
+	MessageStateIgnored,
+	MessageStateFailed,
+}
+
+// NewMessageState creates a MessageState from a string
+func NewMessageState(state string) (MessageState, error) {
+	s := MessageState(state)
+	if !s.IsValid() {
+		return "", ErrInvalidMessageState
+	}
+	return s, nil
+}
+
+// String returns the string representation of MessageState
+func (s MessageState) String() string {
+	return string(s)
+}
+
+// IsValid checks if the MessageState is valid
+func (s MessageState) IsValid() bool {
+	_, ok := messageStateTransitions[s]
+	return ok
+}
+
+// CanTransitionTo checks if the state can move to the target state
+func (s MessageState) CanTransitionTo(target MessageState) bool {
+	for _, allowed := range messageStateTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal checks if the message has left the pipeline and needs no further processing
+func (s MessageState) IsFinal() bool {
+	return s == MessageStateDocumented || s == MessageStateIgnored
+}
+
+// IsFailed checks if the MessageState is failed
+func (s MessageState) IsFailed() bool {
+	return s == MessageStateFailed
+}
+
+// MessageStateChange records when a message entered a state and why
+type MessageStateChange struct {
+	state  MessageState
+	at     time.Time
+	reason string
+}
+
+// NewMessageStateChange creates a MessageStateChange
+func NewMessageStateChange(state MessageState, at time.Time, reason string) (MessageStateChange, error) {
+	if !state.IsValid() {
+		return MessageStateChange{}, ErrInvalidMessageState
+	}
+	return MessageStateChange{state: state, at: at, reason: reason}, nil
+}
+
+// State returns the state the message entered
+func (c MessageStateChange) State() MessageState {
+	return c.state
+}
+
+// At returns when the message entered the state
+func (c MessageStateChange) At() time.Time {
+	return c.at
+}
+
+// Reason returns why the message entered the state, e.g. the failure
+func (c MessageStateChange) Reason() string {
+	return c.reason
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+func TestMessageState_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from MessageState
+		to   MessageState
+		want bool
+	}{
+		{MessageStatePending, MessageStateAnalyzing, true},
+		{MessageStatePending, MessageStateIgnored, true},
+		{MessageStatePending, MessageStateDocumented, false},
+		{MessageStateAnalyzing, MessageStateDocumented, true},
+		{MessageStateAnalyzing, MessageStateIgnored, true},
+		{MessageStateAnalyzing, MessageStateFailed, true},
+		{MessageStateFailed, MessageStateAnalyzing, true},
+		{MessageStateFailed, MessageStateDocumented, false},
+		{MessageStateDocumented, MessageStateAnalyzing, false},
+		{MessageStateIgnored, MessageStateFailed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("CanTransitionTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMessageState(t *testing.T) {
+	if _, err := NewMessageState("documented"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := NewMessageState("lost"); err != ErrInvalidMessageState {
+		t.Errorf("expected ErrInvalidMessageState, got %v", err)
+	}
+}
+
+func newStateTestMessage(t *testing.T) *Message {
+	t.Helper()
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeUnknown, CategoryUnknown, nil)
+	if err != nil {
+		t.Fatalf("failed to create message: %v", err)
+	}
+	return msg
+}
+
+func TestMessage_StateTransitions(t *testing.T) {
+	msg := newStateTestMessage(t)
+	if msg.State() != MessageStatePending {
+		t.Fatalf("new message state = %s, want pending", msg.State())
+	}
+
+	if err := msg.MarkDocumented(); err != ErrInvalidMessageStateTransition {
+		t.Errorf("MarkDocumented() on pending message error = %v, want ErrInvalidMessageStateTransition", err)
+	}
+	if err := msg.StartAnalysis(); err != nil {
+		t.Fatalf("StartAnalysis() error = %v", err)
+	}
+	if err := msg.MarkFailed("AI agent timed out"); err != nil {
+		t.Fatalf("MarkFailed() error = %v", err)
+	}
+	if msg.StateReason() != "AI agent timed out" {
+		t.Errorf("StateReason() = %q", msg.StateReason())
+	}
+	if err := msg.StartAnalysis(); err != nil {
+		t.Fatalf("retry StartAnalysis() error = %v", err)
+	}
+	if err := msg.MarkDocumented(); err != nil {
+		t.Fatalf("MarkDocumented() error = %v", err)
+	}
+	if err := msg.TransitionTo("lost", ""); err != ErrInvalidMessageState {
+		t.Errorf("TransitionTo(lost) error = %v, want ErrInvalidMessageState", err)
+	}
+
+	history := msg.StateHistory()
+	want := []MessageState{MessageStatePending, MessageStateAnalyzing, MessageStateFailed, MessageStateAnalyzing, MessageStateDocumented}
+	if len(history) != len(want) {
+		t.Fatalf("StateHistory() has %d entries, want %d", len(history), len(want))
+	}
+	for i, change := range history {
+		if change.State() != want[i] {
+			t.Errorf("StateHistory()[%d] = %s, want %s", i, change.State(), want[i])
+		}
+		if i > 0 && change.At().Before(history[i-1].At()) {
+			t.Errorf("StateHistory()[%d] is older than the previous entry", i)
+		}
+	}
+	if !msg.StateChangedAt().Equal(history[len(history)-1].At()) {
+		t.Errorf("StateChangedAt() does not match the last state change")
+	}
+}
+
+func TestNewMessageStats(t *testing.T) {
+	pending := newStateTestMessage(t)
+	analyzing := newStateTestMessage(t)
+	_ = analyzing.StartAnalysis()
+	documented := newStateTestMessage(t)
+	_ = documented.StartAnalysis()
+	_ = documented.MarkDocumented()
+
+	stats := NewMessageStats([]*Message{pending, analyzing, documented, nil}, time.Now().Add(time.Hour), 15*time.Minute)
+
+	if stats.Total() != 3 {
+		t.Errorf("Total() = %d, want 3", stats.Total())
+	}
+	for state, want := range map[MessageState]int{MessageStatePending: 1, MessageStateAnalyzing: 1, MessageStateDocumented: 1, MessageStateFailed: 0} {
+		if got := stats.Count(state); got != want {
+			t.Errorf("Count(%s) = %d, want %d", state, got, want)
+		}
+	}
+
+	stalled := stats.Stalled()
+	if len(stalled) != 2 || stalled[0] != pending || stalled[1] != analyzing {
+		t.Errorf("Stalled() = %v, want the pending and analyzing messages oldest first", stalled)
+	}
+
+	if fresh := NewMessageStats([]*Message{pending}, time.Now(), time.Hour); len(fresh.Stalled()) != 0 {
+		t.Errorf("recent messages should not be stalled")
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// MessageStats summarises where messages are in the documentation pipeline
+type MessageStats struct {
+	counts  map[MessageState]int
+	stalled []*Message
+}
+
+// NewMessageStats counts messages per state. Messages that are not final and have not
+// changed state for longer than stallAfter are reported as stalled, oldest first.
+func NewMessageStats(messages []*Message, now time.Time, stallAfter time.Duration) *MessageStats {
+	stats := &MessageStats{counts: make(map[MessageState]int, len(MessageStates))}
+
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		state := msg.State()
+		stats.counts[state]++
+		if !state.IsFinal() && now.Sub(msg.StateChangedAt()) > stallAfter {
+			stats.stalled = append(stats.stalled, msg)
+		}
+	}
+
+	sort.Slice(stats.stalled, func(i, j int) bool {
+		return stats.stalled[i].StateChangedAt().Before(stats.stalled[j].StateChangedAt())
+	})
+	return stats
+}
+
+// Count returns the number of messages in the given state
+func (s *MessageStats) Count(state MessageState) int {
+	return s.counts[state]
+}
+
+// Total returns the number of tracked messages
+func (s *MessageStats) Total() int {
+	total := 0
+	for _, count := range s.counts {
+		total += count
+	}
+	return total
+}
+
+// Stalled returns the messages that stopped moving through the pipeline, oldest first
+func (s *MessageStats) Stalled() []*Message {
+	stalled := make([]*Message, len(s.stalled))
+	copy(stalled, s.stalled)
+	return stalled
+}
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrInvalidModeration = errors.New("invalid moderation policy")
+
+const (
+	// DefaultModerationBlockScore is the moderator score from which a message is never documented
+	DefaultModerationBlockScore = 0.8
+	// DefaultModerationReviewScore is the moderator score from which a message waits for people to decide
+	DefaultModerationReviewScore = 0.4
+)
+
+// ModerationVerdict is what moderation decided about a message
+type ModerationVerdict string
+
+const (
+	// ModerationAllow documents the message
+	ModerationAllow ModerationVerdict = "allow"
+	// ModerationReview holds the message until people approve or reject it
+	ModerationReview ModerationVerdict = "review"
+	// ModerationBlock never documents the message
+	ModerationBlock ModerationVerdict = "block"
+)
+
+// String returns the verdict
+func (v ModerationVerdict) String() string {
+	return string(v)
+}
+
+// ModerationResult is how a moderator rated content
+type ModerationResult struct {
+	// Flagged tells if the moderator considers the content harmful
+	Flagged bool
+	// Scores rate the content per category, like "harassment", between 0 and 1
+	Scores map[string]float64
+}
+
+// Top returns the category the content scored highest in, empty when nothing was scored
+func (r *ModerationResult) Top() (string, float64) {
+	if r == nil {
+		return "", 0
+	}
+	categories := make([]string, 0, len(r.Scores))
+	for category := range r.Scores {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	top, score := "", 0.0
+	for _, category := range categories {
+		if r.Scores[category] > score {
+			top, score = category, r.Scores[category]
+		}
+	}
+	return top, score
+}
+
+// ModerationDecision is the verdict of moderation on a message, with the reason for it
+type ModerationDecision struct {
+	Verdict ModerationVerdict
+	Reason  string
+}
+
+// ModerationPolicy decides which messages are documented, from local rules and the scores of a moderator.
+// Phrases match whole words, ignoring case.
+type ModerationPolicy struct {
+	// Blocked are offensive words and phrases, messages containing them are never documented
+	Blocked []string `json:"blocked,omitempty"`
+	// OffTopic are phrases of chatter that is clearly not project knowledge, like "happy birthday",
+	// messages containing them are not documented
+	OffTopic []string `json:"offTopic,omitempty"`
+	// Review are phrases that hold messages until people decide, like the names of customers
+	Review []string `json:"review,omitempty"`
+	// BlockScore is the moderator score from which a message is blocked, defaults to 0.8
+	BlockScore float64 `json:"blockScore,omitempty"`
+	// ReviewScore is the moderator score from which a message is held for review, defaults to 0.4.
+	// Messages the moderator flags are held at any score below the block score.
+	ReviewScore float64 `json:"reviewScore,omitempty"`
+}
+
+// Validate ensures the policy is usable
+func (p ModerationPolicy) Validate() error {
+	for _, score := range []float64{p.BlockScore, p.ReviewScore} {
+		if score < 0 || score > 1 {
+			return fmt.Errorf("%w: scores must be between 0 and 1", ErrInvalidModeration)
+		}
+	}
+	if p.reviewScore() > p.blockScore() {
+		return fmt.Errorf("%w: the review score cannot be above the block score", ErrInvalidModeration)
+	}
+	for _, phrases := range [][]string{p.Blocked, p.OffTopic, p.Review} {
+		for _, phrase := range phrases {
+			if strings.TrimSpace(phrase) == "" {
+				return fmt.Errorf("%w: phrases cannot be empty", ErrInvalidModeration)
+			}
+		}
+	}
+	return nil
+}
+
+// Decide returns the verdict on content, given how a moderator rated it. The result is nil when only the
+// local rules apply. Blocking rules win over the moderator, which wins over the review rules.
+func (p ModerationPolicy) Decide(content string, result *ModerationResult) ModerationDecision {
+	if phrase, ok := matchPhrase(content, p.Blocked); ok {
+		return ModerationDecision{Verdict: ModerationBlock, Reason: fmt.Sprintf("contains blocked phrase %q", phrase)}
+	}
+	if phrase, ok := matchPhrase(content, p.OffTopic); ok {
+		return ModerationDecision{Verdict: ModerationBlock, Reason: fmt.Sprintf("off topic, contains %q", phrase)}
+	}
+
+	if category, score := result.Top(); category != "" {
+		switch {
+		case score >= p.blockScore():
+			return ModerationDecision{Verdict: ModerationBlock, Reason: fmt.Sprintf("rated %s (%.2f)", category, score)}
+		case result.Flagged || score >= p.reviewScore():
+			return ModerationDecision{Verdict: ModerationReview, Reason: fmt.Sprintf("rated %s (%.2f)", category, score)}
+		}
+	} else if result != nil && result.Flagged {
+		return ModerationDecision{Verdict: ModerationReview, Reason: "flagged by the moderator"}
+	}
+
+	if phrase, ok := matchPhrase(content, p.Review); ok {
+		return ModerationDecision{Verdict: ModerationReview, Reason: fmt.Sprintf("contains %q", phrase)}
+	}
+	return ModerationDecision{Verdict: ModerationAllow}
+}
+
+func (p ModerationPolicy) blockScore() float64 {
+	if p.BlockScore <= 0 {
+		return DefaultModerationBlockScore
+	}
+	return p.BlockScore
+}
+
+func (p ModerationPolicy) reviewScore() float64 {
+	if p.ReviewScore <= 0 {
+		return DefaultModerationReviewScore
+	}
+	return p.ReviewScore
+}
+
+// matchPhrase returns the first phrase the content contains as whole words, ignoring case
+func matchPhrase(content string, phrases []string) (string, bool) {
+	for _, phrase := range phrases {
+		phrase = strings.TrimSpace(phrase)
+		if phrase == "" {
+			continue
+		}
+		pattern := regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])` + regexp.QuoteMeta(phrase) + `(?:$|[^\p{L}\p{N}])`)
+		if pattern.MatchString(content) {
+			return phrase, true
+		}
+	}
+	return "", false
+}
+
+// HeldMessage is a message moderation holds until people approve or reject it
+type HeldMessage struct {
+	message *Message
+	reason  string
+	heldAt  time.Time
+}
+
+// NewHeldMessage holds a message for the reason moderation gave
+func NewHeldMessage(msg *Message, reason string, heldAt time.Time) (*HeldMessage, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("%w: the message is required", ErrInvalidModeration)
+	}
+	return &HeldMessage{
+		message: msg,
+		reason:  strings.TrimSpace(reason),
+		heldAt:  heldAt.UTC(),
+	}, nil
+}
+
+// Message returns the held message
+func (h *HeldMessage) Message() *Message {
+	return h.message
+}
+
+// Reason returns why moderation held the message
+func (h *HeldMessage) Reason() string {
+	return h.reason
+}
+
+// HeldAt returns when the message was held
+func (h *HeldMessage) HeldAt() time.Time {
+	return h.heldAt
+}
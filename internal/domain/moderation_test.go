@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestModerationPolicy_Decide(t *testing.T) {
+	policy := ModerationPolicy{
+		Blocked:  []string{"idiot"},
+		OffTopic: []string{"happy birthday"},
+		Review:   []string{"Acme"},
+	}
+	rated := func(flagged bool, category string, score float64) *ModerationResult {
+		return &ModerationResult{Flagged: flagged, Scores: map[string]float64{"violence": 0.01, category: score}}
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		result  *ModerationResult
+		want    ModerationVerdict
+		reason  string
+	}{
+		{name: "nothing found", content: "We will use Postgres", want: ModerationAllow},
+		{name: "blocked phrase", content: "Only an IDIOT would use MySQL", want: ModerationBlock, reason: `contains blocked phrase "idiot"`},
+		{name: "phrases match whole words", content: "Idiotproof deploys with Postgres", want: ModerationAllow},
+		{name: "off topic", content: "Happy birthday, Sam!", want: ModerationBlock, reason: `off topic, contains "happy birthday"`},
+		{name: "review phrase", content: "acme wants invoices in Postgres", want: ModerationReview, reason: `contains "Acme"`},
+		{name: "high score", content: "We will use Postgres", result: rated(true, "harassment", 0.93), want: ModerationBlock, reason: "rated harassment (0.93)"},
+		{name: "borderline score", content: "We will use Postgres", result: rated(false, "harassment", 0.45), want: ModerationReview, reason: "rated harassment (0.45)"},
+		{name: "flagged below the review score", content: "We will use Postgres", result: rated(true, "hate", 0.2), want: ModerationReview, reason: "rated hate (0.20)"},
+		{name: "low score", content: "We will use Postgres", result: rated(false, "harassment", 0.1), want: ModerationAllow},
+		{name: "flagged without scores", content: "We will use Postgres", result: &ModerationResult{Flagged: true}, want: ModerationReview, reason: "flagged by the moderator"},
+		{name: "blocked rules win", content: "Acme is run by an idiot", result: rated(false, "harassment", 0.5), want: ModerationBlock, reason: `contains blocked phrase "idiot"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Decide(tt.content, tt.result)
+			if got.Verdict != tt.want || got.Reason != tt.reason {
+				t.Errorf("Decide() = %+v, want %s %q", got, tt.want, tt.reason)
+			}
+		})
+	}
+}
+
+func TestModerationPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ModerationPolicy
+		wantErr error
+	}{
+		{name: "defaults", policy: ModerationPolicy{}},
+		{name: "custom scores", policy: ModerationPolicy{BlockScore: 0.9, ReviewScore: 0.3}},
+		{name: "score above one", policy: ModerationPolicy{BlockScore: 1.2}, wantErr: ErrInvalidModeration},
+		{name: "review above block", policy: ModerationPolicy{BlockScore: 0.5, ReviewScore: 0.6}, wantErr: ErrInvalidModeration},
+		{name: "review above default block", policy: ModerationPolicy{ReviewScore: 0.9}, wantErr: ErrInvalidModeration},
+		{name: "empty phrase", policy: ModerationPolicy{Blocked: []string{" "}}, wantErr: ErrInvalidModeration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewHeldMessage(t *testing.T) {
+	if _, err := NewHeldMessage(nil, "contains \"Acme\"", time.Now()); !errors.Is(err, ErrInvalidModeration) {
+		t.Errorf("NewHeldMessage() error = %v, want %v", err, ErrInvalidModeration)
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RoutingTable sends the capture confirmations and digest items of some categories to other channels than the
+// ones their messages were posted in, like quality assurance captures to the QA channel. It maps categories to
+// channel IDs.
+type RoutingTable map[Category]string
+
+// Validate ensures every route leads to a channel
+func (r RoutingTable) Validate() error {
+	for category, channelID := range r {
+		if !category.IsValid() || category == CategoryUnknown {
+			return fmt.Errorf("%w: route for unknown category %q", ErrInvalidReplyConfig, category)
+		}
+		if channelID = strings.TrimSpace(channelID); channelID == "" || strings.ContainsAny(channelID, " #") {
+			return fmt.Errorf("%w: %s is routed to %q, which is not a channel ID", ErrInvalidReplyConfig, category, r[category])
+		}
+	}
+	return nil
+}
+
+// ChannelFor returns the channel the notifications of a category go to, the fallback when it is not routed
+func (r RoutingTable) ChannelFor(category Category, fallback string) string {
+	if channelID := strings.TrimSpace(r[category]); channelID != "" {
+		return channelID
+	}
+	return fallback
+}
+
+// Routes checks if the notifications of a message go to another channel than the one it was posted in
+func (r RoutingTable) Routes(msg *Message) bool {
+	return r.ChannelFor(msg.Category(), msg.ChannelID()) != msg.ChannelID()
+}
+
+func (r RoutingTable) clone() RoutingTable {
+	if r == nil {
+		return nil
+	}
+	routes := make(RoutingTable, len(r))
+	for category, channelID := range r {
+		routes[category] = channelID
+	}
+	return routes
+}
+
+// RenderRoutedConfirmation tells the channel a capture is routed to what was captured and where it came from
+func RenderRoutedConfirmation(msg *Message, reply string) string {
+	excerpt := strings.Join(strings.Fields(msg.Content().Text()), " ")
+	if runes := []rune(excerpt); len(runes) > maxTriageExcerpt {
+		excerpt = string(runes[:maxTriageExcerpt]) + "…"
+	}
+	return fmt.Sprintf("%s\n> %s\n↪️ Posted by %s in channel %s", reply, excerpt, msg.Sender(), msg.ChannelID())
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutingTable_ChannelFor(t *testing.T) {
+	routes := RoutingTable{CategoryQualityAssurance: "C0QA"}
+
+	assert.Equal(t, "C0QA", routes.ChannelFor(CategoryQualityAssurance, "C0001"))
+	assert.Equal(t, "C0001", routes.ChannelFor(CategoryDevelopment, "C0001"))
+	assert.Equal(t, "C0001", RoutingTable(nil).ChannelFor(CategoryQualityAssurance, "C0001"))
+
+	msg, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("Regression suite is green"), MessageTypeStatus, CategoryQualityAssurance, nil)
+	require.NoError(t, err)
+	msg.SetChannelID("C0001")
+	assert.True(t, routes.Routes(msg))
+	msg.SetChannelID("C0QA")
+	assert.False(t, routes.Routes(msg), "messages posted in the routed channel stay in their thread")
+}
+
+func TestRenderRoutedConfirmation(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("Regression suite\nis green"), MessageTypeStatus, CategoryQualityAssurance, nil)
+	require.NoError(t, err)
+	msg.SetChannelID("C0001")
+
+	assert.Equal(t, "📊 Logged status update\n> Regression suite is green\n↪️ Posted by alice in channel C0001",
+		RenderRoutedConfirmation(msg, "📊 Logged status update"))
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ObsidianVault is the documentation repository laid out as an Obsidian vault, so people can sync the knowledge
+// base into their personal vaults. Notes keep the folders of the docs directory and link to each other with
+// [[wikilinks]].
+type ObsidianVault struct {
+	// Files maps the paths of the notes and attachments, relative to the vault, to their content
+	Files map[string][]byte
+	// Notes is the number of Markdown documents exported as notes
+	Notes int
+	// Attachments is the number of other files exported, like images
+	Attachments int
+	// Unlinked is the number of links to documents left out of the vault, kept as plain text
+	Unlinked int
+}
+
+// Paths returns the paths of the files of the vault in order
+func (v *ObsidianVault) Paths() []string {
+	paths := make([]string, 0, len(v.Files))
+	for p := range v.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ExportObsidianVault converts the files of the documentation repository, keyed by their path in the repository,
+// into an Obsidian vault. Only the docs directory is exported, without the generated tables of contents: Obsidian
+// lists the notes itself. Relative links between documents become [[wikilinks]] and local images become embeds.
+func ExportObsidianVault(files map[string][]byte) *ObsidianVault {
+	vault := &ObsidianVault{Files: make(map[string][]byte)}
+
+	// Collect the headings of every note first, wikilinks name the heading rather than its anchor
+	notes := make(map[string]map[string]string)
+	for docPath, content := range files {
+		if vaultPath(docPath) == "" || !strings.HasSuffix(docPath, ".md") || IsTableOfContents(docPath) {
+			continue
+		}
+		notes[docPath] = noteHeadings(string(content))
+	}
+
+	for docPath, content := range files {
+		notePath := vaultPath(docPath)
+		switch {
+		case notePath == "" || path.Base(notePath) == ".gitkeep":
+			continue
+		case notes[docPath] != nil:
+			vault.Files[notePath] = []byte(vault.note(docPath, string(content), files, notes))
+			vault.Notes++
+		case !strings.HasSuffix(docPath, ".md"):
+			vault.Files[notePath] = content
+			vault.Attachments++
+		}
+	}
+	return vault
+}
+
+// note converts a document into a note: its title becomes an alias, so the note can be found by it, and its
+// links become wikilinks
+func (v *ObsidianVault) note(docPath, content string, files map[string][]byte, notes map[string]map[string]string) string {
+	fm, body, err := ParseFrontMatter(content)
+	if err != nil {
+		fm, body = NewFrontMatter(), content
+	}
+	// The signature covers the document as stored, the note no longer matches it
+	fm.Delete(signatureKey)
+
+	if title := TitleFromMarkdown(body); title != "" {
+		aliases := fm.GetList("aliases")
+		if !containsFold(aliases, title) {
+			fm.SetList("aliases", append(aliases, title))
+		}
+	}
+	if parent := fm.Get("parent"); notes[parent] != nil {
+		fm.Set("parent", "[["+noteName(parent)+"]]")
+	}
+
+	lines := strings.Split(body, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" || isFence(trimmed) {
+			fence = toggleFence(fence, trimmed)
+			continue
+		}
+		lines[i] = linkPattern.ReplaceAllStringFunc(line, func(link string) string {
+			match := linkPattern.FindStringSubmatch(link)
+			return v.wikilink(docPath, link, match[1] == "!", match[2], match[3], files, notes)
+		})
+	}
+	return fm.Apply(strings.Join(lines, "\n"))
+}
+
+// wikilink returns the wikilink or embed replacing a link of a document. External links are kept, links to
+// documents left out of the vault are replaced by their text.
+func (v *ObsidianVault) wikilink(docPath, link string, image bool, text, target string, files map[string][]byte,
+	notes map[string]map[string]string) string {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Scheme != "" || parsed.Host != "" || target == "" {
+		return link
+	}
+
+	linked := docPath
+	if parsed.Path != "" {
+		linked = path.Clean(path.Join(path.Dir(docPath), parsed.Path))
+		if strings.HasPrefix(parsed.Path, "/") {
+			linked = path.Clean(strings.TrimPrefix(parsed.Path, "/"))
+		}
+	}
+
+	if image {
+		if _, ok := files[linked]; !ok || vaultPath(linked) == "" {
+			return link
+		}
+		return "![[" + vaultPath(linked) + "]]"
+	}
+
+	headings, ok := notes[linked]
+	if !ok {
+		if _, exists := files[linked]; exists && vaultPath(linked) != "" && !strings.HasSuffix(linked, ".md") {
+			return "[[" + vaultPath(linked) + "|" + wikilinkText(text) + "]]"
+		}
+		v.Unlinked++
+		return text
+	}
+
+	name := noteName(linked)
+	if linked == docPath {
+		name = ""
+	}
+	if heading, ok := headings[strings.ToLower(parsed.Fragment)]; ok && parsed.Fragment != "" {
+		name += "#" + heading
+	}
+	if name == "" {
+		return text
+	}
+	if text == "" {
+		return "[[" + name + "]]"
+	}
+	return "[[" + name + "|" + wikilinkText(text) + "]]"
+}
+
+// vaultPath returns the path of a file of the docs directory in the vault, empty for files outside it
+func vaultPath(docPath string) string {
+	if !strings.HasPrefix(docPath, docsRoot+"/") {
+		return ""
+	}
+	return strings.TrimPrefix(docPath, docsRoot+"/")
+}
+
+// noteName returns the name wikilinks use for a document, its path in the vault without the extension
+func noteName(docPath string) string {
+	return strings.TrimSuffix(vaultPath(docPath), ".md")
+}
+
+// noteHeadings maps the anchors of the headings of a document to the headings
+func noteHeadings(content string) map[string]string {
+	headings := make(map[string]string)
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" || isFence(trimmed) {
+			fence = toggleFence(fence, trimmed)
+			continue
+		}
+		if match := headingPattern.FindStringSubmatch(trimmed); match != nil {
+			headings[headingAnchor(match[2])] = strings.NewReplacer("[", "", "]", "", "|", "", "#", "", "^", "").Replace(match[2])
+		}
+	}
+	return headings
+}
+
+// wikilinkText keeps the text of a link from closing the wikilink it is placed in
+func wikilinkText(text string) string {
+	return strings.NewReplacer("|", "-", "[", "", "]", "").Replace(text)
+}
+
+// containsFold checks if the values contain the value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
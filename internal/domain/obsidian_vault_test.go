@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportObsidianVault(t *testing.T) {
+	files := map[string][]byte{
+		"README.md":                     []byte("# Team docs\n"),
+		"docs/SUMMARY.md":               []byte("# Summary\n"),
+		"docs/development/INDEX.md":     []byte("# Development\n"),
+		"docs/development/.gitkeep":     nil,
+		"docs/assets/flow.png":          []byte("png"),
+		"docs/development/use-redis.md": []byte("# Use Redis\n\n## Next Steps\n\nBenchmark it.\n"),
+		"docs/development/adopt-postgres.md": []byte("---\n" +
+			"type: \"decision\"\n" +
+			"tags: [\"database\"]\n" +
+			"parent: \"docs/development/use-redis.md\"\n" +
+			"signature: \"hmac-sha256:abc\"\n" +
+			"---\n\n" +
+			"# Adopt Postgres\n\n" +
+			"See [the cache](use-redis.md#next-steps), [Redis](/docs/development/use-redis.md) and " +
+			"[the index](INDEX.md).\n\n" +
+			"![Flow](../assets/flow.png) ![Logo](https://example.com/logo.png) [below](#context) [RFC](https://example.com)\n\n" +
+			"## Context\n\n" +
+			"```markdown\n[Redis](use-redis.md)\n```\n"),
+	}
+
+	vault := ExportObsidianVault(files)
+
+	assert.Equal(t, []string{"assets/flow.png", "development/adopt-postgres.md", "development/use-redis.md"}, vault.Paths())
+	assert.Equal(t, 2, vault.Notes)
+	assert.Equal(t, 1, vault.Attachments)
+	assert.Equal(t, 1, vault.Unlinked)
+	assert.Equal(t, []byte("png"), vault.Files["assets/flow.png"])
+
+	fm, body, err := ParseFrontMatter(string(vault.Files["development/adopt-postgres.md"]))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Adopt Postgres"}, fm.GetList("aliases"))
+	assert.Equal(t, []string{"database"}, fm.GetList("tags"))
+	assert.Equal(t, "[[development/use-redis]]", fm.Get("parent"))
+	assert.False(t, fm.Has("signature"))
+	assert.Contains(t, body, "See [[development/use-redis#Next Steps|the cache]], [[development/use-redis|Redis]] and the index.")
+	assert.Contains(t, body, "![[assets/flow.png]] ![Logo](https://example.com/logo.png) [[#Context|below]] [RFC](https://example.com)")
+	assert.Contains(t, body, "```markdown\n[Redis](use-redis.md)\n```")
+}
+
+func TestExportObsidianVault_KeepsAliases(t *testing.T) {
+	files := map[string][]byte{
+		"docs/other/on-call.md": []byte("---\naliases: [\"Pager\", \"on-call\"]\n---\n\n# On-call\n"),
+	}
+
+	vault := ExportObsidianVault(files)
+
+	fm, _, err := ParseFrontMatter(string(vault.Files["other/on-call.md"]))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Pager", "on-call"}, fm.GetList("aliases"))
+}
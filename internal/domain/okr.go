@@ -0,0 +1,354 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// okrsDir is the directory of docs/ the quarterly OKR progress pages are stored in
+const okrsDir = "okrs"
+
+var (
+	ErrInvalidObjective = errors.New("invalid objective")
+	ErrInvalidQuarter   = errors.New("invalid quarter")
+)
+
+var (
+	// quarterPattern matches quarters like 2024-Q3
+	quarterPattern = regexp.MustCompile(`^(\d{4})-Q([1-4])$`)
+	// keyResultIDPattern matches the IDs status updates refer to key results with, like KR1 or growth-2
+	keyResultIDPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+)
+
+// Objective is a goal of a project for a quarter, measured by its key results. Every objective advances one
+// of the project's business goals.
+type Objective struct {
+	Title string `json:"title"`
+	// Goal is the business goal of the project the objective advances
+	Goal string `json:"goal"`
+	// Quarter is the quarter of the objective, formatted as 2024-Q3
+	Quarter    string      `json:"quarter"`
+	KeyResults []KeyResult `json:"keyResults"`
+}
+
+// KeyResult is a measurable outcome of an objective. Status updates mentioning its ID report its progress,
+// like "KR1 at 45%".
+type KeyResult struct {
+	// ID is unique among the key results of the project's objectives of a quarter
+	ID    string  `json:"id"`
+	Title string  `json:"title"`
+	Start float64 `json:"start,omitempty"`
+	// Target is the value of the key result once achieved, it differs from Start
+	Target float64 `json:"target"`
+	// Unit is shown after the values, like % or ms
+	Unit string `json:"unit,omitempty"`
+}
+
+// QuarterOf returns the quarter of a time, like 2024-Q3
+func QuarterOf(at time.Time) string {
+	at = at.UTC()
+	return fmt.Sprintf("%d-Q%d", at.Year(), (int(at.Month())-1)/3+1)
+}
+
+// ValidateQuarter ensures a quarter is formatted as 2024-Q3
+func ValidateQuarter(quarter string) error {
+	if !quarterPattern.MatchString(quarter) {
+		return fmt.Errorf("%w: %q, quarters are formatted as 2024-Q3", ErrInvalidQuarter, quarter)
+	}
+	return nil
+}
+
+// Validate ensures the objective is usable by a project with the business goals
+func (o Objective) Validate(goals []string) error {
+	if strings.TrimSpace(o.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidObjective)
+	}
+	if err := ValidateQuarter(o.Quarter); err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrInvalidObjective, o.Title, err)
+	}
+	if !containsFold(goals, strings.TrimSpace(o.Goal)) {
+		return fmt.Errorf("%w: %q advances %q, which is not a business goal of the project", ErrInvalidObjective, o.Title, o.Goal)
+	}
+	if len(o.KeyResults) == 0 {
+		return fmt.Errorf("%w: %q has no key results", ErrInvalidObjective, o.Title)
+	}
+	for _, kr := range o.KeyResults {
+		if !keyResultIDPattern.MatchString(kr.ID) {
+			return fmt.Errorf("%w: %q: key result id %q must be letters, digits, dots, dashes and underscores", ErrInvalidObjective, o.Title, kr.ID)
+		}
+		if strings.TrimSpace(kr.Title) == "" {
+			return fmt.Errorf("%w: %q: key result %s has no title", ErrInvalidObjective, o.Title, kr.ID)
+		}
+		if kr.Target == kr.Start {
+			return fmt.Errorf("%w: %q: the target of key result %s must differ from its start", ErrInvalidObjective, o.Title, kr.ID)
+		}
+	}
+	return nil
+}
+
+// Progress returns how far a value of the key result is from its start to its target, between 0 and 1
+func (kr KeyResult) Progress(value float64) float64 {
+	progress := (value - kr.Start) / (kr.Target - kr.Start)
+	return math.Max(0, math.Min(1, progress))
+}
+
+// FormatValue renders a value of the key result with its unit
+func (kr KeyResult) FormatValue(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if kr.Unit == "" {
+		return formatted
+	}
+	if kr.Unit == "%" {
+		return formatted + "%"
+	}
+	return formatted + " " + kr.Unit
+}
+
+// validateObjectives ensures the objectives are usable by a project with the business goals, and that the IDs of
+// their key results are unique within each quarter
+func validateObjectives(objectives []Objective, goals []string) error {
+	seen := make(map[string]string)
+	for _, objective := range objectives {
+		if err := objective.Validate(goals); err != nil {
+			return err
+		}
+		for _, kr := range objective.KeyResults {
+			key := objective.Quarter + "/" + strings.ToLower(kr.ID)
+			if other, ok := seen[key]; ok {
+				return fmt.Errorf("%w: key result %s of %q is also a key result of %q", ErrInvalidObjective, kr.ID, objective.Title, other)
+			}
+			seen[key] = objective.Title
+		}
+	}
+	return nil
+}
+
+// cloneObjectives copies objectives so their key results are not shared
+func cloneObjectives(objectives []Objective) []Objective {
+	if objectives == nil {
+		return nil
+	}
+	cloned := make([]Objective, len(objectives))
+	for i, objective := range objectives {
+		objective.KeyResults = append([]KeyResult(nil), objective.KeyResults...)
+		cloned[i] = objective
+	}
+	return cloned
+}
+
+// KeyResultMention is a key result a status update reports on, with the value it reports when it has one
+type KeyResultMention struct {
+	KeyResultID string
+	Value       float64
+	Measured    bool
+}
+
+// MatchKeyResults finds the key results of the objectives a status update reports on: those whose ID it mentions,
+// with the number following the ID as their value, like "KR1: 45%" or "KR1 is now at 45", and those whose ID
+// it is tagged with
+func MatchKeyResults(text string, tags []string, objectives []Objective) []KeyResultMention {
+	var mentions []KeyResultMention
+	for _, objective := range objectives {
+		for _, kr := range objective.KeyResults {
+			if mention, ok := matchKeyResult(text, tags, kr); ok {
+				mentions = append(mentions, mention)
+			}
+		}
+	}
+	return mentions
+}
+
+// matchKeyResult checks if a status update mentions a key result
+func matchKeyResult(text string, tags []string, kr KeyResult) (KeyResultMention, bool) {
+	id := regexp.QuoteMeta(kr.ID)
+	measured := regexp.MustCompile(`(?i)(?:^|[^\w.-])` + id + `\b(?:[\s:=]|\b(?:is|are|at|now|reached|to|hit)\b)*?(-?\d+(?:\.\d+)?)`)
+	if match := measured.FindStringSubmatch(text); match != nil {
+		if value, err := strconv.ParseFloat(match[1], 64); err == nil {
+			return KeyResultMention{KeyResultID: kr.ID, Value: value, Measured: true}, true
+		}
+	}
+	mentioned := regexp.MustCompile(`(?i)(?:^|[^\w.-])` + id + `(?:$|[^\w-])`)
+	if mentioned.MatchString(text) {
+		return KeyResultMention{KeyResultID: kr.ID}, true
+	}
+	for _, tag := range tags {
+		if strings.EqualFold(tag, kr.ID) || tag == Slugify(kr.ID) {
+			return KeyResultMention{KeyResultID: kr.ID}, true
+		}
+	}
+	return KeyResultMention{}, false
+}
+
+// KeyResultUpdate is the progress a status update reported on a key result
+type KeyResultUpdate struct {
+	ProjectID   common.ID `json:"projectId"`
+	Quarter     string    `json:"quarter"`
+	KeyResultID string    `json:"keyResultId"`
+	Value       float64   `json:"value,omitempty"`
+	// Measured tells if the update reported a value, updates without one only point to the status update
+	Measured  bool      `json:"measured,omitempty"`
+	MessageID string    `json:"messageId"`
+	Sender    string    `json:"sender,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// OKRReport is the progress page of the objectives of a project for a quarter
+type OKRReport struct {
+	projectName string
+	quarter     string
+	objectives  []Objective
+	updates     []KeyResultUpdate
+}
+
+// NewOKRReport creates the progress page of the objectives of a quarter from the updates on their key results
+func NewOKRReport(projectName, quarter string, objectives []Objective, updates []KeyResultUpdate) *OKRReport {
+	var ofQuarter []Objective
+	for _, objective := range objectives {
+		if objective.Quarter == quarter {
+			ofQuarter = append(ofQuarter, objective)
+		}
+	}
+	sorted := append([]KeyResultUpdate(nil), updates...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+	return &OKRReport{
+		projectName: projectName,
+		quarter:     quarter,
+		objectives:  cloneObjectives(ofQuarter),
+		updates:     sorted,
+	}
+}
+
+// Path returns where the page is stored, docs/okrs/<quarter>-<slugified-project>.md
+func (r *OKRReport) Path() string {
+	name := r.quarter
+	if slug := Slugify(r.projectName); slug != "" {
+		name += "-" + slug
+	}
+	return path.Join(docsRoot, okrsDir, name+".md")
+}
+
+// Title returns the title of the page
+func (r *OKRReport) Title() string {
+	return fmt.Sprintf("OKRs of %s, %s", r.projectName, r.quarter)
+}
+
+// updatesOf returns the updates on a key result, oldest first
+func (r *OKRReport) updatesOf(kr KeyResult) []KeyResultUpdate {
+	var updates []KeyResultUpdate
+	for _, update := range r.updates {
+		if strings.EqualFold(update.KeyResultID, kr.ID) {
+			updates = append(updates, update)
+		}
+	}
+	return updates
+}
+
+// Current returns the latest value reported on a key result, false when none was
+func (r *OKRReport) Current(kr KeyResult) (float64, bool) {
+	updates := r.updatesOf(kr)
+	for i := len(updates) - 1; i >= 0; i-- {
+		if updates[i].Measured {
+			return updates[i].Value, true
+		}
+	}
+	return 0, false
+}
+
+// Progress returns the progress of an objective, the average progress of its key results, between 0 and 1.
+// Key results nothing was reported on have made no progress.
+func (r *OKRReport) Progress(objective Objective) float64 {
+	if len(objective.KeyResults) == 0 {
+		return 0
+	}
+	var total float64
+	for _, kr := range objective.KeyResults {
+		if current, ok := r.Current(kr); ok {
+			total += kr.Progress(current)
+		}
+	}
+	return total / float64(len(objective.KeyResults))
+}
+
+// Render renders the page: the progress of each objective and its key results, then the updates on them
+// linking to the status updates they were reported in
+func (r *OKRReport) Render() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n\n", r.Title()))
+	if len(r.objectives) == 0 {
+		b.WriteString("No objectives are set for this quarter.\n")
+		return b.String()
+	}
+	b.WriteString("Progress of the objectives from the status updates mentioning their key results.\n")
+
+	dir := path.Dir(r.Path())
+	for _, objective := range r.objectives {
+		b.WriteString(fmt.Sprintf("\n## %s\n\n", objective.Title))
+		b.WriteString(fmt.Sprintf("Advances the goal \"%s\", %s done.\n\n", objective.Goal, FormatProgress(r.Progress(objective))))
+		b.WriteString("| Key result | Start | Current | Target | Progress |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, kr := range objective.KeyResults {
+			current, progress := "-", FormatProgress(0)
+			if value, ok := r.Current(kr); ok {
+				current, progress = kr.FormatValue(value), FormatProgress(kr.Progress(value))
+			}
+			b.WriteString(fmt.Sprintf("| %s: %s | %s | %s | %s | %s |\n",
+				tableCell(kr.ID), tableCell(kr.Title), kr.FormatValue(kr.Start), current, kr.FormatValue(kr.Target), progress))
+		}
+
+		var lines []string
+		for _, kr := range objective.KeyResults {
+			for _, update := range r.updatesOf(kr) {
+				lines = append(lines, r.renderUpdate(dir, kr, update))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		sort.Strings(lines)
+		b.WriteString("\n### Updates\n\n")
+		for _, line := range lines {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// renderUpdate renders the line of an update, starting with its date so the lines sort oldest first
+func (r *OKRReport) renderUpdate(dir string, kr KeyResult, update KeyResultUpdate) string {
+	line := fmt.Sprintf("- %s %s", update.At.UTC().Format("2006-01-02 15:04"), kr.ID)
+	if update.Measured {
+		line += " at " + kr.FormatValue(update.Value)
+	}
+	if update.Sender != "" {
+		line += " by " + update.Sender
+	}
+	if link, err := filepath.Rel(dir, update.Path); update.Path != "" && err == nil {
+		line += fmt.Sprintf(" ([status update](%s))", filepath.ToSlash(link))
+	}
+	return line + "\n"
+}
+
+// Record adds the page to its front matter
+func (r *OKRReport) Record(fm *FrontMatter, projectID common.ID, at time.Time) {
+	fm.Set("type", MessageTypeStatus.String())
+	fm.Set("updated_at", at.UTC().Format(time.RFC3339))
+	fm.Set("project", projectID.String())
+	fm.Set("quarter", r.quarter)
+	fm.SetList("tags", []string{"okr"})
+}
+
+// FormatProgress renders progress between 0 and 1 as a whole percentage
+func FormatProgress(progress float64) string {
+	return fmt.Sprintf("%d%%", int(math.Round(progress*100)))
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selfServe is an objective of the test project, advancing its "Bill customers" goal
+var selfServe = Objective{
+	Title:   "Make billing self-serve",
+	Goal:    "Bill customers",
+	Quarter: "2024-Q3",
+	KeyResults: []KeyResult{
+		{ID: "KR1", Title: "Self-serve signups", Target: 60, Unit: "%"},
+		{ID: "KR2", Title: "Invoice latency", Start: 800, Target: 200, Unit: "ms"},
+	},
+}
+
+func TestQuarterOf(t *testing.T) {
+	assert.Equal(t, "2024-Q1", QuarterOf(time.Date(2024, time.March, 31, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2024-Q3", QuarterOf(time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2024-Q4", QuarterOf(time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestObjective_Validate(t *testing.T) {
+	goals := []string{"Bill customers"}
+	withKeyResult := func(kr KeyResult) Objective {
+		objective := selfServe
+		objective.KeyResults = []KeyResult{kr}
+		return objective
+	}
+	tests := []struct {
+		name      string
+		objective Objective
+		wantErr   bool
+	}{
+		{name: "valid", objective: selfServe},
+		{name: "goal of another case", objective: Objective{Title: "T", Goal: " bill customers", Quarter: "2024-Q1", KeyResults: selfServe.KeyResults}},
+		{name: "no title", objective: Objective{Goal: "Bill customers", Quarter: "2024-Q1", KeyResults: selfServe.KeyResults}, wantErr: true},
+		{name: "unknown goal", objective: Objective{Title: "T", Goal: "Grow", Quarter: "2024-Q1", KeyResults: selfServe.KeyResults}, wantErr: true},
+		{name: "invalid quarter", objective: Objective{Title: "T", Goal: "Bill customers", Quarter: "Q1 2024", KeyResults: selfServe.KeyResults}, wantErr: true},
+		{name: "no key results", objective: Objective{Title: "T", Goal: "Bill customers", Quarter: "2024-Q1"}, wantErr: true},
+		{name: "key result id with spaces", objective: withKeyResult(KeyResult{ID: "KR 1", Title: "T", Target: 1}), wantErr: true},
+		{name: "key result without title", objective: withKeyResult(KeyResult{ID: "KR1", Target: 1}), wantErr: true},
+		{name: "target equal to start", objective: withKeyResult(KeyResult{ID: "KR1", Title: "T", Start: 5, Target: 5}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.objective.Validate(goals)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidObjective)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProject_SetObjectives(t *testing.T) {
+	project := MustNewProject("Billing", "Billing rewrite", []string{"Bill customers"})
+
+	require.NoError(t, project.SetObjectives([]Objective{selfServe}))
+	assert.Equal(t, []Objective{selfServe}, project.ObjectivesOf("2024-Q3"))
+	assert.Empty(t, project.ObjectivesOf("2024-Q4"))
+
+	// Key result IDs are unique within a quarter only
+	next := selfServe
+	next.Quarter = "2024-Q4"
+	require.NoError(t, project.SetObjectives([]Objective{selfServe, next}))
+	err := project.SetObjectives([]Objective{selfServe, selfServe})
+	assert.ErrorIs(t, err, ErrInvalidObjective)
+
+	// Goals with objectives cannot be dropped
+	err = project.UpdateGoals([]string{"Grow revenue"})
+	assert.ErrorIs(t, err, ErrInvalidObjective)
+	assert.Equal(t, []string{"Bill customers"}, project.Goals())
+}
+
+func TestKeyResult_Progress(t *testing.T) {
+	assert.InDelta(t, 0.75, selfServe.KeyResults[0].Progress(45), 0.001)
+	assert.InDelta(t, 0.5, selfServe.KeyResults[1].Progress(500), 0.001, "decreasing key results progress as they go down")
+	assert.Equal(t, 1.0, selfServe.KeyResults[0].Progress(80))
+	assert.Equal(t, 0.0, selfServe.KeyResults[1].Progress(900))
+}
+
+func TestMatchKeyResults(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		tags []string
+		want []KeyResultMention
+	}{
+		{name: "value after a colon", text: "Weekly update. KR1: 45% of accounts", want: []KeyResultMention{{KeyResultID: "KR1", Value: 45, Measured: true}}},
+		{name: "value after words", text: "kr2 is now at 520 ms", want: []KeyResultMention{{KeyResultID: "KR2", Value: 520, Measured: true}}},
+		{name: "both", text: "KR1 at 30.5, KR2 at 700", want: []KeyResultMention{{KeyResultID: "KR1", Value: 30.5, Measured: true}, {KeyResultID: "KR2", Value: 700, Measured: true}}},
+		{name: "mention without a value", text: "KR1 and KR2 are on track", want: []KeyResultMention{{KeyResultID: "KR1"}, {KeyResultID: "KR2"}}},
+		{name: "tagged", text: "Signups are growing", tags: []string{"kr1"}, want: []KeyResultMention{{KeyResultID: "KR1"}}},
+		{name: "longer id", text: "KR10 at 5"},
+		{name: "unrelated", text: "Deployed v2 at 14:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchKeyResults(tt.text, tt.tags, []Objective{selfServe}))
+		})
+	}
+}
+
+func TestOKRReport_Render(t *testing.T) {
+	projectID := common.GenerateID()
+	at := time.Date(2024, time.July, 8, 10, 0, 0, 0, time.UTC)
+	updates := []KeyResultUpdate{
+		{ProjectID: projectID, Quarter: "2024-Q3", KeyResultID: "KR1", Value: 45, Measured: true, Sender: "bob", Path: "docs/status/development/2024-W29.md", At: at.AddDate(0, 0, 7)},
+		{ProjectID: projectID, Quarter: "2024-Q3", KeyResultID: "KR1", Value: 30, Measured: true, Sender: "alice", Path: "docs/status/development/2024-W28.md", At: at},
+		{ProjectID: projectID, Quarter: "2024-Q3", KeyResultID: "KR2", Sender: "alice", At: at},
+	}
+	later := selfServe
+	later.Quarter = "2024-Q4"
+	later.Title = "Automate dunning"
+
+	report := NewOKRReport("Billing", "2024-Q3", []Objective{selfServe, later}, updates)
+
+	assert.Equal(t, "docs/okrs/2024-Q3-billing.md", report.Path())
+	current, ok := report.Current(selfServe.KeyResults[0])
+	require.True(t, ok)
+	assert.Equal(t, 45.0, current, "the latest value counts")
+	_, ok = report.Current(selfServe.KeyResults[1])
+	assert.False(t, ok)
+	assert.InDelta(t, 0.375, report.Progress(selfServe), 0.001)
+
+	page := report.Render()
+	assert.Contains(t, page, "# OKRs of Billing, 2024-Q3\n")
+	assert.Contains(t, page, "## Make billing self-serve\n\nAdvances the goal \"Bill customers\", 38% done.\n")
+	assert.Contains(t, page, "| KR1: Self-serve signups | 0% | 45% | 60% | 75% |\n")
+	assert.Contains(t, page, "| KR2: Invoice latency | 800 ms | - | 200 ms | 0% |\n")
+	assert.Contains(t, page, "### Updates\n\n"+
+		"- 2024-07-08 10:00 KR1 at 30% by alice ([status update](../status/development/2024-W28.md))\n"+
+		"- 2024-07-08 10:00 KR2 by alice\n"+
+		"- 2024-07-15 10:00 KR1 at 45% by bob ([status update](../status/development/2024-W29.md))\n")
+	assert.NotContains(t, page, "Automate dunning")
+
+	fm := NewFrontMatter()
+	report.Record(fm, projectID, at)
+	assert.Equal(t, "2024-Q3", fm.Get("quarter"))
+	assert.Equal(t, []string{"okr"}, fm.GetList("tags"))
+}
+
+func TestOKRReport_RenderWithoutObjectives(t *testing.T) {
+	page := NewOKRReport("Billing", "2024-Q3", nil, nil).Render()
+
+	assert.Equal(t, "# OKRs of Billing, 2024-Q3\n\nNo objectives are set for this quarter.\n", page)
+}
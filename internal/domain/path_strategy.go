@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// docsRoot is the repository directory generated documentation is stored under
+const docsRoot = "docs"
+
+// maxSlugLength keeps file names derived from titles readable
+const maxSlugLength = 60
+
+// PathScheme names the layout used to store a project's documentation
+type PathScheme string
+
+// Path schemes name documents <yyyy-mm-dd>-<slugified-title>.md, or <type>-<timestamp>.md when the document has no title
+const (
+	// PathSchemeCategory stores documents as docs/<category>/<name>
+	PathSchemeCategory PathScheme = "category"
+	// PathSchemeDate stores documents as docs/<yyyy>/<mm>/<dd>/<name> without repeating the date
+	PathSchemeDate PathScheme = "date"
+	// PathSchemeThread stores documents as docs/threads/<thread>/<name>
+	PathSchemeThread PathScheme = "thread"
+	// PathSchemeTypeFirst stores documents as docs/<type>/<category>/<name>
+	PathSchemeTypeFirst PathScheme = "type"
+	// PathSchemeTitle stores documents as docs/<category>/<slugified-title>.md without a date
+	PathSchemeTitle PathScheme = "title"
+)
+
+var ErrInvalidPathScheme = errors.New("invalid path scheme")
+
+// PathSchemes lists the built-in path schemes
+var PathSchemes = []PathScheme{
+	PathSchemeCategory,
+	PathSchemeDate,
+	PathSchemeThread,
+	PathSchemeTypeFirst,
+	PathSchemeTitle,
+}
+
+// PathStrategy decides where the documentation generated from a message is stored
+type PathStrategy interface {
+	// Path returns the repository path of the document. The title is the one generated for the document and may be empty.
+	Path(msg *Message, title string, at time.Time) string
+}
+
+// PathStrategyFunc adapts a function to the PathStrategy interface
+type PathStrategyFunc func(msg *Message, title string, at time.Time) string
+
+// Path implements the PathStrategy interface
+func (f PathStrategyFunc) Path(msg *Message, title string, at time.Time) string {
+	return f(msg, title, at)
+}
+
+// NewPathScheme creates a PathScheme from a string, an empty string selects the category scheme
+func NewPathScheme(s string) (PathScheme, error) {
+	scheme := PathScheme(strings.ToLower(strings.TrimSpace(s)))
+	if scheme == "" {
+		return PathSchemeCategory, nil
+	}
+	if !scheme.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPathScheme, s)
+	}
+	return scheme, nil
+}
+
+// IsValid checks if the scheme is a built-in one. The empty scheme is the category scheme.
+func (s PathScheme) IsValid() bool {
+	if s == "" {
+		return true
+	}
+	for _, scheme := range PathSchemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the string representation of the scheme
+func (s PathScheme) String() string {
+	return string(s)
+}
+
+// Strategy returns the built-in strategy of the scheme, unknown schemes use the category scheme
+func (s PathScheme) Strategy() PathStrategy {
+	switch s {
+	case PathSchemeDate:
+		return PathStrategyFunc(datePath)
+	case PathSchemeThread:
+		return PathStrategyFunc(threadPath)
+	case PathSchemeTypeFirst:
+		return PathStrategyFunc(typeFirstPath)
+	case PathSchemeTitle:
+		return PathStrategyFunc(titlePath)
+	default:
+		return PathStrategyFunc(categoryPath)
+	}
+}
+
+func categoryPath(msg *Message, title string, at time.Time) string {
+	return path.Join(docsRoot, msg.Category().String(), documentName(msg, title, at))
+}
+
+func datePath(msg *Message, title string, at time.Time) string {
+	at = at.UTC()
+	name := fmt.Sprintf("%s-%s.md", msg.Type().String(), at.Format("150405"))
+	if slug := Slugify(title); slug != "" {
+		name = slug + ".md"
+	}
+	return path.Join(docsRoot, at.Format("2006"), at.Format("01"), at.Format("02"), name)
+}
+
+func threadPath(msg *Message, title string, at time.Time) string {
+	thread := msg.ThreadID().String()
+	if thread == "" {
+		thread = msg.ID().String()
+	}
+	return path.Join(docsRoot, "threads", thread, documentName(msg, title, at))
+}
+
+func typeFirstPath(msg *Message, title string, at time.Time) string {
+	return path.Join(docsRoot, msg.Type().String(), msg.Category().String(), documentName(msg, title, at))
+}
+
+func titlePath(msg *Message, title string, at time.Time) string {
+	slug := Slugify(title)
+	if slug == "" {
+		return categoryPath(msg, title, at)
+	}
+	return path.Join(docsRoot, msg.Category().String(), slug+".md")
+}
+
+// documentName names a document after its date and title, falling back to its type and timestamp
+func documentName(msg *Message, title string, at time.Time) string {
+	at = at.UTC()
+	if slug := Slugify(title); slug != "" {
+		return fmt.Sprintf("%s-%s.md", at.Format("2006-01-02"), slug)
+	}
+	return fmt.Sprintf("%s-%s.md", msg.Type().String(), at.Format("20060102-150405"))
+}
+
+// UniquePath returns the path, or the first free path with a numeric suffix when it is taken
+func UniquePath(docPath string, taken func(string) bool) string {
+	if !taken(docPath) {
+		return docPath
+	}
+
+	ext := path.Ext(docPath)
+	base := strings.TrimSuffix(docPath, ext)
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, n, ext)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// Slugify turns text into a lowercase, dash separated file name fragment
+func Slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimRight(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		// Cut at a word boundary so the name does not end in half a word
+		slug = strings.ToValidUTF8(slug[:maxSlugLength], "")
+		if idx := strings.LastIndex(slug, "-"); idx > 0 {
+			slug = slug[:idx]
+		}
+	}
+	return slug
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+func TestPathScheme_Strategy(t *testing.T) {
+	threadID := common.GenerateID()
+	msg, err := NewMessage(threadID, "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	at := time.Date(2024, 6, 1, 12, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		scheme PathScheme
+		title  string
+		want   string
+	}{
+		{
+			name:   "default",
+			scheme: "",
+			want:   "docs/development/decision-20240601-123045.md",
+		},
+		{
+			name:   "category",
+			scheme: PathSchemeCategory,
+			want:   "docs/development/decision-20240601-123045.md",
+		},
+		{
+			name:   "date",
+			scheme: PathSchemeDate,
+			want:   "docs/2024/06/01/decision-123045.md",
+		},
+		{
+			name:   "thread",
+			scheme: PathSchemeThread,
+			want:   "docs/threads/" + threadID.String() + "/decision-20240601-123045.md",
+		},
+		{
+			name:   "type first",
+			scheme: PathSchemeTypeFirst,
+			want:   "docs/decision/development/decision-20240601-123045.md",
+		},
+		{
+			name:   "title",
+			scheme: PathSchemeTitle,
+			title:  "Adopt Postgres!",
+			want:   "docs/development/adopt-postgres.md",
+		},
+		{
+			name:   "category with title",
+			scheme: PathSchemeCategory,
+			title:  "Adopt Postgres",
+			want:   "docs/development/2024-06-01-adopt-postgres.md",
+		},
+		{
+			name:   "date with title",
+			scheme: PathSchemeDate,
+			title:  "Adopt Postgres",
+			want:   "docs/2024/06/01/adopt-postgres.md",
+		},
+		{
+			name:   "thread with title",
+			scheme: PathSchemeThread,
+			title:  "Adopt Postgres",
+			want:   "docs/threads/" + threadID.String() + "/2024-06-01-adopt-postgres.md",
+		},
+		{
+			name:   "type first with title",
+			scheme: PathSchemeTypeFirst,
+			title:  "Adopt Postgres",
+			want:   "docs/decision/development/2024-06-01-adopt-postgres.md",
+		},
+		{
+			name:   "title falls back to timestamp",
+			scheme: PathSchemeTitle,
+			title:  "  ",
+			want:   "docs/development/decision-20240601-123045.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scheme.Strategy().Path(msg, tt.title, at); got != tt.want {
+				t.Errorf("Path() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniquePath(t *testing.T) {
+	existing := map[string]bool{
+		"docs/development/2024-06-01-adopt-postgres.md":   true,
+		"docs/development/2024-06-01-adopt-postgres-2.md": true,
+	}
+	taken := func(p string) bool { return existing[p] }
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "docs/development/2024-06-01-use-redis.md", want: "docs/development/2024-06-01-use-redis.md"},
+		{path: "docs/development/2024-06-01-adopt-postgres.md", want: "docs/development/2024-06-01-adopt-postgres-3.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := UniquePath(tt.path, taken); got != tt.want {
+				t.Errorf("UniquePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPathScheme(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    PathScheme
+		wantErr error
+	}{
+		{input: "", want: PathSchemeCategory},
+		{input: " Date ", want: PathSchemeDate},
+		{input: "title", want: PathSchemeTitle},
+		{input: "random", wantErr: ErrInvalidPathScheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NewPathScheme(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPathScheme() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewPathScheme() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "Adopt Postgres", want: "adopt-postgres"},
+		{input: "  API v2: rate-limits & quotas  ", want: "api-v2-rate-limits-quotas"},
+		{input: "Café über", want: "café-über"},
+		{input: "---", want: ""},
+		{input: "a very long title that keeps going on and on well past the limit of sixty", want: "a-very-long-title-that-keeps-going-on-and-on-well-past-the"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Slugify(tt.input); got != tt.want {
+				t.Errorf("Slugify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
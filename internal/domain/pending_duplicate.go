@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDuplicateChoiceTTL is how long the author of an idea looking like documented ones has to choose
+// between merging it and documenting it separately
+const DefaultDuplicateChoiceTTL = 24 * time.Hour
+
+var ErrInvalidPendingDuplicate = errors.New("invalid pending duplicate")
+
+// PendingDuplicate is an idea that looked like documents already written, waiting in its thread for its author
+// to merge it into one of the candidates or document it separately
+type PendingDuplicate struct {
+	threadID   string
+	messageID  string
+	author     string
+	candidates []string
+	expiresAt  time.Time
+}
+
+// NewPendingDuplicate creates the choice offered for an idea, the candidates being the paths of the similar
+// documents, the closest first
+func NewPendingDuplicate(threadID, messageID, author string, candidates []string, expiresAt time.Time) (*PendingDuplicate, error) {
+	threadID = strings.TrimSpace(threadID)
+	messageID = strings.TrimSpace(messageID)
+	author = strings.TrimSpace(author)
+	if threadID == "" || messageID == "" {
+		return nil, fmt.Errorf("%w: the thread and the message are required", ErrInvalidPendingDuplicate)
+	}
+	if author == "" {
+		return nil, fmt.Errorf("%w: the author is required", ErrInvalidPendingDuplicate)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: at least one candidate is required", ErrInvalidPendingDuplicate)
+	}
+	for _, candidate := range candidates {
+		if strings.TrimSpace(candidate) == "" {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPendingDuplicate, ErrEmptyDocumentPath)
+		}
+	}
+
+	return &PendingDuplicate{
+		threadID:   threadID,
+		messageID:  messageID,
+		author:     author,
+		candidates: append([]string(nil), candidates...),
+		expiresAt:  expiresAt.UTC(),
+	}, nil
+}
+
+// ThreadID returns the thread the choice is asked in
+func (p *PendingDuplicate) ThreadID() string {
+	return p.threadID
+}
+
+// MessageID returns the idea waiting for the choice
+func (p *PendingDuplicate) MessageID() string {
+	return p.messageID
+}
+
+// Author returns the author of the idea, the only one who can choose
+func (p *PendingDuplicate) Author() string {
+	return p.author
+}
+
+// Candidates returns the paths of the documents the idea can be merged into, the closest first
+func (p *PendingDuplicate) Candidates() []string {
+	return append([]string(nil), p.candidates...)
+}
+
+// Candidate returns the path of the nth candidate counting from 1, the closest one when n is out of range
+func (p *PendingDuplicate) Candidate(n int) string {
+	if n < 1 || n > len(p.candidates) {
+		return p.candidates[0]
+	}
+	return p.candidates[n-1]
+}
+
+// ExpiresAt returns when the choice is no longer waited for
+func (p *PendingDuplicate) ExpiresAt() time.Time {
+	return p.expiresAt
+}
+
+// IsExpired checks if the choice is no longer waited for at the time
+func (p *PendingDuplicate) IsExpired(at time.Time) bool {
+	return !at.Before(p.expiresAt)
+}
+
+// CanChoose checks if the user can make the choice, only the author of the idea can
+func (p *PendingDuplicate) CanChoose(user string) bool {
+	return strings.TrimSpace(user) == p.author
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPendingDuplicate(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		threadID   string
+		messageID  string
+		author     string
+		candidates []string
+		wantErr    bool
+	}{
+		{name: "valid", threadID: "T1", messageID: "M1", author: "alice", candidates: []string{"docs/a.md", "docs/b.md"}},
+		{name: "missing thread", messageID: "M1", author: "alice", candidates: []string{"docs/a.md"}, wantErr: true},
+		{name: "missing message", threadID: "T1", author: "alice", candidates: []string{"docs/a.md"}, wantErr: true},
+		{name: "missing author", threadID: "T1", messageID: "M1", candidates: []string{"docs/a.md"}, wantErr: true},
+		{name: "no candidates", threadID: "T1", messageID: "M1", author: "alice", wantErr: true},
+		{name: "empty candidate", threadID: "T1", messageID: "M1", author: "alice", candidates: []string{" "}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending, err := NewPendingDuplicate(tt.threadID, tt.messageID, tt.author, tt.candidates, expiresAt)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPendingDuplicate)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.candidates, pending.Candidates())
+			assert.Equal(t, expiresAt, pending.ExpiresAt())
+		})
+	}
+}
+
+func TestPendingDuplicate_Choice(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 4, 10, 0, 0, 0, time.UTC)
+	pending, err := NewPendingDuplicate("T1", "M1", "alice", []string{"docs/a.md", "docs/b.md"}, expiresAt)
+	require.NoError(t, err)
+
+	assert.Equal(t, "docs/a.md", pending.Candidate(1))
+	assert.Equal(t, "docs/b.md", pending.Candidate(2))
+	assert.Equal(t, "docs/a.md", pending.Candidate(3), "out of range picks the closest")
+	assert.True(t, pending.CanChoose("alice"))
+	assert.False(t, pending.CanChoose("bob"))
+	assert.False(t, pending.IsExpired(expiresAt.Add(-time.Second)))
+	assert.True(t, pending.IsExpired(expiresAt))
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultUpdateApprovalTTL is how long the author of an idea merged into a document has to apply or discard the
+// diff of the merge
+const DefaultUpdateApprovalTTL = 7 * 24 * time.Hour
+
+var ErrInvalidPendingUpdate = errors.New("invalid pending update")
+
+// PendingUpdate is the merge of an idea into a document, waiting in the idea's thread for its author to apply
+// or discard the diff posted there. It keeps the merged content as it was shown, with the files attached to it.
+type PendingUpdate struct {
+	threadID  string
+	messageID string
+	author    string
+	path      string
+	content   string
+	// baseRevision is the revision of the document the content was merged into, empty when its store does not
+	// version documents
+	baseRevision string
+	attached     map[string][]byte
+	expiresAt    time.Time
+}
+
+// NewPendingUpdate creates the update of the document at the path waiting for the author of the message
+func NewPendingUpdate(
+	threadID, messageID, author, path, content, baseRevision string,
+	attached map[string][]byte,
+	expiresAt time.Time,
+) (*PendingUpdate, error) {
+	threadID = strings.TrimSpace(threadID)
+	messageID = strings.TrimSpace(messageID)
+	author = strings.TrimSpace(author)
+	path = strings.TrimSpace(path)
+	if threadID == "" || messageID == "" {
+		return nil, fmt.Errorf("%w: the thread and the message are required", ErrInvalidPendingUpdate)
+	}
+	if author == "" {
+		return nil, fmt.Errorf("%w: the author is required", ErrInvalidPendingUpdate)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPendingUpdate, ErrEmptyDocumentPath)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("%w: the content is required", ErrInvalidPendingUpdate)
+	}
+
+	files := make(map[string][]byte, len(attached))
+	for name, data := range attached {
+		files[name] = append([]byte(nil), data...)
+	}
+	return &PendingUpdate{
+		threadID:     threadID,
+		messageID:    messageID,
+		author:       author,
+		path:         path,
+		content:      content,
+		baseRevision: strings.TrimSpace(baseRevision),
+		attached:     files,
+		expiresAt:    expiresAt.UTC(),
+	}, nil
+}
+
+// ThreadID returns the thread the diff was posted in
+func (p *PendingUpdate) ThreadID() string {
+	return p.threadID
+}
+
+// MessageID returns the idea merged by the update
+func (p *PendingUpdate) MessageID() string {
+	return p.messageID
+}
+
+// Author returns the author of the idea, the only one who can apply or discard the update
+func (p *PendingUpdate) Author() string {
+	return p.author
+}
+
+// Path returns the path of the updated document
+func (p *PendingUpdate) Path() string {
+	return p.path
+}
+
+// Content returns the content of the document once updated
+func (p *PendingUpdate) Content() string {
+	return p.content
+}
+
+// BaseRevision returns the revision of the document the update was drafted on, empty when unknown
+func (p *PendingUpdate) BaseRevision() string {
+	return p.baseRevision
+}
+
+// Attached returns the files stored with the update, like the images of the idea, by path
+func (p *PendingUpdate) Attached() map[string][]byte {
+	files := make(map[string][]byte, len(p.attached))
+	for name, data := range p.attached {
+		files[name] = append([]byte(nil), data...)
+	}
+	return files
+}
+
+// ExpiresAt returns when the update is no longer waited for
+func (p *PendingUpdate) ExpiresAt() time.Time {
+	return p.expiresAt
+}
+
+// IsExpired checks if the update is no longer waited for at the time
+func (p *PendingUpdate) IsExpired(at time.Time) bool {
+	return !at.Before(p.expiresAt)
+}
+
+// CanDecide checks if the user can apply or discard the update, only the author of the idea can
+func (p *PendingUpdate) CanDecide(user string) bool {
+	return strings.TrimSpace(user) == p.author
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPendingUpdate(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		threadID  string
+		messageID string
+		author    string
+		path      string
+		content   string
+		wantErr   bool
+	}{
+		{name: "valid", threadID: "T1", messageID: "M1", author: "alice", path: "docs/a.md", content: "# A"},
+		{name: "missing thread", messageID: "M1", author: "alice", path: "docs/a.md", content: "# A", wantErr: true},
+		{name: "missing message", threadID: "T1", author: "alice", path: "docs/a.md", content: "# A", wantErr: true},
+		{name: "missing author", threadID: "T1", messageID: "M1", path: "docs/a.md", content: "# A", wantErr: true},
+		{name: "missing path", threadID: "T1", messageID: "M1", author: "alice", path: " ", content: "# A", wantErr: true},
+		{name: "missing content", threadID: "T1", messageID: "M1", author: "alice", path: "docs/a.md", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending, err := NewPendingUpdate(tt.threadID, tt.messageID, tt.author, tt.path, tt.content, "abc123", nil, expiresAt)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPendingUpdate)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.path, pending.Path())
+			assert.Equal(t, tt.content, pending.Content())
+			assert.Equal(t, "abc123", pending.BaseRevision())
+			assert.Empty(t, pending.Attached())
+			assert.Equal(t, expiresAt, pending.ExpiresAt())
+		})
+	}
+}
+
+func TestPendingUpdate_Decision(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 10, 10, 0, 0, 0, time.UTC)
+	attached := map[string][]byte{"docs/assets/diagram.png": []byte("png")}
+	pending, err := NewPendingUpdate("T1", "M1", "alice", "docs/a.md", "# A", "", attached, expiresAt)
+	require.NoError(t, err)
+
+	attached["docs/assets/diagram.png"][0] = 'j'
+	assert.Equal(t, map[string][]byte{"docs/assets/diagram.png": []byte("png")}, pending.Attached(), "the files are copied")
+	assert.True(t, pending.CanDecide(" alice"))
+	assert.False(t, pending.CanDecide("bob"))
+	assert.False(t, pending.IsExpired(expiresAt.Add(-time.Second)))
+	assert.True(t, pending.IsExpired(expiresAt))
+}
@@ -0,0 +1,391 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+var (
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+)
+
+// ProjectDTO is the persistence representation of a Project
+type ProjectDTO struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Description   string              `json:"description"`
+	Goals         []string            `json:"goals"`
+	KPIs          []string            `json:"kpis,omitempty"`
+	Milestones    []MilestoneDTO      `json:"milestones,omitempty"`
+	Channels      []string            `json:"channels,omitempty"`
+	Status        string              `json:"status"`
+	AutoDetection AutoDetectionConfig `json:"autoDetection"`
+	Documentation DocumentationConfig `json:"documentation"`
+	Replies       ReplyConfig         `json:"replies"`
+	Standup       StandupConfig       `json:"standup"`
+	Objectives    []Objective         `json:"objectives,omitempty"`
+	ArchivedAt    time.Time           `json:"archivedAt,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+}
+
+// MilestoneDTO is the persistence representation of a Milestone
+type MilestoneDTO struct {
+	Name     string    `json:"name"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// MessageDTO is the persistence representation of a Message
+type MessageDTO struct {
+	ID            string                   `json:"id"`
+	ThreadID      string                   `json:"threadId,omitempty"`
+	ChannelID     string                   `json:"channelId,omitempty"`
+	SourceTS      string                   `json:"sourceTimestamp,omitempty"`
+	CorrelationID string                   `json:"correlationId,omitempty"`
+	Sender        string                   `json:"sender"`
+	Content       string                   `json:"content"`
+	Type          string                   `json:"type"`
+	TypeFixed     bool                     `json:"typeFixed,omitempty"`
+	PromptVersion string                   `json:"promptVersion,omitempty"`
+	Model         string                   `json:"model,omitempty"`
+	Confidence    float64                  `json:"confidence,omitempty"`
+	Anonymized    bool                     `json:"anonymized,omitempty"`
+	Category      string                   `json:"category"`
+	CategoryFixed bool                     `json:"categoryFixed,omitempty"`
+	References    []string                 `json:"references,omitempty"`
+	Resolutions   []ReferenceResolutionDTO `json:"referenceResolutions,omitempty"`
+	Tags          []string                 `json:"tags,omitempty"`
+	Attachments   []AttachmentDTO          `json:"attachments,omitempty"`
+	States        []MessageStateChangeDTO  `json:"states,omitempty"`
+	Timestamp     time.Time                `json:"timestamp"`
+}
+
+// ReferenceResolutionDTO is the persistence representation of how a reference of a message was matched
+type ReferenceResolutionDTO struct {
+	Reference  string  `json:"reference"`
+	Resolution string  `json:"resolution"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// AttachmentDTO is the persistence representation of an Attachment
+type AttachmentDTO struct {
+	Name     string `json:"name"`
+	MimeType string `json:"mimeType"`
+	URL      string `json:"url"`
+}
+
+// MessageStateChangeDTO is the persistence representation of a MessageStateChange
+type MessageStateChangeDTO struct {
+	State  string    `json:"state"`
+	At     time.Time `json:"at"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// QueuedMessageDTO is the wire representation of a QueuedMessage
+type QueuedMessageDTO struct {
+	Message MessageDTO        `json:"message"`
+	Route   map[string]string `json:"route,omitempty"`
+	Retries int               `json:"retries,omitempty"`
+}
+
+// ApprovalRequestDTO is the persistence representation of an ApprovalRequest
+type ApprovalRequestDTO struct {
+	Message     MessageDTO          `json:"message"`
+	Policies    []ApprovalPolicy    `json:"policies"`
+	Roles       map[string][]string `json:"roles,omitempty"`
+	Approvers   []string            `json:"approvers,omitempty"`
+	RequestedAt time.Time           `json:"requestedAt"`
+}
+
+// ToDTO converts the project into its persistence representation
+func (p *Project) ToDTO() ProjectDTO {
+	milestones := make([]MilestoneDTO, 0, len(p.milestones))
+	for _, m := range p.milestones {
+		milestones = append(milestones, MilestoneDTO{Name: m.name, Deadline: m.deadline})
+	}
+
+	return ProjectDTO{
+		ID:            p.id.String(),
+		Name:          p.name,
+		Description:   p.description,
+		Goals:         p.Goals(),
+		KPIs:          p.KPIs(),
+		Milestones:    milestones,
+		Channels:      p.Channels(),
+		Status:        p.status.String(),
+		AutoDetection: p.AutoDetection(),
+		Documentation: p.Documentation(),
+		Replies:       p.Replies(),
+		Standup:       p.Standup(),
+		Objectives:    p.Objectives(),
+		ArchivedAt:    p.archivedAt,
+		CreatedAt:     p.createdAt,
+		UpdatedAt:     p.updatedAt,
+	}
+}
+
+// ProjectFromDTO restores a project from its persistence representation
+func ProjectFromDTO(dto ProjectDTO) (*Project, error) {
+	id, err := common.NewID(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: project id: %v", ErrInvalidSnapshot, err)
+	}
+	if err := validateProjectName(dto.Name); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if err := validateProjectGoals(dto.Goals); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	status := ProjectStatus(dto.Status)
+	if dto.Status == "" {
+		// Projects stored before lifecycle statuses existed are active
+		status = ProjectStatusActive
+	}
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, ErrInvalidProjectStatus)
+	}
+	if err := dto.AutoDetection.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if err := dto.Documentation.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if err := dto.Replies.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if err := dto.Standup.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if err := validateObjectives(dto.Objectives, dto.Goals); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	var milestones []Milestone
+	for _, m := range dto.Milestones {
+		milestones = append(milestones, Milestone{name: m.Name, deadline: m.Deadline})
+	}
+
+	p := &Project{
+		id:          id,
+		name:        dto.Name,
+		description: dto.Description,
+		goals:       append([]string(nil), dto.Goals...),
+		kpis:        append([]string(nil), dto.KPIs...),
+		milestones:  milestones,
+		channels:    append([]string(nil), dto.Channels...),
+		status:      status,
+		archivedAt:  dto.ArchivedAt,
+		createdAt:   dto.CreatedAt,
+		updatedAt:   dto.UpdatedAt,
+	}
+	p.autoDetection = dto.AutoDetection.clone()
+	p.documentation = dto.Documentation.clone()
+	p.replies = dto.Replies.clone()
+	p.standup = dto.Standup.clone()
+	p.objectives = cloneObjectives(dto.Objectives)
+	return p, nil
+}
+
+// ToDTO converts the message into its persistence representation
+func (m *Message) ToDTO() MessageDTO {
+	refs := make([]string, 0, len(m.references))
+	var resolutions []ReferenceResolutionDTO
+	for _, ref := range m.references {
+		refs = append(refs, ref.String())
+		if ref.resolution != ReferenceDetected {
+			resolutions = append(resolutions, ReferenceResolutionDTO{Reference: ref.String(), Resolution: string(ref.resolution), Confidence: ref.confidence})
+		}
+	}
+
+	states := make([]MessageStateChangeDTO, 0, len(m.states))
+	for _, change := range m.states {
+		states = append(states, MessageStateChangeDTO{State: change.state.String(), At: change.at, Reason: change.reason})
+	}
+
+	var attachments []AttachmentDTO
+	for _, a := range m.attachments {
+		attachments = append(attachments, AttachmentDTO{Name: a.name, MimeType: a.mimeType, URL: a.url})
+	}
+
+	return MessageDTO{
+		ID:            m.id.String(),
+		ThreadID:      m.threadID.String(),
+		ChannelID:     m.channelID,
+		SourceTS:      m.sourceTimestamp,
+		CorrelationID: m.correlationID.String(),
+		Sender:        m.sender,
+		Content:       m.content.Text(),
+		Type:          m.messageType.String(),
+		TypeFixed:     m.typeFixed,
+		PromptVersion: m.promptVersion,
+		Model:         m.model,
+		Confidence:    m.confidence,
+		Anonymized:    m.anonymized,
+		Category:      m.category.String(),
+		CategoryFixed: m.categoryFixed,
+		References:    refs,
+		Resolutions:   resolutions,
+		Tags:          TagStrings(m.tags),
+		Attachments:   attachments,
+		States:        states,
+		Timestamp:     m.timestamp,
+	}
+}
+
+// MessageFromDTO restores a message from its persistence representation
+func MessageFromDTO(dto MessageDTO) (*Message, error) {
+	id, err := common.NewID(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: message id: %v", ErrInvalidSnapshot, err)
+	}
+
+	// Messages outside of a thread have no thread ID
+	var threadID common.ID
+	if dto.ThreadID != "" {
+		if threadID, err = common.NewID(dto.ThreadID); err != nil {
+			return nil, fmt.Errorf("%w: thread id: %v", ErrInvalidSnapshot, err)
+		}
+	}
+
+	content, err := NewMessageContent(dto.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	messageType, err := NewMessageType(dto.Type)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	category := CategoryUnknown
+	if dto.Category != "" {
+		if category, err = NewCategory(dto.Category); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+	}
+	if dto.Sender == "" {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, ErrInvalidSender)
+	}
+
+	// Messages stored before resolutions were kept have their references as detected
+	resolutions := make(map[string]ReferenceResolutionDTO, len(dto.Resolutions))
+	for _, raw := range dto.Resolutions {
+		resolutions[raw.Reference] = raw
+	}
+	refs := make([]*Reference, 0, len(dto.References))
+	for _, raw := range dto.References {
+		ref, err := ParseReference(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		if res, ok := resolutions[raw]; ok {
+			if ref, err = ref.WithResolution(ReferenceResolution(res.Resolution), res.Confidence); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+			}
+		}
+		refs = append(refs, ref)
+	}
+
+	var attachments []*Attachment
+	for _, raw := range dto.Attachments {
+		attachment, err := NewAttachment(raw.Name, raw.MimeType, raw.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	// Messages stored before state tracking existed have no history and count as pending
+	var states []MessageStateChange
+	for _, raw := range dto.States {
+		change, err := NewMessageStateChange(MessageState(raw.State), raw.At, raw.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		states = append(states, change)
+	}
+
+	return &Message{
+		id:              id,
+		threadID:        threadID,
+		channelID:       dto.ChannelID,
+		sourceTimestamp: dto.SourceTS,
+		correlationID:   CorrelationID(dto.CorrelationID),
+		sender:          dto.Sender,
+		content:         content,
+		messageType:     messageType,
+		category:        category,
+		references:      refs,
+		tags:            NewTags(dto.Tags),
+		attachments:     attachments,
+		typeFixed:       dto.TypeFixed,
+		categoryFixed:   dto.CategoryFixed,
+		promptVersion:   dto.PromptVersion,
+		model:           dto.Model,
+		confidence:      dto.Confidence,
+		anonymized:      dto.Anonymized,
+		states:          states,
+		timestamp:       dto.Timestamp,
+	}, nil
+}
+
+// ToDTO converts the queued message into its wire representation
+func (q *QueuedMessage) ToDTO() QueuedMessageDTO {
+	return QueuedMessageDTO{Message: q.message.ToDTO(), Route: q.Route(), Retries: q.retries}
+}
+
+// QueuedMessageFromDTO restores a queued message from its wire representation
+func QueuedMessageFromDTO(dto QueuedMessageDTO) (*QueuedMessage, error) {
+	msg, err := MessageFromDTO(dto.Message)
+	if err != nil {
+		return nil, err
+	}
+	queued, err := NewQueuedMessage(msg, dto.Route)
+	if err != nil {
+		return nil, err
+	}
+	queued.retries = dto.Retries
+	return queued, nil
+}
+
+// ToDTO converts the approval request into its persistence representation
+func (r *ApprovalRequest) ToDTO() ApprovalRequestDTO {
+	policies := make([]ApprovalPolicy, 0, len(r.policies))
+	for _, policy := range r.policies {
+		policies = append(policies, policy.clone())
+	}
+	return ApprovalRequestDTO{
+		Message:     r.msg.ToDTO(),
+		Policies:    policies,
+		Roles:       cloneRoles(r.roles),
+		Approvers:   append([]string(nil), r.approvers...),
+		RequestedAt: r.requestedAt,
+	}
+}
+
+// ApprovalRequestFromDTO restores an approval request from its persistence representation
+func ApprovalRequestFromDTO(dto ApprovalRequestDTO) (*ApprovalRequest, error) {
+	msg, err := MessageFromDTO(dto.Message)
+	if err != nil {
+		return nil, err
+	}
+	if len(dto.Policies) == 0 {
+		return nil, fmt.Errorf("%w: approval request of message %s has no policy", ErrInvalidSnapshot, dto.Message.ID)
+	}
+	policies := make([]ApprovalPolicy, 0, len(dto.Policies))
+	for _, policy := range dto.Policies {
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		policies = append(policies, policy.clone())
+	}
+	return &ApprovalRequest{
+		msg:         msg,
+		policies:    policies,
+		roles:       cloneRoles(dto.Roles),
+		approvers:   append([]string(nil), dto.Approvers...),
+		requestedAt: dto.RequestedAt,
+	}, nil
+}
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectDTO_RoundTrip(t *testing.T) {
+	project := MustNewProject("Billing", "Billing rewrite", []string{"Ship v2"})
+	require.NoError(t, project.AddKPI("Churn below 2%"))
+	require.NoError(t, project.AddMilestone("Beta", time.Now().Add(24*time.Hour)))
+	require.NoError(t, project.BindChannel("C0001"))
+	require.NoError(t, project.ConfigureAutoDetection(AutoDetectionConfig{Enabled: true, MinConfidence: 0.8, Keywords: []string{"decision"}}))
+	require.NoError(t, project.ConfigureDocumentation(DocumentationConfig{BasePath: "teams/billing", DefaultTags: []Tag{"billing"}}))
+	require.NoError(t, project.ConfigureReplies(ReplyConfig{MaxRepliesPerHour: 10, BatchWindow: time.Minute}))
+	require.NoError(t, project.ConfigureStandup(StandupConfig{Members: []string{"U1"}, PromptAt: "09:30", Timezone: "Europe/Kyiv"}))
+	require.NoError(t, project.SetObjectives([]Objective{{Title: "Self-serve billing", Goal: "Ship v2", Quarter: "2024-Q3", KeyResults: []KeyResult{{ID: "KR1", Title: "Signups", Target: 100}}}}))
+	require.NoError(t, project.Pause())
+
+	restored, err := ProjectFromDTO(project.ToDTO())
+
+	require.NoError(t, err)
+	assert.Equal(t, project.ToDTO(), restored.ToDTO())
+	assert.Equal(t, "teams/billing", restored.DocumentationPath())
+	assert.False(t, restored.AcceptsMessages())
+}
+
+func TestProjectFromDTO(t *testing.T) {
+	valid := MustNewProject("Billing", "Billing rewrite", []string{"Ship v2"}).ToDTO()
+
+	t.Run("defaults missing status to active", func(t *testing.T) {
+		dto := valid
+		dto.Status = ""
+
+		project, err := ProjectFromDTO(dto)
+
+		require.NoError(t, err)
+		assert.Equal(t, ProjectStatusActive, project.Status())
+	})
+
+	t.Run("rejects invalid snapshots", func(t *testing.T) {
+		for name, mutate := range map[string]func(*ProjectDTO){
+			"id":            func(d *ProjectDTO) { d.ID = "not-an-id" },
+			"name":          func(d *ProjectDTO) { d.Name = "" },
+			"goals":         func(d *ProjectDTO) { d.Goals = nil },
+			"status":        func(d *ProjectDTO) { d.Status = "deleted" },
+			"documentation": func(d *ProjectDTO) { d.Documentation.BasePath = "/etc" },
+			"replies":       func(d *ProjectDTO) { d.Replies.MaxRepliesPerHour = -1 },
+			"standup":       func(d *ProjectDTO) { d.Standup.PromptAt = "9am" },
+			"objectives": func(d *ProjectDTO) {
+				d.Objectives = []Objective{{Title: "Self-serve", Goal: "Unknown goal", Quarter: "2024-Q3", KeyResults: []KeyResult{{ID: "KR1", Title: "Signups", Target: 100}}}}
+			},
+		} {
+			dto := valid
+			mutate(&dto)
+
+			_, err := ProjectFromDTO(dto)
+			assert.ErrorIs(t, err, ErrInvalidSnapshot, name)
+		}
+	})
+}
+
+func TestMessageDTO_RoundTrip(t *testing.T) {
+	ref, err := NewDocumentReference("docs/decisions/postgres.md")
+	require.NoError(t, err)
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, []*Reference{ref})
+	require.NoError(t, err)
+	msg.SetChannelID("C0001")
+	msg.SetSourceTimestamp("1717243200.000100")
+	msg.SetCorrelationID("QX-3F9A1C0B7E2D")
+	msg.AddTags("postgres")
+	msg.RecordPromptVersion("v1")
+	msg.RecordConfidence(0.8)
+	msg.RecordModel("ollama:llama3")
+	msg.FixCategory(CategoryOperations)
+	screenshot, err := NewAttachment("schema.png", "image/png", "https://files.example.com/schema.png")
+	require.NoError(t, err)
+	msg.AddAttachments(screenshot)
+	require.NoError(t, msg.StartAnalysis())
+	require.NoError(t, msg.MarkFailed("timeout"))
+
+	restored, err := MessageFromDTO(msg.ToDTO())
+
+	require.NoError(t, err)
+	assert.Equal(t, msg.ToDTO(), restored.ToDTO())
+	assert.Equal(t, "v1", restored.PromptVersion())
+	assert.Equal(t, "1717243200.000100", restored.SourceTimestamp())
+	assert.Equal(t, CorrelationID("QX-3F9A1C0B7E2D"), restored.CorrelationID())
+	assert.Equal(t, 0.8, restored.Confidence())
+	assert.Equal(t, "ollama:llama3", restored.Model())
+	assert.True(t, restored.HasFixedCategory())
+	assert.True(t, restored.ThreadID().Equals(msg.ThreadID()))
+	assert.Equal(t, MessageStateFailed, restored.State())
+	assert.Equal(t, "timeout", restored.StateReason())
+	require.Len(t, restored.Images(), 1)
+	assert.Equal(t, "https://files.example.com/schema.png", restored.Images()[0].URL())
+}
+
+func TestMessageFromDTO(t *testing.T) {
+	t.Run("allows messages without thread", func(t *testing.T) {
+		msg, err := MessageFromDTO(MessageDTO{ID: common.GenerateID().String(), Sender: "jane", Content: "hello", Type: "unknown"})
+
+		require.NoError(t, err)
+		assert.Empty(t, msg.ThreadID().String())
+		assert.Equal(t, CategoryUnknown, msg.Category())
+		assert.Equal(t, MessageStatePending, msg.State())
+	})
+
+	t.Run("rejects invalid states", func(t *testing.T) {
+		_, err := MessageFromDTO(MessageDTO{ID: common.GenerateID().String(), Sender: "jane", Content: "hello", Type: "unknown", States: []MessageStateChangeDTO{{State: "lost"}}})
+
+		assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+
+	t.Run("keeps reference resolutions", func(t *testing.T) {
+		resolved, err := MustNewReference(ReferenceTypeDocument, "docs/postgres.md").WithResolution(ReferenceResolved, 0.7)
+		require.NoError(t, err)
+		unresolved, err := MustNewReference(ReferenceTypeDocument, "the hiring plan").WithResolution(ReferenceUnresolved, 0)
+		require.NoError(t, err)
+		detected := MustNewReference(ReferenceTypeURL, "https://example.com/rfc")
+		msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, []*Reference{resolved, unresolved, detected})
+		require.NoError(t, err)
+
+		restored, err := MessageFromDTO(msg.ToDTO())
+
+		require.NoError(t, err)
+		refs := restored.References()
+		require.Len(t, refs, 3)
+		assert.Equal(t, ReferenceResolved, refs[0].Resolution())
+		assert.Equal(t, 0.7, refs[0].Confidence())
+		assert.True(t, refs[1].IsUnresolved())
+		assert.Equal(t, ReferenceDetected, refs[2].Resolution())
+		assert.Equal(t, []*Reference{resolved, detected}, restored.LinkedReferences())
+		assert.Equal(t, []*Reference{unresolved}, restored.UnresolvedReferences())
+	})
+
+	t.Run("rejects invalid reference resolutions", func(t *testing.T) {
+		_, err := MessageFromDTO(MessageDTO{ID: common.GenerateID().String(), Sender: "jane", Content: "hello", Type: "unknown",
+			References: []string{"url:https://example.com"}, Resolutions: []ReferenceResolutionDTO{{Reference: "url:https://example.com", Resolution: "maybe"}}})
+
+		assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+
+	t.Run("rejects invalid references", func(t *testing.T) {
+		_, err := MessageFromDTO(MessageDTO{ID: common.GenerateID().String(), Sender: "jane", Content: "hello", Type: "unknown", References: []string{"bogus"}})
+
+		assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+}
+
+func TestQueuedMessageDTO_RoundTrip(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID("C0001")
+	queued, err := NewQueuedMessage(msg, map[string]string{"channel": "C0001", "ts": "1700000000.000100"})
+	require.NoError(t, err)
+
+	restored, err := QueuedMessageFromDTO(queued.ToDTO())
+
+	require.NoError(t, err)
+	assert.Equal(t, queued.ToDTO(), restored.ToDTO())
+	assert.Equal(t, "1700000000.000100", restored.Route()["ts"])
+
+	retried, err := QueuedMessageFromDTO(restored.Retry().ToDTO())
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried.Retries())
+	assert.Equal(t, 0, restored.Retries())
+
+	_, err = NewQueuedMessage(nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidQueuedMessage)
+	_, err = QueuedMessageFromDTO(QueuedMessageDTO{})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+}
+
+func TestApprovalRequestDTO_RoundTrip(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+	require.NoError(t, err)
+	config := DocumentationConfig{
+		ApprovalPolicies: []ApprovalPolicy{{Approvals: 2}, {Role: "architect"}},
+		Roles:            map[string][]string{"architect": {"carol"}},
+	}
+	request := NewApprovalRequest(msg, config, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC))
+	require.NotNil(t, request)
+	require.NoError(t, request.Approve("carol"))
+
+	restored, err := ApprovalRequestFromDTO(request.ToDTO())
+
+	require.NoError(t, err)
+	assert.Equal(t, request.ToDTO(), restored.ToDTO())
+	assert.Equal(t, "2/3", restored.Progress())
+	assert.Equal(t, "1 approval", restored.Missing())
+
+	_, err = ApprovalRequestFromDTO(ApprovalRequestDTO{Message: msg.ToDTO()})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot, "requests have policies")
+	_, err = ApprovalRequestFromDTO(ApprovalRequestDTO{Message: msg.ToDTO(), Policies: []ApprovalPolicy{{Approvals: -1}}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// ApprovalRequestStore keeps the decisions waiting for the approvals their project's policies require, by the
+// thread they were posted in, so the approvals given so far survive restarts and count on any replica
+type ApprovalRequestStore interface {
+	// Put keeps the request of the thread of its decision, replacing the earlier one
+	Put(ctx context.Context, request *domain.ApprovalRequest) error
+
+	// Find returns the request waiting in a thread, ErrNotFound when there is none
+	Find(ctx context.Context, threadID string) (*domain.ApprovalRequest, error)
+
+	// List returns the requests waiting in every thread, oldest first
+	List(ctx context.Context) ([]*domain.ApprovalRequest, error)
+
+	// Remove takes the request of a thread out of the store, ErrNotFound when there is none. Replicas releasing
+	// a request remove it first, so only the one that removed it documents or rejects its decision.
+	Remove(ctx context.Context, threadID string) error
+}
@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// AuditLog defines interface for recording changes made by people
+type AuditLog interface {
+	// Record appends an entry to the log
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+
+	// List returns all entries, oldest first
+	List(ctx context.Context) ([]*domain.AuditEntry, error)
+
+	// ReplaceActor replaces the actor of the entries made by actor, and returns how many it changed
+	ReplaceActor(ctx context.Context, actor, replacement string) (int, error)
+}
+
+// CorrectionStore defines interface for the dataset of corrections people made to message analyses
+type CorrectionStore interface {
+	// Record adds a correction to the dataset
+	Record(ctx context.Context, correction *domain.Correction) error
+
+	// List returns all corrections, oldest first
+	List(ctx context.Context) ([]*domain.Correction, error)
+
+	// ReplaceActor replaces the actor of the corrections made by actor, and returns how many it changed
+	ReplaceActor(ctx context.Context, actor, replacement string) (int, error)
+
+	// DeleteByMessage removes the corrections of the messages, and returns how many it removed
+	DeleteByMessage(ctx context.Context, messageIDs ...string) (int, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+)
+
+// BackfillCursorStore keeps the newest message processed in each chat channel, named by the chat's own message
+// ID like the Slack ts, so fetching the messages missed while disconnected resumes there after a restart
+type BackfillCursorStore interface {
+	// Set records the newest message processed in a channel
+	Set(ctx context.Context, channelID, messageID string) error
+
+	// List returns the newest message processed in every channel, by channel ID
+	List(ctx context.Context) (map[string]string, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"time"
+)
+
+// CalendarProvider reads the events of a team calendar, so documents can name the meeting a discussion happened in
+type CalendarProvider interface {
+	// MeetingsBetween returns the timed events overlapping the period from from to to, all-day and canceled events excluded
+	MeetingsBetween(ctx context.Context, from, to time.Time) ([]*domain.Meeting, error)
+}
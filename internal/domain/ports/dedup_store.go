@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// DedupStore remembers the keys of the chat events already handled, so an event delivered more than once, or to
+// more than one replica, is handled once
+type DedupStore interface {
+	// MarkSeen records a key and reports whether it was new, false when it was recorded before
+	MarkSeen(ctx context.Context, key string) (bool, error)
+}
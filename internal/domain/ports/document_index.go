@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// DocumentIndex defines interface for the searchable index of stored documents
+type DocumentIndex interface {
+	// Index adds or replaces a document in the index
+	Index(ctx context.Context, doc *domain.IndexedDocument) error
+
+	// Remove removes a document from the index
+	Remove(ctx context.Context, path string) error
+
+	// FindByPath retrieves an indexed document by its path
+	FindByPath(ctx context.Context, path string) (*domain.IndexedDocument, error)
+
+	// FindByIdempotencyKey retrieves the document generated from the message with the idempotency key
+	FindByIdempotencyKey(ctx context.Context, key string) (*domain.IndexedDocument, error)
+
+	// List returns all indexed documents
+	List(ctx context.Context) ([]*domain.IndexedDocument, error)
+
+	// FindByTag returns the documents carrying the tag
+	FindByTag(ctx context.Context, tag domain.Tag) ([]*domain.IndexedDocument, error)
+
+	// Search returns the documents best matching the query, most relevant first
+	Search(ctx context.Context, query string, limit int) ([]*domain.IndexedDocument, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// IncidentStore defines interface for the incidents declared in threads
+type IncidentStore interface {
+	// Save stores an incident, saving the incident of a thread again replaces the earlier one
+	Save(ctx context.Context, incident *domain.Incident) error
+
+	// Find returns the latest incident of a thread, ErrNotFound when none was declared in it
+	Find(ctx context.Context, threadID string) (*domain.Incident, error)
+}
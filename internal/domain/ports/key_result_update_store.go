@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// KeyResultUpdateStore defines interface for the progress status updates reported on the key results of projects
+type KeyResultUpdateStore interface {
+	// Record stores an update, recording the update of a message on a key result again replaces the earlier one
+	Record(ctx context.Context, update domain.KeyResultUpdate) error
+
+	// List returns the updates on the key results of a project's objectives of a quarter, oldest first
+	List(ctx context.Context, projectID common.ID, quarter string) ([]domain.KeyResultUpdate, error)
+}
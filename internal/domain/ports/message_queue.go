@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// MessageQueue defines interface for handing chat messages from ingestion to processing workers,
+// so receiving messages and the LLM-heavy processing scale independently
+type MessageQueue interface {
+	// Publish enqueues a message for processing
+	Publish(ctx context.Context, msg *domain.QueuedMessage) error
+
+	// Consume passes queued messages to handle until ctx is canceled. Messages the handler
+	// fails on are delivered again when the queue supports redelivery.
+	Consume(ctx context.Context, handle func(ctx context.Context, msg *domain.QueuedMessage) error) error
+}
+
+// QueueInspector is implemented by message queues that can tell how many messages wait in them
+type QueueInspector interface {
+	// Depth returns how many messages wait to be processed
+	Depth(ctx context.Context) (int, error)
+}
+
+// DeadLetterQueue defines interface for keeping the queued messages processing failed on, until an operator
+// retries or discards them
+type DeadLetterQueue interface {
+	// Add keeps a failed message, replacing an earlier failure of the same message
+	Add(ctx context.Context, letter *domain.DeadLetter) error
+
+	// List returns the dead letters, the oldest failure first
+	List(ctx context.Context) ([]*domain.DeadLetter, error)
+
+	// Get returns the dead letter of a message, ErrNotFound when the message has none
+	Get(ctx context.Context, messageID string) (*domain.DeadLetter, error)
+
+	// Remove deletes the dead letter of a message, ErrNotFound when the message has none
+	Remove(ctx context.Context, messageID string) error
+}
+
+// MessageRouter is implemented by chat providers that can reply to messages another instance received
+type MessageRouter interface {
+	// MessageRoute returns the provider's location of a message it received
+	MessageRoute(messageID string) (map[string]string, bool)
+
+	// RestoreMessageRoute makes a message received by another instance known, so it can be replied to
+	RestoreMessageRoute(messageID string, route map[string]string) error
+}
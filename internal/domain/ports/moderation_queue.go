@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// ModerationQueue defines interface for the messages moderation holds until people approve or reject them
+type ModerationQueue interface {
+	// Hold adds a message to the queue, holding it again replaces the earlier entry
+	Hold(ctx context.Context, held *domain.HeldMessage) error
+
+	// List returns the held messages, oldest first
+	List(ctx context.Context) ([]*domain.HeldMessage, error)
+
+	// Release removes a message from the queue and returns it, ErrNotFound when it is not held
+	Release(ctx context.Context, messageID string) (*domain.HeldMessage, error)
+}
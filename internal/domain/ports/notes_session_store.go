@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// NotesSessionStore defines interface for the meeting notes taken in threads
+type NotesSessionStore interface {
+	// Save stores a notes session, saving the session of a thread again replaces the earlier one
+	Save(ctx context.Context, session *domain.NotesSession) error
+
+	// Find returns the latest notes session of a thread, ErrNotFound when no notes were taken in it
+	Find(ctx context.Context, threadID string) (*domain.NotesSession, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"time"
+)
+
+// PendingDuplicateStore keeps the ideas waiting for their author to choose between merging them into a similar
+// document and documenting them separately, so the choice survives restarts
+type PendingDuplicateStore interface {
+	// Put keeps the choice of a thread, replacing the earlier one
+	Put(ctx context.Context, pending *domain.PendingDuplicate) error
+
+	// Find returns the choice waited for in a thread at the time, ErrNotFound when there is none or it expired
+	Find(ctx context.Context, threadID string, at time.Time) (*domain.PendingDuplicate, error)
+
+	// Remove takes the choice of a thread out of the store, ErrNotFound when there is none. Replicas acting on a
+	// choice remove it first, so only the one that removed it acts.
+	Remove(ctx context.Context, threadID string) error
+
+	// Purge removes the choices expired at the time, and returns how many it removed
+	Purge(ctx context.Context, at time.Time) (int, error)
+}
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"time"
+)
+
+// PendingUpdateStore keeps the merges of ideas waiting for their author to apply or discard their diff, so the
+// decision survives restarts and can be made on any replica
+type PendingUpdateStore interface {
+	// Put keeps the update waiting in a thread, replacing the earlier one
+	Put(ctx context.Context, pending *domain.PendingUpdate) error
+
+	// Find returns the update waiting in a thread at the time, ErrNotFound when there is none or it expired
+	Find(ctx context.Context, threadID string, at time.Time) (*domain.PendingUpdate, error)
+
+	// List returns the updates waiting at the time in every thread, oldest to expire first
+	List(ctx context.Context, at time.Time) ([]*domain.PendingUpdate, error)
+
+	// Remove takes the update of a thread out of the store, ErrNotFound when there is none. Replicas acting on an
+	// update remove it first, so only the one that removed it acts.
+	Remove(ctx context.Context, threadID string) error
+
+	// Purge removes the updates expired at the time, and returns how many it removed
+	Purge(ctx context.Context, at time.Time) (int, error)
+}
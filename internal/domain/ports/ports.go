@@ -2,8 +2,15 @@ package ports
 
 import (
 	"context"
+	"errors"
 	"github.com/massimo-ua/quill/internal/domain"
 	"github.com/massimo-ua/quill/internal/domain/common"
+	"io"
+)
+
+var (
+	// ErrNotFound indicates that the requested entity does not exist in the repository
+	ErrNotFound = errors.New("not found")
 )
 
 // ChatAccessProvider defines interface for chat platform interactions
@@ -14,6 +21,9 @@ type ChatAccessProvider interface {
 	// ReplyToMessage replies to a specific message
 	ReplyToMessage(ctx context.Context, messageID, content string) error
 
+	// AddReaction reacts to a message with an emoji, named without colons like "memo"
+	AddReaction(ctx context.Context, messageID, emoji string) error
+
 	// ListenForMessages returns a channel for receiving messages
 	ListenForMessages(ctx context.Context) (<-chan *domain.Message, error)
 
@@ -21,6 +31,114 @@ type ChatAccessProvider interface {
 	HandleInteraction(ctx context.Context, interaction interface{}) error
 }
 
+// ProjectEditor is implemented by chat providers that can show a form for editing project metadata
+type ProjectEditor interface {
+	// OpenProjectEditor offers a form pre-filled with the project's metadata in the thread of a message
+	OpenProjectEditor(ctx context.Context, messageID string, project *domain.Project) error
+
+	// OnProjectEdit registers the function applying submitted forms, its error is shown to the editor
+	OnProjectEdit(apply func(ctx context.Context, edit *domain.ProjectEdit) (*domain.Project, error))
+}
+
+// ProjectOnboarding is implemented by chat providers that can show the onboarding wizard creating projects
+type ProjectOnboarding interface {
+	// OpenProjectWizard offers a form creating a project from one of the templates in the thread of a message
+	OpenProjectWizard(ctx context.Context, messageID string, templates []domain.ProjectTemplate) error
+
+	// OnProjectCreate registers the function creating the projects of submitted forms, its error is shown to the creator
+	OnProjectCreate(create func(ctx context.Context, draft *domain.ProjectDraft) (*domain.Project, error))
+}
+
+// PrivateReplier is implemented by chat providers that can reply to the author of a message only
+type PrivateReplier interface {
+	// ReplyEphemeral replies in the thread of a message, visible only to the message's author
+	ReplyEphemeral(ctx context.Context, messageID, content string) error
+
+	// ReplyDirect sends the reply to the author of a message in a direct message
+	ReplyDirect(ctx context.Context, messageID, content string) error
+}
+
+// DirectMessenger is implemented by chat providers that can start a direct message with anyone, not only
+// with the authors of messages
+type DirectMessenger interface {
+	// SendDirect sends a direct message to a person named by their chat user ID, and returns the channel of the
+	// conversation, the channel their replies are delivered from
+	SendDirect(ctx context.Context, userID, content string) (string, error)
+}
+
+// DetailsShortcut is implemented by chat providers that let people ask for the details of an acknowledged message
+type DetailsShortcut interface {
+	// OnDetailsRequest registers the function returning the details of a message, they are shown only to the requester
+	OnDetailsRequest(details func(ctx context.Context, messageID string) (string, error))
+}
+
+// CaptureShortcut is implemented by chat providers that let people capture any message, like old ones or ones
+// the bot did not document, with the type and category they pick. Captured messages are delivered by
+// ListenForMessages with the picked type and category fixed.
+type CaptureShortcut interface {
+	// OnCaptureRequest registers the function analyzing a message posted in a channel, it pre-fills the picks
+	OnCaptureRequest(analyze func(ctx context.Context, channelID, text string) (*domain.MessageAnalysisResult, error))
+}
+
+// HomePage is implemented by chat providers that show each person a home page, like Slack's App Home tab
+type HomePage interface {
+	// OnHomeOpened registers the function returning what a person sees on their home page
+	OnHomeOpened(home func(ctx context.Context, viewer domain.HomeViewer) (*domain.Home, error))
+
+	// OnModerationDecision registers the function approving or rejecting a held message from the home page
+	OnModerationDecision(decide func(ctx context.Context, messageID, actor string, approve bool) error)
+}
+
+// CategoryPicker is implemented by chat providers that can offer buttons for filing a document under another category
+type CategoryPicker interface {
+	// OfferCategories replies to a message with the content and a button for each document category
+	OfferCategories(ctx context.Context, messageID, content, path string, current domain.Category) error
+
+	// OnRecategorize registers the function applying a picked category, it returns the document's new path
+	OnRecategorize(apply func(ctx context.Context, change *domain.Recategorization) (string, error))
+}
+
+// ReviewRequester is implemented by chat providers that can offer buttons for reconfirming or superseding a document
+type ReviewRequester interface {
+	// RequestReview posts the content to a channel with buttons for reconfirming or superseding the document
+	RequestReview(ctx context.Context, channelID, content, path string) error
+
+	// OnReview registers the function applying a clicked review button
+	OnReview(apply func(ctx context.Context, review *domain.DocumentReview) error)
+}
+
+// TriageRequester is implemented by chat providers that can offer buttons for categorizing or dismissing the
+// messages waiting for triage
+type TriageRequester interface {
+	// RequestTriage posts the messages waiting for triage in a channel, each with a button per category and one
+	// for dismissing it
+	RequestTriage(ctx context.Context, channelID string, items []*domain.TriageItem) error
+
+	// OnTriage registers the function applying a clicked triage button
+	OnTriage(apply func(ctx context.Context, decision *domain.TriageDecision) error)
+}
+
+// CaptureOptOut is implemented by chat providers where people can react to a message with domain.NoDocReaction
+// to stop capturing its thread
+type CaptureOptOut interface {
+	// OnOptOut registers the function stopping the capture of the thread of a message, given our message ID
+	// and who reacted
+	OnOptOut(optOut func(ctx context.Context, messageID, actor string) error)
+}
+
+// LinkUnfurler is implemented by chat providers that can preview links to documents posted in a channel
+type LinkUnfurler interface {
+	// OnLinkShared registers the function finding the document a link posted in a channel points at.
+	// It returns ErrNotFound for links to anything else, and to documents the channel may not see.
+	OnLinkShared(find func(ctx context.Context, channelID, link string) (*domain.IndexedDocument, error))
+}
+
+// AttachmentFetcher is implemented by chat providers that can download the files shared with messages
+type AttachmentFetcher interface {
+	// FetchAttachment returns the contents of a file shared with a message received from the provider
+	FetchAttachment(ctx context.Context, attachment *domain.Attachment) ([]byte, error)
+}
+
 // DocumentStoreProvider defines interface for document storage operations
 type DocumentStoreProvider interface {
 	// StoreDocument stores a new document
@@ -39,6 +157,62 @@ type DocumentStoreProvider interface {
 	DeleteDocument(ctx context.Context, path string) error
 }
 
+// RepositoryBootstrapper is implemented by document stores that can scaffold an empty repository
+type RepositoryBootstrapper interface {
+	// NeedsBootstrap checks if the repository has no documentation structure yet
+	NeedsBootstrap(ctx context.Context) (bool, error)
+
+	// Bootstrap writes all files in a single commit
+	Bootstrap(ctx context.Context, files map[string][]byte, message string) error
+}
+
+// BatchCommitter is implemented by document stores that can write several documents atomically
+type BatchCommitter interface {
+	// CommitFiles creates or replaces all files in a single commit
+	CommitFiles(ctx context.Context, files map[string][]byte, message string) error
+}
+
+// RevisionReader is implemented by document stores that version documents. An update whose metadata holds
+// the revision it was based on under "base_revision" is merged with the changes made to the document since,
+// rather than replacing them.
+type RevisionReader interface {
+	// GetDocumentRevision retrieves a document with the revision it is at
+	GetDocumentRevision(ctx context.Context, path string) ([]byte, string, error)
+}
+
+// DocumentWalker is implemented by document stores that can list the documents of a whole directory tree
+type DocumentWalker interface {
+	// WalkDocuments lists the documents in a path and all its subdirectories
+	WalkDocuments(ctx context.Context, root string) ([]string, error)
+}
+
+// DocumentStoreFactory creates document stores for repositories other than the default one
+type DocumentStoreFactory interface {
+	// ForRepository returns a document store writing to the owner/name repository.
+	// An empty branch uses the branch of the default configuration.
+	ForRepository(repository, branch string) (DocumentStoreProvider, error)
+}
+
+// CanvasPublisher is implemented by chat providers that can show a document on the canvas of a channel
+type CanvasPublisher interface {
+	// PublishCanvas replaces the content of the canvas of a channel with Markdown, creating the canvas when the
+	// channel has none
+	PublishCanvas(ctx context.Context, channelID, markdown string) error
+}
+
+// DocumentLinker is implemented by document stores that can link to documents in a browser
+type DocumentLinker interface {
+	// DocumentURL returns the URL where a human can read the document
+	DocumentURL(path string) string
+}
+
+// DataLocator is implemented by providers that report where they process or keep content.
+// Providers that do not implement it are treated as cloud providers by the local-only policy.
+type DataLocator interface {
+	// DataLocation returns where the provider processes or keeps the content it is given
+	DataLocation() domain.DataLocation
+}
+
 // AiAgentProvider defines interface for AI operations
 type AiAgentProvider interface {
 	// AnalyzeMessage analyzes message content
@@ -54,6 +228,85 @@ type AiAgentProvider interface {
 	DetectReferences(ctx context.Context, content string) ([]*domain.Reference, error)
 }
 
+// EmbeddingProvider defines interface for computing text embeddings
+type EmbeddingProvider interface {
+	// Embed returns the embedding vector of the text
+	Embed(ctx context.Context, text string) ([]float64, error)
+}
+
+// TitleGenerator defines interface for naming generated documentation
+type TitleGenerator interface {
+	// GenerateTitle returns a short title of a few words describing the content
+	GenerateTitle(ctx context.Context, content string) (string, error)
+}
+
+// TermDefiner defines interface for writing glossary definitions of domain terms
+type TermDefiner interface {
+	// DefineTerm returns a short definition of a term as the message it was used in means it.
+	// The definition is empty when the message does not make the meaning clear.
+	DefineTerm(ctx context.Context, term, usage string) (string, error)
+}
+
+// PostmortemWriter defines interface for drafting the postmortems of incidents
+type PostmortemWriter interface {
+	// DraftPostmortem returns the sections of a postmortem of an incident drawn from its Markdown timeline
+	DraftPostmortem(ctx context.Context, title, timeline string) (string, error)
+}
+
+// MeetingNotesWriter defines interface for drawing the notes of a meeting from its transcript
+type MeetingNotesWriter interface {
+	// SummarizeMeeting returns the agenda, decisions and action items of a meeting drawn from its Markdown transcript
+	SummarizeMeeting(ctx context.Context, title, transcript string) (*domain.MeetingSummary, error)
+}
+
+// RiskDetector defines interface for finding the risks a message raises
+type RiskDetector interface {
+	// DetectRisks returns the risk statements of a message rated by severity and likelihood, none when it raises none
+	DetectRisks(ctx context.Context, content string) ([]domain.RiskStatement, error)
+}
+
+// ExampleGuidedAnalyzer is implemented by AI agents that can learn from corrections when analyzing messages
+type ExampleGuidedAnalyzer interface {
+	// AnalyzeMessageWithExamples analyzes message content following the corrections people made to earlier analyses
+	AnalyzeMessageWithExamples(ctx context.Context, content string, examples []*domain.Correction) (*domain.MessageAnalysisResult, error)
+}
+
+// PromptVersionedAnalyzer is implemented by AI agents that keep the earlier versions of their analysis prompt,
+// so a project can pin the prompt its messages are analyzed with
+type PromptVersionedAnalyzer interface {
+	// PromptVersions lists the available analysis prompt versions, oldest first
+	PromptVersions() []string
+	// AnalyzeMessageWithPrompt analyzes message content with a prompt version, following the corrections if any.
+	// Unknown versions fail with domain.ErrUnknownPromptVersion.
+	AnalyzeMessageWithPrompt(ctx context.Context, version, content string, examples []*domain.Correction) (*domain.MessageAnalysisResult, error)
+}
+
+// TranscriptionProvider defines interface for turning recorded speech into text
+type TranscriptionProvider interface {
+	// Transcribe returns the text spoken in an audio or video recording. The name, like "clip.m4a",
+	// tells the format by its extension.
+	Transcribe(ctx context.Context, name string, audio io.Reader) (string, error)
+}
+
+// ImageDescriber defines interface for vision models reading screenshots, diagrams and whiteboard photos
+type ImageDescriber interface {
+	// DescribeImage returns the text in an image and a description of its diagrams and charts, for documentation
+	DescribeImage(ctx context.Context, mimeType string, image []byte) (string, error)
+}
+
+// ContentModerator is implemented by providers that rate content for harmful categories, like harassment or hate
+type ContentModerator interface {
+	// Moderate returns how the content rates in each category the provider checks
+	Moderate(ctx context.Context, content string) (*domain.ModerationResult, error)
+}
+
+// QuestionAnswerer defines interface for answering questions from stored documents
+type QuestionAnswerer interface {
+	// AnswerQuestion answers the question using only the given sources, citing them as [n].
+	// The conversation holds the earlier questions and answers of the thread.
+	AnswerQuestion(ctx context.Context, question string, conversation *domain.Conversation, sources []*domain.AnswerSource) (string, error)
+}
+
 // ProjectRepository defines interface for project persistence
 type ProjectRepository interface {
 	// Save persists a project
@@ -67,6 +320,12 @@ type ProjectRepository interface {
 
 	// Delete removes a project
 	Delete(ctx context.Context, id common.ID) error
+
+	// FindByChannel retrieves the project bound to a chat channel
+	FindByChannel(ctx context.Context, channelID string) (*domain.Project, error)
+
+	// List retrieves all projects
+	List(ctx context.Context) ([]*domain.Project, error)
 }
 
 // MessageRepository defines interface for message persistence
@@ -79,4 +338,23 @@ type MessageRepository interface {
 
 	// FindByThread retrieves messages in a thread
 	FindByThread(ctx context.Context, threadID string) ([]*domain.Message, error)
+
+	// Update persists changes to a stored message, e.g. its processing state
+	Update(ctx context.Context, message *domain.Message) error
+
+	// FindByState retrieves messages in the given processing states, or all messages when none are given
+	FindByState(ctx context.Context, states ...domain.MessageState) ([]*domain.Message, error)
+
+	// FindBySender retrieves the messages a person sent, e.g. to erase them
+	FindBySender(ctx context.Context, sender string) ([]*domain.Message, error)
+
+	// Delete removes a message, deleting a missing message is not an error
+	Delete(ctx context.Context, id string) error
+}
+
+// FeatureFlagSource provides feature flags managed outside the deployment's configuration, so features can be
+// rolled out without restarting the bot
+type FeatureFlagSource interface {
+	// FeatureFlags returns the current flags, they replace the configured flags of the same features
+	FeatureFlags(ctx context.Context) (domain.FeatureFlags, error)
 }
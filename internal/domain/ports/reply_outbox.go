@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"time"
+)
+
+// ReplyOutbox keeps the capture confirmations waiting to be posted, so batches survive restarts and any replica
+// can post them, and the confirmations of the messages acknowledged with a reaction, so any replica can show them
+type ReplyOutbox interface {
+	// Add keeps a batch, or appends its replies to the pending batch with the same key. It reports whether the
+	// batch was kept as a new one.
+	Add(ctx context.Context, batch *domain.ReplyBatch) (bool, error)
+
+	// Due returns the batches due by now, the earliest first
+	Due(ctx context.Context, now time.Time) ([]*domain.ReplyBatch, error)
+
+	// Postpone moves the time a batch is due to, ErrNotFound when there is no batch with the key
+	Postpone(ctx context.Context, key string, dueAt time.Time) error
+
+	// Remove takes a batch out of the outbox, ErrNotFound when there is none. Replicas posting a batch remove it
+	// first, so only the one that removed it posts it.
+	Remove(ctx context.Context, key string) error
+
+	// KeepDetails keeps the confirmation of a message acknowledged with a reaction, replacing the earlier one
+	KeepDetails(ctx context.Context, messageID, reply string, at time.Time) error
+
+	// Details returns the confirmation kept for a message, ErrNotFound when there is none
+	Details(ctx context.Context, messageID string) (string, error)
+
+	// ForgetDetails removes the confirmations kept before the time, and returns how many it removed
+	ForgetDetails(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// SnoozeStore defines interface for the threads whose messages are not captured for a while
+type SnoozeStore interface {
+	// Snooze stores the snooze of a thread, snoozing it again replaces the earlier snooze
+	Snooze(ctx context.Context, snooze *domain.ThreadSnooze) error
+
+	// Find returns the snooze of a thread, ErrNotFound when it is not snoozed
+	Find(ctx context.Context, threadID string) (*domain.ThreadSnooze, error)
+
+	// Resume removes the snooze of a thread, ErrNotFound when it is not snoozed
+	Resume(ctx context.Context, threadID string) error
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// StandupStore defines interface for the daily standups of projects
+type StandupStore interface {
+	// Save stores a standup, saving the standup of a project and day again replaces the earlier one
+	Save(ctx context.Context, standup *domain.Standup) error
+
+	// Find returns the standup of a project on a day formatted as 2006-01-02, ErrNotFound when none was run
+	Find(ctx context.Context, projectID common.ID, date string) (*domain.Standup, error)
+
+	// ListOpen returns the standups whose answers were not compiled yet, oldest first
+	ListOpen(ctx context.Context) ([]*domain.Standup, error)
+
+	// List returns every standup, oldest first
+	List(ctx context.Context) ([]*domain.Standup, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// ThreadMappingCache keeps which of our threads each chat thread is, named by its channel and the chat's own
+// thread ID, like the Slack thread ts
+type ThreadMappingCache interface {
+	// Get returns the ID of our thread a chat thread maps to, ErrNotFound when it is not mapped
+	Get(ctx context.Context, channelID, externalID string) (common.ID, error)
+
+	// Map maps a chat thread to id unless it is mapped already, and returns the ID it maps to. Replicas receiving
+	// the messages of a new thread at once all get the ID of the first one.
+	Map(ctx context.Context, channelID, externalID string, id common.ID) (common.ID, error)
+}
@@ -8,18 +8,19 @@ import (
 
 // ThreadRepository defines interface for thread persistence
 type ThreadRepository interface {
-	// Save persists a thread
+	// Save persists a thread with its messages, replacing the earlier version of it
 	Save(ctx context.Context, thread *domain.Thread) error
 
-	// FindByID retrieves a thread by ID
+	// FindByID retrieves a thread by ID, ErrNotFound when it is unknown
 	FindByID(ctx context.Context, id common.ID) (*domain.Thread, error)
 
-	// Update updates thread information
-	Update(ctx context.Context, thread *domain.Thread) error
+	// FindByChannel retrieves the threads of a channel, oldest first
+	FindByChannel(ctx context.Context, channelID string) ([]*domain.Thread, error)
 
-	// Delete removes a thread
-	Delete(ctx context.Context, id common.ID) error
+	// AppendMessage adds a message to a stored thread, ErrNotFound when the thread is unknown.
+	// A message already in the thread is not added again.
+	AppendMessage(ctx context.Context, threadID common.ID, msg *domain.Message) error
 
-	// FindByProject retrieves all threads for a project
-	FindByProject(ctx context.Context, projectID common.ID) ([]*domain.Thread, error)
+	// Delete removes a thread, ErrNotFound when it is unknown
+	Delete(ctx context.Context, id common.ID) error
 }
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// TriageQueue defines interface for the messages analysed with low confidence that wait for people to triage them
+type TriageQueue interface {
+	// Add queues a message, queuing it again replaces the earlier entry
+	Add(ctx context.Context, item *domain.TriageItem) error
+
+	// List returns the queued messages, oldest first
+	List(ctx context.Context) ([]*domain.TriageItem, error)
+
+	// Take removes a message from the queue and returns it, ErrNotFound when it is not queued
+	Take(ctx context.Context, messageID string) (*domain.TriageItem, error)
+}
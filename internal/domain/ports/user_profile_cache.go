@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// UserProfileCache keeps the profiles of chat users for a while, so messages do not each fetch their author's
+type UserProfileCache interface {
+	// Get returns the cached profile of a chat user, ErrNotFound when it is not cached or expired
+	Get(ctx context.Context, userID string) (domain.UserProfile, error)
+
+	// Put caches the profile of a chat user, replacing the earlier one
+	Put(ctx context.Context, profile domain.UserProfile) error
+
+	// Delete removes the cached profile of a chat user, ErrNotFound when it is not cached
+	Delete(ctx context.Context, userID string) error
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// Wiki reads the pages of a knowledge base, like a Notion workspace or a Confluence space, so teams migrating
+// into the documentation repository can import them
+type Wiki interface {
+	// Source returns the kind of knowledge base the pages come from
+	Source() domain.WikiSource
+
+	// Pages returns the pages with their content converted to Markdown, each naming the page it is nested in
+	Pages(ctx context.Context) ([]*domain.WikiPage, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+)
+
+// WorkCoordinator decides which bot instance processes the messages of a channel, so replicas
+// receiving the same chat events do not document a message twice
+type WorkCoordinator interface {
+	// Owns reports whether this instance processes the messages posted in the channel
+	Owns(ctx context.Context, channelID string) (bool, error)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// ProcessingEventKind tells what happened to a message in the documentation pipeline
+type ProcessingEventKind string
+
+const (
+	// ProcessingEventReceived is published when a message starts being tracked
+	ProcessingEventReceived ProcessingEventKind = "received"
+	// ProcessingEventAnalyzed is published once the type and category of a message are known
+	ProcessingEventAnalyzed ProcessingEventKind = "analyzed"
+	// ProcessingEventCommitted is published when the document of a message is committed
+	ProcessingEventCommitted ProcessingEventKind = "committed"
+	// ProcessingEventIgnored is published when a message is left undocumented
+	ProcessingEventIgnored ProcessingEventKind = "ignored"
+	// ProcessingEventFailed is published when processing a message fails
+	ProcessingEventFailed ProcessingEventKind = "failed"
+)
+
+func (k ProcessingEventKind) String() string {
+	return string(k)
+}
+
+// ProcessingEvent is something that happened to a message while it was processed, for dashboards and
+// debugging tools following the pipeline live. It carries no content nor sender, so streaming it does not
+// expose what people wrote.
+type ProcessingEvent struct {
+	kind       ProcessingEventKind
+	messageID  common.ID
+	channelID  string
+	threadID   common.ID
+	msgType    MessageType
+	category   Category
+	confidence float64
+	state      MessageState
+	path       string
+	reason     string
+	at         time.Time
+	// correlationID ties the event to the logs and audit entries of processing the message
+	correlationID CorrelationID
+}
+
+// NewProcessingEvent creates an event about a message as it is now. The path is the document committed,
+// empty for other kinds.
+func NewProcessingEvent(kind ProcessingEventKind, msg *Message, path string, at time.Time) ProcessingEvent {
+	return ProcessingEvent{
+		kind:       kind,
+		messageID:  msg.ID(),
+		channelID:  msg.ChannelID(),
+		threadID:   msg.ThreadID(),
+		msgType:    msg.Type(),
+		category:   msg.Category(),
+		confidence: msg.Confidence(),
+		state:      msg.State(),
+		path:       path,
+		reason:     msg.StateReason(),
+		at:         at,
+
+		correlationID: msg.CorrelationID(),
+	}
+}
+
+// Kind returns what happened
+func (e ProcessingEvent) Kind() ProcessingEventKind {
+	return e.kind
+}
+
+// MessageID returns the ID of the message
+func (e ProcessingEvent) MessageID() common.ID {
+	return e.messageID
+}
+
+// ChannelID returns the channel the message was posted in
+func (e ProcessingEvent) ChannelID() string {
+	return e.channelID
+}
+
+// ThreadID returns the thread of the message
+func (e ProcessingEvent) ThreadID() common.ID {
+	return e.threadID
+}
+
+// Type returns the type of the message when the event happened
+func (e ProcessingEvent) Type() MessageType {
+	return e.msgType
+}
+
+// Category returns the category of the message when the event happened
+func (e ProcessingEvent) Category() Category {
+	return e.category
+}
+
+// Confidence returns the confidence of the analysis, 0 before the message is analyzed
+func (e ProcessingEvent) Confidence() float64 {
+	return e.confidence
+}
+
+// State returns the processing state of the message when the event happened
+func (e ProcessingEvent) State() MessageState {
+	return e.state
+}
+
+// Path returns the path of the committed document, empty for other kinds
+func (e ProcessingEvent) Path() string {
+	return e.path
+}
+
+// Reason returns why the message reached its state, if it was told
+func (e ProcessingEvent) Reason() string {
+	return e.reason
+}
+
+// CorrelationID returns the correlation ID of processing the message, empty when it had none
+func (e ProcessingEvent) CorrelationID() CorrelationID {
+	return e.correlationID
+}
+
+// At returns when the event happened
+func (e ProcessingEvent) At() time.Time {
+	return e.at
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProcessingEvent(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+	require.NoError(t, err)
+	msg.SetChannelID("C1")
+	msg.SetCorrelationID("QX-3F9A1C0B7E2D")
+	msg.RecordConfidence(0.9)
+	require.NoError(t, msg.StartAnalysis())
+	require.NoError(t, msg.MarkDocumented())
+
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := NewProcessingEvent(ProcessingEventCommitted, msg, "docs/development/decision/postgres.md", at)
+
+	assert.Equal(t, ProcessingEventCommitted, event.Kind())
+	assert.Equal(t, msg.ID(), event.MessageID())
+	assert.Equal(t, msg.ThreadID(), event.ThreadID())
+	assert.Equal(t, "C1", event.ChannelID())
+	assert.Equal(t, MessageTypeDecision, event.Type())
+	assert.Equal(t, CategoryDevelopment, event.Category())
+	assert.Equal(t, 0.9, event.Confidence())
+	assert.Equal(t, MessageStateDocumented, event.State())
+	assert.Equal(t, "docs/development/decision/postgres.md", event.Path())
+	assert.Equal(t, at, event.At())
+	assert.Equal(t, CorrelationID("QX-3F9A1C0B7E2D"), event.CorrelationID())
+}
+
+func TestNewProcessingEvent_KeepsReason(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("thanks!"), MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+	require.NoError(t, msg.TransitionTo(MessageStateIgnored, "nothing to document"))
+
+	event := NewProcessingEvent(ProcessingEventIgnored, msg, "", time.Now())
+	assert.Equal(t, "nothing to document", event.Reason())
+	assert.Empty(t, event.Path())
+}
@@ -19,14 +19,22 @@ var (
 
 // Project represents a project entity in the system
 type Project struct {
-	id          common.ID
-	name        string
-	description string
-	goals       []string
-	kpis        []string
-	milestones  []Milestone
-	createdAt   time.Time
-	updatedAt   time.Time
+	id            common.ID
+	name          string
+	description   string
+	goals         []string
+	kpis          []string
+	milestones    []Milestone
+	channels      []string
+	status        ProjectStatus
+	autoDetection AutoDetectionConfig
+	documentation DocumentationConfig
+	replies       ReplyConfig
+	standup       StandupConfig
+	objectives    []Objective
+	archivedAt    time.Time
+	createdAt     time.Time
+	updatedAt     time.Time
 }
 
 // Milestone represents a project milestone
@@ -35,6 +43,16 @@ type Milestone struct {
 	deadline time.Time
 }
 
+// Name returns the milestone name
+func (m Milestone) Name() string {
+	return m.name
+}
+
+// Deadline returns the milestone deadline, or the zero time when it has none
+func (m Milestone) Deadline() time.Time {
+	return m.deadline
+}
+
 // NewProject creates a new Project instance
 func NewProject(name, description string, goals []string) (*Project, error) {
 	if err := validateProjectName(name); err != nil {
@@ -46,12 +64,17 @@ func NewProject(name, description string, goals []string) (*Project, error) {
 
 	now := time.Now()
 	return &Project{
-		id:          common.GenerateID(),
-		name:        strings.TrimSpace(name),
-		description: strings.TrimSpace(description),
-		goals:       goals,
-		createdAt:   now,
-		updatedAt:   now,
+		id:            common.GenerateID(),
+		name:          strings.TrimSpace(name),
+		description:   strings.TrimSpace(description),
+		goals:         goals,
+		status:        ProjectStatusActive,
+		autoDetection: DefaultAutoDetectionConfig(),
+		documentation: DefaultDocumentationConfig(),
+		replies:       DefaultReplyConfig(),
+		standup:       DefaultStandupConfig(),
+		createdAt:     now,
+		updatedAt:     now,
 	}, nil
 }
 
@@ -100,6 +123,87 @@ func (p *Project) Milestones() []Milestone {
 	return milestones
 }
 
+// Channels returns the chat channels bound to the project
+func (p *Project) Channels() []string {
+	channels := make([]string, len(p.channels))
+	copy(channels, p.channels)
+	return channels
+}
+
+// IsBoundTo checks if the chat channel is bound to the project
+func (p *Project) IsBoundTo(channelID string) bool {
+	for _, c := range p.channels {
+		if c == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the project's lifecycle status
+func (p *Project) Status() ProjectStatus {
+	return p.status
+}
+
+// ArchivedAt returns when the project was archived, or the zero time
+func (p *Project) ArchivedAt() time.Time {
+	return p.archivedAt
+}
+
+// AcceptsMessages checks if messages in the project's channels should be processed
+func (p *Project) AcceptsMessages() bool {
+	return p.status.IsActive()
+}
+
+// IsReadOnly checks if the project and its documentation can no longer be changed
+func (p *Project) IsReadOnly() bool {
+	return p.status.IsArchived()
+}
+
+// AutoDetection returns the project's auto detection settings
+func (p *Project) AutoDetection() AutoDetectionConfig {
+	return p.autoDetection.clone()
+}
+
+// Documentation returns the project's documentation settings
+func (p *Project) Documentation() DocumentationConfig {
+	return p.documentation.clone()
+}
+
+// Replies returns the project's reply settings
+func (p *Project) Replies() ReplyConfig {
+	return p.replies.clone()
+}
+
+// Standup returns the project's standup settings
+func (p *Project) Standup() StandupConfig {
+	return p.standup.clone()
+}
+
+// Objectives returns the project's objectives of every quarter
+func (p *Project) Objectives() []Objective {
+	return cloneObjectives(p.objectives)
+}
+
+// ObjectivesOf returns the project's objectives of a quarter, like 2024-Q3
+func (p *Project) ObjectivesOf(quarter string) []Objective {
+	var objectives []Objective
+	for _, objective := range p.objectives {
+		if objective.Quarter == quarter {
+			objectives = append(objectives, objective)
+		}
+	}
+	return cloneObjectives(objectives)
+}
+
+// DocumentationPath returns the directory the project documentation is written to
+func (p *Project) DocumentationPath() string {
+	if basePath := strings.Trim(strings.TrimSpace(p.documentation.BasePath), "/"); basePath != "" {
+		return basePath
+	}
+	return "projects/" + p.id.String()
+}
+
 // CreatedAt returns the project's creation timestamp
 func (p *Project) CreatedAt() time.Time {
 	return p.createdAt
@@ -111,16 +215,23 @@ func (p *Project) UpdatedAt() time.Time {
 }
 
 // AddKPI adds a new KPI to the project
-func (p *Project) AddKPI(kpi string) {
+func (p *Project) AddKPI(kpi string) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
 	kpi = strings.TrimSpace(kpi)
 	if kpi != "" {
 		p.kpis = append(p.kpis, kpi)
 		p.updatedAt = time.Now()
 	}
+	return nil
 }
 
 // AddMilestone adds a new milestone to the project
 func (p *Project) AddMilestone(name string, deadline time.Time) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return errors.New("milestone name cannot be empty")
@@ -143,21 +254,158 @@ func (p *Project) AddMilestone(name string, deadline time.Time) error {
 }
 
 // UpdateDescription updates the project's description
-func (p *Project) UpdateDescription(description string) {
+func (p *Project) UpdateDescription(description string) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
 	p.description = strings.TrimSpace(description)
 	p.updatedAt = time.Now()
+	return nil
 }
 
-// UpdateGoals updates the project's goals
+// UpdateGoals updates the project's goals. Goals the project's objectives advance cannot be dropped.
 func (p *Project) UpdateGoals(goals []string) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
 	if err := validateProjectGoals(goals); err != nil {
 		return err
 	}
+	if err := validateObjectives(p.objectives, goals); err != nil {
+		return err
+	}
 	p.goals = goals
 	p.updatedAt = time.Now()
 	return nil
 }
 
+// ConfigureAutoDetection replaces the project's auto detection settings
+func (p *Project) ConfigureAutoDetection(cfg AutoDetectionConfig) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	p.autoDetection = cfg.clone()
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// ConfigureDocumentation replaces the project's documentation settings
+func (p *Project) ConfigureDocumentation(cfg DocumentationConfig) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	p.documentation = cfg.clone()
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// ConfigureReplies replaces the project's reply settings
+func (p *Project) ConfigureReplies(cfg ReplyConfig) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	p.replies = cfg.clone()
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// ConfigureStandup replaces the project's standup settings
+func (p *Project) ConfigureStandup(cfg StandupConfig) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	p.standup = cfg.clone()
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// SetObjectives replaces the project's objectives, each advancing one of its business goals
+func (p *Project) SetObjectives(objectives []Objective) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
+	if err := validateObjectives(objectives, p.goals); err != nil {
+		return err
+	}
+	p.objectives = cloneObjectives(objectives)
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// BindChannel binds a chat channel to the project
+func (p *Project) BindChannel(channelID string) error {
+	if p.IsReadOnly() {
+		return ErrProjectArchived
+	}
+	channelID = strings.TrimSpace(channelID)
+	if channelID == "" {
+		return errors.New("channel ID cannot be empty")
+	}
+	if !p.IsBoundTo(channelID) {
+		p.channels = append(p.channels, channelID)
+		p.updatedAt = time.Now()
+	}
+	return nil
+}
+
+// UnbindChannel removes a chat channel from the project
+func (p *Project) UnbindChannel(channelID string) {
+	for i, c := range p.channels {
+		if c == channelID {
+			p.channels = append(p.channels[:i], p.channels[i+1:]...)
+			p.updatedAt = time.Now()
+			return
+		}
+	}
+}
+
+// TransitionTo moves the project to another lifecycle status
+func (p *Project) TransitionTo(status ProjectStatus) error {
+	if !status.IsValid() {
+		return ErrInvalidProjectStatus
+	}
+	if !p.status.CanTransitionTo(status) {
+		return ErrInvalidStatusTransition
+	}
+
+	now := time.Now()
+	p.status = status
+	if status.IsArchived() {
+		p.archivedAt = now
+	} else {
+		p.archivedAt = time.Time{}
+	}
+	p.updatedAt = now
+	return nil
+}
+
+// Pause stops documenting the project's channels until it is resumed
+func (p *Project) Pause() error {
+	return p.TransitionTo(ProjectStatusPaused)
+}
+
+// Resume restarts documenting the project's channels
+func (p *Project) Resume() error {
+	return p.TransitionTo(ProjectStatusActive)
+}
+
+// Archive stops documenting the project's channels and makes its documentation read-only
+func (p *Project) Archive() error {
+	return p.TransitionTo(ProjectStatusArchived)
+}
+
 // validation helpers
 func validateProjectName(name string) error {
 	if strings.TrimSpace(name) == "" {
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// ProjectEdit holds changes to a project's metadata submitted by a user
+type ProjectEdit struct {
+	projectID   common.ID
+	description string
+	goals       []string
+	kpis        []string
+	editor      string
+}
+
+// NewProjectEdit creates a ProjectEdit. Goals replace the current goals, KPIs are added to the project.
+func NewProjectEdit(projectID common.ID, description string, goals, kpis []string, editor string) (*ProjectEdit, error) {
+	goals = trimLines(goals)
+	if err := validateProjectGoals(goals); err != nil {
+		return nil, err
+	}
+
+	return &ProjectEdit{
+		projectID:   projectID,
+		description: strings.TrimSpace(description),
+		goals:       goals,
+		kpis:        trimLines(kpis),
+		editor:      editor,
+	}, nil
+}
+
+// ProjectID returns the identifier of the edited project
+func (e *ProjectEdit) ProjectID() common.ID {
+	return e.projectID
+}
+
+// Description returns the new project description
+func (e *ProjectEdit) Description() string {
+	return e.description
+}
+
+// Goals returns the new project goals
+func (e *ProjectEdit) Goals() []string {
+	goals := make([]string, len(e.goals))
+	copy(goals, e.goals)
+	return goals
+}
+
+// KPIs returns the KPIs to add to the project
+func (e *ProjectEdit) KPIs() []string {
+	kpis := make([]string, len(e.kpis))
+	copy(kpis, e.kpis)
+	return kpis
+}
+
+// Editor returns who submitted the changes
+func (e *ProjectEdit) Editor() string {
+	return e.editor
+}
+
+// SplitLines splits multi-line form input into trimmed, non-empty lines
+func SplitLines(text string) []string {
+	return trimLines(strings.Split(text, "\n"))
+}
+
+// trimLines trims entries and drops empty ones, including list markers copied from Markdown
+func trimLines(lines []string) []string {
+	trimmed := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*-•"))
+		if line != "" {
+			trimmed = append(trimmed, line)
+		}
+	}
+	return trimmed
+}
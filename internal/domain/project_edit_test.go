@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+func TestNewProjectEdit(t *testing.T) {
+	id := common.GenerateID()
+
+	edit, err := NewProjectEdit(id, "  New description ", []string{"- Ship v3", " ", "* Cut costs"}, []string{"", "NPS above 40"}, "jane")
+	if err != nil {
+		t.Fatalf("NewProjectEdit() error = %v", err)
+	}
+	if edit.Description() != "New description" {
+		t.Errorf("Description() = %q", edit.Description())
+	}
+	if want := []string{"Ship v3", "Cut costs"}; !reflect.DeepEqual(edit.Goals(), want) {
+		t.Errorf("Goals() = %v, want %v", edit.Goals(), want)
+	}
+	if want := []string{"NPS above 40"}; !reflect.DeepEqual(edit.KPIs(), want) {
+		t.Errorf("KPIs() = %v, want %v", edit.KPIs(), want)
+	}
+
+	if _, err := NewProjectEdit(id, "desc", []string{" ", "-"}, nil, "jane"); err != ErrInvalidProjectGoals {
+		t.Errorf("expected ErrInvalidProjectGoals, got %v", err)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	got := SplitLines("Ship v3\r\n\n• Cut costs\n")
+	if want := []string{"Ship v3", "Cut costs"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitLines() = %v, want %v", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -87,20 +88,21 @@ func (pm *ProjectMetadata) validateDates() error {
 
 // FormatContext returns a formatted string suitable for use in prompts
 func (pm *ProjectMetadata) FormatContext() string {
-	context := fmt.Sprintf("Project: %s\nDescription: %s\n\nBusiness Goals:\n", pm.Name, pm.Description)
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Project: %s\nDescription: %s\n\nBusiness Goals:\n", pm.Name, pm.Description))
 
 	for _, goal := range pm.BusinessGoals {
-		context += fmt.Sprintf("- %s\n", goal)
+		b.WriteString(fmt.Sprintf("- %s\n", goal))
 	}
 
-	context += "\nKey Performance Indicators:\n"
+	b.WriteString("\nKey Performance Indicators:\n")
 	for _, kpi := range pm.KPIs {
-		context += fmt.Sprintf("- %s\n", kpi)
+		b.WriteString(fmt.Sprintf("- %s\n", kpi))
 	}
 
-	context += fmt.Sprintf("\nProject Timeline: %s to %s",
+	b.WriteString(fmt.Sprintf("\nProject Timeline: %s to %s",
 		pm.StartDate.Format("2006-01-02"),
-		pm.EndDate.Format("2006-01-02"))
+		pm.EndDate.Format("2006-01-02")))
 
-	return context
+	return b.String()
 }
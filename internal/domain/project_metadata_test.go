@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -276,3 +277,19 @@ func TestProjectMetadata_Validate(t *testing.T) {
 		}
 	})
 }
+
+func BenchmarkFormatContext(b *testing.B) {
+	now := time.Now()
+	goals := make([]string, 100)
+	kpis := make([]string, 100)
+	for i := range goals {
+		goals[i] = fmt.Sprintf("Goal %d", i)
+		kpis[i] = fmt.Sprintf("KPI %d", i)
+	}
+	pm := MustNewProjectMetadata("Test Project", "A test project description", goals, kpis, now, now.AddDate(0, 1, 0))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = pm.FormatContext()
+	}
+}
@@ -0,0 +1,337 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMinConfidence is the analysis confidence a message needs before it is documented automatically
+	DefaultMinConfidence = 0.7
+)
+
+var (
+	ErrInvalidAutoDetectionConfig = errors.New("invalid auto detection config")
+	ErrInvalidDocumentationConfig = errors.New("invalid documentation config")
+)
+
+// AutoDetectionConfig controls which messages in a project's channels are documented without being asked
+type AutoDetectionConfig struct {
+	Enabled       bool     `json:"enabled"`
+	MinConfidence float64  `json:"minConfidence"`
+	Keywords      []string `json:"keywords,omitempty"`
+	// EnabledTypes limits documentation to these message types, all types are documented when empty
+	EnabledTypes []MessageType `json:"enabledTypes,omitempty"`
+	// EnabledCategories limits documentation to these categories, all categories are documented when empty
+	EnabledCategories []Category `json:"enabledCategories,omitempty"`
+	// PromptVersion pins the analysis prompt, the AI agent's latest prompt is used when empty
+	PromptVersion string `json:"promptVersion,omitempty"`
+	// Triage queues messages analysed below MinConfidence for the daily triage instead of documenting them
+	Triage bool `json:"triage,omitempty"`
+}
+
+// DefaultAutoDetectionConfig returns the auto detection settings of a new project
+func DefaultAutoDetectionConfig() AutoDetectionConfig {
+	return AutoDetectionConfig{
+		Enabled:       true,
+		MinConfidence: DefaultMinConfidence,
+	}
+}
+
+// Validate ensures the auto detection settings are usable
+func (c AutoDetectionConfig) Validate() error {
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("%w: min confidence must be between 0 and 1", ErrInvalidAutoDetectionConfig)
+	}
+	for _, keyword := range c.Keywords {
+		if strings.TrimSpace(keyword) == "" {
+			return fmt.Errorf("%w: keywords cannot be empty", ErrInvalidAutoDetectionConfig)
+		}
+	}
+	for _, msgType := range c.EnabledTypes {
+		if !msgType.IsValid() || msgType.IsUnknown() {
+			return fmt.Errorf("%w: unknown message type %q", ErrInvalidAutoDetectionConfig, msgType)
+		}
+	}
+	for _, category := range c.EnabledCategories {
+		if !category.IsValid() || category == CategoryUnknown {
+			return fmt.Errorf("%w: unknown category %q", ErrInvalidAutoDetectionConfig, category)
+		}
+	}
+	if strings.ContainsAny(c.PromptVersion, " \t\n") {
+		return fmt.Errorf("%w: prompt version %q cannot contain spaces", ErrInvalidAutoDetectionConfig, c.PromptVersion)
+	}
+	return nil
+}
+
+// TypeEnabled checks if messages of the type are documented
+func (c AutoDetectionConfig) TypeEnabled(msgType MessageType) bool {
+	if len(c.EnabledTypes) == 0 {
+		return true
+	}
+	for _, enabled := range c.EnabledTypes {
+		if enabled == msgType {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryEnabled checks if messages of the category are documented
+func (c AutoDetectionConfig) CategoryEnabled(category Category) bool {
+	if len(c.EnabledCategories) == 0 {
+		return true
+	}
+	for _, enabled := range c.EnabledCategories {
+		if enabled == category {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipReason explains why an analysed message is not documented or answered with suggestions, it is empty when the message is handled.
+// Messages whose type was fixed by their source are handled even when auto-detection is disabled.
+func (c AutoDetectionConfig) SkipReason(msg *Message) string {
+	if !c.Enabled && !msg.HasFixedType() {
+		return "auto-detection is disabled"
+	}
+	// Unknown messages are not documented, but may still get suggestions
+	if msg.Type().IsUnknown() {
+		return ""
+	}
+	if !c.TypeEnabled(msg.Type()) {
+		return fmt.Sprintf("%s messages are not documented", msg.Type())
+	}
+	if !c.CategoryEnabled(msg.Category()) {
+		return fmt.Sprintf("%s messages are not documented", msg.Category())
+	}
+	return ""
+}
+
+// clone returns a copy that shares no slices with the config
+func (c AutoDetectionConfig) clone() AutoDetectionConfig {
+	c.Keywords = append([]string(nil), c.Keywords...)
+	c.EnabledTypes = append([]MessageType(nil), c.EnabledTypes...)
+	c.EnabledCategories = append([]Category(nil), c.EnabledCategories...)
+	return c
+}
+
+// Accepts checks if a message analysed with the given confidence should be documented.
+// When keywords are configured the message text must contain at least one of them.
+func (c AutoDetectionConfig) Accepts(text string, confidence float64) bool {
+	if !c.Enabled || confidence < c.MinConfidence {
+		return false
+	}
+	if len(c.Keywords) == 0 {
+		return true
+	}
+
+	text = strings.ToLower(text)
+	for _, keyword := range c.Keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DocumentationConfig controls where and how a project's documentation is written
+type DocumentationConfig struct {
+	// Repository is the owner/name of the repository the documentation is written to, defaults to the global one
+	Repository string `json:"repository,omitempty"`
+	// Branch is the branch the documentation is committed to, defaults to the repository's configured branch
+	Branch string `json:"branch,omitempty"`
+	// BasePath is the directory of the project documentation, defaults to projects/<id>
+	BasePath string `json:"basePath,omitempty"`
+	// PathScheme is the layout of the generated documents, defaults to the category scheme
+	PathScheme PathScheme `json:"pathScheme,omitempty"`
+	// StatusRollup collects status updates into weekly documents, defaults to weekly
+	StatusRollup    RollupPolicy `json:"statusRollup,omitempty"`
+	DefaultCategory Category     `json:"defaultCategory,omitempty"`
+	DefaultTags     []Tag        `json:"defaultTags,omitempty"`
+	// Taxonomy are the categories the project's documentation is organized in, the repository is scaffolded
+	// with a directory for each. Defaults to DocumentCategories.
+	Taxonomy []Category `json:"taxonomy,omitempty"`
+	// DocumentTemplates are the outlines the documents of each message type are written to, in Markdown
+	DocumentTemplates map[MessageType]string `json:"documentTemplates,omitempty"`
+	// Diagrams asks for Mermaid diagrams in the documents of decisions and architecture discussions
+	Diagrams bool `json:"diagrams,omitempty"`
+	// ReviewAfterDays is how many days a decision can go without changes or discussion before people are
+	// asked to review it, defaults to 180
+	ReviewAfterDays int `json:"reviewAfterDays,omitempty"`
+	// Visibility is who outside of the project can find its documents unless they are marked otherwise,
+	// defaults to internal
+	Visibility Visibility `json:"visibility,omitempty"`
+	// LocalOnly keeps the project's content on local providers: messages are not analysed or documented by cloud
+	// AI providers and documents are not stored in cloud document stores
+	LocalOnly bool `json:"localOnly,omitempty"`
+	// MinGrounding is the share of a generated document's claims that must be found in the messages it was
+	// written from, documents below it are flagged for review. Defaults to 0.5.
+	MinGrounding float64 `json:"minGrounding,omitempty"`
+	// Moderation holds the project's messages to the moderation policy before they are analysed, keeping
+	// offensive and off-topic content out of the documentation
+	Moderation bool `json:"moderation,omitempty"`
+	// ApproveUpdates shows the diff of an update to an existing document in chat, the document is only
+	// changed once someone applies it
+	ApproveUpdates bool `json:"approveUpdates,omitempty"`
+	// DigestCanvas publishes the weekly status rollups as a digest on the canvas of each project channel, in
+	// addition to committing them
+	DigestCanvas bool `json:"digestCanvas,omitempty"`
+	// ApprovalPolicies hold the decisions they apply to until people approve them, the decision is documented
+	// once every policy got its approvals
+	ApprovalPolicies []ApprovalPolicy `json:"approvalPolicies,omitempty"`
+	// Roles are the people of each role approval policies can require, by the names their messages are sent by
+	Roles map[string][]string `json:"roles,omitempty"`
+}
+
+// DefaultDocumentationConfig returns the documentation settings of a new project
+func DefaultDocumentationConfig() DocumentationConfig {
+	return DocumentationConfig{}
+}
+
+// HasRepository checks if the project documentation goes to its own repository
+func (c DocumentationConfig) HasRepository() bool {
+	return strings.TrimSpace(c.Repository) != ""
+}
+
+// PathStrategy returns the strategy placing the project's generated documents
+func (c DocumentationConfig) PathStrategy() PathStrategy {
+	return c.PathScheme.Strategy()
+}
+
+// Categories returns the categories the project's documentation is organized in
+func (c DocumentationConfig) Categories() []Category {
+	if len(c.Taxonomy) == 0 {
+		return append([]Category(nil), DocumentCategories...)
+	}
+	return append([]Category(nil), c.Taxonomy...)
+}
+
+// TemplateFor returns the outline the document of a message type is written to, empty when it has none
+func (c DocumentationConfig) TemplateFor(msgType MessageType) string {
+	return strings.TrimSpace(c.DocumentTemplates[msgType])
+}
+
+// DiagramsFor checks if the document of a message should come with Mermaid diagrams
+func (c DocumentationConfig) DiagramsFor(msg *Message) bool {
+	if !c.Diagrams || msg == nil {
+		return false
+	}
+	return msg.Type().IsDecision() || msg.HasTag(TagArchitecture)
+}
+
+// ReviewAfter returns how long the project's decisions can go without changes or discussion before review
+func (c DocumentationConfig) ReviewAfter() time.Duration {
+	if c.ReviewAfterDays <= 0 {
+		return DefaultReviewAfter
+	}
+	return time.Duration(c.ReviewAfterDays) * 24 * time.Hour
+}
+
+// DefaultVisibility returns the visibility of the project's documents that are not marked otherwise
+func (c DocumentationConfig) DefaultVisibility() Visibility {
+	if c.Visibility == "" {
+		return VisibilityInternal
+	}
+	return c.Visibility
+}
+
+// Grounding returns the share of a generated document's claims that must be supported to commit it without review
+func (c DocumentationConfig) Grounding() float64 {
+	if c.MinGrounding <= 0 {
+		return DefaultMinGrounding
+	}
+	return c.MinGrounding
+}
+
+// Validate ensures the documentation settings are usable
+func (c DocumentationConfig) Validate() error {
+	if c.HasRepository() {
+		parts := strings.Split(strings.TrimSpace(c.Repository), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("%w: repository must be owner/name", ErrInvalidDocumentationConfig)
+		}
+	}
+	if strings.ContainsAny(c.Branch, " ~^:?*[\\") {
+		return fmt.Errorf("%w: invalid branch name %q", ErrInvalidDocumentationConfig, c.Branch)
+	}
+	basePath := strings.TrimSpace(c.BasePath)
+	if strings.HasPrefix(basePath, "/") || strings.Contains(basePath, "..") {
+		return fmt.Errorf("%w: base path must be relative to the repository root", ErrInvalidDocumentationConfig)
+	}
+	if !c.PathScheme.IsValid() {
+		return fmt.Errorf("%w: unknown path scheme %q", ErrInvalidDocumentationConfig, c.PathScheme)
+	}
+	if !c.StatusRollup.IsValid() {
+		return fmt.Errorf("%w: unknown status rollup policy %q", ErrInvalidDocumentationConfig, c.StatusRollup)
+	}
+	if c.DefaultCategory != "" && !c.DefaultCategory.IsValid() {
+		return fmt.Errorf("%w: unknown default category %q", ErrInvalidDocumentationConfig, c.DefaultCategory)
+	}
+	for _, tag := range c.DefaultTags {
+		if _, err := NewTag(string(tag)); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidDocumentationConfig, err)
+		}
+	}
+	for _, category := range c.Taxonomy {
+		if !category.IsValid() || category == CategoryUnknown {
+			return fmt.Errorf("%w: unknown taxonomy category %q", ErrInvalidDocumentationConfig, category)
+		}
+	}
+	for msgType, template := range c.DocumentTemplates {
+		if !msgType.IsValid() || msgType.IsUnknown() {
+			return fmt.Errorf("%w: document template for unknown type %q", ErrInvalidDocumentationConfig, msgType)
+		}
+		if strings.TrimSpace(template) == "" {
+			return fmt.Errorf("%w: the document template of %s is empty", ErrInvalidDocumentationConfig, msgType)
+		}
+	}
+	if c.ReviewAfterDays < 0 {
+		return fmt.Errorf("%w: review period cannot be negative", ErrInvalidDocumentationConfig)
+	}
+	if c.Visibility != "" && !c.Visibility.IsValid() {
+		return fmt.Errorf("%w: unknown visibility %q", ErrInvalidDocumentationConfig, c.Visibility)
+	}
+	if c.MinGrounding < 0 || c.MinGrounding > 1 {
+		return fmt.Errorf("%w: minimum grounding must be between 0 and 1", ErrInvalidDocumentationConfig)
+	}
+	for role, members := range c.Roles {
+		if strings.TrimSpace(role) == "" || len(members) == 0 {
+			return fmt.Errorf("%w: roles must have a name and members", ErrInvalidDocumentationConfig)
+		}
+	}
+	for _, policy := range c.ApprovalPolicies {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidDocumentationConfig, err)
+		}
+		if policy.Role != "" && len(c.Roles[policy.Role]) == 0 {
+			return fmt.Errorf("%w: approval policy requires unknown role %q", ErrInvalidDocumentationConfig, policy.Role)
+		}
+	}
+	return nil
+}
+
+// clone returns a copy that shares no slices or maps with the config
+func (c DocumentationConfig) clone() DocumentationConfig {
+	c.DefaultTags = append([]Tag(nil), c.DefaultTags...)
+	c.Taxonomy = append([]Category(nil), c.Taxonomy...)
+	if c.DocumentTemplates != nil {
+		templates := make(map[MessageType]string, len(c.DocumentTemplates))
+		for msgType, template := range c.DocumentTemplates {
+			templates[msgType] = template
+		}
+		c.DocumentTemplates = templates
+	}
+	if c.ApprovalPolicies != nil {
+		policies := make([]ApprovalPolicy, len(c.ApprovalPolicies))
+		for i, policy := range c.ApprovalPolicies {
+			policies[i] = policy.clone()
+		}
+		c.ApprovalPolicies = policies
+	}
+	c.Roles = cloneRoles(c.Roles)
+	return c
+}
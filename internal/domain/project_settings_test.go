@@ -0,0 +1,383 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+func TestAutoDetectionConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AutoDetectionConfig
+		wantErr error
+	}{
+		{
+			name:   "default config",
+			config: DefaultAutoDetectionConfig(),
+		},
+		{
+			name:    "confidence above one",
+			config:  AutoDetectionConfig{Enabled: true, MinConfidence: 1.5},
+			wantErr: ErrInvalidAutoDetectionConfig,
+		},
+		{
+			name:   "enabled types and categories",
+			config: AutoDetectionConfig{Enabled: true, EnabledTypes: []MessageType{MessageTypeDecision}, EnabledCategories: []Category{CategoryProduct}},
+		},
+		{
+			name:    "unknown enabled type",
+			config:  AutoDetectionConfig{Enabled: true, EnabledTypes: []MessageType{MessageTypeUnknown}},
+			wantErr: ErrInvalidAutoDetectionConfig,
+		},
+		{
+			name:    "invalid enabled category",
+			config:  AutoDetectionConfig{Enabled: true, EnabledCategories: []Category{"finance"}},
+			wantErr: ErrInvalidAutoDetectionConfig,
+		},
+		{
+			name:    "empty keyword",
+			config:  AutoDetectionConfig{Enabled: true, Keywords: []string{"decision", " "}},
+			wantErr: ErrInvalidAutoDetectionConfig,
+		},
+		{
+			name:   "pinned prompt version",
+			config: AutoDetectionConfig{Enabled: true, PromptVersion: "v1"},
+		},
+		{
+			name:    "prompt version with spaces",
+			config:  AutoDetectionConfig{Enabled: true, PromptVersion: "v1 "},
+			wantErr: ErrInvalidAutoDetectionConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAutoDetectionConfig_Accepts(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     AutoDetectionConfig
+		text       string
+		confidence float64
+		want       bool
+	}{
+		{
+			name:       "confident enough",
+			config:     DefaultAutoDetectionConfig(),
+			text:       "We will use Postgres",
+			confidence: 0.9,
+			want:       true,
+		},
+		{
+			name:       "below min confidence",
+			config:     DefaultAutoDetectionConfig(),
+			text:       "We will use Postgres",
+			confidence: 0.5,
+		},
+		{
+			name:       "disabled",
+			config:     AutoDetectionConfig{MinConfidence: 0.1},
+			text:       "We will use Postgres",
+			confidence: 0.9,
+		},
+		{
+			name:       "keyword matches case-insensitively",
+			config:     AutoDetectionConfig{Enabled: true, Keywords: []string{"decision"}},
+			text:       "Decision: we will use Postgres",
+			confidence: 0.9,
+			want:       true,
+		},
+		{
+			name:       "keyword missing",
+			config:     AutoDetectionConfig{Enabled: true, Keywords: []string{"decision"}},
+			text:       "We will use Postgres",
+			confidence: 0.9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Accepts(tt.text, tt.confidence); got != tt.want {
+				t.Errorf("Accepts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoDetectionConfig_Enabled(t *testing.T) {
+	all := DefaultAutoDetectionConfig()
+	if !all.TypeEnabled(MessageTypeStatus) || !all.CategoryEnabled(CategoryOperations) {
+		t.Error("expected every type and category to be enabled by default")
+	}
+
+	limited := AutoDetectionConfig{
+		Enabled:           true,
+		EnabledTypes:      []MessageType{MessageTypeDecision, MessageTypeIdea},
+		EnabledCategories: []Category{CategoryProduct},
+	}
+	if !limited.TypeEnabled(MessageTypeDecision) {
+		t.Error("expected decisions to be enabled")
+	}
+	if limited.TypeEnabled(MessageTypeStatus) {
+		t.Error("expected status updates to be disabled")
+	}
+	if !limited.CategoryEnabled(CategoryProduct) {
+		t.Error("expected product to be enabled")
+	}
+	if limited.CategoryEnabled(CategoryDevelopment) {
+		t.Error("expected development to be disabled")
+	}
+}
+
+func TestAutoDetectionConfig_SkipReason(t *testing.T) {
+	newMessage := func(msgType MessageType, category Category, fixed bool) *Message {
+		msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), msgType, category, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fixed {
+			msg.FixType(msgType)
+		}
+		return msg
+	}
+
+	limited := AutoDetectionConfig{
+		Enabled:           true,
+		EnabledTypes:      []MessageType{MessageTypeDecision},
+		EnabledCategories: []Category{CategoryDevelopment},
+	}
+
+	tests := []struct {
+		name   string
+		config AutoDetectionConfig
+		msg    *Message
+		want   string
+	}{
+		{
+			name:   "handled by default",
+			config: DefaultAutoDetectionConfig(),
+			msg:    newMessage(MessageTypeStatus, CategoryOperations, false),
+		},
+		{
+			name:   "auto-detection disabled",
+			config: AutoDetectionConfig{},
+			msg:    newMessage(MessageTypeUnknown, CategoryUnknown, false),
+			want:   "auto-detection is disabled",
+		},
+		{
+			name:   "fixed type bypasses disabled auto-detection",
+			config: AutoDetectionConfig{},
+			msg:    newMessage(MessageTypeDecision, CategoryDevelopment, true),
+		},
+		{
+			name:   "disabled type",
+			config: limited,
+			msg:    newMessage(MessageTypeStatus, CategoryDevelopment, false),
+			want:   "status messages are not documented",
+		},
+		{
+			name:   "disabled category",
+			config: limited,
+			msg:    newMessage(MessageTypeDecision, CategoryProduct, false),
+			want:   "product messages are not documented",
+		},
+		{
+			name:   "unknown messages may get suggestions",
+			config: limited,
+			msg:    newMessage(MessageTypeUnknown, CategoryUnknown, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.SkipReason(tt.msg); got != tt.want {
+				t.Errorf("SkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentationConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DocumentationConfig
+		wantErr error
+	}{
+		{
+			name:   "default config",
+			config: DefaultDocumentationConfig(),
+		},
+		{
+			name:   "custom settings",
+			config: DocumentationConfig{BasePath: "teams/billing", DefaultCategory: CategoryDevelopment, DefaultTags: []Tag{"billing"}},
+		},
+		{
+			name:   "own repository",
+			config: DocumentationConfig{Repository: "acme/billing-docs", Branch: "docs/main"},
+		},
+		{
+			name:    "repository without owner",
+			config:  DocumentationConfig{Repository: "billing-docs"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "invalid branch",
+			config:  DocumentationConfig{Repository: "acme/billing-docs", Branch: "docs main"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "absolute base path",
+			config:  DocumentationConfig{BasePath: "/etc"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "base path escapes repository",
+			config:  DocumentationConfig{BasePath: "docs/../../secrets"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:   "review period",
+			config: DocumentationConfig{ReviewAfterDays: 90},
+		},
+		{
+			name:    "negative review period",
+			config:  DocumentationConfig{ReviewAfterDays: -1},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:   "minimum grounding",
+			config: DocumentationConfig{MinGrounding: 0.8},
+		},
+		{
+			name:    "minimum grounding above one",
+			config:  DocumentationConfig{MinGrounding: 1.5},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:   "shared documents",
+			config: DocumentationConfig{Visibility: VisibilityShared},
+		},
+		{
+			name:    "unknown visibility",
+			config:  DocumentationConfig{Visibility: "public"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:   "date path scheme",
+			config: DocumentationConfig{PathScheme: PathSchemeDate},
+		},
+		{
+			name:    "unknown path scheme",
+			config:  DocumentationConfig{PathScheme: "random"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:   "status rollups disabled",
+			config: DocumentationConfig{StatusRollup: RollupNone},
+		},
+		{
+			name:    "unknown status rollup policy",
+			config:  DocumentationConfig{StatusRollup: "daily"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "unknown category",
+			config:  DocumentationConfig{DefaultCategory: "finance"},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "invalid tag",
+			config:  DocumentationConfig{DefaultTags: []Tag{"c++"}},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name: "approval policies",
+			config: DocumentationConfig{
+				ApprovalPolicies: []ApprovalPolicy{{Categories: []Category{CategoryDevelopment}, Approvals: 2}, {Role: "architect"}},
+				Roles:            map[string][]string{"architect": {"carol"}},
+			},
+		},
+		{
+			name:    "approval policy with an unknown role",
+			config:  DocumentationConfig{ApprovalPolicies: []ApprovalPolicy{{Role: "architect"}}},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "approval policy with an unknown category",
+			config:  DocumentationConfig{ApprovalPolicies: []ApprovalPolicy{{Categories: []Category{"finance"}}}},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "role without members",
+			config:  DocumentationConfig{Roles: map[string][]string{"architect": nil}},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:   "taxonomy and document templates",
+			config: DocumentationConfig{Taxonomy: []Category{CategoryProduct}, DocumentTemplates: map[MessageType]string{MessageTypeDecision: "## Decision\n"}},
+		},
+		{
+			name:    "unknown taxonomy category",
+			config:  DocumentationConfig{Taxonomy: []Category{"finance"}},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "document template for an unknown type",
+			config:  DocumentationConfig{DocumentTemplates: map[MessageType]string{"memo": "## Memo\n"}},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+		{
+			name:    "empty document template",
+			config:  DocumentationConfig{DocumentTemplates: map[MessageType]string{MessageTypeStatus: " "}},
+			wantErr: ErrInvalidDocumentationConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDocumentationConfig_DiagramsFor(t *testing.T) {
+	newMessage := func(msgType MessageType, tags ...Tag) *Message {
+		msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("The API calls the billing service"), msgType, CategoryDevelopment, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		msg.AddTags(tags...)
+		return msg
+	}
+
+	tests := []struct {
+		name   string
+		config DocumentationConfig
+		msg    *Message
+		want   bool
+	}{
+		{name: "disabled by default", config: DefaultDocumentationConfig(), msg: newMessage(MessageTypeDecision)},
+		{name: "decision", config: DocumentationConfig{Diagrams: true}, msg: newMessage(MessageTypeDecision), want: true},
+		{name: "architecture discussion", config: DocumentationConfig{Diagrams: true}, msg: newMessage(MessageTypeInformation, TagArchitecture), want: true},
+		{name: "status update", config: DocumentationConfig{Diagrams: true}, msg: newMessage(MessageTypeStatus, "billing")},
+		{name: "no message", config: DocumentationConfig{Diagrams: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.DiagramsFor(tt.msg); got != tt.want {
+				t.Errorf("DiagramsFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
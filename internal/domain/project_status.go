@@ -0,0 +1,76 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrInvalidProjectStatus indicates that the project status is not recognized
+	ErrInvalidProjectStatus = errors.New("invalid project status")
+	// ErrInvalidStatusTransition indicates that the project cannot move to the requested status
+	ErrInvalidStatusTransition = errors.New("invalid project status transition")
+	// ErrProjectArchived indicates that an archived project cannot be changed
+	ErrProjectArchived = errors.New("project is archived")
+)
+
+// ProjectStatus represents the lifecycle state of a project
+type ProjectStatus string
+
+const (
+	// ProjectStatusActive is a project whose channels are documented
+	ProjectStatusActive ProjectStatus = "active"
+	// ProjectStatusPaused is a project whose channels are temporarily not documented
+	ProjectStatusPaused ProjectStatus = "paused"
+	// ProjectStatusArchived is a finished project whose documentation is read-only
+	ProjectStatusArchived ProjectStatus = "archived"
+)
+
+// projectStatusTransitions lists the statuses each status can move to
+var projectStatusTransitions = map[ProjectStatus][]ProjectStatus{
+	ProjectStatusActive:   {ProjectStatusPaused, ProjectStatusArchived},
+	ProjectStatusPaused:   {ProjectStatusActive, ProjectStatusArchived},
+	ProjectStatusArchived: {ProjectStatusActive},
+}
+
+// NewProjectStatus creates a ProjectStatus from a string
+func NewProjectStatus(status string) (ProjectStatus, error) {
+	s := ProjectStatus(status)
+	if !s.IsValid() {
+		return "", ErrInvalidProjectStatus
+	}
+	return s, nil
+}
+
+// String returns the string representation of ProjectStatus
+func (s ProjectStatus) String() string {
+	return string(s)
+}
+
+// IsValid checks if the ProjectStatus is valid
+func (s ProjectStatus) IsValid() bool {
+	_, ok := projectStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo checks if the status can move to the target status
+func (s ProjectStatus) CanTransitionTo(target ProjectStatus) bool {
+	for _, allowed := range projectStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive checks if the ProjectStatus is active
+func (s ProjectStatus) IsActive() bool {
+	return s == ProjectStatusActive
+}
+
+// IsPaused checks if the ProjectStatus is paused
+func (s ProjectStatus) IsPaused() bool {
+	return s == ProjectStatusPaused
+}
+
+// IsArchived checks if the ProjectStatus is archived
+func (s ProjectStatus) IsArchived() bool {
+	return s == ProjectStatusArchived
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "testing"
+
+func TestProjectStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from ProjectStatus
+		to   ProjectStatus
+		want bool
+	}{
+		{ProjectStatusActive, ProjectStatusPaused, true},
+		{ProjectStatusActive, ProjectStatusArchived, true},
+		{ProjectStatusActive, ProjectStatusActive, false},
+		{ProjectStatusPaused, ProjectStatusActive, true},
+		{ProjectStatusPaused, ProjectStatusArchived, true},
+		{ProjectStatusArchived, ProjectStatusActive, true},
+		{ProjectStatusArchived, ProjectStatusPaused, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("CanTransitionTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProjectStatus(t *testing.T) {
+	if _, err := NewProjectStatus("archived"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := NewProjectStatus("deleted"); err != ErrInvalidProjectStatus {
+		t.Errorf("expected ErrInvalidProjectStatus, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownProjectTemplate = errors.New("unknown project template")
+
+// Names of the built-in project templates
+const (
+	ProjectTemplateSoftwareDelivery = "software-delivery"
+	ProjectTemplateResearch         = "research"
+	ProjectTemplateMarketing        = "marketing"
+)
+
+// ProjectTemplate pre-populates the settings of a new project for the kind of work it does: the taxonomy and
+// default tags its documents are filed with, the path scheme, the outlines of its documents and when its status
+// is rolled up and summarized
+type ProjectTemplate struct {
+	Name          string
+	Description   string
+	Documentation DocumentationConfig
+	Standup       StandupConfig
+}
+
+// ProjectTemplates returns the built-in project templates
+func ProjectTemplates() []ProjectTemplate {
+	return []ProjectTemplate{
+		{
+			Name:        ProjectTemplateSoftwareDelivery,
+			Description: "Decisions recorded as ADRs, weekly status rollups on the channel canvas and a daily standup",
+			Documentation: DocumentationConfig{
+				PathScheme:      PathSchemeTypeFirst,
+				StatusRollup:    RollupWeekly,
+				DefaultCategory: CategoryDevelopment,
+				DefaultTags:     []Tag{"delivery"},
+				Taxonomy:        []Category{CategoryDevelopment, CategoryProduct, CategoryOperations, CategoryQualityAssurance},
+				Diagrams:        true,
+				DigestCanvas:    true,
+				DocumentTemplates: map[MessageType]string{
+					MessageTypeDecision: "## Context\n\n## Decision\n\n## Consequences\n",
+					MessageTypeStatus:   "## Done\n\n## Next\n\n## Blockers\n",
+				},
+			},
+			Standup: StandupConfig{PromptAt: "09:30", SummaryAt: "11:30"},
+		},
+		{
+			Name:        ProjectTemplateResearch,
+			Description: "Hypotheses, findings and sources filed by date, with weekly status rollups and decisions reviewed quarterly",
+			Documentation: DocumentationConfig{
+				PathScheme:      PathSchemeDate,
+				StatusRollup:    RollupWeekly,
+				DefaultCategory: CategoryDataAnalysis,
+				DefaultTags:     []Tag{"research"},
+				Taxonomy:        []Category{CategoryDataAnalysis, CategoryProduct, CategoryOther},
+				ReviewAfterDays: 90,
+				DocumentTemplates: map[MessageType]string{
+					MessageTypeIdea:        "## Hypothesis\n\n## Method\n\n## Expected outcome\n",
+					MessageTypeDecision:    "## Question\n\n## Findings\n\n## Decision\n\n## Open questions\n",
+					MessageTypeInformation: "## Source\n\n## Summary\n\n## Implications\n",
+				},
+			},
+		},
+		{
+			Name:        ProjectTemplateMarketing,
+			Description: "Campaign ideas and results by title, with weekly status rollups on the channel canvas and a morning standup",
+			Documentation: DocumentationConfig{
+				PathScheme:      PathSchemeTitle,
+				StatusRollup:    RollupWeekly,
+				DefaultCategory: CategoryProduct,
+				DefaultTags:     []Tag{"marketing"},
+				Taxonomy:        []Category{CategoryProduct, CategoryDataAnalysis, CategoryOther},
+				DigestCanvas:    true,
+				DocumentTemplates: map[MessageType]string{
+					MessageTypeIdea:   "## Campaign\n\n## Audience\n\n## Channels\n\n## Success metric\n",
+					MessageTypeStatus: "## Launched\n\n## Results\n\n## Next\n",
+				},
+			},
+			Standup: StandupConfig{PromptAt: "10:00", SummaryAt: "12:00"},
+		},
+	}
+}
+
+// ProjectTemplateNamed returns the built-in project template of a name
+func ProjectTemplateNamed(name string) (ProjectTemplate, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, template := range ProjectTemplates() {
+		if template.Name == name {
+			return template, nil
+		}
+	}
+	return ProjectTemplate{}, fmt.Errorf("%w %q, use one of %s", ErrUnknownProjectTemplate, name, strings.Join(ProjectTemplateNames(), ", "))
+}
+
+// ProjectTemplateNames returns the names of the built-in project templates
+func ProjectTemplateNames() []string {
+	templates := ProjectTemplates()
+	names := make([]string, len(templates))
+	for i, template := range templates {
+		names[i] = template.Name
+	}
+	return names
+}
+
+// Apply configures a project with the settings of the template. The repository, branch and base path of the
+// project are kept, they depend on where its documentation goes rather than on the kind of work it does.
+func (t ProjectTemplate) Apply(project *Project) error {
+	docConfig := t.Documentation.clone()
+	current := project.Documentation()
+	docConfig.Repository = current.Repository
+	docConfig.Branch = current.Branch
+	docConfig.BasePath = current.BasePath
+	if err := project.ConfigureDocumentation(docConfig); err != nil {
+		return err
+	}
+
+	standup := project.Standup()
+	if standup.PromptAt == "" {
+		standup.PromptAt = t.Standup.PromptAt
+		standup.SummaryAt = t.Standup.SummaryAt
+	}
+	return project.ConfigureStandup(standup)
+}
+
+// ProjectDraft is a project submitted in the onboarding wizard, created from a template and bound to the
+// channel the wizard was started in
+type ProjectDraft struct {
+	metadata  ProjectMetadata
+	template  ProjectTemplate
+	channelID string
+	creator   string
+}
+
+// NewProjectDraft creates a ProjectDraft. The project needs a name and at least one goal, KPIs are optional.
+func NewProjectDraft(name, templateName, description string, goals, kpis []string, channelID, creator string) (*ProjectDraft, error) {
+	name = strings.TrimSpace(name)
+	if err := validateProjectName(name); err != nil {
+		return nil, err
+	}
+	goals = trimLines(goals)
+	if err := validateProjectGoals(goals); err != nil {
+		return nil, err
+	}
+	template, err := ProjectTemplateNamed(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectDraft{
+		metadata: ProjectMetadata{
+			Name:          name,
+			Description:   strings.TrimSpace(description),
+			BusinessGoals: goals,
+			KPIs:          trimLines(kpis),
+		},
+		template:  template,
+		channelID: strings.TrimSpace(channelID),
+		creator:   creator,
+	}, nil
+}
+
+// Metadata returns the name, description, goals and KPIs of the project
+func (d *ProjectDraft) Metadata() *ProjectMetadata {
+	metadata := d.metadata
+	metadata.BusinessGoals = append([]string(nil), d.metadata.BusinessGoals...)
+	metadata.KPIs = append([]string(nil), d.metadata.KPIs...)
+	return &metadata
+}
+
+// Template returns the template the project is created from
+func (d *ProjectDraft) Template() ProjectTemplate {
+	return d.template
+}
+
+// ChannelID returns the channel the project is bound to, empty when it is not bound to any
+func (d *ProjectDraft) ChannelID() string {
+	return d.channelID
+}
+
+// Creator returns who submitted the draft
+func (d *ProjectDraft) Creator() string {
+	return d.creator
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTemplates(t *testing.T) {
+	for _, template := range ProjectTemplates() {
+		t.Run(template.Name, func(t *testing.T) {
+			assert.NotEmpty(t, template.Description)
+			assert.NoError(t, template.Documentation.Validate())
+			assert.NoError(t, template.Standup.Validate())
+		})
+	}
+}
+
+func TestProjectTemplateNamed(t *testing.T) {
+	template, err := ProjectTemplateNamed(" Research ")
+	require.NoError(t, err)
+	assert.Equal(t, ProjectTemplateResearch, template.Name)
+
+	_, err = ProjectTemplateNamed("sales")
+	assert.ErrorIs(t, err, ErrUnknownProjectTemplate)
+	assert.Contains(t, err.Error(), "software-delivery, research, marketing")
+}
+
+func TestProjectTemplate_Apply(t *testing.T) {
+	project := MustNewProject("Billing", "", []string{"Ship invoicing"})
+	require.NoError(t, project.ConfigureDocumentation(DocumentationConfig{Repository: "acme/billing-docs", BasePath: "billing", DefaultCategory: CategoryOther}))
+
+	template, err := ProjectTemplateNamed(ProjectTemplateSoftwareDelivery)
+	require.NoError(t, err)
+	require.NoError(t, template.Apply(project))
+
+	docConfig := project.Documentation()
+	assert.Equal(t, "acme/billing-docs", docConfig.Repository)
+	assert.Equal(t, "billing", docConfig.BasePath)
+	assert.Equal(t, CategoryDevelopment, docConfig.DefaultCategory)
+	assert.Equal(t, PathSchemeTypeFirst, docConfig.PathScheme)
+	assert.Equal(t, []Category{CategoryDevelopment, CategoryProduct, CategoryOperations, CategoryQualityAssurance}, docConfig.Categories())
+	assert.Contains(t, docConfig.TemplateFor(MessageTypeDecision), "## Consequences")
+	assert.Empty(t, docConfig.TemplateFor(MessageTypeIdea))
+	assert.Equal(t, "09:30", project.Standup().PromptAt)
+
+	// Templates are copied, changing the project leaves the template alone
+	docConfig.DocumentTemplates[MessageTypeDecision] = "## Changed\n"
+	assert.Contains(t, template.Documentation.TemplateFor(MessageTypeDecision), "## Context")
+}
+
+func TestNewProjectDraft(t *testing.T) {
+	draft, err := NewProjectDraft(" Pricing ", "marketing", " Launch pricing ", []string{"- Grow signups", ""}, []string{"Conversion"}, " C0001 ", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Pricing", draft.Metadata().Name)
+	assert.Equal(t, "Launch pricing", draft.Metadata().Description)
+	assert.Equal(t, []string{"Grow signups"}, draft.Metadata().BusinessGoals)
+	assert.Equal(t, []string{"Conversion"}, draft.Metadata().KPIs)
+	assert.Equal(t, ProjectTemplateMarketing, draft.Template().Name)
+	assert.Equal(t, "C0001", draft.ChannelID())
+	assert.Equal(t, "alice", draft.Creator())
+
+	_, err = NewProjectDraft(" ", "marketing", "", []string{"Grow signups"}, nil, "", "alice")
+	assert.ErrorIs(t, err, ErrInvalidProjectName)
+
+	_, err = NewProjectDraft("Pricing", "marketing", "", []string{" "}, nil, "", "alice")
+	assert.ErrorIs(t, err, ErrInvalidProjectGoals)
+
+	_, err = NewProjectDraft("Pricing", "sales", "", []string{"Grow signups"}, nil, "", "alice")
+	assert.ErrorIs(t, err, ErrUnknownProjectTemplate)
+}
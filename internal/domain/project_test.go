@@ -198,3 +198,88 @@ func TestProject_DataImmutability(t *testing.T) {
 		assert.Equal(t, "Milestone 1", project.Milestones()[0].name)
 	})
 }
+
+func TestProject_Lifecycle(t *testing.T) {
+	t.Run("new project is active", func(t *testing.T) {
+		project := MustNewProject("name", "desc", []string{"goal"})
+
+		assert.Equal(t, ProjectStatusActive, project.Status())
+		assert.True(t, project.AcceptsMessages())
+		assert.False(t, project.IsReadOnly())
+	})
+
+	t.Run("paused project stops accepting messages", func(t *testing.T) {
+		project := MustNewProject("name", "desc", []string{"goal"})
+
+		assert.NoError(t, project.Pause())
+		assert.False(t, project.AcceptsMessages())
+		assert.False(t, project.IsReadOnly())
+
+		assert.NoError(t, project.Resume())
+		assert.True(t, project.AcceptsMessages())
+	})
+
+	t.Run("archived project is read-only", func(t *testing.T) {
+		project := MustNewProject("name", "desc", []string{"goal"})
+
+		assert.NoError(t, project.Archive())
+		assert.True(t, project.IsReadOnly())
+		assert.False(t, project.AcceptsMessages())
+		assert.NotZero(t, project.ArchivedAt())
+
+		assert.ErrorIs(t, project.AddKPI("KPI"), ErrProjectArchived)
+		assert.ErrorIs(t, project.UpdateDescription("new"), ErrProjectArchived)
+		assert.ErrorIs(t, project.UpdateGoals([]string{"new goal"}), ErrProjectArchived)
+		assert.ErrorIs(t, project.AddMilestone("M1", time.Now()), ErrProjectArchived)
+		assert.ErrorIs(t, project.BindChannel("C0001"), ErrProjectArchived)
+		assert.ErrorIs(t, project.Pause(), ErrInvalidStatusTransition)
+
+		assert.NoError(t, project.Resume())
+		assert.Zero(t, project.ArchivedAt())
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		project := MustNewProject("name", "desc", []string{"goal"})
+
+		assert.ErrorIs(t, project.TransitionTo("deleted"), ErrInvalidProjectStatus)
+		assert.ErrorIs(t, project.TransitionTo(ProjectStatusActive), ErrInvalidStatusTransition)
+	})
+}
+
+func TestProject_Channels(t *testing.T) {
+	project := MustNewProject("name", "desc", []string{"goal"})
+
+	assert.NoError(t, project.BindChannel("C0001"))
+	assert.NoError(t, project.BindChannel("C0001"))
+	assert.Error(t, project.BindChannel(" "))
+	assert.Equal(t, []string{"C0001"}, project.Channels())
+	assert.True(t, project.IsBoundTo("C0001"))
+
+	project.UnbindChannel("C0001")
+	assert.False(t, project.IsBoundTo("C0001"))
+}
+
+func TestProject_Settings(t *testing.T) {
+	t.Run("new project uses defaults", func(t *testing.T) {
+		project := MustNewProject("name", "desc", []string{"goal"})
+
+		assert.Equal(t, DefaultAutoDetectionConfig(), project.AutoDetection())
+		assert.Equal(t, "projects/"+project.ID().String(), project.DocumentationPath())
+	})
+
+	t.Run("configures settings", func(t *testing.T) {
+		project := MustNewProject("name", "desc", []string{"goal"})
+
+		assert.NoError(t, project.ConfigureDocumentation(DocumentationConfig{BasePath: "teams/billing/"}))
+		assert.Equal(t, "teams/billing", project.DocumentationPath())
+		assert.ErrorIs(t, project.ConfigureAutoDetection(AutoDetectionConfig{MinConfidence: 2}), ErrInvalidAutoDetectionConfig)
+	})
+
+	t.Run("archived project settings are read-only", func(t *testing.T) {
+		project := MustNewProject("name", "desc", []string{"goal"})
+		assert.NoError(t, project.Archive())
+
+		assert.ErrorIs(t, project.ConfigureAutoDetection(DefaultAutoDetectionConfig()), ErrProjectArchived)
+		assert.ErrorIs(t, project.ConfigureDocumentation(DefaultDocumentationConfig()), ErrProjectArchived)
+	})
+}
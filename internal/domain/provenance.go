@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidProvenanceKey = errors.New("provenance key must have at least 32 bytes")
+	ErrUnsigned             = errors.New("document has no provenance signature")
+	ErrInvalidSignature     = errors.New("provenance signature does not match the document")
+)
+
+// BotVersion is the version of the bot recorded as the generator of documents, set at build time with
+// -ldflags "-X github.com/massimo-ua/quill/internal/domain.BotVersion=1.4.0"
+var BotVersion = "dev"
+
+const (
+	// signatureKey is the front matter key holding the provenance signature
+	signatureKey = "signature"
+	// signaturePrefix names the algorithm of the signature
+	signaturePrefix = "hmac-sha256:"
+)
+
+// Generator returns how documents name the bot that wrote them, like "quill/1.4.0"
+func Generator() string {
+	return "quill/" + BotVersion
+}
+
+// RecordProvenance adds a message to the sources of a document, with the model and prompt version that analyzed it
+// and the bot version writing it. A document built from several messages keeps the analysis of the latest one.
+func RecordProvenance(fm *FrontMatter, msg *Message) {
+	sources := fm.GetList("source_messages")
+	if !containsString(sources, msg.ID().String()) {
+		sources = append(sources, msg.ID().String())
+	}
+	fm.SetList("source_messages", sources)
+	if model := msg.Model(); model != "" {
+		fm.Set("model", model)
+	}
+	if version := msg.PromptVersion(); version != "" {
+		fm.Set("prompt_version", version)
+	}
+	fm.Set("generator", Generator())
+}
+
+// HasProvenance checks if the front matter describes a document the bot generated from messages
+func HasProvenance(fm *FrontMatter) bool {
+	return fm.Has("source_messages") || fm.Has("source_message")
+}
+
+// ProvenanceSigner signs generated documents with an HMAC over their front matter and body, so consumers
+// holding the key can tell the bot wrote the document as it is
+type ProvenanceSigner struct {
+	key []byte
+}
+
+// NewProvenanceSigner creates a ProvenanceSigner with a secret key shared with the consumers verifying documents
+func NewProvenanceSigner(key []byte) (*ProvenanceSigner, error) {
+	if len(key) < 32 {
+		return nil, ErrInvalidProvenanceKey
+	}
+	signer := &ProvenanceSigner{key: make([]byte, len(key))}
+	copy(signer.key, key)
+	return signer, nil
+}
+
+// Sign returns the document with the signature of its content in the front matter, replacing an earlier signature
+func (s *ProvenanceSigner) Sign(content string) (string, error) {
+	fm, body, err := ParseFrontMatter(content)
+	if err != nil {
+		return "", err
+	}
+	fm.Delete(signatureKey)
+	fm.Set(signatureKey, signaturePrefix+s.mac(fm, body))
+	return fm.Apply(body), nil
+}
+
+// Verify checks that the document is signed and was not changed since
+func (s *ProvenanceSigner) Verify(content string) error {
+	fm, body, err := ParseFrontMatter(content)
+	if err != nil {
+		return err
+	}
+	signature, ok := strings.CutPrefix(fm.Get(signatureKey), signaturePrefix)
+	if !ok {
+		return ErrUnsigned
+	}
+	fm.Delete(signatureKey)
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	actual, _ := hex.DecodeString(s.mac(fm, body))
+	if !hmac.Equal(expected, actual) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// mac returns the hex HMAC of the document as it renders without its signature
+func (s *ProvenanceSigner) mac(fm *FrontMatter, body string) string {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(fm.Apply(body)))
+	return hex.EncodeToString(h.Sum(nil))
+}
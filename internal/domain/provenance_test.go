@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordProvenance(t *testing.T) {
+	first, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+	require.NoError(t, err)
+	first.RecordModel("ollama:llama3")
+	first.RecordPromptVersion("v1")
+	second, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("And Redis for caching"), MessageTypeDecision, CategoryDevelopment, nil)
+	require.NoError(t, err)
+	second.RecordModel("openai:gpt-4o")
+
+	fm := NewFrontMatter()
+	assert.False(t, HasProvenance(fm))
+	RecordProvenance(fm, first)
+	RecordProvenance(fm, second)
+	RecordProvenance(fm, second)
+
+	assert.True(t, HasProvenance(fm))
+	assert.Equal(t, []string{first.ID().String(), second.ID().String()}, fm.GetList("source_messages"))
+	assert.Equal(t, "openai:gpt-4o", fm.Get("model"))
+	assert.Equal(t, "v1", fm.Get("prompt_version"))
+	assert.Equal(t, "quill/"+BotVersion, fm.Get("generator"))
+}
+
+func TestProvenanceSigner(t *testing.T) {
+	signer, err := NewProvenanceSigner(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	document := "---\ntype: \"decision\"\nsource_messages: [\"01HPZ\"]\n---\n\n# Use Postgres\n\nWe will use Postgres.\n"
+
+	signed, err := signer.Sign(document)
+	require.NoError(t, err)
+	assert.Contains(t, signed, "signature: \"hmac-sha256:")
+	assert.NoError(t, signer.Verify(signed))
+
+	resigned, err := signer.Sign(strings.Replace(signed, "We will use Postgres.", "We will use Postgres 16.", 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(resigned, "signature:"))
+	assert.NoError(t, signer.Verify(resigned))
+
+	other, err := NewProvenanceSigner(bytes.Repeat([]byte{8}, 32))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		content string
+		signer  *ProvenanceSigner
+		wantErr error
+	}{
+		{name: "changed body", content: strings.Replace(signed, "Postgres.", "MySQL.", 1), signer: signer, wantErr: ErrInvalidSignature},
+		{name: "changed front matter", content: strings.Replace(signed, "decision", "idea", 1), signer: signer, wantErr: ErrInvalidSignature},
+		{name: "other key", content: signed, signer: other, wantErr: ErrInvalidSignature},
+		{name: "unsigned", content: document, signer: signer, wantErr: ErrUnsigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.signer.Verify(tt.content), tt.wantErr)
+		})
+	}
+}
+
+func TestNewProvenanceSigner_ShortKey(t *testing.T) {
+	_, err := NewProvenanceSigner([]byte("secret"))
+
+	assert.ErrorIs(t, err, ErrInvalidProvenanceKey)
+}
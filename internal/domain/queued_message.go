@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+)
+
+var ErrInvalidQueuedMessage = errors.New("invalid queued message")
+
+// QueuedMessage is a chat message handed from the instance that received it to a processing worker.
+// The route is the chat provider's location of the message, the worker needs it to reply.
+type QueuedMessage struct {
+	message *Message
+	route   map[string]string
+	retries int
+}
+
+// NewQueuedMessage creates a QueuedMessage, the route may be empty for providers that need none
+func NewQueuedMessage(msg *Message, route map[string]string) (*QueuedMessage, error) {
+	if msg == nil {
+		return nil, ErrInvalidQueuedMessage
+	}
+	return &QueuedMessage{message: msg, route: cloneRoute(route)}, nil
+}
+
+// Message returns the queued chat message
+func (q *QueuedMessage) Message() *Message {
+	return q.message
+}
+
+// Route returns the chat provider's location of the message
+func (q *QueuedMessage) Route() map[string]string {
+	return cloneRoute(q.route)
+}
+
+// Retries returns how many times the message was taken out of the dead-letter queue to be processed again
+func (q *QueuedMessage) Retries() int {
+	return q.retries
+}
+
+// Retry returns the message queued again after processing it failed
+func (q *QueuedMessage) Retry() *QueuedMessage {
+	return &QueuedMessage{message: q.message, route: cloneRoute(q.route), retries: q.retries + 1}
+}
+
+func cloneRoute(route map[string]string) map[string]string {
+	if len(route) == 0 {
+		return nil
+	}
+	clone := make(map[string]string, len(route))
+	for key, value := range route {
+		clone[key] = value
+	}
+	return clone
+}
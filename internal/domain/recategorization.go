@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidRecategorization = errors.New("invalid recategorization")
+
+// Recategorization files a document under another category on request of a person
+type Recategorization struct {
+	path      string
+	messageID string
+	category  Category
+	actor     string
+}
+
+// NewRecategorization creates a Recategorization of the document generated from the message
+func NewRecategorization(path, messageID string, category Category, actor string) (*Recategorization, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, ErrEmptyDocumentPath
+	}
+	if !isDocumentCategory(category) {
+		return nil, ErrInvalidCategory
+	}
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return nil, fmt.Errorf("%w: the person asking for it is unknown", ErrInvalidRecategorization)
+	}
+
+	return &Recategorization{
+		path:      path,
+		messageID: strings.TrimSpace(messageID),
+		category:  category,
+		actor:     actor,
+	}, nil
+}
+
+// Path returns the path of the document to recategorize
+func (r *Recategorization) Path() string {
+	return r.path
+}
+
+// MessageID returns the ID of the message the document was generated from, empty when unknown
+func (r *Recategorization) MessageID() string {
+	return r.messageID
+}
+
+// Category returns the category the document is filed under
+func (r *Recategorization) Category() Category {
+	return r.category
+}
+
+// Actor returns who picked the category
+func (r *Recategorization) Actor() string {
+	return r.actor
+}
+
+// RecategorizedPath returns where a document filed under one category belongs under another.
+// Directories named after the old category are renamed; paths without one, like those of the
+// date and thread schemes, stay where they are.
+func RecategorizedPath(path string, from, to Category) string {
+	if from == to || from == "" {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == from.String() {
+			segments[i] = to.String()
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isDocumentCategory(category Category) bool {
+	for _, c := range DocumentCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewRecategorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		category Category
+		actor    string
+		wantErr  error
+	}{
+		{name: "valid", path: "docs/other/pricing.md", category: CategoryProduct, actor: "U0001"},
+		{name: "empty path", path: " ", category: CategoryProduct, actor: "U0001", wantErr: ErrEmptyDocumentPath},
+		{name: "unknown category", path: "docs/other/pricing.md", category: CategoryUnknown, actor: "U0001", wantErr: ErrInvalidCategory},
+		{name: "missing actor", path: "docs/other/pricing.md", category: CategoryProduct, actor: "", wantErr: ErrInvalidRecategorization},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := NewRecategorization(tt.path, "MSG1", tt.category, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewRecategorization() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (change.Path() != tt.path || change.Category() != tt.category || change.MessageID() != "MSG1") {
+				t.Errorf("NewRecategorization() = %+v", change)
+			}
+		})
+	}
+}
+
+func TestRecategorizedPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		from Category
+		to   Category
+		want string
+	}{
+		{
+			name: "category scheme",
+			path: "docs/other/2024-05-01-pricing.md",
+			from: CategoryOther,
+			to:   CategoryProduct,
+			want: "docs/product/2024-05-01-pricing.md",
+		},
+		{
+			name: "type scheme",
+			path: "docs/decision/development/2024-05-01-adopt-postgres.md",
+			from: CategoryDevelopment,
+			to:   CategoryOperations,
+			want: "docs/decision/operations/2024-05-01-adopt-postgres.md",
+		},
+		{
+			name: "date scheme has no category",
+			path: "docs/2024/05/01/pricing.md",
+			from: CategoryOther,
+			to:   CategoryProduct,
+			want: "docs/2024/05/01/pricing.md",
+		},
+		{
+			name: "file name is kept",
+			path: "docs/other/other.md",
+			from: CategoryOther,
+			to:   CategoryProduct,
+			want: "docs/product/other.md",
+		},
+		{
+			name: "same category",
+			path: "docs/other/pricing.md",
+			from: CategoryOther,
+			to:   CategoryOther,
+			want: "docs/other/pricing.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecategorizedPath(tt.path, tt.from, tt.to); got != tt.want {
+				t.Errorf("RecategorizedPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
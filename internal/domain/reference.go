@@ -3,6 +3,8 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 )
 
@@ -14,6 +16,14 @@ const (
 	ReferenceTypeMessage ReferenceType = "message"
 	// ReferenceTypeDocument represents a reference to a document
 	ReferenceTypeDocument ReferenceType = "document"
+	// ReferenceTypeThread represents a reference to a conversation thread
+	ReferenceTypeThread ReferenceType = "thread"
+	// ReferenceTypeURL represents a reference to an external link
+	ReferenceTypeURL ReferenceType = "url"
+	// ReferenceTypeIssue represents a reference to an issue tracker item (e.g. PROJ-123, owner/repo#42)
+	ReferenceTypeIssue ReferenceType = "issue"
+	// ReferenceTypeUser represents a reference to a user (e.g. U024BE7LH, @jane)
+	ReferenceTypeUser ReferenceType = "user"
 	// ReferenceTypeUnknown represents an unrecognized reference type
 	ReferenceTypeUnknown ReferenceType = "unknown"
 )
@@ -26,14 +36,43 @@ var (
 	validReferenceTypes = map[ReferenceType]bool{
 		ReferenceTypeMessage:  true,
 		ReferenceTypeDocument: true,
+		ReferenceTypeThread:   true,
+		ReferenceTypeURL:      true,
+		ReferenceTypeIssue:    true,
+		ReferenceTypeUser:     true,
 		ReferenceTypeUnknown:  true,
 	}
+
+	// issueKeyPattern matches Jira-style keys (PROJ-123) and GitHub-style issues (#42, owner/repo#42)
+	issueKeyPattern = regexp.MustCompile(`^(?:[A-Z][A-Z0-9_]+-[0-9]+|(?:[\w.-]+/[\w.-]+)?#[0-9]+)$`)
+	// userPattern matches chat user IDs and handles, optionally wrapped as a Slack mention
+	userPattern = regexp.MustCompile(`^@?[\w.-]+$`)
+)
+
+// ReferenceResolution is how a reference was matched against the known targets
+type ReferenceResolution string
+
+const (
+	// ReferenceDetected is a reference as it was detected, not matched yet
+	ReferenceDetected ReferenceResolution = ""
+	// ReferenceResolved is a reference pointing to the target it was matched to
+	ReferenceResolved ReferenceResolution = "resolved"
+	// ReferenceUnresolved is a reference no target matched, kept as it was detected
+	ReferenceUnresolved ReferenceResolution = "unresolved"
 )
 
+// IsValid checks if the ReferenceResolution is valid
+func (r ReferenceResolution) IsValid() bool {
+	return r == ReferenceDetected || r == ReferenceResolved || r == ReferenceUnresolved
+}
+
 // Reference is a value object that represents a reference to another entity in the system
 type Reference struct {
 	refType ReferenceType
-	value   string // either message ID or document path
+	value   string // message ID, document path or thread ID
+	// resolution and confidence record how the reference was matched, they do not take part in equality
+	resolution ReferenceResolution
+	confidence float64
 }
 
 // NewReference creates a new Reference instance
@@ -42,13 +81,19 @@ func NewReference(refType ReferenceType, value string) (*Reference, error) {
 		return nil, fmt.Errorf("%w: invalid reference type", ErrInvalidReference)
 	}
 
-	if strings.TrimSpace(value) == "" {
+	value = strings.TrimSpace(value)
+	if value == "" {
 		return nil, ErrEmptyReferenceValue
 	}
 
+	value, err := normalizeReferenceValue(refType, value)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Reference{
 		refType: refType,
-		value:   strings.TrimSpace(value),
+		value:   value,
 	}, nil
 }
 
@@ -72,6 +117,26 @@ func NewDocumentReference(documentPath string) (*Reference, error) {
 	return NewReference(ReferenceTypeDocument, documentPath)
 }
 
+// NewThreadReference creates a new Reference to a thread
+func NewThreadReference(threadID string) (*Reference, error) {
+	return NewReference(ReferenceTypeThread, threadID)
+}
+
+// NewURLReference creates a new Reference to an external link
+func NewURLReference(link string) (*Reference, error) {
+	return NewReference(ReferenceTypeURL, link)
+}
+
+// NewIssueReference creates a new Reference to an issue tracker item
+func NewIssueReference(key string) (*Reference, error) {
+	return NewReference(ReferenceTypeIssue, key)
+}
+
+// NewUserReference creates a new Reference to a user
+func NewUserReference(user string) (*Reference, error) {
+	return NewReference(ReferenceTypeUser, user)
+}
+
 // Type returns the type of the reference
 func (r Reference) Type() ReferenceType {
 	return r.refType
@@ -82,6 +147,38 @@ func (r Reference) Value() string {
 	return r.value
 }
 
+// Resolution returns how the reference was matched against the known targets
+func (r Reference) Resolution() ReferenceResolution {
+	return r.resolution
+}
+
+// Confidence returns how confident the resolver was in the match, 0 unless the reference is resolved
+func (r Reference) Confidence() float64 {
+	return r.confidence
+}
+
+// IsUnresolved checks if no target matched the reference
+func (r Reference) IsUnresolved() bool {
+	return r.resolution == ReferenceUnresolved
+}
+
+// WithResolution returns a copy of the reference recording how it was matched. Unresolved and detected
+// references have no confidence.
+func (r Reference) WithResolution(resolution ReferenceResolution, confidence float64) (*Reference, error) {
+	if !resolution.IsValid() {
+		return nil, fmt.Errorf("%w: invalid resolution %q", ErrInvalidReference, resolution)
+	}
+	if confidence < 0 || confidence > 1 {
+		return nil, ErrInvalidConfidenceScore
+	}
+	if resolution != ReferenceResolved {
+		confidence = 0
+	}
+	r.resolution = resolution
+	r.confidence = confidence
+	return &r, nil
+}
+
 // String returns a string representation of the reference
 func (r Reference) String() string {
 	return fmt.Sprintf("%s:%s", r.refType, r.value)
@@ -127,7 +224,66 @@ func (rt ReferenceType) IsDocument() bool {
 	return rt == ReferenceTypeDocument
 }
 
+// IsThread checks if the ReferenceType is a thread reference
+func (rt ReferenceType) IsThread() bool {
+	return rt == ReferenceTypeThread
+}
+
+// IsURL checks if the ReferenceType is an external link reference
+func (rt ReferenceType) IsURL() bool {
+	return rt == ReferenceTypeURL
+}
+
+// IsIssue checks if the ReferenceType is an issue reference
+func (rt ReferenceType) IsIssue() bool {
+	return rt == ReferenceTypeIssue
+}
+
+// IsUser checks if the ReferenceType is a user reference
+func (rt ReferenceType) IsUser() bool {
+	return rt == ReferenceTypeUser
+}
+
+// IsExternal checks if the ReferenceType points outside of the system
+func (rt ReferenceType) IsExternal() bool {
+	return rt.IsURL() || rt.IsIssue()
+}
+
 // IsUnknown checks if the ReferenceType is unknown
 func (rt ReferenceType) IsUnknown() bool {
 	return rt == ReferenceTypeUnknown
 }
+
+// normalizeReferenceValue applies the validation rules of each reference type and returns the canonical value
+func normalizeReferenceValue(refType ReferenceType, value string) (string, error) {
+	switch refType {
+	case ReferenceTypeURL:
+		value = strings.Trim(value, "<>")
+		// Slack formats links as <https://example.com|label>
+		if idx := strings.Index(value, "|"); idx >= 0 {
+			value = value[:idx]
+		}
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("%w: invalid URL %q", ErrInvalidReference, value)
+		}
+		return u.String(), nil
+	case ReferenceTypeIssue:
+		if !issueKeyPattern.MatchString(value) {
+			return "", fmt.Errorf("%w: invalid issue key %q", ErrInvalidReference, value)
+		}
+		return value, nil
+	case ReferenceTypeUser:
+		// Slack mentions look like <@U024BE7LH> or <@U024BE7LH|jane>
+		value = strings.TrimPrefix(strings.Trim(value, "<>"), "@")
+		if idx := strings.Index(value, "|"); idx >= 0 {
+			value = value[:idx]
+		}
+		if !userPattern.MatchString(value) {
+			return "", fmt.Errorf("%w: invalid user %q", ErrInvalidReference, value)
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
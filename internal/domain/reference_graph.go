@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrInvalidGraphEdge = errors.New("invalid reference graph edge")
+)
+
+// ReferenceGraph is a directed graph of references between documents, messages and threads.
+// An edge from A to B means that A references B. Edges between documents can carry a relation,
+// like a decision superseding another.
+type ReferenceGraph struct {
+	nodes     map[string]Reference
+	outgoing  map[string]map[string]bool
+	incoming  map[string]map[string]bool
+	relations map[string]map[string]Relation
+}
+
+// NewReferenceGraph creates a new empty ReferenceGraph
+func NewReferenceGraph() *ReferenceGraph {
+	return &ReferenceGraph{
+		nodes:     make(map[string]Reference),
+		outgoing:  make(map[string]map[string]bool),
+		incoming:  make(map[string]map[string]bool),
+		relations: make(map[string]map[string]Relation),
+	}
+}
+
+// AddNode registers a reference as a node of the graph
+func (g *ReferenceGraph) AddNode(ref Reference) {
+	key := ref.String()
+	if _, exists := g.nodes[key]; exists {
+		return
+	}
+	g.nodes[key] = ref
+	g.outgoing[key] = make(map[string]bool)
+	g.incoming[key] = make(map[string]bool)
+}
+
+// HasNode checks if the reference is a node of the graph
+func (g *ReferenceGraph) HasNode(ref Reference) bool {
+	_, exists := g.nodes[ref.String()]
+	return exists
+}
+
+// Link adds an edge meaning that from references to
+func (g *ReferenceGraph) Link(from, to Reference) error {
+	if from.Equals(to) {
+		return ErrInvalidGraphEdge
+	}
+
+	g.AddNode(from)
+	g.AddNode(to)
+	g.outgoing[from.String()][to.String()] = true
+	g.incoming[to.String()][from.String()] = true
+	return nil
+}
+
+// Relate adds an edge meaning that from references to with the given relation
+func (g *ReferenceGraph) Relate(from, to Reference, relation Relation) error {
+	if !from.Type().IsDocument() || !to.Type().IsDocument() || !relation.IsValid() {
+		return ErrInvalidGraphEdge
+	}
+	if err := g.Link(from, to); err != nil {
+		return err
+	}
+	if g.relations[from.String()] == nil {
+		g.relations[from.String()] = make(map[string]Relation)
+	}
+	g.relations[from.String()][to.String()] = relation
+	return nil
+}
+
+// RelationBetween returns the relation of the edge from one node to another, if it carries one
+func (g *ReferenceGraph) RelationBetween(from, to Reference) (Relation, bool) {
+	relation, ok := g.relations[from.String()][to.String()]
+	return relation, ok
+}
+
+// Unlink removes the edge between from and to if it exists
+func (g *ReferenceGraph) Unlink(from, to Reference) {
+	if out, ok := g.outgoing[from.String()]; ok {
+		delete(out, to.String())
+	}
+	if related, ok := g.relations[from.String()]; ok {
+		delete(related, to.String())
+	}
+	if in, ok := g.incoming[to.String()]; ok {
+		delete(in, from.String())
+	}
+}
+
+// RemoveNode removes a reference and all its edges from the graph
+func (g *ReferenceGraph) RemoveNode(ref Reference) {
+	key := ref.String()
+	for to := range g.outgoing[key] {
+		delete(g.incoming[to], key)
+	}
+	for from := range g.incoming[key] {
+		delete(g.outgoing[from], key)
+		delete(g.relations[from], key)
+	}
+	delete(g.relations, key)
+	delete(g.outgoing, key)
+	delete(g.incoming, key)
+	delete(g.nodes, key)
+}
+
+// References returns the references made by the given node
+func (g *ReferenceGraph) References(ref Reference) []Reference {
+	return g.collect(g.outgoing[ref.String()])
+}
+
+// Backlinks returns the nodes that reference the given node
+func (g *ReferenceGraph) Backlinks(ref Reference) []Reference {
+	return g.collect(g.incoming[ref.String()])
+}
+
+// Orphans returns the document nodes that are not referenced by any other node
+func (g *ReferenceGraph) Orphans() []Reference {
+	var orphans []Reference
+	for key, node := range g.nodes {
+		if node.Type().IsDocument() && len(g.incoming[key]) == 0 {
+			orphans = append(orphans, node)
+		}
+	}
+	sortReferences(orphans)
+	return orphans
+}
+
+// Documents returns the document nodes of the graph
+func (g *ReferenceGraph) Documents() []Reference {
+	var docs []Reference
+	for _, node := range g.nodes {
+		if node.Type().IsDocument() {
+			docs = append(docs, node)
+		}
+	}
+	sortReferences(docs)
+	return docs
+}
+
+// NodeCount returns the number of nodes in the graph
+func (g *ReferenceGraph) NodeCount() int {
+	return len(g.nodes)
+}
+
+func (g *ReferenceGraph) collect(keys map[string]bool) []Reference {
+	refs := make([]Reference, 0, len(keys))
+	for key := range keys {
+		refs = append(refs, g.nodes[key])
+	}
+	sortReferences(refs)
+	return refs
+}
+
+// sortReferences orders references by their string form to keep results deterministic
+func sortReferences(refs []Reference) {
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestReferenceGraph_Link(t *testing.T) {
+	doc := *MustNewReference(ReferenceTypeDocument, "docs/development/decision.md")
+	msg := *MustNewReference(ReferenceTypeMessage, "msg_123")
+
+	tests := []struct {
+		name      string
+		from      Reference
+		to        Reference
+		wantError bool
+	}{
+		{
+			name:      "links document to message",
+			from:      doc,
+			to:        msg,
+			wantError: false,
+		},
+		{
+			name:      "rejects self reference",
+			from:      doc,
+			to:        doc,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewReferenceGraph()
+			err := g.Link(tt.from, tt.to)
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !g.HasNode(tt.from) || !g.HasNode(tt.to) {
+				t.Error("expected both nodes to be registered")
+			}
+			if refs := g.References(tt.from); len(refs) != 1 || !refs[0].Equals(tt.to) {
+				t.Errorf("References() = %v, want [%v]", refs, tt.to)
+			}
+			if refs := g.Backlinks(tt.to); len(refs) != 1 || !refs[0].Equals(tt.from) {
+				t.Errorf("Backlinks() = %v, want [%v]", refs, tt.from)
+			}
+		})
+	}
+}
+
+func TestReferenceGraph_Orphans(t *testing.T) {
+	decision := *MustNewReference(ReferenceTypeDocument, "docs/development/decision.md")
+	idea := *MustNewReference(ReferenceTypeDocument, "docs/product/idea.md")
+	status := *MustNewReference(ReferenceTypeDocument, "docs/operations/status.md")
+	msg := *MustNewReference(ReferenceTypeMessage, "msg_123")
+
+	g := NewReferenceGraph()
+	g.AddNode(status)
+	if err := g.Link(idea, decision); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := g.Link(idea, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	orphans := g.Orphans()
+	if len(orphans) != 2 {
+		t.Fatalf("expected 2 orphans, got %d: %v", len(orphans), orphans)
+	}
+	if !orphans[0].Equals(status) || !orphans[1].Equals(idea) {
+		t.Errorf("Orphans() = %v, want [%v %v]", orphans, status, idea)
+	}
+}
+
+func TestReferenceGraph_RemoveNode(t *testing.T) {
+	decision := *MustNewReference(ReferenceTypeDocument, "docs/development/decision.md")
+	idea := *MustNewReference(ReferenceTypeDocument, "docs/product/idea.md")
+
+	g := NewReferenceGraph()
+	if err := g.Link(idea, decision); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g.RemoveNode(idea)
+
+	if g.HasNode(idea) {
+		t.Error("expected node to be removed")
+	}
+	if refs := g.Backlinks(decision); len(refs) != 0 {
+		t.Errorf("expected no backlinks, got %v", refs)
+	}
+	if g.NodeCount() != 1 {
+		t.Errorf("NodeCount() = %d, want 1", g.NodeCount())
+	}
+}
+
+func TestReferenceGraph_Relate(t *testing.T) {
+	older := *MustNewReference(ReferenceTypeDocument, "docs/development/use-mysql.md")
+	newer := *MustNewReference(ReferenceTypeDocument, "docs/development/use-postgres.md")
+	msg := *MustNewReference(ReferenceTypeMessage, "msg_123")
+
+	g := NewReferenceGraph()
+	if err := g.Relate(newer, older, RelationSupersedes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := g.Relate(newer, msg, RelationAmends); err == nil {
+		t.Error("expected relating a message to fail")
+	}
+	if err := g.Relate(newer, older, "replaces"); err == nil {
+		t.Error("expected an unknown relation to fail")
+	}
+
+	if relation, ok := g.RelationBetween(newer, older); !ok || relation != RelationSupersedes {
+		t.Errorf("RelationBetween() = %q, %v, want %q", relation, ok, RelationSupersedes)
+	}
+	if _, ok := g.RelationBetween(older, newer); ok {
+		t.Error("expected the relation to have a direction")
+	}
+	if refs := g.Backlinks(older); len(refs) != 1 || !refs[0].Equals(newer) {
+		t.Errorf("Backlinks() = %v, want [%v]", refs, newer)
+	}
+
+	g.RemoveNode(older)
+	if _, ok := g.RelationBetween(newer, older); ok {
+		t.Error("expected the relation to be removed with the node")
+	}
+}
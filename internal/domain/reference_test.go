@@ -274,6 +274,45 @@ func TestReference_Equals(t *testing.T) {
 	}
 }
 
+func TestReference_WithResolution(t *testing.T) {
+	ref := MustNewReference(ReferenceTypeDocument, "docs/postgres.md")
+
+	tests := []struct {
+		name           string
+		resolution     ReferenceResolution
+		confidence     float64
+		wantConfidence float64
+		wantErr        bool
+	}{
+		{name: "resolved", resolution: ReferenceResolved, confidence: 0.75, wantConfidence: 0.75},
+		{name: "unresolved drops the confidence", resolution: ReferenceUnresolved, confidence: 0.75, wantConfidence: 0},
+		{name: "detected", resolution: ReferenceDetected, wantConfidence: 0},
+		{name: "invalid resolution", resolution: "maybe", wantErr: true},
+		{name: "confidence above 1", resolution: ReferenceResolved, confidence: 1.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ref.WithResolution(tt.resolution, tt.confidence)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithResolution() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Resolution() != tt.resolution || got.Confidence() != tt.wantConfidence {
+				t.Errorf("WithResolution() = %s %v, want %s %v", got.Resolution(), got.Confidence(), tt.resolution, tt.wantConfidence)
+			}
+			if !got.Equals(*ref) {
+				t.Errorf("resolution changed the reference to %s", got)
+			}
+			if ref.Resolution() != ReferenceDetected {
+				t.Errorf("the original reference was changed")
+			}
+		})
+	}
+}
+
 func TestParseReference(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -296,6 +335,27 @@ func TestParseReference(t *testing.T) {
 			wantValue: "docs/decisions/001.md",
 			wantError: false,
 		},
+		{
+			name:      "valid url reference",
+			input:     "url:https://example.com/docs?page=1",
+			wantType:  ReferenceTypeURL,
+			wantValue: "https://example.com/docs?page=1",
+			wantError: false,
+		},
+		{
+			name:      "valid issue reference",
+			input:     "issue:PROJ-123",
+			wantType:  ReferenceTypeIssue,
+			wantValue: "PROJ-123",
+			wantError: false,
+		},
+		{
+			name:      "valid user reference",
+			input:     "user:<@U024BE7LH>",
+			wantType:  ReferenceTypeUser,
+			wantValue: "U024BE7LH",
+			wantError: false,
+		},
 		{
 			name:      "invalid format",
 			input:     "invalid_format",
@@ -437,3 +497,101 @@ func TestReferenceType_Methods(t *testing.T) {
 		})
 	}
 }
+
+func TestNewReference_TypeValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		refType   ReferenceType
+		value     string
+		wantValue string
+		wantError bool
+	}{
+		{
+			name:      "https url",
+			refType:   ReferenceTypeURL,
+			value:     "https://github.com/massimo-ua/quill",
+			wantValue: "https://github.com/massimo-ua/quill",
+		},
+		{
+			name:      "slack formatted url",
+			refType:   ReferenceTypeURL,
+			value:     "<https://example.com|example>",
+			wantValue: "https://example.com",
+		},
+		{
+			name:      "url without scheme",
+			refType:   ReferenceTypeURL,
+			value:     "example.com/page",
+			wantError: true,
+		},
+		{
+			name:      "non http url",
+			refType:   ReferenceTypeURL,
+			value:     "ftp://example.com/file",
+			wantError: true,
+		},
+		{
+			name:      "jira issue key",
+			refType:   ReferenceTypeIssue,
+			value:     "OPS-42",
+			wantValue: "OPS-42",
+		},
+		{
+			name:      "github issue number",
+			refType:   ReferenceTypeIssue,
+			value:     "#17",
+			wantValue: "#17",
+		},
+		{
+			name:      "github issue with repository",
+			refType:   ReferenceTypeIssue,
+			value:     "massimo-ua/quill#17",
+			wantValue: "massimo-ua/quill#17",
+		},
+		{
+			name:      "lowercase issue key",
+			refType:   ReferenceTypeIssue,
+			value:     "ops-42",
+			wantError: true,
+		},
+		{
+			name:      "user handle",
+			refType:   ReferenceTypeUser,
+			value:     "@jane.doe",
+			wantValue: "jane.doe",
+		},
+		{
+			name:      "slack mention with label",
+			refType:   ReferenceTypeUser,
+			value:     "<@U024BE7LH|jane>",
+			wantValue: "U024BE7LH",
+		},
+		{
+			name:      "user with spaces",
+			refType:   ReferenceTypeUser,
+			value:     "jane doe",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := NewReference(tt.refType, tt.value)
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ref.Value() != tt.wantValue {
+				t.Errorf("expected value %v, got %v", tt.wantValue, ref.Value())
+			}
+			if !ref.Type().IsValid() {
+				t.Errorf("expected valid type, got %v", ref.Type())
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidReplyBatch = errors.New("invalid reply batch")
+
+// ReplyBatch is the capture confirmations of a thread waiting in the outbox to be posted together, once the
+// batch window ends and the channel is out of its quiet hours. The summary is posted in reply to the first
+// message of the batch.
+type ReplyBatch struct {
+	key       string
+	messageID string
+	channelID string
+	mode      ReplyMode
+	replies   []string
+	route     map[string]string
+	dueAt     time.Time
+}
+
+// NewReplyBatch starts the batch of a thread, keyed by the thread ID or by the message ID of messages outside
+// threads, with the confirmation of its first message
+func NewReplyBatch(key, messageID, channelID string, mode ReplyMode, reply string, dueAt time.Time) (*ReplyBatch, error) {
+	return RestoreReplyBatch(key, messageID, channelID, mode, []string{reply}, nil, dueAt)
+}
+
+// RestoreReplyBatch recreates a batch kept in the outbox
+func RestoreReplyBatch(key, messageID, channelID string, mode ReplyMode, replies []string, route map[string]string, dueAt time.Time) (*ReplyBatch, error) {
+	key = strings.TrimSpace(key)
+	messageID = strings.TrimSpace(messageID)
+	if key == "" || messageID == "" || !mode.IsValid() || len(replies) == 0 {
+		return nil, ErrInvalidReplyBatch
+	}
+	for _, reply := range replies {
+		if strings.TrimSpace(reply) == "" {
+			return nil, ErrInvalidReplyBatch
+		}
+	}
+	return &ReplyBatch{
+		key:       key,
+		messageID: messageID,
+		channelID: channelID,
+		mode:      mode,
+		replies:   append([]string(nil), replies...),
+		route:     route,
+		dueAt:     dueAt.UTC(),
+	}, nil
+}
+
+// Key returns the thread ID the batch collects the confirmations of, or the message ID outside threads
+func (b *ReplyBatch) Key() string {
+	return b.key
+}
+
+// MessageID returns the first message of the batch, the summary replies to it
+func (b *ReplyBatch) MessageID() string {
+	return b.messageID
+}
+
+// ChannelID returns the channel of the thread
+func (b *ReplyBatch) ChannelID() string {
+	return b.channelID
+}
+
+// Mode returns where the summary is posted
+func (b *ReplyBatch) Mode() ReplyMode {
+	return b.mode
+}
+
+// Replies returns the confirmations of the batch, in the order they were added
+func (b *ReplyBatch) Replies() []string {
+	return append([]string(nil), b.replies...)
+}
+
+// Add appends a confirmation to the batch
+func (b *ReplyBatch) Add(reply string) error {
+	if strings.TrimSpace(reply) == "" {
+		return ErrInvalidReplyBatch
+	}
+	b.replies = append(b.replies, reply)
+	return nil
+}
+
+// Route returns the chat provider's route to the first message, so the summary can be posted after a restart or
+// by another replica; nil when the provider needs none
+func (b *ReplyBatch) Route() map[string]string {
+	return b.route
+}
+
+// SetRoute records the chat provider's route to the first message
+func (b *ReplyBatch) SetRoute(route map[string]string) {
+	b.route = route
+}
+
+// DueAt returns when the summary is to be posted
+func (b *ReplyBatch) DueAt() time.Time {
+	return b.dueAt
+}
+
+// IsDue checks if the summary is to be posted by now
+func (b *ReplyBatch) IsDue(now time.Time) bool {
+	return !b.dueAt.After(now)
+}
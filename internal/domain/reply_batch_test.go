@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReplyBatch(t *testing.T) {
+	due := time.Date(2024, 6, 3, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	batch, err := NewReplyBatch(" 01J0ZJ ", "01J0ZK", "C0001", ReplyModeThread, "📝 Captured idea", due)
+	require.NoError(t, err)
+	assert.Equal(t, "01J0ZJ", batch.Key())
+	assert.Equal(t, "01J0ZK", batch.MessageID())
+	assert.Equal(t, "C0001", batch.ChannelID())
+	assert.Equal(t, ReplyModeThread, batch.Mode())
+	assert.Equal(t, time.UTC, batch.DueAt().Location())
+	assert.Nil(t, batch.Route())
+
+	require.NoError(t, batch.Add("✅ Recorded decision"))
+	assert.Equal(t, []string{"📝 Captured idea", "✅ Recorded decision"}, batch.Replies())
+	assert.ErrorIs(t, batch.Add(" "), ErrInvalidReplyBatch)
+
+	assert.False(t, batch.IsDue(due.Add(-time.Second)))
+	assert.True(t, batch.IsDue(due))
+
+	for name, create := range map[string]func() (*ReplyBatch, error){
+		"missing key": func() (*ReplyBatch, error) {
+			return NewReplyBatch(" ", "01J0ZK", "C0001", ReplyModeThread, "reply", due)
+		},
+		"missing message": func() (*ReplyBatch, error) {
+			return NewReplyBatch("01J0ZJ", "", "C0001", ReplyModeThread, "reply", due)
+		},
+		"invalid mode": func() (*ReplyBatch, error) { return NewReplyBatch("01J0ZJ", "01J0ZK", "C0001", "loud", "reply", due) },
+		"blank reply": func() (*ReplyBatch, error) {
+			return NewReplyBatch("01J0ZJ", "01J0ZK", "C0001", ReplyModeThread, " ", due)
+		},
+		"no replies": func() (*ReplyBatch, error) {
+			return RestoreReplyBatch("01J0ZJ", "01J0ZK", "C0001", ReplyModeThread, nil, nil, due)
+		},
+	} {
+		_, err := create()
+		assert.ErrorIs(t, err, ErrInvalidReplyBatch, name)
+	}
+}
+
+func TestRestoreReplyBatch(t *testing.T) {
+	route := map[string]string{"channel": "C0001", "ts": "1700000000.000100"}
+	batch, err := RestoreReplyBatch("01J0ZJ", "01J0ZK", "C0001", ReplyModeDirect, []string{"a", "b"}, route, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, batch.Replies())
+	assert.Equal(t, route, batch.Route())
+	assert.Equal(t, ReplyModeDirect, batch.Mode())
+}
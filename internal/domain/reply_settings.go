@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidReplyConfig = errors.New("invalid reply config")
+
+// QuietHours is a daily period during which the bot posts no capture confirmations.
+// The period may wrap around midnight, like 22:00 to 07:00.
+type QuietHours struct {
+	// Start is the local time quiet hours begin, formatted as 15:04
+	Start string `json:"start,omitempty"`
+	// End is the local time quiet hours end, formatted as 15:04
+	End string `json:"end,omitempty"`
+	// Timezone is the IANA name of the time zone of Start and End, defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// IsSet checks if quiet hours are configured
+func (q QuietHours) IsSet() bool {
+	return strings.TrimSpace(q.Start) != "" || strings.TrimSpace(q.End) != ""
+}
+
+// Validate ensures the quiet hours can be applied
+func (q QuietHours) Validate() error {
+	if !q.IsSet() {
+		return nil
+	}
+	if _, err := parseClock(q.Start); err != nil {
+		return fmt.Errorf("%w: quiet hours start: %v", ErrInvalidReplyConfig, err)
+	}
+	if _, err := parseClock(q.End); err != nil {
+		return fmt.Errorf("%w: quiet hours end: %v", ErrInvalidReplyConfig, err)
+	}
+	if _, err := q.location(); err != nil {
+		return fmt.Errorf("%w: quiet hours timezone: %v", ErrInvalidReplyConfig, err)
+	}
+	return nil
+}
+
+// Contains checks if the time falls within quiet hours
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.IsSet() {
+		return false
+	}
+	start, errStart := parseClock(q.Start)
+	end, errEnd := parseClock(q.End)
+	loc, errLoc := q.location()
+	if errStart != nil || errEnd != nil || errLoc != nil || start == end {
+		return false
+	}
+
+	local := t.In(loc)
+	now := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// EndAfter returns the first time quiet hours end after t, or t itself when quiet hours are not set or cannot
+// be applied
+func (q QuietHours) EndAfter(t time.Time) time.Time {
+	if !q.IsSet() {
+		return t
+	}
+	end, errEnd := parseClock(q.End)
+	loc, errLoc := q.location()
+	if errEnd != nil || errLoc != nil {
+		return t
+	}
+
+	local := t.In(loc)
+	endsAt := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).Add(end)
+	if !endsAt.After(t) {
+		endsAt = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc).Add(end)
+	}
+	return endsAt
+}
+
+func (q QuietHours) location() (*time.Location, error) {
+	if strings.TrimSpace(q.Timezone) == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(strings.TrimSpace(q.Timezone))
+}
+
+// parseClock parses a 15:04 time of day into the duration since midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day like 22:00", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ReplyMode is where capture confirmations are posted
+type ReplyMode string
+
+const (
+	// ReplyModeThread posts confirmations in the thread of the captured message, visible to everyone
+	ReplyModeThread ReplyMode = "thread"
+	// ReplyModeEphemeral posts confirmations in the thread, visible only to the author of the message
+	ReplyModeEphemeral ReplyMode = "ephemeral"
+	// ReplyModeDirect sends confirmations to the author of the message in a direct message
+	ReplyModeDirect ReplyMode = "dm"
+	// ReplyModeReaction reacts to the message with an emoji, the confirmation is shown on request
+	ReplyModeReaction ReplyMode = "reaction"
+)
+
+// defaultReactions are the emoji acknowledging captured messages, named without colons
+var defaultReactions = map[MessageType]string{
+	MessageTypeIdea:        "memo",
+	MessageTypeDecision:    "white_check_mark",
+	MessageTypeStatus:      "bar_chart",
+	MessageTypeInformation: "information_source",
+	MessageTypeUnknown:     "grey_question",
+}
+
+// IsValid checks if the reply mode is supported, empty means the thread
+func (m ReplyMode) IsValid() bool {
+	switch m {
+	case "", ReplyModeThread, ReplyModeEphemeral, ReplyModeDirect, ReplyModeReaction:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPrivate checks if confirmations are only shown to the author of the message
+func (m ReplyMode) IsPrivate() bool {
+	return m == ReplyModeEphemeral || m == ReplyModeDirect
+}
+
+// String returns the reply mode, defaulting to the thread
+func (m ReplyMode) String() string {
+	if m == "" {
+		return string(ReplyModeThread)
+	}
+	return string(m)
+}
+
+// ReplyConfig controls how often the bot confirms captured messages in a project's channels
+type ReplyConfig struct {
+	// Mode is where confirmations are posted, empty posts them in the thread
+	Mode ReplyMode `json:"mode,omitempty"`
+	// Reactions overrides the emoji of each message type in reaction mode, named without colons like "memo"
+	Reactions map[MessageType]string `json:"reactions,omitempty"`
+	// QuietHours suppresses confirmations during part of the day
+	QuietHours QuietHours `json:"quietHours,omitempty"`
+	// MaxRepliesPerHour limits confirmations per channel in any hour, zero means no limit
+	MaxRepliesPerHour int `json:"maxRepliesPerHour,omitempty"`
+	// BatchWindow collects the confirmations of a thread posted within the window into one summary, zero replies at once
+	BatchWindow time.Duration `json:"batchWindow,omitempty"`
+	// Routes send the confirmations and digest items of some categories to other channels instead of the thread
+	Routes RoutingTable `json:"routes,omitempty"`
+}
+
+// DefaultReplyConfig returns the reply settings of a new project, every capture is confirmed at once
+func DefaultReplyConfig() ReplyConfig {
+	return ReplyConfig{}
+}
+
+// Validate ensures the reply settings are usable
+func (c ReplyConfig) Validate() error {
+	if !c.Mode.IsValid() {
+		return fmt.Errorf("%w: unknown reply mode %q", ErrInvalidReplyConfig, c.Mode)
+	}
+	if err := c.QuietHours.Validate(); err != nil {
+		return err
+	}
+	for messageType, emoji := range c.Reactions {
+		if !messageType.IsValid() {
+			return fmt.Errorf("%w: reaction for unknown message type %q", ErrInvalidReplyConfig, messageType)
+		}
+		if name := strings.Trim(strings.TrimSpace(emoji), ":"); name == "" || strings.ContainsAny(name, " :") {
+			return fmt.Errorf("%w: %q is not an emoji name", ErrInvalidReplyConfig, emoji)
+		}
+	}
+	if c.MaxRepliesPerHour < 0 {
+		return fmt.Errorf("%w: max replies per hour cannot be negative", ErrInvalidReplyConfig)
+	}
+	if c.BatchWindow < 0 {
+		return fmt.Errorf("%w: batch window cannot be negative", ErrInvalidReplyConfig)
+	}
+	return c.Routes.Validate()
+}
+
+// ReactionFor returns the emoji acknowledging a message of the type, without colons
+func (c ReplyConfig) ReactionFor(messageType MessageType) string {
+	if emoji, ok := c.Reactions[messageType]; ok {
+		return strings.Trim(strings.TrimSpace(emoji), ":")
+	}
+	if emoji, ok := defaultReactions[messageType]; ok {
+		return emoji
+	}
+	return defaultReactions[MessageTypeUnknown]
+}
+
+func (c ReplyConfig) clone() ReplyConfig {
+	c.Routes = c.Routes.clone()
+	if c.Reactions == nil {
+		return c
+	}
+	reactions := make(map[MessageType]string, len(c.Reactions))
+	for messageType, emoji := range c.Reactions {
+		reactions[messageType] = emoji
+	}
+	c.Reactions = reactions
+	return c
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplyConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ReplyConfig
+		wantErr error
+	}{
+		{
+			name:   "default config",
+			config: DefaultReplyConfig(),
+		},
+		{
+			name:   "all settings",
+			config: ReplyConfig{QuietHours: QuietHours{Start: "22:00", End: "07:30", Timezone: "Europe/Kyiv"}, MaxRepliesPerHour: 5, BatchWindow: time.Minute},
+		},
+		{
+			name:   "direct messages",
+			config: ReplyConfig{Mode: ReplyModeDirect},
+		},
+		{
+			name:   "custom reactions",
+			config: ReplyConfig{Mode: ReplyModeReaction, Reactions: map[MessageType]string{MessageTypeIdea: ":bulb:"}},
+		},
+		{
+			name:    "reaction for unknown type",
+			config:  ReplyConfig{Mode: ReplyModeReaction, Reactions: map[MessageType]string{"todo": "memo"}},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "reaction is not an emoji name",
+			config:  ReplyConfig{Mode: ReplyModeReaction, Reactions: map[MessageType]string{MessageTypeIdea: "light bulb"}},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "unknown mode",
+			config:  ReplyConfig{Mode: "email"},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "invalid start",
+			config:  ReplyConfig{QuietHours: QuietHours{Start: "10pm", End: "07:00"}},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "missing end",
+			config:  ReplyConfig{QuietHours: QuietHours{Start: "22:00"}},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "unknown timezone",
+			config:  ReplyConfig{QuietHours: QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "negative limit",
+			config:  ReplyConfig{MaxRepliesPerHour: -1},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "negative batch window",
+			config:  ReplyConfig{BatchWindow: -time.Second},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:   "routes",
+			config: ReplyConfig{Routes: RoutingTable{CategoryQualityAssurance: "C0QA"}},
+		},
+		{
+			name:    "route for unknown category",
+			config:  ReplyConfig{Routes: RoutingTable{"finance": "C0FIN"}},
+			wantErr: ErrInvalidReplyConfig,
+		},
+		{
+			name:    "route to a channel name",
+			config:  ReplyConfig{Routes: RoutingTable{CategoryQualityAssurance: "#qa"}},
+			wantErr: ErrInvalidReplyConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 6, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		t     time.Time
+		want  bool
+	}{
+		{name: "not set", quiet: QuietHours{}, t: at(23, 0), want: false},
+		{name: "within same-day period", quiet: QuietHours{Start: "12:00", End: "13:00"}, t: at(12, 30), want: true},
+		{name: "end is exclusive", quiet: QuietHours{Start: "12:00", End: "13:00"}, t: at(13, 0), want: false},
+		{name: "before midnight", quiet: QuietHours{Start: "22:00", End: "07:00"}, t: at(23, 15), want: true},
+		{name: "after midnight", quiet: QuietHours{Start: "22:00", End: "07:00"}, t: at(6, 59), want: true},
+		{name: "daytime", quiet: QuietHours{Start: "22:00", End: "07:00"}, t: at(9, 0), want: false},
+		{name: "timezone", quiet: QuietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"}, t: at(3, 0), want: true},
+		{name: "timezone daytime", quiet: QuietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"}, t: at(13, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Contains(tt.t); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHours_EndAfter(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		t     time.Time
+		want  time.Time
+	}{
+		{name: "not set", quiet: QuietHours{}, t: at(4, 23, 0), want: at(4, 23, 0)},
+		{name: "later today", quiet: QuietHours{Start: "12:00", End: "13:00"}, t: at(4, 12, 30), want: at(4, 13, 0)},
+		{name: "before midnight", quiet: QuietHours{Start: "22:00", End: "07:00"}, t: at(4, 23, 15), want: at(5, 7, 0)},
+		{name: "after midnight", quiet: QuietHours{Start: "22:00", End: "07:00"}, t: at(5, 6, 59), want: at(5, 7, 0)},
+		{name: "at the end", quiet: QuietHours{Start: "22:00", End: "07:00"}, t: at(5, 7, 0), want: at(6, 7, 0)},
+		{name: "timezone", quiet: QuietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"}, t: at(4, 3, 0), want: at(4, 11, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.EndAfter(tt.t); !got.Equal(tt.want) {
+				t.Errorf("EndAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplyConfig_ReactionFor(t *testing.T) {
+	cfg := ReplyConfig{Mode: ReplyModeReaction, Reactions: map[MessageType]string{MessageTypeIdea: ":bulb:"}}
+
+	tests := []struct {
+		messageType MessageType
+		want        string
+	}{
+		{MessageTypeIdea, "bulb"},
+		{MessageTypeDecision, "white_check_mark"},
+		{MessageTypeStatus, "bar_chart"},
+		{MessageType("todo"), "grey_question"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.messageType.String(), func(t *testing.T) {
+			if got := cfg.ReactionFor(tt.messageType); got != tt.want {
+				t.Errorf("ReactionFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidReprocess = errors.New("invalid reprocess request")
+
+// ReprocessRequest selects the documented messages to run through the current prompt and model again, so their
+// documents pick up improvements of the prompt
+type ReprocessRequest struct {
+	since       time.Time
+	category    Category
+	dryRun      bool
+	requestedBy string
+}
+
+// NewReprocessRequest creates a ReprocessRequest for the messages posted since a time, all of them when since is
+// zero, in a category, any when it is empty. A dry run reports the changes without storing them.
+func NewReprocessRequest(since time.Time, category Category, dryRun bool, requestedBy string) (*ReprocessRequest, error) {
+	requestedBy = strings.TrimSpace(requestedBy)
+	if requestedBy == "" {
+		return nil, fmt.Errorf("%w: requester is required", ErrInvalidReprocess)
+	}
+	if category != "" && (!category.IsValid() || category.IsUnknown()) {
+		return nil, fmt.Errorf("%w: category %q", ErrInvalidReprocess, category)
+	}
+	if since.After(time.Now()) {
+		return nil, fmt.Errorf("%w: since is in the future", ErrInvalidReprocess)
+	}
+	return &ReprocessRequest{since: since, category: category, dryRun: dryRun, requestedBy: requestedBy}, nil
+}
+
+// Since returns the time the selected messages were posted after, zero for all messages
+func (r *ReprocessRequest) Since() time.Time {
+	return r.since
+}
+
+// Category returns the category of the selected messages, empty for any category
+func (r *ReprocessRequest) Category() Category {
+	return r.category
+}
+
+// DryRun reports whether the changes are only reported
+func (r *ReprocessRequest) DryRun() bool {
+	return r.dryRun
+}
+
+// RequestedBy returns who asked for the reprocessing
+func (r *ReprocessRequest) RequestedBy() string {
+	return r.requestedBy
+}
+
+// String describes the selected messages, like "development messages since 2024-06-01"
+func (r *ReprocessRequest) String() string {
+	selection := "messages"
+	if r.category != "" {
+		selection = r.category.String() + " messages"
+	}
+	if r.since.IsZero() {
+		return "all " + selection
+	}
+	return selection + " since " + r.since.UTC().Format(time.DateOnly)
+}
+
+// Matches checks if a message is selected: it was documented, in the category and since the time. Anonymized
+// messages lost their text and are never reprocessed.
+func (r *ReprocessRequest) Matches(msg *Message) bool {
+	if msg.State() != MessageStateDocumented || msg.IsAnonymized() {
+		return false
+	}
+	if msg.Timestamp().Before(r.since) {
+		return false
+	}
+	return r.category == "" || msg.Category() == r.category
+}
+
+// BumpDocumentVersion increments the version in the front matter of a document and returns it. Documents
+// without one are at their first version.
+func BumpDocumentVersion(fm *FrontMatter) uint {
+	current := NewDefaultDocumentVersion()
+	raw := strings.TrimPrefix(fm.Get("version"), "v")
+	if n, err := strconv.ParseUint(raw, 10, 32); err == nil && n > 0 {
+		if version, err := NewDocumentVersion(uint(n), time.Now()); err == nil {
+			current = version
+		}
+	}
+	next := current.Increment()
+	fm.Set("version", strconv.FormatUint(uint64(next.Version()), 10))
+	return next.Version()
+}
+
+// ReprocessedDocument is a document regenerated from its message
+type ReprocessedDocument struct {
+	Path      string
+	MessageID string
+	// Version is the version of the document after the update
+	Version uint
+	Added   int
+	Removed int
+	// Diff is the unified diff of the update
+	Diff string
+}
+
+// ReprocessFailure is a message that could not be reprocessed
+type ReprocessFailure struct {
+	MessageID string
+	Error     string
+}
+
+// ReprocessReport tells what a reprocessing changed, for the person who asked for it
+type ReprocessReport struct {
+	DryRun bool
+	// Messages counts the messages that were analyzed again
+	Messages int
+	// Documents are the documents whose content changed
+	Documents []ReprocessedDocument
+	// Unchanged counts the messages whose documents were generated again with the same content
+	Unchanged   int
+	Failures    []ReprocessFailure
+	CompletedAt time.Time
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidResolution = errors.New("invalid reference resolution")
+)
+
+// ResolvedReference is the outcome of matching a detected reference against known targets
+type ResolvedReference struct {
+	original   *Reference
+	target     *Reference
+	confidence float64
+}
+
+// NewResolvedReference creates a ResolvedReference pointing to a concrete target
+func NewResolvedReference(original, target *Reference, confidence float64) (*ResolvedReference, error) {
+	if original == nil || target == nil {
+		return nil, ErrInvalidResolution
+	}
+	if confidence < 0 || confidence > 1 {
+		return nil, ErrInvalidConfidenceScore
+	}
+
+	return &ResolvedReference{
+		original:   original,
+		target:     target,
+		confidence: confidence,
+	}, nil
+}
+
+// NewUnresolvedReference creates a ResolvedReference for a reference without a matching target
+func NewUnresolvedReference(original *Reference) *ResolvedReference {
+	return &ResolvedReference{
+		original: original,
+	}
+}
+
+// Original returns the reference as it was detected
+func (r *ResolvedReference) Original() *Reference {
+	return r.original
+}
+
+// Target returns the canonical reference, or nil if the reference could not be resolved
+func (r *ResolvedReference) Target() *Reference {
+	return r.target
+}
+
+// Confidence returns how confident the resolver is in the match
+func (r *ResolvedReference) Confidence() float64 {
+	return r.confidence
+}
+
+// Reference returns the reference a message keeps: the target with the confidence of the match, or the original
+// marked unresolved
+func (r *ResolvedReference) Reference() *Reference {
+	if r.target == nil {
+		ref, _ := r.original.WithResolution(ReferenceUnresolved, 0)
+		return ref
+	}
+	ref, _ := r.target.WithResolution(ReferenceResolved, r.confidence)
+	return ref
+}
+
+// IsResolved checks if a concrete target was found
+func (r *ResolvedReference) IsResolved() bool {
+	return r.target != nil
+}
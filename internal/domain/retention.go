@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRetention = errors.New("invalid retention policy")
+
+// RetentionAction is what happens to a raw message once it is kept longer than the retention period
+type RetentionAction string
+
+const (
+	// RetentionDelete removes the message from the message repository
+	RetentionDelete RetentionAction = "delete"
+	// RetentionAnonymize keeps the message for statistics, without its text, sender, attachments and references
+	RetentionAnonymize RetentionAction = "anonymize"
+)
+
+// ParseRetentionAction returns the action named by s, like "delete" or "anonymize"
+func ParseRetentionAction(s string) (RetentionAction, error) {
+	action := RetentionAction(strings.ToLower(strings.TrimSpace(s)))
+	if !action.IsValid() {
+		return "", fmt.Errorf("%w: action %q, use delete or anonymize", ErrInvalidRetention, s)
+	}
+	return action, nil
+}
+
+// IsValid checks if the action is known
+func (a RetentionAction) IsValid() bool {
+	return a == RetentionDelete || a == RetentionAnonymize
+}
+
+// String returns the action
+func (a RetentionAction) String() string {
+	return string(a)
+}
+
+// RetentionPolicy limits how long raw chat messages are stored. Only the messages are purged,
+// the documents generated from them stay in the document store.
+type RetentionPolicy struct {
+	days   int
+	action RetentionAction
+}
+
+// NewRetentionPolicy creates a RetentionPolicy purging messages posted more than days ago
+func NewRetentionPolicy(days int, action RetentionAction) (RetentionPolicy, error) {
+	if days <= 0 {
+		return RetentionPolicy{}, fmt.Errorf("%w: %d days, must be positive", ErrInvalidRetention, days)
+	}
+	if !action.IsValid() {
+		return RetentionPolicy{}, fmt.Errorf("%w: action %q, use delete or anonymize", ErrInvalidRetention, action)
+	}
+	return RetentionPolicy{days: days, action: action}, nil
+}
+
+// Days returns how many days messages are kept
+func (p RetentionPolicy) Days() int {
+	return p.days
+}
+
+// Action returns what happens to expired messages
+func (p RetentionPolicy) Action() RetentionAction {
+	return p.action
+}
+
+// Cutoff returns the time before which messages posted are expired
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.Add(-time.Duration(p.days) * 24 * time.Hour)
+}
+
+// Expired checks if a message should be purged. Messages still in the pipeline are kept until they
+// are documented, ignored or failed, and anonymized messages were purged already.
+func (p RetentionPolicy) Expired(msg *Message, now time.Time) bool {
+	if msg == nil || msg.IsAnonymized() {
+		return false
+	}
+	state := msg.State()
+	if !state.IsFinal() && !state.IsFailed() {
+		return false
+	}
+	return msg.Timestamp().Before(p.Cutoff(now))
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetentionAction(t *testing.T) {
+	action, err := ParseRetentionAction(" Anonymize ")
+	require.NoError(t, err)
+	assert.Equal(t, RetentionAnonymize, action)
+
+	_, err = ParseRetentionAction("archive")
+	assert.ErrorIs(t, err, ErrInvalidRetention)
+}
+
+func TestNewRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		days    int
+		action  RetentionAction
+		wantErr bool
+	}{
+		{name: "delete", days: 90, action: RetentionDelete},
+		{name: "anonymize", days: 30, action: RetentionAnonymize},
+		{name: "no days", days: 0, action: RetentionDelete, wantErr: true},
+		{name: "unknown action", days: 30, action: "archive", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewRetentionPolicy(tt.days, tt.action)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRetention)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.days, policy.Days())
+			assert.Equal(t, tt.action, policy.Action())
+		})
+	}
+}
+
+func TestRetentionPolicy_Expired(t *testing.T) {
+	policy, err := NewRetentionPolicy(30, RetentionDelete)
+	require.NoError(t, err)
+	newMessage := func(finish func(*Message)) *Message {
+		msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, nil)
+		require.NoError(t, err)
+		finish(msg)
+		return msg
+	}
+	documented := func(msg *Message) {
+		require.NoError(t, msg.StartAnalysis())
+		require.NoError(t, msg.MarkDocumented())
+	}
+
+	later := time.Now().Add(31 * 24 * time.Hour)
+	tests := []struct {
+		name string
+		msg  *Message
+		now  time.Time
+		want bool
+	}{
+		{name: "documented long ago", msg: newMessage(documented), now: later, want: true},
+		{name: "failed long ago", msg: newMessage(func(msg *Message) { require.NoError(t, msg.MarkFailed("timeout")) }), now: later, want: true},
+		{name: "within the period", msg: newMessage(documented), now: time.Now()},
+		{name: "still in the pipeline", msg: newMessage(func(msg *Message) { require.NoError(t, msg.StartAnalysis()) }), now: later},
+		{name: "anonymized already", msg: newMessage(func(msg *Message) { documented(msg); msg.Anonymize() }), now: later},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Expired(tt.msg, tt.now))
+		})
+	}
+}
+
+func TestMessage_Anonymize(t *testing.T) {
+	ref, err := NewDocumentReference("docs/decisions/postgres.md")
+	require.NoError(t, err)
+	msg, err := NewMessage(common.GenerateID(), "jane", MustNewMessageContent("We will use Postgres"), MessageTypeDecision, CategoryDevelopment, []*Reference{ref})
+	require.NoError(t, err)
+	msg.RecordConfidence(0.8)
+	screenshot, err := NewAttachment("schema.png", "image/png", "https://files.example.com/schema.png")
+	require.NoError(t, err)
+	msg.AddAttachments(screenshot)
+
+	msg.Anonymize()
+	restored, err := MessageFromDTO(msg.ToDTO())
+
+	require.NoError(t, err)
+	assert.True(t, restored.IsAnonymized())
+	assert.Equal(t, AnonymizedSender, restored.Sender())
+	assert.NotContains(t, restored.Content().Text(), "Postgres")
+	assert.Empty(t, restored.Attachments())
+	assert.Empty(t, restored.References())
+	assert.Equal(t, MessageTypeDecision, restored.Type())
+	assert.Equal(t, 0.8, restored.Confidence())
+}
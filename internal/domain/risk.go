@@ -0,0 +1,411 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RiskRegisterFile is the living register of the risks raised in the documented messages
+const RiskRegisterFile = docsRoot + "/risks.md"
+
+// RiskLevel rates the severity or the likelihood of a risk
+type RiskLevel string
+
+const (
+	RiskLow    RiskLevel = "low"
+	RiskMedium RiskLevel = "medium"
+	RiskHigh   RiskLevel = "high"
+)
+
+// RiskStatus tells if a risk still needs attention
+type RiskStatus string
+
+const (
+	// RiskOpen risks have no mitigation yet
+	RiskOpen RiskStatus = "open"
+	// RiskMitigated risks have a plan keeping them in check
+	RiskMitigated RiskStatus = "mitigated"
+	// RiskClosed risks can no longer happen
+	RiskClosed RiskStatus = "closed"
+)
+
+var (
+	ErrInvalidRisk  = errors.New("invalid risk")
+	ErrRiskNotFound = errors.New("risk not found")
+)
+
+var (
+	// riskCuePattern matches the phrasing of risk statements, like "we might miss the deadline if…" or
+	// "the launch is at risk"
+	riskCuePattern = regexp.MustCompile(`(?i)\b(?:at risk|risks?|risky|in danger of|jeopardi[sz]e[sd]?|(?:might|may|could) (?:not|miss|slip|delay|fail|break|lose|run out|be late|be blocked))\b`)
+	// riskHeadingPattern matches the heading of a risk in the register, like "## R-3: Vendor API may slip"
+	riskHeadingPattern = regexp.MustCompile(`^## R-(\d+): (.+)$`)
+	// riskFieldPattern matches the fields of a risk in the register, like "- Severity: high"
+	riskFieldPattern = regexp.MustCompile(`^- ([A-Za-z]+): (.*)$`)
+	// riskSourcePattern matches the link to the document a risk was raised in
+	riskSourcePattern = regexp.MustCompile(`\]\(([^)]+)\)`)
+)
+
+// NewRiskLevel creates a RiskLevel from a string, an empty string rates medium
+func NewRiskLevel(s string) (RiskLevel, error) {
+	level := RiskLevel(strings.ToLower(strings.TrimSpace(s)))
+	if level == "" {
+		return RiskMedium, nil
+	}
+	if !level.IsValid() {
+		return "", fmt.Errorf("%w: level %q is not low, medium or high", ErrInvalidRisk, s)
+	}
+	return level, nil
+}
+
+// IsValid checks if the level is known
+func (l RiskLevel) IsValid() bool {
+	return l == RiskLow || l == RiskMedium || l == RiskHigh
+}
+
+// weight orders the levels, low first
+func (l RiskLevel) weight() int {
+	switch l {
+	case RiskHigh:
+		return 3
+	case RiskMedium:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// String returns the string representation of the level
+func (l RiskLevel) String() string {
+	return string(l)
+}
+
+// String returns the string representation of the status
+func (s RiskStatus) String() string {
+	return string(s)
+}
+
+// RiskStatement is a risk a message raises, as the AI agent or the risk cues read it
+type RiskStatement struct {
+	Statement  string `json:"statement"`
+	Severity   string `json:"severity"`
+	Likelihood string `json:"likelihood"`
+	// Owner is who the message puts in charge of the risk, empty when it names nobody
+	Owner      string `json:"owner"`
+	Mitigation string `json:"mitigation"`
+}
+
+// HasRiskCue checks if a text is phrased like it raises a risk, so the AI agent is only asked about those
+func HasRiskCue(text string) bool {
+	return riskCuePattern.MatchString(text)
+}
+
+// DetectRiskStatements returns the sentences of a text phrased like risks, rated medium, for when no AI agent
+// reads them
+func DetectRiskStatements(text string) []RiskStatement {
+	var statements []RiskStatement
+	for _, line := range strings.Split(text, "\n") {
+		for _, sentence := range sentenceEndPattern.Split(line, -1) {
+			sentence = strings.Join(strings.Fields(sentence), " ")
+			if sentence != "" && HasRiskCue(sentence) {
+				statements = append(statements, RiskStatement{Statement: sentence})
+			}
+		}
+	}
+	return statements
+}
+
+// Risk is an entry of the risk register
+type Risk struct {
+	id         int
+	statement  string
+	severity   RiskLevel
+	likelihood RiskLevel
+	owner      string
+	status     RiskStatus
+	mitigation string
+	raisedAt   time.Time
+	source     string
+}
+
+// ID returns the identifier of the risk in its register, like R-3
+func (r *Risk) ID() string {
+	return fmt.Sprintf("R-%d", r.id)
+}
+
+// Statement returns what could go wrong
+func (r *Risk) Statement() string {
+	return r.statement
+}
+
+// Severity returns how bad it would be if the risk happened
+func (r *Risk) Severity() RiskLevel {
+	return r.severity
+}
+
+// Likelihood returns how likely the risk is to happen
+func (r *Risk) Likelihood() RiskLevel {
+	return r.likelihood
+}
+
+// Owner returns who is in charge of the risk
+func (r *Risk) Owner() string {
+	return r.owner
+}
+
+// Status returns if the risk still needs attention
+func (r *Risk) Status() RiskStatus {
+	return r.status
+}
+
+// Mitigation returns how the risk is kept in check, empty when it is not
+func (r *Risk) Mitigation() string {
+	return r.mitigation
+}
+
+// RaisedAt returns when the risk was raised
+func (r *Risk) RaisedAt() time.Time {
+	return r.raisedAt
+}
+
+// Source returns the path of the document the risk was raised in, empty when unknown
+func (r *Risk) Source() string {
+	return r.source
+}
+
+// Unmitigated checks if the risk is open without a mitigation
+func (r *Risk) Unmitigated() bool {
+	return r.status == RiskOpen && r.mitigation == ""
+}
+
+// score orders risks by severity and likelihood
+func (r *Risk) score() int {
+	return r.severity.weight() * r.likelihood.weight()
+}
+
+// RiskRegister is the list of the risks raised in the documented messages, kept in RiskRegisterFile
+type RiskRegister struct {
+	risks []*Risk
+}
+
+// NewRiskRegister creates an empty risk register
+func NewRiskRegister() *RiskRegister {
+	return &RiskRegister{}
+}
+
+// ParseRiskRegister reads a register rendered by Render. Entries it cannot read are dropped.
+func ParseRiskRegister(content string) *RiskRegister {
+	register := NewRiskRegister()
+
+	var current *Risk
+	flush := func() {
+		if current != nil && current.severity.IsValid() && current.likelihood.IsValid() {
+			register.risks = append(register.risks, current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if match := riskHeadingPattern.FindStringSubmatch(trimmed); match != nil {
+			flush()
+			id, _ := strconv.Atoi(match[1])
+			current = &Risk{id: id, statement: match[2], status: RiskOpen}
+			continue
+		}
+		match := riskFieldPattern.FindStringSubmatch(trimmed)
+		if current == nil || match == nil {
+			continue
+		}
+		value := strings.TrimSpace(match[2])
+		switch match[1] {
+		case "Severity":
+			current.severity = RiskLevel(value)
+		case "Likelihood":
+			current.likelihood = RiskLevel(value)
+		case "Owner":
+			current.owner = value
+		case "Status":
+			current.status = RiskStatus(value)
+		case "Mitigation":
+			if value != riskNoMitigation {
+				current.mitigation = value
+			}
+		case "Raised":
+			if fields := strings.Fields(value); len(fields) > 0 {
+				if at, err := time.Parse("2006-01-02", fields[0]); err == nil {
+					current.raisedAt = at
+				}
+			}
+			if source := riskSourcePattern.FindStringSubmatch(value); source != nil {
+				current.source = path.Join(path.Dir(RiskRegisterFile), source[1])
+			}
+		}
+	}
+	flush()
+	return register
+}
+
+// riskNoMitigation is rendered in place of the mitigation of risks without one
+const riskNoMitigation = "none yet"
+
+// Risks returns the risks of the register, the most severe and likely first
+func (g *RiskRegister) Risks() []*Risk {
+	risks := append([]*Risk(nil), g.risks...)
+	sort.SliceStable(risks, func(i, j int) bool {
+		if risks[i].status == RiskClosed || risks[j].status == RiskClosed {
+			return risks[i].status != RiskClosed && risks[j].status == RiskClosed
+		}
+		if risks[i].score() != risks[j].score() {
+			return risks[i].score() > risks[j].score()
+		}
+		return risks[i].id < risks[j].id
+	})
+	return risks
+}
+
+// Find returns the risk with an ID like R-3
+func (g *RiskRegister) Find(id string) (*Risk, error) {
+	for _, risk := range g.risks {
+		if strings.EqualFold(risk.ID(), strings.TrimSpace(id)) {
+			return risk, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRiskNotFound, id)
+}
+
+// Add records a risk raised in the document at source, owned by the owner the statement names or else by
+// owner. It returns nil when a risk that is not closed already says the same.
+func (g *RiskRegister) Add(statement RiskStatement, owner, source string, at time.Time) (*Risk, error) {
+	text := strings.Join(strings.Fields(statement.Statement), " ")
+	if text == "" {
+		return nil, fmt.Errorf("%w: statement is required", ErrInvalidRisk)
+	}
+	severity, err := NewRiskLevel(statement.Severity)
+	if err != nil {
+		return nil, err
+	}
+	likelihood, err := NewRiskLevel(statement.Likelihood)
+	if err != nil {
+		return nil, err
+	}
+	for _, risk := range g.risks {
+		if risk.status != RiskClosed && strings.EqualFold(risk.statement, text) {
+			return nil, nil
+		}
+	}
+	if named := strings.TrimSpace(statement.Owner); named != "" {
+		owner = named
+	}
+
+	next := 0
+	for _, risk := range g.risks {
+		if risk.id > next {
+			next = risk.id
+		}
+	}
+	risk := &Risk{
+		id:         next + 1,
+		statement:  text,
+		severity:   severity,
+		likelihood: likelihood,
+		owner:      strings.TrimSpace(owner),
+		status:     RiskOpen,
+		raisedAt:   at.UTC(),
+		source:     source,
+	}
+	if mitigation := strings.Join(strings.Fields(statement.Mitigation), " "); mitigation != "" {
+		risk.mitigation = mitigation
+		risk.status = RiskMitigated
+	}
+	g.risks = append(g.risks, risk)
+	return risk, nil
+}
+
+// Mitigate records how a risk is kept in check
+func (g *RiskRegister) Mitigate(id, mitigation string) (*Risk, error) {
+	risk, err := g.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	mitigation = strings.Join(strings.Fields(mitigation), " ")
+	if mitigation == "" {
+		return nil, fmt.Errorf("%w: mitigation is required", ErrInvalidRisk)
+	}
+	risk.mitigation = mitigation
+	risk.status = RiskMitigated
+	return risk, nil
+}
+
+// Close records that a risk can no longer happen
+func (g *RiskRegister) Close(id string) (*Risk, error) {
+	risk, err := g.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	risk.status = RiskClosed
+	return risk, nil
+}
+
+// Unmitigated returns the open risks without a mitigation grouped by owner, the most severe first
+func (g *RiskRegister) Unmitigated() map[string][]*Risk {
+	byOwner := make(map[string][]*Risk)
+	for _, risk := range g.Risks() {
+		if risk.Unmitigated() {
+			byOwner[risk.owner] = append(byOwner[risk.owner], risk)
+		}
+	}
+	return byOwner
+}
+
+// Render renders the register, the most severe and likely risks first and closed risks last
+func (g *RiskRegister) Render() string {
+	var b strings.Builder
+	b.WriteString("# Risk Register\n\n")
+	b.WriteString("Risks raised in the documented messages, the most severe and likely first. Owners are reminded of the open risks without a mitigation.\n")
+
+	dir := path.Dir(RiskRegisterFile)
+	for _, risk := range g.Risks() {
+		b.WriteString(fmt.Sprintf("\n## %s: %s\n\n", risk.ID(), risk.statement))
+		b.WriteString(fmt.Sprintf("- Severity: %s\n", risk.severity))
+		b.WriteString(fmt.Sprintf("- Likelihood: %s\n", risk.likelihood))
+		if risk.owner != "" {
+			b.WriteString(fmt.Sprintf("- Owner: %s\n", risk.owner))
+		}
+		b.WriteString(fmt.Sprintf("- Status: %s\n", risk.status))
+		mitigation := risk.mitigation
+		if mitigation == "" {
+			mitigation = riskNoMitigation
+		}
+		b.WriteString(fmt.Sprintf("- Mitigation: %s\n", mitigation))
+		raised := risk.raisedAt.Format("2006-01-02")
+		if link, err := filepath.Rel(dir, risk.source); risk.source != "" && err == nil {
+			raised += fmt.Sprintf(" in [%s](%s)", path.Base(risk.source), filepath.ToSlash(link))
+		}
+		b.WriteString(fmt.Sprintf("- Raised: %s\n", raised))
+	}
+	return b.String()
+}
+
+// RenderRiskReminder renders the digest reminding an owner of their open risks without a mitigation
+func RenderRiskReminder(owner string, risks []*Risk, link string) string {
+	var b strings.Builder
+	if owner == "" {
+		b.WriteString(fmt.Sprintf("⚠️ %d risks without an owner have no mitigation yet, the register is in %s", len(risks), link))
+	} else {
+		b.WriteString(fmt.Sprintf("⚠️ %d risks owned by %s have no mitigation yet, the register is in %s", len(risks), owner, link))
+	}
+	for _, risk := range risks {
+		b.WriteString(fmt.Sprintf("\n• *%s* (%s severity, %s likelihood, raised %s): %s",
+			risk.ID(), risk.severity, risk.likelihood, risk.raisedAt.Format("2006-01-02"), risk.statement))
+	}
+	b.WriteString(fmt.Sprintf("\nRecord a mitigation with `%s risk mitigate <id> <how>`, or `%s risk close <id>` once it can no longer happen.", CommandPrefix, CommandPrefix))
+	return b.String()
+}
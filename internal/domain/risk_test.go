@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectRiskStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []RiskStatement
+	}{
+		{
+			name: "might miss",
+			text: "Vendor API is late. We might miss the deadline if it slips again!\nOtherwise fine.",
+			want: []RiskStatement{{Statement: "We might miss the deadline if it slips again"}},
+		},
+		{name: "at risk", text: "The March launch is at risk", want: []RiskStatement{{Statement: "The March launch is at risk"}}},
+		{name: "risk of", text: "There is a risk of data loss during the migration.", want: []RiskStatement{{Statement: "There is a risk of data loss during the migration"}}},
+		{name: "could fail", text: "The import could fail on large files", want: []RiskStatement{{Statement: "The import could fail on large files"}}},
+		{name: "no risk", text: "We shipped the invoice export. Next is refunds."},
+		{name: "may as permission", text: "You may deploy after lunch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectRiskStatements(tt.text))
+			assert.Equal(t, len(tt.want) > 0, HasRiskCue(tt.text))
+		})
+	}
+}
+
+func TestNewRiskLevel(t *testing.T) {
+	level, err := NewRiskLevel(" High ")
+	require.NoError(t, err)
+	assert.Equal(t, RiskHigh, level)
+
+	level, err = NewRiskLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, RiskMedium, level)
+
+	_, err = NewRiskLevel("critical")
+	assert.ErrorIs(t, err, ErrInvalidRisk)
+}
+
+func TestRiskRegister_Add(t *testing.T) {
+	register := NewRiskRegister()
+	at := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+
+	first, err := register.Add(RiskStatement{Statement: "We might miss  the deadline", Severity: "high"}, "alice", "docs/status/development/2024-W28.md", at)
+	require.NoError(t, err)
+	assert.Equal(t, "R-1", first.ID())
+	assert.Equal(t, "We might miss the deadline", first.Statement())
+	assert.Equal(t, RiskHigh, first.Severity())
+	assert.Equal(t, RiskMedium, first.Likelihood())
+	assert.Equal(t, "alice", first.Owner())
+	assert.True(t, first.Unmitigated())
+
+	// The same risk is not added twice
+	again, err := register.Add(RiskStatement{Statement: "we might miss the deadline"}, "bob", "", at)
+	require.NoError(t, err)
+	assert.Nil(t, again)
+
+	named, err := register.Add(RiskStatement{Statement: "The import could fail", Owner: "carol", Mitigation: "Retry in batches"}, "bob", "", at)
+	require.NoError(t, err)
+	assert.Equal(t, "R-2", named.ID())
+	assert.Equal(t, "carol", named.Owner(), "the owner the statement names wins over the sender")
+	assert.Equal(t, RiskMitigated, named.Status())
+
+	_, err = register.Add(RiskStatement{Statement: " "}, "bob", "", at)
+	assert.ErrorIs(t, err, ErrInvalidRisk)
+	_, err = register.Add(RiskStatement{Statement: "Outage", Severity: "critical"}, "bob", "", at)
+	assert.ErrorIs(t, err, ErrInvalidRisk)
+}
+
+func TestRiskRegister_MitigateAndClose(t *testing.T) {
+	register := NewRiskRegister()
+	at := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+	_, err := register.Add(RiskStatement{Statement: "We might miss the deadline"}, "alice", "", at)
+	require.NoError(t, err)
+	_, err = register.Add(RiskStatement{Statement: "The import could fail"}, "alice", "", at)
+	require.NoError(t, err)
+
+	_, err = register.Mitigate("r-1", " ")
+	assert.ErrorIs(t, err, ErrInvalidRisk)
+	mitigated, err := register.Mitigate("r-1", "Cut the reporting scope")
+	require.NoError(t, err)
+	assert.Equal(t, RiskMitigated, mitigated.Status())
+	assert.Equal(t, "Cut the reporting scope", mitigated.Mitigation())
+
+	closed, err := register.Close("R-2")
+	require.NoError(t, err)
+	assert.Equal(t, RiskClosed, closed.Status())
+	assert.Empty(t, register.Unmitigated())
+
+	_, err = register.Close("R-9")
+	assert.ErrorIs(t, err, ErrRiskNotFound)
+}
+
+func TestRiskRegister_RenderAndParse(t *testing.T) {
+	register := NewRiskRegister()
+	at := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+	_, err := register.Add(RiskStatement{Statement: "Docs might be late", Severity: "low", Likelihood: "low"}, "bob", "", at)
+	require.NoError(t, err)
+	_, err = register.Add(RiskStatement{Statement: "We might miss the deadline", Severity: "high", Likelihood: "medium"}, "alice", "docs/status/development/2024-W28.md", at)
+	require.NoError(t, err)
+	_, err = register.Add(RiskStatement{Statement: "The import could fail", Severity: "high", Likelihood: "high"}, "alice", "", at)
+	require.NoError(t, err)
+	_, err = register.Close("R-3")
+	require.NoError(t, err)
+
+	content := register.Render()
+
+	assert.Contains(t, content, "# Risk Register\n")
+	assert.Contains(t, content, "## R-2: We might miss the deadline\n\n"+
+		"- Severity: high\n- Likelihood: medium\n- Owner: alice\n- Status: open\n- Mitigation: none yet\n"+
+		"- Raised: 2024-07-08 in [2024-W28.md](status/development/2024-W28.md)\n")
+	assert.Less(t, strings.Index(content, "R-2:"), strings.Index(content, "R-1:"), "the most severe risks come first")
+	assert.Less(t, strings.Index(content, "R-1:"), strings.Index(content, "R-3:"), "closed risks come last")
+
+	parsed := ParseRiskRegister(content)
+	assert.Equal(t, content, parsed.Render())
+	risk, err := parsed.Find("R-2")
+	require.NoError(t, err)
+	assert.Equal(t, "docs/status/development/2024-W28.md", risk.Source())
+	assert.True(t, risk.RaisedAt().Equal(time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)))
+
+	unmitigated := parsed.Unmitigated()
+	require.Len(t, unmitigated["alice"], 1)
+	assert.Equal(t, "R-2", unmitigated["alice"][0].ID())
+	require.Len(t, unmitigated["bob"], 1)
+}
+
+func TestRenderRiskReminder(t *testing.T) {
+	register := NewRiskRegister()
+	risk, err := register.Add(RiskStatement{Statement: "We might miss the deadline", Severity: "high"}, "alice", "", time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	reminder := RenderRiskReminder("alice", []*Risk{risk}, "docs/risks.md")
+
+	assert.Contains(t, reminder, "⚠️ 1 risks owned by alice have no mitigation yet, the register is in docs/risks.md\n")
+	assert.Contains(t, reminder, "• *R-1* (high severity, medium likelihood, raised 2024-07-08): We might miss the deadline\n")
+	assert.Contains(t, reminder, "`/quill risk mitigate <id> <how>`")
+}
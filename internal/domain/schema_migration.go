@@ -0,0 +1,13 @@
+package domain
+
+// SchemaMigration is a versioned change of the database schema the stores keep their data in
+type SchemaMigration struct {
+	// Version orders the migrations, they are applied in ascending and rolled back in descending order
+	Version int
+	Name    string
+	// Applied reports if the migration was applied to the database
+	Applied bool
+	// Dirty marks the version a migration failed at halfway, the schema needs repairing by hand before it is
+	// migrated again
+	Dirty bool
+}
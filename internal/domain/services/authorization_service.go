@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"sync"
+	"time"
+)
+
+var ErrNoApprovalPending = errors.New("no decision waits for approval in this thread")
+
+// AuthorizationService enforces the approval policies of projects: decisions the policies apply to are held
+// until enough people approved them, and every approval or rejection is recorded in the audit log
+type AuthorizationService struct {
+	docs     *DocumentationService
+	audit    ports.AuditLog
+	flags    *FeatureFlagService
+	requests ports.ApprovalRequestStore
+
+	// mu keeps the approvals given on this replica from overwriting each other
+	mu sync.Mutex
+}
+
+// NewAuthorizationService creates an AuthorizationService. Decisions waiting for approval are kept in the
+// store by the thread they were posted in, approvals are replies in that thread.
+// With feature flags (optional), only the projects the approvals feature is on for hold their decisions.
+func NewAuthorizationService(docs *DocumentationService, audit ports.AuditLog, flags *FeatureFlagService, requests ports.ApprovalRequestStore) *AuthorizationService {
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	if requests == nil {
+		panic("approval request store cannot be nil")
+	}
+	return &AuthorizationService{
+		docs:     docs,
+		audit:    audit,
+		flags:    flags,
+		requests: requests,
+	}
+}
+
+// RequireApproval holds a message until the approval policies of its project are met. It returns nil when no
+// policy applies and the message may be documented right away.
+func (s *AuthorizationService) RequireApproval(ctx context.Context, msg *domain.Message) (*domain.ApprovalRequest, error) {
+	project, err := s.docs.messageProject(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil || !featureEnabled(ctx, s.flags, domain.FeatureApprovals, project) {
+		return nil, nil
+	}
+	request := domain.NewApprovalRequest(msg, project.Documentation(), time.Now())
+	if request == nil {
+		return nil, nil
+	}
+
+	if err := s.requests.Put(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to hold decision %s: %w", msg.ID(), err)
+	}
+	logf(ctx, "Decision %s waits for %s", msg.ID(), request.Missing())
+	return request, nil
+}
+
+// Waiting checks if a decision waits for approval in the thread
+func (s *AuthorizationService) Waiting(ctx context.Context, threadID string) (bool, error) {
+	_, err := s.requests.Find(ctx, threadID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Approve records the approval of the decision waiting in the thread. The request is released once its
+// policies are met, the caller documents its message then.
+func (s *AuthorizationService) Approve(ctx context.Context, threadID, actor string) (*domain.ApprovalRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request, err := s.find(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := request.Progress()
+	if err := request.Approve(actor); err != nil {
+		return nil, err
+	}
+	outcome := request.Progress()
+	if request.Satisfied() {
+		// Only the replica releasing the request documents its decision
+		if err := s.release(ctx, threadID); err != nil {
+			return nil, err
+		}
+		outcome = "approved"
+	} else if err := s.requests.Put(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to save approval of %s: %w", actor, err)
+	}
+	if err := s.record(ctx, request, actor, before, outcome); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Reject releases the decision waiting in the thread so it is never documented
+func (s *AuthorizationService) Reject(ctx context.Context, threadID, actor string) (*domain.ApprovalRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request, err := s.find(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.release(ctx, threadID); err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, request, actor, request.Progress(), "rejected"); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Pending returns the decisions waiting for approval, oldest first
+func (s *AuthorizationService) Pending(ctx context.Context) ([]*domain.ApprovalRequest, error) {
+	requests, err := s.requests.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decisions waiting for approval: %w", err)
+	}
+	return requests, nil
+}
+
+// find returns the request waiting in the thread, ErrNoApprovalPending when there is none
+func (s *AuthorizationService) find(ctx context.Context, threadID string) (*domain.ApprovalRequest, error) {
+	request, err := s.requests.Find(ctx, threadID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, ErrNoApprovalPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// release takes the request of the thread out of the store, ErrNoApprovalPending when another replica did first
+func (s *AuthorizationService) release(ctx context.Context, threadID string) error {
+	err := s.requests.Remove(ctx, threadID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return ErrNoApprovalPending
+	}
+	return err
+}
+
+func (s *AuthorizationService) record(ctx context.Context, request *domain.ApprovalRequest, actor, from, to string) error {
+	entry, err := domain.NewAuditEntry(domain.AuditActionApprove, actor, request.Message().ID().String(), from, to)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record approval: %w", err)
+	}
+	return nil
+}
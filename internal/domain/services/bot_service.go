@@ -2,9 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/massimo-ua/quill/internal/domain"
 	"github.com/massimo-ua/quill/internal/domain/ports"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type MessageHandler interface {
@@ -16,74 +21,407 @@ type SuggestionsHandler interface {
 	HandleWithAnalysis(ctx context.Context, msg *domain.Message, analysis *domain.MessageAnalysisResult) error
 }
 
+// FollowUpHandler is implemented by handlers that wait for a reply in the thread before acting
+type FollowUpHandler interface {
+	MessageHandler
+	// HandleFollowUp processes a thread reply and reports whether it was consumed
+	HandleFollowUp(ctx context.Context, msg *domain.Message) (bool, error)
+}
+
 type baseHandler struct {
-	docService   *DocumentationService
-	chatProvider ports.ChatAccessProvider
+	docService    *DocumentationService
+	chatProvider  ports.ChatAccessProvider
+	tracker       *MessageTracker
+	notifications *NotificationService
 }
 
 type ideaHandler struct {
 	baseHandler
+	duplicates *DuplicateDetector
+	pending    ports.PendingDuplicateStore
+	updates    ports.PendingUpdateStore
 }
 
+// maxDiffPreviewLines limits how much of a diff is posted when an update waits for approval
+const maxDiffPreviewLines = 40
+
 type decisionHandler struct {
 	baseHandler
+	authz *AuthorizationService
 }
 
 type statusHandler struct {
 	baseHandler
+	okrs *OKRService
 }
 
 type unknownHandler struct {
 	baseHandler
 }
 
-func (h *baseHandler) createDocumentation(ctx context.Context, msg *domain.Message) error {
-	return h.docService.CreateDocumentation(
-		ctx,
-		msg.Type(),
-		msg.Category(),
-		msg.Content().Text(),
-		msg.References(),
-	)
+// createDocumentation documents a message and returns the document path. People are asked to review
+// documents saying things the message does not.
+func (h *baseHandler) createDocumentation(ctx context.Context, msg *domain.Message) (string, error) {
+	path, flagged, err := h.docService.CreateDocumentation(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	if err := h.tracker.Document(ctx, msg, path, ""); err != nil {
+		return "", err
+	}
+	if flagged != nil {
+		return path, h.requestGroundingReview(ctx, msg, path, *flagged)
+	}
+	return path, nil
+}
+
+// requestGroundingReview asks the channel of a message to check the unsupported claims of its document, with
+// review buttons when the chat provider has them, or in the thread of the message otherwise
+func (h *baseHandler) requestGroundingReview(ctx context.Context, msg *domain.Message, path string, report domain.GroundingReport) error {
+	request := domain.RenderGroundingReview(path, report)
+	if requester, ok := h.chatProvider.(ports.ReviewRequester); ok {
+		if err := requester.RequestReview(ctx, msg.ChannelID(), request, path); err != nil {
+			return fmt.Errorf("failed to request review in %s: %w", msg.ChannelID(), err)
+		}
+		return nil
+	}
+	if err := h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), request); err != nil {
+		return fmt.Errorf("failed to ask for the review of %s: %w", path, err)
+	}
+	return nil
 }
 
 func (h *ideaHandler) Handle(ctx context.Context, msg *domain.Message) error {
-	if err := h.createDocumentation(ctx, msg); err != nil {
+	duplicates, err := h.duplicates.FindDuplicates(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("failed to check for duplicate ideas: %w", err)
+	}
+	if len(duplicates) > 0 {
+		return h.offerMerge(ctx, msg, duplicates)
+	}
+
+	return h.documentIdea(ctx, msg)
+}
+
+// HandleFollowUp applies the author's choice for an idea that looked like a duplicate.
+// Replying "merge" (or "merge 2" to pick another candidate) appends to the existing document,
+// replying "new" documents the idea separately. Only the author of the idea chooses, and only until
+// the choice expires. When the project approves updates, the merge is shown as a diff first and
+// the author replying "apply" or "discard" decides on it, until domain.DefaultUpdateApprovalTTL passes.
+func (h *ideaHandler) HandleFollowUp(ctx context.Context, msg *domain.Message) (bool, error) {
+	fields := strings.Fields(strings.ToLower(msg.Content().Text()))
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	switch fields[0] {
+	case "merge", "append":
+		n := 1
+		if len(fields) > 1 {
+			if choice, err := strconv.Atoi(fields[1]); err == nil {
+				n = choice
+			}
+		}
+		return h.choose(ctx, msg, func(pending *domain.PendingDuplicate, idea *domain.Message) error {
+			return h.merge(ctx, idea, pending.Candidate(n))
+		})
+	case "new", "create":
+		return h.choose(ctx, msg, func(_ *domain.PendingDuplicate, idea *domain.Message) error {
+			return h.documentIdea(ctx, idea)
+		})
+	case "apply":
+		return h.decide(ctx, msg, h.applyMerge)
+	case "discard":
+		return h.decide(ctx, msg, func(ctx context.Context, update *DocumentUpdate) error {
+			if err := h.tracker.Transition(ctx, update.Message(), domain.MessageStateIgnored, "update discarded by "+msg.Sender()); err != nil {
+				return err
+			}
+			reply := fmt.Sprintf("🗑️ Discarded the update, %s is unchanged", h.docService.DocumentLink(ctx, update.Path()))
+			return h.chatProvider.ReplyToMessage(ctx, update.Message().ID().String(), reply)
+		})
+	default:
+		return false, nil
+	}
+}
+
+// choose applies a reply choosing for the idea waiting in its thread. Replies of other people than the author of
+// the idea are answered without applying them. The choice is removed before it is applied, so when replicas get
+// the reply only the one that removed it applies it. Replies are not consumed when no choice is waited for.
+func (h *ideaHandler) choose(ctx context.Context, msg *domain.Message, apply func(*domain.PendingDuplicate, *domain.Message) error) (bool, error) {
+	threadID := msg.ThreadID().String()
+	pending, err := h.pending.Find(ctx, threadID, time.Now())
+	if errors.Is(err, ports.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to find the duplicate choice of thread %s: %w", threadID, err)
+	}
+	if !pending.CanChoose(msg.Sender()) {
+		reply := "🙅 Only the author of the idea can choose whether it is merged"
+		return true, h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+	}
+	if err := h.pending.Remove(ctx, threadID); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return true, nil
+		}
+		return true, fmt.Errorf("failed to take the duplicate choice of thread %s: %w", threadID, err)
+	}
+
+	idea, err := h.tracker.Find(ctx, pending.MessageID())
+	if err != nil {
+		return true, err
+	}
+	return true, apply(pending, idea)
+}
+
+// decide applies a reply applying or discarding the update waiting in its thread. Like choices, only the author of
+// the idea decides, and the update is removed before it is acted on so only one replica acts. Replies are not
+// consumed when no update is waited for.
+func (h *ideaHandler) decide(ctx context.Context, msg *domain.Message, act func(context.Context, *DocumentUpdate) error) (bool, error) {
+	threadID := msg.ThreadID().String()
+	pending, err := h.updates.Find(ctx, threadID, time.Now())
+	if errors.Is(err, ports.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to find the pending update of thread %s: %w", threadID, err)
+	}
+	if !pending.CanDecide(msg.Sender()) {
+		reply := "🙅 Only the author of the idea can apply or discard its merge"
+		return true, h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+	}
+	if err := h.updates.Remove(ctx, threadID); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return true, nil
+		}
+		return true, fmt.Errorf("failed to take the pending update of thread %s: %w", threadID, err)
+	}
+
+	idea, err := h.tracker.Find(ctx, pending.MessageID())
+	if err != nil {
+		return true, err
+	}
+	return true, act(ctx, restoreUpdate(pending, idea))
+}
+
+// merge appends an idea to an existing document. When the project approves updates, the diff is posted
+// in the thread and the document is only changed once the author applies it.
+func (h *ideaHandler) merge(ctx context.Context, msg *domain.Message, target string) error {
+	update, err := h.docService.DraftAppend(ctx, target, msg)
+	if err != nil {
+		return fmt.Errorf("failed to merge idea documentation: %w", err)
+	}
+	docConfig, err := h.docService.documentationConfig(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if !docConfig.ApproveUpdates {
+		return h.applyMerge(ctx, update)
+	}
+
+	now := time.Now()
+	if purged, err := h.updates.Purge(ctx, now); err != nil {
+		logf(ctx, "Failed to purge expired pending updates: %v", err)
+	} else if purged > 0 {
+		logf(ctx, "Purged %d expired pending updates", purged)
+	}
+	pending, err := update.pending(now.Add(domain.DefaultUpdateApprovalTTL))
+	if err != nil {
+		return fmt.Errorf("failed to keep the merge for approval: %w", err)
+	}
+	if err := h.updates.Put(ctx, pending); err != nil {
+		return fmt.Errorf("failed to keep the merge for approval: %w", err)
+	}
+	reply := fmt.Sprintf("📝 Merging this idea changes %s (%s):\n```\n%s\n```\nReply `apply` to update the document, or `discard` to leave it as it is.",
+		h.docService.DocumentLink(ctx, target), update.Diff().Summary(), update.Diff().Preview(maxDiffPreviewLines))
+	return h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+}
+
+// applyMerge stores a merged idea and marks its message documented
+func (h *ideaHandler) applyMerge(ctx context.Context, update *DocumentUpdate) error {
+	if err := h.docService.ApplyUpdate(ctx, update); err != nil {
+		return fmt.Errorf("failed to merge idea documentation: %w", err)
+	}
+	if err := h.tracker.Document(ctx, update.Message(), update.Path(), "merged into "+update.Path()); err != nil {
+		return err
+	}
+	reply := fmt.Sprintf("🔀 Merged idea into %s", h.docService.DocumentLink(ctx, update.Path()))
+	return h.chatProvider.ReplyToMessage(ctx, update.Message().ID().String(), reply)
+}
+
+// offerMerge tells the author about similar ideas and waits for their choice, until domain.DefaultDuplicateChoiceTTL
+// passes. The analysis of the idea is saved, so the choice can be made after a restart.
+func (h *ideaHandler) offerMerge(ctx context.Context, msg *domain.Message, duplicates []*domain.DocumentMatch) error {
+	now := time.Now()
+	if purged, err := h.pending.Purge(ctx, now); err != nil {
+		logf(ctx, "Failed to purge expired duplicate choices: %v", err)
+	} else if purged > 0 {
+		logf(ctx, "Purged %d expired duplicate choices", purged)
+	}
+
+	candidates := make([]string, 0, len(duplicates))
+	for _, match := range duplicates {
+		candidates = append(candidates, match.Document().Path())
+	}
+	pending, err := domain.NewPendingDuplicate(msg.ThreadID().String(), msg.ID().String(), msg.Sender(), candidates,
+		now.Add(domain.DefaultDuplicateChoiceTTL))
+	if err != nil {
+		return fmt.Errorf("failed to offer a merge: %w", err)
+	}
+	if err := h.tracker.Hold(ctx, msg); err != nil {
+		return err
+	}
+	if err := h.pending.Put(ctx, pending); err != nil {
+		return fmt.Errorf("failed to keep the duplicate choice: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("🤔 This idea looks similar to what's already documented:\n")
+	for i, match := range duplicates {
+		doc := match.Document()
+		b.WriteString(fmt.Sprintf("%d. %s (%s, %.0f%% similar)\n", i+1, doc.Title(), h.docService.DocumentLink(ctx, doc.Path()), match.Score()*100))
+	}
+	b.WriteString("Reply `merge` to append it to the first one (or `merge <n>` to pick another), or `new` to document it separately.")
+
+	return h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), b.String())
+}
+
+func (h *ideaHandler) documentIdea(ctx context.Context, msg *domain.Message) error {
+	path, err := h.createDocumentation(ctx, msg)
+	if err != nil {
 		return fmt.Errorf("failed to create idea documentation: %w", err)
 	}
 
 	reply := fmt.Sprintf("📝 Captured idea in category: %s", msg.Category())
-	if msg.HasReferences() {
-		reply += fmt.Sprintf("\n🔗 Linked to %d related items", len(msg.References()))
+	if linked := msg.LinkedReferences(); len(linked) > 0 {
+		reply += fmt.Sprintf("\n🔗 Linked to %d related items", len(linked))
 	}
 
-	return h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+	return h.notifications.ConfirmDocument(ctx, msg, reply, path)
 }
 
+// Handle documents a decision, unless the approval policies of its project hold it until people approve it
 func (h *decisionHandler) Handle(ctx context.Context, msg *domain.Message) error {
-	if err := h.createDocumentation(ctx, msg); err != nil {
+	if h.authz != nil {
+		request, err := h.authz.RequireApproval(ctx, msg)
+		if err != nil {
+			return fmt.Errorf("failed to check the approval policies: %w", err)
+		}
+		if request != nil {
+			reply := fmt.Sprintf("🔐 This decision needs %s before it is documented. Reply `approve` or `reject` in this thread.", request.Missing())
+			return h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+		}
+	}
+	return h.document(ctx, msg)
+}
+
+// HandleFollowUp records the approvals of a decision held by an approval policy. Replying "approve" counts
+// the sender's approval, the decision is documented once every policy is met; replying "reject" drops it.
+func (h *decisionHandler) HandleFollowUp(ctx context.Context, msg *domain.Message) (bool, error) {
+	threadID := msg.ThreadID().String()
+	if h.authz == nil {
+		return false, nil
+	}
+	waiting, err := h.authz.Waiting(ctx, threadID)
+	if err != nil || !waiting {
+		return false, err
+	}
+
+	fields := strings.Fields(strings.ToLower(msg.Content().Text()))
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	switch fields[0] {
+	case "approve", "approved":
+		request, err := h.authz.Approve(ctx, threadID, msg.Sender())
+		if errors.Is(err, ErrNoApprovalPending) {
+			return false, nil
+		}
+		if errors.Is(err, domain.ErrInvalidApproval) {
+			return true, h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), "⚠️ "+err.Error())
+		}
+		if err != nil {
+			return true, err
+		}
+		if !request.Satisfied() {
+			reply := fmt.Sprintf("👍 Approved by %s, the decision still needs %s", msg.Sender(), request.Missing())
+			return true, h.chatProvider.ReplyToMessage(ctx, request.Message().ID().String(), reply)
+		}
+		return true, h.document(ctx, request.Message())
+	case "reject", "rejected":
+		request, err := h.authz.Reject(ctx, threadID, msg.Sender())
+		if errors.Is(err, ErrNoApprovalPending) {
+			return false, nil
+		}
+		if err != nil {
+			return true, err
+		}
+		if err := h.tracker.Transition(ctx, request.Message(), domain.MessageStateIgnored, "decision rejected by "+msg.Sender()); err != nil {
+			return true, err
+		}
+		reply := fmt.Sprintf("🚫 Rejected by %s, the decision is not documented", msg.Sender())
+		return true, h.chatProvider.ReplyToMessage(ctx, request.Message().ID().String(), reply)
+	default:
+		return false, nil
+	}
+}
+
+func (h *decisionHandler) document(ctx context.Context, msg *domain.Message) error {
+	path, err := h.createDocumentation(ctx, msg)
+	if err != nil {
 		return fmt.Errorf("failed to create decision documentation: %w", err)
 	}
 
 	reply := fmt.Sprintf("✅ Recorded decision in category: %s", msg.Category())
-	if msg.HasReferences() {
-		reply += fmt.Sprintf("\n🔗 Linked to %d related items", len(msg.References()))
+	if linked := msg.LinkedReferences(); len(linked) > 0 {
+		reply += fmt.Sprintf("\n🔗 Linked to %d related items", len(linked))
 	}
 
-	return h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+	return h.notifications.ConfirmDocument(ctx, msg, reply, path)
 }
 
 func (h *statusHandler) Handle(ctx context.Context, msg *domain.Message) error {
-	if err := h.createDocumentation(ctx, msg); err != nil {
+	// Status updates may be rolled up with the rest of the week, so their documents are not recategorized
+	path, err := h.createDocumentation(ctx, msg)
+	if err != nil {
 		return fmt.Errorf("failed to create status documentation: %w", err)
 	}
 
 	reply := fmt.Sprintf("📊 Logged status update in category: %s", msg.Category())
-	if msg.HasReferences() {
-		reply += fmt.Sprintf("\n🔗 Linked to %d related items", len(msg.References()))
+	if linked := msg.LinkedReferences(); len(linked) > 0 {
+		reply += fmt.Sprintf("\n🔗 Linked to %d related items", len(linked))
+	}
+	if progress := h.recordProgress(ctx, msg, path); progress != "" {
+		reply += "\n" + progress
 	}
 
-	return h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+	return h.notifications.Confirm(ctx, msg, reply)
+}
+
+// recordProgress attaches a documented status update to the key results it reports on, and describes the
+// progress recorded. The update stays documented when its progress cannot be recorded.
+func (h *statusHandler) recordProgress(ctx context.Context, msg *domain.Message, path string) string {
+	if h.okrs == nil {
+		return ""
+	}
+	mentions, err := h.okrs.Record(ctx, msg, path)
+	if err != nil {
+		logf(ctx, "Failed to record the key result progress of message %s: %v", msg.ID(), err)
+	}
+	if len(mentions) == 0 {
+		return ""
+	}
+
+	reported := make([]string, 0, len(mentions))
+	for _, mention := range mentions {
+		if mention.Measured {
+			reported = append(reported, fmt.Sprintf("%s at %s", mention.KeyResultID, strconv.FormatFloat(mention.Value, 'f', -1, 64)))
+		} else {
+			reported = append(reported, mention.KeyResultID)
+		}
+	}
+	return "🎯 Progress recorded on " + strings.Join(reported, ", ")
 }
 
 func (h *unknownHandler) Handle(ctx context.Context, msg *domain.Message) error {
@@ -95,12 +433,13 @@ func (h *unknownHandler) HandleWithAnalysis(ctx context.Context, msg *domain.Mes
 		return nil
 	}
 
-	suggestion := "💡 I noticed this might be relevant. Consider adding these tags:\n"
+	var b strings.Builder
+	b.WriteString("💡 I noticed this might be relevant. Consider adding these tags:\n")
 	for _, tag := range analysis.SuggestedTags() {
-		suggestion += fmt.Sprintf("- #%s\n", tag)
+		b.WriteString(fmt.Sprintf("- #%s\n", tag))
 	}
 
-	return h.chatProvider.ReplyToMessage(ctx, msg.ID().String(), suggestion)
+	return h.notifications.Confirm(ctx, msg, b.String())
 }
 
 type BotService struct {
@@ -109,15 +448,64 @@ type BotService struct {
 	aiAgent        ports.AiAgentProvider
 	projectService *ProjectService
 	docService     *DocumentationService
+	resolver       *ReferenceResolver
+	commands       *CommandService
+	tracker        *MessageTracker
+	feedback       *FeedbackService
+	timeouts       StageTimeouts
+	coordinator    ports.WorkCoordinator
+	moderation     *ModerationService
+	triage         *TriageService
+	snoozes        *SnoozeService
+	threads        *ThreadService
+	incidents      *IncidentService
+	notes          *MeetingNotesService
+	standups       *StandupService
+	authz          *AuthorizationService
+	updates        ports.PendingUpdateStore
 	handlers       map[domain.MessageType]MessageHandler
 }
 
+// NewBotService creates a BotService. The feedback service is optional, without it messages
+// are analyzed without the corrections people made to earlier analyses. Zero timeouts use the defaults.
+// Without a coordinator every message is processed, replicas need one so each message is processed once.
+// Without a moderation service the messages of projects turning moderation on are documented unchecked.
+// Without a triage service the messages of projects turning triage on are documented whatever their confidence.
+// Without a snooze service only the messages starting with domain.NoDocMarker are kept from being captured.
+// With a thread service the threads of the messages of active projects are kept and titled.
+// With an incident service the messages of threads with an open incident are captured for its timeline instead.
+// With a meeting notes service the messages of threads taken as meeting notes are collected for the notes instead.
+// With a standup service the direct messages answering the standup questions are collected for the standup notes.
+// With an OKR service the status updates mentioning key results are recorded as their progress.
+// Without an authorization service the approval policies of projects are not enforced.
+// The notification service confirms captures, run it alongside the bot so batched confirmations are posted.
+// The pending duplicate store keeps the ideas waiting for their author to choose whether they are merged, and the
+// pending update store the merges waiting for their author to apply their diff.
 func NewBotService(
 	chat ports.ChatAccessProvider,
 	docs ports.DocumentStoreProvider,
 	ai ports.AiAgentProvider,
 	ps *ProjectService,
 	ds *DocumentationService,
+	resolver *ReferenceResolver,
+	duplicates *DuplicateDetector,
+	commands *CommandService,
+	tracker *MessageTracker,
+	feedback *FeedbackService,
+	timeouts StageTimeouts,
+	coordinator ports.WorkCoordinator,
+	moderation *ModerationService,
+	triage *TriageService,
+	snoozes *SnoozeService,
+	threads *ThreadService,
+	incidents *IncidentService,
+	notes *MeetingNotesService,
+	standups *StandupService,
+	okrs *OKRService,
+	authz *AuthorizationService,
+	notifications *NotificationService,
+	pendingDuplicates ports.PendingDuplicateStore,
+	pendingUpdates ports.PendingUpdateStore,
 ) *BotService {
 	if chat == nil {
 		panic("chat provider cannot be nil")
@@ -134,29 +522,78 @@ func NewBotService(
 	if ds == nil {
 		panic("documentation service cannot be nil")
 	}
+	if resolver == nil {
+		panic("reference resolver cannot be nil")
+	}
+	if duplicates == nil {
+		panic("duplicate detector cannot be nil")
+	}
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if tracker == nil {
+		panic("message tracker cannot be nil")
+	}
+
+	if notifications == nil {
+		panic("notification service cannot be nil")
+	}
+	if pendingDuplicates == nil {
+		panic("pending duplicate store cannot be nil")
+	}
+	if pendingUpdates == nil {
+		panic("pending update store cannot be nil")
+	}
+	if shortcut, ok := chat.(ports.DetailsShortcut); ok {
+		shortcut.OnDetailsRequest(notifications.Details)
+	}
 
 	base := baseHandler{
-		docService:   ds,
-		chatProvider: chat,
+		docService:    ds,
+		chatProvider:  chat,
+		tracker:       tracker,
+		notifications: notifications,
 	}
 
 	handlers := map[domain.MessageType]MessageHandler{
-		domain.MessageTypeIdea:     &ideaHandler{base},
-		domain.MessageTypeDecision: &decisionHandler{base},
-		domain.MessageTypeStatus:   &statusHandler{base},
+		domain.MessageTypeIdea:     &ideaHandler{base, duplicates, pendingDuplicates, pendingUpdates},
+		domain.MessageTypeDecision: &decisionHandler{base, authz},
+		domain.MessageTypeStatus:   &statusHandler{base, okrs},
 		domain.MessageTypeUnknown:  &unknownHandler{base},
 	}
 
-	return &BotService{
+	service := &BotService{
 		chatProvider:   chat,
 		docStore:       docs,
 		aiAgent:        ai,
 		projectService: ps,
 		docService:     ds,
+		resolver:       resolver,
+		commands:       commands,
+		tracker:        tracker,
+		feedback:       feedback,
+		timeouts:       timeouts.withDefaults(),
+		coordinator:    coordinator,
+		moderation:     moderation,
+		triage:         triage,
+		snoozes:        snoozes,
+		threads:        threads,
+		incidents:      incidents,
+		notes:          notes,
+		standups:       standups,
+		authz:          authz,
+		updates:        pendingUpdates,
 		handlers:       handlers,
 	}
+	if capture, ok := chat.(ports.CaptureShortcut); ok {
+		capture.OnCaptureRequest(service.AnalyzeCapture)
+	}
+	return service
 }
 
+// ProcessMessage documents a message, or handles the command it is. Processing a message gets a correlation ID,
+// kept by messages that already have one like queued ones, which its log lines, processing events and audit
+// entries carry. When processing fails, the reply to the message names its short form for people to report.
 func (s *BotService) ProcessMessage(ctx context.Context, msg *domain.Message) error {
 	if ctx == nil {
 		return fmt.Errorf("context cannot be nil")
@@ -165,36 +602,499 @@ func (s *BotService) ProcessMessage(ctx context.Context, msg *domain.Message) er
 		return fmt.Errorf("message cannot be nil")
 	}
 
-	analysis, err := s.analyzeMessage(ctx, msg)
+	ctx = withCorrelationID(ctx, msg)
+	err := s.processMessage(ctx, msg)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	logf(ctx, "Failed to process message %s: %v", msg.ID(), err)
+	if replyErr := s.chatProvider.ReplyToMessage(ctx, msg.ID().String(), failureReply(msg.CorrelationID())); replyErr != nil {
+		logf(ctx, "Failed to report the failure of message %s: %v", msg.ID(), replyErr)
+	}
+	return err
+}
+
+// processMessage runs a message through the pipeline
+func (s *BotService) processMessage(ctx context.Context, msg *domain.Message) error {
+	// Other replicas process the channels this one does not own, including their commands. That only holds when every
+	// replica receives every message, like feed pollers do. A chat delivering each event to one replica, like Slack
+	// Socket Mode, is only connected on the owner, so a message skipped here is logged rather than lost silently.
+	if s.coordinator != nil {
+		owns, err := s.coordinator.Owns(ctx, msg.ChannelID())
+		if err != nil {
+			return fmt.Errorf("failed to check channel ownership: %w", err)
+		}
+		if !owns {
+			logf(ctx, "Skipping message %s, another replica processes channel %s", msg.ID(), msg.ChannelID())
+			return nil
+		}
+	}
+
+	// Commands keep working in paused and archived channels so projects can be resumed
+	if domain.IsCommand(msg.Content().Text()) {
+		return s.handleCommand(ctx, msg)
+	}
+
+	// Opted out messages are dropped before being tracked, so nothing of them is kept
+	optedOut, err := s.optedOut(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if optedOut {
+		return nil
+	}
+
+	if err := s.tracker.Track(ctx, msg); err != nil {
+		return err
+	}
+
+	accepts, err := s.projectService.AcceptsMessagesFrom(ctx, msg.ChannelID())
+	if err != nil {
+		return s.tracker.Fail(ctx, msg, err)
+	}
+	if !accepts {
+		return s.tracker.Transition(ctx, msg, domain.MessageStateIgnored, "project is not active")
+	}
+
+	// The thread is titled before the message is documented, so its document can name it
+	if s.threads != nil {
+		if _, err := s.threads.Record(ctx, msg); err != nil {
+			logf(ctx, "Failed to record message %s in its thread: %v", msg.ID(), err)
+		}
+	}
+
+	for _, handler := range s.handlers {
+		if followUp, ok := handler.(FollowUpHandler); ok {
+			handled, err := followUp.HandleFollowUp(ctx, msg)
+			if err != nil {
+				return s.tracker.Fail(ctx, msg, err)
+			}
+			if handled {
+				return s.tracker.Transition(ctx, msg, domain.MessageStateIgnored, "follow-up reply")
+			}
+		}
+	}
+
+	moderated, err := s.moderate(ctx, msg)
+	if err != nil {
+		return s.tracker.Fail(ctx, msg, err)
+	}
+	if moderated {
+		return nil
+	}
+
+	// Messages of an incident are kept for its timeline whatever their analysis, they are documented with it
+	if s.incidents != nil {
+		captured, err := s.incidents.Capture(ctx, msg)
+		if err != nil {
+			return s.tracker.Fail(ctx, msg, err)
+		}
+		if captured {
+			return nil
+		}
+	}
+	// So are the messages of a meeting, they are documented with its notes
+	if s.notes != nil {
+		captured, err := s.notes.Capture(ctx, msg)
+		if err != nil {
+			return s.tracker.Fail(ctx, msg, err)
+		}
+		if captured {
+			return nil
+		}
+	}
+	// And the answers to the standup questions, they are documented with the day's standup notes
+	if s.standups != nil {
+		captured, err := s.standups.Capture(ctx, msg)
+		if err != nil {
+			return s.tracker.Fail(ctx, msg, err)
+		}
+		if captured {
+			return nil
+		}
+	}
+	return s.analyze(ctx, msg)
+}
+
+// optedOut checks if a message starts with domain.NoDocMarker or was sent in a snoozed thread
+func (s *BotService) optedOut(ctx context.Context, msg *domain.Message) (bool, error) {
+	if domain.HasNoDocMarker(msg.Content().Text()) {
+		return true, nil
+	}
+	if s.snoozes == nil {
+		return false, nil
+	}
+	snoozed, err := s.snoozes.Snoozed(ctx, msg.ThreadID().String())
+	if err != nil {
+		return false, fmt.Errorf("failed to check thread snooze: %w", err)
+	}
+	return snoozed, nil
+}
+
+// AnalyzeCapture analyzes a message someone is capturing by hand, so the type and category they pick are
+// pre-filled. The message is analyzed like the messages of its channel, with the same residency checks.
+func (s *BotService) AnalyzeCapture(ctx context.Context, channelID, text string) (*domain.MessageAnalysisResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	autoDetection, err := s.projectService.AutoDetectionFor(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	docConfig, err := s.projectService.DocumentationFor(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResidency(channelID, docConfig.LocalOnly, "analysis", s.aiAgent); err != nil {
+		return nil, err
+	}
+
+	var analysis *domain.MessageAnalysisResult
+	err = runStage(ctx, "analysis", s.timeouts.Analysis, func(ctx context.Context) error {
+		var err error
+		analysis, err = s.analyzeMessage(ctx, text, autoDetection.PromptVersion)
+		return err
+	})
+	return analysis, err
+}
+
+// Reanalyze analyzes a stored message again with the prompt and model its channel uses now, and records the
+// analysis on the message. Types and categories people picked are kept, and references are not detected again.
+func (s *BotService) Reanalyze(ctx context.Context, msg *domain.Message) error {
+	if msg == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+	analysis, err := s.AnalyzeCapture(ctx, msg.ChannelID(), msg.Content().Text())
 	if err != nil {
 		return fmt.Errorf("failed to analyze message: %w", err)
 	}
+	msg.UpdateType(analysis.MessageType())
+	msg.UpdateCategory(analysis.Category())
+	msg.RecordPromptVersion(analysis.PromptVersion())
+	msg.RecordConfidence(analysis.ConfidenceScore())
+	msg.RecordModel(analysis.Model())
+	msg.AddTags(domain.NewTags(analysis.SuggestedTags())...)
+	return nil
+}
+
+// ApproveHeld documents a message moderation held, as if it had just been posted
+func (s *BotService) ApproveHeld(ctx context.Context, messageID, actor string) error {
+	if s.moderation == nil {
+		return fmt.Errorf("moderation is not configured")
+	}
+	msg, err := s.moderation.Approve(ctx, messageID, actor)
+	if err != nil {
+		return err
+	}
+	return s.analyze(ctx, msg)
+}
 
-	s.updateMessageWithAnalysis(msg, analysis)
+// RejectHeld ignores a message moderation held, it is never documented
+func (s *BotService) RejectHeld(ctx context.Context, messageID, actor string) error {
+	if s.moderation == nil {
+		return fmt.Errorf("moderation is not configured")
+	}
+	msg, err := s.moderation.Reject(ctx, messageID, actor)
+	if err != nil {
+		return err
+	}
+	return s.tracker.Transition(ctx, msg, domain.MessageStateIgnored, "rejected in moderation by "+actor)
+}
 
-	if !msg.HasReferences() {
-		if err := s.detectAndAddReferences(ctx, msg); err != nil {
-			return err
+// PendingApprovals returns what waits for approval in the channels, oldest first: the messages moderation
+// held, the document updates waiting for someone to apply their diff and the decisions approval policies hold
+func (s *BotService) PendingApprovals(ctx context.Context, channels []string) ([]domain.PendingApproval, error) {
+	inChannels := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		inChannels[channel] = true
+	}
+
+	var approvals []domain.PendingApproval
+	if s.moderation != nil {
+		held, err := s.moderation.Held(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range held {
+			if msg := h.Message(); inChannels[msg.ChannelID()] {
+				approvals = append(approvals, domain.PendingApproval{
+					Kind:      domain.ApprovalModeration,
+					MessageID: msg.ID().String(),
+					ChannelID: msg.ChannelID(),
+					Subject:   msg.Content().Text(),
+					Reason:    h.Reason(),
+					Since:     h.HeldAt(),
+				})
+			}
+		}
+	}
+	updates, err := s.updates.List(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, update := range updates {
+		msg, err := s.tracker.Find(ctx, update.MessageID())
+		if errors.Is(err, ports.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if inChannels[msg.ChannelID()] {
+			approvals = append(approvals, domain.PendingApproval{
+				Kind:      domain.ApprovalUpdate,
+				MessageID: msg.ID().String(),
+				ChannelID: msg.ChannelID(),
+				Subject:   s.docService.DocumentLink(ctx, update.Path()),
+				Since:     msg.Timestamp(),
+			})
+		}
+	}
+
+	if s.authz != nil {
+		requests, err := s.authz.Pending(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range requests {
+			if msg := request.Message(); inChannels[msg.ChannelID()] {
+				approvals = append(approvals, domain.PendingApproval{
+					Kind:      domain.ApprovalDecision,
+					MessageID: msg.ID().String(),
+					ChannelID: msg.ChannelID(),
+					Subject:   msg.Content().Text(),
+					Reason:    "needs " + request.Missing(),
+					Since:     request.RequestedAt(),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(approvals, func(i, j int) bool {
+		return approvals[i].Since.Before(approvals[j].Since)
+	})
+	return approvals, nil
+}
+
+// Triage documents a message of the triage queue under the category someone picked, keeping the type it was
+// analysed as, or ignores it when they dismissed it
+func (s *BotService) Triage(ctx context.Context, decision *domain.TriageDecision) error {
+	if decision == nil {
+		return fmt.Errorf("triage decision cannot be nil")
+	}
+	if s.triage == nil {
+		return fmt.Errorf("triage is not configured")
+	}
+	msg, err := s.triage.Take(ctx, decision.MessageID())
+	if err != nil {
+		return err
+	}
+	if decision.Dismissed() {
+		return s.tracker.Transition(ctx, msg, domain.MessageStateIgnored, "dismissed in triage by "+decision.Actor())
+	}
+
+	msg.FixType(msg.Type())
+	msg.FixCategory(decision.Category())
+	handler, ok := s.handlers[msg.Type()]
+	if !ok {
+		return s.tracker.Fail(ctx, msg, fmt.Errorf("no handler for %s messages", msg.Type()))
+	}
+	err = runStage(ctx, "documentation", s.timeouts.Documentation, func(ctx context.Context) error {
+		return handler.Handle(ctx, msg)
+	})
+	if err != nil {
+		return s.tracker.Fail(ctx, msg, err)
+	}
+	return nil
+}
+
+// needsTriage checks if an analysed message waits for triage: its project turned triage on and the analysis
+// was less confident than the project asks for. Messages whose type was fixed by their source are trusted.
+func (s *BotService) needsTriage(msg *domain.Message, autoDetection domain.AutoDetectionConfig) bool {
+	if s.triage == nil || !autoDetection.Triage {
+		return false
+	}
+	if msg.HasFixedType() || msg.Type().IsUnknown() {
+		return false
+	}
+	return msg.Confidence() < autoDetection.MinConfidence
+}
+
+// moderate checks the message when its project turned moderation on. It reports true when the message
+// was blocked, and ignored, or held for people to decide, and stays pending.
+func (s *BotService) moderate(ctx context.Context, msg *domain.Message) (bool, error) {
+	if s.moderation == nil {
+		return false, nil
+	}
+	docConfig, err := s.projectService.DocumentationFor(ctx, msg.ChannelID())
+	if err != nil {
+		return false, err
+	}
+	if !docConfig.Moderation {
+		return false, nil
+	}
+
+	decision, err := s.moderation.Check(ctx, msg, docConfig.LocalOnly)
+	if err != nil {
+		return false, err
+	}
+	switch decision.Verdict {
+	case domain.ModerationBlock:
+		return true, s.tracker.Transition(ctx, msg, domain.MessageStateIgnored, "moderation: "+decision.Reason)
+	case domain.ModerationReview:
+		return true, s.chatProvider.ReplyToMessage(ctx, msg.ID().String(),
+			fmt.Sprintf("⏸️ This message waits for moderation before it is documented (%s).", decision.Reason))
+	}
+	return false, nil
+}
+
+// analyze moves the message to analysis and documents it
+func (s *BotService) analyze(ctx context.Context, msg *domain.Message) error {
+	if err := s.tracker.Transition(ctx, msg, domain.MessageStateAnalyzing, ""); err != nil {
+		return err
+	}
+	if err := s.processAnalyzedMessage(ctx, msg); err != nil {
+		return s.tracker.Fail(ctx, msg, err)
+	}
+	return nil
+}
+
+// processAnalyzedMessage analyses a message and hands it to the handler of its type.
+// Handlers mark the message documented, ideas waiting for a merge decision stay analyzing.
+// Analysis and documentation each run under their own timeout.
+func (s *BotService) processAnalyzedMessage(ctx context.Context, msg *domain.Message) error {
+	autoDetection, err := s.projectService.AutoDetectionFor(ctx, msg.ChannelID())
+	if err != nil {
+		return err
+	}
+	docConfig, err := s.projectService.DocumentationFor(ctx, msg.ChannelID())
+	if err != nil {
+		return err
+	}
+	// Reference detection uses the same AI agent, so the check guards it too
+	if err := checkResidency(msg.ChannelID(), docConfig.LocalOnly, "analysis", s.aiAgent); err != nil {
+		return err
+	}
+
+	var analysis *domain.MessageAnalysisResult
+	var unresolved []*domain.Reference
+	err = runStage(ctx, "analysis", s.timeouts.Analysis, func(ctx context.Context) error {
+		// Deployment notifications of CI/CD bots are parsed, the AI agent is not asked about them
+		if notice, ok := s.docService.DeploymentNotice(msg); ok {
+			analysis = notice.Analysis()
+			s.updateMessageWithAnalysis(msg, analysis)
+			return nil
+		}
+
+		var err error
+		analysis, err = s.analyzeMessage(ctx, msg.Content().Text(), autoDetection.PromptVersion)
+		if err != nil {
+			return fmt.Errorf("failed to analyze message: %w", err)
+		}
+
+		s.updateMessageWithAnalysis(msg, analysis)
+		if autoDetection.SkipReason(msg) != "" {
+			return nil
+		}
+
+		if !msg.HasReferences() {
+			if err := s.detectAndAddReferences(ctx, msg); err != nil {
+				return err
+			}
+		}
+
+		unresolved, err = s.resolveReferences(ctx, msg)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.tracker.Analyzed(msg)
+
+	if reason := autoDetection.SkipReason(msg); reason != "" {
+		return s.tracker.Transition(ctx, msg, domain.MessageStateIgnored, reason)
+	}
+
+	// Messages the analysis is unsure about wait for the triage digest, they stay analyzing until decided
+	if s.needsTriage(msg, autoDetection) {
+		return s.triage.Queue(ctx, msg)
+	}
+
+	// The message type may have been fixed by the source, so route by the message rather than the analysis
+	handler, ok := s.handlers[msg.Type()]
+	if !ok {
+		handler = s.handlers[domain.MessageTypeUnknown]
+	}
+	err = runStage(ctx, "documentation", s.timeouts.Documentation, func(ctx context.Context) error {
+		if suggestHandler, ok := handler.(SuggestionsHandler); ok {
+			return suggestHandler.HandleWithAnalysis(ctx, msg, analysis)
 		}
+		return handler.Handle(ctx, msg)
+	})
+	if err != nil {
+		return err
 	}
 
-	handler := s.handlers[analysis.MessageType()]
-	if suggestHandler, ok := handler.(SuggestionsHandler); ok {
-		return suggestHandler.HandleWithAnalysis(ctx, msg, analysis)
+	// Nothing was documented, so there is nothing to link
+	if !ok || msg.Type().IsUnknown() {
+		return s.tracker.Transition(ctx, msg, domain.MessageStateIgnored, "nothing to document")
 	}
-	return handler.Handle(ctx, msg)
+	return s.requestReferenceConfirmation(ctx, msg, unresolved)
 }
 
-func (s *BotService) analyzeMessage(ctx context.Context, msg *domain.Message) (*domain.MessageAnalysisResult, error) {
-	result, err := s.aiAgent.AnalyzeMessage(ctx, msg.Content().Text())
+func (s *BotService) handleCommand(ctx context.Context, msg *domain.Message) error {
+	cmd, err := domain.ParseCommand(msg.Content().Text())
+	if err != nil {
+		return s.chatProvider.ReplyToMessage(ctx, msg.ID().String(),
+			fmt.Sprintf("⚠️ %s, try `%s help`", err, domain.CommandPrefix))
+	}
+	return s.commands.Handle(ctx, msg, cmd)
+}
+
+// analyzeMessage analyzes the content of a message with the prompt version pinned by its project, or the AI agent's
+// latest prompt when none is pinned. A pinned version fails the analysis when the AI agent cannot honor it.
+func (s *BotService) analyzeMessage(ctx context.Context, content, promptVersion string) (*domain.MessageAnalysisResult, error) {
+	examples := s.correctionExamples(ctx, content)
+
+	var result *domain.MessageAnalysisResult
+	var err error
+	if promptVersion != "" {
+		analyzer, ok := s.aiAgent.(ports.PromptVersionedAnalyzer)
+		if !ok {
+			return nil, fmt.Errorf("AI agent cannot pin prompt version %q: %w", promptVersion, domain.ErrUnknownPromptVersion)
+		}
+		result, err = analyzer.AnalyzeMessageWithPrompt(ctx, promptVersion, content, examples)
+	} else if analyzer, ok := s.aiAgent.(ports.ExampleGuidedAnalyzer); ok && len(examples) > 0 {
+		result, err = analyzer.AnalyzeMessageWithExamples(ctx, content, examples)
+	} else {
+		result, err = s.aiAgent.AnalyzeMessage(ctx, content)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("AI analysis failed: %w", err)
 	}
 	return result, nil
 }
 
+// correctionExamples returns the corrections that guide the analysis of the content, if any
+func (s *BotService) correctionExamples(ctx context.Context, content string) []*domain.Correction {
+	if s.feedback == nil {
+		return nil
+	}
+	examples, err := s.feedback.Examples(ctx, content)
+	if err != nil {
+		logf(ctx, "Failed to select correction examples: %v", err)
+		return nil
+	}
+	return examples
+}
+
 func (s *BotService) updateMessageWithAnalysis(msg *domain.Message, analysis *domain.MessageAnalysisResult) {
+	msg.UpdateType(analysis.MessageType())
 	msg.UpdateCategory(analysis.Category())
+	msg.RecordPromptVersion(analysis.PromptVersion())
+	msg.RecordConfidence(analysis.ConfidenceScore())
+	msg.RecordModel(analysis.Model())
+	msg.AddTags(domain.NewTags(analysis.SuggestedTags())...)
 	for _, ref := range analysis.References() {
 		msg.AddReference(ref)
 	}
@@ -210,3 +1110,43 @@ func (s *BotService) detectAndAddReferences(ctx context.Context, msg *domain.Mes
 	}
 	return nil
 }
+
+// resolveReferences replaces message references with their canonical targets, keeping the ones no target matched
+// marked unresolved, and returns the unresolved ones
+func (s *BotService) resolveReferences(ctx context.Context, msg *domain.Message) ([]*domain.Reference, error) {
+	if !msg.HasReferences() {
+		return nil, nil
+	}
+
+	project, err := s.docService.messageProject(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve references: %w", err)
+	}
+	results, err := s.resolver.ResolveAll(ctx, project, msg.References())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve references: %w", err)
+	}
+
+	refs := make([]*domain.Reference, 0, len(results))
+	for _, res := range results {
+		refs = append(refs, res.Reference())
+	}
+	msg.ReplaceReferences(refs)
+
+	return msg.UnresolvedReferences(), nil
+}
+
+// requestReferenceConfirmation asks the author to clarify references that could not be resolved
+func (s *BotService) requestReferenceConfirmation(ctx context.Context, msg *domain.Message, unresolved []*domain.Reference) error {
+	if len(unresolved) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("❓ I couldn't match these references to existing documents or messages, could you link them?\n")
+	for _, ref := range unresolved {
+		b.WriteString(fmt.Sprintf("- %s\n", ref.Value()))
+	}
+
+	return s.chatProvider.ReplyToMessage(ctx, msg.ID().String(), b.String())
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// DefaultCalibrationBins is how many confidence bins the calibration report has when not asked for
+const DefaultCalibrationBins = 10
+
+// CalibrationService compares the confidence each model reported in production with how often people
+// corrected its analyses, so the confidence thresholds of projects can be tuned on data
+type CalibrationService struct {
+	messages    ports.MessageRepository
+	corrections ports.CorrectionStore
+}
+
+// NewCalibrationService creates a new CalibrationService
+func NewCalibrationService(messages ports.MessageRepository, corrections ports.CorrectionStore) *CalibrationService {
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if corrections == nil {
+		panic("correction store cannot be nil")
+	}
+	return &CalibrationService{
+		messages:    messages,
+		corrections: corrections,
+	}
+}
+
+// Report returns the calibration of every model, zero bins uses DefaultCalibrationBins
+func (s *CalibrationService) Report(ctx context.Context, bins int) ([]domain.ModelCalibration, error) {
+	if bins <= 0 {
+		bins = DefaultCalibrationBins
+	}
+	messages, err := s.messages.FindByState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	corrections, err := s.corrections.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corrections: %w", err)
+	}
+	return domain.NewCalibrationReport(messages, corrections, bins), nil
+}
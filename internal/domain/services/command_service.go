@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CommandHandlerFunc executes a command and returns the reply for the user
+type CommandHandlerFunc func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error)
+
+type registeredCommand struct {
+	usage   string
+	handler CommandHandlerFunc
+}
+
+// CommandService dispatches "/quill <command>" messages to registered handlers
+type CommandService struct {
+	chatProvider ports.ChatAccessProvider
+	mu           sync.RWMutex
+	commands     map[string]registeredCommand
+}
+
+func NewCommandService(chat ports.ChatAccessProvider) *CommandService {
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	return &CommandService{
+		chatProvider: chat,
+		commands:     make(map[string]registeredCommand),
+	}
+}
+
+// Register adds a command handler; usage is shown by "/quill help"
+func (s *CommandService) Register(name, usage string, handler CommandHandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands[strings.ToLower(name)] = registeredCommand{usage: usage, handler: handler}
+}
+
+// Handle executes the command and replies with its result.
+// Handlers that answered through the chat provider themselves return an empty reply.
+func (s *CommandService) Handle(ctx context.Context, msg *domain.Message, cmd *domain.Command) error {
+	reply, err := s.execute(ctx, msg, cmd)
+	if err != nil {
+		reply = fmt.Sprintf("⚠️ %s", err)
+	}
+	if reply == "" {
+		return nil
+	}
+
+	return s.chatProvider.ReplyToMessage(ctx, msg.ID().String(), reply)
+}
+
+func (s *CommandService) execute(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+	if cmd.Name() == "help" {
+		return s.help(), nil
+	}
+
+	s.mu.RLock()
+	registered, ok := s.commands[cmd.Name()]
+	s.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown command %q, try `%s help`", cmd.Name(), domain.CommandPrefix)
+	}
+
+	return registered.handler(ctx, msg, cmd)
+}
+
+func (s *CommandService) help() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.commands))
+	for name := range s.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("Available commands:\n")
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("• `%s %s`\n", domain.CommandPrefix, s.commands[name].usage))
+	}
+	return b.String()
+}
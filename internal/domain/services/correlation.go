@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"log"
+)
+
+// logf logs a line about processing a message or an API request. The line starts with the correlation ID ctx
+// carries, if any, so operators find every line of a failure people report with its reference.
+func logf(ctx context.Context, format string, args ...interface{}) {
+	if id := domain.CorrelationIDFrom(ctx); id != "" {
+		format = "[" + id.String() + "] " + format
+	}
+	log.Printf(format, args...)
+}
+
+// withCorrelationID gives a message a correlation ID unless it has one, and returns a context carrying it
+func withCorrelationID(ctx context.Context, msg *domain.Message) context.Context {
+	if msg.CorrelationID() == "" {
+		msg.SetCorrelationID(domain.NewCorrelationID())
+	}
+	return domain.ContextWithCorrelationID(ctx, msg.CorrelationID())
+}
+
+// failureReply tells people processing their message failed, with the reference operators find it by
+func failureReply(id domain.CorrelationID) string {
+	return "⚠️ Something went wrong, ref: " + id.Short()
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"strings"
+	"sync"
+)
+
+// DocStoreResolver picks the document store a project's documentation is written to.
+// Projects without their own repository use the default store, the others get a store
+// created by the factory which is cached per repository and branch.
+type DocStoreResolver struct {
+	fallback ports.DocumentStoreProvider
+	factory  ports.DocumentStoreFactory
+	mu       sync.Mutex
+	stores   map[string]ports.DocumentStoreProvider
+}
+
+// NewDocStoreResolver creates a resolver. Without a factory every project uses the default store.
+func NewDocStoreResolver(fallback ports.DocumentStoreProvider, factory ports.DocumentStoreFactory) *DocStoreResolver {
+	if fallback == nil {
+		panic("default docStore cannot be nil")
+	}
+	return &DocStoreResolver{
+		fallback: fallback,
+		factory:  factory,
+		stores:   make(map[string]ports.DocumentStoreProvider),
+	}
+}
+
+// Default returns the store used by projects without their own repository
+func (r *DocStoreResolver) Default() ports.DocumentStoreProvider {
+	return r.fallback
+}
+
+// ForProject returns the store the project's documentation is written to
+func (r *DocStoreResolver) ForProject(project *domain.Project) (ports.DocumentStoreProvider, error) {
+	if project == nil {
+		return r.fallback, nil
+	}
+	return r.ForDocumentation(project.Name(), project.Documentation())
+}
+
+// ForDocumentation returns the store documentation with the given settings is written to. Local-only settings
+// refuse stores that are not local with domain.ErrLocalOnly, the subject names the documentation in the log.
+func (r *DocStoreResolver) ForDocumentation(subject string, config domain.DocumentationConfig) (ports.DocumentStoreProvider, error) {
+	store, err := r.Resolve(config.Repository, config.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResidency(subject, config.LocalOnly, "storage", store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Resolve returns the store for a repository and branch, an empty repository selects the default store
+func (r *DocStoreResolver) Resolve(repository, branch string) (ports.DocumentStoreProvider, error) {
+	repository = strings.TrimSpace(repository)
+	branch = strings.TrimSpace(branch)
+	if repository == "" {
+		return r.fallback, nil
+	}
+	if r.factory == nil {
+		return nil, fmt.Errorf("documentation store cannot write to repository %s", repository)
+	}
+
+	key := repository + "@" + branch
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if store, ok := r.stores[key]; ok {
+		return store, nil
+	}
+
+	store, err := r.factory.ForRepository(repository, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create documentation store for %s: %w", repository, err)
+	}
+	r.stores[key] = store
+	return store, nil
+}
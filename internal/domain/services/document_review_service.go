@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultReviewCheckInterval is how often decisions are checked for staleness
+const DefaultReviewCheckInterval = 24 * time.Hour
+
+// DocumentReviewService flags decisions that have gone without changes or discussion for longer than their
+// project's review period, and asks the channel they came from to reconfirm or supersede them.
+// The verdict is kept in the status of the document's front matter and recorded in the audit log.
+type DocumentReviewService struct {
+	docs        *DocumentationService
+	index       ports.DocumentIndex
+	messages    ports.MessageRepository
+	projects    ports.ProjectRepository
+	chat        ports.ChatAccessProvider
+	audit       ports.AuditLog
+	coordinator ports.WorkCoordinator
+}
+
+// NewDocumentReviewService creates a DocumentReviewService. The coordinator is optional, with it each
+// channel is reminded by the replica owning it only.
+func NewDocumentReviewService(
+	docs *DocumentationService,
+	index ports.DocumentIndex,
+	messages ports.MessageRepository,
+	projects ports.ProjectRepository,
+	chat ports.ChatAccessProvider,
+	audit ports.AuditLog,
+	coordinator ports.WorkCoordinator,
+) *DocumentReviewService {
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	return &DocumentReviewService{
+		docs:        docs,
+		index:       index,
+		messages:    messages,
+		projects:    projects,
+		chat:        chat,
+		audit:       audit,
+		coordinator: coordinator,
+	}
+}
+
+// RegisterDocumentReview lets people reconfirm or supersede documents in chat.
+// It does nothing when the chat provider does not implement ports.ReviewRequester.
+func RegisterDocumentReview(chat ports.ChatAccessProvider, service *DocumentReviewService) {
+	if service == nil {
+		panic("document review service cannot be nil")
+	}
+	if requester, ok := chat.(ports.ReviewRequester); ok {
+		requester.OnReview(service.Review)
+	}
+}
+
+// Run checks for stale decisions at each interval until ctx is canceled.
+// Zero interval uses DefaultReviewCheckInterval. Failed checks are logged and retried at the next interval.
+func (s *DocumentReviewService) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultReviewCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if err := s.FlagStale(ctx, now); err != nil {
+				log.Printf("Failed to flag stale decisions: %v", err)
+			}
+		}
+	}
+}
+
+// FlagStale flags the current decisions whose last change, review or thread message is older than the
+// review period of their project, and posts a reminder to the channel of each. Every decision is flagged
+// once, and a decision that fails does not keep the others from being flagged. The owners of the flagged
+// decisions are then sent the digest of every document they own pending review.
+func (s *DocumentReviewService) FlagStale(ctx context.Context, now time.Time) error {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	var errs []error
+	owners := make(map[string]bool)
+	for _, doc := range docs {
+		if !doc.Type().IsDecision() {
+			continue
+		}
+		flagged, err := s.flagIfStale(ctx, doc, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", doc.Path(), err))
+		}
+		if flagged && doc.Owner() != "" {
+			owners[doc.Owner()] = true
+		}
+	}
+	if err := s.digestOwners(ctx, owners); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// flagIfStale flags a decision gone without activity for longer than its review period and reports whether it did
+func (s *DocumentReviewService) flagIfStale(ctx context.Context, doc *domain.IndexedDocument, now time.Time) (bool, error) {
+	project, err := s.project(ctx, doc.Project())
+	if err != nil {
+		return false, err
+	}
+	reviewAfter := domain.DefaultReviewAfter
+	if project != nil {
+		if !project.AcceptsMessages() {
+			return false, nil
+		}
+		reviewAfter = project.Documentation().ReviewAfter()
+	}
+	// The index tells when the document last changed, only documents older than the period are read
+	if !domain.DueForReview(doc.UpdatedAt(), now, reviewAfter) {
+		return false, nil
+	}
+
+	content, err := s.docs.GetDocumentation(ctx, doc.Path())
+	if err != nil {
+		return false, err
+	}
+	fm, body, err := domain.ParseFrontMatter(string(content))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse front matter: %w", err)
+	}
+	if !domain.DocumentStatusOf(fm).IsCurrent() {
+		return false, nil
+	}
+
+	lastActivity, err := s.lastActivity(ctx, doc, fm)
+	if err != nil {
+		return false, err
+	}
+	if !domain.DueForReview(lastActivity, now, reviewAfter) {
+		return false, nil
+	}
+
+	channels, err := s.reviewChannels(ctx, fm, project)
+	if err != nil || len(channels) == 0 {
+		return false, err
+	}
+
+	domain.FlagForReview(fm, now)
+	if err := s.docs.UpdateDocumentation(ctx, doc.Path(), fm.Apply(body), nil); err != nil {
+		return false, err
+	}
+
+	reminder := domain.RenderReviewReminder(doc.Title(), doc.Path(), lastActivity, now)
+	for _, channelID := range channels {
+		if err := s.remind(ctx, channelID, reminder, doc.Path()); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// OwnedReviews returns the documents an owner owns that wait for review, sorted by path
+func (s *DocumentReviewService) OwnedReviews(ctx context.Context, owner string) ([]*domain.IndexedDocument, error) {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	var owned []*domain.IndexedDocument
+	for _, doc := range docs {
+		if doc.Owner() == owner && doc.Status() == domain.DocumentStatusNeedsReview {
+			owned = append(owned, doc)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].Path() < owned[j].Path()
+	})
+	return owned, nil
+}
+
+// digestOwners sends each owner the digest of their documents pending review in a direct message. It does
+// nothing when the chat provider does not implement ports.DirectMessenger, the channels were reminded already.
+func (s *DocumentReviewService) digestOwners(ctx context.Context, owners map[string]bool) error {
+	messenger, ok := s.chat.(ports.DirectMessenger)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(owners))
+	for owner := range owners {
+		names = append(names, owner)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, owner := range names {
+		docs, err := s.OwnedReviews(ctx, owner)
+		if err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			continue
+		}
+		digest := domain.RenderOwnedReviews(owner, docs, func(path string) string {
+			return s.docs.DocumentLink(ctx, path)
+		})
+		if _, err := messenger.SendDirect(ctx, owner, digest); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send the review digest of %s: %w", owner, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reassign makes someone else the owner of a document and records who did it in the audit log. It returns who
+// owned the document before.
+func (s *DocumentReviewService) Reassign(ctx context.Context, path, owner, actor string) (string, error) {
+	previous, err := s.docs.ReassignOwner(ctx, path, owner)
+	if err != nil {
+		return "", err
+	}
+	owner = strings.TrimPrefix(strings.TrimSpace(owner), "@")
+	if previous == owner {
+		return previous, nil
+	}
+
+	entry, err := domain.NewAuditEntry(domain.AuditActionReassign, actor, path, previous, owner)
+	if err != nil {
+		return "", fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return "", fmt.Errorf("failed to record reassignment: %w", err)
+	}
+	return previous, nil
+}
+
+// lastActivity returns when a document last changed, was reviewed or discussed in its source thread
+func (s *DocumentReviewService) lastActivity(ctx context.Context, doc *domain.IndexedDocument, fm *domain.FrontMatter) (time.Time, error) {
+	last := doc.UpdatedAt()
+	if reviewed := domain.ReviewedAt(fm); reviewed.After(last) {
+		last = reviewed
+	}
+
+	threadID := fm.Get("thread")
+	if threadID == "" {
+		return last, nil
+	}
+	thread, err := s.messages.FindByThread(ctx, threadID)
+	if err != nil && !errors.Is(err, ports.ErrNotFound) {
+		return time.Time{}, fmt.Errorf("failed to find messages of thread %s: %w", threadID, err)
+	}
+	for _, msg := range thread {
+		if msg.Timestamp().After(last) {
+			last = msg.Timestamp()
+		}
+	}
+	return last, nil
+}
+
+// reviewChannels returns the channels owned by this replica to remind about a document: the channel of its
+// source message, or the channels of its project when the message is not stored
+func (s *DocumentReviewService) reviewChannels(ctx context.Context, fm *domain.FrontMatter, project *domain.Project) ([]string, error) {
+	var candidates []string
+	if messageID := fm.Get("source_message"); messageID != "" {
+		msg, err := s.messages.FindByID(ctx, messageID)
+		if err != nil && !errors.Is(err, ports.ErrNotFound) {
+			return nil, fmt.Errorf("failed to find message: %w", err)
+		}
+		if msg != nil && msg.ChannelID() != "" {
+			candidates = append(candidates, msg.ChannelID())
+		}
+	}
+	if len(candidates) == 0 && project != nil {
+		candidates = project.Channels()
+	}
+
+	var channels []string
+	for _, channelID := range candidates {
+		owned, err := s.owns(ctx, channelID)
+		if err != nil {
+			return nil, err
+		}
+		if owned {
+			channels = append(channels, channelID)
+		}
+	}
+	return channels, nil
+}
+
+// remind posts the reminder with review buttons, or as plain text when the chat provider has no buttons
+func (s *DocumentReviewService) remind(ctx context.Context, channelID, reminder, path string) error {
+	if requester, ok := s.chat.(ports.ReviewRequester); ok {
+		if err := requester.RequestReview(ctx, channelID, reminder, path); err != nil {
+			return fmt.Errorf("failed to request review in %s: %w", channelID, err)
+		}
+		return nil
+	}
+	if err := s.chat.SendMessage(ctx, channelID, reminder); err != nil {
+		return fmt.Errorf("failed to post review reminder to %s: %w", channelID, err)
+	}
+	return nil
+}
+
+// Review records the verdict of a person in the front matter of the document and in the audit log
+func (s *DocumentReviewService) Review(ctx context.Context, review *domain.DocumentReview) error {
+	if review == nil {
+		return fmt.Errorf("document review cannot be nil")
+	}
+
+	content, err := s.docs.GetDocumentation(ctx, review.Path())
+	if err != nil {
+		return err
+	}
+	fm, body, err := domain.ParseFrontMatter(string(content))
+	if err != nil {
+		return fmt.Errorf("failed to parse front matter of %s: %w", review.Path(), err)
+	}
+
+	from := domain.DocumentStatusOf(fm)
+	review.Apply(fm, time.Now())
+	if err := s.docs.UpdateDocumentation(ctx, review.Path(), fm.Apply(body), nil); err != nil {
+		return err
+	}
+
+	entry, err := domain.NewAuditEntry(domain.AuditActionReview, review.Actor(), review.Path(), from.String(), review.Verdict().Status().String())
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record review: %w", err)
+	}
+	return nil
+}
+
+// project returns the project of a document, or nil when it has none
+func (s *DocumentReviewService) project(ctx context.Context, id common.ID) (*domain.Project, error) {
+	if id.String() == "" {
+		return nil, nil
+	}
+	project, err := s.projects.FindByID(ctx, id)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	return project, nil
+}
+
+func (s *DocumentReviewService) owns(ctx context.Context, channelID string) (bool, error) {
+	if s.coordinator == nil {
+		return true, nil
+	}
+	owned, err := s.coordinator.Owns(ctx, channelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check ownership of channel %s: %w", channelID, err)
+	}
+	return owned, nil
+}
@@ -2,65 +2,420 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
 	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 type DocumentationService struct {
-	docStore ports.DocumentStoreProvider
+	stores   *DocStoreResolver
+	projects ports.ProjectRepository
 	aiAgent  ports.AiAgentProvider
+	graph    *ReferenceGraphService
+	index    ports.DocumentIndex
+	meetings *MeetingContext
+	images   *ImageAnalysis
+	glossary *GlossaryService
+	// provenance signs generated documents, nil leaves them unsigned
+	provenance *domain.ProvenanceSigner
+	threads    *ThreadService
+	// deployments recognizes the notifications of CI/CD bots, nil leaves them to the AI agent
+	deployments *domain.DeploymentPatterns
+	// risks maintains the risk registers, nil leaves them out
+	risks *RiskRegisterService
+	// canvases shows the weekly digests in chat, nil leaves them in the store only
+	canvases ports.CanvasPublisher
 }
 
-func NewDocumentationService(docs ports.DocumentStoreProvider, ai ports.AiAgentProvider) *DocumentationService {
-	if docs == nil {
-		panic("docStore cannot be nil")
+// NewDocumentationService creates a DocumentationService.
+// Documentation of a message is written to the repository of the project bound to its channel.
+// The meeting context is optional, with it documents name the meeting their discussion happened in.
+// The image analysis is optional too, with it the images shared with a message are described and stored
+// with its document. With the optional glossary, the terms of documented messages are kept in GLOSSARY.md
+// and linked from the documents. With the optional provenance signer, every write of a generated document
+// signs it again. With the optional thread service, documents name the thread their message was posted in.
+// With the optional deployment patterns, deployment notifications of CI/CD bots are documented as structured
+// status updates without asking the AI agent. With the optional risk register service, the risks raised in
+// documented messages are kept in docs/risks.md. With the optional canvas publisher, the weekly status rollups of
+// projects with digestCanvas turned on are also published on the canvas of their channels.
+func NewDocumentationService(
+	stores *DocStoreResolver,
+	projects ports.ProjectRepository,
+	ai ports.AiAgentProvider,
+	graph *ReferenceGraphService,
+	index ports.DocumentIndex,
+	meetings *MeetingContext,
+	images *ImageAnalysis,
+	glossary *GlossaryService,
+	provenance *domain.ProvenanceSigner,
+	threads *ThreadService,
+	deployments *domain.DeploymentPatterns,
+	risks *RiskRegisterService,
+	canvases ports.CanvasPublisher,
+) *DocumentationService {
+	if stores == nil {
+		panic("docStore resolver cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
 	}
 	if ai == nil {
 		panic("aiAgent cannot be nil")
 	}
+	if graph == nil {
+		panic("reference graph cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
 	return &DocumentationService{
-		docStore: docs,
-		aiAgent:  ai,
+		stores:      stores,
+		projects:    projects,
+		aiAgent:     ai,
+		graph:       graph,
+		index:       index,
+		meetings:    meetings,
+		images:      images,
+		glossary:    glossary,
+		provenance:  provenance,
+		threads:     threads,
+		deployments: deployments,
+		risks:       risks,
+		canvases:    canvases,
 	}
 }
 
-// CreateDocumentation generates and stores documentation from a message
-func (s *DocumentationService) CreateDocumentation(
-	ctx context.Context,
-	msgType domain.MessageType,
-	category domain.Category,
-	content string,
-	references []*domain.Reference,
-) error {
+// DeploymentNotice reads the deployment notification a message is, false when it is not one or no deployment
+// patterns are registered
+func (s *DocumentationService) DeploymentNotice(msg *domain.Message) (*domain.DeploymentNotice, bool) {
+	return s.deployments.Match(msg.Content().Text())
+}
+
+// CreateDocumentation generates and stores documentation from a message and returns the document path.
+// Documents whose claims the message does not support are stored flagged for review, and their grounding
+// report is returned; it is nil for the documents committed as they are.
+func (s *DocumentationService) CreateDocumentation(ctx context.Context, msg *domain.Message) (string, *domain.GroundingReport, error) {
 	if ctx == nil {
-		return fmt.Errorf("context cannot be nil")
+		return "", nil, fmt.Errorf("context cannot be nil")
+	}
+	if msg == nil {
+		return "", nil, fmt.Errorf("message cannot be nil")
+	}
+
+	// Deployment notifications are documented as they were parsed, nothing is generated
+	if notice, ok := s.DeploymentNotice(msg); ok {
+		return s.createDocumentation(ctx, msg, notice)
+	}
+	return s.createDocumentation(ctx, msg, nil)
+}
+
+// preparedDocument is a document a message is documented with as it is, instead of one the AI agent generates
+type preparedDocument interface {
+	Title() string
+	Document() string
+	RollupEntry() string
+	Record(fm *domain.FrontMatter)
+}
+
+// createDocumentation stores the documentation of a message, generated unless a prepared document is given
+func (s *DocumentationService) createDocumentation(ctx context.Context, msg *domain.Message, prepared preparedDocument) (string, *domain.GroundingReport, error) {
+	// A message documented before, like a retried or replayed one, keeps its document
+	key := domain.MessageIdempotencyKey(msg)
+	existing, err := s.index.FindByIdempotencyKey(ctx, key)
+	if err == nil {
+		logf(ctx, "Message %s was already documented in %s", msg.ID(), existing.Path())
+		return existing.Path(), nil, nil
+	}
+	if !errors.Is(err, ports.ErrNotFound) {
+		return "", nil, fmt.Errorf("failed to look up whether message %s was documented: %w", msg.ID(), err)
 	}
 
 	// Generate documentation using AI
 	metadata := map[string]interface{}{
-		"type":       msgType.String(),
-		"category":   category.String(),
+		"type":       msg.Type().String(),
+		"category":   msg.Category().String(),
 		"created_at": time.Now().UTC(),
-		"references": references,
+		"references": msg.LinkedReferences(),
+	}
+
+	docConfig, err := s.documentationConfig(ctx, msg)
+	if err != nil {
+		return "", nil, err
 	}
 
-	doc, err := s.aiAgent.GenerateDocumentation(ctx, content, metadata)
+	// The store is checked first, content of local-only projects is not generated for a store it cannot go to
+	store, err := s.stores.ForDocumentation(msg.ChannelID(), docConfig)
 	if err != nil {
-		return fmt.Errorf("failed to generate documentation: %w", err)
+		return "", nil, err
+	}
+
+	images := s.analyzeImages(ctx, msg, docConfig)
+	var doc string
+	if prepared != nil {
+		doc = prepared.Document()
+	} else if doc, err = s.generateDocument(ctx, msg, images, metadata, docConfig); err != nil {
+		return "", nil, err
+	}
+
+	meeting := s.originatingMeeting(ctx, msg)
+	if docConfig.StatusRollup.Applies(msg.Type()) {
+		if prepared != nil {
+			doc = prepared.RollupEntry()
+		}
+		path, appended, err := s.appendToRollup(ctx, store, docConfig, msg, doc, meeting)
+		if err != nil {
+			return "", nil, err
+		}
+		if !appended {
+			logf(ctx, "Message %s was already rolled up in %s", msg.ID(), path)
+			return path, nil, nil
+		}
+		attached := make(map[string][]byte)
+		s.updateRisks(ctx, store, msg, path, docConfig, attached)
+		if err := storeAttached(ctx, store, path, attached); err != nil {
+			logf(ctx, "Failed to update the risk register with message %s: %v", msg.ID(), err)
+		}
+		s.publishDigest(ctx, store, docConfig, msg)
+		return path, nil, nil
 	}
 
 	// Store the documentation
-	path := s.generatePath(msgType, category)
-	if err := s.docStore.StoreDocument(ctx, path, []byte(doc), metadata); err != nil {
-		return fmt.Errorf("failed to store documentation: %w", err)
+	var title string
+	if prepared != nil {
+		title = prepared.Title()
+	} else {
+		title = s.documentTitle(ctx, msg, doc)
+	}
+	path, err := s.uniquePath(ctx, store, docConfig.PathStrategy().Path(msg, title, time.Now().UTC()))
+	if err != nil {
+		return "", nil, err
+	}
+	glossary, attached := s.updateGlossary(ctx, store, msg, path)
+	s.updateRisks(ctx, store, msg, path, docConfig, attached)
+	attachImages(ctx, store, attached, images)
+	fm := s.frontMatterFor(msg, meeting, s.threadTitle(ctx, msg))
+	fm.Set("idempotency_key", key)
+	var flagged *domain.GroundingReport
+	if prepared != nil {
+		prepared.Record(fm)
+	} else if grounding := domain.CheckGrounding(doc, groundingSources(msg, images)...); grounding.Score() < docConfig.Grounding() {
+		logf(ctx, "Flagging %s for review, %d of its %d claims are not supported by message %s", path, len(grounding.Unsupported), grounding.Claims, msg.ID())
+		domain.FlagUngrounded(fm, grounding, time.Now())
+		flagged = &grounding
+	}
+	content := fm.Apply(withImageSection(domain.LinkTerms(doc, path, glossary), path, images))
+
+	// The document is indexed first so the tables of contents stored with it list it
+	if err := s.indexDocument(ctx, path, doc, fm, msg, docConfig, key); err != nil {
+		return "", nil, err
+	}
+	if err := s.storeWithContents(ctx, store, docConfig, path, content, metadata, msg.Category(), attached); err != nil {
+		// Keep the index in line with the store, the document was not written
+		_ = s.index.Remove(ctx, path)
+		return "", nil, err
+	}
+
+	if err := s.graph.RecordDocument(path, msg); err != nil {
+		return "", nil, fmt.Errorf("failed to record document references: %w", err)
+	}
+
+	return path, flagged, nil
+}
+
+// DocumentUpdate is an addition to an existing document, or a new version of it, generated from a message and
+// not stored yet
+type DocumentUpdate struct {
+	path     string
+	content  string
+	metadata map[string]interface{}
+	attached map[string][]byte
+	msg      *domain.Message
+	diff     *domain.DocumentDiff
+	// version is the version of a regenerated document, zero for additions
+	version uint
+}
+
+// Path returns the path of the updated document
+func (u *DocumentUpdate) Path() string {
+	return u.path
+}
+
+// Message returns the message the addition was generated from
+func (u *DocumentUpdate) Message() *domain.Message {
+	return u.msg
+}
+
+// Diff returns the change the update makes to the document
+func (u *DocumentUpdate) Diff() *domain.DocumentDiff {
+	return u.diff
+}
+
+// Version returns the version of a document regenerated with DraftRegeneration, zero for additions
+func (u *DocumentUpdate) Version() uint {
+	return u.version
+}
+
+// pending returns the addition to keep while the author of its message decides on its diff, until expiresAt
+func (u *DocumentUpdate) pending(expiresAt time.Time) (*domain.PendingUpdate, error) {
+	revision, _ := u.metadata["base_revision"].(string)
+	return domain.NewPendingUpdate(u.msg.ThreadID().String(), u.msg.ID().String(), u.msg.Sender(), u.path, u.content,
+		revision, u.attached, expiresAt)
+}
+
+// restoreUpdate returns the addition a pending update keeps, drafted from the message. It has no diff, the diff
+// was posted when the update was kept.
+func restoreUpdate(pending *domain.PendingUpdate, msg *domain.Message) *DocumentUpdate {
+	metadata := map[string]interface{}{
+		"type":       msg.Type().String(),
+		"category":   msg.Category().String(),
+		"references": msg.LinkedReferences(),
+	}
+	if revision := pending.BaseRevision(); revision != "" {
+		metadata["base_revision"] = revision
+	}
+	return &DocumentUpdate{
+		path:     pending.Path(),
+		content:  pending.Content(),
+		metadata: metadata,
+		attached: pending.Attached(),
+		msg:      msg,
+	}
+}
+
+// AppendDocumentation generates documentation for a message and appends it to an existing document
+func (s *DocumentationService) AppendDocumentation(ctx context.Context, path string, msg *domain.Message) error {
+	update, err := s.DraftAppend(ctx, path, msg)
+	if err != nil {
+		return err
+	}
+	return s.ApplyUpdate(ctx, update)
+}
+
+// DraftAppend generates documentation for a message as an addendum to an existing document, without storing
+// it, so the change can be reviewed before ApplyUpdate stores it
+func (s *DocumentationService) DraftAppend(ctx context.Context, path string, msg *domain.Message) (*DocumentUpdate, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("message cannot be nil")
+	}
+	if err := s.checkWritable(ctx, path); err != nil {
+		return nil, err
+	}
+
+	store, err := s.storeFor(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	existing, revision, err := readRevision(ctx, store, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve documentation: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"type":       msg.Type().String(),
+		"category":   msg.Category().String(),
+		"created_at": time.Now().UTC(),
+		"references": msg.LinkedReferences(),
+	}
+	// Changes people make to the document until the update is applied are merged with it
+	if revision != "" {
+		metadata["base_revision"] = revision
+	}
+
+	docConfig, err := s.documentationConfig(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	// The document may have been written before its project became local-only
+	if err := checkResidency(msg.ChannelID(), docConfig.LocalOnly, "storage", store); err != nil {
+		return nil, err
+	}
+
+	images := s.analyzeImages(ctx, msg, docConfig)
+	addition, err := s.generateDocument(ctx, msg, images, metadata, docConfig)
+	if err != nil {
+		return nil, err
+	}
+	delete(metadata, "created_at")
+	glossary, attached := s.updateGlossary(ctx, store, msg, path)
+	s.updateRisks(ctx, store, msg, path, docConfig, attached)
+	attachImages(ctx, store, attached, images)
+
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+	if domain.HasProvenance(fm) {
+		domain.RecordProvenance(fm, msg)
+	}
+	content := fmt.Sprintf("%s\n\n## Addendum %s\n\n%s\n",
+		strings.TrimRight(fm.Apply(body), "\n"),
+		time.Now().UTC().Format("2006-01-02"),
+		withImageSection(domain.LinkTerms(stripTitle(addition), path, glossary), path, images),
+	)
+
+	return &DocumentUpdate{
+		path:     path,
+		content:  content,
+		metadata: metadata,
+		attached: attached,
+		msg:      msg,
+		diff:     domain.NewDocumentDiff(path, existing, []byte(content)),
+	}, nil
+}
+
+// ApplyUpdate stores an update drafted with DraftAppend or DraftRegeneration, with the files attached to it
+func (s *DocumentationService) ApplyUpdate(ctx context.Context, update *DocumentUpdate) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+	if update == nil {
+		return fmt.Errorf("update cannot be nil")
+	}
+
+	store, err := s.storeFor(ctx, update.path)
+	if err != nil {
+		return err
+	}
+	if err := storeAttached(ctx, store, update.path, update.attached); err != nil {
+		return err
+	}
+	if err := s.UpdateDocumentation(ctx, update.path, update.content, update.metadata); err != nil {
+		return err
+	}
+	if update.version > 0 {
+		if err := s.refreshIndexed(ctx, update); err != nil {
+			return err
+		}
+	}
+
+	if err := s.graph.RecordDocument(update.path, update.msg); err != nil {
+		return fmt.Errorf("failed to record document references: %w", err)
 	}
 
 	return nil
 }
 
+// DocumentLink returns a link to the document, or the bare path when the store cannot link to it
+func (s *DocumentationService) DocumentLink(ctx context.Context, path string) string {
+	store, err := s.storeFor(ctx, path)
+	if err != nil {
+		return path
+	}
+	if linker, ok := store.(ports.DocumentLinker); ok {
+		return linker.DocumentURL(path)
+	}
+	return path
+}
+
 // UpdateDocumentation updates existing documentation
 func (s *DocumentationService) UpdateDocumentation(
 	ctx context.Context,
@@ -71,6 +426,9 @@ func (s *DocumentationService) UpdateDocumentation(
 	if ctx == nil {
 		return fmt.Errorf("context cannot be nil")
 	}
+	if err := s.checkWritable(ctx, path); err != nil {
+		return err
+	}
 
 	// Update metadata with modification time
 	if metadata == nil {
@@ -78,13 +436,85 @@ func (s *DocumentationService) UpdateDocumentation(
 	}
 	metadata["updated_at"] = time.Now().UTC()
 
-	if err := s.docStore.UpdateDocument(ctx, path, []byte(content), metadata); err != nil {
+	store, err := s.storeFor(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	content, err = s.sign(s.graph.InjectBacklinks(path, content))
+	if err != nil {
+		return err
+	}
+	// The commit of the update tells how much of the document it changed
+	if existing, err := store.GetDocument(ctx, path); err == nil {
+		if diff := domain.NewDocumentDiff(path, existing, []byte(content)); !diff.IsEmpty() {
+			metadata["diff_summary"] = diff.Summary()
+		}
+	}
+	if err := store.UpdateDocument(ctx, path, []byte(content), metadata); err != nil {
 		return fmt.Errorf("failed to update documentation: %w", err)
 	}
+	s.touchIndexedFrontMatter(ctx, path, content)
 
 	return nil
 }
 
+// touchIndexedFrontMatter records in the index that a document changed, with the owner and status of its new
+// front matter
+func (s *DocumentationService) touchIndexedFrontMatter(ctx context.Context, path, content string) {
+	entry, err := s.index.FindByPath(ctx, path)
+	if err != nil {
+		return
+	}
+	if fm, _, err := domain.ParseFrontMatter(content); err == nil {
+		entry.SetFrontMatter(fm)
+	}
+	entry.Touch()
+	if err := s.index.Index(ctx, entry); err != nil {
+		logf(ctx, "Failed to update the index entry of %s: %v", path, err)
+	}
+}
+
+// ReassignOwner makes someone else the owner of a document, in its front matter and the document index. It
+// returns who owned the document before, empty when nobody did.
+func (s *DocumentationService) ReassignOwner(ctx context.Context, path, owner string) (string, error) {
+	if ctx == nil {
+		return "", fmt.Errorf("context cannot be nil")
+	}
+	if _, err := s.index.FindByPath(ctx, path); err != nil {
+		return "", fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+
+	existing, err := s.GetDocumentation(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+	previous := domain.OwnerOf(fm)
+	if err := domain.AssignOwner(fm, owner); err != nil {
+		return "", err
+	}
+	if domain.OwnerOf(fm) == previous {
+		return previous, nil
+	}
+	return previous, s.UpdateDocumentation(ctx, path, fm.Apply(body), nil)
+}
+
+// touchIndexed records in the index that a document changed, so its entry tells how current it is
+func (s *DocumentationService) touchIndexed(ctx context.Context, path string) {
+	entry, err := s.index.FindByPath(ctx, path)
+	if err != nil {
+		return
+	}
+	entry.Touch()
+	if err := s.index.Index(ctx, entry); err != nil {
+		logf(ctx, "Failed to update the index entry of %s: %v", path, err)
+	}
+}
+
 // GetDocumentation retrieves documentation by path
 func (s *DocumentationService) GetDocumentation(
 	ctx context.Context,
@@ -94,7 +524,12 @@ func (s *DocumentationService) GetDocumentation(
 		return nil, fmt.Errorf("context cannot be nil")
 	}
 
-	doc, err := s.docStore.GetDocument(ctx, path)
+	store, err := s.storeFor(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := store.GetDocument(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve documentation: %w", err)
 	}
@@ -102,27 +537,918 @@ func (s *DocumentationService) GetDocumentation(
 	return doc, nil
 }
 
-// ListDocumentation lists all documentation in a category
+// ListDocumentation lists all documentation in a category.
+// Documents stored outside docs/<category> of the default repository, because their project uses
+// another path scheme or repository, are found through the document index.
+// When tags are given only documents carrying all of them are returned.
 func (s *DocumentationService) ListDocumentation(
 	ctx context.Context,
 	category domain.Category,
+	tags ...domain.Tag,
 ) ([]string, error) {
 	if ctx == nil {
 		return nil, fmt.Errorf("context cannot be nil")
 	}
 
 	basePath := filepath.Join("docs", category.String())
-	docs, err := s.docStore.ListDocuments(ctx, basePath)
+	stored, err := s.stores.Default().ListDocuments(ctx, basePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list documentation: %w", err)
 	}
 
-	return docs, nil
+	var docs []string
+	for _, path := range stored {
+		if !domain.IsTableOfContents(path) {
+			docs = append(docs, path)
+		}
+	}
+
+	indexed, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+	listed := make(map[string]bool, len(docs))
+	for _, path := range docs {
+		listed[path] = true
+	}
+	for _, entry := range indexed {
+		if entry.Category() == category && !listed[entry.Path()] {
+			docs = append(docs, entry.Path())
+		}
+	}
+
+	if len(tags) == 0 {
+		return docs, nil
+	}
+
+	var tagged []string
+	for _, path := range docs {
+		entry, err := s.index.FindByPath(ctx, path)
+		if errors.Is(err, ports.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up document tags: %w", err)
+		}
+		if hasAllTags(entry, tags) {
+			tagged = append(tagged, path)
+		}
+	}
+
+	return tagged, nil
+}
+
+// ListTags returns every tag in use with the number of documents carrying it, counting the documents visible
+// from the viewer project only, a zero ID for channels outside of projects
+func (s *DocumentationService) ListTags(ctx context.Context, viewer common.ID) (map[domain.Tag]int, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	docs, err := NewSharedIndex(s.index, s.projects).Visible(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.Tag]int)
+	for _, doc := range docs {
+		for _, tag := range doc.Tags() {
+			counts[tag]++
+		}
+	}
+	return counts, nil
 }
 
-// generatePath creates the storage path for documentation
-func (s *DocumentationService) generatePath(msgType domain.MessageType, category domain.Category) string {
-	timestamp := time.Now().UTC().Format("20060102-150405")
-	filename := fmt.Sprintf("%s-%s.md", msgType.String(), timestamp)
-	return filepath.Join("docs", category.String(), filename)
+// FindByTag returns the paths of the documents carrying the tag that are visible from the viewer project, a zero
+// ID for channels outside of projects
+func (s *DocumentationService) FindByTag(ctx context.Context, tag domain.Tag, viewer common.ID) ([]string, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	docs, err := s.index.FindByTag(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents by tag: %w", err)
+	}
+
+	shared := NewSharedIndex(s.index, s.projects)
+	paths := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		visible, err := shared.IsVisible(ctx, doc, viewer)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			paths = append(paths, doc.Path())
+		}
+	}
+	return paths, nil
+}
+
+// AddTags adds tags to a document's front matter and the tag index
+func (s *DocumentationService) AddTags(ctx context.Context, path string, tags ...domain.Tag) error {
+	return s.editTags(ctx, path, func(current []domain.Tag) []domain.Tag {
+		for _, tag := range tags {
+			if !containsTag(current, tag) {
+				current = append(current, tag)
+			}
+		}
+		return current
+	})
+}
+
+// RemoveTags removes tags from a document's front matter and the tag index
+func (s *DocumentationService) RemoveTags(ctx context.Context, path string, tags ...domain.Tag) error {
+	return s.editTags(ctx, path, func(current []domain.Tag) []domain.Tag {
+		kept := current[:0]
+		for _, tag := range current {
+			if !containsTag(tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		return kept
+	})
+}
+
+func (s *DocumentationService) editTags(ctx context.Context, path string, edit func([]domain.Tag) []domain.Tag) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+	if err := s.checkWritable(ctx, path); err != nil {
+		return err
+	}
+
+	existing, err := s.GetDocumentation(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+
+	tags := edit(domain.NewTags(fm.GetList("tags")))
+	fm.SetList("tags", domain.TagStrings(tags))
+
+	if err := s.UpdateDocumentation(ctx, path, fm.Apply(body), nil); err != nil {
+		return err
+	}
+
+	entry, err := s.index.FindByPath(ctx, path)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	entry.SetTags(tags)
+	if err := s.index.Index(ctx, entry); err != nil {
+		return fmt.Errorf("failed to index documentation: %w", err)
+	}
+
+	return nil
+}
+
+// SetVisibility marks a document as internal to its project or shared with every project, in its front matter
+// and the document index
+func (s *DocumentationService) SetVisibility(ctx context.Context, path string, visibility domain.Visibility) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+	if !visibility.IsValid() {
+		return domain.ErrInvalidVisibility
+	}
+
+	entry, err := s.index.FindByPath(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	if err := s.checkWritable(ctx, path); err != nil {
+		return err
+	}
+
+	existing, err := s.GetDocumentation(ctx, path)
+	if err != nil {
+		return err
+	}
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+	fm.Set("visibility", visibility.String())
+	if err := s.UpdateDocumentation(ctx, path, fm.Apply(body), nil); err != nil {
+		return err
+	}
+
+	// The entry is looked up again, updating the document touched it
+	if refreshed, err := s.index.FindByPath(ctx, path); err == nil {
+		entry = refreshed
+	}
+	entry.SetVisibility(visibility)
+	if err := s.index.Index(ctx, entry); err != nil {
+		return fmt.Errorf("failed to index documentation: %w", err)
+	}
+	return nil
+}
+
+// MoveToCategory files a document under another category: its front matter, index entry and tables of
+// contents are updated, and it is moved when its path names the old category. It returns the category
+// the document was filed under before and its new path.
+func (s *DocumentationService) MoveToCategory(ctx context.Context, path string, category domain.Category) (domain.Category, string, error) {
+	if ctx == nil {
+		return "", "", fmt.Errorf("context cannot be nil")
+	}
+
+	entry, err := s.index.FindByPath(ctx, path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	from := entry.Category()
+	if from == category {
+		return from, path, nil
+	}
+	if err := s.checkWritable(ctx, path); err != nil {
+		return "", "", err
+	}
+
+	store, err := s.stores.Resolve(entry.Repository(), entry.Branch())
+	if err != nil {
+		return "", "", err
+	}
+	existing, err := store.GetDocument(ctx, path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get documentation: %w", err)
+	}
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+	fm.Set("category", category.String())
+
+	newPath := domain.RecategorizedPath(path, from, category)
+	if newPath != path {
+		if newPath, err = s.uniquePath(ctx, store, newPath); err != nil {
+			return "", "", err
+		}
+	}
+	moved, err := entry.Moved(newPath, category)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create index entry: %w", err)
+	}
+
+	// The moved document is indexed first so the tables of contents list it under its new category
+	if err := s.index.Remove(ctx, path); err != nil {
+		return "", "", fmt.Errorf("failed to index documentation: %w", err)
+	}
+	if err := s.index.Index(ctx, moved); err != nil {
+		return "", "", fmt.Errorf("failed to index documentation: %w", err)
+	}
+	if err := s.moveWithContents(ctx, store, moved, path, fm.Apply(body), from, category); err != nil {
+		// Keep the index in line with the store, the document was not moved
+		_ = s.index.Remove(ctx, newPath)
+		_ = s.index.Index(ctx, entry)
+		return "", "", err
+	}
+
+	if err := s.graph.MoveDocument(path, newPath); err != nil {
+		return "", "", fmt.Errorf("failed to record document references: %w", err)
+	}
+	return from, newPath, nil
+}
+
+// RelateDocuments records that a document supersedes or amends an older one. The older document gets the
+// status and a banner linking to the newer one, both front matters list each other, the reference graph keeps
+// the relation and the tables of contents note it. Documents in the same repository are written in one commit.
+func (s *DocumentationService) RelateDocuments(ctx context.Context, rel *domain.DocumentRelation) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+	if rel == nil {
+		return fmt.Errorf("document relation cannot be nil")
+	}
+
+	newer, err := s.index.FindByPath(ctx, rel.From())
+	if err != nil {
+		return fmt.Errorf("failed to look up indexed document %s: %w", rel.From(), err)
+	}
+	older, err := s.index.FindByPath(ctx, rel.To())
+	if err != nil {
+		return fmt.Errorf("failed to look up indexed document %s: %w", rel.To(), err)
+	}
+	for _, path := range []string{rel.From(), rel.To()} {
+		if err := s.checkWritable(ctx, path); err != nil {
+			return err
+		}
+	}
+
+	newerContent, err := s.relatedContent(ctx, newer, func(fm *domain.FrontMatter, body string) string {
+		fm.SetList(rel.Relation().FrontMatterKey(), appendMissing(fm.GetList(rel.Relation().FrontMatterKey()), rel.To()))
+		return body
+	})
+	if err != nil {
+		return err
+	}
+	olderContent, err := s.relatedContent(ctx, older, func(fm *domain.FrontMatter, body string) string {
+		key := rel.Relation().InverseFrontMatterKey()
+		fm.SetList(key, appendMissing(fm.GetList(key), rel.From()))
+		// A superseded document stays superseded when it is amended afterwards
+		if domain.DocumentStatusOf(fm) != domain.DocumentStatusSuperseded {
+			fm.Set("status", rel.Relation().Status().String())
+		}
+		return domain.AddBanner(body, rel.Banner(newer.Title()))
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.graph.RecordRelation(rel); err != nil {
+		return fmt.Errorf("failed to record document relation: %w", err)
+	}
+
+	message := fmt.Sprintf("Note that %s %s %s", filepath.Base(rel.From()), rel.Relation(), filepath.Base(rel.To()))
+	for _, group := range groupByLocation(newer, older) {
+		store, err := s.stores.Resolve(group[0].Repository(), group[0].Branch())
+		if err != nil {
+			return err
+		}
+
+		var categories []domain.Category
+		files := make(map[string][]byte)
+		for _, doc := range group {
+			content := newerContent
+			if doc == older {
+				content = olderContent
+			}
+			files[doc.Path()] = []byte(s.graph.InjectBacklinks(doc.Path(), content))
+			categories = append(categories, doc.Category())
+		}
+		contents, err := s.tableOfContents(ctx, group[0].Repository(), group[0].Branch(), categories...)
+		if err != nil {
+			return err
+		}
+		for tocPath, toc := range contents {
+			files[tocPath] = toc
+		}
+		if err := writeFiles(ctx, store, files, message); err != nil {
+			return fmt.Errorf("failed to relate documentation: %w", err)
+		}
+		for _, doc := range group {
+			s.touchIndexed(ctx, doc.Path())
+		}
+	}
+	return nil
+}
+
+// relatedContent returns the content of an indexed document after editing its front matter and body
+func (s *DocumentationService) relatedContent(
+	ctx context.Context,
+	doc *domain.IndexedDocument,
+	edit func(fm *domain.FrontMatter, body string) string,
+) (string, error) {
+	store, err := s.stores.Resolve(doc.Repository(), doc.Branch())
+	if err != nil {
+		return "", err
+	}
+	existing, err := store.GetDocument(ctx, doc.Path())
+	if err != nil {
+		return "", fmt.Errorf("failed to get documentation: %w", err)
+	}
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse front matter of %s: %w", doc.Path(), err)
+	}
+	body = edit(fm, body)
+	return fm.Apply(body), nil
+}
+
+// groupByLocation groups indexed documents by the repository and branch they are stored in
+func groupByLocation(docs ...*domain.IndexedDocument) [][]*domain.IndexedDocument {
+	var groups [][]*domain.IndexedDocument
+	for _, doc := range docs {
+		placed := false
+		for i, group := range groups {
+			if group[0].Repository() == doc.Repository() && group[0].Branch() == doc.Branch() {
+				groups[i] = append(group, doc)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []*domain.IndexedDocument{doc})
+		}
+	}
+	return groups
+}
+
+// appendMissing appends the value to the list unless it is already there
+func appendMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// DiscardDocumentation removes the documents generated from a message and returns their paths.
+// Documents the message was merged into and status rollups also hold other messages, so they are kept.
+func (s *DocumentationService) DiscardDocumentation(ctx context.Context, msg *domain.Message) ([]string, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("message cannot be nil")
+	}
+
+	source, err := domain.NewMessageReference(msg.ID().String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message reference: %w", err)
+	}
+
+	var removed []string
+	for _, ref := range s.graph.ReferencedBy(*source) {
+		if !ref.Type().IsDocument() {
+			continue
+		}
+		ok, err := s.discardDocument(ctx, ref.Value(), msg)
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed = append(removed, ref.Value())
+		}
+	}
+	return removed, nil
+}
+
+// discardDocument removes a document when it was generated from the message alone
+func (s *DocumentationService) discardDocument(ctx context.Context, path string, msg *domain.Message) (bool, error) {
+	entry, err := s.index.FindByPath(ctx, path)
+	if errors.Is(err, ports.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	if err := s.checkWritable(ctx, path); err != nil {
+		return false, err
+	}
+
+	store, err := s.stores.Resolve(entry.Repository(), entry.Branch())
+	if err != nil {
+		return false, err
+	}
+	existing, err := store.GetDocument(ctx, path)
+	if err != nil {
+		return false, fmt.Errorf("failed to get documentation: %w", err)
+	}
+	fm, _, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+	if fm.Get("source_message") != msg.ID().String() {
+		return false, nil
+	}
+
+	// The document is removed from the index first so the tables of contents no longer list it
+	if err := s.index.Remove(ctx, path); err != nil {
+		return false, fmt.Errorf("failed to remove indexed document: %w", err)
+	}
+	if err := s.removeWithContents(ctx, store, entry); err != nil {
+		_ = s.index.Index(ctx, entry)
+		return false, err
+	}
+	if err := s.graph.RemoveDocument(path); err != nil {
+		return false, fmt.Errorf("failed to remove document references: %w", err)
+	}
+	return true, nil
+}
+
+// originatingMeeting returns the meeting a message's thread happened during, or nil. The calendar
+// only adds context, so documenting goes on without it when it cannot be read.
+func (s *DocumentationService) originatingMeeting(ctx context.Context, msg *domain.Message) *domain.Meeting {
+	if s.meetings == nil {
+		return nil
+	}
+	meeting, err := s.meetings.OriginatingMeeting(ctx, msg)
+	if err != nil {
+		logf(ctx, "Failed to find the originating meeting of message %s: %v", msg.ID(), err)
+		return nil
+	}
+	return meeting
+}
+
+// maxGenerationAttempts is how often a document is generated before its lint issues fail the message
+const maxGenerationAttempts = 2
+
+// generateDocument asks the AI agent for the documentation of a message. Diagrams are requested when the
+// project wants them for the message, the document follows the project's template for its type when there is one,
+// and Mermaid blocks that do not parse are dropped before anything is stored.
+// The document is linted and fixed where possible, otherwise it is generated again with the issues the draft had.
+func (s *DocumentationService) generateDocument(
+	ctx context.Context,
+	msg *domain.Message,
+	images []*domain.ImageAsset,
+	metadata map[string]interface{},
+	docConfig domain.DocumentationConfig,
+) (string, error) {
+	if err := checkResidency(msg.ChannelID(), docConfig.LocalOnly, "documentation", s.aiAgent); err != nil {
+		return "", err
+	}
+	if docConfig.DiagramsFor(msg) {
+		metadata = withMetadata(metadata, "diagrams", true)
+	}
+	if template := docConfig.TemplateFor(msg.Type()); template != "" {
+		metadata = withMetadata(metadata, "template", template)
+	}
+
+	linkExists := s.linkExists(ctx)
+	request := metadata
+	for attempt := 1; ; attempt++ {
+		doc, err := s.aiAgent.GenerateDocumentation(ctx, generationInput(msg, images), request)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate documentation: %w", err)
+		}
+
+		doc, dropped := domain.DropInvalidMermaid(doc)
+		for _, err := range dropped {
+			logf(ctx, "Dropped a diagram from the documentation of message %s: %v", msg.ID(), err)
+		}
+
+		doc, report := domain.LintDocument(doc, linkExists)
+		for _, issue := range report.Fixed {
+			logf(ctx, "Fixed the documentation of message %s: %s", msg.ID(), issue)
+		}
+		if report.OK() {
+			return doc, nil
+		}
+		if attempt == maxGenerationAttempts {
+			return "", report.Err()
+		}
+
+		// Tell the model what was wrong with the draft, so it does not make the same mistakes
+		logf(ctx, "Generating the documentation of message %s again: %v", msg.ID(), report.Err())
+		issues := make([]string, len(report.Remaining))
+		for i, issue := range report.Remaining {
+			issues[i] = issue.String()
+		}
+		request = withMetadata(metadata, "lint_issues", issues)
+	}
+}
+
+// linkExists returns the check of the relative links in generated documentation. A link holds when it ends in
+// the path of an indexed document, a table of contents or the glossary, as the model cannot know where the
+// document will be stored. Links are left unchecked when the index cannot be listed.
+func (s *DocumentationService) linkExists(ctx context.Context) func(target string) bool {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		logf(ctx, "Leaving the links of generated documentation unchecked: %v", err)
+		return nil
+	}
+
+	paths := []string{domain.GlossaryFile}
+	for _, doc := range docs {
+		paths = append(paths, doc.Path())
+	}
+	return func(target string) bool {
+		target = strings.TrimPrefix(filepath.ToSlash(filepath.Clean("/"+target)), "/")
+		if domain.IsTableOfContents(target) {
+			return true
+		}
+		for _, p := range paths {
+			if p == target || strings.HasSuffix(p, "/"+target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// withMetadata returns a copy of the metadata with a value added, leaving the original to other requests
+func withMetadata(metadata map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	request := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		request[k] = v
+	}
+	request[key] = value
+	return request
+}
+
+// analyzeImages describes the images shared with a message, if image analysis is enabled. Images of
+// local-only projects are left out when the vision model is not local.
+func (s *DocumentationService) analyzeImages(ctx context.Context, msg *domain.Message, docConfig domain.DocumentationConfig) []*domain.ImageAsset {
+	if s.images == nil || len(msg.Images()) == 0 {
+		return nil
+	}
+	if err := checkResidency(msg.ChannelID(), docConfig.LocalOnly, "image analysis", s.images.describer); err != nil {
+		logf(ctx, "Leaving the images of message %s out: %v", msg.ID(), err)
+		return nil
+	}
+	return s.images.Analyze(ctx, msg)
+}
+
+// attachImages adds the images of a message to the files stored with its document. Images are named by their
+// contents, so the ones already in the store, shared with earlier messages, are not written again.
+func attachImages(ctx context.Context, store ports.DocumentStoreProvider, attached map[string][]byte, images []*domain.ImageAsset) {
+	for _, image := range images {
+		if _, err := store.GetDocument(ctx, image.Path()); err == nil {
+			continue
+		}
+		attached[image.Path()] = image.Data()
+	}
+}
+
+// readRevision reads a document with the revision it is at, the revision is empty when the store does not
+// version documents
+func readRevision(ctx context.Context, store ports.DocumentStoreProvider, path string) ([]byte, string, error) {
+	if reader, ok := store.(ports.RevisionReader); ok {
+		return reader.GetDocumentRevision(ctx, path)
+	}
+	content, err := store.GetDocument(ctx, path)
+	return content, "", err
+}
+
+// storeAttached writes the files attached to an existing document, like the images merged into it
+func storeAttached(ctx context.Context, store ports.DocumentStoreProvider, docPath string, attached map[string][]byte) error {
+	if len(attached) == 0 {
+		return nil
+	}
+	if err := writeFiles(ctx, store, attached, fmt.Sprintf("Add files for %s", filepath.Base(docPath))); err != nil {
+		return fmt.Errorf("failed to store files of %s: %w", filepath.Base(docPath), err)
+	}
+	return nil
+}
+
+// updateGlossary records the terms of a message in the glossary of the store, if the glossary is enabled.
+// It returns the glossary to link the document to, and the files to store with the document. Failures are
+// logged, the document is stored without the glossary.
+func (s *DocumentationService) updateGlossary(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	msg *domain.Message,
+	docPath string,
+) (*domain.Glossary, map[string][]byte) {
+	attached := make(map[string][]byte)
+	if s.glossary == nil {
+		return nil, attached
+	}
+
+	glossary, content, err := s.glossary.Update(ctx, store, msg, docPath)
+	if err != nil {
+		logf(ctx, "Failed to update the glossary with message %s: %v", msg.ID(), err)
+		return nil, attached
+	}
+	if content != nil {
+		attached[domain.GlossaryFile] = content
+	}
+	return glossary, attached
+}
+
+// generationInput is the message text followed by what the vision model read from its images
+func generationInput(msg *domain.Message, images []*domain.ImageAsset) string {
+	described := domain.DescribeImages(images)
+	if described == "" {
+		return msg.Content().Text()
+	}
+	return msg.Content().Text() + "\n\n" + described
+}
+
+// groundingSources returns what the model was told about a message, the claims of its document are checked against it
+func groundingSources(msg *domain.Message, images []*domain.ImageAsset) []string {
+	sources := []string{generationInput(msg, images)}
+	for _, ref := range msg.References() {
+		sources = append(sources, ref.Value())
+	}
+	return sources
+}
+
+// withImageSection appends the images of a message to generated documentation
+func withImageSection(doc, docPath string, images []*domain.ImageAsset) string {
+	section := domain.RenderImageSection(docPath, images)
+	if section == "" {
+		return doc
+	}
+	return strings.TrimRight(doc, "\n") + "\n\n" + section
+}
+
+// frontMatterFor builds the front matter describing a message's document
+func (s *DocumentationService) frontMatterFor(msg *domain.Message, meeting *domain.Meeting, threadTitle string) *domain.FrontMatter {
+	fm := domain.NewFrontMatter()
+	fm.Set("type", msg.Type().String())
+	fm.Set("category", msg.Category().String())
+	fm.Set("created_at", time.Now().UTC().Format(time.RFC3339))
+	fm.Set("source_message", msg.ID().String())
+	// Documents are owned by the author of their message until someone reassigns them
+	if err := domain.AssignOwner(fm, msg.Sender()); err != nil {
+		log.Printf("Message %s has no owner for its document: %v", msg.ID(), err)
+	}
+	domain.RecordProvenance(fm, msg)
+	if threadID := msg.ThreadID().String(); threadID != "" {
+		fm.Set("thread", threadID)
+	}
+	if threadTitle != "" {
+		fm.Set("thread_title", threadTitle)
+	}
+	if meeting != nil {
+		fm.Set("originating_meeting", meeting.String())
+		if link := meeting.Link(); link != "" {
+			fm.Set("originating_meeting_link", link)
+		}
+	}
+	fm.SetList("tags", domain.TagStrings(msg.Tags()))
+	return fm
+}
+
+// threadTitle returns the title of the thread a message was posted in, empty when it is unknown
+func (s *DocumentationService) threadTitle(ctx context.Context, msg *domain.Message) string {
+	if s.threads == nil {
+		return ""
+	}
+	return s.threads.Title(ctx, msg.ThreadID())
+}
+
+// sign signs a generated document with the provenance signer, other documents are returned unchanged
+func (s *DocumentationService) sign(content string) (string, error) {
+	if s.provenance == nil {
+		return content, nil
+	}
+	fm, _, err := domain.ParseFrontMatter(content)
+	if err != nil || !domain.HasProvenance(fm) {
+		return content, nil
+	}
+	signed, err := s.provenance.Sign(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign document: %w", err)
+	}
+	return signed, nil
+}
+
+func hasAllTags(doc *domain.IndexedDocument, tags []domain.Tag) bool {
+	for _, tag := range tags {
+		if !doc.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsTag(tags []domain.Tag, tag domain.Tag) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// stripTitle removes the top-level heading so generated content can be embedded in another document
+func stripTitle(content string) string {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "# ") {
+		if idx := strings.Index(content, "\n"); idx >= 0 {
+			return strings.TrimSpace(content[idx+1:])
+		}
+		return ""
+	}
+	return content
+}
+
+// indexDocument adds a stored document to the document index with the owner and status of its front matter.
+// Documents generated from one message are indexed under its idempotency key, rollups gathering many messages
+// have none.
+func (s *DocumentationService) indexDocument(
+	ctx context.Context,
+	path string,
+	content string,
+	fm *domain.FrontMatter,
+	msg *domain.Message,
+	docConfig domain.DocumentationConfig,
+	idempotencyKey string,
+) error {
+	entry, err := domain.NewIndexedDocument(
+		path,
+		domain.TitleFromMarkdown(content),
+		domain.SummaryFromMarkdown(content),
+		msg.Type(),
+		msg.Category(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create index entry: %w", err)
+	}
+	entry.SetTags(msg.Tags())
+	entry.SetLocation(docConfig.Repository, docConfig.Branch)
+	entry.SetIdempotencyKey(idempotencyKey)
+	entry.SetFrontMatter(fm)
+	if project, err := s.messageProject(ctx, msg); err == nil && project != nil {
+		entry.SetProject(project.ID())
+	}
+
+	if embedder, ok := s.aiAgent.(ports.EmbeddingProvider); ok {
+		// Documents without embeddings can still be found by title
+		if embedding, err := embedder.Embed(ctx, entry.SearchText()); err == nil {
+			entry.SetEmbedding(embedding)
+		}
+	}
+
+	if err := s.index.Index(ctx, entry); err != nil {
+		return fmt.Errorf("failed to index documentation: %w", err)
+	}
+
+	return nil
+}
+
+// documentationConfig returns the documentation settings of the project bound to the message's channel.
+// Messages from channels without a project use the default settings.
+func (s *DocumentationService) documentationConfig(ctx context.Context, msg *domain.Message) (domain.DocumentationConfig, error) {
+	project, err := s.messageProject(ctx, msg)
+	if err != nil {
+		return domain.DocumentationConfig{}, err
+	}
+	if project == nil {
+		return domain.DefaultDocumentationConfig(), nil
+	}
+	return project.Documentation(), nil
+}
+
+// messageProject returns the project bound to the message's channel, nil when there is none
+func (s *DocumentationService) messageProject(ctx context.Context, msg *domain.Message) (*domain.Project, error) {
+	if msg.ChannelID() == "" {
+		return nil, nil
+	}
+
+	project, err := s.projects.FindByChannel(ctx, msg.ChannelID())
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project of channel %s: %w", msg.ChannelID(), err)
+	}
+	return project, nil
+}
+
+// documentTitle asks the AI agent for a short title of the message.
+// The heading of the generated document is used when the agent cannot name documents.
+func (s *DocumentationService) documentTitle(ctx context.Context, msg *domain.Message, doc string) string {
+	if generator, ok := s.aiAgent.(ports.TitleGenerator); ok {
+		// A document without a generated title is still stored, under its heading or timestamp
+		if title, err := generator.GenerateTitle(ctx, msg.Content().Text()); err == nil && strings.TrimSpace(title) != "" {
+			return title
+		}
+	}
+	return domain.TitleFromMarkdown(doc)
+}
+
+// uniquePath adds a numeric suffix to the path when a document with the same name is already stored
+func (s *DocumentationService) uniquePath(ctx context.Context, store ports.DocumentStoreProvider, path string) (string, error) {
+	existing, err := store.ListDocuments(ctx, filepath.Dir(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to list documentation: %w", err)
+	}
+
+	taken := make(map[string]bool, len(existing))
+	for _, doc := range existing {
+		taken[doc] = true
+	}
+	return domain.UniquePath(path, func(candidate string) bool {
+		return taken[candidate]
+	}), nil
+}
+
+// storeFor returns the store holding a document, documents missing from the index are looked up in the default store
+func (s *DocumentationService) storeFor(ctx context.Context, path string) (ports.DocumentStoreProvider, error) {
+	entry, err := s.index.FindByPath(ctx, path)
+	if errors.Is(err, ports.ErrNotFound) {
+		return s.stores.Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	return s.stores.Resolve(entry.Repository(), entry.Branch())
+}
+
+// checkWritable checks a document can be changed: archived projects keep their documentation as it was when they
+// were archived. Documents outside of projects, and those not indexed, can always be changed.
+func (s *DocumentationService) checkWritable(ctx context.Context, path string) error {
+	entry, err := s.index.FindByPath(ctx, path)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	if entry.Project().String() == "" {
+		return nil
+	}
+	project, err := s.projects.FindByID(ctx, entry.Project())
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find project of %s: %w", path, err)
+	}
+	if project.IsReadOnly() {
+		return fmt.Errorf("%s belongs to project %s: %w", path, project.Name(), domain.ErrProjectArchived)
+	}
+	return nil
 }
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"math"
+	"sort"
+)
+
+const (
+	// DefaultDuplicateThreshold is the minimum similarity for two documents to be considered duplicates
+	DefaultDuplicateThreshold = 0.8
+	// maxDuplicateCandidates limits how many similar documents are offered to the user
+	maxDuplicateCandidates = 3
+)
+
+// DuplicateDetector finds already documented content that is highly similar to a new message
+type DuplicateDetector struct {
+	index      ports.DocumentIndex
+	embeddings ports.EmbeddingProvider
+	threshold  float64
+}
+
+// NewDuplicateDetector creates a new DuplicateDetector.
+// Embedding similarity is used when the AI agent also implements ports.EmbeddingProvider.
+func NewDuplicateDetector(index ports.DocumentIndex, ai ports.AiAgentProvider) *DuplicateDetector {
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if ai == nil {
+		panic("AI agent cannot be nil")
+	}
+
+	embeddings, _ := ai.(ports.EmbeddingProvider)
+	return &DuplicateDetector{
+		index:      index,
+		embeddings: embeddings,
+		threshold:  DefaultDuplicateThreshold,
+	}
+}
+
+// FindDuplicates returns documents of the same type as the message that are highly similar to it
+func (d *DuplicateDetector) FindDuplicates(ctx context.Context, msg *domain.Message) ([]*domain.DocumentMatch, error) {
+	docs, err := d.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	text := msg.Content().Text()
+	var embedding []float64
+	if d.embeddings != nil {
+		// Fall back to text similarity when embeddings are unavailable
+		embedding, _ = d.embeddings.Embed(ctx, text)
+	}
+
+	var matches []*domain.DocumentMatch
+	for _, doc := range docs {
+		if doc.Type() != msg.Type() {
+			continue
+		}
+
+		score := domain.TextSimilarity(text, doc.SearchText())
+		if len(embedding) > 0 && doc.HasEmbedding() {
+			score = math.Max(score, domain.CosineSimilarity(embedding, doc.Embedding()))
+		}
+		if score < d.threshold {
+			continue
+		}
+
+		match, err := domain.NewDocumentMatch(doc, math.Min(score, 1))
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score() > matches[j].Score()
+	})
+	if len(matches) > maxDuplicateCandidates {
+		matches = matches[:maxDuplicateCandidates]
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// RegisterDeadLetterErasure erases the person from the dead letters of the messages they sent
+func RegisterDeadLetterErasure(erasure *ErasureService, queue ports.DeadLetterQueue) {
+	erasure.RegisterHook("dead letters", func(ctx context.Context, request *domain.ErasureRequest, _ []*domain.Message) (int, error) {
+		letters, err := queue.List(ctx)
+		if err != nil {
+			return 0, err
+		}
+		changed := 0
+		for _, letter := range letters {
+			if !request.ApplyToMessage(letter.Queued().Message()) {
+				continue
+			}
+			if request.Mode() == domain.ErasurePseudonymize {
+				err = queue.Add(ctx, letter)
+			} else {
+				err = queue.Remove(ctx, letter.ID())
+			}
+			if err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return changed, fmt.Errorf("dead letter %s: %w", letter.ID(), err)
+			}
+			changed++
+		}
+		return changed, nil
+	})
+}
+
+// RegisterThreadErasure erases the messages the person sent from their threads, threads left without messages
+// are deleted
+func RegisterThreadErasure(erasure *ErasureService, threads ports.ThreadRepository) {
+	erasure.RegisterHook("threads", func(ctx context.Context, request *domain.ErasureRequest, sent []*domain.Message) (int, error) {
+		changed := 0
+		for _, id := range sentThreads(sent) {
+			thread, err := threads.FindByID(ctx, id)
+			if errors.Is(err, ports.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return changed, err
+			}
+			if !thread.Erase(request) {
+				continue
+			}
+			if thread.MessageCount() == 0 {
+				err = threads.Delete(ctx, id)
+			} else {
+				err = threads.Save(ctx, thread)
+			}
+			if err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return changed, err
+			}
+			changed++
+		}
+		return changed, nil
+	})
+}
+
+// RegisterUserProfileErasure removes the cached profile of the person, it is fetched again if they keep posting
+func RegisterUserProfileErasure(erasure *ErasureService, profiles ports.UserProfileCache) {
+	erasure.RegisterHook("user profiles", func(ctx context.Context, request *domain.ErasureRequest, _ []*domain.Message) (int, error) {
+		err := profiles.Delete(ctx, request.Identity())
+		if errors.Is(err, ports.ErrNotFound) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		return 1, nil
+	})
+}
+
+// RegisterTriageErasure erases the messages the person sent from the triage queue
+func RegisterTriageErasure(erasure *ErasureService, queue ports.TriageQueue) {
+	erasure.RegisterHook("triage queue", func(ctx context.Context, request *domain.ErasureRequest, _ []*domain.Message) (int, error) {
+		items, err := queue.List(ctx)
+		if err != nil {
+			return 0, err
+		}
+		changed := 0
+		for _, item := range items {
+			if !request.ApplyToMessage(item.Message()) {
+				continue
+			}
+			if request.Mode() == domain.ErasurePseudonymize {
+				err = queue.Add(ctx, item)
+			} else {
+				_, err = queue.Take(ctx, item.Message().ID().String())
+			}
+			if err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return changed, err
+			}
+			changed++
+		}
+		return changed, nil
+	})
+}
+
+// RegisterIncidentErasure erases the person from the keys and the body of incident reports naming people
+func RegisterIncidentErasure(erasure *ErasureService) {
+	erasure.RegisterPersonKeys(domain.IncidentPersonKeys()...)
+	erasure.RegisterBodyHook(BodyNames(domain.IncidentBodyNames()...))
+}
+
+// RegisterMeetingNotesErasure erases the person from the keys and the body of meeting notes naming people
+func RegisterMeetingNotesErasure(erasure *ErasureService) {
+	erasure.RegisterPersonKeys(domain.MeetingNotesPersonKeys()...)
+	erasure.RegisterBodyHook(BodyNames(domain.MeetingNotesBodyNames()...))
+}
+
+// RegisterStandupErasure erases the person from the standups they were asked in and answered, and from the
+// answered_by key and the body of standup notes
+func RegisterStandupErasure(erasure *ErasureService, store ports.StandupStore) {
+	erasure.RegisterPersonKeys(domain.StandupPersonKeys()...)
+	erasure.RegisterBodyHook(BodyNames(domain.StandupBodyNames()...))
+	erasure.RegisterHook("standups", func(ctx context.Context, request *domain.ErasureRequest, _ []*domain.Message) (int, error) {
+		standups, err := store.List(ctx)
+		if err != nil {
+			return 0, err
+		}
+		changed := 0
+		for _, standup := range standups {
+			if !standup.Erase(request) {
+				continue
+			}
+			if err := store.Save(ctx, standup); err != nil {
+				return changed, err
+			}
+			changed++
+		}
+		return changed, nil
+	})
+}
+
+// RegisterApprovalRequestErasure erases the person from the decisions waiting for approval: the decisions they
+// sent are dropped and left undocumented, or pseudonymized, and their approvals are dropped or pseudonymized
+func RegisterApprovalRequestErasure(erasure *ErasureService, store ports.ApprovalRequestStore) {
+	erasure.RegisterHook("approval requests", func(ctx context.Context, request *domain.ErasureRequest, _ []*domain.Message) (int, error) {
+		requests, err := store.List(ctx)
+		if err != nil {
+			return 0, err
+		}
+		changed := 0
+		for _, waiting := range requests {
+			sent := waiting.Message().Sender() == request.Identity()
+			if !waiting.Erase(request) {
+				continue
+			}
+			threadID := waiting.Message().ThreadID().String()
+			if sent && request.Mode() == domain.ErasureErase {
+				err = store.Remove(ctx, threadID)
+			} else {
+				err = store.Put(ctx, waiting)
+			}
+			if err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return changed, fmt.Errorf("approval request of thread %s: %w", threadID, err)
+			}
+			changed++
+		}
+		return changed, nil
+	})
+}
+
+// RegisterPendingDuplicateErasure drops the choices waited for from the person, their ideas are left undocumented
+func RegisterPendingDuplicateErasure(erasure *ErasureService, store ports.PendingDuplicateStore) {
+	erasure.RegisterHook("pending duplicates", func(ctx context.Context, request *domain.ErasureRequest, sent []*domain.Message) (int, error) {
+		changed := 0
+		for _, id := range sentThreads(sent) {
+			pending, err := store.Find(ctx, id.String(), time.Now())
+			if errors.Is(err, ports.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return changed, err
+			}
+			if !pending.CanChoose(request.Identity()) {
+				continue
+			}
+			if err := store.Remove(ctx, id.String()); err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return changed, err
+			}
+			changed++
+		}
+		return changed, nil
+	})
+}
+
+// RegisterPendingUpdateErasure drops the merges waiting for the person to apply their diff, their ideas are left
+// undocumented
+func RegisterPendingUpdateErasure(erasure *ErasureService, store ports.PendingUpdateStore) {
+	erasure.RegisterHook("pending updates", func(ctx context.Context, request *domain.ErasureRequest, sent []*domain.Message) (int, error) {
+		changed := 0
+		for _, id := range sentThreads(sent) {
+			pending, err := store.Find(ctx, id.String(), time.Now())
+			if errors.Is(err, ports.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return changed, err
+			}
+			if !pending.CanDecide(request.Identity()) {
+				continue
+			}
+			if err := store.Remove(ctx, id.String()); err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return changed, err
+			}
+			changed++
+		}
+		return changed, nil
+	})
+}
+
+// sentThreads returns the threads of the messages, each once
+func sentThreads(sent []*domain.Message) []common.ID {
+	seen := make(map[string]bool, len(sent))
+	var threads []common.ID
+	for _, msg := range sent {
+		if seen[msg.ThreadID().String()] {
+			continue
+		}
+		seen[msg.ThreadID().String()] = true
+		threads = append(threads, msg.ThreadID())
+	}
+	return threads
+}
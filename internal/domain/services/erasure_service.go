@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"sync"
+	"time"
+)
+
+// ErasureHook erases or pseudonymizes what a store keeps about the person of a request, given the messages the
+// person sent, and returns how many records it changed
+type ErasureHook func(ctx context.Context, request *domain.ErasureRequest, sent []*domain.Message) (int, error)
+
+// BodyErasureHook removes the person of a request from the body of a document, where a feature writes names into
+// the documents it renders, and reports if it changed the body
+type BodyErasureHook func(request *domain.ErasureRequest, body string) (string, bool)
+
+// registeredHook is an erasure hook with the store it erases from
+type registeredHook struct {
+	store string
+	erase ErasureHook
+}
+
+// ErasureService erases or pseudonymizes the data stored about a person: the messages they sent, the
+// corrections of those messages and the ones they made, the audit entries they were the actor of, and the
+// fields of the documents' front matter naming them. Other stores keeping people's data register a hook,
+// features naming people in front matter keys of their own register the keys, and features writing names into
+// the bodies of documents register a body hook. The erasure itself is audited under the person's pseudonym.
+type ErasureService struct {
+	messages    ports.MessageRepository
+	corrections ports.CorrectionStore
+	audit       ports.AuditLog
+	docs        *DocumentationService
+	index       ports.DocumentIndex
+	mu          sync.RWMutex
+	personKeys  []string
+	hooks       []registeredHook
+	bodyHooks   []BodyErasureHook
+}
+
+// NewErasureService creates a new ErasureService. The names in the status rollups of the documentation service
+// are erased without registering them.
+func NewErasureService(
+	messages ports.MessageRepository,
+	corrections ports.CorrectionStore,
+	audit ports.AuditLog,
+	docs *DocumentationService,
+	index ports.DocumentIndex,
+) *ErasureService {
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if corrections == nil {
+		panic("correction store cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	s := &ErasureService{
+		messages:    messages,
+		corrections: corrections,
+		audit:       audit,
+		docs:        docs,
+		index:       index,
+	}
+	s.RegisterBodyHook(BodyNames(domain.StatusRollupBodyNames()...))
+	return s
+}
+
+// RegisterPersonKeys adds front matter keys naming people, which erasures remove the person from
+func (s *ErasureService) RegisterPersonKeys(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.personKeys = append(s.personKeys, keys...)
+}
+
+// RegisterBodyHook adds a feature writing names into the bodies of documents, which erasures run the hook on
+// for every indexed document
+func (s *ErasureService) RegisterBodyHook(hook BodyErasureHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bodyHooks = append(s.bodyHooks, hook)
+}
+
+// BodyNames returns a body hook replacing the names where the formats of domain.ErasureRequest.ApplyToBody put them
+func BodyNames(formats ...string) BodyErasureHook {
+	return func(request *domain.ErasureRequest, body string) (string, bool) {
+		return request.ApplyToBody(body, formats...)
+	}
+}
+
+// RegisterHook adds a store keeping people's data, which erasures run the hook on. The hooks run in the order
+// they were registered, before the messages are erased.
+func (s *ErasureService) RegisterHook(store string, hook ErasureHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks = append(s.hooks, registeredHook{store: store, erase: hook})
+}
+
+// Erase removes the person named by the request from every store, and reports what changed.
+// It stops at the first store that fails, running it again finishes the erasure.
+func (s *ErasureService) Erase(ctx context.Context, request *domain.ErasureRequest) (*domain.DeletionReport, error) {
+	if request == nil {
+		return nil, fmt.Errorf("erasure request cannot be nil")
+	}
+	report := &domain.DeletionReport{
+		Identity:  request.Identity(),
+		Mode:      request.Mode(),
+		Pseudonym: request.Pseudonym(),
+	}
+
+	sent, err := s.messages.FindBySender(ctx, request.Identity())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the messages of %s: %w", request.Pseudonym(), err)
+	}
+	if err := s.runHooks(ctx, request, sent, report); err != nil {
+		return nil, err
+	}
+	if err := s.eraseMessages(ctx, request, sent, report); err != nil {
+		return nil, err
+	}
+	corrected, err := s.corrections.ReplaceActor(ctx, request.Identity(), request.Replacement())
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase corrections: %w", err)
+	}
+	report.Corrections += corrected
+	if report.AuditEntries, err = s.audit.ReplaceActor(ctx, request.Identity(), request.Replacement()); err != nil {
+		return nil, fmt.Errorf("failed to erase audit entries: %w", err)
+	}
+	if err := s.eraseDocuments(ctx, request, report); err != nil {
+		return nil, err
+	}
+
+	entry, err := domain.NewAuditEntry(domain.AuditActionErase, request.RequestedBy(), request.Pseudonym(), "", request.Mode().String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to record erasure: %w", err)
+	}
+	report.CompletedAt = time.Now().UTC()
+	logf(ctx, "Completed %s of %s: %d messages, %d corrections, %d audit entries, %d documents, records %v",
+		request.Mode(), request.Pseudonym(), report.Messages, report.Corrections, report.AuditEntries, len(report.Documents), report.Records)
+	return report, nil
+}
+
+// runHooks erases the person from the stores that registered a hook
+func (s *ErasureService) runHooks(ctx context.Context, request *domain.ErasureRequest, sent []*domain.Message, report *domain.DeletionReport) error {
+	s.mu.RLock()
+	hooks := append([]registeredHook(nil), s.hooks...)
+	s.mu.RUnlock()
+
+	report.Records = make(map[string]int, len(hooks))
+	for _, hook := range hooks {
+		changed, err := hook.erase(ctx, request, sent)
+		if err != nil {
+			return fmt.Errorf("failed to erase %s: %w", hook.store, err)
+		}
+		report.Records[hook.store] += changed
+	}
+	return nil
+}
+
+// eraseMessages deletes the messages the person sent along with their corrections, or pseudonymizes their sender
+func (s *ErasureService) eraseMessages(ctx context.Context, request *domain.ErasureRequest, sent []*domain.Message, report *domain.DeletionReport) error {
+	var erased []string
+	for _, msg := range sent {
+		if request.Mode() == domain.ErasurePseudonymize {
+			msg.Pseudonymize(request.Pseudonym())
+			if err := s.messages.Update(ctx, msg); err != nil {
+				return fmt.Errorf("failed to pseudonymize message %s: %w", msg.ID(), err)
+			}
+		} else if err := s.messages.Delete(ctx, msg.ID().String()); err != nil {
+			return fmt.Errorf("failed to delete message %s: %w", msg.ID(), err)
+		}
+		erased = append(erased, msg.ID().String())
+		report.Messages++
+	}
+
+	// Corrections keep the text of the corrected message, so they go with the messages
+	if request.Mode() == domain.ErasureErase && len(erased) > 0 {
+		removed, err := s.corrections.DeleteByMessage(ctx, erased...)
+		if err != nil {
+			return fmt.Errorf("failed to delete corrections: %w", err)
+		}
+		report.Corrections += removed
+	}
+	return nil
+}
+
+// eraseDocuments removes the person from the front matter and the body of every indexed document naming them.
+// Documents of the index that are missing from their store are skipped.
+func (s *ErasureService) eraseDocuments(ctx context.Context, request *domain.ErasureRequest, report *domain.DeletionReport) error {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+	s.mu.RLock()
+	keys := append([]string(nil), s.personKeys...)
+	bodyHooks := append([]BodyErasureHook(nil), s.bodyHooks...)
+	s.mu.RUnlock()
+
+	for _, doc := range docs {
+		content, err := s.docs.GetDocumentation(ctx, doc.Path())
+		if errors.Is(err, ports.ErrNotFound) {
+			logf(ctx, "Skipping %s in the %s of %s, it is indexed but missing from its store", doc.Path(), request.Mode(), request.Pseudonym())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fm, body, err := domain.ParseFrontMatter(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse front matter of %s: %w", doc.Path(), err)
+		}
+		changed := request.ApplyTo(fm, keys...)
+		for _, hook := range bodyHooks {
+			var scrubbed bool
+			if body, scrubbed = hook(request, body); scrubbed {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		err = s.docs.UpdateDocumentation(ctx, doc.Path(), fm.Apply(body), nil)
+		if errors.Is(err, domain.ErrProjectArchived) {
+			logf(ctx, "Leaving %s in the %s of %s as it is: %v", doc.Path(), request.Mode(), request.Pseudonym(), err)
+			report.ReadOnlyDocuments = append(report.ReadOnlyDocuments, doc.Path())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		report.Documents = append(report.Documents, doc.Path())
+	}
+	return nil
+}
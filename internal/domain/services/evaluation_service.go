@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+)
+
+// ErrEmptyDataset indicates that there are no labeled messages to evaluate against
+var ErrEmptyDataset = errors.New("dataset has no labeled messages")
+
+// EvaluatedProvider is an AI agent configuration taking part in an evaluation
+type EvaluatedProvider struct {
+	Name     string
+	Provider ports.AiAgentProvider
+}
+
+// EvaluationService runs labeled messages through AI agent configurations so their analyses
+// can be compared before switching models or prompts
+type EvaluationService struct {
+	dataset []domain.LabeledMessage
+}
+
+func NewEvaluationService(dataset []domain.LabeledMessage) (*EvaluationService, error) {
+	if len(dataset) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	for i, message := range dataset {
+		if err := message.Validate(); err != nil {
+			return nil, fmt.Errorf("labeled message %d: %w", i+1, err)
+		}
+	}
+	return &EvaluationService{dataset: append([]domain.LabeledMessage(nil), dataset...)}, nil
+}
+
+// Evaluate analyzes every labeled message with each provider in turn. A failed analysis is counted
+// against the provider instead of stopping the run, only a canceled context stops it.
+func (s *EvaluationService) Evaluate(ctx context.Context, providers ...EvaluatedProvider) ([]*domain.Evaluation, error) {
+	evaluations := make([]*domain.Evaluation, 0, len(providers))
+	for _, p := range providers {
+		if p.Provider == nil {
+			return nil, fmt.Errorf("provider %q cannot be nil", p.Name)
+		}
+
+		evaluation := domain.NewEvaluation(p.Name)
+		for _, message := range s.dataset {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			result, err := p.Provider.AnalyzeMessage(ctx, message.Content)
+			if err != nil {
+				log.Printf("Evaluation of %s failed to analyze %q: %v", p.Name, message.Content, err)
+				evaluation.RecordFailure()
+				continue
+			}
+			evaluation.Record(message, result)
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+	return evaluations, nil
+}
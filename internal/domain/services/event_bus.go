@@ -0,0 +1,57 @@
+package services
+
+import (
+	"github.com/massimo-ua/quill/internal/domain"
+	"sync"
+)
+
+// DefaultEventBuffer is how many events a subscriber may fall behind before new events are dropped for it
+const DefaultEventBuffer = 64
+
+// EventBus fans the processing events of the bot out to the subscribers following them live.
+// Publishing never blocks: a subscriber that does not keep up misses events rather than slowing the
+// pipeline down.
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers map[chan domain.ProcessingEvent]struct{}
+}
+
+// NewEventBus creates an EventBus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[chan domain.ProcessingEvent]struct{})}
+}
+
+// Publish sends an event to every subscriber with room for it
+func (b *EventBus) Publish(event domain.ProcessingEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe starts receiving the events published from now on, buffering up to buffer of them
+// (0 uses DefaultEventBuffer). The returned function ends the subscription and closes the channel.
+func (b *EventBus) Subscribe(buffer int) (<-chan domain.ProcessingEvent, func()) {
+	if buffer <= 0 {
+		buffer = DefaultEventBuffer
+	}
+	events := make(chan domain.ProcessingEvent, buffer)
+
+	b.mu.Lock()
+	b.subscribers[events] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, events)
+			b.mu.Unlock()
+			close(events)
+		})
+	}
+}
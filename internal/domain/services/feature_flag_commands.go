@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// RegisterFeatureFlagCommands registers the "features" command listing the experimental features on for the
+// project of the channel
+func RegisterFeatureFlagCommands(commands *CommandService, flags *FeatureFlagService, docs *DocumentationService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if flags == nil {
+		panic("feature flag service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+
+	commands.Register("features", "features", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		project, err := docs.messageProject(ctx, msg)
+		if err != nil {
+			return "", err
+		}
+		return formatFeatures(flags.Flags(ctx), project), nil
+	})
+}
+
+func formatFeatures(flags domain.FeatureFlags, project *domain.Project) string {
+	var b strings.Builder
+	if project != nil {
+		b.WriteString(fmt.Sprintf("🧪 Experimental features for *%s*:\n", project.Name()))
+	} else {
+		b.WriteString("🧪 Experimental features outside projects:\n")
+	}
+
+	for _, feature := range domain.Features() {
+		mark := "❌"
+		if flags.Enabled(feature, project) {
+			mark = "✅"
+		}
+		b.WriteString(fmt.Sprintf("%s %s", mark, feature))
+		if flag, ok := flags[feature]; ok && !flag.Enabled && flag.Rollout > 0 {
+			b.WriteString(fmt.Sprintf(" (rolling out to %d%% of projects)", flag.Rollout))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
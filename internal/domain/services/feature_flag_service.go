@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultFlagRefresh is how long flags fetched from a remote source are used before they are fetched again
+const DefaultFlagRefresh = time.Minute
+
+// FeatureFlagService decides which projects experimental features are on for, from the flags of the
+// deployment's configuration and, when there is one, a remote source overriding them
+type FeatureFlagService struct {
+	configured domain.FeatureFlags
+	source     ports.FeatureFlagSource
+	refresh    time.Duration
+
+	mu        sync.Mutex
+	remote    domain.FeatureFlags
+	fetchedAt time.Time
+}
+
+// NewFeatureFlagService creates a FeatureFlagService.
+// The remote source is optional, its flags are fetched again once they are older than refresh
+// (0 uses DefaultFlagRefresh). When fetching fails, the last flags fetched stay in use.
+func NewFeatureFlagService(configured domain.FeatureFlags, source ports.FeatureFlagSource, refresh time.Duration) *FeatureFlagService {
+	if refresh <= 0 {
+		refresh = DefaultFlagRefresh
+	}
+	return &FeatureFlagService{
+		configured: configured,
+		source:     source,
+		refresh:    refresh,
+	}
+}
+
+// Enabled checks if a feature is on for a project, nil for messages outside projects
+func (s *FeatureFlagService) Enabled(ctx context.Context, feature domain.Feature, project *domain.Project) bool {
+	return s.Flags(ctx).Enabled(feature, project)
+}
+
+// Flags returns the flags in effect, the configured ones overridden by those of the remote source
+func (s *FeatureFlagService) Flags(ctx context.Context) domain.FeatureFlags {
+	if s.source == nil {
+		return s.configured
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.fetchedAt) >= s.refresh {
+		s.fetch(ctx)
+	}
+	return s.configured.Merge(s.remote)
+}
+
+// fetch replaces the remote flags, keeping the previous ones when the source fails or returns invalid flags.
+// Failures are retried after the refresh period too, so an unavailable source does not slow every message down.
+func (s *FeatureFlagService) fetch(ctx context.Context) {
+	s.fetchedAt = time.Now()
+
+	flags, err := s.source.FeatureFlags(ctx)
+	if err == nil {
+		err = flags.Validate()
+	}
+	if err != nil {
+		log.Printf("Failed to fetch feature flags, keeping the previous ones: %v", err)
+		return
+	}
+	s.remote = flags
+}
+
+// featureEnabled checks a feature against optional flags, features are on without them
+func featureEnabled(ctx context.Context, flags *FeatureFlagService, feature domain.Feature, project *domain.Project) bool {
+	return flags == nil || flags.Enabled(ctx, feature, project)
+}
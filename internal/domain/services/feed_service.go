@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// FeedService builds the feeds of recently created and updated documents per project, for people following a
+// project without Slack or GitHub access
+type FeedService struct {
+	index    ports.DocumentIndex
+	projects ports.ProjectRepository
+}
+
+// NewFeedService creates a new FeedService
+func NewFeedService(index ports.DocumentIndex, projects ports.ProjectRepository) *FeedService {
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	return &FeedService{
+		index:    index,
+		projects: projects,
+	}
+}
+
+// Feed returns the size most recently updated documents of a project, ports.ErrNotFound when there is no such
+// project. A size of zero or less returns domain.DefaultFeedSize documents.
+func (s *FeedService) Feed(ctx context.Context, projectID string, size int) (*domain.DocumentFeed, error) {
+	id, err := common.NewID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project %q: %w", projectID, ports.ErrNotFound)
+	}
+	project, err := s.projects.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project %s: %w", id, err)
+	}
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+	return domain.NewDocumentFeed(project, docs, size), nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// RegisterFeedbackCommands registers the "correct" command, used in the thread of a captured message
+// to fix its analysis. Corrections guide the analysis of similar messages.
+func RegisterFeedbackCommands(commands *CommandService, feedback *FeedbackService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if feedback == nil {
+		panic("feedback service cannot be nil")
+	}
+
+	commands.Register("correct", "correct type <idea|decision|status|information> | correct discard", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return correctCapture(ctx, feedback, msg, cmd)
+	})
+}
+
+func correctCapture(ctx context.Context, feedback *FeedbackService, msg *domain.Message, cmd *domain.Command) (string, error) {
+	captured, err := feedback.CapturedMessage(ctx, msg.ThreadID().String())
+	if err != nil {
+		return "", err
+	}
+
+	switch strings.ToLower(cmd.Arg(0)) {
+	case "type":
+		messageType, err := domain.NewMessageType(strings.ToLower(cmd.Arg(1)))
+		if err != nil || messageType.IsUnknown() {
+			return "", fmt.Errorf("unknown message type %q, use idea, decision, status or information", cmd.Arg(1))
+		}
+		if err := feedback.CorrectType(ctx, captured, messageType, msg.Sender()); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("🧠 Thanks, noted that this is a %s. Similar messages will be analyzed that way.", messageType), nil
+	case "discard":
+		removed, err := feedback.Discard(ctx, captured, msg.Sender())
+		if err != nil {
+			return "", err
+		}
+		if len(removed) == 0 {
+			return "🧠 Thanks, similar messages will not be documented. The document holds other messages too, so it was kept.", nil
+		}
+		return fmt.Sprintf("🗑️ Removed %s. Similar messages will not be documented.", strings.Join(removed, ", ")), nil
+	default:
+		return "", fmt.Errorf("usage: %s correct type <type> | correct discard", domain.CommandPrefix)
+	}
+}
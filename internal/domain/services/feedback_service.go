@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// DefaultCorrectionExamples is how many corrections are shown to the AI agent when it analyzes a message
+const DefaultCorrectionExamples = 5
+
+// ErrNothingCaptured indicates that a thread has no documented message to correct
+var ErrNothingCaptured = errors.New("nothing was captured in this thread")
+
+// FeedbackService collects the corrections people make to message analyses into a labeled dataset
+// and selects the ones worth showing to the AI agent when it analyzes new messages
+type FeedbackService struct {
+	corrections ports.CorrectionStore
+	messages    ports.MessageRepository
+	docs        *DocumentationService
+	examples    int
+}
+
+func NewFeedbackService(corrections ports.CorrectionStore, messages ports.MessageRepository, docs *DocumentationService) *FeedbackService {
+	if corrections == nil {
+		panic("correction store cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	return &FeedbackService{
+		corrections: corrections,
+		messages:    messages,
+		docs:        docs,
+		examples:    DefaultCorrectionExamples,
+	}
+}
+
+// CapturedMessage returns the message documented last in a thread
+func (s *FeedbackService) CapturedMessage(ctx context.Context, threadID string) (*domain.Message, error) {
+	if threadID == "" {
+		return nil, ErrNothingCaptured
+	}
+
+	messages, err := s.messages.FindByThread(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find thread messages: %w", err)
+	}
+
+	var captured *domain.Message
+	for _, msg := range messages {
+		if msg.State() != domain.MessageStateDocumented {
+			continue
+		}
+		if captured == nil || msg.Timestamp().After(captured.Timestamp()) {
+			captured = msg
+		}
+	}
+	if captured == nil {
+		return nil, ErrNothingCaptured
+	}
+	return captured, nil
+}
+
+// CorrectType records the type a message should have been analyzed as
+func (s *FeedbackService) CorrectType(ctx context.Context, msg *domain.Message, messageType domain.MessageType, actor string) error {
+	from := msg.Type()
+	if from == messageType {
+		return nil
+	}
+
+	if err := s.record(ctx, msg, domain.CorrectionFieldType, from.String(), messageType.String(), actor); err != nil {
+		return err
+	}
+
+	msg.UpdateType(messageType)
+	if err := s.messages.Update(ctx, msg); err != nil {
+		return fmt.Errorf("failed to update message type: %w", err)
+	}
+	return nil
+}
+
+// Discard removes the documents captured from a message and records that it was not worth documenting
+func (s *FeedbackService) Discard(ctx context.Context, msg *domain.Message, actor string) ([]string, error) {
+	removed, err := s.docs.DiscardDocumentation(ctx, msg)
+	if err != nil {
+		return removed, err
+	}
+
+	if err := s.record(ctx, msg, domain.CorrectionFieldDiscard, msg.Type().String(), domain.MessageTypeUnknown.String(), actor); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
+
+// Examples returns the corrections most relevant to analyzing the content
+func (s *FeedbackService) Examples(ctx context.Context, content string) ([]*domain.Correction, error) {
+	corrections, err := s.corrections.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corrections: %w", err)
+	}
+	return domain.SelectCorrectionExamples(corrections, content, s.examples), nil
+}
+
+func (s *FeedbackService) record(ctx context.Context, msg *domain.Message, field domain.CorrectionField, from, to, actor string) error {
+	correction, err := domain.NewCorrection(msg, field, from, to, actor)
+	if err != nil {
+		return fmt.Errorf("failed to create correction: %w", err)
+	}
+	if err := s.corrections.Record(ctx, correction); err != nil {
+		return fmt.Errorf("failed to record correction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// maxNewTerms caps the terms defined for a single message, so a message full of acronyms
+// does not turn into a burst of model requests
+const maxNewTerms = 5
+
+// GlossaryService maintains the GLOSSARY.md of documentation repositories from the terms used in captured
+// messages, defining new terms with the AI agent
+type GlossaryService struct {
+	definer ports.TermDefiner
+}
+
+// NewGlossaryService creates a GlossaryService
+func NewGlossaryService(definer ports.TermDefiner) *GlossaryService {
+	if definer == nil {
+		panic("term definer cannot be nil")
+	}
+	return &GlossaryService{
+		definer: definer,
+	}
+}
+
+// Update adds the terms a message uses to the glossary of the store and records the message's document
+// under each of them. New terms are defined from the message, a term that cannot be defined is left for
+// a later message. It returns the glossary and its rendered content, which is nil when nothing changed.
+func (s *GlossaryService) Update(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	msg *domain.Message,
+	docPath string,
+) (*domain.Glossary, []byte, error) {
+	glossary, err := s.load(ctx, store)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text := msg.Content().Text()
+	changed := false
+	defined := 0
+	for _, detected := range domain.DetectTerms(text) {
+		if _, ok := glossary.Entry(detected.Term); ok || defined == maxNewTerms {
+			continue
+		}
+		defined++
+
+		entry, err := s.define(ctx, detected, text)
+		if err != nil {
+			logf(ctx, "Failed to define %s used in message %s: %v", detected.Term, msg.ID(), err)
+			continue
+		}
+		if entry != nil {
+			glossary.Add(entry)
+			changed = true
+		}
+	}
+
+	for _, entry := range glossary.Mentions(text) {
+		if entry.AddDocument(docPath) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return glossary, nil, nil
+	}
+	return glossary, []byte(glossary.Render()), nil
+}
+
+// load reads the glossary of a store, a store without one has an empty glossary
+func (s *GlossaryService) load(ctx context.Context, store ports.DocumentStoreProvider) (*domain.Glossary, error) {
+	exists, err := documentExists(ctx, store, domain.GlossaryFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find glossary: %w", err)
+	}
+	if !exists {
+		return domain.NewGlossary(), nil
+	}
+
+	content, err := store.GetDocument(ctx, domain.GlossaryFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glossary: %w", err)
+	}
+	return domain.ParseGlossary(string(content)), nil
+}
+
+// define asks the AI agent for the definition of a term, nil when the message does not make it clear
+func (s *GlossaryService) define(ctx context.Context, detected domain.DetectedTerm, usage string) (*domain.GlossaryEntry, error) {
+	if detected.Expansion != "" {
+		usage = fmt.Sprintf("%s\n\n%s stands for %s.", usage, detected.Term, detected.Expansion)
+	}
+
+	definition, err := s.definer.DefineTerm(ctx, detected.Term, usage)
+	if err != nil {
+		return nil, err
+	}
+	if definition == "" {
+		return nil, nil
+	}
+	return domain.NewGlossaryEntry(detected.Term, definition)
+}
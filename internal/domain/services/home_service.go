@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"sort"
+)
+
+// maxHomeCaptures bounds the captures listed on a home page
+const maxHomeCaptures = 5
+
+// HomeService puts together the home page people see in chat: the documents generated from their latest
+// messages, the documents they own that wait for review, what waits for approval in their channels, and the
+// projects their channels are bound to
+type HomeService struct {
+	messages ports.MessageRepository
+	projects ports.ProjectRepository
+	index    ports.DocumentIndex
+	graph    *ReferenceGraphService
+	docs     *DocumentationService
+	bot      *BotService
+}
+
+// NewHomeService creates a HomeService
+func NewHomeService(
+	messages ports.MessageRepository,
+	projects ports.ProjectRepository,
+	index ports.DocumentIndex,
+	graph *ReferenceGraphService,
+	docs *DocumentationService,
+	bot *BotService,
+) *HomeService {
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if graph == nil {
+		panic("reference graph service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if bot == nil {
+		panic("bot service cannot be nil")
+	}
+	return &HomeService{
+		messages: messages,
+		projects: projects,
+		index:    index,
+		graph:    graph,
+		docs:     docs,
+		bot:      bot,
+	}
+}
+
+// RegisterHome shows people their home page in chat, where held messages can be approved or rejected.
+// It does nothing when the chat provider does not implement ports.HomePage.
+func RegisterHome(chat ports.ChatAccessProvider, service *HomeService) {
+	if service == nil {
+		panic("home service cannot be nil")
+	}
+	if page, ok := chat.(ports.HomePage); ok {
+		page.OnHomeOpened(service.Home)
+		page.OnModerationDecision(service.Decide)
+	}
+}
+
+// Home returns the home page of the viewer
+func (s *HomeService) Home(ctx context.Context, viewer domain.HomeViewer) (*domain.Home, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	captures, err := s.captures(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviews(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	approvals, err := s.bot.PendingApprovals(ctx, viewer.Channels)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projectsOf(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Home{Captures: captures, Reviews: reviews, Approvals: approvals, Projects: projects}, nil
+}
+
+// Decide approves or rejects a held message on behalf of the actor
+func (s *HomeService) Decide(ctx context.Context, messageID, actor string, approve bool) error {
+	if approve {
+		return s.bot.ApproveHeld(ctx, messageID, actor)
+	}
+	return s.bot.RejectHeld(ctx, messageID, actor)
+}
+
+// captures returns the documents generated from the viewer's latest documented messages, newest first
+func (s *HomeService) captures(ctx context.Context, viewer domain.HomeViewer) ([]domain.HomeCapture, error) {
+	documented, err := s.messages.FindByState(ctx, domain.MessageStateDocumented)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documented messages: %w", err)
+	}
+
+	var mine []*domain.Message
+	for _, msg := range documented {
+		if msg.Sender() == viewer.Name {
+			mine = append(mine, msg)
+		}
+	}
+	sort.SliceStable(mine, func(i, j int) bool {
+		return mine[i].Timestamp().After(mine[j].Timestamp())
+	})
+
+	var captures []domain.HomeCapture
+	for _, msg := range mine {
+		source, err := domain.NewMessageReference(msg.ID().String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create message reference: %w", err)
+		}
+		for _, ref := range s.graph.ReferencedBy(*source) {
+			if !ref.Type().IsDocument() {
+				continue
+			}
+			doc, err := s.index.FindByPath(ctx, ref.Value())
+			if errors.Is(err, ports.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up indexed document: %w", err)
+			}
+			captures = append(captures, domain.HomeCapture{
+				Title:      doc.Title(),
+				Link:       s.docs.DocumentLink(ctx, doc.Path()),
+				Type:       doc.Type(),
+				Category:   doc.Category(),
+				CapturedAt: msg.Timestamp(),
+			})
+			if len(captures) == maxHomeCaptures {
+				return captures, nil
+			}
+		}
+	}
+	return captures, nil
+}
+
+// reviews returns the documents the viewer owns that wait for review, by title
+func (s *HomeService) reviews(ctx context.Context, viewer domain.HomeViewer) ([]domain.HomeReview, error) {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	var reviews []domain.HomeReview
+	for _, doc := range docs {
+		if doc.Owner() != viewer.Name || doc.Status() != domain.DocumentStatusNeedsReview {
+			continue
+		}
+		reviews = append(reviews, domain.HomeReview{
+			Title:    doc.Title(),
+			Link:     s.docs.DocumentLink(ctx, doc.Path()),
+			Category: doc.Category(),
+		})
+	}
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].Title < reviews[j].Title
+	})
+	return reviews, nil
+}
+
+// projectsOf returns the projects bound to the viewer's channels
+func (s *HomeService) projectsOf(ctx context.Context, viewer domain.HomeViewer) ([]domain.HomeProject, error) {
+	projects, err := s.projects.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var bound []domain.HomeProject
+	for _, project := range projects {
+		var channels []string
+		for _, channel := range project.Channels() {
+			if viewer.InChannel(channel) {
+				channels = append(channels, channel)
+			}
+		}
+		if len(channels) == 0 {
+			continue
+		}
+		bound = append(bound, domain.HomeProject{
+			Name:       project.Name(),
+			Status:     project.Status(),
+			Channels:   channels,
+			Repository: project.Documentation().Repository,
+		})
+	}
+	sort.SliceStable(bound, func(i, j int) bool {
+		return bound[i].Name < bound[j].Name
+	})
+	return bound, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// ImageAnalysis reads the images shared with messages using a vision model, so screenshots and whiteboard
+// photos are documented with the message instead of being lost
+type ImageAnalysis struct {
+	describer ports.ImageDescriber
+	fetcher   ports.AttachmentFetcher
+	limits    domain.AssetLimits
+}
+
+// NewImageAnalysis creates an ImageAnalysis fetching images through the chat provider they were shared on.
+// Images are stored within the limits, zero limits use the defaults.
+func NewImageAnalysis(describer ports.ImageDescriber, fetcher ports.AttachmentFetcher, limits domain.AssetLimits) *ImageAnalysis {
+	if describer == nil {
+		panic("image describer cannot be nil")
+	}
+	if fetcher == nil {
+		panic("attachment fetcher cannot be nil")
+	}
+	return &ImageAnalysis{
+		describer: describer,
+		fetcher:   fetcher,
+		limits:    limits,
+	}
+}
+
+// Analyze fetches the images of a message and describes them. An image that cannot be fetched or downscaled
+// to the limits is left out, one the vision model fails on is kept without a description; all are logged.
+func (a *ImageAnalysis) Analyze(ctx context.Context, msg *domain.Message) []*domain.ImageAsset {
+	var assets []*domain.ImageAsset
+	for _, image := range msg.Images() {
+		asset, err := a.analyzeImage(ctx, msg, image)
+		if err != nil {
+			logf(ctx, "Failed to analyze image %s of message %s: %v", image.Name(), msg.ID(), err)
+		}
+		if asset != nil {
+			assets = append(assets, asset)
+		}
+	}
+	return assets
+}
+
+func (a *ImageAnalysis) analyzeImage(ctx context.Context, msg *domain.Message, image *domain.Attachment) (*domain.ImageAsset, error) {
+	data, err := a.fetcher.FetchAttachment(ctx, image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+
+	// The stored image is downscaled, the vision model reads the original
+	fitted, err := domain.FitImage(data, a.limits)
+	if err != nil {
+		return nil, err
+	}
+	description, describeErr := a.describer.DescribeImage(ctx, image.MimeType(), data)
+	asset, err := domain.NewImageAsset(image, fitted, description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image asset: %w", err)
+	}
+	if describeErr != nil {
+		return asset, fmt.Errorf("failed to describe image: %w", describeErr)
+	}
+	return asset, nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// RegisterIncidentCommands registers the "incident" command, sent in a thread to declare an incident in it or
+// to resolve it
+func RegisterIncidentCommands(commands *CommandService, incidents *IncidentService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if incidents == nil {
+		panic("incident service cannot be nil")
+	}
+
+	commands.Register("incident", "incident start [<title>]|resolve", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		switch strings.ToLower(cmd.Arg(0)) {
+		case "start":
+			return startIncident(ctx, incidents, msg, cmd)
+		case "resolve":
+			return resolveIncident(ctx, incidents, msg)
+		default:
+			return "", fmt.Errorf("usage: `%s incident start [<title>]` or `%s incident resolve`", domain.CommandPrefix, domain.CommandPrefix)
+		}
+	})
+}
+
+func startIncident(ctx context.Context, incidents *IncidentService, msg *domain.Message, cmd *domain.Command) (string, error) {
+	incident, err := incidents.Start(ctx, msg, strings.Join(cmd.Args()[1:], " "))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🚨 Incident *%s* declared. Every message of this thread is captured for its timeline until `%s incident resolve`.",
+		incident.Title(), domain.CommandPrefix), nil
+}
+
+func resolveIncident(ctx context.Context, incidents *IncidentService, msg *domain.Message) (string, error) {
+	incident, path, err := incidents.Resolve(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("✅ Incident *%s* resolved after %s. The timeline and a postmortem draft are in %s.",
+		incident.Title(), incident.Duration(), incidents.docs.DocumentLink(ctx, path)), nil
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// incidentCaptureReason is the reason recorded on the messages captured for the timeline of an incident
+const incidentCaptureReason = "captured for incident"
+
+// IncidentService runs incident mode: a thread declared an incident has every message captured until it is
+// resolved, and its resolution stores the timeline and a postmortem draft under docs/incidents/
+type IncidentService struct {
+	store    ports.IncidentStore
+	messages ports.MessageRepository
+	docs     *DocumentationService
+	tracker  *MessageTracker
+	writer   ports.PostmortemWriter
+}
+
+// NewIncidentService creates an IncidentService. Without a postmortem writer the reports have the sections of a
+// postmortem for people to write.
+func NewIncidentService(
+	store ports.IncidentStore,
+	messages ports.MessageRepository,
+	docs *DocumentationService,
+	tracker *MessageTracker,
+	writer ports.PostmortemWriter,
+) *IncidentService {
+	if store == nil {
+		panic("incident store cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if tracker == nil {
+		panic("message tracker cannot be nil")
+	}
+	return &IncidentService{
+		store:    store,
+		messages: messages,
+		docs:     docs,
+		tracker:  tracker,
+		writer:   writer,
+	}
+}
+
+// Start declares an incident in the thread of a message. Without a title the incident is named after its thread.
+func (s *IncidentService) Start(ctx context.Context, msg *domain.Message, title string) (*domain.Incident, error) {
+	open, err := s.Open(ctx, msg.ThreadID().String())
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, fmt.Errorf("incident %q is already open in this thread", open.Title())
+	}
+
+	if title == "" {
+		title = s.docs.threadTitle(ctx, msg)
+	}
+	incident, err := domain.NewIncident(msg.ThreadID().String(), msg.ChannelID(), title, msg.Sender(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Save(ctx, incident); err != nil {
+		return nil, fmt.Errorf("failed to save incident: %w", err)
+	}
+	return incident, nil
+}
+
+// Open returns the incident open in a thread, nil when there is none
+func (s *IncidentService) Open(ctx context.Context, threadID string) (*domain.Incident, error) {
+	incident, err := s.store.Find(ctx, threadID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incident: %w", err)
+	}
+	if incident.Resolved() {
+		return nil, nil
+	}
+	return incident, nil
+}
+
+// Capture keeps a message of a thread with an open incident for the incident's timeline, it is not analyzed
+// on its own. It reports whether the message was captured.
+func (s *IncidentService) Capture(ctx context.Context, msg *domain.Message) (bool, error) {
+	incident, err := s.Open(ctx, msg.ThreadID().String())
+	if err != nil || incident == nil {
+		return false, err
+	}
+	return true, s.tracker.Transition(ctx, msg, domain.MessageStateAnalyzing, incidentCaptureReason)
+}
+
+// Resolve resolves the incident open in the thread of a message, and stores its timeline with a postmortem
+// draft. It returns the resolved incident and the path of its report.
+func (s *IncidentService) Resolve(ctx context.Context, msg *domain.Message) (*domain.Incident, string, error) {
+	incident, err := s.Open(ctx, msg.ThreadID().String())
+	if err != nil {
+		return nil, "", err
+	}
+	if incident == nil {
+		return nil, "", fmt.Errorf("no incident is open in this thread, `%s incident start` declares one", domain.CommandPrefix)
+	}
+
+	if err := incident.Resolve(msg.Sender(), time.Now()); err != nil {
+		return nil, "", err
+	}
+	messages, err := s.messages.FindByThread(ctx, incident.ThreadID())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find the messages of the incident: %w", err)
+	}
+	timeline := domain.NewIncidentTimeline(incident, messages)
+	report := domain.RenderIncidentReport(incident, timeline, s.draftPostmortem(ctx, msg, incident, timeline))
+
+	path, err := s.docs.storeIncidentReport(ctx, msg, incident, report)
+	if err != nil {
+		return nil, "", err
+	}
+	// The incident is only resolved once its report is stored, so a failed resolution can be retried
+	if err := s.store.Save(ctx, incident); err != nil {
+		return nil, "", fmt.Errorf("failed to save incident: %w", err)
+	}
+
+	for _, captured := range messages {
+		if captured.State() != domain.MessageStateAnalyzing || captured.StateReason() != incidentCaptureReason {
+			continue
+		}
+		if err := s.tracker.Document(ctx, captured, path, "documented in "+path); err != nil {
+			logf(ctx, "Failed to mark message %s documented in %s: %v", captured.ID(), path, err)
+		}
+	}
+	return incident, path, nil
+}
+
+// draftPostmortem asks the postmortem writer for a draft, empty when there is no writer, the incident's project is
+// local-only and the writer is not, or the draft fails
+func (s *IncidentService) draftPostmortem(ctx context.Context, msg *domain.Message, incident *domain.Incident, timeline []domain.IncidentEvent) string {
+	if s.writer == nil {
+		return ""
+	}
+	docConfig, err := s.docs.documentationConfig(ctx, msg)
+	if err != nil {
+		logf(ctx, "Leaving the postmortem of incident %q to people: %v", incident.Title(), err)
+		return ""
+	}
+	if err := checkResidency(msg.ChannelID(), docConfig.LocalOnly, "postmortem", s.writer); err != nil {
+		logf(ctx, "Leaving the postmortem of incident %q to people: %v", incident.Title(), err)
+		return ""
+	}
+
+	draft, err := s.writer.DraftPostmortem(ctx, incident.Title(), domain.RenderIncidentTimeline(timeline))
+	if err != nil {
+		logf(ctx, "Leaving the postmortem of incident %q to people: %v", incident.Title(), err)
+		return ""
+	}
+	return draft
+}
+
+// storeIncidentReport stores the report of a resolved incident in the store of its channel's project and
+// indexes it as an operations status document
+func (s *DocumentationService) storeIncidentReport(ctx context.Context, msg *domain.Message, incident *domain.Incident, report string) (string, error) {
+	docConfig, err := s.documentationConfig(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	store, err := s.stores.ForDocumentation(msg.ChannelID(), docConfig)
+	if err != nil {
+		return "", err
+	}
+	path, err := s.uniquePath(ctx, store, incident.Path())
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := domain.NewIndexedDocument(
+		path,
+		domain.TitleFromMarkdown(report),
+		domain.SummaryFromMarkdown(report),
+		domain.MessageTypeStatus,
+		domain.CategoryOperations,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create index entry: %w", err)
+	}
+	entry.SetTags(domain.NewTags([]string{"incident", "postmortem"}))
+	entry.SetLocation(docConfig.Repository, docConfig.Branch)
+	if project, err := s.messageProject(ctx, msg); err == nil && project != nil {
+		entry.SetProject(project.ID())
+	}
+	if err := s.index.Index(ctx, entry); err != nil {
+		return "", fmt.Errorf("failed to index incident report: %w", err)
+	}
+
+	fm := domain.NewFrontMatter()
+	incident.Record(fm)
+	metadata := map[string]interface{}{
+		"type":     "incident",
+		"category": domain.CategoryOperations.String(),
+	}
+	if err := s.storeWithContents(ctx, store, docConfig, path, fm.Apply(report), metadata, domain.CategoryOperations, nil); err != nil {
+		// Keep the index in line with the store, the report was not written
+		_ = s.index.Remove(ctx, path)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// RegisterKnowledgeCommands registers the "ask" command backed by the knowledge service.
+// With feature flags (optional), questions are only answered in the projects the questions feature is on for.
+func RegisterKnowledgeCommands(commands *CommandService, knowledge *KnowledgeService, docs *DocumentationService, flags *FeatureFlagService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if knowledge == nil {
+		panic("knowledge service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+
+	commands.Register("ask", "ask <question>", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		question := strings.Join(cmd.Args(), " ")
+		if question == "" {
+			return "", fmt.Errorf("usage: `%s ask <question>`", domain.CommandPrefix)
+		}
+		if flags != nil {
+			project, err := docs.messageProject(ctx, msg)
+			if err != nil {
+				return "", err
+			}
+			if !flags.Enabled(ctx, domain.FeatureQuestions, project) {
+				return "🧪 Questions are not turned on for this project yet.", nil
+			}
+		}
+
+		answer, err := knowledge.AnswerQuestion(ctx, msg, question)
+		if err != nil {
+			return "", err
+		}
+		return formatAnswer(ctx, answer, docs), nil
+	})
+}
+
+func formatAnswer(ctx context.Context, answer *domain.Answer, docs *DocumentationService) string {
+	var b strings.Builder
+	b.WriteString(answer.Text())
+
+	if answer.HasSources() {
+		b.WriteString("\n\n📚 Sources:\n")
+		for i, source := range answer.Sources() {
+			b.WriteString(fmt.Sprintf("[%d] %s — %s\n", i+1, source.Title(), docs.DocumentLink(ctx, source.Path())))
+		}
+	}
+	return b.String()
+}
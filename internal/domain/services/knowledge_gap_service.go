@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"time"
+)
+
+// DefaultGapReportInterval is how often knowledge gaps are reported to project channels
+const DefaultGapReportInterval = 7 * 24 * time.Hour
+
+// KnowledgeGapService compares the goals, KPIs and milestones of projects with their indexed documents,
+// and posts what the documentation is missing to the project channels
+type KnowledgeGapService struct {
+	projects    ports.ProjectRepository
+	index       ports.DocumentIndex
+	stores      *DocStoreResolver
+	chat        ports.ChatAccessProvider
+	coordinator ports.WorkCoordinator
+	staleAfter  time.Duration
+}
+
+// NewKnowledgeGapService creates a KnowledgeGapService. KPIs are reported once they have no status updates
+// for staleAfter, zero uses domain.DefaultGapStaleness. The coordinator is optional, with it each channel
+// is reported to by the replica owning it only.
+func NewKnowledgeGapService(
+	projects ports.ProjectRepository,
+	index ports.DocumentIndex,
+	stores *DocStoreResolver,
+	chat ports.ChatAccessProvider,
+	coordinator ports.WorkCoordinator,
+	staleAfter time.Duration,
+) *KnowledgeGapService {
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if stores == nil {
+		panic("docStore resolver cannot be nil")
+	}
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	if staleAfter <= 0 {
+		staleAfter = domain.DefaultGapStaleness
+	}
+	return &KnowledgeGapService{
+		projects:    projects,
+		index:       index,
+		stores:      stores,
+		chat:        chat,
+		coordinator: coordinator,
+		staleAfter:  staleAfter,
+	}
+}
+
+// Run reports the knowledge gaps of every project at each interval until ctx is canceled.
+// Zero interval uses DefaultGapReportInterval. Failed reports are logged and retried at the next interval.
+func (s *KnowledgeGapService) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultGapReportInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if err := s.Report(ctx, now); err != nil {
+				log.Printf("Failed to report knowledge gaps: %v", err)
+			}
+		}
+	}
+}
+
+// Report posts the knowledge gaps of each active project to its channels. Projects without gaps are not
+// reported to, and a project that fails does not keep the others from being reported.
+func (s *KnowledgeGapService) Report(ctx context.Context, now time.Time) error {
+	projects, err := s.projects.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var errs []error
+	for _, project := range projects {
+		if !project.AcceptsMessages() || len(project.Channels()) == 0 {
+			continue
+		}
+		if err := s.reportProject(ctx, project, now); err != nil {
+			errs = append(errs, fmt.Errorf("project %s: %w", project.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *KnowledgeGapService) reportProject(ctx context.Context, project *domain.Project, now time.Time) error {
+	var channels []string
+	for _, channelID := range project.Channels() {
+		owned, err := s.owns(ctx, channelID)
+		if err != nil {
+			return err
+		}
+		if owned {
+			channels = append(channels, channelID)
+		}
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+
+	gaps, err := s.Analyze(ctx, project, now)
+	if err != nil {
+		return err
+	}
+	if len(gaps) == 0 {
+		return nil
+	}
+
+	report := domain.RenderKnowledgeGaps(project, gaps)
+	for _, channelID := range channels {
+		if err := s.chat.SendMessage(ctx, channelID, report); err != nil {
+			return fmt.Errorf("failed to post knowledge gaps to %s: %w", channelID, err)
+		}
+	}
+	return nil
+}
+
+// Analyze returns the knowledge gaps of a project. Its documents are matched on their index entries,
+// and recent status updates on their content, since weekly rollups collect many updates in one document.
+func (s *KnowledgeGapService) Analyze(ctx context.Context, project *domain.Project, now time.Time) ([]domain.KnowledgeGap, error) {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	since := now.Add(-s.staleAfter)
+	var evidence []domain.GapEvidence
+	for _, doc := range docs {
+		if doc.Project() != project.ID() {
+			continue
+		}
+		e := domain.EvidenceFromIndex(doc)
+		if doc.Type().IsStatus() && !doc.UpdatedAt().Before(since) {
+			content, err := s.documentContent(ctx, doc)
+			if err != nil {
+				log.Printf("Failed to read status update %s, matching its index entry: %v", doc.Path(), err)
+			}
+			e.Text += " " + content
+		}
+		evidence = append(evidence, e)
+	}
+
+	return domain.AnalyzeKnowledgeGaps(project, evidence, now, s.staleAfter), nil
+}
+
+func (s *KnowledgeGapService) documentContent(ctx context.Context, doc *domain.IndexedDocument) (string, error) {
+	store, err := s.stores.Resolve(doc.Repository(), doc.Branch())
+	if err != nil {
+		return "", err
+	}
+	content, err := store.GetDocument(ctx, doc.Path())
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func (s *KnowledgeGapService) owns(ctx context.Context, channelID string) (bool, error) {
+	if s.coordinator == nil {
+		return true, nil
+	}
+	owned, err := s.coordinator.Owns(ctx, channelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check ownership of channel %s: %w", channelID, err)
+	}
+	return owned, nil
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxAnswerSources limits how many documents are given to the AI agent per question
+	maxAnswerSources = 4
+	// maxSourceLength limits how much of each document is given to the AI agent
+	maxSourceLength = 4000
+	// maxConversationTurns limits how many earlier questions of a thread are remembered
+	maxConversationTurns = 5
+	// AssistantSender identifies answers given by the bot in the message store
+	AssistantSender = "quill"
+	// conversationStateReason explains why questions and answers are not documented
+	conversationStateReason = "conversation"
+)
+
+// KnowledgeService answers questions from the stored documentation the asking channel may see: documents
+// outside of projects, those of its own project and those other projects share
+type KnowledgeService struct {
+	docService *DocumentationService
+	index      ports.DocumentIndex
+	shared     *SharedIndex
+	messages   ports.MessageRepository
+	answerer   ports.QuestionAnswerer
+	embeddings ports.EmbeddingProvider
+}
+
+// NewKnowledgeService creates a new KnowledgeService.
+// Answers are generated when the AI agent implements ports.QuestionAnswerer;
+// otherwise only the relevant documents are returned.
+func NewKnowledgeService(
+	docs *DocumentationService,
+	index ports.DocumentIndex,
+	messages ports.MessageRepository,
+	ai ports.AiAgentProvider,
+) *KnowledgeService {
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if ai == nil {
+		panic("AI agent cannot be nil")
+	}
+
+	answerer, _ := ai.(ports.QuestionAnswerer)
+	embeddings, _ := ai.(ports.EmbeddingProvider)
+	return &KnowledgeService{
+		docService: docs,
+		index:      index,
+		shared:     NewSharedIndex(index, docs.projects),
+		messages:   messages,
+		answerer:   answerer,
+		embeddings: embeddings,
+	}
+}
+
+// AnswerQuestion answers a question asked in msg's thread with citations.
+// Earlier questions of the thread and the documents selected for them are used as context,
+// and the question and answer are remembered for follow-ups.
+func (s *KnowledgeService) AnswerQuestion(ctx context.Context, msg *domain.Message, question string) (*domain.Answer, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("message cannot be nil")
+	}
+	if strings.TrimSpace(question) == "" {
+		return nil, domain.ErrEmptyQuestion
+	}
+
+	conversation, err := s.loadConversation(ctx, msg.ThreadID().String())
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.docService.messageProject(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	var viewer common.ID
+	if project != nil {
+		viewer = project.ID()
+	}
+
+	docs, err := s.retrieve(ctx, question, conversation, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	generate, err := s.mayGenerate(ctx, msg, project, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := s.answer(ctx, question, conversation, docs, generate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.remember(ctx, msg, answer); err != nil {
+		return nil, err
+	}
+
+	return answer, nil
+}
+
+func (s *KnowledgeService) answer(
+	ctx context.Context,
+	question string,
+	conversation *domain.Conversation,
+	docs []*domain.IndexedDocument,
+	generate bool,
+) (*domain.Answer, error) {
+	if len(docs) == 0 {
+		return domain.NewAnswer(question, "I couldn't find anything about that in the knowledge base.", nil)
+	}
+
+	sources, err := s.loadSources(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.answerer == nil || !generate {
+		return domain.NewAnswer(question, "These documents look relevant to your question:", sources)
+	}
+
+	text, err := s.answerer.AnswerQuestion(ctx, question, conversation, sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	return domain.NewAnswer(question, text, sources)
+}
+
+// mayGenerate checks if the question and the documents may be sent to the AI agent to generate an answer.
+// Questions asked in local-only projects and documents of local-only projects only go to local agents,
+// otherwise the documents are listed without an answer.
+func (s *KnowledgeService) mayGenerate(ctx context.Context, msg *domain.Message, project *domain.Project, docs []*domain.IndexedDocument) (bool, error) {
+	if s.answerer == nil {
+		return false, nil
+	}
+
+	localOnly := project != nil && project.Documentation().LocalOnly
+	for _, doc := range docs {
+		if localOnly {
+			break
+		}
+		if doc.Project().String() == "" {
+			continue
+		}
+		owner, err := s.docService.projects.FindByID(ctx, doc.Project())
+		if errors.Is(err, ports.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to find project: %w", err)
+		}
+		localOnly = owner.Documentation().LocalOnly
+	}
+
+	return checkResidency(msg.ChannelID(), localOnly, "question answering", s.answerer) == nil, nil
+}
+
+// loadConversation rebuilds the questions and answers of a thread from the message store
+func (s *KnowledgeService) loadConversation(ctx context.Context, threadID string) (*domain.Conversation, error) {
+	msgs, err := s.messages.FindByThread(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation: %w", err)
+	}
+
+	var turns []*domain.ConversationTurn
+	var question string
+	for _, m := range msgs {
+		if m.Sender() != AssistantSender {
+			if cmd, err := domain.ParseCommand(m.Content().Text()); err == nil && cmd.Name() == "ask" {
+				question = strings.Join(cmd.Args(), " ")
+			}
+			continue
+		}
+		if question == "" {
+			continue
+		}
+
+		var sources []string
+		for _, ref := range m.LinkedReferences() {
+			if ref.Type().IsDocument() {
+				sources = append(sources, ref.Value())
+			}
+		}
+		turn, err := domain.NewConversationTurn(question, m.Content().Text(), sources)
+		if err != nil {
+			return nil, err
+		}
+		turns = append(turns, turn)
+		question = ""
+	}
+
+	return domain.NewConversation(turns, maxConversationTurns), nil
+}
+
+// remember stores the question and its answer so follow-up questions can build on them
+func (s *KnowledgeService) remember(ctx context.Context, msg *domain.Message, answer *domain.Answer) error {
+	// Questions and answers are conversation history, they never enter the documentation pipeline
+	if msg.State() == domain.MessageStatePending {
+		if err := msg.MarkIgnored(conversationStateReason); err != nil {
+			return err
+		}
+	}
+	if err := s.messages.Save(ctx, msg); err != nil {
+		return fmt.Errorf("failed to save question: %w", err)
+	}
+
+	refs := make([]*domain.Reference, 0, len(answer.Sources()))
+	for _, source := range answer.Sources() {
+		ref, err := domain.NewDocumentReference(source.Path())
+		if err != nil {
+			return err
+		}
+		refs = append(refs, ref)
+	}
+
+	content, err := domain.NewMessageContent(answer.Text())
+	if err != nil {
+		return err
+	}
+	reply, err := domain.NewMessage(
+		msg.ThreadID(),
+		AssistantSender,
+		content,
+		domain.MessageTypeInformation,
+		domain.CategoryUnknown,
+		refs,
+	)
+	if err != nil {
+		return err
+	}
+	if err := reply.MarkIgnored(conversationStateReason); err != nil {
+		return err
+	}
+	if err := s.messages.Save(ctx, reply); err != nil {
+		return fmt.Errorf("failed to save answer: %w", err)
+	}
+
+	return nil
+}
+
+// retrieve returns the documents visible from the viewer project most relevant to the question.
+// Follow-up questions are matched together with the previous question,
+// and documents selected earlier in the conversation fill the remaining slots.
+func (s *KnowledgeService) retrieve(
+	ctx context.Context,
+	question string,
+	conversation *domain.Conversation,
+	viewer common.ID,
+) ([]*domain.IndexedDocument, error) {
+	query := strings.TrimSpace(conversation.LastQuestion() + " " + question)
+
+	docs, err := s.shared.Visible(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var queryEmbedding []float64
+	if s.embeddings != nil {
+		// Fall back to text similarity when embeddings are unavailable
+		queryEmbedding, _ = s.embeddings.Embed(ctx, query)
+	}
+
+	type scored struct {
+		doc   *domain.IndexedDocument
+		score float64
+	}
+
+	var matches []scored
+	for _, doc := range docs {
+		score := domain.TextSimilarity(query, doc.SearchText())
+		if len(queryEmbedding) > 0 && doc.HasEmbedding() {
+			score = math.Max(score, domain.CosineSimilarity(queryEmbedding, doc.Embedding()))
+		}
+		if score > 0 {
+			matches = append(matches, scored{doc: doc, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	if len(matches) > maxAnswerSources {
+		matches = matches[:maxAnswerSources]
+	}
+
+	result := make([]*domain.IndexedDocument, 0, maxAnswerSources)
+	selected := make(map[string]bool)
+	for _, m := range matches {
+		result = append(result, m.doc)
+		selected[m.doc.Path()] = true
+	}
+
+	for _, path := range conversation.Sources() {
+		if len(result) >= maxAnswerSources {
+			break
+		}
+		if selected[path] {
+			continue
+		}
+		doc, err := s.index.FindByPath(ctx, path)
+		if errors.Is(err, ports.ErrNotFound) {
+			// The document was removed since it was cited
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up document: %w", err)
+		}
+		// The document may have been made internal since it was cited
+		visible, err := s.shared.IsVisible(ctx, doc, viewer)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+		result = append(result, doc)
+		selected[path] = true
+	}
+
+	return result, nil
+}
+
+// loadSources reads the documents and strips the parts that are not useful to the AI agent
+func (s *KnowledgeService) loadSources(ctx context.Context, docs []*domain.IndexedDocument) ([]*domain.AnswerSource, error) {
+	sources := make([]*domain.AnswerSource, 0, len(docs))
+	for _, doc := range docs {
+		content, err := s.docService.GetDocumentation(ctx, doc.Path())
+		if err != nil {
+			return nil, err
+		}
+
+		_, body, err := domain.ParseFrontMatter(string(content))
+		if err != nil {
+			// Documents with broken front matter are still worth reading
+			body = string(content)
+		}
+		body = domain.StripBacklinks(body)
+		if len(body) > maxSourceLength {
+			body = body[:maxSourceLength]
+		}
+
+		source, err := domain.NewAnswerSource(doc.Path(), doc.Title(), body)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"net/url"
+	"strings"
+)
+
+// LinkPreviewService finds the documents behind links people post in chat, so they can be previewed with
+// their title, type, summary and last update. Links to documents in the document stores and on the
+// dashboard are recognized. Documents are only previewed in channels that may see them.
+type LinkPreviewService struct {
+	docs         *DocumentationService
+	index        ports.DocumentIndex
+	projects     ports.ProjectRepository
+	shared       *SharedIndex
+	dashboardURL string
+}
+
+// NewLinkPreviewService creates a LinkPreviewService. The dashboard URL is where the documents endpoint is
+// served, like https://quill.example.com/documents/, empty when links to the dashboard are not previewed.
+func NewLinkPreviewService(
+	docs *DocumentationService,
+	index ports.DocumentIndex,
+	projects ports.ProjectRepository,
+	dashboardURL string,
+) *LinkPreviewService {
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	dashboardURL = strings.TrimSpace(dashboardURL)
+	if dashboardURL != "" && !strings.HasSuffix(dashboardURL, "/") {
+		dashboardURL += "/"
+	}
+	return &LinkPreviewService{
+		docs:         docs,
+		index:        index,
+		projects:     projects,
+		shared:       NewSharedIndex(index, projects),
+		dashboardURL: dashboardURL,
+	}
+}
+
+// RegisterLinkPreviews lets the chat provider preview links to documents.
+// It does nothing when the chat provider does not implement ports.LinkUnfurler.
+func RegisterLinkPreviews(chat ports.ChatAccessProvider, service *LinkPreviewService) {
+	if service == nil {
+		panic("link preview service cannot be nil")
+	}
+	if unfurler, ok := chat.(ports.LinkUnfurler); ok {
+		unfurler.OnLinkShared(service.Find)
+	}
+}
+
+// Find returns the indexed document a link posted in a channel points at. It returns ports.ErrNotFound
+// for links to anything else, and to documents of other projects the channel may not see.
+func (s *LinkPreviewService) Find(ctx context.Context, channelID, link string) (*domain.IndexedDocument, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	doc, err := s.document(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	var viewer common.ID
+	project, err := s.projects.FindByChannel(ctx, channelID)
+	switch {
+	case err == nil:
+		viewer = project.ID()
+	case !errors.Is(err, ports.ErrNotFound):
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+
+	visible, err := s.shared.IsVisible(ctx, doc, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, fmt.Errorf("document %s is not visible in channel %s: %w", doc.Path(), channelID, ports.ErrNotFound)
+	}
+	return doc, nil
+}
+
+// document returns the indexed document a link points at
+func (s *LinkPreviewService) document(ctx context.Context, link string) (*domain.IndexedDocument, error) {
+	link = withoutQuery(strings.TrimSpace(link))
+	if s.dashboardURL != "" && strings.HasPrefix(link, s.dashboardURL) {
+		docPath, err := url.PathUnescape(strings.TrimPrefix(link, s.dashboardURL))
+		if err != nil {
+			return nil, fmt.Errorf("invalid document link %s: %w", link, ports.ErrNotFound)
+		}
+		return s.index.FindByPath(ctx, docPath)
+	}
+
+	// Store links end with the document's path, after the repository's base path
+	unescaped, err := url.PathUnescape(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document link %s: %w", link, ports.ErrNotFound)
+	}
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+	for _, doc := range docs {
+		if strings.HasSuffix(unescaped, "/"+doc.Path()) && withoutQuery(s.docs.DocumentLink(ctx, doc.Path())) == unescaped {
+			return doc, nil
+		}
+	}
+	return nil, fmt.Errorf("no document at %s: %w", link, ports.ErrNotFound)
+}
+
+// withoutQuery drops the query and the fragment of a link, like the anchor of a heading
+func withoutQuery(link string) string {
+	if i := strings.IndexAny(link, "?#"); i >= 0 {
+		return link[:i]
+	}
+	return link
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// MeetingContext finds the meeting during which a threaded discussion happened by matching the
+// timestamps of the thread's messages to the events of a calendar
+type MeetingContext struct {
+	calendar ports.CalendarProvider
+	messages ports.MessageRepository
+}
+
+// NewMeetingContext creates a MeetingContext. Without a message repository only the documented message's
+// own timestamp is matched.
+func NewMeetingContext(calendar ports.CalendarProvider, messages ports.MessageRepository) *MeetingContext {
+	if calendar == nil {
+		panic("calendar provider cannot be nil")
+	}
+	return &MeetingContext{
+		calendar: calendar,
+		messages: messages,
+	}
+}
+
+// OriginatingMeeting returns the meeting the message's thread happened during, or nil when it happened outside of any
+func (c *MeetingContext) OriginatingMeeting(ctx context.Context, msg *domain.Message) (*domain.Meeting, error) {
+	from, to, err := c.threadSpan(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	meetings, err := c.calendar.MeetingsBetween(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return domain.OriginatingMeeting(meetings, from, to), nil
+}
+
+// threadSpan returns when the first and the last message of the message's thread were posted
+func (c *MeetingContext) threadSpan(ctx context.Context, msg *domain.Message) (time.Time, time.Time, error) {
+	from, to := msg.Timestamp(), msg.Timestamp()
+	threadID := msg.ThreadID().String()
+	if c.messages == nil || threadID == "" {
+		return from, to, nil
+	}
+
+	thread, err := c.messages.FindByThread(ctx, threadID)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to find thread messages: %w", err)
+	}
+	for _, m := range thread {
+		if m.Timestamp().Before(from) {
+			from = m.Timestamp()
+		}
+		if m.Timestamp().After(to) {
+			to = m.Timestamp()
+		}
+	}
+	return from, to, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// RegisterMeetingNotesCommands registers the "notes" command, sent in a thread to take it as the notes of a
+// meeting or to close them
+func RegisterMeetingNotesCommands(commands *CommandService, notes *MeetingNotesService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if notes == nil {
+		panic("meeting notes service cannot be nil")
+	}
+
+	commands.Register("notes", "notes [<title>]|close", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		if strings.EqualFold(cmd.Arg(0), "close") && len(cmd.Args()) == 1 {
+			return closeNotes(ctx, notes, msg)
+		}
+		return startNotes(ctx, notes, msg, cmd)
+	})
+}
+
+func startNotes(ctx context.Context, notes *MeetingNotesService, msg *domain.Message, cmd *domain.Command) (string, error) {
+	session, err := notes.Start(ctx, msg, strings.Join(cmd.Args(), " "))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("📝 Taking notes of *%s*. Every message of this thread is collected for them until `%s notes close`.",
+		session.Title(), domain.CommandPrefix), nil
+}
+
+func closeNotes(ctx context.Context, notes *MeetingNotesService, msg *domain.Message) (string, error) {
+	meeting, path, records, err := notes.Close(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	reply := fmt.Sprintf("✅ Notes of *%s* closed with %d attendees, %d decisions and %d action items. They are in %s.",
+		meeting.Session().Title(), len(meeting.Attendees()), len(meeting.Decisions()), len(meeting.ActionItems()),
+		notes.docs.DocumentLink(ctx, path))
+	for _, record := range records {
+		reply += "\n• " + notes.docs.DocumentLink(ctx, record)
+	}
+	return reply, nil
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// notesCaptureReason is the reason recorded on the messages captured for the notes of a meeting
+const notesCaptureReason = "captured for meeting notes"
+
+// MeetingNotesService runs meeting-notes mode: a thread taken as the notes of a meeting, like the thread of a
+// huddle, has every message collected until it is closed. Closing it stores structured notes under docs/meetings/
+// and documents each decision taken as a decision record of its own.
+type MeetingNotesService struct {
+	store    ports.NotesSessionStore
+	messages ports.MessageRepository
+	docs     *DocumentationService
+	tracker  *MessageTracker
+	writer   ports.MeetingNotesWriter
+}
+
+// NewMeetingNotesService creates a MeetingNotesService. Without a notes writer the notes list the attendees
+// and keep the discussion as it was, with no agenda, decisions or action items drawn from it.
+func NewMeetingNotesService(
+	store ports.NotesSessionStore,
+	messages ports.MessageRepository,
+	docs *DocumentationService,
+	tracker *MessageTracker,
+	writer ports.MeetingNotesWriter,
+) *MeetingNotesService {
+	if store == nil {
+		panic("notes session store cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if tracker == nil {
+		panic("message tracker cannot be nil")
+	}
+	return &MeetingNotesService{
+		store:    store,
+		messages: messages,
+		docs:     docs,
+		tracker:  tracker,
+		writer:   writer,
+	}
+}
+
+// Start takes the thread of a message as the notes of a meeting. Without a title the meeting is named after
+// its thread.
+func (s *MeetingNotesService) Start(ctx context.Context, msg *domain.Message, title string) (*domain.NotesSession, error) {
+	open, err := s.Open(ctx, msg.ThreadID().String())
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, fmt.Errorf("notes of %q are already taken in this thread", open.Title())
+	}
+
+	if title == "" {
+		title = s.docs.threadTitle(ctx, msg)
+	}
+	session, err := domain.NewNotesSession(msg.ThreadID().String(), msg.ChannelID(), title, msg.Sender(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save notes session: %w", err)
+	}
+	return session, nil
+}
+
+// Open returns the notes session open in a thread, nil when there is none
+func (s *MeetingNotesService) Open(ctx context.Context, threadID string) (*domain.NotesSession, error) {
+	session, err := s.store.Find(ctx, threadID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notes session: %w", err)
+	}
+	if session.Closed() {
+		return nil, nil
+	}
+	return session, nil
+}
+
+// Capture keeps a message of a thread with open notes for the notes, it is not analyzed on its own. It reports
+// whether the message was captured.
+func (s *MeetingNotesService) Capture(ctx context.Context, msg *domain.Message) (bool, error) {
+	session, err := s.Open(ctx, msg.ThreadID().String())
+	if err != nil || session == nil {
+		return false, err
+	}
+	return true, s.tracker.Transition(ctx, msg, domain.MessageStateAnalyzing, notesCaptureReason)
+}
+
+// Close closes the notes open in the thread of a message, stores them and documents their decisions as
+// decision records. It returns the notes with the path they are stored at and the paths of the records.
+func (s *MeetingNotesService) Close(ctx context.Context, msg *domain.Message) (*domain.MeetingNotes, string, []string, error) {
+	session, err := s.Open(ctx, msg.ThreadID().String())
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if session == nil {
+		return nil, "", nil, fmt.Errorf("no notes are taken in this thread, `%s notes` starts them", domain.CommandPrefix)
+	}
+
+	if err := session.Close(msg.Sender(), time.Now()); err != nil {
+		return nil, "", nil, err
+	}
+	messages, err := s.messages.FindByThread(ctx, session.ThreadID())
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to find the messages of the meeting: %w", err)
+	}
+	notes := domain.NewMeetingNotes(session, messages, s.summarize(ctx, msg, session, messages))
+
+	path, err := s.docs.notesPath(ctx, msg, session)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	// A decision that cannot be documented is still listed in the notes, without a link
+	records := notes.DecisionRecords(path)
+	recordPaths := make([]string, len(records))
+	for i, record := range records {
+		recordPaths[i], err = s.documentDecision(ctx, msg.ThreadID(), session, record, i)
+		if err != nil {
+			logf(ctx, "Failed to document decision %q of meeting %q: %v", record.Title(), session.Title(), err)
+		}
+	}
+
+	if err := s.docs.storeMeetingNotes(ctx, msg, notes, path, recordPaths); err != nil {
+		return nil, "", nil, err
+	}
+	// The notes are only closed once they are stored, so a failed close can be retried
+	if err := s.store.Save(ctx, session); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to save notes session: %w", err)
+	}
+
+	for _, captured := range messages {
+		if captured.State() != domain.MessageStateAnalyzing || captured.StateReason() != notesCaptureReason {
+			continue
+		}
+		if err := s.tracker.Document(ctx, captured, path, "documented in "+path); err != nil {
+			logf(ctx, "Failed to mark message %s documented in %s: %v", captured.ID(), path, err)
+		}
+	}
+
+	var documented []string
+	for _, recordPath := range recordPaths {
+		if recordPath != "" {
+			documented = append(documented, recordPath)
+		}
+	}
+	return notes, path, documented, nil
+}
+
+// summarize asks the notes writer for the summary of a meeting, nil when there is no writer, the meeting's project
+// is local-only and the writer is not, or the summary fails
+func (s *MeetingNotesService) summarize(ctx context.Context, msg *domain.Message, session *domain.NotesSession, messages []*domain.Message) *domain.MeetingSummary {
+	if s.writer == nil {
+		return nil
+	}
+	transcript := domain.MeetingTranscript(session.Messages(messages))
+	if transcript == "" {
+		return nil
+	}
+	docConfig, err := s.docs.documentationConfig(ctx, msg)
+	if err != nil {
+		logf(ctx, "Keeping the discussion of meeting %q as it was: %v", session.Title(), err)
+		return nil
+	}
+	if err := checkResidency(msg.ChannelID(), docConfig.LocalOnly, "meeting notes", s.writer); err != nil {
+		logf(ctx, "Keeping the discussion of meeting %q as it was: %v", session.Title(), err)
+		return nil
+	}
+
+	summary, err := s.writer.SummarizeMeeting(ctx, session.Title(), transcript)
+	if err != nil {
+		logf(ctx, "Keeping the discussion of meeting %q as it was: %v", session.Title(), err)
+		return nil
+	}
+	return summary
+}
+
+// documentDecision documents a decision of a meeting as a decision of the meeting's thread, posted by who
+// started the notes, with the record as its document
+func (s *MeetingNotesService) documentDecision(
+	ctx context.Context,
+	threadID common.ID,
+	session *domain.NotesSession,
+	record *domain.DecisionRecord,
+	i int,
+) (string, error) {
+	content, err := domain.NewMessageContent(record.Text())
+	if err != nil {
+		return "", err
+	}
+	decision, err := domain.NewMessage(threadID, session.StartedBy(), content, domain.MessageTypeDecision, record.Category(), nil)
+	if err != nil {
+		return "", err
+	}
+	decision.SetChannelID(session.ChannelID())
+	// Decisions documented before a failed close are found again when it is retried
+	decision.SetSourceTimestamp(fmt.Sprintf("notes-%d-decision-%d", session.StartedAt().Unix(), i+1))
+
+	path, _, err := s.docs.createDocumentation(ctx, decision, record)
+	return path, err
+}
+
+// notesPath returns a path for the notes of a meeting no stored document has
+func (s *DocumentationService) notesPath(ctx context.Context, msg *domain.Message, session *domain.NotesSession) (string, error) {
+	docConfig, err := s.documentationConfig(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	store, err := s.stores.ForDocumentation(msg.ChannelID(), docConfig)
+	if err != nil {
+		return "", err
+	}
+	return s.uniquePath(ctx, store, session.Path())
+}
+
+// storeMeetingNotes stores the notes of a closed meeting in the store of its channel's project and indexes them
+func (s *DocumentationService) storeMeetingNotes(ctx context.Context, msg *domain.Message, notes *domain.MeetingNotes, path string, recordPaths []string) error {
+	docConfig, err := s.documentationConfig(ctx, msg)
+	if err != nil {
+		return err
+	}
+	store, err := s.stores.ForDocumentation(msg.ChannelID(), docConfig)
+	if err != nil {
+		return err
+	}
+
+	content := notes.Render(path, recordPaths)
+	entry, err := domain.NewIndexedDocument(
+		path,
+		domain.TitleFromMarkdown(content),
+		domain.SummaryFromMarkdown(content),
+		domain.MessageTypeInformation,
+		domain.CategoryOther,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create index entry: %w", err)
+	}
+	entry.SetTags(domain.NewTags([]string{"meeting-notes"}))
+	entry.SetLocation(docConfig.Repository, docConfig.Branch)
+	if project, err := s.messageProject(ctx, msg); err == nil && project != nil {
+		entry.SetProject(project.ID())
+	}
+	if err := s.index.Index(ctx, entry); err != nil {
+		return fmt.Errorf("failed to index meeting notes: %w", err)
+	}
+
+	fm := domain.NewFrontMatter()
+	notes.Record(fm)
+	metadata := map[string]interface{}{
+		"type":     "meeting_notes",
+		"category": domain.CategoryOther.String(),
+	}
+	if err := s.storeWithContents(ctx, store, docConfig, path, fm.Apply(content), metadata, domain.CategoryOther, nil); err != nil {
+		// Keep the index in line with the store, the notes were not written
+		_ = s.index.Remove(ctx, path)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultQueueWorkers is how many queued messages a worker pool processes at once
+const DefaultQueueWorkers = 4
+
+// QueueIngestion publishes the messages a chat provider receives to a queue instead of processing them,
+// so the instances holding chat connections stay light and processing scales on its own
+type QueueIngestion struct {
+	chat  ports.ChatAccessProvider
+	queue ports.MessageQueue
+}
+
+func NewQueueIngestion(chat ports.ChatAccessProvider, queue ports.MessageQueue) *QueueIngestion {
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	if queue == nil {
+		panic("message queue cannot be nil")
+	}
+	return &QueueIngestion{chat: chat, queue: queue}
+}
+
+// Run publishes received messages until ctx is canceled or the chat provider stops delivering them.
+// Messages that cannot be published are logged and dropped, the chat provider does not deliver them twice.
+func (i *QueueIngestion) Run(ctx context.Context) error {
+	messages, err := i.chat.ListenForMessages(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to listen for messages: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			if err := i.publish(ctx, msg); err != nil {
+				log.Printf("Failed to queue message %s: %v", msg.ID(), err)
+			}
+		}
+	}
+}
+
+// publish enqueues a message with the chat provider's route to it, so workers can reply
+func (i *QueueIngestion) publish(ctx context.Context, msg *domain.Message) error {
+	var route map[string]string
+	if router, ok := i.chat.(ports.MessageRouter); ok {
+		route, _ = router.MessageRoute(msg.ID().String())
+	}
+
+	// The correlation ID is given before publishing, so the deliveries and retries of the message share it
+	ctx = withCorrelationID(ctx, msg)
+	queued, err := domain.NewQueuedMessage(msg, route)
+	if err != nil {
+		return err
+	}
+	return i.queue.Publish(ctx, queued)
+}
+
+// QueueWorkers process queued messages with the bot, several at a time
+type QueueWorkers struct {
+	queue       ports.MessageQueue
+	bot         *BotService
+	workers     int
+	deadLetters ports.DeadLetterQueue
+
+	mu     sync.Mutex
+	status []domain.WorkerStatus
+}
+
+// NewQueueWorkers creates a worker pool. Zero workers use DefaultQueueWorkers.
+// The dead-letter queue is optional, with it messages the bot fails on are set aside for an operator to retry
+// or discard instead of being delivered again by the queue.
+func NewQueueWorkers(queue ports.MessageQueue, bot *BotService, workers int, deadLetters ports.DeadLetterQueue) *QueueWorkers {
+	if queue == nil {
+		panic("message queue cannot be nil")
+	}
+	if bot == nil {
+		panic("bot service cannot be nil")
+	}
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+	return &QueueWorkers{queue: queue, bot: bot, workers: workers, deadLetters: deadLetters}
+}
+
+// Run consumes the queue with every worker until ctx is canceled or a worker loses the queue
+func (w *QueueWorkers) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w.mu.Lock()
+	w.status = make([]domain.WorkerStatus, w.workers)
+	for n := range w.status {
+		w.status[n] = domain.WorkerStatus{Worker: n + 1, Since: time.Now()}
+	}
+	w.mu.Unlock()
+
+	errs := make(chan error, w.workers)
+	var wg sync.WaitGroup
+	for n := 0; n < w.workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.queue.Consume(ctx, func(ctx context.Context, queued *domain.QueuedMessage) error {
+				return w.process(ctx, n, queued)
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				errs <- err
+			}
+			// Without this worker the pool is short, stop the others so the caller can restart it
+			cancel()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	if err, ok := <-errs; ok {
+		return fmt.Errorf("failed to consume message queue: %w", err)
+	}
+	return ctx.Err()
+}
+
+// Status returns what each worker is doing, none before the pool runs
+func (w *QueueWorkers) Status() []domain.WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := make([]domain.WorkerStatus, len(w.status))
+	copy(status, w.status)
+	return status
+}
+
+// process handles a queued message with worker n, recording what the worker is doing
+func (w *QueueWorkers) process(ctx context.Context, n int, queued *domain.QueuedMessage) error {
+	w.update(n, func(status *domain.WorkerStatus) {
+		status.MessageID = queued.Message().ID().String()
+	})
+	err := w.handle(ctx, queued)
+	w.update(n, func(status *domain.WorkerStatus) {
+		status.MessageID = ""
+		if err != nil {
+			status.Failed++
+		} else {
+			status.Processed++
+		}
+	})
+
+	if err != nil && w.deadLetters != nil && ctx.Err() == nil {
+		return w.deadLetter(domain.ContextWithCorrelationID(ctx, queued.Message().CorrelationID()), queued, err)
+	}
+	return err
+}
+
+// handle restores the chat provider's route to a queued message and processes it
+func (w *QueueWorkers) handle(ctx context.Context, queued *domain.QueuedMessage) error {
+	msg := queued.Message()
+	if router, ok := w.bot.chatProvider.(ports.MessageRouter); ok && len(queued.Route()) > 0 {
+		if err := router.RestoreMessageRoute(msg.ID().String(), queued.Route()); err != nil {
+			return err
+		}
+	}
+	return w.bot.ProcessMessage(ctx, msg)
+}
+
+// deadLetter sets a message the bot failed on aside, and tells the queue it was handled so it is not delivered
+// again. When it cannot be set aside the failure is returned, and the queue delivers it again if it can.
+func (w *QueueWorkers) deadLetter(ctx context.Context, queued *domain.QueuedMessage, cause error) error {
+	letter, err := domain.NewDeadLetter(queued, cause.Error(), time.Now())
+	if err == nil {
+		err = w.deadLetters.Add(ctx, letter)
+	}
+	if err != nil {
+		logf(ctx, "Failed to dead-letter message %s: %v", queued.Message().ID(), err)
+		return cause
+	}
+	logf(ctx, "Dead-lettered message %s after %d attempts: %v", letter.ID(), letter.Attempts(), cause)
+	return nil
+}
+
+// update changes the status of worker n
+func (w *QueueWorkers) update(n int, change func(status *domain.WorkerStatus)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	change(&w.status[n])
+	w.status[n].Since = time.Now()
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// DefaultStallAfter is how long a message may stay in a non-final state before it is reported as stalled
+const DefaultStallAfter = 15 * time.Minute
+
+// MessageTracker records the processing state of messages so stalled messages can be found
+type MessageTracker struct {
+	messages   ports.MessageRepository
+	stallAfter time.Duration
+	events     *EventBus
+}
+
+// NewMessageTracker creates a new MessageTracker, a zero stallAfter uses DefaultStallAfter.
+// The event bus is optional, with it messages received, analyzed, committed, ignored and failed are published.
+func NewMessageTracker(messages ports.MessageRepository, stallAfter time.Duration, events *EventBus) *MessageTracker {
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if stallAfter <= 0 {
+		stallAfter = DefaultStallAfter
+	}
+	return &MessageTracker{
+		messages:   messages,
+		stallAfter: stallAfter,
+		events:     events,
+	}
+}
+
+// Track starts tracking a received message in its current state
+func (t *MessageTracker) Track(ctx context.Context, msg *domain.Message) error {
+	if err := t.messages.Save(ctx, msg); err != nil {
+		return fmt.Errorf("failed to track message: %w", err)
+	}
+	t.publish(domain.ProcessingEventReceived, msg, "")
+	return nil
+}
+
+// Hold saves the analysis of a message waiting in its thread for a reply, so it can be found again
+func (t *MessageTracker) Hold(ctx context.Context, msg *domain.Message) error {
+	if err := t.messages.Update(ctx, msg); err != nil {
+		return fmt.Errorf("failed to hold message %s: %w", msg.ID(), err)
+	}
+	return nil
+}
+
+// Find returns a tracked message
+func (t *MessageTracker) Find(ctx context.Context, id string) (*domain.Message, error) {
+	msg, err := t.messages.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message %s: %w", id, err)
+	}
+	return msg, nil
+}
+
+// Analyzed reports that the type and category of a message are known
+func (t *MessageTracker) Analyzed(msg *domain.Message) {
+	t.publish(domain.ProcessingEventAnalyzed, msg, "")
+}
+
+// Transition moves a tracked message to another state and persists it
+func (t *MessageTracker) Transition(ctx context.Context, msg *domain.Message, state domain.MessageState, reason string) error {
+	if err := t.transition(ctx, msg, state, reason); err != nil {
+		return err
+	}
+	switch state {
+	case domain.MessageStateDocumented:
+		t.publish(domain.ProcessingEventCommitted, msg, "")
+	case domain.MessageStateIgnored:
+		t.publish(domain.ProcessingEventIgnored, msg, "")
+	case domain.MessageStateFailed:
+		t.publish(domain.ProcessingEventFailed, msg, "")
+	}
+	return nil
+}
+
+// Document marks a tracked message documented in the document at path
+func (t *MessageTracker) Document(ctx context.Context, msg *domain.Message, path, reason string) error {
+	if err := t.transition(ctx, msg, domain.MessageStateDocumented, reason); err != nil {
+		return err
+	}
+	t.publish(domain.ProcessingEventCommitted, msg, path)
+	return nil
+}
+
+func (t *MessageTracker) transition(ctx context.Context, msg *domain.Message, state domain.MessageState, reason string) error {
+	if err := msg.TransitionTo(state, reason); err != nil {
+		return fmt.Errorf("failed to move message from %s to %s: %w", msg.State(), state, err)
+	}
+	if err := t.messages.Update(ctx, msg); err != nil {
+		return fmt.Errorf("failed to update message state: %w", err)
+	}
+	return nil
+}
+
+// Fail records a processing failure and returns the original error.
+// Messages that were already documented or ignored keep their state.
+func (t *MessageTracker) Fail(ctx context.Context, msg *domain.Message, cause error) error {
+	if msg.State().IsFinal() {
+		return cause
+	}
+	if err := t.Transition(ctx, msg, domain.MessageStateFailed, cause.Error()); err != nil {
+		return fmt.Errorf("%w (%v)", cause, err)
+	}
+	return cause
+}
+
+// Stats summarises the state of all tracked messages
+func (t *MessageTracker) Stats(ctx context.Context) (*domain.MessageStats, error) {
+	msgs, err := t.messages.FindByState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find messages: %w", err)
+	}
+	return domain.NewMessageStats(msgs, time.Now(), t.stallAfter), nil
+}
+
+// publish sends a processing event when there is an event bus
+func (t *MessageTracker) publish(kind domain.ProcessingEventKind, msg *domain.Message, path string) {
+	if t.events == nil {
+		return
+	}
+	t.events.Publish(domain.NewProcessingEvent(kind, msg, path, time.Now()))
+}
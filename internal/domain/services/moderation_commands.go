@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// maxHeldPreview limits how much of a held message is quoted when the queue is listed
+const maxHeldPreview = 80
+
+// RegisterModerationCommands registers the "moderation" command listing the messages held in a channel,
+// and approving or rejecting them
+func RegisterModerationCommands(commands *CommandService, moderation *ModerationService, bot *BotService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if moderation == nil {
+		panic("moderation service cannot be nil")
+	}
+	if bot == nil {
+		panic("bot service cannot be nil")
+	}
+
+	commands.Register("moderation", "moderation [approve|reject <message-id>]", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		if cmd.ArgCount() == 0 {
+			return listHeld(ctx, moderation, msg.ChannelID())
+		}
+		return decideHeld(ctx, moderation, bot, msg, cmd)
+	})
+}
+
+func listHeld(ctx context.Context, moderation *ModerationService, channelID string) (string, error) {
+	held, err := heldIn(ctx, moderation, channelID)
+	if err != nil {
+		return "", err
+	}
+	if len(held) == 0 {
+		return "No messages are waiting for moderation.", nil
+	}
+
+	var b strings.Builder
+	b.WriteString("⏸️ Messages waiting for moderation:\n")
+	for _, h := range held {
+		b.WriteString(fmt.Sprintf("- `%s` from %s, %s: %q\n",
+			h.Message().ID(), h.Message().Sender(), h.Reason(), heldPreview(h.Message().Content().Text())))
+	}
+	b.WriteString(fmt.Sprintf("Decide with `%s moderation approve|reject <message-id>`", domain.CommandPrefix))
+	return b.String(), nil
+}
+
+// decideHeld approves or rejects a message held in the channel the command was sent in
+func decideHeld(ctx context.Context, moderation *ModerationService, bot *BotService, msg *domain.Message, cmd *domain.Command) (string, error) {
+	action := strings.ToLower(cmd.Arg(0))
+	if cmd.ArgCount() != 2 || (action != "approve" && action != "reject") {
+		return "", fmt.Errorf("usage: `%s moderation [approve|reject <message-id>]`", domain.CommandPrefix)
+	}
+	messageID := cmd.Arg(1)
+
+	held, err := heldIn(ctx, moderation, msg.ChannelID())
+	if err != nil {
+		return "", err
+	}
+	found := false
+	for _, h := range held {
+		found = found || h.Message().ID().String() == messageID
+	}
+	if !found {
+		return "", fmt.Errorf("message %s is not waiting for moderation in this channel", messageID)
+	}
+
+	if action == "reject" {
+		if err := bot.RejectHeld(ctx, messageID, msg.Sender()); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("🚫 Rejected message %s, it will not be documented", messageID), nil
+	}
+	if err := bot.ApproveHeld(ctx, messageID, msg.Sender()); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("✅ Approved message %s", messageID), nil
+}
+
+// heldIn returns the messages held in a channel, so channels only see and decide on their own messages
+func heldIn(ctx context.Context, moderation *ModerationService, channelID string) ([]*domain.HeldMessage, error) {
+	held, err := moderation.Held(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var inChannel []*domain.HeldMessage
+	for _, h := range held {
+		if h.Message().ChannelID() == channelID {
+			inChannel = append(inChannel, h)
+		}
+	}
+	return inChannel, nil
+}
+
+// heldPreview shortens a message to a single line
+func heldPreview(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxHeldPreview {
+		return string(runes[:maxHeldPreview]) + "…"
+	}
+	return text
+}
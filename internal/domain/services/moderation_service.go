@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// ModerationService keeps offensive and off-topic messages out of the documentation of the projects that turn
+// moderation on. Messages are checked against the local rules of the policy and, when a moderator is configured,
+// rated by it. Borderline messages are held in a queue until people approve or reject them.
+type ModerationService struct {
+	moderator ports.ContentModerator
+	policy    domain.ModerationPolicy
+	queue     ports.ModerationQueue
+	audit     ports.AuditLog
+}
+
+// NewModerationService creates a new ModerationService. The moderator is optional, without it only the
+// local rules of the policy apply.
+func NewModerationService(
+	moderator ports.ContentModerator,
+	policy domain.ModerationPolicy,
+	queue ports.ModerationQueue,
+	audit ports.AuditLog,
+) *ModerationService {
+	if queue == nil {
+		panic("moderation queue cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	return &ModerationService{
+		moderator: moderator,
+		policy:    policy,
+		queue:     queue,
+		audit:     audit,
+	}
+}
+
+// Check decides whether a message may be documented, holding it in the queue when people should decide.
+// Projects keeping their content local are only checked against the local rules.
+func (s *ModerationService) Check(ctx context.Context, msg *domain.Message, localOnly bool) (domain.ModerationDecision, error) {
+	text := msg.Content().Text()
+
+	var result *domain.ModerationResult
+	if s.moderator != nil && checkResidency(msg.ChannelID(), localOnly, "moderation", s.moderator) == nil {
+		var err error
+		if result, err = s.moderator.Moderate(ctx, text); err != nil {
+			return domain.ModerationDecision{}, fmt.Errorf("failed to moderate message: %w", err)
+		}
+	}
+
+	decision := s.policy.Decide(text, result)
+	if decision.Verdict == domain.ModerationReview {
+		held, err := domain.NewHeldMessage(msg, decision.Reason, time.Now())
+		if err != nil {
+			return domain.ModerationDecision{}, err
+		}
+		if err := s.queue.Hold(ctx, held); err != nil {
+			return domain.ModerationDecision{}, fmt.Errorf("failed to hold message: %w", err)
+		}
+	}
+	if decision.Verdict != domain.ModerationAllow {
+		logf(ctx, "Moderation: %s message %s: %s", decision.Verdict, msg.ID(), decision.Reason)
+	}
+	return decision, nil
+}
+
+// Held returns the messages waiting for people to approve or reject them, oldest first
+func (s *ModerationService) Held(ctx context.Context) ([]*domain.HeldMessage, error) {
+	held, err := s.queue.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list held messages: %w", err)
+	}
+	return held, nil
+}
+
+// Approve releases a held message so it is documented, and audits who approved it
+func (s *ModerationService) Approve(ctx context.Context, messageID, actor string) (*domain.Message, error) {
+	return s.release(ctx, messageID, actor, "approved")
+}
+
+// Reject releases a held message so it is never documented, and audits who rejected it
+func (s *ModerationService) Reject(ctx context.Context, messageID, actor string) (*domain.Message, error) {
+	return s.release(ctx, messageID, actor, "rejected")
+}
+
+func (s *ModerationService) release(ctx context.Context, messageID, actor, outcome string) (*domain.Message, error) {
+	held, err := s.queue.Release(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := domain.NewAuditEntry(domain.AuditActionModerate, actor, messageID, "held", outcome)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to record moderation: %w", err)
+	}
+	return held.Message(), nil
+}
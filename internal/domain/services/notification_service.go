@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// NotificationService decides where the bot tells people about captured messages. Captures of the categories
+// a project routes to another channel are confirmed there, the others in their thread within the reply settings
+// of the project, and digests list each message in the channel its category is routed to.
+type NotificationService struct {
+	chat     ports.ChatAccessProvider
+	projects *ProjectService
+	replies  *ReplyThrottle
+}
+
+// NewNotificationService creates a NotificationService keeping the batched confirmations and those held during
+// quiet hours in the outbox, zero timeouts use the defaults. Run it alongside the bot so they are posted.
+func NewNotificationService(chat ports.ChatAccessProvider, projects *ProjectService, outbox ports.ReplyOutbox, timeouts StageTimeouts) *NotificationService {
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	if projects == nil {
+		panic("project service cannot be nil")
+	}
+	return &NotificationService{
+		chat:     chat,
+		projects: projects,
+		replies:  NewReplyThrottle(chat, projects, outbox, timeouts),
+	}
+}
+
+// Run posts the confirmation summaries of the batches that are due at each interval until ctx is canceled,
+// zero interval uses DefaultReplyFlushInterval
+func (s *NotificationService) Run(ctx context.Context, interval time.Duration) error {
+	return s.replies.Run(ctx, interval)
+}
+
+// Flush posts the confirmation summaries of the batches due by now, and returns how many it posted
+func (s *NotificationService) Flush(ctx context.Context, now time.Time) (int, error) {
+	return s.replies.Flush(ctx, now)
+}
+
+// Confirm tells people a message was captured
+func (s *NotificationService) Confirm(ctx context.Context, msg *domain.Message, reply string) error {
+	return s.ConfirmDocument(ctx, msg, reply, "")
+}
+
+// ConfirmDocument tells people a message was documented at the path, in the channel its category is routed to
+// or in its thread otherwise. Routed confirmations are dropped during the quiet hours of the project and once
+// the channel they are posted to reached the hourly limit.
+func (s *NotificationService) ConfirmDocument(ctx context.Context, msg *domain.Message, reply, path string) error {
+	cfg, err := s.projects.RepliesFor(ctx, msg.ChannelID())
+	if err != nil {
+		return err
+	}
+	if !cfg.Routes.Routes(msg) {
+		return s.replies.ConfirmDocument(ctx, msg, reply, path)
+	}
+
+	channelID := cfg.Routes.ChannelFor(msg.Category(), msg.ChannelID())
+	now := s.replies.now()
+	if cfg.QuietHours.Contains(now) || !s.replies.allow(channelID, cfg.MaxRepliesPerHour, now) {
+		return nil
+	}
+	if err := s.chat.SendMessage(ctx, channelID, domain.RenderRoutedConfirmation(msg, reply)); err != nil {
+		return fmt.Errorf("failed to confirm message %s in %s: %w", msg.ID(), channelID, err)
+	}
+	return nil
+}
+
+// Details returns the confirmation of a message that was acknowledged with a reaction
+func (s *NotificationService) Details(ctx context.Context, messageID string) (string, error) {
+	return s.replies.Details(ctx, messageID)
+}
+
+// DigestChannel returns the channel a digest lists a message in, the one its category is routed to by the
+// project of its channel or its own channel otherwise
+func (s *NotificationService) DigestChannel(ctx context.Context, msg *domain.Message) (string, error) {
+	cfg, err := s.projects.RepliesFor(ctx, msg.ChannelID())
+	if err != nil {
+		return "", err
+	}
+	return cfg.Routes.ChannelFor(msg.Category(), msg.ChannelID()), nil
+}
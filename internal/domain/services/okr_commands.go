@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+	"time"
+)
+
+// RegisterOKRCommands registers the "okrs" command that publishes the OKR progress page of the channel's project,
+// for the current quarter or the one given like 2024-Q3
+func RegisterOKRCommands(commands *CommandService, okrs *OKRService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if okrs == nil {
+		panic("OKR service cannot be nil")
+	}
+
+	commands.Register("okrs", "okrs [<quarter>]", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		quarter := domain.QuarterOf(time.Now())
+		if cmd.ArgCount() > 0 {
+			quarter = strings.ToUpper(cmd.Arg(0))
+		}
+		project, report, path, err := okrs.PublishFor(ctx, msg, quarter)
+		if err != nil {
+			return "", err
+		}
+		return formatOKRProgress(report, project.ObjectivesOf(quarter), okrs.docs.DocumentLink(ctx, path)), nil
+	})
+}
+
+// formatOKRProgress renders the progress of the objectives of a quarter for a chat reply
+func formatOKRProgress(report *domain.OKRReport, objectives []domain.Objective, link string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🎯 *%s*, the page is in %s", report.Title(), link))
+	if len(objectives) == 0 {
+		b.WriteString("\nNo objectives are set for this quarter.")
+	}
+	for _, objective := range objectives {
+		b.WriteString(fmt.Sprintf("\n• %s: %s done", objective.Title, domain.FormatProgress(report.Progress(objective))))
+	}
+	return b.String()
+}
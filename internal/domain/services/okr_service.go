@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// OKRService tracks the objectives and key results of projects: documented status updates mentioning a key
+// result are recorded as its progress, and each quarter has a progress page under docs/okrs/
+type OKRService struct {
+	updates ports.KeyResultUpdateStore
+	docs    *DocumentationService
+}
+
+// NewOKRService creates an OKRService
+func NewOKRService(updates ports.KeyResultUpdateStore, docs *DocumentationService) *OKRService {
+	if updates == nil {
+		panic("key result update store cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	return &OKRService{
+		updates: updates,
+		docs:    docs,
+	}
+}
+
+// Record attaches a status update documented at path to the key results of its project it reports on, and
+// refreshes the progress page of the quarter. It returns the key results the update reported on, none when
+// the project has no objectives for the quarter of the update or it mentions none of their key results.
+func (s *OKRService) Record(ctx context.Context, msg *domain.Message, path string) ([]domain.KeyResultMention, error) {
+	project, err := s.docs.messageProject(ctx, msg)
+	if err != nil || project == nil {
+		return nil, err
+	}
+	quarter := domain.QuarterOf(msg.Timestamp())
+	objectives := project.ObjectivesOf(quarter)
+	if len(objectives) == 0 {
+		return nil, nil
+	}
+
+	mentions := domain.MatchKeyResults(msg.Content().Text(), domain.TagStrings(msg.Tags()), objectives)
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+	for _, mention := range mentions {
+		update := domain.KeyResultUpdate{
+			ProjectID:   project.ID(),
+			Quarter:     quarter,
+			KeyResultID: mention.KeyResultID,
+			Value:       mention.Value,
+			Measured:    mention.Measured,
+			MessageID:   msg.ID().String(),
+			Sender:      msg.Sender(),
+			Path:        path,
+			At:          msg.Timestamp(),
+		}
+		if err := s.updates.Record(ctx, update); err != nil {
+			return nil, fmt.Errorf("failed to record the update on key result %s: %w", mention.KeyResultID, err)
+		}
+	}
+
+	if _, _, err := s.Publish(ctx, project, quarter); err != nil {
+		return mentions, err
+	}
+	return mentions, nil
+}
+
+// PublishFor publishes the progress page of a quarter of the project bound to the channel of a message
+func (s *OKRService) PublishFor(ctx context.Context, msg *domain.Message, quarter string) (*domain.Project, *domain.OKRReport, string, error) {
+	project, err := s.docs.messageProject(ctx, msg)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if project == nil {
+		return nil, nil, "", fmt.Errorf("this channel is not bound to a project, its objectives are set in the project's configuration")
+	}
+	report, path, err := s.Publish(ctx, project, quarter)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return project, report, path, nil
+}
+
+// Publish renders the progress page of a project's objectives of a quarter and stores it, replacing the
+// earlier one. It returns the page and where it is stored.
+func (s *OKRService) Publish(ctx context.Context, project *domain.Project, quarter string) (*domain.OKRReport, string, error) {
+	if err := domain.ValidateQuarter(quarter); err != nil {
+		return nil, "", err
+	}
+	updates, err := s.updates.List(ctx, project.ID(), quarter)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list the key result updates of %s: %w", quarter, err)
+	}
+
+	report := domain.NewOKRReport(project.Name(), quarter, project.ObjectivesOf(quarter), updates)
+	path, err := s.docs.storeOKRReport(ctx, project, report)
+	if err != nil {
+		return nil, "", err
+	}
+	return report, path, nil
+}
+
+// storeOKRReport stores the progress page of a quarter in the store of its project and indexes it as a status
+// document. The page of a quarter is replaced each time it is stored.
+func (s *DocumentationService) storeOKRReport(ctx context.Context, project *domain.Project, report *domain.OKRReport) (string, error) {
+	docConfig := project.Documentation()
+	store, err := s.stores.ForProject(project)
+	if err != nil {
+		return "", err
+	}
+	path := report.Path()
+	page := report.Render()
+	now := time.Now().UTC()
+
+	fm := domain.NewFrontMatter()
+	report.Record(fm, project.ID(), now)
+	content := fm.Apply(page)
+
+	exists, err := documentExists(ctx, store, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up OKR page: %w", err)
+	}
+	if exists {
+		if content, err = s.sign(content); err != nil {
+			return "", err
+		}
+		metadata := map[string]interface{}{
+			"type":       "okr",
+			"category":   domain.CategoryOther.String(),
+			"updated_at": now,
+		}
+		if err := store.UpdateDocument(ctx, path, []byte(content), metadata); err != nil {
+			return "", fmt.Errorf("failed to update OKR page: %w", err)
+		}
+		s.touchIndexed(ctx, path)
+		return path, nil
+	}
+
+	entry, err := domain.NewIndexedDocument(
+		path,
+		report.Title(),
+		domain.SummaryFromMarkdown(page),
+		domain.MessageTypeStatus,
+		domain.CategoryOther,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create index entry: %w", err)
+	}
+	entry.SetTags(domain.NewTags([]string{"okr"}))
+	entry.SetLocation(docConfig.Repository, docConfig.Branch)
+	entry.SetProject(project.ID())
+	if err := s.index.Index(ctx, entry); err != nil {
+		return "", fmt.Errorf("failed to index OKR page: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"type":     "okr",
+		"category": domain.CategoryOther.String(),
+	}
+	if err := s.storeWithContents(ctx, store, docConfig, path, content, metadata, domain.CategoryOther, nil); err != nil {
+		// Keep the index in line with the store, the page was not written
+		_ = s.index.Remove(ctx, path)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// RegisterOwnershipCommands registers the "owner" command showing who owns a document or handing it to someone
+// else, and the "reviews" command listing the documents the sender owns that wait for review
+func RegisterOwnershipCommands(commands *CommandService, reviews *DocumentReviewService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if reviews == nil {
+		panic("document review service cannot be nil")
+	}
+
+	commands.Register("owner", "owner <path> [user]", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		path := cmd.Arg(0)
+		if path == "" {
+			return "", fmt.Errorf("usage: `%s owner <path> [user]`", domain.CommandPrefix)
+		}
+		if cmd.ArgCount() < 2 {
+			entry, err := reviews.index.FindByPath(ctx, path)
+			if err != nil {
+				return "", err
+			}
+			if entry.Owner() == "" {
+				return fmt.Sprintf("Nobody owns %s yet.", path), nil
+			}
+			return fmt.Sprintf("👤 %s is owned by %s", path, entry.Owner()), nil
+		}
+
+		owner := strings.TrimPrefix(cmd.Arg(1), "@")
+		previous, err := reviews.Reassign(ctx, path, owner, msg.Sender())
+		if err != nil {
+			return "", err
+		}
+		if previous == "" || previous == owner {
+			return fmt.Sprintf("👤 %s is now owned by %s", path, owner), nil
+		}
+		return fmt.Sprintf("👤 %s is now owned by %s (was %s)", path, owner, previous), nil
+	})
+
+	commands.Register("reviews", "reviews", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		owned, err := reviews.OwnedReviews(ctx, msg.Sender())
+		if err != nil {
+			return "", err
+		}
+		if len(owned) == 0 {
+			return "✅ None of your documents wait for review.", nil
+		}
+		return domain.RenderOwnedReviews(msg.Sender(), owned, func(path string) string {
+			return reviews.docs.DocumentLink(ctx, path)
+		}), nil
+	})
+}
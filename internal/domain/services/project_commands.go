@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"strings"
+)
+
+// RegisterProjectCommands registers the "project" command backed by the project service.
+// Projects can be edited when the chat provider implements ports.ProjectEditor, and created from a template in
+// the onboarding wizard when it implements ports.ProjectOnboarding.
+func RegisterProjectCommands(commands *CommandService, projects *ProjectService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if projects == nil {
+		panic("project service cannot be nil")
+	}
+
+	editor, _ := commands.chatProvider.(ports.ProjectEditor)
+	if editor != nil {
+		editor.OnProjectEdit(projects.ApplyEdit)
+	}
+	onboarding, _ := commands.chatProvider.(ports.ProjectOnboarding)
+	if onboarding != nil {
+		onboarding.OnProjectCreate(projects.CreateFromDraft)
+	}
+
+	commands.Register("project", "project new|templates | project status|edit|pause|resume|archive [project-id]", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return handleProjectCommand(ctx, projects, editor, onboarding, msg, cmd)
+	})
+}
+
+func handleProjectCommand(ctx context.Context, projects *ProjectService, editor ports.ProjectEditor, onboarding ports.ProjectOnboarding, msg *domain.Message, cmd *domain.Command) (string, error) {
+	action := strings.ToLower(cmd.Arg(0))
+
+	switch action {
+	case "templates":
+		return formatProjectTemplates(domain.ProjectTemplates()), nil
+	case "new":
+		return "", openProjectWizard(ctx, projects, onboarding, msg)
+	}
+
+	project, err := commandProject(ctx, projects, msg, cmd.Arg(1))
+	if err != nil {
+		return "", err
+	}
+
+	switch action {
+	case "status", "":
+		return formatProjectStatus(project), nil
+	case "edit":
+		return "", editProject(ctx, editor, msg, project)
+	case "pause":
+		project, err = projects.PauseProject(ctx, project.ID())
+	case "resume":
+		project, err = projects.ResumeProject(ctx, project.ID())
+	case "archive":
+		project, err = projects.ArchiveProject(ctx, project.ID())
+	default:
+		return "", fmt.Errorf("unknown project action %q", action)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("✅ %s", formatProjectStatus(project)), nil
+}
+
+// editProject offers the pre-filled project form, the chat provider posts it so there is no reply
+func editProject(ctx context.Context, editor ports.ProjectEditor, msg *domain.Message, project *domain.Project) error {
+	if editor == nil {
+		return fmt.Errorf("this chat does not support editing projects")
+	}
+	if project.IsReadOnly() {
+		return fmt.Errorf("project *%s* is archived, resume it before editing", project.Name())
+	}
+	return editor.OpenProjectEditor(ctx, msg.ID().String(), project)
+}
+
+// openProjectWizard offers the onboarding wizard, the chat provider posts it so there is no reply. Channels
+// bound to a project already are not offered it, a channel documents for one project.
+func openProjectWizard(ctx context.Context, projects *ProjectService, onboarding ports.ProjectOnboarding, msg *domain.Message) error {
+	if msg.ChannelID() != "" {
+		if project, err := projects.FindByChannel(ctx, msg.ChannelID()); err == nil && project != nil {
+			return fmt.Errorf("this channel is bound to project *%s* already", project.Name())
+		}
+	}
+	if onboarding == nil {
+		return fmt.Errorf("this chat does not support creating projects")
+	}
+	return onboarding.OpenProjectWizard(ctx, msg.ID().String(), domain.ProjectTemplates())
+}
+
+// commandProject returns the project named by ID, or the project bound to the message's channel
+func commandProject(ctx context.Context, projects *ProjectService, msg *domain.Message, rawID string) (*domain.Project, error) {
+	if rawID != "" {
+		id, err := common.NewID(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project ID %q", rawID)
+		}
+		return projects.GetProject(ctx, id)
+	}
+
+	if msg.ChannelID() == "" {
+		return nil, fmt.Errorf("no project given and this channel is unknown")
+	}
+	project, err := projects.FindByChannel(ctx, msg.ChannelID())
+	if err != nil {
+		return nil, fmt.Errorf("no project is bound to this channel, pass a project ID")
+	}
+	return project, nil
+}
+
+func formatProjectStatus(project *domain.Project) string {
+	status := fmt.Sprintf("Project *%s* is %s", project.Name(), project.Status())
+	if project.Status().IsArchived() {
+		status += fmt.Sprintf(" since %s; its documentation is read-only", project.ArchivedAt().UTC().Format("2006-01-02"))
+	}
+	return status
+}
+
+func formatProjectTemplates(templates []domain.ProjectTemplate) string {
+	var b strings.Builder
+	b.WriteString("🧩 Project templates, pick one with `" + domain.CommandPrefix + " project new`:\n")
+	for _, template := range templates {
+		b.WriteString(fmt.Sprintf("- *%s*: %s\n", template.Name, template.Description))
+	}
+	return b.String()
+}
@@ -2,86 +2,349 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/massimo-ua/quill/internal/domain"
 	"github.com/massimo-ua/quill/internal/domain/common"
 	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"strings"
 )
 
 type ProjectService struct {
-	docStore    ports.DocumentStoreProvider
+	stores      *DocStoreResolver
 	projectRepo ports.ProjectRepository
 }
 
-func NewProjectService(docs ports.DocumentStoreProvider, repo ports.ProjectRepository) *ProjectService {
+func NewProjectService(stores *DocStoreResolver, repo ports.ProjectRepository) *ProjectService {
 	return &ProjectService{
-		docStore:    docs,
+		stores:      stores,
 		projectRepo: repo,
 	}
 }
 
 func (s *ProjectService) CreateProject(ctx context.Context, metadata *domain.ProjectMetadata) error {
+	_, err := s.CreateConfiguredProject(ctx, metadata, domain.DefaultDocumentationConfig())
+	return err
+}
+
+// CreateConfiguredProject creates a project with documentation settings and writes its README.
+// An empty documentation repository is scaffolded in the same commit when the store supports it.
+func (s *ProjectService) CreateConfiguredProject(ctx context.Context, metadata *domain.ProjectMetadata, docConfig domain.DocumentationConfig) (*domain.Project, error) {
+	return s.createProject(ctx, metadata, func(project *domain.Project) error {
+		return project.ConfigureDocumentation(docConfig)
+	})
+}
+
+// CreateFromTemplate creates a project with the settings of a template and writes its README
+func (s *ProjectService) CreateFromTemplate(ctx context.Context, metadata *domain.ProjectMetadata, template domain.ProjectTemplate) (*domain.Project, error) {
+	return s.createProject(ctx, metadata, template.Apply)
+}
+
+// CreateFromDraft creates the project submitted in the onboarding wizard from its template, bound to the
+// channel the wizard was started in
+func (s *ProjectService) CreateFromDraft(ctx context.Context, draft *domain.ProjectDraft) (*domain.Project, error) {
+	project, err := s.createProject(ctx, draft.Metadata(), func(project *domain.Project) error {
+		if err := draft.Template().Apply(project); err != nil {
+			return err
+		}
+		if draft.ChannelID() == "" {
+			return nil
+		}
+		return project.BindChannel(draft.ChannelID())
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("%s created project %s from the %s template", draft.Creator(), project.Name(), draft.Template().Name)
+	return project, nil
+}
+
+// createProject creates a project configured by a function and writes its README.
+// An empty documentation repository is scaffolded in the same commit when the store supports it.
+func (s *ProjectService) createProject(ctx context.Context, metadata *domain.ProjectMetadata, configure func(project *domain.Project) error) (*domain.Project, error) {
 	project, err := domain.NewProject(metadata.Name, metadata.Description, metadata.BusinessGoals)
 	if err != nil {
-		return fmt.Errorf("failed to create project: %w", err)
+		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
 
 	// Add KPIs and dates from metadata
 	for _, kpi := range metadata.KPIs {
-		project.AddKPI(kpi)
+		if err := project.AddKPI(kpi); err != nil {
+			return nil, fmt.Errorf("failed to create project: %w", err)
+		}
+	}
+	if err := configure(project); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
 
 	if err := s.projectRepo.Save(ctx, project); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil, fmt.Errorf("failed to save project: %w", err)
 	}
 
-	// Generate project documentation content
-	docContent := fmt.Sprintf("# %s\n\n## Description\n%s\n\n## Business Goals\n",
-		project.Name(), project.Description())
+	store, err := s.stores.ForProject(project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store project documentation: %w", err)
+	}
 
-	for _, goal := range project.Goals() {
-		docContent += fmt.Sprintf("* %s\n", goal)
+	bootstrapped, err := s.bootstrapRepository(ctx, store, project)
+	if err != nil {
+		return nil, err
+	}
+	if bootstrapped {
+		return project, nil
 	}
 
-	docContent += "\n## KPIs\n"
-	for _, kpi := range project.KPIs() {
-		docContent += fmt.Sprintf("* %s\n", kpi)
+	if err := store.StoreDocument(ctx, projectDocPath(project), []byte(renderProjectDocument(project)), nil); err != nil {
+		return nil, fmt.Errorf("failed to store project documentation: %w", err)
 	}
 
-	docPath := fmt.Sprintf("projects/%s/README.md", project.ID())
-	if err := s.docStore.StoreDocument(ctx, docPath, []byte(docContent), nil); err != nil {
-		return fmt.Errorf("failed to store project documentation: %w", err)
+	return project, nil
+}
+
+// bootstrapRepository scaffolds the documentation layout when the project's repository is empty
+func (s *ProjectService) bootstrapRepository(ctx context.Context, store ports.DocumentStoreProvider, project *domain.Project) (bool, error) {
+	bootstrapper, ok := store.(ports.RepositoryBootstrapper)
+	if !ok {
+		return false, nil
 	}
 
-	return nil
+	needed, err := bootstrapper.NeedsBootstrap(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect documentation repository: %w", err)
+	}
+	if !needed {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("Set up documentation for %s", project.Name())
+	if err := bootstrapper.Bootstrap(ctx, repositoryScaffold(project), message); err != nil {
+		return false, fmt.Errorf("failed to set up documentation repository: %w", err)
+	}
+	return true, nil
 }
 
 func (s *ProjectService) GetProject(ctx context.Context, id common.ID) (*domain.Project, error) {
 	return s.projectRepo.FindByID(ctx, id)
 }
 
+// FindByChannel returns the project bound to a chat channel
+func (s *ProjectService) FindByChannel(ctx context.Context, channelID string) (*domain.Project, error) {
+	return s.projectRepo.FindByChannel(ctx, channelID)
+}
+
 func (s *ProjectService) UpdateProject(ctx context.Context, project *domain.Project) error {
+	// Archived projects keep their documentation as it was when they were archived
+	if project.IsReadOnly() {
+		return fmt.Errorf("failed to update project: %w", domain.ErrProjectArchived)
+	}
+
 	if err := s.projectRepo.Update(ctx, project); err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
 
-	// Update documentation with current project state
-	docContent := fmt.Sprintf("# %s\n\n## Description\n%s\n\n## Business Goals\n",
-		project.Name(), project.Description())
+	return s.updateProjectDocument(ctx, project)
+}
 
-	for _, goal := range project.Goals() {
-		docContent += fmt.Sprintf("* %s\n", goal)
+// ApplyEdit applies submitted metadata changes and regenerates the project documentation
+func (s *ProjectService) ApplyEdit(ctx context.Context, edit *domain.ProjectEdit) (*domain.Project, error) {
+	project, err := s.projectRepo.FindByID(ctx, edit.ProjectID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
 	}
 
-	docContent += "\n## KPIs\n"
-	for _, kpi := range project.KPIs() {
-		docContent += fmt.Sprintf("* %s\n", kpi)
+	if err := project.UpdateDescription(edit.Description()); err != nil {
+		return nil, fmt.Errorf("failed to update project description: %w", err)
+	}
+	if err := project.UpdateGoals(edit.Goals()); err != nil {
+		return nil, fmt.Errorf("failed to update project goals: %w", err)
+	}
+	for _, kpi := range edit.KPIs() {
+		if containsString(project.KPIs(), kpi) {
+			continue
+		}
+		if err := project.AddKPI(kpi); err != nil {
+			return nil, fmt.Errorf("failed to add project KPI: %w", err)
+		}
 	}
 
-	docPath := fmt.Sprintf("projects/%s/README.md", project.ID())
-	if err := s.docStore.UpdateDocument(ctx, docPath, []byte(docContent), nil); err != nil {
-		return fmt.Errorf("failed to update project documentation: %w", err)
+	if err := s.UpdateProject(ctx, project); err != nil {
+		return nil, err
 	}
+	return project, nil
+}
 
+// BindChannel binds a chat channel to a project so its messages are documented for it
+func (s *ProjectService) BindChannel(ctx context.Context, id common.ID, channelID string) error {
+	project, err := s.projectRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	if err := project.BindChannel(channelID); err != nil {
+		return fmt.Errorf("failed to bind channel: %w", err)
+	}
+
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
 	return nil
 }
+
+// ChangeStatus moves a project to another lifecycle status and records it in the project documentation
+func (s *ProjectService) ChangeStatus(ctx context.Context, id common.ID, status domain.ProjectStatus) (*domain.Project, error) {
+	project, err := s.projectRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+
+	if err := project.TransitionTo(status); err != nil {
+		return nil, fmt.Errorf("failed to change project status from %s to %s: %w", project.Status(), status, err)
+	}
+
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	// This is the last write before an archived project becomes read-only
+	if err := s.updateProjectDocument(ctx, project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// ArchiveProject stops documenting a project's channels and makes its documentation read-only
+func (s *ProjectService) ArchiveProject(ctx context.Context, id common.ID) (*domain.Project, error) {
+	return s.ChangeStatus(ctx, id, domain.ProjectStatusArchived)
+}
+
+// PauseProject stops documenting a project's channels until it is resumed
+func (s *ProjectService) PauseProject(ctx context.Context, id common.ID) (*domain.Project, error) {
+	return s.ChangeStatus(ctx, id, domain.ProjectStatusPaused)
+}
+
+// ResumeProject restarts documenting a paused or archived project's channels
+func (s *ProjectService) ResumeProject(ctx context.Context, id common.ID) (*domain.Project, error) {
+	return s.ChangeStatus(ctx, id, domain.ProjectStatusActive)
+}
+
+// AcceptsMessagesFrom checks if messages posted in a channel should be processed.
+// Channels that are not bound to any project are always processed.
+func (s *ProjectService) AcceptsMessagesFrom(ctx context.Context, channelID string) (bool, error) {
+	project, err := s.channelProject(ctx, channelID)
+	if err != nil {
+		return false, err
+	}
+	if project == nil {
+		return true, nil
+	}
+	return project.AcceptsMessages(), nil
+}
+
+// AutoDetectionFor returns the auto detection settings of the project bound to a channel.
+// Channels without a project use the default settings.
+func (s *ProjectService) AutoDetectionFor(ctx context.Context, channelID string) (domain.AutoDetectionConfig, error) {
+	project, err := s.channelProject(ctx, channelID)
+	if err != nil {
+		return domain.AutoDetectionConfig{}, err
+	}
+	if project == nil {
+		return domain.DefaultAutoDetectionConfig(), nil
+	}
+	return project.AutoDetection(), nil
+}
+
+// DocumentationFor returns the documentation settings of the project bound to a channel.
+// Channels without a project use the default settings.
+func (s *ProjectService) DocumentationFor(ctx context.Context, channelID string) (domain.DocumentationConfig, error) {
+	project, err := s.channelProject(ctx, channelID)
+	if err != nil {
+		return domain.DocumentationConfig{}, err
+	}
+	if project == nil {
+		return domain.DefaultDocumentationConfig(), nil
+	}
+	return project.Documentation(), nil
+}
+
+// RepliesFor returns the reply settings of the project bound to a channel.
+// Channels without a project use the default settings.
+func (s *ProjectService) RepliesFor(ctx context.Context, channelID string) (domain.ReplyConfig, error) {
+	project, err := s.channelProject(ctx, channelID)
+	if err != nil {
+		return domain.ReplyConfig{}, err
+	}
+	if project == nil {
+		return domain.DefaultReplyConfig(), nil
+	}
+	return project.Replies(), nil
+}
+
+// channelProject returns the project bound to a channel, or nil when the channel has none
+func (s *ProjectService) channelProject(ctx context.Context, channelID string) (*domain.Project, error) {
+	if channelID == "" {
+		return nil, nil
+	}
+
+	project, err := s.projectRepo.FindByChannel(ctx, channelID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project for channel: %w", err)
+	}
+	return project, nil
+}
+
+func (s *ProjectService) updateProjectDocument(ctx context.Context, project *domain.Project) error {
+	store, err := s.stores.ForProject(project)
+	if err != nil {
+		return fmt.Errorf("failed to update project documentation: %w", err)
+	}
+	if err := store.UpdateDocument(ctx, projectDocPath(project), []byte(renderProjectDocument(project)), nil); err != nil {
+		return fmt.Errorf("failed to update project documentation: %w", err)
+	}
+	return nil
+}
+
+// projectDocPath returns the path of a project's README
+func projectDocPath(project *domain.Project) string {
+	return project.DocumentationPath() + "/README.md"
+}
+
+// renderProjectDocument generates the project README from the current project state
+func renderProjectDocument(project *domain.Project) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n\n", project.Name()))
+
+	if !project.Status().IsActive() {
+		b.WriteString(fmt.Sprintf("> **Status:** %s", project.Status()))
+		if project.Status().IsArchived() {
+			b.WriteString(fmt.Sprintf(" since %s. This project is read-only.", project.ArchivedAt().UTC().Format("2006-01-02")))
+		}
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(fmt.Sprintf("## Description\n%s\n\n## Business Goals\n", project.Description()))
+
+	for _, goal := range project.Goals() {
+		b.WriteString(fmt.Sprintf("* %s\n", goal))
+	}
+
+	b.WriteString("\n## KPIs\n")
+	for _, kpi := range project.KPIs() {
+		b.WriteString(fmt.Sprintf("* %s\n", kpi))
+	}
+
+	return b.String()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+)
+
+// QueueAdminService lets operators see how the message queue and its workers are doing, and retry or discard
+// the messages the workers failed on, without access to the queue or the database behind it
+type QueueAdminService struct {
+	queue       ports.MessageQueue
+	deadLetters ports.DeadLetterQueue
+	audit       ports.AuditLog
+	workers     *QueueWorkers
+}
+
+// NewQueueAdminService creates a new QueueAdminService.
+// The worker pool is optional, without it the status lists no workers, like on instances that only ingest.
+func NewQueueAdminService(queue ports.MessageQueue, deadLetters ports.DeadLetterQueue, audit ports.AuditLog, workers *QueueWorkers) *QueueAdminService {
+	if queue == nil {
+		panic("message queue cannot be nil")
+	}
+	if deadLetters == nil {
+		panic("dead-letter queue cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	return &QueueAdminService{
+		queue:       queue,
+		deadLetters: deadLetters,
+		audit:       audit,
+		workers:     workers,
+	}
+}
+
+// Status reports the depth of the queue when it can tell, the number of dead letters and what the workers do
+func (s *QueueAdminService) Status(ctx context.Context) (*domain.QueueStatus, error) {
+	status := &domain.QueueStatus{}
+	if inspector, ok := s.queue.(ports.QueueInspector); ok {
+		depth, err := inspector.Depth(ctx)
+		if err != nil {
+			return nil, err
+		}
+		status.Depth, status.DepthKnown = depth, true
+	}
+
+	letters, err := s.deadLetters.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	status.DeadLetters = len(letters)
+
+	if s.workers != nil {
+		status.Workers = s.workers.Status()
+	}
+	return status, nil
+}
+
+// DeadLetters lists the messages the workers failed on, the oldest failure first
+func (s *QueueAdminService) DeadLetters(ctx context.Context) ([]*domain.DeadLetter, error) {
+	letters, err := s.deadLetters.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// DeadLetter returns the dead letter of a message, ports.ErrNotFound when it has none
+func (s *QueueAdminService) DeadLetter(ctx context.Context, messageID string) (*domain.DeadLetter, error) {
+	return s.deadLetters.Get(ctx, messageID)
+}
+
+// Retry queues a dead-lettered message again. If it fails again it comes back with one more attempt.
+func (s *QueueAdminService) Retry(ctx context.Context, messageID, actor string) (*domain.DeadLetter, error) {
+	letter, err := s.deadLetters.Get(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	// The dead letter is removed first, a worker failing on the message again may set it aside before Publish
+	// returns. It is put back when the message cannot be queued.
+	if err := s.deadLetters.Remove(ctx, messageID); err != nil {
+		return nil, fmt.Errorf("failed to remove dead letter %s: %w", messageID, err)
+	}
+	if err := s.queue.Publish(ctx, letter.Queued().Retry()); err != nil {
+		if addErr := s.deadLetters.Add(ctx, letter); addErr != nil {
+			log.Printf("Failed to put dead letter %s back: %v", messageID, addErr)
+		}
+		return nil, fmt.Errorf("failed to queue message %s again: %w", messageID, err)
+	}
+	s.record(ctx, domain.AuditActionRetry, actor, letter, fmt.Sprintf("attempt %d", letter.Attempts()+1))
+	return letter, nil
+}
+
+// Discard drops a dead-lettered message for good, its chat message is not documented
+func (s *QueueAdminService) Discard(ctx context.Context, messageID, actor string) (*domain.DeadLetter, error) {
+	letter, err := s.deadLetters.Get(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deadLetters.Remove(ctx, messageID); err != nil {
+		return nil, fmt.Errorf("failed to remove dead letter %s: %w", messageID, err)
+	}
+	s.record(ctx, domain.AuditActionDiscard, actor, letter, "discarded")
+	return letter, nil
+}
+
+// record audits what an operator did with a dead letter. The action is done by then, so failures are only logged.
+func (s *QueueAdminService) record(ctx context.Context, action domain.AuditAction, actor string, letter *domain.DeadLetter, to string) {
+	entry, err := domain.NewAuditEntry(action, actor, letter.ID(), letter.Error(), to)
+	if err == nil {
+		entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+		err = s.audit.Record(ctx, entry)
+	}
+	if err != nil {
+		log.Printf("Failed to audit %s of dead letter %s by %s: %v", action, letter.ID(), actor, err)
+	}
+}
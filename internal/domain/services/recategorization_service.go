@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// RecategorizationService files documents under the category people pick for them.
+// Every change is recorded in the audit log and, when a correction store is given, the
+// overridden category of the source message is added to the dataset of corrections.
+type RecategorizationService struct {
+	docs        *DocumentationService
+	messages    ports.MessageRepository
+	audit       ports.AuditLog
+	corrections ports.CorrectionStore
+}
+
+// NewRecategorizationService creates a RecategorizationService, the correction store is optional
+func NewRecategorizationService(
+	docs *DocumentationService,
+	messages ports.MessageRepository,
+	audit ports.AuditLog,
+	corrections ports.CorrectionStore,
+) *RecategorizationService {
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	return &RecategorizationService{
+		docs:        docs,
+		messages:    messages,
+		audit:       audit,
+		corrections: corrections,
+	}
+}
+
+// RegisterRecategorization lets people pick the category of documents in chat.
+// It does nothing when the chat provider does not implement ports.CategoryPicker.
+func RegisterRecategorization(chat ports.ChatAccessProvider, service *RecategorizationService) {
+	if service == nil {
+		panic("recategorization service cannot be nil")
+	}
+	if picker, ok := chat.(ports.CategoryPicker); ok {
+		picker.OnRecategorize(service.Recategorize)
+	}
+}
+
+// Recategorize files a document under the picked category and returns its new path
+func (s *RecategorizationService) Recategorize(ctx context.Context, change *domain.Recategorization) (string, error) {
+	if change == nil {
+		return "", fmt.Errorf("recategorization cannot be nil")
+	}
+
+	from, path, err := s.docs.MoveToCategory(ctx, change.Path(), change.Category())
+	if err != nil {
+		return "", err
+	}
+	if from == change.Category() {
+		return path, nil
+	}
+
+	entry, err := domain.NewAuditEntry(domain.AuditActionRecategorize, change.Actor(), change.Path(), from.String(), change.Category().String())
+	if err != nil {
+		return "", fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return "", fmt.Errorf("failed to record recategorization: %w", err)
+	}
+
+	msg, err := s.sourceMessage(ctx, change.MessageID())
+	if err != nil || msg == nil {
+		return path, err
+	}
+
+	msg.UpdateCategory(change.Category())
+	if err := s.messages.Update(ctx, msg); err != nil {
+		return "", fmt.Errorf("failed to update message category: %w", err)
+	}
+
+	if s.corrections == nil {
+		return path, nil
+	}
+	correction, err := domain.NewCorrection(msg, domain.CorrectionFieldCategory, from.String(), change.Category().String(), change.Actor())
+	if err != nil {
+		return "", fmt.Errorf("failed to create correction: %w", err)
+	}
+	if err := s.corrections.Record(ctx, correction); err != nil {
+		return "", fmt.Errorf("failed to record correction: %w", err)
+	}
+	return path, nil
+}
+
+// sourceMessage returns the message a document was generated from, or nil when it is not stored
+func (s *RecategorizationService) sourceMessage(ctx context.Context, messageID string) (*domain.Message, error) {
+	if messageID == "" {
+		return nil, nil
+	}
+
+	msg, err := s.messages.FindByID(ctx, messageID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message: %w", err)
+	}
+	return msg, nil
+}
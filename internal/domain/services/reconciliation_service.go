@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultReconcileInterval is how often the document stores are checked for documents edited outside the bot
+const DefaultReconcileInterval = time.Hour
+
+// ReconciliationService keeps the document index and the reference graph in sync with the document stores.
+// Documents people wrote or edited in the repository are read from their front matter, so search and
+// questions find them like the ones the bot generated.
+type ReconciliationService struct {
+	stores   *DocStoreResolver
+	index    ports.DocumentIndex
+	graph    *ReferenceGraphService
+	projects ports.ProjectRepository
+	aiAgent  ports.AiAgentProvider
+	chat     ports.ChatAccessProvider
+	mu       sync.Mutex
+	synced   map[string][sha256.Size]byte
+}
+
+// reconcileTarget is a document store with the project its documents belong to, none for the default store
+type reconcileTarget struct {
+	store   ports.DocumentStoreProvider
+	project *domain.Project
+	config  domain.DocumentationConfig
+}
+
+// NewReconciliationService creates a ReconciliationService. Without projects only the default store is
+// reconciled, without an AI agent providing embeddings documents are indexed for title search only, and
+// without a chat provider nobody is told about the documents people changed.
+func NewReconciliationService(
+	stores *DocStoreResolver,
+	index ports.DocumentIndex,
+	graph *ReferenceGraphService,
+	projects ports.ProjectRepository,
+	aiAgent ports.AiAgentProvider,
+	chat ports.ChatAccessProvider,
+) *ReconciliationService {
+	if stores == nil {
+		panic("docStore resolver cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if graph == nil {
+		panic("reference graph service cannot be nil")
+	}
+	return &ReconciliationService{
+		stores:   stores,
+		index:    index,
+		graph:    graph,
+		projects: projects,
+		aiAgent:  aiAgent,
+		chat:     chat,
+		synced:   make(map[string][sha256.Size]byte),
+	}
+}
+
+// Run reconciles the document stores at each interval until ctx is canceled.
+// Zero interval uses DefaultReconcileInterval. Failed runs are logged and retried at the next interval.
+func (s *ReconciliationService) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultReconcileInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			synced, err := s.Reconcile(ctx)
+			if err != nil {
+				log.Printf("Failed to reconcile documents: %v", err)
+			}
+			if synced > 0 {
+				log.Printf("Reconciled %d documents edited outside the bot", synced)
+			}
+		}
+	}
+}
+
+// Reconcile reads the documents of the default store and of the projects with their own repository, and
+// indexes the ones missing from the index or changed since they were indexed. It returns how many documents
+// it synced. Documents read unchanged since the last run are skipped, and a failing document or store does
+// not keep the others from being synced.
+func (s *ReconciliationService) Reconcile(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, fmt.Errorf("context cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targets, err := s.targets(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	var errs []error
+	for _, target := range targets {
+		paths, err := walkDocuments(ctx, target.store)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("repository %q: %w", target.config.Repository, err))
+			continue
+		}
+		for _, docPath := range paths {
+			_, changed, err := s.reconcile(ctx, target, docPath)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("document %s: %w", docPath, err))
+				continue
+			}
+			if changed {
+				synced++
+			}
+		}
+	}
+	return synced, errors.Join(errs...)
+}
+
+// RebuildGraph records every indexed document in the reference graph, as read from its store, and returns how
+// many it recorded. The graph is kept in memory, so call it at startup before documents are written, or their
+// Backlinks sections lose the references recorded before the restart. A document that cannot be read does not
+// keep the others from being recorded.
+func (s *ReconciliationService) RebuildGraph(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, fmt.Errorf("context cannot be nil")
+	}
+
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	recorded := 0
+	var errs []error
+	for _, doc := range docs {
+		store, err := s.stores.Resolve(doc.Repository(), doc.Branch())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("document %s: %w", doc.Path(), err))
+			continue
+		}
+		content, err := store.GetDocument(ctx, doc.Path())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("document %s: failed to read document: %w", doc.Path(), err))
+			continue
+		}
+		if err := s.graph.RecordStoredDocument(domain.ParseStoredDocument(doc.Path(), string(content))); err != nil {
+			errs = append(errs, fmt.Errorf("document %s: %w", doc.Path(), err))
+			continue
+		}
+		recorded++
+	}
+	return recorded, errors.Join(errs...)
+}
+
+// targets returns the default store and the store of every project writing to its own repository
+func (s *ReconciliationService) targets(ctx context.Context) ([]reconcileTarget, error) {
+	targets := []reconcileTarget{{store: s.stores.Default()}}
+	if s.projects == nil {
+		return targets, nil
+	}
+
+	projects, err := s.projects.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, project := range projects {
+		config := project.Documentation()
+		key := config.Repository + "@" + config.Branch
+		if config.Repository == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		store, err := s.stores.ForDocumentation(project.Name(), config)
+		if err != nil {
+			log.Printf("Skipping the documents of project %s: %v", project.Name(), err)
+			continue
+		}
+		targets = append(targets, reconcileTarget{store: store, project: project, config: config})
+	}
+	return targets, nil
+}
+
+// HandlePush refreshes the index with the documents a push changed, without waiting for the next
+// reconciliation. When people changed or deleted documents the bot generated, the channels of the
+// documents' project are told. Pushes to repositories no project documents into are ignored.
+func (s *ReconciliationService) HandlePush(ctx context.Context, push *domain.DocumentPush) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+	if push == nil {
+		return fmt.Errorf("push cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, ok, err := s.pushTarget(ctx, push)
+	if err != nil || !ok {
+		return err
+	}
+
+	var errs []error
+	for _, change := range push.Changes() {
+		if !reconciled(change.Path) {
+			continue
+		}
+		if err := s.applyChange(ctx, target, change); err != nil {
+			errs = append(errs, fmt.Errorf("document %s: %w", change.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pushTarget returns the store a push went to, and false when no documentation is written to its repository
+func (s *ReconciliationService) pushTarget(ctx context.Context, push *domain.DocumentPush) (reconcileTarget, bool, error) {
+	if push.Repository == "" {
+		return reconcileTarget{store: s.stores.Default()}, true, nil
+	}
+	if s.projects == nil {
+		return reconcileTarget{}, false, nil
+	}
+
+	projects, err := s.projects.List(ctx)
+	if err != nil {
+		return reconcileTarget{}, false, fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, project := range projects {
+		config := project.Documentation()
+		if !strings.EqualFold(config.Repository, push.Repository) || config.Branch != push.Branch {
+			continue
+		}
+		store, err := s.stores.ForDocumentation(project.Name(), config)
+		if err != nil {
+			return reconcileTarget{}, false, err
+		}
+		return reconcileTarget{store: store, project: project, config: config}, true, nil
+	}
+	return reconcileTarget{}, false, nil
+}
+
+// applyChange syncs the index and the graph with a document a push changed
+func (s *ReconciliationService) applyChange(ctx context.Context, target reconcileTarget, change domain.DocumentChange) error {
+	if change.Kind == domain.DocumentRemoved {
+		return s.remove(ctx, target, change)
+	}
+
+	stored, _, err := s.reconcile(ctx, target, change.Path)
+	if err != nil {
+		return err
+	}
+	if change.ByBot || len(stored.Sources) == 0 {
+		return nil
+	}
+
+	link := change.Path
+	if linker, ok := target.store.(ports.DocumentLinker); ok {
+		link = linker.DocumentURL(change.Path)
+	}
+	s.notify(ctx, target, change.Path, fmt.Sprintf("✏️ %s edited %s by hand, search and answers now use their version.", change.Author, link))
+	return nil
+}
+
+// remove drops a deleted document from the index and the graph
+func (s *ReconciliationService) remove(ctx context.Context, target reconcileTarget, change domain.DocumentChange) error {
+	doc, err := domain.NewDocumentReference(change.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+	generated := false
+	for _, ref := range s.graph.ReferencesOf(*doc) {
+		generated = generated || ref.Type() == domain.ReferenceTypeMessage
+	}
+	// The channels are looked up before the entry naming the project is gone
+	if !change.ByBot && generated {
+		s.notify(ctx, target, change.Path, fmt.Sprintf("🗑️ %s deleted %s, it is no longer found by search and answers.", change.Author, change.Path))
+	}
+
+	if err := s.index.Remove(ctx, change.Path); err != nil && !errors.Is(err, ports.ErrNotFound) {
+		return fmt.Errorf("failed to remove index entry: %w", err)
+	}
+	if err := s.graph.RemoveDocument(change.Path); err != nil {
+		return fmt.Errorf("failed to remove document references: %w", err)
+	}
+	delete(s.synced, syncKey(target, change.Path))
+	return nil
+}
+
+// notify posts a note about a document to the channels of its project. Failures are logged, the index is
+// already up to date.
+func (s *ReconciliationService) notify(ctx context.Context, target reconcileTarget, docPath, note string) {
+	if s.chat == nil {
+		return
+	}
+	project := target.project
+	if project == nil && s.projects != nil {
+		if entry, err := s.index.FindByPath(ctx, docPath); err == nil && entry.Project().String() != "" {
+			project, _ = s.projects.FindByID(ctx, entry.Project())
+		}
+	}
+	if project == nil {
+		return
+	}
+	for _, channelID := range project.Channels() {
+		if err := s.chat.SendMessage(ctx, channelID, note); err != nil {
+			log.Printf("Failed to post the change of %s to %s: %v", docPath, channelID, err)
+		}
+	}
+}
+
+// reconcile syncs a document into the index and the graph, and reports whether it had to. It returns the
+// document as stored, nil for files that are not reconciled.
+func (s *ReconciliationService) reconcile(ctx context.Context, target reconcileTarget, docPath string) (*domain.StoredDocument, bool, error) {
+	if !reconciled(docPath) {
+		return nil, false, nil
+	}
+
+	content, err := target.store.GetDocument(ctx, docPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read document: %w", err)
+	}
+	stored := domain.ParseStoredDocument(docPath, string(content))
+	key := syncKey(target, docPath)
+	sum := sha256.Sum256(content)
+	if s.synced[key] == sum {
+		return stored, false, nil
+	}
+
+	entry, err := s.index.FindByPath(ctx, docPath)
+	if err != nil && !errors.Is(err, ports.ErrNotFound) {
+		return nil, false, fmt.Errorf("failed to find index entry: %w", err)
+	}
+
+	changed := true
+	if entry != nil {
+		changed = entry.Refresh(stored.Title, stored.Summary)
+	} else {
+		if entry, err = stored.IndexEntry(); err != nil {
+			return nil, false, fmt.Errorf("failed to create index entry: %w", err)
+		}
+		entry.SetLocation(target.config.Repository, target.config.Branch)
+		if target.project != nil {
+			entry.SetProject(target.project.ID())
+		}
+	}
+
+	if changed {
+		if embedder, ok := s.aiAgent.(ports.EmbeddingProvider); ok {
+			// Documents without embeddings can still be found by title
+			if embedding, err := embedder.Embed(ctx, entry.SearchText()); err == nil {
+				entry.SetEmbedding(embedding)
+			}
+		}
+		if err := s.index.Index(ctx, entry); err != nil {
+			return nil, false, fmt.Errorf("failed to index document: %w", err)
+		}
+		if err := s.graph.RecordStoredDocument(stored); err != nil {
+			return nil, false, fmt.Errorf("failed to record document references: %w", err)
+		}
+	}
+
+	s.synced[key] = sum
+	return stored, changed, nil
+}
+
+// reconciled checks if a file is a document the index keeps, rather than a generated listing
+func reconciled(docPath string) bool {
+	return strings.HasSuffix(docPath, ".md") && !domain.IsTableOfContents(docPath) && docPath != domain.GlossaryFile
+}
+
+// syncKey identifies a document across the stores
+func syncKey(target reconcileTarget, docPath string) string {
+	return target.config.Repository + "@" + target.config.Branch + ":" + docPath
+}
+
+// walkDocuments lists the documents of a store. Stores that cannot walk their directory tree are listed
+// in the docs directory and its category directories.
+func walkDocuments(ctx context.Context, store ports.DocumentStoreProvider) ([]string, error) {
+	if walker, ok := store.(ports.DocumentWalker); ok {
+		return walker.WalkDocuments(ctx, "docs")
+	}
+
+	dirs := []string{"docs"}
+	for _, category := range domain.DocumentCategories {
+		dirs = append(dirs, path.Join("docs", category.String()))
+	}
+	var paths []string
+	for _, dir := range dirs {
+		listed, err := store.ListDocuments(ctx, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents in %s: %w", dir, err)
+		}
+		paths = append(paths, listed...)
+	}
+	return paths, nil
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+	"sync"
+)
+
+// ReferenceGraphService maintains the graph of references between documents, messages and threads
+type ReferenceGraphService struct {
+	mu    sync.RWMutex
+	graph *domain.ReferenceGraph
+}
+
+func NewReferenceGraphService() *ReferenceGraphService {
+	return &ReferenceGraphService{
+		graph: domain.NewReferenceGraph(),
+	}
+}
+
+// RecordDocument registers a stored document together with the message it was generated from
+// and the references detected in that message
+func (s *ReferenceGraphService) RecordDocument(path string, msg *domain.Message) error {
+	doc, err := domain.NewDocumentReference(path)
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.graph.AddNode(*doc)
+	if msg == nil {
+		return nil
+	}
+
+	source, err := domain.NewMessageReference(msg.ID().String())
+	if err != nil {
+		return fmt.Errorf("failed to create message reference: %w", err)
+	}
+	if err := s.graph.Link(*doc, *source); err != nil {
+		return fmt.Errorf("failed to link document to source message: %w", err)
+	}
+
+	if threadID := msg.ThreadID().String(); threadID != "" {
+		thread, err := domain.NewThreadReference(threadID)
+		if err != nil {
+			return fmt.Errorf("failed to create thread reference: %w", err)
+		}
+		if err := s.graph.Link(*source, *thread); err != nil {
+			return fmt.Errorf("failed to link message to thread: %w", err)
+		}
+	}
+
+	for _, ref := range msg.LinkedReferences() {
+		// Self references are ignored, they carry no information
+		if ref.Equals(*doc) {
+			continue
+		}
+		if err := s.graph.Link(*doc, *ref); err != nil {
+			return fmt.Errorf("failed to link document reference: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RecordRelation registers that a document supersedes or amends another
+func (s *ReferenceGraphService) RecordRelation(rel *domain.DocumentRelation) error {
+	if rel == nil {
+		return fmt.Errorf("document relation cannot be nil")
+	}
+	from, err := domain.NewDocumentReference(rel.From())
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+	to, err := domain.NewDocumentReference(rel.To())
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.graph.Relate(*from, *to, rel.Relation()); err != nil {
+		return fmt.Errorf("failed to relate documents: %w", err)
+	}
+	return nil
+}
+
+// Relations returns the relations between documents recorded in the graph
+func (s *ReferenceGraphService) Relations() []*domain.DocumentRelation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var relations []*domain.DocumentRelation
+	for _, doc := range s.graph.Documents() {
+		for _, to := range s.graph.References(doc) {
+			relation, ok := s.graph.RelationBetween(doc, to)
+			if !ok {
+				continue
+			}
+			rel, err := domain.NewDocumentRelation(doc.Value(), relation, to.Value())
+			if err != nil {
+				continue
+			}
+			relations = append(relations, rel)
+		}
+	}
+	domain.SortRelations(relations)
+	return relations
+}
+
+// RecordStoredDocument adds a document read from the store, like one people wrote, to the graph: it is
+// linked to the messages it was generated from and the documents it links to, and related to the documents
+// its front matter says it supersedes or amends
+func (s *ReferenceGraphService) RecordStoredDocument(stored *domain.StoredDocument) error {
+	doc, err := domain.NewDocumentReference(stored.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+
+	var targets []domain.Reference
+	for _, id := range stored.Sources {
+		source, err := domain.NewMessageReference(id)
+		if err != nil {
+			return fmt.Errorf("failed to create message reference: %w", err)
+		}
+		targets = append(targets, *source)
+	}
+	for _, link := range stored.Links {
+		target, err := domain.NewDocumentReference(link)
+		if err != nil {
+			return fmt.Errorf("failed to create document reference: %w", err)
+		}
+		targets = append(targets, *target)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.graph.AddNode(*doc)
+	for _, target := range targets {
+		if err := s.graph.Link(*doc, target); err != nil {
+			return fmt.Errorf("failed to link document reference: %w", err)
+		}
+	}
+	for _, rel := range stored.Relations {
+		older, err := domain.NewDocumentReference(rel.To())
+		if err != nil {
+			return fmt.Errorf("failed to create document reference: %w", err)
+		}
+		if err := s.graph.Relate(*doc, *older, rel.Relation()); err != nil {
+			return fmt.Errorf("failed to relate documents: %w", err)
+		}
+	}
+	return nil
+}
+
+// RemoveDocument removes a document and all its edges from the graph
+func (s *ReferenceGraphService) RemoveDocument(path string) error {
+	doc, err := domain.NewDocumentReference(path)
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.graph.RemoveNode(*doc)
+	return nil
+}
+
+// MoveDocument renames a document in the graph, keeping its references and backlinks
+func (s *ReferenceGraphService) MoveDocument(from, to string) error {
+	oldDoc, err := domain.NewDocumentReference(from)
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+	newDoc, err := domain.NewDocumentReference(to)
+	if err != nil {
+		return fmt.Errorf("failed to create document reference: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.graph.HasNode(*oldDoc) {
+		return nil
+	}
+
+	references := s.graph.References(*oldDoc)
+	backlinks := s.graph.Backlinks(*oldDoc)
+	outgoing := make(map[string]domain.Relation)
+	for _, ref := range references {
+		if relation, ok := s.graph.RelationBetween(*oldDoc, ref); ok {
+			outgoing[ref.String()] = relation
+		}
+	}
+	incoming := make(map[string]domain.Relation)
+	for _, ref := range backlinks {
+		if relation, ok := s.graph.RelationBetween(ref, *oldDoc); ok {
+			incoming[ref.String()] = relation
+		}
+	}
+
+	s.graph.RemoveNode(*oldDoc)
+	s.graph.AddNode(*newDoc)
+	for _, ref := range references {
+		if err := s.relink(*newDoc, ref, outgoing[ref.String()]); err != nil {
+			return fmt.Errorf("failed to link moved document: %w", err)
+		}
+	}
+	for _, ref := range backlinks {
+		if err := s.relink(ref, *newDoc, incoming[ref.String()]); err != nil {
+			return fmt.Errorf("failed to link moved document: %w", err)
+		}
+	}
+	return nil
+}
+
+// relink adds an edge of a moved document back, with its relation when it had one
+func (s *ReferenceGraphService) relink(from, to domain.Reference, relation domain.Relation) error {
+	if relation == "" {
+		return s.graph.Link(from, to)
+	}
+	return s.graph.Relate(from, to, relation)
+}
+
+// ReferencedBy answers "what references this" for the given reference
+func (s *ReferenceGraphService) ReferencedBy(ref domain.Reference) []domain.Reference {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.graph.Backlinks(ref)
+}
+
+// ReferencesOf returns everything the given reference points to
+func (s *ReferenceGraphService) ReferencesOf(ref domain.Reference) []domain.Reference {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.graph.References(ref)
+}
+
+// OrphanedDocuments returns the paths of documents that nothing references
+func (s *ReferenceGraphService) OrphanedDocuments() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orphans := s.graph.Orphans()
+	paths := make([]string, 0, len(orphans))
+	for _, ref := range orphans {
+		paths = append(paths, ref.Value())
+	}
+	return paths
+}
+
+// InjectBacklinks replaces the Backlinks section of a document with the current incoming references
+func (s *ReferenceGraphService) InjectBacklinks(path string, content string) string {
+	doc, err := domain.NewDocumentReference(path)
+	if err != nil {
+		return content
+	}
+
+	content = domain.StripBacklinks(content)
+	backlinks := s.ReferencedBy(*doc)
+	if len(backlinks) == 0 {
+		return content
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(content, "\n"))
+	b.WriteString("\n\n")
+	b.WriteString(domain.BacklinksHeading)
+	b.WriteString("\n\n")
+	for _, ref := range backlinks {
+		if ref.Type().IsDocument() {
+			b.WriteString(fmt.Sprintf("- [%s](/%s)", ref.Value(), strings.TrimPrefix(ref.Value(), "/")))
+			if relation, ok := s.relationBetween(ref, *doc); ok {
+				b.WriteString(fmt.Sprintf(" (%s this document)", relation))
+			}
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(fmt.Sprintf("- %s\n", ref.String()))
+	}
+	return b.String()
+}
+
+func (s *ReferenceGraphService) relationBetween(from, to domain.Reference) (domain.Relation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.graph.RelationBetween(from, to)
+}
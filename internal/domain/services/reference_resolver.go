@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"math"
+)
+
+// DefaultResolutionThreshold is the minimum match score for a reference to be considered resolved
+const DefaultResolutionThreshold = 0.6
+
+// ReferenceResolver matches detected references against the document index and message store
+type ReferenceResolver struct {
+	index      ports.DocumentIndex
+	messages   ports.MessageRepository
+	embeddings ports.EmbeddingProvider
+	flags      *FeatureFlagService
+	threshold  float64
+}
+
+// NewReferenceResolver creates a new ReferenceResolver.
+// Embedding similarity is used when the AI agent also implements ports.EmbeddingProvider, for the projects the
+// embedding-references feature is on for when feature flags are given (optional).
+func NewReferenceResolver(
+	index ports.DocumentIndex,
+	messages ports.MessageRepository,
+	ai ports.AiAgentProvider,
+	flags *FeatureFlagService,
+) *ReferenceResolver {
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if ai == nil {
+		panic("AI agent cannot be nil")
+	}
+
+	embeddings, _ := ai.(ports.EmbeddingProvider)
+	return &ReferenceResolver{
+		index:      index,
+		messages:   messages,
+		embeddings: embeddings,
+		flags:      flags,
+		threshold:  DefaultResolutionThreshold,
+	}
+}
+
+// ResolveAll resolves every reference of a message of the project, nil outside projects, keeping the input order
+func (r *ReferenceResolver) ResolveAll(ctx context.Context, project *domain.Project, refs []*domain.Reference) ([]*domain.ResolvedReference, error) {
+	resolved := make([]*domain.ResolvedReference, 0, len(refs))
+	for _, ref := range refs {
+		res, err := r.Resolve(ctx, project, ref)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, res)
+	}
+	return resolved, nil
+}
+
+// Resolve matches a single reference of a message of the project to a canonical target
+func (r *ReferenceResolver) Resolve(ctx context.Context, project *domain.Project, ref *domain.Reference) (*domain.ResolvedReference, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("reference cannot be nil")
+	}
+
+	switch {
+	case ref.Type().IsMessage():
+		return r.resolveMessage(ctx, ref)
+	case ref.Type().IsDocument(), ref.Type().IsUnknown():
+		embeddings := r.embeddings != nil && featureEnabled(ctx, r.flags, domain.FeatureEmbeddingReferences, project)
+		return r.resolveDocument(ctx, ref, embeddings)
+	default:
+		// Threads, links, issues and users are already canonical identifiers
+		return domain.NewResolvedReference(ref, ref, 1)
+	}
+}
+
+func (r *ReferenceResolver) resolveMessage(ctx context.Context, ref *domain.Reference) (*domain.ResolvedReference, error) {
+	msg, err := r.messages.FindByID(ctx, ref.Value())
+	if errors.Is(err, ports.ErrNotFound) {
+		return domain.NewUnresolvedReference(ref), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up message: %w", err)
+	}
+
+	target, err := domain.NewMessageReference(msg.ID().String())
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewResolvedReference(ref, target, 1)
+}
+
+func (r *ReferenceResolver) resolveDocument(ctx context.Context, ref *domain.Reference, embeddings bool) (*domain.ResolvedReference, error) {
+	doc, err := r.index.FindByPath(ctx, ref.Value())
+	if err == nil {
+		target, err := domain.NewDocumentReference(doc.Path())
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewResolvedReference(ref, target, 1)
+	}
+	if !errors.Is(err, ports.ErrNotFound) {
+		return nil, fmt.Errorf("failed to look up document: %w", err)
+	}
+
+	docs, err := r.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	var queryEmbedding []float64
+	if embeddings {
+		// Embeddings improve matching but are not required for it
+		queryEmbedding, _ = r.embeddings.Embed(ctx, ref.Value())
+	}
+
+	var best *domain.IndexedDocument
+	bestScore := 0.0
+	for _, doc := range docs {
+		score := domain.TextSimilarity(ref.Value(), doc.Title())
+		if pathScore := domain.TextSimilarity(ref.Value(), doc.Path()); pathScore > score {
+			score = pathScore
+		}
+		if len(queryEmbedding) > 0 && doc.HasEmbedding() {
+			if sim := domain.CosineSimilarity(queryEmbedding, doc.Embedding()); sim > score {
+				score = sim
+			}
+		}
+		if score > bestScore {
+			best, bestScore = doc, score
+		}
+	}
+
+	if best == nil || bestScore < r.threshold {
+		return domain.NewUnresolvedReference(ref), nil
+	}
+
+	target, err := domain.NewDocumentReference(best.Path())
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewResolvedReference(ref, target, math.Min(bestScore, 1))
+}
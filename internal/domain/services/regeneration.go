@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"strings"
+	"time"
+)
+
+// DraftRegeneration generates the documents of a message again, as the AI agent writes them now, without storing
+// them, so ApplyUpdate updates them in place. Each changed document gets its version bumped and the prompt version
+// and model of the message in its front matter. Documents generated again with the same content are left out, as
+// are the documents the message was merged into and status rollups, which also hold other messages.
+func (s *DocumentationService) DraftRegeneration(ctx context.Context, msg *domain.Message) ([]*DocumentUpdate, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("message cannot be nil")
+	}
+
+	source, err := domain.NewMessageReference(msg.ID().String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message reference: %w", err)
+	}
+
+	var updates []*DocumentUpdate
+	for _, ref := range s.graph.ReferencedBy(*source) {
+		if !ref.Type().IsDocument() {
+			continue
+		}
+		update, err := s.draftRegeneration(ctx, ref.Value(), msg)
+		if err != nil {
+			return nil, err
+		}
+		if update != nil {
+			updates = append(updates, update)
+		}
+	}
+	return updates, nil
+}
+
+// draftRegeneration generates a document of a message again, it returns nil when the document was not generated
+// from the message alone or did not change
+func (s *DocumentationService) draftRegeneration(ctx context.Context, path string, msg *domain.Message) (*DocumentUpdate, error) {
+	entry, err := s.index.FindByPath(ctx, path)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	store, err := s.stores.Resolve(entry.Repository(), entry.Branch())
+	if err != nil {
+		return nil, err
+	}
+	existing, revision, err := readRevision(ctx, store, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve documentation: %w", err)
+	}
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+	if fm.Get("source_message") != msg.ID().String() {
+		return nil, nil
+	}
+
+	docConfig, err := s.documentationConfig(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	// The document may have been written before its project became local-only
+	if err := checkResidency(msg.ChannelID(), docConfig.LocalOnly, "storage", store); err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"type":       msg.Type().String(),
+		"category":   msg.Category().String(),
+		"references": msg.LinkedReferences(),
+	}
+	// Changes people make to the document until the update is applied are merged with it
+	if revision != "" {
+		metadata["base_revision"] = revision
+	}
+
+	images := s.analyzeImages(ctx, msg, docConfig)
+	doc, err := s.generateDocument(ctx, msg, images, metadata, docConfig)
+	if err != nil {
+		return nil, err
+	}
+	glossary, attached := s.updateGlossary(ctx, store, msg, path)
+	attachImages(ctx, store, attached, images)
+	regenerated := withImageSection(domain.LinkTerms(doc, path, glossary), path, images)
+	if strings.TrimSpace(regenerated) == strings.TrimSpace(domain.StripBacklinks(body)) {
+		return nil, nil
+	}
+
+	// The document stays where it is, under the type and category it was filed with
+	domain.RecordProvenance(fm, msg)
+	fm.Set("idempotency_key", domain.MessageIdempotencyKey(msg))
+	tags := fm.GetList("tags")
+	for _, tag := range domain.TagStrings(msg.Tags()) {
+		tags = appendMissing(tags, tag)
+	}
+	fm.SetList("tags", tags)
+	fm.Set("reprocessed_at", time.Now().UTC().Format(time.RFC3339))
+	version := domain.BumpDocumentVersion(fm)
+	content := fm.Apply(regenerated)
+
+	return &DocumentUpdate{
+		path:     path,
+		content:  content,
+		metadata: metadata,
+		attached: attached,
+		msg:      msg,
+		diff:     domain.NewDocumentDiff(path, existing, []byte(content)),
+		version:  version,
+	}, nil
+}
+
+// refreshIndexed updates the index entry of a regenerated document with its new title, summary, tags and
+// idempotency key
+func (s *DocumentationService) refreshIndexed(ctx context.Context, update *DocumentUpdate) error {
+	entry, err := s.index.FindByPath(ctx, update.path)
+	if err != nil {
+		return fmt.Errorf("failed to find index entry of %s: %w", update.path, err)
+	}
+	fm, body, err := domain.ParseFrontMatter(update.content)
+	if err != nil {
+		return fmt.Errorf("failed to parse front matter of %s: %w", update.path, err)
+	}
+
+	changed := entry.Refresh(domain.TitleFromMarkdown(body), domain.SummaryFromMarkdown(body))
+	entry.SetTags(domain.NewTags(fm.GetList("tags")))
+	entry.SetIdempotencyKey(fm.Get("idempotency_key"))
+	if embedder, ok := s.aiAgent.(ports.EmbeddingProvider); ok && changed {
+		// Documents without embeddings can still be found by title
+		if embedding, err := embedder.Embed(ctx, entry.SearchText()); err == nil {
+			entry.SetEmbedding(embedding)
+		}
+	}
+	if err := s.index.Index(ctx, entry); err != nil {
+		return fmt.Errorf("failed to index documentation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// RegisterRelationCommands registers the "relate" command linking a decision to the older one it supersedes or amends
+func RegisterRelationCommands(commands *CommandService, docs *DocumentationService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+
+	commands.Register("relate", "relate <path> supersedes|amends <older-path>", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return relateDocuments(ctx, docs, cmd)
+	})
+}
+
+func relateDocuments(ctx context.Context, docs *DocumentationService, cmd *domain.Command) (string, error) {
+	if cmd.ArgCount() != 3 {
+		return "", fmt.Errorf("usage: `%s relate <path> supersedes|amends <older-path>`", domain.CommandPrefix)
+	}
+	relation, err := domain.ParseRelation(cmd.Arg(1))
+	if err != nil {
+		return "", err
+	}
+	rel, err := domain.NewDocumentRelation(cmd.Arg(0), relation, cmd.Arg(2))
+	if err != nil {
+		return "", err
+	}
+
+	if err := docs.RelateDocuments(ctx, rel); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🔗 %s now %s %s", docs.DocumentLink(ctx, rel.From()), relation, docs.DocumentLink(ctx, rel.To())), nil
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultReplyFlushInterval is how often the batches whose window ended are posted
+const DefaultReplyFlushInterval = 5 * time.Second
+
+// ReplyThrottle posts capture confirmations within the reply settings of the channel's project.
+// Confirmations are dropped once the channel reached its hourly limit. The confirmations of a thread
+// posted within the batch window are combined into one summary, and those posted during quiet hours
+// are held until they end. Held confirmations wait in the outbox, so they survive restarts, and Run
+// posts them once they are due.
+// Private reply modes fall back to the thread when the chat provider cannot reply privately.
+// In reaction mode the message only gets an emoji, the confirmation is kept in the outbox until someone
+// asks for it.
+type ReplyThrottle struct {
+	chat     ports.ChatAccessProvider
+	projects *ProjectService
+	outbox   ports.ReplyOutbox
+	timeouts StageTimeouts
+	now      func() time.Time
+
+	mu   sync.Mutex
+	sent map[string][]time.Time // Confirmation times per channel within the last hour
+}
+
+const (
+	// replyDetailsRetention is how long the confirmations of acknowledged messages are kept
+	replyDetailsRetention = 30 * 24 * time.Hour
+	// replyRetryDelay is how long a summary that failed to post waits in the outbox before it is posted again
+	replyRetryDelay = time.Minute
+)
+
+// NewReplyThrottle creates a ReplyThrottle, zero timeouts use the defaults
+func NewReplyThrottle(chat ports.ChatAccessProvider, projects *ProjectService, outbox ports.ReplyOutbox, timeouts StageTimeouts) *ReplyThrottle {
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	if projects == nil {
+		panic("project service cannot be nil")
+	}
+	if outbox == nil {
+		panic("reply outbox cannot be nil")
+	}
+	return &ReplyThrottle{
+		chat:     chat,
+		projects: projects,
+		outbox:   outbox,
+		timeouts: timeouts.withDefaults(),
+		now:      time.Now,
+		sent:     make(map[string][]time.Time),
+	}
+}
+
+// Confirm posts a confirmation for a captured message as the reply settings of its channel allow
+func (t *ReplyThrottle) Confirm(ctx context.Context, msg *domain.Message, reply string) error {
+	return t.ConfirmDocument(ctx, msg, reply, "")
+}
+
+// ConfirmDocument posts the confirmation of a message documented at the path. Confirmations posted
+// on their own in the thread offer buttons for filing the document under another category when the
+// chat provider implements ports.CategoryPicker.
+func (t *ReplyThrottle) ConfirmDocument(ctx context.Context, msg *domain.Message, reply, path string) error {
+	cfg, err := t.projects.RepliesFor(ctx, msg.ChannelID())
+	if err != nil {
+		return err
+	}
+
+	// Reactions notify nobody, so they are not throttled
+	if cfg.Mode == domain.ReplyModeReaction {
+		return t.acknowledge(ctx, msg, reply, cfg)
+	}
+
+	now := t.now()
+	quiet := cfg.QuietHours.Contains(now)
+	if cfg.BatchWindow <= 0 && !quiet {
+		if !t.allow(msg.ChannelID(), cfg.MaxRepliesPerHour, now) {
+			return nil
+		}
+		if picker, ok := t.chat.(ports.CategoryPicker); ok && path != "" && !cfg.Mode.IsPrivate() {
+			return picker.OfferCategories(ctx, msg.ID().String(), reply, path, msg.Category())
+		}
+		return t.deliver(ctx, msg.ID().String(), cfg.Mode, reply)
+	}
+
+	dueAt := now.Add(cfg.BatchWindow)
+	if end := cfg.QuietHours.EndAfter(now); quiet && end.After(dueAt) {
+		dueAt = end
+	}
+	return t.enqueue(ctx, msg, reply, cfg.Mode, dueAt)
+}
+
+// acknowledge reacts to a captured message and keeps the confirmation for the details shortcut
+func (t *ReplyThrottle) acknowledge(ctx context.Context, msg *domain.Message, reply string, cfg domain.ReplyConfig) error {
+	if err := t.chat.AddReaction(ctx, msg.ID().String(), cfg.ReactionFor(msg.Type())); err != nil {
+		return err
+	}
+
+	now := t.now()
+	if err := t.outbox.KeepDetails(ctx, msg.ID().String(), reply, now); err != nil {
+		return fmt.Errorf("failed to keep confirmation of message %s: %w", msg.ID(), err)
+	}
+	if _, err := t.outbox.ForgetDetails(ctx, now.Add(-replyDetailsRetention)); err != nil {
+		logf(ctx, "Failed to forget old confirmations: %v", err)
+	}
+	return nil
+}
+
+// Details returns the confirmation of a message that was acknowledged with a reaction
+func (t *ReplyThrottle) Details(ctx context.Context, messageID string) (string, error) {
+	return t.outbox.Details(ctx, messageID)
+}
+
+// enqueue adds a confirmation to its thread's batch in the outbox, the first confirmation of a thread sets
+// when the batch is due
+func (t *ReplyThrottle) enqueue(ctx context.Context, msg *domain.Message, reply string, mode domain.ReplyMode, dueAt time.Time) error {
+	key := msg.ThreadID().String()
+	if key == "" {
+		key = msg.ID().String()
+	}
+
+	batch, err := domain.NewReplyBatch(key, msg.ID().String(), msg.ChannelID(), mode, reply, dueAt)
+	if err != nil {
+		return fmt.Errorf("failed to batch confirmation of message %s: %w", msg.ID(), err)
+	}
+	if router, ok := t.chat.(ports.MessageRouter); ok {
+		if route, ok := router.MessageRoute(msg.ID().String()); ok {
+			batch.SetRoute(route)
+		}
+	}
+	if _, err := t.outbox.Add(ctx, batch); err != nil {
+		return fmt.Errorf("failed to batch confirmation of message %s: %w", msg.ID(), err)
+	}
+	return nil
+}
+
+// Run posts the summaries of the batches whose window ended at each interval until ctx is canceled.
+// Zero interval uses DefaultReplyFlushInterval. Each summary is posted under ctx, bounded by the delivery
+// timeout, so stopping the bot stops the posts in progress and no post outlives it.
+func (t *ReplyThrottle) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultReplyFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if _, err := t.Flush(ctx, now); err != nil {
+				log.Printf("Failed to post confirmation summaries: %v", err)
+			}
+		}
+	}
+}
+
+// Flush posts the summaries of the batches due by now, the earliest first, and returns how many it posted.
+// Batches of channels in their quiet hours are postponed until the quiet hours end. A summary that fails does
+// not keep the others from being posted.
+func (t *ReplyThrottle) Flush(ctx context.Context, now time.Time) (int, error) {
+	due, err := t.outbox.Due(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read reply outbox: %w", err)
+	}
+
+	posted := 0
+	var errs []error
+	for _, batch := range due {
+		ok, err := t.flush(ctx, batch, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("batch %s: %w", batch.Key(), err))
+			continue
+		}
+		if ok {
+			posted++
+		}
+	}
+	return posted, errors.Join(errs...)
+}
+
+// flush posts the summary of a batch within the hourly limit of its channel, and reports whether it was posted.
+// The batch is removed from the outbox before it is posted, so a batch another replica took is skipped, and put
+// back to be posted again later when posting it fails.
+func (t *ReplyThrottle) flush(ctx context.Context, batch *domain.ReplyBatch, now time.Time) (bool, error) {
+	cfg, err := t.projects.RepliesFor(ctx, batch.ChannelID())
+	if err != nil {
+		return false, err
+	}
+	if cfg.QuietHours.Contains(now) {
+		if err := t.outbox.Postpone(ctx, batch.Key(), cfg.QuietHours.EndAfter(now)); err != nil && !errors.Is(err, ports.ErrNotFound) {
+			return false, fmt.Errorf("failed to postpone confirmation summary: %w", err)
+		}
+		return false, nil
+	}
+
+	if err := t.outbox.Remove(ctx, batch.Key()); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to take confirmation summary: %w", err)
+	}
+	if !t.allow(batch.ChannelID(), cfg.MaxRepliesPerHour, now) {
+		return false, nil
+	}
+	if router, ok := t.chat.(ports.MessageRouter); ok && batch.Route() != nil {
+		if err := router.RestoreMessageRoute(batch.MessageID(), batch.Route()); err != nil {
+			return false, t.requeue(ctx, batch, now, fmt.Errorf("failed to restore route of message %s: %w", batch.MessageID(), err))
+		}
+	}
+
+	err = runStage(ctx, "delivery", t.timeouts.Delivery, func(ctx context.Context) error {
+		return t.deliver(ctx, batch.MessageID(), batch.Mode(), summarizeReplies(batch.Replies()))
+	})
+	if err != nil {
+		return false, t.requeue(ctx, batch, now, fmt.Errorf("failed to post confirmation summary: %w", err))
+	}
+	return true, nil
+}
+
+// requeue puts a batch that failed to post back in the outbox, to be posted again after replyRetryDelay. It
+// returns the failure, joined with the error of putting the batch back when that fails too.
+func (t *ReplyThrottle) requeue(ctx context.Context, batch *domain.ReplyBatch, now time.Time, cause error) error {
+	retry, err := domain.RestoreReplyBatch(batch.Key(), batch.MessageID(), batch.ChannelID(), batch.Mode(), batch.Replies(),
+		batch.Route(), now.Add(replyRetryDelay))
+	if err == nil {
+		_, err = t.outbox.Add(ctx, retry)
+	}
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("failed to put confirmation summary back in the outbox: %w", err))
+	}
+	return cause
+}
+
+// deliver posts a confirmation where the reply mode asks for it
+func (t *ReplyThrottle) deliver(ctx context.Context, messageID string, mode domain.ReplyMode, reply string) error {
+	replier, ok := t.chat.(ports.PrivateReplier)
+	if !ok || !mode.IsPrivate() {
+		return t.chat.ReplyToMessage(ctx, messageID, reply)
+	}
+
+	if mode == domain.ReplyModeDirect {
+		return replier.ReplyDirect(ctx, messageID, reply)
+	}
+	return replier.ReplyEphemeral(ctx, messageID, reply)
+}
+
+// allow records a confirmation for the channel unless it already reached the hourly limit
+func (t *ReplyThrottle) allow(channelID string, limit int, now time.Time) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := now.Add(-time.Hour)
+	recent := t.sent[channelID][:0]
+	for _, sentAt := range t.sent[channelID] {
+		if sentAt.After(cutoff) {
+			recent = append(recent, sentAt)
+		}
+	}
+
+	if len(recent) >= limit {
+		t.sent[channelID] = recent
+		return false
+	}
+	t.sent[channelID] = append(recent, now)
+	return true
+}
+
+// summarizeReplies combines the confirmations of a thread, each on its own line
+func summarizeReplies(replies []string) string {
+	if len(replies) == 1 {
+		return replies[0]
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🗂️ Captured %d items in this thread:\n", len(replies)))
+	for _, reply := range replies {
+		b.WriteString(fmt.Sprintf("- %s\n", strings.ReplaceAll(strings.TrimSpace(reply), "\n", " · ")))
+	}
+	return b.String()
+}
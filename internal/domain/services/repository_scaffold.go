@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"path/filepath"
+	"strings"
+)
+
+// decisionsDir holds architecture decision records
+const decisionsDir = "docs/decisions"
+
+const contributingNote = `# Contributing
+
+Most documents in this repository are generated by Quill from chat conversations.
+
+- Documents under ` + "`docs/`" + ` start with front matter that links them to the message they came from. Keep it intact so Quill can update and cross-reference them.
+- Edits by hand are welcome, but Quill may append to generated documents when the conversation continues.
+- To change how a project is documented, use ` + "`/quill project edit`" + ` in its channel rather than editing the project README.
+`
+
+// repositoryScaffold returns the files that give an empty repository the documentation layout,
+// including the project's README so everything lands in one commit
+func repositoryScaffold(project *domain.Project) map[string][]byte {
+	files := map[string][]byte{
+		"README.md":                             []byte(renderRepositoryReadme(project)),
+		"CONTRIBUTING.md":                       []byte(contributingNote),
+		filepath.Join(decisionsDir, ".gitkeep"): {},
+		projectDocPath(project):                 []byte(renderProjectDocument(project)),
+	}
+	for _, category := range project.Documentation().Categories() {
+		files[filepath.Join("docs", category.String(), ".gitkeep")] = []byte{}
+	}
+	return files
+}
+
+func renderRepositoryReadme(project *domain.Project) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s documentation\n\n", project.Name()))
+	if project.Description() != "" {
+		b.WriteString(project.Description() + "\n\n")
+	}
+
+	b.WriteString("## Layout\n\n")
+	b.WriteString(fmt.Sprintf("- `%s/README.md` - project overview, goals and KPIs\n", project.DocumentationPath()))
+	b.WriteString(fmt.Sprintf("- `%s/` - decisions\n", decisionsDir))
+	for _, category := range project.Documentation().Categories() {
+		b.WriteString(fmt.Sprintf("- `docs/%s/` - %s documents\n", category, strings.ReplaceAll(category.String(), "_", " ")))
+	}
+	b.WriteString("\nSee CONTRIBUTING.md before editing generated documents.\n")
+	return b.String()
+}
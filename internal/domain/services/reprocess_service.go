@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"sort"
+	"time"
+)
+
+// ReprocessService runs documented messages through the prompt and model their channels use now, and updates
+// their documents in place, so historical documents benefit from prompt improvements
+type ReprocessService struct {
+	messages ports.MessageRepository
+	bot      *BotService
+	docs     *DocumentationService
+	audit    ports.AuditLog
+}
+
+// NewReprocessService creates a new ReprocessService
+func NewReprocessService(
+	messages ports.MessageRepository,
+	bot *BotService,
+	docs *DocumentationService,
+	audit ports.AuditLog,
+) *ReprocessService {
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if bot == nil {
+		panic("bot service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	return &ReprocessService{
+		messages: messages,
+		bot:      bot,
+		docs:     docs,
+		audit:    audit,
+	}
+}
+
+// Reprocess analyzes the messages the request selects again, oldest first, and regenerates their documents.
+// Messages that fail are reported and the others go on; a dry run stores nothing and reports the diffs.
+func (s *ReprocessService) Reprocess(ctx context.Context, request *domain.ReprocessRequest) (*domain.ReprocessReport, error) {
+	if request == nil {
+		return nil, fmt.Errorf("reprocess request cannot be nil")
+	}
+
+	stored, err := s.messages.FindByState(ctx, domain.MessageStateDocumented)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	var selected []*domain.Message
+	for _, msg := range stored {
+		if request.Matches(msg) {
+			selected = append(selected, msg)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Timestamp().Before(selected[j].Timestamp())
+	})
+
+	report := &domain.ReprocessReport{DryRun: request.DryRun()}
+	for _, msg := range selected {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := s.reprocessMessage(ctx, msg, request.DryRun(), report); err != nil {
+			log.Printf("Failed to reprocess message %s: %v", msg.ID(), err)
+			report.Failures = append(report.Failures, domain.ReprocessFailure{MessageID: msg.ID().String(), Error: err.Error()})
+		}
+	}
+
+	if !request.DryRun() {
+		entry, err := domain.NewAuditEntry(domain.AuditActionReprocess, request.RequestedBy(), request.String(), "", fmt.Sprintf("%d documents", len(report.Documents)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create audit entry: %w", err)
+		}
+		entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+		if err := s.audit.Record(ctx, entry); err != nil {
+			return nil, fmt.Errorf("failed to record reprocessing: %w", err)
+		}
+	}
+	report.CompletedAt = time.Now().UTC()
+	log.Printf("Reprocessed %s: %d messages, %d documents updated, %d messages unchanged, %d failed",
+		request, report.Messages, len(report.Documents), report.Unchanged, len(report.Failures))
+	return report, nil
+}
+
+// reprocessMessage analyzes a message again and updates its documents. It works on a copy of the message, so a
+// dry run leaves the stored one as it is.
+func (s *ReprocessService) reprocessMessage(ctx context.Context, stored *domain.Message, dryRun bool, report *domain.ReprocessReport) error {
+	msg, err := domain.MessageFromDTO(stored.ToDTO())
+	if err != nil {
+		return err
+	}
+	if err := s.bot.Reanalyze(ctx, msg); err != nil {
+		return err
+	}
+	updates, err := s.docs.DraftRegeneration(ctx, msg)
+	if err != nil {
+		return err
+	}
+	report.Messages++
+
+	if len(updates) == 0 {
+		report.Unchanged++
+	}
+	for _, update := range updates {
+		if !dryRun {
+			if err := s.docs.ApplyUpdate(ctx, update); err != nil {
+				return err
+			}
+		}
+		report.Documents = append(report.Documents, domain.ReprocessedDocument{
+			Path:      update.Path(),
+			MessageID: msg.ID().String(),
+			Version:   update.Version(),
+			Added:     update.Diff().Added(),
+			Removed:   update.Diff().Removed(),
+			Diff:      update.Diff().Unified(),
+		})
+	}
+
+	if dryRun {
+		return nil
+	}
+	if err := s.messages.Update(ctx, msg); err != nil {
+		return fmt.Errorf("failed to update message %s: %w", msg.ID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+)
+
+// checkResidency decides if content may be sent to a provider, and logs the decision when the content is
+// local-only. The subject names the content in the log, like its channel or repository.
+func checkResidency(subject string, localOnly bool, purpose string, provider interface{}) error {
+	decision := domain.ResidencyDecision{
+		Subject:   subject,
+		Purpose:   purpose,
+		Location:  locationOf(provider),
+		LocalOnly: localOnly,
+	}
+	if decision.LocalOnly {
+		log.Printf("Local-only policy: %s", decision)
+	}
+	return decision.Err()
+}
+
+// locationOf returns where a provider keeps content, providers that do not tell are treated as cloud providers
+func locationOf(provider interface{}) domain.DataLocation {
+	if locator, ok := provider.(ports.DataLocator); ok && locator.DataLocation() == domain.DataLocationLocal {
+		return domain.DataLocationLocal
+	}
+	return domain.DataLocationCloud
+}
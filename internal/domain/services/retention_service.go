@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"time"
+)
+
+// DefaultPurgeInterval is how often messages are checked against the retention policy
+const DefaultPurgeInterval = 24 * time.Hour
+
+// retentionActor is the actor of the audit entries recorded by the purge
+const retentionActor = "retention-policy"
+
+// RetentionService purges the raw chat messages kept longer than the retention policy allows, deleting or
+// anonymizing them. Generated documents are left alone, and every purged message is recorded in the audit log.
+type RetentionService struct {
+	messages ports.MessageRepository
+	audit    ports.AuditLog
+	policy   domain.RetentionPolicy
+}
+
+// NewRetentionService creates a RetentionService enforcing the policy
+func NewRetentionService(messages ports.MessageRepository, audit ports.AuditLog, policy domain.RetentionPolicy) *RetentionService {
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if audit == nil {
+		panic("audit log cannot be nil")
+	}
+	if !policy.Action().IsValid() {
+		panic("retention policy cannot be empty")
+	}
+	return &RetentionService{
+		messages: messages,
+		audit:    audit,
+		policy:   policy,
+	}
+}
+
+// Run purges expired messages at each interval until ctx is canceled.
+// Zero interval uses DefaultPurgeInterval. Failed purges are logged and retried at the next interval.
+func (s *RetentionService) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			purged, err := s.Purge(ctx, now)
+			if err != nil {
+				log.Printf("Failed to purge expired messages: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("Purged %d messages older than %d days", purged, s.policy.Days())
+			}
+		}
+	}
+}
+
+// Purge deletes or anonymizes the messages that expired by now, and returns how many it purged.
+// A message that fails does not keep the others from being purged.
+func (s *RetentionService) Purge(ctx context.Context, now time.Time) (int, error) {
+	messages, err := s.messages.FindByState(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	purged := 0
+	var errs []error
+	for _, msg := range messages {
+		if !s.policy.Expired(msg, now) {
+			continue
+		}
+		if err := s.purge(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("message %s: %w", msg.ID(), err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+func (s *RetentionService) purge(ctx context.Context, msg *domain.Message) error {
+	state := msg.State()
+	switch s.policy.Action() {
+	case domain.RetentionDelete:
+		if err := s.messages.Delete(ctx, msg.ID().String()); err != nil {
+			return fmt.Errorf("failed to delete message: %w", err)
+		}
+	case domain.RetentionAnonymize:
+		msg.Anonymize()
+		if err := s.messages.Update(ctx, msg); err != nil {
+			return fmt.Errorf("failed to anonymize message: %w", err)
+		}
+	}
+
+	entry, err := domain.NewAuditEntry(domain.AuditActionPurge, retentionActor, msg.ID().String(), state.String(), s.policy.Action().String())
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	entry.SetCorrelationID(domain.CorrelationIDFrom(ctx))
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record purge: %w", err)
+	}
+	return nil
+}
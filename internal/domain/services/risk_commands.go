@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// RegisterRiskCommands registers the "risks" command listing the risks of the channel's project that are not
+// closed, and the "risk" command recording how a risk is mitigated or closing it
+func RegisterRiskCommands(commands *CommandService, docs *DocumentationService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+
+	commands.Register("risks", "risks", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		project, err := docs.messageProject(ctx, msg)
+		if err != nil {
+			return "", err
+		}
+		if project == nil {
+			return "", fmt.Errorf("this channel is not bound to a project")
+		}
+		register, err := docs.RiskRegisterOf(ctx, project)
+		if err != nil {
+			return "", err
+		}
+		return formatRisks(register, docs.RiskRegisterLink(project)), nil
+	})
+
+	commands.Register("risk", "risk mitigate <id> <how>|close <id>", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		id := cmd.Arg(1)
+		switch strings.ToLower(cmd.Arg(0)) {
+		case "mitigate":
+			mitigation := strings.Join(cmd.Args()[min(2, cmd.ArgCount()):], " ")
+			risk, err := docs.editRiskRegister(ctx, msg, func(register *domain.RiskRegister) (*domain.Risk, error) {
+				return register.Mitigate(id, mitigation)
+			})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("🛡️ Risk *%s* is mitigated: %s", risk.ID(), risk.Mitigation()), nil
+		case "close":
+			risk, err := docs.editRiskRegister(ctx, msg, func(register *domain.RiskRegister) (*domain.Risk, error) {
+				return register.Close(id)
+			})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("✅ Risk *%s* is closed.", risk.ID()), nil
+		default:
+			return "", fmt.Errorf("usage: `%s risk mitigate <id> <how>` or `%s risk close <id>`", domain.CommandPrefix, domain.CommandPrefix)
+		}
+	})
+}
+
+// formatRisks renders the risks of a register that are not closed for a chat reply, most severe first
+func formatRisks(register *domain.RiskRegister, link string) string {
+	var risks []*domain.Risk
+	for _, risk := range register.Risks() {
+		if risk.Status() != domain.RiskClosed {
+			risks = append(risks, risk)
+		}
+	}
+	if len(risks) == 0 {
+		return "No open risks, the register is in " + link
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("⚠️ *%d risks* are open, the register is in %s", len(risks), link))
+	for _, risk := range risks {
+		b.WriteString(fmt.Sprintf("\n• *%s* (%s severity, %s likelihood, %s): %s", risk.ID(), risk.Severity(), risk.Likelihood(), risk.Status(), risk.Statement()))
+		if risk.Owner() != "" {
+			b.WriteString(", owned by " + risk.Owner())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// RiskRegisterService maintains the docs/risks.md register of documentation repositories from the risk
+// statements of captured messages, like "we might miss the deadline if the vendor API slips"
+type RiskRegisterService struct {
+	detector ports.RiskDetector
+}
+
+// NewRiskRegisterService creates a RiskRegisterService. The detector is optional, with it the AI agent finds
+// and rates the risks of a message; without it they are found by their phrasing and rated medium.
+func NewRiskRegisterService(detector ports.RiskDetector) *RiskRegisterService {
+	return &RiskRegisterService{
+		detector: detector,
+	}
+}
+
+// Update adds the risks a message raises to the register of the store, owned by the sender unless the
+// message names their owner, and links them to the message's document. Messages without risk phrasing are
+// not sent to the detector. It returns the risks added and the rendered register, which is nil when nothing
+// changed. The detector is not asked about messages of local-only projects unless it runs locally.
+func (s *RiskRegisterService) Update(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	msg *domain.Message,
+	docPath string,
+	localOnly bool,
+) ([]*domain.Risk, []byte, error) {
+	text := msg.Content().Text()
+	if !domain.HasRiskCue(text) {
+		return nil, nil, nil
+	}
+
+	register, err := s.Load(ctx, store)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var added []*domain.Risk
+	for _, statement := range s.detect(ctx, msg, localOnly) {
+		risk, err := register.Add(statement, msg.Sender(), docPath, msg.Timestamp())
+		if err != nil {
+			logf(ctx, "Skipping a risk of message %s: %v", msg.ID(), err)
+			continue
+		}
+		if risk != nil {
+			added = append(added, risk)
+		}
+	}
+
+	if len(added) == 0 {
+		return nil, nil, nil
+	}
+	return added, []byte(register.Render()), nil
+}
+
+// Load reads the risk register of a store, a store without one has an empty register
+func (s *RiskRegisterService) Load(ctx context.Context, store ports.DocumentStoreProvider) (*domain.RiskRegister, error) {
+	exists, err := documentExists(ctx, store, domain.RiskRegisterFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find risk register: %w", err)
+	}
+	if !exists {
+		return domain.NewRiskRegister(), nil
+	}
+
+	content, err := store.GetDocument(ctx, domain.RiskRegisterFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk register: %w", err)
+	}
+	return domain.ParseRiskRegister(string(content)), nil
+}
+
+// Save writes the register to a store
+func (s *RiskRegisterService) Save(ctx context.Context, store ports.DocumentStoreProvider, register *domain.RiskRegister) error {
+	if err := writeDocument(ctx, store, domain.RiskRegisterFile, []byte(register.Render())); err != nil {
+		return fmt.Errorf("failed to write risk register: %w", err)
+	}
+	return nil
+}
+
+// detect returns the risk statements of a message, asking the detector when there is one it may be sent to.
+// A failing detector falls back to the statements found by their phrasing.
+func (s *RiskRegisterService) detect(ctx context.Context, msg *domain.Message, localOnly bool) []domain.RiskStatement {
+	text := msg.Content().Text()
+	if s.detector == nil || checkResidency(msg.ChannelID(), localOnly, "risk detection", s.detector) != nil {
+		return domain.DetectRiskStatements(text)
+	}
+
+	statements, err := s.detector.DetectRisks(ctx, text)
+	if err != nil {
+		logf(ctx, "Failed to detect the risks of message %s, falling back to their phrasing: %v", msg.ID(), err)
+		return domain.DetectRiskStatements(text)
+	}
+	return statements
+}
+
+// updateRisks records the risks of a message in the register of the store, if the register is enabled, and
+// adds the register to the files stored with the document. Failures are logged, the document is stored
+// without updating the register.
+func (s *DocumentationService) updateRisks(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	msg *domain.Message,
+	docPath string,
+	docConfig domain.DocumentationConfig,
+	attached map[string][]byte,
+) {
+	if s.risks == nil {
+		return
+	}
+
+	added, content, err := s.risks.Update(ctx, store, msg, docPath, docConfig.LocalOnly)
+	if err != nil {
+		logf(ctx, "Failed to update the risk register with message %s: %v", msg.ID(), err)
+		return
+	}
+	if content != nil {
+		logf(ctx, "Added %d risks of message %s to the risk register", len(added), msg.ID())
+		attached[domain.RiskRegisterFile] = content
+	}
+}
+
+// RiskRegisterOf reads the risk register of a project
+func (s *DocumentationService) RiskRegisterOf(ctx context.Context, project *domain.Project) (*domain.RiskRegister, error) {
+	if s.risks == nil {
+		return nil, fmt.Errorf("the risk register is not enabled")
+	}
+	store, err := s.stores.ForProject(project)
+	if err != nil {
+		return nil, err
+	}
+	return s.risks.Load(ctx, store)
+}
+
+// RiskRegisterLink returns where people can read the risk register of a project
+func (s *DocumentationService) RiskRegisterLink(project *domain.Project) string {
+	store, err := s.stores.ForProject(project)
+	if err != nil {
+		return domain.RiskRegisterFile
+	}
+	if linker, ok := store.(ports.DocumentLinker); ok {
+		return linker.DocumentURL(domain.RiskRegisterFile)
+	}
+	return domain.RiskRegisterFile
+}
+
+// editRiskRegister applies a change to the risk register of the project bound to the channel of a message and
+// stores the register. It returns the risk the change was made to.
+func (s *DocumentationService) editRiskRegister(
+	ctx context.Context,
+	msg *domain.Message,
+	change func(register *domain.RiskRegister) (*domain.Risk, error),
+) (*domain.Risk, error) {
+	if s.risks == nil {
+		return nil, fmt.Errorf("the risk register is not enabled")
+	}
+	project, err := s.messageProject(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("this channel is not bound to a project")
+	}
+	store, err := s.stores.ForProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	register, err := s.risks.Load(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	risk, err := change(register)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.risks.Save(ctx, store, register); err != nil {
+		return nil, err
+	}
+	logf(ctx, "%s set risk %s of project %s to %s", msg.Sender(), risk.ID(), project.Name(), risk.Status())
+	return risk, nil
+}
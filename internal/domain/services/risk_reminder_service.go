@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"sort"
+	"time"
+)
+
+// DefaultRiskReminderInterval is how often owners are reminded of their risks without a mitigation
+const DefaultRiskReminderInterval = 7 * 24 * time.Hour
+
+// RiskReminderService sends the owners of open risks a digest of the risks in their projects' registers that
+// have no mitigation yet
+type RiskReminderService struct {
+	docs        *DocumentationService
+	projects    ports.ProjectRepository
+	chat        ports.ChatAccessProvider
+	coordinator ports.WorkCoordinator
+}
+
+// NewRiskReminderService creates a RiskReminderService. Owners are sent their digest in a direct message when
+// the chat provider implements ports.DirectMessenger, otherwise the digests are posted to the project channels.
+// The coordinator is optional, with it the owners of a project are reminded by the replica owning its first
+// channel only.
+func NewRiskReminderService(
+	docs *DocumentationService,
+	projects ports.ProjectRepository,
+	chat ports.ChatAccessProvider,
+	coordinator ports.WorkCoordinator,
+) *RiskReminderService {
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	return &RiskReminderService{
+		docs:        docs,
+		projects:    projects,
+		chat:        chat,
+		coordinator: coordinator,
+	}
+}
+
+// Run reminds the owners of open risks at each interval until ctx is canceled.
+// Zero interval uses DefaultRiskReminderInterval. Failed reminders are logged and retried at the next interval.
+func (s *RiskReminderService) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultRiskReminderInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.Remind(ctx); err != nil {
+				log.Printf("Failed to remind risk owners: %v", err)
+			}
+		}
+	}
+}
+
+// Remind sends each owner of open risks of an active project the digest of those risks. A project that fails
+// does not keep the owners of the others from being reminded.
+func (s *RiskReminderService) Remind(ctx context.Context) error {
+	projects, err := s.projects.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var errs []error
+	for _, project := range projects {
+		if !project.AcceptsMessages() {
+			continue
+		}
+		if err := s.remindProject(ctx, project); err != nil {
+			errs = append(errs, fmt.Errorf("project %s: %w", project.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *RiskReminderService) remindProject(ctx context.Context, project *domain.Project) error {
+	if owned, err := s.owns(ctx, project); err != nil || !owned {
+		return err
+	}
+	register, err := s.docs.RiskRegisterOf(ctx, project)
+	if err != nil {
+		return err
+	}
+	unmitigated := register.Unmitigated()
+	if len(unmitigated) == 0 {
+		return nil
+	}
+
+	owners := make([]string, 0, len(unmitigated))
+	for owner := range unmitigated {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	link := s.docs.RiskRegisterLink(project)
+	messenger, direct := s.chat.(ports.DirectMessenger)
+	var errs []error
+	for _, owner := range owners {
+		reminder := domain.RenderRiskReminder(owner, unmitigated[owner], link)
+		if direct && owner != "" {
+			if _, err := messenger.SendDirect(ctx, owner, reminder); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remind %s: %w", owner, err))
+			}
+			continue
+		}
+		// Risks nobody owns, or owners who cannot be written to directly, are brought up in the project channels
+		for _, channelID := range project.Channels() {
+			if err := s.chat.SendMessage(ctx, channelID, reminder); err != nil {
+				errs = append(errs, fmt.Errorf("failed to post risk reminder to %s: %w", channelID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// owns checks if this replica reminds the owners of a project, the one owning its first channel
+func (s *RiskReminderService) owns(ctx context.Context, project *domain.Project) (bool, error) {
+	channels := project.Channels()
+	if s.coordinator == nil || len(channels) == 0 {
+		return true, nil
+	}
+	owned, err := s.coordinator.Owns(ctx, channels[0])
+	if err != nil {
+		return false, fmt.Errorf("failed to check ownership of channel %s: %w", channels[0], err)
+	}
+	return owned, nil
+}
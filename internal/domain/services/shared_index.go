@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"sort"
+	"strings"
+)
+
+// SharedIndex aggregates the decisions projects share for org-wide search, and filters the document index down to
+// what a project may see. Visibility is worked out on every call, so changing a project's setting applies to its
+// documents at once.
+type SharedIndex struct {
+	index    ports.DocumentIndex
+	projects ports.ProjectRepository
+}
+
+// NewSharedIndex creates a SharedIndex over the document index
+func NewSharedIndex(index ports.DocumentIndex, projects ports.ProjectRepository) *SharedIndex {
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	return &SharedIndex{
+		index:    index,
+		projects: projects,
+	}
+}
+
+// Visible returns the indexed documents that can be found from the viewer project, a zero ID for
+// channels outside of projects
+func (s *SharedIndex) Visible(ctx context.Context, viewer common.ID) ([]*domain.IndexedDocument, error) {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+	owners, err := s.owners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var visible []*domain.IndexedDocument
+	for _, doc := range docs {
+		if domain.VisibleTo(doc, owners[doc.Project().String()], viewer) {
+			visible = append(visible, doc)
+		}
+	}
+	return visible, nil
+}
+
+// IsVisible checks if a single document can be found from the viewer project
+func (s *SharedIndex) IsVisible(ctx context.Context, doc *domain.IndexedDocument, viewer common.ID) (bool, error) {
+	if doc.Project().String() == "" || doc.Project() == viewer {
+		return true, nil
+	}
+	owner, err := s.projects.FindByID(ctx, doc.Project())
+	if err != nil && !errors.Is(err, ports.ErrNotFound) {
+		return false, fmt.Errorf("failed to find project: %w", err)
+	}
+	return domain.VisibleTo(doc, owner, viewer), nil
+}
+
+// Decisions returns the shared decisions of every project, newest first. A query keeps the decisions
+// matching it, best matches first.
+func (s *SharedIndex) Decisions(ctx context.Context, query string, limit int) ([]domain.SharedDecision, error) {
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+	owners, err := s.owners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		decision domain.SharedDecision
+		score    float64
+	}
+	query = strings.TrimSpace(query)
+	var matches []scored
+	for _, doc := range docs {
+		owner := owners[doc.Project().String()]
+		if !doc.Type().IsDecision() || domain.EffectiveVisibility(doc, owner) != domain.VisibilityShared {
+			continue
+		}
+		score := 1.0
+		if query != "" {
+			if score = domain.TextSimilarity(query, doc.SearchText()); score <= 0 {
+				continue
+			}
+		}
+		decision := domain.SharedDecision{Document: doc}
+		if owner != nil {
+			decision.Project = owner.Name()
+		}
+		matches = append(matches, scored{decision: decision, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].decision.Document.CreatedAt().After(matches[j].decision.Document.CreatedAt())
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	decisions := make([]domain.SharedDecision, len(matches))
+	for i, m := range matches {
+		decisions[i] = m.decision
+	}
+	return decisions, nil
+}
+
+// owners returns the projects by ID
+func (s *SharedIndex) owners(ctx context.Context) (map[string]*domain.Project, error) {
+	projects, err := s.projects.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	owners := make(map[string]*domain.Project, len(projects))
+	for _, project := range projects {
+		owners[project.ID().String()] = project
+	}
+	return owners, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"strings"
+)
+
+// maxSharedResults limits how many shared decisions are listed per command
+const maxSharedResults = 10
+
+// RegisterSharingCommands registers the "visibility" command marking documents internal or shared, and the
+// "shared" command searching the decisions every project shares
+func RegisterSharingCommands(commands *CommandService, docs *DocumentationService, shared *SharedIndex) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if shared == nil {
+		panic("shared index cannot be nil")
+	}
+
+	commands.Register("visibility", "visibility <path> internal|shared", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return setVisibility(ctx, docs, msg, cmd)
+	})
+	commands.Register("shared", "shared [query]", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return searchShared(ctx, docs, shared, strings.Join(cmd.Args(), " "))
+	})
+}
+
+// setVisibility changes the visibility of a document, which only the channels of its project may do
+func setVisibility(ctx context.Context, docs *DocumentationService, msg *domain.Message, cmd *domain.Command) (string, error) {
+	if cmd.ArgCount() != 2 {
+		return "", fmt.Errorf("usage: `%s visibility <path> internal|shared`", domain.CommandPrefix)
+	}
+	path := cmd.Arg(0)
+	visibility, err := domain.ParseVisibility(cmd.Arg(1))
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkProjectChannel(ctx, docs, msg, path, "change its visibility"); err != nil {
+		return "", err
+	}
+
+	if err := docs.SetVisibility(ctx, path, visibility); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("👁️ %s is now %s", docs.DocumentLink(ctx, path), visibility), nil
+}
+
+// checkProjectChannel checks the message was posted in a channel of the project of an indexed document, before
+// the action changes it. Documents written outside of projects can be changed from any channel.
+func checkProjectChannel(ctx context.Context, docs *DocumentationService, msg *domain.Message, path, action string) error {
+	entry, err := docs.index.FindByPath(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	if entry.Project().String() == "" {
+		return nil
+	}
+	project, err := docs.messageProject(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if project == nil || project.ID() != entry.Project() {
+		return fmt.Errorf("only the channels of the document's project can %s", action)
+	}
+	return nil
+}
+
+// viewerProject returns the project the message was posted from, a zero ID for channels outside of projects
+func viewerProject(ctx context.Context, docs *DocumentationService, msg *domain.Message) (common.ID, error) {
+	project, err := docs.messageProject(ctx, msg)
+	if err != nil || project == nil {
+		return common.ID{}, err
+	}
+	return project.ID(), nil
+}
+
+func searchShared(ctx context.Context, docs *DocumentationService, shared *SharedIndex, query string) (string, error) {
+	decisions, err := shared.Decisions(ctx, query, maxSharedResults)
+	if err != nil {
+		return "", err
+	}
+	if len(decisions) == 0 {
+		if query != "" {
+			return fmt.Sprintf("No shared decisions match %q.", query), nil
+		}
+		return "No decisions are shared yet.", nil
+	}
+
+	var b strings.Builder
+	b.WriteString("🌐 Shared decisions:\n")
+	for _, decision := range decisions {
+		project := decision.Project
+		if project == "" {
+			project = "no project"
+		}
+		b.WriteString(fmt.Sprintf("- %s (%s) — %s\n", decision.Document.Title(), project, docs.DocumentLink(ctx, decision.Document.Path())))
+	}
+	return b.String(), nil
+}
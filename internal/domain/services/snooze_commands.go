@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+	"time"
+)
+
+// RegisterSnoozeCommands registers the "snooze" command, sent in a thread to stop capturing it for a while
+// or to resume capturing it
+func RegisterSnoozeCommands(commands *CommandService, snoozes *SnoozeService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if snoozes == nil {
+		panic("snooze service cannot be nil")
+	}
+
+	commands.Register("snooze", "snooze [<duration>|off]", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return snoozeThread(ctx, snoozes, msg, cmd)
+	})
+}
+
+func snoozeThread(ctx context.Context, snoozes *SnoozeService, msg *domain.Message, cmd *domain.Command) (string, error) {
+	threadID := msg.ThreadID().String()
+	if strings.EqualFold(cmd.Arg(0), "off") {
+		if err := snoozes.Resume(ctx, threadID); err != nil {
+			return "", err
+		}
+		return "⏰ Quill captures this thread again.", nil
+	}
+
+	duration := domain.DefaultSnoozeDuration
+	if cmd.ArgCount() > 0 {
+		parsed, err := time.ParseDuration(cmd.Arg(0))
+		if err != nil || parsed <= 0 {
+			return "", fmt.Errorf("usage: `%s snooze [<duration>|off]`, like `%s snooze 4h`", domain.CommandPrefix, domain.CommandPrefix)
+		}
+		duration = parsed
+	}
+
+	snooze, err := snoozes.Snooze(ctx, threadID, msg.Sender(), duration)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("😴 Quill stops capturing this thread until %s. `%s snooze off` resumes it.",
+		snooze.Until().Format("2006-01-02 15:04 UTC"), domain.CommandPrefix), nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"time"
+)
+
+// SnoozeService keeps the threads people asked Quill to stay out of from being captured, for a while with
+// the snooze command or until resumed with the opt-out reaction
+type SnoozeService struct {
+	store    ports.SnoozeStore
+	messages ports.MessageRepository
+}
+
+func NewSnoozeService(store ports.SnoozeStore, messages ports.MessageRepository) *SnoozeService {
+	if store == nil {
+		panic("snooze store cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	return &SnoozeService{
+		store:    store,
+		messages: messages,
+	}
+}
+
+// RegisterOptOut lets people stop capturing a thread with domain.NoDocReaction.
+// It does nothing when the chat provider does not implement ports.CaptureOptOut.
+func RegisterOptOut(chat ports.ChatAccessProvider, snoozes *SnoozeService) {
+	if snoozes == nil {
+		panic("snooze service cannot be nil")
+	}
+	if optOut, ok := chat.(ports.CaptureOptOut); ok {
+		optOut.OnOptOut(snoozes.OptOut)
+	}
+}
+
+// Snooze stops capturing a thread for the duration, zero duration until it is resumed
+func (s *SnoozeService) Snooze(ctx context.Context, threadID, actor string, duration time.Duration) (*domain.ThreadSnooze, error) {
+	snooze, err := domain.NewThreadSnooze(threadID, actor, time.Now(), duration)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Snooze(ctx, snooze); err != nil {
+		return nil, fmt.Errorf("failed to snooze thread: %w", err)
+	}
+	return snooze, nil
+}
+
+// Resume captures a snoozed thread again
+func (s *SnoozeService) Resume(ctx context.Context, threadID string) error {
+	err := s.store.Resume(ctx, threadID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return fmt.Errorf("this thread is not snoozed")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resume thread: %w", err)
+	}
+	return nil
+}
+
+// OptOut stops capturing the thread of a message until it is resumed
+func (s *SnoozeService) OptOut(ctx context.Context, messageID, actor string) error {
+	msg, err := s.messages.FindByID(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to find message: %w", err)
+	}
+	_, err = s.Snooze(ctx, msg.ThreadID().String(), actor, 0)
+	return err
+}
+
+// Snoozed checks if the thread is snoozed now. Snoozes that ended are removed.
+func (s *SnoozeService) Snoozed(ctx context.Context, threadID string) (bool, error) {
+	snooze, err := s.store.Find(ctx, threadID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to find snooze: %w", err)
+	}
+	if snooze.ActiveAt(time.Now()) {
+		return true, nil
+	}
+	if err := s.store.Resume(ctx, threadID); err != nil && !errors.Is(err, ports.ErrNotFound) {
+		return false, fmt.Errorf("failed to remove ended snooze: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultAnalysisTimeout bounds analyzing a message, long enough for a local model on modest hardware
+	DefaultAnalysisTimeout = 2 * time.Minute
+	// DefaultDocumentationTimeout bounds documenting a message, which takes several AI and document store calls
+	DefaultDocumentationTimeout = 3 * time.Minute
+	// DefaultDeliveryTimeout bounds posting a confirmation summary, one chat call
+	DefaultDeliveryTimeout = 30 * time.Second
+)
+
+// StageTimeouts bound the stages of processing a message. Each stage runs under a context derived
+// from the one the message is processed with, so canceling that context also cancels the running stage.
+// Zero fields use the defaults.
+type StageTimeouts struct {
+	// Analysis bounds analyzing the message and resolving its references
+	Analysis time.Duration
+	// Documentation bounds writing the document, from generating it to committing it to the document store
+	Documentation time.Duration
+	// Delivery bounds posting a confirmation summary once its batch is due
+	Delivery time.Duration
+}
+
+// withDefaults fills the unset timeouts with the defaults
+func (t StageTimeouts) withDefaults() StageTimeouts {
+	if t.Analysis <= 0 {
+		t.Analysis = DefaultAnalysisTimeout
+	}
+	if t.Documentation <= 0 {
+		t.Documentation = DefaultDocumentationTimeout
+	}
+	if t.Delivery <= 0 {
+		t.Delivery = DefaultDeliveryTimeout
+	}
+	return t
+}
+
+// runStage runs a stage of processing under its timeout. Running out of time is reported with the
+// stage's name, cancellation of the parent context is returned as it is.
+func runStage(ctx context.Context, stage string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(stageCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s: %w", stage, timeout, err)
+	}
+	return err
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultStandupInterval is how often the standup schedules of projects are checked
+	DefaultStandupInterval = time.Minute
+	// standupCaptureReason is the reason recorded on the direct messages captured as standup answers
+	standupCaptureReason = "captured for standup"
+)
+
+// StandupService runs the daily standups of projects: at the prompt time of a project its members are asked its
+// questions in a direct message, their replies are collected as answers, and at the summary time the answers are
+// stored as the day's standup notes under docs/standups/ and summarized in the project's channels.
+type StandupService struct {
+	store       ports.StandupStore
+	projects    ports.ProjectRepository
+	chat        ports.ChatAccessProvider
+	messages    ports.MessageRepository
+	docs        *DocumentationService
+	tracker     *MessageTracker
+	coordinator ports.WorkCoordinator
+}
+
+// NewStandupService creates a StandupService. Members are only asked when the chat provider implements
+// ports.DirectMessenger. The coordinator is optional, with it the standup of a project is run by the replica
+// owning its first channel only.
+func NewStandupService(
+	store ports.StandupStore,
+	projects ports.ProjectRepository,
+	chat ports.ChatAccessProvider,
+	messages ports.MessageRepository,
+	docs *DocumentationService,
+	tracker *MessageTracker,
+	coordinator ports.WorkCoordinator,
+) *StandupService {
+	if store == nil {
+		panic("standup store cannot be nil")
+	}
+	if projects == nil {
+		panic("project repository cannot be nil")
+	}
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+	if tracker == nil {
+		panic("message tracker cannot be nil")
+	}
+	return &StandupService{
+		store:       store,
+		projects:    projects,
+		chat:        chat,
+		messages:    messages,
+		docs:        docs,
+		tracker:     tracker,
+		coordinator: coordinator,
+	}
+}
+
+// Run checks the standup schedules at each interval until ctx is canceled.
+// Zero interval uses DefaultStandupInterval. Failures are logged and retried at the next interval.
+func (s *StandupService) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultStandupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if err := s.Tick(ctx, now); err != nil {
+				log.Printf("Failed to run standups: %v", err)
+			}
+		}
+	}
+}
+
+// Tick asks the members of the projects whose standup is due and not asked yet, then compiles the standups
+// whose answers are due. A project that fails does not keep the others from their standup.
+func (s *StandupService) Tick(ctx context.Context, now time.Time) error {
+	projects, err := s.projects.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var errs []error
+	for _, project := range projects {
+		if err := s.promptProject(ctx, project, now); err != nil {
+			errs = append(errs, fmt.Errorf("project %s: %w", project.Name(), err))
+		}
+	}
+
+	open, err := s.store.ListOpen(ctx)
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to list open standups: %w", err))...)
+	}
+	for _, standup := range open {
+		if now.Before(standup.DueAt()) {
+			continue
+		}
+		if err := s.compile(ctx, standup, now); err != nil {
+			errs = append(errs, fmt.Errorf("standup %s of project %s: %w", standup.Date(), standup.ProjectID(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// promptProject asks the members of a project its standup questions, once a day between the prompt and summary times
+func (s *StandupService) promptProject(ctx context.Context, project *domain.Project, now time.Time) error {
+	if !project.AcceptsMessages() {
+		return nil
+	}
+	config := project.Standup()
+	promptAt, summaryAt, ok := config.Schedule(now)
+	if !ok || now.Before(promptAt) || !now.Before(summaryAt) {
+		return nil
+	}
+	if owned, err := s.owns(ctx, project); err != nil || !owned {
+		return err
+	}
+
+	_, err := s.store.Find(ctx, project.ID(), promptAt.Format("2006-01-02"))
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ports.ErrNotFound) {
+		return fmt.Errorf("failed to find standup: %w", err)
+	}
+
+	messenger, ok := s.chat.(ports.DirectMessenger)
+	if !ok {
+		return fmt.Errorf("the chat provider cannot send direct messages to ask the standup questions")
+	}
+	standup, err := domain.NewStandup(project.ID(), project.Name(), config.QuestionsOrDefault(), promptAt, summaryAt)
+	if err != nil {
+		return err
+	}
+	// A member who cannot be asked is listed as not answering, the others still are
+	for _, member := range config.Members {
+		channelID, err := messenger.SendDirect(ctx, member, standup.Prompt())
+		if err != nil {
+			log.Printf("Failed to ask %s the standup questions of project %s: %v", member, project.Name(), err)
+			continue
+		}
+		if err := standup.Ask(member, channelID); err != nil {
+			log.Printf("Failed to record the standup prompt of %s: %v", member, err)
+		}
+	}
+	if err := s.store.Save(ctx, standup); err != nil {
+		return fmt.Errorf("failed to save standup: %w", err)
+	}
+	return nil
+}
+
+// Capture records a direct message sent in a conversation members were asked the standup questions in as their
+// answer, it is not analyzed on its own. It reports whether the message was captured.
+func (s *StandupService) Capture(ctx context.Context, msg *domain.Message) (bool, error) {
+	open, err := s.store.ListOpen(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to list open standups: %w", err)
+	}
+
+	captured := false
+	for _, standup := range open {
+		member, ok := standup.AskedIn(msg.ChannelID())
+		if !ok {
+			continue
+		}
+		err := standup.Answer(domain.StandupAnswer{
+			Member:    member,
+			Sender:    msg.Sender(),
+			MessageID: msg.ID().String(),
+			At:        msg.Timestamp(),
+			Text:      msg.Content().Text(),
+		})
+		if err != nil {
+			return false, err
+		}
+		if err := s.store.Save(ctx, standup); err != nil {
+			return false, fmt.Errorf("failed to save standup: %w", err)
+		}
+		captured = true
+	}
+	if !captured {
+		return false, nil
+	}
+	return true, s.tracker.Transition(ctx, msg, domain.MessageStateAnalyzing, standupCaptureReason)
+}
+
+// compile stores the notes of a standup whose answers are due and posts its summary to the project's channels
+func (s *StandupService) compile(ctx context.Context, standup *domain.Standup, now time.Time) error {
+	project, err := s.projects.FindByID(ctx, standup.ProjectID())
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	if owned, err := s.owns(ctx, project); err != nil || !owned {
+		return err
+	}
+
+	if err := standup.Compile(now); err != nil {
+		return err
+	}
+	path, err := s.docs.storeStandup(ctx, project, standup)
+	if err != nil {
+		return err
+	}
+	// The standup is only compiled once its notes are stored, so a failed compilation is retried
+	if err := s.store.Save(ctx, standup); err != nil {
+		return fmt.Errorf("failed to save standup: %w", err)
+	}
+
+	summary := standup.Summary(s.docs.DocumentLink(ctx, path))
+	for _, channelID := range project.Channels() {
+		if err := s.chat.SendMessage(ctx, channelID, summary); err != nil {
+			log.Printf("Failed to post the standup summary of project %s to %s: %v", project.Name(), channelID, err)
+		}
+	}
+
+	for _, answer := range standup.Answers() {
+		msg, err := s.messages.FindByID(ctx, answer.MessageID)
+		if err != nil {
+			continue
+		}
+		if msg.State() != domain.MessageStateAnalyzing || msg.StateReason() != standupCaptureReason {
+			continue
+		}
+		if err := s.tracker.Document(ctx, msg, path, "documented in "+path); err != nil {
+			log.Printf("Failed to mark message %s documented in %s: %v", msg.ID(), path, err)
+		}
+	}
+	return nil
+}
+
+// owns checks if this replica runs the standup of a project, the one owning its first channel
+func (s *StandupService) owns(ctx context.Context, project *domain.Project) (bool, error) {
+	channels := project.Channels()
+	if s.coordinator == nil || len(channels) == 0 {
+		return true, nil
+	}
+	owned, err := s.coordinator.Owns(ctx, channels[0])
+	if err != nil {
+		return false, fmt.Errorf("failed to check ownership of channel %s: %w", channels[0], err)
+	}
+	return owned, nil
+}
+
+// storeStandup stores the notes of a compiled standup in the store of its project and indexes them as a
+// status document
+func (s *DocumentationService) storeStandup(ctx context.Context, project *domain.Project, standup *domain.Standup) (string, error) {
+	docConfig := project.Documentation()
+	store, err := s.stores.ForProject(project)
+	if err != nil {
+		return "", err
+	}
+	path, err := s.uniquePath(ctx, store, standup.Path())
+	if err != nil {
+		return "", err
+	}
+
+	notes := standup.Render()
+	entry, err := domain.NewIndexedDocument(
+		path,
+		domain.TitleFromMarkdown(notes),
+		domain.SummaryFromMarkdown(notes),
+		domain.MessageTypeStatus,
+		domain.CategoryOther,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create index entry: %w", err)
+	}
+	entry.SetTags(domain.NewTags([]string{"standup"}))
+	entry.SetLocation(docConfig.Repository, docConfig.Branch)
+	entry.SetProject(project.ID())
+	if err := s.index.Index(ctx, entry); err != nil {
+		return "", fmt.Errorf("failed to index standup notes: %w", err)
+	}
+
+	fm := domain.NewFrontMatter()
+	standup.Record(fm)
+	metadata := map[string]interface{}{
+		"type":     "standup",
+		"category": domain.CategoryOther.String(),
+	}
+	if err := s.storeWithContents(ctx, store, docConfig, path, fm.Apply(notes), metadata, domain.CategoryOther, nil); err != nil {
+		// Keep the index in line with the store, the notes were not written
+		_ = s.index.Remove(ctx, path)
+		return "", err
+	}
+	return path, nil
+}
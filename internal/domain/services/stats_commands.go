@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+)
+
+// maxContributorsListed limits how many contributors the stats command lists
+const maxContributorsListed = 5
+
+// RegisterStatsCommands registers the "stats" command that reports what the workspace captured
+func RegisterStatsCommands(commands *CommandService, stats *StatsService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if stats == nil {
+		panic("stats service cannot be nil")
+	}
+
+	commands.Register("stats", "stats", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		workspace, err := stats.Stats(ctx)
+		if err != nil {
+			return "", err
+		}
+		return formatWorkspaceStats(workspace), nil
+	})
+}
+
+func formatWorkspaceStats(stats *domain.WorkspaceStats) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📊 %d messages captured, %d documents indexed\n", stats.Captured(), stats.Documents()))
+	if confidence := stats.AverageConfidence(); confidence > 0 {
+		b.WriteString(fmt.Sprintf("Average confidence: %.0f%%\n", confidence*100))
+	}
+
+	writeCounts(&b, "By type", stats.ByType())
+	writeCounts(&b, "By category", stats.ByCategory())
+	writeCounts(&b, "By week", stats.ByWeek())
+	writeCounts(&b, "Top contributors", stats.TopContributors(maxContributorsListed))
+
+	coverage := stats.Coverage()
+	if len(coverage) == 0 {
+		return b.String()
+	}
+	b.WriteString("\nCoverage per channel:\n")
+	for _, channel := range coverage {
+		b.WriteString(fmt.Sprintf("• %s: %.0f%% (%d of %d documented)\n", channel.Channel, channel.Coverage()*100, channel.Documented, channel.Processed))
+	}
+	return b.String()
+}
+
+func writeCounts(b *strings.Builder, title string, counts []domain.StatCount) {
+	if len(counts) == 0 {
+		return
+	}
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		parts[i] = fmt.Sprintf("%s %d", count.Key, count.Count)
+	}
+	b.WriteString(fmt.Sprintf("%s: %s\n", title, strings.Join(parts, ", ")))
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// StatsService computes workspace analytics from the stored messages and the document index
+type StatsService struct {
+	messages ports.MessageRepository
+	index    ports.DocumentIndex
+}
+
+// NewStatsService creates a new StatsService
+func NewStatsService(messages ports.MessageRepository, index ports.DocumentIndex) *StatsService {
+	if messages == nil {
+		panic("message repository cannot be nil")
+	}
+	if index == nil {
+		panic("document index cannot be nil")
+	}
+	return &StatsService{
+		messages: messages,
+		index:    index,
+	}
+}
+
+// Stats returns the statistics of every stored message and indexed document
+func (s *StatsService) Stats(ctx context.Context) (*domain.WorkspaceStats, error) {
+	messages, err := s.messages.FindByState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	docs, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+	return domain.NewWorkspaceStats(messages, docs), nil
+}
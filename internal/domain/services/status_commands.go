@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"strings"
+	"time"
+)
+
+// maxStalledListed limits how many stalled messages the status command lists
+const maxStalledListed = 5
+
+// RegisterStatusCommands registers the "status" command that reports the message pipeline
+func RegisterStatusCommands(commands *CommandService, tracker *MessageTracker) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if tracker == nil {
+		panic("message tracker cannot be nil")
+	}
+
+	commands.Register("status", "status", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		stats, err := tracker.Stats(ctx)
+		if err != nil {
+			return "", err
+		}
+		return formatMessageStats(stats, time.Now()), nil
+	})
+}
+
+func formatMessageStats(stats *domain.MessageStats, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📈 %d messages tracked\n", stats.Total()))
+	for _, state := range domain.MessageStates {
+		b.WriteString(fmt.Sprintf("• %s: %d\n", state, stats.Count(state)))
+	}
+
+	stalled := stats.Stalled()
+	if len(stalled) == 0 {
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("\n⏳ %d stalled:\n", len(stalled)))
+	for i, msg := range stalled {
+		if i == maxStalledListed {
+			b.WriteString(fmt.Sprintf("… and %d more\n", len(stalled)-maxStalledListed))
+			break
+		}
+		b.WriteString(fmt.Sprintf("• %s from %s, %s for %s", msg.ID(), msg.Sender(), msg.State(), now.Sub(msg.StateChangedAt()).Round(time.Minute)))
+		if reason := msg.StateReason(); reason != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", reason))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"strings"
+	"time"
+)
+
+// appendToRollup adds a status update to the weekly rollup of its category and returns the rollup path.
+// The first update of a week creates the rollup. An update the rollup has an entry of already, like a retried or
+// replayed one, is not appended again and false is returned.
+func (s *DocumentationService) appendToRollup(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	docConfig domain.DocumentationConfig,
+	msg *domain.Message,
+	doc string,
+	meeting *domain.Meeting,
+) (string, bool, error) {
+	// Updates go to the week they were posted in, so replayed and backfilled ones are not filed under this week
+	now := msg.Timestamp().UTC()
+	path := domain.StatusRollupPath(msg.Category(), now)
+	entry := domain.RenderStatusEntry(msg, stripTitle(doc), now, meeting)
+	metadata := map[string]interface{}{
+		"type":     msg.Type().String(),
+		"category": msg.Category().String(),
+	}
+
+	exists, err := documentExists(ctx, store, path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up status rollup: %w", err)
+	}
+
+	appended := true
+	if exists {
+		appended, err = s.appendRollupEntry(ctx, store, path, msg, entry, metadata)
+	} else {
+		err = s.startRollup(ctx, store, docConfig, msg, path, entry, now, metadata)
+	}
+	if err != nil || !appended {
+		return path, false, err
+	}
+
+	if err := s.graph.RecordDocument(path, msg); err != nil {
+		return "", false, fmt.Errorf("failed to record document references: %w", err)
+	}
+
+	return path, true, nil
+}
+
+// startRollup stores the rollup of a new week with its first entry
+func (s *DocumentationService) startRollup(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	docConfig domain.DocumentationConfig,
+	msg *domain.Message,
+	path string,
+	entry string,
+	now time.Time,
+	metadata map[string]interface{},
+) error {
+	body := domain.RenderStatusRollup(msg.Category(), now)
+
+	fm := domain.NewFrontMatter()
+	fm.Set("type", msg.Type().String())
+	fm.Set("category", msg.Category().String())
+	fm.Set("rollup", domain.RollupWeekly.String())
+	fm.Set("created_at", now.Format(time.RFC3339))
+	domain.RecordProvenance(fm, msg)
+	content := fm.Apply(body + "\n" + entry)
+
+	if err := s.indexDocument(ctx, path, body, fm, msg, docConfig, ""); err != nil {
+		return err
+	}
+	if err := s.storeWithContents(ctx, store, docConfig, path, content, metadata, msg.Category(), nil); err != nil {
+		_ = s.index.Remove(ctx, path)
+		return err
+	}
+	return nil
+}
+
+// appendRollupEntry adds the entry of a message to the end of an existing rollup, false when the rollup has an
+// entry of the message already
+func (s *DocumentationService) appendRollupEntry(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	path string,
+	msg *domain.Message,
+	entry string,
+	metadata map[string]interface{},
+) (bool, error) {
+	existing, err := store.GetDocument(ctx, path)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve status rollup: %w", err)
+	}
+
+	fm, body, err := domain.ParseFrontMatter(string(existing))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+	}
+	if domain.HasStatusEntry(body, msg) {
+		return false, nil
+	}
+	domain.RecordProvenance(fm, msg)
+
+	// Backlinks stay at the end of the document, below the new entry
+	content := fmt.Sprintf("%s\n\n%s", strings.TrimRight(domain.StripBacklinks(fm.Apply(body)), "\n"), entry)
+	if content, err = s.sign(s.graph.InjectBacklinks(path, content)); err != nil {
+		return false, err
+	}
+
+	metadata["updated_at"] = time.Now().UTC()
+	if err := store.UpdateDocument(ctx, path, []byte(content), metadata); err != nil {
+		return false, fmt.Errorf("failed to update status rollup: %w", err)
+	}
+	s.touchIndexed(ctx, path)
+	return true, nil
+}
+
+// publishDigest publishes the rollups of the week of the message as a digest on the canvas of the channels of the message's
+// project, if the project has digestCanvas turned on and the chat provider has canvases. Failures are logged,
+// the rollups are committed either way.
+func (s *DocumentationService) publishDigest(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	docConfig domain.DocumentationConfig,
+	msg *domain.Message,
+) {
+	if s.canvases == nil || !docConfig.DigestCanvas {
+		return
+	}
+	project, err := s.messageProject(ctx, msg)
+	if err != nil || project == nil {
+		return
+	}
+
+	now := msg.Timestamp().UTC()
+	rollups := make(map[domain.Category]string)
+	for _, category := range domain.DocumentCategories {
+		path := domain.StatusRollupPath(category, now)
+		exists, err := documentExists(ctx, store, path)
+		if err != nil || !exists {
+			continue
+		}
+		content, err := store.GetDocument(ctx, path)
+		if err != nil {
+			logf(ctx, "Failed to read status rollup %s for the digest of project %s: %v", path, project.Name(), err)
+			continue
+		}
+		_, body, err := domain.ParseFrontMatter(string(content))
+		if err != nil {
+			logf(ctx, "Failed to parse status rollup %s for the digest of project %s: %v", path, project.Name(), err)
+			continue
+		}
+		rollups[category] = domain.StripBacklinks(body)
+	}
+
+	digest := domain.RenderDigestCanvas(project.Name(), now, rollups)
+	for _, channelID := range project.Channels() {
+		if err := s.canvases.PublishCanvas(ctx, channelID, digest); err != nil {
+			logf(ctx, "Failed to publish the weekly digest of project %s to %s: %v", project.Name(), channelID, err)
+		}
+	}
+}
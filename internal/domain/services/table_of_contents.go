@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// storeWithContents stores a new document with the files attached to it, like its images and the glossary,
+// and refreshes the INDEX.md of its category and the root SUMMARY.md. Stores supporting batch commits write
+// all files in one commit so the tables of contents never list a missing document, other stores write them
+// one after another.
+func (s *DocumentationService) storeWithContents(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	docConfig domain.DocumentationConfig,
+	path string,
+	content string,
+	metadata map[string]interface{},
+	category domain.Category,
+	attached map[string][]byte,
+) error {
+	contents, err := s.tableOfContents(ctx, docConfig.Repository, docConfig.Branch, category)
+	if err != nil {
+		return err
+	}
+	if content, err = s.sign(content); err != nil {
+		return err
+	}
+
+	if committer, ok := store.(ports.BatchCommitter); ok {
+		files := map[string][]byte{path: []byte(content)}
+		for tocPath, toc := range contents {
+			files[tocPath] = toc
+		}
+		for attachedPath, data := range attached {
+			files[attachedPath] = data
+		}
+		if err := committer.CommitFiles(ctx, files, commitMessage(metadata)); err != nil {
+			return fmt.Errorf("failed to store documentation: %w", err)
+		}
+		return nil
+	}
+
+	// Attached files go first so the document never links to missing files
+	for _, attachedPath := range sortedPaths(attached) {
+		if err := writeDocument(ctx, store, attachedPath, attached[attachedPath]); err != nil {
+			return fmt.Errorf("failed to store %s: %w", attachedPath, err)
+		}
+	}
+	if err := store.StoreDocument(ctx, path, []byte(content), metadata); err != nil {
+		return fmt.Errorf("failed to store documentation: %w", err)
+	}
+
+	for _, tocPath := range sortedPaths(contents) {
+		if err := writeDocument(ctx, store, tocPath, contents[tocPath]); err != nil {
+			return fmt.Errorf("failed to update table of contents: %w", err)
+		}
+	}
+	return nil
+}
+
+// moveWithContents writes a document to its new path, deletes the old one and refreshes the INDEX.md of
+// the categories it moved between and the root SUMMARY.md. Stores supporting batch commits write the new
+// document and the tables of contents in one commit before the old document is deleted.
+func (s *DocumentationService) moveWithContents(
+	ctx context.Context,
+	store ports.DocumentStoreProvider,
+	moved *domain.IndexedDocument,
+	from string,
+	content string,
+	categories ...domain.Category,
+) error {
+	contents, err := s.tableOfContents(ctx, moved.Repository(), moved.Branch(), categories...)
+	if err != nil {
+		return err
+	}
+	if content, err = s.sign(content); err != nil {
+		return err
+	}
+
+	files := map[string][]byte{moved.Path(): []byte(content)}
+	for tocPath, toc := range contents {
+		files[tocPath] = toc
+	}
+	if err := writeFiles(ctx, store, files, fmt.Sprintf("Move %s to %s", filepath.Base(from), moved.Category())); err != nil {
+		return fmt.Errorf("failed to move documentation: %w", err)
+	}
+
+	if moved.Path() == from {
+		return nil
+	}
+	if err := store.DeleteDocument(ctx, from); err != nil {
+		return fmt.Errorf("failed to remove moved documentation: %w", err)
+	}
+	return nil
+}
+
+// removeWithContents deletes a document and refreshes the INDEX.md of its category and the root SUMMARY.md.
+// The document must already be removed from the index.
+func (s *DocumentationService) removeWithContents(ctx context.Context, store ports.DocumentStoreProvider, removed *domain.IndexedDocument) error {
+	contents, err := s.tableOfContents(ctx, removed.Repository(), removed.Branch(), removed.Category())
+	if err != nil {
+		return err
+	}
+
+	if err := store.DeleteDocument(ctx, removed.Path()); err != nil {
+		return fmt.Errorf("failed to remove documentation: %w", err)
+	}
+	if err := writeFiles(ctx, store, contents, fmt.Sprintf("Remove %s", filepath.Base(removed.Path()))); err != nil {
+		return fmt.Errorf("failed to update table of contents: %w", err)
+	}
+	return nil
+}
+
+// writeFiles writes the files in one commit when the store supports it, otherwise one after another
+func writeFiles(ctx context.Context, store ports.DocumentStoreProvider, files map[string][]byte, message string) error {
+	if committer, ok := store.(ports.BatchCommitter); ok {
+		return committer.CommitFiles(ctx, files, message)
+	}
+	for _, path := range sortedPaths(files) {
+		if err := writeDocument(ctx, store, path, files[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableOfContents renders the INDEX.md of the categories and the SUMMARY.md of a repository
+func (s *DocumentationService) tableOfContents(
+	ctx context.Context,
+	repository string,
+	branch string,
+	categories ...domain.Category,
+) (map[string][]byte, error) {
+	indexed, err := s.index.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed documents: %w", err)
+	}
+
+	repository = strings.TrimSpace(repository)
+	branch = strings.TrimSpace(branch)
+	var docs []*domain.IndexedDocument
+	for _, doc := range indexed {
+		if doc.Repository() == repository && doc.Branch() == branch {
+			docs = append(docs, doc)
+		}
+	}
+
+	relations := s.graph.Relations()
+	contents := map[string][]byte{
+		domain.SummaryFile: []byte(domain.RenderSummary(docs, relations)),
+	}
+	for _, category := range categories {
+		contents[domain.CategoryIndexPath(category)] = []byte(domain.RenderCategoryIndex(category, docs, relations))
+	}
+	return contents, nil
+}
+
+// sortedPaths returns the paths of the files in a stable order for writing them one after another
+func sortedPaths(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// writeDocument creates the document or replaces it when it already exists
+func writeDocument(ctx context.Context, store ports.DocumentStoreProvider, path string, content []byte) error {
+	exists, err := documentExists(ctx, store, path)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return store.UpdateDocument(ctx, path, content, nil)
+	}
+	return store.StoreDocument(ctx, path, content, nil)
+}
+
+// documentExists checks if the store holds a document at the path
+func documentExists(ctx context.Context, store ports.DocumentStoreProvider, path string) (bool, error) {
+	existing, err := store.ListDocuments(ctx, filepath.Dir(path))
+	if err != nil {
+		return false, err
+	}
+	for _, doc := range existing {
+		if doc == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// commitMessage describes a new document like the document stores do for single files
+func commitMessage(metadata map[string]interface{}) string {
+	message := "Add documentation"
+	if msgType, ok := metadata["type"].(string); ok {
+		message = fmt.Sprintf("Add %s documentation", msgType)
+	}
+	if category, ok := metadata["category"].(string); ok {
+		message = fmt.Sprintf("%s (%s)", message, category)
+	}
+	return message
+}
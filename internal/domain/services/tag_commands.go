@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"sort"
+	"strings"
+)
+
+// RegisterTagCommands registers the "tags" and "tag" commands backed by the documentation service
+func RegisterTagCommands(commands *CommandService, docs *DocumentationService) {
+	if commands == nil {
+		panic("command service cannot be nil")
+	}
+	if docs == nil {
+		panic("documentation service cannot be nil")
+	}
+
+	commands.Register("tags", "tags", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return listTags(ctx, docs, msg)
+	})
+	commands.Register("tag", "tag add|remove <path> <tag...> | tag list <tag>", func(ctx context.Context, msg *domain.Message, cmd *domain.Command) (string, error) {
+		return editTags(ctx, docs, msg, cmd)
+	})
+}
+
+// listTags lists the tags of the documents visible from the channel's project
+func listTags(ctx context.Context, docs *DocumentationService, msg *domain.Message) (string, error) {
+	viewer, err := viewerProject(ctx, docs, msg)
+	if err != nil {
+		return "", err
+	}
+	counts, err := docs.ListTags(ctx, viewer)
+	if err != nil {
+		return "", err
+	}
+	if len(counts) == 0 {
+		return "No documents are tagged yet.", nil
+	}
+
+	tags := make([]domain.Tag, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+
+	var b strings.Builder
+	b.WriteString("🏷️ Tags in use:\n")
+	for _, tag := range tags {
+		b.WriteString(fmt.Sprintf("- #%s (%d)\n", tag, counts[tag]))
+	}
+	return b.String(), nil
+}
+
+// editTags tags and untags documents, which only the channels of their project may do, or lists the documents
+// visible from the channel's project carrying a tag
+func editTags(ctx context.Context, docs *DocumentationService, msg *domain.Message, cmd *domain.Command) (string, error) {
+	action := strings.ToLower(cmd.Arg(0))
+
+	if action == "list" {
+		tag, err := domain.NewTag(cmd.Arg(1))
+		if err != nil {
+			return "", err
+		}
+		viewer, err := viewerProject(ctx, docs, msg)
+		if err != nil {
+			return "", err
+		}
+		paths, err := docs.FindByTag(ctx, tag, viewer)
+		if err != nil {
+			return "", err
+		}
+		if len(paths) == 0 {
+			return fmt.Sprintf("No documents are tagged #%s.", tag), nil
+		}
+
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("📄 Documents tagged #%s:\n", tag))
+		for _, path := range paths {
+			b.WriteString(fmt.Sprintf("- %s\n", docs.DocumentLink(ctx, path)))
+		}
+		return b.String(), nil
+	}
+
+	if cmd.ArgCount() < 3 {
+		return "", fmt.Errorf("usage: `%s tag add|remove <path> <tag...>`", domain.CommandPrefix)
+	}
+	path := cmd.Arg(1)
+	tags := domain.NewTags(cmd.Args()[2:])
+	if len(tags) == 0 {
+		return "", domain.ErrInvalidTag
+	}
+	if action != "add" && action != "remove" {
+		return "", fmt.Errorf("unknown tag action %q", action)
+	}
+	if err := checkProjectChannel(ctx, docs, msg, path, "tag it"); err != nil {
+		return "", err
+	}
+
+	switch action {
+	case "add":
+		if err := docs.AddTags(ctx, path, tags...); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("✅ Tagged %s with %s", path, formatTags(tags)), nil
+	case "remove":
+		if err := docs.RemoveTags(ctx, path, tags...); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("✅ Removed %s from %s", formatTags(tags), path), nil
+	default:
+		return "", fmt.Errorf("unknown tag action %q", action)
+	}
+}
+
+func formatTags(tags []domain.Tag) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + tag.String()
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"strings"
+	"sync"
+)
+
+// ThreadService keeps the threads messages are posted in, each titled when it starts so documents and digests
+// can tell what the conversation was about
+type ThreadService struct {
+	threads ports.ThreadRepository
+	aiAgent ports.AiAgentProvider
+	// mu keeps concurrent messages of a new thread from each starting it
+	mu sync.Mutex
+}
+
+// NewThreadService creates a ThreadService. Threads are titled by the AI agent when it implements
+// ports.TitleGenerator, and with the first words of their first message otherwise.
+func NewThreadService(threads ports.ThreadRepository, ai ports.AiAgentProvider) *ThreadService {
+	if threads == nil {
+		panic("thread repository cannot be nil")
+	}
+	return &ThreadService{
+		threads: threads,
+		aiAgent: ai,
+	}
+}
+
+// Record adds a message to its thread, starting and titling the thread with its first message. Threads the chat
+// provider started, to find them again after a restart, are titled when their first message is recorded.
+func (s *ThreadService) Record(ctx context.Context, msg *domain.Message) (*domain.Thread, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	thread, err := s.threads.FindByID(ctx, msg.ThreadID())
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		thread, err = domain.NewThread(msg.ThreadID(), msg.ChannelID(), s.title(ctx, msg))
+		if err != nil {
+			return nil, err
+		}
+		return s.save(ctx, thread, msg)
+	case err != nil:
+		return nil, fmt.Errorf("failed to find thread: %w", err)
+	case thread.MessageCount() == 0:
+		if err := thread.Retitle(s.title(ctx, msg)); err != nil {
+			return nil, err
+		}
+		return s.save(ctx, thread, msg)
+	case thread.HasMessage(msg.ID()):
+		return thread, nil
+	}
+
+	if err := s.threads.AppendMessage(ctx, thread.ID(), msg); err != nil {
+		return nil, fmt.Errorf("failed to add message to thread: %w", err)
+	}
+	if err := thread.AddMessage(msg); err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// save stores a thread with its first message
+func (s *ThreadService) save(ctx context.Context, thread *domain.Thread, msg *domain.Message) (*domain.Thread, error) {
+	if err := thread.AddMessage(msg); err != nil {
+		return nil, err
+	}
+	if err := s.threads.Save(ctx, thread); err != nil {
+		return nil, fmt.Errorf("failed to save thread: %w", err)
+	}
+	return thread, nil
+}
+
+// Title returns the title of a thread, empty when the thread is unknown
+func (s *ThreadService) Title(ctx context.Context, id common.ID) string {
+	thread, err := s.threads.FindByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			logf(ctx, "Failed to find thread %s: %v", id, err)
+		}
+		return ""
+	}
+	return thread.Title()
+}
+
+// title asks the AI agent for a short title of the message starting a thread
+func (s *ThreadService) title(ctx context.Context, msg *domain.Message) string {
+	text := msg.Content().Text()
+	if generator, ok := s.aiAgent.(ports.TitleGenerator); ok && strings.TrimSpace(text) != "" {
+		// A thread without a generated title is still kept, under the first words of its message
+		if title, err := generator.GenerateTitle(ctx, text); err == nil && strings.TrimSpace(title) != "" {
+			return title
+		}
+	}
+	return domain.ThreadTitleFrom(text)
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"time"
+)
+
+// DefaultTriageInterval is how often the triage digest is posted
+const DefaultTriageInterval = 24 * time.Hour
+
+// TriageService keeps the messages analysed below their project's minimum confidence out of the documentation
+// until people triage them. Rather than asking in each thread as the messages come, the queue is posted once a
+// day to the channels the messages came from, where each can be categorized or dismissed with one click.
+type TriageService struct {
+	queue         ports.TriageQueue
+	chat          ports.ChatAccessProvider
+	coordinator   ports.WorkCoordinator
+	threads       *ThreadService
+	notifications *NotificationService
+}
+
+// NewTriageService creates a TriageService. The coordinator is optional, with it each channel gets the
+// digest from the replica owning it only. The thread service is optional too, with it the digest names the
+// thread of each message. So is the notification service, with it messages are listed in the channel their
+// category is routed to rather than their own.
+func NewTriageService(
+	queue ports.TriageQueue,
+	chat ports.ChatAccessProvider,
+	coordinator ports.WorkCoordinator,
+	threads *ThreadService,
+	notifications *NotificationService,
+) *TriageService {
+	if queue == nil {
+		panic("triage queue cannot be nil")
+	}
+	if chat == nil {
+		panic("chat provider cannot be nil")
+	}
+	return &TriageService{
+		queue:         queue,
+		chat:          chat,
+		coordinator:   coordinator,
+		threads:       threads,
+		notifications: notifications,
+	}
+}
+
+// RegisterTriage lets people categorize or dismiss the messages of the triage digest in chat.
+// It does nothing when the chat provider does not implement ports.TriageRequester.
+func RegisterTriage(chat ports.ChatAccessProvider, bot *BotService) {
+	if bot == nil {
+		panic("bot service cannot be nil")
+	}
+	if requester, ok := chat.(ports.TriageRequester); ok {
+		requester.OnTriage(bot.Triage)
+	}
+}
+
+// Queue adds an analysed message to the triage queue
+func (s *TriageService) Queue(ctx context.Context, msg *domain.Message) error {
+	item, err := domain.NewTriageItem(msg, time.Now())
+	if err != nil {
+		return err
+	}
+	if s.threads != nil {
+		item.SetThreadTitle(s.threads.Title(ctx, msg.ThreadID()))
+	}
+	if err := s.queue.Add(ctx, item); err != nil {
+		return fmt.Errorf("failed to queue message for triage: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the messages waiting for triage, oldest first
+func (s *TriageService) Pending(ctx context.Context) ([]*domain.TriageItem, error) {
+	items, err := s.queue.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list triage queue: %w", err)
+	}
+	return items, nil
+}
+
+// Take removes a message from the triage queue once someone decided on it
+func (s *TriageService) Take(ctx context.Context, messageID string) (*domain.Message, error) {
+	item, err := s.queue.Take(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	return item.Message(), nil
+}
+
+// Run posts the triage digest at each interval until ctx is canceled.
+// Zero interval uses DefaultTriageInterval. Failed posts are logged and retried at the next interval.
+func (s *TriageService) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultTriageInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.PostDigest(ctx); err != nil {
+				log.Printf("Failed to post triage digest: %v", err)
+			}
+		}
+	}
+}
+
+// PostDigest posts the messages waiting for triage to the channel of each, or the channel their category is
+// routed to. Messages stay queued until someone decides on them, so they are listed again in the next digest.
+// A channel that fails does not keep the others from getting their digest.
+func (s *TriageService) PostDigest(ctx context.Context) error {
+	items, err := s.Pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	var channels []string
+	byChannel := make(map[string][]*domain.TriageItem)
+	for _, item := range items {
+		channelID := s.digestChannel(ctx, item.Message())
+		if _, ok := byChannel[channelID]; !ok {
+			channels = append(channels, channelID)
+		}
+		byChannel[channelID] = append(byChannel[channelID], item)
+	}
+
+	var errs []error
+	for _, channelID := range channels {
+		if err := s.post(ctx, channelID, byChannel[channelID]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channelID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// digestChannel returns the channel a message is listed in, its own when its route cannot be found
+func (s *TriageService) digestChannel(ctx context.Context, msg *domain.Message) string {
+	if s.notifications == nil {
+		return msg.ChannelID()
+	}
+	channelID, err := s.notifications.DigestChannel(ctx, msg)
+	if err != nil {
+		logf(ctx, "Failed to route message %s of the triage digest: %v", msg.ID(), err)
+		return msg.ChannelID()
+	}
+	return channelID
+}
+
+// post sends the digest of a channel owned by this replica, with buttons when the chat provider has them
+func (s *TriageService) post(ctx context.Context, channelID string, items []*domain.TriageItem) error {
+	if s.coordinator != nil {
+		owned, err := s.coordinator.Owns(ctx, channelID)
+		if err != nil {
+			return fmt.Errorf("failed to check ownership of channel %s: %w", channelID, err)
+		}
+		if !owned {
+			return nil
+		}
+	}
+
+	if requester, ok := s.chat.(ports.TriageRequester); ok {
+		if err := requester.RequestTriage(ctx, channelID, items); err != nil {
+			return fmt.Errorf("failed to request triage: %w", err)
+		}
+		return nil
+	}
+	if err := s.chat.SendMessage(ctx, channelID, domain.RenderTriageDigest(items)); err != nil {
+		return fmt.Errorf("failed to post triage digest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
+	"path"
+	"time"
+)
+
+// WikiImportService imports the pages of a knowledge base, like Notion or Confluence, into the documentation
+// repository, for teams moving their knowledge base into git
+type WikiImportService struct {
+	store ports.DocumentStoreProvider
+}
+
+// NewWikiImportService creates a new WikiImportService storing the imported pages in a document store
+func NewWikiImportService(store ports.DocumentStoreProvider) *WikiImportService {
+	if store == nil {
+		panic("document store cannot be nil")
+	}
+	return &WikiImportService{store: store}
+}
+
+// Import reads the pages of a wiki and stores them in the category's directory, nested like they are in the wiki.
+// Pages imported before keep their document, which is updated when the page was edited since. Stores that commit
+// batches get the whole import in a single commit, other stores get the pages one by one, and a page that fails
+// does not keep the others from being imported.
+func (s *WikiImportService) Import(ctx context.Context, wiki ports.Wiki, category domain.Category) (*domain.WikiImportReport, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if wiki == nil {
+		return nil, fmt.Errorf("wiki cannot be nil")
+	}
+	if !category.IsValid() || category.IsUnknown() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidCategory, category)
+	}
+
+	pages, err := wiki.Pages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s pages: %w", wiki.Source(), err)
+	}
+
+	report := &domain.WikiImportReport{Source: wiki.Source(), Pages: len(pages)}
+	importedAt := time.Now().UTC()
+	var pending []pendingImport
+	for _, imported := range domain.PlanWikiImport(pages, path.Join("docs", category.String())) {
+		content := imported.Page.Document(category, imported.ParentPath, importedAt)
+		created, changed, err := s.compare(ctx, imported, content)
+		if err != nil {
+			report.Failures = append(report.Failures, importFailure(imported, err))
+			continue
+		}
+		if !changed {
+			report.Unchanged++
+			continue
+		}
+		pending = append(pending, pendingImport{WikiImport: imported, content: []byte(content), created: created})
+	}
+
+	if err := s.write(ctx, wiki.Source(), category, pending, report); err != nil {
+		return nil, err
+	}
+	report.CompletedAt = time.Now().UTC()
+	log.Printf("Imported %d %s pages: %d documents created, %d updated, %d unchanged, %d failed",
+		report.Pages, report.Source, len(report.Created), len(report.Updated), report.Unchanged, len(report.Failures))
+	return report, nil
+}
+
+// compare checks the document a page goes to, it reports whether the document is new and whether it changes.
+// Documents that are not an import of the page are never overwritten.
+func (s *WikiImportService) compare(ctx context.Context, imported domain.WikiImport, content string) (created, changed bool, err error) {
+	existing, err := s.store.GetDocument(ctx, imported.Path)
+	if errors.Is(err, ports.ErrNotFound) {
+		return true, true, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to read %s: %w", imported.Path, err)
+	}
+
+	fm, _, err := domain.ParseFrontMatter(string(existing))
+	if err != nil || fm.Get("imported_from") != imported.Page.Source().String() || fm.Get("import_id") != imported.Page.ID() {
+		return false, false, fmt.Errorf("%s already holds another document", imported.Path)
+	}
+	return false, !domain.SameImport(string(existing), content), nil
+}
+
+// pendingImport is a page whose document is added or changed
+type pendingImport struct {
+	domain.WikiImport
+	content []byte
+	created bool
+}
+
+// write stores the documents of the imported pages and reports them, in a single commit when the store supports it
+func (s *WikiImportService) write(ctx context.Context, source domain.WikiSource, category domain.Category, pending []pendingImport, report *domain.WikiImportReport) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if committer, ok := s.store.(ports.BatchCommitter); ok {
+		files := make(map[string][]byte, len(pending))
+		for _, imported := range pending {
+			files[imported.Path] = imported.content
+		}
+		if err := committer.CommitFiles(ctx, files, fmt.Sprintf("Import %d pages from %s", len(files), source)); err != nil {
+			return fmt.Errorf("failed to commit imported pages: %w", err)
+		}
+		for _, imported := range pending {
+			reportImport(report, imported)
+		}
+		return nil
+	}
+
+	metadata := map[string]interface{}{
+		"type":     domain.MessageTypeInformation.String(),
+		"category": category.String(),
+	}
+	for _, imported := range pending {
+		write := s.store.UpdateDocument
+		if imported.created {
+			write = s.store.StoreDocument
+		}
+		if err := write(ctx, imported.Path, imported.content, metadata); err != nil {
+			report.Failures = append(report.Failures, importFailure(imported.WikiImport, err))
+			continue
+		}
+		reportImport(report, imported)
+	}
+	return nil
+}
+
+// reportImport adds a stored document to the report
+func reportImport(report *domain.WikiImportReport, imported pendingImport) {
+	if imported.created {
+		report.Created = append(report.Created, imported.Path)
+	} else {
+		report.Updated = append(report.Updated, imported.Path)
+	}
+}
+
+// importFailure reports a page that could not be imported
+func importFailure(imported domain.WikiImport, err error) domain.WikiImportFailure {
+	log.Printf("Failed to import %s page %s: %v", imported.Page.Source(), imported.Page.ID(), err)
+	return domain.WikiImportFailure{PageID: imported.Page.ID(), Title: imported.Page.Title(), Error: err.Error()}
+}
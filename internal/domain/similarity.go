@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stopWords are ignored when comparing texts because they carry no meaning on their own
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "of": true, "and": true, "or": true,
+	"to": true, "in": true, "on": true, "for": true, "from": true, "with": true,
+	"md": true,
+}
+
+// Tokenize splits text into lowercase word tokens, dropping punctuation and stop words
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !stopWords[f] {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// TextSimilarity returns a fuzzy similarity score between 0 and 1 based on shared tokens
+func TextSimilarity(a, b string) float64 {
+	left := tokenSet(Tokenize(a))
+	right := tokenSet(Tokenize(b))
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range left {
+		if right[token] {
+			shared++
+		}
+	}
+
+	// Dice coefficient
+	return 2 * float64(shared) / float64(len(left)+len(right))
+}
+
+// CosineSimilarity returns the cosine similarity of two embedding vectors
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func tokenSet(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		set[t] = true
+	}
+	return set
+}
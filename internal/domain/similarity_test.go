@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "lowercases and splits on punctuation",
+			input: "Adopt Postgres, drop MySQL!",
+			want:  []string{"adopt", "postgres", "drop", "mysql"},
+		},
+		{
+			name:  "drops stop words",
+			input: "the decision of the week",
+			want:  []string{"decision", "week"},
+		},
+		{
+			name:  "splits paths",
+			input: "docs/development/adopt-postgres.md",
+			want:  []string{"docs", "development", "adopt", "postgres"},
+		},
+		{
+			name:  "empty input",
+			input: "",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want float64
+	}{
+		{
+			name: "identical texts",
+			a:    "Adopt Postgres",
+			b:    "adopt postgres",
+			want: 1,
+		},
+		{
+			name: "partial overlap",
+			a:    "adopt postgres",
+			b:    "postgres migration",
+			want: 0.5,
+		},
+		{
+			name: "no overlap",
+			a:    "adopt postgres",
+			b:    "hiring plan",
+			want: 0,
+		},
+		{
+			name: "empty text",
+			a:    "",
+			b:    "hiring plan",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TextSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("TextSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []float64
+		b    []float64
+		want float64
+	}{
+		{
+			name: "same direction",
+			a:    []float64{1, 2, 3},
+			b:    []float64{2, 4, 6},
+			want: 1,
+		},
+		{
+			name: "orthogonal vectors",
+			a:    []float64{1, 0},
+			b:    []float64{0, 1},
+			want: 0,
+		},
+		{
+			name: "different lengths",
+			a:    []float64{1, 0},
+			b:    []float64{1, 0, 0},
+			want: 0,
+		},
+		{
+			name: "zero vector",
+			a:    []float64{0, 0},
+			b:    []float64{1, 0},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CosineSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// NoDocMarker starts the messages that must not be captured
+	NoDocMarker = "!nodoc"
+	// NoDocReaction is the emoji, named without colons, that stops capturing the thread of the message it is added to
+	NoDocReaction = "see_no_evil"
+	// DefaultSnoozeDuration is how long a thread is snoozed when no duration is given
+	DefaultSnoozeDuration = 24 * time.Hour
+)
+
+var ErrInvalidSnooze = errors.New("invalid snooze")
+
+// HasNoDocMarker checks if a message opts out of being captured by starting with NoDocMarker
+func HasNoDocMarker(text string) bool {
+	fields := strings.Fields(text)
+	return len(fields) > 0 && strings.EqualFold(fields[0], NoDocMarker)
+}
+
+// ThreadSnooze stops capturing the messages of a thread, for a while or until someone resumes it
+type ThreadSnooze struct {
+	threadID string
+	actor    string
+	since    time.Time
+	until    time.Time
+}
+
+// NewThreadSnooze snoozes a thread from since for the duration, zero duration snoozes it until it is resumed
+func NewThreadSnooze(threadID, actor string, since time.Time, duration time.Duration) (*ThreadSnooze, error) {
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" {
+		return nil, fmt.Errorf("%w: the thread is required", ErrInvalidSnooze)
+	}
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return nil, fmt.Errorf("%w: who snoozed is unknown", ErrInvalidSnooze)
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("%w: the duration cannot be negative", ErrInvalidSnooze)
+	}
+
+	snooze := &ThreadSnooze{threadID: threadID, actor: actor, since: since.UTC()}
+	if duration > 0 {
+		snooze.until = snooze.since.Add(duration)
+	}
+	return snooze, nil
+}
+
+// ThreadID returns the ID of the snoozed thread
+func (s *ThreadSnooze) ThreadID() string {
+	return s.threadID
+}
+
+// Actor returns who snoozed the thread
+func (s *ThreadSnooze) Actor() string {
+	return s.actor
+}
+
+// Since returns when the thread was snoozed
+func (s *ThreadSnooze) Since() time.Time {
+	return s.since
+}
+
+// Until returns when the snooze ends, zero when it lasts until the thread is resumed
+func (s *ThreadSnooze) Until() time.Time {
+	return s.until
+}
+
+// Indefinite checks if the snooze lasts until the thread is resumed
+func (s *ThreadSnooze) Indefinite() bool {
+	return s.until.IsZero()
+}
+
+// ActiveAt checks if the thread is snoozed at the time
+func (s *ThreadSnooze) ActiveAt(t time.Time) bool {
+	return s.Indefinite() || t.Before(s.until)
+}
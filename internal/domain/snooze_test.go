@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasNoDocMarker(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "!nodoc we decided to fire the vendor", want: true},
+		{text: "  !NoDoc", want: true},
+		{text: "!nodoc\nsalaries are going up", want: true},
+		{text: "!nodocs are fine", want: false},
+		{text: "we decided to use !nodoc markers", want: false},
+		{text: "", want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, HasNoDocMarker(tt.text), tt.text)
+	}
+}
+
+func TestNewThreadSnooze(t *testing.T) {
+	since := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+
+	snooze, err := NewThreadSnooze("T1", "alice", since, DefaultSnoozeDuration)
+	require.NoError(t, err)
+	assert.Equal(t, since.Add(24*time.Hour), snooze.Until())
+	assert.False(t, snooze.Indefinite())
+	assert.True(t, snooze.ActiveAt(since.Add(23*time.Hour)))
+	assert.False(t, snooze.ActiveAt(since.Add(24*time.Hour)))
+
+	forever, err := NewThreadSnooze("T1", "alice", since, 0)
+	require.NoError(t, err)
+	assert.True(t, forever.Indefinite())
+	assert.True(t, forever.ActiveAt(since.Add(365*24*time.Hour)))
+
+	for _, tt := range []struct {
+		threadID, actor string
+		duration        time.Duration
+	}{
+		{threadID: "", actor: "alice"},
+		{threadID: "T1", actor: " "},
+		{threadID: "T1", actor: "alice", duration: -time.Hour},
+	} {
+		_, err := NewThreadSnooze(tt.threadID, tt.actor, since, tt.duration)
+		assert.ErrorIs(t, err, ErrInvalidSnooze, tt)
+	}
+}
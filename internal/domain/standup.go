@@ -0,0 +1,461 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+const (
+	// standupsDir is the directory of docs/ standup notes are stored in
+	standupsDir = "standups"
+	// defaultStandupWindow is how long after the prompt the answers are compiled when no summary time is set
+	defaultStandupWindow = 2 * time.Hour
+	// standupExcerptLength is how much of each answer the channel summary quotes
+	standupExcerptLength = 120
+)
+
+var (
+	ErrInvalidStandupConfig = errors.New("invalid standup config")
+	ErrInvalidStandup       = errors.New("invalid standup")
+	ErrStandupCompiled      = errors.New("standup already compiled")
+)
+
+// DefaultStandupQuestions are asked when a project configures none
+var DefaultStandupQuestions = []string{
+	"What did you get done since the last standup?",
+	"What are you working on today?",
+	"Is anything blocking you?",
+}
+
+// StandupConfig controls the daily standup of a project: on weekdays its members are asked the questions in a
+// direct message, and their answers are compiled into the standup notes of the day
+type StandupConfig struct {
+	// Members are the chat user IDs of the people asked, no standup is run without them
+	Members []string `json:"members,omitempty"`
+	// Questions are asked in the prompt, defaults to DefaultStandupQuestions
+	Questions []string `json:"questions,omitempty"`
+	// PromptAt is the local time members are asked, formatted as 15:04
+	PromptAt string `json:"promptAt,omitempty"`
+	// SummaryAt is the local time the answers are compiled, formatted as 15:04, defaults to two hours after PromptAt
+	SummaryAt string `json:"summaryAt,omitempty"`
+	// Timezone is the IANA name of the time zone of PromptAt and SummaryAt, defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// DefaultStandupConfig returns the standup settings of a new project, no standup is run
+func DefaultStandupConfig() StandupConfig {
+	return StandupConfig{}
+}
+
+// Enabled checks if the project runs a standup
+func (c StandupConfig) Enabled() bool {
+	return len(c.Members) > 0 && strings.TrimSpace(c.PromptAt) != ""
+}
+
+// Validate ensures the standup settings can be applied
+func (c StandupConfig) Validate() error {
+	for _, member := range c.Members {
+		if strings.TrimSpace(member) == "" {
+			return fmt.Errorf("%w: members cannot be empty", ErrInvalidStandupConfig)
+		}
+	}
+	for _, question := range c.Questions {
+		if strings.TrimSpace(question) == "" {
+			return fmt.Errorf("%w: questions cannot be empty", ErrInvalidStandupConfig)
+		}
+	}
+	if len(c.Members) > 0 && strings.TrimSpace(c.PromptAt) == "" {
+		return fmt.Errorf("%w: members are asked at promptAt, it is required", ErrInvalidStandupConfig)
+	}
+	if strings.TrimSpace(c.PromptAt) == "" {
+		return nil
+	}
+	prompt, err := parseClock(c.PromptAt)
+	if err != nil {
+		return fmt.Errorf("%w: prompt time: %v", ErrInvalidStandupConfig, err)
+	}
+	if strings.TrimSpace(c.SummaryAt) != "" {
+		summary, err := parseClock(c.SummaryAt)
+		if err != nil {
+			return fmt.Errorf("%w: summary time: %v", ErrInvalidStandupConfig, err)
+		}
+		if summary <= prompt {
+			return fmt.Errorf("%w: the summary time must be after the prompt time", ErrInvalidStandupConfig)
+		}
+	}
+	if _, err := c.location(); err != nil {
+		return fmt.Errorf("%w: timezone: %v", ErrInvalidStandupConfig, err)
+	}
+	return nil
+}
+
+// QuestionsOrDefault returns the questions members are asked
+func (c StandupConfig) QuestionsOrDefault() []string {
+	if len(c.Questions) == 0 {
+		return append([]string(nil), DefaultStandupQuestions...)
+	}
+	return append([]string(nil), c.Questions...)
+}
+
+// Schedule returns when members are asked and when their answers are compiled on the day of now, in the
+// standup's time zone. There is no standup on weekends or when it is not enabled.
+func (c StandupConfig) Schedule(now time.Time) (time.Time, time.Time, bool) {
+	if !c.Enabled() {
+		return time.Time{}, time.Time{}, false
+	}
+	prompt, err := parseClock(c.PromptAt)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	loc, err := c.location()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	local := now.In(loc)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return time.Time{}, time.Time{}, false
+	}
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	promptAt := midnight.Add(prompt)
+	summaryAt := promptAt.Add(defaultStandupWindow)
+	if summary, err := parseClock(c.SummaryAt); err == nil && strings.TrimSpace(c.SummaryAt) != "" {
+		summaryAt = midnight.Add(summary)
+	}
+	return promptAt, summaryAt, true
+}
+
+func (c StandupConfig) location() (*time.Location, error) {
+	if strings.TrimSpace(c.Timezone) == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(strings.TrimSpace(c.Timezone))
+}
+
+func (c StandupConfig) clone() StandupConfig {
+	c.Members = append([]string(nil), c.Members...)
+	c.Questions = append([]string(nil), c.Questions...)
+	return c
+}
+
+// StandupAnswer is a direct message a member sent in answer to the standup prompt
+type StandupAnswer struct {
+	// Member is the chat user ID of the member
+	Member string
+	// Sender is the name the member's message was sent under
+	Sender string
+	// MessageID is the ID of the message, empty when it was not tracked
+	MessageID string
+	At        time.Time
+	Text      string
+}
+
+// Standup is the standup of a project on one day: the members asked, the direct messages they were asked in and
+// their answers until it is compiled
+type Standup struct {
+	projectID   common.ID
+	projectName string
+	date        string
+	questions   []string
+	askedAt     time.Time
+	dueAt       time.Time
+	members     []string
+	channels    map[string]string
+	answers     []StandupAnswer
+	compiledAt  time.Time
+}
+
+// NewStandup starts the standup of a project asked at askedAt, whose answers are compiled at dueAt. The day of
+// the standup is the day of askedAt in its time zone.
+func NewStandup(projectID common.ID, projectName string, questions []string, askedAt, dueAt time.Time) (*Standup, error) {
+	if projectID.String() == "" {
+		return nil, fmt.Errorf("%w: the project is required", ErrInvalidStandup)
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("%w: there are no questions to ask", ErrInvalidStandup)
+	}
+	if !dueAt.After(askedAt) {
+		return nil, fmt.Errorf("%w: the answers are due before members are asked", ErrInvalidStandup)
+	}
+
+	return &Standup{
+		projectID:   projectID,
+		projectName: strings.TrimSpace(projectName),
+		date:        askedAt.Format("2006-01-02"),
+		questions:   append([]string(nil), questions...),
+		askedAt:     askedAt,
+		dueAt:       dueAt,
+		channels:    make(map[string]string),
+	}, nil
+}
+
+// ProjectID returns the project of the standup
+func (s *Standup) ProjectID() common.ID {
+	return s.projectID
+}
+
+// Date returns the day of the standup, formatted as 2006-01-02
+func (s *Standup) Date() string {
+	return s.date
+}
+
+// Questions returns the questions members are asked
+func (s *Standup) Questions() []string {
+	return append([]string(nil), s.questions...)
+}
+
+// DueAt returns when the answers are compiled
+func (s *Standup) DueAt() time.Time {
+	return s.dueAt
+}
+
+// Members returns the members asked, in the order they were asked
+func (s *Standup) Members() []string {
+	return append([]string(nil), s.members...)
+}
+
+// Ask records that a member was asked in the direct message channel channelID, where their answers are posted
+func (s *Standup) Ask(member, channelID string) error {
+	member = strings.TrimSpace(member)
+	channelID = strings.TrimSpace(channelID)
+	if member == "" || channelID == "" {
+		return fmt.Errorf("%w: the member and their channel are required", ErrInvalidStandup)
+	}
+	if _, ok := s.channels[member]; !ok {
+		s.members = append(s.members, member)
+	}
+	s.channels[member] = channelID
+	return nil
+}
+
+// AskedIn returns the member asked in a direct message channel, false when nobody was
+func (s *Standup) AskedIn(channelID string) (string, bool) {
+	for _, member := range s.members {
+		if s.channels[member] == channelID {
+			return member, true
+		}
+	}
+	return "", false
+}
+
+// Answer records a message a member sent in answer to the prompt, members can answer in several messages
+func (s *Standup) Answer(answer StandupAnswer) error {
+	if s.Compiled() {
+		return fmt.Errorf("%w on %s", ErrStandupCompiled, s.compiledAt.Format("2006-01-02 15:04"))
+	}
+	if _, ok := s.channels[answer.Member]; !ok {
+		return fmt.Errorf("%w: %s was not asked", ErrInvalidStandup, answer.Member)
+	}
+	answer.Text = strings.TrimSpace(answer.Text)
+	if answer.Text == "" {
+		return nil
+	}
+	s.answers = append(s.answers, answer)
+	return nil
+}
+
+// Answers returns the answers of the members, in the order they were sent
+func (s *Standup) Answers() []StandupAnswer {
+	return append([]StandupAnswer(nil), s.answers...)
+}
+
+// Compile closes the standup, later answers are not recorded
+func (s *Standup) Compile(at time.Time) error {
+	if s.Compiled() {
+		return fmt.Errorf("%w on %s", ErrStandupCompiled, s.compiledAt.Format("2006-01-02 15:04"))
+	}
+	s.compiledAt = at
+	return nil
+}
+
+// Compiled checks if the answers were compiled
+func (s *Standup) Compiled() bool {
+	return !s.compiledAt.IsZero()
+}
+
+// Path returns where the standup notes are stored, docs/standups/<yyyy-mm-dd>-<slugified-project>.md
+func (s *Standup) Path() string {
+	name := s.date
+	if slug := Slugify(s.projectName); slug != "" {
+		name += "-" + slug
+	}
+	return path.Join(docsRoot, standupsDir, name+".md")
+}
+
+// Title returns the title of the standup notes
+func (s *Standup) Title() string {
+	if s.projectName == "" {
+		return "Standup of " + s.date
+	}
+	return fmt.Sprintf("Standup of %s on %s", s.projectName, s.date)
+}
+
+// Prompt renders the direct message asking a member the questions
+func (s *Standup) Prompt() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("👋 Time for the *%s*! Reply here before %s:\n", s.Title(), s.dueAt.Format("15:04 MST")))
+	for i, question := range s.questions {
+		b.WriteString(fmt.Sprintf("%d. %s\n", i+1, question))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// answered groups the answers by member, in the order members were asked, with the names they answered under
+func (s *Standup) answered() ([]string, map[string]string, map[string][]string) {
+	names := make(map[string]string)
+	texts := make(map[string][]string)
+	for _, answer := range s.answers {
+		names[answer.Member] = answer.Sender
+		texts[answer.Member] = append(texts[answer.Member], answer.Text)
+	}
+	var members []string
+	for _, member := range s.members {
+		if len(texts[member]) > 0 {
+			members = append(members, member)
+		}
+	}
+	return members, names, texts
+}
+
+// Missing returns the members who did not answer
+func (s *Standup) Missing() []string {
+	var missing []string
+	for _, member := range s.members {
+		answered := false
+		for _, answer := range s.answers {
+			if answer.Member == member {
+				answered = true
+				break
+			}
+		}
+		if !answered {
+			missing = append(missing, member)
+		}
+	}
+	return missing
+}
+
+// Render renders the standup notes: the questions, then the answers of each member who answered
+func (s *Standup) Render() string {
+	members, names, texts := s.answered()
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n\n", s.Title()))
+	b.WriteString(fmt.Sprintf("%d of %d members answered.\n\n## Questions\n\n", len(members), len(s.members)))
+	for i, question := range s.questions {
+		b.WriteString(fmt.Sprintf("%d. %s\n", i+1, question))
+	}
+	for _, member := range members {
+		b.WriteString(fmt.Sprintf("\n## %s\n\n", names[member]))
+		b.WriteString(strings.Join(texts[member], "\n\n"))
+		b.WriteString("\n")
+	}
+	if missing := s.Missing(); len(missing) > 0 {
+		b.WriteString("\n## No answer\n\n")
+		for _, member := range missing {
+			b.WriteString(fmt.Sprintf("- %s\n", member))
+		}
+	}
+	return b.String()
+}
+
+// Summary renders the message posted to the project's channels once the standup is compiled, quoting the start
+// of each member's answer and linking to the notes
+func (s *Standup) Summary(link string) string {
+	members, names, texts := s.answered()
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🧍 *%s*: %d of %d answered, the notes are in %s", s.Title(), len(members), len(s.members), link))
+	for _, member := range members {
+		excerpt := strings.Join(strings.Fields(strings.Join(texts[member], " ")), " ")
+		if len([]rune(excerpt)) > standupExcerptLength {
+			excerpt = string([]rune(excerpt)[:standupExcerptLength]) + "…"
+		}
+		b.WriteString(fmt.Sprintf("\n• *%s*: %s", names[member], excerpt))
+	}
+	if missing := s.Missing(); len(missing) > 0 {
+		b.WriteString(fmt.Sprintf("\nNo answer from %s", strings.Join(missing, ", ")))
+	}
+	return b.String()
+}
+
+// StandupPersonKeys returns the front matter keys of standup notes naming people
+func StandupPersonKeys() []string {
+	return []string{"answered_by"}
+}
+
+// StandupBodyNames returns where standup notes name people in their body, for ErasureRequest.ApplyToBody: the
+// headings of the answers and the members who did not answer
+func StandupBodyNames() []string {
+	return []string{"## %s\n", "- %s\n"}
+}
+
+// Erase removes the person of the request from the members asked and their answers, or pseudonymizes them,
+// and reports if the standup named them
+func (s *Standup) Erase(request *ErasureRequest) bool {
+	identity := request.Identity()
+	replacement := ""
+	if request.Mode() == ErasurePseudonymize {
+		replacement = request.Pseudonym()
+	}
+
+	changed := false
+	if channelID, ok := s.channels[identity]; ok {
+		delete(s.channels, identity)
+		members := make([]string, 0, len(s.members))
+		for _, member := range s.members {
+			if member != identity {
+				members = append(members, member)
+			} else if replacement != "" {
+				members = append(members, replacement)
+			}
+		}
+		s.members = members
+		if replacement != "" {
+			s.channels[replacement] = channelID
+		}
+		changed = true
+	}
+
+	answers := make([]StandupAnswer, 0, len(s.answers))
+	for _, answer := range s.answers {
+		if answer.Member != identity && answer.Sender != identity {
+			answers = append(answers, answer)
+			continue
+		}
+		changed = true
+		if replacement == "" {
+			continue
+		}
+		if answer.Member == identity {
+			answer.Member = replacement
+		}
+		if answer.Sender == identity {
+			answer.Sender = replacement
+		}
+		answers = append(answers, answer)
+	}
+	s.answers = answers
+	return changed
+}
+
+// Record adds the standup to the front matter of its notes
+func (s *Standup) Record(fm *FrontMatter) {
+	members, names, _ := s.answered()
+	answered := make([]string, 0, len(members))
+	for _, member := range members {
+		answered = append(answered, names[member])
+	}
+
+	fm.Set("type", MessageTypeStatus.String())
+	fm.Set("created_at", s.compiledAt.UTC().Format(time.RFC3339))
+	fm.Set("project", s.projectID.String())
+	fm.Set("standup_date", s.date)
+	fm.SetList("answered_by", answered)
+	fm.SetList("tags", []string{"standup"})
+}
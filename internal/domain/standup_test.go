@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandupConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  StandupConfig
+		wantErr bool
+	}{
+		{name: "default", config: DefaultStandupConfig()},
+		{name: "scheduled", config: StandupConfig{Members: []string{"U1", "U2"}, PromptAt: "09:30", SummaryAt: "11:00", Timezone: "Europe/Kyiv"}},
+		{name: "members without a prompt time", config: StandupConfig{Members: []string{"U1"}}, wantErr: true},
+		{name: "empty member", config: StandupConfig{Members: []string{" "}, PromptAt: "09:30"}, wantErr: true},
+		{name: "empty question", config: StandupConfig{Members: []string{"U1"}, PromptAt: "09:30", Questions: []string{""}}, wantErr: true},
+		{name: "invalid prompt time", config: StandupConfig{Members: []string{"U1"}, PromptAt: "9am"}, wantErr: true},
+		{name: "summary before prompt", config: StandupConfig{Members: []string{"U1"}, PromptAt: "09:30", SummaryAt: "09:00"}, wantErr: true},
+		{name: "unknown timezone", config: StandupConfig{Members: []string{"U1"}, PromptAt: "09:30", Timezone: "Mars/Olympus"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidStandupConfig)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStandupConfig_Schedule(t *testing.T) {
+	config := StandupConfig{Members: []string{"U1"}, PromptAt: "09:30", Timezone: "Europe/Kyiv"}
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	// Monday 2024-06-10, 05:00 UTC is 08:00 in Kyiv
+	promptAt, summaryAt, ok := config.Schedule(time.Date(2024, 6, 10, 5, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.True(t, promptAt.Equal(time.Date(2024, 6, 10, 9, 30, 0, 0, kyiv)))
+	assert.True(t, summaryAt.Equal(time.Date(2024, 6, 10, 11, 30, 0, 0, kyiv)))
+
+	config.SummaryAt = "17:00"
+	_, summaryAt, _ = config.Schedule(time.Date(2024, 6, 10, 5, 0, 0, 0, time.UTC))
+	assert.True(t, summaryAt.Equal(time.Date(2024, 6, 10, 17, 0, 0, 0, kyiv)))
+
+	_, _, ok = config.Schedule(time.Date(2024, 6, 15, 9, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "no standup on Saturdays")
+	_, _, ok = DefaultStandupConfig().Schedule(time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "no standup without members")
+	assert.Equal(t, DefaultStandupQuestions, config.QuestionsOrDefault())
+}
+
+func TestStandup(t *testing.T) {
+	askedAt := time.Date(2024, 6, 10, 9, 30, 0, 0, time.UTC)
+	standup, err := NewStandup(common.GenerateID(), "Billing", []string{"Yesterday?", "Today?"}, askedAt, askedAt.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-10", standup.Date())
+	assert.Equal(t, "docs/standups/2024-06-10-billing.md", standup.Path())
+	assert.Equal(t, "👋 Time for the *Standup of Billing on 2024-06-10*! Reply here before 11:30 UTC:\n1. Yesterday?\n2. Today?", standup.Prompt())
+
+	require.NoError(t, standup.Ask("U1", "D1"))
+	require.NoError(t, standup.Ask("U2", "D2"))
+	require.NoError(t, standup.Ask("U3", "D3"))
+	member, ok := standup.AskedIn("D2")
+	assert.True(t, ok)
+	assert.Equal(t, "U2", member)
+	_, ok = standup.AskedIn("C1")
+	assert.False(t, ok)
+
+	require.NoError(t, standup.Answer(StandupAnswer{Member: "U2", Sender: "bob", Text: "Shipped the invoice export"}))
+	require.NoError(t, standup.Answer(StandupAnswer{Member: "U1", Sender: "alice", Text: "Reviewed the migration"}))
+	require.NoError(t, standup.Answer(StandupAnswer{Member: "U2", Sender: "bob", Text: "Blocked on the tax API keys"}))
+	assert.ErrorIs(t, standup.Answer(StandupAnswer{Member: "U9", Sender: "eve", Text: "Hi"}), ErrInvalidStandup)
+	assert.Equal(t, []string{"U3"}, standup.Missing())
+
+	rendered := standup.Render()
+	assert.True(t, strings.HasPrefix(rendered, "# Standup of Billing on 2024-06-10\n\n2 of 3 members answered.\n\n## Questions\n\n1. Yesterday?\n2. Today?\n"))
+	assert.Contains(t, rendered, "## alice\n\nReviewed the migration\n\n## bob\n\nShipped the invoice export\n\nBlocked on the tax API keys\n")
+	assert.Contains(t, rendered, "## No answer\n\n- U3\n")
+	assert.Equal(t, "🧍 *Standup of Billing on 2024-06-10*: 2 of 3 answered, the notes are in docs/standups/2024-06-10-billing.md"+
+		"\n• *alice*: Reviewed the migration\n• *bob*: Shipped the invoice export Blocked on the tax API keys\nNo answer from U3",
+		standup.Summary(standup.Path()))
+
+	require.NoError(t, standup.Compile(askedAt.Add(2*time.Hour)))
+	assert.True(t, standup.Compiled())
+	assert.ErrorIs(t, standup.Answer(StandupAnswer{Member: "U3", Sender: "carol", Text: "Late"}), ErrStandupCompiled)
+	assert.ErrorIs(t, standup.Compile(askedAt.Add(3*time.Hour)), ErrStandupCompiled)
+
+	fm := NewFrontMatter()
+	standup.Record(fm)
+	assert.Equal(t, "status", fm.Get("type"))
+	assert.Equal(t, "2024-06-10", fm.Get("standup_date"))
+	assert.Equal(t, []string{"alice", "bob"}, fm.GetList("answered_by"))
+}
+
+func TestStandup_Erase(t *testing.T) {
+	newStandup := func(t *testing.T) *Standup {
+		askedAt := time.Date(2024, 6, 10, 9, 30, 0, 0, time.UTC)
+		standup, err := NewStandup(common.GenerateID(), "Billing", []string{"Today?"}, askedAt, askedAt.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, standup.Ask("U1", "D1"))
+		require.NoError(t, standup.Ask("U2", "D2"))
+		require.NoError(t, standup.Answer(StandupAnswer{Member: "U1", Sender: "U1", Text: "Reviewed the migration"}))
+		require.NoError(t, standup.Answer(StandupAnswer{Member: "U2", Sender: "U2", Text: "Shipped the invoice export"}))
+		return standup
+	}
+
+	t.Run("erase", func(t *testing.T) {
+		standup := newStandup(t)
+		request, err := NewErasureRequest("U1", ErasureErase, "dpo")
+		require.NoError(t, err)
+
+		assert.True(t, standup.Erase(request))
+		assert.Equal(t, []string{"U2"}, standup.Members())
+		require.Len(t, standup.Answers(), 1)
+		assert.Equal(t, "U2", standup.Answers()[0].Member)
+		_, ok := standup.AskedIn("D1")
+		assert.False(t, ok)
+		assert.False(t, standup.Erase(request))
+	})
+
+	t.Run("pseudonymize", func(t *testing.T) {
+		standup := newStandup(t)
+		request, err := NewErasureRequest("U1", ErasurePseudonymize, "dpo")
+		require.NoError(t, err)
+
+		assert.True(t, standup.Erase(request))
+		assert.Equal(t, []string{request.Pseudonym(), "U2"}, standup.Members())
+		assert.Equal(t, request.Pseudonym(), standup.Answers()[0].Member)
+		assert.Equal(t, request.Pseudonym(), standup.Answers()[0].Sender)
+		member, ok := standup.AskedIn("D1")
+		assert.True(t, ok)
+		assert.Equal(t, request.Pseudonym(), member)
+	})
+}
+
+func TestNewStandup_Invalid(t *testing.T) {
+	askedAt := time.Now()
+	_, err := NewStandup(common.GenerateID(), "Billing", nil, askedAt, askedAt.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidStandup)
+	_, err = NewStandup(common.GenerateID(), "Billing", DefaultStandupQuestions, askedAt, askedAt)
+	assert.ErrorIs(t, err, ErrInvalidStandup)
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// RollupPolicy decides whether status updates are collected into periodic documents
+type RollupPolicy string
+
+const (
+	// RollupWeekly appends status updates to one document per category and ISO week. It is the default.
+	RollupWeekly RollupPolicy = "weekly"
+	// RollupNone stores every status update in its own document
+	RollupNone RollupPolicy = "none"
+)
+
+var ErrInvalidRollupPolicy = errors.New("invalid rollup policy")
+
+// NewRollupPolicy creates a RollupPolicy from a string, an empty string selects weekly rollups
+func NewRollupPolicy(s string) (RollupPolicy, error) {
+	policy := RollupPolicy(strings.ToLower(strings.TrimSpace(s)))
+	if policy == "" {
+		return RollupWeekly, nil
+	}
+	if !policy.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidRollupPolicy, s)
+	}
+	return policy, nil
+}
+
+// IsValid checks if the policy is known. The empty policy is the weekly one.
+func (p RollupPolicy) IsValid() bool {
+	return p == "" || p == RollupWeekly || p == RollupNone
+}
+
+// Applies checks if messages of the type are collected into rollups under this policy
+func (p RollupPolicy) Applies(msgType MessageType) bool {
+	return msgType == MessageTypeStatus && p != RollupNone
+}
+
+// String returns the string representation of the policy
+func (p RollupPolicy) String() string {
+	return string(p)
+}
+
+// StatusRollupPath returns the path of the weekly status rollup of a category, like docs/status/development/2024-W23.md
+func StatusRollupPath(category Category, at time.Time) string {
+	year, week := at.UTC().ISOWeek()
+	return path.Join(docsRoot, "status", category.String(), fmt.Sprintf("%d-W%02d.md", year, week))
+}
+
+// RenderStatusRollup renders a new weekly rollup document, entries are appended to it with RenderStatusEntry
+func RenderStatusRollup(category Category, at time.Time) string {
+	year, week := at.UTC().ISOWeek()
+	return fmt.Sprintf("# %s status updates, week %d of %d\n\nStatus updates posted during the week, oldest first.\n",
+		categoryTitle(category), week, year)
+}
+
+// RenderStatusEntry renders one status update of a rollup with the metadata of its message
+func RenderStatusEntry(msg *Message, content string, at time.Time, meeting *Meeting) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("## %s", at.UTC().Format("Mon 2006-01-02 15:04 UTC")))
+	if msg.Sender() != "" {
+		b.WriteString(fmt.Sprintf(" by %s", msg.Sender()))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(fmt.Sprintf("- Source message: `%s`\n", msg.ID()))
+	b.WriteString(statusEntryKey(msg))
+	if threadID := msg.ThreadID().String(); threadID != "" {
+		b.WriteString(fmt.Sprintf("- Thread: `%s`\n", threadID))
+	}
+	if meeting != nil {
+		if meeting.Link() != "" {
+			b.WriteString(fmt.Sprintf("- Meeting: [%s](%s)\n", meeting, meeting.Link()))
+		} else {
+			b.WriteString(fmt.Sprintf("- Meeting: %s\n", meeting))
+		}
+	}
+	if tags := msg.Tags(); len(tags) > 0 {
+		b.WriteString(fmt.Sprintf("- Tags: %s\n", strings.Join(TagStrings(tags), ", ")))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(strings.TrimSpace(content))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// StatusRollupBodyNames returns where rollups name people in their body, for ErasureRequest.ApplyToBody: the
+// sender at the end of the heading of each entry
+func StatusRollupBodyNames() []string {
+	return []string{" by %s\n"}
+}
+
+// HasStatusEntry checks if a rollup has the entry of a message, rendered by RenderStatusEntry. Entries are told
+// apart by the idempotency key of their message, so a retried or replayed update is recognized.
+func HasStatusEntry(rollup string, msg *Message) bool {
+	return strings.Contains(rollup, statusEntryKey(msg))
+}
+
+// statusEntryKey renders the line of a status entry naming the idempotency key of its message
+func statusEntryKey(msg *Message) string {
+	return fmt.Sprintf("- Idempotency key: `%s`\n", MessageIdempotencyKey(msg))
+}
+
+// RenderDigestCanvas renders the weekly digest of a project published to the canvas of its channels, from the
+// bodies of the week's rollups by category. Rollups are listed in the order of DocumentCategories, with their
+// headings moved a level down under the digest's title.
+func RenderDigestCanvas(project string, at time.Time, rollups map[Category]string) string {
+	year, week := at.UTC().ISOWeek()
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Weekly digest of %s, week %d of %d\n\n", project, week, year))
+	b.WriteString(fmt.Sprintf("The status updates of the week, updated %s with each new one.\n", at.UTC().Format("Mon 2006-01-02 15:04 UTC")))
+	for _, category := range DocumentCategories {
+		body, ok := rollups[category]
+		if !ok {
+			continue
+		}
+		b.WriteString("\n")
+		b.WriteString(demoteHeadings(strings.TrimSpace(body)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// demoteHeadings moves the Markdown headings outside code blocks a level down, to the third at most since
+// canvases have no deeper headings
+func demoteHeadings(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		if level < 3 && strings.HasPrefix(line[level:], " ") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
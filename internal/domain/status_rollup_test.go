@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+func TestRollupPolicy_Applies(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RollupPolicy
+		msgType MessageType
+		want    bool
+	}{
+		{name: "default policy rolls up status", policy: "", msgType: MessageTypeStatus, want: true},
+		{name: "weekly policy rolls up status", policy: RollupWeekly, msgType: MessageTypeStatus, want: true},
+		{name: "disabled", policy: RollupNone, msgType: MessageTypeStatus, want: false},
+		{name: "other types are never rolled up", policy: RollupWeekly, msgType: MessageTypeDecision, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Applies(tt.msgType); got != tt.want {
+				t.Errorf("Applies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRollupPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RollupPolicy
+		wantErr error
+	}{
+		{input: "", want: RollupWeekly},
+		{input: " None ", want: RollupNone},
+		{input: "daily", wantErr: ErrInvalidRollupPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NewRollupPolicy(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewRollupPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewRollupPolicy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusRollup(t *testing.T) {
+	at := time.Date(2024, 6, 4, 14, 5, 0, 0, time.UTC)
+
+	if got, want := StatusRollupPath(CategoryDevelopment, at), "docs/status/development/2024-W23.md"; got != want {
+		t.Errorf("StatusRollupPath() = %q, want %q", got, want)
+	}
+	// The ISO week of the first days of January can belong to the previous year
+	if got, want := StatusRollupPath(CategoryProduct, time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)), "docs/status/product/2020-W53.md"; got != want {
+		t.Errorf("StatusRollupPath() = %q, want %q", got, want)
+	}
+
+	if got := RenderStatusRollup(CategoryDevelopment, at); !strings.HasPrefix(got, "# Development status updates, week 23 of 2024\n") {
+		t.Errorf("unexpected rollup heading:\n%s", got)
+	}
+
+	threadID := common.GenerateID()
+	msg, err := NewMessage(threadID, "jane", MustNewMessageContent("API migration is 80% done"), MessageTypeStatus, CategoryDevelopment, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg.AddTags("api")
+
+	meeting, err := NewMeeting("evt-1", "Platform sync", at.Add(-30*time.Minute), at.Add(30*time.Minute), "https://calendar.example.com/evt-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry := RenderStatusEntry(msg, "API migration is 80% done.", at, meeting)
+	for _, want := range []string{
+		"## Tue 2024-06-04 14:05 UTC by jane\n",
+		"- Meeting: [Platform sync (2024-06-04 13:35 UTC)](https://calendar.example.com/evt-1)\n",
+		"- Source message: `" + msg.ID().String() + "`\n",
+		"- Thread: `" + threadID.String() + "`\n",
+		"- Tags: api\n",
+		"- Idempotency key: `" + MessageIdempotencyKey(msg) + "`\n",
+		"\nAPI migration is 80% done.\n",
+	} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("entry does not contain %q:\n%s", want, entry)
+		}
+	}
+
+	rollup := RenderStatusRollup(CategoryDevelopment, at) + "\n" + entry
+	if !HasStatusEntry(rollup, msg) {
+		t.Errorf("HasStatusEntry() = false for the rollup with the entry")
+	}
+	other, err := NewMessage(threadID, "jane", MustNewMessageContent("API migration is done"), MessageTypeStatus, CategoryDevelopment, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if HasStatusEntry(rollup, other) {
+		t.Errorf("HasStatusEntry() = true for another message of the thread")
+	}
+}
+
+func TestRenderDigestCanvas(t *testing.T) {
+	at := time.Date(2024, 6, 4, 14, 5, 0, 0, time.UTC)
+	rollups := map[Category]string{
+		CategoryOperations:  "# Operations status updates, week 23 of 2024\n\n## Tue 2024-06-04 09:00 UTC by joe\n\nRotated the certificates.\n",
+		CategoryDevelopment: "# Development status updates, week 23 of 2024\n\n## Tue 2024-06-04 14:05 UTC by jane\n\n```sh\n# not a heading\n```\n\n### Details\n",
+	}
+
+	got := RenderDigestCanvas("Billing", at, rollups)
+
+	want := "# Weekly digest of Billing, week 23 of 2024\n\n" +
+		"The status updates of the week, updated Tue 2024-06-04 14:05 UTC with each new one.\n\n" +
+		"## Development status updates, week 23 of 2024\n\n### Tue 2024-06-04 14:05 UTC by jane\n\n```sh\n# not a heading\n```\n\n### Details\n\n" +
+		"## Operations status updates, week 23 of 2024\n\n### Tue 2024-06-04 09:00 UTC by joe\n\nRotated the certificates.\n"
+	if got != want {
+		t.Errorf("RenderDigestCanvas() =\n%s\nwant\n%s", got, want)
+	}
+}
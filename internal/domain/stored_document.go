@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// StoredDocument is a document as the bot finds it in the document store, like one people wrote or edited
+// in the repository. Its metadata comes from its front matter, falling back on its path and content.
+type StoredDocument struct {
+	// Path is the path of the document in the store
+	Path string
+	// Title is the first heading, or the file name when the document has none
+	Title string
+	// Summary is the first paragraph line
+	Summary string
+	// Type is the type of message the document records, information unless its front matter says otherwise
+	Type MessageType
+	// Category is the category of the front matter, or the first directory naming a category, or other
+	Category Category
+	// Tags are the tags of the front matter
+	Tags []Tag
+	// Visibility is the visibility of the front matter, empty when the document follows its project's default
+	Visibility Visibility
+	// IdempotencyKey is the idempotency key of the message the document was generated from, see IdempotencyKey
+	IdempotencyKey string
+	// Owner is the username of the person owning the document, empty when nobody does
+	Owner string
+	// Status is the status of the front matter, active when it has none
+	Status DocumentStatus
+	// Sources are the IDs of the messages the document was generated from
+	Sources []string
+	// Links are the paths of the other documents the document links to, outside its Backlinks section
+	Links []string
+	// Relations are the older documents the front matter says the document supersedes or amends
+	Relations []*DocumentRelation
+}
+
+// ParseStoredDocument reads a Markdown document found in the store. Documents with broken front matter are
+// read as if they had none.
+func ParseStoredDocument(docPath, content string) *StoredDocument {
+	fm, body, err := ParseFrontMatter(content)
+	if err != nil {
+		fm, body = NewFrontMatter(), content
+	}
+
+	doc := &StoredDocument{
+		Path:    docPath,
+		Title:   TitleFromMarkdown(body),
+		Summary: SummaryFromMarkdown(body),
+		Type:    MessageTypeInformation,
+		Tags:    NewTags(fm.GetList("tags")),
+		Links:   documentLinks(docPath, StripBacklinks(body)),
+	}
+	if msgType, err := NewMessageType(fm.Get("type")); err == nil && !msgType.IsUnknown() {
+		doc.Type = msgType
+	}
+	doc.Category = storedCategory(docPath, fm.Get("category"))
+	if visibility, err := ParseVisibility(fm.Get("visibility")); err == nil {
+		doc.Visibility = visibility
+	}
+
+	doc.IdempotencyKey = fm.Get("idempotency_key")
+	doc.Owner = OwnerOf(fm)
+	doc.Status = DocumentStatusOf(fm)
+	doc.Sources = fm.GetList("source_messages")
+	if source := fm.Get("source_message"); source != "" && len(doc.Sources) == 0 {
+		doc.Sources = []string{source}
+	}
+	for _, relation := range []Relation{RelationSupersedes, RelationAmends} {
+		for _, older := range fm.GetList(relation.FrontMatterKey()) {
+			if rel, err := NewDocumentRelation(docPath, relation, older); err == nil {
+				doc.Relations = append(doc.Relations, rel)
+			}
+		}
+	}
+	return doc
+}
+
+// IndexEntry returns the document index entry of the document
+func (d *StoredDocument) IndexEntry() (*IndexedDocument, error) {
+	entry, err := NewIndexedDocument(d.Path, d.Title, d.Summary, d.Type, d.Category)
+	if err != nil {
+		return nil, err
+	}
+	entry.SetTags(d.Tags)
+	entry.SetVisibility(d.Visibility)
+	entry.SetIdempotencyKey(d.IdempotencyKey)
+	entry.SetOwner(d.Owner)
+	entry.SetStatus(d.Status)
+	return entry, nil
+}
+
+// storedCategory returns the category of the front matter, or the first directory of the path naming one
+func storedCategory(docPath, declared string) Category {
+	if category, err := NewCategory(declared); err == nil && !category.IsUnknown() {
+		return category
+	}
+	for _, dir := range strings.Split(path.Dir(docPath), "/") {
+		if category, err := NewCategory(dir); err == nil && !category.IsUnknown() {
+			return category
+		}
+	}
+	return CategoryOther
+}
+
+// documentLinks returns the paths of the Markdown documents a document links to with relative links
+func documentLinks(docPath, body string) []string {
+	seen := make(map[string]bool)
+	var links []string
+	for _, match := range linkPattern.FindAllStringSubmatch(body, -1) {
+		if match[1] == "!" {
+			continue
+		}
+		target, err := url.Parse(match[3])
+		if err != nil || target.Scheme != "" || target.Host != "" || !strings.HasSuffix(target.Path, ".md") {
+			continue
+		}
+		linked := path.Clean(path.Join(path.Dir(docPath), target.Path))
+		if strings.HasPrefix(target.Path, "/") {
+			linked = path.Clean(strings.TrimPrefix(target.Path, "/"))
+		}
+		if linked == docPath || seen[linked] {
+			continue
+		}
+		seen[linked] = true
+		links = append(links, linked)
+	}
+	return links
+}
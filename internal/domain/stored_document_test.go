@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStoredDocument(t *testing.T) {
+	content := `---
+type: decision
+category: operations
+tags: [postgres, oncall]
+visibility: shared
+idempotency_key: 9d41c07a
+source_messages: [01HZX, 01HZY]
+---
+# Database Failover
+
+Promote the replica when the primary stops answering.
+
+See [the decision](../adopt-postgres.md), [the runbook](/docs/operations/restore.md#steps),
+[Postgres](https://www.postgresql.org/docs/) and ![the diagram](failover.md).
+Again, [the decision](../adopt-postgres.md).
+`
+
+	doc := ParseStoredDocument("docs/development/runbooks/failover.md", content)
+
+	assert.Equal(t, "Database Failover", doc.Title)
+	assert.Equal(t, "Promote the replica when the primary stops answering.", doc.Summary)
+	assert.Equal(t, MessageTypeDecision, doc.Type)
+	assert.Equal(t, CategoryOperations, doc.Category)
+	assert.Equal(t, []Tag{"postgres", "oncall"}, doc.Tags)
+	assert.Equal(t, VisibilityShared, doc.Visibility)
+	assert.Equal(t, "9d41c07a", doc.IdempotencyKey)
+	assert.Equal(t, []string{"01HZX", "01HZY"}, doc.Sources)
+	assert.Equal(t, []string{"docs/development/adopt-postgres.md", "docs/operations/restore.md"}, doc.Links)
+}
+
+func TestParseStoredDocument_RelationsAndBacklinks(t *testing.T) {
+	content := `---
+supersedes: [docs/development/adopt-mysql.md]
+amends: [docs/development/billing.md]
+---
+# Adopt Postgres
+
+See [the runbook](runbooks/failover.md).
+
+## Backlinks
+
+- [docs/development/adopt-cockroach.md](/docs/development/adopt-cockroach.md)
+`
+
+	doc := ParseStoredDocument("docs/development/adopt-postgres.md", content)
+
+	assert.Equal(t, []string{"docs/development/runbooks/failover.md"}, doc.Links, "backlinks are not links of the document")
+	require.Len(t, doc.Relations, 2)
+	assert.Equal(t, "docs/development/adopt-postgres.md supersedes docs/development/adopt-mysql.md", doc.Relations[0].String())
+	assert.Equal(t, "docs/development/adopt-postgres.md amends docs/development/billing.md", doc.Relations[1].String())
+}
+
+func TestParseStoredDocument_FallsBackOnPathAndContent(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		content      string
+		wantTitle    string
+		wantCategory Category
+		wantSources  []string
+	}{
+		{
+			name:         "no front matter",
+			path:         "docs/product/pricing.md",
+			content:      "# Pricing\n\nPlans are billed monthly.\n",
+			wantTitle:    "Pricing",
+			wantCategory: CategoryProduct,
+		},
+		{
+			name:         "broken front matter",
+			path:         "docs/notes.md",
+			content:      "---\ntags: [a\n# Notes\n",
+			wantTitle:    "Notes",
+			wantCategory: CategoryOther,
+		},
+		{
+			name:         "single source message",
+			path:         "docs/misc/handover.md",
+			content:      "---\nsource_message: 01HZX\ncategory: finance\n---\n# Handover\n",
+			wantTitle:    "Handover",
+			wantCategory: CategoryOther,
+			wantSources:  []string{"01HZX"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseStoredDocument(tt.path, tt.content)
+
+			assert.Equal(t, tt.wantTitle, doc.Title)
+			assert.Equal(t, tt.wantCategory, doc.Category)
+			assert.Equal(t, MessageTypeInformation, doc.Type)
+			assert.ElementsMatch(t, tt.wantSources, doc.Sources)
+		})
+	}
+}
+
+func TestStoredDocument_IndexEntry(t *testing.T) {
+	doc := ParseStoredDocument("docs/operations/failover.md", "---\ntags: [oncall]\nvisibility: shared\nidempotency_key: 9d41c07a\nowner: alice\nstatus: needs-review\n---\n# Failover\n\nPromote the replica.\n")
+
+	entry, err := doc.IndexEntry()
+	require.NoError(t, err)
+
+	assert.Equal(t, "docs/operations/failover.md", entry.Path())
+	assert.Equal(t, "Failover", entry.Title())
+	assert.Equal(t, "Promote the replica.", entry.Summary())
+	assert.Equal(t, CategoryOperations, entry.Category())
+	assert.True(t, entry.HasTag("oncall"))
+	assert.Equal(t, VisibilityShared, entry.Visibility())
+	assert.Equal(t, "9d41c07a", entry.IdempotencyKey())
+	assert.Equal(t, "alice", entry.Owner())
+	assert.Equal(t, DocumentStatusNeedsReview, entry.Status())
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// CategoryIndexFile lists the documents of a category in its directory
+	CategoryIndexFile = "INDEX.md"
+	// SummaryFile lists every category and document at the repository root
+	SummaryFile = "SUMMARY.md"
+)
+
+// CategoryIndexPath returns the path of a category's INDEX.md
+func CategoryIndexPath(category Category) string {
+	return path.Join(docsRoot, category.String(), CategoryIndexFile)
+}
+
+// IsTableOfContents checks if the path is a generated INDEX.md or SUMMARY.md
+func IsTableOfContents(docPath string) bool {
+	name := path.Base(docPath)
+	return name == CategoryIndexFile || name == SummaryFile
+}
+
+// RenderCategoryIndex renders the INDEX.md of a category, newest documents first, noting which documents
+// supersede or amend others. Documents of other categories are left out.
+func RenderCategoryIndex(category Category, docs []*IndexedDocument, relations []*DocumentRelation) string {
+	indexPath := CategoryIndexPath(category)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n\n", categoryTitle(category)))
+	b.WriteString("_This index is maintained by Quill, changes made by hand are overwritten._\n\n")
+
+	listed := documentsIn(category, docs)
+	if len(listed) == 0 {
+		b.WriteString("No documents yet.\n")
+		return b.String()
+	}
+
+	link := func(target string) string {
+		return relativeLink(path.Dir(indexPath), target)
+	}
+	b.WriteString("| Date | Document | Summary |\n|------|----------|---------|\n")
+	for _, doc := range listed {
+		b.WriteString(fmt.Sprintf("| %s | [%s](%s)%s | %s |\n",
+			doc.CreatedAt().UTC().Format("2006-01-02"),
+			tableCell(doc.Title()),
+			link(doc.Path()),
+			tableCell(relationNotes(doc, docs, relations, link)),
+			tableCell(doc.Summary()),
+		))
+	}
+	return b.String()
+}
+
+// RenderSummary renders the root SUMMARY.md listing every category with its documents, newest first,
+// noting which documents supersede or amend others
+func RenderSummary(docs []*IndexedDocument, relations []*DocumentRelation) string {
+	var b strings.Builder
+	b.WriteString("# Summary\n\n")
+
+	link := func(target string) string {
+		return target
+	}
+	for _, category := range summaryCategories(docs) {
+		b.WriteString(fmt.Sprintf("- [%s](%s)\n", categoryTitle(category), CategoryIndexPath(category)))
+		for _, doc := range documentsIn(category, docs) {
+			b.WriteString(fmt.Sprintf("  - [%s](%s)%s\n", doc.Title(), doc.Path(), relationNotes(doc, docs, relations, link)))
+		}
+	}
+	return b.String()
+}
+
+// relationNotes describes the relations of a document to others, like " — _superseded by [Use Postgres](...)_"
+func relationNotes(doc *IndexedDocument, docs []*IndexedDocument, relations []*DocumentRelation, link func(string) string) string {
+	var notes []string
+	for _, rel := range relations {
+		switch doc.Path() {
+		case rel.To():
+			notes = append(notes, fmt.Sprintf("_%s [%s](%s)_", rel.Relation().Inverse(), documentTitle(rel.From(), docs), link(rel.From())))
+		case rel.From():
+			notes = append(notes, fmt.Sprintf("_%s [%s](%s)_", rel.Relation(), documentTitle(rel.To(), docs), link(rel.To())))
+		}
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " — " + strings.Join(notes, ", ")
+}
+
+// documentTitle returns the title a document is indexed under, or its file name when it is not indexed
+func documentTitle(docPath string, docs []*IndexedDocument) string {
+	for _, doc := range docs {
+		if doc.Path() == docPath {
+			return doc.Title()
+		}
+	}
+	return path.Base(docPath)
+}
+
+// documentsIn returns the documents of a category, newest first
+func documentsIn(category Category, docs []*IndexedDocument) []*IndexedDocument {
+	var listed []*IndexedDocument
+	for _, doc := range docs {
+		if doc.Category() == category {
+			listed = append(listed, doc)
+		}
+	}
+	sort.SliceStable(listed, func(i, j int) bool {
+		if !listed[i].CreatedAt().Equal(listed[j].CreatedAt()) {
+			return listed[i].CreatedAt().After(listed[j].CreatedAt())
+		}
+		return listed[i].Path() < listed[j].Path()
+	})
+	return listed
+}
+
+// summaryCategories returns the documentation categories in their usual order followed by any other category in use
+func summaryCategories(docs []*IndexedDocument) []Category {
+	used := make(map[Category]bool)
+	for _, doc := range docs {
+		used[doc.Category()] = true
+	}
+
+	var categories []Category
+	for _, category := range DocumentCategories {
+		if used[category] {
+			categories = append(categories, category)
+			delete(used, category)
+		}
+	}
+
+	var rest []Category
+	for category := range used {
+		rest = append(rest, category)
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i] < rest[j] })
+	return append(categories, rest...)
+}
+
+// categoryTitle turns a category like quality_assurance into "Quality Assurance"
+func categoryTitle(category Category) string {
+	words := strings.Split(category.String(), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// relativeLink returns the path of target relative to the directory dir
+func relativeLink(dir, target string) string {
+	if strings.HasPrefix(target, dir+"/") {
+		return strings.TrimPrefix(target, dir+"/")
+	}
+
+	up := strings.Count(dir, "/") + 1
+	return strings.Repeat("../", up) + target
+}
+
+// tableCell keeps text from breaking the Markdown table it is placed in
+func tableCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\n", " "), "|", "\\|")
+}
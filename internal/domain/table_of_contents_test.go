@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustIndexedDocument(t *testing.T, docPath, title, summary string, category Category) *IndexedDocument {
+	t.Helper()
+	doc, err := NewIndexedDocument(docPath, title, summary, MessageTypeDecision, category)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return doc
+}
+
+func TestRenderCategoryIndex(t *testing.T) {
+	docs := []*IndexedDocument{
+		mustIndexedDocument(t, "docs/development/2024-06-01-adopt-postgres.md", "Adopt Postgres", "We use Postgres | for billing", CategoryDevelopment),
+		mustIndexedDocument(t, "docs/2024/06/02/use-redis.md", "Use Redis", "Caching layer", CategoryDevelopment),
+		mustIndexedDocument(t, "docs/product/2024-06-01-dark-mode.md", "Dark mode", "", CategoryProduct),
+	}
+
+	index := RenderCategoryIndex(CategoryDevelopment, docs, nil)
+
+	for _, want := range []string{
+		"# Development\n",
+		"[Adopt Postgres](2024-06-01-adopt-postgres.md) | We use Postgres \\| for billing |",
+		"[Use Redis](../../docs/2024/06/02/use-redis.md) | Caching layer |",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index does not contain %q:\n%s", want, index)
+		}
+	}
+	if strings.Contains(index, "Dark mode") {
+		t.Errorf("index lists a document of another category:\n%s", index)
+	}
+
+	empty := RenderCategoryIndex(CategoryOperations, docs, nil)
+	if !strings.Contains(empty, "# Operations\n") || !strings.Contains(empty, "No documents yet.") {
+		t.Errorf("unexpected empty index:\n%s", empty)
+	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	docs := []*IndexedDocument{
+		mustIndexedDocument(t, "docs/quality_assurance/2024-06-01-flaky-tests.md", "Flaky tests", "", CategoryQualityAssurance),
+		mustIndexedDocument(t, "docs/development/2024-06-01-adopt-postgres.md", "Adopt Postgres", "", CategoryDevelopment),
+	}
+
+	want := "# Summary\n\n" +
+		"- [Development](docs/development/INDEX.md)\n" +
+		"  - [Adopt Postgres](docs/development/2024-06-01-adopt-postgres.md)\n" +
+		"- [Quality Assurance](docs/quality_assurance/INDEX.md)\n" +
+		"  - [Flaky tests](docs/quality_assurance/2024-06-01-flaky-tests.md)\n"
+
+	if got := RenderSummary(docs, nil); got != want {
+		t.Errorf("RenderSummary() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestIsTableOfContents(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "docs/development/INDEX.md", want: true},
+		{path: "SUMMARY.md", want: true},
+		{path: "docs/development/2024-06-01-adopt-postgres.md", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsTableOfContents(tt.path); got != tt.want {
+				t.Errorf("IsTableOfContents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTablesOfContents_Relations(t *testing.T) {
+	docs := []*IndexedDocument{
+		mustIndexedDocument(t, "docs/development/2024-06-01-use-mysql.md", "Use MySQL", "", CategoryDevelopment),
+		mustIndexedDocument(t, "docs/development/2024-07-01-use-postgres.md", "Use Postgres", "", CategoryDevelopment),
+	}
+	rel, err := NewDocumentRelation("docs/development/2024-07-01-use-postgres.md", RelationSupersedes, "docs/development/2024-06-01-use-mysql.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	relations := []*DocumentRelation{rel}
+
+	index := RenderCategoryIndex(CategoryDevelopment, docs, relations)
+	for _, want := range []string{
+		"[Use MySQL](2024-06-01-use-mysql.md) — _superseded by [Use Postgres](2024-07-01-use-postgres.md)_ |",
+		"[Use Postgres](2024-07-01-use-postgres.md) — _supersedes [Use MySQL](2024-06-01-use-mysql.md)_ |",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index does not contain %q:\n%s", want, index)
+		}
+	}
+
+	summary := RenderSummary(docs, relations)
+	want := "  - [Use MySQL](docs/development/2024-06-01-use-mysql.md) — _superseded by [Use Postgres](docs/development/2024-07-01-use-postgres.md)_\n"
+	if !strings.Contains(summary, want) {
+		t.Errorf("summary does not contain %q:\n%s", want, summary)
+	}
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxTagLength is the maximum allowed tag length
+	MaxTagLength = 50
+
+	// TagArchitecture marks architecture discussions
+	TagArchitecture Tag = "architecture"
+)
+
+var (
+	ErrInvalidTag = errors.New("invalid tag")
+
+	// tagPattern matches normalized tags: lowercase words separated by dashes
+	tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+)
+
+// Tag is a value object that represents a normalized document tag
+type Tag string
+
+// NewTag creates a new Tag from user or AI provided text.
+// Leading '#' is dropped, the text is lowercased and spaces/underscores become dashes.
+func NewTag(t string) (Tag, error) {
+	t = strings.TrimPrefix(strings.TrimSpace(t), "#")
+	t = strings.ToLower(strings.TrimSpace(t))
+	t = strings.NewReplacer(" ", "-", "_", "-").Replace(t)
+	t = strings.Trim(t, "-")
+
+	if t == "" || len(t) > MaxTagLength || !tagPattern.MatchString(t) {
+		return "", ErrInvalidTag
+	}
+	return Tag(t), nil
+}
+
+// MustNewTag creates a new Tag and panics if it is invalid
+func MustNewTag(t string) Tag {
+	tag, err := NewTag(t)
+	if err != nil {
+		panic(err)
+	}
+	return tag
+}
+
+// NewTags converts raw strings into unique tags, skipping invalid entries
+func NewTags(raw []string) []Tag {
+	seen := make(map[Tag]bool, len(raw))
+	tags := make([]Tag, 0, len(raw))
+	for _, r := range raw {
+		tag, err := NewTag(r)
+		if err != nil || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// String returns the string representation of the Tag
+func (t Tag) String() string {
+	return string(t)
+}
+
+// TagStrings converts tags to their string values
+func TagStrings(tags []Tag) []string {
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		values[i] = tag.String()
+	}
+	return values
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      Tag
+		wantError bool
+	}{
+		{
+			name:  "plain tag",
+			input: "postgres",
+			want:  Tag("postgres"),
+		},
+		{
+			name:  "hashtag",
+			input: "#Database",
+			want:  Tag("database"),
+		},
+		{
+			name:  "spaces and underscores become dashes",
+			input: "data_model v2",
+			want:  Tag("data-model-v2"),
+		},
+		{
+			name:      "empty tag",
+			input:     " # ",
+			wantError: true,
+		},
+		{
+			name:      "punctuation",
+			input:     "c++",
+			wantError: true,
+		},
+		{
+			name:      "too long",
+			input:     "abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijk",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := NewTag(tt.input)
+			if tt.wantError {
+				if err != ErrInvalidTag {
+					t.Errorf("expected error %v, got %v", ErrInvalidTag, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tag != tt.want {
+				t.Errorf("NewTag() = %v, want %v", tag, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTags(t *testing.T) {
+	got := NewTags([]string{"Postgres", "#postgres", "", "migration", "c++"})
+	want := []Tag{"postgres", "migration"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewTags() = %v, want %v", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"github.com/massimo-ua/quill/internal/domain/common"
+	"strings"
 	"time"
 )
 
@@ -11,24 +12,39 @@ var (
 	ErrInvalidMessages  = errors.New("invalid messages list")
 )
 
+// UntitledThread titles the threads whose first message has no text, like a shared image
+const UntitledThread = "Untitled thread"
+
+const (
+	// maxThreadTitle bounds the length of thread titles, in runes
+	maxThreadTitle = 80
+	// threadTitleWords is how many words of its first message a thread is titled with when no title is generated
+	threadTitleWords = 8
+)
+
 // Thread represents a conversation thread
 type Thread struct {
-	id        common.ID
-	title     string
-	messages  []*Message
-	createdAt time.Time
-	updatedAt time.Time
+	id         common.ID
+	channelID  string
+	externalID string
+	title      string
+	messages   []*Message
+	createdAt  time.Time
+	updatedAt  time.Time
 }
 
-// NewThread creates a new Thread instance
-func NewThread(title string) (*Thread, error) {
+// NewThread creates a Thread for the thread of a chat, identified as its messages' ThreadID.
+// The title is cut to one line of at most 80 characters.
+func NewThread(id common.ID, channelID, title string) (*Thread, error) {
+	title = cleanThreadTitle(title)
 	if title == "" {
 		return nil, ErrEmptyThreadTitle
 	}
 
 	now := time.Now()
 	return &Thread{
-		id:        common.GenerateID(),
+		id:        id,
+		channelID: channelID,
 		title:     title,
 		messages:  make([]*Message, 0),
 		createdAt: now,
@@ -36,16 +52,93 @@ func NewThread(title string) (*Thread, error) {
 	}, nil
 }
 
+// RestoreThread recreates a stored thread
+func RestoreThread(id common.ID, channelID, externalID, title string, messages []*Message, createdAt, updatedAt time.Time) (*Thread, error) {
+	title = cleanThreadTitle(title)
+	if title == "" {
+		return nil, ErrEmptyThreadTitle
+	}
+	for _, msg := range messages {
+		if msg == nil {
+			return nil, ErrInvalidMessages
+		}
+	}
+	return &Thread{
+		id:         id,
+		channelID:  channelID,
+		externalID: externalID,
+		title:      title,
+		messages:   append([]*Message(nil), messages...),
+		createdAt:  createdAt,
+		updatedAt:  updatedAt,
+	}, nil
+}
+
+// ThreadTitleFrom titles a thread with the first words of its first message, for when no title is generated.
+// Messages without text title it UntitledThread.
+func ThreadTitleFrom(text string) string {
+	firstLine := strings.TrimSpace(text)
+	if idx := strings.Index(firstLine, "\n"); idx >= 0 {
+		firstLine = firstLine[:idx]
+	}
+	words := strings.Fields(firstLine)
+	if len(words) > threadTitleWords {
+		return strings.Join(words[:threadTitleWords], " ") + "…"
+	}
+	if len(words) == 0 {
+		return UntitledThread
+	}
+	return strings.Join(words, " ")
+}
+
+func cleanThreadTitle(title string) string {
+	title = strings.TrimSpace(title)
+	if idx := strings.Index(title, "\n"); idx >= 0 {
+		title = strings.TrimSpace(title[:idx])
+	}
+	if runes := []rune(title); len(runes) > maxThreadTitle {
+		title = strings.TrimSpace(string(runes[:maxThreadTitle-1])) + "…"
+	}
+	return title
+}
+
 // ID returns thread identifier
 func (t *Thread) ID() common.ID {
 	return t.id
 }
 
+// ChannelID returns the channel the thread is in
+func (t *Thread) ChannelID() string {
+	return t.channelID
+}
+
+// ExternalID returns the chat's own ID of the thread, like the timestamp of a Slack thread's first message.
+// It is empty when the chat provider does not keep it.
+func (t *Thread) ExternalID() string {
+	return t.externalID
+}
+
+// SetExternalID sets the chat's own ID of the thread
+func (t *Thread) SetExternalID(externalID string) {
+	t.externalID = externalID
+}
+
 // Title returns thread title
 func (t *Thread) Title() string {
 	return t.title
 }
 
+// Retitle replaces the title of the thread
+func (t *Thread) Retitle(title string) error {
+	title = cleanThreadTitle(title)
+	if title == "" {
+		return ErrEmptyThreadTitle
+	}
+	t.title = title
+	t.updatedAt = time.Now()
+	return nil
+}
+
 // Messages returns thread messages
 func (t *Thread) Messages() []*Message {
 	msgs := make([]*Message, len(t.messages))
@@ -53,17 +146,50 @@ func (t *Thread) Messages() []*Message {
 	return msgs
 }
 
-// AddMessage adds a message to the thread
+// AddMessage adds a message to the thread. A message already in the thread, like a redelivered one, is not
+// added again.
 func (t *Thread) AddMessage(msg *Message) error {
 	if msg == nil {
 		return ErrInvalidMessages
 	}
+	if t.HasMessage(msg.ID()) {
+		return nil
+	}
 
 	t.messages = append(t.messages, msg)
 	t.updatedAt = time.Now()
 	return nil
 }
 
+// HasMessage checks if a message is in the thread
+func (t *Thread) HasMessage(id common.ID) bool {
+	for _, msg := range t.messages {
+		if msg.ID().Equals(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Erase removes the messages the person of the request sent from the thread, or pseudonymizes their sender,
+// and reports if the person sent any
+func (t *Thread) Erase(request *ErasureRequest) bool {
+	changed := false
+	kept := make([]*Message, 0, len(t.messages))
+	for _, msg := range t.messages {
+		if !request.ApplyToMessage(msg) {
+			kept = append(kept, msg)
+			continue
+		}
+		changed = true
+		if request.Mode() == ErasurePseudonymize {
+			kept = append(kept, msg)
+		}
+	}
+	t.messages = kept
+	return changed
+}
+
 // LastMessage returns the most recent message
 func (t *Thread) LastMessage() *Message {
 	if len(t.messages) == 0 {
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewThread(t *testing.T) {
+	id := common.GenerateID()
+	thread, err := NewThread(id, "C0001", "  Adopt Postgres for billing\nsecond line")
+	require.NoError(t, err)
+	assert.Equal(t, id, thread.ID())
+	assert.Equal(t, "C0001", thread.ChannelID())
+	assert.Equal(t, "Adopt Postgres for billing", thread.Title())
+	assert.Zero(t, thread.MessageCount())
+
+	long, err := NewThread(id, "C0001", strings.Repeat("word ", 40))
+	require.NoError(t, err)
+	assert.Len(t, []rune(long.Title()), maxThreadTitle)
+	assert.True(t, strings.HasSuffix(long.Title(), "…"))
+
+	_, err = NewThread(id, "C0001", " \n")
+	assert.ErrorIs(t, err, ErrEmptyThreadTitle)
+}
+
+func TestThread_AddMessage(t *testing.T) {
+	id := common.GenerateID()
+	thread, err := NewThread(id, "C0001", "Billing database")
+	require.NoError(t, err)
+	first, err := NewMessage(id, "alice", MustNewMessageContent("Which database for billing?"), MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+	second, err := NewMessage(id, "bob", MustNewMessageContent("Postgres"), MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, thread.AddMessage(first))
+	require.NoError(t, thread.AddMessage(second))
+	require.NoError(t, thread.AddMessage(first), "a redelivered message is not added again")
+
+	assert.Equal(t, 2, thread.MessageCount())
+	assert.True(t, thread.HasMessage(first.ID()))
+	assert.Equal(t, second, thread.LastMessage())
+	assert.ErrorIs(t, thread.AddMessage(nil), ErrInvalidMessages)
+}
+
+func TestThread_Erase(t *testing.T) {
+	newThread := func(t *testing.T) (*Thread, *Message) {
+		id := common.GenerateID()
+		thread, err := NewThread(id, "C0001", "Billing database")
+		require.NoError(t, err)
+		for _, sender := range []string{"alice", "bob"} {
+			msg, err := NewMessage(id, sender, MustNewMessageContent("Postgres"), MessageTypeUnknown, CategoryUnknown, nil)
+			require.NoError(t, err)
+			require.NoError(t, thread.AddMessage(msg))
+		}
+		return thread, thread.Messages()[1]
+	}
+
+	t.Run("erase", func(t *testing.T) {
+		thread, kept := newThread(t)
+		request, err := NewErasureRequest("alice", ErasureErase, "dpo")
+		require.NoError(t, err)
+
+		assert.True(t, thread.Erase(request))
+		assert.Equal(t, []*Message{kept}, thread.Messages())
+		assert.False(t, thread.Erase(request))
+	})
+
+	t.Run("pseudonymize", func(t *testing.T) {
+		thread, _ := newThread(t)
+		request, err := NewErasureRequest("alice", ErasurePseudonymize, "dpo")
+		require.NoError(t, err)
+
+		assert.True(t, thread.Erase(request))
+		assert.Equal(t, 2, thread.MessageCount())
+		assert.Equal(t, request.Pseudonym(), thread.Messages()[0].Sender())
+	})
+}
+
+func TestThreadTitleFrom(t *testing.T) {
+	assert.Equal(t, "Which database for billing?", ThreadTitleFrom("  Which database for billing?\nI think Postgres"))
+	assert.Equal(t, "one two three four five six seven eight…", ThreadTitleFrom("one two three four five six seven eight nine ten"))
+	assert.Equal(t, UntitledThread, ThreadTitleFrom(" "))
+}
+
+func TestThread_Retitle(t *testing.T) {
+	thread, err := NewThread(common.GenerateID(), "C0001", "Which database for billing?")
+	require.NoError(t, err)
+
+	require.NoError(t, thread.Retitle("Billing database"))
+	assert.Equal(t, "Billing database", thread.Title())
+	assert.ErrorIs(t, thread.Retitle(" "), ErrEmptyThreadTitle)
+	assert.Equal(t, "Billing database", thread.Title())
+}
+
+func TestRestoreThread(t *testing.T) {
+	id := common.GenerateID()
+	msg, err := NewMessage(id, "alice", MustNewMessageContent("Which database for billing?"), MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+	createdAt := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	thread, err := RestoreThread(id, "C0001", "1700000000.000100", "Billing database", []*Message{msg}, createdAt, updatedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "1700000000.000100", thread.ExternalID())
+	assert.Equal(t, []*Message{msg}, thread.Messages())
+	assert.Equal(t, createdAt, thread.CreatedAt())
+	assert.Equal(t, updatedAt, thread.UpdatedAt())
+
+	_, err = RestoreThread(id, "C0001", "", "", nil, createdAt, updatedAt)
+	assert.ErrorIs(t, err, ErrEmptyThreadTitle)
+	_, err = RestoreThread(id, "C0001", "", "Billing database", []*Message{nil}, createdAt, updatedAt)
+	assert.ErrorIs(t, err, ErrInvalidMessages)
+}
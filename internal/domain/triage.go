@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTriage = errors.New("invalid triage")
+
+// TriageDismiss is the choice of a triage decision ignoring the message
+const TriageDismiss = "dismiss"
+
+// maxTriageExcerpt bounds the text of a message quoted in the triage digest
+const maxTriageExcerpt = 200
+
+// TriageItem is a message analysed below its project's minimum confidence, waiting in the triage queue for
+// someone to categorize or dismiss it rather than being documented on a guess
+type TriageItem struct {
+	message     *Message
+	queuedAt    time.Time
+	threadTitle string
+}
+
+// NewTriageItem queues an analysed message for triage
+func NewTriageItem(msg *Message, queuedAt time.Time) (*TriageItem, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("%w: the message is required", ErrInvalidTriage)
+	}
+	return &TriageItem{
+		message:  msg,
+		queuedAt: queuedAt.UTC(),
+	}, nil
+}
+
+// Message returns the queued message, with the type, category and confidence it was analysed with
+func (i *TriageItem) Message() *Message {
+	return i.message
+}
+
+// QueuedAt returns when the message was queued
+func (i *TriageItem) QueuedAt() time.Time {
+	return i.queuedAt
+}
+
+// ThreadTitle returns the title of the thread the message was posted in, empty when it is unknown
+func (i *TriageItem) ThreadTitle() string {
+	return i.threadTitle
+}
+
+// SetThreadTitle sets the title of the thread the message was posted in
+func (i *TriageItem) SetThreadTitle(title string) {
+	i.threadTitle = strings.TrimSpace(title)
+}
+
+// TriageDecision is what a person decided for a message in the triage queue: the category it is documented
+// under, or dismissing it
+type TriageDecision struct {
+	messageID string
+	category  Category
+	actor     string
+}
+
+// NewTriageDecision creates a TriageDecision from the choice of a person, a category or TriageDismiss
+func NewTriageDecision(messageID, choice, actor string) (*TriageDecision, error) {
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return nil, fmt.Errorf("%w: the message is required", ErrInvalidTriage)
+	}
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return nil, fmt.Errorf("%w: who decided is unknown", ErrInvalidTriage)
+	}
+
+	decision := &TriageDecision{messageID: messageID, actor: actor}
+	if choice == TriageDismiss {
+		return decision, nil
+	}
+	category, err := NewCategory(choice)
+	if err != nil || category == CategoryUnknown {
+		return nil, fmt.Errorf("%w: unknown choice %q", ErrInvalidTriage, choice)
+	}
+	decision.category = category
+	return decision, nil
+}
+
+// MessageID returns the ID of the message decided on
+func (d *TriageDecision) MessageID() string {
+	return d.messageID
+}
+
+// Category returns the category the message is documented under, unknown when it is dismissed
+func (d *TriageDecision) Category() Category {
+	return d.category
+}
+
+// Dismissed checks if the message is ignored rather than documented
+func (d *TriageDecision) Dismissed() bool {
+	return d.category == ""
+}
+
+// Actor returns who decided
+func (d *TriageDecision) Actor() string {
+	return d.actor
+}
+
+// RenderTriageDigest lists the messages waiting for triage in a channel, with what each was analysed as
+func RenderTriageDigest(items []*TriageItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🗂️ %d %s could not be documented with confidence. Categorize or dismiss %s:\n",
+		len(items), pluralize(len(items), "message", "messages"), pluralize(len(items), "it", "them"))
+	for _, item := range items {
+		b.WriteString("\n" + RenderTriageItem(item))
+	}
+	return b.String()
+}
+
+// RenderTriageItem quotes a message waiting for triage and tells what it was analysed as
+func RenderTriageItem(item *TriageItem) string {
+	msg := item.Message()
+	excerpt := strings.Join(strings.Fields(msg.Content().Text()), " ")
+	if runes := []rune(excerpt); len(runes) > maxTriageExcerpt {
+		excerpt = string(runes[:maxTriageExcerpt]) + "…"
+	}
+	thread := ""
+	if item.ThreadTitle() != "" {
+		thread = fmt.Sprintf(" in “%s”", item.ThreadTitle())
+	}
+	return fmt.Sprintf("> %s\n%s by %s%s, read as %s in %s (%.0f%% confident)",
+		excerpt, msg.ID(), msg.Sender(), thread, msg.Type(), msg.Category(), msg.Confidence()*100)
+}
+
+func pluralize(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
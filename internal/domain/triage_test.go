@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTriageDecision(t *testing.T) {
+	categorized, err := NewTriageDecision("M1", "operations", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "M1", categorized.MessageID())
+	assert.Equal(t, CategoryOperations, categorized.Category())
+	assert.False(t, categorized.Dismissed())
+	assert.Equal(t, "alice", categorized.Actor())
+
+	dismissed, err := NewTriageDecision("M1", TriageDismiss, "alice")
+	require.NoError(t, err)
+	assert.True(t, dismissed.Dismissed())
+
+	for _, tt := range []struct{ messageID, choice, actor string }{
+		{messageID: "", choice: "operations", actor: "alice"},
+		{messageID: "M1", choice: "operations", actor: " "},
+		{messageID: "M1", choice: "unknown", actor: "alice"},
+		{messageID: "M1", choice: "finance", actor: "alice"},
+	} {
+		_, err := NewTriageDecision(tt.messageID, tt.choice, tt.actor)
+		assert.ErrorIs(t, err, ErrInvalidTriage, tt)
+	}
+}
+
+func TestRenderTriageDigest(t *testing.T) {
+	msg, err := NewMessage(common.GenerateID(), "alice", MustNewMessageContent("Maybe we\nmove billing to Postgres "+strings.Repeat("x", 300)),
+		MessageTypeUnknown, CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.UpdateType(MessageTypeDecision)
+	msg.UpdateCategory(CategoryDevelopment)
+	msg.RecordConfidence(0.45)
+	item, err := NewTriageItem(msg, time.Now())
+	require.NoError(t, err)
+
+	digest := RenderTriageDigest([]*TriageItem{item})
+
+	assert.True(t, strings.HasPrefix(digest, "🗂️ 1 message could not be documented with confidence. Categorize or dismiss it:\n"))
+	assert.Contains(t, digest, "> Maybe we move billing to Postgres xxx")
+	assert.Contains(t, digest, "…\n"+msg.ID().String()+" by alice, read as decision in development (45% confident)")
+
+	item.SetThreadTitle(" Billing database ")
+	assert.Contains(t, RenderTriageItem(item), msg.ID().String()+" by alice in “Billing database”, read as decision")
+
+	_, err = NewTriageItem(nil, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidTriage)
+}
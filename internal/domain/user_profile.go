@@ -0,0 +1,11 @@
+package domain
+
+// UserProfile is what the bot needs to know about a chat user, kept in a cache so each message does not fetch it
+// from the chat again
+type UserProfile struct {
+	// ID is the chat user ID, like the Slack U123
+	ID string
+	// Name is the handle messages are attributed to
+	Name     string
+	RealName string
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+var ErrInvalidVisibility = errors.New("invalid visibility")
+
+// Visibility controls who outside of a project can find its documents
+type Visibility string
+
+const (
+	// VisibilityInternal keeps documents to the project's own channels
+	VisibilityInternal Visibility = "internal"
+	// VisibilityShared lets every project find the documents, and lists decisions in the shared index
+	VisibilityShared Visibility = "shared"
+)
+
+// ParseVisibility returns the visibility named by s, like "internal" or "shared"
+func ParseVisibility(s string) (Visibility, error) {
+	visibility := Visibility(strings.ToLower(strings.TrimSpace(s)))
+	if !visibility.IsValid() {
+		return "", fmt.Errorf("%w: %q, use internal or shared", ErrInvalidVisibility, s)
+	}
+	return visibility, nil
+}
+
+// IsValid checks if the visibility is known
+func (v Visibility) IsValid() bool {
+	return v == VisibilityInternal || v == VisibilityShared
+}
+
+// String returns the visibility
+func (v Visibility) String() string {
+	return string(v)
+}
+
+// EffectiveVisibility returns the visibility a document was marked with, or else the default of its project.
+// Documents written outside of projects are shared. The owner is the project of the document, nil when it
+// has none or the project no longer exists.
+func EffectiveVisibility(doc *IndexedDocument, owner *Project) Visibility {
+	if doc.Visibility() != "" {
+		return doc.Visibility()
+	}
+	if doc.Project().String() == "" {
+		return VisibilityShared
+	}
+	if owner == nil {
+		return VisibilityInternal
+	}
+	return owner.Documentation().DefaultVisibility()
+}
+
+// VisibleTo checks if a document can be found from the viewer project, a zero ID for channels outside of projects.
+// Projects always see their own documents, and the shared documents of others.
+func VisibleTo(doc *IndexedDocument, owner *Project, viewer common.ID) bool {
+	if doc.Project().String() != "" && doc.Project() == viewer {
+		return true
+	}
+	return EffectiveVisibility(doc, owner) == VisibilityShared
+}
+
+// SharedDecision is a decision listed in the shared index, with the name of the project it was made in
+type SharedDecision struct {
+	Document *IndexedDocument
+	// Project is the name of the project, empty for decisions made outside of projects
+	Project string
+}
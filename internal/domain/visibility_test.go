@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+func TestParseVisibility(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Visibility
+		wantErr error
+	}{
+		{input: "internal", want: VisibilityInternal},
+		{input: " Shared ", want: VisibilityShared},
+		{input: "public", wantErr: ErrInvalidVisibility},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVisibility(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseVisibility() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVisibility() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleTo(t *testing.T) {
+	newProject := func(visibility Visibility) *Project {
+		project, err := NewProject("Billing", "", []string{"Move billing to Postgres"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := project.ConfigureDocumentation(DocumentationConfig{Visibility: visibility}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return project
+	}
+	newDocument := func(owner *Project, marked Visibility) *IndexedDocument {
+		doc := mustIndexedDocument(t, "docs/development/use-postgres.md", "Use Postgres", "", CategoryDevelopment)
+		if owner != nil {
+			doc.SetProject(owner.ID())
+		}
+		doc.SetVisibility(marked)
+		return doc
+	}
+
+	internal := newProject("")
+	shared := newProject(VisibilityShared)
+	other := common.GenerateID()
+
+	tests := []struct {
+		name   string
+		doc    *IndexedDocument
+		owner  *Project
+		viewer common.ID
+		want   bool
+	}{
+		{name: "outside of projects", doc: newDocument(nil, ""), viewer: other, want: true},
+		{name: "own project", doc: newDocument(internal, ""), owner: internal, viewer: internal.ID(), want: true},
+		{name: "internal by default", doc: newDocument(internal, ""), owner: internal, viewer: other},
+		{name: "shared by the project", doc: newDocument(shared, ""), owner: shared, viewer: other, want: true},
+		{name: "marked shared", doc: newDocument(internal, VisibilityShared), owner: internal, viewer: other, want: true},
+		{name: "marked internal in a sharing project", doc: newDocument(shared, VisibilityInternal), owner: shared, viewer: other},
+		{name: "project no longer exists", doc: newDocument(shared, ""), viewer: other},
+		{name: "channel outside of projects", doc: newDocument(internal, ""), owner: internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VisibleTo(tt.doc, tt.owner, tt.viewer); got != tt.want {
+				t.Errorf("VisibleTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidWikiPage = errors.New("invalid wiki page")
+)
+
+// WikiSource is the knowledge base pages are imported from
+type WikiSource string
+
+const (
+	// WikiSourceNotion is a Notion workspace
+	WikiSourceNotion WikiSource = "notion"
+	// WikiSourceConfluence is a Confluence space
+	WikiSourceConfluence WikiSource = "confluence"
+)
+
+// String returns the string representation of the source
+func (s WikiSource) String() string {
+	return string(s)
+}
+
+// WikiPage is a page of a knowledge base, like Notion or Confluence, with its content converted to Markdown so it
+// can move into the documentation repository
+type WikiPage struct {
+	source    WikiSource
+	id        string
+	parentID  string
+	title     string
+	body      string
+	link      string
+	author    string
+	createdAt time.Time
+	updatedAt time.Time
+	labels    []Tag
+}
+
+// NewWikiPage creates a new WikiPage. The parent is the ID of the page it is nested in, empty for top-level pages,
+// and the body is the page's Markdown without its title.
+func NewWikiPage(source WikiSource, id, parentID, title, body string) (*WikiPage, error) {
+	if strings.TrimSpace(string(source)) == "" {
+		return nil, fmt.Errorf("%w: missing source", ErrInvalidWikiPage)
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("%w: missing page ID", ErrInvalidWikiPage)
+	}
+	parentID = strings.TrimSpace(parentID)
+	if parentID == id {
+		return nil, fmt.Errorf("%w: page %s is its own parent", ErrInvalidWikiPage, id)
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		// Notion allows pages without a title, it shows them as Untitled
+		title = "Untitled"
+	}
+
+	return &WikiPage{
+		source:   source,
+		id:       id,
+		parentID: parentID,
+		title:    title,
+		body:     strings.TrimSpace(body),
+	}, nil
+}
+
+// Source returns the knowledge base the page comes from
+func (p *WikiPage) Source() WikiSource {
+	return p.source
+}
+
+// ID returns the page's identifier in its knowledge base
+func (p *WikiPage) ID() string {
+	return p.id
+}
+
+// ParentID returns the ID of the page this one is nested in, or an empty string
+func (p *WikiPage) ParentID() string {
+	return p.parentID
+}
+
+// Title returns the page's title
+func (p *WikiPage) Title() string {
+	return p.title
+}
+
+// Body returns the page's content as Markdown
+func (p *WikiPage) Body() string {
+	return p.body
+}
+
+// Link returns the address of the page in its knowledge base, or an empty string
+func (p *WikiPage) Link() string {
+	return p.link
+}
+
+// SetLink sets the address of the page in its knowledge base
+func (p *WikiPage) SetLink(link string) {
+	p.link = strings.TrimSpace(link)
+}
+
+// Author returns who created the page, or an empty string
+func (p *WikiPage) Author() string {
+	return p.author
+}
+
+// SetAuthor sets who created the page
+func (p *WikiPage) SetAuthor(author string) {
+	p.author = strings.TrimSpace(author)
+}
+
+// CreatedAt returns when the page was created, zero when unknown
+func (p *WikiPage) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// UpdatedAt returns when the page was last edited, zero when unknown
+func (p *WikiPage) UpdatedAt() time.Time {
+	return p.updatedAt
+}
+
+// SetTimes sets when the page was created and last edited
+func (p *WikiPage) SetTimes(createdAt, updatedAt time.Time) {
+	p.createdAt = createdAt
+	p.updatedAt = updatedAt
+}
+
+// Labels returns the page's labels as tags
+func (p *WikiPage) Labels() []Tag {
+	return p.labels
+}
+
+// SetLabels sets the page's labels, the ones that are not valid tags are dropped
+func (p *WikiPage) SetLabels(labels []string) {
+	p.labels = NewTags(labels)
+}
+
+// Document returns the page as a Markdown document with front matter recording where it was imported from.
+// The parent path is the document of the page it is nested in, empty for top-level pages.
+func (p *WikiPage) Document(category Category, parentPath string, importedAt time.Time) string {
+	fm := NewFrontMatter()
+	fm.Set("type", MessageTypeInformation.String())
+	fm.Set("category", category.String())
+	if len(p.labels) > 0 {
+		tags := make([]string, len(p.labels))
+		for i, label := range p.labels {
+			tags[i] = label.String()
+		}
+		fm.SetList("tags", tags)
+	}
+	if p.author != "" {
+		fm.Set("author", p.author)
+	}
+	if !p.createdAt.IsZero() {
+		fm.Set("created_at", p.createdAt.UTC().Format(time.RFC3339))
+	}
+	if !p.updatedAt.IsZero() {
+		fm.Set("updated_at", p.updatedAt.UTC().Format(time.RFC3339))
+	}
+	if parentPath != "" {
+		fm.Set("parent", parentPath)
+	}
+	fm.Set("imported_from", p.source.String())
+	fm.Set("import_id", p.id)
+	if p.link != "" {
+		fm.Set("import_link", p.link)
+	}
+	fm.Set("imported_at", importedAt.UTC().Format(time.RFC3339))
+
+	body := "# " + p.title + "\n"
+	if p.body != "" {
+		body += "\n" + p.body + "\n"
+	}
+	return fm.Apply(body)
+}
+
+// SameImport checks if two documents hold the same import of a page, whenever they were imported
+func SameImport(a, b string) bool {
+	return withoutImportTime(a) == withoutImportTime(b)
+}
+
+// withoutImportTime drops the import time from a document's front matter
+func withoutImportTime(content string) string {
+	fm, body, err := ParseFrontMatter(content)
+	if err != nil {
+		return content
+	}
+	fm.Delete("imported_at")
+	return fm.Apply(body)
+}
+
+// WikiImport is where an imported page goes in the documentation repository
+type WikiImport struct {
+	Page *WikiPage
+	// Path is the path of the page's document
+	Path string
+	// ParentPath is the path of the document of the page it is nested in, empty for top-level pages
+	ParentPath string
+}
+
+// PlanWikiImport lays the pages out under a directory, keeping their hierarchy: a page nested in another goes in
+// the directory named after its parent's document. Pages whose parent is not imported are top-level pages, and
+// siblings with the same title are numbered. The imports are returned parents first.
+func PlanWikiImport(pages []*WikiPage, dir string) []WikiImport {
+	byID := make(map[string]*WikiPage, len(pages))
+	for _, page := range pages {
+		byID[page.ID()] = page
+	}
+	children := make(map[string][]*WikiPage)
+	for _, page := range pages {
+		parent := page.ParentID()
+		if _, ok := byID[parent]; !ok {
+			parent = ""
+		}
+		children[parent] = append(children[parent], page)
+	}
+
+	var plan []WikiImport
+	placed := make(map[string]bool, len(pages))
+	taken := make(map[string]bool, len(pages))
+	var place func(siblings []*WikiPage, parentPath, dir string)
+	place = func(siblings []*WikiPage, parentPath, dir string) {
+		// Sorted so a page keeps its path when the space is imported again
+		sort.SliceStable(siblings, func(i, j int) bool {
+			if siblings[i].Title() != siblings[j].Title() {
+				return siblings[i].Title() < siblings[j].Title()
+			}
+			return siblings[i].ID() < siblings[j].ID()
+		})
+
+		for _, page := range siblings {
+			if placed[page.ID()] {
+				continue
+			}
+			placed[page.ID()] = true
+
+			name := Slugify(page.Title())
+			if name == "" {
+				name = "page-" + Slugify(page.ID())
+			}
+			docPath := UniquePath(path.Join(dir, name+".md"), func(candidate string) bool {
+				return taken[candidate]
+			})
+			taken[docPath] = true
+
+			plan = append(plan, WikiImport{Page: page, Path: docPath, ParentPath: parentPath})
+			place(children[page.ID()], docPath, strings.TrimSuffix(docPath, ".md"))
+		}
+	}
+	place(children[""], "", dir)
+
+	// Pages nested in each other in a loop have no top-level ancestor, they are imported as top-level pages
+	for _, page := range pages {
+		if !placed[page.ID()] {
+			place([]*WikiPage{page}, "", dir)
+		}
+	}
+	return plan
+}
+
+// WikiImportFailure is a page that could not be imported
+type WikiImportFailure struct {
+	PageID string
+	Title  string
+	Error  string
+}
+
+// WikiImportReport tells what an import stored in the documentation repository
+type WikiImportReport struct {
+	Source WikiSource
+	// Pages counts the pages read from the knowledge base
+	Pages int
+	// Created are the paths of the documents added
+	Created []string
+	// Updated are the paths of the documents of pages imported before and edited since
+	Updated []string
+	// Unchanged counts the pages imported before and not edited since
+	Unchanged   int
+	Failures    []WikiImportFailure
+	CompletedAt time.Time
+}
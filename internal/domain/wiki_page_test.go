@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWikiPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		parentID  string
+		title     string
+		wantTitle string
+		wantErr   bool
+	}{
+		{name: "valid page", id: "p1", parentID: "root", title: " Onboarding ", wantTitle: "Onboarding"},
+		{name: "untitled page", id: "p1", wantTitle: "Untitled"},
+		{name: "missing ID", id: " ", title: "Onboarding", wantErr: true},
+		{name: "own parent", id: "p1", parentID: "p1", title: "Onboarding", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := NewWikiPage(WikiSourceNotion, tt.id, tt.parentID, tt.title, "")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidWikiPage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, page.Title())
+		})
+	}
+}
+
+func TestWikiPage_Document(t *testing.T) {
+	page, err := NewWikiPage(WikiSourceConfluence, "65537", "65536", "Release Process", "Releases go out on **Tuesdays**.")
+	require.NoError(t, err)
+	page.SetLink("https://acme.atlassian.net/wiki/spaces/ENG/pages/65537")
+	page.SetAuthor("Alice")
+	page.SetTimes(time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC))
+	page.SetLabels([]string{"release", "Ops Team", "release"})
+	importedAt := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	content := page.Document(CategoryOperations, "docs/operations/engineering.md", importedAt)
+
+	fm, body, err := ParseFrontMatter(content)
+	require.NoError(t, err)
+	assert.Equal(t, "information", fm.Get("type"))
+	assert.Equal(t, "operations", fm.Get("category"))
+	assert.Equal(t, []string{"release", "ops-team"}, fm.GetList("tags"))
+	assert.Equal(t, "Alice", fm.Get("author"))
+	assert.Equal(t, "2023-03-01T09:00:00Z", fm.Get("created_at"))
+	assert.Equal(t, "2024-05-02T10:30:00Z", fm.Get("updated_at"))
+	assert.Equal(t, "docs/operations/engineering.md", fm.Get("parent"))
+	assert.Equal(t, "confluence", fm.Get("imported_from"))
+	assert.Equal(t, "65537", fm.Get("import_id"))
+	assert.Equal(t, "https://acme.atlassian.net/wiki/spaces/ENG/pages/65537", fm.Get("import_link"))
+	assert.Equal(t, "2026-01-05T12:00:00Z", fm.Get("imported_at"))
+	assert.Equal(t, "# Release Process\n\nReleases go out on **Tuesdays**.\n", body)
+
+	stored := ParseStoredDocument("docs/operations/engineering/release-process.md", content)
+	assert.Equal(t, "Release Process", stored.Title)
+	assert.Equal(t, CategoryOperations, stored.Category)
+
+	assert.True(t, SameImport(content, page.Document(CategoryOperations, "docs/operations/engineering.md", importedAt.Add(time.Hour))))
+	page.SetLabels(nil)
+	assert.False(t, SameImport(content, page.Document(CategoryOperations, "docs/operations/engineering.md", importedAt)))
+}
+
+func TestPlanWikiImport(t *testing.T) {
+	page := func(id, parentID, title string) *WikiPage {
+		p, err := NewWikiPage(WikiSourceNotion, id, parentID, title, "")
+		require.NoError(t, err)
+		return p
+	}
+	pages := []*WikiPage{
+		page("setup", "handbook", "Setup"),
+		page("handbook", "", "Handbook"),
+		page("laptop", "setup", "Laptop"),
+		page("faq-2", "handbook", "FAQ"),
+		page("faq-1", "handbook", "FAQ"),
+		page("orphan", "deleted", "Orphan"),
+		page("loop-a", "loop-b", "Loop A"),
+		page("loop-b", "loop-a", "Loop B"),
+		page("symbols", "", "🚀"),
+	}
+
+	plan := PlanWikiImport(pages, "docs/other")
+
+	paths := make(map[string]WikiImport, len(plan))
+	for _, imported := range plan {
+		paths[imported.Page.ID()] = imported
+	}
+	require.Len(t, plan, len(pages))
+	assert.Equal(t, "docs/other/handbook.md", paths["handbook"].Path)
+	assert.Empty(t, paths["handbook"].ParentPath)
+	assert.Equal(t, "docs/other/handbook/setup.md", paths["setup"].Path)
+	assert.Equal(t, "docs/other/handbook.md", paths["setup"].ParentPath)
+	assert.Equal(t, "docs/other/handbook/setup/laptop.md", paths["laptop"].Path)
+	assert.Equal(t, "docs/other/handbook/faq.md", paths["faq-1"].Path)
+	assert.Equal(t, "docs/other/handbook/faq-2.md", paths["faq-2"].Path)
+	assert.Equal(t, "docs/other/orphan.md", paths["orphan"].Path)
+	assert.Equal(t, "docs/other/loop-a.md", paths["loop-a"].Path)
+	assert.Equal(t, "docs/other/loop-a/loop-b.md", paths["loop-b"].Path)
+	assert.Equal(t, "docs/other/page-symbols.md", paths["symbols"].Path)
+
+	// Parents come before their children
+	seen := make(map[string]bool)
+	for _, imported := range plan {
+		if imported.ParentPath != "" {
+			assert.True(t, seen[imported.ParentPath], imported.Path)
+		}
+		seen[imported.Path] = true
+	}
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StatCount is the number of captured messages sharing a key, like a type, category, week or sender
+type StatCount struct {
+	Key   string
+	Count int
+}
+
+// ChannelCoverage tells how much of what a channel discussed ended up documented
+type ChannelCoverage struct {
+	Channel string
+	// Processed counts the messages that were documented, ignored or failed
+	Processed int
+	// Documented counts the messages that were captured as documentation
+	Documented int
+}
+
+// Coverage returns the share of processed messages that were documented, between 0 and 1
+func (c ChannelCoverage) Coverage() float64 {
+	if c.Processed == 0 {
+		return 0
+	}
+	return float64(c.Documented) / float64(c.Processed)
+}
+
+// WorkspaceStats summarises what the workspace captured as documentation. A message is captured
+// once it is documented, messages still in the pipeline only count towards the average confidence.
+type WorkspaceStats struct {
+	captured      int
+	documents     int
+	byType        map[string]int
+	byCategory    map[string]int
+	byWeek        map[string]int
+	contributors  map[string]int
+	confidenceSum float64
+	analyzed      int
+	channels      map[string]*ChannelCoverage
+}
+
+// NewWorkspaceStats computes the statistics of the given messages and indexed documents
+func NewWorkspaceStats(messages []*Message, documents []*IndexedDocument) *WorkspaceStats {
+	stats := &WorkspaceStats{
+		documents:    len(documents),
+		byType:       make(map[string]int),
+		byCategory:   make(map[string]int),
+		byWeek:       make(map[string]int),
+		contributors: make(map[string]int),
+		channels:     make(map[string]*ChannelCoverage),
+	}
+
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		if msg.Confidence() > 0 {
+			stats.confidenceSum += msg.Confidence()
+			stats.analyzed++
+		}
+
+		state := msg.State()
+		if !state.IsFinal() && !state.IsFailed() {
+			continue
+		}
+		documented := state == MessageStateDocumented
+		if msg.ChannelID() != "" {
+			channel, ok := stats.channels[msg.ChannelID()]
+			if !ok {
+				channel = &ChannelCoverage{Channel: msg.ChannelID()}
+				stats.channels[msg.ChannelID()] = channel
+			}
+			channel.Processed++
+			if documented {
+				channel.Documented++
+			}
+		}
+		if !documented {
+			continue
+		}
+
+		stats.captured++
+		stats.byType[msg.Type().String()]++
+		stats.byCategory[msg.Category().String()]++
+		stats.byWeek[isoWeek(msg)]++
+		stats.contributors[msg.Sender()]++
+	}
+	return stats
+}
+
+// Captured returns the number of documented messages
+func (s *WorkspaceStats) Captured() int {
+	return s.captured
+}
+
+// Documents returns the number of indexed documents
+func (s *WorkspaceStats) Documents() int {
+	return s.documents
+}
+
+// ByType returns the captured messages per type, most frequent first
+func (s *WorkspaceStats) ByType() []StatCount {
+	return byCount(s.byType)
+}
+
+// ByCategory returns the captured messages per category, most frequent first
+func (s *WorkspaceStats) ByCategory() []StatCount {
+	return byCount(s.byCategory)
+}
+
+// ByWeek returns the captured messages per ISO week they were posted in, like "2024-W07", oldest first
+func (s *WorkspaceStats) ByWeek() []StatCount {
+	weeks := make([]StatCount, 0, len(s.byWeek))
+	for week, count := range s.byWeek {
+		weeks = append(weeks, StatCount{Key: week, Count: count})
+	}
+	sort.Slice(weeks, func(i, j int) bool {
+		return weeks[i].Key < weeks[j].Key
+	})
+	return weeks
+}
+
+// TopContributors returns the senders of the most captured messages, at most limit of them when limit is positive
+func (s *WorkspaceStats) TopContributors(limit int) []StatCount {
+	contributors := byCount(s.contributors)
+	if limit > 0 && len(contributors) > limit {
+		contributors = contributors[:limit]
+	}
+	return contributors
+}
+
+// AverageConfidence returns the mean confidence of the analyzed messages, zero when none were analyzed
+func (s *WorkspaceStats) AverageConfidence() float64 {
+	if s.analyzed == 0 {
+		return 0
+	}
+	return s.confidenceSum / float64(s.analyzed)
+}
+
+// Coverage returns the documentation coverage of each channel, ordered by channel
+func (s *WorkspaceStats) Coverage() []ChannelCoverage {
+	coverage := make([]ChannelCoverage, 0, len(s.channels))
+	for _, channel := range s.channels {
+		coverage = append(coverage, *channel)
+	}
+	sort.Slice(coverage, func(i, j int) bool {
+		return coverage[i].Channel < coverage[j].Channel
+	})
+	return coverage
+}
+
+// byCount orders counts by frequency, and keys with the same count alphabetically
+func byCount(counts map[string]int) []StatCount {
+	sorted := make([]StatCount, 0, len(counts))
+	for key, count := range counts {
+		sorted = append(sorted, StatCount{Key: key, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// isoWeek returns the ISO week a message was posted in, like "2024-W07"
+func isoWeek(msg *Message) string {
+	year, week := msg.Timestamp().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWorkspaceStats(t *testing.T) {
+	week7 := time.Date(2024, 2, 14, 10, 0, 0, 0, time.UTC)
+	week8 := week7.AddDate(0, 0, 7)
+
+	newMessage := func(sender, channel string, messageType MessageType, category Category, state MessageState, confidence float64, at time.Time) *Message {
+		msg, err := MessageFromDTO(MessageDTO{
+			ID:         common.GenerateID().String(),
+			ThreadID:   common.GenerateID().String(),
+			ChannelID:  channel,
+			Sender:     sender,
+			Content:    "We will use Postgres",
+			Type:       messageType.String(),
+			Category:   category.String(),
+			Confidence: confidence,
+			States: []MessageStateChangeDTO{
+				{State: MessageStatePending.String(), At: at},
+				{State: state.String(), At: at},
+			},
+			Timestamp: at,
+		})
+		require.NoError(t, err)
+		return msg
+	}
+
+	stats := NewWorkspaceStats([]*Message{
+		newMessage("jane", "C1", MessageTypeDecision, CategoryDevelopment, MessageStateDocumented, 0.9, week7),
+		newMessage("jane", "C1", MessageTypeDecision, CategoryProduct, MessageStateDocumented, 0.7, week8),
+		newMessage("john", "C2", MessageTypeIdea, CategoryDevelopment, MessageStateDocumented, 0.8, week8),
+		newMessage("john", "C1", MessageTypeInformation, CategoryOther, MessageStateIgnored, 0.6, week8),
+		newMessage("joe", "C2", MessageTypeInformation, CategoryOther, MessageStateFailed, 0, week8),
+		newMessage("joe", "C2", MessageTypeInformation, CategoryOther, MessageStatePending, 0, week8),
+		nil,
+	}, []*IndexedDocument{
+		mustIndexedDocument(t, "docs/development/use-postgres.md", "Use Postgres", "", CategoryDevelopment),
+	})
+
+	assert.Equal(t, 3, stats.Captured())
+	assert.Equal(t, 1, stats.Documents())
+	assert.Equal(t, []StatCount{{Key: "decision", Count: 2}, {Key: "idea", Count: 1}}, stats.ByType())
+	assert.Equal(t, []StatCount{{Key: "development", Count: 2}, {Key: "product", Count: 1}}, stats.ByCategory())
+	assert.Equal(t, []StatCount{{Key: "2024-W07", Count: 1}, {Key: "2024-W08", Count: 2}}, stats.ByWeek())
+	assert.Equal(t, []StatCount{{Key: "jane", Count: 2}}, stats.TopContributors(1))
+	assert.InDelta(t, 0.75, stats.AverageConfidence(), 0.0001)
+
+	coverage := stats.Coverage()
+	require.Len(t, coverage, 2)
+	assert.Equal(t, ChannelCoverage{Channel: "C1", Processed: 3, Documented: 2}, coverage[0])
+	assert.Equal(t, ChannelCoverage{Channel: "C2", Processed: 2, Documented: 1}, coverage[1])
+	assert.InDelta(t, 0.5, coverage[1].Coverage(), 0.0001)
+}
+
+func TestNewWorkspaceStats_Empty(t *testing.T) {
+	stats := NewWorkspaceStats(nil, nil)
+
+	assert.Zero(t, stats.Captured())
+	assert.Zero(t, stats.Documents())
+	assert.Zero(t, stats.AverageConfidence())
+	assert.Empty(t, stats.ByWeek())
+	assert.Empty(t, stats.Coverage())
+	assert.Zero(t, ChannelCoverage{}.Coverage())
+}
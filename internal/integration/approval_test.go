@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replyAs builds a reply a person posted in the thread of a message
+func (h *harness) replyAs(t *testing.T, to *domain.Message, sender, text string) *domain.Message {
+	t.Helper()
+
+	msg, err := domain.NewMessage(to.ThreadID(), sender, domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID(to.ChannelID())
+	return msg
+}
+
+// governedProject binds the test channel to a project whose development decisions need two approvals, one of
+// them from an architect
+func governedProject(t *testing.T, h *harness) {
+	t.Helper()
+
+	ctx := context.Background()
+	docConfig := domain.DefaultDocumentationConfig()
+	docConfig.ApprovalPolicies = []domain.ApprovalPolicy{
+		{Categories: []domain.Category{domain.CategoryDevelopment}, Approvals: 2},
+		{Categories: []domain.Category{domain.CategoryDevelopment}, Role: "architect"},
+	}
+	docConfig.Roles = map[string][]string{"architect": {"carol"}}
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, docConfig)
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+}
+
+func TestApproval_DecisionIsDocumentedOncePoliciesAreMet(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	governedProject(t, h)
+
+	msg := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	assert.Empty(t, documents(h.github))
+	assert.Equal(t, domain.MessageStateAnalyzing, h.stored(t, msg).State())
+	assert.Equal(t, "🔐 This decision needs 2 approvals and 1 approval from an architect before it is documented. "+
+		"Reply `approve` or `reject` in this thread.", lastReply(t, h, msg))
+
+	approvals, err := h.bot.PendingApprovals(ctx, []string{testChannel})
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+	assert.Equal(t, domain.ApprovalDecision, approvals[0].Kind)
+	assert.Equal(t, "needs 2 approvals and 1 approval from an architect", approvals[0].Reason)
+
+	// Authors cannot approve their own decisions
+	self := h.replyAs(t, msg, "alice", "approve")
+	require.NoError(t, h.bot.ProcessMessage(ctx, self))
+	assert.Contains(t, lastReply(t, h, self), "alice cannot approve their own decision")
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, msg, "bob", "approve")))
+	assert.Equal(t, "👍 Approved by bob, the decision still needs 1 approval and 1 approval from an architect", lastReply(t, h, msg))
+	waiting, err := h.approvals.Find(ctx, msg.ThreadID().String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, waiting.Approvers(), "approvals are kept in the store")
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, msg, "dave", "approve")))
+	assert.Equal(t, "👍 Approved by dave, the decision still needs 1 approval from an architect", lastReply(t, h, msg))
+	assert.Empty(t, documents(h.github))
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, msg, "carol", "approve")))
+	assert.Len(t, documents(h.github), 1)
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+	assert.Contains(t, lastReply(t, h, msg), "✅ Recorded decision in category: development")
+
+	approvals, err = h.bot.PendingApprovals(ctx, []string{testChannel})
+	require.NoError(t, err)
+	assert.Empty(t, approvals)
+	_, err = h.approvals.Find(ctx, msg.ThreadID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, domain.AuditActionApprove, entry.Action())
+		assert.Equal(t, msg.ID().String(), entry.Subject())
+	}
+	transitions := map[string][2]string{}
+	for _, entry := range entries {
+		transitions[entry.Actor()] = [2]string{entry.From(), entry.To()}
+	}
+	assert.Equal(t, [2]string{"0/3", "1/3"}, transitions["bob"])
+	assert.Equal(t, [2]string{"1/3", "2/3"}, transitions["dave"])
+	assert.Equal(t, [2]string{"2/3", "approved"}, transitions["carol"])
+}
+
+func TestApproval_RejectedDecisionIsNotDocumented(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	governedProject(t, h)
+
+	msg := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, msg, "carol", "reject")))
+
+	assert.Empty(t, documents(h.github))
+	assert.Equal(t, domain.MessageStateIgnored, h.stored(t, msg).State())
+	assert.Equal(t, "🚫 Rejected by carol, the decision is not documented", lastReply(t, h, msg))
+
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "0/3", entries[0].From())
+	assert.Equal(t, "rejected", entries[0].To())
+
+	// Later replies in the thread are not taken as approvals
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, msg, "bob", "approve")))
+	assert.Empty(t, documents(h.github))
+}
+
+func TestApproval_PoliciesApplyToTheirCategories(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryProduct)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	governedProject(t, h)
+
+	msg := h.post(t, "We decided to bill customers monthly")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+	assert.Contains(t, lastReply(t, h, msg), "✅ Recorded decision in category: product")
+}
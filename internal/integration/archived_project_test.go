@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchivedProject_DocumentationIsReadOnly(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+	_, doc := reviewedDecision(t, h)
+	before, ok := h.github.file(doc.Path())
+	require.True(t, ok)
+
+	project, err := h.projects.FindByChannel(ctx, testChannel)
+	require.NoError(t, err)
+	_, err = h.projects.ArchiveProject(ctx, project.ID())
+	require.NoError(t, err)
+
+	assert.Contains(t, h.command(t, testChannel, "/quill tag add "+doc.Path()+" invoices"), domain.ErrProjectArchived.Error())
+	review, err := domain.NewDocumentReview(doc.Path(), domain.ReviewReconfirm, "bob")
+	require.NoError(t, err)
+	assert.ErrorIs(t, h.reviews.Review(ctx, review), domain.ErrProjectArchived)
+
+	// The erasure leaves the archived document as it is and tells so
+	request, err := domain.NewErasureRequest("alice", domain.ErasureErase, "dpo")
+	require.NoError(t, err)
+	report, err := h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+	assert.Empty(t, report.Documents)
+	assert.Equal(t, []string{doc.Path()}, report.ReadOnlyDocuments)
+	after, ok := h.github.file(doc.Path())
+	require.True(t, ok)
+	assert.Equal(t, before, after)
+
+	// Once resumed, the document is erased by running the erasure again
+	_, err = h.projects.ResumeProject(ctx, project.ID())
+	require.NoError(t, err)
+	report, err = h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, []string{doc.Path()}, report.Documents)
+	assert.Empty(t, report.ReadOnlyDocuments)
+	assert.False(t, frontMatterOf(t, h, doc.Path()).Has("reviewed_by"))
+}
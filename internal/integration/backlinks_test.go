@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceGraph_InjectBacklinks(t *testing.T) {
+	const decision = "docs/development/adopt-postgres.md"
+
+	tests := []struct {
+		name    string
+		path    string // The decision unless set
+		record  func(t *testing.T, graph *services.ReferenceGraphService)
+		content string
+		want    string
+	}{
+		{
+			name:    "nothing references the document",
+			content: "# Adopt Postgres\n",
+			want:    "# Adopt Postgres\n",
+		},
+		{
+			name:    "stale section is removed",
+			content: "# Adopt Postgres\n\n## Backlinks\n\n- [docs/old.md](/docs/old.md)\n",
+			want:    "# Adopt Postgres\n",
+		},
+		{
+			name: "documents linking to it",
+			record: func(t *testing.T, graph *services.ReferenceGraphService) {
+				require.NoError(t, graph.RecordStoredDocument(domain.ParseStoredDocument("docs/operations/failover.md", "See [the decision](/"+decision+").")))
+			},
+			content: "# Adopt Postgres\n",
+			want:    "# Adopt Postgres\n\n## Backlinks\n\n- [docs/operations/failover.md](/docs/operations/failover.md)\n",
+		},
+		{
+			name: "relation",
+			record: func(t *testing.T, graph *services.ReferenceGraphService) {
+				rel, err := domain.NewDocumentRelation("docs/development/adopt-cockroach.md", domain.RelationSupersedes, decision)
+				require.NoError(t, err)
+				require.NoError(t, graph.RecordRelation(rel))
+			},
+			content: "# Adopt Postgres\n",
+			want:    "# Adopt Postgres\n\n## Backlinks\n\n- [docs/development/adopt-cockroach.md](/docs/development/adopt-cockroach.md) (supersedes this document)\n",
+		},
+		{
+			name: "section is replaced, not repeated",
+			record: func(t *testing.T, graph *services.ReferenceGraphService) {
+				require.NoError(t, graph.RecordStoredDocument(domain.ParseStoredDocument("docs/operations/failover.md", "See [the decision](/"+decision+").")))
+			},
+			content: "# Adopt Postgres\n\n## Backlinks\n\n- [docs/old.md](/docs/old.md)\n",
+			want:    "# Adopt Postgres\n\n## Backlinks\n\n- [docs/operations/failover.md](/docs/operations/failover.md)\n",
+		},
+		{
+			name: "documents of messages referencing it",
+			record: func(t *testing.T, graph *services.ReferenceGraphService) {
+				msg, err := domain.NewMessage(common.GenerateID(), "alice", domain.MustNewMessageContent("See the decision"), domain.MessageTypeInformation, domain.CategoryDevelopment,
+					[]*domain.Reference{domain.MustNewReference(domain.ReferenceTypeDocument, decision)})
+				require.NoError(t, err)
+				require.NoError(t, graph.RecordDocument("docs/development/notes.md", msg))
+			},
+			content: "# Adopt Postgres\n",
+			want:    "# Adopt Postgres\n\n## Backlinks\n\n- [docs/development/notes.md](/docs/development/notes.md)\n",
+		},
+		{
+			name:    "invalid path leaves the content alone",
+			path:    " ",
+			content: "# Adopt Postgres\n\n## Backlinks\n",
+			want:    "# Adopt Postgres\n\n## Backlinks\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := services.NewReferenceGraphService()
+			if tt.record != nil {
+				tt.record(t, graph)
+			}
+			path := tt.path
+			if path == "" {
+				path = decision
+			}
+
+			assert.Equal(t, tt.want, graph.InjectBacklinks(path, tt.content))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// messagesPerRepository bounds how many documents a benchmark writes to one repository, category
+// indexes grow with every document and would otherwise dominate the measurement of long runs
+const messagesPerRepository = 100
+
+// BenchmarkProcessMessage measures documenting a decision end to end, from analysis to the commit
+func BenchmarkProcessMessage(b *testing.B) {
+	for _, p := range providers {
+		b.Run(p.name, func(b *testing.B) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			ai := p.new(model, b)
+			var h *harness
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if i%messagesPerRepository == 0 {
+					b.StopTimer()
+					h = newHarness(b, ai)
+					b.StartTimer()
+				}
+
+				msg := h.post(b, "We decided to use Postgres for billing")
+				if err := h.bot.ProcessMessage(context.Background(), msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCalendar serves a fixed set of meetings, like a team calendar
+type fakeCalendar struct {
+	mu       sync.Mutex
+	meetings []*domain.Meeting
+	err      error
+}
+
+func (c *fakeCalendar) schedule(t testing.TB, title string, start, end time.Time) {
+	t.Helper()
+	meeting, err := domain.NewMeeting(title, title, start, end, "https://calendar.example.com/"+title)
+	require.NoError(t, err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.meetings = append(c.meetings, meeting)
+}
+
+func (c *fakeCalendar) MeetingsBetween(ctx context.Context, from, to time.Time) ([]*domain.Meeting, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var overlapping []*domain.Meeting
+	for _, m := range c.meetings {
+		if m.Start().Before(to.Add(time.Nanosecond)) && m.End().After(from) {
+			overlapping = append(overlapping, m)
+		}
+	}
+	return overlapping, nil
+}
+
+func TestProcessMessage_NamesOriginatingMeeting(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	now := time.Now()
+	h.calendar.schedule(t, "standup", now.Add(-3*time.Hour), now.Add(-2*time.Hour))
+	h.calendar.schedule(t, "architecture-review", now.Add(-30*time.Minute), now.Add(30*time.Minute))
+	msg := h.post(t, "We decided to use Postgres for billing")
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	docs := documents(h.github)
+	require.Len(t, docs, 1)
+	content, ok := h.github.file(docs[0])
+	require.True(t, ok)
+	assert.Contains(t, content, `originating_meeting: "architecture-review (`)
+	assert.Contains(t, content, `originating_meeting_link: "https://calendar.example.com/architecture-review"`)
+}
+
+func TestProcessMessage_DocumentsWithoutCalendar(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	h.calendar.err = errors.New("calendar unavailable")
+	msg := h.post(t, "We decided to use Postgres for billing")
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	docs := documents(h.github)
+	require.Len(t, docs, 1)
+	content, ok := h.github.file(docs[0])
+	require.True(t, ok)
+	assert.NotContains(t, content, "originating_meeting")
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+}
@@ -0,0 +1,41 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalibration_ComparesConfidenceWithCorrections(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	decision := h.post(t, "We decided to move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, decision))
+	other := h.post(t, "We decided to keep the monolith")
+	require.NoError(t, h.bot.ProcessMessage(ctx, other))
+
+	stored := h.stored(t, decision)
+	assert.Equal(t, "ollama:llama3", stored.Model())
+
+	corrections := memory.NewCorrectionStore()
+	correction, err := domain.NewCorrection(stored, domain.CorrectionFieldType, "decision", "idea", "U0001")
+	require.NoError(t, err)
+	require.NoError(t, corrections.Record(ctx, correction))
+
+	report, err := services.NewCalibrationService(h.messages, corrections).Report(ctx, 0)
+	require.NoError(t, err)
+
+	require.Len(t, report, 1)
+	assert.Equal(t, "ollama:llama3", report[0].Model)
+	assert.Equal(t, 2, report[0].Analyzed)
+	assert.Equal(t, 1, report[0].Overridden)
+	require.Len(t, report[0].Bins, 1)
+	assert.InDelta(t, 0.9, report[0].Bins[0].Lower, 0.0001)
+}
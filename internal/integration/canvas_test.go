@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestCanvas_PublishesTheWeeksRollups(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	config := domain.DefaultDocumentationConfig()
+	config.DigestCanvas = true
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{Name: "Billing", BusinessGoals: []string{"Bill customers"}}, config)
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "Invoice export is done")))
+	first, ok := h.chat.canvasOf(testChannel)
+	require.True(t, ok, "the digest is published on the channel canvas")
+	assert.Contains(t, first, "# Weekly digest of Billing, week ")
+	assert.Contains(t, first, "\n## Development status updates, week ")
+	assert.Contains(t, first, " by alice\n")
+
+	// Each status update replaces the digest with the week's rollups so far
+	model.analysis.Category = domain.CategoryOperations
+	msg := h.post(t, "Rotated the database certificates")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	second, ok := h.chat.canvasOf(testChannel)
+	require.True(t, ok)
+	assert.Contains(t, second, "\n## Development status updates, week ")
+	assert.Contains(t, second, "\n## Operations status updates, week ")
+
+	// The rollups are committed as before
+	_, ok = h.github.file(domain.StatusRollupPath(domain.CategoryOperations, msg.Timestamp()))
+	assert.True(t, ok, "rollup not found in %v", h.github.paths())
+}
+
+func TestDigestCanvas_OffByDefault(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	billingProject(t, h)
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.post(t, "Invoice export is done")))
+
+	_, ok := h.chat.canvasOf(testChannel)
+	assert.False(t, ok)
+}
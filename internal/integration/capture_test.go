@@ -0,0 +1,36 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapture_PreFillsTheAnalysis(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+
+	analysis, err := h.bot.AnalyzeCapture(context.Background(), testChannel, "We decided to move billing to Postgres")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.MessageTypeDecision, analysis.MessageType())
+	assert.Equal(t, domain.CategoryDevelopment, analysis.Category())
+	assert.Empty(t, documents(h.github), "analyzing a capture documents nothing")
+}
+
+func TestCapture_KeepsThePickedTypeAndCategory(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	msg := h.post(t, "We decided to move billing to Postgres")
+	msg.FixType(domain.MessageTypeDecision)
+	msg.FixCategory(domain.CategoryOperations)
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	assert.Equal(t, domain.CategoryOperations, msg.Category())
+	assert.Empty(t, documents(h.github), "the category the model suggested is not used")
+	assert.Contains(t, h.github.paths(), "docs/operations/INDEX.md")
+}
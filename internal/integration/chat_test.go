@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"context"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// fakeChat is a chat provider that records what the bot posts
+type fakeChat struct {
+	mu        sync.Mutex
+	replies   map[string][]string  // Replies by the ID of the message replied to
+	sent      map[string][]string  // Messages by channel
+	reactions map[string][]string  // Emoji by message ID
+	incoming  chan *domain.Message // Messages the chat delivers to the bot
+	files     map[string][]byte    // Shared files by URL
+	canvases  map[string]string    // Canvas content by channel
+	replyErr  error                // Error replies fail with, nil posts them
+}
+
+func newFakeChat() *fakeChat {
+	return &fakeChat{
+		replies:   make(map[string][]string),
+		sent:      make(map[string][]string),
+		reactions: make(map[string][]string),
+		incoming:  make(chan *domain.Message),
+		files:     make(map[string][]byte),
+		canvases:  make(map[string]string),
+	}
+}
+
+func (c *fakeChat) SendMessage(ctx context.Context, channelID, content string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent[channelID] = append(c.sent[channelID], content)
+	return nil
+}
+
+// failReplies makes the replies fail with err until it is called with nil
+func (c *fakeChat) failReplies(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replyErr = err
+}
+
+func (c *fakeChat) ReplyToMessage(ctx context.Context, messageID, content string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.replyErr != nil {
+		return c.replyErr
+	}
+	c.replies[messageID] = append(c.replies[messageID], content)
+	return nil
+}
+
+func (c *fakeChat) AddReaction(ctx context.Context, messageID, emoji string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reactions[messageID] = append(c.reactions[messageID], emoji)
+	return nil
+}
+
+func (c *fakeChat) ListenForMessages(ctx context.Context) (<-chan *domain.Message, error) {
+	return c.incoming, nil
+}
+
+func (c *fakeChat) HandleInteraction(ctx context.Context, interaction interface{}) error {
+	return nil
+}
+
+// repliesTo returns the replies to a message
+func (c *fakeChat) repliesTo(messageID string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.replies[messageID]...)
+}
+
+// sentTo returns the messages posted to a channel
+func (c *fakeChat) sentTo(channelID string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.sent[channelID]...)
+}
+
+// SendDirect posts to the direct conversation with a user, its channel is "D" followed by the user ID
+func (c *fakeChat) SendDirect(ctx context.Context, userID, content string) (string, error) {
+	channelID := "D" + userID
+	return channelID, c.SendMessage(ctx, channelID, content)
+}
+
+// PublishCanvas replaces the canvas of a channel
+func (c *fakeChat) PublishCanvas(ctx context.Context, channelID, markdown string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.canvases[channelID] = markdown
+	return nil
+}
+
+// canvasOf returns the canvas of a channel, false when none was published
+func (c *fakeChat) canvasOf(channelID string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	canvas, ok := c.canvases[channelID]
+	return canvas, ok
+}
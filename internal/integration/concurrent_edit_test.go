@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// draftMerge offers to merge an idea into a document and asks for the merge, which waits for approval
+func draftMerge(t *testing.T, h *harness) (string, *domain.Message) {
+	t.Helper()
+
+	approvingProject(t, h)
+	path, idea := offerMerge(t, h)
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.replyAs(t, idea, idea.Sender(), "merge")))
+	return path, idea
+}
+
+func TestConcurrentEdit_MergesChangesMadeSinceTheDraft(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	path, idea := draftMerge(t, h)
+	read, _ := h.github.file(path)
+	edited := strings.Replace(read, "# Adopt Postgres\n", "# Adopt Postgres 16\n", 1)
+	require.NotEqual(t, read, edited)
+	h.github.edit(path, edited)
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.replyAs(t, idea, idea.Sender(), "apply")))
+
+	merged, _ := h.github.file(path)
+	assert.Contains(t, merged, "# Adopt Postgres 16\n")
+	assert.Contains(t, merged, "## Addendum ")
+	messages := h.github.commitMessages()
+	assert.Contains(t, messages[len(messages)-1], "Merged with the changes made since")
+	assert.Empty(t, h.github.pullRequests())
+}
+
+func TestConcurrentEdit_ProposesConflictingUpdate(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	path, idea := draftMerge(t, h)
+	read, _ := h.github.file(path)
+	edited := read + "\nA note added by hand.\n"
+	h.github.edit(path, edited)
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.replyAs(t, idea, idea.Sender(), "apply")))
+
+	current, _ := h.github.file(path)
+	assert.Equal(t, edited, current)
+	pulls := h.github.pullRequests()
+	require.Len(t, pulls, 1)
+	assert.Equal(t, githubBranch, pulls[0].Base)
+	assert.True(t, strings.HasPrefix(pulls[0].Title, "Update idea documentation (development)"))
+	assert.Contains(t, pulls[0].Body, "1 of the changes conflict")
+	proposed, ok := h.github.branchFile(pulls[0].Head, path)
+	require.True(t, ok)
+	assert.Contains(t, proposed, "## Addendum ")
+}
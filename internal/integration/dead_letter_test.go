@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetters_RetryAndDiscardFailedMessages(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	queue := memory.NewMessageQueue(0)
+	deadLetters := memory.NewDeadLetterQueue()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ingestion := services.NewQueueIngestion(h.chat, queue)
+	workers := services.NewQueueWorkers(queue, h.bot, 2, deadLetters)
+	admin := services.NewQueueAdminService(queue, deadLetters, h.audit, workers)
+	go func() { _ = ingestion.Run(ctx) }()
+	go func() { _ = workers.Run(ctx) }()
+
+	deadLettered := func(n int) []*domain.DeadLetter {
+		var letters []*domain.DeadLetter
+		require.Eventually(t, func() bool {
+			letters, _ = admin.DeadLetters(context.Background())
+			return len(letters) == n
+		}, 5*time.Second, 10*time.Millisecond)
+		return letters
+	}
+
+	// The analysis fails, so the workers set the message aside rather than losing it
+	model.failNext(operationAnalyze, 1)
+	msg := h.post(t, "We decided to use Postgres for billing")
+	h.chat.incoming <- msg
+	letters := deadLettered(1)
+	assert.Equal(t, msg.ID().String(), letters[0].ID())
+	assert.Equal(t, 1, letters[0].Attempts())
+	assert.Contains(t, letters[0].Error(), "failed to analyze message")
+	assert.Empty(t, documents(h.github))
+
+	status, err := admin.Status(context.Background())
+	require.NoError(t, err)
+	assert.True(t, status.DepthKnown)
+	assert.Equal(t, 1, status.DeadLetters)
+	require.Len(t, status.Workers, 2)
+	failed := 0
+	for _, worker := range status.Workers {
+		failed += worker.Failed
+	}
+	assert.Equal(t, 1, failed)
+
+	// Once the model recovered, retrying documents the message
+	_, err = admin.Retry(context.Background(), msg.ID().String(), "oncall")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		stored, err := h.messages.FindByID(context.Background(), msg.ID().String())
+		return err == nil && stored.State() == domain.MessageStateDocumented
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, documents(h.github), 1)
+	deadLettered(0)
+
+	// A message failing again is discarded for good
+	model.failNext(operationAnalyze, 1)
+	other := h.post(t, "We decided to render invoices in a queue")
+	h.chat.incoming <- other
+	deadLettered(1)
+	_, err = admin.Discard(context.Background(), other.ID().String(), "oncall")
+	require.NoError(t, err)
+	deadLettered(0)
+	_, err = admin.Retry(context.Background(), other.ID().String(), "oncall")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	entries, err := h.audit.List(context.Background())
+	require.NoError(t, err)
+	var actions []domain.AuditAction
+	for _, entry := range entries {
+		if entry.Actor() == "oncall" {
+			actions = append(actions, entry.Action())
+		}
+	}
+	assert.Equal(t, []domain.AuditAction{domain.AuditActionRetry, domain.AuditActionDiscard}, actions)
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deploymentNotice is a deployment notification as the GitHub app posts it
+const deploymentNotice = "Deployment failed for acme/checkout@v1.4.2 to production by alice\n" +
+	"<https://github.com/acme/checkout/actions/runs/42|View run>"
+
+func TestDeploymentNotice_DocumentedWithoutTheModel(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryProduct)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	config := domain.DefaultDocumentationConfig()
+	config.StatusRollup = domain.RollupNone
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{Name: "Checkout", BusinessGoals: []string{"Take payments"}}, config)
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+	msg := h.post(t, deploymentNotice)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	stored := h.stored(t, msg)
+	assert.Equal(t, domain.MessageStateDocumented, stored.State())
+	assert.Equal(t, domain.MessageTypeStatus, stored.Type())
+	assert.Equal(t, domain.CategoryOperations, stored.Category())
+	assert.Equal(t, 0, model.callCount(operationAnalyze))
+	assert.Equal(t, 0, model.callCount(operationDocument))
+
+	var path string
+	for _, p := range h.github.paths() {
+		if strings.HasPrefix(p, "docs/operations/") && !domain.IsTableOfContents(p) {
+			path = p
+		}
+	}
+	require.NotEmpty(t, path, "documents: %v", h.github.paths())
+	fm := frontMatterOf(t, h, path)
+	assert.Equal(t, "acme/checkout", fm.Get("application"))
+	assert.Equal(t, "production", fm.Get("environment"))
+	assert.Equal(t, "failed", fm.Get("deployment_status"))
+	assert.Equal(t, "https://github.com/acme/checkout/actions/runs/42", fm.Get("deployment_link"))
+	assert.Equal(t, "deployment-pattern:github-deployment", fm.Get("model"))
+	assert.False(t, fm.Has("grounding"))
+	content, _ := h.github.file(path)
+	assert.Contains(t, content, "# Failed to deploy acme/checkout v1.4.2 to production")
+}
+
+func TestDeploymentNotice_RolledUpWithTheWeeksStatus(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryProduct)
+	h := newHarness(t, model.ollamaProvider(t))
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.post(t, deploymentNotice)))
+
+	var rollup string
+	for _, p := range h.github.paths() {
+		if strings.HasPrefix(p, "docs/status/operations/") {
+			rollup = p
+		}
+	}
+	require.NotEmpty(t, rollup, "documents: %v", h.github.paths())
+	content, _ := h.github.file(rollup)
+	assert.Contains(t, content, "**Failed to deploy acme/checkout v1.4.2 to production**")
+	assert.Contains(t, content, "| Environment | production |")
+}
+
+func TestDeploymentNotice_OtherMessagesAreAnalyzed(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.post(t, "We decided to deploy checkout on Tuesdays")))
+
+	assert.Equal(t, 1, model.callCount(operationAnalyze))
+	assert.Len(t, documents(h.github), 1)
+}
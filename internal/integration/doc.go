@@ -0,0 +1,4 @@
+// Package integration tests the message pipeline end to end. The services run against the real
+// GitHub and LLM provider clients talking to httptest servers that emulate those APIs, and a fake
+// chat provider that records the bot's replies.
+package integration
@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentLint_FixesGeneratedDocument(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.responses[operationDocument] = "Sure! Here is the documentation:\n\n```markdown\n# Adopt Postgres\n\n" +
+				"As an AI language model, I was not part of the discussion.\nThe team will use Postgres for billing, " +
+				"see [the runbook](runbook.md).\n\n### Context\n\nInvoices outgrew MySQL.\n```\n\nLet me know if you need anything else!"
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "We decided to use Postgres for billing")
+
+			require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+			docs := documents(h.github)
+			require.Len(t, docs, 1)
+			content, ok := h.github.file(docs[0])
+			require.True(t, ok)
+			_, body, err := domain.ParseFrontMatter(content)
+			require.NoError(t, err)
+			assert.Equal(t, "# Adopt Postgres\n\nThe team will use Postgres for billing, see the runbook.\n\n## Context\n\nInvoices outgrew MySQL.", body)
+			assert.Equal(t, 1, model.callCount(operationDocument))
+			assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+		})
+	}
+}
+
+func TestDocumentLint_RegeneratesDocument(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.draft(operationDocument, "## Context\n\nThe team will use Postgres for [Insert team name].")
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "We decided to use Postgres for billing")
+
+			require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+			assert.Equal(t, 2, model.callCount(operationDocument))
+			docs := documents(h.github)
+			require.Len(t, docs, 1)
+			content, ok := h.github.file(docs[0])
+			require.True(t, ok)
+			assert.Contains(t, content, "# Adopt Postgres\n\nThe team will use Postgres for billing.")
+			assert.NotContains(t, content, "Insert team name")
+			assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+		})
+	}
+}
+
+func TestDocumentLint_FailsWhenRegeneratedDocumentIsInvalid(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.responses[operationDocument] = "As an AI language model, I cannot document meetings I did not attend."
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "We decided to use Postgres for billing")
+
+			err := h.bot.ProcessMessage(context.Background(), msg)
+
+			require.ErrorIs(t, err, domain.ErrDocumentLint)
+			assert.Equal(t, 2, model.callCount(operationDocument))
+			assert.Empty(t, documents(h.github))
+			assert.Equal(t, domain.MessageStateFailed, h.stored(t, msg).State())
+		})
+	}
+}
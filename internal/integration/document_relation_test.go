@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelateCommand_SupersedesOlderDecision(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	model.mu.Lock()
+	model.responses[operationTitle] = "Use MySQL for billing"
+	model.responses[operationDocument] = "# Use MySQL\n\nBilling stores invoices in MySQL."
+	model.mu.Unlock()
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use MySQL for billing")))
+
+	model.mu.Lock()
+	model.responses[operationTitle] = "Use Postgres for billing"
+	model.responses[operationDocument] = "# Use Postgres\n\nBilling moves to Postgres."
+	model.mu.Unlock()
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move billing to Postgres")))
+
+	var older, newer string
+	for _, path := range documents(h.github) {
+		if strings.Contains(path, "mysql") {
+			older = path
+		} else {
+			newer = path
+		}
+	}
+	require.NotEmpty(t, older)
+	require.NotEmpty(t, newer)
+
+	command := h.post(t, "/quill relate "+newer+" supersedes "+older)
+	require.NoError(t, h.bot.ProcessMessage(ctx, command))
+	replies := h.chat.repliesTo(command.ID().String())
+	require.NotEmpty(t, replies)
+	assert.Contains(t, replies[len(replies)-1], "now supersedes")
+
+	olderContent, ok := h.github.file(older)
+	require.True(t, ok)
+	fm, body, err := domain.ParseFrontMatter(olderContent)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DocumentStatusSuperseded, domain.DocumentStatusOf(fm))
+	assert.Equal(t, []string{newer}, fm.GetList("superseded_by"))
+	assert.True(t, strings.HasPrefix(body, "# Use MySQL\n\n> ⚠️ This decision was superseded by [Use Postgres]("), body)
+	assert.Contains(t, body, "(supersedes this document)")
+
+	newerContent, ok := h.github.file(newer)
+	require.True(t, ok)
+	fm, _, err = domain.ParseFrontMatter(newerContent)
+	require.NoError(t, err)
+	assert.Equal(t, []string{older}, fm.GetList("supersedes"))
+
+	index, ok := h.github.file(domain.CategoryIndexPath(domain.CategoryDevelopment))
+	require.True(t, ok)
+	assert.Contains(t, index, "_superseded by [Use Postgres](")
+	assert.Contains(t, index, "_supersedes [Use MySQL](")
+
+	// Relating the documents again changes nothing
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "/quill relate "+newer+" supersedes "+older)))
+	again, _ := h.github.file(older)
+	assert.Equal(t, 1, strings.Count(again, "This decision was superseded by"))
+}
+
+func TestRelateCommand_RejectsUnknownRelation(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+
+	command := h.post(t, "/quill relate docs/development/a.md replaces docs/development/b.md")
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), command))
+
+	replies := h.chat.repliesTo(command.ID().String())
+	require.Len(t, replies, 1)
+	assert.Contains(t, replies[0], "unknown relation")
+}
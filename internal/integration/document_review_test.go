@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewedProject creates a project bound to the test channel whose decisions are reviewed after 30 days
+func reviewedProject(t *testing.T, h *harness) *domain.Project {
+	t.Helper()
+	ctx := context.Background()
+
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{Name: "Billing", BusinessGoals: []string{"Move billing to Postgres"}}, domain.DocumentationConfig{ReviewAfterDays: 30})
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+	return project
+}
+
+// decisionDocument returns the index entry of the only decision documented
+func decisionDocument(t *testing.T, h *harness) *domain.IndexedDocument {
+	t.Helper()
+
+	indexed, err := h.index.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, indexed, 1)
+	require.True(t, indexed[0].Type().IsDecision())
+	return indexed[0]
+}
+
+func frontMatterOf(t *testing.T, h *harness, path string) *domain.FrontMatter {
+	t.Helper()
+
+	content, ok := h.github.file(path)
+	require.True(t, ok, "document %s not found", path)
+	fm, _, err := domain.ParseFrontMatter(content)
+	require.NoError(t, err)
+	return fm
+}
+
+func TestDocumentReview_FlagsStaleDecisionAndRecordsVerdict(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	reviewedProject(t, h)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+	doc := decisionDocument(t, h)
+
+	require.NoError(t, h.reviews.FlagStale(ctx, doc.UpdatedAt().Add(29*24*time.Hour)))
+	assert.Empty(t, h.chat.sentTo(testChannel))
+	assert.Equal(t, domain.DocumentStatusActive, domain.DocumentStatusOf(frontMatterOf(t, h, doc.Path())))
+
+	require.NoError(t, h.reviews.FlagStale(ctx, doc.UpdatedAt().Add(31*24*time.Hour)))
+	reminders := h.chat.sentTo(testChannel)
+	require.Len(t, reminders, 1)
+	assert.Contains(t, reminders[0], "`"+doc.Path()+"`")
+	assert.Equal(t, domain.DocumentStatusNeedsReview, domain.DocumentStatusOf(frontMatterOf(t, h, doc.Path())))
+
+	// A decision waiting for review is not flagged again
+	require.NoError(t, h.reviews.FlagStale(ctx, doc.UpdatedAt().Add(62*24*time.Hour)))
+	assert.Len(t, h.chat.sentTo(testChannel), 1)
+
+	review, err := domain.NewDocumentReview(doc.Path(), domain.ReviewSupersede, "U0002")
+	require.NoError(t, err)
+	require.NoError(t, h.reviews.Review(ctx, review))
+
+	fm := frontMatterOf(t, h, doc.Path())
+	assert.Equal(t, domain.DocumentStatusSuperseded, domain.DocumentStatusOf(fm))
+	assert.Equal(t, "U0002", fm.Get("reviewed_by"))
+	assert.False(t, domain.ReviewedAt(fm).IsZero())
+	assert.False(t, fm.Has("review_requested_at"))
+
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionReview, entries[0].Action())
+	assert.Equal(t, doc.Path(), entries[0].Subject())
+	assert.Equal(t, "needs-review", entries[0].From())
+	assert.Equal(t, "superseded", entries[0].To())
+}
+
+func TestDocumentReview_ThreadActivityKeepsDecisionCurrent(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	reviewedProject(t, h)
+
+	msg := h.post(t, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	doc := decisionDocument(t, h)
+
+	// Someone replies in the source thread 20 days after the decision was documented
+	reply := msg.ToDTO()
+	reply.ID = "01HZZZZZZZZZZZZZZZZZZZZZZZ"
+	reply.Content = "Postgres is still the plan"
+	reply.Timestamp = doc.UpdatedAt().Add(20 * 24 * time.Hour)
+	replyMsg, err := domain.MessageFromDTO(reply)
+	require.NoError(t, err)
+	require.NoError(t, h.messages.Save(ctx, replyMsg))
+
+	require.NoError(t, h.reviews.FlagStale(ctx, doc.UpdatedAt().Add(45*24*time.Hour)))
+	assert.Empty(t, h.chat.sentTo(testChannel))
+
+	require.NoError(t, h.reviews.FlagStale(ctx, doc.UpdatedAt().Add(51*24*time.Hour)))
+	reminders := h.chat.sentTo(testChannel)
+	require.Len(t, reminders, 1)
+	assert.Contains(t, reminders[0], "has not changed or been discussed in 4 weeks")
+
+	review, err := domain.NewDocumentReview(doc.Path(), domain.ReviewReconfirm, "U0002")
+	require.NoError(t, err)
+	require.NoError(t, h.reviews.Review(ctx, review))
+	assert.Equal(t, domain.DocumentStatusActive, domain.DocumentStatusOf(frontMatterOf(t, h, doc.Path())))
+}
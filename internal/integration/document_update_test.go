@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reply builds a reply posted in the thread of a message
+func (h *harness) reply(t *testing.T, to *domain.Message, text string) *domain.Message {
+	t.Helper()
+
+	msg, err := domain.NewMessage(to.ThreadID(), "bob", domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID(to.ChannelID())
+	return msg
+}
+
+// approvingProject binds the test channel to a project approving document updates
+func approvingProject(t *testing.T, h *harness) {
+	t.Helper()
+
+	ctx := context.Background()
+	docConfig := domain.DefaultDocumentationConfig()
+	docConfig.ApproveUpdates = true
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, docConfig)
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+}
+
+// offerMerge documents an idea and posts it again, so the second message is offered to merge into the first
+func offerMerge(t *testing.T, h *harness) (string, *domain.Message) {
+	t.Helper()
+
+	ctx := context.Background()
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We could use Postgres for billing")))
+	docs := documents(h.github)
+	require.Len(t, docs, 1)
+
+	// The idea repeats what the first document says
+	idea := h.post(t, "Adopt Postgres: the team will use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, idea))
+	replies := h.chat.repliesTo(idea.ID().String())
+	require.Len(t, replies, 1)
+	require.Contains(t, replies[0], "looks similar")
+	return docs[0], idea
+}
+
+func TestDocumentUpdate_AppliesApprovedDiff(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	approvingProject(t, h)
+	path, idea := offerMerge(t, h)
+	before, _ := h.github.file(path)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "merge")))
+
+	unchanged, _ := h.github.file(path)
+	assert.Equal(t, before, unchanged)
+	replies := h.chat.repliesTo(idea.ID().String())
+	require.Len(t, replies, 2)
+	assert.True(t, strings.HasPrefix(replies[1], "📝 Merging this idea changes"))
+	assert.Contains(t, replies[1], "--- a/"+path)
+	assert.Contains(t, replies[1], "+## Addendum ")
+	assert.Equal(t, domain.MessageStateAnalyzing, h.stored(t, idea).State())
+
+	// Only the author of the idea applies the merge
+	denied := h.reply(t, idea, "apply")
+	require.NoError(t, h.bot.ProcessMessage(ctx, denied))
+	assert.Contains(t, h.chat.repliesTo(denied.ID().String()), "🙅 Only the author of the idea can apply or discard its merge")
+	unchanged, _ = h.github.file(path)
+	assert.Equal(t, before, unchanged)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "apply")))
+
+	updated, _ := h.github.file(path)
+	assert.Contains(t, updated, "## Addendum ")
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, idea).State())
+	messages := h.github.commitMessages()
+	last := messages[len(messages)-1]
+	assert.True(t, strings.HasPrefix(last, "Update idea documentation (development)"))
+	assert.Regexp(t, `\n\n\d+ lines added, \d+ removed$`, last)
+}
+
+func TestDocumentUpdate_DiscardsDiff(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	approvingProject(t, h)
+	path, idea := offerMerge(t, h)
+	before, _ := h.github.file(path)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "merge")))
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "discard")))
+
+	after, _ := h.github.file(path)
+	assert.Equal(t, before, after)
+	stored := h.stored(t, idea)
+	assert.Equal(t, domain.MessageStateIgnored, stored.State())
+	assert.Equal(t, "update discarded by "+idea.Sender(), stored.StateReason())
+}
+
+func TestDocumentUpdate_WaitsInThePendingUpdateStore(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	approvingProject(t, h)
+	path, idea := offerMerge(t, h)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "merge")))
+
+	pending, err := h.updates.Find(ctx, idea.ThreadID().String(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, path, pending.Path())
+	assert.Equal(t, idea.Sender(), pending.Author())
+	assert.WithinDuration(t, time.Now().Add(domain.DefaultUpdateApprovalTTL), pending.ExpiresAt(), time.Minute)
+	approvals, err := h.bot.PendingApprovals(ctx, []string{testChannel})
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+	assert.Equal(t, domain.ApprovalUpdate, approvals[0].Kind)
+
+	// Once expired, the merge is no longer applied
+	expired, err := domain.NewPendingUpdate(pending.ThreadID(), pending.MessageID(), pending.Author(), pending.Path(),
+		pending.Content(), pending.BaseRevision(), pending.Attached(), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, h.updates.Put(ctx, expired))
+	before, _ := h.github.file(path)
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "apply")))
+	after, _ := h.github.file(path)
+	assert.Equal(t, before, after)
+}
+
+func TestDocumentUpdate_MergesWithoutApproval(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	path, idea := offerMerge(t, h)
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.replyAs(t, idea, idea.Sender(), "merge")))
+
+	updated, _ := h.github.file(path)
+	assert.Contains(t, updated, "## Addendum ")
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, idea).State())
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateChoice_OnlyTheAuthorChooses(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	path, idea := offerMerge(t, h)
+	before, _ := h.github.file(path)
+
+	other := h.reply(t, idea, "merge")
+	require.NoError(t, h.bot.ProcessMessage(ctx, other))
+
+	unchanged, _ := h.github.file(path)
+	assert.Equal(t, before, unchanged)
+	assert.Len(t, documents(h.github), 1, "the reply is not documented either")
+	assert.Contains(t, h.chat.repliesTo(other.ID().String()), "🙅 Only the author of the idea can choose whether it is merged")
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "merge")))
+
+	updated, _ := h.github.file(path)
+	assert.Contains(t, updated, "## Addendum ")
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, idea).State())
+}
+
+func TestDuplicateChoice_Expires(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	path, idea := offerMerge(t, h)
+	before, _ := h.github.file(path)
+
+	pending, err := h.duplicates.Find(ctx, idea.ThreadID().String(), time.Now())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(domain.DefaultDuplicateChoiceTTL), pending.ExpiresAt(), time.Minute)
+	expired, err := domain.NewPendingDuplicate(pending.ThreadID(), pending.MessageID(), pending.Author(), pending.Candidates(), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, h.duplicates.Put(ctx, expired))
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "merge")))
+
+	unchanged, _ := h.github.file(path)
+	assert.Equal(t, before, unchanged)
+	assert.NotEqual(t, domain.MessageStateDocumented, h.stored(t, idea).State())
+}
+
+func TestDuplicateChoice_MadeWithTheStoredIdea(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	_, idea := offerMerge(t, h)
+
+	// The choice and the analysis of the idea are stored, nothing is kept in the bot for a restart to lose
+	pending, err := h.duplicates.Find(ctx, idea.ThreadID().String(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, idea.ID().String(), pending.MessageID())
+	assert.Equal(t, "alice", pending.Author())
+	stored := h.stored(t, idea)
+	assert.Equal(t, domain.MessageTypeIdea, stored.Type())
+	assert.Equal(t, domain.CategoryDevelopment, stored.Category())
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.replyAs(t, idea, idea.Sender(), "new")))
+
+	assert.Len(t, documents(h.github), 2)
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, idea).State())
+	_, err = h.duplicates.Find(ctx, idea.ThreadID().String(), time.Now())
+	assert.Error(t, err, "the choice is made once")
+}
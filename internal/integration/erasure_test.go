@@ -0,0 +1,250 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewedDecision documents a decision posted by alice and reviewed by her
+func reviewedDecision(t *testing.T, h *harness) (*domain.Message, *domain.IndexedDocument) {
+	t.Helper()
+	ctx := context.Background()
+
+	msg := h.post(t, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	doc := decisionDocument(t, h)
+	review, err := domain.NewDocumentReview(doc.Path(), domain.ReviewReconfirm, "alice")
+	require.NoError(t, err)
+	require.NoError(t, h.reviews.Review(ctx, review))
+	correction, err := domain.NewCorrection(h.stored(t, msg), domain.CorrectionFieldType, "status", "decision", "alice")
+	require.NoError(t, err)
+	require.NoError(t, h.corrections.Record(ctx, correction))
+	return msg, doc
+}
+
+func TestErasure_ErasesEverythingStoredAboutAPerson(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	msg, doc := reviewedDecision(t, h)
+
+	request, err := domain.NewErasureRequest("alice", domain.ErasureErase, "dpo")
+	require.NoError(t, err)
+	report, err := h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Messages)
+	assert.Equal(t, 1, report.Corrections)
+	assert.Equal(t, 1, report.AuditEntries)
+	assert.Equal(t, []string{doc.Path()}, report.Documents)
+	assert.False(t, report.CompletedAt.IsZero())
+
+	_, err = h.messages.FindByID(ctx, msg.ID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	corrections, err := h.corrections.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+	fm := frontMatterOf(t, h, doc.Path())
+	assert.False(t, fm.Has("reviewed_by"))
+	assert.Equal(t, domain.DocumentStatusActive, domain.DocumentStatusOf(fm))
+
+	// The review stays in the audit log without naming alice, and the erasure is recorded under her pseudonym
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, domain.ErasedIdentity, entries[0].Actor())
+	assert.Equal(t, domain.AuditActionErase, entries[1].Action())
+	assert.Equal(t, "dpo", entries[1].Actor())
+	assert.Equal(t, domain.Pseudonym("alice"), entries[1].Subject())
+}
+
+// keptElsewhere puts what alice sent in the other stores keeping people's data, and names her in the front
+// matter keys of features
+func keptElsewhere(t *testing.T, h *harness, msg *domain.Message, doc *domain.IndexedDocument) {
+	t.Helper()
+	ctx := context.Background()
+
+	thread, err := domain.NewThread(msg.ThreadID(), testChannel, "Billing database")
+	require.NoError(t, err)
+	require.NoError(t, thread.AddMessage(h.stored(t, msg)))
+	require.NoError(t, thread.AddMessage(h.reply(t, msg, "Postgres works for me")))
+	require.NoError(t, h.threadRepo.Save(ctx, thread))
+	queued, err := domain.NewQueuedMessage(h.post(t, "We decided to shard invoices"), nil)
+	require.NoError(t, err)
+	letter, err := domain.NewDeadLetter(queued, "analysis timed out", time.Now())
+	require.NoError(t, err)
+	require.NoError(t, h.deadLetters.Add(ctx, letter))
+	require.NoError(t, h.profiles.Put(ctx, domain.UserProfile{ID: "alice", Name: "alice", RealName: "Alice Liddell"}))
+	item, err := domain.NewTriageItem(h.post(t, "Maybe Postgres?"), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, h.triageQueue.Add(ctx, item))
+	standup, err := domain.NewStandup(common.GenerateID(), "Billing", []string{"Today?"}, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, standup.Ask("alice", "D0001"))
+	require.NoError(t, standup.Answer(domain.StandupAnswer{Member: "alice", Sender: "alice", Text: "Migrating invoices"}))
+	require.NoError(t, h.standupStore.Save(ctx, standup))
+	pending, err := domain.NewPendingDuplicate(msg.ThreadID().String(), msg.ID().String(), "alice", []string{doc.Path()}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, h.duplicates.Put(ctx, pending))
+	update, err := domain.NewPendingUpdate(msg.ThreadID().String(), msg.ID().String(), "alice", doc.Path(), "# Billing database",
+		"", nil, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, h.updates.Put(ctx, update))
+	request := domain.NewApprovalRequest(h.stored(t, msg), domain.DocumentationConfig{ApprovalPolicies: []domain.ApprovalPolicy{{}}}, time.Now())
+	require.NoError(t, h.approvals.Put(ctx, request))
+
+	content, ok := h.github.file(doc.Path())
+	require.True(t, ok)
+	fm, body, err := domain.ParseFrontMatter(content)
+	require.NoError(t, err)
+	fm.Set("owner", "alice")
+	fm.Set("incident_commander", "alice")
+	fm.SetList("attendees", []string{"alice", "bob"})
+	fm.SetList("answered_by", []string{"alice"})
+	h.github.edit(doc.Path(), fm.Apply(body))
+}
+
+func TestErasure_ErasesTheOtherStores(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	msg, doc := reviewedDecision(t, h)
+	keptElsewhere(t, h, msg, doc)
+
+	request, err := domain.NewErasureRequest("alice", domain.ErasureErase, "dpo")
+	require.NoError(t, err)
+	report, err := h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"dead letters": 1, "threads": 1, "user profiles": 1, "triage queue": 1, "standups": 1,
+		"pending duplicates": 1, "pending updates": 1, "approval requests": 1}, report.Records)
+	thread, err := h.threadRepo.FindByID(ctx, msg.ThreadID())
+	require.NoError(t, err)
+	require.Equal(t, 1, thread.MessageCount())
+	assert.Equal(t, "bob", thread.Messages()[0].Sender())
+	letters, err := h.deadLetters.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+	_, err = h.profiles.Get(ctx, "alice")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	items, err := h.triageQueue.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	standups, err := h.standupStore.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, standups, 1)
+	assert.Empty(t, standups[0].Members())
+	assert.Empty(t, standups[0].Answers())
+	_, err = h.duplicates.Find(ctx, msg.ThreadID().String(), time.Now())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	_, err = h.updates.Find(ctx, msg.ThreadID().String(), time.Now())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	_, err = h.approvals.Find(ctx, msg.ThreadID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	fm := frontMatterOf(t, h, doc.Path())
+	for _, key := range []string{"owner", "incident_commander", "answered_by"} {
+		assert.False(t, fm.Has(key), key)
+	}
+	assert.Equal(t, []string{"bob"}, fm.GetList("attendees"))
+}
+
+func TestErasure_PseudonymizesTheOtherStores(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	msg, doc := reviewedDecision(t, h)
+	keptElsewhere(t, h, msg, doc)
+
+	request, err := domain.NewErasureRequest("alice", domain.ErasurePseudonymize, "dpo")
+	require.NoError(t, err)
+	_, err = h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+
+	thread, err := h.threadRepo.FindByID(ctx, msg.ThreadID())
+	require.NoError(t, err)
+	require.Equal(t, 2, thread.MessageCount())
+	assert.Equal(t, request.Pseudonym(), thread.Messages()[0].Sender())
+	letters, err := h.deadLetters.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, request.Pseudonym(), letters[0].Queued().Message().Sender())
+	items, err := h.triageQueue.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, request.Pseudonym(), items[0].Message().Sender())
+	standups, err := h.standupStore.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{request.Pseudonym()}, standups[0].Members())
+	waiting, err := h.approvals.Find(ctx, msg.ThreadID().String())
+	require.NoError(t, err)
+	assert.Equal(t, request.Pseudonym(), waiting.Message().Sender())
+	fm := frontMatterOf(t, h, doc.Path())
+	assert.Equal(t, request.Pseudonym(), fm.Get("incident_commander"))
+	assert.Equal(t, []string{request.Pseudonym(), "bob"}, fm.GetList("attendees"))
+}
+
+func TestErasure_PseudonymizesAPerson(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	msg, doc := reviewedDecision(t, h)
+
+	request, err := domain.NewErasureRequest("alice", domain.ErasurePseudonymize, "dpo")
+	require.NoError(t, err)
+	report, err := h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Messages)
+	assert.Equal(t, []string{doc.Path()}, report.Documents)
+	stored := h.stored(t, msg)
+	assert.Equal(t, request.Pseudonym(), stored.Sender())
+	assert.Equal(t, "We decided to use Postgres for billing", stored.Content().Text())
+	corrections, err := h.corrections.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, request.Pseudonym(), corrections[0].Actor())
+	assert.Equal(t, request.Pseudonym(), frontMatterOf(t, h, doc.Path()).Get("reviewed_by"))
+
+	// Nothing names alice any more, so erasing again changes nothing
+	report, err = h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+	assert.Zero(t, report.Messages)
+	assert.Zero(t, report.Corrections)
+	assert.Zero(t, report.AuditEntries)
+	assert.Empty(t, report.Documents)
+}
+
+func TestErasure_ErasesNamesInDocumentBodies(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+	msg := h.post(t, "Invoice export is done")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	rollup := domain.StatusRollupPath(domain.CategoryDevelopment, msg.Timestamp())
+
+	// A document indexed but gone from its store does not hold up the erasure of the others
+	gone, err := domain.NewIndexedDocument("docs/development/gone.md", "Gone", "Deleted by hand", domain.MessageTypeDecision, domain.CategoryDevelopment)
+	require.NoError(t, err)
+	require.NoError(t, h.index.Index(ctx, gone))
+
+	request, err := domain.NewErasureRequest("alice", domain.ErasureErase, "dpo")
+	require.NoError(t, err)
+	report, err := h.erasure.Erase(ctx, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{rollup}, report.Documents)
+	content, ok := h.github.file(rollup)
+	require.True(t, ok, "rollup not found in %v", h.github.paths())
+	assert.NotContains(t, content, " by alice")
+	assert.Contains(t, content, " by "+domain.ErasedIdentity+"\n")
+	assert.Contains(t, content, "- Source message: `"+msg.ID().String()+"`", "the entry is kept")
+}
@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFlagSource serves the feature flags a remote flag service would
+type fakeFlagSource struct {
+	mu    sync.Mutex
+	flags domain.FeatureFlags
+	err   error
+}
+
+func (s *fakeFlagSource) set(flags domain.FeatureFlags) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags, s.err = flags, nil
+}
+
+func (s *fakeFlagSource) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *fakeFlagSource) FeatureFlags(ctx context.Context) (domain.FeatureFlags, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flags, s.err
+}
+
+func TestFeatureFlags_ApprovalsRollOutPerProject(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	governedProject(t, h)
+
+	// Approvals are off for every project but another one, so the decision is documented right away
+	h.flags.set(domain.FeatureFlags{domain.FeatureApprovals: {Projects: []string{"Search"}}})
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move invoices to Postgres")))
+	assert.Len(t, documents(h.github), 1)
+	assert.Contains(t, h.command(t, testChannel, "/quill features"), "❌ approvals")
+
+	// Once the project is listed, its decisions wait for their approvals
+	h.flags.set(domain.FeatureFlags{domain.FeatureApprovals: {Projects: []string{"Search", "Billing"}}})
+	msg := h.post(t, "We decided to render invoices in a queue")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	assert.Len(t, documents(h.github), 1)
+	assert.Contains(t, lastReply(t, h, msg), "🔐 This decision needs 2 approvals")
+	assert.Contains(t, h.command(t, testChannel, "/quill features"), "✅ approvals")
+}
+
+func TestFeatureFlags_QuestionsKeepLastFlagsWhenSourceFails(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	reviewedProject(t, h)
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.post(t, "We decided to use Postgres for billing")))
+
+	h.flags.set(domain.FeatureFlags{domain.FeatureQuestions: {Rollout: 0}})
+	assert.Equal(t, "🧪 Questions are not turned on for this project yet.", h.command(t, testChannel, "/quill ask postgres billing"))
+	assert.Zero(t, model.callCount(operationAnswer))
+
+	h.flags.set(domain.FeatureFlags{domain.FeatureQuestions: {Rollout: 100}})
+	h.command(t, testChannel, "/quill ask postgres billing")
+	assert.Equal(t, 1, model.callCount(operationAnswer))
+
+	// The flags fetched last stay in use while the flag service is down
+	h.flags.fail(errors.New("flag service unavailable"))
+	h.command(t, testChannel, "/quill ask postgres billing")
+	assert.Equal(t, 2, model.callCount(operationAnswer))
+	assert.Contains(t, h.command(t, testChannel, "/quill features"), "✅ questions (rolling out to 100% of projects)")
+}
@@ -0,0 +1,34 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeed_ListsTheDocumentsOfAProject(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	path := documentedInProject(t, h)
+
+	entry, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	feed, err := h.feeds.Feed(ctx, entry.Project().String(), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Billing", feed.Project().Name())
+	require.Len(t, feed.Documents(), 1)
+	assert.Equal(t, path, feed.Documents()[0].Path())
+	assert.Equal(t, "Adopt Postgres", feed.Documents()[0].Title())
+
+	_, err = h.feeds.Feed(ctx, common.GenerateID().String(), 0)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	_, err = h.feeds.Feed(ctx, "billing", 0)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
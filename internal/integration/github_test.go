@@ -0,0 +1,438 @@
+package integration
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/providers/docstore/github"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	githubOwner  = "acme"
+	githubRepo   = "docs"
+	githubBranch = "main"
+)
+
+// fakeGitHub emulates the contents and Git Data APIs of a single repository branch in memory
+type fakeGitHub struct {
+	mu       sync.Mutex
+	files    map[string][]byte
+	head     string
+	trees    map[string]map[string][]byte
+	blobs    map[string][]byte // Every version of a file read or written, by blob SHA
+	branches map[string]map[string][]byte
+	pulls    []github.GitHubCreatePullRequest
+	commits  map[string]string // Commit SHA to tree SHA
+	messages []string          // Commit messages, oldest first
+	failures []githubFailure
+	requests []string
+}
+
+// githubFailure makes the next matching requests fail with a status code
+type githubFailure struct {
+	method string
+	prefix string
+	status int
+	times  int
+}
+
+func newFakeGitHub() *fakeGitHub {
+	return &fakeGitHub{
+		files:    make(map[string][]byte),
+		trees:    make(map[string]map[string][]byte),
+		blobs:    make(map[string][]byte),
+		branches: make(map[string]map[string][]byte),
+		commits:  make(map[string]string),
+	}
+}
+
+// store creates a GitHub document store backed by a server running the fake
+func (f *fakeGitHub) store(t testing.TB) *github.DocumentStoreProvider {
+	t.Helper()
+
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	client, err := github.NewClient(&github.Config{
+		Token:          "token",
+		Owner:          githubOwner,
+		Repo:           githubRepo,
+		Branch:         githubBranch,
+		CommitterName:  "Quill",
+		CommitterEmail: "quill@example.com",
+		BaseURL:        server.URL,
+	})
+	require.NoError(t, err)
+	return github.NewDocumentStoreProvider(client)
+}
+
+// failNext makes the next requests with the method and a path starting with the prefix fail
+func (f *fakeGitHub) failNext(method, prefix string, status, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, githubFailure{method: method, prefix: prefix, status: status, times: times})
+}
+
+// file returns the content of a file on the branch
+func (f *fakeGitHub) file(path string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.files[path]
+	return string(content), ok
+}
+
+// paths lists the files on the branch, .gitkeep placeholders left out
+func (f *fakeGitHub) paths() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var paths []string
+	for path := range f.files {
+		if !strings.HasSuffix(path, ".gitkeep") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// edit changes a file on the branch as a person would in the web interface
+func (f *fakeGitHub) edit(path, content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[path] = []byte(content)
+	f.commit("Update " + path)
+}
+
+// pullRequests lists the pull requests opened so far
+func (f *fakeGitHub) pullRequests() []github.GitHubCreatePullRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]github.GitHubCreatePullRequest(nil), f.pulls...)
+}
+
+// branchFile returns the content of a file on another branch than the main one
+func (f *fakeGitHub) branchFile(branch, path string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.branches[branch][path]
+	return string(content), ok
+}
+
+// commitMessages lists the messages of the commits made so far
+func (f *fakeGitHub) commitMessages() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.messages...)
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if status, ok := f.failure(r); ok {
+		writeJSON(w, status, map[string]string{"message": "injected failure"})
+		return
+	}
+
+	repoPrefix := fmt.Sprintf("/repos/%s/%s/", githubOwner, githubRepo)
+	if !strings.HasPrefix(r.URL.Path, repoPrefix) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, repoPrefix)
+
+	switch {
+	case strings.HasPrefix(path, "contents"):
+		f.serveContents(w, r, strings.Trim(strings.TrimPrefix(path, "contents"), "/"))
+	case strings.HasPrefix(path, "git/"):
+		f.serveGit(w, r, strings.TrimPrefix(path, "git/"))
+	case r.Method == http.MethodPost && path == "pulls":
+		var request github.GitHubCreatePullRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		if _, ok := f.branches[request.Head]; !ok || request.Base != githubBranch {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "Validation Failed"})
+			return
+		}
+		f.pulls = append(f.pulls, request)
+		writeJSON(w, http.StatusCreated, github.GitHubPullRequest{
+			Number:  len(f.pulls),
+			HTMLURL: fmt.Sprintf("https://github.com/%s/%s/pull/%d", githubOwner, githubRepo, len(f.pulls)),
+		})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+func (f *fakeGitHub) failure(r *http.Request) (int, bool) {
+	for i := range f.failures {
+		failure := &f.failures[i]
+		if failure.times == 0 || failure.method != r.Method || !strings.HasPrefix(r.URL.Path, failure.prefix) {
+			continue
+		}
+		failure.times--
+		return failure.status, true
+	}
+	return 0, false
+}
+
+func (f *fakeGitHub) serveContents(w http.ResponseWriter, r *http.Request, path string) {
+	switch r.Method {
+	case http.MethodGet:
+		if content, ok := f.files[path]; ok {
+			writeJSON(w, http.StatusOK, f.contentOf(path, content))
+			return
+		}
+		items := f.list(path)
+		if len(items) == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, items)
+	case http.MethodPut:
+		var file github.GitHubFile
+		if err := json.NewDecoder(r.Body).Decode(&file); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		files := f.files
+		if file.Branch != "" && file.Branch != githubBranch {
+			if files = f.branches[file.Branch]; files == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"message": "Branch not found"})
+				return
+			}
+		}
+		existing, exists := files[path]
+		if exists && file.SHA != blobSHA(existing) {
+			writeJSON(w, http.StatusConflict, map[string]string{"message": path + " does not match " + file.SHA})
+			return
+		}
+		content, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		files[path] = content
+		status := http.StatusCreated
+		if exists {
+			status = http.StatusOK
+		}
+		stored := f.contentOf(path, content)
+		writeJSON(w, status, github.GitHubCommitResponse{Content: &stored, Commit: &github.GitHubCommit{SHA: f.commit(file.Message)}})
+	case http.MethodDelete:
+		var file github.GitHubFile
+		_ = json.NewDecoder(r.Body).Decode(&file)
+		existing, exists := f.files[path]
+		if !exists {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		if file.SHA != blobSHA(existing) {
+			writeJSON(w, http.StatusConflict, map[string]string{"message": path + " does not match " + file.SHA})
+			return
+		}
+		delete(f.files, path)
+		writeJSON(w, http.StatusOK, github.GitHubCommitResponse{Commit: &github.GitHubCommit{SHA: f.commit(file.Message)}})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "Method Not Allowed"})
+	}
+}
+
+func (f *fakeGitHub) serveGit(w http.ResponseWriter, r *http.Request, path string) {
+	switch {
+	case r.Method == http.MethodGet && path == "ref/heads/"+githubBranch:
+		if f.head == "" {
+			writeJSON(w, http.StatusConflict, map[string]string{"message": "Git Repository is empty."})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"ref":    "refs/heads/" + githubBranch,
+			"object": map[string]string{"sha": f.head, "type": "commit"},
+		})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "commits/"):
+		sha := strings.TrimPrefix(path, "commits/")
+		if sha != f.head {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		// The head tree is the current branch content
+		treeSHA := "tree-" + sha
+		f.trees[treeSHA] = f.snapshot()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"sha": sha, "tree": map[string]string{"sha": treeSHA}})
+	case r.Method == http.MethodPost && path == "trees":
+		var request github.GitHubCreateTree
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		base, ok := f.trees[request.BaseTree]
+		if !ok {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "base tree not found"})
+			return
+		}
+		tree := make(map[string][]byte, len(base)+len(request.Tree))
+		for p, content := range base {
+			tree[p] = content
+		}
+		for _, entry := range request.Tree {
+			if entry.SHA != "" {
+				tree[entry.Path] = f.blobs[entry.SHA]
+				continue
+			}
+			tree[entry.Path] = []byte(entry.Content)
+		}
+		sha := fmt.Sprintf("tree-%d", len(f.trees)+1)
+		f.trees[sha] = tree
+		writeJSON(w, http.StatusCreated, github.GitHubObject{SHA: sha})
+	case r.Method == http.MethodPost && path == "blobs":
+		var request github.GitHubCreateBlob
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		content, err := base64.StdEncoding.DecodeString(request.Content)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		f.blobs[blobSHA(content)] = content
+		writeJSON(w, http.StatusCreated, github.GitHubObject{SHA: blobSHA(content)})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "blobs/"):
+		content, ok := f.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, github.GitHubBlob{SHA: blobSHA(content), Content: base64.StdEncoding.EncodeToString(content), Encoding: "base64"})
+	case r.Method == http.MethodPost && path == "refs":
+		var request github.GitHubCreateRef
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		branch := strings.TrimPrefix(request.Ref, "refs/heads/")
+		if _, exists := f.branches[branch]; exists || request.SHA != f.head {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "Reference cannot be created"})
+			return
+		}
+		f.branches[branch] = f.snapshot()
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"ref": request.Ref, "object": map[string]string{"sha": request.SHA}})
+	case r.Method == http.MethodPost && path == "commits":
+		var request github.GitHubCreateCommit
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		if len(request.Parents) != 1 || request.Parents[0] != f.head {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "parent is not the branch head"})
+			return
+		}
+		sha := fmt.Sprintf("commit-pending-%d", len(f.commits)+1)
+		f.commits[sha] = request.Tree
+		f.messages = append(f.messages, request.Message)
+		writeJSON(w, http.StatusCreated, github.GitHubObject{SHA: sha})
+	case r.Method == http.MethodPatch && path == "refs/heads/"+githubBranch:
+		var request github.GitHubUpdateRef
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		treeSHA, ok := f.commits[request.SHA]
+		if !ok {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "commit not found"})
+			return
+		}
+		f.files = f.trees[treeSHA]
+		f.head = request.SHA
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ref": "refs/heads/" + githubBranch, "object": map[string]string{"sha": f.head}})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+// commit records a commit made through the contents API and moves the branch head
+func (f *fakeGitHub) commit(message string) string {
+	f.messages = append(f.messages, message)
+	f.head = fmt.Sprintf("commit-%d", len(f.messages))
+	return f.head
+}
+
+// list returns the entries directly inside a directory
+func (f *fakeGitHub) list(dir string) []github.GitHubContentListItem {
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+
+	seen := make(map[string]bool)
+	var items []github.GitHubContentListItem
+	for path, content := range f.files {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		name, rest, isDir := strings.Cut(strings.TrimPrefix(path, prefix), "/")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		item := github.GitHubContentListItem{Type: "file", Name: name, Path: prefix + name, Size: len(content)}
+		if isDir && rest != "" {
+			item.Type = "dir"
+			item.Size = 0
+		} else {
+			item.SHA = blobSHA(content)
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items
+}
+
+// contentOf describes a file as the contents API does, and keeps the version readable as a blob
+func (f *fakeGitHub) contentOf(path string, content []byte) github.GitHubContent {
+	f.blobs[blobSHA(content)] = content
+	return github.GitHubContent{
+		Type:     "file",
+		Size:     len(content),
+		Name:     path[strings.LastIndex(path, "/")+1:],
+		Path:     path,
+		Content:  base64.StdEncoding.EncodeToString(content),
+		Encoding: "base64",
+		SHA:      blobSHA(content),
+	}
+}
+
+func (f *fakeGitHub) snapshot() map[string][]byte {
+	files := make(map[string][]byte, len(f.files))
+	for path, content := range f.files {
+		files[path] = content
+	}
+	return files
+}
+
+// blobSHA returns the Git blob SHA of the content
+func blobSHA(content []byte) string {
+	h := sha1.New()
+	_, _ = fmt.Fprintf(h, "blob %d\x00", len(content))
+	_, _ = h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/docstore/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const githubWebhookSecret = "webhook-secret"
+
+// webhook returns the handler of the push webhooks of the fake repository
+func (h *harness) webhook(t *testing.T) http.Handler {
+	t.Helper()
+
+	handler, err := github.NewWebhookHandler(&github.Config{
+		Token:          "token",
+		Owner:          githubOwner,
+		Repo:           githubRepo,
+		Branch:         githubBranch,
+		CommitterName:  "Quill",
+		CommitterEmail: "quill@example.com",
+		WebhookSecret:  githubWebhookSecret,
+	}, h.reconciler)
+	require.NoError(t, err)
+	return handler
+}
+
+// push delivers a signed push webhook of a commit by the author changing a file
+func push(t *testing.T, handler http.Handler, email, change, path string) {
+	t.Helper()
+
+	payload := fmt.Sprintf(`{"ref":"refs/heads/%s","repository":{"full_name":"%s/%s"},"commits":[
+		{"id":"c1","author":{"name":"Alice","email":%q,"username":"alice"},%q:[%q]}]}`,
+		githubBranch, githubOwner, githubRepo, email, change, path)
+	mac := hmac.New(sha256.New, []byte(githubWebhookSecret))
+	mac.Write([]byte(payload))
+	req := httptest.NewRequest(http.MethodPost, "/github", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+}
+
+// documentedInProject documents a decision in a project bound to the test channel and returns its path
+func documentedInProject(t *testing.T, h *harness) string {
+	t.Helper()
+
+	ctx := context.Background()
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{Name: "Billing", BusinessGoals: []string{"Bill customers"}}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move billing to Postgres")))
+	return documents(h.github)[0]
+}
+
+func TestGitHubWebhook_RefreshesDocumentsPeopleEdit(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	path := documentedInProject(t, h)
+
+	content, _ := h.github.file(path)
+	h.github.edit(path, strings.Replace(content, "# Adopt Postgres\n", "# Adopt Postgres 16\n", 1))
+	push(t, h.webhook(t), "alice@example.com", "modified", path)
+
+	entry, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "Adopt Postgres 16", entry.Title())
+	notes := h.chat.sentTo(testChannel)
+	require.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "✏️ alice edited ")
+	assert.Contains(t, notes[0], path)
+}
+
+func TestGitHubWebhook_IgnoresTheBotsOwnCommits(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	path := documentedInProject(t, h)
+
+	push(t, h.webhook(t), "quill@example.com", "modified", path)
+
+	assert.Empty(t, h.chat.sentTo(testChannel))
+}
+
+func TestGitHubWebhook_RemovesDeletedDocuments(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	path := documentedInProject(t, h)
+
+	push(t, h.webhook(t), "alice@example.com", "removed", path)
+
+	_, err := h.index.FindByPath(ctx, path)
+	assert.Error(t, err)
+	notes := h.chat.sentTo(testChannel)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "🗑️ alice deleted "+path+", it is no longer found by search and answers.", notes[0])
+}
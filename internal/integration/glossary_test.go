@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessMessage_MaintainsGlossary(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.responses[operationDocument] = "# Billing SLA\n\nThe billing SLA is 99.9% uptime."
+			model.responses[operationDefine] = "Service level agreement, the uptime promised to customers."
+			h := newHarness(t, p.new(model, t))
+			ctx := context.Background()
+
+			first := h.post(t, "We decided on a Service Level Agreement (SLA) of 99.9% for billing, FYI")
+			require.NoError(t, h.bot.ProcessMessage(ctx, first))
+
+			docs := documents(h.github)
+			require.Len(t, docs, 1)
+			content, ok := h.github.file(docs[0])
+			require.True(t, ok)
+			assert.Contains(t, content, "The billing [SLA](../GLOSSARY.md#sla) is 99.9% uptime.")
+
+			glossary, ok := h.github.file(domain.GlossaryFile)
+			require.True(t, ok)
+			assert.Contains(t, glossary, "## SLA\n\nService level agreement, the uptime promised to customers.\n")
+			assert.NotContains(t, glossary, "FYI")
+			assert.Equal(t, 1, model.callCount(operationDefine))
+
+			model.responses[operationTitle] = "Raise the billing SLA"
+			second := h.post(t, "We decided to raise the SLA of billing to 99.95%")
+			require.NoError(t, h.bot.ProcessMessage(ctx, second))
+
+			assert.Equal(t, 1, model.callCount(operationDefine), "known terms are not defined again")
+			glossary, _ = h.github.file(domain.GlossaryFile)
+			entry, ok := domain.ParseGlossary(glossary).Entry("SLA")
+			require.True(t, ok)
+			assert.ElementsMatch(t, documents(h.github), entry.Documents())
+		})
+	}
+}
+
+func TestProcessMessage_KeepsUndefinedTermsOut(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.failNext(operationDefine, 1)
+	h := newHarness(t, model.ollamaProvider(t))
+
+	msg := h.post(t, "We decided the XYZ and QRS teams own billing")
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	assert.Equal(t, 2, model.callCount(operationDefine))
+	_, ok := h.github.file(domain.GlossaryFile)
+	assert.False(t, ok, "neither term could be defined")
+	assert.Len(t, documents(h.github), 1)
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrounding_FlagsUnsupportedDocumentForReview(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.responses[operationDocument] = "# Adopt Postgres\n\nThe team will use Postgres for billing. " +
+				"The migration takes 6 weeks. Kubernetes operators manage the replicated clusters."
+			h := newHarness(t, p.new(model, t))
+			ctx := context.Background()
+			msg := h.post(t, "We decided to use Postgres for billing")
+
+			require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+			docs := documents(h.github)
+			require.Len(t, docs, 1)
+			fm := frontMatterOf(t, h, docs[0])
+			assert.Equal(t, domain.DocumentStatusNeedsReview, domain.DocumentStatusOf(fm))
+			assert.Equal(t, "0.33", fm.Get("grounding"))
+			assert.Equal(t, []string{"The migration takes 6 weeks", "Kubernetes operators manage the replicated clusters"}, fm.GetList("unsupported_claims"))
+			assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+
+			replies := h.chat.repliesTo(msg.ID().String())
+			require.Len(t, replies, 2)
+			assert.True(t, strings.HasPrefix(replies[0], "🔍 The document `"+docs[0]+"`"))
+			assert.Contains(t, replies[0], "• The migration takes 6 weeks")
+			assert.True(t, strings.HasPrefix(replies[1], "✅ Recorded decision"))
+
+			review, err := domain.NewDocumentReview(docs[0], domain.ReviewReconfirm, "U0002")
+			require.NoError(t, err)
+			require.NoError(t, h.reviews.Review(ctx, review))
+			assert.Equal(t, domain.DocumentStatusActive, domain.DocumentStatusOf(frontMatterOf(t, h, docs[0])))
+		})
+	}
+}
+
+func TestGrounding_CommitsSupportedDocument(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	msg := h.post(t, "We decided the team will use Postgres for billing")
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	docs := documents(h.github)
+	require.Len(t, docs, 1)
+	fm := frontMatterOf(t, h, docs[0])
+	assert.Equal(t, domain.DocumentStatusActive, domain.DocumentStatusOf(fm))
+	assert.False(t, fm.Has("grounding"))
+	assert.Len(t, h.chat.repliesTo(msg.ID().String()), 1)
+}
+
+func TestGrounding_ProjectMinimum(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.responses[operationDocument] = "# Adopt Postgres\n\nThe team will use Postgres for billing. " +
+		"Billing keeps invoices in Postgres. The migration takes 6 weeks."
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	docConfig := domain.DefaultDocumentationConfig()
+	docConfig.MinGrounding = 0.9
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, docConfig)
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+
+	msg := h.post(t, "We decided the team will use Postgres for billing invoices")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	var flagged []string
+	for _, path := range h.github.paths() {
+		if strings.HasSuffix(path, "adopt-postgres-for-billing.md") {
+			flagged = append(flagged, path)
+		}
+	}
+	require.Len(t, flagged, 1)
+	fm := frontMatterOf(t, h, flagged[0])
+	// Two of the three claims are supported, enough by default but not for the project
+	assert.Equal(t, "0.67", fm.Get("grounding"))
+	assert.Equal(t, domain.DocumentStatusNeedsReview, domain.DocumentStatusOf(fm))
+}
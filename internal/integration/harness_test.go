@@ -0,0 +1,262 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannel = "C0001"
+
+// dashboardURL is where the harness serves documents on the dashboard
+const dashboardURL = "https://quill.example.com/documents/"
+
+// testProvenanceKey signs the documents the harness generates
+const testProvenanceKey = "provenance-key-of-the-test-harness"
+
+// testModerationPolicy is the moderation policy of the projects turning moderation on
+var testModerationPolicy = domain.ModerationPolicy{
+	Blocked:  []string{"idiots"},
+	OffTopic: []string{"happy birthday"},
+	Review:   []string{"Acme Corp"},
+}
+
+// harness wires the bot services the way a deployment does, around fake backends
+type harness struct {
+	github        *fakeGitHub
+	chat          *fakeChat
+	calendar      *fakeCalendar
+	vision        *fakeVision
+	flags         *fakeFlagSource
+	bot           *services.BotService
+	projects      *services.ProjectService
+	gaps          *services.KnowledgeGapService
+	reviews       *services.DocumentReviewService
+	erasure       *services.ErasureService
+	reprocess     *services.ReprocessService
+	reconciler    *services.ReconciliationService
+	previews      *services.LinkPreviewService
+	feeds         *services.FeedService
+	home          *services.HomeService
+	triage        *services.TriageService
+	notifications *services.NotificationService
+	outbox        *memory.ReplyOutbox
+	duplicates    *memory.PendingDuplicateStore
+	updates       *memory.PendingUpdateStore
+	approvals     *memory.ApprovalRequestStore
+	deadLetters   *memory.DeadLetterQueue
+	profiles      *memory.UserProfileCache
+	triageQueue   *memory.TriageQueue
+	standupStore  *memory.StandupStore
+	snoozes       *services.SnoozeService
+	threads       *services.ThreadService
+	incidents     *services.IncidentService
+	notes         *services.MeetingNotesService
+	standups      *services.StandupService
+	okrs          *services.OKRService
+	risks         *services.RiskReminderService
+	threadRepo    *memory.ThreadRepository
+	graph         *services.ReferenceGraphService
+	audit         *memory.AuditLog
+	corrections   *memory.CorrectionStore
+	messages      *memory.MessageRepository
+	index         *memory.DocumentIndex
+	provenance    *domain.ProvenanceSigner
+	moderation    *memory.ModerationQueue
+	events        *services.EventBus
+}
+
+func newHarness(t testing.TB, ai ports.AiAgentProvider) *harness {
+	t.Helper()
+	return newHarnessWithTimeouts(t, ai, services.StageTimeouts{})
+}
+
+// newHarnessWithTimeouts wires the bot services with the stage timeouts
+func newHarnessWithTimeouts(t testing.TB, ai ports.AiAgentProvider, timeouts services.StageTimeouts) *harness {
+	t.Helper()
+	return wireHarness(t, ai, timeouts, nil)
+}
+
+// newReplica wires the bot services of one replica of a deployment, processing the channels the coordinator owns
+func newReplica(t testing.TB, ai ports.AiAgentProvider, coordinator ports.WorkCoordinator) *harness {
+	t.Helper()
+	return wireHarness(t, ai, services.StageTimeouts{}, coordinator)
+}
+
+func wireHarness(t testing.TB, ai ports.AiAgentProvider, timeouts services.StageTimeouts, coordinator ports.WorkCoordinator) *harness {
+	t.Helper()
+
+	gh := newFakeGitHub()
+	chat := newFakeChat()
+	calendar := &fakeCalendar{}
+	vision := &fakeVision{descriptions: make(map[string]string)}
+	messages := memory.NewMessageRepository()
+	projectRepo := memory.NewProjectRepository()
+	index := memory.NewDocumentIndex()
+	audit := memory.NewAuditLog()
+	corrections := memory.NewCorrectionStore()
+	flagSource := &fakeFlagSource{}
+	// Flags are fetched for every check, so tests can change them between messages
+	flags := services.NewFeatureFlagService(nil, flagSource, time.Nanosecond)
+	provenance, err := domain.NewProvenanceSigner([]byte(testProvenanceKey))
+	require.NoError(t, err)
+
+	stores := services.NewDocStoreResolver(gh.store(t), nil)
+	projects := services.NewProjectService(stores, projectRepo)
+	var glossary *services.GlossaryService
+	if definer, ok := ai.(ports.TermDefiner); ok {
+		glossary = services.NewGlossaryService(definer)
+	}
+	detector, _ := ai.(ports.RiskDetector)
+	graph := services.NewReferenceGraphService()
+	threadRepo := memory.NewThreadRepository()
+	threads := services.NewThreadService(threadRepo, ai)
+	docs := services.NewDocumentationService(stores, projectRepo, ai, graph, index, services.NewMeetingContext(calendar, messages), services.NewImageAnalysis(vision, chat, domain.AssetLimits{}), glossary, provenance, threads, domain.DefaultDeploymentPatterns(), services.NewRiskRegisterService(detector), chat)
+	commands := services.NewCommandService(chat)
+	services.RegisterProjectCommands(commands, projects)
+	services.RegisterRelationCommands(commands, docs)
+	services.RegisterSharingCommands(commands, docs, services.NewSharedIndex(index, projectRepo))
+	services.RegisterKnowledgeCommands(commands, services.NewKnowledgeService(docs, index, messages, ai), docs, flags)
+	services.RegisterFeatureFlagCommands(commands, flags, docs)
+	services.RegisterStatsCommands(commands, services.NewStatsService(messages, index))
+	services.RegisterTagCommands(commands, docs)
+	events := services.NewEventBus()
+	tracker := services.NewMessageTracker(messages, 0, events)
+	moderationQueue := memory.NewModerationQueue()
+	moderator, _ := ai.(ports.ContentModerator)
+	moderation := services.NewModerationService(moderator, testModerationPolicy, moderationQueue, audit)
+	outbox := memory.NewReplyOutbox()
+	duplicates := memory.NewPendingDuplicateStore()
+	updates := memory.NewPendingUpdateStore()
+	approvals := memory.NewApprovalRequestStore()
+	notifications := services.NewNotificationService(chat, projects, outbox, timeouts)
+	triageQueue := memory.NewTriageQueue()
+	triage := services.NewTriageService(triageQueue, chat, coordinator, threads, notifications)
+	snoozes := services.NewSnoozeService(memory.NewSnoozeStore(), messages)
+	services.RegisterSnoozeCommands(commands, snoozes)
+	services.RegisterOptOut(chat, snoozes)
+	writer, _ := ai.(ports.PostmortemWriter)
+	incidents := services.NewIncidentService(memory.NewIncidentStore(), messages, docs, tracker, writer)
+	services.RegisterIncidentCommands(commands, incidents)
+	secretary, _ := ai.(ports.MeetingNotesWriter)
+	notes := services.NewMeetingNotesService(memory.NewNotesSessionStore(), messages, docs, tracker, secretary)
+	services.RegisterMeetingNotesCommands(commands, notes)
+	okrs := services.NewOKRService(memory.NewKeyResultUpdateStore(), docs)
+	services.RegisterOKRCommands(commands, okrs)
+	services.RegisterRiskCommands(commands, docs)
+	standupStore := memory.NewStandupStore()
+	standups := services.NewStandupService(standupStore, projectRepo, chat, messages, docs, tracker, coordinator)
+
+	bot := services.NewBotService(
+		chat,
+		stores.Default(),
+		ai,
+		projects,
+		docs,
+		services.NewReferenceResolver(index, messages, ai, flags),
+		services.NewDuplicateDetector(index, ai),
+		commands,
+		tracker,
+		nil,
+		timeouts,
+		coordinator,
+		moderation,
+		triage,
+		snoozes,
+		threads,
+		incidents,
+		notes,
+		standups,
+		okrs,
+		services.NewAuthorizationService(docs, audit, flags, approvals),
+		notifications,
+		duplicates,
+		updates,
+	)
+	services.RegisterModerationCommands(commands, moderation, bot)
+	deadLetters := memory.NewDeadLetterQueue()
+	profiles := memory.NewUserProfileCache(0)
+	erasure := services.NewErasureService(messages, corrections, audit, docs, index)
+	services.RegisterIncidentErasure(erasure)
+	services.RegisterMeetingNotesErasure(erasure)
+	services.RegisterDeadLetterErasure(erasure, deadLetters)
+	services.RegisterThreadErasure(erasure, threadRepo)
+	services.RegisterUserProfileErasure(erasure, profiles)
+	services.RegisterTriageErasure(erasure, triageQueue)
+	services.RegisterStandupErasure(erasure, standupStore)
+	services.RegisterPendingDuplicateErasure(erasure, duplicates)
+	services.RegisterPendingUpdateErasure(erasure, updates)
+	services.RegisterApprovalRequestErasure(erasure, approvals)
+	reviews := services.NewDocumentReviewService(docs, index, messages, projectRepo, chat, audit, coordinator)
+	services.RegisterOwnershipCommands(commands, reviews)
+
+	return &harness{
+		github:        gh,
+		chat:          chat,
+		calendar:      calendar,
+		vision:        vision,
+		flags:         flagSource,
+		bot:           bot,
+		projects:      projects,
+		gaps:          services.NewKnowledgeGapService(projectRepo, index, stores, chat, coordinator, 0),
+		reviews:       reviews,
+		erasure:       erasure,
+		reprocess:     services.NewReprocessService(messages, bot, docs, audit),
+		reconciler:    services.NewReconciliationService(stores, index, graph, projectRepo, ai, chat),
+		previews:      services.NewLinkPreviewService(docs, index, projectRepo, dashboardURL),
+		feeds:         services.NewFeedService(index, projectRepo),
+		triage:        triage,
+		notifications: notifications,
+		outbox:        outbox,
+		duplicates:    duplicates,
+		updates:       updates,
+		approvals:     approvals,
+		deadLetters:   deadLetters,
+		profiles:      profiles,
+		triageQueue:   triageQueue,
+		standupStore:  standupStore,
+		snoozes:       snoozes,
+		threads:       threads,
+		incidents:     incidents,
+		notes:         notes,
+		standups:      standups,
+		okrs:          okrs,
+		risks:         services.NewRiskReminderService(docs, projectRepo, chat, coordinator),
+		threadRepo:    threadRepo,
+		home:          services.NewHomeService(messages, projectRepo, index, graph, docs, bot),
+		graph:         graph,
+		audit:         audit,
+		corrections:   corrections,
+		messages:      messages,
+		index:         index,
+		provenance:    provenance,
+		moderation:    moderationQueue,
+		events:        events,
+	}
+}
+
+// post builds a message posted in the test channel, as the chat provider would deliver it
+func (h *harness) post(t testing.TB, text string) *domain.Message {
+	t.Helper()
+
+	msg, err := domain.NewMessage(common.GenerateID(), "alice", domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID(testChannel)
+	return msg
+}
+
+// stored returns the message as the pipeline persisted it
+func (h *harness) stored(t *testing.T, msg *domain.Message) *domain.Message {
+	t.Helper()
+
+	stored, err := h.messages.FindByID(context.Background(), msg.ID().String())
+	require.NoError(t, err)
+	return stored
+}
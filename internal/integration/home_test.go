@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHome_ShowsCapturesApprovalsAndProjects(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	moderatedProject(t, h)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move billing to Postgres")))
+	held := h.post(t, "We decided to use Postgres for the Acme Corp billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, held))
+	viewer, err := domain.NewHomeViewer("alice", []string{testChannel})
+	require.NoError(t, err)
+
+	home, err := h.home.Home(ctx, viewer)
+
+	require.NoError(t, err)
+	require.Len(t, home.Captures, 1)
+	assert.Equal(t, "Adopt Postgres", home.Captures[0].Title)
+	assert.Contains(t, home.Captures[0].Link, documents(h.github)[0])
+	assert.Equal(t, domain.MessageTypeDecision, home.Captures[0].Type)
+	require.Len(t, home.Approvals, 1)
+	assert.Equal(t, domain.ApprovalModeration, home.Approvals[0].Kind)
+	assert.Equal(t, held.ID().String(), home.Approvals[0].MessageID)
+	assert.Equal(t, testChannel, home.Approvals[0].ChannelID)
+	assert.Contains(t, home.Approvals[0].Reason, `contains "Acme Corp"`)
+	require.Len(t, home.Projects, 1)
+	assert.Equal(t, "Billing", home.Projects[0].Name)
+	assert.Equal(t, []string{testChannel}, home.Projects[0].Channels)
+}
+
+func TestHome_OnlyShowsWhatTheViewerCanSee(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	moderatedProject(t, h)
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for the Acme Corp billing")))
+	viewer, err := domain.NewHomeViewer("bob", []string{otherChannel})
+	require.NoError(t, err)
+
+	home, err := h.home.Home(ctx, viewer)
+
+	require.NoError(t, err)
+	assert.Empty(t, home.Captures)
+	assert.Empty(t, home.Approvals)
+	assert.Empty(t, home.Projects)
+}
+
+func TestHome_ApprovesHeldMessage(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	moderatedProject(t, h)
+	held := h.post(t, "We decided to use Postgres for the Acme Corp billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, held))
+
+	require.NoError(t, h.home.Decide(ctx, held.ID().String(), "bob", true))
+
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, held).State())
+	viewer, err := domain.NewHomeViewer("alice", []string{testChannel})
+	require.NoError(t, err)
+	home, err := h.home.Home(ctx, viewer)
+	require.NoError(t, err)
+	assert.Empty(t, home.Approvals)
+	assert.Len(t, home.Captures, 1)
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redeliver builds a new delivery of a message, as the chat provider makes when the chat retries an event
+func redeliver(t *testing.T, msg *domain.Message) *domain.Message {
+	t.Helper()
+
+	again, err := domain.NewMessage(msg.ThreadID(), msg.Sender(), msg.Content(), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	again.SetChannelID(msg.ChannelID())
+	again.SetSourceTimestamp(msg.SourceTimestamp())
+	return again
+}
+
+func TestIdempotency_RedeliveredMessagesKeepTheirDocument(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	msg := h.post(t, "We decided to move billing to Postgres")
+	msg.SetSourceTimestamp("1718000000.000100")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	require.Len(t, documents(h.github), 1)
+	path := documents(h.github)[0]
+
+	content, ok := h.github.file(path)
+	require.True(t, ok)
+	fm, _, err := domain.ParseFrontMatter(content)
+	require.NoError(t, err)
+	key := domain.MessageIdempotencyKey(h.stored(t, msg))
+	assert.Equal(t, key, fm.Get("idempotency_key"))
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, redeliver(t, msg)))
+	assert.Equal(t, []string{path}, documents(h.github), "a redelivered message is not documented again")
+
+	// After a restart the index is rebuilt from the front matter of the stored documents
+	require.NoError(t, h.index.Remove(ctx, path))
+	_, err = h.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	entry, err := h.index.FindByIdempotencyKey(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, path, entry.Path())
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, redeliver(t, msg)))
+	assert.Equal(t, []string{path}, documents(h.github))
+}
+
+func TestIdempotency_OtherMessagesOfTheThreadAreDocumented(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	first := h.post(t, "We decided to move billing to Postgres")
+	first.SetSourceTimestamp("1718000000.000100")
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+	reply := h.reply(t, first, "We decided to keep nightly backups of the billing database")
+	reply.SetSourceTimestamp("1718000000.000200")
+	require.NoError(t, h.bot.ProcessMessage(ctx, reply))
+
+	assert.Len(t, documents(h.github), 2)
+}
+
+func TestIdempotency_RedeliveredStatusUpdatesAreRolledUpOnce(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	billingProject(t, h)
+	ctx := context.Background()
+
+	msg := h.post(t, "Invoice export is done")
+	msg.SetSourceTimestamp("1718000000.000100")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	again := redeliver(t, msg)
+	again.SetTimestamp(msg.Timestamp())
+	require.NoError(t, h.bot.ProcessMessage(ctx, again))
+
+	content, ok := h.github.file(domain.StatusRollupPath(domain.CategoryDevelopment, msg.Timestamp()))
+	require.True(t, ok, "rollup not found in %v", h.github.paths())
+	assert.Equal(t, 1, strings.Count(content, "- Idempotency key: "), "a redelivered update is not appended again:\n%s", content)
+
+	reply := h.reply(t, msg, "Invoice import is done too")
+	reply.SetSourceTimestamp("1718000000.000200")
+	reply.SetTimestamp(msg.Timestamp())
+	require.NoError(t, h.bot.ProcessMessage(ctx, reply))
+	content, _ = h.github.file(domain.StatusRollupPath(domain.CategoryDevelopment, msg.Timestamp()))
+	assert.Equal(t, 2, strings.Count(content, "- Idempotency key: "), "other updates of the thread are appended:\n%s", content)
+}
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lastReply returns the last reply the bot posted to a message
+func lastReply(t *testing.T, h *harness, msg *domain.Message) string {
+	t.Helper()
+
+	replies := h.chat.repliesTo(msg.ID().String())
+	require.NotEmpty(t, replies)
+	return replies[len(replies)-1]
+}
+
+func TestIncident_CapturesTheThreadAndDraftsThePostmortem(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	start := h.post(t, "/quill incident start Payments API down")
+	require.NoError(t, h.bot.ProcessMessage(ctx, start))
+	assert.Contains(t, lastReply(t, h, start), "🚨 Incident *Payments API down* declared")
+
+	// Messages of the incident are captured without being analyzed, whatever they say
+	alert := h.reply(t, start, "Error rate above 5% since the 14:00 deploy")
+	require.NoError(t, h.bot.ProcessMessage(ctx, alert))
+	fix := h.reply(t, start, "Rolled back to v1.4.1")
+	require.NoError(t, h.bot.ProcessMessage(ctx, fix))
+	assert.Equal(t, domain.MessageStateAnalyzing, h.stored(t, alert).State())
+	assert.Zero(t, model.callCount(operationAnalyze))
+	assert.Empty(t, documents(h.github))
+
+	resolve := h.reply(t, start, "/quill incident resolve")
+	require.NoError(t, h.bot.ProcessMessage(ctx, resolve))
+	assert.Contains(t, lastReply(t, h, resolve), "✅ Incident *Payments API down* resolved after")
+
+	path := "docs/incidents/" + time.Now().UTC().Format("2006-01-02") + "-payments-api-down.md"
+	report, ok := h.github.file(path)
+	require.True(t, ok, "report %s not found in %v", path, h.github.paths())
+	assert.Contains(t, report, "# Incident: Payments API down\n")
+	assert.Contains(t, report, "alice: Declared the incident\n")
+	assert.Contains(t, report, "bob: Error rate above 5% since the 14:00 deploy\n")
+	assert.Contains(t, report, "bob: Rolled back to v1.4.1\n")
+	assert.Contains(t, report, "bob: Resolved the incident\n")
+	assert.Contains(t, report, "## Postmortem draft\n\n### Summary\n\nThe payments API failed after a deploy and was rolled back.\n")
+	assert.Equal(t, 1, model.callCount(operationPostmortem))
+
+	fm := frontMatterOf(t, h, path)
+	assert.Equal(t, "alice", fm.Get("incident_commander"))
+	assert.Equal(t, "bob", fm.Get("resolved_by"))
+	assert.Equal(t, []string{"incident", "postmortem"}, fm.GetList("tags"))
+	indexed, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "Incident: Payments API down", indexed.Title())
+
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, alert).State())
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, fix).State())
+
+	// Once resolved, the thread is analyzed like any other
+	after := h.reply(t, start, "We decided to gate deploys on the error rate")
+	require.NoError(t, h.bot.ProcessMessage(ctx, after))
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, after).State())
+	assert.Equal(t, 1, model.callCount(operationAnalyze))
+}
+
+func TestIncident_ReportWithoutADraftHasThePostmortemSections(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+	model.failNext(operationPostmortem, 10)
+
+	start := h.post(t, "/quill incident start")
+	require.NoError(t, h.bot.ProcessMessage(ctx, start))
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.reply(t, start, "Checkout is timing out")))
+	resolve := h.reply(t, start, "/quill incident resolve")
+	require.NoError(t, h.bot.ProcessMessage(ctx, resolve))
+	assert.Contains(t, lastReply(t, h, resolve), "✅ Incident *Incident of ")
+
+	var reports []string
+	for _, path := range h.github.paths() {
+		if strings.HasPrefix(path, "docs/incidents/") {
+			reports = append(reports, path)
+		}
+	}
+	require.Len(t, reports, 1)
+	report, _ := h.github.file(reports[0])
+	assert.Contains(t, report, "bob: Checkout is timing out\n")
+	assert.Contains(t, report, "### Root cause\n")
+	assert.Contains(t, report, "### Action items\n")
+}
+
+func TestIncident_CommandsNeedAnOpenIncident(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	resolve := h.post(t, "/quill incident resolve")
+	require.NoError(t, h.bot.ProcessMessage(ctx, resolve))
+	assert.Equal(t, "⚠️ no incident is open in this thread, `/quill incident start` declares one", lastReply(t, h, resolve))
+
+	start := h.post(t, "/quill incident start Checkout down")
+	require.NoError(t, h.bot.ProcessMessage(ctx, start))
+	again := h.reply(t, start, "/quill incident start")
+	require.NoError(t, h.bot.ProcessMessage(ctx, again))
+	assert.Equal(t, "⚠️ incident \"Checkout down\" is already open in this thread", lastReply(t, h, again))
+
+	usage := h.reply(t, start, "/quill incident")
+	require.NoError(t, h.bot.ProcessMessage(ctx, usage))
+	assert.Contains(t, lastReply(t, h, usage), "usage: `/quill incident start [<title>]`")
+}
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnowledgeGaps_ReportedToProjectChannel(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Move billing to Postgres"},
+		KPIs:          []string{"Invoice error rate", "Payment latency"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, project.AddMilestone("Invoicing launch", now.Add(60*24*time.Hour)))
+	require.NoError(t, h.projects.UpdateProject(ctx, project))
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+
+	model.mu.Lock()
+	model.analysis.Type = domain.MessageTypeStatus
+	model.responses[operationDocument] = "# Invoices\n\nThe invoice error rate dropped to 0.5%."
+	model.mu.Unlock()
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "The invoice error rate dropped to 0.5% this week")))
+
+	// Report when the status update is about to go stale, the project created before it is old enough
+	// for its KPIs to be checked by then
+	var statusUpdated time.Time
+	indexed, err := h.index.List(ctx)
+	require.NoError(t, err)
+	for _, doc := range indexed {
+		if doc.Type().IsStatus() {
+			assert.Equal(t, project.ID(), doc.Project())
+			statusUpdated = doc.UpdatedAt()
+		}
+	}
+	require.False(t, statusUpdated.IsZero())
+	require.NoError(t, h.gaps.Report(ctx, statusUpdated.Add(domain.DefaultGapStaleness)))
+
+	reports := h.chat.sentTo(testChannel)
+	require.Len(t, reports, 1)
+	assert.Contains(t, reports[0], "Knowledge gaps in Billing:\n")
+	assert.Contains(t, reports[0], "• No decisions recorded for the Invoicing launch milestone, due "+now.Add(60*24*time.Hour).Format("2006-01-02")+"\n")
+	assert.Contains(t, reports[0], "• KPI \"Payment latency\" has no status updates in 3 weeks\n")
+	assert.NotContains(t, reports[0], "Invoice error rate")
+	assert.NotContains(t, reports[0], "Move billing to Postgres")
+}
+
+func TestKnowledgeGaps_SkipsPausedAndCoveredProjects(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	paused, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Paused",
+		BusinessGoals: []string{"Nothing documented"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, paused.ID(), "C0002"))
+	_, err = h.projects.PauseProject(ctx, paused.ID())
+	require.NoError(t, err)
+
+	covered, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Move billing to Postgres"},
+		KPIs:          []string{"Invoice error rate"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, covered.ID(), testChannel))
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+
+	// The project is too new for its KPI to be stale
+	require.NoError(t, h.gaps.Report(ctx, time.Now()))
+	assert.Empty(t, h.chat.sentTo("C0002"))
+	assert.Empty(t, h.chat.sentTo(testChannel))
+}
@@ -0,0 +1,58 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkPreview_FindsLinkedDocuments(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	path := documentedInProject(t, h)
+
+	links := []string{
+		"https://github.com/" + githubOwner + "/" + githubRepo + "/blob/" + githubBranch + "/" + path,
+		"https://github.com/" + githubOwner + "/" + githubRepo + "/blob/" + githubBranch + "/" + path + "#why",
+		dashboardURL + path + "?format=html",
+	}
+	for _, link := range links {
+		doc, err := h.previews.Find(ctx, testChannel, link)
+		require.NoError(t, err, link)
+		assert.Equal(t, path, doc.Path())
+		assert.Equal(t, "Adopt Postgres", doc.Title())
+		assert.Equal(t, domain.MessageTypeDecision, doc.Type())
+	}
+}
+
+func TestLinkPreview_IgnoresOtherLinks(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	path := documentedInProject(t, h)
+
+	links := []string{
+		"https://github.com/" + githubOwner + "/" + githubRepo + "/pulls",
+		"https://github.com/" + githubOwner + "/handbook/blob/" + githubBranch + "/" + path,
+		dashboardURL + "docs/missing.md",
+	}
+	for _, link := range links {
+		_, err := h.previews.Find(ctx, testChannel, link)
+		assert.ErrorIs(t, err, ports.ErrNotFound, link)
+	}
+}
+
+func TestLinkPreview_HidesInternalDocumentsFromOtherChannels(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	path := documentedInProject(t, h)
+
+	_, err := h.previews.Find(context.Background(), "C0999", dashboardURL+path)
+
+	assert.ErrorIs(t, err, ports.ErrNotFound, "the project's documents are internal by default")
+}
@@ -0,0 +1,230 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/providers/llm"
+	"github.com/massimo-ua/quill/internal/providers/llm/ollama"
+	"github.com/massimo-ua/quill/internal/providers/llm/openai"
+	"github.com/stretchr/testify/require"
+)
+
+// Operations the fake model tells apart by the opening of their system prompt
+const (
+	operationAnalyze    = "You are a message analyzer"
+	operationDocument   = "You are a documentation writer"
+	operationReferences = "You are a reference detector"
+	operationTitle      = "You are a documentation editor"
+	operationCategorize = "You are a content categorizer"
+	operationDefine     = "You are a glossary editor"
+	operationAnswer     = "You are a knowledge base assistant"
+	operationPostmortem = "You are an incident reviewer"
+	operationNotes      = "You are a meeting secretary"
+	operationRisks      = "You are a risk analyst"
+)
+
+// fakeModel answers chat completions like a model would, from scripted responses per operation
+type fakeModel struct {
+	mu        sync.Mutex
+	analysis  domain.Analysis
+	responses map[string]string
+	// drafts are answered before the responses, once each
+	drafts   map[string][]string
+	failures map[string]int
+	stalls   map[string]bool
+	calls    []string
+	// moderation rates the content the OpenAI moderation endpoint is asked about
+	moderation domain.ModerationResult
+}
+
+func newFakeModel(messageType domain.MessageType, category domain.Category) *fakeModel {
+	return &fakeModel{
+		analysis: domain.Analysis{Type: messageType, Category: category, ConfidenceScore: 0.9, SuggestedTags: []string{"postgres"}},
+		responses: map[string]string{
+			operationDocument:   "# Adopt Postgres\n\nThe team will use Postgres for billing.",
+			operationReferences: "[]",
+			operationTitle:      "Adopt Postgres for billing",
+			operationCategorize: string(category),
+			operationDefine:     "UNKNOWN",
+			operationAnswer:     "The team uses Postgres [1].",
+			operationPostmortem: "### Summary\n\nThe payments API failed after a deploy and was rolled back.",
+			operationNotes: `{"agenda": ["Late invoices"], "decisions": [{"title": "Render invoices in a queue", "context": "Invoices are late", ` +
+				`"decision": "Move PDF rendering to a worker queue", "category": "development"}], ` +
+				`"action_items": [{"task": "Set up the queue", "owner": "carol", "due": "Friday"}]}`,
+			operationRisks: `{"risks": []}`,
+		},
+		drafts:   make(map[string][]string),
+		failures: make(map[string]int),
+		stalls:   make(map[string]bool),
+	}
+}
+
+// draft makes the next requests of an operation answer the contents, before the scripted response
+func (m *fakeModel) draft(operation string, contents ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drafts[operation] = append(m.drafts[operation], contents...)
+}
+
+// failNext makes the next requests of an operation fail with a server error
+func (m *fakeModel) failNext(operation string, times int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[operation] += times
+}
+
+// stall makes requests of an operation hang until the client gives up
+func (m *fakeModel) stall(operation string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stalls[operation] = true
+}
+
+// respond returns the completion of a conversation, false when the request should fail.
+// Stalled operations only return once the request was canceled.
+func (m *fakeModel) respond(ctx context.Context, system string) (string, bool) {
+	content, ok, stalled := m.script(system)
+	if stalled {
+		<-ctx.Done()
+		return "", false
+	}
+	return content, ok
+}
+
+// script looks up the scripted outcome of a conversation and records the call
+func (m *fakeModel) script(system string) (content string, ok bool, stalled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, operation := range []string{operationAnalyze, operationDocument, operationReferences, operationTitle, operationCategorize, operationDefine, operationAnswer, operationPostmortem, operationNotes, operationRisks} {
+		if !strings.HasPrefix(system, operation) {
+			continue
+		}
+		m.calls = append(m.calls, operation)
+		if m.stalls[operation] {
+			return "", false, true
+		}
+		if m.failures[operation] > 0 {
+			m.failures[operation]--
+			return "", false, false
+		}
+		if operation == operationAnalyze {
+			data, _ := json.Marshal(m.analysis)
+			return string(data), true, false
+		}
+		if drafts := m.drafts[operation]; len(drafts) > 0 {
+			m.drafts[operation] = drafts[1:]
+			return drafts[0], true, false
+		}
+		return m.responses[operation], true, false
+	}
+	return "", false, false
+}
+
+// callCount returns how often an operation was requested
+func (m *fakeModel) callCount(operation string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, call := range m.calls {
+		if call == operation {
+			count++
+		}
+	}
+	return count
+}
+
+// embed returns a bag-of-words vector, so texts sharing words are similar
+func embed(text string) []float64 {
+	vector := make([]float64, 32)
+	for _, token := range domain.Tokenize(text) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(token))
+		vector[h.Sum32()%uint32(len(vector))]++
+	}
+	return vector
+}
+
+// ollamaProvider creates an Ollama provider talking to a server that runs the model
+func (m *fakeModel) ollamaProvider(t testing.TB) ports.AiAgentProvider {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat", func(w http.ResponseWriter, r *http.Request) {
+		var request ollama.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Messages) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+			return
+		}
+		content, ok := m.respond(r.Context(), request.Messages[0].Content)
+		if !ok {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "model crashed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, ollama.ChatResponse{Model: request.Model, Message: ollama.Message{Role: "assistant", Content: content}, Done: true})
+	})
+	mux.HandleFunc("/api/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var request ollama.EmbeddingRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		writeJSON(w, http.StatusOK, ollama.EmbeddingResponse{Embedding: embed(request.Prompt)})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider, err := llm.NewLLMProvider(context.Background(), &llm.Config{Type: llm.ProviderTypeOllama, Ollama: ollama.NewDefaultConfig(server.URL, "llama3")})
+	require.NoError(t, err)
+	return provider
+}
+
+// openAIProvider creates an OpenAI provider talking to a server that runs the model
+func (m *fakeModel) openAIProvider(t testing.TB) ports.AiAgentProvider {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Messages) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]string{"message": "invalid request"}})
+			return
+		}
+		content, ok := m.respond(r.Context(), request.Messages[0].Content)
+		if !ok {
+			writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": map[string]string{"message": "model crashed"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": openai.Message{Role: "assistant", Content: content}, "finish_reason": "stop"}},
+		})
+	})
+	mux.HandleFunc("/moderations", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"results": []map[string]interface{}{{"flagged": m.moderation.Flagged, "category_scores": m.moderation.Scores}},
+		})
+	})
+	mux.HandleFunc("/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.EmbeddingRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": []map[string]interface{}{{"index": 0, "embedding": embed(request.Input)}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	cfg := openai.NewDefaultConfig("sk-test", "gpt-4")
+	cfg.BaseURL = server.URL
+	provider, err := llm.NewLLMProvider(context.Background(), &llm.Config{Type: llm.ProviderTypeOpenAI, OpenAI: cfg})
+	require.NoError(t, err)
+	return provider
+}
@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// localOnlyProject binds a project to the test channel and turns its local-only policy on
+func localOnlyProject(t *testing.T, h *harness) *domain.Project {
+	t.Helper()
+	ctx := context.Background()
+
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+
+	project, err = h.projects.GetProject(ctx, project.ID())
+	require.NoError(t, err)
+	config := project.Documentation()
+	config.LocalOnly = true
+	require.NoError(t, project.ConfigureDocumentation(config))
+	// The setting is kept, but the project README is not written to the cloud repository any more
+	assert.ErrorIs(t, h.projects.UpdateProject(ctx, project), domain.ErrLocalOnly)
+	return project
+}
+
+func TestLocalOnly_RefusesProjectsStoredInTheCloud(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+
+	_, err := h.projects.CreateConfiguredProject(context.Background(), &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, domain.DocumentationConfig{LocalOnly: true})
+
+	assert.ErrorIs(t, err, domain.ErrLocalOnly)
+	assert.Empty(t, h.github.commitMessages())
+}
+
+func TestLocalOnly_KeepsContentFromCloudStores(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	localOnlyProject(t, h)
+	commits := len(h.github.commitMessages())
+
+	msg := h.post(t, "We decided to use Postgres for billing")
+	err := h.bot.ProcessMessage(context.Background(), msg)
+
+	require.ErrorIs(t, err, domain.ErrLocalOnly)
+	// Ollama is local, so the message is analysed but not documented
+	assert.Equal(t, 1, model.callCount(operationAnalyze))
+	assert.Zero(t, model.callCount(operationDocument))
+	assert.Len(t, h.github.commitMessages(), commits)
+	stored := h.stored(t, msg)
+	assert.Equal(t, domain.MessageStateFailed, stored.State())
+	assert.Contains(t, stored.StateReason(), "storage of "+testChannel+" uses a cloud provider")
+}
+
+func TestLocalOnly_KeepsContentFromCloudModels(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.openAIProvider(t))
+	localOnlyProject(t, h)
+
+	msg := h.post(t, "We decided to use Postgres for billing")
+	err := h.bot.ProcessMessage(context.Background(), msg)
+
+	require.ErrorIs(t, err, domain.ErrLocalOnly)
+	assert.Zero(t, model.callCount(operationAnalyze))
+	stored := h.stored(t, msg)
+	assert.Equal(t, domain.MessageStateFailed, stored.State())
+	assert.Contains(t, stored.StateReason(), "analysis of "+testChannel+" uses a cloud provider")
+}
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeetingNotes_CollectsTheThreadAndDocumentsItsDecisions(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	start := h.post(t, "/quill notes Billing sync")
+	require.NoError(t, h.bot.ProcessMessage(ctx, start))
+	assert.Contains(t, lastReply(t, h, start), "📝 Taking notes of *Billing sync*")
+
+	// Messages of the meeting are collected without being analyzed
+	late := h.reply(t, start, "Invoices are late because of the PDF renderer")
+	require.NoError(t, h.bot.ProcessMessage(ctx, late))
+	queue := h.reply(t, start, "Agreed, let's move rendering to a queue")
+	require.NoError(t, h.bot.ProcessMessage(ctx, queue))
+	assert.Equal(t, domain.MessageStateAnalyzing, h.stored(t, late).State())
+	assert.Zero(t, model.callCount(operationAnalyze))
+	assert.Empty(t, documents(h.github))
+
+	closing := h.reply(t, start, "/quill notes close")
+	require.NoError(t, h.bot.ProcessMessage(ctx, closing))
+	assert.Contains(t, lastReply(t, h, closing), "✅ Notes of *Billing sync* closed with 2 attendees, 1 decisions and 1 action items.")
+	assert.Equal(t, 1, model.callCount(operationNotes))
+
+	path := "docs/meetings/" + time.Now().UTC().Format("2006-01-02") + "-billing-sync.md"
+	notes, ok := h.github.file(path)
+	require.True(t, ok, "notes %s not found in %v", path, h.github.paths())
+	assert.Contains(t, notes, "# Billing sync\n")
+	assert.Contains(t, notes, "## Attendees\n\n- alice\n- bob\n")
+	assert.Contains(t, notes, "## Agenda\n\n1. Late invoices\n")
+	assert.Contains(t, notes, "## Action items\n\n- [ ] **carol**: Set up the queue (due Friday)\n")
+	assert.NotContains(t, notes, "/quill")
+
+	// The decision is documented as a record of its own, linked from the notes
+	var records []string
+	for _, doc := range documents(h.github) {
+		if strings.HasPrefix(doc, "docs/development/") {
+			records = append(records, doc)
+		}
+	}
+	require.Len(t, records, 1)
+	assert.Contains(t, notes, "- **[Render invoices in a queue](../development/")
+	assert.Contains(t, lastReply(t, h, closing), records[0])
+	record, _ := h.github.file(records[0])
+	assert.Contains(t, record, "# Render invoices in a queue\n\n## Status\n\nAccepted\n\n## Context\n\nInvoices are late\n")
+	fm := frontMatterOf(t, h, records[0])
+	assert.Equal(t, "decision", fm.Get("type"))
+	assert.Equal(t, path, fm.Get("meeting_notes"))
+	assert.Zero(t, model.callCount(operationDocument))
+
+	fm = frontMatterOf(t, h, path)
+	assert.Equal(t, []string{"alice", "bob"}, fm.GetList("attendees"))
+	indexed, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "Billing sync", indexed.Title())
+
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, late).State())
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, queue).State())
+
+	// Once closed, the thread is analyzed like any other
+	after := h.reply(t, start, "We decided to bill annually")
+	require.NoError(t, h.bot.ProcessMessage(ctx, after))
+	assert.Equal(t, 1, model.callCount(operationAnalyze))
+}
+
+func TestMeetingNotes_WithoutASummaryKeepTheDiscussion(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+	model.failNext(operationNotes, 10)
+
+	start := h.post(t, "/quill notes")
+	require.NoError(t, h.bot.ProcessMessage(ctx, start))
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.reply(t, start, "Checkout is slow again")))
+	closing := h.reply(t, start, "/quill notes close")
+	require.NoError(t, h.bot.ProcessMessage(ctx, closing))
+	assert.Contains(t, lastReply(t, h, closing), "0 decisions and 0 action items")
+
+	var notes []string
+	for _, path := range h.github.paths() {
+		if strings.HasPrefix(path, "docs/meetings/") {
+			notes = append(notes, path)
+		}
+	}
+	require.Len(t, notes, 1)
+	content, _ := h.github.file(notes[0])
+	assert.Contains(t, content, "_No decisions recorded._\n")
+	assert.Contains(t, content, "## Discussion\n\n")
+	assert.Contains(t, content, "bob: Checkout is slow again\n")
+}
+
+func TestMeetingNotes_CommandsNeedOpenNotes(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	closing := h.post(t, "/quill notes close")
+	require.NoError(t, h.bot.ProcessMessage(ctx, closing))
+	assert.Equal(t, "⚠️ no notes are taken in this thread, `/quill notes` starts them", lastReply(t, h, closing))
+
+	start := h.post(t, "/quill notes Billing sync")
+	require.NoError(t, h.bot.ProcessMessage(ctx, start))
+	again := h.reply(t, start, "/quill notes")
+	require.NoError(t, h.bot.ProcessMessage(ctx, again))
+	assert.Equal(t, "⚠️ notes of \"Billing sync\" are already taken in this thread", lastReply(t, h, again))
+}
@@ -0,0 +1,45 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageQueue_WorkersDocumentIngestedMessages(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	queue := memory.NewMessageQueue(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ingestion := services.NewQueueIngestion(h.chat, queue)
+	workers := services.NewQueueWorkers(queue, h.bot, 2, nil)
+	ingested := make(chan error, 1)
+	processed := make(chan error, 1)
+	go func() { ingested <- ingestion.Run(ctx) }()
+	go func() { processed <- workers.Run(ctx) }()
+
+	msg := h.post(t, "We decided to use Postgres for billing")
+	h.chat.incoming <- msg
+
+	require.Eventually(t, func() bool {
+		stored, err := h.messages.FindByID(context.Background(), msg.ID().String())
+		return err == nil && stored.State() == domain.MessageStateDocumented
+	}, 5*time.Second, 10*time.Millisecond)
+	docs := documents(h.github)
+	require.Len(t, docs, 1)
+	assert.True(t, strings.HasSuffix(docs[0], "-adopt-postgres-for-billing.md"))
+	assert.Len(t, h.chat.repliesTo(msg.ID().String()), 1)
+
+	cancel()
+	assert.ErrorIs(t, <-ingested, context.Canceled)
+	assert.ErrorIs(t, <-processed, context.Canceled)
+}
@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moderatedProject binds the test channel to a project turning moderation on
+func moderatedProject(t *testing.T, h *harness) {
+	t.Helper()
+
+	ctx := context.Background()
+	docConfig := domain.DefaultDocumentationConfig()
+	docConfig.Moderation = true
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, docConfig)
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+}
+
+func TestModeration_BlocksOffensiveAndOffTopicMessages(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	moderatedProject(t, h)
+
+	offensive := h.post(t, "The idiots in ops decided to use Postgres for billing")
+	offTopic := h.post(t, "Happy birthday Bob, we decided cake is mandatory")
+	require.NoError(t, h.bot.ProcessMessage(ctx, offensive))
+	require.NoError(t, h.bot.ProcessMessage(ctx, offTopic))
+
+	assert.Empty(t, documents(h.github))
+	assert.Equal(t, 0, model.callCount(operationAnalyze))
+	stored := h.stored(t, offensive)
+	assert.Equal(t, domain.MessageStateIgnored, stored.State())
+	assert.Equal(t, `moderation: contains blocked phrase "idiots"`, stored.StateReason())
+	assert.Equal(t, `moderation: off topic, contains "happy birthday"`, h.stored(t, offTopic).StateReason())
+}
+
+func TestModeration_ApprovesHeldMessage(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	moderatedProject(t, h)
+
+	msg := h.post(t, "We decided to use Postgres for the Acme Corp billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	assert.Empty(t, documents(h.github))
+	assert.Equal(t, domain.MessageStatePending, h.stored(t, msg).State())
+	replies := h.chat.repliesTo(msg.ID().String())
+	require.Len(t, replies, 1)
+	assert.True(t, strings.HasPrefix(replies[0], "⏸️ This message waits for moderation"))
+
+	listed := h.command(t, testChannel, "/quill moderation")
+	assert.Contains(t, listed, msg.ID().String())
+	assert.Contains(t, listed, `contains "Acme Corp"`)
+	// Other channels neither see nor decide on the message
+	assert.Equal(t, "No messages are waiting for moderation.", h.command(t, otherChannel, "/quill moderation"))
+	assert.Contains(t, h.command(t, otherChannel, "/quill moderation approve "+msg.ID().String()), "not waiting for moderation")
+
+	approved := h.command(t, testChannel, "/quill moderation approve "+msg.ID().String())
+	assert.Equal(t, "✅ Approved message "+msg.ID().String(), approved)
+	assert.Len(t, documents(h.github), 1)
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+
+	held, err := h.moderation.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, held)
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionModerate, entries[0].Action())
+	assert.Equal(t, msg.ID().String(), entries[0].Subject())
+	assert.Equal(t, "held", entries[0].From())
+	assert.Equal(t, "approved", entries[0].To())
+}
+
+func TestModeration_RejectsHeldMessage(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	moderatedProject(t, h)
+
+	msg := h.post(t, "We decided to use Postgres for the Acme Corp billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	rejected := h.command(t, testChannel, "/quill moderation reject "+msg.ID().String())
+
+	assert.Equal(t, "🚫 Rejected message "+msg.ID().String()+", it will not be documented", rejected)
+	assert.Empty(t, documents(h.github))
+	stored := h.stored(t, msg)
+	assert.Equal(t, domain.MessageStateIgnored, stored.State())
+	assert.Equal(t, "rejected in moderation by alice", stored.StateReason())
+}
+
+func TestModeration_UsesModeratorScores(t *testing.T) {
+	tests := []struct {
+		name  string
+		score float64
+		want  domain.MessageState
+	}{
+		{name: "harmless", score: 0.1, want: domain.MessageStateDocumented},
+		{name: "borderline", score: 0.5, want: domain.MessageStatePending},
+		{name: "offensive", score: 0.95, want: domain.MessageStateIgnored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.moderation = domain.ModerationResult{Scores: map[string]float64{"harassment": tt.score, "violence": 0.01}}
+			h := newHarness(t, model.openAIProvider(t))
+			moderatedProject(t, h)
+
+			msg := h.post(t, "We decided to use Postgres for billing")
+			require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+			assert.Equal(t, tt.want, h.stored(t, msg).State())
+		})
+	}
+}
+
+func TestModeration_OffByDefault(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	msg := h.post(t, "The idiots in ops decided to use Postgres for billing")
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	assert.Len(t, documents(h.github), 1)
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+}
@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// okrProject binds the test channel to a project with an objective for the current quarter
+func okrProject(t *testing.T, h *harness) string {
+	t.Helper()
+
+	ctx := context.Background()
+	billingProject(t, h)
+	project, err := h.projects.FindByChannel(ctx, testChannel)
+	require.NoError(t, err)
+	quarter := domain.QuarterOf(time.Now())
+	require.NoError(t, project.SetObjectives([]domain.Objective{{
+		Title:   "Make billing self-serve",
+		Goal:    "Bill customers",
+		Quarter: quarter,
+		KeyResults: []domain.KeyResult{
+			{ID: "KR1", Title: "Self-serve signups", Target: 60, Unit: "%"},
+			{ID: "KR2", Title: "Invoice latency", Start: 800, Target: 200, Unit: "ms"},
+		},
+	}}))
+	require.NoError(t, h.projects.UpdateProject(ctx, project))
+	return quarter
+}
+
+func TestOKR_StatusUpdatesReportKeyResultProgress(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	quarter := okrProject(t, h)
+
+	first := h.post(t, "Self-serve signups went up this week, KR1: 30%")
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+	assert.Contains(t, lastReply(t, h, first), "🎯 Progress recorded on KR1 at 30")
+	second := h.post(t, "KR1 is now at 45% and KR2 is on track")
+	require.NoError(t, h.bot.ProcessMessage(ctx, second))
+	assert.Contains(t, lastReply(t, h, second), "🎯 Progress recorded on KR1 at 45, KR2")
+
+	path := "docs/okrs/" + quarter + "-billing.md"
+	page, ok := h.github.file(path)
+	require.True(t, ok, "page %s not found in %v", path, h.github.paths())
+	assert.Contains(t, page, "# OKRs of Billing, "+quarter+"\n")
+	assert.Contains(t, page, "Advances the goal \"Bill customers\", 38% done.\n")
+	assert.Contains(t, page, "| KR1: Self-serve signups | 0% | 45% | 60% | 75% |\n")
+	assert.Contains(t, page, "| KR2: Invoice latency | 800 ms | - | 200 ms | 0% |\n")
+	assert.Contains(t, page, "KR1 at 30% by alice ([status update](../status/development/")
+
+	fm := frontMatterOf(t, h, path)
+	assert.Equal(t, quarter, fm.Get("quarter"))
+	indexed, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "OKRs of Billing, "+quarter, indexed.Title())
+
+	// Status updates mentioning no key result leave the page as it is
+	unrelated := h.post(t, "Deployed the new invoice template")
+	require.NoError(t, h.bot.ProcessMessage(ctx, unrelated))
+	assert.NotContains(t, lastReply(t, h, unrelated), "🎯")
+}
+
+func TestOKR_CommandPublishesTheProgressPage(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	quarter := okrProject(t, h)
+
+	okrs := h.post(t, "/quill okrs")
+	require.NoError(t, h.bot.ProcessMessage(ctx, okrs))
+	reply := lastReply(t, h, okrs)
+	assert.Contains(t, reply, "🎯 *OKRs of Billing, "+quarter+"*, the page is in ")
+	assert.Contains(t, reply, "\n• Make billing self-serve: 0% done")
+	_, ok := h.github.file("docs/okrs/" + quarter + "-billing.md")
+	assert.True(t, ok)
+
+	other := h.post(t, "/quill okrs 2020-q1")
+	require.NoError(t, h.bot.ProcessMessage(ctx, other))
+	assert.Contains(t, lastReply(t, h, other), "No objectives are set for this quarter.")
+
+	invalid := h.post(t, "/quill okrs next")
+	require.NoError(t, h.bot.ProcessMessage(ctx, invalid))
+	assert.Contains(t, lastReply(t, h, invalid), "⚠️ invalid quarter")
+}
@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnership_AuthorOwnsDocumentUntilReassigned(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	reviewedProject(t, h)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+	doc := decisionDocument(t, h)
+	assert.Equal(t, "alice", domain.OwnerOf(frontMatterOf(t, h, doc.Path())))
+	assert.Equal(t, "alice", doc.Owner())
+	assert.Equal(t, "👤 "+doc.Path()+" is owned by alice", h.command(t, testChannel, "/quill owner "+doc.Path()))
+
+	reply := h.command(t, testChannel, "/quill owner "+doc.Path()+" @bob")
+	assert.Equal(t, "👤 "+doc.Path()+" is now owned by bob (was alice)", reply)
+	assert.Equal(t, "bob", domain.OwnerOf(frontMatterOf(t, h, doc.Path())))
+	assert.Equal(t, "bob", decisionDocument(t, h).Owner())
+
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionReassign, entries[0].Action())
+	assert.Equal(t, "alice", entries[0].Actor())
+	assert.Equal(t, doc.Path(), entries[0].Subject())
+	assert.Equal(t, "alice", entries[0].From())
+	assert.Equal(t, "bob", entries[0].To())
+}
+
+func TestOwnership_OwnersAreRemindedOfDocumentsPendingReview(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	reviewedProject(t, h)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+	doc := decisionDocument(t, h)
+	assert.Equal(t, "✅ None of your documents wait for review.", h.command(t, testChannel, "/quill reviews"))
+
+	require.NoError(t, h.reviews.FlagStale(ctx, doc.UpdatedAt().Add(31*24*time.Hour)))
+	digests := h.chat.sentTo("Dalice")
+	require.Len(t, digests, 1)
+	assert.Contains(t, digests[0], "1 document owned by alice waits for review")
+	assert.Contains(t, digests[0], "*"+doc.Title()+"*")
+	assert.Contains(t, h.command(t, testChannel, "/quill reviews"), "*"+doc.Title()+"*")
+
+	viewer, err := domain.NewHomeViewer("alice", []string{testChannel})
+	require.NoError(t, err)
+	home, err := h.home.Home(ctx, viewer)
+	require.NoError(t, err)
+	require.Len(t, home.Reviews, 1)
+	assert.Equal(t, doc.Title(), home.Reviews[0].Title)
+	assert.Equal(t, domain.CategoryDevelopment, home.Reviews[0].Category)
+
+	// Once reconfirmed the document no longer waits for its owner
+	review, err := domain.NewDocumentReview(doc.Path(), domain.ReviewReconfirm, "alice")
+	require.NoError(t, err)
+	require.NoError(t, h.reviews.Review(ctx, review))
+	home, err = h.home.Home(ctx, viewer)
+	require.NoError(t, err)
+	assert.Empty(t, home.Reviews)
+}
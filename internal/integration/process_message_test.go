@@ -0,0 +1,268 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/massimo-ua/quill/internal/providers/coordination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// providers are the LLM backends every scenario runs against
+var providers = []struct {
+	name string
+	new  func(model *fakeModel, t testing.TB) ports.AiAgentProvider
+}{
+	{name: "ollama", new: (*fakeModel).ollamaProvider},
+	{name: "openai", new: (*fakeModel).openAIProvider},
+}
+
+const docPath = "docs/development/"
+
+// documents lists the message documents committed to the fake repository
+func documents(gh *fakeGitHub) []string {
+	var docs []string
+	for _, path := range gh.paths() {
+		if strings.HasPrefix(path, docPath) && !strings.HasSuffix(path, "/INDEX.md") {
+			docs = append(docs, path)
+		}
+	}
+	return docs
+}
+
+func TestProcessMessage_DocumentsDecision(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "We decided to use Postgres for billing")
+
+			require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+			docs := documents(h.github)
+			require.Len(t, docs, 1)
+			assert.True(t, strings.HasSuffix(docs[0], "-adopt-postgres-for-billing.md"))
+
+			content, ok := h.github.file(docs[0])
+			require.True(t, ok)
+			assert.Contains(t, content, "source_message: "+msg.ID().String())
+			assert.Contains(t, content, "prompt_version: v1")
+			assert.Contains(t, content, "The team will use Postgres for billing.")
+
+			index, ok := h.github.file(docPath + "INDEX.md")
+			require.True(t, ok)
+			assert.Contains(t, index, "[Adopt Postgres]("+strings.TrimPrefix(docs[0], docPath)+")")
+			assert.Contains(t, h.github.commitMessages(), "Add decision documentation (development)")
+
+			replies := h.chat.repliesTo(msg.ID().String())
+			require.Len(t, replies, 1)
+			assert.True(t, strings.HasPrefix(replies[0], "✅ Recorded decision"))
+
+			stored := h.stored(t, msg)
+			assert.Equal(t, domain.MessageStateDocumented, stored.State())
+			assert.Equal(t, domain.MessageTypeDecision, stored.Type())
+			assert.Equal(t, "v1", stored.PromptVersion())
+		})
+	}
+}
+
+func TestProcessMessage_DocumentsIdea(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeIdea, domain.CategoryDevelopment)
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "What if billing moved to Postgres?")
+
+			require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+			require.Len(t, documents(h.github), 1)
+			replies := h.chat.repliesTo(msg.ID().String())
+			require.Len(t, replies, 1)
+			assert.True(t, strings.HasPrefix(replies[0], "📝 Captured idea"))
+			assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+		})
+	}
+}
+
+func TestProcessMessage_RetriesAfterGitHubFailure(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "We decided to use Postgres for billing")
+			h.github.failNext(http.MethodPost, "/repos/acme/docs/git/trees", http.StatusInternalServerError, 1)
+
+			err := h.bot.ProcessMessage(context.Background(), msg)
+
+			require.Error(t, err)
+			assert.Empty(t, documents(h.github))
+			stored := h.stored(t, msg)
+			assert.Equal(t, domain.MessageStateFailed, stored.State())
+			assert.NotEmpty(t, stored.StateReason())
+
+			// The failure reply refers to the correlation ID the logs of the processing start with
+			correlationID := stored.CorrelationID()
+			require.NotEmpty(t, correlationID)
+			assert.Equal(t, []string{"⚠️ Something went wrong, ref: " + correlationID.Short()}, h.chat.repliesTo(msg.ID().String()))
+
+			// Failed messages can be processed again once the backend recovered, under the same correlation ID
+			require.NoError(t, h.bot.ProcessMessage(context.Background(), stored))
+
+			assert.Len(t, documents(h.github), 1)
+			assert.Len(t, h.chat.repliesTo(msg.ID().String()), 2)
+			assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+			assert.Equal(t, correlationID, h.stored(t, msg).CorrelationID())
+		})
+	}
+}
+
+func TestProcessMessage_FailsWhenAnalysisFails(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.failNext(operationAnalyze, 1)
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "We decided to use Postgres for billing")
+
+			err := h.bot.ProcessMessage(context.Background(), msg)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to analyze message")
+			assert.Empty(t, h.github.commitMessages())
+			assert.Zero(t, model.callCount(operationDocument))
+			stored := h.stored(t, msg)
+			assert.Equal(t, domain.MessageStateFailed, stored.State())
+			assert.Contains(t, stored.StateReason(), "AI analysis failed")
+		})
+	}
+}
+
+func TestProcessMessage_IgnoresPausedProject(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			h := newHarness(t, p.new(model, t))
+			ctx := context.Background()
+
+			project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+				Name:          "Billing",
+				BusinessGoals: []string{"Bill customers"},
+			}, domain.DefaultDocumentationConfig())
+			require.NoError(t, err)
+			require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+			_, err = h.projects.PauseProject(ctx, project.ID())
+			require.NoError(t, err)
+			commits := len(h.github.commitMessages())
+
+			msg := h.post(t, "We decided to use Postgres for billing")
+			require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+			assert.Zero(t, model.callCount(operationAnalyze))
+			assert.Len(t, h.github.commitMessages(), commits)
+			assert.Empty(t, h.chat.repliesTo(msg.ID().String()))
+			stored := h.stored(t, msg)
+			assert.Equal(t, domain.MessageStateIgnored, stored.State())
+			assert.Equal(t, "project is not active", stored.StateReason())
+		})
+	}
+}
+
+func TestProcessMessage_AnalysisTimeout(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.stall(operationAnalyze)
+			h := newHarnessWithTimeouts(t, p.new(model, t), services.StageTimeouts{Analysis: 50 * time.Millisecond})
+			msg := h.post(t, "We decided to use Postgres for billing")
+
+			err := h.bot.ProcessMessage(context.Background(), msg)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Contains(t, err.Error(), "analysis timed out after 50ms")
+			assert.Empty(t, h.github.commitMessages())
+			stored := h.stored(t, msg)
+			assert.Equal(t, domain.MessageStateFailed, stored.State())
+			assert.Contains(t, stored.StateReason(), "analysis timed out")
+		})
+	}
+}
+
+func TestProcessMessage_Canceled(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.stall(operationDocument)
+			h := newHarness(t, p.new(model, t))
+			msg := h.post(t, "We decided to use Postgres for billing")
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := h.bot.ProcessMessage(ctx, msg)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.NotContains(t, err.Error(), "timed out after")
+			assert.Empty(t, documents(h.github))
+		})
+	}
+}
+
+func TestProcessMessage_ReplicasProcessEachChannelOnce(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	var replicas []*harness
+	for i := 0; i < 2; i++ {
+		shard, err := coordination.NewShardCoordinator(i, 2)
+		require.NoError(t, err)
+		replicas = append(replicas, newReplica(t, model.ollamaProvider(t), shard))
+	}
+
+	msg := replicas[0].post(t, "We decided to use Postgres for billing")
+	processed := 0
+	for _, replica := range replicas {
+		require.NoError(t, replica.bot.ProcessMessage(context.Background(), msg))
+		processed += len(documents(replica.github))
+	}
+
+	assert.Equal(t, 1, processed, "exactly one replica documents the message")
+}
+
+func TestProcessMessage_DropsInvalidDiagrams(t *testing.T) {
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+			model.responses[operationDocument] = "# Adopt Postgres\n\n```mermaid\nflowchart LR\n  api[Billing API] --> db[(Postgres)]\n```\n\n" +
+				"## Flow\n\n```mermaid\nsequenceDiagram\n  api->>db: insert invoice (retried)\n  loop nightly\n```\n\nThe team will use Postgres for billing."
+			h := newHarness(t, p.new(model, t))
+			ctx := context.Background()
+
+			docConfig := domain.DefaultDocumentationConfig()
+			docConfig.Diagrams = true
+			project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+				Name:          "Billing",
+				BusinessGoals: []string{"Bill customers"},
+			}, docConfig)
+			require.NoError(t, err)
+			require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+
+			msg := h.post(t, "We decided the billing API writes invoices to Postgres")
+			require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+			docs := documents(h.github)
+			require.Len(t, docs, 1)
+			content, ok := h.github.file(docs[0])
+			require.True(t, ok)
+			assert.Contains(t, content, "```mermaid\nflowchart LR\n  api[Billing API] --> db[(Postgres)]\n```")
+			assert.NotContains(t, content, "sequenceDiagram")
+			assert.Contains(t, content, "## Flow\n\nThe team will use Postgres for billing.")
+			assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+		})
+	}
+}
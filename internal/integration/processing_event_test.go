@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receivedEvents drains the events published so far
+func receivedEvents(events <-chan domain.ProcessingEvent) []domain.ProcessingEvent {
+	var received []domain.ProcessingEvent
+	for {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		default:
+			return received
+		}
+	}
+}
+
+func eventKinds(events []domain.ProcessingEvent) []domain.ProcessingEventKind {
+	kinds := make([]domain.ProcessingEventKind, len(events))
+	for i, event := range events {
+		kinds[i] = event.Kind()
+	}
+	return kinds
+}
+
+func TestProcessingEvents_FollowDecisionToCommit(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	events, unsubscribe := h.events.Subscribe(0)
+	defer unsubscribe()
+
+	msg := h.post(t, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	received := receivedEvents(events)
+	require.Equal(t, []domain.ProcessingEventKind{
+		domain.ProcessingEventReceived,
+		domain.ProcessingEventAnalyzed,
+		domain.ProcessingEventCommitted,
+	}, eventKinds(received))
+	for _, event := range received {
+		assert.Equal(t, msg.ID(), event.MessageID())
+		assert.Equal(t, testChannel, event.ChannelID())
+	}
+
+	analyzed, committed := received[1], received[2]
+	assert.Equal(t, domain.MessageTypeDecision, analyzed.Type())
+	assert.Equal(t, domain.CategoryDevelopment, analyzed.Category())
+	assert.Equal(t, domain.MessageStateAnalyzing, analyzed.State())
+	assert.Equal(t, []string{committed.Path()}, documents(h.github))
+	assert.Equal(t, domain.MessageStateDocumented, committed.State())
+}
+
+func TestProcessingEvents_IgnoredMessage(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeUnknown, domain.CategoryUnknown)
+	h := newHarness(t, model.ollamaProvider(t))
+	events, unsubscribe := h.events.Subscribe(0)
+	defer unsubscribe()
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), h.post(t, "Thanks everyone!")))
+
+	received := receivedEvents(events)
+	require.NotEmpty(t, received)
+	last := received[len(received)-1]
+	assert.Equal(t, domain.ProcessingEventIgnored, last.Kind())
+	assert.NotEmpty(t, last.Reason())
+	assert.Empty(t, last.Path())
+}
@@ -0,0 +1,39 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTemplate_ScaffoldsAndDocumentsWithTemplateSettings(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	assert.Contains(t, h.command(t, testChannel, "/quill project templates"), "*software-delivery*")
+
+	draft, err := domain.NewProjectDraft("Billing", domain.ProjectTemplateSoftwareDelivery, "", []string{"Move billing to Postgres"}, nil, testChannel, "alice")
+	require.NoError(t, err)
+	project, err := h.projects.CreateFromDraft(ctx, draft)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testChannel}, project.Channels())
+
+	// The empty repository is scaffolded with the folders of the template's taxonomy only
+	_, ok := h.github.file("docs/quality_assurance/.gitkeep")
+	assert.True(t, ok)
+	_, ok = h.github.file("docs/data_analysis/.gitkeep")
+	assert.False(t, ok)
+
+	// Decisions of the channel are filed with the template's path scheme
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+	doc := decisionDocument(t, h)
+	assert.True(t, strings.HasPrefix(doc.Path(), "docs/decision/development/"), doc.Path())
+
+	// A channel set up already cannot run the wizard again
+	assert.Contains(t, h.command(t, testChannel, "/quill project new"), "bound to project *Billing* already")
+}
@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance_SignsGeneratedDocuments(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	msg := h.post(t, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	doc := decisionDocument(t, h)
+
+	fm := frontMatterOf(t, h, doc.Path())
+	assert.Equal(t, []string{msg.ID().String()}, fm.GetList("source_messages"))
+	assert.Equal(t, "ollama:llama3", fm.Get("model"))
+	assert.Equal(t, domain.Generator(), fm.Get("generator"))
+	content, ok := h.github.file(doc.Path())
+	require.True(t, ok)
+	require.NoError(t, h.provenance.Verify(content))
+
+	// Changes made by the bot sign the document again
+	review, err := domain.NewDocumentReview(doc.Path(), domain.ReviewReconfirm, "U0002")
+	require.NoError(t, err)
+	require.NoError(t, h.reviews.Review(ctx, review))
+	content, _ = h.github.file(doc.Path())
+	assert.NoError(t, h.provenance.Verify(content))
+
+	// Changes made by anyone else do not
+	tampered := strings.Replace(content, "status: ", "status: superseded\nwas: ", 1)
+	assert.ErrorIs(t, h.provenance.Verify(tampered), domain.ErrInvalidSignature)
+}
+
+func TestProvenance_SignsStatusRollups(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	first := h.post(t, "Billing migration is 50% done")
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+	second := h.post(t, "Billing migration is 80% done")
+	require.NoError(t, h.bot.ProcessMessage(ctx, second))
+
+	indexed, err := h.index.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, indexed, 1)
+	content, ok := h.github.file(indexed[0].Path())
+	require.True(t, ok)
+	require.NoError(t, h.provenance.Verify(content))
+	fm, _, err := domain.ParseFrontMatter(content)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first.ID().String(), second.ID().String()}, fm.GetList("source_messages"))
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const runbookPath = "docs/development/runbooks/failover.md"
+
+const runbook = `---
+category: operations
+tags: [postgres, oncall]
+---
+# Database Failover
+
+Promote the replica when the primary stops answering.
+
+See [the decision](../adopt-postgres.md).
+`
+
+func TestReconciliation_IndexesDocumentsPeopleWrote(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move billing to Postgres")))
+	h.github.edit(runbookPath, runbook)
+
+	synced, err := h.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, synced, "the generated document is already indexed")
+
+	entry, err := h.index.FindByPath(ctx, runbookPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Database Failover", entry.Title())
+	assert.Equal(t, "Promote the replica when the primary stops answering.", entry.Summary())
+	assert.Equal(t, domain.CategoryOperations, entry.Category())
+	assert.True(t, entry.HasTag(domain.Tag("oncall")))
+
+	found, err := h.index.Search(ctx, "failover", 5)
+	require.NoError(t, err)
+	require.NotEmpty(t, found)
+	assert.Equal(t, runbookPath, found[0].Path())
+
+	decision, err := domain.NewDocumentReference("docs/development/adopt-postgres.md")
+	require.NoError(t, err)
+	var linking []string
+	for _, ref := range h.graph.ReferencedBy(*decision) {
+		linking = append(linking, ref.Value())
+	}
+	assert.Contains(t, linking, runbookPath)
+}
+
+func TestReconciliation_SyncsExternalEditsOnce(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move billing to Postgres")))
+	path := documents(h.github)[0]
+	_, err := h.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+
+	synced, err := h.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, synced, "unchanged documents are skipped")
+
+	h.github.edit(path, "# Adopt Postgres 16\n\nThe team will use Postgres 16 for billing.\n")
+	synced, err = h.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, synced)
+
+	entry, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "Adopt Postgres 16", entry.Title())
+	assert.Equal(t, "The team will use Postgres 16 for billing.", entry.Summary())
+}
+
+func TestReconciliation_RebuildsTheGraphAfterRestart(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move billing to Postgres")))
+	h.github.edit(runbookPath, runbook)
+	h.github.edit("docs/development/adopt-cockroach.md", "---\nsupersedes: [docs/development/adopt-postgres.md]\n---\n# Adopt CockroachDB\n")
+	_, err := h.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+
+	// A restarted bot starts with an empty graph over the same index and stores
+	graph := services.NewReferenceGraphService()
+	reconciler := services.NewReconciliationService(services.NewDocStoreResolver(h.github.store(t), nil), h.index, graph, nil, nil, nil)
+	recorded, err := reconciler.RebuildGraph(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, recorded)
+
+	decision, err := domain.NewDocumentReference("docs/development/adopt-postgres.md")
+	require.NoError(t, err)
+	var linking []string
+	for _, ref := range graph.ReferencedBy(*decision) {
+		linking = append(linking, ref.Value())
+	}
+	assert.ElementsMatch(t, []string{runbookPath, "docs/development/adopt-cockroach.md"}, linking)
+	backlinks := graph.InjectBacklinks(decision.Value(), "# Adopt Postgres\n")
+	assert.Contains(t, backlinks, "- [docs/development/adopt-cockroach.md](/docs/development/adopt-cockroach.md) (supersedes this document)")
+	assert.Contains(t, backlinks, "- ["+runbookPath+"](/"+runbookPath+")")
+}
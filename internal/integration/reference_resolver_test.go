@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceResolver_Resolve(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	stored := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.messages.Save(ctx, stored))
+	doc, err := domain.NewIndexedDocument("development/adopt-postgres.md", "Adopt Postgres for billing", "", domain.MessageTypeDecision, domain.CategoryDevelopment)
+	require.NoError(t, err)
+	require.NoError(t, h.index.Index(ctx, doc))
+	resolver := services.NewReferenceResolver(h.index, h.messages, model.ollamaProvider(t), nil)
+
+	tests := []struct {
+		name           string
+		ref            *domain.Reference
+		wantTarget     *domain.Reference
+		wantConfidence float64 // Exact unless between the resolution threshold and 1
+	}{
+		{
+			name:           "stored message",
+			ref:            domain.MustNewReference(domain.ReferenceTypeMessage, stored.ID().String()),
+			wantTarget:     domain.MustNewReference(domain.ReferenceTypeMessage, stored.ID().String()),
+			wantConfidence: 1,
+		},
+		{
+			name: "unknown message",
+			ref:  domain.MustNewReference(domain.ReferenceTypeMessage, "missing"),
+		},
+		{
+			name:           "document path",
+			ref:            domain.MustNewReference(domain.ReferenceTypeDocument, "development/adopt-postgres.md"),
+			wantTarget:     domain.MustNewReference(domain.ReferenceTypeDocument, "development/adopt-postgres.md"),
+			wantConfidence: 1,
+		},
+		{
+			name:       "document title",
+			ref:        domain.MustNewReference(domain.ReferenceTypeDocument, "adopt postgres for billing"),
+			wantTarget: domain.MustNewReference(domain.ReferenceTypeDocument, "development/adopt-postgres.md"),
+		},
+		{
+			name:       "unknown reference matching a title",
+			ref:        domain.MustNewReference(domain.ReferenceTypeUnknown, "Adopt Postgres for billing"),
+			wantTarget: domain.MustNewReference(domain.ReferenceTypeDocument, "development/adopt-postgres.md"),
+		},
+		{
+			name: "unmatched document",
+			ref:  domain.MustNewReference(domain.ReferenceTypeDocument, "quarterly hiring plan"),
+		},
+		{
+			name:           "link",
+			ref:            domain.MustNewReference(domain.ReferenceTypeURL, "https://example.com/rfc"),
+			wantTarget:     domain.MustNewReference(domain.ReferenceTypeURL, "https://example.com/rfc"),
+			wantConfidence: 1,
+		},
+		{
+			name:           "issue",
+			ref:            domain.MustNewReference(domain.ReferenceTypeIssue, "BILL-42"),
+			wantTarget:     domain.MustNewReference(domain.ReferenceTypeIssue, "BILL-42"),
+			wantConfidence: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := resolver.Resolve(ctx, nil, tt.ref)
+			require.NoError(t, err)
+
+			kept := res.Reference()
+			if tt.wantTarget == nil {
+				assert.False(t, res.IsResolved())
+				assert.True(t, kept.IsUnresolved())
+				assert.True(t, kept.Equals(*tt.ref), "the reference is kept as it was detected")
+				assert.Zero(t, kept.Confidence())
+				return
+			}
+
+			require.True(t, res.IsResolved())
+			assert.True(t, kept.Equals(*tt.wantTarget), "resolved to %s", kept)
+			assert.Equal(t, domain.ReferenceResolved, kept.Resolution())
+			if tt.wantConfidence > 0 {
+				assert.Equal(t, tt.wantConfidence, kept.Confidence())
+			} else {
+				assert.GreaterOrEqual(t, kept.Confidence(), services.DefaultResolutionThreshold)
+				assert.LessOrEqual(t, kept.Confidence(), 1.0)
+			}
+		})
+	}
+}
+
+func TestReferenceResolver_NilReference(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	resolver := services.NewReferenceResolver(h.index, h.messages, model.ollamaProvider(t), nil)
+
+	_, err := resolver.Resolve(context.Background(), nil, nil)
+
+	assert.Error(t, err)
+}
+
+func TestReferences_UnresolvedKeptOnTheMessage(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.responses[operationReferences] = `[{"type": "document", "value": "quarterly hiring plan"}, {"type": "url", "value": "https://example.com/rfc"}]`
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	msg := h.post(t, "We decided to move invoices to Postgres, see https://example.com/rfc and the quarterly hiring plan")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	refs := h.stored(t, msg).References()
+	require.Len(t, refs, 2)
+	assert.Equal(t, "quarterly hiring plan", refs[0].Value())
+	assert.True(t, refs[0].IsUnresolved())
+	assert.Equal(t, "https://example.com/rfc", refs[1].Value())
+	assert.Equal(t, domain.ReferenceResolved, refs[1].Resolution())
+	assert.Equal(t, 1.0, refs[1].Confidence())
+}
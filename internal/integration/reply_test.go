@@ -0,0 +1,168 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// repliesProject binds the test channel to a project with the reply settings
+func repliesProject(t *testing.T, h *harness, replies domain.ReplyConfig) {
+	t.Helper()
+
+	ctx := context.Background()
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, project.ConfigureReplies(replies))
+	require.NoError(t, h.projects.UpdateProject(ctx, project))
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+}
+
+// confirmations returns the capture confirmations replying to a message
+func confirmations(h *harness, msg *domain.Message) []string {
+	var found []string
+	for _, reply := range h.chat.repliesTo(msg.ID().String()) {
+		if strings.Contains(reply, "Recorded decision") {
+			found = append(found, reply)
+		}
+	}
+	return found
+}
+
+func TestReplies_BatchSummaryPostedOnceTheWindowEnds(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	repliesProject(t, h, domain.ReplyConfig{BatchWindow: time.Minute})
+
+	first := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+	second := h.reply(t, first, "We decided to keep the invoices for ten years")
+	require.NoError(t, h.bot.ProcessMessage(ctx, second))
+
+	posted, err := h.notifications.Flush(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, posted, "the window has not ended")
+	assert.Empty(t, confirmations(h, first))
+
+	posted, err = h.notifications.Flush(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, posted)
+	summaries := confirmations(h, first)
+	require.Len(t, summaries, 1)
+	assert.Contains(t, summaries[0], "Captured 2 items in this thread")
+}
+
+func TestReplies_QuietHoursConfirmationPostedOnceTheyEnd(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	now := time.Now().UTC()
+	repliesProject(t, h, domain.ReplyConfig{QuietHours: domain.QuietHours{
+		Start: now.Add(-time.Hour).Format("15:04"),
+		End:   now.Add(time.Hour).Format("15:04"),
+	}})
+
+	msg := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	posted, err := h.notifications.Flush(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, posted, "quiet hours have not ended")
+	assert.Empty(t, confirmations(h, msg))
+
+	posted, err = h.notifications.Flush(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, posted)
+	assert.Len(t, confirmations(h, msg), 1)
+}
+
+func TestReplies_BatchPostedAfterRestart(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	repliesProject(t, h, domain.ReplyConfig{BatchWindow: time.Minute})
+
+	msg := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	// A restarted bot posts the batches kept in the outbox
+	restarted := services.NewNotificationService(h.chat, h.projects, h.outbox, services.StageTimeouts{})
+	posted, err := restarted.Flush(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, posted)
+	assert.Len(t, confirmations(h, msg), 1)
+
+	posted, err = h.notifications.Flush(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, posted, "the batch is posted once")
+}
+
+func TestReplies_FailedSummaryPostedAgain(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	repliesProject(t, h, domain.ReplyConfig{BatchWindow: time.Minute})
+
+	msg := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	// A summary that fails to post stays in the outbox
+	h.chat.failReplies(errors.New("slack unavailable"))
+	now := time.Now().Add(time.Minute)
+	posted, err := h.notifications.Flush(ctx, now)
+	assert.Error(t, err)
+	assert.Zero(t, posted)
+	h.chat.failReplies(nil)
+
+	posted, err = h.notifications.Flush(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, posted, "the summary waits before it is posted again")
+	posted, err = h.notifications.Flush(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, posted)
+	assert.Len(t, confirmations(h, msg), 1)
+}
+
+func TestReplies_DetailsKeptAfterRestart(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	repliesProject(t, h, domain.ReplyConfig{Mode: domain.ReplyModeReaction})
+
+	msg := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	assert.Empty(t, confirmations(h, msg))
+
+	// A restarted bot shows the confirmations kept in the outbox
+	restarted := services.NewNotificationService(h.chat, h.projects, h.outbox, services.StageTimeouts{})
+	details, err := restarted.Details(ctx, msg.ID().String())
+	require.NoError(t, err)
+	assert.Contains(t, details, "Recorded decision")
+}
+
+func TestReplies_RunStopsWithItsContext(t *testing.T) {
+	h := newHarness(t, newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment).ollamaProvider(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- h.notifications.Run(ctx, time.Millisecond) }()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("the notification service did not stop")
+	}
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// improvedDocument is what the documentation prompt writes once it was improved
+const improvedDocument = "# Adopt Postgres 16\n\nThe team will use Postgres 16 for billing, with nightly backups."
+
+func reprocess(t *testing.T, h *harness, since time.Time, category domain.Category, dryRun bool) *domain.ReprocessReport {
+	t.Helper()
+
+	request, err := domain.NewReprocessRequest(since, category, dryRun, "alice")
+	require.NoError(t, err)
+	report, err := h.reprocess.Reprocess(context.Background(), request)
+	require.NoError(t, err)
+	return report
+}
+
+func TestReprocess_UpdatesDocumentsInPlace(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	msg := h.post(t, "We decided to move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	path := decisionDocument(t, h).Path()
+	before, _ := h.github.file(path)
+	model.responses[operationDocument] = improvedDocument
+
+	// A dry run reports the change without storing it
+	report := reprocess(t, h, time.Time{}, "", true)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Messages)
+	require.Len(t, report.Documents, 1)
+	assert.Equal(t, path, report.Documents[0].Path)
+	assert.Equal(t, uint(2), report.Documents[0].Version)
+	assert.Contains(t, report.Documents[0].Diff, "+The team will use Postgres 16 for billing, with nightly backups.")
+	after, _ := h.github.file(path)
+	assert.Equal(t, before, after)
+
+	report = reprocess(t, h, time.Time{}, "", false)
+	require.Len(t, report.Documents, 1)
+	assert.Equal(t, msg.ID().String(), report.Documents[0].MessageID)
+	assert.Positive(t, report.Documents[0].Added)
+	assert.Empty(t, report.Failures)
+
+	assert.Equal(t, []string{path}, documents(h.github), "the document is updated where it is")
+	content, _ := h.github.file(path)
+	assert.Contains(t, content, "with nightly backups")
+	fm := frontMatterOf(t, h, path)
+	assert.Equal(t, "2", fm.Get("version"))
+	assert.Equal(t, "v1", fm.Get("prompt_version"))
+	assert.NotEmpty(t, fm.Get("reprocessed_at"))
+	assert.Equal(t, domain.MessageIdempotencyKey(h.stored(t, msg)), fm.Get("idempotency_key"))
+	entry, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "Adopt Postgres 16", entry.Title())
+
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionReprocess, entries[0].Action())
+	assert.Equal(t, "alice", entries[0].Actor())
+	assert.Equal(t, "all messages", entries[0].Subject())
+
+	// Documents the prompt writes the same way are left as they are
+	report = reprocess(t, h, time.Time{}, "", false)
+	assert.Empty(t, report.Documents)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, "2", frontMatterOf(t, h, path).Get("version"))
+}
+
+func TestReprocess_SelectsMessagesBySinceAndCategory(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to move billing to Postgres")))
+	cutoff := time.Now()
+	model.analysis.Category = domain.CategoryProduct
+	product := h.post(t, "We decided to move product billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, product))
+	indexed, err := h.index.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, indexed, 2)
+	model.responses[operationDocument] = improvedDocument
+
+	report := reprocess(t, h, time.Time{}, domain.CategoryProduct, true)
+	assert.Equal(t, 1, report.Messages)
+	require.Len(t, report.Documents, 1)
+	assert.Equal(t, product.ID().String(), report.Documents[0].MessageID)
+
+	report = reprocess(t, h, cutoff, "", true)
+	assert.Equal(t, 1, report.Messages)
+	require.Len(t, report.Documents, 1)
+	assert.Equal(t, product.ID().String(), report.Documents[0].MessageID)
+
+	report = reprocess(t, h, time.Time{}, "", true)
+	assert.Equal(t, 2, report.Messages)
+	assert.Len(t, report.Documents, 2)
+}
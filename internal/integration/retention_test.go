@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetention_DeletesExpiredMessagesAndKeepsDocuments(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	msg := h.post(t, "We decided to move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	docs := documents(h.github)
+	policy, err := domain.NewRetentionPolicy(30, domain.RetentionDelete)
+	require.NoError(t, err)
+	retention := services.NewRetentionService(h.messages, h.audit, policy)
+
+	purged, err := retention.Purge(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = retention.Purge(ctx, time.Now().Add(31*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	_, err = h.messages.FindByID(ctx, msg.ID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.Equal(t, docs, documents(h.github))
+
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionPurge, entries[0].Action())
+	assert.Equal(t, msg.ID().String(), entries[0].Subject())
+	assert.Equal(t, "documented", entries[0].From())
+	assert.Equal(t, "delete", entries[0].To())
+}
+
+func TestRetention_AnonymizesExpiredMessagesOnce(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	msg := h.post(t, "We decided to move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	policy, err := domain.NewRetentionPolicy(30, domain.RetentionAnonymize)
+	require.NoError(t, err)
+	retention := services.NewRetentionService(h.messages, h.audit, policy)
+	later := time.Now().Add(31 * 24 * time.Hour)
+
+	purged, err := retention.Purge(ctx, later)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	stored := h.stored(t, msg)
+	assert.True(t, stored.IsAnonymized())
+	assert.Equal(t, domain.AnonymizedSender, stored.Sender())
+	assert.NotContains(t, stored.Content().Text(), "Postgres")
+	assert.Equal(t, domain.MessageStateDocumented, stored.State())
+
+	purged, err = retention.Purge(ctx, later)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	entries, err := h.audit.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRisk_DocumentedMessagesFillTheRegister(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.responses[operationRisks] = `{"risks": [{"statement": "The billing launch slips if the vendor API is late", "severity": "high", "likelihood": "medium"}]}`
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	msg := h.post(t, "We decided to adopt Postgres, but we might miss the deadline if the vendor API is late")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+	assert.Equal(t, 1, model.callCount(operationRisks))
+
+	register, ok := h.github.file(domain.RiskRegisterFile)
+	require.True(t, ok, "register not found in %v", h.github.paths())
+	assert.Contains(t, register, "## R-1: The billing launch slips if the vendor API is late\n\n"+
+		"- Severity: high\n- Likelihood: medium\n- Owner: alice\n- Status: open\n- Mitigation: none yet\n")
+	assert.Contains(t, register, "- Raised: ")
+	assert.Contains(t, register, "](development/", "risks link to the document of their message")
+
+	// Messages without risk phrasing are not sent to the model
+	calm := h.post(t, "We decided to keep invoices in Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, calm))
+	assert.Equal(t, 1, model.callCount(operationRisks))
+}
+
+func TestRisk_StatusUpdatesFallBackToThePhrasing(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	model.failNext(operationRisks, 10)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	msg := h.post(t, "Invoices are done. The refunds API could slip to next sprint.")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	register, ok := h.github.file(domain.RiskRegisterFile)
+	require.True(t, ok, "register not found in %v", h.github.paths())
+	assert.Contains(t, register, "## R-1: The refunds API could slip to next sprint\n\n- Severity: medium\n- Likelihood: medium\n")
+	assert.Contains(t, register, "](status/development/")
+}
+
+func TestRisk_CommandsAndOwnerReminders(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.responses[operationRisks] = `{"risks": [` +
+		`{"statement": "The vendor API may be late", "severity": "high", "likelihood": "high"}, ` +
+		`{"statement": "Refunds could fail for old invoices", "severity": "low", "likelihood": "low", "owner": "U2"}]}`
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	msg := h.post(t, "We decided to ship refunds, though the vendor API may be late")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	risks := h.post(t, "/quill risks")
+	require.NoError(t, h.bot.ProcessMessage(ctx, risks))
+	reply := lastReply(t, h, risks)
+	assert.Contains(t, reply, "⚠️ *2 risks* are open, the register is in ")
+	assert.Contains(t, reply, "\n• *R-1* (high severity, high likelihood, open): The vendor API may be late, owned by alice")
+
+	require.NoError(t, h.risks.Remind(ctx))
+	reminders := h.chat.sentTo("Dalice")
+	require.Len(t, reminders, 1)
+	assert.Contains(t, reminders[0], "⚠️ 1 risks owned by alice have no mitigation yet")
+	assert.Contains(t, reminders[0], "• *R-1* (high severity, high likelihood, raised ")
+	assert.Len(t, h.chat.sentTo("DU2"), 1)
+
+	mitigate := h.post(t, "/quill risk mitigate R-1 Cache the vendor responses")
+	require.NoError(t, h.bot.ProcessMessage(ctx, mitigate))
+	assert.Contains(t, lastReply(t, h, mitigate), "🛡️ Risk *R-1* is mitigated: Cache the vendor responses")
+	closed := h.post(t, "/quill risk close r-2")
+	require.NoError(t, h.bot.ProcessMessage(ctx, closed))
+	assert.Contains(t, lastReply(t, h, closed), "✅ Risk *R-2* is closed.")
+
+	register, ok := h.github.file(domain.RiskRegisterFile)
+	require.True(t, ok)
+	assert.Contains(t, register, "- Status: mitigated\n- Mitigation: Cache the vendor responses\n")
+	assert.Contains(t, register, "- Status: closed\n")
+
+	// Mitigated and closed risks are not reminded of
+	require.NoError(t, h.risks.Remind(ctx))
+	assert.Len(t, h.chat.sentTo("Dalice"), 1)
+	assert.Len(t, h.chat.sentTo("DU2"), 1)
+
+	unknown := h.post(t, "/quill risk close R-9")
+	require.NoError(t, h.bot.ProcessMessage(ctx, unknown))
+	assert.Contains(t, lastReply(t, h, unknown), "risk not found")
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// qaChannel is where the test project routes its quality assurance captures
+const qaChannel = "C0QA"
+
+// routedProject binds the test channel to a project routing its quality assurance captures to the QA channel
+func routedProject(t *testing.T, h *harness, triage bool) {
+	t.Helper()
+
+	ctx := context.Background()
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	replies := project.Replies()
+	replies.Routes = domain.RoutingTable{domain.CategoryQualityAssurance: qaChannel}
+	require.NoError(t, project.ConfigureReplies(replies))
+	autoDetection := project.AutoDetection()
+	autoDetection.Triage = triage
+	require.NoError(t, project.ConfigureAutoDetection(autoDetection))
+	require.NoError(t, h.projects.UpdateProject(ctx, project))
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+}
+
+func TestRouting_ConfirmsRoutedCategoriesInTheirChannel(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryQualityAssurance)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	routedProject(t, h, false)
+
+	msg := h.post(t, "We decided to run the regression suite on every release branch")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+	for _, reply := range h.chat.repliesTo(msg.ID().String()) {
+		assert.NotContains(t, reply, "Recorded decision", "routed captures are not confirmed in their thread")
+	}
+	confirmations := h.chat.sentTo(qaChannel)
+	require.Len(t, confirmations, 1)
+	assert.True(t, strings.HasPrefix(confirmations[0], "✅ Recorded decision in category: quality_assurance"))
+	assert.Contains(t, confirmations[0], "\n> We decided to run the regression suite on every release branch\n↪️ Posted by alice in channel "+testChannel)
+
+	// Other categories are confirmed in their thread
+	model.analysis.Category = domain.CategoryDevelopment
+	other := h.post(t, "We decided to move invoices to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, other))
+	assert.Contains(t, lastReply(t, h, other), "✅ Recorded decision in category: development")
+	assert.Len(t, h.chat.sentTo(qaChannel), 1)
+}
+
+func TestRouting_TriageDigestListsMessagesInTheRoutedChannel(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryQualityAssurance)
+	model.analysis.ConfidenceScore = 0.4
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	routedProject(t, h, true)
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "Maybe we drop the flaky checkout tests")))
+	model.analysis.Category = domain.CategoryDevelopment
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "Maybe we move billing to Postgres")))
+
+	require.NoError(t, h.triage.PostDigest(ctx))
+	routed := h.chat.sentTo(qaChannel)
+	require.Len(t, routed, 1)
+	assert.Contains(t, routed[0], "> Maybe we drop the flaky checkout tests")
+	assert.NotContains(t, routed[0], "Maybe we move billing")
+	own := h.chat.sentTo(testChannel)
+	require.Len(t, own, 1)
+	assert.Contains(t, own[0], "> Maybe we move billing to Postgres")
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const otherChannel = "C0002"
+
+// command posts a command in a channel and returns the bot's reply
+func (h *harness) command(t *testing.T, channelID, text string) string {
+	t.Helper()
+
+	msg := h.post(t, text)
+	msg.SetChannelID(channelID)
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+	replies := h.chat.repliesTo(msg.ID().String())
+	require.NotEmpty(t, replies)
+	return replies[len(replies)-1]
+}
+
+func TestSharing_ProjectsSeeOnlySharedDocumentsOfOthers(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	billing, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{Name: "Billing", BusinessGoals: []string{"Move billing to Postgres"}}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, billing.ID(), testChannel))
+	payments, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{Name: "Payments", BusinessGoals: []string{"Settle payments daily"}}, domain.DocumentationConfig{Visibility: domain.VisibilityShared})
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, payments.ID(), otherChannel))
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+
+	model.mu.Lock()
+	model.responses[operationTitle] = "Settle payments with Kafka"
+	model.responses[operationDocument] = "# Settle payments with Kafka\n\nPayments are settled from a Kafka topic."
+	model.mu.Unlock()
+	settlement := h.post(t, "We decided to settle payments from Kafka")
+	settlement.SetChannelID(otherChannel)
+	require.NoError(t, h.bot.ProcessMessage(ctx, settlement))
+
+	var billingDoc string
+	indexed, err := h.index.List(ctx)
+	require.NoError(t, err)
+	for _, doc := range indexed {
+		if doc.Project() == billing.ID() {
+			billingDoc = doc.Path()
+		}
+	}
+	require.NotEmpty(t, billingDoc)
+
+	// Billing keeps its documents internal, Payments shares them
+	shared := h.command(t, testChannel, "/quill shared")
+	assert.Contains(t, shared, "Settle payments with Kafka (Payments)")
+	assert.NotContains(t, shared, "Adopt Postgres")
+
+	answer := h.command(t, otherChannel, "/quill ask postgres billing")
+	assert.NotContains(t, answer, billingDoc)
+	answer = h.command(t, testChannel, "/quill ask postgres billing")
+	assert.Contains(t, answer, billingDoc)
+
+	// Only Billing's channels can share its documents
+	denied := h.command(t, otherChannel, "/quill visibility "+billingDoc+" shared")
+	assert.Contains(t, denied, "only the channels of the document's project")
+
+	assert.Contains(t, h.command(t, testChannel, "/quill visibility "+billingDoc+" shared"), "is now shared")
+	content, ok := h.github.file(billingDoc)
+	require.True(t, ok)
+	fm, _, err := domain.ParseFrontMatter(content)
+	require.NoError(t, err)
+	assert.Equal(t, "shared", fm.Get("visibility"))
+
+	shared = h.command(t, otherChannel, "/quill shared postgres")
+	assert.Contains(t, shared, "Adopt Postgres (Billing)")
+	assert.False(t, strings.Contains(shared, "Kafka"), shared)
+
+	answer = h.command(t, otherChannel, "/quill ask postgres billing")
+	assert.Contains(t, answer, billingDoc)
+}
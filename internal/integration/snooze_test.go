@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// billingProject binds the test channel to a project documenting with the default configuration
+func billingProject(t *testing.T, h *harness) {
+	t.Helper()
+
+	ctx := context.Background()
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+}
+
+// assertNotKept checks the pipeline kept nothing of a message
+func assertNotKept(t *testing.T, h *harness, msg *domain.Message) {
+	t.Helper()
+
+	_, err := h.messages.FindByID(context.Background(), msg.ID().String())
+	assert.Error(t, err, "the message is not stored")
+}
+
+func TestSnooze_SkipsMessagesWithTheNoDocMarker(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	billingProject(t, h)
+
+	msg := h.post(t, "!nodoc We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	assertNotKept(t, h, msg)
+	assert.Empty(t, documents(h.github))
+	assert.Empty(t, h.chat.repliesTo(msg.ID().String()))
+}
+
+func TestSnooze_SkipsSnoozedThreadsUntilResumed(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+	first := h.post(t, "Let's talk about the billing database")
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+
+	snooze := h.reply(t, first, "/quill snooze 2h")
+	require.NoError(t, h.bot.ProcessMessage(ctx, snooze))
+	replies := h.chat.repliesTo(snooze.ID().String())
+	require.NotEmpty(t, replies)
+	assert.Contains(t, replies[len(replies)-1], "😴 Quill stops capturing this thread until")
+
+	skipped := h.reply(t, first, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, skipped))
+	assertNotKept(t, h, skipped)
+
+	// Other threads are still captured
+	other := h.post(t, "We decided to bill monthly")
+	require.NoError(t, h.bot.ProcessMessage(ctx, other))
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, other).State())
+
+	resume := h.reply(t, first, "/quill snooze off")
+	require.NoError(t, h.bot.ProcessMessage(ctx, resume))
+	replies = h.chat.repliesTo(resume.ID().String())
+	require.NotEmpty(t, replies)
+	assert.Equal(t, "⏰ Quill captures this thread again.", replies[len(replies)-1])
+
+	captured := h.reply(t, first, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, captured))
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, captured).State())
+}
+
+func TestSnooze_OptOutStopsCapturingTheThread(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+	first := h.post(t, "Let's talk about the billing database")
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+
+	require.NoError(t, h.snoozes.OptOut(ctx, first.ID().String(), "bob"))
+
+	skipped := h.reply(t, first, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, skipped))
+	assertNotKept(t, h, skipped)
+
+	// The opt-out lasts until the thread is resumed
+	snoozed, err := h.snoozes.Snoozed(ctx, first.ThreadID().String())
+	require.NoError(t, err)
+	assert.True(t, snoozed)
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// standupMonday is a weekday the standup of the billing project runs on
+var standupMonday = time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+// standupProject binds the test channel to a project asking U1 and U2 at 09:30 and compiling at 11:00
+func standupProject(t *testing.T, h *harness) {
+	t.Helper()
+
+	ctx := context.Background()
+	billingProject(t, h)
+	project, err := h.projects.FindByChannel(ctx, testChannel)
+	require.NoError(t, err)
+	require.NoError(t, project.ConfigureStandup(domain.StandupConfig{
+		Members:   []string{"U1", "U2"},
+		Questions: []string{"What did you do?", "What is next?"},
+		PromptAt:  "09:30",
+		SummaryAt: "11:00",
+	}))
+	require.NoError(t, h.projects.UpdateProject(ctx, project))
+}
+
+// direct returns a message a member sent in their direct conversation with the bot
+func (h *harness) direct(t *testing.T, channelID, sender, text string) *domain.Message {
+	t.Helper()
+
+	msg, err := domain.NewMessage(common.GenerateID(), sender, domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID(channelID)
+	return msg
+}
+
+func TestStandup_AsksMembersAndCompilesTheirAnswers(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	standupProject(t, h)
+
+	// Nothing is asked before the prompt time
+	require.NoError(t, h.standups.Tick(ctx, standupMonday.Add(9*time.Hour)))
+	assert.Empty(t, h.chat.sentTo("DU1"))
+
+	promptAt := standupMonday.Add(9*time.Hour + 30*time.Minute)
+	require.NoError(t, h.standups.Tick(ctx, promptAt))
+	require.NoError(t, h.standups.Tick(ctx, promptAt.Add(time.Minute)))
+	prompts := h.chat.sentTo("DU1")
+	require.Len(t, prompts, 1, "members are asked once a day")
+	assert.Equal(t, "👋 Time for the *Standup of Billing on 2026-03-02*! Reply here before 11:00 UTC:\n1. What did you do?\n2. What is next?", prompts[0])
+	assert.Len(t, h.chat.sentTo("DU2"), 1)
+
+	// Answers are collected without being analyzed
+	answer := h.direct(t, "DU1", "alice", "Shipped the invoice export, next is the refunds API")
+	require.NoError(t, h.bot.ProcessMessage(ctx, answer))
+	assert.Equal(t, domain.MessageStateAnalyzing, h.stored(t, answer).State())
+	assert.Zero(t, model.callCount(operationAnalyze))
+
+	require.NoError(t, h.standups.Tick(ctx, standupMonday.Add(11*time.Hour)))
+
+	path := "docs/standups/2026-03-02-billing.md"
+	notes, ok := h.github.file(path)
+	require.True(t, ok, "notes %s not found in %v", path, h.github.paths())
+	assert.Contains(t, notes, "# Standup of Billing on 2026-03-02\n\n1 of 2 members answered.\n")
+	assert.Contains(t, notes, "## alice\n\nShipped the invoice export, next is the refunds API\n")
+	assert.Contains(t, notes, "## No answer\n\n- U2\n")
+
+	fm := frontMatterOf(t, h, path)
+	assert.Equal(t, "2026-03-02", fm.Get("standup_date"))
+	assert.Equal(t, []string{"alice"}, fm.GetList("answered_by"))
+	indexed, err := h.index.FindByPath(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, domain.MessageTypeStatus, indexed.Type())
+
+	summaries := h.chat.sentTo(testChannel)
+	require.Len(t, summaries, 1)
+	assert.Contains(t, summaries[0], "🧍 *Standup of Billing on 2026-03-02*: 1 of 2 answered, the notes are in ")
+	assert.Contains(t, summaries[0], "\n• *alice*: Shipped the invoice export, next is the refunds API")
+	assert.Contains(t, summaries[0], "\nNo answer from U2")
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, answer).State())
+
+	// Once compiled, the summary is not posted again and late answers are analyzed like any other message
+	require.NoError(t, h.standups.Tick(ctx, standupMonday.Add(11*time.Hour+time.Minute)))
+	assert.Len(t, h.chat.sentTo(testChannel), 1)
+	late := h.direct(t, "DU2", "bob", "We decided to drop the legacy exporter")
+	require.NoError(t, h.bot.ProcessMessage(ctx, late))
+	assert.Equal(t, 1, model.callCount(operationAnalyze))
+}
+
+func TestStandup_SkipsWeekends(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	standupProject(t, h)
+
+	saturday := standupMonday.AddDate(0, 0, -2).Add(10 * time.Hour)
+	require.NoError(t, h.standups.Tick(ctx, saturday))
+	assert.Empty(t, h.chat.sentTo("DU1"))
+}
@@ -0,0 +1,29 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsCommand_ReportsCapturedDocumentation(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	decision := h.post(t, "We decided to move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, decision))
+	assert.Equal(t, 0.9, h.stored(t, decision).Confidence())
+
+	reply := h.command(t, testChannel, "/quill stats")
+
+	assert.Contains(t, reply, "1 messages captured, 1 documents indexed")
+	assert.Contains(t, reply, "Average confidence: 90%")
+	assert.Contains(t, reply, "By type: decision 1")
+	assert.Contains(t, reply, "By category: development 1")
+	assert.Contains(t, reply, "Top contributors: alice 1")
+	assert.Contains(t, reply, testChannel+": 100% (1 of 1 documented)")
+}
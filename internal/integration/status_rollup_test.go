@@ -0,0 +1,30 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusRollup_FiledUnderTheWeekTheUpdateWasPosted(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeStatus, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	billingProject(t, h)
+
+	// A backfilled update, posted three weeks ago
+	posted := time.Now().UTC().AddDate(0, 0, -21)
+	msg := h.post(t, "Invoice export is done")
+	msg.SetTimestamp(posted)
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	content, ok := h.github.file(domain.StatusRollupPath(domain.CategoryDevelopment, posted))
+	require.True(t, ok, "rollup not found in %v", h.github.paths())
+	assert.Contains(t, content, "## "+posted.Format("Mon 2006-01-02 15:04 UTC")+" by alice")
+
+	_, ok = h.github.file(domain.StatusRollupPath(domain.CategoryDevelopment, time.Now()))
+	assert.False(t, ok, "the update is not filed under this week")
+}
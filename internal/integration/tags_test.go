@@ -0,0 +1,40 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags_ProjectsTagAndListOnlyTheirDocuments(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+
+	// Billing keeps its documents internal, Payments shares them
+	billingProject(t, h)
+	payments, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{Name: "Payments", BusinessGoals: []string{"Settle payments daily"}}, domain.DocumentationConfig{Visibility: domain.VisibilityShared})
+	require.NoError(t, err)
+	require.NoError(t, h.projects.BindChannel(ctx, payments.ID(), otherChannel))
+	require.NoError(t, h.bot.ProcessMessage(ctx, h.post(t, "We decided to use Postgres for billing")))
+	billingDoc := decisionDocument(t, h).Path()
+
+	denied := h.command(t, otherChannel, "/quill tag add "+billingDoc+" invoices")
+	assert.Contains(t, denied, "only the channels of the document's project")
+	assert.NotContains(t, frontMatterOf(t, h, billingDoc).GetList("tags"), "invoices")
+
+	assert.Contains(t, h.command(t, testChannel, "/quill tag add "+billingDoc+" invoices"), "Tagged "+billingDoc)
+	assert.Contains(t, frontMatterOf(t, h, billingDoc).GetList("tags"), "invoices")
+
+	// Billing's internal documents and their tags stay out of the listings of Payments
+	assert.Contains(t, h.command(t, testChannel, "/quill tag list invoices"), billingDoc)
+	assert.Contains(t, h.command(t, testChannel, "/quill tags"), "#invoices (1)")
+	assert.Equal(t, "No documents are tagged #invoices.", h.command(t, otherChannel, "/quill tag list invoices"))
+	assert.NotContains(t, h.command(t, otherChannel, "/quill tags"), "#invoices")
+
+	assert.Contains(t, h.command(t, testChannel, "/quill visibility "+billingDoc+" shared"), "is now shared")
+	assert.Contains(t, h.command(t, otherChannel, "/quill tag list invoices"), billingDoc)
+}
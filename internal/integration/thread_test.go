@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreads_AreTitledWhenTheyStart(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.responses[operationTitle] = "Billing database"
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	first := h.post(t, "Which database should billing use?")
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+	model.responses[operationTitle] = "Adopt Postgres for billing"
+	reply := h.reply(t, first, "We decided to use Postgres for billing")
+	require.NoError(t, h.bot.ProcessMessage(ctx, reply))
+
+	// The thread keeps the title of its first message
+	assert.Equal(t, "Billing database", h.threads.Title(ctx, first.ThreadID()))
+	thread, err := h.threads.Record(ctx, reply)
+	require.NoError(t, err)
+	assert.Equal(t, 2, thread.MessageCount(), "a message recorded again is not added twice")
+
+	docs := documents(h.github)
+	require.NotEmpty(t, docs)
+	for _, path := range docs {
+		content, ok := h.github.file(path)
+		require.True(t, ok)
+		fm, _, err := domain.ParseFrontMatter(content)
+		require.NoError(t, err)
+		assert.Equal(t, "Billing database", fm.Get("thread_title"), path)
+	}
+}
+
+func TestThreads_TitlesThreadsTheChatProviderStarted(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.responses[operationTitle] = "Billing database"
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	billingProject(t, h)
+
+	// The chat provider stores the Slack threads it sees, titled with their text, to find them after a restart
+	msg := h.post(t, "Which database should billing use?")
+	started, err := domain.NewThread(msg.ThreadID(), testChannel, domain.ThreadTitleFrom(msg.Content().Text()))
+	require.NoError(t, err)
+	started.SetExternalID("1718000000.000100")
+	require.NoError(t, h.threadRepo.Save(ctx, started))
+
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	thread, err := h.threadRepo.FindByID(ctx, msg.ThreadID())
+	require.NoError(t, err)
+	assert.Equal(t, "Billing database", thread.Title())
+	assert.Equal(t, "1718000000.000100", thread.ExternalID())
+	assert.Equal(t, 1, thread.MessageCount())
+}
+
+func TestThreads_NameTheThreadInTheTriageDigest(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.analysis.ConfidenceScore = 0.4
+	model.responses[operationTitle] = "Billing database"
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	triagedProject(t, h)
+
+	msg := h.post(t, "Maybe we move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	require.NoError(t, h.triage.PostDigest(ctx))
+	digests := h.chat.sentTo(testChannel)
+	require.Len(t, digests, 1)
+	assert.Contains(t, digests[0], "by alice in “Billing database”, read as decision")
+}
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// triagedProject binds the test channel to a project queuing the messages analysed below 0.7 confidence
+func triagedProject(t *testing.T, h *harness) {
+	t.Helper()
+
+	ctx := context.Background()
+	project, err := h.projects.CreateConfiguredProject(ctx, &domain.ProjectMetadata{
+		Name:          "Billing",
+		BusinessGoals: []string{"Bill customers"},
+	}, domain.DefaultDocumentationConfig())
+	require.NoError(t, err)
+	autoDetection := project.AutoDetection()
+	autoDetection.Triage = true
+	require.NoError(t, project.ConfigureAutoDetection(autoDetection))
+	require.NoError(t, h.projects.UpdateProject(ctx, project))
+	require.NoError(t, h.projects.BindChannel(ctx, project.ID(), testChannel))
+}
+
+func TestTriage_QueuesLowConfidenceMessagesForTheDigest(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.analysis.ConfidenceScore = 0.4
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	triagedProject(t, h)
+
+	msg := h.post(t, "Maybe we move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	assert.Empty(t, documents(h.github))
+	assert.Empty(t, h.chat.repliesTo(msg.ID().String()), "the thread is not interrupted")
+	assert.Equal(t, domain.MessageStateAnalyzing, h.stored(t, msg).State())
+
+	require.NoError(t, h.triage.PostDigest(ctx))
+	digests := h.chat.sentTo(testChannel)
+	require.Len(t, digests, 1)
+	assert.True(t, strings.HasPrefix(digests[0], "🗂️ 1 message could not be documented with confidence"))
+	assert.Contains(t, digests[0], "> Maybe we move billing to Postgres")
+	assert.Contains(t, digests[0], "read as decision in development (40% confident)")
+}
+
+func TestTriage_DocumentsUnderThePickedCategory(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.analysis.ConfidenceScore = 0.4
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	triagedProject(t, h)
+	msg := h.post(t, "Maybe we move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	decision, err := domain.NewTriageDecision(msg.ID().String(), "operations", "bob")
+	require.NoError(t, err)
+	require.NoError(t, h.bot.Triage(ctx, decision))
+
+	assert.Contains(t, h.github.paths(), "docs/operations/INDEX.md")
+	stored := h.stored(t, msg)
+	assert.Equal(t, domain.MessageStateDocumented, stored.State())
+	assert.Equal(t, domain.CategoryOperations, stored.Category())
+	pending, err := h.triage.Pending(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// A decided message is no longer in the queue
+	assert.Error(t, h.bot.Triage(ctx, decision))
+}
+
+func TestTriage_DismissesMessages(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	model.analysis.ConfidenceScore = 0.4
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	triagedProject(t, h)
+	msg := h.post(t, "Maybe we move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	decision, err := domain.NewTriageDecision(msg.ID().String(), domain.TriageDismiss, "bob")
+	require.NoError(t, err)
+	require.NoError(t, h.bot.Triage(ctx, decision))
+
+	assert.Empty(t, documents(h.github))
+	stored := h.stored(t, msg)
+	assert.Equal(t, domain.MessageStateIgnored, stored.State())
+	assert.Equal(t, "dismissed in triage by bob", stored.StateReason())
+	require.NoError(t, h.triage.PostDigest(ctx))
+	assert.Empty(t, h.chat.sentTo(testChannel), "an empty queue posts no digest")
+}
+
+func TestTriage_DocumentsConfidentMessagesRightAway(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	triagedProject(t, h)
+
+	msg := h.post(t, "We decided to move billing to Postgres")
+	require.NoError(t, h.bot.ProcessMessage(ctx, msg))
+
+	assert.Len(t, documents(h.github), 1)
+	pending, err := h.triage.Pending(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
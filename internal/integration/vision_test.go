@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVision describes images by their contents, like a vision model reading screenshots
+type fakeVision struct {
+	mu           sync.Mutex
+	descriptions map[string]string
+}
+
+func (v *fakeVision) DescribeImage(ctx context.Context, mimeType string, image []byte) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	description, ok := v.descriptions[string(image)]
+	if !ok {
+		return "", fmt.Errorf("unsupported image")
+	}
+	return description, nil
+}
+
+func (c *fakeChat) FetchAttachment(ctx context.Context, attachment *domain.Attachment) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.files[attachment.URL()]
+	if !ok {
+		return nil, fmt.Errorf("file not found")
+	}
+	return data, nil
+}
+
+// share attaches an image to a message as the chat provider would, serving its contents
+func (h *harness) share(t testing.TB, msg *domain.Message, name string, data []byte) {
+	t.Helper()
+	url := "https://files.example.com/" + name
+	attachment, err := domain.NewAttachment(name, "image/png", url)
+	require.NoError(t, err)
+	msg.AddAttachments(attachment)
+
+	h.chat.mu.Lock()
+	defer h.chat.mu.Unlock()
+	if data != nil {
+		h.chat.files[url] = data
+	}
+}
+
+func TestProcessMessage_StoresDescribedImages(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	h.vision.descriptions["whiteboard"] = "Diagram: billing writes to Postgres"
+	msg := h.post(t, "We decided to use Postgres for billing, see the whiteboard")
+	h.share(t, msg, "whiteboard.png", []byte("whiteboard"))
+	h.share(t, msg, "blurry.png", []byte("blurry"))
+	h.share(t, msg, "deleted.png", nil)
+
+	require.NoError(t, h.bot.ProcessMessage(context.Background(), msg))
+
+	docs := documents(h.github)
+	require.Len(t, docs, 1)
+	content, ok := h.github.file(docs[0])
+	require.True(t, ok)
+
+	whiteboard := domain.AssetPath([]byte("whiteboard"), "whiteboard.png")
+	assert.Contains(t, content, "## Images\n\n![whiteboard.png](../"+strings.TrimPrefix(whiteboard, "docs/")+")\n\n> Diagram: billing writes to Postgres\n")
+	assert.Contains(t, content, "![blurry.png](../"+strings.TrimPrefix(domain.AssetPath([]byte("blurry"), "blurry.png"), "docs/")+")")
+	assert.NotContains(t, content, "deleted.png")
+
+	image, ok := h.github.file(whiteboard)
+	require.True(t, ok)
+	assert.Equal(t, "whiteboard", image)
+	assert.Equal(t, domain.MessageStateDocumented, h.stored(t, msg).State())
+}
+
+func TestProcessMessage_StoresSharedImageOnce(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	h.vision.descriptions["whiteboard"] = "Diagram: billing writes to Postgres"
+
+	first := h.post(t, "We decided to use Postgres for billing, see the whiteboard")
+	h.share(t, first, "whiteboard.png", []byte("whiteboard"))
+	require.NoError(t, h.bot.ProcessMessage(ctx, first))
+	model.mu.Lock()
+	model.responses[operationTitle] = "Keep invoices in Postgres"
+	model.mu.Unlock()
+	second := h.post(t, "We decided to keep invoices in Postgres, same whiteboard")
+	h.share(t, second, "board-photo.png", []byte("whiteboard"))
+	require.NoError(t, h.bot.ProcessMessage(ctx, second))
+
+	docs := documents(h.github)
+	require.Len(t, docs, 2)
+	whiteboard := domain.AssetPath([]byte("whiteboard"), "whiteboard.png")
+	for _, doc := range docs {
+		content, ok := h.github.file(doc)
+		require.True(t, ok)
+		assert.Contains(t, content, strings.TrimPrefix(whiteboard, "docs/"))
+	}
+	var assets []string
+	for _, path := range h.github.paths() {
+		if strings.HasPrefix(path, domain.AssetsDir+"/") {
+			assets = append(assets, path)
+		}
+	}
+	assert.Equal(t, []string{whiteboard}, assets)
+}
@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWiki serves a fixed set of pages, like a Notion workspace
+type fakeWiki struct {
+	pages []*domain.WikiPage
+}
+
+func (w *fakeWiki) add(t testing.TB, id, parentID, title, body string) *domain.WikiPage {
+	t.Helper()
+	page, err := domain.NewWikiPage(domain.WikiSourceNotion, id, parentID, title, body)
+	require.NoError(t, err)
+	w.pages = append(w.pages, page)
+	return page
+}
+
+func (w *fakeWiki) Source() domain.WikiSource {
+	return domain.WikiSourceNotion
+}
+
+func (w *fakeWiki) Pages(ctx context.Context) ([]*domain.WikiPage, error) {
+	return w.pages, nil
+}
+
+func TestWikiImport_StoresPagesWithTheirHierarchy(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	ctx := context.Background()
+	importer := services.NewWikiImportService(h.github.store(t))
+
+	wiki := &fakeWiki{}
+	handbook := wiki.add(t, "handbook", "", "Engineering Handbook", "Start here.")
+	handbook.SetAuthor("Alice")
+	handbook.SetLabels([]string{"onboarding"})
+	wiki.add(t, "setup", "handbook", "Laptop Setup", "Install Go and Docker.")
+
+	report, err := importer.Import(ctx, wiki, domain.CategoryDevelopment)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Pages)
+	assert.Equal(t, []string{
+		"docs/development/engineering-handbook.md",
+		"docs/development/engineering-handbook/laptop-setup.md",
+	}, report.Created)
+	assert.Empty(t, report.Failures)
+
+	fm := frontMatterOf(t, h, "docs/development/engineering-handbook/laptop-setup.md")
+	assert.Equal(t, "notion", fm.Get("imported_from"))
+	assert.Equal(t, "setup", fm.Get("import_id"))
+	assert.Equal(t, "docs/development/engineering-handbook.md", fm.Get("parent"))
+	assert.Equal(t, "Alice", frontMatterOf(t, h, "docs/development/engineering-handbook.md").Get("author"))
+
+	// Reconciliation indexes the imported documents like the ones people write in the repository
+	_, err = h.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	entry, err := h.index.FindByPath(ctx, "docs/development/engineering-handbook.md")
+	require.NoError(t, err)
+	assert.Equal(t, "Engineering Handbook", entry.Title())
+	assert.True(t, entry.HasTag("onboarding"))
+
+	// Importing again updates the pages edited since and leaves the others alone
+	wiki.pages[1], err = domain.NewWikiPage(domain.WikiSourceNotion, "setup", "handbook", "Laptop Setup", "Install Go, Docker and Make.")
+	require.NoError(t, err)
+	report, err = importer.Import(ctx, wiki, domain.CategoryDevelopment)
+	require.NoError(t, err)
+	assert.Empty(t, report.Created)
+	assert.Equal(t, []string{"docs/development/engineering-handbook/laptop-setup.md"}, report.Updated)
+	assert.Equal(t, 1, report.Unchanged)
+	content, ok := h.github.file("docs/development/engineering-handbook/laptop-setup.md")
+	require.True(t, ok)
+	assert.Contains(t, content, "Install Go, Docker and Make.")
+}
+
+func TestWikiImport_KeepsDocumentsPeopleWrote(t *testing.T) {
+	model := newFakeModel(domain.MessageTypeDecision, domain.CategoryDevelopment)
+	h := newHarness(t, model.ollamaProvider(t))
+	h.github.edit("docs/other/release-process.md", "# Release Process\n\nWritten by hand.\n")
+
+	wiki := &fakeWiki{}
+	wiki.add(t, "release", "", "Release Process", "Copied from Notion.")
+	wiki.add(t, "oncall", "", "On-call", "Page the primary.")
+
+	report, err := services.NewWikiImportService(h.github.store(t)).Import(context.Background(), wiki, domain.CategoryOther)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"docs/other/on-call.md"}, report.Created)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, "release", report.Failures[0].PageID)
+	content, ok := h.github.file("docs/other/release-process.md")
+	require.True(t, ok)
+	assert.Contains(t, content, "Written by hand.")
+}
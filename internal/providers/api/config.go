@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingTokens       = errors.New("at least one API token is required")
+	ErrInvalidOverrideRate = errors.New("override rate must be between 0 and 1")
+	ErrBlankFeedToken      = errors.New("feed tokens cannot be blank")
+)
+
+// Config contains the settings of the REST API
+type Config struct {
+	// Tokens lists the accepted bearer tokens
+	Tokens []string
+
+	// FeedTokens lists the tokens feed readers pass in the token query parameter of the feed URLs, since most
+	// cannot send headers. They only read feeds.
+	FeedTokens []string
+
+	// TopContributors limits how many contributors the stats list (default: 10)
+	TopContributors int
+
+	// MaxOverrideRate is the share of corrected analyses the suggested confidence thresholds allow (default: 0.1)
+	MaxOverrideRate float64
+
+	// FeedSize is how many documents a feed lists (default: 50)
+	FeedSize int
+
+	// DocumentLinkBase is the URL links between rendered documents point at, followed by the path of the linked
+	// document, like the documents endpoint or a dashboard page (default: /documents/)
+	DocumentLinkBase string
+
+	// EventKeepAlive is how often the event stream sends a comment when no event happens, so proxies keep the
+	// connection open (default: 15s)
+	EventKeepAlive time.Duration
+}
+
+const (
+	// DefaultTopContributors is how many contributors the stats list when not configured
+	DefaultTopContributors = 10
+	// DefaultMaxOverrideRate is the share of corrected analyses the suggested confidence thresholds allow
+	DefaultMaxOverrideRate = 0.1
+	// DefaultDocumentLinkBase makes links between rendered documents point at the documents endpoint
+	DefaultDocumentLinkBase = "/documents/"
+	// DefaultEventKeepAlive is how often the event stream sends a comment when no event happens
+	DefaultEventKeepAlive = 15 * time.Second
+)
+
+// NewConfig creates a new API configuration accepting a single token
+func NewConfig(token string) *Config {
+	return &Config{
+		Tokens:          []string{token},
+		TopContributors: DefaultTopContributors,
+		MaxOverrideRate: DefaultMaxOverrideRate,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if len(c.Tokens) == 0 {
+		return ErrMissingTokens
+	}
+	for _, token := range c.Tokens {
+		if strings.TrimSpace(token) == "" {
+			return ErrMissingTokens
+		}
+	}
+	for _, token := range c.FeedTokens {
+		if strings.TrimSpace(token) == "" {
+			return ErrBlankFeedToken
+		}
+	}
+	if c.MaxOverrideRate < 0 || c.MaxOverrideRate > 1 {
+		return ErrInvalidOverrideRate
+	}
+	return nil
+}
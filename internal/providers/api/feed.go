@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// jsonFeedVersion is the version of the JSON Feed format the feeds follow
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// atomFeed is an Atom feed, see RFC 4287
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   atomPerson  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+// JSONFeed is a feed in the JSON Feed format, see https://jsonfeed.org/version/1.1
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+// JSONFeedItem is a document of a JSONFeed
+type JSONFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary,omitempty"`
+	ContentText   string   `json:"content_text"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// newAtomFeed renders a feed in Atom, its entries linking to the documents under linkBase
+func newAtomFeed(feed *domain.DocumentFeed, feedURL, linkBase string) atomFeed {
+	project := feed.Project()
+	atom := atomFeed{
+		ID:       feedURL,
+		Title:    feedTitle(project),
+		Subtitle: project.Description(),
+		Updated:  feedTime(feed.UpdatedAt()),
+		Author:   atomPerson{Name: "Quill"},
+		Links:    []atomLink{{Rel: "self", Type: "application/atom+xml", Href: feedURL}},
+		Entries:  []atomEntry{},
+	}
+	for _, doc := range feed.Documents() {
+		link := feedDocumentLink(linkBase, doc.Path())
+		categories := []atomCategory{{Term: doc.Type().String()}, {Term: doc.Category().String()}}
+		for _, tag := range doc.Tags() {
+			categories = append(categories, atomCategory{Term: tag.String()})
+		}
+		atom.Entries = append(atom.Entries, atomEntry{
+			ID:         link,
+			Title:      doc.Title(),
+			Links:      []atomLink{{Rel: "alternate", Href: link}},
+			Published:  feedTime(doc.CreatedAt()),
+			Updated:    feedTime(doc.UpdatedAt()),
+			Summary:    doc.Summary(),
+			Categories: categories,
+		})
+	}
+	return atom
+}
+
+// newJSONFeed renders a feed in the JSON Feed format, its items linking to the documents under linkBase
+func newJSONFeed(feed *domain.DocumentFeed, feedURL, linkBase string) JSONFeed {
+	project := feed.Project()
+	jsonFeed := JSONFeed{
+		Version:     jsonFeedVersion,
+		Title:       feedTitle(project),
+		Description: project.Description(),
+		FeedURL:     feedURL,
+		Items:       []JSONFeedItem{},
+	}
+	for _, doc := range feed.Documents() {
+		link := feedDocumentLink(linkBase, doc.Path())
+		tags := []string{doc.Type().String(), doc.Category().String()}
+		for _, tag := range doc.Tags() {
+			tags = append(tags, tag.String())
+		}
+		content := doc.Summary()
+		if content == "" {
+			content = doc.Title()
+		}
+		jsonFeed.Items = append(jsonFeed.Items, JSONFeedItem{
+			ID:            link,
+			URL:           link,
+			Title:         doc.Title(),
+			Summary:       doc.Summary(),
+			ContentText:   content,
+			DatePublished: feedTime(doc.CreatedAt()),
+			DateModified:  feedTime(doc.UpdatedAt()),
+			Tags:          tags,
+		})
+	}
+	return jsonFeed
+}
+
+// feedTitle returns the title of the feed of a project
+func feedTitle(project *domain.Project) string {
+	return project.Name() + " documentation"
+}
+
+// feedTime formats a time of a feed as RFC 3339, which both Atom and JSON Feed use
+func feedTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// feedDocumentLink returns the link of a document in a feed, rendered to HTML when it is Markdown
+func feedDocumentLink(linkBase, docPath string) string {
+	link := linkBase + (&url.URL{Path: docPath}).EscapedPath()
+	if path.Ext(docPath) == ".md" {
+		link += "?format=html"
+	}
+	return link
+}
+
+// absoluteURL resolves a URL of the API against the address a request was sent to, feed readers need
+// absolute links. Behind a proxy, X-Forwarded-Proto tells the scheme.
+func absoluteURL(r *http.Request, ref string) string {
+	scheme := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	base := &url.URL{Scheme: scheme, Host: r.Host, Path: "/"}
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return resolved.String()
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"html"
+	"strings"
+)
+
+// codeSyntax is what the highlighter needs to know about a language
+type codeSyntax struct {
+	keywords      map[string]bool
+	lineComments  []string
+	blockComment  [2]string
+	quotes        string
+	caseSensitive bool
+}
+
+func keywords(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+var (
+	cSyntax = codeSyntax{
+		lineComments:  []string{"//"},
+		blockComment:  [2]string{"/*", "*/"},
+		quotes:        "\"'`",
+		caseSensitive: true,
+	}
+	goSyntax = withKeywords(cSyntax, `break case chan const continue default defer else fallthrough for func go goto if
+		import interface map package range return select struct switch type var nil true false iota`)
+	jsSyntax = withKeywords(cSyntax, `async await break case catch class const continue debugger default delete do else
+		export extends finally for from function if import in instanceof interface let new null of return static super
+		switch this throw true false try type typeof undefined var void while yield`)
+	javaSyntax = withKeywords(cSyntax, `abstract boolean break case catch class const continue default do double else enum
+		extends final finally float for if implements import instanceof int interface long new null package private
+		protected public return static super switch this throw throws true false try void while`)
+	rustSyntax = withKeywords(cSyntax, `as async await break const continue crate else enum extern false fn for if impl in
+		let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while`)
+	pythonSyntax = codeSyntax{
+		keywords: keywords(`and as assert async await break class continue def del elif else except False finally for
+			from global if import in is lambda None nonlocal not or pass raise return True try while with yield`),
+		lineComments:  []string{"#"},
+		quotes:        "\"'",
+		caseSensitive: true,
+	}
+	shellSyntax = codeSyntax{
+		keywords:      keywords(`case do done elif else esac export fi for function if in local return then until while`),
+		lineComments:  []string{"#"},
+		quotes:        "\"'",
+		caseSensitive: true,
+	}
+	sqlSyntax = codeSyntax{
+		keywords: keywords(`add all alter and as asc begin between by case check column commit constraint create default
+			delete desc distinct drop else end exists foreign from group having in index inner insert into is join key
+			left like limit not null on or order outer primary references right rollback select set table then union
+			unique update values view when where with`),
+		lineComments: []string{"--"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "'\"",
+	}
+	yamlSyntax = codeSyntax{
+		keywords:      keywords(`true false null yes no on off`),
+		lineComments:  []string{"#"},
+		quotes:        "\"'",
+		caseSensitive: true,
+	}
+	jsonSyntax = codeSyntax{
+		keywords:      keywords(`true false null`),
+		quotes:        "\"",
+		caseSensitive: true,
+	}
+)
+
+func withKeywords(syntax codeSyntax, words string) codeSyntax {
+	syntax.keywords = keywords(words)
+	return syntax
+}
+
+// syntaxes maps the names code blocks are tagged with to the syntax of their language
+var syntaxes = map[string]codeSyntax{
+	"go":         goSyntax,
+	"golang":     goSyntax,
+	"js":         jsSyntax,
+	"javascript": jsSyntax,
+	"ts":         jsSyntax,
+	"typescript": jsSyntax,
+	"java":       javaSyntax,
+	"kotlin":     javaSyntax,
+	"c":          javaSyntax,
+	"cpp":        javaSyntax,
+	"c++":        javaSyntax,
+	"cs":         javaSyntax,
+	"csharp":     javaSyntax,
+	"rust":       rustSyntax,
+	"rs":         rustSyntax,
+	"python":     pythonSyntax,
+	"py":         pythonSyntax,
+	"bash":       shellSyntax,
+	"sh":         shellSyntax,
+	"shell":      shellSyntax,
+	"zsh":        shellSyntax,
+	"sql":        sqlSyntax,
+	"yaml":       yamlSyntax,
+	"yml":        yamlSyntax,
+	"json":       jsonSyntax,
+}
+
+// highlightCode escapes code and wraps its keywords, strings, numbers and comments in spans of the classes
+// hl-keyword, hl-string, hl-number and hl-comment. Code of languages it does not know is only escaped.
+func highlightCode(lang, code string) string {
+	syntax, ok := syntaxes[lang]
+	if !ok {
+		return html.EscapeString(code)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(code); {
+		if end := syntax.comment(code, i); end > i {
+			span(&b, "hl-comment", code[i:end])
+			i = end
+			continue
+		}
+		c := code[i]
+		switch {
+		case strings.IndexByte(syntax.quotes, c) >= 0:
+			end := quoted(code, i)
+			span(&b, "hl-string", code[i:end])
+			i = end
+		case isDigit(c) && (i == 0 || !isWordByte(code[i-1])):
+			end := i + 1
+			for end < len(code) && (isWordByte(code[end]) || code[end] == '.') {
+				end++
+			}
+			span(&b, "hl-number", code[i:end])
+			i = end
+		case isWordByte(c):
+			end := i + 1
+			for end < len(code) && isWordByte(code[end]) {
+				end++
+			}
+			word := code[i:end]
+			if !syntax.caseSensitive {
+				word = strings.ToLower(word)
+			}
+			if syntax.keywords[word] {
+				span(&b, "hl-keyword", code[i:end])
+			} else {
+				b.WriteString(html.EscapeString(code[i:end]))
+			}
+			i = end
+		default:
+			b.WriteString(html.EscapeString(code[i : i+1]))
+			i++
+		}
+	}
+	return b.String()
+}
+
+// comment returns where the comment starting at code[i] ends, i when none starts there
+func (s codeSyntax) comment(code string, i int) int {
+	if open := s.blockComment[0]; open != "" && strings.HasPrefix(code[i:], open) {
+		end := strings.Index(code[i+len(open):], s.blockComment[1])
+		if end < 0 {
+			return len(code)
+		}
+		return i + len(open) + end + len(s.blockComment[1])
+	}
+	for _, prefix := range s.lineComments {
+		if !strings.HasPrefix(code[i:], prefix) {
+			continue
+		}
+		// Shell and YAML comments start words, like in echo "a" # comment, but not a#b
+		if prefix == "#" && i > 0 && !strings.ContainsRune(" \t\n", rune(code[i-1])) {
+			continue
+		}
+		if end := strings.IndexByte(code[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(code)
+	}
+	return i
+}
+
+// quoted returns where the string opened by the quote at code[i] ends. Strings end at the line, except
+// backtick strings.
+func quoted(code string, i int) int {
+	quote := code[i]
+	for j := i + 1; j < len(code); j++ {
+		switch {
+		case code[j] == '\\' && quote != '`':
+			j++
+		case code[j] == quote:
+			return j + 1
+		case code[j] == '\n' && quote != '`':
+			return j
+		}
+	}
+	return len(code)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func span(b *strings.Builder, class, text string) {
+	b.WriteString(`<span class="` + class + `">` + html.EscapeString(text) + `</span>`)
+}
@@ -0,0 +1,713 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+var (
+	// orderedMarkerPattern matches the marker of an ordered list item, like "1." or "2)"
+	orderedMarkerPattern = regexp.MustCompile(`^(\d{1,9})[.)]( +|$)`)
+	// delimiterCellPattern matches a cell of the row separating the header of a table from its body
+	delimiterCellPattern = regexp.MustCompile(`^:?-+:?$`)
+)
+
+// asciiPunctuation are the characters a backslash escapes
+const asciiPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// htmlRenderer renders the Markdown of a stored document to HTML that is safe to embed in a page: raw HTML is
+// escaped, links and images keep web and relative URLs only, and relative links to other documents point at
+// the documents endpoint
+type htmlRenderer struct {
+	docPath  string
+	linkBase string
+	// tight renders paragraphs without <p>, for the items of lists without blank lines between them
+	tight bool
+	ids   map[string]int
+}
+
+// renderHTML renders a Markdown document without its front matter. Relative links are resolved against the
+// document's directory and prefixed with linkBase.
+func renderHTML(docPath, markdown, linkBase string) string {
+	_, body, err := domain.ParseFrontMatter(markdown)
+	if err != nil {
+		body = markdown
+	}
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+
+	r := &htmlRenderer{docPath: docPath, linkBase: linkBase, ids: make(map[string]int)}
+	var b strings.Builder
+	r.blocks(&b, strings.Split(body, "\n"))
+	return b.String()
+}
+
+// blocks renders block elements: headings, paragraphs, lists, quotes, tables, code blocks and rules
+func (r *htmlRenderer) blocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case trimmed == "":
+			i++
+		case fenceOpening(trimmed) != "":
+			i = r.fence(b, lines, i)
+		case headingLevel(trimmed) > 0:
+			r.heading(b, trimmed)
+			i++
+		case isRule(trimmed):
+			b.WriteString("<hr>\n")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			i = r.quote(b, lines, i)
+		case listItemAt(lines[i]) != nil:
+			i = r.list(b, lines, i)
+		case isTableAt(lines, i):
+			i = r.table(b, lines, i)
+		default:
+			i = r.paragraph(b, lines, i)
+		}
+	}
+}
+
+// fenceOpening returns the backticks or tildes opening a fenced code block, empty when the line opens none
+func fenceOpening(trimmed string) string {
+	for _, fence := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == fence {
+			n++
+		}
+		if n >= 3 && (fence == '~' || !strings.Contains(trimmed[n:], "`")) {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
+
+// fence renders a fenced code block, highlighted by the language of its info string. Mermaid diagrams are
+// left for the page to draw.
+func (r *htmlRenderer) fence(b *strings.Builder, lines []string, start int) int {
+	opening := strings.TrimSpace(lines[start])
+	fence := fenceOpening(opening)
+	indent := len(lines[start]) - len(strings.TrimLeft(lines[start], " "))
+	lang := ""
+	if fields := strings.Fields(opening[len(fence):]); len(fields) > 0 {
+		lang = codeLanguage(fields[0])
+	}
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		closing := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+
+	text := strings.Join(code, "\n")
+	if len(code) > 0 {
+		text += "\n"
+	}
+	switch lang {
+	case "mermaid":
+		fmt.Fprintf(b, "<pre class=\"mermaid\">%s</pre>\n", html.EscapeString(text))
+	case "":
+		fmt.Fprintf(b, "<pre><code>%s</code></pre>\n", html.EscapeString(text))
+	default:
+		fmt.Fprintf(b, "<pre><code class=\"language-%s\">%s</code></pre>\n", lang, highlightCode(lang, text))
+	}
+	return i
+}
+
+// codeLanguage keeps the characters of a language name that are safe in a class name
+func codeLanguage(info string) string {
+	return strings.Map(func(c rune) rune {
+		if c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("+#-_", c)) {
+			return unicode.ToLower(c)
+		}
+		return -1
+	}, info)
+}
+
+// headingLevel returns the level of an ATX heading, 0 when the line is not one
+func headingLevel(trimmed string) int {
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// heading renders a heading with an id, so anchors of links to its section work
+func (r *htmlRenderer) heading(b *strings.Builder, trimmed string) {
+	level := headingLevel(trimmed)
+	text := strings.TrimSpace(trimmed[level:])
+	if closed := strings.TrimRight(text, "#"); closed == "" || strings.HasSuffix(closed, " ") {
+		text = strings.TrimSpace(closed)
+	}
+	fmt.Fprintf(b, "<h%d id=\"%s\">%s</h%d>\n", level, r.headingID(text), r.inline(text), level)
+}
+
+// headingID derives the anchor of a heading as GitHub does: lowercase words joined by hyphens, numbered
+// when several headings share it
+func (r *htmlRenderer) headingID(text string) string {
+	id := strings.Map(func(c rune) rune {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '-' || c == '_':
+			return unicode.ToLower(c)
+		case c == ' ':
+			return '-'
+		}
+		return -1
+	}, text)
+	seen := r.ids[id]
+	r.ids[id]++
+	if seen > 0 {
+		id = fmt.Sprintf("%s-%d", id, seen)
+	}
+	return html.EscapeString(id)
+}
+
+// isRule checks if a line is a thematic break, three or more -, * or _ with optional spaces
+func isRule(trimmed string) bool {
+	compact := strings.ReplaceAll(trimmed, " ", "")
+	if len(compact) < 3 {
+		return false
+	}
+	return strings.Trim(compact, compact[:1]) == "" && strings.Contains("-*_", compact[:1])
+}
+
+// quote renders consecutive lines starting with > as a block quote of their own blocks
+func (r *htmlRenderer) quote(b *strings.Builder, lines []string, start int) int {
+	var inner []string
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		line := strings.TrimPrefix(trimmed, ">")
+		inner = append(inner, strings.TrimPrefix(line, " "))
+	}
+
+	tight := r.tight
+	r.tight = false
+	b.WriteString("<blockquote>\n")
+	r.blocks(b, inner)
+	b.WriteString("</blockquote>\n")
+	r.tight = tight
+	return i
+}
+
+// listItem is the marker starting an item of a list
+type listItem struct {
+	ordered bool
+	start   int
+	// indent is where the content of the item starts, continuation lines are indented as far
+	indent  int
+	content string
+}
+
+// listItemAt reads the list marker starting a line, nil when the line starts no list item
+func listItemAt(line string) *listItem {
+	trimmed := strings.TrimLeft(line, " ")
+	lead := len(line) - len(trimmed)
+	if len(trimmed) > 0 && strings.ContainsRune("-*+", rune(trimmed[0])) && (len(trimmed) == 1 || trimmed[1] == ' ') {
+		if isRule(trimmed) {
+			return nil
+		}
+		content := strings.TrimLeft(trimmed[1:], " ")
+		return &listItem{indent: lead + min(len(trimmed)-len(content), 5), content: content}
+	}
+	if match := orderedMarkerPattern.FindStringSubmatch(trimmed); match != nil {
+		start, _ := strconv.Atoi(match[1])
+		return &listItem{ordered: true, start: start, indent: lead + min(len(match[0]), len(match[1])+5), content: trimmed[len(match[0]):]}
+	}
+	return nil
+}
+
+// list renders the items of a list, each item's lines rendered as blocks of their own so lists can nest
+func (r *htmlRenderer) list(b *strings.Builder, lines []string, start int) int {
+	first := listItemAt(lines[start])
+	var items [][]string
+	loose := false
+	i := start
+	for i < len(lines) {
+		item := listItemAt(lines[i])
+		if item == nil || item.ordered != first.ordered {
+			break
+		}
+		content := []string{item.content}
+		blank := false
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if strings.TrimSpace(line) == "" {
+				blank = true
+				content = append(content, "")
+				continue
+			}
+			if indent >= item.indent {
+				line = line[item.indent:]
+			} else if blank || listItemAt(line) != nil || startsBlock(lines, i) {
+				break
+			} else {
+				// A lazy continuation of the item's paragraph
+				line = strings.TrimSpace(line)
+			}
+			if blank {
+				loose = true
+				blank = false
+			}
+			content = append(content, line)
+		}
+
+		for len(content) > 0 && content[len(content)-1] == "" {
+			content = content[:len(content)-1]
+		}
+		items = append(items, content)
+		if next := listItemAt(lineAt(lines, i)); blank && next != nil && next.ordered == first.ordered {
+			loose = true
+		}
+	}
+
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	if first.ordered && first.start != 1 {
+		fmt.Fprintf(b, "<ol start=\"%d\">\n", first.start)
+	} else {
+		fmt.Fprintf(b, "<%s>\n", tag)
+	}
+	tight := r.tight
+	r.tight = !loose
+	for _, content := range items {
+		b.WriteString("<li>")
+		content[0] = r.taskBox(b, content[0])
+		var inner strings.Builder
+		r.blocks(&inner, content)
+		if loose {
+			b.WriteString("\n" + inner.String())
+		} else {
+			b.WriteString(strings.TrimSuffix(inner.String(), "\n"))
+		}
+		b.WriteString("</li>\n")
+	}
+	r.tight = tight
+	fmt.Fprintf(b, "</%s>\n", tag)
+	return i
+}
+
+// taskBox renders the checkbox of a task list item and returns the item without it
+func (r *htmlRenderer) taskBox(b *strings.Builder, line string) string {
+	switch {
+	case strings.HasPrefix(line, "[ ] "):
+		b.WriteString(`<input type="checkbox" disabled> `)
+	case strings.HasPrefix(line, "[x] "), strings.HasPrefix(line, "[X] "):
+		b.WriteString(`<input type="checkbox" checked disabled> `)
+	default:
+		return line
+	}
+	return line[4:]
+}
+
+// lineAt returns the line at i, empty past the last line
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+// startsBlock checks if a line starts a block that interrupts a paragraph
+func startsBlock(lines []string, i int) bool {
+	trimmed := strings.TrimSpace(lines[i])
+	if fenceOpening(trimmed) != "" || headingLevel(trimmed) > 0 || isRule(trimmed) || strings.HasPrefix(trimmed, ">") {
+		return true
+	}
+	if item := listItemAt(lines[i]); item != nil && item.content != "" && (!item.ordered || item.start == 1) {
+		return true
+	}
+	return isTableAt(lines, i)
+}
+
+// isTableAt checks if a table starts at a line: a row of cells followed by a delimiter row as wide
+func isTableAt(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") {
+		return false
+	}
+	delimiters := tableCells(lines[i+1])
+	if len(delimiters) == 0 || len(delimiters) != len(tableCells(lines[i])) {
+		return false
+	}
+	for _, cell := range delimiters {
+		if !delimiterCellPattern.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// tableCells splits a table row into its trimmed cells, escaped pipes stay in their cell
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// table renders a table, aligned as its delimiter row says
+func (r *htmlRenderer) table(b *strings.Builder, lines []string, start int) int {
+	header := tableCells(lines[start])
+	aligns := make([]string, len(header))
+	for n, cell := range tableCells(lines[start+1]) {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			aligns[n] = "center"
+		case strings.HasSuffix(cell, ":"):
+			aligns[n] = "right"
+		case strings.HasPrefix(cell, ":"):
+			aligns[n] = "left"
+		}
+	}
+
+	b.WriteString("<table>\n<thead>\n")
+	r.tableRow(b, "th", header, aligns)
+	b.WriteString("</thead>\n")
+	i := start + 2
+	if i < len(lines) && strings.Contains(lines[i], "|") {
+		b.WriteString("<tbody>\n")
+		for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|"); i++ {
+			r.tableRow(b, "td", tableCells(lines[i]), aligns)
+		}
+		b.WriteString("</tbody>\n")
+	}
+	b.WriteString("</table>\n")
+	return i
+}
+
+// tableRow renders a row with as many cells as the header, missing cells left empty
+func (r *htmlRenderer) tableRow(b *strings.Builder, tag string, cells, aligns []string) {
+	b.WriteString("<tr>\n")
+	for n, align := range aligns {
+		cell := ""
+		if n < len(cells) {
+			cell = cells[n]
+		}
+		if align != "" {
+			fmt.Fprintf(b, "<%s style=\"text-align: %s\">%s</%s>\n", tag, align, r.inline(cell), tag)
+		} else {
+			fmt.Fprintf(b, "<%s>%s</%s>\n", tag, r.inline(cell), tag)
+		}
+	}
+	b.WriteString("</tr>\n")
+}
+
+// paragraph renders the lines up to a blank line or the start of another block as a paragraph
+func (r *htmlRenderer) paragraph(b *strings.Builder, lines []string, start int) int {
+	text := []string{strings.TrimLeft(lines[start], " ")}
+	i := start + 1
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines, i); i++ {
+		text = append(text, strings.TrimLeft(lines[i], " "))
+	}
+
+	content := r.inline(strings.TrimRight(strings.Join(text, "\n"), " "))
+	if r.tight {
+		b.WriteString(content + "\n")
+	} else {
+		b.WriteString("<p>" + content + "</p>\n")
+	}
+	return i
+}
+
+// inline renders the text of a block: emphasis, code spans, links, images and line breaks
+func (r *htmlRenderer) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if rendered, next, ok := r.inlineElement(s, i); ok {
+			b.WriteString(rendered)
+			i = next
+			continue
+		}
+		// Plain text up to the next character that may start an element
+		j := i + 1
+		for j < len(s) && !strings.ContainsRune("\\`![<*_~ ", rune(s[j])) {
+			j++
+		}
+		b.WriteString(html.EscapeString(s[i:j]))
+		i = j
+	}
+	return b.String()
+}
+
+// inlineElement renders the element starting at s[i], false when none does
+func (r *htmlRenderer) inlineElement(s string, i int) (string, int, bool) {
+	switch s[i] {
+	case '\\':
+		if i+1 < len(s) && s[i+1] == '\n' {
+			return "<br>\n", i + 2, true
+		}
+		if i+1 < len(s) && strings.IndexByte(asciiPunctuation, s[i+1]) >= 0 {
+			return html.EscapeString(s[i+1 : i+2]), i + 2, true
+		}
+	case '`':
+		return codeSpan(s, i)
+	case '!':
+		if i+1 < len(s) && s[i+1] == '[' {
+			return r.link(s, i+1, true)
+		}
+	case '[':
+		return r.link(s, i, false)
+	case '<':
+		return r.autolink(s, i)
+	case '*', '_', '~':
+		return r.emphasis(s, i)
+	case ' ':
+		// Two spaces ending a line break it
+		j := i
+		for j < len(s) && s[j] == ' ' {
+			j++
+		}
+		if j-i >= 2 && j < len(s) && s[j] == '\n' {
+			return "<br>\n", j + 1, true
+		}
+	}
+	return "", i, false
+}
+
+// codeSpan renders text between runs of as many backticks
+func codeSpan(s string, i int) (string, int, bool) {
+	n := runLength(s, i)
+	for j := i + n; j < len(s); {
+		k := strings.IndexByte(s[j:], '`')
+		if k < 0 {
+			break
+		}
+		j += k
+		if m := runLength(s, j); m != n {
+			j += m
+			continue
+		}
+		code := strings.ReplaceAll(s[i+n:j], "\n", " ")
+		if len(code) > 2 && strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") {
+			code = code[1 : len(code)-1]
+		}
+		return "<code>" + html.EscapeString(code) + "</code>", j + n, true
+	}
+	// An unclosed run is text
+	return html.EscapeString(s[i : i+n]), i + n, true
+}
+
+// runLength counts the repetitions of the character at s[i]
+func runLength(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+// link renders [text](destination "title"), or an image when it started with !
+func (r *htmlRenderer) link(s string, open int, image bool) (string, int, bool) {
+	closing := -1
+	depth := 0
+	for j := open; j < len(s) && closing < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				closing = j
+			}
+		}
+	}
+	if closing < 0 || closing+1 >= len(s) || s[closing+1] != '(' {
+		return "", open, false
+	}
+
+	// The destination may hold balanced parentheses, an optional title follows it
+	end := -1
+	depth = 0
+	for j := closing + 1; j < len(s) && end < 0; j++ {
+		switch s[j] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				end = j
+			}
+		case '\n':
+			return "", open, false
+		}
+	}
+	if end < 0 {
+		return "", open, false
+	}
+	dest, title := strings.TrimSpace(s[closing+2:end]), ""
+	if q := strings.IndexAny(dest, " \t"); q >= 0 {
+		dest, title = dest[:q], strings.Trim(strings.TrimSpace(dest[q:]), `"'`)
+	}
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	text := s[open+1 : closing]
+
+	attrs := ""
+	if title != "" {
+		attrs = fmt.Sprintf(" title=\"%s\"", html.EscapeString(title))
+	}
+	href := r.href(dest)
+	switch {
+	case image && href == "":
+		return html.EscapeString(text), end + 1, true
+	case image:
+		return fmt.Sprintf("<img src=\"%s\" alt=\"%s\"%s>", html.EscapeString(href), html.EscapeString(text), attrs), end + 1, true
+	case href == "":
+		return r.inline(text), end + 1, true
+	}
+	if external(dest) {
+		attrs += ` rel="noopener noreferrer"`
+	}
+	return fmt.Sprintf("<a href=\"%s\"%s>%s</a>", html.EscapeString(href), attrs, r.inline(text)), end + 1, true
+}
+
+// autolink renders <https://example.com> and <someone@example.com> as links, other angle brackets are text
+func (r *htmlRenderer) autolink(s string, i int) (string, int, bool) {
+	end := strings.IndexByte(s[i:], '>')
+	if end < 0 {
+		return "", i, false
+	}
+	target := s[i+1 : i+end]
+	if strings.ContainsAny(target, " \n<") {
+		return "", i, false
+	}
+	href := target
+	if !strings.Contains(target, ":") && strings.Contains(target, "@") {
+		href = "mailto:" + target
+	}
+	if !external(href) {
+		return "", i, false
+	}
+	return fmt.Sprintf("<a href=\"%s\" rel=\"noopener noreferrer\">%s</a>", html.EscapeString(href), html.EscapeString(target)), i + end + 1, true
+}
+
+// emphasis renders *em*, **strong** and ~~deleted~~ text, underscores only around whole words
+func (r *htmlRenderer) emphasis(s string, i int) (string, int, bool) {
+	c := s[i]
+	n := runLength(s, i)
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return html.EscapeString(s[i : i+n]), i + n, true
+	}
+	width, tag := 1, "em"
+	switch {
+	case c == '~' && n < 2:
+		return "", i, false
+	case c == '~':
+		width, tag = 2, "del"
+	case n >= 2:
+		width, tag = 2, "strong"
+	}
+	if i+width >= len(s) || s[i+width] == ' ' || s[i+width] == '\n' {
+		return "", i, false
+	}
+
+	for j := i + width; j < len(s); {
+		k := strings.IndexByte(s[j:], c)
+		if k < 0 {
+			break
+		}
+		j += k
+		m := runLength(s, j)
+		closes := m >= width && s[j-1] != ' ' && s[j-1] != '\n' && (width == 2 || m%2 == 1)
+		if c == '_' && j+m < len(s) && isWordByte(s[j+m]) {
+			closes = false
+		}
+		if closes && j+m-width > i+width {
+			closing := j + m - width
+			return "<" + tag + ">" + r.inline(s[i+width:closing]) + "</" + tag + ">", closing + width, true
+		}
+		j += m
+	}
+	return html.EscapeString(s[i : i+n]), i + n, true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// href makes a link destination safe. Web and mail links are kept, relative links to other documents are
+// resolved against the document's directory and point at the documents endpoint, other schemes are dropped.
+func (r *htmlRenderer) href(dest string) string {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return u.String()
+	case "":
+	default:
+		return ""
+	}
+	if u.Host != "" || u.Path == "" {
+		return u.String()
+	}
+
+	resolved := path.Join(path.Dir(r.docPath), u.Path)
+	if strings.HasPrefix(u.Path, "/") {
+		resolved = path.Clean(strings.TrimPrefix(u.Path, "/"))
+	}
+	link := r.linkBase + (&url.URL{Path: resolved}).EscapedPath()
+	if strings.HasSuffix(resolved, ".md") {
+		link += "?format=html"
+	}
+	if u.Fragment != "" {
+		link += "#" + u.EscapedFragment()
+	}
+	return link
+}
+
+// external checks if a link leaves the documentation
+func external(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "front matter, headings and paragraphs",
+			markdown: "---\ntype: decision\n---\n# Adopt Postgres\n\nThe team will use **Postgres** for *billing*,\nnot ~~MySQL~~.\n\n## Why? ##\n",
+			want:     "<h1 id=\"adopt-postgres\">Adopt Postgres</h1>\n<p>The team will use <strong>Postgres</strong> for <em>billing</em>,\nnot <del>MySQL</del>.</p>\n<h2 id=\"why\">Why?</h2>\n",
+		},
+		{
+			name:     "raw HTML is escaped",
+			markdown: "<script>alert(1)</script> & <b>bold</b>\n",
+			want:     "<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; &lt;b&gt;bold&lt;/b&gt;</p>\n",
+		},
+		{
+			name:     "links to other documents are resolved",
+			markdown: "See [the decision](../development/adopt-postgres.md#why), [the runbook](/docs/operations/failover.md) and [Postgres](https://www.postgresql.org).\n",
+			want: "<p>See <a href=\"/documents/docs/development/adopt-postgres.md?format=html#why\">the decision</a>, " +
+				"<a href=\"/documents/docs/operations/failover.md?format=html\">the runbook</a> and " +
+				"<a href=\"https://www.postgresql.org\" rel=\"noopener noreferrer\">Postgres</a>.</p>\n",
+		},
+		{
+			name:     "unsafe links keep their text only",
+			markdown: "[click](javascript:alert(1)) ![x](data:image/png;base64,AAAA) ![diagram](assets/flow.png \"Flow\")\n",
+			want:     "<p>click x <img src=\"/documents/docs/product/assets/flow.png\" alt=\"diagram\" title=\"Flow\"></p>\n",
+		},
+		{
+			name:     "code spans, escapes and line breaks",
+			markdown: "Run `go test ./...` \\*now\\*  \nor <https://ci.example.com> snake_case_name\n",
+			want:     "<p>Run <code>go test ./...</code> *now*<br>\nor <a href=\"https://ci.example.com\" rel=\"noopener noreferrer\">https://ci.example.com</a> snake_case_name</p>\n",
+		},
+		{
+			name:     "lists",
+			markdown: "- one\n- two\n  - nested\n- [x] done\n\n3. three\n4. four\n",
+			want:     "<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested</li>\n</ul></li>\n<li><input type=\"checkbox\" checked disabled> done</li>\n</ul>\n<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n",
+		},
+		{
+			name:     "loose list",
+			markdown: "- one\n\n- two\n",
+			want:     "<ul>\n<li>\n<p>one</p>\n</li>\n<li>\n<p>two</p>\n</li>\n</ul>\n",
+		},
+		{
+			name:     "quotes and rules",
+			markdown: "> Decided on **Monday**\n> by the team\n\n---\n",
+			want:     "<blockquote>\n<p>Decided on <strong>Monday</strong>\nby the team</p>\n</blockquote>\n<hr>\n",
+		},
+		{
+			name:     "tables",
+			markdown: "| Option | Cost |\n|:-------|-----:|\n| Postgres | low \\| none |\n| MySQL |\n",
+			want: "<table>\n<thead>\n<tr>\n<th style=\"text-align: left\">Option</th>\n<th style=\"text-align: right\">Cost</th>\n</tr>\n</thead>\n" +
+				"<tbody>\n<tr>\n<td style=\"text-align: left\">Postgres</td>\n<td style=\"text-align: right\">low | none</td>\n</tr>\n" +
+				"<tr>\n<td style=\"text-align: left\">MySQL</td>\n<td style=\"text-align: right\"></td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			name:     "code blocks",
+			markdown: "```go\nreturn \"<ok>\" // done\n```\n\n```mermaid\ngraph TD; A-->B\n```\n",
+			want: "<pre><code class=\"language-go\"><span class=\"hl-keyword\">return</span> <span class=\"hl-string\">&#34;&lt;ok&gt;&#34;</span> <span class=\"hl-comment\">// done</span>\n</code></pre>\n" +
+				"<pre class=\"mermaid\">graph TD; A--&gt;B\n</pre>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderHTML("docs/product/pricing.md", tt.markdown, DefaultDocumentLinkBase))
+		})
+	}
+}
+
+func TestRenderHTML_HeadingIDsAreUnique(t *testing.T) {
+	html := renderHTML("docs/notes.md", "## Notes\n\n## Notes\n", DefaultDocumentLinkBase)
+
+	assert.Equal(t, "<h2 id=\"notes\">Notes</h2>\n<h2 id=\"notes-1\">Notes</h2>\n", html)
+}
+
+func TestHighlightCode(t *testing.T) {
+	tests := []struct {
+		lang string
+		code string
+		want string
+	}{
+		{
+			lang: "sql",
+			code: "SELECT id FROM invoices WHERE total > 10 -- large",
+			want: `<span class="hl-keyword">SELECT</span> id <span class="hl-keyword">FROM</span> invoices <span class="hl-keyword">WHERE</span> total &gt; <span class="hl-number">10</span> <span class="hl-comment">-- large</span>`,
+		},
+		{
+			lang: "bash",
+			code: "echo a#b # note",
+			want: `echo a#b <span class="hl-comment"># note</span>`,
+		},
+		{
+			lang: "cobol",
+			code: "DISPLAY '<hi>'",
+			want: "DISPLAY &#39;&lt;hi&gt;&#39;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			assert.Equal(t, tt.want, highlightCode(tt.lang, tt.code))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// writeMetrics writes the stats and calibration in the Prometheus text format
+func writeMetrics(w io.Writer, stats *domain.WorkspaceStats, calibration []domain.ModelCalibration, maxOverrideRate float64) error {
+	b := bufio.NewWriter(w)
+
+	gauge(b, "quill_messages_captured", "Messages documented.")
+	sample(b, "quill_messages_captured", nil, float64(stats.Captured()))
+	gauge(b, "quill_documents_indexed", "Documents in the index.")
+	sample(b, "quill_documents_indexed", nil, float64(stats.Documents()))
+	gauge(b, "quill_analysis_confidence_average", "Mean confidence of the analyzed messages.")
+	sample(b, "quill_analysis_confidence_average", nil, stats.AverageConfidence())
+	gauge(b, "quill_channel_coverage_ratio", "Share of the processed messages of a channel that were documented.")
+	for _, channel := range stats.Coverage() {
+		sample(b, "quill_channel_coverage_ratio", []string{"channel", channel.Channel}, channel.Coverage())
+	}
+
+	if len(calibration) > 0 {
+		gauge(b, "quill_model_analyses", "Messages analyzed per model.")
+		for _, m := range calibration {
+			sample(b, "quill_model_analyses", []string{"model", m.Model}, float64(m.Analyzed))
+		}
+		gauge(b, "quill_model_override_ratio", "Share of a model's analyses people corrected.")
+		for _, m := range calibration {
+			sample(b, "quill_model_override_ratio", []string{"model", m.Model}, m.OverrideRate())
+		}
+		gauge(b, "quill_model_confidence_average", "Mean confidence a model reported.")
+		for _, m := range calibration {
+			sample(b, "quill_model_confidence_average", []string{"model", m.Model}, m.Confidence)
+		}
+		gauge(b, "quill_model_bin_override_ratio", "Share of a model's analyses people corrected per confidence bin, by the bin's lower bound.")
+		for _, m := range calibration {
+			for _, bin := range m.Bins {
+				sample(b, "quill_model_bin_override_ratio", []string{"model", m.Model, "confidence", formatFloat(bin.Lower)}, bin.OverrideRate())
+			}
+		}
+		gauge(b, "quill_model_suggested_threshold", fmt.Sprintf("Lowest confidence at which a model's analyses were corrected at most %s of the time.", formatFloat(maxOverrideRate)))
+		for _, m := range calibration {
+			if threshold, ok := m.SuggestedThreshold(maxOverrideRate); ok {
+				sample(b, "quill_model_suggested_threshold", []string{"model", m.Model}, threshold)
+			}
+		}
+	}
+	return b.Flush()
+}
+
+func gauge(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// sample writes a value with labels given as name and value pairs
+func sample(w io.Writer, name string, labels []string, value float64) {
+	if len(labels) == 0 {
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+		return
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,426 @@
+package api
+
+import (
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// StatsResponse is the body of GET /stats
+type StatsResponse struct {
+	Captured          int               `json:"captured"`
+	Documents         int               `json:"documents"`
+	AverageConfidence float64           `json:"averageConfidence"`
+	ByType            []CountResponse   `json:"byType"`
+	ByCategory        []CountResponse   `json:"byCategory"`
+	ByWeek            []CountResponse   `json:"byWeek"`
+	TopContributors   []CountResponse   `json:"topContributors"`
+	Coverage          []ChannelResponse `json:"coverage"`
+}
+
+// CountResponse is the number of captured messages sharing a key
+type CountResponse struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// ChannelResponse is the documentation coverage of a channel
+type ChannelResponse struct {
+	Channel    string  `json:"channel"`
+	Processed  int     `json:"processed"`
+	Documented int     `json:"documented"`
+	Coverage   float64 `json:"coverage"`
+}
+
+func newStatsResponse(stats *domain.WorkspaceStats, topContributors int) StatsResponse {
+	coverage := make([]ChannelResponse, 0, len(stats.Coverage()))
+	for _, channel := range stats.Coverage() {
+		coverage = append(coverage, ChannelResponse{
+			Channel:    channel.Channel,
+			Processed:  channel.Processed,
+			Documented: channel.Documented,
+			Coverage:   channel.Coverage(),
+		})
+	}
+
+	return StatsResponse{
+		Captured:          stats.Captured(),
+		Documents:         stats.Documents(),
+		AverageConfidence: stats.AverageConfidence(),
+		ByType:            newCounts(stats.ByType()),
+		ByCategory:        newCounts(stats.ByCategory()),
+		ByWeek:            newCounts(stats.ByWeek()),
+		TopContributors:   newCounts(stats.TopContributors(topContributors)),
+		Coverage:          coverage,
+	}
+}
+
+func newCounts(counts []domain.StatCount) []CountResponse {
+	responses := make([]CountResponse, len(counts))
+	for i, count := range counts {
+		responses[i] = CountResponse{Key: count.Key, Count: count.Count}
+	}
+	return responses
+}
+
+// CalibrationResponse is the body of GET /calibration
+type CalibrationResponse struct {
+	// MaxOverrideRate is the share of corrected analyses the suggested thresholds allow
+	MaxOverrideRate float64                    `json:"maxOverrideRate"`
+	Models          []ModelCalibrationResponse `json:"models"`
+}
+
+// ModelCalibrationResponse is the calibration of one provider and model
+type ModelCalibrationResponse struct {
+	Model        string  `json:"model"`
+	Analyzed     int     `json:"analyzed"`
+	Overridden   int     `json:"overridden"`
+	OverrideRate float64 `json:"overrideRate"`
+	Confidence   float64 `json:"confidence"`
+	// SuggestedThreshold is the lowest confidence at which analyses were overridden at most MaxOverrideRate
+	// of the time, absent when no confidence is good enough
+	SuggestedThreshold *float64            `json:"suggestedThreshold,omitempty"`
+	Bins               []OverrideBinResult `json:"bins"`
+}
+
+// OverrideBinResult is one confidence bin of a model's calibration
+type OverrideBinResult struct {
+	Lower        float64 `json:"lower"`
+	Upper        float64 `json:"upper"`
+	Count        int     `json:"count"`
+	Overridden   int     `json:"overridden"`
+	OverrideRate float64 `json:"overrideRate"`
+	Confidence   float64 `json:"confidence"`
+}
+
+func newCalibrationResponse(report []domain.ModelCalibration, maxOverrideRate float64) CalibrationResponse {
+	models := make([]ModelCalibrationResponse, 0, len(report))
+	for _, m := range report {
+		model := ModelCalibrationResponse{
+			Model:        m.Model,
+			Analyzed:     m.Analyzed,
+			Overridden:   m.Overridden,
+			OverrideRate: m.OverrideRate(),
+			Confidence:   m.Confidence,
+			Bins:         make([]OverrideBinResult, 0, len(m.Bins)),
+		}
+		if threshold, ok := m.SuggestedThreshold(maxOverrideRate); ok {
+			model.SuggestedThreshold = &threshold
+		}
+		for _, bin := range m.Bins {
+			model.Bins = append(model.Bins, OverrideBinResult{
+				Lower:        bin.Lower,
+				Upper:        bin.Upper,
+				Count:        bin.Count,
+				Overridden:   bin.Overridden,
+				OverrideRate: bin.OverrideRate(),
+				Confidence:   bin.Confidence,
+			})
+		}
+		models = append(models, model)
+	}
+	return CalibrationResponse{MaxOverrideRate: maxOverrideRate, Models: models}
+}
+
+// ErasureRequest is the body of POST /erasures
+type ErasureRequest struct {
+	// Identity is the ID the chat platform knows the person by, like a Slack user ID
+	Identity string `json:"identity"`
+	// Mode is erase or pseudonymize
+	Mode string `json:"mode"`
+	// RequestedBy is who asked for the erasure, recorded in the audit log
+	RequestedBy string `json:"requestedBy,omitempty"`
+}
+
+// DeletionReportResponse is the body of the response to POST /erasures
+type DeletionReportResponse struct {
+	Identity          string         `json:"identity"`
+	Mode              string         `json:"mode"`
+	Pseudonym         string         `json:"pseudonym"`
+	Messages          int            `json:"messages"`
+	Corrections       int            `json:"corrections"`
+	AuditEntries      int            `json:"auditEntries"`
+	Documents         []string       `json:"documents"`
+	ReadOnlyDocuments []string       `json:"readOnlyDocuments,omitempty"`
+	Records           map[string]int `json:"records"`
+	CompletedAt       time.Time      `json:"completedAt"`
+}
+
+func newDeletionReportResponse(report *domain.DeletionReport) DeletionReportResponse {
+	documents := report.Documents
+	if documents == nil {
+		documents = []string{}
+	}
+	records := report.Records
+	if records == nil {
+		records = map[string]int{}
+	}
+	return DeletionReportResponse{
+		Identity:          report.Identity,
+		Mode:              report.Mode.String(),
+		Pseudonym:         report.Pseudonym,
+		Messages:          report.Messages,
+		Corrections:       report.Corrections,
+		AuditEntries:      report.AuditEntries,
+		Documents:         documents,
+		ReadOnlyDocuments: report.ReadOnlyDocuments,
+		Records:           records,
+		CompletedAt:       report.CompletedAt,
+	}
+}
+
+// ReprocessRequest is the body of POST /reprocess
+type ReprocessRequest struct {
+	// Since selects the messages posted since a date, like 2024-06-01, or an RFC 3339 time; all when empty
+	Since string `json:"since,omitempty"`
+	// Category selects the messages of a category, all when empty
+	Category string `json:"category,omitempty"`
+	// DryRun reports the changes without storing them
+	DryRun bool `json:"dryRun,omitempty"`
+	// RequestedBy is who asked for the reprocessing, recorded in the audit log
+	RequestedBy string `json:"requestedBy,omitempty"`
+}
+
+// ReprocessedDocumentResponse is a document a reprocessing updated
+type ReprocessedDocumentResponse struct {
+	Path      string `json:"path"`
+	MessageID string `json:"messageId"`
+	Version   uint   `json:"version"`
+	Added     int    `json:"added"`
+	Removed   int    `json:"removed"`
+	Diff      string `json:"diff"`
+}
+
+// ReprocessFailureResponse is a message a reprocessing failed on
+type ReprocessFailureResponse struct {
+	MessageID string `json:"messageId"`
+	Error     string `json:"error"`
+}
+
+// ReprocessReportResponse is the body of the response to POST /reprocess
+type ReprocessReportResponse struct {
+	DryRun      bool                          `json:"dryRun"`
+	Messages    int                           `json:"messages"`
+	Documents   []ReprocessedDocumentResponse `json:"documents"`
+	Unchanged   int                           `json:"unchanged"`
+	Failures    []ReprocessFailureResponse    `json:"failures"`
+	CompletedAt time.Time                     `json:"completedAt"`
+}
+
+func newReprocessReportResponse(report *domain.ReprocessReport) ReprocessReportResponse {
+	documents := make([]ReprocessedDocumentResponse, 0, len(report.Documents))
+	for _, doc := range report.Documents {
+		documents = append(documents, ReprocessedDocumentResponse{
+			Path:      doc.Path,
+			MessageID: doc.MessageID,
+			Version:   doc.Version,
+			Added:     doc.Added,
+			Removed:   doc.Removed,
+			Diff:      doc.Diff,
+		})
+	}
+	failures := make([]ReprocessFailureResponse, 0, len(report.Failures))
+	for _, failure := range report.Failures {
+		failures = append(failures, ReprocessFailureResponse{MessageID: failure.MessageID, Error: failure.Error})
+	}
+	return ReprocessReportResponse{
+		DryRun:      report.DryRun,
+		Messages:    report.Messages,
+		Documents:   documents,
+		Unchanged:   report.Unchanged,
+		Failures:    failures,
+		CompletedAt: report.CompletedAt,
+	}
+}
+
+// ProjectRequest is the body of POST /projects
+type ProjectRequest struct {
+	Name string `json:"name"`
+	// Template is the name of the template the project is created from, like software-delivery
+	Template    string   `json:"template"`
+	Description string   `json:"description,omitempty"`
+	Goals       []string `json:"goals"`
+	KPIs        []string `json:"kpis,omitempty"`
+	// Channel is the ID of the chat channel the project is bound to, none when empty
+	Channel string `json:"channel,omitempty"`
+	// CreatedBy is who created the project, logged with it
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+// ProjectResponse is the body of the response to POST /projects
+type ProjectResponse struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Template string   `json:"template"`
+	Channels []string `json:"channels"`
+	// Path is the directory of the project's documentation
+	Path string `json:"path"`
+}
+
+func newProjectResponse(project *domain.Project, template string) ProjectResponse {
+	channels := project.Channels()
+	if channels == nil {
+		channels = []string{}
+	}
+	return ProjectResponse{
+		ID:       project.ID().String(),
+		Name:     project.Name(),
+		Template: template,
+		Channels: channels,
+		Path:     project.DocumentationPath(),
+	}
+}
+
+// EventResponse is the data of an event streamed by GET /events
+type EventResponse struct {
+	Kind      string `json:"kind"`
+	MessageID string `json:"messageId"`
+	ChannelID string `json:"channelId"`
+	ThreadID  string `json:"threadId"`
+	Type      string `json:"type"`
+	Category  string `json:"category"`
+	// Confidence is the confidence of the analysis, 0 before the message is analyzed
+	Confidence float64 `json:"confidence"`
+	State      string  `json:"state"`
+	// Path is the path of the committed document, only for committed events
+	Path   string `json:"path,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// CorrelationID is the ID the logs and the error reply of the processing of the message refer to
+	CorrelationID string    `json:"correlationId,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+func newEventResponse(event domain.ProcessingEvent) EventResponse {
+	return EventResponse{
+		Kind:          event.Kind().String(),
+		MessageID:     event.MessageID().String(),
+		ChannelID:     event.ChannelID(),
+		ThreadID:      event.ThreadID().String(),
+		Type:          event.Type().String(),
+		Category:      event.Category().String(),
+		Confidence:    event.Confidence(),
+		State:         event.State().String(),
+		Path:          event.Path(),
+		Reason:        event.Reason(),
+		CorrelationID: event.CorrelationID().String(),
+		At:            event.At(),
+	}
+}
+
+// QueueStatusResponse is the body of GET /queue
+type QueueStatusResponse struct {
+	// Depth is how many messages wait in the queue, absent when the queue cannot tell
+	Depth       *int             `json:"depth,omitempty"`
+	DeadLetters int              `json:"deadLetters"`
+	Busy        int              `json:"busy"`
+	Workers     []WorkerResponse `json:"workers"`
+}
+
+// WorkerResponse is what a queue worker is doing
+type WorkerResponse struct {
+	Worker int `json:"worker"`
+	// MessageID is the message the worker processes, empty when it is idle
+	MessageID string    `json:"messageId,omitempty"`
+	Since     time.Time `json:"since"`
+	Processed int       `json:"processed"`
+	Failed    int       `json:"failed"`
+}
+
+func newQueueStatusResponse(status *domain.QueueStatus) QueueStatusResponse {
+	workers := make([]WorkerResponse, 0, len(status.Workers))
+	for _, worker := range status.Workers {
+		workers = append(workers, WorkerResponse{
+			Worker:    worker.Worker,
+			MessageID: worker.MessageID,
+			Since:     worker.Since,
+			Processed: worker.Processed,
+			Failed:    worker.Failed,
+		})
+	}
+
+	response := QueueStatusResponse{
+		DeadLetters: status.DeadLetters,
+		Busy:        status.Busy(),
+		Workers:     workers,
+	}
+	if status.DepthKnown {
+		depth := status.Depth
+		response.Depth = &depth
+	}
+	return response
+}
+
+// DeadLetterResponse is a message the queue workers failed on
+type DeadLetterResponse struct {
+	ID        string    `json:"id"`
+	ChannelID string    `json:"channelId"`
+	ThreadID  string    `json:"threadId"`
+	Sender    string    `json:"sender"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	FailedAt  time.Time `json:"failedAt"`
+	PostedAt  time.Time `json:"postedAt"`
+	// Text is the text of the message, only served by GET /dead-letters/<id>
+	Text string `json:"text,omitempty"`
+	// Route is the chat provider's location of the message, only served by GET /dead-letters/<id>
+	Route map[string]string `json:"route,omitempty"`
+}
+
+func newDeadLetterResponse(letter *domain.DeadLetter, detailed bool) DeadLetterResponse {
+	msg := letter.Queued().Message()
+	response := DeadLetterResponse{
+		ID:        letter.ID(),
+		ChannelID: msg.ChannelID(),
+		ThreadID:  msg.ThreadID().String(),
+		Sender:    msg.Sender(),
+		Attempts:  letter.Attempts(),
+		Error:     letter.Error(),
+		FailedAt:  letter.FailedAt(),
+		PostedAt:  msg.Timestamp(),
+	}
+	if detailed {
+		response.Text = msg.Content().Text()
+		response.Route = letter.Queued().Route()
+	}
+	return response
+}
+
+// DeadLetterActionRequest is the optional body of POST /dead-letters/<id>/retry and /discard
+type DeadLetterActionRequest struct {
+	// RequestedBy is who retries or discards the message, recorded in the audit log
+	RequestedBy string `json:"requestedBy,omitempty"`
+}
+
+// RestoreResponse is the body of the response to POST /restore
+type RestoreResponse struct {
+	// Tables is how many rows were restored per table
+	Tables map[string]int `json:"tables"`
+}
+
+// MigrationResponse is a versioned change of the database schema
+type MigrationResponse struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+	// Dirty marks the version a migration failed at halfway, nothing is migrated until the schema is repaired
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+func newMigrationResponses(migrations []domain.SchemaMigration) []MigrationResponse {
+	responses := make([]MigrationResponse, 0, len(migrations))
+	for _, migration := range migrations {
+		responses = append(responses, MigrationResponse{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Applied,
+			Dirty:   migration.Dirty,
+		})
+	}
+	return responses
+}
+
+// MigrateDownRequest is the optional body of POST /migrations/down
+type MigrateDownRequest struct {
+	// Steps is how many of the last applied migrations to roll back (default: 1)
+	Steps int `json:"steps,omitempty"`
+}
@@ -0,0 +1,859 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// StatsSource computes the workspace statistics, implemented by services.StatsService
+type StatsSource interface {
+	Stats(ctx context.Context) (*domain.WorkspaceStats, error)
+}
+
+// CalibrationSource compares the confidence of models with the corrections people made, implemented by
+// services.CalibrationService
+type CalibrationSource interface {
+	Report(ctx context.Context, bins int) ([]domain.ModelCalibration, error)
+}
+
+// Eraser erases or pseudonymizes the data stored about a person, implemented by services.ErasureService
+type Eraser interface {
+	Erase(ctx context.Context, request *domain.ErasureRequest) (*domain.DeletionReport, error)
+}
+
+// Reprocessor runs documented messages through the current prompt and model again, implemented by
+// services.ReprocessService
+type Reprocessor interface {
+	Reprocess(ctx context.Context, request *domain.ReprocessRequest) (*domain.ReprocessReport, error)
+}
+
+// DocumentSource reads stored documents, implemented by services.DocumentationService
+type DocumentSource interface {
+	GetDocumentation(ctx context.Context, path string) ([]byte, error)
+}
+
+// FeedSource builds the feeds of recent documents per project, implemented by services.FeedService
+type FeedSource interface {
+	Feed(ctx context.Context, projectID string, size int) (*domain.DocumentFeed, error)
+}
+
+// ProjectCreator creates projects from templates, implemented by services.ProjectService
+type ProjectCreator interface {
+	CreateFromDraft(ctx context.Context, draft *domain.ProjectDraft) (*domain.Project, error)
+}
+
+// EventStream follows the processing events of the bot live, implemented by services.EventBus
+type EventStream interface {
+	Subscribe(buffer int) (<-chan domain.ProcessingEvent, func())
+}
+
+// QueueAdmin inspects the message queue and recovers the messages its workers failed on, implemented by
+// services.QueueAdminService
+type QueueAdmin interface {
+	Status(ctx context.Context) (*domain.QueueStatus, error)
+	DeadLetters(ctx context.Context) ([]*domain.DeadLetter, error)
+	DeadLetter(ctx context.Context, messageID string) (*domain.DeadLetter, error)
+	Retry(ctx context.Context, messageID, actor string) (*domain.DeadLetter, error)
+	Discard(ctx context.Context, messageID, actor string) (*domain.DeadLetter, error)
+}
+
+// StateBackup copies the processing state of the bot to a single snapshot and back, implemented by
+// sqlstore.StateStore
+type StateBackup interface {
+	Backup(ctx context.Context, w io.Writer) error
+	Restore(ctx context.Context, r io.Reader) (map[string]int, error)
+}
+
+// SchemaMigrator applies and rolls back the versioned migrations of the database schema, implemented by
+// sqlstore.Migrator
+type SchemaMigrator interface {
+	Status(ctx context.Context) ([]domain.SchemaMigration, error)
+	Up(ctx context.Context) ([]domain.SchemaMigration, error)
+	Down(ctx context.Context, steps int) ([]domain.SchemaMigration, error)
+}
+
+// defaultRequester is who asked for an erasure or a reprocessing when the request does not tell
+const defaultRequester = "api"
+
+// maxRequestBody limits the size of request bodies
+const maxRequestBody = 64 << 10
+
+// maxSnapshotBody limits the size of the snapshots restored, which hold every message the bot kept
+const maxSnapshotBody = 1 << 30
+
+// requestIDHeader carries the correlation ID of a request, see withRequestID
+const requestIDHeader = "X-Request-ID"
+
+// Server is the REST API of the bot, for dashboards and scripts that read what the workspace captured
+type Server struct {
+	config      *Config
+	stats       StatsSource
+	calibration CalibrationSource
+	eraser      Eraser
+	documents   DocumentSource
+	reprocessor Reprocessor
+	feeds       FeedSource
+	projects    ProjectCreator
+	events      EventStream
+	queueAdmin  QueueAdmin
+	backups     StateBackup
+	migrator    SchemaMigrator
+}
+
+// NewServer creates a new Server. The calibration source is optional, without it the calibration
+// endpoint is not served and the metrics leave the calibration out. The eraser is optional too,
+// without it personal data cannot be erased through the API, and so is the document source, without it
+// documents are not served, the reprocessor, without it messages cannot be reprocessed, the feed source,
+// without it the feeds are not served, the project creator, without it projects cannot be created, the
+// event stream, without it processing events are not streamed, the queue admin, without it the queue and
+// its dead letters cannot be inspected, the state backup, without it the state cannot be backed up or
+// restored through the API, and the schema migrator, without it the schema cannot be migrated through the API.
+func NewServer(
+	config *Config,
+	stats StatsSource,
+	calibration CalibrationSource,
+	eraser Eraser,
+	documents DocumentSource,
+	reprocessor Reprocessor,
+	feeds FeedSource,
+	projects ProjectCreator,
+	events EventStream,
+	queueAdmin QueueAdmin,
+	backups StateBackup,
+	migrator SchemaMigrator,
+) (*Server, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if stats == nil {
+		return nil, fmt.Errorf("stats source cannot be nil")
+	}
+	return &Server{
+		config:      config,
+		stats:       stats,
+		calibration: calibration,
+		eraser:      eraser,
+		documents:   documents,
+		reprocessor: reprocessor,
+		feeds:       feeds,
+		projects:    projects,
+		events:      events,
+		queueAdmin:  queueAdmin,
+		backups:     backups,
+		migrator:    migrator,
+	}, nil
+}
+
+// Handler serves GET /stats, GET /calibration, GET /metrics, POST /erasures, POST /reprocess,
+// GET /documents/<path>, GET /feeds/<project>.atom|json, POST /projects, GET /events, GET /queue,
+// GET /dead-letters, GET /dead-letters/<id>, POST /dead-letters/<id>/retry|discard, GET /backup,
+// POST /restore, GET /migrations and POST /migrations/up|down.
+// Requests authenticate with a configured token as bearer token, feeds with a feed token in the query too.
+// Every response carries the correlation ID of its request in the X-Request-ID header, see withRequestID.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", s.authenticated(s.handleStats))
+	if s.calibration != nil {
+		mux.HandleFunc("/calibration", s.authenticated(s.handleCalibration))
+	}
+	mux.HandleFunc("/metrics", s.authenticated(s.handleMetrics))
+	if s.eraser != nil {
+		mux.HandleFunc("/erasures", s.authenticated(s.handleErasure))
+	}
+	if s.reprocessor != nil {
+		mux.HandleFunc("/reprocess", s.authenticated(s.handleReprocess))
+	}
+	if s.documents != nil {
+		mux.HandleFunc("/documents/", s.authenticated(s.handleDocument))
+	}
+	if s.feeds != nil {
+		mux.HandleFunc("/feeds/", s.feedAuthenticated(s.handleFeed))
+	}
+	if s.projects != nil {
+		mux.HandleFunc("/projects", s.authenticated(s.handleProjects))
+	}
+	if s.events != nil {
+		mux.HandleFunc("/events", s.authenticated(s.handleEvents))
+	}
+	if s.queueAdmin != nil {
+		mux.HandleFunc("/queue", s.authenticated(s.handleQueue))
+		mux.HandleFunc("/dead-letters", s.authenticated(s.handleDeadLetters))
+		mux.HandleFunc("/dead-letters/", s.authenticated(s.handleDeadLetter))
+	}
+	if s.backups != nil {
+		mux.HandleFunc("/backup", s.authenticated(s.handleBackup))
+		mux.HandleFunc("/restore", s.authenticated(s.handleRestore))
+	}
+	if s.migrator != nil {
+		mux.HandleFunc("/migrations", s.authenticated(s.handleMigrations))
+		mux.HandleFunc("/migrations/", s.authenticated(s.handleMigrate))
+	}
+	return withRequestID(mux)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats, err := s.stats.Stats(r.Context())
+	if err != nil {
+		log.Printf("Failed to compute stats: %v", err)
+		http.Error(w, "failed to compute stats", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newStatsResponse(stats, s.topContributors()))
+}
+
+// handleCalibration reports the calibration of every model, ?bins= sets the number of confidence bins
+func (s *Server) handleCalibration(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bins := 0
+	if raw := r.URL.Query().Get("bins"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "bins must be a positive number", http.StatusBadRequest)
+			return
+		}
+		bins = n
+	}
+
+	report, err := s.calibration.Report(r.Context(), bins)
+	if err != nil {
+		log.Printf("Failed to compute calibration: %v", err)
+		http.Error(w, "failed to compute calibration", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newCalibrationResponse(report, s.maxOverrideRate()))
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats, err := s.stats.Stats(r.Context())
+	if err != nil {
+		log.Printf("Failed to compute stats: %v", err)
+		http.Error(w, "failed to compute metrics", http.StatusInternalServerError)
+		return
+	}
+	var report []domain.ModelCalibration
+	if s.calibration != nil {
+		if report, err = s.calibration.Report(r.Context(), 0); err != nil {
+			log.Printf("Failed to compute calibration: %v", err)
+			http.Error(w, "failed to compute metrics", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := writeMetrics(w, stats, report, s.maxOverrideRate()); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
+
+// handleErasure erases or pseudonymizes the data of a person and returns the deletion report
+func (s *Server) handleErasure(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body ErasureRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&body); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	requestedBy := body.RequestedBy
+	if strings.TrimSpace(requestedBy) == "" {
+		requestedBy = defaultRequester
+	}
+	mode, err := domain.ParseErasureMode(body.Mode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request, err := domain.NewErasureRequest(body.Identity, mode, requestedBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := s.eraser.Erase(r.Context(), request)
+	if err != nil {
+		log.Printf("Failed to erase the data of %s: %v", request.Pseudonym(), err)
+		http.Error(w, "failed to erase data, retry to finish the erasure", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newDeletionReportResponse(report))
+}
+
+// handleReprocess runs the selected messages through the current prompt and model, and returns what changed
+func (s *Server) handleReprocess(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body ReprocessRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&body); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	requestedBy := body.RequestedBy
+	if strings.TrimSpace(requestedBy) == "" {
+		requestedBy = defaultRequester
+	}
+	since, err := parseSince(body.Since)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var category domain.Category
+	if strings.TrimSpace(body.Category) != "" {
+		if category, err = domain.NewCategory(body.Category); err != nil {
+			http.Error(w, fmt.Sprintf("unknown category %q", body.Category), http.StatusBadRequest)
+			return
+		}
+	}
+	request, err := domain.NewReprocessRequest(since, category, body.DryRun, requestedBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := s.reprocessor.Reprocess(r.Context(), request)
+	if err != nil {
+		log.Printf("Failed to reprocess %s: %v", request, err)
+		http.Error(w, "failed to reprocess messages", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newReprocessReportResponse(report))
+}
+
+// handleProjects creates a project from a template, bound to a channel when the request names one
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body ProjectRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&body); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	createdBy := body.CreatedBy
+	if strings.TrimSpace(createdBy) == "" {
+		createdBy = defaultRequester
+	}
+	draft, err := domain.NewProjectDraft(body.Name, body.Template, body.Description, body.Goals, body.KPIs, body.Channel, createdBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	project, err := s.projects.CreateFromDraft(r.Context(), draft)
+	if err != nil {
+		log.Printf("Failed to create project %s: %v", draft.Metadata().Name, err)
+		http.Error(w, "failed to create project", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newProjectResponse(project, draft.Template().Name))
+}
+
+// parseSince reads the since of a ReprocessRequest, a date or an RFC 3339 time. It is zero when empty.
+func parseSince(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if since, err := time.Parse(time.DateOnly, raw); err == nil {
+		return since, nil
+	}
+	since, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q, use a date like 2024-06-01 or an RFC 3339 time", raw)
+	}
+	return since, nil
+}
+
+// handleDocument serves a stored document as it is, or rendered to sanitized HTML with ?format=html
+func (s *Server) handleDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	docPath := strings.TrimPrefix(r.URL.Path, "/documents/")
+	if !fs.ValidPath(docPath) || docPath == "." {
+		http.Error(w, "invalid document path", http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	markdown := path.Ext(docPath) == ".md"
+	switch {
+	case format != "" && format != "markdown" && format != "html":
+		http.Error(w, "format must be markdown or html", http.StatusBadRequest)
+		return
+	case format == "html" && !markdown:
+		http.Error(w, "only Markdown documents can be rendered", http.StatusBadRequest)
+		return
+	}
+
+	content, err := s.documents.GetDocumentation(r.Context(), docPath)
+	if errors.Is(err, ports.ErrNotFound) {
+		http.Error(w, "document not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to read %s: %v", docPath, err)
+		http.Error(w, "failed to read document", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	switch {
+	case format == "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, err = w.Write([]byte(renderHTML(docPath, string(content), s.documentLinkBase())))
+	case markdown:
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		_, err = w.Write(content)
+	default:
+		w.Header().Set("Content-Type", assetType(docPath))
+		_, err = w.Write(content)
+	}
+	if err != nil {
+		log.Printf("Failed to write %s: %v", docPath, err)
+	}
+}
+
+// handleFeed serves the feed of the documents of a project recently created or updated, in Atom for
+// /feeds/<project>.atom and in the JSON Feed format for /feeds/<project>.json
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/feeds/")
+	format := path.Ext(name)
+	projectID := strings.TrimSuffix(name, format)
+	if (format != ".atom" && format != ".json") || projectID == "" || strings.Contains(projectID, "/") {
+		http.Error(w, "feeds are served as /feeds/<project>.atom or /feeds/<project>.json", http.StatusNotFound)
+		return
+	}
+
+	feed, err := s.feeds.Feed(r.Context(), projectID, s.feedSize())
+	if errors.Is(err, ports.ErrNotFound) {
+		http.Error(w, "project not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to build the feed of project %s: %v", projectID, err)
+		http.Error(w, "failed to build feed", http.StatusInternalServerError)
+		return
+	}
+
+	// The token stays out of the feed URL, so it is not copied around with the feed
+	feedURL := absoluteURL(r, r.URL.Path)
+	linkBase := absoluteURL(r, s.documentLinkBase())
+	if format == ".json" {
+		w.Header().Set("Content-Type", "application/feed+json")
+		if err := json.NewEncoder(w).Encode(newJSONFeed(feed, feedURL, linkBase)); err != nil {
+			log.Printf("Failed to write response: %v", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	body, err := xml.MarshalIndent(newAtomFeed(feed, feedURL, linkBase), "", "  ")
+	if err == nil {
+		_, err = w.Write(append([]byte(xml.Header), body...))
+	}
+	if err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// handleEvents streams the processing events as server-sent events until the client disconnects.
+// ?channel= only streams the events of a channel, ?kind= those of the kinds it lists, separated by commas.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	channel := r.URL.Query().Get("channel")
+	kinds := make(map[domain.ProcessingEventKind]bool)
+	for _, kind := range strings.Split(r.URL.Query().Get("kind"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds[domain.ProcessingEventKind(kind)] = true
+		}
+	}
+
+	events, unsubscribe := s.events.Subscribe(0)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(s.eventKeepAlive())
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if (channel != "" && event.ChannelID() != channel) || (len(kinds) > 0 && !kinds[event.Kind()]) {
+				continue
+			}
+			data, err := json.Marshal(newEventResponse(event))
+			if err != nil {
+				log.Printf("Failed to encode %s event: %v", event.Kind(), err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind(), data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// handleQueue reports the depth of the message queue, the number of dead letters and what the workers do
+func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := s.queueAdmin.Status(r.Context())
+	if err != nil {
+		log.Printf("Failed to inspect the message queue: %v", err)
+		http.Error(w, "failed to inspect queue", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newQueueStatusResponse(status))
+}
+
+// handleDeadLetters lists the messages the queue workers failed on, without their text
+func (s *Server) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	letters, err := s.queueAdmin.DeadLetters(r.Context())
+	if err != nil {
+		log.Printf("Failed to list dead letters: %v", err)
+		http.Error(w, "failed to list dead letters", http.StatusInternalServerError)
+		return
+	}
+	responses := make([]DeadLetterResponse, 0, len(letters))
+	for _, letter := range letters {
+		responses = append(responses, newDeadLetterResponse(letter, false))
+	}
+	writeJSON(w, responses)
+}
+
+// handleDeadLetter serves a dead letter with the text of its message on GET /dead-letters/<id>, and queues it
+// again or discards it on POST /dead-letters/<id>/retry and /discard
+func (s *Server) handleDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/dead-letters/"), "/")
+	if id == "" || (action != "" && action != "retry" && action != "discard") {
+		http.Error(w, "dead letters are served as /dead-letters/<id>", http.StatusNotFound)
+		return
+	}
+	if (action == "" && r.Method != http.MethodGet) || (action != "" && r.Method != http.MethodPost) {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var letter *domain.DeadLetter
+	var err error
+	switch action {
+	case "":
+		letter, err = s.queueAdmin.DeadLetter(r.Context(), id)
+	default:
+		var body DeadLetterActionRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		requestedBy := body.RequestedBy
+		if strings.TrimSpace(requestedBy) == "" {
+			requestedBy = defaultRequester
+		}
+		if action == "retry" {
+			letter, err = s.queueAdmin.Retry(r.Context(), id, requestedBy)
+		} else {
+			letter, err = s.queueAdmin.Discard(r.Context(), id, requestedBy)
+		}
+	}
+	if errors.Is(err, ports.ErrNotFound) {
+		http.Error(w, "dead letter not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		verb := action
+		if verb == "" {
+			verb = "read"
+		}
+		log.Printf("Failed to %s dead letter %s: %v", verb, id, err)
+		http.Error(w, "failed to "+verb+" dead letter", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newDeadLetterResponse(letter, action == ""))
+}
+
+// handleBackup serves a snapshot of the processing state as a file to download
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The snapshot is buffered, so a failure halfway is reported rather than served as a truncated file
+	var snapshot bytes.Buffer
+	if err := s.backups.Backup(r.Context(), &snapshot); err != nil {
+		log.Printf("Failed to back up the state: %v", err)
+		http.Error(w, "failed to back up state", http.StatusInternalServerError)
+		return
+	}
+	name := "quill-" + time.Now().UTC().Format("20060102-150405") + ".snapshot.json"
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	if _, err := w.Write(snapshot.Bytes()); err != nil {
+		log.Printf("Failed to write backup: %v", err)
+	}
+}
+
+// handleRestore replaces the processing state with the snapshot in the body, and reports how many rows it
+// restored per table
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	restored, err := s.backups.Restore(r.Context(), http.MaxBytesReader(w, r.Body, maxSnapshotBody))
+	if errors.Is(err, domain.ErrInvalidSnapshot) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to restore the state: %v", err)
+		http.Error(w, "failed to restore state", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, RestoreResponse{Tables: restored})
+}
+
+// handleMigrations lists the migrations of the database schema, oldest first, with when they were applied
+func (s *Server) handleMigrations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	migrations, err := s.migrator.Status(r.Context())
+	if err != nil {
+		log.Printf("Failed to list schema migrations: %v", err)
+		http.Error(w, "failed to list migrations", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newMigrationResponses(migrations))
+}
+
+// handleMigrate applies the pending migrations on POST /migrations/up, and rolls back the last applied ones on
+// POST /migrations/down, one unless the body asks for more steps. Both return the migrations they changed.
+func (s *Server) handleMigrate(w http.ResponseWriter, r *http.Request) {
+	direction := strings.TrimPrefix(r.URL.Path, "/migrations/")
+	if direction != "up" && direction != "down" {
+		http.Error(w, "migrations are applied with /migrations/up and rolled back with /migrations/down", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var migrations []domain.SchemaMigration
+	var err error
+	if direction == "up" {
+		migrations, err = s.migrator.Up(r.Context())
+	} else {
+		var body MigrateDownRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if body.Steps < 0 {
+			http.Error(w, "steps must be positive", http.StatusBadRequest)
+			return
+		}
+		if body.Steps == 0 {
+			body.Steps = 1
+		}
+		migrations, err = s.migrator.Down(r.Context(), body.Steps)
+	}
+	if err != nil {
+		// The migrations done before the failure stay done, GET /migrations lists them
+		log.Printf("Failed to migrate the schema %s after %d migrations: %v", direction, len(migrations), err)
+		http.Error(w, "failed to migrate "+direction, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newMigrationResponses(migrations))
+}
+
+// assetType returns the content type of a file stored with the documents. Only images are served as what
+// they are, except SVG which can run scripts; anything else is downloaded.
+func assetType(docPath string) string {
+	contentType := mime.TypeByExtension(path.Ext(docPath))
+	if strings.HasPrefix(contentType, "image/") && !strings.HasPrefix(contentType, "image/svg") {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.authenticate(r.Header.Get("Authorization")) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// feedAuthenticated accepts the requests authenticated with a bearer token, or with a feed token in the token
+// query parameter since most feed readers cannot send headers
+func (s *Server) feedAuthenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.authenticate(r.Header.Get("Authorization")) && !matchToken(r.URL.Query().Get("token"), s.config.FeedTokens) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// withRequestID gives every request a correlation ID, the one of its X-Request-ID header when it is valid, so the
+// audit entries it leads to can be traced back to it. The ID is sent back in the X-Request-ID header.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := domain.ParseCorrelationID(r.Header.Get(requestIDHeader))
+		if err != nil {
+			id = domain.NewCorrelationID()
+		}
+		w.Header().Set(requestIDHeader, id.String())
+		next.ServeHTTP(w, r.WithContext(domain.ContextWithCorrelationID(r.Context(), id)))
+	})
+}
+
+// authenticate checks the bearer token of a request
+func (s *Server) authenticate(header string) bool {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false
+	}
+	return matchToken(token, s.config.Tokens)
+}
+
+// matchToken checks if a token is one of the known ones, in constant time
+func matchToken(token string, known []string) bool {
+	if token == "" {
+		return false
+	}
+	for _, k := range known {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(k)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) eventKeepAlive() time.Duration {
+	if s.config.EventKeepAlive > 0 {
+		return s.config.EventKeepAlive
+	}
+	return DefaultEventKeepAlive
+}
+
+func (s *Server) topContributors() int {
+	if s.config.TopContributors > 0 {
+		return s.config.TopContributors
+	}
+	return DefaultTopContributors
+}
+
+func (s *Server) documentLinkBase() string {
+	if s.config.DocumentLinkBase != "" {
+		return s.config.DocumentLinkBase
+	}
+	return DefaultDocumentLinkBase
+}
+
+func (s *Server) feedSize() int {
+	if s.config.FeedSize > 0 {
+		return s.config.FeedSize
+	}
+	return domain.DefaultFeedSize
+}
+
+func (s *Server) maxOverrideRate() float64 {
+	if s.config.MaxOverrideRate > 0 {
+		return s.config.MaxOverrideRate
+	}
+	return DefaultMaxOverrideRate
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,912 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubStats struct {
+	stats *domain.WorkspaceStats
+	err   error
+}
+
+func (s *stubStats) Stats(ctx context.Context) (*domain.WorkspaceStats, error) {
+	return s.stats, s.err
+}
+
+func newTestStats(t *testing.T) *domain.WorkspaceStats {
+	t.Helper()
+	at := time.Date(2024, 2, 14, 10, 0, 0, 0, time.UTC)
+	msg, err := domain.MessageFromDTO(domain.MessageDTO{
+		ID:         common.GenerateID().String(),
+		ChannelID:  "C0001",
+		Sender:     "jane",
+		Content:    "We will use Postgres",
+		Type:       domain.MessageTypeDecision.String(),
+		Category:   domain.CategoryDevelopment.String(),
+		Confidence: 0.9,
+		States: []domain.MessageStateChangeDTO{
+			{State: domain.MessageStatePending.String(), At: at},
+			{State: domain.MessageStateDocumented.String(), At: at},
+		},
+		Timestamp: at,
+	})
+	require.NoError(t, err)
+	return domain.NewWorkspaceStats([]*domain.Message{msg}, nil)
+}
+
+type stubCalibration struct {
+	report []domain.ModelCalibration
+	bins   int
+}
+
+func (s *stubCalibration) Report(ctx context.Context, bins int) ([]domain.ModelCalibration, error) {
+	s.bins = bins
+	return s.report, nil
+}
+
+func newTestCalibration() *stubCalibration {
+	return &stubCalibration{report: []domain.ModelCalibration{{
+		Model:      "ollama:llama3",
+		Analyzed:   4,
+		Overridden: 1,
+		Confidence: 0.75,
+		Bins: []domain.OverrideBin{
+			{Lower: 0.5, Upper: 0.75, Count: 2, Overridden: 1, Confidence: 0.6},
+			{Lower: 0.75, Upper: 1, Count: 2, Confidence: 0.9},
+		},
+	}}}
+}
+
+func get(t *testing.T, server *Server, method, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	return request(t, server, method, "/stats", token)
+}
+
+func request(t *testing.T, server *Server, method, target, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Stats(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	rec := get(t, server, http.MethodGet, "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp StatsResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, 1, resp.Captured)
+	assert.Equal(t, 0.9, resp.AverageConfidence)
+	assert.Equal(t, []CountResponse{{Key: "decision", Count: 1}}, resp.ByType)
+	assert.Equal(t, []CountResponse{{Key: "2024-W07", Count: 1}}, resp.ByWeek)
+	assert.Equal(t, []CountResponse{{Key: "jane", Count: 1}}, resp.TopContributors)
+	assert.Equal(t, []ChannelResponse{{Channel: "C0001", Processed: 1, Documented: 1, Coverage: 1}}, resp.Coverage)
+}
+
+func TestServer_StatsRejectsRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		source *stubStats
+		want   int
+	}{
+		{name: "missing token", method: http.MethodGet, want: http.StatusUnauthorized},
+		{name: "unknown token", method: http.MethodGet, token: "other", want: http.StatusUnauthorized},
+		{name: "wrong method", method: http.MethodPost, token: "dashboard-token", want: http.StatusMethodNotAllowed},
+		{name: "failing source", method: http.MethodGet, token: "dashboard-token", source: &stubStats{err: errors.New("down")}, want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			if source == nil {
+				source = &stubStats{stats: newTestStats(t)}
+			}
+			server, err := NewServer(NewConfig("dashboard-token"), source, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, get(t, server, tt.method, tt.token).Code)
+		})
+	}
+}
+
+func TestNewServer_InvalidConfig(t *testing.T) {
+	_, err := NewServer(&Config{Tokens: []string{" "}}, &stubStats{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrMissingTokens)
+
+	_, err = NewServer(&Config{Tokens: []string{"dashboard-token"}, MaxOverrideRate: 2}, &stubStats{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidOverrideRate)
+
+	_, err = NewServer(NewConfig("dashboard-token"), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestServer_Calibration(t *testing.T) {
+	calibration := newTestCalibration()
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, calibration, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	rec := request(t, server, http.MethodGet, "/calibration?bins=4", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, 4, calibration.bins)
+
+	var resp CalibrationResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, DefaultMaxOverrideRate, resp.MaxOverrideRate)
+	require.Len(t, resp.Models, 1)
+	model := resp.Models[0]
+	assert.Equal(t, "ollama:llama3", model.Model)
+	assert.Equal(t, 0.25, model.OverrideRate)
+	require.NotNil(t, model.SuggestedThreshold)
+	assert.Equal(t, 0.75, *model.SuggestedThreshold)
+	require.Len(t, model.Bins, 2)
+	assert.Equal(t, 0.5, model.Bins[0].OverrideRate)
+
+	assert.Equal(t, http.StatusBadRequest, request(t, server, http.MethodGet, "/calibration?bins=none", "dashboard-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(t, server, http.MethodGet, "/calibration", "").Code)
+}
+
+func TestServer_CalibrationWithoutSource(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, request(t, server, http.MethodGet, "/calibration", "dashboard-token").Code)
+}
+
+func TestServer_Metrics(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, newTestCalibration(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	rec := request(t, server, http.MethodGet, "/metrics", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE quill_messages_captured gauge\nquill_messages_captured 1\n")
+	assert.Contains(t, body, `quill_channel_coverage_ratio{channel="C0001"} 1`)
+	assert.Contains(t, body, `quill_model_override_ratio{model="ollama:llama3"} 0.25`)
+	assert.Contains(t, body, `quill_model_bin_override_ratio{model="ollama:llama3",confidence="0.5"} 0.5`)
+	assert.Contains(t, body, `quill_model_suggested_threshold{model="ollama:llama3"} 0.75`)
+}
+
+type stubEraser struct {
+	request *domain.ErasureRequest
+}
+
+func (s *stubEraser) Erase(ctx context.Context, request *domain.ErasureRequest) (*domain.DeletionReport, error) {
+	s.request = request
+	return &domain.DeletionReport{
+		Identity:  request.Identity(),
+		Mode:      request.Mode(),
+		Pseudonym: request.Pseudonym(),
+		Messages:  2,
+		Documents: []string{"docs/development/use-postgres.md"},
+		Records:   map[string]int{"threads": 1},
+	}, nil
+}
+
+func postErasure(t *testing.T, server *Server, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/erasures", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Erasure(t *testing.T) {
+	eraser := &stubEraser{}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, eraser, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	rec := postErasure(t, server, `{"identity":"U0001","mode":"pseudonymize"}`, "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "U0001", eraser.request.Identity())
+	assert.Equal(t, defaultRequester, eraser.request.RequestedBy())
+
+	var resp DeletionReportResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "pseudonymize", resp.Mode)
+	assert.Equal(t, domain.Pseudonym("U0001"), resp.Pseudonym)
+	assert.Equal(t, 2, resp.Messages)
+	assert.Equal(t, []string{"docs/development/use-postgres.md"}, resp.Documents)
+	assert.Equal(t, map[string]int{"threads": 1}, resp.Records)
+
+	assert.Equal(t, http.StatusBadRequest, postErasure(t, server, `{"identity":"U0001","mode":"forget"}`, "dashboard-token").Code)
+	assert.Equal(t, http.StatusBadRequest, postErasure(t, server, `{"mode":"erase"}`, "dashboard-token").Code)
+	assert.Equal(t, http.StatusBadRequest, postErasure(t, server, `not json`, "dashboard-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, postErasure(t, server, `{"identity":"U0001","mode":"erase"}`, "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, server, http.MethodGet, "/erasures", "dashboard-token").Code)
+}
+
+func TestServer_ErasureWithoutEraser(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, postErasure(t, server, `{"identity":"U0001","mode":"erase"}`, "dashboard-token").Code)
+}
+
+type stubReprocessor struct {
+	request *domain.ReprocessRequest
+}
+
+func (s *stubReprocessor) Reprocess(ctx context.Context, request *domain.ReprocessRequest) (*domain.ReprocessReport, error) {
+	s.request = request
+	return &domain.ReprocessReport{
+		DryRun:   request.DryRun(),
+		Messages: 2,
+		Documents: []domain.ReprocessedDocument{
+			{Path: "docs/development/use-postgres.md", MessageID: "01HZX", Version: 2, Added: 3, Removed: 1, Diff: "--- a/docs/development/use-postgres.md\n"},
+		},
+		Unchanged: 1,
+	}, nil
+}
+
+func postReprocess(t *testing.T, server *Server, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/reprocess", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer dashboard-token")
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Reprocess(t *testing.T) {
+	reprocessor := &stubReprocessor{}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, reprocessor, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	rec := postReprocess(t, server, `{"since":"2024-06-01","category":"development","dryRun":true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), reprocessor.request.Since())
+	assert.Equal(t, domain.CategoryDevelopment, reprocessor.request.Category())
+	assert.True(t, reprocessor.request.DryRun())
+	assert.Equal(t, defaultRequester, reprocessor.request.RequestedBy())
+
+	var resp ReprocessReportResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 2, resp.Messages)
+	assert.Equal(t, 1, resp.Unchanged)
+	require.Len(t, resp.Documents, 1)
+	assert.Equal(t, uint(2), resp.Documents[0].Version)
+	assert.Equal(t, 3, resp.Documents[0].Added)
+	assert.Empty(t, resp.Failures)
+
+	rec = postReprocess(t, server, `{"since":"2024-06-01T08:00:00Z"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), reprocessor.request.Since())
+	assert.Equal(t, domain.Category(""), reprocessor.request.Category())
+
+	assert.Equal(t, http.StatusBadRequest, postReprocess(t, server, `{"since":"last week"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postReprocess(t, server, `{"category":"marketing"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postReprocess(t, server, `not json`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, server, http.MethodGet, "/reprocess", "dashboard-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(t, server, http.MethodPost, "/reprocess", "").Code)
+}
+
+func TestServer_ReprocessWithoutReprocessor(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, postReprocess(t, server, `{}`).Code)
+}
+
+type stubDocuments struct {
+	files map[string]string
+}
+
+func (s *stubDocuments) GetDocumentation(ctx context.Context, path string) ([]byte, error) {
+	content, ok := s.files[path]
+	if !ok {
+		return nil, fmt.Errorf("document %s: %w", path, ports.ErrNotFound)
+	}
+	return []byte(content), nil
+}
+
+func TestServer_Documents(t *testing.T) {
+	documents := &stubDocuments{files: map[string]string{
+		"docs/development/adopt-postgres.md":  "---\ntype: decision\n---\n# Adopt Postgres\n\nSee [the runbook](../operations/failover.md).\n",
+		"docs/development/assets/schema.png":  "\x89PNG",
+		"docs/development/assets/diagram.svg": "<svg onload=\"alert(1)\"/>",
+	}}
+	config := NewConfig("dashboard-token")
+	config.DocumentLinkBase = "https://dashboard.example.com/docs/"
+	server, err := NewServer(config, &stubStats{stats: newTestStats(t)}, nil, nil, documents, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	rec := request(t, server, http.MethodGet, "/documents/docs/development/adopt-postgres.md", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, documents.files["docs/development/adopt-postgres.md"], rec.Body.String())
+
+	rec = request(t, server, http.MethodGet, "/documents/docs/development/adopt-postgres.md?format=html", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "<h1 id=\"adopt-postgres\">Adopt Postgres</h1>\n"+
+		"<p>See <a href=\"https://dashboard.example.com/docs/docs/operations/failover.md?format=html\">the runbook</a>.</p>\n", rec.Body.String())
+
+	rec = request(t, server, http.MethodGet, "/documents/docs/development/assets/schema.png", "dashboard-token")
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	rec = request(t, server, http.MethodGet, "/documents/docs/development/assets/diagram.svg", "dashboard-token")
+	assert.Equal(t, "application/octet-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+}
+
+func TestServer_DocumentsRejectsRequests(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, &stubDocuments{files: map[string]string{
+		"docs/development/assets/schema.png": "\x89PNG",
+	}}, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		target string
+		token  string
+		status int
+	}{
+		{name: "missing document", target: "/documents/docs/missing.md", token: "dashboard-token", status: http.StatusNotFound},
+		{name: "no path", target: "/documents/", token: "dashboard-token", status: http.StatusBadRequest},
+		{name: "unknown format", target: "/documents/docs/a.md?format=pdf", token: "dashboard-token", status: http.StatusBadRequest},
+		{name: "rendering an image", target: "/documents/docs/development/assets/schema.png?format=html", token: "dashboard-token", status: http.StatusBadRequest},
+		{name: "no token", target: "/documents/docs/a.md", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, request(t, server, http.MethodGet, tt.target, tt.token).Code)
+		})
+	}
+}
+
+func TestServer_DocumentsWithoutSource(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, request(t, server, http.MethodGet, "/documents/docs/a.md", "dashboard-token").Code)
+}
+
+type stubFeeds struct {
+	feed *domain.DocumentFeed
+	size int
+}
+
+func (s *stubFeeds) Feed(ctx context.Context, projectID string, size int) (*domain.DocumentFeed, error) {
+	s.size = size
+	if projectID != s.feed.Project().ID().String() {
+		return nil, fmt.Errorf("project %s: %w", projectID, ports.ErrNotFound)
+	}
+	return s.feed, nil
+}
+
+func newTestFeeds(t *testing.T) *stubFeeds {
+	t.Helper()
+	project := domain.MustNewProject("Billing", "Invoices and payments", []string{"Ship invoicing"})
+	doc, err := domain.NewIndexedDocument("docs/development/adopt postgres.md", "Adopt Postgres", "We use Postgres for billing",
+		domain.MessageTypeDecision, domain.CategoryDevelopment)
+	require.NoError(t, err)
+	doc.SetProject(project.ID())
+	doc.SetTags([]domain.Tag{"database"})
+	return &stubFeeds{feed: domain.NewDocumentFeed(project, []*domain.IndexedDocument{doc}, 0)}
+}
+
+func TestServer_Feeds(t *testing.T) {
+	feeds := newTestFeeds(t)
+	config := NewConfig("dashboard-token")
+	config.FeedTokens = []string{"feed-token"}
+	server, err := NewServer(config, &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, feeds, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	project := feeds.feed.Project().ID().String()
+
+	rec := request(t, server, http.MethodGet, "/feeds/"+project+".json?token=feed-token", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/feed+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, domain.DefaultFeedSize, feeds.size)
+
+	var jsonFeed JSONFeed
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&jsonFeed))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", jsonFeed.Version)
+	assert.Equal(t, "Billing documentation", jsonFeed.Title)
+	assert.Equal(t, "http://example.com/feeds/"+project+".json", jsonFeed.FeedURL)
+	require.Len(t, jsonFeed.Items, 1)
+	assert.Equal(t, "http://example.com/documents/docs/development/adopt%20postgres.md?format=html", jsonFeed.Items[0].URL)
+	assert.Equal(t, "We use Postgres for billing", jsonFeed.Items[0].ContentText)
+	assert.Equal(t, []string{"decision", "development", "database"}, jsonFeed.Items[0].Tags)
+
+	rec = request(t, server, http.MethodGet, "/feeds/"+project+".atom", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/atom+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "<?xml"))
+	assert.Contains(t, body, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, body, `<link rel="self" type="application/atom+xml" href="http://example.com/feeds/`+project+`.atom"></link>`)
+	assert.Contains(t, body, "<title>Adopt Postgres</title>")
+	assert.Contains(t, body, `<category term="database"></category>`)
+}
+
+func TestServer_FeedsRejectsRequests(t *testing.T) {
+	feeds := newTestFeeds(t)
+	config := NewConfig("dashboard-token")
+	config.FeedTokens = []string{"feed-token"}
+	server, err := NewServer(config, &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, feeds, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	project := feeds.feed.Project().ID().String()
+
+	tests := []struct {
+		name   string
+		target string
+		token  string
+		status int
+	}{
+		{name: "no token", target: "/feeds/" + project + ".atom", status: http.StatusUnauthorized},
+		{name: "unknown feed token", target: "/feeds/" + project + ".atom?token=other", status: http.StatusUnauthorized},
+		{name: "API token in the query", target: "/feeds/" + project + ".atom?token=dashboard-token", status: http.StatusUnauthorized},
+		{name: "unknown project", target: "/feeds/" + common.GenerateID().String() + ".atom", token: "dashboard-token", status: http.StatusNotFound},
+		{name: "unknown format", target: "/feeds/" + project + ".rss", token: "dashboard-token", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, request(t, server, http.MethodGet, tt.target, tt.token).Code)
+		})
+	}
+
+	_, err = NewServer(&Config{Tokens: []string{"dashboard-token"}, FeedTokens: []string{""}}, &stubStats{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrBlankFeedToken)
+}
+
+type stubProjects struct {
+	draft *domain.ProjectDraft
+}
+
+func (s *stubProjects) CreateFromDraft(ctx context.Context, draft *domain.ProjectDraft) (*domain.Project, error) {
+	s.draft = draft
+	metadata := draft.Metadata()
+	project, err := domain.NewProject(metadata.Name, metadata.Description, metadata.BusinessGoals)
+	if err != nil {
+		return nil, err
+	}
+	if err := draft.Template().Apply(project); err != nil {
+		return nil, err
+	}
+	return project, project.BindChannel(draft.ChannelID())
+}
+
+func TestServer_Projects(t *testing.T) {
+	projects := &stubProjects{}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, projects, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/projects", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer dashboard-token")
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post(`{"name":"Pricing","template":"research","goals":["Find the price that converts"],"channel":"C0001"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, domain.ProjectTemplateResearch, projects.draft.Template().Name)
+	assert.Equal(t, defaultRequester, projects.draft.Creator())
+
+	var resp ProjectResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "Pricing", resp.Name)
+	assert.Equal(t, "research", resp.Template)
+	assert.Equal(t, []string{"C0001"}, resp.Channels)
+	assert.NotEmpty(t, resp.ID)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"name":"Pricing","template":"sales","goals":["Sell"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`{"name":"Pricing","template":"research"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`not json`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, server, http.MethodGet, "/projects", "dashboard-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(t, server, http.MethodPost, "/projects", "").Code)
+}
+
+// stubEvents streams the events sent to it to its single subscriber
+type stubEvents struct {
+	events chan domain.ProcessingEvent
+}
+
+func (s *stubEvents) Subscribe(buffer int) (<-chan domain.ProcessingEvent, func()) {
+	return s.events, func() {}
+}
+
+func newTestEvent(t *testing.T, kind domain.ProcessingEventKind, channel string) domain.ProcessingEvent {
+	t.Helper()
+	msg, err := domain.NewMessage(common.GenerateID(), "U0001", domain.MustNewMessageContent("We will use Postgres"), domain.MessageTypeDecision, domain.CategoryDevelopment, nil)
+	require.NoError(t, err)
+	msg.SetChannelID(channel)
+	path := ""
+	if kind == domain.ProcessingEventCommitted {
+		path = "docs/development/decision/postgres.md"
+	}
+	return domain.NewProcessingEvent(kind, msg, path, time.Now())
+}
+
+func TestServer_Events(t *testing.T) {
+	stream := &stubEvents{events: make(chan domain.ProcessingEvent, 4)}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, stream, nil, nil, nil)
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events?channel=C0001&kind=received,committed", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer dashboard-token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	stream.events <- newTestEvent(t, domain.ProcessingEventReceived, "C0002")
+	stream.events <- newTestEvent(t, domain.ProcessingEventAnalyzed, "C0001")
+	stream.events <- newTestEvent(t, domain.ProcessingEventCommitted, "C0001")
+
+	buf := make([]byte, 4096)
+	n, err := resp.Body.Read(buf)
+	require.NoError(t, err)
+	frame := string(buf[:n])
+	require.True(t, strings.HasPrefix(frame, "event: committed\ndata: "), frame)
+	assert.True(t, strings.HasSuffix(frame, "\n\n"), frame)
+
+	var event EventResponse
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(frame), "event: committed\ndata: ")), &event))
+	assert.Equal(t, "committed", event.Kind)
+	assert.Equal(t, "C0001", event.ChannelID)
+	assert.Equal(t, "decision", event.Type)
+	assert.Equal(t, "docs/development/decision/postgres.md", event.Path)
+}
+
+func TestServer_EventsRejectsRequests(t *testing.T) {
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, &stubEvents{}, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, request(t, server, http.MethodGet, "/events", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, server, http.MethodPost, "/events", "dashboard-token").Code)
+
+	server, err = NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, request(t, server, http.MethodGet, "/events", "dashboard-token").Code)
+}
+
+type stubQueueAdmin struct {
+	status    *domain.QueueStatus
+	letters   []*domain.DeadLetter
+	retried   []string
+	discarded []string
+	actor     string
+	// correlationID is the one of the last retry or discard
+	correlationID domain.CorrelationID
+}
+
+func (s *stubQueueAdmin) Status(ctx context.Context) (*domain.QueueStatus, error) {
+	return s.status, nil
+}
+
+func (s *stubQueueAdmin) DeadLetters(ctx context.Context) ([]*domain.DeadLetter, error) {
+	return s.letters, nil
+}
+
+func (s *stubQueueAdmin) DeadLetter(ctx context.Context, messageID string) (*domain.DeadLetter, error) {
+	for _, letter := range s.letters {
+		if letter.ID() == messageID {
+			return letter, nil
+		}
+	}
+	return nil, ports.ErrNotFound
+}
+
+func (s *stubQueueAdmin) Retry(ctx context.Context, messageID, actor string) (*domain.DeadLetter, error) {
+	letter, err := s.DeadLetter(ctx, messageID)
+	if err == nil {
+		s.retried, s.actor = append(s.retried, messageID), actor
+		s.correlationID = domain.CorrelationIDFrom(ctx)
+	}
+	return letter, err
+}
+
+func (s *stubQueueAdmin) Discard(ctx context.Context, messageID, actor string) (*domain.DeadLetter, error) {
+	letter, err := s.DeadLetter(ctx, messageID)
+	if err == nil {
+		s.discarded, s.actor = append(s.discarded, messageID), actor
+		s.correlationID = domain.CorrelationIDFrom(ctx)
+	}
+	return letter, err
+}
+
+func newTestDeadLetter(t *testing.T) *domain.DeadLetter {
+	t.Helper()
+	msg, err := domain.NewMessage(common.GenerateID(), "U0001", domain.MustNewMessageContent("We will use Postgres"), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID("C0001")
+	queued, err := domain.NewQueuedMessage(msg, map[string]string{"slack_ts": "1700000000.000100"})
+	require.NoError(t, err)
+	letter, err := domain.NewDeadLetter(queued.Retry(), "analysis timed out", time.Now())
+	require.NoError(t, err)
+	return letter
+}
+
+func TestServer_Queue(t *testing.T) {
+	admin := &stubQueueAdmin{status: &domain.QueueStatus{
+		Depth:       3,
+		DepthKnown:  true,
+		DeadLetters: 1,
+		Workers:     []domain.WorkerStatus{{Worker: 1, MessageID: "01J0ZK", Processed: 5}, {Worker: 2, Failed: 1}},
+	}}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, admin, nil, nil)
+	require.NoError(t, err)
+
+	rec := request(t, server, http.MethodGet, "/queue", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status QueueStatusResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+	require.NotNil(t, status.Depth)
+	assert.Equal(t, 3, *status.Depth)
+	assert.Equal(t, 1, status.DeadLetters)
+	assert.Equal(t, 1, status.Busy)
+	require.Len(t, status.Workers, 2)
+	assert.Equal(t, "01J0ZK", status.Workers[0].MessageID)
+
+	admin.status = &domain.QueueStatus{}
+	rec = request(t, server, http.MethodGet, "/queue", "dashboard-token")
+	assert.NotContains(t, rec.Body.String(), `"depth"`, "queues that cannot tell their depth leave it out")
+	assert.Equal(t, http.StatusUnauthorized, request(t, server, http.MethodGet, "/queue", "").Code)
+}
+
+func TestServer_DeadLetters(t *testing.T) {
+	letter := newTestDeadLetter(t)
+	admin := &stubQueueAdmin{letters: []*domain.DeadLetter{letter}}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, admin, nil, nil)
+	require.NoError(t, err)
+
+	rec := request(t, server, http.MethodGet, "/dead-letters", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var letters []DeadLetterResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&letters))
+	require.Len(t, letters, 1)
+	assert.Equal(t, letter.ID(), letters[0].ID)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Equal(t, "analysis timed out", letters[0].Error)
+	assert.Empty(t, letters[0].Text, "the list leaves the text out")
+
+	rec = request(t, server, http.MethodGet, "/dead-letters/"+letter.ID(), "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var detail DeadLetterResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&detail))
+	assert.Equal(t, "We will use Postgres", detail.Text)
+	assert.Equal(t, "1700000000.000100", detail.Route["slack_ts"])
+
+	post := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer dashboard-token")
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+	assert.Equal(t, http.StatusOK, post("/dead-letters/"+letter.ID()+"/retry", `{"requestedBy":"oncall"}`).Code)
+	assert.Equal(t, []string{letter.ID()}, admin.retried)
+	assert.Equal(t, "oncall", admin.actor)
+	assert.Equal(t, http.StatusOK, post("/dead-letters/"+letter.ID()+"/discard", "").Code)
+	assert.Equal(t, []string{letter.ID()}, admin.discarded)
+	assert.Equal(t, defaultRequester, admin.actor)
+}
+
+func TestServer_RequestID(t *testing.T) {
+	letter := newTestDeadLetter(t)
+	admin := &stubQueueAdmin{letters: []*domain.DeadLetter{letter}}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, admin, nil, nil)
+	require.NoError(t, err)
+
+	retry := func(requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/dead-letters/"+letter.ID()+"/retry", nil)
+		req.Header.Set("Authorization", "Bearer dashboard-token")
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A valid ID is passed on to the services, and sent back
+	rec := retry("QX-3F9A0B1C2D3E")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "QX-3F9A0B1C2D3E", rec.Header().Get("X-Request-ID"))
+	assert.Equal(t, domain.CorrelationID("QX-3F9A0B1C2D3E"), admin.correlationID)
+
+	// Requests without one, or with an invalid one, get a new one
+	for _, requestID := range []string{"", "not-an-id"} {
+		rec := retry(requestID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		id, err := domain.ParseCorrelationID(rec.Header().Get("X-Request-ID"))
+		require.NoError(t, err, requestID)
+		assert.Equal(t, id, admin.correlationID)
+	}
+
+	// Rejected requests carry one too
+	rec = request(t, server, http.MethodGet, "/stats", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("X-Request-ID"))
+}
+
+func TestServer_DeadLettersRejectsRequests(t *testing.T) {
+	letter := newTestDeadLetter(t)
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, &stubQueueAdmin{letters: []*domain.DeadLetter{letter}}, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		token  string
+		status int
+	}{
+		{name: "unknown dead letter", method: http.MethodGet, target: "/dead-letters/" + common.GenerateID().String(), token: "dashboard-token", status: http.StatusNotFound},
+		{name: "unknown action", method: http.MethodPost, target: "/dead-letters/" + letter.ID() + "/replay", token: "dashboard-token", status: http.StatusNotFound},
+		{name: "retry with GET", method: http.MethodGet, target: "/dead-letters/" + letter.ID() + "/retry", token: "dashboard-token", status: http.StatusMethodNotAllowed},
+		{name: "delete", method: http.MethodDelete, target: "/dead-letters/" + letter.ID(), token: "dashboard-token", status: http.StatusMethodNotAllowed},
+		{name: "no token", method: http.MethodGet, target: "/dead-letters", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, request(t, server, tt.method, tt.target, tt.token).Code)
+		})
+	}
+}
+
+type stubBackups struct {
+	snapshot string
+	restored string
+}
+
+func (s *stubBackups) Backup(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, s.snapshot)
+	return err
+}
+
+func (s *stubBackups) Restore(ctx context.Context, r io.Reader) (map[string]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%w: not JSON", domain.ErrInvalidSnapshot)
+	}
+	s.restored = string(data)
+	return map[string]int{"messages": 2}, nil
+}
+
+func TestServer_BackupRestore(t *testing.T) {
+	backups := &stubBackups{snapshot: `{"version":1,"tables":[]}`}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, backups, nil)
+	require.NoError(t, err)
+
+	rec := request(t, server, http.MethodGet, "/backup", "dashboard-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, backups.snapshot, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), ".snapshot.json")
+
+	restore := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/restore", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer dashboard-token")
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+	rec = restore(backups.snapshot)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response RestoreResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, map[string]int{"messages": 2}, response.Tables)
+	assert.Equal(t, backups.snapshot, backups.restored)
+
+	assert.Equal(t, http.StatusBadRequest, restore("backup").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, server, http.MethodGet, "/restore", "dashboard-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(t, server, http.MethodGet, "/backup", "").Code)
+
+	// Without a state backup the endpoints are not served
+	server, err = NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, request(t, server, http.MethodGet, "/backup", "dashboard-token").Code)
+}
+
+type stubMigrator struct {
+	migrations []domain.SchemaMigration
+	steps      int
+}
+
+func (s *stubMigrator) Status(ctx context.Context) ([]domain.SchemaMigration, error) {
+	return s.migrations, nil
+}
+
+func (s *stubMigrator) Up(ctx context.Context) ([]domain.SchemaMigration, error) {
+	var applied []domain.SchemaMigration
+	for i := range s.migrations {
+		if !s.migrations[i].Applied {
+			s.migrations[i].Applied = true
+			applied = append(applied, s.migrations[i])
+		}
+	}
+	return applied, nil
+}
+
+func (s *stubMigrator) Down(ctx context.Context, steps int) ([]domain.SchemaMigration, error) {
+	s.steps = steps
+	last := s.migrations[len(s.migrations)-1]
+	s.migrations[len(s.migrations)-1].Applied = false
+	return []domain.SchemaMigration{last}, nil
+}
+
+func TestServer_Migrations(t *testing.T) {
+	migrator := &stubMigrator{migrations: []domain.SchemaMigration{
+		{Version: 1, Name: "create_threads", Applied: true},
+		{Version: 2, Name: "create_messages", Dirty: true},
+	}}
+	server, err := NewServer(NewConfig("dashboard-token"), &stubStats{stats: newTestStats(t)}, nil, nil, nil, nil, nil, nil, nil, nil, nil, migrator)
+	require.NoError(t, err)
+
+	migrations := func(rec *httptest.ResponseRecorder) []MigrationResponse {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var responses []MigrationResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&responses))
+		return responses
+	}
+	post := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer dashboard-token")
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	status := migrations(request(t, server, http.MethodGet, "/migrations", "dashboard-token"))
+	require.Len(t, status, 2)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[0].Dirty)
+	assert.False(t, status[1].Applied)
+	assert.True(t, status[1].Dirty)
+
+	applied := migrations(post("/migrations/up", ""))
+	require.Len(t, applied, 1)
+	assert.Equal(t, "create_messages", applied[0].Name)
+
+	rolledBack := migrations(post("/migrations/down", ""))
+	require.Len(t, rolledBack, 1)
+	assert.Equal(t, 1, migrator.steps, "one migration is rolled back by default")
+	migrations(post("/migrations/down", `{"steps": 2}`))
+	assert.Equal(t, 2, migrator.steps)
+
+	assert.Equal(t, http.StatusBadRequest, post("/migrations/down", `{"steps": -1}`).Code)
+	assert.Equal(t, http.StatusNotFound, post("/migrations/sideways", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, server, http.MethodGet, "/migrations/up", "dashboard-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(t, server, http.MethodGet, "/migrations", "").Code)
+}
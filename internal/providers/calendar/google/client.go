@@ -0,0 +1,145 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid Google Calendar configuration")
+)
+
+const (
+	// DefaultTimeout is the default timeout for HTTP requests
+	DefaultTimeout = 30 * time.Second
+	// maxPages bounds the pages read for one period, a busy calendar has a few hundred events a week
+	maxPages = 10
+)
+
+// Client implements the CalendarProvider interface for Google Calendar, reading events only
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient creates a new Google Calendar client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		baseURL:    baseURL,
+	}, nil
+}
+
+// MeetingsBetween returns the timed events overlapping the period from from to to.
+// Recurring events are expanded into their occurrences; all-day and canceled events are skipped.
+func (c *Client) MeetingsBetween(ctx context.Context, from, to time.Time) ([]*domain.Meeting, error) {
+	if !to.After(from) {
+		// The API returns nothing for an empty period, a single instant still lies within meetings
+		to = from.Add(time.Second)
+	}
+
+	var meetings []*domain.Meeting
+	pageToken := ""
+	for page := 0; page < maxPages; page++ {
+		params := url.Values{
+			"timeMin":      {from.UTC().Format(time.RFC3339)},
+			"timeMax":      {to.UTC().Format(time.RFC3339)},
+			"singleEvents": {"true"},
+			"orderBy":      {"startTime"},
+			"maxResults":   {"250"},
+		}
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		var resp eventsResponse
+		if err := c.get(ctx, "/calendars/"+url.PathEscape(c.config.CalendarID)+"/events", params, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+		for _, event := range resp.Items {
+			if meeting, ok := event.meeting(); ok {
+				meetings = append(meetings, meeting)
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			return meetings, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+	return meetings, nil
+}
+
+// get sends a GET request to the Calendar API and decodes the response
+func (c *Client) get(ctx context.Context, path string, params url.Values, response interface{}) error {
+	if c.config.APIKey != "" {
+		params.Set("key", c.config.APIKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+	if err := json.Unmarshal(buf.Bytes(), response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if c.config.TokenSource != nil {
+		return c.config.TokenSource(ctx)
+	}
+	return c.config.AccessToken, nil
+}
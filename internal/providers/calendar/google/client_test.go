@@ -0,0 +1,85 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_MeetingsBetween(t *testing.T) {
+	from := time.Date(2025, 6, 2, 14, 10, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 14, 40, 0, 0, time.UTC)
+
+	pages := map[string]eventsResponse{
+		"": {
+			Items: []event{
+				{ID: "review", Summary: "Architecture review", HTMLLink: "https://calendar.google.com/event?eid=review",
+					Start: eventTime{DateTime: "2025-06-02T16:00:00+02:00"}, End: eventTime{DateTime: "2025-06-02T17:00:00+02:00"}},
+				{ID: "offsite", Summary: "Offsite", Start: eventTime{Date: "2025-06-02"}, End: eventTime{Date: "2025-06-03"}},
+			},
+			NextPageToken: "page-2",
+		},
+		"page-2": {
+			Items: []event{
+				{ID: "sync", Status: "cancelled", Summary: "Sync",
+					Start: eventTime{DateTime: "2025-06-02T14:00:00Z"}, End: eventTime{DateTime: "2025-06-02T14:30:00Z"}},
+				{ID: "pairing", Summary: "Pairing",
+					Start: eventTime{DateTime: "2025-06-02T14:30:00Z"}, End: eventTime{DateTime: "2025-06-02T15:00:00Z"}},
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/calendars/team@example.com/events", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		query := r.URL.Query()
+		assert.Equal(t, "2025-06-02T14:10:00Z", query.Get("timeMin"))
+		assert.Equal(t, "2025-06-02T14:40:00Z", query.Get("timeMax"))
+		assert.Equal(t, "true", query.Get("singleEvents"))
+
+		page, ok := pages[query.Get("pageToken")]
+		require.True(t, ok)
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	config := NewConfig("team@example.com", "token")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	meetings, err := client.MeetingsBetween(context.Background(), from, to)
+	require.NoError(t, err)
+
+	require.Len(t, meetings, 2)
+	assert.Equal(t, "Architecture review", meetings[0].Title())
+	assert.Equal(t, "https://calendar.google.com/event?eid=review", meetings[0].Link())
+	assert.True(t, meetings[0].Start().Equal(time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Pairing", meetings[1].Title())
+}
+
+func TestClient_MeetingsBetween_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "public-key", r.URL.Query().Get("key"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"message":"Calendar usage limits exceeded."}}`))
+	}))
+	defer server.Close()
+
+	config := NewConfig("team@example.com", "")
+	config.APIKey = "public-key"
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	at := time.Date(2025, 6, 2, 14, 10, 0, 0, time.UTC)
+	_, err = client.MeetingsBetween(context.Background(), at, at)
+	assert.ErrorContains(t, err, "unexpected status code: 403")
+}
@@ -0,0 +1,78 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingCalendarID  = errors.New("calendar ID is required")
+	ErrMissingCredentials = errors.New("an access token, a token source or an API key is required")
+	ErrInvalidBaseURL     = errors.New("base URL must be an absolute http or https URL")
+)
+
+// DefaultBaseURL is the address of the Google Calendar API
+const DefaultBaseURL = "https://www.googleapis.com/calendar/v3"
+
+// TokenSource returns a current OAuth access token, e.g. refreshed for a service account
+type TokenSource func(ctx context.Context) (string, error)
+
+// Config contains the settings of a read-only Google Calendar
+type Config struct {
+	// CalendarID is the calendar to read, like "team@example.com" or "primary"
+	CalendarID string
+
+	// AccessToken is an OAuth token with the calendar.readonly or calendar.events.readonly scope
+	AccessToken string
+
+	// TokenSource returns access tokens for long-running bots, it takes precedence over AccessToken (optional)
+	TokenSource TokenSource
+
+	// APIKey reads public calendars without OAuth (optional)
+	APIKey string
+
+	// BaseURL is the API endpoint (optional, defaults to DefaultBaseURL)
+	BaseURL string
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewConfig creates a new Google Calendar configuration authenticated with an access token
+func NewConfig(calendarID, accessToken string) *Config {
+	return &Config{
+		CalendarID:  calendarID,
+		AccessToken: accessToken,
+		BaseURL:     DefaultBaseURL,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.CalendarID) == "" {
+		return ErrMissingCalendarID
+	}
+
+	if strings.TrimSpace(c.AccessToken) == "" && c.TokenSource == nil && strings.TrimSpace(c.APIKey) == "" {
+		return ErrMissingCredentials
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidBaseURL
+		}
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package google
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{
+			name: "token source",
+			mutate: func(c *Config) {
+				c.AccessToken = ""
+				c.TokenSource = func(ctx context.Context) (string, error) { return "token", nil }
+			},
+		},
+		{name: "API key", mutate: func(c *Config) { c.AccessToken = ""; c.APIKey = "key" }},
+		{name: "missing calendar", mutate: func(c *Config) { c.CalendarID = " " }, wantErr: ErrMissingCalendarID},
+		{name: "missing credentials", mutate: func(c *Config) { c.AccessToken = "" }, wantErr: ErrMissingCredentials},
+		{name: "relative base URL", mutate: func(c *Config) { c.BaseURL = "www.googleapis.com" }, wantErr: ErrInvalidBaseURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig("team@example.com", "token")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package google
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures Google Calendar clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new Google Calendar client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateCalendarProvider creates a new Google Calendar client that implements the CalendarProvider interface
+func (f *Factory) CreateCalendarProvider() (ports.CalendarProvider, error) {
+	return NewClient(f.config)
+}
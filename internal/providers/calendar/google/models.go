@@ -0,0 +1,48 @@
+package google
+
+import (
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// eventsResponse is a page of the events.list response
+type eventsResponse struct {
+	Items         []event `json:"items"`
+	NextPageToken string  `json:"nextPageToken"`
+}
+
+// event is a calendar event, or an occurrence of a recurring one
+type event struct {
+	ID       string    `json:"id"`
+	Status   string    `json:"status"`
+	Summary  string    `json:"summary"`
+	HTMLLink string    `json:"htmlLink"`
+	Start    eventTime `json:"start"`
+	End      eventTime `json:"end"`
+}
+
+// eventTime is when an event starts or ends: a date for all-day events, a time otherwise
+type eventTime struct {
+	Date     string `json:"date"`
+	DateTime string `json:"dateTime"`
+}
+
+// meeting converts a timed, confirmed or tentative event into a meeting
+func (e event) meeting() (*domain.Meeting, bool) {
+	if e.Status == "cancelled" || e.Start.DateTime == "" || e.End.DateTime == "" {
+		return nil, false
+	}
+
+	start, errStart := time.Parse(time.RFC3339, e.Start.DateTime)
+	end, errEnd := time.Parse(time.RFC3339, e.End.DateTime)
+	if errStart != nil || errEnd != nil {
+		return nil, false
+	}
+
+	meeting, err := domain.NewMeeting(e.ID, e.Summary, start, end, e.HTMLLink)
+	if err != nil {
+		return nil, false
+	}
+	return meeting, true
+}
@@ -0,0 +1,146 @@
+package outlook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid Outlook calendar configuration")
+)
+
+const (
+	// DefaultTimeout is the default timeout for HTTP requests
+	DefaultTimeout = 30 * time.Second
+	// maxPages bounds the pages read for one period, a busy calendar has a few hundred events a week
+	maxPages = 10
+)
+
+// Client implements the CalendarProvider interface for Outlook calendars through Microsoft Graph, reading events only
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient creates a new Outlook calendar client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		baseURL:    baseURL,
+	}, nil
+}
+
+// MeetingsBetween returns the timed events overlapping the period from from to to.
+// Recurring events are expanded into their occurrences; all-day and canceled events are skipped.
+func (c *Client) MeetingsBetween(ctx context.Context, from, to time.Time) ([]*domain.Meeting, error) {
+	if !to.After(from) {
+		// The calendar view is empty for an empty period, a single instant still lies within meetings
+		to = from.Add(time.Second)
+	}
+
+	params := url.Values{
+		"startDateTime": {from.UTC().Format(time.RFC3339)},
+		"endDateTime":   {to.UTC().Format(time.RFC3339)},
+		"$select":       {"id,subject,webLink,isAllDay,isCancelled,start,end"},
+		"$top":          {"100"},
+	}
+	next := c.calendarPath() + "?" + params.Encode()
+
+	var meetings []*domain.Meeting
+	for page := 0; page < maxPages && next != ""; page++ {
+		var resp calendarViewResponse
+		if err := c.get(ctx, next, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+		for _, event := range resp.Value {
+			if meeting, ok := event.meeting(); ok {
+				meetings = append(meetings, meeting)
+			}
+		}
+		next = resp.NextLink
+	}
+	return meetings, nil
+}
+
+// calendarPath returns the address of the calendar view of the configured calendar
+func (c *Client) calendarPath() string {
+	user := strings.TrimSpace(c.config.User)
+	prefix := c.baseURL + "/users/" + url.PathEscape(user)
+	if user == "" || user == DefaultUser {
+		prefix = c.baseURL + "/me"
+	}
+	if calendarID := strings.TrimSpace(c.config.CalendarID); calendarID != "" {
+		return prefix + "/calendars/" + url.PathEscape(calendarID) + "/calendarView"
+	}
+	return prefix + "/calendarView"
+}
+
+// get sends a GET request to Microsoft Graph and decodes the response. Times are requested in UTC.
+func (c *Client) get(ctx context.Context, endpoint string, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+	if err := json.Unmarshal(buf.Bytes(), response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if c.config.TokenSource != nil {
+		return c.config.TokenSource(ctx)
+	}
+	return c.config.AccessToken, nil
+}
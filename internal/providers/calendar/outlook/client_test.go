@@ -0,0 +1,85 @@
+package outlook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_MeetingsBetween(t *testing.T) {
+	from := time.Date(2025, 6, 2, 14, 10, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 14, 40, 0, 0, time.UTC)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/team@example.com/calendars/cal-1/calendarView", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, `outlook.timezone="UTC"`, r.Header.Get("Prefer"))
+
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(calendarViewResponse{Value: []event{
+				{ID: "pairing", Subject: "Pairing",
+					Start: eventTime{DateTime: "2025-06-02T14:30:00.0000000", TimeZone: "UTC"},
+					End:   eventTime{DateTime: "2025-06-02T15:00:00.0000000", TimeZone: "UTC"}},
+			}})
+			return
+		}
+
+		assert.Equal(t, "2025-06-02T14:10:00Z", r.URL.Query().Get("startDateTime"))
+		assert.Equal(t, "2025-06-02T14:40:00Z", r.URL.Query().Get("endDateTime"))
+		json.NewEncoder(w).Encode(calendarViewResponse{
+			Value: []event{
+				{ID: "review", Subject: "Architecture review", WebLink: "https://outlook.office365.com/owa/?itemid=review",
+					Start: eventTime{DateTime: "2025-06-02T14:00:00.0000000", TimeZone: "UTC"},
+					End:   eventTime{DateTime: "2025-06-02T15:00:00.0000000", TimeZone: "UTC"}},
+				{ID: "offsite", Subject: "Offsite", IsAllDay: true,
+					Start: eventTime{DateTime: "2025-06-02T00:00:00.0000000", TimeZone: "UTC"},
+					End:   eventTime{DateTime: "2025-06-03T00:00:00.0000000", TimeZone: "UTC"}},
+				{ID: "sync", Subject: "Sync", IsCancelled: true,
+					Start: eventTime{DateTime: "2025-06-02T14:00:00.0000000", TimeZone: "UTC"},
+					End:   eventTime{DateTime: "2025-06-02T14:30:00.0000000", TimeZone: "UTC"}},
+			},
+			NextLink: server.URL + r.URL.Path + "?page=2",
+		})
+	}))
+	defer server.Close()
+
+	config := NewConfig("team@example.com", "token")
+	config.CalendarID = "cal-1"
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	meetings, err := client.MeetingsBetween(context.Background(), from, to)
+	require.NoError(t, err)
+
+	require.Len(t, meetings, 2)
+	assert.Equal(t, "Architecture review", meetings[0].Title())
+	assert.Equal(t, "https://outlook.office365.com/owa/?itemid=review", meetings[0].Link())
+	assert.True(t, meetings[0].Start().Equal(time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Pairing", meetings[1].Title())
+}
+
+func TestClient_MeetingsBetween_SignedInUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/me/calendarView", r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":"InvalidAuthenticationToken"}}`))
+	}))
+	defer server.Close()
+
+	config := NewConfig("", "expired")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	at := time.Date(2025, 6, 2, 14, 10, 0, 0, time.UTC)
+	_, err = client.MeetingsBetween(context.Background(), at, at)
+	assert.ErrorContains(t, err, "unexpected status code: 401")
+}
@@ -0,0 +1,77 @@
+package outlook
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingCredentials = errors.New("an access token or a token source is required")
+	ErrInvalidBaseURL     = errors.New("base URL must be an absolute http or https URL")
+)
+
+const (
+	// DefaultBaseURL is the address of the Microsoft Graph API
+	DefaultBaseURL = "https://graph.microsoft.com/v1.0"
+	// DefaultUser reads the calendar of the signed-in user, tokens of an application name the user instead
+	DefaultUser = "me"
+)
+
+// TokenSource returns a current OAuth access token, e.g. from the client credentials flow
+type TokenSource func(ctx context.Context) (string, error)
+
+// Config contains the settings of a read-only Outlook calendar
+type Config struct {
+	// User is the user or shared mailbox whose calendar is read, by ID or principal name (default: "me")
+	User string
+
+	// CalendarID selects a calendar other than the user's default one (optional)
+	CalendarID string
+
+	// AccessToken is a Microsoft Graph token with the Calendars.Read permission
+	AccessToken string
+
+	// TokenSource returns access tokens for long-running bots, it takes precedence over AccessToken (optional)
+	TokenSource TokenSource
+
+	// BaseURL is the API endpoint (optional, defaults to DefaultBaseURL)
+	BaseURL string
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewConfig creates a new Outlook calendar configuration for a user's default calendar
+func NewConfig(user, accessToken string) *Config {
+	return &Config{
+		User:        user,
+		AccessToken: accessToken,
+		BaseURL:     DefaultBaseURL,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.AccessToken) == "" && c.TokenSource == nil {
+		return ErrMissingCredentials
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidBaseURL
+		}
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package outlook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "signed-in user", mutate: func(c *Config) { c.User = "" }},
+		{
+			name: "token source",
+			mutate: func(c *Config) {
+				c.AccessToken = ""
+				c.TokenSource = func(ctx context.Context) (string, error) { return "token", nil }
+			},
+		},
+		{name: "missing credentials", mutate: func(c *Config) { c.AccessToken = " " }, wantErr: ErrMissingCredentials},
+		{name: "relative base URL", mutate: func(c *Config) { c.BaseURL = "graph.microsoft.com" }, wantErr: ErrInvalidBaseURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig("team@example.com", "token")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
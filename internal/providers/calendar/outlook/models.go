@@ -0,0 +1,63 @@
+package outlook
+
+import (
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// graphTimeLayout is how Microsoft Graph formats times, without an offset as the time zone is given separately
+const graphTimeLayout = "2006-01-02T15:04:05.9999999"
+
+// calendarViewResponse is a page of the calendarView response
+type calendarViewResponse struct {
+	Value    []event `json:"value"`
+	NextLink string  `json:"@odata.nextLink"`
+}
+
+// event is a calendar event, or an occurrence of a recurring one
+type event struct {
+	ID          string    `json:"id"`
+	Subject     string    `json:"subject"`
+	WebLink     string    `json:"webLink"`
+	IsAllDay    bool      `json:"isAllDay"`
+	IsCancelled bool      `json:"isCancelled"`
+	Start       eventTime `json:"start"`
+	End         eventTime `json:"end"`
+}
+
+// eventTime is a time with the name of its time zone, UTC as requested by the client
+type eventTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+func (t eventTime) time() (time.Time, error) {
+	loc := time.UTC
+	if t.TimeZone != "" && t.TimeZone != "UTC" {
+		var err error
+		if loc, err = time.LoadLocation(t.TimeZone); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.ParseInLocation(graphTimeLayout, t.DateTime, loc)
+}
+
+// meeting converts a timed event that takes place into a meeting
+func (e event) meeting() (*domain.Meeting, bool) {
+	if e.IsAllDay || e.IsCancelled {
+		return nil, false
+	}
+
+	start, errStart := e.Start.time()
+	end, errEnd := e.End.time()
+	if errStart != nil || errEnd != nil {
+		return nil, false
+	}
+
+	meeting, err := domain.NewMeeting(e.ID, e.Subject, start, end, e.WebLink)
+	if err != nil {
+		return nil, false
+	}
+	return meeting, true
+}
@@ -0,0 +1,415 @@
+package email
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid email configuration")
+)
+
+const (
+	// maxSeenEmails bounds the set used to drop duplicate deliveries of the same email
+	maxSeenEmails = 1000
+	// queueWait is how long a webhook waits for room in a full listener before asking for a retry
+	queueWait = 10 * time.Second
+)
+
+// Client implements the ChatAccessProvider interface for email. Each capture address is a channel and
+// each conversation, identified by the Message-ID of the email that started it, is a thread. Emails arrive
+// through webhooks: MailgunHandler for Mailgun inbound routes and RawHandler for raw MIME, e.g. posted by
+// an SES notification handler or an MTA pipe.
+type Client struct {
+	config    *Config
+	mailer    mailer
+	messageCh chan *domain.Message
+	now       func() time.Time
+	tokens    *tokenCache
+
+	lock      sync.RWMutex
+	threadMap map[string]common.ID   // Maps the Message-ID starting a conversation to our ThreadID
+	messages  map[string]MessageData // Maps our message IDs to the email they came from
+	seen      map[string]struct{}
+	seenOrder []string
+}
+
+// NewClient creates a new email client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	var m mailer
+	if config.SMTP != nil {
+		m = newSMTPMailer(config.SMTP)
+	}
+
+	return &Client{
+		config:    config,
+		mailer:    m,
+		messageCh: make(chan *domain.Message, 100),
+		now:       time.Now,
+		tokens:    newTokenCache(),
+		threadMap: make(map[string]common.ID),
+		messages:  make(map[string]MessageData),
+		seen:      make(map[string]struct{}),
+	}, nil
+}
+
+// SendMessage does nothing, capture addresses are where mail arrives, not where the bot writes to
+func (c *Client) SendMessage(ctx context.Context, channelID, content string) error {
+	if c.config.DebugMode {
+		log.Printf("Not sending message to email channel %s: the bot only replies to emails", channelID)
+	}
+	return nil
+}
+
+// ReplyToMessage replies to the sender of an email, in the same conversation
+func (c *Client) ReplyToMessage(ctx context.Context, messageID, content string) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to reply to message: unknown message %s", messageID)
+	}
+	if c.mailer == nil {
+		return nil
+	}
+	if !c.senderAllowed(data.From) {
+		if c.config.DebugMode {
+			log.Printf("Not replying to message %s: %s is not an allowed sender", messageID, data.From)
+		}
+		return nil
+	}
+
+	if err := c.mailer.Send(ctx, replyFor(data, c.config.SMTP.From, content)); err != nil {
+		return fmt.Errorf("failed to reply to message: %w", err)
+	}
+	return nil
+}
+
+// AddReaction does nothing, emails cannot be reacted to
+func (c *Client) AddReaction(ctx context.Context, messageID, emoji string) error {
+	return nil
+}
+
+// ListenForMessages returns the channel of emails received by the webhook handlers
+func (c *Client) ListenForMessages(ctx context.Context) (<-chan *domain.Message, error) {
+	return c.messageCh, nil
+}
+
+// HandleInteraction is not supported, emails have no interactive components
+func (c *Client) HandleInteraction(ctx context.Context, interaction interface{}) error {
+	return fmt.Errorf("unsupported interaction type: %T", interaction)
+}
+
+// ReplyEphemeral replies to the sender of an email, which only they receive
+func (c *Client) ReplyEphemeral(ctx context.Context, messageID, content string) error {
+	return c.ReplyToMessage(ctx, messageID, content)
+}
+
+// ReplyDirect replies to the sender of an email, which only they receive
+func (c *Client) ReplyDirect(ctx context.Context, messageID, content string) error {
+	return c.ReplyToMessage(ctx, messageID, content)
+}
+
+// MailgunHandler receives emails from a Mailgun inbound route that forwards to the handler's URL
+func (c *Client) MailgunHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if c.config.MailgunSigningKey == "" {
+			http.Error(w, "mailgun webhooks are not configured", http.StatusNotFound)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, c.maxEmailBytes())
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, "invalid form", http.StatusBadRequest)
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		token := r.FormValue("token")
+		err := verifyMailgunSignature(c.config.MailgunSigningKey, r.FormValue("timestamp"), token, r.FormValue("signature"), c.now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !c.tokens.use(token, c.now()) {
+			http.Error(w, ErrReplayedWebhook.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		e, err := parseMailgun(r, c.maxAttachmentText())
+		if err != nil {
+			http.Error(w, "invalid email", http.StatusBadRequest)
+			return
+		}
+		err = c.ingest(r.Context(), e)
+		if err != nil {
+			c.tokens.forget(token)
+		}
+		c.respond(w, err)
+	})
+}
+
+// RawHandler receives raw MIME emails posted with the ingest token as a bearer token
+func (c *Client) RawHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if c.config.IngestToken == "" {
+			http.Error(w, "raw ingestion is not configured", http.StatusNotFound)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(c.config.IngestToken)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, c.maxEmailBytes())
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "email too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		e, err := parseMIME(raw, c.maxAttachmentText())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.respond(w, c.ingest(r.Context(), e))
+	})
+}
+
+// respond acknowledges an email; skipped emails are acknowledged too, so they are not retried
+func (c *Client) respond(w http.ResponseWriter, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ingest converts an email into a domain message and hands it to the listener
+func (c *Client) ingest(ctx context.Context, e *inboundEmail) error {
+	channelID, reason := c.captureAddress(e)
+	if reason != "" {
+		if c.config.DebugMode {
+			log.Printf("Skipping email %s: %s", e.messageID, reason)
+		}
+		return nil
+	}
+	if e.messageID != "" && !c.markSeen(e.messageID) {
+		return nil
+	}
+
+	messageContent, err := domain.NewMessageContent(messageText(e))
+	if err != nil {
+		if c.config.DebugMode {
+			log.Printf("Skipping email %s: %v", e.messageID, err)
+		}
+		return nil
+	}
+
+	// For now, use default type and category - these will be determined later by AI analysis
+	domainMsg, err := domain.NewMessage(
+		c.threadIDFor(e.threadRoot()),
+		e.sender(),
+		messageContent,
+		domain.MessageTypeInformation,
+		domain.CategoryOther,
+		nil, // no references initially
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	domainMsg.SetChannelID(channelID)
+	domainMsg.SetSourceTimestamp(e.messageID)
+
+	data := MessageData{
+		CaptureAddress: channelID,
+		MessageID:      e.messageID,
+		References:     append(append([]string(nil), e.references...), e.messageID),
+		Subject:        e.subject,
+	}
+	if e.from != nil {
+		data.From = e.from.Address
+	}
+	c.rememberMessage(domainMsg.ID().String(), data)
+
+	// The webhook is answered with an error while the listener is full, so the sender retries later
+	timer := time.NewTimer(queueWait)
+	defer timer.Stop()
+	select {
+	case c.messageCh <- domainMsg:
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	c.forgetSeen(e.messageID)
+	return errors.New("message queue is full")
+}
+
+// captureAddress returns the capture address an email was sent to, or why it is skipped
+func (c *Client) captureAddress(e *inboundEmail) (string, string) {
+	if e.autoSubmitted {
+		return "", "sent automatically"
+	}
+	if e.from == nil {
+		return "", "no sender"
+	}
+	if c.config.SMTP != nil && strings.EqualFold(e.from.Address, c.fromAddress()) {
+		return "", "sent by the bot"
+	}
+	if len(c.config.AllowedSenders) > 0 && !c.senderAllowed(e.from.Address) {
+		return "", "sender not allowed"
+	}
+
+	if len(c.config.CaptureAddresses) == 0 {
+		if len(e.recipients) == 0 {
+			return "", "no recipient"
+		}
+		return e.recipients[0], ""
+	}
+	for _, recipient := range e.recipients {
+		for _, capture := range c.config.CaptureAddresses {
+			if address, err := parseAddress(capture); err == nil && address == recipient {
+				return address, ""
+			}
+		}
+	}
+	return "", "not sent to a capture address"
+}
+
+// senderAllowed checks an address, or its domain, is one of Config.AllowedSenders
+func (c *Client) senderAllowed(address string) bool {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if address == "" {
+		return false
+	}
+	domain := address[strings.LastIndex(address, "@")+1:]
+	for _, allowed := range c.config.AllowedSenders {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == address || allowed == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) fromAddress() string {
+	address, _ := parseAddress(c.config.SMTP.From)
+	return address
+}
+
+func (c *Client) maxEmailBytes() int64 {
+	if c.config.MaxEmailBytes > 0 {
+		return c.config.MaxEmailBytes
+	}
+	return DefaultMaxEmailBytes
+}
+
+func (c *Client) maxAttachmentText() int {
+	if c.config.MaxAttachmentText > 0 {
+		return c.config.MaxAttachmentText
+	}
+	return DefaultMaxAttachmentText
+}
+
+// threadIDFor maps the Message-ID starting a conversation to our ThreadID, creating one for new conversations
+func (c *Client) threadIDFor(root string) common.ID {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if id, exists := c.threadMap[root]; exists {
+		return id
+	}
+	id := common.GenerateID()
+	if root != "" {
+		c.threadMap[root] = id
+	}
+	return id
+}
+
+// rememberMessage keeps the email a domain message came from so replies reach its sender
+func (c *Client) rememberMessage(messageID string, data MessageData) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.messages[messageID] = data
+}
+
+// lookupMessage returns the email a domain message came from
+func (c *Client) lookupMessage(messageID string) (MessageData, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	data, ok := c.messages[messageID]
+	return data, ok
+}
+
+// markSeen records a Message-ID and reports whether it was new
+func (c *Client) markSeen(id string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.seen[id]; ok {
+		return false
+	}
+	if len(c.seenOrder) >= maxSeenEmails {
+		delete(c.seen, c.seenOrder[0])
+		c.seenOrder = c.seenOrder[1:]
+	}
+	c.seen[id] = struct{}{}
+	c.seenOrder = append(c.seenOrder, id)
+	return true
+}
+
+// forgetSeen drops a Message-ID that could not be delivered, so the retried email is accepted
+func (c *Client) forgetSeen(id string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.seen, id)
+}
+
+// messageText builds the message content of an email. The subject titles the conversation, so the
+// email starting it is prefixed with it. Attachments are listed after the body, text files with their content.
+func messageText(e *inboundEmail) string {
+	var b strings.Builder
+	if !e.isReply() && e.subject != "" {
+		b.WriteString(fmt.Sprintf("%s\n\n", e.subject))
+	}
+	b.WriteString(e.text)
+
+	for _, a := range e.attachments {
+		if a.text != "" {
+			b.WriteString(fmt.Sprintf("\n\nAttachment %s:\n%s", a.name, strings.TrimSpace(a.text)))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\n\nAttachment %s (%s, %d bytes)", a.name, a.contentType, a.size))
+	}
+	return strings.TrimSpace(b.String())
+}
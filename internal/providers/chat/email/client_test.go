@@ -0,0 +1,312 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubMailer records the emails the client sends
+type stubMailer struct {
+	mu   sync.Mutex
+	sent []outgoingEmail
+}
+
+func (m *stubMailer) Send(ctx context.Context, email outgoingEmail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+func newTestClient(t *testing.T, mutate func(c *Config)) (*Client, *stubMailer) {
+	t.Helper()
+	config := NewConfig("signing-key")
+	config.IngestToken = "ingest-token"
+	config.CaptureAddresses = []string{"Decisions <decisions@example.com>"}
+	config.AllowedSenders = []string{"example.com"}
+	config.SMTP = &SMTPConfig{Addr: "smtp.example.com:587", From: "Quill <quill@example.com>"}
+	if mutate != nil {
+		mutate(config)
+	}
+
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	mailer := &stubMailer{}
+	client.mailer = mailer
+	return client, mailer
+}
+
+var mailgunTokens atomic.Int64
+
+// mailgunRequest builds a signed Mailgun inbound route webhook, with a new token unless fields names one
+func mailgunRequest(t *testing.T, key string, signedAt time.Time, fields map[string]string, files map[string]string) *http.Request {
+	t.Helper()
+	if fields["token"] == "" {
+		fields["token"] = fmt.Sprintf("token-%d", mailgunTokens.Add(1))
+	}
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + fields["token"]))
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fields["timestamp"] = timestamp
+	fields["signature"] = hex.EncodeToString(mac.Sum(nil))
+	fields["attachment-count"] = strconv.Itoa(len(files))
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	n := 1
+	for name, content := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachment-%d"; filename=%q`, n, name))
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+		n++
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/email/mailgun", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func rawRequest(token, raw string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/email/raw", strings.NewReader(raw))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func receive(t *testing.T, client *Client) *domain.Message {
+	t.Helper()
+	messages, err := client.ListenForMessages(context.Background())
+	require.NoError(t, err)
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func TestClient_MailgunHandler(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+
+	req := mailgunRequest(t, "signing-key", time.Now(), map[string]string{
+		"recipient":     "decisions@example.com",
+		"from":          "Ana Lima <ana@example.com>",
+		"subject":       "Database choice",
+		"body-plain":    "We decided to use Postgres.\n\nOn Mon, Bob wrote:\n> Which database?",
+		"stripped-text": "We decided to use Postgres.",
+		"Message-Id":    "<root@example.com>",
+	}, map[string]string{"notes.txt": "Postgres wins."})
+	rec := httptest.NewRecorder()
+	client.MailgunHandler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	msg := receive(t, client)
+	assert.Equal(t, "decisions@example.com", msg.ChannelID())
+	assert.Equal(t, "Ana Lima", msg.Sender())
+	assert.Equal(t, "Database choice\n\nWe decided to use Postgres.\n\nAttachment notes.txt:\nPostgres wins.", msg.Content().Text())
+	assert.NotEmpty(t, msg.ThreadID().String())
+}
+
+func TestClient_MailgunHandler_RejectsSignatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		signedAt time.Time
+	}{
+		{name: "wrong key", key: "other-key", signedAt: time.Now()},
+		{name: "expired signature", key: "signing-key", signedAt: time.Now().Add(-time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, nil)
+			req := mailgunRequest(t, tt.key, tt.signedAt, map[string]string{"recipient": "decisions@example.com"}, nil)
+
+			rec := httptest.NewRecorder()
+			client.MailgunHandler().ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		})
+	}
+}
+
+func TestClient_MailgunHandler_RejectsReplays(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	fields := func() map[string]string {
+		return map[string]string{
+			"token":      "captured-token",
+			"recipient":  "decisions@example.com",
+			"from":       "ana@example.com",
+			"body-plain": "We decided to use Postgres.",
+			"Message-Id": "<root@example.com>",
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	client.MailgunHandler().ServeHTTP(rec, mailgunRequest(t, "signing-key", time.Now(), fields(), nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	client.MailgunHandler().ServeHTTP(rec, mailgunRequest(t, "signing-key", time.Now(), fields(), nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "a signed webhook is accepted once")
+
+	// Tokens are forgotten once their signature would have expired anyway
+	client.now = func() time.Time { return time.Now().Add(2 * DefaultSignatureMaxAge) }
+	rec = httptest.NewRecorder()
+	client.MailgunHandler().ServeHTTP(rec, mailgunRequest(t, "signing-key", client.now(), fields(), nil))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestClient_RawHandler_ThreadsReplies(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+
+	rec := httptest.NewRecorder()
+	client.RawHandler().ServeHTTP(rec, rawRequest("ingest-token", multipartEmail))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	first := receive(t, client)
+	assert.True(t, strings.HasPrefix(first.Content().Text(), "Database choice — final\n\nWe decided to use Postgres"))
+	assert.Contains(t, first.Content().Text(), "Attachment diagram.png (image/png, 8 bytes)")
+
+	reply := "From: bob@example.com\r\n" +
+		"To: decisions@example.com\r\n" +
+		"Subject: Re: Database choice\r\n" +
+		"Message-ID: <reply@example.com>\r\n" +
+		"In-Reply-To: <root@example.com>\r\n" +
+		"\r\n" +
+		"Agreed.\r\n"
+	rec = httptest.NewRecorder()
+	client.RawHandler().ServeHTTP(rec, rawRequest("ingest-token", reply))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	second := receive(t, client)
+
+	assert.Equal(t, "Agreed.", second.Content().Text())
+	assert.Equal(t, first.ThreadID(), second.ThreadID())
+}
+
+func TestClient_RawHandler_RejectsToken(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+
+	rec := httptest.NewRecorder()
+	client.RawHandler().ServeHTTP(rec, rawRequest("wrong", multipartEmail))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestClient_SkipsEmails(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "out of office", raw: "From: ana@example.com\r\nTo: decisions@example.com\r\nAuto-Submitted: auto-replied\r\nMessage-ID: <a@x>\r\n\r\nAway.\r\n"},
+		{name: "sent by the bot", raw: "From: quill@example.com\r\nTo: decisions@example.com\r\nMessage-ID: <b@x>\r\n\r\nCaptured.\r\n"},
+		{name: "other recipient", raw: "From: ana@example.com\r\nTo: team@example.com\r\nMessage-ID: <c@x>\r\n\r\nLunch?\r\n"},
+		{name: "sender not allowed", raw: "From: eve@example.net\r\nTo: decisions@example.com\r\nMessage-ID: <d@x>\r\n\r\nWe decided to wire the money.\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, nil)
+
+			rec := httptest.NewRecorder()
+			client.RawHandler().ServeHTTP(rec, rawRequest("ingest-token", tt.raw))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Empty(t, client.messageCh)
+		})
+	}
+}
+
+func TestClient_DropsDuplicateDeliveries(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		client.RawHandler().ServeHTTP(rec, rawRequest("ingest-token", multipartEmail))
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Len(t, client.messageCh, 1)
+}
+
+func TestClient_Replies(t *testing.T) {
+	client, mailer := newTestClient(t, nil)
+
+	rec := httptest.NewRecorder()
+	client.RawHandler().ServeHTTP(rec, rawRequest("ingest-token", multipartEmail))
+	require.Equal(t, http.StatusOK, rec.Code)
+	msg := receive(t, client)
+
+	require.NoError(t, client.ReplyDirect(context.Background(), msg.ID().String(), "Captured as a decision"))
+	require.Len(t, mailer.sent, 1)
+	sent := mailer.sent[0]
+	assert.Equal(t, "ana@example.com", sent.to)
+	assert.Equal(t, "Re: Database choice — final", sent.subject)
+	assert.Equal(t, "root@example.com", sent.inReplyTo)
+
+	rendered := string(sent.render(time.Now()))
+	assert.Contains(t, rendered, "In-Reply-To: <root@example.com>\r\n")
+	assert.Contains(t, rendered, "Auto-Submitted: auto-replied\r\n")
+	assert.Contains(t, rendered, "\r\n\r\nCaptured as a decision\r\n")
+
+	assert.Error(t, client.ReplyToMessage(context.Background(), "unknown", "reply"))
+}
+
+func TestClient_RepliesOnlyToAllowedSenders(t *testing.T) {
+	// Without an allowlist any sender's mail is documented, but no one is answered
+	client, mailer := newTestClient(t, func(c *Config) { c.AllowedSenders = nil })
+
+	rec := httptest.NewRecorder()
+	client.RawHandler().ServeHTTP(rec, rawRequest("ingest-token", multipartEmail))
+	require.Equal(t, http.StatusOK, rec.Code)
+	msg := receive(t, client)
+
+	require.NoError(t, client.ReplyDirect(context.Background(), msg.ID().String(), "Captured as a decision"))
+	assert.Empty(t, mailer.sent)
+
+	client.config.AllowedSenders = []string{"ana@example.com"}
+	require.NoError(t, client.ReplyDirect(context.Background(), msg.ID().String(), "Captured as a decision"))
+	assert.Len(t, mailer.sent, 1)
+}
+
+func TestClient_MessageRoute(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+
+	rec := httptest.NewRecorder()
+	client.RawHandler().ServeHTTP(rec, rawRequest("ingest-token", multipartEmail))
+	require.Equal(t, http.StatusOK, rec.Code)
+	msg := receive(t, client)
+
+	route, ok := client.MessageRoute(msg.ID().String())
+	require.True(t, ok)
+
+	worker, mailer := newTestClient(t, nil)
+	require.NoError(t, worker.RestoreMessageRoute(msg.ID().String(), route))
+	require.NoError(t, worker.ReplyToMessage(context.Background(), msg.ID().String(), "Captured"))
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "ana@example.com", mailer.sent[0].to)
+	assert.Equal(t, []string{"root@example.com"}, mailer.sent[0].references)
+
+	assert.ErrorIs(t, worker.RestoreMessageRoute("other", map[string]string{}), ErrInvalidRoute)
+}
@@ -0,0 +1,121 @@
+package email
+
+import (
+	"errors"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingWebhookSecret  = errors.New("a Mailgun signing key or an ingest token is required")
+	ErrInvalidCaptureAddress = errors.New("capture addresses must be email addresses")
+	ErrInvalidAllowedSender  = errors.New("allowed senders must be email addresses or domains")
+	ErrInvalidSMTPConfig     = errors.New("SMTP replies need a host:port address and a from address")
+	ErrInvalidSizeLimit      = errors.New("size limits cannot be negative")
+)
+
+const (
+	// DefaultMaxEmailBytes bounds the size of an accepted email, attachments included
+	DefaultMaxEmailBytes = 25 << 20
+	// DefaultMaxAttachmentText bounds the text taken from each text attachment
+	DefaultMaxAttachmentText = 64 << 10
+	// DefaultSignatureMaxAge is how old a Mailgun webhook signature may be
+	DefaultSignatureMaxAge = 5 * time.Minute
+)
+
+// Config contains the settings of email ingestion
+type Config struct {
+	// CaptureAddresses lists the addresses whose mail is documented (optional, any recipient)
+	CaptureAddresses []string
+
+	// AllowedSenders lists the addresses, like ana@example.com, and the domains, like example.com, whose mail is
+	// documented and answered (optional, any sender's mail is documented). Replies only go to allowed senders, so
+	// mail with a forged sender does not make the bot send mail to someone else; without the list none are sent.
+	AllowedSenders []string
+
+	// MailgunSigningKey verifies the signature of Mailgun inbound route webhooks
+	MailgunSigningKey string
+
+	// IngestToken authenticates raw MIME posts, e.g. from an SES notification handler or an MTA pipe
+	IngestToken string
+
+	// MaxEmailBytes bounds the size of an accepted email (default: 25MB)
+	MaxEmailBytes int64
+
+	// MaxAttachmentText bounds the text taken from each text attachment (default: 64KB)
+	MaxAttachmentText int
+
+	// SMTP sends confirmations as replies to the sender (optional, without it no replies are sent)
+	SMTP *SMTPConfig
+
+	// DebugMode logs why emails are skipped when true
+	DebugMode bool
+}
+
+// SMTPConfig contains the settings of the server sending replies
+type SMTPConfig struct {
+	// Addr is the host:port of the SMTP server
+	Addr string
+
+	// Username and Password authenticate with PLAIN auth (optional)
+	Username string
+	Password string
+
+	// From is the address replies are sent from, usually a capture address
+	From string
+}
+
+// NewConfig creates a new email configuration for Mailgun webhooks
+func NewConfig(mailgunSigningKey string) *Config {
+	return &Config{
+		MailgunSigningKey: mailgunSigningKey,
+		MaxEmailBytes:     DefaultMaxEmailBytes,
+		MaxAttachmentText: DefaultMaxAttachmentText,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.MailgunSigningKey) == "" && strings.TrimSpace(c.IngestToken) == "" {
+		return ErrMissingWebhookSecret
+	}
+
+	for _, address := range c.CaptureAddresses {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return ErrInvalidCaptureAddress
+		}
+	}
+
+	for _, sender := range c.AllowedSenders {
+		if !validAllowedSender(sender) {
+			return ErrInvalidAllowedSender
+		}
+	}
+
+	if c.MaxEmailBytes < 0 || c.MaxAttachmentText < 0 {
+		return ErrInvalidSizeLimit
+	}
+
+	if c.SMTP != nil {
+		if _, _, err := net.SplitHostPort(c.SMTP.Addr); err != nil {
+			return ErrInvalidSMTPConfig
+		}
+		if _, err := mail.ParseAddress(c.SMTP.From); err != nil {
+			return ErrInvalidSMTPConfig
+		}
+	}
+
+	return nil
+}
+
+// validAllowedSender checks an allowed sender is an email address or a domain
+func validAllowedSender(sender string) bool {
+	sender = strings.TrimSpace(sender)
+	if strings.Contains(sender, "@") {
+		address, err := mail.ParseAddress(sender)
+		return err == nil && address.Name == "" && address.Address == sender
+	}
+	return strings.Contains(sender, ".") && !strings.ContainsAny(sender, " <>,;") && !strings.HasPrefix(sender, ".")
+}
@@ -0,0 +1,42 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "ingest token only", mutate: func(c *Config) { c.MailgunSigningKey = ""; c.IngestToken = "token" }},
+		{name: "missing secrets", mutate: func(c *Config) { c.MailgunSigningKey = " " }, wantErr: ErrMissingWebhookSecret},
+		{name: "capture address with name", mutate: func(c *Config) { c.CaptureAddresses = []string{"Decisions <decisions@example.com>"} }},
+		{name: "invalid capture address", mutate: func(c *Config) { c.CaptureAddresses = []string{"decisions"} }, wantErr: ErrInvalidCaptureAddress},
+		{name: "allowed senders", mutate: func(c *Config) { c.AllowedSenders = []string{"ana@example.com", "example.org"} }},
+		{name: "allowed sender with name", mutate: func(c *Config) { c.AllowedSenders = []string{"Ana <ana@example.com>"} }, wantErr: ErrInvalidAllowedSender},
+		{name: "invalid allowed sender", mutate: func(c *Config) { c.AllowedSenders = []string{"example"} }, wantErr: ErrInvalidAllowedSender},
+		{name: "negative size limit", mutate: func(c *Config) { c.MaxEmailBytes = -1 }, wantErr: ErrInvalidSizeLimit},
+		{name: "valid SMTP", mutate: func(c *Config) { c.SMTP = &SMTPConfig{Addr: "smtp.example.com:587", From: "quill@example.com"} }},
+		{name: "SMTP without port", mutate: func(c *Config) { c.SMTP = &SMTPConfig{Addr: "smtp.example.com", From: "quill@example.com"} }, wantErr: ErrInvalidSMTPConfig},
+		{name: "SMTP without from", mutate: func(c *Config) { c.SMTP = &SMTPConfig{Addr: "smtp.example.com:587"} }, wantErr: ErrInvalidSMTPConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig("signing-key")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
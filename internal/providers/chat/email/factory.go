@@ -0,0 +1,22 @@
+package email
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures email clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new email client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateChatProvider creates a new email client that implements the ChatAccessProvider interface
+func (f *Factory) CreateChatProvider() (ports.ChatAccessProvider, error) {
+	return NewClient(f.config)
+}
@@ -0,0 +1,115 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// outgoingEmail is a reply sent by the bot
+type outgoingEmail struct {
+	from       string
+	to         string
+	subject    string
+	inReplyTo  string
+	references []string
+	body       string
+}
+
+// mailer sends emails, replaced in tests
+type mailer interface {
+	Send(ctx context.Context, email outgoingEmail) error
+}
+
+// smtpMailer sends emails through an SMTP server
+type smtpMailer struct {
+	config *SMTPConfig
+}
+
+func newSMTPMailer(config *SMTPConfig) *smtpMailer {
+	return &smtpMailer{config: config}
+}
+
+// Send delivers the email, giving up when ctx ends
+func (m *smtpMailer) Send(ctx context.Context, email outgoingEmail) error {
+	host, _, err := net.SplitHostPort(m.config.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address: %w", err)
+	}
+
+	// The envelope sender is the bare address, the From header may carry a display name
+	sender, err := parseAddress(email.from)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, host)
+	}
+
+	// net/smtp has no context support, the result is abandoned when ctx ends first
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.config.Addr, auth, sender, []string{email.to}, email.render(time.Now()))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// replyFor builds the reply to an email, threaded below it in the sender's mail client
+func replyFor(data MessageData, from, content string) outgoingEmail {
+	subject := data.Subject
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = "Re: " + subject
+	}
+	return outgoingEmail{
+		from:       from,
+		to:         data.From,
+		subject:    strings.TrimSpace(subject),
+		inReplyTo:  data.MessageID,
+		references: data.References,
+		body:       content,
+	}
+}
+
+// render formats the email as an RFC 5322 message. Replies are marked as sent automatically, so
+// the sender's out-of-office reply does not answer them.
+func (e outgoingEmail) render(now time.Time) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		b.WriteString(fmt.Sprintf("%s: %s\r\n", name, value))
+	}
+
+	header("From", e.from)
+	header("To", e.to)
+	header("Subject", mime.QEncoding.Encode("utf-8", e.subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@quill>", common.GenerateID()))
+	if e.inReplyTo != "" {
+		header("In-Reply-To", "<"+e.inReplyTo+">")
+	}
+	if len(e.references) > 0 {
+		header("References", "<"+strings.Join(e.references, "> <")+">")
+	}
+	header("Auto-Submitted", "auto-replied")
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(e.body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
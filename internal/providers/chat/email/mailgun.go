@@ -0,0 +1,152 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrReplayedWebhook  = errors.New("webhook token already used")
+)
+
+// verifyMailgunSignature checks the webhook was signed with the signing key within the allowed age
+func verifyMailgunSignature(key, timestamp, token, signature string, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > DefaultSignatureMaxAge || age < -DefaultSignatureMaxAge {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// tokenCache remembers the tokens of verified Mailgun webhooks while their signature could still be accepted, so a
+// captured webhook cannot be posted again
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time // When each token can be forgotten
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: make(map[string]time.Time)}
+}
+
+// use records a token and reports whether it was new. A signature is accepted while its timestamp is within
+// DefaultSignatureMaxAge either way of the clock, so tokens are kept for twice that.
+func (c *tokenCache) use(token string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for seen, expiresAt := range c.tokens {
+		if !now.Before(expiresAt) {
+			delete(c.tokens, seen)
+		}
+	}
+	if _, ok := c.tokens[token]; ok {
+		return false
+	}
+	c.tokens[token] = now.Add(2 * DefaultSignatureMaxAge)
+	return true
+}
+
+// forget drops a token whose email could not be delivered, so the retried webhook is accepted
+func (c *tokenCache) forget(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.tokens, token)
+}
+
+// parseMailgun reads an email from the multipart form of a Mailgun inbound route webhook
+func parseMailgun(r *http.Request, maxAttachmentText int) (*inboundEmail, error) {
+	form := r.MultipartForm
+	value := func(name string) string {
+		if values := form.Value[name]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	e := &inboundEmail{
+		messageID:  trimMessageID(value("Message-Id")),
+		inReplyTo:  trimMessageID(value("In-Reply-To")),
+		references: parseReferences(value("References")),
+		subject:    strings.TrimSpace(value("subject")),
+	}
+	if from, err := mail.ParseAddress(value("from")); err == nil {
+		e.from = from
+	} else if sender := strings.TrimSpace(value("sender")); sender != "" {
+		e.from = &mail.Address{Address: sender}
+	}
+	for _, recipient := range strings.Split(value("recipient"), ",") {
+		if recipient = strings.ToLower(strings.TrimSpace(recipient)); recipient != "" {
+			e.recipients = append(e.recipients, recipient)
+		}
+	}
+
+	// message-headers holds every header as [name, value] pairs
+	var headers [][]string
+	_ = json.Unmarshal([]byte(value("message-headers")), &headers)
+	var autoSubmitted, precedence string
+	for _, header := range headers {
+		if len(header) != 2 {
+			continue
+		}
+		switch strings.ToLower(header[0]) {
+		case "auto-submitted":
+			autoSubmitted = header[1]
+		case "precedence":
+			precedence = header[1]
+		}
+	}
+	e.autoSubmitted = isAutoSubmitted(autoSubmitted, precedence)
+
+	// Mailgun already removed the quoted conversation and signature from stripped-text
+	switch {
+	case strings.TrimSpace(value("stripped-text")) != "":
+		e.text = strings.TrimSpace(value("stripped-text"))
+	case strings.TrimSpace(value("body-plain")) != "":
+		e.text = stripQuoted(value("body-plain"))
+	default:
+		e.text = stripQuoted(htmlToText(value("body-html")))
+	}
+
+	count, _ := strconv.Atoi(value("attachment-count"))
+	for n := 1; n <= count; n++ {
+		files := form.File["attachment-"+strconv.Itoa(n)]
+		if len(files) == 0 {
+			continue
+		}
+		file, err := files[0].Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		contentType := strings.SplitN(files[0].Header.Get("Content-Type"), ";", 2)[0]
+		e.attachments = append(e.attachments, newAttachment(files[0].Filename, strings.TrimSpace(contentType), content, maxAttachmentText))
+	}
+	return e, nil
+}
@@ -0,0 +1,33 @@
+package email
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// MessageData is the email a message came from
+type MessageData struct {
+	// CaptureAddress is the address the email was sent to, the domain channel ID
+	CaptureAddress string
+
+	// MessageID is the Message-ID of the email, without angle brackets
+	MessageID string
+
+	// References lists the Message-IDs of the conversation up to and including the email
+	References []string
+
+	// Subject is the subject of the email
+	Subject string
+
+	// From is the address of the sender, replies are sent to it
+	From string
+}
+
+// parseAddress returns the lowercase address of an email address with an optional display name
+func parseAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(parsed.Address), nil
+}
@@ -0,0 +1,288 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+)
+
+var ErrMalformedEmail = errors.New("malformed email")
+
+// inboundEmail is a received email before it becomes a domain message
+type inboundEmail struct {
+	messageID     string // Message-ID without angle brackets
+	inReplyTo     string
+	references    []string
+	from          *mail.Address
+	recipients    []string // Lowercase addresses the email was sent to
+	subject       string
+	text          string // Body without the quoted conversation
+	attachments   []attachment
+	autoSubmitted bool // Out-of-office replies, bounces and other mail sent by machines
+}
+
+// attachment is a file sent with an email, text files keep their content
+type attachment struct {
+	name        string
+	contentType string
+	size        int
+	text        string
+}
+
+// threadRoot returns the Message-ID of the email that started the conversation
+func (e *inboundEmail) threadRoot() string {
+	if len(e.references) > 0 {
+		return e.references[0]
+	}
+	if e.inReplyTo != "" {
+		return e.inReplyTo
+	}
+	return e.messageID
+}
+
+// isReply checks if the email answers an earlier one
+func (e *inboundEmail) isReply() bool {
+	return e.threadRoot() != e.messageID
+}
+
+// sender returns the display name of the sender, or the address without one
+func (e *inboundEmail) sender() string {
+	if e.from == nil {
+		return "unknown"
+	}
+	if name := strings.TrimSpace(e.from.Name); name != "" {
+		return name
+	}
+	return e.from.Address
+}
+
+var (
+	wordDecoder = &mime.WordDecoder{}
+
+	// quoteHeaderPattern matches the line mail clients put above the quoted conversation
+	quoteHeaderPattern = regexp.MustCompile(`(?i)^(on\s.+\swrote:|-+\s*original message\s*-+|from:\s.+)$`)
+	htmlBreakPattern   = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</h[1-6]>`)
+	htmlTagPattern     = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlHiddenPattern  = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+)
+
+// parseMIME parses a raw RFC 5322 email
+func parseMIME(raw []byte, maxAttachmentText int) (*inboundEmail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEmail, err)
+	}
+
+	e := &inboundEmail{
+		messageID:     trimMessageID(msg.Header.Get("Message-Id")),
+		inReplyTo:     trimMessageID(msg.Header.Get("In-Reply-To")),
+		references:    parseReferences(msg.Header.Get("References")),
+		subject:       decodeHeader(msg.Header.Get("Subject")),
+		autoSubmitted: isAutoSubmitted(msg.Header.Get("Auto-Submitted"), msg.Header.Get("Precedence")),
+	}
+	if from, err := msg.Header.AddressList("From"); err == nil && len(from) > 0 {
+		e.from = from[0]
+	}
+	for _, field := range []string{"To", "Cc"} {
+		if addresses, err := msg.Header.AddressList(field); err == nil {
+			for _, address := range addresses {
+				e.recipients = append(e.recipients, strings.ToLower(address.Address))
+			}
+		}
+	}
+
+	var body parsedBody
+	if err := body.walk(textproto.MIMEHeader(msg.Header), msg.Body, maxAttachmentText); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEmail, err)
+	}
+	e.text = body.text()
+	e.attachments = body.attachments
+	return e, nil
+}
+
+// parsedBody collects the text and attachments of a MIME body
+type parsedBody struct {
+	plain       string
+	html        string
+	attachments []attachment
+}
+
+// walk visits a MIME part and its children
+func (b *parsedBody) walk(header textproto.MIMEHeader, body io.Reader, maxAttachmentText int) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := b.walk(part.Header, part, maxAttachmentText); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := decodeHeader(dispositionParams["filename"])
+	if name == "" {
+		name = decodeHeader(params["name"])
+	}
+
+	if disposition != "attachment" && name == "" {
+		switch {
+		case mediaType == "text/plain" && b.plain == "":
+			b.plain = string(content)
+			return nil
+		case mediaType == "text/html" && b.html == "":
+			b.html = string(content)
+			return nil
+		}
+	}
+
+	if name == "" {
+		name = "unnamed"
+	}
+	b.attachments = append(b.attachments, newAttachment(name, mediaType, content, maxAttachmentText))
+	return nil
+}
+
+// text returns the body as plain text without the quoted conversation
+func (b *parsedBody) text() string {
+	text := b.plain
+	if strings.TrimSpace(text) == "" {
+		text = htmlToText(b.html)
+	}
+	return stripQuoted(text)
+}
+
+// newAttachment describes a file, keeping the beginning of text files
+func newAttachment(name, contentType string, content []byte, maxText int) attachment {
+	a := attachment{name: name, contentType: contentType, size: len(content)}
+	if isTextType(contentType) {
+		if len(content) > maxText {
+			content = content[:maxText]
+		}
+		a.text = strings.ToValidUTF8(string(content), "")
+	}
+	return a
+}
+
+func isTextType(contentType string) bool {
+	switch contentType {
+	case "text/plain", "text/markdown", "text/csv", "text/x-markdown":
+		return true
+	}
+	return false
+}
+
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// newlineStripper drops line breaks, which base64 decoding does not accept
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (s *newlineStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, c := range p[:n] {
+		if c != '\r' && c != '\n' {
+			p[kept] = c
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// stripQuoted drops the quoted conversation and signature a reply carries below the new text
+func stripQuoted(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		// The signature delimiter is "-- ", quoted-printable decoding drops its trailing space
+		if trimmed == "--" || quoteHeaderPattern.MatchString(trimmed) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// htmlToText reduces an HTML body to its text
+func htmlToText(body string) string {
+	body = htmlHiddenPattern.ReplaceAllString(body, "")
+	body = htmlBreakPattern.ReplaceAllString(body, "\n")
+	body = htmlTagPattern.ReplaceAllString(body, "")
+	return html.UnescapeString(body)
+}
+
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+func trimMessageID(id string) string {
+	return strings.Trim(strings.TrimSpace(id), "<>")
+}
+
+func parseReferences(references string) []string {
+	var ids []string
+	for _, id := range strings.Fields(references) {
+		if id = trimMessageID(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// isAutoSubmitted checks the headers machines set on mail nobody wrote, like out-of-office replies
+func isAutoSubmitted(autoSubmitted, precedence string) bool {
+	autoSubmitted = strings.ToLower(strings.TrimSpace(autoSubmitted))
+	if autoSubmitted != "" && autoSubmitted != "no" {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(precedence)) {
+	case "bulk", "junk", "auto_reply", "list":
+		return true
+	}
+	return false
+}
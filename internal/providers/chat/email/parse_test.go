@@ -0,0 +1,138 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multipartEmail = "From: Ana Lima <ana@example.com>\r\n" +
+	"To: decisions@example.com\r\n" +
+	"Cc: team@example.com\r\n" +
+	"Subject: =?utf-8?q?Database_choice_=E2=80=94_final?=\r\n" +
+	"Message-ID: <root@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"We decided to use Postgres for the event store.=\r\n" +
+	"\r\n" +
+	"-- \r\n" +
+	"Ana\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>We decided to use Postgres for the event store.</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/markdown; name=notes.md\r\n" +
+	"Content-Disposition: attachment; filename=notes.md\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"IyBOb3RlcwpQb3N0Z3JlcyB3\r\n" +
+	"aW5zLg==\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-Disposition: attachment; filename=diagram.png\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"iVBORw0KGgo=\r\n" +
+	"--outer--\r\n"
+
+func TestParseMIME(t *testing.T) {
+	e, err := parseMIME([]byte(multipartEmail), DefaultMaxAttachmentText)
+	require.NoError(t, err)
+
+	assert.Equal(t, "root@example.com", e.messageID)
+	assert.Equal(t, "Database choice — final", e.subject)
+	assert.Equal(t, "Ana Lima", e.sender())
+	assert.Equal(t, []string{"decisions@example.com", "team@example.com"}, e.recipients)
+	assert.Equal(t, "We decided to use Postgres for the event store.", e.text)
+	assert.False(t, e.isReply())
+	assert.False(t, e.autoSubmitted)
+
+	require.Len(t, e.attachments, 2)
+	assert.Equal(t, attachment{name: "notes.md", contentType: "text/markdown", size: 22, text: "# Notes\nPostgres wins."}, e.attachments[0])
+	assert.Equal(t, "diagram.png", e.attachments[1].name)
+	assert.Empty(t, e.attachments[1].text)
+}
+
+func TestParseMIME_Reply(t *testing.T) {
+	raw := "From: bob@example.com\r\n" +
+		"To: decisions@example.com\r\n" +
+		"Subject: Re: Database choice\r\n" +
+		"Message-ID: <reply-2@example.com>\r\n" +
+		"In-Reply-To: <reply-1@example.com>\r\n" +
+		"References: <root@example.com> <reply-1@example.com>\r\n" +
+		"Content-Type: text/html\r\n" +
+		"\r\n" +
+		"<div>Agreed, &amp; let&#39;s ship it.</div><div>On Mon, Ana wrote:</div><blockquote>Postgres</blockquote>\r\n"
+
+	e, err := parseMIME([]byte(raw), DefaultMaxAttachmentText)
+	require.NoError(t, err)
+
+	assert.Equal(t, "root@example.com", e.threadRoot())
+	assert.True(t, e.isReply())
+	assert.Equal(t, "bob@example.com", e.sender())
+	assert.Equal(t, "Agreed, & let's ship it.", e.text)
+}
+
+func TestParseMIME_Malformed(t *testing.T) {
+	_, err := parseMIME([]byte("not an email"), DefaultMaxAttachmentText)
+	assert.ErrorIs(t, err, ErrMalformedEmail)
+}
+
+func TestStripQuoted(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain text", text: "Ship it on Friday.\n", want: "Ship it on Friday."},
+		{name: "quoted lines", text: "Agreed.\n> Ship it on Friday?\n", want: "Agreed."},
+		{name: "reply header", text: "Agreed.\n\nOn Tue, 3 Jun 2025, Ana <ana@example.com> wrote:\nShip it?", want: "Agreed."},
+		{name: "outlook header", text: "Agreed.\n-----Original Message-----\nFrom: Ana", want: "Agreed."},
+		{name: "signature", text: "Agreed.\n-- \nBob\nCTO", want: "Agreed."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stripQuoted(tt.text))
+		})
+	}
+}
+
+func TestIsAutoSubmitted(t *testing.T) {
+	tests := []struct {
+		name          string
+		autoSubmitted string
+		precedence    string
+		want          bool
+	}{
+		{name: "written by a person", want: false},
+		{name: "explicitly not automatic", autoSubmitted: "no", want: false},
+		{name: "out of office", autoSubmitted: "auto-replied", want: true},
+		{name: "bulk mail", precedence: "Bulk", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isAutoSubmitted(tt.autoSubmitted, tt.precedence))
+		})
+	}
+}
+
+func TestNewAttachment_TruncatesText(t *testing.T) {
+	a := newAttachment("log.txt", "text/plain", []byte(strings.Repeat("a", 10)), 4)
+
+	assert.Equal(t, 10, a.size)
+	assert.Equal(t, "aaaa", a.text)
+}
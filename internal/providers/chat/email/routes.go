@@ -0,0 +1,51 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidRoute = errors.New("invalid message route")
+)
+
+// Keys of the route describing the email a message came from
+const (
+	routeCapture    = "email_capture_address"
+	routeMessageID  = "email_message_id"
+	routeReferences = "email_references"
+	routeSubject    = "email_subject"
+	routeFrom       = "email_from"
+)
+
+// MessageRoute returns the email a received message came from, so another instance can reply to it
+func (c *Client) MessageRoute(messageID string) (map[string]string, bool) {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return nil, false
+	}
+	return map[string]string{
+		routeCapture:    data.CaptureAddress,
+		routeMessageID:  data.MessageID,
+		routeReferences: strings.Join(data.References, " "),
+		routeSubject:    data.Subject,
+		routeFrom:       data.From,
+	}, true
+}
+
+// RestoreMessageRoute makes a message received by another instance known, replies to it reach its sender
+func (c *Client) RestoreMessageRoute(messageID string, route map[string]string) error {
+	if route[routeCapture] == "" || route[routeFrom] == "" {
+		return fmt.Errorf("%w: message %s has no capture address or sender", ErrInvalidRoute, messageID)
+	}
+
+	c.rememberMessage(messageID, MessageData{
+		CaptureAddress: route[routeCapture],
+		MessageID:      route[routeMessageID],
+		References:     strings.Fields(route[routeReferences]),
+		Subject:        route[routeSubject],
+		From:           route[routeFrom],
+	})
+	return nil
+}
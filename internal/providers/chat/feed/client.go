@@ -0,0 +1,300 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid feed configuration")
+)
+
+// maxSummaryRunes bounds the part of an item's description that is ingested
+const maxSummaryRunes = 2000
+
+// Client implements the ChatAccessProvider interface for RSS and Atom feeds. Every poll interval the
+// configured feeds are fetched and their new items are delivered as status or information messages,
+// so weekly rollups include dependency and vendor updates next to the team's own status posts.
+// Feeds cannot be replied to, so confirmations and reactions are dropped.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	messageCh  chan *domain.Message
+
+	lock  sync.Mutex
+	state map[string]*feedState // Keyed by feed
+
+	pollSupervisor *supervisor.Supervisor // Restarts the poller
+}
+
+// feedState is what the poller remembers of a feed between fetches
+type feedState struct {
+	primed       bool                // The feed was fetched once, its items from then on are new
+	seen         map[string]struct{} // IDs of the items in the feed when it was last fetched
+	etag         string
+	lastModified string
+}
+
+// NewClient creates a new feed client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	pollSupervisor, err := supervisor.New("feed-poller", config.Supervision)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	return &Client{
+		config:         config,
+		httpClient:     httpClient,
+		messageCh:      make(chan *domain.Message, 100),
+		state:          make(map[string]*feedState),
+		pollSupervisor: pollSupervisor,
+	}, nil
+}
+
+// SendMessage does nothing, feeds cannot receive messages
+func (c *Client) SendMessage(ctx context.Context, channelID, content string) error {
+	return nil
+}
+
+// ReplyToMessage does nothing, feed items cannot be replied to
+func (c *Client) ReplyToMessage(ctx context.Context, messageID, content string) error {
+	return nil
+}
+
+// AddReaction does nothing, feed items cannot be reacted to
+func (c *Client) AddReaction(ctx context.Context, messageID, emoji string) error {
+	return nil
+}
+
+// ListenForMessages starts polling the feeds. The poller is supervised: it is restarted with
+// backoff after panicking until ctx is canceled.
+func (c *Client) ListenForMessages(ctx context.Context) (<-chan *domain.Message, error) {
+	go c.pollSupervisor.Run(ctx, c.pollFeeds)
+	return c.messageCh, nil
+}
+
+// Health reports the state of the supervised poller
+func (c *Client) Health() []supervisor.Health {
+	return []supervisor.Health{c.pollSupervisor.Health()}
+}
+
+// HandleInteraction is not supported, feeds have no interactive components
+func (c *Client) HandleInteraction(ctx context.Context, interaction interface{}) error {
+	return fmt.Errorf("unsupported interaction type: %T", interaction)
+}
+
+// pollFeeds fetches every feed each poll interval until ctx is canceled. A feed that fails to
+// load is logged and tried again at the next interval.
+func (c *Client) pollFeeds(ctx context.Context) error {
+	interval := c.config.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, feed := range c.config.Feeds {
+			if err := c.poll(ctx, feed); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to poll feed %s: %v", feed.key(), err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches a feed and delivers its new items, oldest first
+func (c *Client) poll(ctx context.Context, feed FeedConfig) error {
+	state := c.feedState(feed)
+
+	fetched, err := c.fetch(ctx, feed, state)
+	if err != nil || fetched == nil {
+		return err
+	}
+
+	name := strings.TrimSpace(feed.Name)
+	if name == "" {
+		name = firstNonEmpty(fetched.title, feed.URL)
+	}
+
+	// Items not delivered yet stay unseen, so the next poll delivers them
+	seen := make(map[string]struct{}, len(fetched.items))
+	deliver := state.primed || c.config.Backfill
+	var fresh []item
+	for _, it := range fetched.items {
+		if _, ok := state.seen[it.id]; ok || !deliver {
+			seen[it.id] = struct{}{}
+		} else {
+			fresh = append(fresh, it)
+		}
+	}
+
+	for i := len(fresh) - 1; i >= 0; i-- {
+		it := fresh[i]
+		if err := c.deliver(ctx, feed, name, it); err != nil {
+			c.updateState(feed, seen, false)
+			return err
+		}
+		seen[it.id] = struct{}{}
+	}
+
+	c.updateState(feed, seen, true)
+	return nil
+}
+
+// fetch downloads a feed, returning nil when it did not change since the last fetch
+func (c *Client) fetch(ctx context.Context, feed FeedConfig, state feedState) (*parsedFeed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	if state.etag != "" {
+		req.Header.Set("If-None-Match", state.etag)
+	}
+	if state.lastModified != "" {
+		req.Header.Set("If-Modified-Since", state.lastModified)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	parsed, err := parseFeed(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.state[feed.key()].etag = resp.Header.Get("ETag")
+	c.state[feed.key()].lastModified = resp.Header.Get("Last-Modified")
+	c.lock.Unlock()
+	return parsed, nil
+}
+
+// deliver converts a feed item into a domain message and hands it to the listener
+func (c *Client) deliver(ctx context.Context, feed FeedConfig, name string, it item) error {
+	messageContent, err := domain.NewMessageContent(itemText(name, it))
+	if err != nil {
+		if c.config.DebugMode {
+			log.Printf("Skipping item %s of feed %s: %v", it.id, name, err)
+		}
+		return nil
+	}
+
+	// Each item is a conversation of its own
+	domainMsg, err := domain.NewMessage(
+		common.GenerateID(),
+		name,
+		messageContent,
+		feed.messageType(),
+		domain.CategoryOther,
+		nil, // no references initially
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	domainMsg.SetChannelID(feed.channel())
+	domainMsg.FixType(feed.messageType())
+
+	select {
+	case c.messageCh <- domainMsg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// feedState returns a copy of what is remembered of a feed
+func (c *Client) feedState(feed FeedConfig) feedState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	state, ok := c.state[feed.key()]
+	if !ok {
+		state = &feedState{seen: make(map[string]struct{})}
+		c.state[feed.key()] = state
+	}
+	return *state
+}
+
+// updateState remembers the items of the last fetch, items dropping off the feed are forgotten.
+// An incomplete fetch forgets the validators, so the next fetch returns the whole feed again.
+func (c *Client) updateState(feed FeedConfig, seen map[string]struct{}, complete bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	state := c.state[feed.key()]
+	state.seen = seen
+	state.primed = true
+	if !complete {
+		state.etag = ""
+		state.lastModified = ""
+	}
+}
+
+// itemText builds the message content of a feed item: the feed and title, the description and the link
+func itemText(name string, it item) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s: %s", name, firstNonEmpty(it.title, "New entry")))
+
+	if summary := truncate(it.summary, maxSummaryRunes); summary != "" {
+		b.WriteString(fmt.Sprintf("\n\n%s", summary))
+	}
+	if it.link != "" {
+		b.WriteString(fmt.Sprintf("\n\n%s", it.link))
+	}
+	return b.String()
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
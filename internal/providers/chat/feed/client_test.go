@@ -0,0 +1,163 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeed serves an RSS feed whose items can be added while the test runs
+type fakeFeed struct {
+	mu       sync.Mutex
+	items    []string // Titles, newest first
+	requests int
+	server   *httptest.Server
+}
+
+func newFakeFeed(t *testing.T, titles ...string) *fakeFeed {
+	t.Helper()
+	f := &fakeFeed{items: titles}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeFeed) publish(title string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items = append([]string{title}, f.items...)
+}
+
+func (f *fakeFeed) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	etag := fmt.Sprintf(`"%d"`, len(f.items))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(`<rss version="2.0"><channel><title>Vendor status</title>`)
+	for _, title := range f.items {
+		b.WriteString(fmt.Sprintf(`<item><guid>%s</guid><title>%s</title><link>https://status.example.com/%s</link></item>`, title, title, title))
+	}
+	b.WriteString(`</channel></rss>`)
+	w.Header().Set("ETag", etag)
+	w.Write([]byte(b.String()))
+}
+
+func newTestClient(t *testing.T, feed FeedConfig, backfill bool) *Client {
+	t.Helper()
+	config := NewConfig(feed)
+	config.Backfill = backfill
+
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	return client
+}
+
+func drain(client *Client) []*domain.Message {
+	var messages []*domain.Message
+	for {
+		select {
+		case msg := <-client.messageCh:
+			messages = append(messages, msg)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestClient_Poll_DeliversNewItems(t *testing.T) {
+	feed := newFakeFeed(t, "incident-1")
+	config := FeedConfig{URL: feed.server.URL, Channel: "vendors"}
+	client := newTestClient(t, config, false)
+	ctx := context.Background()
+
+	// Items already in the feed are history, not news
+	require.NoError(t, client.poll(ctx, config))
+	assert.Empty(t, drain(client))
+
+	feed.publish("incident-2")
+	feed.publish("incident-3")
+	require.NoError(t, client.poll(ctx, config))
+
+	messages := drain(client)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Vendor status: incident-2\n\nhttps://status.example.com/incident-2", messages[0].Content().Text())
+	assert.Equal(t, "Vendor status", messages[0].Sender())
+	assert.Equal(t, "vendors", messages[0].ChannelID())
+	assert.Equal(t, domain.MessageTypeStatus, messages[0].Type())
+	assert.True(t, messages[0].HasFixedType())
+	assert.NotEqual(t, messages[0].ThreadID(), messages[1].ThreadID())
+	assert.Contains(t, messages[1].Content().Text(), "incident-3")
+}
+
+func TestClient_Poll_Backfill(t *testing.T) {
+	feed := newFakeFeed(t, "v2", "v1")
+	config := FeedConfig{URL: feed.server.URL, Name: "SDK releases", Type: domain.MessageTypeInformation}
+	client := newTestClient(t, config, true)
+
+	require.NoError(t, client.poll(context.Background(), config))
+
+	messages := drain(client)
+	require.Len(t, messages, 2)
+	assert.True(t, strings.HasPrefix(messages[0].Content().Text(), "SDK releases: v1"))
+	assert.Equal(t, domain.MessageTypeInformation, messages[1].Type())
+	assert.Equal(t, DefaultChannel, messages[1].ChannelID())
+}
+
+func TestClient_Poll_UnchangedFeed(t *testing.T) {
+	feed := newFakeFeed(t, "v1")
+	config := FeedConfig{URL: feed.server.URL}
+	client := newTestClient(t, config, true)
+	ctx := context.Background()
+
+	require.NoError(t, client.poll(ctx, config))
+	require.NoError(t, client.poll(ctx, config))
+
+	assert.Len(t, drain(client), 1)
+	assert.Equal(t, 2, feed.requests)
+}
+
+func TestClient_Poll_RedeliversAfterCanceledDelivery(t *testing.T) {
+	feed := newFakeFeed(t, "v1")
+	config := FeedConfig{URL: feed.server.URL}
+	client := newTestClient(t, config, false)
+	client.messageCh = make(chan *domain.Message) // Nobody listens, so delivery waits for ctx
+
+	require.NoError(t, client.poll(context.Background(), config))
+	feed.publish("v2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, client.poll(ctx, config), context.Canceled)
+
+	client.messageCh = make(chan *domain.Message, 10)
+	require.NoError(t, client.poll(context.Background(), config))
+	messages := drain(client)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Content().Text(), "v2")
+}
+
+func TestClient_Poll_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	config := FeedConfig{URL: server.URL}
+	client := newTestClient(t, config, false)
+
+	assert.ErrorContains(t, client.poll(context.Background(), config), "unexpected status code: 502")
+}
@@ -0,0 +1,146 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingFeeds      = errors.New("at least one feed is required")
+	ErrInvalidFeedURL    = errors.New("feed URL must be an absolute http or https URL")
+	ErrDuplicateFeed     = errors.New("feed names must be unique")
+	ErrInvalidFeedType   = errors.New("feeds are ingested as status or information messages")
+	ErrInvalidPollPeriod = errors.New("poll interval must be at least a minute")
+)
+
+const (
+	// DefaultPollInterval is how often feeds are fetched
+	DefaultPollInterval = 15 * time.Minute
+	// DefaultChannel is the channel of items from feeds without one
+	DefaultChannel = "feeds"
+	// DefaultTimeout is the default timeout for fetching a feed
+	DefaultTimeout = 30 * time.Second
+)
+
+// FeedConfig describes one RSS or Atom feed
+type FeedConfig struct {
+	// URL is the address of the feed, see GitHubReleasesURL for release feeds
+	URL string
+
+	// Name labels the feed's items, like "Stripe changelog" (optional, defaults to the feed's title)
+	Name string
+
+	// Channel is the channel of the feed's items, and through its binding their project (default: "feeds")
+	Channel string
+
+	// Type is the message type of the feed's items, status or information (default: status)
+	Type domain.MessageType
+}
+
+// Config contains the settings of feed ingestion
+type Config struct {
+	// Feeds lists the feeds to poll
+	Feeds []FeedConfig
+
+	// PollInterval is how often feeds are fetched (default: 15 minutes)
+	PollInterval time.Duration
+
+	// Backfill ingests the items a feed already has when it is first fetched. Without it only items
+	// published afterwards are ingested, so adding a feed does not flood the documentation.
+	Backfill bool
+
+	// DebugMode logs skipped items when true
+	DebugMode bool
+
+	// Supervision controls restarting the poller after failures (optional)
+	Supervision *supervisor.Config
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewConfig creates a new feed configuration
+func NewConfig(feeds ...FeedConfig) *Config {
+	return &Config{
+		Feeds:        feeds,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// GitHubReleasesURL returns the Atom feed of a GitHub repository's releases, the repository named like "owner/repo"
+func GitHubReleasesURL(repository string) string {
+	return fmt.Sprintf("https://github.com/%s/releases.atom", strings.Trim(strings.TrimSpace(repository), "/"))
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if len(c.Feeds) == 0 {
+		return ErrMissingFeeds
+	}
+
+	names := make(map[string]bool, len(c.Feeds))
+	for _, feed := range c.Feeds {
+		u, err := url.Parse(feed.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidFeedURL, feed.URL)
+		}
+		key := strings.ToLower(feed.key())
+		if names[key] {
+			return fmt.Errorf("%w: %q", ErrDuplicateFeed, feed.key())
+		}
+		names[key] = true
+
+		switch feed.Type {
+		case "", domain.MessageTypeStatus, domain.MessageTypeInformation:
+		default:
+			return ErrInvalidFeedType
+		}
+	}
+
+	if c.PollInterval != 0 && c.PollInterval < time.Minute {
+		return ErrInvalidPollPeriod
+	}
+
+	if c.Supervision != nil {
+		if err := c.Supervision.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// key identifies the feed, by its name or else its URL
+func (f FeedConfig) key() string {
+	if name := strings.TrimSpace(f.Name); name != "" {
+		return name
+	}
+	return f.URL
+}
+
+func (f FeedConfig) channel() string {
+	if channel := strings.TrimSpace(f.Channel); channel != "" {
+		return channel
+	}
+	return DefaultChannel
+}
+
+func (f FeedConfig) messageType() domain.MessageType {
+	if f.Type == "" {
+		return domain.MessageTypeStatus
+	}
+	return f.Type
+}
@@ -0,0 +1,49 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "information feed", mutate: func(c *Config) { c.Feeds[0].Type = domain.MessageTypeInformation }},
+		{name: "missing feeds", mutate: func(c *Config) { c.Feeds = nil }, wantErr: ErrMissingFeeds},
+		{name: "relative URL", mutate: func(c *Config) { c.Feeds[0].URL = "status.example.com/feed" }, wantErr: ErrInvalidFeedURL},
+		{name: "decision feed", mutate: func(c *Config) { c.Feeds[0].Type = domain.MessageTypeDecision }, wantErr: ErrInvalidFeedType},
+		{
+			name: "duplicate names",
+			mutate: func(c *Config) {
+				c.Feeds = append(c.Feeds, FeedConfig{URL: "https://example.com/other.xml", Name: "stripe changelog"})
+			},
+			wantErr: ErrDuplicateFeed,
+		},
+		{name: "polling too often", mutate: func(c *Config) { c.PollInterval = time.Second }, wantErr: ErrInvalidPollPeriod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(FeedConfig{URL: "https://stripe.com/changelog.rss", Name: "Stripe changelog"})
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGitHubReleasesURL(t *testing.T) {
+	assert.Equal(t, "https://github.com/slack-go/slack/releases.atom", GitHubReleasesURL(" /slack-go/slack/ "))
+}
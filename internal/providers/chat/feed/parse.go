@@ -0,0 +1,149 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var ErrUnsupportedFeed = errors.New("not an RSS or Atom feed")
+
+// item is an entry of a feed, RSS and Atom alike
+type item struct {
+	id      string
+	title   string
+	link    string
+	summary string
+}
+
+// parsedFeed is a fetched feed with its entries, newest first as published by most feeds
+type parsedFeed struct {
+	title string
+	items []item
+}
+
+// rssDocument is an RSS 2.0 feed
+type rssDocument struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			GUID        string `xml:"guid"`
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// atomDocument is an Atom feed, the format of GitHub release feeds
+type atomDocument struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary string `xml:"summary"`
+		Content string `xml:"content"`
+	} `xml:"entry"`
+}
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</h[1-6]>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinePattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// parseFeed parses an RSS 2.0 or Atom feed
+func parseFeed(body []byte) (*parsedFeed, error) {
+	root, err := rootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss":
+		var doc rssDocument
+		if err := xml.Unmarshal(body, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		}
+		feed := &parsedFeed{title: strings.TrimSpace(doc.Channel.Title)}
+		for _, entry := range doc.Channel.Items {
+			it := item{
+				id:      firstNonEmpty(entry.GUID, entry.Link, entry.Title),
+				title:   strings.TrimSpace(entry.Title),
+				link:    strings.TrimSpace(entry.Link),
+				summary: htmlToText(entry.Description),
+			}
+			feed.items = append(feed.items, it)
+		}
+		return feed, nil
+
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(body, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+		}
+		feed := &parsedFeed{title: strings.TrimSpace(doc.Title)}
+		for _, entry := range doc.Entries {
+			it := item{
+				title:   strings.TrimSpace(entry.Title),
+				summary: htmlToText(firstNonEmpty(entry.Content, entry.Summary)),
+			}
+			for _, link := range entry.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					it.link = strings.TrimSpace(link.Href)
+					break
+				}
+			}
+			it.id = firstNonEmpty(entry.ID, it.link, it.title)
+			feed.items = append(feed.items, it)
+		}
+		return feed, nil
+
+	default:
+		return nil, fmt.Errorf("%w: root element %q", ErrUnsupportedFeed, root)
+	}
+}
+
+// rootElement returns the name of the document's first element
+func rootElement(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrUnsupportedFeed, err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// htmlToText reduces the HTML of an item's description to its text
+func htmlToText(body string) string {
+	body = htmlBreakPattern.ReplaceAllString(body, "\n")
+	body = htmlTagPattern.ReplaceAllString(body, "")
+	body = html.UnescapeString(body)
+
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
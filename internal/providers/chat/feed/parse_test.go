@@ -0,0 +1,82 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Stripe changelog</title>
+    <item>
+      <guid>https://stripe.com/changelog/2</guid>
+      <title>API version 2025-06-01</title>
+      <link>https://stripe.com/changelog/2</link>
+      <description>&lt;p&gt;Payment intents &amp;amp; refunds changed.&lt;/p&gt;&lt;p&gt;Upgrade soon.&lt;/p&gt;</description>
+      <pubDate>Mon, 02 Jun 2025 10:00:00 +0000</pubDate>
+    </item>
+    <item>
+      <title>Dashboard refresh</title>
+      <link>https://stripe.com/changelog/1</link>
+    </item>
+  </channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Release notes from slack</title>
+  <entry>
+    <id>tag:github.com,2008:Repository/1/v0.16.0</id>
+    <updated>2025-05-20T08:00:00Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/slack-go/slack/releases/tag/v0.16.0"/>
+    <title>v0.16.0</title>
+    <content type="html">&lt;ul&gt;&lt;li&gt;Add Socket Mode reconnects&lt;/li&gt;&lt;/ul&gt;</content>
+  </entry>
+</feed>`
+
+func TestParseFeed_RSS(t *testing.T) {
+	feed, err := parseFeed([]byte(rssFeed))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Stripe changelog", feed.title)
+	require.Len(t, feed.items, 2)
+	assert.Equal(t, item{
+		id:      "https://stripe.com/changelog/2",
+		title:   "API version 2025-06-01",
+		link:    "https://stripe.com/changelog/2",
+		summary: "Payment intents & refunds changed.\nUpgrade soon.",
+	}, feed.items[0])
+	assert.Equal(t, "https://stripe.com/changelog/1", feed.items[1].id)
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	feed, err := parseFeed([]byte(atomFeed))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Release notes from slack", feed.title)
+	require.Len(t, feed.items, 1)
+	it := feed.items[0]
+	assert.Equal(t, "tag:github.com,2008:Repository/1/v0.16.0", it.id)
+	assert.Equal(t, "https://github.com/slack-go/slack/releases/tag/v0.16.0", it.link)
+	assert.Equal(t, "Add Socket Mode reconnects", it.summary)
+}
+
+func TestParseFeed_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "HTML page", body: "<html><body>Not a feed</body></html>"},
+		{name: "not XML", body: "{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFeed([]byte(tt.body))
+			assert.ErrorIs(t, err, ErrUnsupportedFeed)
+		})
+	}
+}
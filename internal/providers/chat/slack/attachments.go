@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+// maxImageBytes is the largest image attached to messages, the request limit of vision APIs
+const maxImageBytes = 20 << 20
+
+// imageAttachments returns the images shared with a message, like screenshots and whiteboard photos
+func imageAttachments(files []slack.File) []*domain.Attachment {
+	var images []*domain.Attachment
+	for _, file := range files {
+		if !strings.HasPrefix(file.Mimetype, "image/") || file.Size > maxImageBytes {
+			continue
+		}
+		downloadURL := file.URLPrivateDownload
+		if downloadURL == "" {
+			downloadURL = file.URLPrivate
+		}
+		image, err := domain.NewAttachment(file.Name, file.Mimetype, downloadURL)
+		if err != nil {
+			log.Printf("Skipping Slack file %s: %v", file.ID, err)
+			continue
+		}
+		images = append(images, image)
+	}
+	return images
+}
+
+// sharedImagesText is the text of a message sharing images without a comment
+func sharedImagesText(images []*domain.Attachment) string {
+	names := make([]string, 0, len(images))
+	for _, image := range images {
+		names = append(names, image.Name())
+	}
+	return "Shared " + strings.Join(names, ", ")
+}
+
+// notificationText is the text of a bot message with the legacy attachments CI/CD integrations post their
+// notifications in, as their text often only says a deployment happened and the attachments say which
+func notificationText(text string, attachments []slack.Attachment) string {
+	var parts []string
+	if strings.TrimSpace(text) != "" {
+		parts = append(parts, text)
+	}
+	for _, attachment := range attachments {
+		var lines []string
+		if attachment.Pretext != "" {
+			lines = append(lines, attachment.Pretext)
+		}
+		switch {
+		case attachment.Title != "" && attachment.TitleLink != "":
+			lines = append(lines, "<"+attachment.TitleLink+"|"+attachment.Title+">")
+		case attachment.Title != "":
+			lines = append(lines, attachment.Title)
+		}
+		if attachment.Text != "" {
+			lines = append(lines, attachment.Text)
+		}
+		for _, field := range attachment.Fields {
+			if field.Title != "" && field.Value != "" {
+				lines = append(lines, field.Title+": "+field.Value)
+			}
+		}
+		if len(lines) == 0 && attachment.Fallback != "" {
+			lines = append(lines, attachment.Fallback)
+		}
+		if len(lines) > 0 {
+			parts = append(parts, strings.Join(lines, "\n"))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// FetchAttachment downloads a file shared with a message. Only Slack's own file hosts are asked,
+// as the download is authenticated with the bot token.
+func (c *Client) FetchAttachment(ctx context.Context, attachment *domain.Attachment) ([]byte, error) {
+	u, err := url.Parse(attachment.URL())
+	if err != nil || u.Scheme != "https" || (u.Hostname() != "slack.com" && !strings.HasSuffix(u.Hostname(), ".slack.com")) {
+		return nil, fmt.Errorf("failed to fetch attachment: %s is not a Slack file", attachment.URL())
+	}
+
+	var data bytes.Buffer
+	if err := c.files.GetFileContext(ctx, attachment.URL(), &data); err != nil {
+		return nil, fmt.Errorf("failed to fetch attachment: %w", err)
+	}
+	return data.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessMessageEvent_AttachesImages(t *testing.T) {
+	client := newTestClient(t)
+
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "message_voice_clip.json"))
+
+	msg := waitForMessage(t, client)
+	assert.Equal(t, "Shared diagram.png", msg.Content().Text())
+	require.Len(t, msg.Images(), 1)
+	assert.Equal(t, "https://files.slack.com/files-pri/T0001-F0002/download/diagram.png", msg.Images()[0].URL())
+	assert.Equal(t, "image/png", msg.Images()[0].MimeType())
+}
+
+func TestImageAttachments_SkipsLargeImages(t *testing.T) {
+	images := imageAttachments([]slack.File{
+		{ID: "F1", Name: "huge.png", Mimetype: "image/png", Size: maxImageBytes + 1, URLPrivate: "https://files.slack.com/huge.png"},
+		{ID: "F2", Name: "clip.m4a", Mimetype: "audio/mp4", URLPrivate: "https://files.slack.com/clip.m4a"},
+	})
+	assert.Empty(t, images)
+}
+
+func TestFetchAttachment(t *testing.T) {
+	client := newTestClient(t)
+	client.files = &stubFiles{contents: map[string]string{"https://files.slack.com/files-pri/T0001-F0002/diagram.png": "png"}}
+
+	slackImage, _ := domain.NewAttachment("diagram.png", "image/png", "https://files.slack.com/files-pri/T0001-F0002/diagram.png")
+	data, err := client.FetchAttachment(context.Background(), slackImage)
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+
+	elsewhere, _ := domain.NewAttachment("diagram.png", "image/png", "https://files.example.com/diagram.png")
+	_, err = client.FetchAttachment(context.Background(), elsewhere)
+	assert.ErrorContains(t, err, "is not a Slack file")
+}
+
+func TestNotificationText(t *testing.T) {
+	text := notificationText("Deployment finished", []slack.Attachment{
+		{
+			Pretext:   "GitHub Actions",
+			Title:     "Deploy to production",
+			TitleLink: "https://github.com/acme/billing/actions/runs/42",
+			Text:      "Deployment of billing succeeded",
+			Fields: []slack.AttachmentField{
+				{Title: "Environment", Value: "production"},
+				{Title: "Version", Value: "v1.4.2"},
+				{Title: "Empty", Value: ""},
+			},
+		},
+		{Fallback: "Deployed by alice"},
+		{},
+	})
+	assert.Equal(t, "Deployment finished\n"+
+		"GitHub Actions\n"+
+		"<https://github.com/acme/billing/actions/runs/42|Deploy to production>\n"+
+		"Deployment of billing succeeded\n"+
+		"Environment: production\n"+
+		"Version: v1.4.2\n"+
+		"Deployed by alice", text)
+
+	assert.Equal(t, "Build #42 passed", notificationText("Build #42 passed", nil))
+	assert.Equal(t, "Synced", notificationText(" ", []slack.Attachment{{Title: "Synced"}}))
+}
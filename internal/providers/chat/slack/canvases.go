@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slack-go/slack"
+)
+
+// canvasAPI is the part of the Slack Web API channel canvases are published with
+type canvasAPI interface {
+	GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error)
+	CreateChannelCanvasContext(ctx context.Context, channel string, documentContent slack.DocumentContent) (string, error)
+	EditCanvasContext(ctx context.Context, params slack.EditCanvasParams) error
+}
+
+// PublishCanvas replaces the content of the canvas of a channel, the tab pinned at its top, creating the canvas
+// when the channel has none. People can edit the canvas in Slack until it is published again.
+func (c *Client) PublishCanvas(ctx context.Context, channelID, markdown string) error {
+	content := slack.DocumentContent{Type: "markdown", Markdown: markdown}
+
+	channel, err := c.canvases.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{ChannelID: channelID})
+	if err != nil {
+		return fmt.Errorf("failed to look up the canvas of channel %s: %w", channelID, err)
+	}
+	if channel.Properties == nil || channel.Properties.Canvas.FileId == "" {
+		if _, err := c.canvases.CreateChannelCanvasContext(ctx, channelID, content); err != nil {
+			return fmt.Errorf("failed to create the canvas of channel %s: %w", channelID, err)
+		}
+		return nil
+	}
+
+	err = c.canvases.EditCanvasContext(ctx, slack.EditCanvasParams{
+		CanvasID: channel.Properties.Canvas.FileId,
+		Changes:  []slack.CanvasChange{{Operation: "replace", DocumentContent: content}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the canvas of channel %s: %w", channelID, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCanvases records the canvases created and edited, channels have the canvases listed in canvasIDs
+type stubCanvases struct {
+	canvasIDs map[string]string
+	created   map[string]slack.DocumentContent
+	edits     []slack.EditCanvasParams
+}
+
+func (s *stubCanvases) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	channel := &slack.Channel{}
+	channel.ID = input.ChannelID
+	if canvasID, ok := s.canvasIDs[input.ChannelID]; ok {
+		channel.Properties = &slack.Properties{Canvas: slack.Canvas{FileId: canvasID}}
+	}
+	return channel, nil
+}
+
+func (s *stubCanvases) CreateChannelCanvasContext(ctx context.Context, channel string, documentContent slack.DocumentContent) (string, error) {
+	if s.created == nil {
+		s.created = make(map[string]slack.DocumentContent)
+	}
+	s.created[channel] = documentContent
+	if s.canvasIDs == nil {
+		s.canvasIDs = make(map[string]string)
+	}
+	s.canvasIDs[channel] = "F" + channel
+	return "F" + channel, nil
+}
+
+func (s *stubCanvases) EditCanvasContext(ctx context.Context, params slack.EditCanvasParams) error {
+	s.edits = append(s.edits, params)
+	return nil
+}
+
+func TestClient_PublishCanvas(t *testing.T) {
+	client := newTestClient(t)
+	canvases := &stubCanvases{}
+	client.canvases = canvases
+	ctx := context.Background()
+
+	require.NoError(t, client.PublishCanvas(ctx, "C0001", "# Weekly digest"))
+	assert.Equal(t, map[string]slack.DocumentContent{"C0001": {Type: "markdown", Markdown: "# Weekly digest"}}, canvases.created)
+	assert.Empty(t, canvases.edits)
+
+	// The canvas the channel has is replaced
+	require.NoError(t, client.PublishCanvas(ctx, "C0001", "# Weekly digest, again"))
+	require.Len(t, canvases.edits, 1)
+	assert.Equal(t, "FC0001", canvases.edits[0].CanvasID)
+	assert.Equal(t, []slack.CanvasChange{{Operation: "replace", DocumentContent: slack.DocumentContent{Type: "markdown", Markdown: "# Weekly digest, again"}}}, canvases.edits[0].Changes)
+	assert.Len(t, canvases.created, 1)
+}
@@ -0,0 +1,202 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// CaptureShortcutCallbackID identifies the message shortcut capturing any message with Quill
+	CaptureShortcutCallbackID = "quill_capture"
+	// CaptureCallbackID identifies submissions of the capture modal
+	CaptureCallbackID = "quill_capture_modal"
+
+	captureTypeBlockID     = "capture_type"
+	captureCategoryBlockID = "capture_category"
+	captureAnalysisBlockID = "capture_analysis"
+	captureSelectActionID  = "value"
+)
+
+// captureTypes are the types a captured message can be documented as
+var captureTypes = []MessageCategory{
+	{Value: domain.MessageTypeDecision.String(), Text: "Decision"},
+	{Value: domain.MessageTypeIdea.String(), Text: "Idea"},
+	{Value: domain.MessageTypeStatus.String(), Text: "Status update"},
+}
+
+// captureMetadata travels with the capture modal so the submission finds the message being captured
+type captureMetadata struct {
+	ChannelID string `json:"channel_id"`
+	MessageTS string `json:"message_ts"`
+	ThreadTS  string `json:"thread_ts"`
+}
+
+// key identifies the message being captured among the open capture modals
+func (m captureMetadata) key() string {
+	return m.ChannelID + ":" + m.MessageTS
+}
+
+// OnCaptureRequest registers the function analyzing messages captured with the shortcut
+func (c *Client) OnCaptureRequest(analyze func(ctx context.Context, channelID, text string) (*domain.MessageAnalysisResult, error)) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.analyzeCapture = analyze
+}
+
+// openCaptureModal opens the capture modal when the shortcut is used on a message. Slack only accepts a
+// modal within seconds of the shortcut, so it opens at once and is pre-filled when the analysis is done.
+func (c *Client) openCaptureModal(ctx context.Context, interaction *slack.InteractionCallback) error {
+	c.formLock.Lock()
+	analyze := c.analyzeCapture
+	c.formLock.Unlock()
+	if analyze == nil {
+		return fmt.Errorf("capture shortcut used but no handler is registered")
+	}
+
+	meta := captureMetadata{
+		ChannelID: interaction.Channel.ID,
+		MessageTS: interaction.Message.Timestamp,
+		ThreadTS:  MessageData{SlackThreadTS: interaction.Message.ThreadTimestamp, SlackMessageTS: interaction.Message.Timestamp}.replyThreadTS(),
+	}
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to open capture modal: %w", err)
+	}
+
+	c.formLock.Lock()
+	c.captureForms[meta.key()] = interaction.Message
+	c.formLock.Unlock()
+
+	view, err := c.web.OpenViewContext(ctx, interaction.TriggerID, buildCaptureModal(string(encoded), nil, "⏳ Quill is reading the message…"))
+	if err != nil {
+		return fmt.Errorf("failed to open capture modal: %w", err)
+	}
+
+	note := "⚠️ Quill could not read the message, pick its type and category"
+	analysis, err := analyze(ctx, meta.ChannelID, interaction.Message.Text)
+	if err != nil {
+		log.Printf("Failed to analyze the message captured in Slack channel %s: %v", meta.ChannelID, err)
+		analysis = nil
+	} else {
+		note = captureNote(analysis)
+	}
+	if _, err := c.web.UpdateViewContext(ctx, buildCaptureModal(string(encoded), analysis, note), "", view.Hash, view.ID); err != nil {
+		return fmt.Errorf("failed to pre-fill capture modal: %w", err)
+	}
+	return nil
+}
+
+// submitCapture delivers the captured message with the picked type and category, like a message that was
+// just posted, and tells the person who captured it
+func (c *Client) submitCapture(ctx context.Context, interaction *slack.InteractionCallback) error {
+	var meta captureMetadata
+	if err := json.Unmarshal([]byte(interaction.View.PrivateMetadata), &meta); err != nil {
+		return fmt.Errorf("invalid capture submission: %w", err)
+	}
+
+	messageType, err := domain.NewMessageType(selectedValue(interaction.View.State, captureTypeBlockID))
+	if err != nil {
+		return c.postCaptureResult(ctx, interaction, meta, fmt.Sprintf("⚠️ Failed to capture: %s", err))
+	}
+	category, err := domain.NewCategory(selectedValue(interaction.View.State, captureCategoryBlockID))
+	if err != nil {
+		return c.postCaptureResult(ctx, interaction, meta, fmt.Sprintf("⚠️ Failed to capture: %s", err))
+	}
+
+	c.formLock.Lock()
+	msg, ok := c.captureForms[meta.key()]
+	delete(c.captureForms, meta.key())
+	c.formLock.Unlock()
+	if !ok {
+		return c.postCaptureResult(ctx, interaction, meta, "⚠️ This form has expired, use the shortcut again")
+	}
+
+	// Messages are captured even when they were processed before, a late delivery of them is dropped
+	c.markSeen(ctx, meta.key())
+	c.deliver(ctx, MessageData{
+		SlackChannelID: meta.ChannelID,
+		SlackThreadTS:  msg.ThreadTimestamp,
+		SlackMessageTS: msg.Timestamp,
+		SlackUserID:    msg.User,
+	}, msg.Username, msg.Text, msg.Files, FilterDecision{Accept: true, MessageType: messageType, Category: category})
+
+	return c.postCaptureResult(ctx, interaction, meta, fmt.Sprintf("📥 Capturing this message as type *%s*, category *%s*", messageType, category))
+}
+
+// postCaptureResult replies in the thread of the captured message, visible only to the person who captured it
+func (c *Client) postCaptureResult(ctx context.Context, interaction *slack.InteractionCallback, meta captureMetadata, text string) error {
+	_, err := c.web.PostEphemeralContext(ctx, meta.ChannelID, interaction.User.ID, slack.MsgOptionText(text, false), slack.MsgOptionTS(meta.ThreadTS))
+	if err != nil {
+		return fmt.Errorf("failed to post capture result: %w", err)
+	}
+	return nil
+}
+
+// captureNote describes what the analysis read the message as
+func captureNote(analysis *domain.MessageAnalysisResult) string {
+	if analysis.MessageType().IsUnknown() || analysis.MessageType().IsInformation() {
+		return "Quill would not document this message on its own, pick what it is"
+	}
+	return fmt.Sprintf("Quill suggests type %s, category %s (%.0f%% confident)",
+		analysis.MessageType(), analysis.Category(), analysis.ConfidenceScore()*100)
+}
+
+// buildCaptureModal builds the modal picking the type and category of a captured message, pre-filled with
+// the analysis when there is one
+func buildCaptureModal(metadata string, analysis *domain.MessageAnalysisResult, note string) slack.ModalViewRequest {
+	var messageType, category string
+	if analysis != nil {
+		messageType = analysis.MessageType().String()
+		category = analysis.Category().String()
+	}
+
+	blocks := []slack.Block{
+		selectBlock(captureTypeBlockID, "Type", captureTypes, messageType),
+		selectBlock(captureCategoryBlockID, "Category", categories, category),
+	}
+	if note != "" {
+		blocks = append(blocks, slack.NewContextBlock(captureAnalysisBlockID, slack.NewTextBlockObject(slack.PlainTextType, note, false, false)))
+	}
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      CaptureCallbackID,
+		PrivateMetadata: metadata,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Capture with Quill", false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Capture", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Blocks:          slack.Blocks{BlockSet: blocks},
+	}
+}
+
+// selectBlock is an input picking one of the choices, the initial one selected when it is among them
+func selectBlock(blockID, label string, choices []MessageCategory, initial string) *slack.InputBlock {
+	options := make([]*slack.OptionBlockObject, len(choices))
+	var selected *slack.OptionBlockObject
+	for i, choice := range choices {
+		options[i] = slack.NewOptionBlockObject(choice.Value, slack.NewTextBlockObject(slack.PlainTextType, choice.Text, false, false), nil)
+		if choice.Value == initial {
+			selected = options[i]
+		}
+	}
+
+	element := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Pick "+strings.ToLower(label), false, false), captureSelectActionID, options...)
+	if selected != nil {
+		element = element.WithInitialOption(selected)
+	}
+	return slack.NewInputBlock(blockID, slack.NewTextBlockObject(slack.PlainTextType, label, false, false), nil, element)
+}
+
+func selectedValue(state *slack.ViewState, blockID string) string {
+	if state == nil {
+		return ""
+	}
+	return state.Values[blockID][captureSelectActionID].SelectedOption.Value
+}
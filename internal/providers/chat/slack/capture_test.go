@@ -0,0 +1,125 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureShortcut(channelID, messageTS, threadTS, author, text string) *slack.InteractionCallback {
+	interaction := &slack.InteractionCallback{
+		Type:       slack.InteractionTypeMessageAction,
+		CallbackID: CaptureShortcutCallbackID,
+		TriggerID:  "trigger",
+		User:       slack.User{ID: "U0002"},
+	}
+	interaction.Channel.ID = channelID
+	interaction.Message.Timestamp = messageTS
+	interaction.Message.ThreadTimestamp = threadTS
+	interaction.Message.User = author
+	interaction.Message.Text = text
+	return interaction
+}
+
+func captureSubmission(metadata, messageType, category string) *slack.InteractionCallback {
+	selected := func(value string) map[string]slack.BlockAction {
+		return map[string]slack.BlockAction{captureSelectActionID: {SelectedOption: slack.OptionBlockObject{Value: value}}}
+	}
+	return &slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		User: slack.User{ID: "U0002"},
+		View: slack.View{
+			CallbackID:      CaptureCallbackID,
+			PrivateMetadata: metadata,
+			State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+				captureTypeBlockID:     selected(messageType),
+				captureCategoryBlockID: selected(category),
+			}},
+		},
+	}
+}
+
+func initialOption(t *testing.T, view slack.ModalViewRequest, blockID string) string {
+	t.Helper()
+	for _, block := range view.Blocks.BlockSet {
+		if input, ok := block.(*slack.InputBlock); ok && input.BlockID == blockID {
+			selectElement := input.Element.(*slack.SelectBlockElement)
+			if selectElement.InitialOption == nil {
+				return ""
+			}
+			return selectElement.InitialOption.Value
+		}
+	}
+	t.Fatalf("no block %s in the modal", blockID)
+	return ""
+}
+
+func TestCaptureShortcut(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+	client.OnCaptureRequest(func(ctx context.Context, channelID, text string) (*domain.MessageAnalysisResult, error) {
+		return domain.NewMessageAnalysisResult(domain.MessageTypeDecision, domain.CategoryDevelopment, nil, 0.4, nil)
+	})
+
+	shortcut := captureShortcut("C0001", "1700000000.000200", "1700000000.000100", "U0001", "Let's go with Postgres")
+	require.NoError(t, client.HandleInteraction(context.Background(), shortcut))
+
+	require.Len(t, web.views, 1, "the modal opens before the analysis")
+	assert.Equal(t, CaptureCallbackID, web.views[0].CallbackID)
+	assert.Empty(t, initialOption(t, web.views[0], captureTypeBlockID))
+	require.Len(t, web.updates, 1)
+	assert.Equal(t, "decision", initialOption(t, web.updates[0], captureTypeBlockID))
+	assert.Equal(t, "development", initialOption(t, web.updates[0], captureCategoryBlockID))
+
+	submission := captureSubmission(web.updates[0].PrivateMetadata, "idea", "product")
+	require.NoError(t, client.HandleInteraction(context.Background(), submission))
+
+	msg := receive(t, client)
+	require.NotNil(t, msg)
+	assert.Equal(t, "alice", msg.Sender())
+	assert.Equal(t, "Let's go with Postgres", msg.Content().Text())
+	assert.Equal(t, domain.MessageTypeIdea, msg.Type())
+	assert.Equal(t, domain.CategoryProduct, msg.Category())
+	assert.True(t, msg.HasFixedType())
+	assert.True(t, msg.HasFixedCategory())
+	data, ok := client.lookupMessage(msg.ID().String())
+	require.True(t, ok)
+	assert.Equal(t, "1700000000.000100", data.replyThreadTS())
+
+	require.Len(t, web.ephemeral, 1)
+	assert.Equal(t, postedMessage{channel: "C0001", user: "U0002", text: "📥 Capturing this message as type *idea*, category *product*", threadTS: "1700000000.000100"}, web.ephemeral[0])
+
+	// The form is gone once submitted
+	require.NoError(t, client.HandleInteraction(context.Background(), submission))
+	assert.Nil(t, receive(t, client))
+	assert.Equal(t, "⚠️ This form has expired, use the shortcut again", web.ephemeral[1].text)
+}
+
+func TestCaptureShortcut_AnalysisFails(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+	client.OnCaptureRequest(func(ctx context.Context, channelID, text string) (*domain.MessageAnalysisResult, error) {
+		return nil, errors.New("model unavailable")
+	})
+
+	require.NoError(t, client.HandleInteraction(context.Background(), captureShortcut("C0001", "1700000000.000200", "", "U0001", "Ship it")))
+
+	require.Len(t, web.updates, 1)
+	assert.Empty(t, initialOption(t, web.updates[0], captureTypeBlockID))
+	var meta captureMetadata
+	require.NoError(t, json.Unmarshal([]byte(web.updates[0].PrivateMetadata), &meta))
+	assert.Equal(t, captureMetadata{ChannelID: "C0001", MessageTS: "1700000000.000200", ThreadTS: "1700000000.000200"}, meta)
+
+	require.NoError(t, client.HandleInteraction(context.Background(), captureSubmission(web.updates[0].PrivateMetadata, "", "")))
+	assert.Nil(t, receive(t, client))
+	require.Len(t, web.ephemeral, 1)
+	assert.Contains(t, web.ephemeral[0].text, "⚠️ Failed to capture")
+}
@@ -0,0 +1,111 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+// categoryActionPrefix starts the action ID of every category button
+const categoryActionPrefix = "category_"
+
+// categoryChoice travels with each category button so the click can be applied to the document
+type categoryChoice struct {
+	Path      string `json:"path"`
+	MessageID string `json:"message_id"`
+	Category  string `json:"category"`
+}
+
+// OfferCategories replies in the thread of a message with the content and a button for each category
+func (c *Client) OfferCategories(ctx context.Context, messageID, content, path string, current domain.Category) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to offer categories: unknown message %s", messageID)
+	}
+
+	blocks, err := GetCategoryBlocks(content, path, messageID, current)
+	if err != nil {
+		return fmt.Errorf("failed to offer categories: %w", err)
+	}
+
+	_, _, err = c.web.PostMessageContext(
+		ctx,
+		data.SlackChannelID,
+		slack.MsgOptionText(content, false),
+		slack.MsgOptionBlocks(blocks...),
+		slack.MsgOptionTS(data.replyThreadTS()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to offer categories: %w", err)
+	}
+	return nil
+}
+
+// OnRecategorize registers the function applying clicked category buttons
+func (c *Client) OnRecategorize(apply func(ctx context.Context, change *domain.Recategorization) (string, error)) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.recategorize = apply
+}
+
+// applyCategoryChoice files the document under the clicked category and reports the result in the thread
+func (c *Client) applyCategoryChoice(ctx context.Context, interaction *slack.InteractionCallback, value string) error {
+	var choice categoryChoice
+	if err := json.Unmarshal([]byte(value), &choice); err != nil {
+		return fmt.Errorf("invalid category button: %w", err)
+	}
+
+	c.formLock.Lock()
+	apply := c.recategorize
+	c.formLock.Unlock()
+	if apply == nil {
+		return fmt.Errorf("category picked but no handler is registered")
+	}
+
+	channelID := interaction.Channel.ID
+	threadTS := interaction.Message.ThreadTimestamp
+	if threadTS == "" {
+		threadTS = interaction.Message.Timestamp
+	}
+
+	text, err := c.recategorizeChoice(ctx, apply, choice, interaction.User.ID)
+	if err != nil {
+		text = fmt.Sprintf("⚠️ Failed to recategorize: %s", err)
+	}
+
+	if _, _, err := c.web.PostMessageContext(ctx, channelID, slack.MsgOptionText(text, false), slack.MsgOptionTS(threadTS)); err != nil {
+		return fmt.Errorf("failed to post recategorization result: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) recategorizeChoice(
+	ctx context.Context,
+	apply func(ctx context.Context, change *domain.Recategorization) (string, error),
+	choice categoryChoice,
+	userID string,
+) (string, error) {
+	category, err := domain.NewCategory(choice.Category)
+	if err != nil {
+		return "", err
+	}
+	change, err := domain.NewRecategorization(choice.Path, choice.MessageID, category, userID)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := apply(ctx, change)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🗂️ <@%s> filed this under *%s*: `%s`", userID, category, path), nil
+}
+
+// isCategoryAction checks if a block action is a click on a category button
+func isCategoryAction(actionID string) bool {
+	return strings.HasPrefix(actionID, categoryActionPrefix)
+}
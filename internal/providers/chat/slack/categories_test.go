@@ -0,0 +1,80 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func categoryClick(t *testing.T, choice categoryChoice) *slack.InteractionCallback {
+	value, err := json.Marshal(choice)
+	require.NoError(t, err)
+
+	interaction := &slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		User: slack.User{ID: "U0002"},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: categoryActionPrefix + choice.Category, Value: string(value)},
+		}},
+	}
+	interaction.Channel.ID = "C0001"
+	interaction.Message.Timestamp = "1700000000.000900"
+	interaction.Message.ThreadTimestamp = "1700000000.000100"
+	return interaction
+}
+
+func TestOfferCategories(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackMessageTS: "1700000000.000100"})
+
+	require.NoError(t, client.OfferCategories(context.Background(), "MSG1", "📝 Captured idea in category: other", "docs/other/pricing.md", domain.CategoryOther))
+
+	require.Len(t, web.posts, 1)
+	post := web.posts[0]
+	assert.Equal(t, "1700000000.000100", post.threadTS)
+	assert.Equal(t, "📝 Captured idea in category: other", post.text)
+	assert.Contains(t, post.blocks, `"action_id":"category_product"`)
+	assert.Contains(t, post.blocks, `"action_id":"category_quality_assurance"`)
+	assert.Contains(t, post.blocks, `docs/other/pricing.md`)
+}
+
+func TestCategoryClick(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	var applied *domain.Recategorization
+	client.OnRecategorize(func(ctx context.Context, change *domain.Recategorization) (string, error) {
+		if change.Category() == domain.CategoryOperations {
+			return "", errors.New("store unavailable")
+		}
+		applied = change
+		return "docs/product/pricing.md", nil
+	})
+
+	choice := categoryChoice{Path: "docs/other/pricing.md", MessageID: "MSG1", Category: "product"}
+	require.NoError(t, client.HandleInteraction(context.Background(), categoryClick(t, choice)))
+
+	require.NotNil(t, applied)
+	assert.Equal(t, "docs/other/pricing.md", applied.Path())
+	assert.Equal(t, "MSG1", applied.MessageID())
+	assert.Equal(t, domain.CategoryProduct, applied.Category())
+	assert.Equal(t, "U0002", applied.Actor())
+
+	choice.Category = "operations"
+	require.NoError(t, client.HandleInteraction(context.Background(), categoryClick(t, choice)))
+
+	require.Len(t, web.posts, 2)
+	assert.Equal(t, "🗂️ <@U0002> filed this under *product*: `docs/product/pricing.md`", web.posts[0].text)
+	assert.Equal(t, "1700000000.000100", web.posts[0].threadTS)
+	assert.Equal(t, "⚠️ Failed to recategorize: store unavailable", web.posts[1].text)
+}
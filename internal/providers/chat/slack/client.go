@@ -9,7 +9,9 @@ import (
 
 	"github.com/massimo-ua/quill/internal/domain"
 	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
 	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 )
 
@@ -22,10 +24,43 @@ var (
 type Client struct {
 	config     *Config
 	api        *slack.Client
+	web        webAPI
 	socket     *socketmode.Client
+	users      userLookup
+	files      fileAPI
+	home       homeAPI
+	canvases   canvasAPI
+	filter     *MessageFilter
 	messageCh  chan *domain.Message
-	threadMap  map[string]common.ID // Maps Slack thread TS to our ThreadID
+	threadMap  map[string]common.ID   // Maps Slack channel and thread TS to our ThreadID
+	restored   map[string]bool        // Channels whose stored threads are in threadMap
+	messages   map[string]MessageData // Maps our message IDs to their Slack location
+	seen       map[string]struct{}
+	seenOrder  []string
 	threadLock sync.RWMutex
+
+	history       historyAPI
+	lastProcessed map[string]string // Timestamp of the newest message processed per channel
+	sessions      int               // Socket Mode sessions that said hello
+
+	socketSupervisor *supervisor.Supervisor // Restarts the Socket Mode connection
+	eventSupervisor  *supervisor.Supervisor // Restarts the event loop
+
+	projectForms     map[string]domain.ProjectDTO // Projects offered for editing, keyed by project ID
+	applyProjectEdit func(ctx context.Context, edit *domain.ProjectEdit) (*domain.Project, error)
+	projectTemplates []domain.ProjectTemplate // Templates offered in the onboarding wizard
+	createProject    func(ctx context.Context, draft *domain.ProjectDraft) (*domain.Project, error)
+	messageDetails   func(ctx context.Context, messageID string) (string, error)
+	recategorize     func(ctx context.Context, change *domain.Recategorization) (string, error)
+	review           func(ctx context.Context, review *domain.DocumentReview) error
+	findLinked       func(ctx context.Context, channelID, link string) (*domain.IndexedDocument, error)
+	analyzeCapture   func(ctx context.Context, channelID, text string) (*domain.MessageAnalysisResult, error)
+	captureForms     map[string]slack.Message // Messages offered for capturing, keyed by channel and timestamp
+	homePage         func(ctx context.Context, viewer domain.HomeViewer) (*domain.Home, error)
+	decideHeld       func(ctx context.Context, messageID, actor string, approve bool) error
+	triage           func(ctx context.Context, decision *domain.TriageDecision) error
+	optOut           func(ctx context.Context, messageID, actor string) error
+	formLock         sync.Mutex
 }
 
 // NewClient creates a new Slack client
@@ -45,31 +80,61 @@ func NewClient(config *Config) (*Client, error) {
 		socketmode.OptionDebug(config.DebugMode),
 	)
 
+	socketSupervisor, err := supervisor.New("slack-socket", config.Supervision)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	eventSupervisor, err := supervisor.New("slack-events", config.Supervision)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
 	return &Client{
-		config:    config,
-		api:       api,
-		socket:    socketClient,
-		messageCh: make(chan *domain.Message, 100),
-		threadMap: make(map[string]common.ID),
+		config:       config,
+		api:          api,
+		web:          api,
+		socket:       socketClient,
+		users:        api,
+		files:        api,
+		home:         api,
+		canvases:     api,
+		history:      api,
+		filter:       NewMessageFilter(config),
+		messageCh:    make(chan *domain.Message, 100),
+		threadMap:    make(map[string]common.ID),
+		restored:     make(map[string]bool),
+		messages:     make(map[string]MessageData),
+		seen:         make(map[string]struct{}),
+		projectForms: make(map[string]domain.ProjectDTO),
+		captureForms: make(map[string]slack.Message),
+
+		lastProcessed:    make(map[string]string),
+		socketSupervisor: socketSupervisor,
+		eventSupervisor:  eventSupervisor,
 	}, nil
 }
 
 // SendMessage sends a message to a Slack channel
 func (c *Client) SendMessage(ctx context.Context, channelID, content string) error {
-	_, _, err := c.api.PostMessageContext(ctx, channelID, slack.MsgOptionText(content, false))
+	_, _, err := c.web.PostMessageContext(ctx, channelID, slack.MsgOptionText(content, false))
 	if err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 	return nil
 }
 
-// ReplyToMessage replies to a specific Slack message
+// ReplyToMessage replies in the thread of a message received from Slack
 func (c *Client) ReplyToMessage(ctx context.Context, messageID, content string) error {
-	_, _, err := c.api.PostMessageContext(
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to reply to message: unknown message %s", messageID)
+	}
+
+	_, _, err := c.web.PostMessageContext(
 		ctx,
-		messageID, // This should be the channel ID
+		data.SlackChannelID,
 		slack.MsgOptionText(content, false),
-		slack.MsgOptionTS(messageID), // Thread TS
+		slack.MsgOptionTS(data.replyThreadTS()),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to reply to message: %w", err)
@@ -77,22 +142,20 @@ func (c *Client) ReplyToMessage(ctx context.Context, messageID, content string)
 	return nil
 }
 
-// ListenForMessages starts listening for Slack messages
+// ListenForMessages starts listening for Slack messages. The socket connection and the event loop
+// are supervised: they are restarted with backoff after failing or panicking until ctx is canceled.
 func (c *Client) ListenForMessages(ctx context.Context) (<-chan *domain.Message, error) {
-	// Start the socket mode client in a separate goroutine
-	go func() {
-		err := c.socket.Run()
-		if err != nil {
-			log.Printf("Error running socket mode client: %v", err)
-		}
-	}()
-
-	// Handle events in a separate goroutine
-	go c.handleEvents(ctx)
+	go c.socketSupervisor.Run(ctx, c.socket.RunContext)
+	go c.eventSupervisor.Run(ctx, c.handleEvents)
 
 	return c.messageCh, nil
 }
 
+// Health reports the state of the supervised socket connection and event loop
+func (c *Client) Health() []supervisor.Health {
+	return []supervisor.Health{c.socketSupervisor.Health(), c.eventSupervisor.Health()}
+}
+
 // HandleInteraction processes interactive components from Slack
 func (c *Client) HandleInteraction(ctx context.Context, interaction interface{}) error {
 	// Process different types of interactions based on the interface type
@@ -104,17 +167,21 @@ func (c *Client) HandleInteraction(ctx context.Context, interaction interface{})
 	}
 }
 
-// handleEvents processes incoming Slack events
-func (c *Client) handleEvents(ctx context.Context) {
+// handleEvents processes incoming Slack events until ctx is canceled
+func (c *Client) handleEvents(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return ctx.Err()
 		case event := <-c.socket.Events:
 			// Handle different event types
 			switch event.Type {
+			case socketmode.EventTypeConnecting, socketmode.EventTypeConnectionError, socketmode.EventTypeConnected,
+				socketmode.EventTypeHello, socketmode.EventTypeDisconnect:
+				c.handleConnectionEvent(ctx, event)
+
 			case socketmode.EventTypeEventsAPI:
-				eventsAPIEvent, ok := event.Data.(EventsAPIEvent)
+				eventsAPIEvent, ok := event.Data.(slackevents.EventsAPIEvent)
 				if !ok {
 					log.Printf("Error: could not type cast to EventsAPIEvent")
 					continue
@@ -123,11 +190,7 @@ func (c *Client) handleEvents(ctx context.Context) {
 				// Acknowledge the event
 				c.socket.Ack(*event.Request)
 
-				// Process the inner event
-				switch innerEvent := eventsAPIEvent.Data.(type) {
-				case *slack.MessageEvent:
-					c.processMessageEvent(eventsAPIEvent.EventID, innerEvent)
-				}
+				c.handleEventsAPIEvent(ctx, eventsAPIEvent)
 
 			case socketmode.EventTypeInteractive:
 				interaction, ok := event.Data.(slack.InteractionCallback)
@@ -140,85 +203,16 @@ func (c *Client) handleEvents(ctx context.Context) {
 				c.socket.Ack(*event.Request)
 
 				// Process the interaction in a separate goroutine
-				go func() {
+				supervisor.Go("slack-interaction", func() {
 					if err := c.processInteractionCallback(ctx, &interaction); err != nil {
 						log.Printf("Error processing interaction: %v", err)
 					}
-				}()
+				})
 			}
 		}
 	}
 }
 
-// processMessageEvent converts a Slack message to our domain Message
-func (c *Client) processMessageEvent(eventID string, msg *slack.MessageEvent) {
-	// Skip bot messages
-	if msg.BotID != "" || msg.User == "" {
-		return
-	}
-
-	// Get user info
-	userInfo, err := c.api.GetUserInfo(msg.User)
-	if err != nil {
-		log.Printf("Error fetching user info: %v", err)
-		return
-	}
-
-	// Handle thread mapping
-	var threadID common.ID
-	c.threadLock.RLock()
-	if msg.ThreadTimestamp != "" {
-		// This is part of a thread
-		if id, exists := c.threadMap[msg.ThreadTimestamp]; exists {
-			threadID = id
-		} else {
-			// The parent message's thread ID wasn't mapped yet
-			threadID = common.GenerateID()
-			c.threadLock.RUnlock()
-			c.threadLock.Lock()
-			c.threadMap[msg.ThreadTimestamp] = threadID
-			c.threadLock.Unlock()
-			return
-		}
-	} else {
-		// This is a new message, not in a thread
-		threadID = common.GenerateID()
-		c.threadLock.RUnlock()
-		c.threadLock.Lock()
-		c.threadMap[msg.Timestamp] = threadID
-		c.threadLock.Unlock()
-	}
-
-	// Create message content
-	messageContent, err := domain.NewMessageContent(msg.Text)
-	if err != nil {
-		log.Printf("Error creating message content: %v", err)
-		return
-	}
-
-	// For now, use default type and category - these will be determined later by AI analysis
-	defaultType := domain.MessageTypeInformation
-	defaultCategory := domain.CategoryOther
-
-	// Create domain message
-	domainMsg, err := domain.NewMessage(
-		threadID,
-		userInfo.Name,
-		messageContent,
-		defaultType,
-		defaultCategory,
-		nil, // no references initially
-	)
-
-	if err != nil {
-		log.Printf("Error creating domain message: %v", err)
-		return
-	}
-
-	// Send to message channel for processing
-	c.messageCh <- domainMsg
-}
-
 // processInteractionCallback handles Slack interaction callbacks
 func (c *Client) processInteractionCallback(ctx context.Context, interaction *slack.InteractionCallback) error {
 	// Process different interaction types
@@ -227,12 +221,44 @@ func (c *Client) processInteractionCallback(ctx context.Context, interaction *sl
 		// Handle block actions
 		for _, action := range interaction.ActionCallback.BlockActions {
 			log.Printf("Received block action: %s with value %s", action.ActionID, action.Value)
-			// Here we would process specific actions based on action.ActionID
+			if action.ActionID == ProjectEditActionID {
+				return c.openProjectEditModal(ctx, interaction.TriggerID, action.Value)
+			}
+			if action.ActionID == ProjectWizardActionID {
+				return c.openProjectWizardModal(ctx, interaction.TriggerID, action.Value)
+			}
+			if isCategoryAction(action.ActionID) {
+				return c.applyCategoryChoice(ctx, interaction, action.Value)
+			}
+			if isReviewAction(action.ActionID) {
+				return c.applyReviewChoice(ctx, interaction, action.Value)
+			}
+			if isTriageAction(action.ActionID) {
+				return c.applyTriageChoice(ctx, interaction, action.Value)
+			}
+			if isHomeAction(action.ActionID) {
+				return c.applyHomeAction(ctx, interaction, action)
+			}
+		}
+	case slack.InteractionTypeMessageAction:
+		// Handle message shortcuts
+		switch interaction.CallbackID {
+		case DetailsShortcutCallbackID:
+			return c.showMessageDetails(ctx, interaction)
+		case CaptureShortcutCallbackID:
+			return c.openCaptureModal(ctx, interaction)
 		}
 	case slack.InteractionTypeViewSubmission:
 		// Handle modal submissions
 		log.Printf("Received view submission: %s", interaction.View.ID)
-		// Process form submission
+		switch interaction.View.CallbackID {
+		case ProjectEditCallbackID:
+			return c.submitProjectEdit(ctx, interaction)
+		case ProjectWizardCallbackID:
+			return c.submitProjectWizard(ctx, interaction)
+		case CaptureCallbackID:
+			return c.submitCapture(ctx, interaction)
+		}
 	}
 
 	return nil
@@ -1,5 +1,10 @@
 package slack
 
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+)
+
 // Config contains Slack configuration parameters
 type Config struct {
 	// BotToken is the Slack API token for the bot
@@ -13,6 +18,49 @@ type Config struct {
 
 	// DebugMode enables detailed logging when true
 	DebugMode bool
+
+	// IgnoredUserIDs lists users whose messages are never processed
+	IgnoredUserIDs []string
+
+	// IgnoredBotIDs lists bots whose messages are never processed, even if allowed
+	IgnoredBotIDs []string
+
+	// AllowedBots lists bots whose messages are processed; all other bots are ignored
+	AllowedBots []AllowedBot
+
+	// ProcessedSubtypes lists the message subtypes to process (nil uses DefaultProcessedSubtypes)
+	ProcessedSubtypes []string
+
+	// Supervision controls restarting the socket connection and event loop after failures (optional)
+	Supervision *supervisor.Config
+
+	// BackfillOnReconnect fetches the messages posted while the socket was disconnected after reconnecting
+	BackfillOnReconnect bool
+
+	// MaxBackfillMessages bounds the missed messages fetched per channel (default: DefaultMaxBackfillMessages)
+	MaxBackfillMessages int
+
+	// Cursors keeps the newest message processed in each channel, so backfilling also fetches the messages
+	// posted while the bot was stopped (optional, without it only reconnects are backfilled)
+	Cursors ports.BackfillCursorStore
+
+	// Transcriber turns voice clips and huddle recordings into text, so spoken decisions are analyzed (optional)
+	Transcriber ports.TranscriptionProvider
+
+	// MaxRecordingBytes skips larger recordings without downloading them (default: DefaultMaxRecordingBytes)
+	MaxRecordingBytes int
+
+	// Threads keeps which of our threads each Slack thread is, so replies after a restart continue their
+	// thread (optional, without it the mapping is kept in memory only)
+	Threads ports.ThreadRepository
+
+	// Dedup, UserProfiles and ThreadMappings keep which events were handled, the profiles of message authors and
+	// which of our threads each Slack thread is. Replicas sharing them, like through Redis, handle each event once
+	// and continue each other's threads (optional, without them each client keeps its own in memory and fetches
+	// every author's profile).
+	Dedup          ports.DedupStore
+	UserProfiles   ports.UserProfileCache
+	ThreadMappings ports.ThreadMappingCache
 }
 
 // NewConfig creates a new Slack configuration
@@ -0,0 +1,208 @@
+package slack
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// DefaultMaxBackfillMessages bounds the missed messages fetched per channel after a reconnect
+const DefaultMaxBackfillMessages = 200
+
+// backfillPageSize is how many messages are requested from conversations.history at once
+const backfillPageSize = 100
+
+// historyAPI fetches the messages posted in a channel
+type historyAPI interface {
+	GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+}
+
+// handleConnectionEvent follows the state of the Socket Mode connection. Once a new session says hello
+// after an earlier one, or after a restart with stored cursors, the messages missed in between are fetched when
+// backfilling is enabled.
+func (c *Client) handleConnectionEvent(ctx context.Context, event socketmode.Event) {
+	switch event.Type {
+	case socketmode.EventTypeConnecting:
+		if c.config.DebugMode {
+			log.Printf("Connecting to Slack Socket Mode")
+		}
+	case socketmode.EventTypeConnectionError:
+		log.Printf("Slack Socket Mode connection failed: %v", event.Data)
+	case socketmode.EventTypeConnected:
+		if c.config.DebugMode {
+			log.Printf("Connected to Slack Socket Mode")
+		}
+	case socketmode.EventTypeDisconnect:
+		log.Printf("Slack requested a reconnect")
+	case socketmode.EventTypeHello:
+		// The first session after a restart resumes where the stored cursors say the earlier run stopped
+		reconnected := c.startSession()
+		if c.config.BackfillOnReconnect && (reconnected || c.config.Cursors != nil) {
+			supervisor.Go("slack-backfill", func() {
+				c.backfill(ctx)
+			})
+		}
+	}
+}
+
+// startSession counts a new Socket Mode session and reports whether it follows an earlier one
+func (c *Client) startSession() bool {
+	c.threadLock.Lock()
+	defer c.threadLock.Unlock()
+
+	c.sessions++
+	return c.sessions > 1
+}
+
+// recordProcessed remembers the newest message processed in a channel, where backfilling resumes. The cursor
+// store, if any, keeps it across restarts.
+func (c *Client) recordProcessed(ctx context.Context, channelID, ts string) {
+	c.threadLock.Lock()
+	last, ok := c.lastProcessed[channelID]
+	newer := !ok || tsAfter(ts, last)
+	if newer {
+		c.lastProcessed[channelID] = ts
+	}
+	c.threadLock.Unlock()
+
+	if newer && c.config.Cursors != nil {
+		if err := c.config.Cursors.Set(ctx, channelID, ts); err != nil {
+			log.Printf("Failed to record the newest message processed in Slack channel %s: %v", channelID, err)
+		}
+	}
+}
+
+// backfill processes the messages posted in known channels since the last processed message, known from this
+// run or the cursor store. Messages that were delivered after all are dropped as duplicates.
+func (c *Client) backfill(ctx context.Context) {
+	c.threadLock.RLock()
+	since := make(map[string]string, len(c.lastProcessed))
+	for channelID, ts := range c.lastProcessed {
+		since[channelID] = ts
+	}
+	c.threadLock.RUnlock()
+
+	if c.config.Cursors != nil {
+		stored, err := c.config.Cursors.List(ctx)
+		if err != nil {
+			log.Printf("Failed to read where backfilling Slack channels stopped: %v", err)
+		}
+		for channelID, ts := range stored {
+			if last, ok := since[channelID]; !ok || tsAfter(ts, last) {
+				since[channelID] = ts
+			}
+		}
+	}
+
+	for channelID, oldest := range since {
+		missed, err := c.missedMessages(ctx, channelID, oldest)
+		if err != nil {
+			log.Printf("Failed to fetch messages missed in Slack channel %s: %v", channelID, err)
+		}
+		if len(missed) > 0 {
+			log.Printf("Processing %d messages missed in Slack channel %s", len(missed), channelID)
+		}
+		for _, ev := range missed {
+			c.processMessageEvent(ctx, ev)
+		}
+	}
+}
+
+// missedMessages returns the channel's top-level messages posted after oldest, oldest first. Pages are fetched
+// from the oldest message forward, so when more were missed than the limit, the newest are left out and the gap
+// is logged.
+func (c *Client) missedMessages(ctx context.Context, channelID, oldest string) ([]*slackevents.MessageEvent, error) {
+	limit := c.config.MaxBackfillMessages
+	if limit <= 0 {
+		limit = DefaultMaxBackfillMessages
+	}
+
+	var missed []*slackevents.MessageEvent
+	more := false
+	for {
+		resp, err := c.history.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: channelID,
+			Oldest:    oldest,
+			Limit:     backfillPageSize,
+		})
+		if err != nil {
+			return missed, err
+		}
+
+		// conversations.history lists each page newest first
+		page := make([]*slackevents.MessageEvent, 0, len(resp.Messages))
+		for _, msg := range resp.Messages {
+			page = append(page, historyEvent(channelID, msg))
+		}
+		sort.Slice(page, func(i, j int) bool {
+			return tsAfter(page[j].TimeStamp, page[i].TimeStamp)
+		})
+		missed = append(missed, page...)
+
+		more = resp.HasMore && len(page) > 0
+		if !more || len(missed) >= limit {
+			break
+		}
+		oldest = page[len(page)-1].TimeStamp
+	}
+
+	if more || len(missed) > limit {
+		if len(missed) > limit {
+			missed = missed[:limit]
+		}
+		log.Printf("Backfilling Slack channel %s stopped at %d messages, the messages posted after %s are not processed",
+			channelID, limit, missed[len(missed)-1].TimeStamp)
+	}
+	return missed, nil
+}
+
+// historyEvent converts a message from conversations.history to the event it was delivered as
+func historyEvent(channelID string, msg slack.Message) *slackevents.MessageEvent {
+	return &slackevents.MessageEvent{
+		Type:            "message",
+		Channel:         channelID,
+		User:            msg.User,
+		Text:            msg.Text,
+		TimeStamp:       msg.Timestamp,
+		ThreadTimeStamp: msg.ThreadTimestamp,
+		SubType:         msg.SubType,
+		BotID:           msg.BotID,
+		Username:        msg.Username,
+		Message:         &msg.Msg,
+	}
+}
+
+// tsAfter checks if Slack timestamp a is later than b. Timestamps are seconds and microseconds
+// like 1700000000.000100, compared as integers because float64 cannot hold them exactly.
+func tsAfter(a, b string) bool {
+	aSec, aMicro := splitTS(a)
+	bSec, bMicro := splitTS(b)
+	if aSec != bSec {
+		return aSec > bSec
+	}
+	return aMicro > bMicro
+}
+
+func splitTS(ts string) (int64, int64) {
+	secs, micros, _ := strings.Cut(ts, ".")
+	s, _ := strconv.ParseInt(secs, 10, 64)
+	m, _ := strconv.ParseInt((micros + "000000")[:6], 10, 64)
+	return s, m
+}
+
+// tsTime returns when a Slack timestamp was posted, zero when it cannot be read
+func tsTime(ts string) time.Time {
+	secs, micros := splitTS(ts)
+	if secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, micros*int64(time.Microsecond)).UTC()
+}
@@ -0,0 +1,171 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHistory serves conversations.history pages in turn, each newest message first like Slack
+type stubHistory struct {
+	pages    [][]slack.Message
+	requests []slack.GetConversationHistoryParameters
+	err      error
+}
+
+func (s *stubHistory) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	s.requests = append(s.requests, *params)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	page := len(s.requests) - 1
+	resp := &slack.GetConversationHistoryResponse{}
+	if page < len(s.pages) {
+		resp.Messages = s.pages[page]
+	}
+	resp.HasMore = page+1 < len(s.pages)
+	return resp, nil
+}
+
+func historyMessage(user, ts, text string) slack.Message {
+	return slack.Message{Msg: slack.Msg{Type: "message", User: user, Timestamp: ts, Text: text}}
+}
+
+func TestClient_BackfillsMessagesMissedWhileDisconnected(t *testing.T) {
+	client := newTestClient(t)
+	client.config.BackfillOnReconnect = true
+	history := &stubHistory{pages: [][]slack.Message{
+		{historyMessage("U0001", "1718000200.000100", "What if we sharded billing?")},
+		{historyMessage("U0002", "1718000300.000100", "We decided to drop MySQL")},
+	}}
+	client.history = history
+
+	client.handleConnectionEvent(context.Background(), socketmode.Event{Type: socketmode.EventTypeHello})
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "message_channel.json"))
+	require.NotNil(t, receive(t, client))
+
+	client.handleConnectionEvent(context.Background(), socketmode.Event{Type: socketmode.EventTypeConnectionError})
+	client.handleConnectionEvent(context.Background(), socketmode.Event{Type: socketmode.EventTypeHello})
+
+	var missed []*domain.Message
+	require.Eventually(t, func() bool {
+		if msg := receive(t, client); msg != nil {
+			missed = append(missed, msg)
+		}
+		return len(missed) == 2
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, "What if we sharded billing?", missed[0].Content().Text(), "missed messages are processed oldest first")
+	assert.Equal(t, "alice", missed[0].Sender())
+	assert.Equal(t, "We decided to drop MySQL", missed[1].Content().Text())
+	require.Len(t, history.requests, 2)
+	assert.Equal(t, "C0001", history.requests[0].ChannelID)
+	assert.Equal(t, "1718000000.000100", history.requests[0].Oldest)
+	assert.Equal(t, "1718000200.000100", history.requests[1].Oldest, "pages are fetched from the oldest message forward")
+}
+
+func TestClient_BackfillSkipsDeliveredMessages(t *testing.T) {
+	client := newTestClient(t)
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "message_channel.json"))
+	require.NotNil(t, receive(t, client))
+	client.history = &stubHistory{pages: [][]slack.Message{
+		{historyMessage("U0001", "1718000000.000100", "We decided to move the billing service to Postgres")},
+	}}
+
+	client.backfill(context.Background())
+
+	assert.Nil(t, receive(t, client))
+}
+
+func TestClient_BackfillLimit(t *testing.T) {
+	client := newTestClient(t)
+	client.config.MaxBackfillMessages = 2
+	history := &stubHistory{pages: [][]slack.Message{
+		{historyMessage("U0001", "1718000200.000100", "older"), historyMessage("U0001", "1718000100.000100", "oldest")},
+		{historyMessage("U0001", "1718000300.000100", "newest")},
+	}}
+	client.history = history
+
+	missed, err := client.missedMessages(context.Background(), "C0001", "1718000000.000100")
+
+	require.NoError(t, err)
+	require.Len(t, missed, 2)
+	assert.Equal(t, "oldest", missed[0].Text, "the oldest missed messages are kept")
+	assert.Equal(t, "older", missed[1].Text)
+	assert.Len(t, history.requests, 1)
+}
+
+func TestClient_BackfillResumesAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	cursors := memory.NewBackfillCursorStore()
+	first := newTestClient(t)
+	first.config.Cursors = cursors
+	first.handleEventsAPIEvent(ctx, loadEvent(t, "message_channel.json"))
+	require.NotNil(t, receive(t, first))
+
+	restarted := newTestClient(t)
+	restarted.config.Cursors = cursors
+	restarted.config.BackfillOnReconnect = true
+	history := &stubHistory{pages: [][]slack.Message{
+		{historyMessage("U0002", "1718000300.000100", "We decided to drop MySQL")},
+	}}
+	restarted.history = history
+
+	restarted.handleConnectionEvent(ctx, socketmode.Event{Type: socketmode.EventTypeHello})
+
+	var missed *domain.Message
+	require.Eventually(t, func() bool {
+		missed = receive(t, restarted)
+		return missed != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "We decided to drop MySQL", missed.Content().Text())
+	require.Len(t, history.requests, 1)
+	assert.Equal(t, "1718000000.000100", history.requests[0].Oldest, "the first session resumes where the earlier run stopped")
+	stored, err := cursors.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1718000300.000100", stored["C0001"])
+}
+
+func TestClient_NoBackfillOnFirstSessionOrWhenDisabled(t *testing.T) {
+	client := newTestClient(t)
+	history := &stubHistory{err: errors.New("unexpected call")}
+	client.history = history
+	client.recordProcessed(context.Background(), "C0001", "1718000000.000100")
+
+	client.handleConnectionEvent(context.Background(), socketmode.Event{Type: socketmode.EventTypeHello})
+	client.handleConnectionEvent(context.Background(), socketmode.Event{Type: socketmode.EventTypeHello})
+
+	assert.Empty(t, history.requests, "backfilling is disabled by default")
+}
+
+func TestTsAfter(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "1718000000.000200", b: "1718000000.000100", want: true},
+		{a: "1718000000.000100", b: "1718000000.000100", want: false},
+		{a: "1718000001.000000", b: "1718000000.999999", want: true},
+		{a: "999999999.999999", b: "1718000000.000000", want: false},
+		{a: "1718000000.1", b: "1718000000.000100", want: true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tsAfter(tt.a, tt.b), "%s after %s", tt.a, tt.b)
+	}
+}
+
+func TestTsTime(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 6, 10, 6, 13, 20, 100000, time.UTC), tsTime("1718000000.000100"))
+	assert.True(t, tsTime("").IsZero())
+	assert.True(t, tsTime("not-a-ts").IsZero())
+}
@@ -0,0 +1,356 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// maxSeenMessages bounds the set used to drop duplicate deliveries of the same message
+const maxSeenMessages = 1000
+
+// leadingMentionPattern matches the bot mention that starts an app_mention text
+var leadingMentionPattern = regexp.MustCompile(`^\s*<@[A-Z0-9]+(?:\|[^>]*)?>\s*`)
+
+// userLookup fetches Slack user profiles
+type userLookup interface {
+	GetUserInfoContext(ctx context.Context, user string) (*slack.User, error)
+}
+
+// ParseEventPayload parses an Events API payload as delivered over HTTP or recorded from Socket Mode.
+// Request signatures are verified by the HTTP layer, so the deprecated verification token is not checked.
+func ParseEventPayload(body []byte) (slackevents.EventsAPIEvent, error) {
+	event, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
+	if err != nil {
+		return slackevents.EventsAPIEvent{}, fmt.Errorf("failed to parse Slack event: %w", err)
+	}
+	return event, nil
+}
+
+// handleEventsAPIEvent dispatches a typed Events API callback
+func (c *Client) handleEventsAPIEvent(ctx context.Context, event slackevents.EventsAPIEvent) {
+	if event.Type != slackevents.CallbackEvent {
+		return
+	}
+
+	switch ev := event.InnerEvent.Data.(type) {
+	case *slackevents.MessageEvent:
+		c.processMessageEvent(ctx, ev)
+	case *slackevents.AppMentionEvent:
+		c.processAppMentionEvent(ctx, ev)
+	case *slackevents.LinkSharedEvent:
+		c.processLinkSharedEvent(ctx, ev)
+	case *slackevents.AppHomeOpenedEvent:
+		c.processAppHomeOpenedEvent(ctx, ev)
+	case *slackevents.ReactionAddedEvent:
+		c.processReactionAddedEvent(ctx, ev)
+	}
+}
+
+// processMessageEvent converts a Slack message to our domain Message
+func (c *Client) processMessageEvent(ctx context.Context, ev *slackevents.MessageEvent) {
+	decision := c.filter.EvaluateMessage(ev)
+	if !decision.Accept {
+		if c.config.DebugMode {
+			log.Printf("Skipping Slack message %s: %s", ev.TimeStamp, decision.Reason)
+		}
+		return
+	}
+
+	text := ev.Text
+	var files []slack.File
+	if ev.Message != nil {
+		files = ev.Message.Files
+		if ev.BotID != "" || ev.SubType == botMessageSubtype {
+			text = notificationText(text, ev.Message.Attachments)
+		}
+	}
+
+	c.publish(ctx, MessageData{
+		SlackChannelID: ev.Channel,
+		SlackThreadTS:  ev.ThreadTimeStamp,
+		SlackMessageTS: ev.TimeStamp,
+		SlackUserID:    ev.User,
+	}, ev.Username, text, files, decision)
+}
+
+// processAppMentionEvent converts a message mentioning the bot to our domain Message
+func (c *Client) processAppMentionEvent(ctx context.Context, ev *slackevents.AppMentionEvent) {
+	decision := c.filter.EvaluateMention(ev)
+	if !decision.Accept {
+		if c.config.DebugMode {
+			log.Printf("Skipping Slack mention %s: %s", ev.TimeStamp, decision.Reason)
+		}
+		return
+	}
+
+	c.publish(ctx, MessageData{
+		SlackChannelID: ev.Channel,
+		SlackThreadTS:  ev.ThreadTimeStamp,
+		SlackMessageTS: ev.TimeStamp,
+		SlackUserID:    ev.User,
+	}, "", leadingMentionPattern.ReplaceAllString(ev.Text, ""), nil, decision)
+}
+
+// publish creates the domain message and sends it for processing.
+// Slack delivers both a message and an app_mention event for mentions in channels; only the first is kept.
+func (c *Client) publish(ctx context.Context, data MessageData, botName, text string, files []slack.File, decision FilterDecision) {
+	if !c.markSeen(ctx, data.SlackChannelID+":"+data.SlackMessageTS) {
+		return
+	}
+
+	found := findRecordings(files, text)
+	if c.config.Transcriber == nil || found.empty() {
+		c.deliver(ctx, data, botName, text, files, decision)
+		return
+	}
+
+	// Transcribing takes a while, so it does not hold up the events of other messages
+	supervisor.Go("slack-transcription", func() {
+		c.deliver(ctx, data, botName, c.withTranscripts(ctx, text, found), files, decision)
+	})
+}
+
+// deliver creates the domain message and sends it to the message channel
+func (c *Client) deliver(ctx context.Context, data MessageData, botName, text string, files []slack.File, decision FilterDecision) {
+	sender, err := c.senderName(ctx, data.SlackUserID, botName)
+	if err != nil {
+		log.Printf("Error fetching user info: %v", err)
+		return
+	}
+
+	images := imageAttachments(files)
+	if strings.TrimSpace(text) == "" && len(images) > 0 {
+		// Images shared without a comment are documented from what is read in them
+		text = sharedImagesText(images)
+	}
+
+	messageContent, err := domain.NewMessageContent(text)
+	if err != nil {
+		log.Printf("Error creating message content: %v", err)
+		return
+	}
+
+	threadTS := data.SlackThreadTS
+	if threadTS == "" {
+		threadTS = data.SlackMessageTS
+	}
+
+	// For now, use default type and category - these will be determined later by AI analysis
+	domainMsg, err := domain.NewMessage(
+		c.threadIDFor(ctx, data.SlackChannelID, threadTS, text),
+		sender,
+		messageContent,
+		domain.MessageTypeInformation,
+		domain.CategoryOther,
+		nil, // no references initially
+	)
+	if err != nil {
+		log.Printf("Error creating domain message: %v", err)
+		return
+	}
+
+	domainMsg.SetChannelID(data.SlackChannelID)
+	domainMsg.SetSourceTimestamp(data.SlackMessageTS)
+	domainMsg.SetTimestamp(tsTime(data.SlackMessageTS))
+	domainMsg.AddAttachments(images...)
+	if decision.MessageType != "" {
+		domainMsg.FixType(decision.MessageType)
+	}
+	if decision.Category != "" {
+		domainMsg.FixCategory(decision.Category)
+	}
+
+	c.rememberMessage(domainMsg.ID().String(), data)
+	c.recordProcessed(ctx, data.SlackChannelID, data.SlackMessageTS)
+
+	// Send to message channel for processing, unless the listener stopped while it was full
+	select {
+	case c.messageCh <- domainMsg:
+	case <-ctx.Done():
+	}
+}
+
+// senderName returns the display name of a person, or the name of a bot for bot messages
+func (c *Client) senderName(ctx context.Context, userID, botName string) (string, error) {
+	if userID == "" {
+		if botName == "" {
+			return "bot", nil
+		}
+		return botName, nil
+	}
+
+	if c.config.UserProfiles != nil {
+		if profile, err := c.config.UserProfiles.Get(ctx, userID); err == nil {
+			return profile.Name, nil
+		} else if !errors.Is(err, ports.ErrNotFound) {
+			log.Printf("Failed to read the cached profile of %s: %v", userID, err)
+		}
+	}
+
+	userInfo, err := c.users.GetUserInfoContext(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if c.config.UserProfiles != nil {
+		profile := domain.UserProfile{ID: userID, Name: userInfo.Name, RealName: userInfo.RealName}
+		if err := c.config.UserProfiles.Put(ctx, profile); err != nil {
+			log.Printf("Failed to cache the profile of %s: %v", userID, err)
+		}
+	}
+	return userInfo.Name, nil
+}
+
+// threadIDFor maps a Slack thread to our ThreadID, creating one for new threads. With a thread repository,
+// the threads stored for the channel are restored first and new threads are stored, titled with their text.
+// With a thread mapping cache, threads another replica created are looked up there, and a new thread is only
+// stored by the replica whose ID the cache kept.
+func (c *Client) threadIDFor(ctx context.Context, channelID, threadTS, text string) common.ID {
+	key := channelID + ":" + threadTS
+
+	c.threadLock.Lock()
+	defer c.threadLock.Unlock()
+
+	if id, exists := c.threadMap[key]; exists {
+		return id
+	}
+	if id, ok := c.cachedThreadID(ctx, channelID, threadTS); ok {
+		c.threadMap[key] = id
+		return id
+	}
+	if c.restoreThreads(ctx, channelID) {
+		if id, exists := c.threadMap[key]; exists {
+			return id
+		}
+	}
+
+	id := common.GenerateID()
+	if c.config.ThreadMappings != nil {
+		mapped, err := c.config.ThreadMappings.Map(ctx, channelID, threadTS, id)
+		if err != nil {
+			log.Printf("Failed to cache thread %s of %s: %v", threadTS, channelID, err)
+		} else if !mapped.Equals(id) {
+			// Another replica created the thread first
+			c.threadMap[key] = mapped
+			return mapped
+		}
+	}
+	c.threadMap[key] = id
+	c.storeThread(ctx, id, channelID, threadTS, text)
+	return id
+}
+
+// cachedThreadID looks a Slack thread up in the thread mapping cache, if any
+func (c *Client) cachedThreadID(ctx context.Context, channelID, threadTS string) (common.ID, bool) {
+	if c.config.ThreadMappings == nil {
+		return common.ID{}, false
+	}
+	id, err := c.config.ThreadMappings.Get(ctx, channelID, threadTS)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Printf("Failed to read the cached thread %s of %s: %v", threadTS, channelID, err)
+		}
+		return common.ID{}, false
+	}
+	return id, true
+}
+
+// restoreThreads loads the stored threads of a channel into the thread map, once per channel.
+// It reports whether any were loaded. Must be called with threadLock held.
+func (c *Client) restoreThreads(ctx context.Context, channelID string) bool {
+	if c.config.Threads == nil || c.restored[channelID] {
+		return false
+	}
+
+	threads, err := c.config.Threads.FindByChannel(ctx, channelID)
+	if err != nil {
+		// Tried again with the next new thread of the channel
+		log.Printf("Failed to restore the threads of %s: %v", channelID, err)
+		return false
+	}
+	c.restored[channelID] = true
+	for _, thread := range threads {
+		if thread.ExternalID() != "" {
+			c.threadMap[channelID+":"+thread.ExternalID()] = thread.ID()
+		}
+	}
+	return len(threads) > 0
+}
+
+// storeThread keeps a new Slack thread in the thread repository, if any. Must be called with threadLock held.
+func (c *Client) storeThread(ctx context.Context, id common.ID, channelID, threadTS, text string) {
+	if c.config.Threads == nil {
+		return
+	}
+
+	thread, err := domain.NewThread(id, channelID, domain.ThreadTitleFrom(text))
+	if err == nil {
+		thread.SetExternalID(threadTS)
+		err = c.config.Threads.Save(ctx, thread)
+	}
+	if err != nil {
+		log.Printf("Failed to store thread %s of %s: %v", threadTS, channelID, err)
+	}
+}
+
+// rememberMessage keeps the Slack location of a domain message so replies land in the right thread
+func (c *Client) rememberMessage(messageID string, data MessageData) {
+	c.threadLock.Lock()
+	defer c.threadLock.Unlock()
+
+	c.messages[messageID] = data
+}
+
+// lookupMessage returns the Slack location of a domain message
+func (c *Client) lookupMessage(messageID string) (MessageData, bool) {
+	c.threadLock.RLock()
+	defer c.threadLock.RUnlock()
+
+	data, ok := c.messages[messageID]
+	return data, ok
+}
+
+// markSeen records a message key and reports whether it was new. With a dedup store the key is recorded there,
+// when the store fails only the keys this client saw are checked.
+func (c *Client) markSeen(ctx context.Context, key string) bool {
+	if c.config.Dedup != nil {
+		isNew, err := c.config.Dedup.MarkSeen(ctx, key)
+		if err == nil {
+			return isNew
+		}
+		log.Printf("Failed to check message %s for duplicates: %v", key, err)
+	}
+
+	c.threadLock.Lock()
+	defer c.threadLock.Unlock()
+
+	if _, ok := c.seen[key]; ok {
+		return false
+	}
+	if len(c.seenOrder) >= maxSeenMessages {
+		delete(c.seen, c.seenOrder[0])
+		c.seenOrder = c.seenOrder[1:]
+	}
+	c.seen[key] = struct{}{}
+	c.seenOrder = append(c.seenOrder, key)
+	return true
+}
+
+// replyThreadTS returns the thread a reply to the message belongs in
+func (d MessageData) replyThreadTS() string {
+	if strings.TrimSpace(d.SlackThreadTS) != "" {
+		return d.SlackThreadTS
+	}
+	return d.SlackMessageTS
+}
@@ -0,0 +1,116 @@
+package slack
+
+import (
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// DefaultProcessedSubtypes are the message subtypes that carry content written by people
+var DefaultProcessedSubtypes = []string{"", "thread_broadcast", "file_share", "me_message"}
+
+const botMessageSubtype = "bot_message"
+
+// AllowedBot is a bot whose messages are processed, e.g. CI notifications
+type AllowedBot struct {
+	// BotID is the Slack bot ID (starts with "B")
+	BotID string
+
+	// MessageType is the type given to the bot's messages; empty lets the AI decide
+	MessageType domain.MessageType
+}
+
+// FilterDecision is the outcome of evaluating a message against the filter policy
+type FilterDecision struct {
+	// Accept reports whether the message should be processed
+	Accept bool
+
+	// MessageType is set when the message type is fixed by the policy
+	MessageType domain.MessageType
+
+	// Category is set when the category is fixed, like for messages captured with the shortcut
+	Category domain.Category
+
+	// Reason explains why a message was dropped
+	Reason string
+}
+
+// MessageFilter decides which Slack messages reach the bot
+type MessageFilter struct {
+	ignoredUsers      map[string]bool
+	ignoredBots       map[string]bool
+	allowedBots       map[string]domain.MessageType
+	processedSubtypes map[string]bool
+}
+
+// NewMessageFilter creates a MessageFilter from the configuration
+func NewMessageFilter(config *Config) *MessageFilter {
+	f := &MessageFilter{
+		ignoredUsers:      make(map[string]bool),
+		ignoredBots:       make(map[string]bool),
+		allowedBots:       make(map[string]domain.MessageType),
+		processedSubtypes: make(map[string]bool),
+	}
+
+	subtypes := DefaultProcessedSubtypes
+	if config != nil && config.ProcessedSubtypes != nil {
+		subtypes = config.ProcessedSubtypes
+	}
+	for _, subtype := range subtypes {
+		f.processedSubtypes[subtype] = true
+	}
+
+	if config == nil {
+		return f
+	}
+	for _, id := range config.IgnoredUserIDs {
+		f.ignoredUsers[id] = true
+	}
+	for _, id := range config.IgnoredBotIDs {
+		f.ignoredBots[id] = true
+	}
+	for _, bot := range config.AllowedBots {
+		f.allowedBots[bot.BotID] = bot.MessageType
+	}
+	return f
+}
+
+// EvaluateMessage applies the policy to a message event
+func (f *MessageFilter) EvaluateMessage(ev *slackevents.MessageEvent) FilterDecision {
+	if ev.BotID != "" || ev.SubType == botMessageSubtype {
+		return f.evaluateBot(ev.BotID)
+	}
+
+	if !f.processedSubtypes[ev.SubType] {
+		return FilterDecision{Reason: "system message subtype " + ev.SubType}
+	}
+	return f.evaluateUser(ev.User)
+}
+
+// EvaluateMention applies the policy to an app_mention event
+func (f *MessageFilter) EvaluateMention(ev *slackevents.AppMentionEvent) FilterDecision {
+	if ev.BotID != "" {
+		return f.evaluateBot(ev.BotID)
+	}
+	return f.evaluateUser(ev.User)
+}
+
+func (f *MessageFilter) evaluateUser(userID string) FilterDecision {
+	if userID == "" {
+		return FilterDecision{Reason: "message has no author"}
+	}
+	if f.ignoredUsers[userID] {
+		return FilterDecision{Reason: "ignored user " + userID}
+	}
+	return FilterDecision{Accept: true}
+}
+
+func (f *MessageFilter) evaluateBot(botID string) FilterDecision {
+	if f.ignoredBots[botID] {
+		return FilterDecision{Reason: "ignored bot " + botID}
+	}
+	msgType, ok := f.allowedBots[botID]
+	if !ok {
+		return FilterDecision{Reason: "bot " + botID + " is not allowed"}
+	}
+	return FilterDecision{Accept: true, MessageType: msgType}
+}
@@ -0,0 +1,81 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageFilter_EvaluateMessage(t *testing.T) {
+	filter := NewMessageFilter(&Config{
+		IgnoredUserIDs: []string{"U0IGNORED"},
+		IgnoredBotIDs:  []string{"B0NOISY"},
+		AllowedBots: []AllowedBot{
+			{BotID: "B0CI", MessageType: domain.MessageTypeStatus},
+			{BotID: "B0NOISY", MessageType: domain.MessageTypeStatus},
+			{BotID: "B0FREE"},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		event      *slackevents.MessageEvent
+		wantAccept bool
+		wantType   domain.MessageType
+	}{
+		{name: "regular message", event: &slackevents.MessageEvent{User: "U0001"}, wantAccept: true},
+		{name: "thread broadcast", event: &slackevents.MessageEvent{User: "U0001", SubType: "thread_broadcast"}, wantAccept: true},
+		{name: "channel join", event: &slackevents.MessageEvent{User: "U0001", SubType: "channel_join"}},
+		{name: "message edited", event: &slackevents.MessageEvent{SubType: "message_changed"}},
+		{name: "ignored user", event: &slackevents.MessageEvent{User: "U0IGNORED"}},
+		{name: "unknown bot", event: &slackevents.MessageEvent{BotID: "B0OTHER", SubType: "bot_message"}},
+		{name: "ignored bot wins over allowed", event: &slackevents.MessageEvent{BotID: "B0NOISY", SubType: "bot_message"}},
+		{
+			name:       "allowed CI bot becomes status update",
+			event:      &slackevents.MessageEvent{BotID: "B0CI", SubType: "bot_message"},
+			wantAccept: true,
+			wantType:   domain.MessageTypeStatus,
+		},
+		{
+			name:       "allowed bot without fixed type",
+			event:      &slackevents.MessageEvent{BotID: "B0FREE", SubType: "bot_message"},
+			wantAccept: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := filter.EvaluateMessage(tt.event)
+			assert.Equal(t, tt.wantAccept, decision.Accept)
+			assert.Equal(t, tt.wantType, decision.MessageType)
+			if !decision.Accept {
+				assert.NotEmpty(t, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestMessageFilter_CustomSubtypes(t *testing.T) {
+	filter := NewMessageFilter(&Config{ProcessedSubtypes: []string{""}})
+
+	assert.True(t, filter.EvaluateMessage(&slackevents.MessageEvent{User: "U0001"}).Accept)
+	assert.False(t, filter.EvaluateMessage(&slackevents.MessageEvent{User: "U0001", SubType: "file_share"}).Accept)
+}
+
+func TestClient_ProcessesAllowedBotMessages(t *testing.T) {
+	client := newTestClient(t)
+	client.filter = NewMessageFilter(&Config{
+		AllowedBots: []AllowedBot{{BotID: "B0001", MessageType: domain.MessageTypeStatus}},
+	})
+
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "bot_message.json"))
+	msg := receive(t, client)
+	if assert.NotNil(t, msg) {
+		assert.Equal(t, "ci", msg.Sender())
+		assert.Equal(t, domain.MessageTypeStatus, msg.Type())
+		assert.True(t, msg.HasFixedType())
+	}
+}
@@ -0,0 +1,263 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const (
+	// HomeRefreshActionID identifies the button republishing the Home tab
+	HomeRefreshActionID = "home_refresh"
+	// HomeApproveActionID identifies the buttons approving a held message from the Home tab
+	HomeApproveActionID = "home_approve"
+	// HomeRejectActionID identifies the buttons rejecting a held message from the Home tab
+	HomeRejectActionID = "home_reject"
+
+	// maxHomeApprovals bounds the approvals listed on the Home tab, which holds at most 100 blocks
+	maxHomeApprovals = 20
+)
+
+// homeAPI is the part of the Slack Web API the Home tab is published with
+type homeAPI interface {
+	PublishViewContext(ctx context.Context, req slack.PublishViewContextRequest) (*slack.ViewResponse, error)
+	GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error)
+}
+
+// OnHomeOpened registers the function returning what people see on their Home tab
+func (c *Client) OnHomeOpened(home func(ctx context.Context, viewer domain.HomeViewer) (*domain.Home, error)) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.homePage = home
+}
+
+// OnModerationDecision registers the function approving or rejecting held messages from the Home tab
+func (c *Client) OnModerationDecision(decide func(ctx context.Context, messageID, actor string, approve bool) error) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.decideHeld = decide
+}
+
+// processAppHomeOpenedEvent publishes the Home tab each time someone opens it, so it is never stale
+func (c *Client) processAppHomeOpenedEvent(ctx context.Context, ev *slackevents.AppHomeOpenedEvent) {
+	if ev.Tab != "home" {
+		return
+	}
+	if err := c.publishHome(ctx, ev.User, ""); err != nil {
+		log.Printf("Failed to publish the Slack Home tab of %s: %v", ev.User, err)
+	}
+}
+
+// applyHomeAction handles the buttons of the Home tab and publishes it again, with the outcome of the click
+func (c *Client) applyHomeAction(ctx context.Context, interaction *slack.InteractionCallback, action *slack.BlockAction) error {
+	notice := ""
+	if action.ActionID != HomeRefreshActionID {
+		notice = c.decideFromHome(ctx, interaction.User.ID, action.Value, action.ActionID == HomeApproveActionID)
+	}
+	return c.publishHome(ctx, interaction.User.ID, notice)
+}
+
+// decideFromHome approves or rejects a held message and describes the outcome
+func (c *Client) decideFromHome(ctx context.Context, userID, messageID string, approve bool) string {
+	c.formLock.Lock()
+	decide := c.decideHeld
+	c.formLock.Unlock()
+	if decide == nil {
+		return "⚠️ Held messages cannot be approved from here"
+	}
+
+	actor, err := c.senderName(ctx, userID, "")
+	if err != nil {
+		return fmt.Sprintf("⚠️ Failed to look up who decided: %s", err)
+	}
+	if err := decide(ctx, messageID, actor, approve); err != nil {
+		return fmt.Sprintf("⚠️ Failed to decide on the held message: %s", err)
+	}
+	if approve {
+		return "✅ Approved the held message"
+	}
+	return "🚫 Rejected the held message"
+}
+
+// publishHome builds the Home tab of a user and publishes it with views.publish
+func (c *Client) publishHome(ctx context.Context, userID, notice string) error {
+	c.formLock.Lock()
+	home := c.homePage
+	c.formLock.Unlock()
+	if home == nil {
+		return nil
+	}
+
+	name, err := c.senderName(ctx, userID, "")
+	if err != nil {
+		return fmt.Errorf("failed to look up user: %w", err)
+	}
+	channels, err := c.channelsOf(ctx, userID)
+	if err != nil {
+		return err
+	}
+	viewer, err := domain.NewHomeViewer(name, channels)
+	if err != nil {
+		return err
+	}
+
+	page, err := home(ctx, viewer)
+	if err != nil {
+		return fmt.Errorf("failed to build home: %w", err)
+	}
+
+	_, err = c.home.PublishViewContext(ctx, slack.PublishViewContextRequest{UserID: userID, View: buildHomeView(page, notice)})
+	if err != nil {
+		return fmt.Errorf("failed to publish home: %w", err)
+	}
+	return nil
+}
+
+// channelsOf lists the channels the user is a member of, following users.conversations pages
+func (c *Client) channelsOf(ctx context.Context, userID string) ([]string, error) {
+	params := &slack.GetConversationsForUserParameters{
+		UserID:          userID,
+		Types:           []string{"public_channel", "private_channel"},
+		Limit:           200,
+		ExcludeArchived: true,
+	}
+
+	var ids []string
+	for {
+		channels, cursor, err := c.home.GetConversationsForUserContext(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list channels of user: %w", err)
+		}
+		for _, channel := range channels {
+			ids = append(ids, channel.ID)
+		}
+		if cursor == "" {
+			return ids, nil
+		}
+		params.Cursor = cursor
+	}
+}
+
+// isHomeAction checks if a block action is a click on a Home tab button
+func isHomeAction(actionID string) bool {
+	return actionID == HomeRefreshActionID || actionID == HomeApproveActionID || actionID == HomeRejectActionID
+}
+
+// buildHomeView lays out the Home tab: the notice of the last click, the latest captures, the documents the
+// user owns pending review, what waits for approval and the projects of the user's channels
+func buildHomeView(home *domain.Home, notice string) slack.HomeTabViewRequest {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "Quill", false, false)),
+		slack.NewActionBlock("home_actions",
+			slack.NewButtonBlockElement(HomeRefreshActionID, "", slack.NewTextBlockObject(slack.PlainTextType, "🔄 Refresh", true, false))),
+	}
+	if notice != "" {
+		blocks = append(blocks, homeContext(notice))
+	}
+
+	blocks = append(blocks, slack.NewDividerBlock(), homeSection("*📥 Your latest captures*"))
+	if len(home.Captures) == 0 {
+		blocks = append(blocks, homeContext("Nothing was documented from your messages yet"))
+	}
+	for _, capture := range home.Captures {
+		blocks = append(blocks, homeSection(fmt.Sprintf("<%s|%s>\n%s · %s · %s",
+			capture.Link, capture.Title, capture.Type, capture.Category, slackDate(capture.CapturedAt))))
+	}
+
+	blocks = append(blocks, slack.NewDividerBlock(), homeSection("*🕰️ Your documents pending review*"))
+	if len(home.Reviews) == 0 {
+		blocks = append(blocks, homeContext("None of the documents you own waits for review"))
+	}
+	for _, review := range home.Reviews {
+		blocks = append(blocks, homeSection(fmt.Sprintf("<%s|%s>\n%s · reconfirm or supersede it", review.Link, review.Title, review.Category)))
+	}
+
+	blocks = append(blocks, slack.NewDividerBlock(), homeSection("*⏳ Waiting for approval*"))
+	if len(home.Approvals) == 0 {
+		blocks = append(blocks, homeContext("Nothing waits for approval in your channels"))
+	}
+	for i, approval := range home.Approvals {
+		if i == maxHomeApprovals {
+			blocks = append(blocks, homeContext(fmt.Sprintf("…and %d more", len(home.Approvals)-maxHomeApprovals)))
+			break
+		}
+		blocks = append(blocks, approvalBlocks(approval)...)
+	}
+
+	blocks = append(blocks, slack.NewDividerBlock(), homeSection("*📚 Projects in your channels*"))
+	if len(home.Projects) == 0 {
+		blocks = append(blocks, homeContext("None of your channels is bound to a project"))
+	}
+	for _, project := range home.Projects {
+		blocks = append(blocks, homeSection(projectLine(project)))
+	}
+
+	return slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}
+}
+
+// approvalBlocks describes something waiting for approval. Held messages get buttons, updates are applied
+// in their thread where the diff was posted, and decisions are approved in their thread.
+func approvalBlocks(approval domain.PendingApproval) []slack.Block {
+	switch approval.Kind {
+	case domain.ApprovalUpdate:
+		return []slack.Block{homeSection(fmt.Sprintf("✏️ Update to %s waiting in <#%s> since %s",
+			approval.Subject, approval.ChannelID, slackDate(approval.Since)))}
+	case domain.ApprovalDecision:
+		return []slack.Block{homeSection(fmt.Sprintf("🔐 Decision in <#%s> since %s, %s\n>%s", approval.ChannelID,
+			slackDate(approval.Since), approval.Reason, strings.ReplaceAll(approval.Subject, "\n", "\n>")))}
+	}
+
+	text := fmt.Sprintf("🛑 Held in <#%s> since %s\n>%s", approval.ChannelID, slackDate(approval.Since),
+		strings.ReplaceAll(approval.Subject, "\n", "\n>"))
+	if approval.Reason != "" {
+		text += "\n_" + approval.Reason + "_"
+	}
+
+	approve := slack.NewButtonBlockElement(HomeApproveActionID, approval.MessageID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false))
+	approve.Style = slack.StylePrimary
+	reject := slack.NewButtonBlockElement(HomeRejectActionID, approval.MessageID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Reject", false, false))
+	reject.Style = slack.StyleDanger
+
+	return []slack.Block{
+		homeSection(text),
+		slack.NewActionBlock("home_approval_"+approval.MessageID, approve, reject),
+	}
+}
+
+// projectLine describes a project with its status, the user's channels bound to it and its repository
+func projectLine(project domain.HomeProject) string {
+	channels := make([]string, len(project.Channels))
+	for i, channel := range project.Channels {
+		channels[i] = "<#" + channel + ">"
+	}
+
+	line := fmt.Sprintf("*%s* (%s) · %s", project.Name, project.Status, strings.Join(channels, ", "))
+	if project.Repository != "" {
+		line += " · `" + project.Repository + "`"
+	}
+	return line
+}
+
+func homeSection(text string) *slack.SectionBlock {
+	return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)
+}
+
+func homeContext(text string) *slack.ContextBlock {
+	return slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, text, false, false))
+}
+
+// slackDate is formatted by Slack in the reader's time zone
+func slackDate(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{date_short_pretty}|%s>", t.Unix(), t.UTC().Format("2006-01-02"))
+}
@@ -0,0 +1,171 @@
+package slack
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHome records published Home tabs and lists the channels of each user, one per page
+type stubHome struct {
+	channels  map[string][]string
+	published []slack.PublishViewContextRequest
+}
+
+func (s *stubHome) PublishViewContext(ctx context.Context, req slack.PublishViewContextRequest) (*slack.ViewResponse, error) {
+	s.published = append(s.published, req)
+	return &slack.ViewResponse{}, nil
+}
+
+func (s *stubHome) GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	channels := s.channels[params.UserID]
+	page, _ := strconv.Atoi(params.Cursor)
+	if page >= len(channels) {
+		return nil, "", nil
+	}
+
+	channel := slack.Channel{}
+	channel.ID = channels[page]
+	cursor := ""
+	if page+1 < len(channels) {
+		cursor = strconv.Itoa(page + 1)
+	}
+	return []slack.Channel{channel}, cursor, nil
+}
+
+func testHome() *domain.Home {
+	since := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	return &domain.Home{
+		Captures: []domain.HomeCapture{{
+			Title:      "Adopt Postgres",
+			Link:       adoptPostgresURL,
+			Type:       domain.MessageTypeDecision,
+			Category:   domain.CategoryDevelopment,
+			CapturedAt: since,
+		}},
+		Reviews: []domain.HomeReview{{Title: "Adopt Postgres", Link: adoptPostgresURL, Category: domain.CategoryDevelopment}},
+		Approvals: []domain.PendingApproval{
+			{Kind: domain.ApprovalModeration, MessageID: "M0001", ChannelID: "C0001", Subject: "We decided to bill Acme Corp", Reason: `contains "Acme Corp"`, Since: since},
+			{Kind: domain.ApprovalUpdate, MessageID: "M0002", ChannelID: "C0002", Subject: adoptPostgresURL, Since: since},
+			{Kind: domain.ApprovalDecision, MessageID: "M0003", ChannelID: "C0001", Subject: "We decided to drop MySQL", Reason: "needs 1 approval", Since: since},
+		},
+		Projects: []domain.HomeProject{{Name: "Billing", Status: domain.ProjectStatusActive, Channels: []string{"C0001"}, Repository: "acme/billing-docs"}},
+	}
+}
+
+// homeText joins the text of the sections of a published Home tab
+func homeText(view slack.HomeTabViewRequest) []string {
+	var texts []string
+	for _, block := range view.Blocks.BlockSet {
+		switch b := block.(type) {
+		case *slack.SectionBlock:
+			texts = append(texts, b.Text.Text)
+		case *slack.ContextBlock:
+			texts = append(texts, b.ContextElements.Elements[0].(*slack.TextBlockObject).Text)
+		}
+	}
+	return texts
+}
+
+func TestClient_PublishesHomeWhenOpened(t *testing.T) {
+	client := newTestClient(t)
+	home := &stubHome{channels: map[string][]string{"U0001": {"C0001", "C0002"}}}
+	client.home = home
+	var viewer domain.HomeViewer
+	client.OnHomeOpened(func(ctx context.Context, v domain.HomeViewer) (*domain.Home, error) {
+		viewer = v
+		return testHome(), nil
+	})
+
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "app_home_opened.json"))
+
+	assert.Equal(t, "alice", viewer.Name)
+	assert.Equal(t, []string{"C0001", "C0002"}, viewer.Channels, "every page of channels is listed")
+	require.Len(t, home.published, 1)
+	published := home.published[0]
+	assert.Equal(t, "U0001", published.UserID)
+	assert.Equal(t, slack.VTHomeTab, published.View.Type)
+	texts := homeText(published.View)
+	assert.Contains(t, texts, "<"+adoptPostgresURL+"|Adopt Postgres>\ndecision · development · <!date^1718010000^{date_short_pretty}|2024-06-10>")
+	assert.Contains(t, texts, "<"+adoptPostgresURL+"|Adopt Postgres>\ndevelopment · reconfirm or supersede it")
+	assert.Contains(t, texts, "🛑 Held in <#C0001> since <!date^1718010000^{date_short_pretty}|2024-06-10>\n>We decided to bill Acme Corp\n_contains \"Acme Corp\"_")
+	assert.Contains(t, texts, "✏️ Update to "+adoptPostgresURL+" waiting in <#C0002> since <!date^1718010000^{date_short_pretty}|2024-06-10>")
+	assert.Contains(t, texts, "🔐 Decision in <#C0001> since <!date^1718010000^{date_short_pretty}|2024-06-10>, needs 1 approval\n>We decided to drop MySQL")
+	assert.Contains(t, texts, "*Billing* (active) · <#C0001> · `acme/billing-docs`")
+
+	var buttons []string
+	for _, block := range published.View.Blocks.BlockSet {
+		if actions, ok := block.(*slack.ActionBlock); ok {
+			for _, element := range actions.Elements.ElementSet {
+				button := element.(*slack.ButtonBlockElement)
+				buttons = append(buttons, button.ActionID+":"+button.Value)
+			}
+		}
+	}
+	assert.Equal(t, []string{"home_refresh:", "home_approve:M0001", "home_reject:M0001"}, buttons, "updates and decisions are approved in their thread")
+}
+
+func TestClient_IgnoresOtherAppTabs(t *testing.T) {
+	client := newTestClient(t)
+	home := &stubHome{}
+	client.home = home
+	client.OnHomeOpened(func(ctx context.Context, v domain.HomeViewer) (*domain.Home, error) {
+		return testHome(), nil
+	})
+	event := loadEvent(t, "app_home_opened.json")
+	event.InnerEvent.Data.(*slackevents.AppHomeOpenedEvent).Tab = "messages"
+
+	client.handleEventsAPIEvent(context.Background(), event)
+
+	assert.Empty(t, home.published)
+}
+
+func TestClient_DecidesHeldMessagesFromHome(t *testing.T) {
+	tests := []struct {
+		actionID    string
+		wantApprove bool
+		wantNotice  string
+	}{
+		{actionID: HomeApproveActionID, wantApprove: true, wantNotice: "✅ Approved the held message"},
+		{actionID: HomeRejectActionID, wantApprove: false, wantNotice: "🚫 Rejected the held message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.actionID, func(t *testing.T) {
+			client := newTestClient(t)
+			home := &stubHome{channels: map[string][]string{"U0002": {"C0001"}}}
+			client.home = home
+			client.OnHomeOpened(func(ctx context.Context, v domain.HomeViewer) (*domain.Home, error) {
+				return &domain.Home{}, nil
+			})
+			var decided []string
+			var approved bool
+			client.OnModerationDecision(func(ctx context.Context, messageID, actor string, approve bool) error {
+				decided = append(decided, messageID, actor)
+				approved = approve
+				return nil
+			})
+
+			err := client.processInteractionCallback(context.Background(), &slack.InteractionCallback{
+				Type: slack.InteractionTypeBlockActions,
+				User: slack.User{ID: "U0002"},
+				ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+					{ActionID: tt.actionID, Value: "M0001"},
+				}},
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"M0001", "bob"}, decided)
+			assert.Equal(t, tt.wantApprove, approved)
+			require.Len(t, home.published, 1, "the Home tab is published again")
+			assert.Contains(t, homeText(home.published[0].View), tt.wantNotice)
+		})
+	}
+}
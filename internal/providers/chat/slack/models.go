@@ -1,6 +1,9 @@
 package slack
 
 import (
+	"encoding/json"
+
+	"github.com/massimo-ua/quill/internal/domain"
 	"github.com/slack-go/slack"
 )
 
@@ -56,31 +59,40 @@ type MessageCategory struct {
 	Description string
 }
 
-// GetCategoryBlocks returns blocks for category selection
-func GetCategoryBlocks() []slack.Block {
-	// Define categories
-	categories := []MessageCategory{
-		{Value: "development", Text: "Development", Description: "Code, architecture, technical implementation"},
-		{Value: "product", Text: "Product", Description: "Features, requirements, UX decisions"},
-		{Value: "operations", Text: "Operations", Description: "Infrastructure, deployment, monitoring"},
-		{Value: "qa", Text: "QA", Description: "Testing, quality assurance, validation"},
-		{Value: "data", Text: "Data", Description: "Analytics, metrics, insights"},
-		{Value: "other", Text: "Other", Description: "Other information"},
-	}
+// categories are the buttons offered for filing a document under another category
+var categories = []MessageCategory{
+	{Value: domain.CategoryDevelopment.String(), Text: "Development", Description: "Code, architecture, technical implementation"},
+	{Value: domain.CategoryProduct.String(), Text: "Product", Description: "Features, requirements, UX decisions"},
+	{Value: domain.CategoryOperations.String(), Text: "Operations", Description: "Infrastructure, deployment, monitoring"},
+	{Value: domain.CategoryQualityAssurance.String(), Text: "QA", Description: "Testing, quality assurance, validation"},
+	{Value: domain.CategoryDataAnalysis.String(), Text: "Data", Description: "Analytics, metrics, insights"},
+	{Value: domain.CategoryOther.String(), Text: "Other", Description: "Other information"},
+}
 
+// GetCategoryBlocks returns the text followed by a button for each category, the current one highlighted.
+// Each button carries the document and its source message so a click can move the document.
+func GetCategoryBlocks(text, path, messageID string, current domain.Category) ([]slack.Block, error) {
 	// Create header section
-	headerText := slack.NewTextBlockObject(slack.MarkdownType, "Please categorize this message:", false, false)
+	headerText := slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
 	headerSection := slack.NewSectionBlock(headerText, nil, nil)
 
 	// Create buttons for each category
 	var buttons []slack.BlockElement
 	for _, category := range categories {
+		value, err := json.Marshal(categoryChoice{Path: path, MessageID: messageID, Category: category.Value})
+		if err != nil {
+			return nil, err
+		}
+
 		buttonText := slack.NewTextBlockObject(slack.PlainTextType, category.Text, false, false)
 		button := slack.NewButtonBlockElement(
-			"category_"+category.Value,
-			category.Value,
+			categoryActionPrefix+category.Value,
+			string(value),
 			buttonText,
 		)
+		if category.Value == current.String() {
+			button.Style = slack.StylePrimary
+		}
 		buttons = append(buttons, button)
 	}
 
@@ -88,5 +100,29 @@ func GetCategoryBlocks() []slack.Block {
 	actionBlock := slack.NewActionBlock("category_selection", buttons...)
 
 	// Return the blocks
-	return []slack.Block{headerSection, actionBlock}
-}
\ No newline at end of file
+	return []slack.Block{headerSection, actionBlock}, nil
+}
+
+// GetReviewBlocks returns the text followed by buttons for reconfirming and superseding a document.
+// Each button carries the document's path so a click can record the verdict in its front matter.
+func GetReviewBlocks(text, path string) ([]slack.Block, error) {
+	headerText := slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+	headerSection := slack.NewSectionBlock(headerText, nil, nil)
+
+	var buttons []slack.BlockElement
+	for _, verdict := range reviewVerdicts {
+		value, err := json.Marshal(reviewChoice{Path: path, Verdict: verdict.Value})
+		if err != nil {
+			return nil, err
+		}
+
+		buttonText := slack.NewTextBlockObject(slack.PlainTextType, verdict.Text, false, false)
+		button := slack.NewButtonBlockElement(reviewActionPrefix+verdict.Value, string(value), buttonText)
+		button.Style = verdict.Style
+		buttons = append(buttons, button)
+	}
+
+	actionBlock := slack.NewActionBlock("review_selection", buttons...)
+
+	return []slack.Block{headerSection, actionBlock}, nil
+}
@@ -0,0 +1,178 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// ProjectWizardActionID identifies the button that opens the onboarding wizard
+	ProjectWizardActionID = "project_wizard_open"
+	// ProjectWizardCallbackID identifies submissions of the onboarding wizard
+	ProjectWizardCallbackID = "project_wizard"
+
+	projectNameBlockID     = "project_name"
+	projectTemplateBlockID = "project_template"
+)
+
+// projectWizardMetadata travels with the button and the modal so the project is bound to the channel the wizard
+// was started in and the result is posted in its thread
+type projectWizardMetadata struct {
+	ChannelID string `json:"channel_id"`
+	ThreadTS  string `json:"thread_ts"`
+}
+
+// OpenProjectWizard posts a button in the thread of a message that opens the onboarding wizard, a modal creating
+// a project from one of the templates. Slack only opens modals in response to a user action.
+func (c *Client) OpenProjectWizard(ctx context.Context, messageID string, templates []domain.ProjectTemplate) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to open project wizard: unknown message %s", messageID)
+	}
+
+	meta, err := json.Marshal(projectWizardMetadata{ChannelID: data.SlackChannelID, ThreadTS: data.replyThreadTS()})
+	if err != nil {
+		return fmt.Errorf("failed to open project wizard: %w", err)
+	}
+
+	c.formLock.Lock()
+	c.projectTemplates = append([]domain.ProjectTemplate(nil), templates...)
+	c.formLock.Unlock()
+
+	text := slack.NewTextBlockObject(slack.MarkdownType, "🧩 Set up a project for this channel from a template", false, false)
+	button := slack.NewButtonBlockElement(ProjectWizardActionID, string(meta),
+		slack.NewTextBlockObject(slack.PlainTextType, "Set up project", false, false))
+
+	_, _, err = c.web.PostMessageContext(
+		ctx,
+		data.SlackChannelID,
+		slack.MsgOptionText("Set up a project", false),
+		slack.MsgOptionBlocks(slack.NewSectionBlock(text, nil, slack.NewAccessory(button))),
+		slack.MsgOptionTS(data.replyThreadTS()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open project wizard: %w", err)
+	}
+	return nil
+}
+
+// OnProjectCreate registers the function creating the projects of submitted onboarding wizards
+func (c *Client) OnProjectCreate(create func(ctx context.Context, draft *domain.ProjectDraft) (*domain.Project, error)) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.createProject = create
+}
+
+// openProjectWizardModal opens the onboarding wizard when its button is clicked
+func (c *Client) openProjectWizardModal(ctx context.Context, triggerID, value string) error {
+	var meta projectWizardMetadata
+	if err := json.Unmarshal([]byte(value), &meta); err != nil {
+		return fmt.Errorf("invalid project wizard button: %w", err)
+	}
+
+	c.formLock.Lock()
+	templates := c.projectTemplates
+	c.formLock.Unlock()
+	if len(templates) == 0 {
+		return c.postProjectWizardResult(ctx, meta, "⚠️ This form has expired, run `/quill project new` again")
+	}
+
+	if _, err := c.web.OpenViewContext(ctx, triggerID, buildProjectWizardModal(templates, value)); err != nil {
+		return fmt.Errorf("failed to open project wizard modal: %w", err)
+	}
+	return nil
+}
+
+// submitProjectWizard creates the project of a submitted wizard and reports the result in the original thread
+func (c *Client) submitProjectWizard(ctx context.Context, interaction *slack.InteractionCallback) error {
+	var meta projectWizardMetadata
+	if err := json.Unmarshal([]byte(interaction.View.PrivateMetadata), &meta); err != nil {
+		return fmt.Errorf("invalid project wizard submission: %w", err)
+	}
+
+	c.formLock.Lock()
+	create := c.createProject
+	c.formLock.Unlock()
+	if create == nil {
+		return fmt.Errorf("project wizard submitted but no handler is registered")
+	}
+
+	creator, err := c.senderName(ctx, interaction.User.ID, "")
+	if err != nil {
+		log.Printf("Failed to look up Slack user %s: %v", interaction.User.ID, err)
+		creator = interaction.User.ID
+	}
+
+	values := interaction.View.State
+	draft, err := domain.NewProjectDraft(
+		stateValue(values, projectNameBlockID),
+		selectedValue(values, projectTemplateBlockID),
+		stateValue(values, projectDescriptionBlockID),
+		domain.SplitLines(stateValue(values, projectGoalsBlockID)),
+		domain.SplitLines(stateValue(values, projectKPIsBlockID)),
+		meta.ChannelID,
+		creator,
+	)
+	if err != nil {
+		return c.postProjectWizardResult(ctx, meta, fmt.Sprintf("⚠️ Failed to create project: %s", err))
+	}
+	project, err := create(ctx, draft)
+	if err != nil {
+		return c.postProjectWizardResult(ctx, meta, fmt.Sprintf("⚠️ Failed to create project: %s", err))
+	}
+
+	return c.postProjectWizardResult(ctx, meta, fmt.Sprintf("✅ Created project *%s* from the %s template, messages in <#%s> are documented for it",
+		project.Name(), draft.Template().Name, meta.ChannelID))
+}
+
+func (c *Client) postProjectWizardResult(ctx context.Context, meta projectWizardMetadata, text string) error {
+	_, _, err := c.web.PostMessageContext(ctx, meta.ChannelID, slack.MsgOptionText(text, false), slack.MsgOptionTS(meta.ThreadTS))
+	if err != nil {
+		return fmt.Errorf("failed to post project wizard result: %w", err)
+	}
+	return nil
+}
+
+// buildProjectWizardModal builds the onboarding wizard, the first template is picked unless people pick another
+func buildProjectWizardModal(templates []domain.ProjectTemplate, metadata string) slack.ModalViewRequest {
+	options := make([]*slack.OptionBlockObject, len(templates))
+	for i, template := range templates {
+		options[i] = slack.NewOptionBlockObject(template.Name,
+			slack.NewTextBlockObject(slack.PlainTextType, template.Name, false, false),
+			slack.NewTextBlockObject(slack.PlainTextType, truncateOption(template.Description), false, false))
+	}
+	picker := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, nil, captureSelectActionID, options...)
+	picker.InitialOption = options[0]
+
+	name := slack.NewPlainTextInputBlockElement(nil, projectInputActionID)
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      ProjectWizardCallbackID,
+		PrivateMetadata: metadata,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "New project", false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Create", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewInputBlock(projectNameBlockID, slack.NewTextBlockObject(slack.PlainTextType, "Name", false, false), nil, name),
+			slack.NewInputBlock(projectTemplateBlockID, slack.NewTextBlockObject(slack.PlainTextType, "Template", false, false),
+				slack.NewTextBlockObject(slack.PlainTextType, "Sets the taxonomy, path scheme, document outlines and digest schedules", false, false), picker),
+			textInputBlock(projectDescriptionBlockID, "Description", "", "", true),
+			textInputBlock(projectGoalsBlockID, "Business goals", "One goal per line", "", false),
+			textInputBlock(projectKPIsBlockID, "KPIs", "One KPI per line", "", true),
+		}},
+	}
+}
+
+// truncateOption shortens the description of an option to the 75 characters Slack allows
+func truncateOption(text string) string {
+	if runes := []rune(text); len(runes) > 75 {
+		return string(runes[:74]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wizardSubmission(meta, name, template, goals string) *slack.InteractionCallback {
+	value := func(v string) map[string]slack.BlockAction {
+		return map[string]slack.BlockAction{projectInputActionID: {Value: v}}
+	}
+
+	return &slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		User: slack.User{ID: "U0001"},
+		View: slack.View{
+			CallbackID:      ProjectWizardCallbackID,
+			PrivateMetadata: meta,
+			State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+				projectNameBlockID:        value(name),
+				projectTemplateBlockID:    {captureSelectActionID: {SelectedOption: slack.OptionBlockObject{Value: template}}},
+				projectDescriptionBlockID: value("Experiments on pricing"),
+				projectGoalsBlockID:       value(goals),
+				projectKPIsBlockID:        value(""),
+			}},
+		},
+	}
+}
+
+func TestClient_ProjectWizard(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackMessageTS: "1700000000.000100"})
+
+	var created *domain.ProjectDraft
+	client.OnProjectCreate(func(ctx context.Context, draft *domain.ProjectDraft) (*domain.Project, error) {
+		created = draft
+		return domain.NewProject(draft.Metadata().Name, draft.Metadata().Description, draft.Metadata().BusinessGoals)
+	})
+
+	// The command posts a button in the thread of the command message
+	require.NoError(t, client.OpenProjectWizard(ctx, "MSG1", domain.ProjectTemplates()))
+	require.Len(t, web.posts, 1)
+	assert.Equal(t, "1700000000.000100", web.posts[0].threadTS)
+
+	var blocks []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(web.posts[0].blocks), &blocks))
+	buttonValue := blocks[0]["accessory"].(map[string]interface{})["value"].(string)
+
+	// Clicking the button opens the wizard with every template to pick from
+	require.NoError(t, client.HandleInteraction(ctx, &slack.InteractionCallback{
+		Type:      slack.InteractionTypeBlockActions,
+		TriggerID: "trigger-1",
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: ProjectWizardActionID, Value: buttonValue},
+		}},
+	}))
+	require.Len(t, web.views, 1)
+	view := web.views[0]
+	assert.Equal(t, ProjectWizardCallbackID, view.CallbackID)
+	var templates []string
+	for _, block := range view.Blocks.BlockSet {
+		if input, ok := block.(*slack.InputBlock); ok && input.BlockID == projectTemplateBlockID {
+			for _, option := range input.Element.(*slack.SelectBlockElement).Options {
+				templates = append(templates, option.Value)
+			}
+		}
+	}
+	assert.Equal(t, domain.ProjectTemplateNames(), templates)
+
+	// Submitting the wizard creates the project for the channel and reports back in the thread
+	require.NoError(t, client.HandleInteraction(ctx, wizardSubmission(view.PrivateMetadata, "Pricing", domain.ProjectTemplateResearch, "Find the price that converts")))
+	require.NotNil(t, created)
+	assert.Equal(t, "Pricing", created.Metadata().Name)
+	assert.Equal(t, domain.ProjectTemplateResearch, created.Template().Name)
+	assert.Equal(t, "C0001", created.ChannelID())
+	assert.Equal(t, "alice", created.Creator())
+	require.Len(t, web.posts, 2)
+	assert.Equal(t, "✅ Created project *Pricing* from the research template, messages in <#C0001> are documented for it", web.posts[1].text)
+
+	// Drafts without goals are reported without creating anything
+	created = nil
+	require.NoError(t, client.HandleInteraction(ctx, wizardSubmission(view.PrivateMetadata, "Pricing", domain.ProjectTemplateResearch, " ")))
+	assert.Nil(t, created)
+	require.Len(t, web.posts, 3)
+	assert.Contains(t, web.posts[2].text, "⚠️ Failed to create project")
+}
@@ -0,0 +1,218 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// ProjectEditActionID identifies the button that opens the project edit modal
+	ProjectEditActionID = "project_edit_open"
+	// ProjectEditCallbackID identifies submissions of the project edit modal
+	ProjectEditCallbackID = "project_edit"
+
+	projectDescriptionBlockID = "project_description"
+	projectGoalsBlockID       = "project_goals"
+	projectKPIsBlockID        = "project_kpis"
+	projectInputActionID      = "value"
+)
+
+// webAPI is the part of the Slack Web API used to post messages, open modals and unfurl links
+type webAPI interface {
+	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
+	PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error)
+	OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error
+	OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
+	UpdateViewContext(ctx context.Context, view slack.ModalViewRequest, externalID, hash, viewID string) (*slack.ViewResponse, error)
+	UnfurlMessageContext(ctx context.Context, channelID, timestamp string, unfurls map[string]slack.Attachment, options ...slack.MsgOption) (string, string, string, error)
+}
+
+// projectEditMetadata travels with the button and the modal so the result is posted in the original thread
+type projectEditMetadata struct {
+	ProjectID string `json:"project_id"`
+	ChannelID string `json:"channel_id"`
+	ThreadTS  string `json:"thread_ts"`
+}
+
+// OpenProjectEditor posts a button in the thread of a message that opens a modal pre-filled with the project.
+// Slack only opens modals in response to a user action, so the modal cannot be opened from the command itself.
+func (c *Client) OpenProjectEditor(ctx context.Context, messageID string, project *domain.Project) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to open project editor: unknown message %s", messageID)
+	}
+
+	meta, err := json.Marshal(projectEditMetadata{
+		ProjectID: project.ID().String(),
+		ChannelID: data.SlackChannelID,
+		ThreadTS:  data.replyThreadTS(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open project editor: %w", err)
+	}
+
+	c.formLock.Lock()
+	c.projectForms[project.ID().String()] = project.ToDTO()
+	c.formLock.Unlock()
+
+	text := slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("✏️ Edit the description, goals and KPIs of *%s*", project.Name()), false, false)
+	button := slack.NewButtonBlockElement(ProjectEditActionID, string(meta),
+		slack.NewTextBlockObject(slack.PlainTextType, "Edit project", false, false))
+
+	_, _, err = c.web.PostMessageContext(
+		ctx,
+		data.SlackChannelID,
+		slack.MsgOptionText(fmt.Sprintf("Edit project %s", project.Name()), false),
+		slack.MsgOptionBlocks(slack.NewSectionBlock(text, nil, slack.NewAccessory(button))),
+		slack.MsgOptionTS(data.replyThreadTS()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open project editor: %w", err)
+	}
+	return nil
+}
+
+// OnProjectEdit registers the function applying submitted project edit modals
+func (c *Client) OnProjectEdit(apply func(ctx context.Context, edit *domain.ProjectEdit) (*domain.Project, error)) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.applyProjectEdit = apply
+}
+
+// openProjectEditModal opens the pre-filled modal when the edit button is clicked
+func (c *Client) openProjectEditModal(ctx context.Context, triggerID, value string) error {
+	var meta projectEditMetadata
+	if err := json.Unmarshal([]byte(value), &meta); err != nil {
+		return fmt.Errorf("invalid project edit button: %w", err)
+	}
+
+	c.formLock.Lock()
+	project, ok := c.projectForms[meta.ProjectID]
+	c.formLock.Unlock()
+	if !ok {
+		return c.postProjectEditResult(ctx, meta, "⚠️ This form has expired, run `/quill project edit` again")
+	}
+
+	if _, err := c.web.OpenViewContext(ctx, triggerID, buildProjectEditModal(project, value)); err != nil {
+		return fmt.Errorf("failed to open project edit modal: %w", err)
+	}
+	return nil
+}
+
+// submitProjectEdit applies a submitted modal and reports the result in the original thread
+func (c *Client) submitProjectEdit(ctx context.Context, interaction *slack.InteractionCallback) error {
+	var meta projectEditMetadata
+	if err := json.Unmarshal([]byte(interaction.View.PrivateMetadata), &meta); err != nil {
+		return fmt.Errorf("invalid project edit submission: %w", err)
+	}
+
+	c.formLock.Lock()
+	apply := c.applyProjectEdit
+	c.formLock.Unlock()
+	if apply == nil {
+		return fmt.Errorf("project edit submitted but no handler is registered")
+	}
+
+	project, err := c.applyProjectEditForm(ctx, apply, meta, interaction)
+	if err != nil {
+		return c.postProjectEditResult(ctx, meta, fmt.Sprintf("⚠️ Failed to update project: %s", err))
+	}
+
+	c.formLock.Lock()
+	delete(c.projectForms, meta.ProjectID)
+	c.formLock.Unlock()
+
+	return c.postProjectEditResult(ctx, meta, fmt.Sprintf("✅ Updated project *%s*", project.Name()))
+}
+
+func (c *Client) applyProjectEditForm(
+	ctx context.Context,
+	apply func(ctx context.Context, edit *domain.ProjectEdit) (*domain.Project, error),
+	meta projectEditMetadata,
+	interaction *slack.InteractionCallback,
+) (*domain.Project, error) {
+	id, err := common.NewID(meta.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID %q", meta.ProjectID)
+	}
+
+	editor, err := c.senderName(ctx, interaction.User.ID, "")
+	if err != nil {
+		log.Printf("Failed to look up Slack user %s: %v", interaction.User.ID, err)
+		editor = interaction.User.ID
+	}
+
+	values := interaction.View.State
+	edit, err := domain.NewProjectEdit(
+		id,
+		stateValue(values, projectDescriptionBlockID),
+		domain.SplitLines(stateValue(values, projectGoalsBlockID)),
+		domain.SplitLines(stateValue(values, projectKPIsBlockID)),
+		editor,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return apply(ctx, edit)
+}
+
+func (c *Client) postProjectEditResult(ctx context.Context, meta projectEditMetadata, text string) error {
+	_, _, err := c.web.PostMessageContext(ctx, meta.ChannelID, slack.MsgOptionText(text, false), slack.MsgOptionTS(meta.ThreadTS))
+	if err != nil {
+		return fmt.Errorf("failed to post project edit result: %w", err)
+	}
+	return nil
+}
+
+// buildProjectEditModal builds the modal pre-filled with the project's metadata
+func buildProjectEditModal(project domain.ProjectDTO, metadata string) slack.ModalViewRequest {
+	kpiHint := "One KPI per line, they are added to the project"
+	if len(project.KPIs) > 0 {
+		kpiHint += ". Current KPIs: " + strings.Join(project.KPIs, "; ")
+	}
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      ProjectEditCallbackID,
+		PrivateMetadata: metadata,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Edit project", false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Save", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*%s*", project.Name), false, false), nil, nil),
+			textInputBlock(projectDescriptionBlockID, "Description", "", project.Description, false),
+			textInputBlock(projectGoalsBlockID, "Business goals", "One goal per line", strings.Join(project.Goals, "\n"), false),
+			textInputBlock(projectKPIsBlockID, "New KPIs", kpiHint, "", true),
+		}},
+	}
+}
+
+func textInputBlock(blockID, label, hint, initial string, optional bool) *slack.InputBlock {
+	input := slack.NewPlainTextInputBlockElement(nil, projectInputActionID).WithMultiline(true)
+	if initial != "" {
+		input = input.WithInitialValue(initial)
+	}
+
+	var hintText *slack.TextBlockObject
+	if hint != "" {
+		hintText = slack.NewTextBlockObject(slack.PlainTextType, hint, false, false)
+	}
+
+	return slack.NewInputBlock(blockID, slack.NewTextBlockObject(slack.PlainTextType, label, false, false), hintText, input).
+		WithOptional(optional)
+}
+
+func stateValue(state *slack.ViewState, blockID string) string {
+	if state == nil {
+		return ""
+	}
+	return state.Values[blockID][projectInputActionID].Value
+}
@@ -0,0 +1,217 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type postedMessage struct {
+	channel  string
+	user     string
+	text     string
+	threadTS string
+	blocks   string
+}
+
+// stubWeb records posted messages and opened modals instead of calling Slack
+type stubWeb struct {
+	posts     []postedMessage
+	ephemeral []postedMessage
+	opened    [][]string
+	reactions []slack.ItemRef
+	emoji     []string
+	views     []slack.ModalViewRequest
+	updates   []slack.ModalViewRequest
+	unfurls   []unfurledMessage
+}
+
+type unfurledMessage struct {
+	channel string
+	ts      string
+	unfurls map[string]slack.Attachment
+}
+
+func (s *stubWeb) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("token", channelID, "", options...)
+	if err != nil {
+		return "", "", err
+	}
+	s.posts = append(s.posts, postedMessage{
+		channel:  channelID,
+		text:     values.Get("text"),
+		threadTS: values.Get("thread_ts"),
+		blocks:   values.Get("blocks"),
+	})
+	return channelID, "1700000000.000900", nil
+}
+
+func (s *stubWeb) PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("token", channelID, "", options...)
+	if err != nil {
+		return "", err
+	}
+	s.ephemeral = append(s.ephemeral, postedMessage{
+		channel:  channelID,
+		user:     userID,
+		text:     values.Get("text"),
+		threadTS: values.Get("thread_ts"),
+	})
+	return "1700000000.000901", nil
+}
+
+func (s *stubWeb) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	s.opened = append(s.opened, params.Users)
+	channel := &slack.Channel{}
+	channel.ID = "D" + strings.Join(params.Users, "")
+	return channel, false, false, nil
+}
+
+func (s *stubWeb) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	s.emoji = append(s.emoji, name)
+	s.reactions = append(s.reactions, item)
+	return nil
+}
+
+func (s *stubWeb) OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	s.views = append(s.views, view)
+	response := &slack.ViewResponse{}
+	response.ID = "V0001"
+	return response, nil
+}
+
+func (s *stubWeb) UpdateViewContext(ctx context.Context, view slack.ModalViewRequest, externalID, hash, viewID string) (*slack.ViewResponse, error) {
+	s.updates = append(s.updates, view)
+	return &slack.ViewResponse{}, nil
+}
+
+func (s *stubWeb) UnfurlMessageContext(ctx context.Context, channelID, timestamp string, unfurls map[string]slack.Attachment, options ...slack.MsgOption) (string, string, string, error) {
+	s.unfurls = append(s.unfurls, unfurledMessage{channel: channelID, ts: timestamp, unfurls: unfurls})
+	return channelID, timestamp, "", nil
+}
+
+func submission(meta string, description, goals, kpis string) *slack.InteractionCallback {
+	value := func(v string) map[string]slack.BlockAction {
+		return map[string]slack.BlockAction{projectInputActionID: {Value: v}}
+	}
+
+	return &slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		User: slack.User{ID: "U0001"},
+		View: slack.View{
+			CallbackID:      ProjectEditCallbackID,
+			PrivateMetadata: meta,
+			State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+				projectDescriptionBlockID: value(description),
+				projectGoalsBlockID:       value(goals),
+				projectKPIsBlockID:        value(kpis),
+			}},
+		},
+	}
+}
+
+func TestClient_ProjectEditor(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	project := domain.MustNewProject("Billing", "Billing rewrite", []string{"Ship v2", "Cut costs"})
+	require.NoError(t, project.AddKPI("Churn below 2%"))
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackMessageTS: "1700000000.000100"})
+
+	var applied *domain.ProjectEdit
+	client.OnProjectEdit(func(ctx context.Context, edit *domain.ProjectEdit) (*domain.Project, error) {
+		applied = edit
+		return project, nil
+	})
+
+	// The command posts a button in the thread of the command message
+	require.NoError(t, client.OpenProjectEditor(ctx, "MSG1", project))
+	require.Len(t, web.posts, 1)
+	assert.Equal(t, "C0001", web.posts[0].channel)
+	assert.Equal(t, "1700000000.000100", web.posts[0].threadTS)
+	assert.Contains(t, web.posts[0].blocks, ProjectEditActionID)
+
+	var meta projectEditMetadata
+	var blocks []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(web.posts[0].blocks), &blocks))
+	buttonValue := blocks[0]["accessory"].(map[string]interface{})["value"].(string)
+	require.NoError(t, json.Unmarshal([]byte(buttonValue), &meta))
+	assert.Equal(t, project.ID().String(), meta.ProjectID)
+
+	// Clicking the button opens the pre-filled modal
+	require.NoError(t, client.HandleInteraction(ctx, &slack.InteractionCallback{
+		Type:      slack.InteractionTypeBlockActions,
+		TriggerID: "trigger-1",
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: ProjectEditActionID, Value: buttonValue},
+		}},
+	}))
+	require.Len(t, web.views, 1)
+	view := web.views[0]
+	assert.Equal(t, ProjectEditCallbackID, view.CallbackID)
+	inputs := map[string]*slack.InputBlock{}
+	for _, block := range view.Blocks.BlockSet {
+		if input, ok := block.(*slack.InputBlock); ok {
+			inputs[input.BlockID] = input
+		}
+	}
+	assert.Equal(t, "Billing rewrite", inputs[projectDescriptionBlockID].Element.(*slack.PlainTextInputBlockElement).InitialValue)
+	assert.Equal(t, "Ship v2\nCut costs", inputs[projectGoalsBlockID].Element.(*slack.PlainTextInputBlockElement).InitialValue)
+	assert.Contains(t, inputs[projectKPIsBlockID].Hint.Text, "Churn below 2%")
+
+	// Submitting the modal applies the edit and reports back in the thread
+	require.NoError(t, client.HandleInteraction(ctx, submission(view.PrivateMetadata, "New description", "- Ship v3\n\n* Cut costs", "NPS above 40")))
+	require.NotNil(t, applied)
+	assert.Equal(t, project.ID(), applied.ProjectID())
+	assert.Equal(t, "New description", applied.Description())
+	assert.Equal(t, []string{"Ship v3", "Cut costs"}, applied.Goals())
+	assert.Equal(t, []string{"NPS above 40"}, applied.KPIs())
+	assert.Equal(t, "alice", applied.Editor())
+	require.Len(t, web.posts, 2)
+	assert.Contains(t, web.posts[1].text, "Updated project *Billing*")
+	assert.Equal(t, "1700000000.000100", web.posts[1].threadTS)
+}
+
+func TestClient_ProjectEditorErrors(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	project := domain.MustNewProject("Billing", "Billing rewrite", []string{"Ship v2"})
+	meta, err := json.Marshal(projectEditMetadata{ProjectID: project.ID().String(), ChannelID: "C0001", ThreadTS: "1700000000.000100"})
+	require.NoError(t, err)
+
+	t.Run("unknown message", func(t *testing.T) {
+		assert.Error(t, client.OpenProjectEditor(ctx, "MISSING", project))
+	})
+
+	t.Run("expired form", func(t *testing.T) {
+		require.NoError(t, client.openProjectEditModal(ctx, "trigger-1", string(meta)))
+		assert.Empty(t, web.views)
+		assert.Contains(t, web.posts[len(web.posts)-1].text, "expired")
+	})
+
+	t.Run("rejected edit is reported", func(t *testing.T) {
+		client.OnProjectEdit(func(ctx context.Context, edit *domain.ProjectEdit) (*domain.Project, error) {
+			return nil, errors.New("project is archived")
+		})
+
+		require.NoError(t, client.HandleInteraction(ctx, submission(string(meta), "desc", "Goal", "")))
+		assert.Contains(t, web.posts[len(web.posts)-1].text, "project is archived")
+	})
+
+	t.Run("goals are required", func(t *testing.T) {
+		require.NoError(t, client.HandleInteraction(ctx, submission(string(meta), "desc", "  \n", "")))
+		assert.Contains(t, web.posts[len(web.posts)-1].text, domain.ErrInvalidProjectGoals.Error())
+	})
+}
@@ -0,0 +1,138 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// DetailsShortcutCallbackID identifies the message shortcut showing what Quill captured from a message
+const DetailsShortcutCallbackID = "quill_details"
+
+// AddReaction reacts to a message received from Slack with an emoji, named without colons
+func (c *Client) AddReaction(ctx context.Context, messageID, emoji string) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to add reaction: unknown message %s", messageID)
+	}
+
+	if err := c.web.AddReactionContext(ctx, emoji, slack.NewRefToMessage(data.SlackChannelID, data.SlackMessageTS)); err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return nil
+}
+
+// OnDetailsRequest registers the function returning the details shown by the message shortcut
+func (c *Client) OnDetailsRequest(details func(ctx context.Context, messageID string) (string, error)) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.messageDetails = details
+}
+
+// showMessageDetails answers the details shortcut with a reply only the person who used it can see
+func (c *Client) showMessageDetails(ctx context.Context, interaction *slack.InteractionCallback) error {
+	c.formLock.Lock()
+	details := c.messageDetails
+	c.formLock.Unlock()
+	if details == nil {
+		return fmt.Errorf("details shortcut used but no handler is registered")
+	}
+
+	channelID := interaction.Channel.ID
+	messageTS := interaction.Message.Timestamp
+	threadTS := interaction.Message.ThreadTimestamp
+	if threadTS == "" {
+		threadTS = messageTS
+	}
+
+	text := "ℹ️ Quill has not captured anything from this message"
+	if messageID, ok := c.findMessage(channelID, messageTS); ok {
+		reply, err := details(ctx, messageID)
+		switch {
+		case err == nil:
+			text = reply
+		case !errors.Is(err, ports.ErrNotFound):
+			return fmt.Errorf("failed to find message details: %w", err)
+		}
+	}
+
+	_, err := c.web.PostEphemeralContext(
+		ctx,
+		channelID,
+		interaction.User.ID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionTS(threadTS),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to show message details: %w", err)
+	}
+	return nil
+}
+
+// OnOptOut registers the function stopping the capture of the thread of a message people react to with
+// domain.NoDocReaction
+func (c *Client) OnOptOut(optOut func(ctx context.Context, messageID, actor string) error) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.optOut = optOut
+}
+
+// processReactionAddedEvent stops capturing the thread of a message someone reacted to with
+// domain.NoDocReaction, and tells them with a reply only they can see
+func (c *Client) processReactionAddedEvent(ctx context.Context, ev *slackevents.ReactionAddedEvent) {
+	if ev.Reaction != domain.NoDocReaction || ev.Item.Type != "message" {
+		return
+	}
+	if err := c.optOutThread(ctx, ev.User, ev.Item.Channel, ev.Item.Timestamp); err != nil {
+		log.Printf("Failed to stop capturing the thread of %s in %s: %v", ev.Item.Timestamp, ev.Item.Channel, err)
+	}
+}
+
+func (c *Client) optOutThread(ctx context.Context, userID, channelID, messageTS string) error {
+	c.formLock.Lock()
+	optOut := c.optOut
+	c.formLock.Unlock()
+	if optOut == nil {
+		return nil
+	}
+
+	messageID, ok := c.findMessage(channelID, messageTS)
+	if !ok {
+		return nil
+	}
+	data, _ := c.lookupMessage(messageID)
+
+	actor, err := c.senderName(ctx, userID, "")
+	if err != nil {
+		return fmt.Errorf("failed to look up user: %w", err)
+	}
+	text := "🙈 Quill stops capturing this thread"
+	if err := optOut(ctx, messageID, actor); err != nil {
+		text = fmt.Sprintf("⚠️ Failed to stop capturing this thread: %s", err)
+	}
+
+	_, err = c.web.PostEphemeralContext(ctx, channelID, userID, slack.MsgOptionText(text, false), slack.MsgOptionTS(data.replyThreadTS()))
+	if err != nil {
+		return fmt.Errorf("failed to confirm opt-out: %w", err)
+	}
+	return nil
+}
+
+// findMessage returns the ID of the message posted at a timestamp in a channel
+func (c *Client) findMessage(channelID, messageTS string) (string, bool) {
+	c.threadLock.RLock()
+	defer c.threadLock.RUnlock()
+
+	for id, data := range c.messages {
+		if data.SlackChannelID == channelID && data.SlackMessageTS == messageTS {
+			return id, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,91 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func detailsShortcut(channelID, messageTS, threadTS string) *slack.InteractionCallback {
+	interaction := &slack.InteractionCallback{
+		Type:       slack.InteractionTypeMessageAction,
+		CallbackID: DetailsShortcutCallbackID,
+		User:       slack.User{ID: "U0002"},
+	}
+	interaction.Channel.ID = channelID
+	interaction.Message.Timestamp = messageTS
+	interaction.Message.ThreadTimestamp = threadTS
+	return interaction
+}
+
+func TestAddReaction(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackThreadTS: "1700000000.000050", SlackMessageTS: "1700000000.000100"})
+
+	require.NoError(t, client.AddReaction(context.Background(), "MSG1", "memo"))
+	assert.Equal(t, []string{"memo"}, web.emoji)
+	assert.Equal(t, []slack.ItemRef{slack.NewRefToMessage("C0001", "1700000000.000100")}, web.reactions)
+
+	assert.Error(t, client.AddReaction(context.Background(), "MSG2", "memo"))
+}
+
+func TestDetailsShortcut(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackMessageTS: "1700000000.000100", SlackUserID: "U0001"})
+	client.OnDetailsRequest(func(ctx context.Context, messageID string) (string, error) {
+		if messageID == "MSG1" {
+			return "📝 Captured idea", nil
+		}
+		return "", fmt.Errorf("no details for message %s: %w", messageID, ports.ErrNotFound)
+	})
+
+	require.NoError(t, client.HandleInteraction(context.Background(), detailsShortcut("C0001", "1700000000.000100", "")))
+	require.NoError(t, client.HandleInteraction(context.Background(), detailsShortcut("C0001", "1700000000.000200", "1700000000.000100")))
+
+	require.Len(t, web.ephemeral, 2)
+	assert.Equal(t, postedMessage{channel: "C0001", user: "U0002", text: "📝 Captured idea", threadTS: "1700000000.000100"}, web.ephemeral[0])
+	assert.Equal(t, "ℹ️ Quill has not captured anything from this message", web.ephemeral[1].text)
+	assert.Equal(t, "1700000000.000100", web.ephemeral[1].threadTS)
+}
+
+func TestReactionOptOut(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackThreadTS: "1700000000.000050", SlackMessageTS: "1700000000.000100", SlackUserID: "U0001"})
+	var optedOut []string
+	client.OnOptOut(func(ctx context.Context, messageID, actor string) error {
+		optedOut = append(optedOut, messageID+" by "+actor)
+		return nil
+	})
+
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "reaction_added.json"))
+
+	assert.Equal(t, []string{"MSG1 by bob"}, optedOut)
+	require.Len(t, web.ephemeral, 1)
+	assert.Equal(t, postedMessage{channel: "C0001", user: "U0002", text: "🙈 Quill stops capturing this thread", threadTS: "1700000000.000050"}, web.ephemeral[0])
+
+	// Other reactions and unknown messages are left alone
+	event := loadEvent(t, "reaction_added.json")
+	event.InnerEvent.Data.(*slackevents.ReactionAddedEvent).Reaction = "tada"
+	client.handleEventsAPIEvent(context.Background(), event)
+	event = loadEvent(t, "reaction_added.json")
+	event.InnerEvent.Data.(*slackevents.ReactionAddedEvent).Item.Timestamp = "1700000000.000200"
+	client.handleEventsAPIEvent(context.Background(), event)
+
+	assert.Len(t, optedOut, 1)
+	assert.Len(t, web.ephemeral, 1)
+}
@@ -0,0 +1,69 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slack-go/slack"
+)
+
+// ReplyEphemeral replies in the thread of a message with a message only its author can see
+func (c *Client) ReplyEphemeral(ctx context.Context, messageID, content string) error {
+	data, err := c.authoredMessage(messageID)
+	if err != nil {
+		return fmt.Errorf("failed to reply to message: %w", err)
+	}
+
+	_, err = c.web.PostEphemeralContext(
+		ctx,
+		data.SlackChannelID,
+		data.SlackUserID,
+		slack.MsgOptionText(content, false),
+		slack.MsgOptionTS(data.replyThreadTS()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reply to message: %w", err)
+	}
+	return nil
+}
+
+// ReplyDirect sends the reply to the author of a message in a direct message.
+// The reply names the channel of the message, as it is read outside of it.
+func (c *Client) ReplyDirect(ctx context.Context, messageID, content string) error {
+	data, err := c.authoredMessage(messageID)
+	if err != nil {
+		return fmt.Errorf("failed to send direct message: %w", err)
+	}
+
+	_, err = c.SendDirect(ctx, data.SlackUserID, fmt.Sprintf("In <#%s>: %s", data.SlackChannelID, content))
+	return err
+}
+
+// SendDirect sends a direct message to a Slack user and returns the ID of the conversation's channel
+func (c *Client) SendDirect(ctx context.Context, userID, content string) (string, error) {
+	channel, _, _, err := c.web.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+		Users:    []string{userID},
+		ReturnIM: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open direct message: %w", err)
+	}
+
+	_, _, err = c.web.PostMessageContext(ctx, channel.ID, slack.MsgOptionText(content, false))
+	if err != nil {
+		return "", fmt.Errorf("failed to send direct message: %w", err)
+	}
+	return channel.ID, nil
+}
+
+// authoredMessage returns the Slack location of a message posted by a person
+func (c *Client) authoredMessage(messageID string) (MessageData, error) {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return MessageData{}, fmt.Errorf("unknown message %s", messageID)
+	}
+	if data.SlackUserID == "" {
+		return MessageData{}, fmt.Errorf("message %s has no author", messageID)
+	}
+	return data, nil
+}
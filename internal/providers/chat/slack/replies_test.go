@@ -0,0 +1,66 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplyEphemeral(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackMessageTS: "1700000000.000100", SlackUserID: "U0001"})
+
+	require.NoError(t, client.ReplyEphemeral(context.Background(), "MSG1", "📝 Captured"))
+
+	require.Len(t, web.ephemeral, 1)
+	assert.Empty(t, web.posts)
+	assert.Equal(t, postedMessage{channel: "C0001", user: "U0001", text: "📝 Captured", threadTS: "1700000000.000100"}, web.ephemeral[0])
+}
+
+func TestReplyDirect(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackMessageTS: "1700000000.000100", SlackUserID: "U0001"})
+
+	require.NoError(t, client.ReplyDirect(context.Background(), "MSG1", "📝 Captured"))
+
+	assert.Equal(t, [][]string{{"U0001"}}, web.opened)
+	require.Len(t, web.posts, 1)
+	assert.Equal(t, "DU0001", web.posts[0].channel)
+	assert.Equal(t, "In <#C0001>: 📝 Captured", web.posts[0].text)
+	assert.Empty(t, web.posts[0].threadTS)
+}
+
+func TestSendDirect(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	channelID, err := client.SendDirect(context.Background(), "U0002", "👋 Time for the standup!")
+	require.NoError(t, err)
+
+	assert.Equal(t, "DU0002", channelID)
+	assert.Equal(t, [][]string{{"U0002"}}, web.opened)
+	require.Len(t, web.posts, 1)
+	assert.Equal(t, postedMessage{channel: "DU0002", text: "👋 Time for the standup!"}, web.posts[0])
+}
+
+func TestPrivateReplies_RequireAuthor(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	client.rememberMessage("MSG1", MessageData{SlackChannelID: "C0001", SlackMessageTS: "1700000000.000100"})
+
+	assert.Error(t, client.ReplyEphemeral(context.Background(), "MSG1", "📝 Captured"))
+	assert.Error(t, client.ReplyDirect(context.Background(), "MSG2", "📝 Captured"))
+	assert.Empty(t, web.ephemeral)
+	assert.Empty(t, web.posts)
+}
@@ -0,0 +1,114 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+// reviewActionPrefix starts the action ID of every review button
+const reviewActionPrefix = "review_"
+
+// reviewVerdict is a button offered for reviewing a stale document
+type reviewVerdict struct {
+	Value string
+	Text  string
+	Style slack.Style
+}
+
+// reviewVerdicts are the buttons offered for reviewing a stale document
+var reviewVerdicts = []reviewVerdict{
+	{Value: domain.ReviewReconfirm.String(), Text: "Reconfirm", Style: slack.StylePrimary},
+	{Value: domain.ReviewSupersede.String(), Text: "Supersede", Style: slack.StyleDanger},
+}
+
+// reviewChoice travels with each review button so the click can be applied to the document
+type reviewChoice struct {
+	Path    string `json:"path"`
+	Verdict string `json:"verdict"`
+}
+
+// RequestReview posts the content to a channel with buttons for reconfirming or superseding the document
+func (c *Client) RequestReview(ctx context.Context, channelID, content, path string) error {
+	blocks, err := GetReviewBlocks(content, path)
+	if err != nil {
+		return fmt.Errorf("failed to request review: %w", err)
+	}
+
+	_, _, err = c.web.PostMessageContext(
+		ctx,
+		channelID,
+		slack.MsgOptionText(content, false),
+		slack.MsgOptionBlocks(blocks...),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to request review: %w", err)
+	}
+	return nil
+}
+
+// OnReview registers the function applying clicked review buttons
+func (c *Client) OnReview(apply func(ctx context.Context, review *domain.DocumentReview) error) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.review = apply
+}
+
+// applyReviewChoice records the clicked verdict and reports the result in the thread of the reminder
+func (c *Client) applyReviewChoice(ctx context.Context, interaction *slack.InteractionCallback, value string) error {
+	var choice reviewChoice
+	if err := json.Unmarshal([]byte(value), &choice); err != nil {
+		return fmt.Errorf("invalid review button: %w", err)
+	}
+
+	c.formLock.Lock()
+	apply := c.review
+	c.formLock.Unlock()
+	if apply == nil {
+		return fmt.Errorf("review requested but no handler is registered")
+	}
+
+	threadTS := interaction.Message.ThreadTimestamp
+	if threadTS == "" {
+		threadTS = interaction.Message.Timestamp
+	}
+
+	text, err := c.reviewChoice(ctx, apply, choice, interaction.User.ID)
+	if err != nil {
+		text = fmt.Sprintf("⚠️ Failed to review: %s", err)
+	}
+
+	if _, _, err := c.web.PostMessageContext(ctx, interaction.Channel.ID, slack.MsgOptionText(text, false), slack.MsgOptionTS(threadTS)); err != nil {
+		return fmt.Errorf("failed to post review result: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) reviewChoice(
+	ctx context.Context,
+	apply func(ctx context.Context, review *domain.DocumentReview) error,
+	choice reviewChoice,
+	userID string,
+) (string, error) {
+	review, err := domain.NewDocumentReview(choice.Path, domain.ReviewVerdict(choice.Verdict), userID)
+	if err != nil {
+		return "", err
+	}
+	if err := apply(ctx, review); err != nil {
+		return "", err
+	}
+
+	if review.Verdict() == domain.ReviewSupersede {
+		return fmt.Sprintf("🗄️ <@%s> marked `%s` as superseded", userID, choice.Path), nil
+	}
+	return fmt.Sprintf("✅ <@%s> reconfirmed `%s`", userID, choice.Path), nil
+}
+
+// isReviewAction checks if a block action is a click on a review button
+func isReviewAction(actionID string) bool {
+	return strings.HasPrefix(actionID, reviewActionPrefix)
+}
@@ -0,0 +1,76 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reviewClick(t *testing.T, choice reviewChoice) *slack.InteractionCallback {
+	value, err := json.Marshal(choice)
+	require.NoError(t, err)
+
+	interaction := &slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		User: slack.User{ID: "U0002"},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: reviewActionPrefix + choice.Verdict, Value: string(value)},
+		}},
+	}
+	interaction.Channel.ID = "C0001"
+	interaction.Message.Timestamp = "1700000000.000900"
+	return interaction
+}
+
+func TestRequestReview(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	require.NoError(t, client.RequestReview(context.Background(), "C0001", "🕰️ Does it still hold?", "docs/development/queue.md"))
+
+	require.Len(t, web.posts, 1)
+	post := web.posts[0]
+	assert.Empty(t, post.threadTS)
+	assert.Equal(t, "🕰️ Does it still hold?", post.text)
+	assert.Contains(t, post.blocks, `"action_id":"review_reconfirm"`)
+	assert.Contains(t, post.blocks, `"action_id":"review_supersede"`)
+	assert.Contains(t, post.blocks, `docs/development/queue.md`)
+}
+
+func TestReviewClick(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	var applied *domain.DocumentReview
+	client.OnReview(func(ctx context.Context, review *domain.DocumentReview) error {
+		if review.Path() == "docs/development/missing.md" {
+			return errors.New("document not found")
+		}
+		applied = review
+		return nil
+	})
+
+	choice := reviewChoice{Path: "docs/development/queue.md", Verdict: "supersede"}
+	require.NoError(t, client.HandleInteraction(context.Background(), reviewClick(t, choice)))
+
+	require.NotNil(t, applied)
+	assert.Equal(t, "docs/development/queue.md", applied.Path())
+	assert.Equal(t, domain.ReviewSupersede, applied.Verdict())
+	assert.Equal(t, "U0002", applied.Actor())
+
+	choice = reviewChoice{Path: "docs/development/missing.md", Verdict: "reconfirm"}
+	require.NoError(t, client.HandleInteraction(context.Background(), reviewClick(t, choice)))
+
+	require.Len(t, web.posts, 2)
+	assert.Equal(t, "🗄️ <@U0002> marked `docs/development/queue.md` as superseded", web.posts[0].text)
+	assert.Equal(t, "1700000000.000900", web.posts[0].threadTS)
+	assert.Equal(t, "⚠️ Failed to review: document not found", web.posts[1].text)
+}
@@ -0,0 +1,49 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidRoute = errors.New("invalid message route")
+)
+
+// Keys of the route describing where a message was posted in Slack
+const (
+	routeChannel  = "slack_channel"
+	routeThreadTS = "slack_thread_ts"
+	routeTS       = "slack_ts"
+	routeUser     = "slack_user"
+)
+
+// MessageRoute returns where in Slack a received message was posted, so another instance can reply to it
+func (c *Client) MessageRoute(messageID string) (map[string]string, bool) {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return nil, false
+	}
+	return map[string]string{
+		routeChannel:  data.SlackChannelID,
+		routeThreadTS: data.SlackThreadTS,
+		routeTS:       data.SlackMessageTS,
+		routeUser:     data.SlackUserID,
+	}, true
+}
+
+// RestoreMessageRoute makes a message received by another instance known, replies to it land in its thread
+func (c *Client) RestoreMessageRoute(messageID string, route map[string]string) error {
+	data := MessageData{
+		SlackChannelID: route[routeChannel],
+		SlackThreadTS:  route[routeThreadTS],
+		SlackMessageTS: route[routeTS],
+		SlackUserID:    route[routeUser],
+	}
+	if strings.TrimSpace(data.SlackChannelID) == "" || strings.TrimSpace(data.SlackMessageTS) == "" {
+		return fmt.Errorf("%w: message %s has no Slack channel or timestamp", ErrInvalidRoute, messageID)
+	}
+
+	c.rememberMessage(messageID, data)
+	return nil
+}
@@ -0,0 +1,33 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_MessageRoute(t *testing.T) {
+	ingestion := newTestClient(t)
+	worker := newTestClient(t)
+	ingestion.rememberMessage("01HMSG", MessageData{
+		SlackChannelID: "C0001",
+		SlackThreadTS:  "1700000000.000100",
+		SlackMessageTS: "1700000000.000200",
+		SlackUserID:    "U0001",
+	})
+
+	route, ok := ingestion.MessageRoute("01HMSG")
+	require.True(t, ok)
+	require.NoError(t, worker.RestoreMessageRoute("01HMSG", route))
+
+	data, ok := worker.lookupMessage("01HMSG")
+	require.True(t, ok)
+	assert.Equal(t, "C0001", data.SlackChannelID)
+	assert.Equal(t, "1700000000.000100", data.replyThreadTS())
+	assert.Equal(t, "U0001", data.SlackUserID)
+
+	_, ok = worker.MessageRoute("01HOTHER")
+	assert.False(t, ok)
+	assert.ErrorIs(t, worker.RestoreMessageRoute("01HOTHER", map[string]string{routeChannel: "C0001"}), ErrInvalidRoute)
+}
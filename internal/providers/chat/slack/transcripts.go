@@ -0,0 +1,129 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// DefaultMaxRecordingBytes is the largest recording transcribed when no limit is configured,
+// the upload limit of hosted Whisper
+const DefaultMaxRecordingBytes = 25 << 20
+
+// fileLinkPattern matches links to files shared in Slack, like huddle recordings, capturing the file ID
+var fileLinkPattern = regexp.MustCompile(`https://[A-Za-z0-9.-]+\.slack\.com/files/[A-Z0-9]+/(F[A-Z0-9]+)`)
+
+// fileAPI reads files shared in Slack
+type fileAPI interface {
+	GetFileInfoContext(ctx context.Context, fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error)
+	GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error
+}
+
+// recordings are the audio and video files attached to a message or linked from its text
+type recordings struct {
+	files   []slack.File
+	linkIDs []string
+}
+
+// findRecordings collects the recordings of a message. Linked files are only known by ID until looked up.
+func findRecordings(files []slack.File, text string) recordings {
+	var found recordings
+	attached := make(map[string]bool)
+	for _, file := range files {
+		if isRecording(file) {
+			found.files = append(found.files, file)
+			attached[file.ID] = true
+		}
+	}
+	for _, match := range fileLinkPattern.FindAllStringSubmatch(text, -1) {
+		if !attached[match[1]] {
+			attached[match[1]] = true
+			found.linkIDs = append(found.linkIDs, match[1])
+		}
+	}
+	return found
+}
+
+func (r recordings) empty() bool {
+	return len(r.files) == 0 && len(r.linkIDs) == 0
+}
+
+// isRecording reports whether a file is a voice clip, a video clip or a huddle recording
+func isRecording(file slack.File) bool {
+	return strings.HasPrefix(file.Mimetype, "audio/") || strings.HasPrefix(file.Mimetype, "video/")
+}
+
+// withTranscripts appends the transcripts of the recordings to the message text. Recordings that cannot
+// be transcribed are logged and left out, so the message is still processed with what was written.
+func (c *Client) withTranscripts(ctx context.Context, text string, found recordings) string {
+	files := found.files
+	for _, id := range found.linkIDs {
+		file, _, _, err := c.files.GetFileInfoContext(ctx, id, 0, 0)
+		if err != nil {
+			log.Printf("Error looking up linked Slack file %s: %v", id, err)
+			continue
+		}
+		if isRecording(*file) {
+			files = append(files, *file)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(text))
+	for _, file := range files {
+		transcript, err := c.transcribe(ctx, file)
+		if err != nil {
+			log.Printf("Error transcribing Slack file %s: %v", file.ID, err)
+			continue
+		}
+		if transcript == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(fmt.Sprintf("Transcript of %s:\n%s", recordingLabel(file), transcript))
+	}
+	return b.String()
+}
+
+// transcribe downloads a recording and returns its transcript
+func (c *Client) transcribe(ctx context.Context, file slack.File) (string, error) {
+	maxBytes := c.config.MaxRecordingBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxRecordingBytes
+	}
+	if file.Size > maxBytes {
+		return "", fmt.Errorf("recording of %d bytes is over the limit of %d", file.Size, maxBytes)
+	}
+
+	downloadURL := file.URLPrivateDownload
+	if downloadURL == "" {
+		downloadURL = file.URLPrivate
+	}
+
+	var audio bytes.Buffer
+	if err := c.files.GetFileContext(ctx, downloadURL, &audio); err != nil {
+		return "", fmt.Errorf("failed to download recording: %w", err)
+	}
+
+	transcript, err := c.config.Transcriber.Transcribe(ctx, file.Name, &audio)
+	if err != nil {
+		return "", fmt.Errorf("failed to transcribe recording: %w", err)
+	}
+	return strings.TrimSpace(transcript), nil
+}
+
+// recordingLabel names a recording in the transcript heading
+func recordingLabel(file slack.File) string {
+	if strings.TrimSpace(file.Title) != "" {
+		return file.Title
+	}
+	return file.Name
+}
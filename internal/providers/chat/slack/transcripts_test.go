@@ -0,0 +1,151 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFiles serves file metadata and contents keyed by file ID and download URL
+type stubFiles struct {
+	info      map[string]*slack.File
+	contents  map[string]string
+	downloads []string
+}
+
+func (s *stubFiles) GetFileInfoContext(ctx context.Context, fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error) {
+	file, ok := s.info[fileID]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("file_not_found")
+	}
+	return file, nil, nil, nil
+}
+
+func (s *stubFiles) GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error {
+	s.downloads = append(s.downloads, downloadURL)
+	content, ok := s.contents[downloadURL]
+	if !ok {
+		return fmt.Errorf("not found")
+	}
+	_, err := io.WriteString(writer, content)
+	return err
+}
+
+// stubTranscriber returns the transcript of each recording by its content
+type stubTranscriber map[string]string
+
+func (s stubTranscriber) Transcribe(ctx context.Context, name string, audio io.Reader) (string, error) {
+	content, _ := io.ReadAll(audio)
+	transcript, ok := s[string(content)]
+	if !ok {
+		return "", fmt.Errorf("unsupported format %s", name)
+	}
+	return transcript, nil
+}
+
+func waitForMessage(t *testing.T, client *Client) *domain.Message {
+	t.Helper()
+
+	select {
+	case msg := <-client.messageCh:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message was published")
+		return nil
+	}
+}
+
+func TestProcessMessageEvent_TranscribesVoiceClip(t *testing.T) {
+	client := newTestClient(t)
+	files := &stubFiles{contents: map[string]string{
+		"https://files.slack.com/files-pri/T0001-F0001/download/audio_message.m4a": "clip",
+	}}
+	client.files = files
+	client.config.Transcriber = stubTranscriber{"clip": "We decided to drop the legacy exporter."}
+
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "message_voice_clip.json"))
+
+	msg := waitForMessage(t, client)
+	assert.Equal(t, "Transcript of Voice clip:\nWe decided to drop the legacy exporter.", msg.Content().Text())
+	assert.Equal(t, "C0001", msg.ChannelID())
+	assert.Len(t, files.downloads, 1, "only the audio file is downloaded")
+}
+
+func TestWithTranscripts(t *testing.T) {
+	recording := &slack.File{
+		ID:                 "F0009",
+		Name:               "huddle_recording.mp4",
+		Mimetype:           "video/mp4",
+		Size:               2048,
+		URLPrivateDownload: "https://files.slack.com/files-pri/T0001-F0009/download/huddle_recording.mp4",
+	}
+
+	tests := []struct {
+		name         string
+		text         string
+		files        []slack.File
+		maxBytes     int
+		want         string
+		wantDownload bool
+	}{
+		{
+			name:         "huddle recording link",
+			text:         "Notes from the sync <https://acme.slack.com/files/U0001/F0009/huddle_recording.mp4>",
+			want:         "Notes from the sync <https://acme.slack.com/files/U0001/F0009/huddle_recording.mp4>\n\nTranscript of huddle_recording.mp4:\nLet's ship on Friday.",
+			wantDownload: true,
+		},
+		{
+			name:         "recording over the size limit",
+			text:         "Notes <https://acme.slack.com/files/U0001/F0009/huddle_recording.mp4>",
+			maxBytes:     1024,
+			want:         "Notes <https://acme.slack.com/files/U0001/F0009/huddle_recording.mp4>",
+			wantDownload: false,
+		},
+		{
+			name:         "unknown linked file",
+			text:         "See https://acme.slack.com/files/U0001/F0404/missing.mp3",
+			want:         "See https://acme.slack.com/files/U0001/F0404/missing.mp3",
+			wantDownload: false,
+		},
+		{
+			name:         "failed transcription",
+			files:        []slack.File{{ID: "F0010", Name: "clip.webm", Mimetype: "audio/webm", URLPrivate: "https://files.slack.com/clip.webm"}},
+			want:         "",
+			wantDownload: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t)
+			files := &stubFiles{
+				info: map[string]*slack.File{"F0009": recording},
+				contents: map[string]string{
+					recording.URLPrivateDownload:        "huddle",
+					"https://files.slack.com/clip.webm": "webm",
+				},
+			}
+			client.files = files
+			client.config.Transcriber = stubTranscriber{"huddle": "Let's ship on Friday."}
+			client.config.MaxRecordingBytes = tt.maxBytes
+
+			found := findRecordings(tt.files, tt.text)
+			require.False(t, found.empty())
+
+			assert.Equal(t, tt.want, client.withTranscripts(context.Background(), tt.text, found))
+			assert.Equal(t, tt.wantDownload, len(files.downloads) > 0)
+		})
+	}
+}
+
+func TestFindRecordings_IgnoresOtherFiles(t *testing.T) {
+	found := findRecordings([]slack.File{{ID: "F0002", Mimetype: "image/png"}}, "see https://example.com/files/U1/F1")
+	assert.True(t, found.empty())
+}
@@ -0,0 +1,171 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// triageActionPrefix starts the action ID of every triage button
+	triageActionPrefix = "triage_"
+
+	// maxTriageItems bounds the messages listed with buttons, as a Slack message holds at most 50 blocks
+	maxTriageItems = 15
+)
+
+// triageChoice travels with each triage button so the click can be applied to the message
+type triageChoice struct {
+	MessageID string `json:"message_id"`
+	Choice    string `json:"choice"`
+}
+
+// RequestTriage posts the messages waiting for triage in a channel, each with a button per category and one
+// for dismissing it
+func (c *Client) RequestTriage(ctx context.Context, channelID string, items []*domain.TriageItem) error {
+	blocks, err := GetTriageBlocks(items)
+	if err != nil {
+		return fmt.Errorf("failed to request triage: %w", err)
+	}
+
+	_, _, err = c.web.PostMessageContext(
+		ctx,
+		channelID,
+		slack.MsgOptionText(domain.RenderTriageDigest(items), false),
+		slack.MsgOptionBlocks(blocks...),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to request triage: %w", err)
+	}
+	return nil
+}
+
+// OnTriage registers the function applying clicked triage buttons
+func (c *Client) OnTriage(apply func(ctx context.Context, decision *domain.TriageDecision) error) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.triage = apply
+}
+
+// applyTriageChoice applies the clicked choice and reports the result in the thread of the digest
+func (c *Client) applyTriageChoice(ctx context.Context, interaction *slack.InteractionCallback, value string) error {
+	var choice triageChoice
+	if err := json.Unmarshal([]byte(value), &choice); err != nil {
+		return fmt.Errorf("invalid triage button: %w", err)
+	}
+
+	c.formLock.Lock()
+	apply := c.triage
+	c.formLock.Unlock()
+	if apply == nil {
+		return fmt.Errorf("triage requested but no handler is registered")
+	}
+
+	threadTS := interaction.Message.ThreadTimestamp
+	if threadTS == "" {
+		threadTS = interaction.Message.Timestamp
+	}
+
+	text, err := c.triageChoice(ctx, apply, choice, interaction.User.ID)
+	if err != nil {
+		text = fmt.Sprintf("⚠️ Failed to triage: %s", err)
+	}
+
+	if _, _, err := c.web.PostMessageContext(ctx, interaction.Channel.ID, slack.MsgOptionText(text, false), slack.MsgOptionTS(threadTS)); err != nil {
+		return fmt.Errorf("failed to post triage result: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) triageChoice(
+	ctx context.Context,
+	apply func(ctx context.Context, decision *domain.TriageDecision) error,
+	choice triageChoice,
+	userID string,
+) (string, error) {
+	actor, err := c.senderName(ctx, userID, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to look up user: %w", err)
+	}
+	decision, err := domain.NewTriageDecision(choice.MessageID, choice.Choice, actor)
+	if err != nil {
+		return "", err
+	}
+	if err := apply(ctx, decision); err != nil {
+		return "", err
+	}
+
+	if decision.Dismissed() {
+		return fmt.Sprintf("🗑️ <@%s> dismissed message %s", userID, choice.MessageID), nil
+	}
+	return fmt.Sprintf("✅ <@%s> filed message %s under %s", userID, choice.MessageID, decision.Category()), nil
+}
+
+// isTriageAction checks if a block action is a click on a triage button
+func isTriageAction(actionID string) bool {
+	return strings.HasPrefix(actionID, triageActionPrefix)
+}
+
+// GetTriageBlocks returns the header of the triage digest followed by each message, with a button per category,
+// the one it was analysed as highlighted, and one for dismissing it
+func GetTriageBlocks(items []*domain.TriageItem) ([]slack.Block, error) {
+	header := fmt.Sprintf("🗂️ *%d %s could not be documented with confidence*", len(items), pluralMessages(len(items)))
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, header, false, false), nil, nil),
+	}
+
+	for i, item := range items {
+		if i == maxTriageItems {
+			more := fmt.Sprintf("…and %d more, they are listed again once these are triaged", len(items)-maxTriageItems)
+			blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, more, false, false)))
+			break
+		}
+		msg := item.Message()
+		id := msg.ID().String()
+
+		var buttons []slack.BlockElement
+		for _, category := range categories {
+			button, err := triageButton(id, category.Value, category.Text)
+			if err != nil {
+				return nil, err
+			}
+			if category.Value == msg.Category().String() {
+				button.Style = slack.StylePrimary
+			}
+			buttons = append(buttons, button)
+		}
+		dismiss, err := triageButton(id, domain.TriageDismiss, "Dismiss")
+		if err != nil {
+			return nil, err
+		}
+		dismiss.Style = slack.StyleDanger
+		buttons = append(buttons, dismiss)
+
+		blocks = append(blocks,
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, domain.RenderTriageItem(item), false, false), nil, nil),
+			slack.NewActionBlock("triage_"+id, buttons...),
+		)
+	}
+	return blocks, nil
+}
+
+func triageButton(messageID, choice, text string) (*slack.ButtonBlockElement, error) {
+	value, err := json.Marshal(triageChoice{MessageID: messageID, Choice: choice})
+	if err != nil {
+		return nil, err
+	}
+	return slack.NewButtonBlockElement(triageActionPrefix+choice, string(value),
+		slack.NewTextBlockObject(slack.PlainTextType, text, false, false)), nil
+}
+
+func pluralMessages(n int) string {
+	if n == 1 {
+		return "message"
+	}
+	return "messages"
+}
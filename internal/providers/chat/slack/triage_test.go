@@ -0,0 +1,104 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func triageItem(t *testing.T, text string) *domain.TriageItem {
+	t.Helper()
+
+	msg, err := domain.NewMessage(common.GenerateID(), "alice", domain.MustNewMessageContent(text), domain.MessageTypeDecision, domain.CategoryOperations, nil)
+	require.NoError(t, err)
+	msg.RecordConfidence(0.4)
+	item, err := domain.NewTriageItem(msg, time.Now())
+	require.NoError(t, err)
+	return item
+}
+
+func triageClick(t *testing.T, choice triageChoice) *slack.InteractionCallback {
+	value, err := json.Marshal(choice)
+	require.NoError(t, err)
+
+	interaction := &slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		User: slack.User{ID: "U0002"},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: triageActionPrefix + choice.Choice, Value: string(value)},
+		}},
+	}
+	interaction.Channel.ID = "C0001"
+	interaction.Message.Timestamp = "1700000000.000900"
+	return interaction
+}
+
+func TestRequestTriage(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+	item := triageItem(t, "Maybe we move the queue to Kafka")
+
+	require.NoError(t, client.RequestTriage(context.Background(), "C0001", []*domain.TriageItem{item}))
+
+	require.Len(t, web.posts, 1)
+	post := web.posts[0]
+	assert.Empty(t, post.threadTS)
+	assert.Contains(t, post.text, "🗂️ 1 message could not be documented with confidence")
+	assert.Contains(t, post.blocks, "Maybe we move the queue to Kafka")
+	assert.Contains(t, post.blocks, `"action_id":"triage_development"`)
+	assert.Contains(t, post.blocks, `"action_id":"triage_dismiss"`)
+	assert.Contains(t, post.blocks, item.Message().ID().String())
+}
+
+func TestGetTriageBlocks_BoundsTheDigest(t *testing.T) {
+	items := make([]*domain.TriageItem, maxTriageItems+2)
+	for i := range items {
+		items[i] = triageItem(t, "Maybe we move the queue to Kafka")
+	}
+
+	blocks, err := GetTriageBlocks(items)
+
+	require.NoError(t, err)
+	assert.Len(t, blocks, 1+3*maxTriageItems+1)
+	more, ok := blocks[len(blocks)-1].(*slack.ContextBlock)
+	require.True(t, ok)
+	assert.Equal(t, "…and 2 more, they are listed again once these are triaged", more.ContextElements.Elements[0].(*slack.TextBlockObject).Text)
+}
+
+func TestTriageClick(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+
+	var applied []*domain.TriageDecision
+	client.OnTriage(func(ctx context.Context, decision *domain.TriageDecision) error {
+		if decision.MessageID() == "M0404" {
+			return errors.New("triage item M0404: not found")
+		}
+		applied = append(applied, decision)
+		return nil
+	})
+
+	require.NoError(t, client.HandleInteraction(context.Background(), triageClick(t, triageChoice{MessageID: "M0001", Choice: "operations"})))
+	require.NoError(t, client.HandleInteraction(context.Background(), triageClick(t, triageChoice{MessageID: "M0002", Choice: domain.TriageDismiss})))
+	require.NoError(t, client.HandleInteraction(context.Background(), triageClick(t, triageChoice{MessageID: "M0404", Choice: "operations"})))
+
+	require.Len(t, applied, 2)
+	assert.Equal(t, domain.CategoryOperations, applied[0].Category())
+	assert.Equal(t, "bob", applied[0].Actor())
+	assert.True(t, applied[1].Dismissed())
+	require.Len(t, web.posts, 3)
+	assert.Equal(t, "✅ <@U0002> filed message M0001 under operations", web.posts[0].text)
+	assert.Equal(t, "1700000000.000900", web.posts[0].threadTS)
+	assert.Equal(t, "🗑️ <@U0002> dismissed message M0002", web.posts[1].text)
+	assert.Equal(t, "⚠️ Failed to triage: triage item M0404: not found", web.posts[2].text)
+}
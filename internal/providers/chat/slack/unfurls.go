@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// OnLinkShared registers the function finding the documents behind links posted in channels
+func (c *Client) OnLinkShared(find func(ctx context.Context, channelID, link string) (*domain.IndexedDocument, error)) {
+	c.formLock.Lock()
+	defer c.formLock.Unlock()
+	c.findLinked = find
+}
+
+// processLinkSharedEvent previews the links to documents in a posted message. Slack only sends the event
+// for the domains listed under the app's unfurl domains.
+func (c *Client) processLinkSharedEvent(ctx context.Context, ev *slackevents.LinkSharedEvent) {
+	c.formLock.Lock()
+	find := c.findLinked
+	c.formLock.Unlock()
+	if find == nil {
+		return
+	}
+
+	// Links typed in the message composer carry an ID in place of a message timestamp, and are left alone
+	if _, err := strconv.ParseFloat(ev.MessageTimeStamp, 64); err != nil {
+		return
+	}
+
+	unfurls := make(map[string]slack.Attachment)
+	for _, link := range ev.Links {
+		doc, err := find(ctx, ev.Channel, link.URL)
+		if errors.Is(err, ports.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to find the document linked at %s: %v", link.URL, err)
+			continue
+		}
+		unfurls[link.URL] = documentPreview(link.URL, doc)
+	}
+	if len(unfurls) == 0 {
+		return
+	}
+
+	if _, _, _, err := c.web.UnfurlMessageContext(ctx, ev.Channel, ev.MessageTimeStamp, unfurls); err != nil {
+		log.Printf("Failed to unfurl document links in Slack channel %s: %v", ev.Channel, err)
+	}
+}
+
+// documentPreview shows a document's title, type, category, summary and the date it was last updated.
+// The date is formatted by Slack in the reader's time zone.
+func documentPreview(link string, doc *domain.IndexedDocument) slack.Attachment {
+	return slack.Attachment{
+		Title:     doc.Title(),
+		TitleLink: link,
+		Text:      doc.Summary(),
+		Fields: []slack.AttachmentField{
+			{Title: "Type", Value: doc.Type().String(), Short: true},
+			{Title: "Category", Value: doc.Category().String(), Short: true},
+			{Title: "Last updated", Value: slackDate(doc.UpdatedAt()), Short: true},
+		},
+		Footer:     "Quill",
+		MarkdownIn: []string{"fields"},
+	}
+}
@@ -0,0 +1,87 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const adoptPostgresURL = "https://github.com/acme/docs/blob/main/docs/development/adopt-postgres.md"
+
+func findAdoptPostgres(t *testing.T) func(ctx context.Context, channelID, link string) (*domain.IndexedDocument, error) {
+	t.Helper()
+
+	doc, err := domain.NewIndexedDocument("docs/development/adopt-postgres.md", "Adopt Postgres",
+		"The team will use Postgres for billing.", domain.MessageTypeDecision, domain.CategoryDevelopment)
+	require.NoError(t, err)
+	return func(ctx context.Context, channelID, link string) (*domain.IndexedDocument, error) {
+		if channelID == "C0001" && link == adoptPostgresURL {
+			return doc, nil
+		}
+		return nil, fmt.Errorf("no document at %s: %w", link, ports.ErrNotFound)
+	}
+}
+
+func TestClient_UnfurlsDocumentLinks(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+	client.OnLinkShared(findAdoptPostgres(t))
+
+	client.handleEventsAPIEvent(context.Background(), loadEvent(t, "link_shared.json"))
+
+	require.Len(t, web.unfurls, 1)
+	unfurl := web.unfurls[0]
+	assert.Equal(t, "C0001", unfurl.channel)
+	assert.Equal(t, "1718000300.000400", unfurl.ts)
+	require.Len(t, unfurl.unfurls, 1, "links to anything but documents are left to Slack")
+	preview := unfurl.unfurls[adoptPostgresURL]
+	assert.Equal(t, "Adopt Postgres", preview.Title)
+	assert.Equal(t, adoptPostgresURL, preview.TitleLink)
+	assert.Equal(t, "The team will use Postgres for billing.", preview.Text)
+	require.Len(t, preview.Fields, 3)
+	assert.Equal(t, slack.AttachmentField{Title: "Type", Value: "decision", Short: true}, preview.Fields[0])
+	assert.Equal(t, slack.AttachmentField{Title: "Category", Value: "development", Short: true}, preview.Fields[1])
+	assert.Contains(t, preview.Fields[2].Value, "<!date^")
+}
+
+func TestClient_SkipsUnknownLinks(t *testing.T) {
+	client := newTestClient(t)
+	web := &stubWeb{}
+	client.web = web
+	client.OnLinkShared(findAdoptPostgres(t))
+
+	// Other channels may not see the document
+	client.processLinkSharedEvent(context.Background(), &slackevents.LinkSharedEvent{
+		Channel:          "C0002",
+		MessageTimeStamp: "1718000300.000400",
+		Links:            []slackevents.SharedLinks{{Domain: "github.com", URL: adoptPostgresURL}},
+	})
+	// Links in the composer are not unfurled
+	client.processLinkSharedEvent(context.Background(), &slackevents.LinkSharedEvent{
+		Channel:          "C0001",
+		MessageTimeStamp: "a7b8c9d0-1e2f-4a3b-8c4d-5e6f7a8b9c0d",
+		Links:            []slackevents.SharedLinks{{Domain: "github.com", URL: adoptPostgresURL}},
+	})
+
+	assert.Empty(t, web.unfurls)
+}
+
+func TestDocumentPreview_LastUpdated(t *testing.T) {
+	doc, err := domain.NewIndexedDocument("docs/notes.md", "Notes", "", domain.MessageTypeInformation, domain.CategoryOther)
+	require.NoError(t, err)
+
+	preview := documentPreview("https://quill.example.com/documents/docs/notes.md", doc)
+
+	updatedAt := doc.UpdatedAt()
+	assert.Equal(t, fmt.Sprintf("<!date^%d^{date_short_pretty}|%s>", updatedAt.Unix(), updatedAt.UTC().Format(time.DateOnly)), preview.Fields[2].Value)
+	assert.Empty(t, preview.Text)
+}
@@ -0,0 +1,287 @@
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid webhook configuration")
+)
+
+const (
+	// maxSeenMessages bounds the set used to drop messages posted again with the same ID
+	maxSeenMessages = 1000
+	// queueWait is how long a request waits for room in a full listener before asking for a retry
+	queueWait = 10 * time.Second
+)
+
+// Client implements the ChatAccessProvider interface for content pushed over HTTP, so CI systems, forms
+// and scripts feed the same processing pipeline as chat. Messages are posted as JSON to Handler.
+// There is nobody to read replies, so confirmations and reactions are dropped.
+type Client struct {
+	config    *Config
+	messageCh chan *domain.Message
+
+	lock      sync.Mutex
+	threadMap map[string]common.ID // Maps channel and thread key to our ThreadID
+	seen      map[string]IngestResponse
+	seenOrder []string
+}
+
+// NewClient creates a new webhook client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	return &Client{
+		config:    config,
+		messageCh: make(chan *domain.Message, 100),
+		threadMap: make(map[string]common.ID),
+		seen:      make(map[string]IngestResponse),
+	}, nil
+}
+
+// SendMessage does nothing, the sources posting messages cannot receive any
+func (c *Client) SendMessage(ctx context.Context, channelID, content string) error {
+	c.drop(channelID, content)
+	return nil
+}
+
+// ReplyToMessage does nothing, the sources posting messages cannot receive replies
+func (c *Client) ReplyToMessage(ctx context.Context, messageID, content string) error {
+	c.drop(messageID, content)
+	return nil
+}
+
+// AddReaction does nothing, posted messages cannot be reacted to
+func (c *Client) AddReaction(ctx context.Context, messageID, emoji string) error {
+	return nil
+}
+
+// ListenForMessages returns the channel of messages posted to the handler
+func (c *Client) ListenForMessages(ctx context.Context) (<-chan *domain.Message, error) {
+	return c.messageCh, nil
+}
+
+// HandleInteraction is not supported, posted messages have no interactive components
+func (c *Client) HandleInteraction(ctx context.Context, interaction interface{}) error {
+	return fmt.Errorf("unsupported interaction type: %T", interaction)
+}
+
+// Handler serves POST /ingest. Requests authenticate with a configured token as bearer token and
+// post an IngestRequest. Accepted messages are answered with 202 and an IngestResponse.
+func (c *Client) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		source, ok := c.authenticate(r.Header.Get("Authorization"))
+		if !ok {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, c.maxBodyBytes())
+		var req IngestRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		resp, err := c.ingest(r.Context(), source, req)
+		var invalid *invalidRequestError
+		switch {
+		case errors.As(err, &invalid):
+			if c.config.DebugMode {
+				log.Printf("Rejecting message from %s: %v", source, err)
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// invalidRequestError reports a message the source has to fix before posting it again
+type invalidRequestError struct {
+	err error
+}
+
+func (e *invalidRequestError) Error() string { return e.err.Error() }
+func (e *invalidRequestError) Unwrap() error { return e.err }
+
+// ingest converts a posted message into a domain message and hands it to the listener
+func (c *Client) ingest(ctx context.Context, source string, req IngestRequest) (IngestResponse, error) {
+	if err := req.validate(); err != nil {
+		return IngestResponse{}, &invalidRequestError{err: err}
+	}
+	messageContent, err := domain.NewMessageContent(req.Content)
+	if err != nil {
+		return IngestResponse{}, &invalidRequestError{err: err}
+	}
+
+	channelID := strings.TrimSpace(req.Channel)
+	if channelID == "" {
+		channelID = c.defaultChannel()
+	}
+
+	// For now, use default type and category - these will be determined later by AI analysis
+	domainMsg, err := domain.NewMessage(
+		c.threadIDFor(channelID, req.Thread),
+		senderName(req.Sender, source),
+		messageContent,
+		domain.MessageTypeInformation,
+		domain.CategoryOther,
+		nil, // no references initially
+	)
+	if err != nil {
+		return IngestResponse{}, &invalidRequestError{err: err}
+	}
+	domainMsg.SetChannelID(channelID)
+
+	resp := IngestResponse{MessageID: domainMsg.ID().String(), ThreadID: domainMsg.ThreadID().String()}
+	key := sourceKey(source, req.ID)
+	domainMsg.SetSourceTimestamp(key)
+	if key != "" {
+		if previous, ok := c.markSeen(key, resp); !ok {
+			previous.Duplicate = true
+			return previous, nil
+		}
+	}
+
+	// The request is answered with an error while the listener is full, so the source retries later
+	timer := time.NewTimer(queueWait)
+	defer timer.Stop()
+	select {
+	case c.messageCh <- domainMsg:
+		return resp, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	c.forgetSeen(key)
+	return IngestResponse{}, errors.New("message queue is full")
+}
+
+// authenticate returns the source a bearer token belongs to
+func (c *Client) authenticate(header string) (string, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return "", false
+	}
+	for known, source := range c.config.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(known)) == 1 {
+			return source, true
+		}
+	}
+	return "", false
+}
+
+func (c *Client) defaultChannel() string {
+	if channel := strings.TrimSpace(c.config.DefaultChannel); channel != "" {
+		return channel
+	}
+	return DefaultChannel
+}
+
+func (c *Client) maxBodyBytes() int64 {
+	if c.config.MaxBodyBytes > 0 {
+		return c.config.MaxBodyBytes
+	}
+	return DefaultMaxBodyBytes
+}
+
+// drop notes a message nobody can receive
+func (c *Client) drop(target, content string) {
+	if c.config.DebugMode {
+		log.Printf("Dropping message for %s, webhook sources cannot receive messages: %s", target, content)
+	}
+}
+
+// threadIDFor maps a thread key of a channel to our ThreadID, creating one for new keys.
+// Messages posted without a thread key each start their own thread.
+func (c *Client) threadIDFor(channelID, thread string) common.ID {
+	thread = strings.TrimSpace(thread)
+	if thread == "" {
+		return common.GenerateID()
+	}
+	key := channelID + ":" + thread
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if id, exists := c.threadMap[key]; exists {
+		return id
+	}
+	id := common.GenerateID()
+	c.threadMap[key] = id
+	return id
+}
+
+// markSeen records the response of a message ID, or returns the response it was first accepted with
+func (c *Client) markSeen(key string, resp IngestResponse) (IngestResponse, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if previous, ok := c.seen[key]; ok {
+		return previous, false
+	}
+	if len(c.seenOrder) >= maxSeenMessages {
+		delete(c.seen, c.seenOrder[0])
+		c.seenOrder = c.seenOrder[1:]
+	}
+	c.seen[key] = resp
+	c.seenOrder = append(c.seenOrder, key)
+	return resp, true
+}
+
+// forgetSeen drops a message ID that could not be delivered, so the retried message is accepted
+func (c *Client) forgetSeen(key string) {
+	if key == "" {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.seen, key)
+}
+
+// senderName records the source with the sender, like "Release Bot (ci)"
+func senderName(sender, source string) string {
+	sender = strings.TrimSpace(sender)
+	if source = strings.TrimSpace(source); source == "" {
+		return sender
+	}
+	return fmt.Sprintf("%s (%s)", sender, source)
+}
+
+// sourceKey scopes a message ID to its source, so two sources can use the same IDs
+func sourceKey(source, id string) string {
+	if id = strings.TrimSpace(id); id == "" {
+		return ""
+	}
+	return source + ":" + id
+}
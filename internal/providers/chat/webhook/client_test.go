@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	config := NewConfig("ci-token", "ci")
+	config.Tokens["form-token"] = "release-form"
+
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	return client
+}
+
+func post(t *testing.T, client *Client, token, body string) (*httptest.ResponseRecorder, IngestResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	client.Handler().ServeHTTP(rec, req)
+
+	var resp IngestResponse
+	if rec.Code == http.StatusAccepted {
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	}
+	return rec, resp
+}
+
+func receive(t *testing.T, client *Client) *domain.Message {
+	t.Helper()
+	messages, err := client.ListenForMessages(context.Background())
+	require.NoError(t, err)
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func TestClient_Handler(t *testing.T) {
+	client := newTestClient(t)
+
+	rec, resp := post(t, client, "ci-token", `{"sender":"Release Bot","content":"Released v2.3 with the new billing API","thread":"release-2.3","channel":"releases"}`)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	msg := receive(t, client)
+	assert.Equal(t, resp.MessageID, msg.ID().String())
+	assert.Equal(t, resp.ThreadID, msg.ThreadID().String())
+	assert.Equal(t, "Release Bot (ci)", msg.Sender())
+	assert.Equal(t, "Released v2.3 with the new billing API", msg.Content().Text())
+	assert.Equal(t, "releases", msg.ChannelID())
+}
+
+func TestClient_Handler_Threads(t *testing.T) {
+	client := newTestClient(t)
+
+	_, first := post(t, client, "ci-token", `{"sender":"ci","content":"Build started","thread":"run-42"}`)
+	_, second := post(t, client, "ci-token", `{"sender":"ci","content":"Build passed","thread":"run-42"}`)
+	_, other := post(t, client, "ci-token", `{"sender":"ci","content":"Build started","thread":"run-43"}`)
+	_, unthreaded := post(t, client, "ci-token", `{"sender":"ci","content":"Nightly report"}`)
+
+	assert.Equal(t, first.ThreadID, second.ThreadID)
+	assert.NotEqual(t, first.ThreadID, other.ThreadID)
+	assert.NotEqual(t, first.ThreadID, unthreaded.ThreadID)
+	assert.Equal(t, DefaultChannel, receive(t, client).ChannelID())
+}
+
+func TestClient_Handler_DropsRepeatedIDs(t *testing.T) {
+	client := newTestClient(t)
+
+	_, first := post(t, client, "ci-token", `{"sender":"ci","content":"Deployed","id":"deploy-7"}`)
+	rec, again := post(t, client, "ci-token", `{"sender":"ci","content":"Deployed","id":"deploy-7"}`)
+	_, otherSource := post(t, client, "form-token", `{"sender":"Ana","content":"Deployed","id":"deploy-7"}`)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.True(t, again.Duplicate)
+	assert.Equal(t, first.MessageID, again.MessageID)
+	assert.False(t, otherSource.Duplicate)
+	assert.Len(t, client.messageCh, 2)
+}
+
+func TestClient_Handler_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{name: "wrong method", method: http.MethodGet, token: "ci-token", wantCode: http.StatusMethodNotAllowed},
+		{name: "unknown token", method: http.MethodPost, token: "other", body: `{"sender":"ci","content":"x"}`, wantCode: http.StatusUnauthorized},
+		{name: "invalid JSON", method: http.MethodPost, token: "ci-token", body: `{"sender":`, wantCode: http.StatusBadRequest},
+		{name: "missing sender", method: http.MethodPost, token: "ci-token", body: `{"content":"x"}`, wantCode: http.StatusBadRequest},
+		{name: "missing content", method: http.MethodPost, token: "ci-token", body: `{"sender":"ci","content":"  "}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t)
+			req := httptest.NewRequest(tt.method, "/ingest", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := httptest.NewRecorder()
+			client.Handler().ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Empty(t, client.messageCh)
+		})
+	}
+}
+
+func TestClient_DropsReplies(t *testing.T) {
+	client := newTestClient(t)
+
+	assert.NoError(t, client.ReplyToMessage(context.Background(), "message", "Captured"))
+	assert.NoError(t, client.SendMessage(context.Background(), "releases", "Summary"))
+	assert.NoError(t, client.AddReaction(context.Background(), "message", "memo"))
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMissingTokens    = errors.New("at least one ingest token is required")
+	ErrInvalidSizeLimit = errors.New("size limit cannot be negative")
+)
+
+const (
+	// DefaultChannel is the channel of messages posted without one
+	DefaultChannel = "webhook"
+	// DefaultMaxBodyBytes bounds the size of a posted message
+	DefaultMaxBodyBytes = 1 << 20
+)
+
+// Config contains the settings of the ingest endpoint
+type Config struct {
+	// Tokens maps each accepted bearer token to the name of the source using it, like "ci" or "release-form".
+	// The source is recorded with the sender, so every system pushing content gets its own token.
+	Tokens map[string]string
+
+	// DefaultChannel is the channel of messages posted without one (default: "webhook")
+	DefaultChannel string
+
+	// MaxBodyBytes bounds the size of a posted message (default: 1MB)
+	MaxBodyBytes int64
+
+	// DebugMode logs rejected messages when true
+	DebugMode bool
+}
+
+// NewConfig creates a new ingest configuration accepting the token of a single source
+func NewConfig(token, source string) *Config {
+	return &Config{
+		Tokens:         map[string]string{token: source},
+		DefaultChannel: DefaultChannel,
+		MaxBodyBytes:   DefaultMaxBodyBytes,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if len(c.Tokens) == 0 {
+		return ErrMissingTokens
+	}
+	for token := range c.Tokens {
+		if strings.TrimSpace(token) == "" {
+			return ErrMissingTokens
+		}
+	}
+
+	if c.MaxBodyBytes < 0 {
+		return ErrInvalidSizeLimit
+	}
+
+	return nil
+}
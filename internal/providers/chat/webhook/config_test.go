@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "several sources", mutate: func(c *Config) { c.Tokens["other-token"] = "release-form" }},
+		{name: "missing tokens", mutate: func(c *Config) { c.Tokens = nil }, wantErr: ErrMissingTokens},
+		{name: "empty token", mutate: func(c *Config) { c.Tokens[" "] = "scripts" }, wantErr: ErrMissingTokens},
+		{name: "negative size limit", mutate: func(c *Config) { c.MaxBodyBytes = -1 }, wantErr: ErrInvalidSizeLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig("ci-token", "ci")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package webhook
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures webhook clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new webhook client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateChatProvider creates a new webhook client that implements the ChatAccessProvider interface
+func (f *Factory) CreateChatProvider() (ports.ChatAccessProvider, error) {
+	return NewClient(f.config)
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMissingSender  = errors.New("sender is required")
+	ErrMissingContent = errors.New("content is required")
+)
+
+// IngestRequest is the JSON body posted to the ingest endpoint
+type IngestRequest struct {
+	// Sender is who wrote the content, like a person or "GitHub Actions"
+	Sender string `json:"sender"`
+
+	// Content is the text to process
+	Content string `json:"content"`
+
+	// Thread groups related messages, messages posted with the same thread key share a thread (optional)
+	Thread string `json:"thread,omitempty"`
+
+	// Channel selects the channel, and through its binding the project, of the message (optional)
+	Channel string `json:"channel,omitempty"`
+
+	// ID identifies the message at the source, a message posted again with the same ID is ignored (optional)
+	ID string `json:"id,omitempty"`
+}
+
+// validate checks the request carries what every message needs
+func (r IngestRequest) validate() error {
+	if strings.TrimSpace(r.Sender) == "" {
+		return ErrMissingSender
+	}
+	if strings.TrimSpace(r.Content) == "" {
+		return ErrMissingContent
+	}
+	return nil
+}
+
+// IngestResponse is the JSON body answering an accepted message
+type IngestResponse struct {
+	// MessageID is the ID the message is processed under
+	MessageID string `json:"messageId,omitempty"`
+
+	// ThreadID is the ID of the message's thread
+	ThreadID string `json:"threadId,omitempty"`
+
+	// Duplicate is true when a message with the same ID was already accepted
+	Duplicate bool `json:"duplicate,omitempty"`
+}
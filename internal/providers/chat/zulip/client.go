@@ -0,0 +1,210 @@
+package zulip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig  = errors.New("invalid zulip configuration")
+	ErrInvalidChannel = errors.New("zulip channel IDs are stream IDs")
+
+	// errBadEventQueue means the server forgot the event queue, a new one has to be registered
+	errBadEventQueue = errors.New("event queue expired")
+)
+
+// DefaultTimeout is the default timeout for HTTP requests, longer than the server holds an event poll open
+const DefaultTimeout = 2 * time.Minute
+
+// Client implements the ChatAccessProvider interface for Zulip. Streams are channels and the topics
+// within a stream are threads, so a conversation keeps its thread for as long as people use the topic.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	apiURL     string
+	messageCh  chan *domain.Message
+
+	lock       sync.RWMutex
+	threadMap  map[string]common.ID   // Maps stream ID and topic to our ThreadID
+	messages   map[string]MessageData // Maps our message IDs to their Zulip location
+	seen       map[int64]struct{}
+	seenOrder  []int64
+	botUserID  int64          // The bot's own Zulip user, learned when the event queue is registered
+	botMention *regexp.Regexp // Matches a mention of the bot starting a message
+
+	eventSupervisor *supervisor.Supervisor // Restarts the event loop
+}
+
+// NewClient creates a new Zulip client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	eventSupervisor, err := supervisor.New("zulip-events", config.Supervision)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	return &Client{
+		config:          config,
+		httpClient:      httpClient,
+		apiURL:          strings.TrimRight(config.SiteURL, "/") + "/api/v1",
+		messageCh:       make(chan *domain.Message, 100),
+		threadMap:       make(map[string]common.ID),
+		messages:        make(map[string]MessageData),
+		seen:            make(map[int64]struct{}),
+		eventSupervisor: eventSupervisor,
+	}, nil
+}
+
+// SendMessage sends a message to a stream, under the default topic
+func (c *Client) SendMessage(ctx context.Context, channelID, content string) error {
+	streamID, err := strconv.ParseInt(channelID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to send message to %q: %w", channelID, ErrInvalidChannel)
+	}
+
+	if err := c.sendStreamMessage(ctx, streamID, c.defaultTopic(), content); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// ReplyToMessage replies in the topic of a message received from Zulip
+func (c *Client) ReplyToMessage(ctx context.Context, messageID, content string) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to reply to message: unknown message %s", messageID)
+	}
+
+	if err := c.sendStreamMessage(ctx, data.StreamID, data.Topic, content); err != nil {
+		return fmt.Errorf("failed to reply to message: %w", err)
+	}
+	return nil
+}
+
+// AddReaction reacts to a message received from Zulip with an emoji, named without colons
+func (c *Client) AddReaction(ctx context.Context, messageID, emoji string) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to add reaction: unknown message %s", messageID)
+	}
+
+	form := url.Values{"emoji_name": {strings.Trim(emoji, ":")}}
+	if err := c.call(ctx, http.MethodPost, fmt.Sprintf("/messages/%d/reactions", data.MessageID), form, nil); err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return nil
+}
+
+// ListenForMessages starts polling the Zulip event queue. The event loop is supervised: it is
+// restarted with backoff after failing or panicking until ctx is canceled.
+func (c *Client) ListenForMessages(ctx context.Context) (<-chan *domain.Message, error) {
+	go c.eventSupervisor.Run(ctx, c.handleEvents)
+	return c.messageCh, nil
+}
+
+// Health reports the state of the supervised event loop
+func (c *Client) Health() []supervisor.Health {
+	return []supervisor.Health{c.eventSupervisor.Health()}
+}
+
+// HandleInteraction is not supported, Zulip bots have no interactive components
+func (c *Client) HandleInteraction(ctx context.Context, interaction interface{}) error {
+	return fmt.Errorf("unsupported interaction type: %T", interaction)
+}
+
+// sendStreamMessage posts a message to a topic of a stream
+func (c *Client) sendStreamMessage(ctx context.Context, streamID int64, topic, content string) error {
+	form := url.Values{
+		"type":    {"stream"},
+		"to":      {strconv.FormatInt(streamID, 10)},
+		"topic":   {topic},
+		"content": {content},
+	}
+	return c.call(ctx, http.MethodPost, "/messages", form, nil)
+}
+
+func (c *Client) defaultTopic() string {
+	if topic := strings.TrimSpace(c.config.DefaultTopic); topic != "" {
+		return topic
+	}
+	return DefaultTopic
+}
+
+// call sends a request to the Zulip API and decodes the response. Parameters are sent as the
+// query of GET requests and as a form otherwise.
+func (c *Client) call(ctx context.Context, method, path string, params url.Values, response interface{}) error {
+	endpoint := c.apiURL + path
+	var body *strings.Reader
+	if method == http.MethodGet {
+		endpoint += "?" + params.Encode()
+		body = strings.NewReader("")
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.config.BotEmail, c.config.APIKey)
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+	payload := buf.Bytes()
+
+	var result apiResponse
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, payload)
+	}
+	if result.Result != "success" {
+		if result.Code == "BAD_EVENT_QUEUE_ID" {
+			return fmt.Errorf("%w: %s", errBadEventQueue, result.Msg)
+		}
+		return fmt.Errorf("zulip API error (status %d, %s): %s", resp.StatusCode, result.Code, result.Msg)
+	}
+
+	if response == nil {
+		return nil
+	}
+	if err := json.Unmarshal(payload, response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
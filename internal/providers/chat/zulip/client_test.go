@@ -0,0 +1,247 @@
+package zulip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeZulip emulates the Zulip endpoints the client uses
+type fakeZulip struct {
+	mu        sync.Mutex
+	queues    int
+	expired   bool // The next poll reports the event queue as garbage collected
+	events    []event
+	nextEvent int64
+	sent      []url.Values
+	reactions []string
+	polls     []url.Values
+}
+
+func newFakeZulip(t *testing.T) (*fakeZulip, *Client) {
+	fake := &fakeZulip{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(NewConfig(server.URL, "quill-bot@example.com", "key"))
+	require.NoError(t, err)
+	return fake, client
+}
+
+func (f *fakeZulip) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if email, key, ok := r.BasicAuth(); !ok || email != "quill-bot@example.com" || key != "key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"result":"error","code":"UNAUTHORIZED","msg":"Invalid API key"}`))
+		return
+	}
+	_ = r.ParseForm()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	switch {
+	case path == "/users/me":
+		_, _ = w.Write([]byte(`{"result":"success","msg":"","user_id":99,"full_name":"Quill"}`))
+	case path == "/register":
+		f.queues++
+		_, _ = fmt.Fprintf(w, `{"result":"success","msg":"","queue_id":"q%d","last_event_id":-1}`, f.queues)
+	case path == "/events":
+		f.polls = append(f.polls, r.Form)
+		if f.expired {
+			f.expired = false
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, `{"result":"error","code":"BAD_EVENT_QUEUE_ID","msg":"Bad event queue ID: %s"}`, r.Form.Get("queue_id"))
+			return
+		}
+		events := f.events
+		f.events = nil
+		if len(events) == 0 {
+			// Long polls end with a heartbeat when nothing happened
+			f.nextEvent++
+			events = []event{{ID: f.nextEvent, Type: "heartbeat"}}
+		}
+		_ = json.NewEncoder(w).Encode(eventsResponse{apiResponse: apiResponse{Result: "success"}, Events: events})
+	case path == "/messages":
+		f.sent = append(f.sent, r.Form)
+		_, _ = w.Write([]byte(`{"result":"success","msg":"","id":500}`))
+	case strings.HasSuffix(path, "/reactions"):
+		f.reactions = append(f.reactions, path+" "+r.Form.Get("emoji_name"))
+		_, _ = w.Write([]byte(`{"result":"success","msg":""}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"result":"error","code":"BAD_REQUEST","msg":"unknown endpoint"}`))
+	}
+}
+
+// post queues a message event in a stream
+func (f *fakeZulip) post(msg eventMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if msg.Type == "" {
+		msg.Type = "stream"
+	}
+	f.nextEvent++
+	f.events = append(f.events, event{ID: f.nextEvent, Type: "message", Message: &msg})
+}
+
+func (f *fakeZulip) sentMessages() []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]url.Values(nil), f.sent...)
+}
+
+func streamMessage(id int64, topic, content string) eventMessage {
+	return eventMessage{
+		ID:               id,
+		SenderID:         7,
+		SenderEmail:      "alice@example.com",
+		SenderFullName:   "Alice",
+		StreamID:         3,
+		DisplayRecipient: "engineering",
+		Subject:          topic,
+		Content:          content,
+	}
+}
+
+// receive waits for the next message the client publishes
+func receive(t *testing.T, messages <-chan *domain.Message) *domain.Message {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func TestClient_ListenForMessages(t *testing.T) {
+	fake, client := newFakeZulip(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake.post(streamMessage(1, "billing", "We decided to use Postgres"))
+	fake.post(streamMessage(2, "Billing", "It ships next week"))
+	fake.post(streamMessage(3, "hiring", "@**Quill** we need a second reviewer"))
+
+	messages, err := client.ListenForMessages(ctx)
+	require.NoError(t, err)
+
+	first := receive(t, messages)
+	second := receive(t, messages)
+	third := receive(t, messages)
+
+	assert.Equal(t, "3", first.ChannelID(), "streams are channels")
+	assert.Equal(t, "Alice", first.Sender())
+	assert.Equal(t, "We decided to use Postgres", first.Content().Text())
+	assert.True(t, first.ThreadID().Equals(second.ThreadID()), "topics are threads, case-insensitively")
+	assert.False(t, first.ThreadID().Equals(third.ThreadID()))
+	assert.Equal(t, "we need a second reviewer", third.Content().Text(), "the bot mention is stripped")
+}
+
+func TestClient_SkipsMessages(t *testing.T) {
+	fake, client := newFakeZulip(t)
+	client.config.IgnoredSenderEmails = []string{"ci@example.com"}
+	client.config.Streams = []string{"engineering"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	own := streamMessage(1, "billing", "✅ Recorded decision")
+	own.SenderID, own.SenderEmail = 99, "quill-bot@example.com"
+	ignored := streamMessage(2, "billing", "Build passed")
+	ignored.SenderEmail = "ci@example.com"
+	direct := streamMessage(3, "", "Can you help me?")
+	direct.Type = "private"
+	otherStream := streamMessage(4, "lunch", "Pizza?")
+	otherStream.StreamID, otherStream.DisplayRecipient = 4, "social"
+	for _, msg := range []eventMessage{own, ignored, direct, otherStream, streamMessage(5, "billing", "We decided to use Postgres")} {
+		fake.post(msg)
+	}
+
+	messages, err := client.ListenForMessages(ctx)
+	require.NoError(t, err)
+
+	msg := receive(t, messages)
+	assert.Equal(t, "We decided to use Postgres", msg.Content().Text())
+}
+
+func TestClient_RegistersNewQueueWhenExpired(t *testing.T) {
+	fake, client := newFakeZulip(t)
+	fake.expired = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, err := client.ListenForMessages(ctx)
+	require.NoError(t, err)
+	fake.post(streamMessage(1, "billing", "We decided to use Postgres"))
+
+	receive(t, messages)
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, 2, fake.queues)
+	assert.Equal(t, "q2", fake.polls[len(fake.polls)-1].Get("queue_id"))
+}
+
+func TestClient_Replies(t *testing.T) {
+	fake, client := newFakeZulip(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake.post(streamMessage(42, "billing", "We decided to use Postgres"))
+	messages, err := client.ListenForMessages(ctx)
+	require.NoError(t, err)
+	msg := receive(t, messages)
+
+	require.NoError(t, client.ReplyToMessage(ctx, msg.ID().String(), "✅ Recorded decision"))
+	require.NoError(t, client.ReplyDirect(ctx, msg.ID().String(), "✅ Recorded decision"))
+	require.NoError(t, client.SendMessage(ctx, "3", "Project created"))
+	require.NoError(t, client.AddReaction(ctx, msg.ID().String(), ":memo:"))
+
+	sent := fake.sentMessages()
+	require.Len(t, sent, 3)
+	assert.Equal(t, url.Values{"type": {"stream"}, "to": {"3"}, "topic": {"billing"}, "content": {"✅ Recorded decision"}}, sent[0])
+	assert.Equal(t, "private", sent[1].Get("type"))
+	assert.Equal(t, "[7]", sent[1].Get("to"))
+	assert.Equal(t, "In #**engineering>billing**: ✅ Recorded decision", sent[1].Get("content"))
+	assert.Equal(t, DefaultTopic, sent[2].Get("topic"))
+	assert.Equal(t, []string{"/messages/42/reactions memo"}, fake.reactions)
+
+	assert.ErrorIs(t, client.SendMessage(ctx, "engineering", "Project created"), ErrInvalidChannel)
+	assert.Error(t, client.ReplyToMessage(ctx, "unknown", "✅ Recorded decision"))
+}
+
+func TestClient_MessageRoute(t *testing.T) {
+	_, ingestion := newFakeZulip(t)
+	fake, worker := newFakeZulip(t)
+	ingestion.rememberMessage("01HMSG", MessageData{StreamID: 3, StreamName: "engineering", Topic: "billing", MessageID: 42, SenderID: 7})
+
+	route, ok := ingestion.MessageRoute("01HMSG")
+	require.True(t, ok)
+	require.NoError(t, worker.RestoreMessageRoute("01HMSG", route))
+	require.NoError(t, worker.ReplyToMessage(context.Background(), "01HMSG", "✅ Recorded decision"))
+
+	sent := fake.sentMessages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "billing", sent[0].Get("topic"))
+	assert.ErrorIs(t, worker.RestoreMessageRoute("01HOTHER", map[string]string{routeTopic: "billing"}), ErrInvalidRoute)
+}
+
+func TestClient_ReportsAPIErrors(t *testing.T) {
+	_, client := newFakeZulip(t)
+	client.config.APIKey = "wrong"
+
+	err := client.SendMessage(context.Background(), "3", "Project created")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid API key")
+}
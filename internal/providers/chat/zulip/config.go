@@ -0,0 +1,89 @@
+package zulip
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingSiteURL     = errors.New("Zulip site URL is required")
+	ErrInvalidSiteURL     = errors.New("Zulip site URL must be an absolute http or https URL")
+	ErrMissingCredentials = errors.New("Zulip bot email and API key are required")
+)
+
+// DefaultTopic is the topic of messages the bot starts on its own, like project announcements
+const DefaultTopic = "quill"
+
+// Config contains Zulip configuration parameters
+type Config struct {
+	// SiteURL is the address of the Zulip organization, e.g. https://research.zulipchat.com
+	SiteURL string
+
+	// BotEmail is the email address of the bot user
+	BotEmail string
+
+	// APIKey is the API key of the bot user
+	APIKey string
+
+	// DefaultTopic is the topic of messages sent to a stream outside of a conversation (default: quill)
+	DefaultTopic string
+
+	// Streams lists the names of the streams whose messages are processed (optional, all streams the bot is subscribed to)
+	Streams []string
+
+	// IgnoredSenderEmails lists people or bots whose messages are never processed
+	IgnoredSenderEmails []string
+
+	// DebugMode logs why messages are skipped when true
+	DebugMode bool
+
+	// Supervision controls restarting the event loop after failures (optional)
+	Supervision *supervisor.Config
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewConfig creates a new Zulip configuration
+func NewConfig(siteURL, botEmail, apiKey string) *Config {
+	return &Config{
+		SiteURL:      siteURL,
+		BotEmail:     botEmail,
+		APIKey:       apiKey,
+		DefaultTopic: DefaultTopic,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.SiteURL) == "" {
+		return ErrMissingSiteURL
+	}
+
+	u, err := url.Parse(c.SiteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidSiteURL
+	}
+
+	if strings.TrimSpace(c.BotEmail) == "" || strings.TrimSpace(c.APIKey) == "" {
+		return ErrMissingCredentials
+	}
+
+	if c.Supervision != nil {
+		if err := c.Supervision.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
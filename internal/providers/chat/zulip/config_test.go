@@ -0,0 +1,37 @@
+package zulip
+
+import (
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/providers/supervisor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "missing site URL", mutate: func(c *Config) { c.SiteURL = "" }, wantErr: ErrMissingSiteURL},
+		{name: "relative site URL", mutate: func(c *Config) { c.SiteURL = "research.zulipchat.com" }, wantErr: ErrInvalidSiteURL},
+		{name: "missing bot email", mutate: func(c *Config) { c.BotEmail = "" }, wantErr: ErrMissingCredentials},
+		{name: "missing API key", mutate: func(c *Config) { c.APIKey = " " }, wantErr: ErrMissingCredentials},
+		{name: "invalid supervision", mutate: func(c *Config) { c.Supervision = &supervisor.Config{} }, wantErr: supervisor.ErrInvalidBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig("https://research.zulipchat.com", "quill-bot@research.zulipchat.com", "key")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
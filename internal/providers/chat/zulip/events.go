@@ -0,0 +1,225 @@
+package zulip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// maxSeenMessages bounds the set used to drop duplicate deliveries of the same message
+const maxSeenMessages = 1000
+
+// handleEvents registers an event queue and polls it until ctx is canceled. Queues the server
+// garbage collected, e.g. after a long disconnect, are replaced by a new one.
+func (c *Client) handleEvents(ctx context.Context) error {
+	for {
+		queueID, lastEventID, err := c.register(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = c.poll(ctx, queueID, lastEventID)
+		if !errors.Is(err, errBadEventQueue) {
+			return err
+		}
+		log.Printf("Zulip event queue %s expired, registering a new one", queueID)
+	}
+}
+
+// register learns who the bot is and creates an event queue delivering messages as written
+func (c *Client) register(ctx context.Context) (string, int64, error) {
+	var me ownUserResponse
+	if err := c.call(ctx, http.MethodGet, "/users/me", url.Values{}, &me); err != nil {
+		return "", 0, fmt.Errorf("failed to fetch bot user: %w", err)
+	}
+	c.lock.Lock()
+	c.botUserID = me.UserID
+	c.botMention = regexp.MustCompile(`^\s*@_?\*\*` + regexp.QuoteMeta(me.FullName) + `(?:\|\d+)?\*\*\s*`)
+	c.lock.Unlock()
+
+	params := url.Values{
+		"event_types":    {`["message"]`},
+		"apply_markdown": {"false"},
+	}
+	var registered registerResponse
+	if err := c.call(ctx, http.MethodPost, "/register", params, &registered); err != nil {
+		return "", 0, fmt.Errorf("failed to register event queue: %w", err)
+	}
+	return registered.QueueID, registered.LastEventID, nil
+}
+
+// poll long-polls the event queue and handles the message events
+func (c *Client) poll(ctx context.Context, queueID string, lastEventID int64) error {
+	for {
+		params := url.Values{
+			"queue_id":      {queueID},
+			"last_event_id": {strconv.FormatInt(lastEventID, 10)},
+		}
+		var response eventsResponse
+		if err := c.call(ctx, http.MethodGet, "/events", params, &response); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to poll events: %w", err)
+		}
+
+		for _, ev := range response.Events {
+			if ev.ID > lastEventID {
+				lastEventID = ev.ID
+			}
+			if ev.Type == "message" && ev.Message != nil {
+				c.processMessage(ctx, ev.Message)
+			}
+		}
+	}
+}
+
+// processMessage converts a Zulip stream message to our domain Message and sends it for processing
+func (c *Client) processMessage(ctx context.Context, msg *eventMessage) {
+	if reason := c.skipReason(msg); reason != "" {
+		if c.config.DebugMode {
+			log.Printf("Skipping Zulip message %d: %s", msg.ID, reason)
+		}
+		return
+	}
+	if !c.markSeen(msg.ID) {
+		return
+	}
+
+	messageContent, err := domain.NewMessageContent(c.stripBotMention(msg.Content))
+	if err != nil {
+		log.Printf("Error creating message content: %v", err)
+		return
+	}
+
+	data := MessageData{
+		StreamID:   msg.StreamID,
+		StreamName: msg.streamName(),
+		Topic:      msg.Subject,
+		MessageID:  msg.ID,
+		SenderID:   msg.SenderID,
+	}
+
+	// For now, use default type and category - these will be determined later by AI analysis
+	domainMsg, err := domain.NewMessage(
+		c.threadIDFor(msg.StreamID, msg.Subject),
+		msg.SenderFullName,
+		messageContent,
+		domain.MessageTypeInformation,
+		domain.CategoryOther,
+		nil, // no references initially
+	)
+	if err != nil {
+		log.Printf("Error creating domain message: %v", err)
+		return
+	}
+	domainMsg.SetChannelID(data.channelID())
+	domainMsg.SetSourceTimestamp(strconv.FormatInt(msg.ID, 10))
+	c.rememberMessage(domainMsg.ID().String(), data)
+
+	// Send to message channel for processing, unless the listener stopped while it was full
+	select {
+	case c.messageCh <- domainMsg:
+	case <-ctx.Done():
+	}
+}
+
+// skipReason explains why a message is not processed, or returns an empty string
+func (c *Client) skipReason(msg *eventMessage) string {
+	if msg.Type != "stream" {
+		return "direct messages are not documented"
+	}
+
+	c.lock.RLock()
+	own := msg.SenderID == c.botUserID
+	c.lock.RUnlock()
+	if own || strings.EqualFold(msg.SenderEmail, c.config.BotEmail) {
+		return "sent by the bot"
+	}
+
+	for _, email := range c.config.IgnoredSenderEmails {
+		if strings.EqualFold(email, msg.SenderEmail) {
+			return "sender is ignored"
+		}
+	}
+
+	if len(c.config.Streams) == 0 {
+		return ""
+	}
+	for _, stream := range c.config.Streams {
+		if strings.EqualFold(stream, msg.streamName()) {
+			return ""
+		}
+	}
+	return "stream is not processed"
+}
+
+// stripBotMention removes the mention of the bot that starts a message addressed to it
+func (c *Client) stripBotMention(content string) string {
+	c.lock.RLock()
+	mention := c.botMention
+	c.lock.RUnlock()
+	if mention == nil {
+		return content
+	}
+	return mention.ReplaceAllString(content, "")
+}
+
+// threadIDFor maps a Zulip topic to our ThreadID, creating one for new topics.
+// Topic names are case-insensitive in Zulip.
+func (c *Client) threadIDFor(streamID int64, topic string) common.ID {
+	key := strconv.FormatInt(streamID, 10) + ":" + strings.ToLower(topic)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if id, exists := c.threadMap[key]; exists {
+		return id
+	}
+	id := common.GenerateID()
+	c.threadMap[key] = id
+	return id
+}
+
+// rememberMessage keeps the Zulip location of a domain message so replies land in its topic
+func (c *Client) rememberMessage(messageID string, data MessageData) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.messages[messageID] = data
+}
+
+// lookupMessage returns the Zulip location of a domain message
+func (c *Client) lookupMessage(messageID string) (MessageData, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	data, ok := c.messages[messageID]
+	return data, ok
+}
+
+// markSeen records a message ID and reports whether it was new
+func (c *Client) markSeen(id int64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.seen[id]; ok {
+		return false
+	}
+	if len(c.seenOrder) >= maxSeenMessages {
+		delete(c.seen, c.seenOrder[0])
+		c.seenOrder = c.seenOrder[1:]
+	}
+	c.seen[id] = struct{}{}
+	c.seenOrder = append(c.seenOrder, id)
+	return true
+}
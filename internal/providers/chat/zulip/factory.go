@@ -0,0 +1,22 @@
+package zulip
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures Zulip clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new Zulip client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateChatProvider creates a new Zulip client that implements the ChatAccessProvider interface
+func (f *Factory) CreateChatProvider() (ports.ChatAccessProvider, error) {
+	return NewClient(f.config)
+}
@@ -0,0 +1,91 @@
+package zulip
+
+import (
+	"strconv"
+)
+
+// MessageData is where a message was posted in Zulip
+type MessageData struct {
+	// StreamID is the ID of the stream, the domain channel ID
+	StreamID int64
+
+	// Topic is the topic within the stream, the domain thread
+	Topic string
+
+	// MessageID is the Zulip message ID
+	MessageID int64
+
+	// StreamName is the name of the stream when the message was received
+	StreamName string
+
+	// SenderID is the Zulip user ID of the author
+	SenderID int64
+}
+
+// streamLabel returns the stream's name, or its ID for messages received before names were kept
+func (d MessageData) streamLabel() string {
+	if d.StreamName != "" {
+		return d.StreamName
+	}
+	return d.channelID()
+}
+
+// channelID returns the domain channel ID of the message's stream
+func (d MessageData) channelID() string {
+	return strconv.FormatInt(d.StreamID, 10)
+}
+
+// apiResponse is the envelope of every Zulip API response
+type apiResponse struct {
+	Result string `json:"result"`
+	Msg    string `json:"msg"`
+	Code   string `json:"code"`
+}
+
+// ownUserResponse is the response of fetching the bot's own user
+type ownUserResponse struct {
+	apiResponse
+	UserID   int64  `json:"user_id"`
+	FullName string `json:"full_name"`
+}
+
+// registerResponse is the response of registering an event queue
+type registerResponse struct {
+	apiResponse
+	QueueID     string `json:"queue_id"`
+	LastEventID int64  `json:"last_event_id"`
+}
+
+// eventsResponse is the response of polling an event queue
+type eventsResponse struct {
+	apiResponse
+	Events []event `json:"events"`
+}
+
+// event is an entry of an event queue, only message events carry a message
+type event struct {
+	ID      int64         `json:"id"`
+	Type    string        `json:"type"`
+	Message *eventMessage `json:"message"`
+}
+
+// eventMessage is a message as delivered in a message event
+type eventMessage struct {
+	ID             int64  `json:"id"`
+	SenderID       int64  `json:"sender_id"`
+	SenderEmail    string `json:"sender_email"`
+	SenderFullName string `json:"sender_full_name"`
+	Type           string `json:"type"`
+	StreamID       int64  `json:"stream_id"`
+	// DisplayRecipient is the stream name for stream messages and the recipients for direct messages
+	DisplayRecipient interface{} `json:"display_recipient"`
+	Subject          string      `json:"subject"`
+	Content          string      `json:"content"`
+	Timestamp        int64       `json:"timestamp"`
+}
+
+// streamName returns the name of the stream a stream message was posted in
+func (m *eventMessage) streamName() string {
+	name, _ := m.DisplayRecipient.(string)
+	return name
+}
@@ -0,0 +1,36 @@
+package zulip
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ReplyEphemeral sends the reply to the author of a message in a direct message.
+// Zulip cannot show a message in a stream to one person only.
+func (c *Client) ReplyEphemeral(ctx context.Context, messageID, content string) error {
+	return c.ReplyDirect(ctx, messageID, content)
+}
+
+// ReplyDirect sends the reply to the author of a message in a direct message.
+// The reply links the topic of the message, as it is read outside of it.
+func (c *Client) ReplyDirect(ctx context.Context, messageID, content string) error {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return fmt.Errorf("failed to send direct message: unknown message %s", messageID)
+	}
+	if data.SenderID == 0 {
+		return fmt.Errorf("failed to send direct message: message %s has no author", messageID)
+	}
+
+	form := url.Values{
+		"type":    {"private"},
+		"to":      {fmt.Sprintf("[%d]", data.SenderID)},
+		"content": {fmt.Sprintf("In #**%s>%s**: %s", data.streamLabel(), data.Topic, content)},
+	}
+	if err := c.call(ctx, http.MethodPost, "/messages", form, nil); err != nil {
+		return fmt.Errorf("failed to send direct message: %w", err)
+	}
+	return nil
+}
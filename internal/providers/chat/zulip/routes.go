@@ -0,0 +1,55 @@
+package zulip
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	ErrInvalidRoute = errors.New("invalid message route")
+)
+
+// Keys of the route describing where a message was posted in Zulip
+const (
+	routeStream     = "zulip_stream_id"
+	routeStreamName = "zulip_stream"
+	routeTopic      = "zulip_topic"
+	routeMessage    = "zulip_message_id"
+	routeSender     = "zulip_sender_id"
+)
+
+// MessageRoute returns where in Zulip a received message was posted, so another instance can reply to it
+func (c *Client) MessageRoute(messageID string) (map[string]string, bool) {
+	data, ok := c.lookupMessage(messageID)
+	if !ok {
+		return nil, false
+	}
+	return map[string]string{
+		routeStream:     data.channelID(),
+		routeStreamName: data.StreamName,
+		routeTopic:      data.Topic,
+		routeMessage:    strconv.FormatInt(data.MessageID, 10),
+		routeSender:     strconv.FormatInt(data.SenderID, 10),
+	}, true
+}
+
+// RestoreMessageRoute makes a message received by another instance known, replies to it land in its topic
+func (c *Client) RestoreMessageRoute(messageID string, route map[string]string) error {
+	streamID, errStream := strconv.ParseInt(route[routeStream], 10, 64)
+	zulipID, errMessage := strconv.ParseInt(route[routeMessage], 10, 64)
+	if errStream != nil || errMessage != nil {
+		return fmt.Errorf("%w: message %s has no Zulip stream or message ID", ErrInvalidRoute, messageID)
+	}
+	// Bots post without a person to reply to privately
+	senderID, _ := strconv.ParseInt(route[routeSender], 10, 64)
+
+	c.rememberMessage(messageID, MessageData{
+		StreamID:   streamID,
+		StreamName: route[routeStreamName],
+		Topic:      route[routeTopic],
+		MessageID:  zulipID,
+		SenderID:   senderID,
+	})
+	return nil
+}
@@ -0,0 +1,181 @@
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	// The leader election runs on Postgres, its driver is bundled so a deployment only needs a DSN
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+var (
+	ErrNilDatabase = errors.New("database cannot be nil")
+)
+
+// DefaultLeaderRetryInterval is how often a follower tries to take over and a leader checks its session
+const DefaultLeaderRetryInterval = 5 * time.Second
+
+// LeaderConfig contains the settings of a Postgres leader election
+type LeaderConfig struct {
+	// LockKey is the advisory lock all replicas of a deployment compete for
+	LockKey int64
+
+	// RetryInterval is how often followers try to take the lock and the leader checks it still holds it
+	// (optional, defaults to DefaultLeaderRetryInterval)
+	RetryInterval time.Duration
+}
+
+// PostgresLeader elects one leader among replicas with a session-level Postgres advisory lock.
+// The leader processes every channel, followers none. When the leader's database session ends,
+// Postgres releases the lock and a follower takes over within one retry interval. Elected and Deposed tell
+// when to connect to a chat that delivers each event to one replica only, like Slack Socket Mode.
+//
+// The database uses the bundled pgx driver, open it with sql.Open("pgx", dsn).
+type PostgresLeader struct {
+	db       *sql.DB
+	key      int64
+	interval time.Duration
+
+	mu     sync.RWMutex
+	leader bool
+	conn   *sql.Conn // Session holding the lock while leading, only used by the goroutine campaigning
+
+	elected     chan struct{}
+	deposed     chan struct{}
+	electedOnce sync.Once
+	deposedOnce sync.Once
+}
+
+// NewPostgresLeader creates a leader election, call Run to take part in it
+func NewPostgresLeader(db *sql.DB, cfg LeaderConfig) (*PostgresLeader, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	interval := cfg.RetryInterval
+	if interval <= 0 {
+		interval = DefaultLeaderRetryInterval
+	}
+	return &PostgresLeader{
+		db:       db,
+		key:      cfg.LockKey,
+		interval: interval,
+		elected:  make(chan struct{}),
+		deposed:  make(chan struct{}),
+	}, nil
+}
+
+// Run campaigns for leadership until ctx is canceled, then gives up the lock
+func (l *PostgresLeader) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	defer l.resign()
+
+	for {
+		l.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader reports whether this instance currently holds the lock
+func (l *PostgresLeader) IsLeader() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.leader
+}
+
+// Elected is closed once this instance first becomes the leader
+func (l *PostgresLeader) Elected() <-chan struct{} {
+	return l.elected
+}
+
+// Deposed is closed once this instance stops leading after it led, because its session ended or it resigned
+func (l *PostgresLeader) Deposed() <-chan struct{} {
+	return l.deposed
+}
+
+// Owns reports whether this instance leads, the leader processes all channels
+func (l *PostgresLeader) Owns(ctx context.Context, channelID string) (bool, error) {
+	return l.IsLeader(), nil
+}
+
+// campaign takes the lock when it is free, or checks that the session holding it is still alive. The database is
+// queried without holding the mutex, so IsLeader does not wait on it.
+func (l *PostgresLeader) campaign(ctx context.Context) {
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err != nil {
+			log.Printf("Lost leadership, database session ended: %v", err)
+			discard(l.conn)
+			l.setConn(nil)
+		}
+		return
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		log.Printf("Failed to connect for leader election: %v", err)
+		return
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		log.Printf("Failed to try leader lock: %v", err)
+		discard(conn)
+		return
+	}
+	if !acquired {
+		_ = conn.Close()
+		return
+	}
+
+	log.Printf("Became leader")
+	l.setConn(conn)
+}
+
+// resign releases the lock so a follower can take over without waiting for the session to time out
+func (l *PostgresLeader) resign() {
+	if l.conn == nil {
+		return
+	}
+
+	// The campaign context is canceled by now
+	ctx, cancel := context.WithTimeout(context.Background(), l.interval)
+	defer cancel()
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		log.Printf("Failed to release leader lock: %v", err)
+		discard(l.conn)
+	} else {
+		_ = l.conn.Close()
+	}
+	l.setConn(nil)
+}
+
+// setConn records the session holding the lock, nil when this instance stops leading
+func (l *PostgresLeader) setConn(conn *sql.Conn) {
+	l.mu.Lock()
+	l.conn = conn
+	l.leader = conn != nil
+	l.mu.Unlock()
+
+	if conn != nil {
+		l.electedOnce.Do(func() { close(l.elected) })
+	} else {
+		l.deposedOnce.Do(func() { close(l.deposed) })
+	}
+}
+
+// discard closes a session instead of returning it to the pool, where a session that may still hold the lock or
+// is broken would be handed out again. The pool drops connections reporting driver.ErrBadConn.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(driverConn any) error {
+		return driver.ErrBadConn
+	})
+}
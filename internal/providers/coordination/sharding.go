@@ -0,0 +1,41 @@
+package coordination
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+)
+
+var (
+	ErrInvalidShard = errors.New("shard count must be positive and the index between 0 and count-1")
+)
+
+// ShardCoordinator splits channels between a fixed number of instances by hashing the channel ID.
+// Every instance runs with the same count and its own index, e.g. the ordinal of a StatefulSet pod.
+type ShardCoordinator struct {
+	index int
+	count int
+}
+
+// NewShardCoordinator creates the coordinator of the shard at index out of count shards
+func NewShardCoordinator(index, count int) (*ShardCoordinator, error) {
+	if count <= 0 || index < 0 || index >= count {
+		return nil, ErrInvalidShard
+	}
+	return &ShardCoordinator{index: index, count: count}, nil
+}
+
+// Owns reports whether the channel hashes to this shard. Messages without a channel belong to the first shard.
+func (s *ShardCoordinator) Owns(ctx context.Context, channelID string) (bool, error) {
+	return ShardOf(channelID, s.count) == s.index, nil
+}
+
+// ShardOf returns the shard out of count that processes a channel
+func ShardOf(channelID string, count int) int {
+	if channelID == "" || count <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(channelID))
+	return int(h.Sum32() % uint32(count))
+}
@@ -0,0 +1,66 @@
+package coordination
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShardCoordinator(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   int
+		count   int
+		wantErr bool
+	}{
+		{name: "single shard", index: 0, count: 1},
+		{name: "last shard", index: 2, count: 3},
+		{name: "no shards", index: 0, count: 0, wantErr: true},
+		{name: "index out of range", index: 3, count: 3, wantErr: true},
+		{name: "negative index", index: -1, count: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewShardCoordinator(tt.index, tt.count)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidShard)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShardCoordinator_EveryChannelHasOneOwner(t *testing.T) {
+	const count = 3
+	shards := make([]*ShardCoordinator, count)
+	for i := range shards {
+		shard, err := NewShardCoordinator(i, count)
+		require.NoError(t, err)
+		shards[i] = shard
+	}
+
+	used := make(map[int]bool)
+	for c := 0; c < 100; c++ {
+		channelID := fmt.Sprintf("C%04d", c)
+		owners := 0
+		for i, shard := range shards {
+			owns, err := shard.Owns(context.Background(), channelID)
+			require.NoError(t, err)
+			if owns {
+				owners++
+				used[i] = true
+			}
+		}
+		assert.Equal(t, 1, owners, "channel %s", channelID)
+	}
+	assert.Len(t, used, count, "channels are spread over all shards")
+
+	owns, err := shards[0].Owns(context.Background(), "")
+	require.NoError(t, err)
+	assert.True(t, owns, "messages without a channel belong to the first shard")
+}
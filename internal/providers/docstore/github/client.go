@@ -5,8 +5,9 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"github.com/massimo-ua/quill/internal/providers/transport"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -17,10 +18,15 @@ import (
 const (
 	// GitHubAPIBaseURL is the base URL for the GitHub API
 	GitHubAPIBaseURL = "https://api.github.com"
+	// GitHubWebBaseURL is the base URL for the GitHub web interface
+	GitHubWebBaseURL = "https://github.com"
 	// DefaultTimeout is the default HTTP client timeout
 	DefaultTimeout = 30 * time.Second
 )
 
+// ErrFileNotFound indicates that a file does not exist on the branch
+var ErrFileNotFound = errors.New("file not found")
+
 // Client represents a GitHub API client
 type Client struct {
 	config     *Config
@@ -38,23 +44,25 @@ func NewClient(cfg *Config) (*Client, error) {
 		return nil, err
 	}
 
-	httpClient := &http.Client{
-		Timeout: DefaultTimeout,
+	httpClient, err := transport.NewHTTPClient(cfg.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	apiBaseURL := GitHubAPIBaseURL
+	if strings.TrimSpace(cfg.BaseURL) != "" {
+		apiBaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
 	}
 
 	return &Client{
 		config:     cfg,
 		httpClient: httpClient,
-		apiBaseURL: GitHubAPIBaseURL,
+		apiBaseURL: apiBaseURL,
 	}, nil
 }
 
 // GetContent retrieves the content of a file from GitHub
 func (c *Client) GetContent(ctx context.Context, path string) (*GitHubContent, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	fullPath := c.buildContentPath(path)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath, nil)
 	if err != nil {
@@ -72,13 +80,15 @@ func (c *Client) GetContent(ctx context.Context, path string) (*GitHubContent, e
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	buf, err := transport.ReadBody(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer transport.PutBuffer(buf)
+	body := buf.Bytes()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("file not found: %s", path)
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, path)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -95,10 +105,6 @@ func (c *Client) GetContent(ctx context.Context, path string) (*GitHubContent, e
 
 // CreateContent creates a new file in GitHub
 func (c *Client) CreateContent(ctx context.Context, path string, content []byte, message string) (*GitHubCommitResponse, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	file := GitHubFile{
 		Path:    path,
 		Content: base64.StdEncoding.EncodeToString(content),
@@ -115,22 +121,24 @@ func (c *Client) CreateContent(ctx context.Context, path string, content []byte,
 
 // UpdateContent updates an existing file in GitHub
 func (c *Client) UpdateContent(ctx context.Context, path string, content []byte, message string) (*GitHubCommitResponse, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	// Get the current file to get its SHA
 	existingContent, err := c.GetContent(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get existing content: %w", err)
 	}
 
+	return c.ReplaceContent(ctx, c.config.Branch, path, content, existingContent.SHA, message)
+}
+
+// ReplaceContent replaces the version of a file with the SHA on a branch, GitHub refuses the update when
+// the file changed since
+func (c *Client) ReplaceContent(ctx context.Context, branch, path string, content []byte, sha, message string) (*GitHubCommitResponse, error) {
 	file := GitHubFile{
 		Path:    path,
 		Content: base64.StdEncoding.EncodeToString(content),
 		Message: message,
-		Branch:  c.config.Branch,
-		SHA:     existingContent.SHA,
+		Branch:  branch,
+		SHA:     sha,
 		Committer: &GitHubCommitter{
 			Name:  c.config.CommitterName,
 			Email: c.config.CommitterEmail,
@@ -142,10 +150,6 @@ func (c *Client) UpdateContent(ctx context.Context, path string, content []byte,
 
 // DeleteContent deletes a file from GitHub
 func (c *Client) DeleteContent(ctx context.Context, path string, message string) (*GitHubCommitResponse, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	// Get the current file to get its SHA
 	existingContent, err := c.GetContent(ctx, path)
 	if err != nil {
@@ -183,10 +187,12 @@ func (c *Client) DeleteContent(ctx context.Context, path string, message string)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	buf, err := transport.ReadBody(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer transport.PutBuffer(buf)
+	body := buf.Bytes()
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
@@ -202,10 +208,6 @@ func (c *Client) DeleteContent(ctx context.Context, path string, message string)
 
 // ListContents lists files and directories in a path
 func (c *Client) ListContents(ctx context.Context, path string) ([]GitHubContentListItem, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	fullPath := c.buildContentPath(path)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath, nil)
 	if err != nil {
@@ -223,10 +225,12 @@ func (c *Client) ListContents(ctx context.Context, path string) ([]GitHubContent
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	buf, err := transport.ReadBody(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer transport.PutBuffer(buf)
+	body := buf.Bytes()
 
 	if resp.StatusCode == http.StatusNotFound {
 		return []GitHubContentListItem{}, nil
@@ -251,10 +255,6 @@ func (c *Client) ListContents(ctx context.Context, path string) ([]GitHubContent
 
 // CreateDirectory creates an empty directory by creating a .gitkeep file
 func (c *Client) CreateDirectory(ctx context.Context, path string) error {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	// GitHub doesn't natively support empty directories, so we create a .gitkeep file
 	gitkeepPath := filepath.Join(path, ".gitkeep")
 	_, err := c.CreateContent(ctx, gitkeepPath, []byte{}, "Create directory "+path)
@@ -287,10 +287,12 @@ func (c *Client) putContent(ctx context.Context, path string, file GitHubFile) (
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	buf, err := transport.ReadBody(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer transport.PutBuffer(buf)
+	body := buf.Bytes()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
@@ -330,13 +332,23 @@ func (c *Client) ensureDirectoryExists(ctx context.Context, path string) error {
 	return nil
 }
 
-// buildContentPath builds the full URL path for content operations
-func (c *Client) buildContentPath(path string) string {
+// repoPath returns the path of a document relative to the repository root
+func (c *Client) repoPath(path string) string {
 	contentPath := strings.TrimPrefix(path, "/")
 	if c.config.BasePath != "" {
 		contentPath = filepath.Join(c.config.BasePath, contentPath)
 	}
-	return fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.apiBaseURL, c.config.Owner, c.config.Repo, url.PathEscape(contentPath))
+	return contentPath
+}
+
+// buildContentPath builds the full URL path for content operations
+func (c *Client) buildContentPath(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.apiBaseURL, c.config.Owner, c.config.Repo, url.PathEscape(c.repoPath(path)))
+}
+
+// buildHTMLURL builds the URL of a file in the GitHub web interface
+func (c *Client) buildHTMLURL(path string) string {
+	return fmt.Sprintf("%s/%s/%s/blob/%s/%s", GitHubWebBaseURL, c.config.Owner, c.config.Repo, c.config.Branch, c.repoPath(path))
 }
 
 // addAuthHeader adds the Authorization header to the request
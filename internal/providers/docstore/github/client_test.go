@@ -56,6 +56,21 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_BaseURL(t *testing.T) {
+	client, err := NewClient(&Config{
+		Token:          "token123",
+		Owner:          "owner",
+		Repo:           "repo",
+		Branch:         "main",
+		CommitterName:  "Test User",
+		CommitterEmail: "test@example.com",
+		BaseURL:        "https://github.example.com/api/v3/",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/api/v3", client.apiBaseURL)
+}
+
 func TestClient_buildContentPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -103,4 +118,45 @@ func TestClient_buildContentPath(t *testing.T) {
 			assert.Equal(t, tt.expected, path)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestClient_buildHTMLURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		path     string
+		expected string
+	}{
+		{
+			name: "with base path",
+			config: &Config{
+				Owner:    "owner",
+				Repo:     "repo",
+				Branch:   "main",
+				BasePath: "docs",
+			},
+			path:     "folder/file.md",
+			expected: "https://github.com/owner/repo/blob/main/docs/folder/file.md",
+		},
+		{
+			name: "with leading slash in path",
+			config: &Config{
+				Owner:  "owner",
+				Repo:   "repo",
+				Branch: "develop",
+			},
+			path:     "/folder/file.md",
+			expected: "https://github.com/owner/repo/blob/develop/folder/file.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				config:     tt.config,
+				apiBaseURL: GitHubAPIBaseURL,
+			}
+			assert.Equal(t, tt.expected, client.buildHTMLURL(tt.path))
+		})
+	}
+}
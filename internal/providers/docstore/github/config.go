@@ -2,6 +2,7 @@ package github
 
 import (
 	"errors"
+	"github.com/massimo-ua/quill/internal/providers/transport"
 	"strings"
 )
 
@@ -20,6 +21,19 @@ type Config struct {
 	// Committer information
 	CommitterName  string
 	CommitterEmail string
+	// BaseURL is the API endpoint, e.g. https://github.example.com/api/v3 for GitHub Enterprise (optional, defaults to GitHubAPIBaseURL)
+	BaseURL string
+	// SelfHosted marks a GitHub Enterprise Server run by the deployment itself, local-only projects may store
+	// their documents in it
+	SelfHosted bool
+	// LFSThreshold is the size in bytes from which files, like large images, are uploaded to Git LFS and
+	// committed as pointers (optional, 0 commits every file to the repository)
+	LFSThreshold int
+	// WebhookSecret verifies the signature of the push webhooks the repository sends (optional, required by
+	// the webhook handler)
+	WebhookSecret string
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
 }
 
 var (
@@ -28,6 +42,8 @@ var (
 	ErrMissingRepo          = errors.New("repository name is required")
 	ErrMissingCommitterName = errors.New("committer name is required")
 	ErrMissingCommitterEmail = errors.New("committer email is required")
+	ErrInvalidLFSThreshold   = errors.New("LFS threshold cannot be negative")
+	ErrMissingWebhookSecret  = errors.New("webhook secret is required")
 )
 
 // Validate checks if the configuration is valid
@@ -48,6 +64,10 @@ func (c *Config) Validate() error {
 		return ErrMissingCommitterEmail
 	}
 
+	if c.LFSThreshold < 0 {
+		return ErrInvalidLFSThreshold
+	}
+
 	// Set default branch if not specified
 	if strings.TrimSpace(c.Branch) == "" {
 		c.Branch = "main"
@@ -56,5 +76,12 @@ func (c *Config) Validate() error {
 	// Clean up base path
 	c.BasePath = strings.Trim(strings.TrimSpace(c.BasePath), "/")
 
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
\ No newline at end of file
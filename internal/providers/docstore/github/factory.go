@@ -3,6 +3,7 @@ package github
 import (
 	"fmt"
 	"github.com/massimo-ua/quill/internal/domain/ports"
+	"strings"
 )
 
 // NewGitHubDocumentStoreProvider creates a new DocumentStoreProvider backed by GitHub
@@ -21,4 +22,39 @@ func NewGitHubDocumentStoreProvider(config *Config) (ports.DocumentStoreProvider
 	}
 
 	return NewDocumentStoreProvider(client), nil
-}
\ No newline at end of file
+}
+
+// DocumentStoreFactory creates GitHub document stores for other repositories with the same credentials
+type DocumentStoreFactory struct {
+	config Config
+}
+
+// NewDocumentStoreFactory creates a DocumentStoreFactory based on the default repository configuration
+func NewDocumentStoreFactory(config *Config) (*DocumentStoreFactory, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &DocumentStoreFactory{config: *config}, nil
+}
+
+// ForRepository implements the ports.DocumentStoreFactory interface
+func (f *DocumentStoreFactory) ForRepository(repository, branch string) (ports.DocumentStoreProvider, error) {
+	owner, repo, ok := strings.Cut(strings.TrimSpace(repository), "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("invalid repository %q: must be owner/name", repository)
+	}
+
+	config := f.config
+	config.Owner = owner
+	config.Repo = repo
+	if strings.TrimSpace(branch) != "" {
+		config.Branch = strings.TrimSpace(branch)
+	}
+
+	return NewGitHubDocumentStoreProvider(&config)
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentStoreFactory_ForRepository(t *testing.T) {
+	factory, err := NewDocumentStoreFactory(&Config{
+		Token:          "token123",
+		Owner:          "owner",
+		Repo:           "repo",
+		Branch:         "main",
+		BasePath:       "docs",
+		CommitterName:  "Test User",
+		CommitterEmail: "test@example.com",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		repository string
+		branch     string
+		wantOwner  string
+		wantRepo   string
+		wantBranch string
+		wantErr    bool
+	}{
+		{
+			name:       "default branch",
+			repository: "team/handbook",
+			wantOwner:  "team",
+			wantRepo:   "handbook",
+			wantBranch: "main",
+		},
+		{
+			name:       "branch override",
+			repository: " team/handbook ",
+			branch:     "docs",
+			wantOwner:  "team",
+			wantRepo:   "handbook",
+			wantBranch: "docs",
+		},
+		{
+			name:       "missing owner",
+			repository: "handbook",
+			wantErr:    true,
+		},
+		{
+			name:       "nested path",
+			repository: "team/handbook/docs",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := factory.ForRepository(tt.repository, tt.branch)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			config := store.(*DocumentStoreProvider).client.config
+			assert.Equal(t, tt.wantOwner, config.Owner)
+			assert.Equal(t, tt.wantRepo, config.Repo)
+			assert.Equal(t, tt.wantBranch, config.Branch)
+			assert.Equal(t, "docs", config.BasePath)
+			assert.Equal(t, "token123", config.Token)
+		})
+	}
+
+	assert.Equal(t, "owner", factory.config.Owner, "the default configuration is left untouched")
+}
+
+func TestDocumentStoreFactory_ForRepositoryKeepsLocation(t *testing.T) {
+	config := &Config{
+		Token:          "token123",
+		Owner:          "owner",
+		Repo:           "repo",
+		CommitterName:  "Test User",
+		CommitterEmail: "test@example.com",
+		BaseURL:        "https://github.example.com/api/v3",
+	}
+	factory, err := NewDocumentStoreFactory(config)
+	require.NoError(t, err)
+
+	store, err := factory.ForRepository("team/handbook", "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DataLocationCloud, store.(*DocumentStoreProvider).DataLocation())
+
+	config.SelfHosted = true
+	factory, err = NewDocumentStoreFactory(config)
+	require.NoError(t, err)
+
+	store, err = factory.ForRepository("team/handbook", "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DataLocationLocal, store.(*DocumentStoreProvider).DataLocation())
+}
@@ -0,0 +1,181 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+// ErrEmptyRepository indicates that the repository has no commits yet
+var ErrEmptyRepository = errors.New("repository is empty")
+
+// CommitFiles writes all files to the configured branch in a single commit.
+// GitHub's Git Data API does not work on repositories without commits, so an empty
+// repository first gets its README through the contents API. Files at or above the
+// LFS threshold are uploaded to Git LFS and committed as pointers.
+func (c *Client) CommitFiles(ctx context.Context, files map[string][]byte, message string) (*GitHubObject, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files to commit")
+	}
+	files, err := c.withLFS(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	head, err := c.getBranchHead(ctx)
+	if errors.Is(err, ErrEmptyRepository) {
+		first := paths[0]
+		if _, ok := files["README.md"]; ok {
+			first = "README.md"
+		}
+		if _, err := c.CreateContent(ctx, first, files[first], message); err != nil {
+			return nil, fmt.Errorf("failed to initialize repository: %w", err)
+		}
+		paths = without(paths, first)
+		if len(paths) == 0 {
+			return c.getBranchHead(ctx)
+		}
+		head, err = c.getBranchHead(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var parent GitHubGitCommit
+	if _, err := c.doJSON(ctx, http.MethodGet, c.buildGitPath("commits/"+head.SHA), nil, &parent); err != nil {
+		return nil, fmt.Errorf("failed to get head commit: %w", err)
+	}
+
+	entries := make([]GitHubTreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entry := GitHubTreeEntry{Path: c.repoPath(path), Mode: "100644", Type: "blob"}
+		// Tree contents must be text, binary files like images are uploaded as blobs first
+		if utf8.Valid(files[path]) {
+			entry.Content = string(files[path])
+		} else if entry.SHA, err = c.createBlob(ctx, files[path]); err != nil {
+			return nil, fmt.Errorf("failed to create blob of %s: %w", path, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	var tree GitHubObject
+	if _, err := c.doJSON(ctx, http.MethodPost, c.buildGitPath("trees"), GitHubCreateTree{BaseTree: parent.Tree.SHA, Tree: entries}, &tree); err != nil {
+		return nil, fmt.Errorf("failed to create tree: %w", err)
+	}
+
+	committer := &GitHubCommitter{Name: c.config.CommitterName, Email: c.config.CommitterEmail}
+	var commit GitHubObject
+	if _, err := c.doJSON(ctx, http.MethodPost, c.buildGitPath("commits"), GitHubCreateCommit{
+		Message:   message,
+		Tree:      tree.SHA,
+		Parents:   []string{head.SHA},
+		Committer: committer,
+		Author:    committer,
+	}, &commit); err != nil {
+		return nil, fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	if _, err := c.doJSON(ctx, http.MethodPatch, c.buildGitPath("refs/heads/"+c.config.Branch), GitHubUpdateRef{SHA: commit.SHA}, nil); err != nil {
+		return nil, fmt.Errorf("failed to update branch %s: %w", c.config.Branch, err)
+	}
+
+	return &commit, nil
+}
+
+// createBlob uploads the content of a file and returns the SHA of its blob
+func (c *Client) createBlob(ctx context.Context, content []byte) (string, error) {
+	var blob GitHubObject
+	request := GitHubCreateBlob{Content: base64.StdEncoding.EncodeToString(content), Encoding: "base64"}
+	if _, err := c.doJSON(ctx, http.MethodPost, c.buildGitPath("blobs"), request, &blob); err != nil {
+		return "", err
+	}
+	return blob.SHA, nil
+}
+
+// getBranchHead returns the commit the configured branch points to
+func (c *Client) getBranchHead(ctx context.Context) (*GitHubObject, error) {
+	var ref GitHubRef
+	status, err := c.doJSON(ctx, http.MethodGet, c.buildGitPath("ref/heads/"+c.config.Branch), nil, &ref)
+	if status == http.StatusConflict {
+		return nil, ErrEmptyRepository
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch %s: %w", c.config.Branch, err)
+	}
+	return &GitHubObject{SHA: ref.Object.SHA}, nil
+}
+
+// doJSON sends a JSON request to the GitHub API and decodes the JSON response into out
+func (c *Client) doJSON(ctx context.Context, method, url string, in, out interface{}) (int, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.addAuthHeader(req)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+	respBody := buf.Bytes()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// buildGitPath builds the full URL for Git Data API operations
+func (c *Client) buildGitPath(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/git/%s", c.apiBaseURL, c.config.Owner, c.config.Repo, path)
+}
+
+func without(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
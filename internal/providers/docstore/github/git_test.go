@@ -0,0 +1,214 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves the parts of the GitHub API used to bootstrap a repository
+type fakeGitHub struct {
+	empty    bool
+	requests []string
+	tree     GitHubCreateTree
+	commit   GitHubCreateCommit
+	ref      GitHubUpdateRef
+	created  []string
+	blobs    []GitHubCreateBlob
+	// lfs records the objects uploaded to Git LFS by their oid, the ones stored before need no upload
+	lfs        map[string][]byte
+	attributes string
+	// blob is the only blob that can be read, branch, file and pull record the requests proposing a change
+	blob   GitHubBlob
+	branch GitHubCreateRef
+	file   GitHubFile
+	pull   GitHubCreatePullRequest
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/git/ref/heads/main":
+		if f.empty {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"Git Repository is empty."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"head-sha","type":"commit"}}`))
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/lfs/"):
+		if r.Header.Get("Authorization") != "RemoteAuth lfs-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		f.lfs[strings.TrimPrefix(r.URL.Path, "/lfs/")] = content
+	case r.Method == http.MethodPut && len(r.URL.Path) > len("/repos/owner/repo/contents/"):
+		_ = json.NewDecoder(r.Body).Decode(&f.file)
+		f.created = append(f.created, r.URL.Path[len("/repos/owner/repo/contents/"):])
+		f.empty = false
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"commit":{"sha":"init-sha"}}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/git/commits/head-sha":
+		_, _ = w.Write([]byte(`{"sha":"head-sha","tree":{"sha":"base-tree"}}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/git/trees":
+		_ = json.NewDecoder(r.Body).Decode(&f.tree)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sha":"new-tree"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/git/commits":
+		_ = json.NewDecoder(r.Body).Decode(&f.commit)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sha":"new-commit"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/git/blobs":
+		var blob GitHubCreateBlob
+		_ = json.NewDecoder(r.Body).Decode(&blob)
+		f.blobs = append(f.blobs, blob)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"sha":"blob-%d"}`, len(f.blobs))
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/.gitattributes"):
+		if f.attributes == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"content":%q}`, base64.StdEncoding.EncodeToString([]byte(f.attributes)))
+	case r.Method == http.MethodPost && r.URL.Path == "/owner/repo.git/info/lfs/objects/batch":
+		var batch lfsBatchRequest
+		_ = json.NewDecoder(r.Body).Decode(&batch)
+		object := batch.Objects[0]
+		response := map[string]interface{}{"oid": object.OID, "size": object.Size}
+		if _, ok := f.lfs[object.OID]; !ok {
+			response["actions"] = map[string]interface{}{
+				"upload": map[string]interface{}{"href": "http://" + r.Host + "/lfs/" + object.OID, "header": map[string]string{"Authorization": "RemoteAuth lfs-token"}},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"objects": []interface{}{response}})
+	case r.Method == http.MethodGet && f.blob.SHA != "" && r.URL.Path == "/repos/owner/repo/git/blobs/"+f.blob.SHA:
+		_ = json.NewEncoder(w).Encode(f.blob)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/git/refs":
+		_ = json.NewDecoder(r.Body).Decode(&f.branch)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/pulls":
+		_ = json.NewDecoder(r.Body).Decode(&f.pull)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":7,"html_url":"https://github.com/owner/repo/pull/7"}`))
+	case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/git/refs/heads/main":
+		_ = json.NewDecoder(r.Body).Decode(&f.ref)
+		_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"new-commit"}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeGitHubClient(t *testing.T, fake *fakeGitHub, basePath string) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(&Config{
+		Token:          "token",
+		Owner:          "owner",
+		Repo:           "repo",
+		BasePath:       basePath,
+		CommitterName:  "Quill",
+		CommitterEmail: "quill@example.com",
+	})
+	require.NoError(t, err)
+	client.apiBaseURL = server.URL
+	return client
+}
+
+// treeEntry returns the entry of the created tree with a path
+func (f *fakeGitHub) treeEntry(path string) (GitHubTreeEntry, bool) {
+	for _, entry := range f.tree.Tree {
+		if entry.Path == path {
+			return entry, true
+		}
+	}
+	return GitHubTreeEntry{}, false
+}
+
+func TestClient_CommitFiles(t *testing.T) {
+	fake := &fakeGitHub{}
+	client := newFakeGitHubClient(t, fake, "docs")
+
+	commit, err := client.CommitFiles(context.Background(), map[string][]byte{
+		"README.md":               []byte("# Billing"),
+		"docs/decisions/.gitkeep": {},
+	}, "Set up documentation")
+
+	require.NoError(t, err)
+	assert.Equal(t, "new-commit", commit.SHA)
+	assert.Equal(t, "base-tree", fake.tree.BaseTree)
+	require.Len(t, fake.tree.Tree, 2)
+	assert.Equal(t, "docs/README.md", fake.tree.Tree[0].Path)
+	assert.Equal(t, "# Billing", fake.tree.Tree[0].Content)
+	assert.Equal(t, "docs/docs/decisions/.gitkeep", fake.tree.Tree[1].Path)
+	assert.Equal(t, "new-tree", fake.commit.Tree)
+	assert.Equal(t, []string{"head-sha"}, fake.commit.Parents)
+	assert.Equal(t, "Quill", fake.commit.Committer.Name)
+	assert.Equal(t, "new-commit", fake.ref.SHA)
+	assert.Empty(t, fake.created)
+}
+
+func TestClient_CommitFilesToEmptyRepository(t *testing.T) {
+	fake := &fakeGitHub{empty: true}
+	client := newFakeGitHubClient(t, fake, "")
+
+	_, err := client.CommitFiles(context.Background(), map[string][]byte{
+		"README.md":       []byte("# Billing"),
+		"CONTRIBUTING.md": []byte("# Contributing"),
+	}, "Set up documentation")
+
+	require.NoError(t, err)
+	// GitHub cannot create trees without a first commit, so the README goes first
+	assert.Equal(t, []string{"README.md"}, fake.created)
+	require.Len(t, fake.tree.Tree, 1)
+	assert.Equal(t, "CONTRIBUTING.md", fake.tree.Tree[0].Path)
+}
+
+func TestDocumentStoreProvider_Bootstrap(t *testing.T) {
+	fake := &fakeGitHub{}
+	provider := NewDocumentStoreProvider(newFakeGitHubClient(t, fake, "docs"))
+
+	needed, err := provider.NeedsBootstrap(context.Background())
+	require.NoError(t, err)
+	assert.True(t, needed)
+
+	require.NoError(t, provider.Bootstrap(context.Background(), map[string][]byte{"README.md": []byte("# Billing")}, "Set up documentation"))
+	assert.Equal(t, "Set up documentation", fake.commit.Message)
+
+	assert.Error(t, provider.Bootstrap(context.Background(), nil, "Set up documentation"))
+}
+
+func TestClient_CommitFilesUploadsBinaryBlobs(t *testing.T) {
+	fake := &fakeGitHub{}
+	client := newFakeGitHubClient(t, fake, "")
+	image := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+
+	_, err := client.CommitFiles(context.Background(), map[string][]byte{
+		"docs/assets/board.png": image,
+		"docs/decision.md":      []byte("# Decision"),
+	}, "Add decision")
+
+	require.NoError(t, err)
+	require.Len(t, fake.blobs, 1)
+	assert.Equal(t, GitHubCreateBlob{Content: base64.StdEncoding.EncodeToString(image), Encoding: "base64"}, fake.blobs[0])
+	entry, ok := fake.treeEntry("docs/assets/board.png")
+	require.True(t, ok)
+	assert.Equal(t, "blob-1", entry.SHA)
+	assert.Empty(t, entry.Content)
+	entry, ok = fake.treeEntry("docs/decision.md")
+	require.True(t, ok)
+	assert.Equal(t, "# Decision", entry.Content)
+	assert.Empty(t, entry.SHA)
+}
@@ -0,0 +1,241 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+const (
+	// lfsMediaType is the media type of the Git LFS batch API
+	lfsMediaType = "application/vnd.git-lfs+json"
+	// gitAttributesFile tracks the files stored with Git LFS, next to the documents
+	gitAttributesFile = ".gitattributes"
+	// lfsAttributes mark a file as stored with Git LFS in .gitattributes
+	lfsAttributes = "filter=lfs diff=lfs merge=lfs -text"
+)
+
+// lfsObject identifies a file in Git LFS by the SHA-256 of its contents
+type lfsObject struct {
+	OID  string `json:"oid"`
+	Size int    `json:"size"`
+}
+
+// lfsBatchRequest asks the Git LFS server where to upload objects
+type lfsBatchRequest struct {
+	Operation string      `json:"operation"`
+	Transfers []string    `json:"transfers"`
+	Objects   []lfsObject `json:"objects"`
+}
+
+// lfsAction is a request the Git LFS server asks the client to make
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+// lfsBatchResponse tells where to upload each object, objects the server already has come without actions
+type lfsBatchResponse struct {
+	Objects []struct {
+		lfsObject
+		Actions map[string]lfsAction `json:"actions,omitempty"`
+		Error   *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error,omitempty"`
+	} `json:"objects"`
+}
+
+// usesLFS checks if a file is large enough to be stored with Git LFS
+func (c *Client) usesLFS(content []byte) bool {
+	return c.config.LFSThreshold > 0 && len(content) >= c.config.LFSThreshold
+}
+
+// withLFS uploads the files at or above the LFS threshold to Git LFS and returns the files to commit, with
+// pointers in their place and .gitattributes tracking them
+func (c *Client) withLFS(ctx context.Context, files map[string][]byte) (map[string][]byte, error) {
+	var large []string
+	for path, content := range files {
+		if path != gitAttributesFile && c.usesLFS(content) {
+			large = append(large, path)
+		}
+	}
+	if len(large) == 0 {
+		return files, nil
+	}
+	sort.Strings(large)
+
+	committed := make(map[string][]byte, len(files)+1)
+	for path, content := range files {
+		committed[path] = content
+	}
+	for _, path := range large {
+		pointer, err := c.uploadLFS(ctx, files[path])
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload %s to Git LFS: %w", path, err)
+		}
+		committed[path] = pointer
+	}
+
+	attributes, ok := files[gitAttributesFile]
+	if !ok {
+		existing, err := c.GetContent(ctx, gitAttributesFile)
+		switch {
+		case errors.Is(err, ErrFileNotFound):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read %s: %w", gitAttributesFile, err)
+		default:
+			if attributes, err = base64.StdEncoding.DecodeString(existing.Content); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", gitAttributesFile, err)
+			}
+		}
+	}
+	if tracked := trackLFS(attributes, large); !bytes.Equal(tracked, attributes) {
+		committed[gitAttributesFile] = tracked
+	}
+	return committed, nil
+}
+
+// uploadLFS uploads a file to the repository's Git LFS storage and returns the pointer committed in its place
+func (c *Client) uploadLFS(ctx context.Context, content []byte) ([]byte, error) {
+	sum := sha256.Sum256(content)
+	object := lfsObject{OID: hex.EncodeToString(sum[:]), Size: len(content)}
+
+	var batch lfsBatchResponse
+	request := lfsBatchRequest{Operation: "upload", Transfers: []string{"basic"}, Objects: []lfsObject{object}}
+	if err := c.doLFS(ctx, http.MethodPost, c.lfsURL()+"/objects/batch", nil, request, &batch); err != nil {
+		return nil, fmt.Errorf("failed to request upload: %w", err)
+	}
+	if len(batch.Objects) != 1 {
+		return nil, fmt.Errorf("expected 1 object in the batch response, got %d", len(batch.Objects))
+	}
+	result := batch.Objects[0]
+	if result.Error != nil {
+		return nil, fmt.Errorf("upload refused: %d %s", result.Error.Code, result.Error.Message)
+	}
+
+	// Objects the server already stores come without an upload action
+	if upload, ok := result.Actions["upload"]; ok {
+		if err := c.putLFS(ctx, upload, content); err != nil {
+			return nil, err
+		}
+		if verify, ok := result.Actions["verify"]; ok {
+			if err := c.doLFS(ctx, http.MethodPost, verify.Href, verify.Header, object, nil); err != nil {
+				return nil, fmt.Errorf("failed to verify upload: %w", err)
+			}
+		}
+	}
+	return lfsPointer(object), nil
+}
+
+// putLFS uploads the content of an object where the batch API said to
+func (c *Client) putLFS(ctx context.Context, upload lfsAction, content []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, upload.Href, bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	for name, value := range upload.Header {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to upload object: unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// doLFS sends a request to the Git LFS API, authenticated with the token unless the action brings its own headers
+func (c *Client) doLFS(ctx context.Context, method, url string, header map[string]string, in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	if len(header) == 0 {
+		req.SetBasicAuth("x-access-token", c.config.Token)
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+	if out != nil {
+		if err := json.Unmarshal(buf.Bytes(), out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// lfsURL returns the Git LFS endpoint of the repository, served next to its web interface
+func (c *Client) lfsURL() string {
+	webURL := GitHubWebBaseURL
+	if c.apiBaseURL != GitHubAPIBaseURL {
+		// GitHub Enterprise serves its API under /api/v3 of the web host
+		webURL = strings.TrimSuffix(c.apiBaseURL, "/api/v3")
+	}
+	return fmt.Sprintf("%s/%s/%s.git/info/lfs", webURL, c.config.Owner, c.config.Repo)
+}
+
+// lfsPointer returns the pointer file committed in place of an object stored with Git LFS
+func lfsPointer(object lfsObject) []byte {
+	return []byte(fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", object.OID, object.Size))
+}
+
+// trackLFS adds the paths missing from .gitattributes, anchored to its directory
+func trackLFS(attributes []byte, paths []string) []byte {
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(attributes), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			existing[fields[0]] = true
+		}
+	}
+
+	tracked := string(attributes)
+	for _, path := range paths {
+		pattern := "/" + strings.TrimPrefix(path, "/")
+		if existing[pattern] {
+			continue
+		}
+		if tracked != "" && !strings.HasSuffix(tracked, "\n") {
+			tracked += "\n"
+		}
+		tracked += pattern + " " + lfsAttributes + "\n"
+	}
+	return []byte(tracked)
+}
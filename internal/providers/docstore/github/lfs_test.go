@@ -0,0 +1,82 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CommitFilesStoresLargeFilesWithLFS(t *testing.T) {
+	fake := &fakeGitHub{lfs: make(map[string][]byte), attributes: "*.md text\n"}
+	client := newFakeGitHubClient(t, fake, "docs")
+	client.config.LFSThreshold = 1024
+	photo := []byte(strings.Repeat("photo", 512))
+	sum := sha256.Sum256(photo)
+	oid := hex.EncodeToString(sum[:])
+
+	_, err := client.CommitFiles(context.Background(), map[string][]byte{
+		"assets/photo.jpg": photo,
+		"decision.md":      []byte("# Decision"),
+	}, "Add decision")
+
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(photo, fake.lfs[oid]), "the photo was not uploaded")
+	entry, ok := fake.treeEntry("docs/assets/photo.jpg")
+	require.True(t, ok)
+	assert.Equal(t, "version https://git-lfs.github.com/spec/v1\noid sha256:"+oid+"\nsize 2560\n", entry.Content)
+	entry, ok = fake.treeEntry("docs/decision.md")
+	require.True(t, ok)
+	assert.Equal(t, "# Decision", entry.Content)
+	// Attributes sit next to the documents and keep the earlier lines
+	entry, ok = fake.treeEntry("docs/.gitattributes")
+	require.True(t, ok)
+	assert.Equal(t, "*.md text\n/assets/photo.jpg filter=lfs diff=lfs merge=lfs -text\n", entry.Content)
+}
+
+func TestClient_CommitFilesSkipsStoredLFSObjects(t *testing.T) {
+	photo := []byte(strings.Repeat("photo", 512))
+	sum := sha256.Sum256(photo)
+	oid := hex.EncodeToString(sum[:])
+	fake := &fakeGitHub{lfs: map[string][]byte{oid: photo}, attributes: "/assets/photo.jpg filter=lfs diff=lfs merge=lfs -text\n"}
+	client := newFakeGitHubClient(t, fake, "")
+	client.config.LFSThreshold = 1024
+
+	_, err := client.CommitFiles(context.Background(), map[string][]byte{"assets/photo.jpg": photo}, "Add photo")
+
+	require.NoError(t, err)
+	for _, request := range fake.requests {
+		assert.False(t, strings.HasPrefix(request, "PUT /lfs/"), request)
+	}
+	// The file is tracked already, so the attributes are left alone
+	require.Len(t, fake.tree.Tree, 1)
+	assert.Equal(t, "assets/photo.jpg", fake.tree.Tree[0].Path)
+}
+
+func TestClient_CommitFilesBelowLFSThreshold(t *testing.T) {
+	fake := &fakeGitHub{lfs: make(map[string][]byte)}
+	client := newFakeGitHubClient(t, fake, "")
+	client.config.LFSThreshold = 1024
+
+	_, err := client.CommitFiles(context.Background(), map[string][]byte{"decision.md": []byte("# Decision")}, "Add decision")
+
+	require.NoError(t, err)
+	assert.Empty(t, fake.lfs)
+	require.Len(t, fake.tree.Tree, 1)
+	assert.Equal(t, "# Decision", fake.tree.Tree[0].Content)
+}
+
+func TestClient_LFSURL(t *testing.T) {
+	client, err := NewClient(&Config{Token: "token", Owner: "acme", Repo: "docs", CommitterName: "Quill", CommitterEmail: "quill@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/docs.git/info/lfs", client.lfsURL())
+
+	client, err = NewClient(&Config{Token: "token", Owner: "acme", Repo: "docs", CommitterName: "Quill", CommitterEmail: "quill@example.com", BaseURL: "https://github.example.com/api/v3/"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/acme/docs.git/info/lfs", client.lfsURL())
+}
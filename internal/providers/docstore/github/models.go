@@ -1,5 +1,7 @@
 package github
 
+import "encoding/json"
+
 // GitHubContent represents the content of a file in a GitHub repository
 type GitHubContent struct {
 	// Type is the type of content ("file", "dir", "symlink", "submodule")
@@ -88,4 +90,157 @@ type GitHubContentListItem struct {
 	GitURL string `json:"git_url"`
 	// HTMLURL is the URL to the file in the GitHub web interface
 	HTMLURL string `json:"html_url"`
-}
\ No newline at end of file
+}
+// GitHubRef represents a Git reference such as a branch head
+type GitHubRef struct {
+	// Ref is the full name of the reference, e.g. refs/heads/main
+	Ref string `json:"ref"`
+	// Object is the object the reference points to
+	Object struct {
+		SHA  string `json:"sha"`
+		Type string `json:"type"`
+	} `json:"object"`
+}
+
+// GitHubGitCommit represents a commit in the Git Data API
+type GitHubGitCommit struct {
+	// SHA is the SHA of the commit
+	SHA string `json:"sha"`
+	// Tree is the tree the commit points to
+	Tree struct {
+		SHA string `json:"sha"`
+	} `json:"tree"`
+}
+
+// GitHubTreeEntry represents a file in a tree to be created
+type GitHubTreeEntry struct {
+	// Path is the path of the file in the repository
+	Path string `json:"path"`
+	// Mode is the file mode, 100644 for regular files
+	Mode string `json:"mode"`
+	// Type is the object type, "blob" for files
+	Type string `json:"type"`
+	// Content is the file content, GitHub creates the blob
+	Content string `json:"content"`
+	// SHA is the blob of the file, set instead of the content for binary files
+	SHA string `json:"sha,omitempty"`
+}
+
+// MarshalJSON leaves the content out of entries pointing to a blob, GitHub accepts only one of them
+func (e GitHubTreeEntry) MarshalJSON() ([]byte, error) {
+	if e.SHA == "" {
+		type entry GitHubTreeEntry
+		return json.Marshal(entry(e))
+	}
+	return json.Marshal(struct {
+		Path string `json:"path"`
+		Mode string `json:"mode"`
+		Type string `json:"type"`
+		SHA  string `json:"sha"`
+	}{e.Path, e.Mode, e.Type, e.SHA})
+}
+
+// GitHubCreateBlob is the request to create a blob
+type GitHubCreateBlob struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// GitHubCreateTree is the request to create a tree on top of a base tree
+type GitHubCreateTree struct {
+	BaseTree string            `json:"base_tree,omitempty"`
+	Tree     []GitHubTreeEntry `json:"tree"`
+}
+
+// GitHubCreateCommit is the request to create a commit
+type GitHubCreateCommit struct {
+	Message   string           `json:"message"`
+	Tree      string           `json:"tree"`
+	Parents   []string         `json:"parents"`
+	Committer *GitHubCommitter `json:"committer,omitempty"`
+	Author    *GitHubCommitter `json:"author,omitempty"`
+}
+
+// GitHubUpdateRef is the request to move a reference to another commit
+type GitHubUpdateRef struct {
+	SHA   string `json:"sha"`
+	Force bool   `json:"force"`
+}
+
+// GitHubObject represents an object created through the Git Data API
+type GitHubObject struct {
+	// SHA is the SHA of the created object
+	SHA string `json:"sha"`
+}
+
+// GitHubBlob represents a blob read from the Git Data API
+type GitHubBlob struct {
+	// SHA is the SHA of the blob
+	SHA string `json:"sha"`
+	// Content is the content of the blob, in the encoding
+	Content string `json:"content"`
+	// Encoding is the encoding of the content, "base64" or "utf-8"
+	Encoding string `json:"encoding"`
+}
+
+// GitHubCreateRef represents a request to create a Git reference such as a branch
+type GitHubCreateRef struct {
+	// Ref is the full name of the reference, e.g. refs/heads/feature
+	Ref string `json:"ref"`
+	// SHA is the commit the reference points to
+	SHA string `json:"sha"`
+}
+
+// GitHubCreatePullRequest represents a request to open a pull request
+type GitHubCreatePullRequest struct {
+	// Title is the title of the pull request
+	Title string `json:"title"`
+	// Head is the branch with the changes
+	Head string `json:"head"`
+	// Base is the branch the changes are proposed for
+	Base string `json:"base"`
+	// Body is the description of the pull request
+	Body string `json:"body"`
+}
+
+// GitHubPullRequest represents a pull request
+type GitHubPullRequest struct {
+	// Number is the number of the pull request in the repository
+	Number int `json:"number"`
+	// HTMLURL is the URL to the pull request in the GitHub web interface
+	HTMLURL string `json:"html_url"`
+}
+
+// GitHubPushEvent is the payload of a push webhook
+type GitHubPushEvent struct {
+	// Ref is the full name of the pushed reference, e.g. refs/heads/main
+	Ref string `json:"ref"`
+	// Deleted tells that the push deleted the reference
+	Deleted bool `json:"deleted"`
+	// Repository is the repository pushed to
+	Repository GitHubPushRepository `json:"repository"`
+	// Commits are the pushed commits, oldest first
+	Commits []GitHubPushCommit `json:"commits"`
+}
+
+// GitHubPushRepository is the repository of a push webhook
+type GitHubPushRepository struct {
+	// FullName is the owner/name of the repository
+	FullName string `json:"full_name"`
+}
+
+// GitHubPushCommit is a commit of a push webhook, with the files it changed
+type GitHubPushCommit struct {
+	ID       string         `json:"id"`
+	Author   GitHubPushUser `json:"author"`
+	Added    []string       `json:"added"`
+	Modified []string       `json:"modified"`
+	Removed  []string       `json:"removed"`
+}
+
+// GitHubPushUser is the author of a pushed commit
+type GitHubPushUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
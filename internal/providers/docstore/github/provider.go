@@ -3,10 +3,15 @@ package github
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
 )
 
 // DocumentStoreProvider implements the domain.DocumentStoreProvider interface
@@ -25,6 +30,14 @@ func NewDocumentStoreProvider(client *Client) *DocumentStoreProvider {
 	}
 }
 
+// DataLocation reports where the repository lives, GitHub's cloud unless the server is self-hosted
+func (p *DocumentStoreProvider) DataLocation() domain.DataLocation {
+	if p.client.config.SelfHosted {
+		return domain.DataLocationLocal
+	}
+	return domain.DataLocationCloud
+}
+
 // StoreDocument implements the ports.DocumentStoreProvider.StoreDocument method
 // It stores a document in a GitHub repository
 func (p *DocumentStoreProvider) StoreDocument(ctx context.Context, path string, content []byte, metadata map[string]interface{}) error {
@@ -51,6 +64,11 @@ func (p *DocumentStoreProvider) StoreDocument(ctx context.Context, path string,
 		}
 	}
 
+	// Files stored with Git LFS are committed with .gitattributes tracking them
+	if p.client.usesLFS(content) {
+		return p.CommitFiles(ctx, map[string][]byte{path: content}, message)
+	}
+
 	// Create content
 	_, err := p.client.CreateContent(ctx, path, content, message)
 	if err != nil {
@@ -68,6 +86,9 @@ func (p *DocumentStoreProvider) GetDocument(ctx context.Context, path string) ([
 	}
 
 	content, err := p.client.GetContent(ctx, path)
+	if errors.Is(err, ErrFileNotFound) {
+		return nil, fmt.Errorf("failed to get document %s: %w", path, ports.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get document: %w", err)
 	}
@@ -100,6 +121,18 @@ func (p *DocumentStoreProvider) UpdateDocument(ctx context.Context, path string,
 		if timestamp, ok := metadata["updated_at"].(time.Time); ok {
 			message = fmt.Sprintf("%s at %s", message, timestamp.Format(time.RFC3339))
 		}
+		if summary, ok := metadata["diff_summary"].(string); ok {
+			message = fmt.Sprintf("%s\n\n%s", message, summary)
+		}
+	}
+
+	if p.client.usesLFS(content) {
+		return p.CommitFiles(ctx, map[string][]byte{path: content}, message)
+	}
+
+	// An update based on an older version is merged with the changes people made since
+	if base, ok := metadata["base_revision"].(string); ok && base != "" {
+		return p.mergeUpdate(ctx, path, content, base, message)
 	}
 
 	// Update content
@@ -111,6 +144,77 @@ func (p *DocumentStoreProvider) UpdateDocument(ctx context.Context, path string,
 	return nil
 }
 
+// GetDocumentRevision implements the ports.RevisionReader interface, the revision is the blob SHA of the document
+func (p *DocumentStoreProvider) GetDocumentRevision(ctx context.Context, path string) ([]byte, string, error) {
+	if ctx == nil {
+		return nil, "", fmt.Errorf("context cannot be nil")
+	}
+
+	content, err := p.client.GetContent(ctx, path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get document: %w", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(content.Content)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode content: %w", err)
+	}
+	return decoded, content.SHA, nil
+}
+
+// mergeUpdate writes an update made to the base version of a document. When people changed the document
+// since, the update is merged with their changes; when both changed the same lines, the merge is proposed
+// in a pull request and the document is left as people left it.
+func (p *DocumentStoreProvider) mergeUpdate(ctx context.Context, path string, content []byte, base, message string) error {
+	current, err := p.client.GetContent(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	if current.SHA == base {
+		if _, err := p.client.ReplaceContent(ctx, p.client.config.Branch, path, content, current.SHA, message); err != nil {
+			return fmt.Errorf("failed to update document: %w", err)
+		}
+		return nil
+	}
+
+	baseContent, err := p.client.GetBlob(ctx, base)
+	if err != nil {
+		return fmt.Errorf("failed to read the version %s of %s: %w", shortSHA(base), path, err)
+	}
+	edited, err := base64.StdEncoding.DecodeString(current.Content)
+	if err != nil {
+		return fmt.Errorf("failed to decode content: %w", err)
+	}
+	merge := domain.MergeDocuments(baseContent, content, edited)
+
+	if !merge.HasConflicts() {
+		message = fmt.Sprintf("%s\n\nMerged with the changes made since %s", message, shortSHA(base))
+		if _, err := p.client.ReplaceContent(ctx, p.client.config.Branch, path, merge.Content(), current.SHA, message); err != nil {
+			return fmt.Errorf("failed to update document: %w", err)
+		}
+		return nil
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	branch := fmt.Sprintf("quill/update-%s-%d", name, time.Now().UTC().Unix())
+	body := fmt.Sprintf("%s changed since the bot read it at %s, and %d of the changes conflict with this update. "+
+		"The conflicting lines are taken from the update, please check them before merging.",
+		path, shortSHA(base), merge.Conflicts())
+	pull, err := p.client.ProposeContent(ctx, branch, path, merge.Content(), current.SHA, message, body)
+	if err != nil {
+		return fmt.Errorf("failed to propose the update of %s: %w", path, err)
+	}
+	log.Printf("Proposed the update of %s in %s, it conflicts with changes made since %s", path, pull.HTMLURL, shortSHA(base))
+	return nil
+}
+
+// shortSHA abbreviates a SHA as Git does
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 // ListDocuments implements the ports.DocumentStoreProvider.ListDocuments method
 // It lists documents in a path in a GitHub repository
 func (p *DocumentStoreProvider) ListDocuments(ctx context.Context, path string) ([]string, error) {
@@ -134,6 +238,36 @@ func (p *DocumentStoreProvider) ListDocuments(ctx context.Context, path string)
 	return paths, nil
 }
 
+// WalkDocuments implements the ports.DocumentWalker interface
+// It lists documents in a path and, one request per directory, in all its subdirectories
+func (p *DocumentStoreProvider) WalkDocuments(ctx context.Context, root string) ([]string, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	var paths []string
+	dirs := []string{root}
+	for len(dirs) > 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+
+		items, err := p.client.ListContents(ctx, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents in %s: %w", dir, err)
+		}
+		for _, item := range items {
+			switch {
+			case item.Type == "dir":
+				dirs = append(dirs, item.Path)
+			case item.Type == "file" && !strings.HasSuffix(item.Name, ".gitkeep"):
+				paths = append(paths, item.Path)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
 // DeleteDocument implements the ports.DocumentStoreProvider.DeleteDocument method
 // It deletes a document from a GitHub repository
 func (p *DocumentStoreProvider) DeleteDocument(ctx context.Context, path string) error {
@@ -147,4 +281,46 @@ func (p *DocumentStoreProvider) DeleteDocument(ctx context.Context, path string)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// DocumentURL implements the ports.DocumentLinker interface
+// It returns the GitHub web URL of a document
+func (p *DocumentStoreProvider) DocumentURL(path string) string {
+	return p.client.buildHTMLURL(path)
+}
+
+// CommitFiles implements the ports.BatchCommitter interface
+// It creates or replaces all files in a single commit
+func (p *DocumentStoreProvider) CommitFiles(ctx context.Context, files map[string][]byte, message string) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	if _, err := p.client.CommitFiles(ctx, files, message); err != nil {
+		return fmt.Errorf("failed to commit documents: %w", err)
+	}
+	return nil
+}
+
+// NeedsBootstrap implements the ports.RepositoryBootstrapper interface
+// The repository needs scaffolding until it has a docs directory, empty repositories have none
+func (p *DocumentStoreProvider) NeedsBootstrap(ctx context.Context) (bool, error) {
+	items, err := p.client.ListContents(ctx, "docs")
+	if err != nil {
+		return false, fmt.Errorf("failed to check repository structure: %w", err)
+	}
+	return len(items) == 0, nil
+}
+
+// Bootstrap implements the ports.RepositoryBootstrapper interface
+// It writes the scaffold files in a single commit
+func (p *DocumentStoreProvider) Bootstrap(ctx context.Context, files map[string][]byte, message string) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	if _, err := p.client.CommitFiles(ctx, files, message); err != nil {
+		return fmt.Errorf("failed to bootstrap repository: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GetBlob returns the content of a blob, like a version of a file the bot read before
+func (c *Client) GetBlob(ctx context.Context, sha string) ([]byte, error) {
+	var blob GitHubBlob
+	if _, err := c.doJSON(ctx, http.MethodGet, c.buildGitPath("blobs/"+sha), nil, &blob); err != nil {
+		return nil, fmt.Errorf("failed to get blob %s: %w", sha, err)
+	}
+	if blob.Encoding != "base64" {
+		return []byte(blob.Content), nil
+	}
+	content, err := base64.StdEncoding.DecodeString(blob.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode blob %s: %w", sha, err)
+	}
+	return content, nil
+}
+
+// ProposeContent commits a new version of a file to a new branch, started from the configured branch, and
+// opens a pull request for it. The SHA is the version of the file the new version replaces.
+func (c *Client) ProposeContent(ctx context.Context, branch, path string, content []byte, sha, message, body string) (*GitHubPullRequest, error) {
+	head, err := c.getBranchHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.doJSON(ctx, http.MethodPost, c.buildGitPath("refs"), GitHubCreateRef{Ref: "refs/heads/" + branch, SHA: head.SHA}, nil); err != nil {
+		return nil, fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	if _, err := c.ReplaceContent(ctx, branch, path, content, sha, message); err != nil {
+		return nil, fmt.Errorf("failed to commit %s to %s: %w", path, branch, err)
+	}
+
+	var pull GitHubPullRequest
+	title, _, _ := strings.Cut(message, "\n")
+	request := GitHubCreatePullRequest{Title: title, Head: branch, Base: c.config.Branch, Body: body}
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls", c.apiBaseURL, c.config.Owner, c.config.Repo)
+	if _, err := c.doJSON(ctx, http.MethodPost, url, request, &pull); err != nil {
+		return nil, fmt.Errorf("failed to open pull request: %w", err)
+	}
+	return &pull, nil
+}
@@ -0,0 +1,44 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetBlob(t *testing.T) {
+	fake := &fakeGitHub{}
+	client := newFakeGitHubClient(t, fake, "")
+	fake.blob = GitHubBlob{SHA: "base-sha", Content: base64.StdEncoding.EncodeToString([]byte("# Billing\n")), Encoding: "base64"}
+
+	content, err := client.GetBlob(context.Background(), "base-sha")
+
+	require.NoError(t, err)
+	assert.Equal(t, "# Billing\n", string(content))
+	_, err = client.GetBlob(context.Background(), "missing-sha")
+	assert.Error(t, err)
+}
+
+func TestClient_ProposeContent(t *testing.T) {
+	fake := &fakeGitHub{}
+	client := newFakeGitHubClient(t, fake, "docs")
+
+	pull, err := client.ProposeContent(context.Background(), "quill/update-billing", "billing.md", []byte("# Billing\n"), "file-sha",
+		"Update decision documentation\n\n2 lines added, 0 removed", "Please check the conflicts.")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7", pull.HTMLURL)
+	assert.Equal(t, GitHubCreateRef{Ref: "refs/heads/quill/update-billing", SHA: "head-sha"}, fake.branch)
+	assert.Equal(t, []string{"docs/billing.md"}, fake.created)
+	assert.Equal(t, "quill/update-billing", fake.file.Branch)
+	assert.Equal(t, "file-sha", fake.file.SHA)
+	assert.Equal(t, GitHubCreatePullRequest{
+		Title: "Update decision documentation",
+		Head:  "quill/update-billing",
+		Base:  "main",
+		Body:  "Please check the conflicts.",
+	}, fake.pull)
+}
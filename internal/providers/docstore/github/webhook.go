@@ -0,0 +1,155 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// maxWebhookBody is the size GitHub caps webhook payloads at
+const maxWebhookBody = 25 << 20
+
+var (
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+)
+
+// PushListener is told about the pushes to document repositories, implemented by services.ReconciliationService
+type PushListener interface {
+	HandlePush(ctx context.Context, push *domain.DocumentPush) error
+}
+
+// WebhookHandler receives the push webhooks of the document repositories, so documents people edit in GitHub
+// are picked up right away rather than at the next reconciliation
+type WebhookHandler struct {
+	config   Config
+	listener PushListener
+}
+
+// NewWebhookHandler creates a WebhookHandler for the repository of the configuration and the repositories
+// the projects document into with the same credentials. The configuration needs a WebhookSecret.
+func NewWebhookHandler(config *Config, listener PushListener) (*WebhookHandler, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if strings.TrimSpace(config.WebhookSecret) == "" {
+		return nil, fmt.Errorf("invalid configuration: %w", ErrMissingWebhookSecret)
+	}
+	if listener == nil {
+		return nil, fmt.Errorf("push listener cannot be nil")
+	}
+	return &WebhookHandler{config: *config, listener: listener}, nil
+}
+
+// ServeHTTP serves the webhook. Deliveries authenticate with the X-Hub-Signature-256 signature of the
+// secret. Push events are handed to the listener before answering, other events are acknowledged and ignored.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := verifyWebhookSignature(h.config.WebhookSecret, body, r.Header.Get("X-Hub-Signature-256")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("X-GitHub-Event") != "push" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var event GitHubPushEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	push, ok := h.documentPush(event)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err := h.listener.HandlePush(r.Context(), push); err != nil {
+		log.Printf("Failed to handle the push to %s: %v", event.Repository.FullName, err)
+		http.Error(w, "failed to handle push", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// documentPush reads the documents a push changed. Pushes deleting a branch, and pushes to other branches
+// of the default repository than the one documents are written to, change no documents.
+func (h *WebhookHandler) documentPush(event GitHubPushEvent) (*domain.DocumentPush, bool) {
+	branch, ok := strings.CutPrefix(event.Ref, "refs/heads/")
+	if !ok || event.Deleted {
+		return nil, false
+	}
+	// An empty repository and branch name the default ones, as in a project's documentation settings
+	repository := event.Repository.FullName
+	if strings.EqualFold(repository, h.config.Owner+"/"+h.config.Repo) {
+		repository = ""
+	}
+	if branch == h.config.Branch {
+		branch = ""
+	}
+	if repository == "" && branch != "" {
+		return nil, false
+	}
+
+	push := domain.NewDocumentPush(repository, branch)
+	for _, commit := range event.Commits {
+		author := commit.Author.Username
+		if author == "" {
+			author = commit.Author.Name
+		}
+		byBot := strings.EqualFold(commit.Author.Email, h.config.CommitterEmail)
+		push.Record(commit.ID, author, byBot, h.storePaths(commit.Added), h.storePaths(commit.Modified), h.storePaths(commit.Removed))
+	}
+	return push, true
+}
+
+// storePaths turns repository paths into document paths, leaving out the files outside the base path
+func (h *WebhookHandler) storePaths(paths []string) []string {
+	if h.config.BasePath == "" {
+		return paths
+	}
+	var docs []string
+	for _, path := range paths {
+		if doc, ok := strings.CutPrefix(path, h.config.BasePath+"/"); ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+// verifyWebhookSignature checks the payload was signed with the secret, the signature is "sha256=" and the
+// hex encoded HMAC-SHA256 of the payload
+func verifyWebhookSignature(secret string, payload []byte, signature string) error {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidWebhookSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(digest))) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
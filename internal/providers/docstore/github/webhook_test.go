@@ -0,0 +1,134 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+type recordingListener struct {
+	pushes []*domain.DocumentPush
+	err    error
+}
+
+func (l *recordingListener) HandlePush(ctx context.Context, push *domain.DocumentPush) error {
+	l.pushes = append(l.pushes, push)
+	return l.err
+}
+
+func newTestWebhookHandler(t *testing.T, basePath string) (*WebhookHandler, *recordingListener) {
+	t.Helper()
+	listener := &recordingListener{}
+	handler, err := NewWebhookHandler(&Config{
+		Token:          "token",
+		Owner:          "owner",
+		Repo:           "repo",
+		BasePath:       basePath,
+		CommitterName:  "Quill Bot",
+		CommitterEmail: "bot@example.com",
+		WebhookSecret:  testWebhookSecret,
+	}, listener)
+	require.NoError(t, err)
+	return handler, listener
+}
+
+func deliver(handler http.Handler, event, payload, secret string) *httptest.ResponseRecorder {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	req := httptest.NewRequest(http.MethodPost, "/github", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func pushPayload(repository, ref string) string {
+	return `{"ref":"` + ref + `","repository":{"full_name":"` + repository + `"},"commits":[
+		{"id":"c1","author":{"name":"Quill Bot","email":"bot@example.com"},"added":["docs/adopt-postgres.md"]},
+		{"id":"c2","author":{"name":"Alice","email":"alice@example.com","username":"alice"},"modified":["docs/adopt-postgres.md","README.md"]}
+	]}`
+}
+
+func TestWebhookHandler_Push(t *testing.T) {
+	handler, listener := newTestWebhookHandler(t, "")
+
+	rec := deliver(handler, "push", pushPayload("owner/repo", "refs/heads/main"), testWebhookSecret)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.Len(t, listener.pushes, 1)
+	push := listener.pushes[0]
+	assert.Empty(t, push.Repository)
+	assert.Empty(t, push.Branch)
+	assert.Equal(t, []domain.DocumentChange{
+		{Path: "docs/adopt-postgres.md", Kind: domain.DocumentAdded, Author: "alice", Commit: "c2"},
+		{Path: "README.md", Kind: domain.DocumentModified, Author: "alice", Commit: "c2"},
+	}, push.Changes())
+}
+
+func TestWebhookHandler_OtherRepositoriesAndBranches(t *testing.T) {
+	handler, listener := newTestWebhookHandler(t, "docs")
+
+	deliver(handler, "push", pushPayload("team/handbook", "refs/heads/wiki"), testWebhookSecret)
+	deliver(handler, "push", pushPayload("owner/repo", "refs/heads/feature"), testWebhookSecret)
+	deliver(handler, "push", pushPayload("owner/repo", "refs/tags/v1"), testWebhookSecret)
+
+	require.Len(t, listener.pushes, 1, "only the branch documents are written to of the default repository")
+	push := listener.pushes[0]
+	assert.Equal(t, "team/handbook", push.Repository)
+	assert.Equal(t, "wiki", push.Branch)
+	changes := push.Changes()
+	require.Len(t, changes, 1, "files outside the base path are left out")
+	assert.Equal(t, "adopt-postgres.md", changes[0].Path)
+}
+
+func TestWebhookHandler_RejectsUnsignedDeliveries(t *testing.T) {
+	handler, listener := newTestWebhookHandler(t, "")
+
+	rec := deliver(handler, "push", pushPayload("owner/repo", "refs/heads/main"), "wrong-secret")
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, listener.pushes)
+}
+
+func TestWebhookHandler_IgnoresOtherEvents(t *testing.T) {
+	handler, listener := newTestWebhookHandler(t, "")
+
+	rec := deliver(handler, "ping", `{"zen":"Keep it logically awesome."}`, testWebhookSecret)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, listener.pushes)
+}
+
+func TestWebhookHandler_ListenerFailure(t *testing.T) {
+	handler, listener := newTestWebhookHandler(t, "")
+	listener.err = errors.New("index unavailable")
+
+	rec := deliver(handler, "push", pushPayload("owner/repo", "refs/heads/main"), testWebhookSecret)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestNewWebhookHandler_RequiresSecret(t *testing.T) {
+	_, err := NewWebhookHandler(&Config{
+		Token:          "token",
+		Owner:          "owner",
+		Repo:           "repo",
+		CommitterName:  "Quill Bot",
+		CommitterEmail: "bot@example.com",
+	}, &recordingListener{})
+
+	assert.ErrorIs(t, err, ErrMissingWebhookSecret)
+}
@@ -0,0 +1,99 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMissingKeys      = errors.New("at least one encryption key is required")
+	ErrInvalidKey       = errors.New("encryption keys must have an ID without colons and 16, 24 or 32 bytes")
+	ErrUnknownActiveKey = errors.New("active key is not one of the keys")
+)
+
+// Key is an AES key, identified in the values it encrypts so it can still decrypt them after a rotation
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeySource fetches the encryption keys from a key management service, so they need not be kept in config
+type KeySource interface {
+	// FetchKeys returns every key that may have encrypted stored values
+	FetchKeys(ctx context.Context) ([]Key, error)
+}
+
+// Config contains the keys of field-level encryption
+type Config struct {
+	// Keys are the known keys, the old ones are kept to decrypt what they encrypted
+	Keys []Key
+
+	// ActiveKeyID names the key new values are encrypted with
+	ActiveKeyID string
+}
+
+// NewConfig creates a Config encrypting with a single key
+func NewConfig(id string, secret []byte) *Config {
+	return &Config{
+		Keys:        []Key{{ID: id, Secret: secret}},
+		ActiveKeyID: id,
+	}
+}
+
+// NewConfigFromSource creates a Config with the keys of a key management service, encrypting with the active one
+func NewConfigFromSource(ctx context.Context, source KeySource, activeKeyID string) (*Config, error) {
+	keys, err := source.FetchKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch encryption keys: %w", err)
+	}
+	return &Config{Keys: keys, ActiveKeyID: activeKeyID}, nil
+}
+
+// ParseKeys parses keys written as comma separated id:base64 pairs, like QUILL_ENCRYPTION_KEYS="2024:q1...,2023:Zm..."
+func ParseKeys(s string) ([]Key, error) {
+	var keys []Key
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not id:base64", ErrInvalidKey, pair)
+		}
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s is not base64: %v", ErrInvalidKey, id, err)
+		}
+		keys = append(keys, Key{ID: strings.TrimSpace(id), Secret: secret})
+	}
+	return keys, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if len(c.Keys) == 0 {
+		return ErrMissingKeys
+	}
+	active := false
+	seen := make(map[string]bool, len(c.Keys))
+	for _, key := range c.Keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") || seen[key.ID] {
+			return fmt.Errorf("%w: key %q", ErrInvalidKey, key.ID)
+		}
+		switch len(key.Secret) {
+		case 16, 24, 32:
+		default:
+			return fmt.Errorf("%w: key %s has %d bytes", ErrInvalidKey, key.ID, len(key.Secret))
+		}
+		seen[key.ID] = true
+		active = active || key.ID == c.ActiveKeyID
+	}
+	if !active {
+		return ErrUnknownActiveKey
+	}
+	return nil
+}
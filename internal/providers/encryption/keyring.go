@@ -0,0 +1,87 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownKey = errors.New("value was encrypted with an unknown key")
+	ErrCorrupted  = errors.New("encrypted value is corrupted or was tampered with")
+)
+
+// prefix marks encrypted values, followed by the key ID and the base64 nonce and ciphertext
+const prefix = "enc:v1:"
+
+// Keyring encrypts fields with AES-GCM under the active key, and decrypts them with whichever key encrypted them.
+// It implements domain.FieldCipher.
+type Keyring struct {
+	active string
+	aeads  map[string]cipher.AEAD
+}
+
+// NewKeyring creates a Keyring with the configured keys
+func NewKeyring(config *Config) (*Keyring, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(config.Keys))
+	for _, key := range config.Keys {
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", key.ID, err)
+		}
+		aeads[key.ID] = aead
+	}
+	return &Keyring{active: config.ActiveKeyID, aeads: aeads}, nil
+}
+
+// Encrypt returns the value encrypted with the active key, like "enc:v1:2024:<base64>".
+// The key ID is authenticated with the value, so it cannot be swapped for another key's.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.aeads[k.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.active))
+	return prefix + k.active + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of a value encrypted with any known key, and values that are not encrypted unchanged
+func (k *Keyring) Decrypt(value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return value, nil
+	}
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrCorrupted
+	}
+	aead, ok := k.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrCorrupted
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", ErrCorrupted
+	}
+	return string(plaintext), nil
+}
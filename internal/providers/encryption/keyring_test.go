@@ -0,0 +1,134 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldSecret = bytes.Repeat([]byte{1}, 32)
+	newSecret = bytes.Repeat([]byte{2}, 32)
+)
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	keyring, err := NewKeyring(NewConfig("2024", newSecret))
+	require.NoError(t, err)
+
+	encrypted, err := keyring.Encrypt("We will use Postgres")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:v1:2024:"))
+	assert.NotContains(t, encrypted, "Postgres")
+
+	again, err := keyring.Encrypt("We will use Postgres")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "every value gets its own nonce")
+
+	decrypted, err := keyring.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "We will use Postgres", decrypted)
+
+	plaintext, err := keyring.Decrypt("stored before encryption")
+	require.NoError(t, err)
+	assert.Equal(t, "stored before encryption", plaintext)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old, err := NewKeyring(NewConfig("2023", oldSecret))
+	require.NoError(t, err)
+	encrypted, err := old.Encrypt("U0001")
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring(&Config{
+		Keys:        []Key{{ID: "2023", Secret: oldSecret}, {ID: "2024", Secret: newSecret}},
+		ActiveKeyID: "2024",
+	})
+	require.NoError(t, err)
+
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "U0001", decrypted)
+	reencrypted, err := rotated.Encrypt(decrypted)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reencrypted, "enc:v1:2024:"))
+
+	_, err = old.Decrypt(reencrypted)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_DetectsTampering(t *testing.T) {
+	keyring, err := NewKeyring(&Config{
+		Keys:        []Key{{ID: "2023", Secret: oldSecret}, {ID: "2024", Secret: newSecret}},
+		ActiveKeyID: "2024",
+	})
+	require.NoError(t, err)
+	encrypted, err := keyring.Encrypt("U0001")
+	require.NoError(t, err)
+
+	flipped := []byte(encrypted)
+	flipped[len(flipped)-2] ^= 1
+	swapped := strings.Replace(encrypted, ":2024:", ":2023:", 1)
+
+	for name, value := range map[string]string{
+		"flipped bit":     string(flipped),
+		"swapped key":     swapped,
+		"missing key":     "enc:v1:" + base64.RawStdEncoding.EncodeToString([]byte("x")),
+		"truncated nonce": "enc:v1:2024:AAAA",
+	} {
+		_, err := keyring.Decrypt(value)
+		assert.ErrorIs(t, err, ErrCorrupted, name)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr error
+	}{
+		{name: "single key", config: NewConfig("2024", newSecret)},
+		{name: "no keys", config: &Config{ActiveKeyID: "2024"}, wantErr: ErrMissingKeys},
+		{name: "short key", config: NewConfig("2024", []byte("short")), wantErr: ErrInvalidKey},
+		{name: "colon in ID", config: NewConfig("20:24", newSecret), wantErr: ErrInvalidKey},
+		{name: "duplicate ID", config: &Config{Keys: []Key{{ID: "2024", Secret: oldSecret}, {ID: "2024", Secret: newSecret}}, ActiveKeyID: "2024"}, wantErr: ErrInvalidKey},
+		{name: "unknown active key", config: &Config{Keys: []Key{{ID: "2023", Secret: oldSecret}}, ActiveKeyID: "2024"}, wantErr: ErrUnknownActiveKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.config.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("2024:" + base64.StdEncoding.EncodeToString(newSecret) + ", 2023:" + base64.StdEncoding.EncodeToString(oldSecret))
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{ID: "2024", Secret: newSecret}, {ID: "2023", Secret: oldSecret}}, keys)
+
+	_, err = ParseKeys("2024")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = ParseKeys("2024:not base64!")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+type stubKeySource struct {
+	keys []Key
+}
+
+func (s stubKeySource) FetchKeys(ctx context.Context) ([]Key, error) {
+	return s.keys, nil
+}
+
+func TestNewConfigFromSource(t *testing.T) {
+	config, err := NewConfigFromSource(context.Background(), stubKeySource{keys: []Key{{ID: "2024", Secret: newSecret}}}, "2024")
+	require.NoError(t, err)
+
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, "2024", config.ActiveKeyID)
+}
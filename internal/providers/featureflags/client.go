@@ -0,0 +1,72 @@
+// Package featureflags fetches feature flags from a JSON document served over HTTP, like a file in object
+// storage or the endpoint of a flag management service, so features roll out without restarting the bot
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+// Client fetches feature flags from a URL, implementing ports.FeatureFlagSource
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+// NewClient creates a feature flag client
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := transport.NewHTTPClient(cfg.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		config:     cfg,
+		httpClient: httpClient,
+	}, nil
+}
+
+// FeatureFlags fetches the flags by feature
+func (c *Client) FeatureFlags(ctx context.Context) (domain.FeatureFlags, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(c.config.URL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feature flags request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feature flags: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("feature flags returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var flags domain.FeatureFlags
+	if err := json.NewDecoder(resp.Body).Decode(&flags); err != nil {
+		return nil, fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+	if err := flags.Validate(); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
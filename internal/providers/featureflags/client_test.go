@@ -0,0 +1,50 @@
+package featureflags
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&Config{URL: "https://flags.example.com/quill.json"}).Validate())
+	assert.ErrorIs(t, (&Config{}).Validate(), ErrInvalidURL)
+	assert.ErrorIs(t, (&Config{URL: "flags.json"}).Validate(), ErrInvalidURL)
+}
+
+func TestClient_FeatureFlags(t *testing.T) {
+	body := `{"questions": {"projects": ["Billing"], "rollout": 20}, "approvals": {"enabled": true}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{URL: server.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	flags, err := client.FeatureFlags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.FeatureFlags{
+		domain.FeatureQuestions: {Projects: []string{"Billing"}, Rollout: 20},
+		domain.FeatureApprovals: {Enabled: true},
+	}, flags)
+
+	// Unknown features are rejected rather than ignored, they are usually typos
+	body = `{"telepathy": {"enabled": true}}`
+	_, err = client.FeatureFlags(context.Background())
+	assert.ErrorIs(t, err, domain.ErrInvalidFeatureFlag)
+
+	unauthorized, err := NewClient(&Config{URL: server.URL})
+	require.NoError(t, err)
+	_, err = unauthorized.FeatureFlags(context.Background())
+	assert.ErrorContains(t, err, "401 Unauthorized")
+}
@@ -0,0 +1,42 @@
+package featureflags
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidURL = errors.New("feature flags URL must be an absolute http or https URL")
+)
+
+// DefaultTimeout is the default timeout for fetching the flags
+const DefaultTimeout = 10 * time.Second
+
+// Config contains the settings of the remote feature flag source
+type Config struct {
+	// URL is the address of the JSON document holding the flags by feature, like
+	// {"questions": {"projects": ["Billing"], "rollout": 20}}
+	URL string
+
+	// Token is sent as a bearer token when fetching the flags (optional)
+	Token string
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	u, err := url.Parse(strings.TrimSpace(c.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if c.HTTP != nil {
+		return c.HTTP.Validate()
+	}
+	return nil
+}
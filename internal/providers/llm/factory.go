@@ -1,8 +1,10 @@
 package llm
 
 import (
+	"context"
 	"fmt"
 	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/providers/llm/fixture"
 	"github.com/massimo-ua/quill/internal/providers/llm/ollama"
 	"github.com/massimo-ua/quill/internal/providers/llm/openai"
 )
@@ -15,6 +17,8 @@ const (
 	ProviderTypeOpenAI ProviderType = "openai"
 	// ProviderTypeOllama represents the Ollama provider
 	ProviderTypeOllama ProviderType = "ollama"
+	// ProviderTypeFixture represents the fixture provider answering from canned responses, for tests and demos
+	ProviderTypeFixture ProviderType = "fixture"
 )
 
 // Config contains configuration for creating an LLM provider
@@ -27,10 +31,14 @@ type Config struct {
 
 	// Ollama-specific configuration
 	Ollama *ollama.Config
+
+	// Fixture-specific configuration
+	Fixture *fixture.Config
 }
 
-// NewLLMProvider creates a new AiAgentProvider based on the specified provider type
-func NewLLMProvider(cfg *Config) (ports.AiAgentProvider, error) {
+// NewLLMProvider creates a new AiAgentProvider based on the specified provider type. Providers checking their
+// models at startup stop when ctx is canceled.
+func NewLLMProvider(ctx context.Context, cfg *Config) (ports.AiAgentProvider, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
@@ -45,7 +53,12 @@ func NewLLMProvider(cfg *Config) (ports.AiAgentProvider, error) {
 		if cfg.Ollama == nil {
 			return nil, fmt.Errorf("Ollama config cannot be nil for Ollama provider")
 		}
-		return ollama.NewOllamaProvider(cfg.Ollama)
+		return ollama.NewOllamaProvider(ctx, cfg.Ollama)
+	case ProviderTypeFixture:
+		if cfg.Fixture == nil {
+			return nil, fmt.Errorf("fixture config cannot be nil for fixture provider")
+		}
+		return fixture.NewFixtureProvider(cfg.Fixture)
 	default:
 		return nil, fmt.Errorf("unsupported provider type: %s", cfg.Type)
 	}
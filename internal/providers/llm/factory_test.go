@@ -1,8 +1,10 @@
 package llm
 
 import (
+	"context"
 	"testing"
 
+	"github.com/massimo-ua/quill/internal/providers/llm/fixture"
 	"github.com/massimo-ua/quill/internal/providers/llm/ollama"
 	"github.com/massimo-ua/quill/internal/providers/llm/openai"
 	"github.com/stretchr/testify/assert"
@@ -59,6 +61,21 @@ func TestNewLLMProvider(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid fixture config",
+			config: &Config{
+				Type:    ProviderTypeFixture,
+				Fixture: &fixture.Config{Fixtures: []fixture.Fixture{{Title: "Adopt Postgres"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing fixture config",
+			config: &Config{
+				Type: ProviderTypeFixture,
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid provider type",
 			config: &Config{
@@ -70,7 +87,7 @@ func TestNewLLMProvider(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			provider, err := NewLLMProvider(tt.config)
+			provider, err := NewLLMProvider(context.Background(), tt.config)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, provider)
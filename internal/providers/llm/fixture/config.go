@@ -0,0 +1,82 @@
+package fixture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrNoFixtures     = errors.New("at least one fixture or a fixture file is required")
+	ErrInvalidFixture = errors.New("invalid fixture")
+)
+
+// Analysis is the analysis a fixture returns, in the JSON form the LLM providers parse
+type Analysis struct {
+	Type            string   `json:"type"`
+	Category        string   `json:"category"`
+	ConfidenceScore float64  `json:"confidenceScore"`
+	SuggestedTags   []string `json:"suggestedTags,omitempty"`
+}
+
+// Fixture maps the content sent to the AI agent to canned responses.
+// A fixture only answers the operations it has a response for, others fall through to the next fixture.
+type Fixture struct {
+	// Match is a regular expression the content must match, empty matches any content
+	Match string `json:"match,omitempty"`
+	// Analysis answers AnalyzeMessage
+	Analysis *Analysis `json:"analysis,omitempty"`
+	// Documentation answers GenerateDocumentation
+	Documentation string `json:"documentation,omitempty"`
+	// Category answers CategorizeContent
+	Category string `json:"category,omitempty"`
+	// References answer DetectReferences, formatted as type:value like "url:https://example.com"
+	References []string `json:"references,omitempty"`
+	// Title answers GenerateTitle
+	Title string `json:"title,omitempty"`
+	// Error fails every operation on matching content with this message
+	Error string `json:"error,omitempty"`
+}
+
+// Config contains the fixtures of the provider
+type Config struct {
+	// Fixtures are tried in order, the first matching fixture with a response wins
+	Fixtures []Fixture
+
+	// Path is a JSON file holding an array of fixtures, tried after Fixtures (optional)
+	Path string
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if len(c.Fixtures) == 0 && strings.TrimSpace(c.Path) == "" {
+		return ErrNoFixtures
+	}
+	for i, f := range c.Fixtures {
+		if _, err := regexp.Compile(f.Match); err != nil {
+			return fmt.Errorf("%w %d: %v", ErrInvalidFixture, i+1, err)
+		}
+	}
+	return nil
+}
+
+// fixtures returns the configured fixtures followed by those of the fixture file
+func (c *Config) fixtures() ([]Fixture, error) {
+	fixtures := append([]Fixture(nil), c.Fixtures...)
+	if strings.TrimSpace(c.Path) == "" {
+		return fixtures, nil
+	}
+
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture file: %w", err)
+	}
+	var loaded []Fixture
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidFixture, c.Path, err)
+	}
+	return append(fixtures, loaded...), nil
+}
@@ -0,0 +1,192 @@
+// Package fixture provides a deterministic AiAgentProvider that answers from canned responses.
+// It lets tests exercise the services without network access or mocking HTTP.
+package fixture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// ErrNoMatch indicates that no fixture answers an operation for the content
+var ErrNoMatch = errors.New("no fixture matches")
+
+// Call is an operation the provider was asked to perform
+type Call struct {
+	Operation string
+	Content   string
+}
+
+// compiledFixture is a fixture with its parsed responses
+type compiledFixture struct {
+	match      *regexp.Regexp
+	fixture    Fixture
+	analysis   *domain.MessageAnalysisResult
+	category   *domain.Category
+	references []*domain.Reference
+}
+
+// Provider implements ports.AiAgentProvider and ports.TitleGenerator with fixtures
+type Provider struct {
+	fixtures []compiledFixture
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewFixtureProvider creates a provider answering from the configured fixtures
+func NewFixtureProvider(cfg *Config) (*Provider, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	fixtures, err := cfg.fixtures()
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &Provider{}
+	for i, f := range fixtures {
+		compiled, err := compile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %v", ErrInvalidFixture, i+1, err)
+		}
+		provider.fixtures = append(provider.fixtures, compiled)
+	}
+	return provider, nil
+}
+
+func compile(f Fixture) (compiledFixture, error) {
+	match, err := regexp.Compile(f.Match)
+	if err != nil {
+		return compiledFixture{}, err
+	}
+	compiled := compiledFixture{match: match, fixture: f}
+
+	if f.Analysis != nil {
+		messageType, err := domain.NewMessageType(strings.ToLower(f.Analysis.Type))
+		if err != nil {
+			return compiledFixture{}, err
+		}
+		category, err := domain.NewCategory(strings.ToLower(f.Analysis.Category))
+		if err != nil {
+			return compiledFixture{}, err
+		}
+		compiled.analysis, err = domain.NewMessageAnalysisResult(messageType, category, nil, f.Analysis.ConfidenceScore, f.Analysis.SuggestedTags)
+		if err != nil {
+			return compiledFixture{}, err
+		}
+	}
+
+	if f.Category != "" {
+		category, err := domain.NewCategory(strings.ToLower(f.Category))
+		if err != nil {
+			return compiledFixture{}, err
+		}
+		compiled.category = &category
+	}
+
+	for _, raw := range f.References {
+		ref, err := domain.ParseReference(raw)
+		if err != nil {
+			return compiledFixture{}, err
+		}
+		compiled.references = append(compiled.references, ref)
+	}
+	return compiled, nil
+}
+
+// DataLocation reports that fixtures never leave the process
+func (p *Provider) DataLocation() domain.DataLocation {
+	return domain.DataLocationLocal
+}
+
+// AnalyzeMessage returns the analysis of the first fixture matching the content
+func (p *Provider) AnalyzeMessage(ctx context.Context, content string) (*domain.MessageAnalysisResult, error) {
+	f, err := p.find(ctx, "AnalyzeMessage", content, func(f compiledFixture) bool { return f.analysis != nil })
+	if err != nil {
+		return nil, err
+	}
+	// Callers may stamp the result, so every call gets its own copy
+	result := *f.analysis
+	return &result, nil
+}
+
+// GenerateDocumentation returns the documentation of the first fixture matching the message
+func (p *Provider) GenerateDocumentation(ctx context.Context, message string, metadata map[string]interface{}) (string, error) {
+	f, err := p.find(ctx, "GenerateDocumentation", message, func(f compiledFixture) bool { return f.fixture.Documentation != "" })
+	if err != nil {
+		return "", err
+	}
+	return f.fixture.Documentation, nil
+}
+
+// CategorizeContent returns the category of the first fixture matching the content
+func (p *Provider) CategorizeContent(ctx context.Context, content string) (*domain.Category, error) {
+	f, err := p.find(ctx, "CategorizeContent", content, func(f compiledFixture) bool { return f.category != nil })
+	if err != nil {
+		return nil, err
+	}
+	category := *f.category
+	return &category, nil
+}
+
+// DetectReferences returns the references of the first fixture matching the content
+func (p *Provider) DetectReferences(ctx context.Context, content string) ([]*domain.Reference, error) {
+	f, err := p.find(ctx, "DetectReferences", content, func(f compiledFixture) bool { return f.fixture.References != nil })
+	if err != nil {
+		return nil, err
+	}
+	return append([]*domain.Reference(nil), f.references...), nil
+}
+
+// GenerateTitle returns the title of the first fixture matching the content
+func (p *Provider) GenerateTitle(ctx context.Context, content string) (string, error) {
+	f, err := p.find(ctx, "GenerateTitle", content, func(f compiledFixture) bool { return f.fixture.Title != "" })
+	if err != nil {
+		return "", err
+	}
+	return f.fixture.Title, nil
+}
+
+// Calls returns the operations performed so far, in order
+func (p *Provider) Calls() []Call {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	calls := make([]Call, len(p.calls))
+	copy(calls, p.calls)
+	return calls
+}
+
+// find records the call and returns the first fixture that matches the content and answers the operation
+func (p *Provider) find(ctx context.Context, operation, content string, answers func(compiledFixture) bool) (compiledFixture, error) {
+	if err := ctx.Err(); err != nil {
+		return compiledFixture{}, err
+	}
+
+	p.mu.Lock()
+	p.calls = append(p.calls, Call{Operation: operation, Content: content})
+	p.mu.Unlock()
+
+	for _, f := range p.fixtures {
+		if !f.match.MatchString(content) {
+			continue
+		}
+		if f.fixture.Error != "" {
+			return compiledFixture{}, errors.New(f.fixture.Error)
+		}
+		if answers(f) {
+			return f, nil
+		}
+	}
+	return compiledFixture{}, fmt.Errorf("%w %s for %q", ErrNoMatch, operation, content)
+}
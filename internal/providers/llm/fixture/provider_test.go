@@ -0,0 +1,114 @@
+package fixture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFixtureProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr error
+	}{
+		{
+			name:    "no fixtures",
+			config:  &Config{},
+			wantErr: ErrNoFixtures,
+		},
+		{
+			name:    "invalid match",
+			config:  &Config{Fixtures: []Fixture{{Match: "("}}},
+			wantErr: ErrInvalidFixture,
+		},
+		{
+			name:    "invalid analysis type",
+			config:  &Config{Fixtures: []Fixture{{Analysis: &Analysis{Type: "rumor", Category: "other"}}}},
+			wantErr: ErrInvalidFixture,
+		},
+		{
+			name:    "invalid reference",
+			config:  &Config{Fixtures: []Fixture{{References: []string{"postgres"}}}},
+			wantErr: ErrInvalidFixture,
+		},
+		{
+			name:   "fixture file",
+			config: &Config{Path: "testdata/fixtures.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFixtureProvider(tt.config)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProvider_AnswersFromFixtures(t *testing.T) {
+	provider, err := NewFixtureProvider(&Config{Path: "testdata/fixtures.json"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	analysis, err := provider.AnalyzeMessage(ctx, "We will use Postgres for billing")
+	require.NoError(t, err)
+	assert.Equal(t, domain.MessageTypeDecision, analysis.MessageType())
+	assert.Equal(t, domain.CategoryDevelopment, analysis.Category())
+	assert.Equal(t, 0.95, analysis.ConfidenceScore())
+	assert.Equal(t, []string{"postgres"}, analysis.SuggestedTags())
+
+	documentation, err := provider.GenerateDocumentation(ctx, "We will use Postgres for billing", nil)
+	require.NoError(t, err)
+	assert.Contains(t, documentation, "# Adopt Postgres")
+
+	refs, err := provider.DetectReferences(ctx, "We will use Postgres for billing")
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	assert.Equal(t, "https://www.postgresql.org", refs[0].Value())
+
+	title, err := provider.GenerateTitle(ctx, "We will use Postgres for billing")
+	require.NoError(t, err)
+	assert.Equal(t, "Adopt Postgres", title)
+
+	// The catch-all fixture answers analyses of any other content
+	analysis, err = provider.AnalyzeMessage(ctx, "Lunch at noon")
+	require.NoError(t, err)
+	assert.Equal(t, domain.MessageTypeUnknown, analysis.MessageType())
+
+	// No fixture has a title for other content
+	_, err = provider.GenerateTitle(ctx, "Lunch at noon")
+	assert.ErrorIs(t, err, ErrNoMatch)
+
+	assert.Equal(t, []Call{
+		{Operation: "AnalyzeMessage", Content: "We will use Postgres for billing"},
+		{Operation: "GenerateDocumentation", Content: "We will use Postgres for billing"},
+		{Operation: "DetectReferences", Content: "We will use Postgres for billing"},
+		{Operation: "GenerateTitle", Content: "We will use Postgres for billing"},
+		{Operation: "AnalyzeMessage", Content: "Lunch at noon"},
+		{Operation: "GenerateTitle", Content: "Lunch at noon"},
+	}, provider.Calls())
+}
+
+func TestProvider_Errors(t *testing.T) {
+	provider, err := NewFixtureProvider(&Config{Fixtures: []Fixture{
+		{Match: "timeout", Error: "model timed out"},
+		{Category: "product"},
+	}})
+	require.NoError(t, err)
+
+	_, err = provider.CategorizeContent(context.Background(), "this will timeout")
+	assert.EqualError(t, err, "model timed out")
+
+	category, err := provider.CategorizeContent(context.Background(), "Checkout redesign")
+	require.NoError(t, err)
+	assert.Equal(t, domain.CategoryProduct, *category)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.CategorizeContent(ctx, "Checkout redesign")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -5,7 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"github.com/massimo-ua/quill/internal/providers/transport"
 	"net/http"
 	"strings"
 	"time"
@@ -22,29 +22,71 @@ type Message struct {
 	Content string `json:"content"`
 }
 
-// GenerateRequest represents an Ollama generate request
+// Options represents the model parameters of an Ollama request
+type Options struct {
+	Temperature float64  `json:"temperature"`
+	TopP        float64  `json:"top_p,omitempty"`
+	TopK        int      `json:"top_k,omitempty"`
+	NumCtx      int      `json:"num_ctx,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Seed        int      `json:"seed,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// GenerateRequest represents an Ollama /api/generate request
 type GenerateRequest struct {
-	Model       string    `json:"model"`
-	Prompt      string    `json:"prompt,omitempty"`
-	System      string    `json:"system,omitempty"`
-	Messages    []Message `json:"messages,omitempty"`
-	Stream      bool      `json:"stream,omitempty"`
-	Temperature float64   `json:"temperature,omitempty"`
-	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Model   string   `json:"model"`
+	Prompt  string   `json:"prompt"`
+	System  string   `json:"system,omitempty"`
+	Stream  bool     `json:"stream"`
+	Options *Options `json:"options,omitempty"`
 }
 
-// GenerateResponse represents an Ollama generate response
+// GenerateResponse represents an Ollama /api/generate response
 type GenerateResponse struct {
-	Model     string `json:"model"`
-	Response  string `json:"response"`
-	Done      bool   `json:"done"`
-	Context   []int  `json:"context,omitempty"`
-	TotalDuration int64 `json:"total_duration,omitempty"`
-	LoadDuration  int64 `json:"load_duration,omitempty"`
-	PromptEvalCount   int  `json:"prompt_eval_count,omitempty"`
-	PromptEvalDuration int64 `json:"prompt_eval_duration,omitempty"`
-	EvalCount   int  `json:"eval_count,omitempty"`
-	EvalDuration int64 `json:"eval_duration,omitempty"`
+	Model              string `json:"model"`
+	Response           string `json:"response"`
+	Done               bool   `json:"done"`
+	Context            []int  `json:"context,omitempty"`
+	TotalDuration      int64  `json:"total_duration,omitempty"`
+	LoadDuration       int64  `json:"load_duration,omitempty"`
+	PromptEvalCount    int    `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64  `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int    `json:"eval_count,omitempty"`
+	EvalDuration       int64  `json:"eval_duration,omitempty"`
+}
+
+// ChatRequest represents an Ollama /api/chat request
+type ChatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+	Options  *Options  `json:"options,omitempty"`
+}
+
+// ChatResponse represents an Ollama /api/chat response
+type ChatResponse struct {
+	Model              string  `json:"model"`
+	Message            Message `json:"message"`
+	Done               bool    `json:"done"`
+	DoneReason         string  `json:"done_reason,omitempty"`
+	TotalDuration      int64   `json:"total_duration,omitempty"`
+	LoadDuration       int64   `json:"load_duration,omitempty"`
+	PromptEvalCount    int     `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64   `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int     `json:"eval_count,omitempty"`
+	EvalDuration       int64   `json:"eval_duration,omitempty"`
+}
+
+// EmbeddingRequest represents an Ollama embeddings request
+type EmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// EmbeddingResponse represents an Ollama embeddings response
+type EmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
 }
 
 // Client represents an Ollama API client
@@ -63,8 +105,9 @@ func NewClient(cfg *Config) (*Client, error) {
 		return nil, err
 	}
 
-	httpClient := &http.Client{
-		Timeout: DefaultTimeout,
+	httpClient, err := transport.NewHTTPClient(cfg.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
 
 	return &Client{
@@ -75,74 +118,115 @@ func NewClient(cfg *Config) (*Client, error) {
 
 // GenerateCompletion sends a generate request to the Ollama API with prompt
 func (c *Client) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
-	if ctx == nil {
-		ctx = context.Background()
+	request := GenerateRequest{
+		Model:   c.config.Model,
+		Prompt:  prompt,
+		System:  c.config.SystemPrompt,
+		Stream:  false,
+		Options: c.options(),
 	}
 
-	endpoint := fmt.Sprintf("%s/api/generate", strings.TrimRight(c.config.ServerURL, "/"))
-
-	request := GenerateRequest{
-		Model:       c.config.Model,
-		Prompt:      prompt,
-		System:      c.config.SystemPrompt,
-		Temperature: c.config.Temperature,
-		MaxTokens:   c.config.MaxTokens,
+	var response GenerateResponse
+	if err := c.postJSON(ctx, "/api/generate", request, &response); err != nil {
+		return "", err
 	}
 
-	return c.sendGenerateRequest(ctx, endpoint, request)
+	return response.Response, nil
 }
 
-// GenerateChatCompletion sends a generate request to the Ollama API with messages
+// GenerateChatCompletion sends a chat request to the Ollama API with messages
 func (c *Client) GenerateChatCompletion(ctx context.Context, messages []Message) (string, error) {
-	if ctx == nil {
-		ctx = context.Background()
+	// The chat endpoint has no system field, so the configured system prompt leads the conversation
+	if strings.TrimSpace(c.config.SystemPrompt) != "" {
+		messages = append([]Message{{Role: "system", Content: c.config.SystemPrompt}}, messages...)
 	}
 
-	endpoint := fmt.Sprintf("%s/api/chat", strings.TrimRight(c.config.ServerURL, "/"))
+	request := ChatRequest{
+		Model:    c.config.Model,
+		Messages: messages,
+		Stream:   false,
+		Options:  c.options(),
+	}
 
-	request := GenerateRequest{
-		Model:       c.config.Model,
-		Messages:    messages,
-		System:      c.config.SystemPrompt,
-		Temperature: c.config.Temperature,
-		MaxTokens:   c.config.MaxTokens,
+	var response ChatResponse
+	if err := c.postJSON(ctx, "/api/chat", request, &response); err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(response.Message.Content) == "" {
+		return "", fmt.Errorf("empty response from model %s", response.Model)
 	}
 
-	return c.sendGenerateRequest(ctx, endpoint, request)
+	return response.Message.Content, nil
 }
 
-func (c *Client) sendGenerateRequest(ctx context.Context, endpoint string, request GenerateRequest) (string, error) {
+// GenerateEmbedding sends an embeddings request to the Ollama API
+func (c *Client) GenerateEmbedding(ctx context.Context, prompt string) ([]float64, error) {
+	request := EmbeddingRequest{
+		Model:  c.config.embeddingModel(),
+		Prompt: prompt,
+	}
+
+	var response EmbeddingResponse
+	if err := c.postJSON(ctx, "/api/embeddings", request, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Embedding) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+
+	return response.Embedding, nil
+}
+
+// options builds the model parameters from the configuration
+func (c *Client) options() *Options {
+	return &Options{
+		Temperature: c.config.Temperature,
+		TopP:        c.config.TopP,
+		TopK:        c.config.TopK,
+		NumCtx:      c.config.NumCtx,
+		NumPredict:  c.config.MaxTokens,
+		Seed:        c.config.Seed,
+		Stop:        c.config.Stop,
+	}
+}
+
+// postJSON sends a JSON request to an API path and decodes the JSON response
+func (c *Client) postJSON(ctx context.Context, path string, request interface{}, response interface{}) error {
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	endpoint := strings.TrimRight(c.config.ServerURL, "/") + path
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	buf, err := transport.ReadBody(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer transport.PutBuffer(buf)
+	body := buf.Bytes()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
 	}
 
-	var generateResponse GenerateResponse
-	if err := json.Unmarshal(body, &generateResponse); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	return generateResponse.Response, nil
-}
\ No newline at end of file
+	return nil
+}
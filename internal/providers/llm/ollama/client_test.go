@@ -0,0 +1,83 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := NewDefaultConfig(server.URL, "llama3")
+	cfg.TopP = 0.9
+	cfg.NumCtx = 8192
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_GenerateChatCompletion(t *testing.T) {
+	var received ChatRequest
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+
+		// Decode into a map as well to check that stream=false is sent explicitly
+		var raw map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		assert.Equal(t, false, raw["stream"])
+
+		data, _ := json.Marshal(raw)
+		require.NoError(t, json.Unmarshal(data, &received))
+
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			Model:   "llama3",
+			Message: Message{Role: "assistant", Content: "Hello there"},
+			Done:    true,
+		})
+	})
+
+	response, err := client.GenerateChatCompletion(context.Background(), []Message{{Role: "user", Content: "Hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello there", response)
+
+	require.NotNil(t, received.Options)
+	assert.Equal(t, 0.9, received.Options.TopP)
+	assert.Equal(t, 8192, received.Options.NumCtx)
+	assert.Equal(t, 1024, received.Options.NumPredict)
+}
+
+func TestClient_GenerateChatCompletion_EmptyResponse(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ChatResponse{Model: "llama3", Done: true})
+	})
+
+	_, err := client.GenerateChatCompletion(context.Background(), []Message{{Role: "user", Content: "Hi"}})
+	assert.Error(t, err)
+}
+
+func TestClient_GenerateCompletion(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/generate", r.URL.Path)
+
+		var request GenerateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "Say hi", request.Prompt)
+		assert.False(t, request.Stream)
+
+		_ = json.NewEncoder(w).Encode(GenerateResponse{Model: "llama3", Response: "hi", Done: true})
+	})
+
+	response, err := client.GenerateCompletion(context.Background(), "Say hi")
+	require.NoError(t, err)
+	assert.Equal(t, "hi", response)
+}
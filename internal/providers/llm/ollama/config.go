@@ -2,7 +2,9 @@ package ollama
 
 import (
 	"errors"
+	"github.com/massimo-ua/quill/internal/providers/transport"
 	"strings"
+	"time"
 )
 
 var (
@@ -10,8 +12,15 @@ var (
 	ErrMissingModelName  = errors.New("model name is required")
 	ErrInvalidTemperature = errors.New("temperature must be between 0 and 2")
 	ErrInvalidMaxTokens   = errors.New("max tokens must be greater than 0")
+	ErrInvalidTopP        = errors.New("top_p must be between 0 and 1")
+	ErrInvalidTopK        = errors.New("top_k cannot be negative")
+	ErrInvalidNumCtx      = errors.New("context window size cannot be negative")
+	ErrInvalidPullTimeout = errors.New("pull timeout cannot be negative")
 )
 
+// DefaultPullTimeout limits pulling a missing model at startup, large models take minutes to download
+const DefaultPullTimeout = 30 * time.Minute
+
 // Config contains Ollama API configuration
 type Config struct {
 	// ServerURL is the Ollama API endpoint (e.g., "http://localhost:11434")
@@ -26,8 +35,38 @@ type Config struct {
 	// MaxTokens is the maximum number of tokens to generate (default: 1024)
 	MaxTokens int
 
+	// TopP enables nucleus sampling (0-1, optional, 0 uses the model default)
+	TopP float64
+
+	// TopK limits sampling to the K most likely tokens (optional, 0 uses the model default)
+	TopK int
+
+	// NumCtx is the context window size in tokens (optional, 0 uses the model default)
+	NumCtx int
+
+	// Seed makes generation reproducible (optional, 0 uses a random seed)
+	Seed int
+
+	// Stop lists sequences that end generation (optional)
+	Stop []string
+
 	// SystemPrompt is the default system prompt to use (optional)
 	SystemPrompt string
+
+	// EmbeddingModel is the model used for embeddings (optional, defaults to Model)
+	EmbeddingModel string
+
+	// VerifyModel checks at startup that the models are available on the server (optional)
+	VerifyModel bool
+
+	// AutoPull pulls missing models at startup instead of failing; requires VerifyModel (optional)
+	AutoPull bool
+
+	// PullTimeout limits pulling each missing model (optional, default: 30m)
+	PullTimeout time.Duration
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
 }
 
 // NewDefaultConfig creates a Config with default values
@@ -37,6 +76,7 @@ func NewDefaultConfig(serverURL, model string) *Config {
 		Model:       model,
 		Temperature: 0.7,
 		MaxTokens:   1024,
+		PullTimeout: DefaultPullTimeout,
 	}
 }
 
@@ -58,5 +98,44 @@ func (c *Config) Validate() error {
 		return ErrInvalidMaxTokens
 	}
 
+	if c.TopP < 0 || c.TopP > 1 {
+		return ErrInvalidTopP
+	}
+
+	if c.TopK < 0 {
+		return ErrInvalidTopK
+	}
+
+	if c.NumCtx < 0 {
+		return ErrInvalidNumCtx
+	}
+
+	if c.PullTimeout < 0 {
+		return ErrInvalidPullTimeout
+	}
+
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// pullTimeout returns how long pulling a model may take
+func (c *Config) pullTimeout() time.Duration {
+	if c.PullTimeout <= 0 {
+		return DefaultPullTimeout
+	}
+	return c.PullTimeout
+}
+
+// embeddingModel returns the model used for embeddings
+func (c *Config) embeddingModel() string {
+	if strings.TrimSpace(c.EmbeddingModel) == "" {
+		return c.Model
+	}
+	return c.EmbeddingModel
+}
@@ -2,6 +2,7 @@ package ollama
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +23,17 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "negative pull timeout",
+			config: &Config{
+				ServerURL:   "http://localhost:11434",
+				Model:       "llama2",
+				Temperature: 0.7,
+				MaxTokens:   1024,
+				PullTimeout: -time.Minute,
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing server URL",
 			config: &Config{
@@ -70,6 +82,28 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "top_p too high",
+			config: &Config{
+				ServerURL:   "http://localhost:11434",
+				Model:       "llama2",
+				Temperature: 0.7,
+				MaxTokens:   1024,
+				TopP:        1.5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative context window",
+			config: &Config{
+				ServerURL:   "http://localhost:11434",
+				Model:       "llama2",
+				Temperature: 0.7,
+				MaxTokens:   1024,
+				NumCtx:      -1,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,4 +128,5 @@ func TestNewDefaultConfig(t *testing.T) {
 	assert.Equal(t, model, config.Model)
 	assert.Equal(t, 0.7, config.Temperature)
 	assert.Equal(t, 1024, config.MaxTokens)
+	assert.Equal(t, DefaultPullTimeout, config.PullTimeout)
 }
\ No newline at end of file
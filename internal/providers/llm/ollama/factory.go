@@ -1,12 +1,15 @@
 package ollama
 
 import (
+	"context"
 	"fmt"
 	"github.com/massimo-ua/quill/internal/domain/ports"
+	"log"
 )
 
-// NewOllamaProvider creates a new AiAgentProvider that uses Ollama
-func NewOllamaProvider(cfg *Config) (ports.AiAgentProvider, error) {
+// NewOllamaProvider creates a new AiAgentProvider that uses Ollama. With VerifyModel the models are checked, and
+// pulled with AutoPull, before it returns; canceling ctx stops them.
+func NewOllamaProvider(ctx context.Context, cfg *Config) (ports.AiAgentProvider, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
@@ -16,5 +19,23 @@ func NewOllamaProvider(cfg *Config) (ports.AiAgentProvider, error) {
 		return nil, fmt.Errorf("failed to create Ollama client: %w", err)
 	}
 
+	if cfg.VerifyModel {
+		if err := client.EnsureModels(ctx, cfg.AutoPull, newPullProgressLogger()); err != nil {
+			return nil, fmt.Errorf("failed to verify Ollama model: %w", err)
+		}
+	}
+
 	return NewProvider(client), nil
-}
\ No newline at end of file
+}
+
+// newPullProgressLogger logs model download progress whenever the status changes
+func newPullProgressLogger() func(PullProgress) {
+	var lastStatus string
+	return func(p PullProgress) {
+		if p.Status == lastStatus {
+			return
+		}
+		lastStatus = p.Status
+		log.Printf("Ollama: %s", p.Status)
+	}
+}
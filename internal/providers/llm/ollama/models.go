@@ -0,0 +1,201 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrModelNotFound = errors.New("model is not available on the Ollama server")
+)
+
+// ModelInfo describes a model available on the Ollama server
+type ModelInfo struct {
+	Name       string    `json:"name"`
+	Model      string    `json:"model"`
+	Size       int64     `json:"size"`
+	Digest     string    `json:"digest"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// ListModelsResponse represents an Ollama /api/tags response
+type ListModelsResponse struct {
+	Models []ModelInfo `json:"models"`
+}
+
+// PullRequest represents an Ollama /api/pull request
+type PullRequest struct {
+	Model  string `json:"model"`
+	Stream bool   `json:"stream"`
+}
+
+// PullProgress is a progress update streamed while a model is pulled
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Percent returns the download progress of the current layer, or 0 when unknown
+func (p PullProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return float64(p.Completed) / float64(p.Total) * 100
+}
+
+// ListModels returns the models available on the Ollama server
+func (c *Client) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	endpoint := strings.TrimRight(c.config.ServerURL, "/") + "/api/tags"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+	}
+
+	var response ListModelsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return response.Models, nil
+}
+
+// HasModel checks if the model is available on the Ollama server.
+// Names without a tag match the "latest" tag.
+func (c *Client) HasModel(ctx context.Context, model string) (bool, error) {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	want := normalizeModelName(model)
+	for _, m := range models {
+		if normalizeModelName(m.Name) == want || normalizeModelName(m.Model) == want {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PullModel downloads a model to the Ollama server, reporting progress if a callback is given. The download
+// stops when ctx is canceled or after the pull timeout of the configuration.
+func (c *Client) PullModel(ctx context.Context, model string, progress func(PullProgress)) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.pullTimeout())
+	defer cancel()
+
+	jsonData, err := json.Marshal(PullRequest{Model: model, Stream: true})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := strings.TrimRight(c.config.ServerURL, "/") + "/api/pull"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Pulling a model takes far longer than a regular request, so the pull timeout limits it instead
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	var last PullProgress
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var update PullProgress
+		if err := json.Unmarshal(line, &update); err != nil {
+			return fmt.Errorf("failed to parse pull progress: %w", err)
+		}
+		if update.Error != "" {
+			return fmt.Errorf("failed to pull model %s: %s", model, update.Error)
+		}
+		if progress != nil {
+			progress(update)
+		}
+		last = update
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read pull progress: %w", err)
+	}
+
+	if last.Status != "success" {
+		return fmt.Errorf("pull of model %s did not complete", model)
+	}
+
+	return nil
+}
+
+// EnsureModels verifies that the chat and embedding models are available.
+// Missing models are pulled when autoPull is set; otherwise ErrModelNotFound is returned.
+func (c *Client) EnsureModels(ctx context.Context, autoPull bool, progress func(PullProgress)) error {
+	models := []string{c.config.Model}
+	if embedding := c.config.embeddingModel(); normalizeModelName(embedding) != normalizeModelName(c.config.Model) {
+		models = append(models, embedding)
+	}
+
+	for _, model := range models {
+		ok, err := c.HasModel(ctx, model)
+		if err != nil {
+			return fmt.Errorf("failed to check model %s: %w", model, err)
+		}
+		if ok {
+			continue
+		}
+		if !autoPull {
+			return fmt.Errorf("%s: %w (run `ollama pull %s`)", model, ErrModelNotFound, model)
+		}
+		if err := c.PullModel(ctx, model, progress); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeModelName adds the implicit "latest" tag to model names
+func normalizeModelName(name string) string {
+	name = strings.TrimSpace(name)
+	if name != "" && !strings.Contains(name, ":") {
+		name += ":latest"
+	}
+	return name
+}
@@ -0,0 +1,120 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_HasModel(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/tags", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(ListModelsResponse{Models: []ModelInfo{
+			{Name: "llama3:latest"},
+			{Name: "nomic-embed-text:v1.5"},
+		}})
+	})
+
+	tests := []struct {
+		model string
+		want  bool
+	}{
+		{model: "llama3", want: true},
+		{model: "llama3:latest", want: true},
+		{model: "nomic-embed-text", want: false},
+		{model: "nomic-embed-text:v1.5", want: true},
+		{model: "mistral", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			ok, err := client.HasModel(context.Background(), tt.model)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestClient_PullModel(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/pull", r.URL.Path)
+		fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+		fmt.Fprintln(w, `{"status":"downloading","digest":"sha256:abc","total":200,"completed":100}`)
+		fmt.Fprintln(w, `{"status":"success"}`)
+	})
+
+	var updates []PullProgress
+	err := client.PullModel(context.Background(), "llama3", func(p PullProgress) {
+		updates = append(updates, p)
+	})
+	require.NoError(t, err)
+	require.Len(t, updates, 3)
+	assert.Equal(t, 50.0, updates[1].Percent())
+}
+
+func TestClient_PullModel_Error(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"error":"pull model manifest: file does not exist"}`)
+	})
+
+	err := client.PullModel(context.Background(), "missing", nil)
+	assert.Error(t, err)
+}
+
+func TestClient_PullModel_Timeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	client.config.PullTimeout = 20 * time.Millisecond
+
+	err := client.PullModel(context.Background(), "llama3", nil)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewOllamaProvider_StopsVerifyingWhenCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	cfg := NewDefaultConfig(server.URL, "llama3")
+	cfg.VerifyModel = true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewOllamaProvider(ctx, cfg)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_EnsureModels(t *testing.T) {
+	pulled := false
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_ = json.NewEncoder(w).Encode(ListModelsResponse{})
+		case "/api/pull":
+			pulled = true
+			fmt.Fprintln(w, `{"status":"success"}`)
+		}
+	})
+
+	err := client.EnsureModels(context.Background(), false, nil)
+	assert.True(t, errors.Is(err, ErrModelNotFound))
+	assert.False(t, pulled)
+
+	err = client.EnsureModels(context.Background(), true, nil)
+	require.NoError(t, err)
+	assert.True(t, pulled)
+}
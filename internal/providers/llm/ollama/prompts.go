@@ -1,8 +1,39 @@
 package ollama
 
+// PromptVersion is the version of the latest analysis prompt. Add a new version instead of editing a released
+// prompt, and describe the change in the prompt changelog of the llm package.
+const PromptVersion = "v1"
+
+// analyzeMessageSystemPrompts holds every released analysis prompt, oldest first, so projects can pin one
+var analyzeMessageSystemPrompts = []struct {
+	version string
+	prompt  string
+}{
+	{version: "v1", prompt: analyzeMessageSystemPromptV1},
+}
+
+// promptVersions lists the analysis prompt versions, oldest first
+func promptVersions() []string {
+	versions := make([]string, 0, len(analyzeMessageSystemPrompts))
+	for _, p := range analyzeMessageSystemPrompts {
+		versions = append(versions, p.version)
+	}
+	return versions
+}
+
+// analyzeMessageSystemPrompt returns the analysis prompt of a version
+func analyzeMessageSystemPrompt(version string) (string, bool) {
+	for _, p := range analyzeMessageSystemPrompts {
+		if p.version == version {
+			return p.prompt, true
+		}
+	}
+	return "", false
+}
+
 const (
-	// System prompt for analyzing messages
-	analyzeMessageSystemPrompt = `You are a message analyzer for a knowledge management system. Your task is to analyze messages and categorize them. Return the analysis in JSON format with the following structure:
+	// System prompt for analyzing messages, version 1
+	analyzeMessageSystemPromptV1 = `You are a message analyzer for a knowledge management system. Your task is to analyze messages and categorize them. Return the analysis in JSON format with the following structure:
 {
   "Type": "idea" | "decision" | "status" | "information" | "unknown",
   "Category": "operations" | "development" | "product" | "quality_assurance" | "data_analysis" | "other" | "unknown",
@@ -60,23 +91,103 @@ Available categories:
 Analyze the content carefully and respond with ONLY the most appropriate category name (single word, lowercase).`
 
 	// System prompt for detecting references
-	detectReferencesSystemPrompt = `You are a reference detector for a knowledge management system. Your task is to identify any references to messages or documents in the given content.
+	detectReferencesSystemPrompt = `You are a reference detector for a knowledge management system. Your task is to identify any references to messages, documents, links, issues, or people in the given content.
 
 A reference can be:
 1. A message reference: References to specific messages or conversations
 2. A document reference: References to documents, files, or other knowledge artifacts
+3. A url reference: External links (must start with http:// or https://)
+4. An issue reference: Issue tracker keys like PROJ-123, #42, or owner/repo#42
+5. A user reference: Mentioned people, as user IDs (U024BE7LH) or handles (@jane)
 
 Look for:
 - Explicit references like "as mentioned in document X" or "as discussed in message Y"
 - IDs or identifiers that might refer to messages or documents
 - Links or paths to documents
+- Issue keys and ticket numbers
+- Mentions of teammates
 - References to past conversations or decisions
 
 Return the detected references in JSON format as an array of objects with "type" and "value" fields:
 [
   {"type": "message", "value": "<message_identifier>"},
-  {"type": "document", "value": "<document_path_or_identifier>"}
+  {"type": "document", "value": "<document_path_or_identifier>"},
+  {"type": "url", "value": "<https_link>"},
+  {"type": "issue", "value": "<issue_key>"},
+  {"type": "user", "value": "<user_id_or_handle>"}
 ]
 
 If no references are found, return an empty array: []`
-)
\ No newline at end of file
+
+	// System prompt for naming generated documentation
+	generateTitleSystemPrompt = `You are a documentation editor for a knowledge management system. Your task is to write a short title for the given message.
+
+Rules:
+1. Use 2 to 6 words that describe the subject, like "Adopt Postgres for billing"
+2. Return only the title, without quotes, punctuation at the end, or Markdown
+3. Use the language of the message`
+
+	// System prompt for answering questions from the knowledge base
+	answerQuestionSystemPrompt = `You are a knowledge base assistant for a team. Answer the user's question using ONLY the numbered sources provided.
+
+Rules:
+1. Cite every statement with the number of the source it comes from, like [1] or [2][3]
+2. If the sources do not contain the answer, say that the knowledge base does not cover it
+3. Do not invent facts, names, dates, or decisions that are not in the sources
+4. Keep the answer short and direct, suitable for a chat message`
+
+	// System prompt for defining glossary terms
+	defineTermSystemPrompt = `You are a glossary editor for a knowledge management system. Your task is to define a term, usually an acronym, used in a team's message.
+
+Rules:
+1. Write one or two plain sentences, starting with the expansion of an acronym when the message or common usage gives it
+2. Define the term as the team uses it in the message, not every meaning it may have
+3. Return only the definition, without the term as a heading, quotes, or Markdown
+4. If the message does not make the meaning clear, answer only UNKNOWN`
+
+	// System prompt for drafting the postmortems of incidents
+	draftPostmortemSystemPrompt = `You are an incident reviewer for a knowledge management system. Your task is to draft the postmortem of an incident from the timeline of the chat thread it was handled in.
+
+Rules:
+1. Write the sections Summary, Impact, Root cause, Resolution and Action items, each as a ### heading
+2. Draw every statement from the timeline, and write "Not clear from the timeline." where it does not tell
+3. List action items as Markdown task lists, naming who took them on when the timeline does
+4. Keep it blameless: describe what systems and processes did, not who was at fault
+5. Return only the sections, without a title or code fences`
+
+	// System prompt for drawing structured notes from the transcript of a meeting
+	summarizeMeetingSystemPrompt = `You are a meeting secretary for a knowledge management system. Your task is to write the notes of a meeting from the transcript of the chat thread it was held in. Return the notes in JSON format with the following structure:
+{
+  "agenda": ["topic", ...],
+  "decisions": [{"title": "short title", "context": "why it came up", "decision": "what was decided", "category": "operations" | "development" | "product" | "quality_assurance" | "data_analysis" | "other"}, ...],
+  "action_items": [{"task": "what to do", "owner": "who took it on", "due": "when it is due"}, ...]
+}
+
+Rules:
+1. List the topics discussed as the agenda, in the order they came up
+2. Only list decisions people agreed on, not proposals or open questions
+3. Leave owner and due empty when the transcript does not tell them
+4. Draw everything from the transcript, do not add anything it does not say
+5. Return only the JSON, without code fences`
+
+	// System prompt for finding the risks raised in messages
+	detectRisksSystemPrompt = `You are a risk analyst for a knowledge management system. Your task is to find the risks a team's message raises, like "we might miss the deadline if the vendor API slips". Return the risks in JSON format with the following structure:
+{
+  "risks": [{"statement": "the risk in one sentence", "severity": "low" | "medium" | "high", "likelihood": "low" | "medium" | "high", "owner": "who looks after it", "mitigation": "how it is kept in check"}, ...]
+}
+
+Rules:
+1. Only list things that may go wrong in the future, not problems that already happened
+2. Rate severity by the harm to the project's goals and likelihood by how the message describes the odds, medium when it does not tell
+3. Leave owner and mitigation empty when the message does not tell them
+4. Return {"risks": []} when the message raises no risk
+5. Return only the JSON, without code fences`
+
+	// Instructions added to the documentation prompt of decisions and architecture discussions
+	diagramInstructions = `
+Add a Mermaid diagram when the message describes a flow, components and their connections, or interactions between systems or people:
+1. Use a flowchart or a sequenceDiagram in a single ` + "```mermaid" + ` code block
+2. Derive the diagram only from the message, do not add steps or components it does not mention
+3. Use simple alphanumeric node IDs, and put labels with punctuation or brackets in double quotes, like A["Call save()"]
+4. Leave the diagram out when the message has nothing to draw`
+)
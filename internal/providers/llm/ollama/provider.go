@@ -23,8 +23,46 @@ func NewProvider(client *Client) *Provider {
 	}
 }
 
-// AnalyzeMessage analyzes message content
+// AnalyzeMessage analyzes message content with the latest analysis prompt
 func (p *Provider) AnalyzeMessage(ctx context.Context, content string) (*domain.MessageAnalysisResult, error) {
+	return p.AnalyzeMessageWithPrompt(ctx, PromptVersion, content, nil)
+}
+
+// AnalyzeMessageWithExamples analyzes message content following the corrections people made to earlier analyses
+func (p *Provider) AnalyzeMessageWithExamples(ctx context.Context, content string, examples []*domain.Correction) (*domain.MessageAnalysisResult, error) {
+	return p.AnalyzeMessageWithPrompt(ctx, PromptVersion, content, examples)
+}
+
+// DataLocation reports that Ollama runs on infrastructure the deployment controls
+func (p *Provider) DataLocation() domain.DataLocation {
+	return domain.DataLocationLocal
+}
+
+// PromptVersions lists the analysis prompt versions, oldest first
+func (p *Provider) PromptVersions() []string {
+	return promptVersions()
+}
+
+// AnalyzeMessageWithPrompt analyzes message content with a pinned analysis prompt version
+func (p *Provider) AnalyzeMessageWithPrompt(ctx context.Context, version, content string, examples []*domain.Correction) (*domain.MessageAnalysisResult, error) {
+	systemPrompt, ok := analyzeMessageSystemPrompt(version)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnknownPromptVersion, version)
+	}
+	if guidance := domain.RenderCorrectionExamples(examples); guidance != "" {
+		systemPrompt += "\n\n" + guidance
+	}
+
+	result, err := p.analyzeMessage(ctx, content, systemPrompt)
+	if err != nil {
+		return nil, err
+	}
+	result.StampPromptVersion(version)
+	result.StampModel("ollama:" + p.client.config.Model)
+	return result, nil
+}
+
+func (p *Provider) analyzeMessage(ctx context.Context, content, systemPrompt string) (*domain.MessageAnalysisResult, error) {
 	if ctx == nil {
 		return nil, fmt.Errorf("context cannot be nil")
 	}
@@ -36,7 +74,7 @@ func (p *Provider) AnalyzeMessage(ctx context.Context, content string) (*domain.
 	messages := []Message{
 		{
 			Role:    "system",
-			Content: analyzeMessageSystemPrompt,
+			Content: systemPrompt,
 		},
 		{
 			Role:    "user",
@@ -213,32 +251,328 @@ func (p *Provider) DetectReferences(ctx context.Context, content string) ([]*dom
 	return references, nil
 }
 
+// Embed returns the embedding vector of the text
+func (p *Provider) Embed(ctx context.Context, text string) ([]float64, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	embedding, err := p.client.GenerateEmbedding(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	return embedding, nil
+}
+
+// GenerateTitle returns a short title describing the content
+func (p *Provider) GenerateTitle(ctx context.Context, content string) (string, error) {
+	if ctx == nil {
+		return "", fmt.Errorf("context cannot be nil")
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return "", fmt.Errorf("content cannot be empty")
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: generateTitleSystemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: content,
+		},
+	}
+
+	response, err := p.client.GenerateChatCompletion(ctx, messages)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chat completion: %w", err)
+	}
+
+	return cleanTitle(response), nil
+}
+
+// DefineTerm writes a one or two sentence glossary definition of a term from the message it was used in.
+// It returns an empty definition when the message does not make the meaning clear.
+func (p *Provider) DefineTerm(ctx context.Context, term, usage string) (string, error) {
+	if ctx == nil {
+		return "", fmt.Errorf("context cannot be nil")
+	}
+
+	if strings.TrimSpace(term) == "" {
+		return "", fmt.Errorf("term cannot be empty")
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: defineTermSystemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Term: %s\n\nMessage:\n%s", term, usage),
+		},
+	}
+
+	response, err := p.client.GenerateChatCompletion(ctx, messages)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chat completion: %w", err)
+	}
+
+	return cleanDefinition(response, term), nil
+}
+
+// DraftPostmortem drafts the postmortem sections of an incident from its timeline, leaving out what the
+// timeline does not tell
+func (p *Provider) DraftPostmortem(ctx context.Context, title, timeline string) (string, error) {
+	if ctx == nil {
+		return "", fmt.Errorf("context cannot be nil")
+	}
+
+	if strings.TrimSpace(timeline) == "" {
+		return "", fmt.Errorf("timeline cannot be empty")
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: draftPostmortemSystemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Incident: %s\n\nTimeline:\n%s", title, timeline),
+		},
+	}
+
+	response, err := p.client.GenerateChatCompletion(ctx, messages)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chat completion: %w", err)
+	}
+
+	return cleanPostmortem(response), nil
+}
+
+// SummarizeMeeting draws the agenda, decisions and action items of a meeting from its transcript
+func (p *Provider) SummarizeMeeting(ctx context.Context, title, transcript string) (*domain.MeetingSummary, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	if strings.TrimSpace(transcript) == "" {
+		return nil, fmt.Errorf("transcript cannot be empty")
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: summarizeMeetingSystemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Meeting: %s\n\nTranscript:\n%s", title, transcript),
+		},
+	}
+
+	response, err := p.client.GenerateChatCompletion(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate chat completion: %w", err)
+	}
+
+	var summary domain.MeetingSummary
+	if err := json.Unmarshal([]byte(stripCodeFence(response)), &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse meeting summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// DetectRisks finds the risks a message raises, rated by severity and likelihood
+func (p *Provider) DetectRisks(ctx context.Context, content string) ([]domain.RiskStatement, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("content cannot be empty")
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: detectRisksSystemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: content,
+		},
+	}
+
+	response, err := p.client.GenerateChatCompletion(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate chat completion: %w", err)
+	}
+
+	var result struct {
+		Risks []domain.RiskStatement `json:"risks"`
+	}
+	if err := json.Unmarshal([]byte(stripCodeFence(response)), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse risks: %w", err)
+	}
+	return result.Risks, nil
+}
+
+// AnswerQuestion answers a question grounded in the given sources
+func (p *Provider) AnswerQuestion(ctx context.Context, question string, conversation *domain.Conversation, sources []*domain.AnswerSource) (string, error) {
+	if ctx == nil {
+		return "", fmt.Errorf("context cannot be nil")
+	}
+
+	if strings.TrimSpace(question) == "" {
+		return "", fmt.Errorf("question cannot be empty")
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: answerQuestionSystemPrompt,
+		},
+	}
+
+	// Earlier turns let follow-up questions omit what was already said
+	if conversation != nil {
+		for _, turn := range conversation.Turns() {
+			messages = append(messages, Message{Role: "user", Content: turn.Question()})
+			if turn.Answer() != "" {
+				messages = append(messages, Message{Role: "assistant", Content: turn.Answer()})
+			}
+		}
+	}
+
+	messages = append(messages, Message{
+		Role:    "user",
+		Content: answerQuestionPrompt(question, sources),
+	})
+
+	response, err := p.client.GenerateChatCompletion(ctx, messages)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chat completion: %w", err)
+	}
+
+	return strings.TrimSpace(response), nil
+}
+
+// cleanTitle keeps the first line of a title response without labels, quotes and Markdown
+func cleanTitle(response string) string {
+	title := strings.TrimSpace(response)
+	if idx := strings.Index(title, "\n"); idx >= 0 {
+		title = title[:idx]
+	}
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	if strings.HasPrefix(strings.ToLower(title), "title:") {
+		title = strings.TrimSpace(title[len("title:"):])
+	}
+	return strings.TrimRight(strings.Trim(title, "\"'*`"), ".")
+}
+
+// cleanPostmortem drops the title and code fence models tend to put around a postmortem, the report has its own
+func cleanPostmortem(response string) string {
+	postmortem := strings.TrimSpace(response)
+	if strings.HasPrefix(postmortem, "```") {
+		postmortem = strings.TrimPrefix(postmortem, "```markdown")
+		postmortem = strings.TrimPrefix(postmortem, "```")
+		postmortem = strings.TrimSpace(strings.TrimSuffix(postmortem, "```"))
+	}
+	if strings.HasPrefix(postmortem, "# ") {
+		if idx := strings.Index(postmortem, "\n"); idx >= 0 {
+			return strings.TrimSpace(postmortem[idx+1:])
+		}
+		return ""
+	}
+	return postmortem
+}
+
+// stripCodeFence drops the code fence models tend to put around JSON
+func stripCodeFence(response string) string {
+	response = strings.TrimSpace(response)
+	if !strings.HasPrefix(response, "```") {
+		return response
+	}
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	return strings.TrimSpace(strings.TrimSuffix(response, "```"))
+}
+
+// cleanDefinition strips the term and Markdown models tend to put around a definition
+func cleanDefinition(response, term string) string {
+	definition := strings.Join(strings.Fields(response), " ")
+	for _, prefix := range []string{"definition:", "**" + strings.ToLower(term) + "**:", strings.ToLower(term) + ":"} {
+		if strings.HasPrefix(strings.ToLower(definition), prefix) {
+			definition = strings.TrimSpace(definition[len(prefix):])
+		}
+	}
+	if strings.EqualFold(strings.Trim(definition, ".\"'*` "), "unknown") {
+		return ""
+	}
+	return definition
+}
+
+// Build the question prompt with numbered sources
+func answerQuestionPrompt(question string, sources []*domain.AnswerSource) string {
+	var b strings.Builder
+	b.WriteString("Sources:\n\n")
+	for i, source := range sources {
+		b.WriteString(fmt.Sprintf("[%d] %s (%s)\n%s\n\n", i+1, source.Title(), source.Path(), source.Content()))
+	}
+	b.WriteString(fmt.Sprintf("Question: %s", question))
+	return b.String()
+}
+
 // Generate documentation prompt with metadata
 func generateDocumentationPrompt(message string, metadata map[string]interface{}) string {
-	prompt := fmt.Sprintf("Generate comprehensive documentation from the following message:\n\n%s\n\n", message)
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Generate comprehensive documentation from the following message:\n\n%s\n\n", message))
 	
 	if metadata != nil {
-		prompt += "Additional context:\n"
+		b.WriteString("Additional context:\n")
 		if msgType, ok := metadata["type"].(string); ok {
-			prompt += fmt.Sprintf("- Type: %s\n", msgType)
+			b.WriteString(fmt.Sprintf("- Type: %s\n", msgType))
 		}
 		if category, ok := metadata["category"].(string); ok {
-			prompt += fmt.Sprintf("- Category: %s\n", category)
+			b.WriteString(fmt.Sprintf("- Category: %s\n", category))
 		}
 		if timestamp, ok := metadata["created_at"].(string); ok {
-			prompt += fmt.Sprintf("- Created: %s\n", timestamp)
+			b.WriteString(fmt.Sprintf("- Created: %s\n", timestamp))
 		}
 		if refs, ok := metadata["references"].([]*domain.Reference); ok && len(refs) > 0 {
-			prompt += "- References:\n"
+			b.WriteString("- References:\n")
 			for _, ref := range refs {
-				prompt += fmt.Sprintf("  - %s: %s\n", ref.Type(), ref.Value())
+				b.WriteString(fmt.Sprintf("  - %s: %s\n", ref.Type(), ref.Value()))
 			}
 		}
 	}
 	
-	prompt += "\nFormat the documentation in Markdown with proper sections, headings, and formatting."
+	b.WriteString("\nFormat the documentation in Markdown with proper sections, headings, and formatting.")
+	if template, ok := metadata["template"].(string); ok && template != "" {
+		b.WriteString("\nUse these sections, in this order, and leave out the ones the message says nothing about:\n")
+		b.WriteString(template)
+	}
+	if diagrams, ok := metadata["diagrams"].(bool); ok && diagrams {
+		b.WriteString("\n")
+		b.WriteString(diagramInstructions)
+	}
+	if issues, ok := metadata["lint_issues"].([]string); ok && len(issues) > 0 {
+		b.WriteString("\n\nA previous draft had these problems, do not repeat them:\n")
+		for _, issue := range issues {
+			b.WriteString(fmt.Sprintf("- %s\n", issue))
+		}
+	}
 	
-	return prompt
+	return b.String()
 }
 
 // Helper function to parse analysis results from unstructured text
@@ -289,15 +623,11 @@ func parseReferencesFromText(text string) ([]*domain.Reference, error) {
 		if strings.Contains(line, ":") {
 			parts := strings.SplitN(line, ":", 2)
 			if len(parts) == 2 {
-				typeStr := strings.TrimSpace(strings.ToLower(parts[0]))
+				typeStr := strings.TrimLeft(strings.TrimSpace(strings.ToLower(parts[0])), "-* ")
 				valueStr := strings.TrimSpace(parts[1])
 				
-				var refType domain.ReferenceType
-				if typeStr == "message" {
-					refType = domain.ReferenceTypeMessage
-				} else if typeStr == "document" {
-					refType = domain.ReferenceTypeDocument
-				} else {
+				refType := domain.ReferenceType(typeStr)
+				if !refType.IsValid() || refType.IsUnknown() {
 					continue
 				}
 				
@@ -0,0 +1,232 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_GenerateTitle(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, generateTitleSystemPrompt, req.Messages[0].Content)
+		assert.Equal(t, "We will use Postgres for billing", req.Messages[1].Content)
+
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			Model:   "llama3",
+			Message: Message{Role: "assistant", Content: "Title: \"Adopt Postgres for billing.\"\nThe team decided..."},
+			Done:    true,
+		})
+	})
+
+	title, err := NewProvider(client).GenerateTitle(context.Background(), "We will use Postgres for billing")
+	require.NoError(t, err)
+	assert.Equal(t, "Adopt Postgres for billing", title)
+}
+
+func TestProvider_AnalyzeMessageWithExamples(t *testing.T) {
+	msg, err := domain.NewMessage(common.GenerateID(), "alice", domain.MustNewMessageContent("Lunch at noon"), domain.MessageTypeStatus, domain.CategoryOther, nil)
+	require.NoError(t, err)
+	correction, err := domain.NewCorrection(msg, domain.CorrectionFieldDiscard, "status", "unknown", "U0001")
+	require.NoError(t, err)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Messages, 2)
+		assert.True(t, strings.HasPrefix(req.Messages[0].Content, analyzeMessageSystemPromptV1))
+		assert.Contains(t, req.Messages[0].Content, `- "Lunch at noon" was not worth documenting`)
+		assert.Equal(t, "Coffee at 3pm", req.Messages[1].Content)
+
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			Model:   "llama3",
+			Message: Message{Role: "assistant", Content: `{"Type": "unknown", "Category": "other", "ConfidenceScore": 0.9}`},
+			Done:    true,
+		})
+	})
+
+	result, err := NewProvider(client).AnalyzeMessageWithExamples(context.Background(), "Coffee at 3pm", []*domain.Correction{correction})
+	require.NoError(t, err)
+	assert.Equal(t, domain.MessageTypeUnknown, result.MessageType())
+	assert.Equal(t, PromptVersion, result.PromptVersion())
+	assert.Equal(t, "ollama:"+client.config.Model, result.Model())
+}
+
+func TestProvider_AnalyzeMessageWithPrompt_UnknownVersion(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unknown prompt versions must not reach the model")
+	})
+
+	provider := NewProvider(client)
+	assert.Equal(t, []string{"v1"}, provider.PromptVersions())
+
+	_, err := provider.AnalyzeMessageWithPrompt(context.Background(), "v0", "Coffee at 3pm", nil)
+	assert.ErrorIs(t, err, domain.ErrUnknownPromptVersion)
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := []struct {
+		response string
+		want     string
+	}{
+		{response: "Adopt Postgres", want: "Adopt Postgres"},
+		{response: "# Adopt Postgres\n\nMore text", want: "Adopt Postgres"},
+		{response: "  **Adopt Postgres.**  ", want: "Adopt Postgres"},
+		{response: "title: `Adopt Postgres`", want: "Adopt Postgres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.response, func(t *testing.T) {
+			assert.Equal(t, tt.want, cleanTitle(tt.response))
+		})
+	}
+}
+
+func TestGenerateDocumentationPrompt_Diagrams(t *testing.T) {
+	prompt := generateDocumentationPrompt("The API publishes orders to the queue", map[string]interface{}{"type": "decision"})
+	assert.NotContains(t, prompt, "Mermaid")
+
+	prompt = generateDocumentationPrompt("The API publishes orders to the queue", map[string]interface{}{"type": "decision", "diagrams": true})
+	assert.Contains(t, prompt, "- Type: decision")
+	assert.True(t, strings.HasSuffix(prompt, diagramInstructions))
+}
+
+func TestGenerateDocumentationPrompt_LintIssues(t *testing.T) {
+	prompt := generateDocumentationPrompt("The API publishes orders to the queue", map[string]interface{}{
+		"type":        "decision",
+		"lint_issues": []string{"heading: the document has no title"},
+	})
+	assert.True(t, strings.HasSuffix(prompt, "A previous draft had these problems, do not repeat them:\n- heading: the document has no title\n"))
+}
+
+func TestGenerateDocumentationPrompt_Template(t *testing.T) {
+	prompt := generateDocumentationPrompt("We decided to publish orders to the queue", map[string]interface{}{
+		"type":     "decision",
+		"template": "## Context\n\n## Decision\n",
+	})
+	assert.Contains(t, prompt, "Use these sections, in this order, and leave out the ones the message says nothing about:\n## Context\n\n## Decision\n")
+}
+
+func TestProvider_DefineTerm(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, defineTermSystemPrompt, req.Messages[0].Content)
+		assert.Equal(t, "Term: SLA\n\nMessage:\nThe SLA for billing is 99.9%", req.Messages[1].Content)
+
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			Model:   "llama3",
+			Message: Message{Role: "assistant", Content: "**SLA**: Service level agreement,\nthe uptime promised to customers."},
+			Done:    true,
+		})
+	})
+
+	definition, err := NewProvider(client).DefineTerm(context.Background(), "SLA", "The SLA for billing is 99.9%")
+	require.NoError(t, err)
+	assert.Equal(t, "Service level agreement, the uptime promised to customers.", definition)
+}
+
+func TestProvider_DraftPostmortem(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, draftPostmortemSystemPrompt, req.Messages[0].Content)
+		assert.Equal(t, "Incident: Payments API down\n\nTimeline:\n- **14:02** bob: Rolled back to v1.4.1\n", req.Messages[1].Content)
+
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			Model:   "llama3",
+			Message: Message{Role: "assistant", Content: "```markdown\n# Postmortem\n\n### Summary\n\nA deploy broke the payments API.\n```"},
+			Done:    true,
+		})
+	})
+
+	postmortem, err := NewProvider(client).DraftPostmortem(context.Background(), "Payments API down", "- **14:02** bob: Rolled back to v1.4.1\n")
+	require.NoError(t, err)
+	assert.Equal(t, "### Summary\n\nA deploy broke the payments API.", postmortem)
+
+	_, err = NewProvider(client).DraftPostmortem(context.Background(), "Payments API down", " ")
+	assert.Error(t, err)
+}
+
+func TestProvider_SummarizeMeeting(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, summarizeMeetingSystemPrompt, req.Messages[0].Content)
+		assert.Equal(t, "Meeting: Billing sync\n\nTranscript:\n- **14:02** bob: Let's queue invoice rendering\n", req.Messages[1].Content)
+
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			Model: "llama3",
+			Message: Message{Role: "assistant", Content: "```json\n" +
+				`{"agenda": ["Late invoices"], "decisions": [{"title": "Queue invoice rendering", "decision": "Render invoices in a worker queue", "category": "development"}], "action_items": [{"task": "Set up the queue", "owner": "bob"}]}` +
+				"\n```"},
+			Done: true,
+		})
+	})
+
+	summary, err := NewProvider(client).SummarizeMeeting(context.Background(), "Billing sync", "- **14:02** bob: Let's queue invoice rendering\n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Late invoices"}, summary.Agenda)
+	require.Len(t, summary.Decisions, 1)
+	assert.Equal(t, "Render invoices in a worker queue", summary.Decisions[0].Decision)
+	assert.Equal(t, []domain.ActionItem{{Task: "Set up the queue", Owner: "bob"}}, summary.ActionItems)
+
+	_, err = NewProvider(client).SummarizeMeeting(context.Background(), "Billing sync", " ")
+	assert.Error(t, err)
+}
+
+func TestProvider_DetectRisks(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, detectRisksSystemPrompt, req.Messages[0].Content)
+		assert.Equal(t, "We might miss the deadline if the vendor API slips", req.Messages[1].Content)
+
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			Model: "llama3",
+			Message: Message{Role: "assistant", Content: "```json\n" +
+				`{"risks": [{"statement": "The deadline slips with the vendor API", "severity": "high", "likelihood": "medium"}]}` +
+				"\n```"},
+			Done: true,
+		})
+	})
+
+	risks, err := NewProvider(client).DetectRisks(context.Background(), "We might miss the deadline if the vendor API slips")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.RiskStatement{{Statement: "The deadline slips with the vendor API", Severity: "high", Likelihood: "medium"}}, risks)
+
+	_, err = NewProvider(client).DetectRisks(context.Background(), " ")
+	assert.Error(t, err)
+}
+
+func TestCleanDefinition(t *testing.T) {
+	tests := []struct {
+		response string
+		want     string
+	}{
+		{response: "Service level agreement.", want: "Service level agreement."},
+		{response: "SLA: Service level agreement.", want: "Service level agreement."},
+		{response: "Definition: Service level\nagreement.", want: "Service level agreement."},
+		{response: "UNKNOWN", want: ""},
+		{response: "Unknown.", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.response, func(t *testing.T) {
+			assert.Equal(t, tt.want, cleanDefinition(tt.response, "SLA"))
+		})
+	}
+}
@@ -3,9 +3,10 @@ package openai
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
+	"github.com/massimo-ua/quill/internal/providers/transport"
 	"net/http"
 	"strings"
 	"time"
@@ -14,6 +15,12 @@ import (
 const (
 	// DefaultAPIURL is the default OpenAI API URL
 	DefaultAPIURL = "https://api.openai.com/v1"
+	// DefaultEmbeddingModel is the embedding model used when none is configured
+	DefaultEmbeddingModel = "text-embedding-3-small"
+	// DefaultVisionModel is the model reading images when none is configured
+	DefaultVisionModel = "gpt-4o-mini"
+	// DefaultModerationModel is the model moderating content when none is configured
+	DefaultModerationModel = "omni-moderation-latest"
 	// DefaultTimeout is the default timeout for HTTP requests
 	DefaultTimeout = 60 * time.Second
 )
@@ -49,6 +56,59 @@ type ChatCompletionResponse struct {
 	} `json:"usage"`
 }
 
+// VisionCompletionRequest represents a chat completion request whose user message holds an image
+type VisionCompletionRequest struct {
+	Model     string          `json:"model"`
+	Messages  []VisionMessage `json:"messages"`
+	MaxTokens int             `json:"max_tokens,omitempty"`
+}
+
+// VisionMessage represents a chat message made of text and image parts
+type VisionMessage struct {
+	Role    string        `json:"role"`
+	Content []ContentPart `json:"content"`
+}
+
+// ContentPart represents a text or an image part of a message
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL represents an image, passed inline as a data URL
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// EmbeddingRequest represents an embeddings request
+type EmbeddingRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+// EmbeddingResponse represents an embeddings response
+type EmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// ModerationRequest represents a moderation request
+type ModerationRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+// ModerationResponse represents a moderation response
+type ModerationResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
 // Client represents an OpenAI API client
 type Client struct {
 	config     *Config
@@ -71,8 +131,9 @@ func NewClient(cfg *Config) (*Client, error) {
 		baseURL = strings.TrimRight(cfg.BaseURL, "/")
 	}
 
-	httpClient := &http.Client{
-		Timeout: DefaultTimeout,
+	httpClient, err := transport.NewHTTPClient(cfg.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
 
 	return &Client{
@@ -84,10 +145,6 @@ func NewClient(cfg *Config) (*Client, error) {
 
 // CreateChatCompletion sends a chat completion request to the OpenAI API
 func (c *Client) CreateChatCompletion(ctx context.Context, messages []Message) (string, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	endpoint := fmt.Sprintf("%s/chat/completions", c.baseURL)
 
 	request := ChatCompletionRequest{
@@ -97,43 +154,134 @@ func (c *Client) CreateChatCompletion(ctx context.Context, messages []Message) (
 		MaxTokens:   c.config.MaxTokens,
 	}
 
+	var completionResponse ChatCompletionResponse
+	if err := c.postJSON(ctx, endpoint, request, &completionResponse); err != nil {
+		return "", err
+	}
+
+	if len(completionResponse.Choices) == 0 {
+		return "", fmt.Errorf("no completions returned")
+	}
+
+	return completionResponse.Choices[0].Message.Content, nil
+}
+
+// CreateVisionCompletion sends a chat completion request for an image to the OpenAI API
+func (c *Client) CreateVisionCompletion(ctx context.Context, systemPrompt, mimeType string, image []byte) (string, error) {
+	endpoint := fmt.Sprintf("%s/chat/completions", c.baseURL)
+
+	model := c.config.VisionModel
+	if strings.TrimSpace(model) == "" {
+		model = DefaultVisionModel
+	}
+
+	request := VisionCompletionRequest{
+		Model: model,
+		Messages: []VisionMessage{
+			{Role: "system", Content: []ContentPart{{Type: "text", Text: systemPrompt}}},
+			{Role: "user", Content: []ContentPart{{
+				Type:     "image_url",
+				ImageURL: &ImageURL{URL: "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(image)},
+			}}},
+		},
+		MaxTokens: c.config.MaxTokens,
+	}
+
+	var completionResponse ChatCompletionResponse
+	if err := c.postJSON(ctx, endpoint, request, &completionResponse); err != nil {
+		return "", err
+	}
+
+	if len(completionResponse.Choices) == 0 {
+		return "", fmt.Errorf("no completions returned")
+	}
+
+	return completionResponse.Choices[0].Message.Content, nil
+}
+
+// CreateEmbedding sends an embeddings request to the OpenAI API
+func (c *Client) CreateEmbedding(ctx context.Context, input string) ([]float64, error) {
+	endpoint := fmt.Sprintf("%s/embeddings", c.baseURL)
+
+	model := c.config.EmbeddingModel
+	if strings.TrimSpace(model) == "" {
+		model = DefaultEmbeddingModel
+	}
+
+	request := EmbeddingRequest{
+		Model: model,
+		Input: input,
+	}
+
+	var embeddingResponse EmbeddingResponse
+	if err := c.postJSON(ctx, endpoint, request, &embeddingResponse); err != nil {
+		return nil, err
+	}
+
+	if len(embeddingResponse.Data) == 0 {
+		return nil, fmt.Errorf("no embeddings returned")
+	}
+
+	return embeddingResponse.Data[0].Embedding, nil
+}
+
+// CreateModeration sends a moderation request to the OpenAI API
+func (c *Client) CreateModeration(ctx context.Context, input string) (*ModerationResponse, error) {
+	endpoint := fmt.Sprintf("%s/moderations", c.baseURL)
+
+	model := c.config.ModerationModel
+	if strings.TrimSpace(model) == "" {
+		model = DefaultModerationModel
+	}
+
+	var moderationResponse ModerationResponse
+	if err := c.postJSON(ctx, endpoint, ModerationRequest{Model: model, Input: input}, &moderationResponse); err != nil {
+		return nil, err
+	}
+
+	if len(moderationResponse.Results) == 0 {
+		return nil, fmt.Errorf("no moderation results returned")
+	}
+
+	return &moderationResponse, nil
+}
+
+// postJSON sends a JSON request and decodes the JSON response
+func (c *Client) postJSON(ctx context.Context, endpoint string, request interface{}, response interface{}) error {
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	c.addHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	buf, err := transport.ReadBody(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer transport.PutBuffer(buf)
+	body := buf.Bytes()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
 	}
 
-	var completionResponse ChatCompletionResponse
-	if err := json.Unmarshal(body, &completionResponse); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	if len(completionResponse.Choices) == 0 {
-		return "", fmt.Errorf("no completions returned")
-	}
-
-	return completionResponse.Choices[0].Message.Content, nil
+	return nil
 }
 
 // addHeaders adds required headers to the request
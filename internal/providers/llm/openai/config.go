@@ -2,6 +2,7 @@ package openai
 
 import (
 	"errors"
+	"github.com/massimo-ua/quill/internal/providers/transport"
 	"strings"
 )
 
@@ -31,6 +32,18 @@ type Config struct {
 
 	// Organization is the OpenAI organization ID (optional)
 	Organization string
+
+	// EmbeddingModel is the model used for embeddings (optional, default: text-embedding-3-small)
+	EmbeddingModel string
+
+	// VisionModel is the model reading images shared with messages (optional, default: gpt-4o-mini)
+	VisionModel string
+
+	// ModerationModel is the model moderating content (optional, default: omni-moderation-latest)
+	ModerationModel string
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
 }
 
 // NewDefaultConfig creates a Config with default values
@@ -61,5 +74,12 @@ func (c *Config) Validate() error {
 		return ErrInvalidMaxTokens
 	}
 
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,78 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_DescribeImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+
+		var request VisionCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, DefaultVisionModel, request.Model)
+		require.Len(t, request.Messages, 2)
+		assert.Equal(t, describeImageSystemPrompt, request.Messages[0].Content[0].Text)
+		require.NotNil(t, request.Messages[1].Content[0].ImageURL)
+		assert.Equal(t, "data:image/png;base64,cG5n", request.Messages[1].Content[0].ImageURL.URL)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": "\nDiagram: API -> queue -> worker\n"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig("sk-test123", "gpt-4")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	description, err := NewProvider(client).DescribeImage(context.Background(), "image/png", []byte("png"))
+	require.NoError(t, err)
+	assert.Equal(t, "Diagram: API -> queue -> worker", description)
+
+	_, err = NewProvider(client).DescribeImage(context.Background(), "image/png", nil)
+	assert.Error(t, err)
+}
+
+func TestProvider_Moderate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderations", r.URL.Path)
+
+		var request ModerationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, DefaultModerationModel, request.Model)
+		assert.Equal(t, "You are all idiots", request.Input)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{
+				{"flagged": true, "category_scores": map[string]float64{"harassment": 0.91, "violence": 0.02}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig("sk-test123", "gpt-4")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	result, err := NewProvider(client).Moderate(context.Background(), "You are all idiots")
+	require.NoError(t, err)
+	assert.True(t, result.Flagged)
+	category, score := result.Top()
+	assert.Equal(t, "harassment", category)
+	assert.Equal(t, 0.91, score)
+
+	_, err = NewProvider(client).Moderate(context.Background(), " ")
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/nats-io/nats.go"
+)
+
+// Queue implements the ports.MessageQueue interface with core NATS. Workers join a queue group,
+// so each message is processed by one of them. Core NATS keeps no messages: those published while
+// no worker is subscribed and those the handler fails on are not delivered again, the message
+// tracker keeps the latter as failed. After losing the server the client reconnects and
+// subscribes again on its own, messages published meanwhile are buffered until it is back.
+type Queue struct {
+	config *Config
+
+	mu   sync.Mutex
+	conn *nats.Conn
+}
+
+// NewQueue creates a NATS message queue, it connects on first use
+func NewQueue(cfg *Config) (*Queue, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Queue{config: cfg}, nil
+}
+
+// Publish sends a message and waits until the server received it, for at most the dial timeout when ctx has
+// no earlier deadline
+func (q *Queue) Publish(ctx context.Context, msg *domain.QueuedMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	payload, err := json.Marshal(msg.ToDTO())
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	nc, err := q.connection(ctx)
+	if err != nil {
+		return err
+	}
+	if err := nc.Publish(q.config.Subject, payload); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.dialTimeout())
+	defer cancel()
+	if err := nc.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// Consume joins the queue group and passes messages to handle until ctx is canceled or the queue is closed
+func (q *Queue) Consume(ctx context.Context, handle func(ctx context.Context, msg *domain.QueuedMessage) error) error {
+	nc, err := q.connection(ctx)
+	if err != nil {
+		return err
+	}
+
+	sub, err := nc.QueueSubscribeSync(q.config.Subject, q.config.QueueGroup)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	for {
+		received, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, nats.ErrConnectionClosed) || errors.Is(err, nats.ErrBadSubscription) {
+				return fmt.Errorf("NATS connection closed: %w", err)
+			}
+			return fmt.Errorf("failed to receive message: %w", err)
+		}
+
+		msg, err := decodeMessage(received.Data)
+		if err != nil {
+			log.Printf("Dropping malformed NATS message: %v", err)
+			continue
+		}
+		if err := handle(ctx, msg); err != nil {
+			log.Printf("Failed to process NATS message %s: %v", msg.Message().ID(), err)
+		}
+	}
+}
+
+// Close ends the connection to the server, the next use connects again
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.conn != nil {
+		q.conn.Close()
+		q.conn = nil
+	}
+	return nil
+}
+
+// connection returns the open connection, connecting when there is none
+func (q *Queue) connection(ctx context.Context) (*nats.Conn, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.conn != nil && !q.conn.IsClosed() {
+		return q.conn, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	options := []nats.Option{
+		nats.Name(q.config.Name),
+		nats.Timeout(q.dialTimeout()),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(q.reconnectWait()),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Printf("Lost NATS connection, reconnecting: %v", err)
+			}
+		}),
+	}
+	if q.config.Token != "" {
+		options = append(options, nats.Token(q.config.Token))
+	}
+	nc, err := nats.Connect(q.config.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	q.conn = nc
+	return nc, nil
+}
+
+func (q *Queue) dialTimeout() time.Duration {
+	if q.config.DialTimeout <= 0 {
+		return DefaultDialTimeout
+	}
+	return q.config.DialTimeout
+}
+
+func (q *Queue) reconnectWait() time.Duration {
+	if q.config.ReconnectWait <= 0 {
+		return DefaultReconnectWait
+	}
+	return q.config.ReconnectWait
+}
+
+// decodeMessage restores a queued message from a NATS payload
+func decodeMessage(payload []byte) (*domain.QueuedMessage, error) {
+	var dto domain.QueuedMessageDTO
+	if err := json.Unmarshal(payload, &dto); err != nil {
+		return nil, err
+	}
+	return domain.QueuedMessageFromDTO(dto)
+}
@@ -0,0 +1,211 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer is an embedded NATS server
+type testServer struct {
+	*server.Server
+	token string
+}
+
+// runTestServer starts a NATS server requiring the token, on the port or on a free one when port is 0
+func runTestServer(t *testing.T, token string, port int) *testServer {
+	t.Helper()
+	if port == 0 {
+		port = server.RANDOM_PORT
+	}
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: port, Authorization: token, NoLog: true, NoSigs: true})
+	require.NoError(t, err)
+	go ns.Start()
+	require.True(t, ns.ReadyForConnections(5*time.Second), "NATS server did not start")
+	t.Cleanup(ns.Shutdown)
+	return &testServer{Server: ns, token: token}
+}
+
+// restart stops the server and starts another one on its port, as a server restart would
+func (s *testServer) restart(t *testing.T) *testServer {
+	t.Helper()
+	port := s.Addr().(*net.TCPAddr).Port
+	s.Shutdown()
+	s.WaitForShutdown()
+	return runTestServer(t, s.token, port)
+}
+
+// subscribers returns how many subscriptions receive the messages published on the default subject
+func (s *testServer) subscribers() int {
+	subs, err := s.Subsz(&server.SubszOptions{Subscriptions: true, Test: DefaultSubject})
+	if err != nil {
+		return 0
+	}
+	return len(subs.Subs)
+}
+
+func newTestQueue(t *testing.T, server *testServer, token string) *Queue {
+	t.Helper()
+	cfg := NewDefaultConfig(server.ClientURL())
+	cfg.Token = token
+	cfg.ReconnectWait = 10 * time.Millisecond
+	queue, err := NewQueue(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = queue.Close() })
+	return queue
+}
+
+func newQueuedMessage(t *testing.T, text string) *domain.QueuedMessage {
+	t.Helper()
+	msg, err := domain.NewMessage(common.GenerateID(), "jane", domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID("C0001")
+	queued, err := domain.NewQueuedMessage(msg, map[string]string{"slack_ts": "1700000000.000100"})
+	require.NoError(t, err)
+	return queued
+}
+
+// consumeInBackground runs a consumer collecting message IDs until the test ends
+func consumeInBackground(t *testing.T, queue *Queue, server *testServer, wantSubscribers int) (<-chan string, <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	ids := make(chan string, 10)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- queue.Consume(ctx, func(ctx context.Context, msg *domain.QueuedMessage) error {
+			ids <- msg.Message().ID().String()
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return server.subscribers() == wantSubscribers }, time.Second, time.Millisecond)
+	return ids, errs
+}
+
+func TestQueue_PublishAndConsume(t *testing.T) {
+	server := runTestServer(t, "s3cret", 0)
+	ingestion := newTestQueue(t, server, "s3cret")
+	worker := newTestQueue(t, server, "s3cret")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var consumed *domain.QueuedMessage
+	subscribed := make(chan error, 1)
+	go func() {
+		subscribed <- worker.Consume(ctx, func(ctx context.Context, msg *domain.QueuedMessage) error {
+			consumed = msg
+			cancel()
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return server.subscribers() == 1 }, time.Second, time.Millisecond)
+
+	published := newQueuedMessage(t, "We decided to use Postgres")
+	require.NoError(t, ingestion.Publish(context.Background(), published))
+
+	assert.ErrorIs(t, <-subscribed, context.Canceled)
+	require.NotNil(t, consumed)
+	assert.Equal(t, published.Message().ID(), consumed.Message().ID())
+	assert.Equal(t, published.Message().Content(), consumed.Message().Content())
+	assert.Equal(t, published.Route(), consumed.Route())
+}
+
+func TestQueue_QueueGroupDeliversOnce(t *testing.T) {
+	server := runTestServer(t, "", 0)
+	ingestion := newTestQueue(t, server, "")
+	first, _ := consumeInBackground(t, newTestQueue(t, server, ""), server, 1)
+	second, _ := consumeInBackground(t, newTestQueue(t, server, ""), server, 2)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, ingestion.Publish(context.Background(), newQueuedMessage(t, fmt.Sprintf("message %d", i))))
+	}
+
+	seen := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		select {
+		case id := <-first:
+			seen[id]++
+		case id := <-second:
+			seen[id]++
+		case <-time.After(time.Second):
+			t.Fatal("messages were not delivered")
+		}
+	}
+	assert.Len(t, seen, 4, "each message is delivered to one worker")
+	assert.Empty(t, first, "no message is delivered twice")
+	assert.Empty(t, second, "no message is delivered twice")
+}
+
+func TestQueue_RejectedCredentials(t *testing.T) {
+	server := runTestServer(t, "s3cret", 0)
+	queue := newTestQueue(t, server, "wrong")
+
+	err := queue.Publish(context.Background(), newQueuedMessage(t, "We decided to use Postgres"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Authorization Violation")
+}
+
+func TestQueue_ReconnectsAfterConnectionLoss(t *testing.T) {
+	server := runTestServer(t, "", 0)
+	queue := newTestQueue(t, server, "")
+	ids, errs := consumeInBackground(t, queue, server, 1)
+
+	restarted := server.restart(t)
+
+	// The client subscribes again on the new server, the consumer keeps running
+	require.Eventually(t, func() bool { return restarted.subscribers() == 1 }, 5*time.Second, time.Millisecond)
+	published := newQueuedMessage(t, "We decided to use Postgres")
+	require.NoError(t, queue.Publish(context.Background(), published))
+	select {
+	case id := <-ids:
+		assert.Equal(t, published.Message().ID().String(), id)
+	case err := <-errs:
+		t.Fatalf("consumer stopped: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not delivered after reconnecting")
+	}
+}
+
+func TestQueue_CloseStopsConsumers(t *testing.T) {
+	server := runTestServer(t, "", 0)
+	queue := newTestQueue(t, server, "")
+	_, errs := consumeInBackground(t, queue, server, 1)
+
+	require.NoError(t, queue.Close())
+
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "NATS connection closed")
+	case <-time.After(time.Second):
+		t.Fatal("consumer did not stop")
+	}
+}
+
+func TestQueue_DropsMalformedMessages(t *testing.T) {
+	server := runTestServer(t, "", 0)
+	queue := newTestQueue(t, server, "")
+	ids, _ := consumeInBackground(t, queue, server, 1)
+
+	nc, err := queue.connection(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, nc.Publish(DefaultSubject, []byte("not json")))
+	published := newQueuedMessage(t, "We decided to use Postgres")
+	require.NoError(t, queue.Publish(context.Background(), published))
+
+	select {
+	case id := <-ids:
+		assert.Equal(t, published.Message().ID().String(), id, "the malformed message is skipped")
+	case <-time.After(time.Second):
+		t.Fatal("message after the malformed one was not delivered")
+	}
+}
@@ -0,0 +1,98 @@
+package sqs
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingQueueURL          = errors.New("SQS queue URL is required")
+	ErrMissingRegion            = errors.New("AWS region is required")
+	ErrMissingCredentials       = errors.New("AWS access key ID and secret access key are required")
+	ErrInvalidWaitTime          = errors.New("wait time must be between 0 and 20 seconds")
+	ErrInvalidMaxMessages       = errors.New("max messages must be between 1 and 10")
+	ErrInvalidVisibilityTimeout = errors.New("visibility timeout must be between 0 and 12 hours")
+)
+
+// Config contains the settings of an SQS message queue
+type Config struct {
+	// QueueURL is the URL of the queue, queues ending in .fifo keep the order of each channel's messages
+	QueueURL string
+
+	// Region is the AWS region of the queue
+	Region string
+
+	// AccessKeyID and SecretAccessKey are the AWS credentials allowed to send, receive and delete messages
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is the token of temporary credentials (optional)
+	SessionToken string
+
+	// Endpoint overrides the SQS API endpoint, e.g. for LocalStack or ElasticMQ
+	// (optional, defaults to https://sqs.<region>.amazonaws.com)
+	Endpoint string
+
+	// WaitTime is how long a receive waits for messages to arrive (0-20s, default: 20s)
+	WaitTime time.Duration
+
+	// MaxMessages is how many messages a receive returns at most (1-10, default: 10)
+	MaxMessages int
+
+	// VisibilityTimeout hides received messages from other workers while they are processed; messages
+	// the handler fails on are delivered again after it (optional, 0 uses the queue's setting)
+	VisibilityTimeout time.Duration
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewDefaultConfig creates a Config with default values
+func NewDefaultConfig(queueURL, region, accessKeyID, secretAccessKey string) *Config {
+	return &Config{
+		QueueURL:        queueURL,
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		WaitTime:        20 * time.Second,
+		MaxMessages:     10,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.QueueURL) == "" {
+		return ErrMissingQueueURL
+	}
+
+	if strings.TrimSpace(c.Region) == "" {
+		return ErrMissingRegion
+	}
+
+	if strings.TrimSpace(c.AccessKeyID) == "" || strings.TrimSpace(c.SecretAccessKey) == "" {
+		return ErrMissingCredentials
+	}
+
+	if c.WaitTime < 0 || c.WaitTime > 20*time.Second {
+		return ErrInvalidWaitTime
+	}
+
+	if c.MaxMessages < 1 || c.MaxMessages > 10 {
+		return ErrInvalidMaxMessages
+	}
+
+	if c.VisibilityTimeout < 0 || c.VisibilityTimeout > 12*time.Hour {
+		return ErrInvalidVisibilityTimeout
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
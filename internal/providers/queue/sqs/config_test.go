@@ -0,0 +1,43 @@
+package sqs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		return NewDefaultConfig("https://sqs.eu-west-1.amazonaws.com/123456789012/quill", "eu-west-1", "AKID", "secret")
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "missing queue URL", mutate: func(c *Config) { c.QueueURL = "" }, wantErr: ErrMissingQueueURL},
+		{name: "missing region", mutate: func(c *Config) { c.Region = " " }, wantErr: ErrMissingRegion},
+		{name: "missing secret", mutate: func(c *Config) { c.SecretAccessKey = "" }, wantErr: ErrMissingCredentials},
+		{name: "wait time too long", mutate: func(c *Config) { c.WaitTime = 21 * time.Second }, wantErr: ErrInvalidWaitTime},
+		{name: "no messages per receive", mutate: func(c *Config) { c.MaxMessages = 0 }, wantErr: ErrInvalidMaxMessages},
+		{name: "too many messages per receive", mutate: func(c *Config) { c.MaxMessages = 11 }, wantErr: ErrInvalidMaxMessages},
+		{name: "negative visibility timeout", mutate: func(c *Config) { c.VisibilityTimeout = -time.Second }, wantErr: ErrInvalidVisibilityTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid()
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+// DefaultTimeout is the default timeout for HTTP requests, longer than the longest receive wait
+const DefaultTimeout = 30 * time.Second
+
+// Queue implements the ports.MessageQueue interface with Amazon SQS, using the AWS SDK.
+// Messages are deleted once processed; those the handler fails on are delivered again after the
+// visibility timeout, and the queue's redrive policy moves them aside after repeated failures.
+type Queue struct {
+	config *Config
+	client *sqs.Client
+	fifo   bool
+}
+
+// NewQueue creates an SQS message queue
+func NewQueue(cfg *Config) (*Queue, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := transport.NewHTTPClient(cfg.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	options := sqs.Options{
+		Region:      cfg.Region,
+		Credentials: credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken),
+		HTTPClient:  httpClient,
+	}
+	if endpoint := strings.TrimSpace(cfg.Endpoint); endpoint != "" {
+		options.BaseEndpoint = aws.String(strings.TrimRight(endpoint, "/"))
+	}
+
+	return &Queue{
+		config: cfg,
+		client: sqs.New(options),
+		fifo:   strings.HasSuffix(cfg.QueueURL, ".fifo"),
+	}, nil
+}
+
+// Publish sends a message to the queue. FIFO queues group messages by channel, so each channel's
+// messages are processed in order, and drop messages published twice.
+func (q *Queue) Publish(ctx context.Context, msg *domain.QueuedMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	body, err := json.Marshal(msg.ToDTO())
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	input := &sqs.SendMessageInput{QueueUrl: aws.String(q.config.QueueURL), MessageBody: aws.String(string(body))}
+	if q.fifo {
+		group := msg.Message().ChannelID()
+		if group == "" {
+			group = "default"
+		}
+		input.MessageGroupId = aws.String(group)
+		input.MessageDeduplicationId = aws.String(msg.Message().ID().String())
+	}
+
+	if _, err := q.client.SendMessage(ctx, input); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// Consume receives messages and passes them to handle until ctx is canceled or the queue cannot be reached
+func (q *Queue) Consume(ctx context.Context, handle func(ctx context.Context, msg *domain.QueuedMessage) error) error {
+	input := &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(q.config.QueueURL),
+		MaxNumberOfMessages: int32(q.config.MaxMessages),
+		WaitTimeSeconds:     int32(q.config.WaitTime / time.Second),
+		VisibilityTimeout:   int32(q.config.VisibilityTimeout / time.Second),
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		output, err := q.client.ReceiveMessage(ctx, input)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to receive messages: %w", err)
+		}
+
+		for _, received := range output.Messages {
+			messageID := aws.ToString(received.MessageId)
+			msg, err := decodeMessage(aws.ToString(received.Body))
+			if err != nil {
+				// Malformed messages never succeed, delivering them again only delays the others
+				log.Printf("Dropping malformed SQS message %s: %v", messageID, err)
+			} else if err := handle(ctx, msg); err != nil {
+				log.Printf("Failed to process SQS message %s, it will be delivered again: %v", messageID, err)
+				continue
+			}
+
+			q.delete(ctx, messageID, aws.ToString(received.ReceiptHandle))
+		}
+	}
+}
+
+// Depth returns the approximate number of messages waiting in the queue, not counting those being processed
+func (q *Queue) Depth(ctx context.Context) (int, error) {
+	output, err := q.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(q.config.QueueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue depth: %w", err)
+	}
+	value := output.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)]
+	depth, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid queue depth %q: %w", value, err)
+	}
+	return depth, nil
+}
+
+// delete removes a processed message from the queue. It is deleted even when ctx was canceled
+// while the message was processed, otherwise it would be processed again.
+func (q *Queue) delete(ctx context.Context, messageID, receiptHandle string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultTimeout)
+	defer cancel()
+
+	input := &sqs.DeleteMessageInput{QueueUrl: aws.String(q.config.QueueURL), ReceiptHandle: aws.String(receiptHandle)}
+	if _, err := q.client.DeleteMessage(ctx, input); err != nil {
+		log.Printf("Failed to delete SQS message %s: %v", messageID, err)
+	}
+}
+
+// decodeMessage restores a queued message from the body of an SQS message
+func decodeMessage(body string) (*domain.QueuedMessage, error) {
+	var dto domain.QueuedMessageDTO
+	if err := json.Unmarshal([]byte(body), &dto); err != nil {
+		return nil, err
+	}
+	return domain.QueuedMessageFromDTO(dto)
+}
@@ -0,0 +1,261 @@
+package sqs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sendMessageRequest struct {
+	QueueURL               string `json:"QueueUrl"`
+	MessageBody            string `json:"MessageBody"`
+	MessageGroupID         string `json:"MessageGroupId,omitempty"`
+	MessageDeduplicationID string `json:"MessageDeduplicationId,omitempty"`
+}
+
+type receivedMessage struct {
+	MessageID     string `json:"MessageId"`
+	ReceiptHandle string `json:"ReceiptHandle"`
+	Body          string `json:"Body"`
+	MD5OfBody     string `json:"MD5OfBody"`
+}
+
+type deleteMessageRequest struct {
+	QueueURL      string `json:"QueueUrl"`
+	ReceiptHandle string `json:"ReceiptHandle"`
+}
+
+// fakeSQS emulates the SendMessage, ReceiveMessage, DeleteMessage and GetQueueAttributes actions of the SQS JSON API
+type fakeSQS struct {
+	mu       sync.Mutex
+	messages map[string]string // Bodies of undeleted messages by receipt handle
+	order    []string
+	inFlight map[string]bool
+	sent     []sendMessageRequest
+	deleted  []string
+	next     int
+	url      string
+}
+
+func newFakeSQS(t *testing.T) (*fakeSQS, *Queue) {
+	fake := &fakeSQS{messages: make(map[string]string), inFlight: make(map[string]bool)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.url = server.URL
+	return fake, fake.newQueue(t, "AKID", "quill")
+}
+
+// newQueue creates a queue of the fake signing its requests with the access key ID
+func (f *fakeSQS) newQueue(t *testing.T, accessKeyID, name string) *Queue {
+	t.Helper()
+	cfg := NewDefaultConfig(f.url+"/123456789012/"+name, "eu-west-1", accessKeyID, "secret")
+	cfg.Endpoint = f.url
+	cfg.WaitTime = 0
+	queue, err := NewQueue(cfg)
+	require.NoError(t, err)
+	return queue
+}
+
+func (f *fakeSQS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"__type":"com.amazon.coral.service#MissingAuthenticationTokenException","message":"unsigned"}`))
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Header.Get("X-Amz-Target") {
+	case "AmazonSQS.SendMessage":
+		var request sendMessageRequest
+		_ = json.Unmarshal(body, &request)
+		f.sent = append(f.sent, request)
+		f.enqueue(request.MessageBody)
+		_, _ = fmt.Fprintf(w, `{"MessageId":"m","MD5OfMessageBody":"%s"}`, md5Hex(request.MessageBody))
+	case "AmazonSQS.ReceiveMessage":
+		var response struct {
+			Messages []receivedMessage `json:"Messages"`
+		}
+		for _, handle := range f.order {
+			if _, ok := f.messages[handle]; ok && !f.inFlight[handle] {
+				f.inFlight[handle] = true
+				body := f.messages[handle]
+				response.Messages = append(response.Messages, receivedMessage{MessageID: handle, ReceiptHandle: handle, Body: body, MD5OfBody: md5Hex(body)})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	case "AmazonSQS.DeleteMessage":
+		var request deleteMessageRequest
+		_ = json.Unmarshal(body, &request)
+		delete(f.messages, request.ReceiptHandle)
+		f.deleted = append(f.deleted, request.ReceiptHandle)
+		_, _ = w.Write([]byte(`{}`))
+	case "AmazonSQS.GetQueueAttributes":
+		waiting := 0
+		for handle := range f.messages {
+			if !f.inFlight[handle] {
+				waiting++
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"Attributes":{"ApproximateNumberOfMessages":"%d"}}`, waiting)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"com.amazonaws.sqs#InvalidAction","message":"unknown action"}`))
+	}
+}
+
+func (f *fakeSQS) enqueue(body string) {
+	f.next++
+	handle := fmt.Sprintf("receipt-%d", f.next)
+	f.messages[handle] = body
+	f.order = append(f.order, handle)
+}
+
+// md5Hex returns the checksum SQS returns of a message body, which the SDK checks
+func md5Hex(body string) string {
+	sum := md5.Sum([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// expire makes messages that were received but not deleted visible again
+func (f *fakeSQS) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inFlight = make(map[string]bool)
+}
+
+func (f *fakeSQS) remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.messages)
+}
+
+func newQueuedMessage(t *testing.T, text string) *domain.QueuedMessage {
+	t.Helper()
+	msg, err := domain.NewMessage(common.GenerateID(), "jane", domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	msg.SetChannelID("C0001")
+	queued, err := domain.NewQueuedMessage(msg, map[string]string{"slack_ts": "1700000000.000100"})
+	require.NoError(t, err)
+	return queued
+}
+
+func TestQueue_PublishAndConsume(t *testing.T) {
+	fake, queue := newFakeSQS(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	published := newQueuedMessage(t, "We decided to use Postgres")
+	require.NoError(t, queue.Publish(ctx, published))
+	require.Len(t, fake.sent, 1)
+	assert.Empty(t, fake.sent[0].MessageGroupID, "standard queues have no message groups")
+
+	var consumed *domain.QueuedMessage
+	err := queue.Consume(ctx, func(ctx context.Context, msg *domain.QueuedMessage) error {
+		consumed = msg
+		cancel()
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, consumed)
+	assert.Equal(t, published.Message().ID(), consumed.Message().ID())
+	assert.Equal(t, published.Message().Content(), consumed.Message().Content())
+	assert.Equal(t, published.Route(), consumed.Route())
+	assert.Zero(t, fake.remaining(), "processed messages are deleted")
+}
+
+func TestQueue_FailedMessagesAreDeliveredAgain(t *testing.T) {
+	fake, queue := newFakeSQS(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, queue.Publish(ctx, newQueuedMessage(t, "We decided to use Postgres")))
+
+	attempts := 0
+	err := queue.Consume(ctx, func(ctx context.Context, msg *domain.QueuedMessage) error {
+		attempts++
+		if attempts == 1 {
+			// The visibility timeout passes before the next receive
+			fake.expire()
+			return errors.New("LLM unavailable")
+		}
+		cancel()
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, attempts)
+	assert.Zero(t, fake.remaining())
+}
+
+func TestQueue_Depth(t *testing.T) {
+	_, queue := newFakeSQS(t)
+	ctx := context.Background()
+	for _, text := range []string{"We decided to use Postgres", "Ship the beta on Friday"} {
+		require.NoError(t, queue.Publish(ctx, newQueuedMessage(t, text)))
+	}
+
+	depth, err := queue.Depth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, depth)
+}
+
+func TestQueue_DropsMalformedMessages(t *testing.T) {
+	fake, queue := newFakeSQS(t)
+	fake.enqueue("not json")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go func() {
+		for fake.remaining() > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+	err := queue.Consume(ctx, func(ctx context.Context, msg *domain.QueuedMessage) error {
+		t.Error("malformed messages are not handled")
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"receipt-1"}, fake.deleted)
+}
+
+func TestQueue_FIFOGroupsByChannel(t *testing.T) {
+	fake, _ := newFakeSQS(t)
+	queue := fake.newQueue(t, "AKID", "quill.fifo")
+	published := newQueuedMessage(t, "We decided to use Postgres")
+
+	require.NoError(t, queue.Publish(context.Background(), published))
+
+	require.Len(t, fake.sent, 1)
+	assert.Equal(t, "C0001", fake.sent[0].MessageGroupID)
+	assert.Equal(t, published.Message().ID().String(), fake.sent[0].MessageDeduplicationID)
+}
+
+func TestQueue_ReportsAPIErrors(t *testing.T) {
+	fake, _ := newFakeSQS(t)
+	queue := fake.newQueue(t, "OTHER", "quill")
+
+	err := queue.Publish(context.Background(), newQueuedMessage(t, "We decided to use Postgres"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MissingAuthenticationTokenException")
+}
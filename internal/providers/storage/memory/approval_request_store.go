@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// ApprovalRequestStore implements the ports.ApprovalRequestStore interface in memory
+type ApprovalRequestStore struct {
+	mu       sync.Mutex
+	requests map[string]domain.ApprovalRequestDTO
+}
+
+// NewApprovalRequestStore creates a new in-memory store of decisions waiting for approval
+func NewApprovalRequestStore() *ApprovalRequestStore {
+	return &ApprovalRequestStore{requests: make(map[string]domain.ApprovalRequestDTO)}
+}
+
+// Put keeps the request of the thread of its decision, replacing the earlier one
+func (s *ApprovalRequestStore) Put(ctx context.Context, request *domain.ApprovalRequest) error {
+	if request == nil {
+		return fmt.Errorf("approval request cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Requests are kept as snapshots, so approvals given to a request that is not put back are not kept
+	s.requests[request.Message().ThreadID().String()] = request.ToDTO()
+	return nil
+}
+
+// Find returns the request waiting in a thread
+func (s *ApprovalRequestStore) Find(ctx context.Context, threadID string) (*domain.ApprovalRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dto, ok := s.requests[threadID]
+	if !ok {
+		return nil, fmt.Errorf("approval request of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return domain.ApprovalRequestFromDTO(dto)
+}
+
+// List returns the requests waiting in every thread, oldest first
+func (s *ApprovalRequestStore) List(ctx context.Context) ([]*domain.ApprovalRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]*domain.ApprovalRequest, 0, len(s.requests))
+	for _, dto := range s.requests {
+		request, err := domain.ApprovalRequestFromDTO(dto)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if !requests[i].RequestedAt().Equal(requests[j].RequestedAt()) {
+			return requests[i].RequestedAt().Before(requests[j].RequestedAt())
+		}
+		return requests[i].Message().ThreadID().String() < requests[j].Message().ThreadID().String()
+	})
+	return requests, nil
+}
+
+// Remove takes the request of a thread out of the store
+func (s *ApprovalRequestStore) Remove(ctx context.Context, threadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.requests[threadID]; !ok {
+		return fmt.Errorf("approval request of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	delete(s.requests, threadID)
+	return nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalRequestStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewApprovalRequestStore()
+	now := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+	config := domain.DocumentationConfig{ApprovalPolicies: []domain.ApprovalPolicy{{Approvals: 2}}}
+	newRequest := func(t *testing.T, at time.Time) *domain.ApprovalRequest {
+		msg, err := domain.NewMessage(common.GenerateID(), "alice", domain.MustNewMessageContent("We will use Postgres"),
+			domain.MessageTypeDecision, domain.CategoryDevelopment, nil)
+		require.NoError(t, err)
+		return domain.NewApprovalRequest(msg, config, at)
+	}
+
+	later := newRequest(t, now.Add(time.Hour))
+	require.NoError(t, store.Put(ctx, later))
+	first := newRequest(t, now)
+	require.NoError(t, store.Put(ctx, first))
+	assert.Error(t, store.Put(ctx, nil))
+
+	// Approvals are kept once the request is put back
+	require.NoError(t, first.Approve("bob"))
+	threadID := first.Message().ThreadID().String()
+	found, err := store.Find(ctx, threadID)
+	require.NoError(t, err)
+	assert.Empty(t, found.Approvers())
+	require.NoError(t, store.Put(ctx, first))
+	found, err = store.Find(ctx, threadID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, found.Approvers())
+
+	waiting, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, waiting, 2)
+	assert.Equal(t, threadID, waiting[0].Message().ThreadID().String())
+
+	require.NoError(t, store.Remove(ctx, threadID))
+	assert.ErrorIs(t, store.Remove(ctx, threadID), ports.ErrNotFound, "a request is removed once")
+	_, err = store.Find(ctx, threadID)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// AuditLog implements the ports.AuditLog interface in memory
+type AuditLog struct {
+	mu      sync.RWMutex
+	entries []*domain.AuditEntry
+}
+
+// NewAuditLog creates a new in-memory audit log
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+// Record appends an entry to the log
+func (l *AuditLog) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry cannot be nil")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// List returns all entries in the order they were recorded
+func (l *AuditLog) List(ctx context.Context) ([]*domain.AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]*domain.AuditEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries, nil
+}
+
+// ReplaceActor replaces the actor of the entries made by actor
+func (l *AuditLog) ReplaceActor(ctx context.Context, actor, replacement string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	replaced := 0
+	for i, entry := range l.entries {
+		if entry.Actor() == actor {
+			l.entries[i] = entry.WithActor(replacement)
+			replaced++
+		}
+	}
+	return replaced, nil
+}
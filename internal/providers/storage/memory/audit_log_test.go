@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog_RecordAndList(t *testing.T) {
+	ctx := context.Background()
+	log := NewAuditLog()
+
+	first, err := domain.NewAuditEntry(domain.AuditActionRecategorize, "U0001", "docs/other/pricing.md", "other", "product")
+	require.NoError(t, err)
+	second, err := domain.NewAuditEntry(domain.AuditActionRecategorize, "U0002", "docs/product/pricing.md", "product", "data_analysis")
+	require.NoError(t, err)
+
+	require.NoError(t, log.Record(ctx, first))
+	require.NoError(t, log.Record(ctx, second))
+	assert.Error(t, log.Record(ctx, nil))
+
+	entries, err := log.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.AuditEntry{first, second}, entries)
+
+	// The returned slice is a copy
+	entries[0] = nil
+	entries, err = log.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, entries[0])
+}
+
+func TestAuditLog_ReplaceActor(t *testing.T) {
+	ctx := context.Background()
+	log := NewAuditLog()
+	for _, actor := range []string{"U0001", "U0002", "U0001"} {
+		entry, err := domain.NewAuditEntry(domain.AuditActionRecategorize, actor, "docs/other/pricing.md", "other", "product")
+		require.NoError(t, err)
+		require.NoError(t, log.Record(ctx, entry))
+	}
+
+	replaced, err := log.ReplaceActor(ctx, "U0001", domain.ErasedIdentity)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, replaced)
+	entries, err := log.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ErasedIdentity, entries[0].Actor())
+	assert.Equal(t, "U0002", entries[1].Actor())
+	assert.Equal(t, domain.ErasedIdentity, entries[2].Actor())
+}
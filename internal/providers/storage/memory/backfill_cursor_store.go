@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// BackfillCursorStore implements the ports.BackfillCursorStore interface in memory
+type BackfillCursorStore struct {
+	mu      sync.RWMutex
+	cursors map[string]string
+}
+
+// NewBackfillCursorStore creates a new in-memory store of the newest message processed per channel
+func NewBackfillCursorStore() *BackfillCursorStore {
+	return &BackfillCursorStore{cursors: make(map[string]string)}
+}
+
+// Set records the newest message processed in a channel
+func (s *BackfillCursorStore) Set(ctx context.Context, channelID, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursors[channelID] = messageID
+	return nil
+}
+
+// List returns the newest message processed in every channel, by channel ID
+func (s *BackfillCursorStore) List(ctx context.Context) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cursors := make(map[string]string, len(s.cursors))
+	for channelID, messageID := range s.cursors {
+		cursors[channelID] = messageID
+	}
+	return cursors, nil
+}
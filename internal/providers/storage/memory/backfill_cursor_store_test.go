@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillCursorStore_SetList(t *testing.T) {
+	ctx := context.Background()
+	store := NewBackfillCursorStore()
+
+	cursors, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, cursors)
+
+	require.NoError(t, store.Set(ctx, "C1", "1700000000.000100"))
+	require.NoError(t, store.Set(ctx, "C1", "1700000000.000200"))
+	require.NoError(t, store.Set(ctx, "C2", "1700000000.000300"))
+
+	cursors, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"C1": "1700000000.000200", "C2": "1700000000.000300"}, cursors)
+
+	cursors["C3"] = "1700000000.000400"
+	again, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, again, 2, "the listed cursors are a copy")
+}
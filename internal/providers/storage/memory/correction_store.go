@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// CorrectionStore implements the ports.CorrectionStore interface in memory
+type CorrectionStore struct {
+	mu          sync.RWMutex
+	corrections []*domain.Correction
+}
+
+// NewCorrectionStore creates a new in-memory correction store
+func NewCorrectionStore() *CorrectionStore {
+	return &CorrectionStore{}
+}
+
+// Record adds a correction to the dataset
+func (s *CorrectionStore) Record(ctx context.Context, correction *domain.Correction) error {
+	if correction == nil {
+		return fmt.Errorf("correction cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.corrections = append(s.corrections, correction)
+	return nil
+}
+
+// List returns all corrections in the order they were recorded
+func (s *CorrectionStore) List(ctx context.Context) ([]*domain.Correction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	corrections := make([]*domain.Correction, len(s.corrections))
+	copy(corrections, s.corrections)
+	return corrections, nil
+}
+
+// ReplaceActor replaces the actor of the corrections made by actor
+func (s *CorrectionStore) ReplaceActor(ctx context.Context, actor, replacement string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replaced := 0
+	for i, correction := range s.corrections {
+		if correction.Actor() == actor {
+			s.corrections[i] = correction.WithActor(replacement)
+			replaced++
+		}
+	}
+	return replaced, nil
+}
+
+// DeleteByMessage removes the corrections of the messages
+func (s *CorrectionStore) DeleteByMessage(ctx context.Context, messageIDs ...string) (int, error) {
+	ids := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		ids[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.corrections[:0]
+	for _, correction := range s.corrections {
+		if !ids[correction.MessageID()] {
+			kept = append(kept, correction)
+		}
+	}
+	removed := len(s.corrections) - len(kept)
+	s.corrections = kept
+	return removed, nil
+}
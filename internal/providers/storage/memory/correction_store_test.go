@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrectionStore_RecordAndList(t *testing.T) {
+	ctx := context.Background()
+	store := NewCorrectionStore()
+
+	msg, err := domain.NewMessage(common.GenerateID(), "alice", domain.MustNewMessageContent("Lunch at noon"), domain.MessageTypeStatus, domain.CategoryOther, nil)
+	require.NoError(t, err)
+	discard, err := domain.NewCorrection(msg, domain.CorrectionFieldDiscard, "status", "unknown", "U0001")
+	require.NoError(t, err)
+	retype, err := domain.NewCorrection(msg, domain.CorrectionFieldType, "status", "information", "U0002")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Record(ctx, discard))
+	require.NoError(t, store.Record(ctx, retype))
+	assert.Error(t, store.Record(ctx, nil))
+
+	corrections, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Correction{discard, retype}, corrections)
+}
+
+func TestCorrectionStore_Erasure(t *testing.T) {
+	ctx := context.Background()
+	store := NewCorrectionStore()
+
+	lunch, err := domain.NewMessage(common.GenerateID(), "alice", domain.MustNewMessageContent("Lunch at noon"), domain.MessageTypeStatus, domain.CategoryOther, nil)
+	require.NoError(t, err)
+	postgres, err := domain.NewMessage(common.GenerateID(), "bob", domain.MustNewMessageContent("We will use Postgres"), domain.MessageTypeStatus, domain.CategoryDevelopment, nil)
+	require.NoError(t, err)
+	discard, err := domain.NewCorrection(lunch, domain.CorrectionFieldDiscard, "status", "unknown", "U0001")
+	require.NoError(t, err)
+	retype, err := domain.NewCorrection(postgres, domain.CorrectionFieldType, "status", "decision", "U0001")
+	require.NoError(t, err)
+	require.NoError(t, store.Record(ctx, discard))
+	require.NoError(t, store.Record(ctx, retype))
+
+	replaced, err := store.ReplaceActor(ctx, "U0001", "user-0123456789ab")
+	require.NoError(t, err)
+	assert.Equal(t, 2, replaced)
+
+	removed, err := store.DeleteByMessage(ctx, lunch.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	corrections, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, postgres.ID().String(), corrections[0].MessageID())
+	assert.Equal(t, "user-0123456789ab", corrections[0].Actor())
+}
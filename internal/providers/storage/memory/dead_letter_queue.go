@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// DeadLetterQueue implements the ports.DeadLetterQueue interface in memory
+type DeadLetterQueue struct {
+	mu      sync.RWMutex
+	letters []*domain.DeadLetter
+}
+
+// NewDeadLetterQueue creates a new in-memory dead-letter queue
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{}
+}
+
+// Add keeps a failed message, replacing the earlier failure of the same message
+func (q *DeadLetterQueue) Add(ctx context.Context, letter *domain.DeadLetter) error {
+	if letter == nil {
+		return fmt.Errorf("dead letter cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.remove(letter.ID())
+	q.letters = append(q.letters, letter)
+	return nil
+}
+
+// List returns the dead letters in the order they failed
+func (q *DeadLetterQueue) List(ctx context.Context) ([]*domain.DeadLetter, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	letters := make([]*domain.DeadLetter, len(q.letters))
+	copy(letters, q.letters)
+	return letters, nil
+}
+
+// Get returns the dead letter of a message
+func (q *DeadLetterQueue) Get(ctx context.Context, messageID string) (*domain.DeadLetter, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	for _, letter := range q.letters {
+		if letter.ID() == messageID {
+			return letter, nil
+		}
+	}
+	return nil, fmt.Errorf("dead letter %s: %w", messageID, ports.ErrNotFound)
+}
+
+// Remove deletes the dead letter of a message
+func (q *DeadLetterQueue) Remove(ctx context.Context, messageID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.remove(messageID) == nil {
+		return fmt.Errorf("dead letter %s: %w", messageID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// remove takes the dead letter of a message out of the queue, the caller holds the lock
+func (q *DeadLetterQueue) remove(messageID string) *domain.DeadLetter {
+	for i, letter := range q.letters {
+		if letter.ID() == messageID {
+			q.letters = append(q.letters[:i], q.letters[i+1:]...)
+			return letter
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	ctx := context.Background()
+	queue := NewDeadLetterQueue()
+	now := time.Now()
+
+	add := func(queued *domain.QueuedMessage) *domain.DeadLetter {
+		letter, err := domain.NewDeadLetter(queued, "analysis timed out", now)
+		require.NoError(t, err)
+		require.NoError(t, queue.Add(ctx, letter))
+		return letter
+	}
+	postgres, err := domain.NewQueuedMessage(newTestMessage(t, common.GenerateID(), "We will use Postgres"), nil)
+	require.NoError(t, err)
+	beta, err := domain.NewQueuedMessage(newTestMessage(t, common.GenerateID(), "Ship the beta on Friday"), nil)
+	require.NoError(t, err)
+	first := add(postgres)
+	second := add(beta)
+	assert.Error(t, queue.Add(ctx, nil))
+
+	// A message failing again after a retry replaces its earlier failure
+	again := add(first.Queued().Retry())
+
+	letters, err := queue.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.DeadLetter{second, again}, letters)
+	assert.Equal(t, 2, letters[1].Attempts())
+
+	found, err := queue.Get(ctx, second.ID())
+	require.NoError(t, err)
+	assert.Equal(t, second, found)
+
+	require.NoError(t, queue.Remove(ctx, second.ID()))
+	_, err = queue.Get(ctx, second.ID())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.ErrorIs(t, queue.Remove(ctx, second.ID()), ports.ErrNotFound)
+}
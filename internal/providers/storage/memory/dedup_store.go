@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultDedupCapacity is how many keys a DedupStore remembers unless told otherwise
+const DefaultDedupCapacity = 1000
+
+// DedupStore implements the ports.DedupStore interface in memory. It remembers a bounded number of keys,
+// forgetting the oldest first, which is enough to drop the retries of a chat within one replica.
+type DedupStore struct {
+	mu       sync.Mutex
+	capacity int
+	seen     map[string]struct{}
+	order    []string
+}
+
+// NewDedupStore creates a new in-memory dedup store remembering up to capacity keys
+// (0 uses DefaultDedupCapacity)
+func NewDedupStore(capacity int) *DedupStore {
+	if capacity <= 0 {
+		capacity = DefaultDedupCapacity
+	}
+	return &DedupStore{capacity: capacity, seen: make(map[string]struct{})}
+}
+
+// MarkSeen records a key and reports whether it was new
+func (s *DedupStore) MarkSeen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seen[key]; ok {
+		return false, nil
+	}
+	if len(s.order) >= s.capacity {
+		delete(s.seen, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.seen[key] = struct{}{}
+	s.order = append(s.order, key)
+	return true, nil
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupStore_MarkSeen(t *testing.T) {
+	ctx := context.Background()
+	store := NewDedupStore(2)
+
+	for _, key := range []string{"C1:1", "C1:2"} {
+		isNew, err := store.MarkSeen(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, isNew, key)
+	}
+
+	isNew, err := store.MarkSeen(ctx, "C1:1")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	// The third key pushes out the oldest one
+	_, err = store.MarkSeen(ctx, "C1:3")
+	require.NoError(t, err)
+	isNew, err = store.MarkSeen(ctx, "C1:1")
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	isNew, err = store.MarkSeen(ctx, "C1:3")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// DocumentIndex implements the ports.DocumentIndex interface in memory
+type DocumentIndex struct {
+	mu   sync.RWMutex
+	docs map[string]*domain.IndexedDocument
+}
+
+// NewDocumentIndex creates a new in-memory document index
+func NewDocumentIndex() *DocumentIndex {
+	return &DocumentIndex{
+		docs: make(map[string]*domain.IndexedDocument),
+	}
+}
+
+// Index adds or replaces a document in the index
+func (i *DocumentIndex) Index(ctx context.Context, doc *domain.IndexedDocument) error {
+	if doc == nil {
+		return fmt.Errorf("document cannot be nil")
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.docs[doc.Path()] = doc
+	return nil
+}
+
+// Remove removes a document from the index
+func (i *DocumentIndex) Remove(ctx context.Context, path string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.docs, path)
+	return nil
+}
+
+// FindByPath retrieves an indexed document by its path
+func (i *DocumentIndex) FindByPath(ctx context.Context, path string) (*domain.IndexedDocument, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	doc, ok := i.docs[path]
+	if !ok {
+		return nil, fmt.Errorf("document %s: %w", path, ports.ErrNotFound)
+	}
+	return doc, nil
+}
+
+// FindByIdempotencyKey retrieves the document generated from the message with the idempotency key
+func (i *DocumentIndex) FindByIdempotencyKey(ctx context.Context, key string) (*domain.IndexedDocument, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if key != "" {
+		for _, doc := range i.docs {
+			if doc.IdempotencyKey() == key {
+				return doc, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("document with idempotency key %s: %w", key, ports.ErrNotFound)
+}
+
+// List returns all indexed documents ordered by path
+func (i *DocumentIndex) List(ctx context.Context) ([]*domain.IndexedDocument, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	docs := make([]*domain.IndexedDocument, 0, len(i.docs))
+	for _, doc := range i.docs {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(a, b int) bool {
+		return docs[a].Path() < docs[b].Path()
+	})
+	return docs, nil
+}
+
+// FindByTag returns the documents carrying the tag ordered by path
+func (i *DocumentIndex) FindByTag(ctx context.Context, tag domain.Tag) ([]*domain.IndexedDocument, error) {
+	docs, err := i.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagged []*domain.IndexedDocument
+	for _, doc := range docs {
+		if doc.HasTag(tag) {
+			tagged = append(tagged, doc)
+		}
+	}
+	return tagged, nil
+}
+
+// Search returns the documents sharing the most terms with the query
+func (i *DocumentIndex) Search(ctx context.Context, query string, limit int) ([]*domain.IndexedDocument, error) {
+	docs, err := i.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		doc   *domain.IndexedDocument
+		score float64
+	}
+
+	var matches []scored
+	for _, doc := range docs {
+		if score := domain.TextSimilarity(query, doc.SearchText()); score > 0 {
+			matches = append(matches, scored{doc: doc, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].score > matches[b].score
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]*domain.IndexedDocument, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.doc)
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentIndex_Search(t *testing.T) {
+	ctx := context.Background()
+	index := NewDocumentIndex()
+
+	docs := []struct {
+		path  string
+		title string
+	}{
+		{path: "docs/development/adopt-postgres.md", title: "Adopt Postgres"},
+		{path: "docs/development/postgres-migration.md", title: "Postgres migration plan"},
+		{path: "docs/product/pricing.md", title: "Pricing tiers"},
+	}
+	for _, d := range docs {
+		doc, err := domain.NewIndexedDocument(d.path, d.title, "", domain.MessageTypeDecision, domain.CategoryDevelopment)
+		require.NoError(t, err)
+		require.NoError(t, index.Index(ctx, doc))
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		limit     int
+		wantPaths []string
+	}{
+		{
+			name:      "best match first",
+			query:     "adopt postgres",
+			limit:     0,
+			wantPaths: []string{"docs/development/adopt-postgres.md", "docs/development/postgres-migration.md"},
+		},
+		{
+			name:      "respects limit",
+			query:     "postgres",
+			limit:     1,
+			wantPaths: []string{"docs/development/adopt-postgres.md"},
+		},
+		{
+			name:      "no matches",
+			query:     "hiring",
+			limit:     5,
+			wantPaths: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := index.Search(ctx, tt.query, tt.limit)
+			require.NoError(t, err)
+
+			paths := make([]string, 0, len(result))
+			for _, doc := range result {
+				paths = append(paths, doc.Path())
+			}
+			assert.Equal(t, tt.wantPaths, paths)
+		})
+	}
+}
+
+func TestDocumentIndex_FindByPath(t *testing.T) {
+	ctx := context.Background()
+	index := NewDocumentIndex()
+
+	doc, err := domain.NewIndexedDocument("docs/product/pricing.md", "Pricing tiers", "", domain.MessageTypeIdea, domain.CategoryProduct)
+	require.NoError(t, err)
+	require.NoError(t, index.Index(ctx, doc))
+
+	found, err := index.FindByPath(ctx, "docs/product/pricing.md")
+	assert.NoError(t, err)
+	assert.Equal(t, doc, found)
+
+	require.NoError(t, index.Remove(ctx, "docs/product/pricing.md"))
+	_, err = index.FindByPath(ctx, "docs/product/pricing.md")
+	assert.True(t, errors.Is(err, ports.ErrNotFound))
+}
+
+func TestDocumentIndex_FindByIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	index := NewDocumentIndex()
+
+	doc, err := domain.NewIndexedDocument("docs/product/pricing.md", "Pricing tiers", "", domain.MessageTypeIdea, domain.CategoryProduct)
+	require.NoError(t, err)
+	doc.SetIdempotencyKey("3f2a9c")
+	require.NoError(t, index.Index(ctx, doc))
+	written, err := domain.NewIndexedDocument("docs/product/roadmap.md", "Roadmap", "", domain.MessageTypeIdea, domain.CategoryProduct)
+	require.NoError(t, err)
+	require.NoError(t, index.Index(ctx, written))
+
+	found, err := index.FindByIdempotencyKey(ctx, "3f2a9c")
+	assert.NoError(t, err)
+	assert.Equal(t, doc, found)
+
+	// Documents people wrote have no key and are never found by one
+	_, err = index.FindByIdempotencyKey(ctx, "")
+	assert.True(t, errors.Is(err, ports.ErrNotFound))
+	_, err = index.FindByIdempotencyKey(ctx, "7b1e04")
+	assert.True(t, errors.Is(err, ports.ErrNotFound))
+}
+
+func TestDocumentIndex_FindByTag(t *testing.T) {
+	ctx := context.Background()
+	index := NewDocumentIndex()
+
+	tagged := map[string][]domain.Tag{
+		"docs/development/b.md": {"postgres", "storage"},
+		"docs/development/a.md": {"postgres"},
+		"docs/product/c.md":     {"pricing"},
+	}
+	for path, tags := range tagged {
+		doc, err := domain.NewIndexedDocument(path, "", "", domain.MessageTypeIdea, domain.CategoryDevelopment)
+		require.NoError(t, err)
+		doc.SetTags(tags)
+		require.NoError(t, index.Index(ctx, doc))
+	}
+
+	docs, err := index.FindByTag(ctx, "postgres")
+	require.NoError(t, err)
+
+	var paths []string
+	for _, doc := range docs {
+		paths = append(paths, doc.Path())
+	}
+	assert.Equal(t, []string{"docs/development/a.md", "docs/development/b.md"}, paths)
+
+	docs, err = index.FindByTag(ctx, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// IncidentStore implements the ports.IncidentStore interface in memory
+type IncidentStore struct {
+	mu        sync.RWMutex
+	incidents map[string]*domain.Incident
+}
+
+// NewIncidentStore creates a new in-memory incident store
+func NewIncidentStore() *IncidentStore {
+	return &IncidentStore{incidents: make(map[string]*domain.Incident)}
+}
+
+// Save stores the incident of a thread, replacing the earlier one
+func (s *IncidentStore) Save(ctx context.Context, incident *domain.Incident) error {
+	if incident == nil {
+		return fmt.Errorf("incident cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.incidents[incident.ThreadID()] = incident
+	return nil
+}
+
+// Find returns the latest incident of a thread
+func (s *IncidentStore) Find(ctx context.Context, threadID string) (*domain.Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	incident, ok := s.incidents[threadID]
+	if !ok {
+		return nil, fmt.Errorf("incident of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return incident, nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentStore_SaveFind(t *testing.T) {
+	ctx := context.Background()
+	store := NewIncidentStore()
+	now := time.Now()
+
+	incident, err := domain.NewIncident("T1", "C1", "Payments API down", "alice", now)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, incident))
+	assert.Error(t, store.Save(ctx, nil))
+
+	// A new incident of the thread replaces the resolved one
+	require.NoError(t, incident.Resolve("bob", now.Add(time.Hour)))
+	again, err := domain.NewIncident("T1", "C1", "Payments API down again", "bob", now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, again))
+
+	found, err := store.Find(ctx, "T1")
+	require.NoError(t, err)
+	assert.Equal(t, again, found)
+	_, err = store.Find(ctx, "T2")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+// KeyResultUpdateStore implements the ports.KeyResultUpdateStore interface in memory
+type KeyResultUpdateStore struct {
+	mu      sync.RWMutex
+	updates []domain.KeyResultUpdate
+}
+
+// NewKeyResultUpdateStore creates a new in-memory key result update store
+func NewKeyResultUpdateStore() *KeyResultUpdateStore {
+	return &KeyResultUpdateStore{}
+}
+
+// Record stores an update, replacing the earlier update of the same message on the same key result
+func (s *KeyResultUpdateStore) Record(ctx context.Context, update domain.KeyResultUpdate) error {
+	if update.KeyResultID == "" || update.MessageID == "" {
+		return fmt.Errorf("key result update needs a key result and a message")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.updates {
+		if existing.ProjectID == update.ProjectID && existing.Quarter == update.Quarter &&
+			existing.MessageID == update.MessageID && strings.EqualFold(existing.KeyResultID, update.KeyResultID) {
+			s.updates[i] = update
+			return nil
+		}
+	}
+	s.updates = append(s.updates, update)
+	return nil
+}
+
+// List returns the updates of a project's quarter, oldest first
+func (s *KeyResultUpdateStore) List(ctx context.Context, projectID common.ID, quarter string) ([]domain.KeyResultUpdate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var updates []domain.KeyResultUpdate
+	for _, update := range s.updates {
+		if update.ProjectID == projectID && update.Quarter == quarter {
+			updates = append(updates, update)
+		}
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].At.Before(updates[j].At)
+	})
+	return updates, nil
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyResultUpdateStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewKeyResultUpdateStore()
+	projectID := common.GenerateID()
+	at := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+
+	later := domain.KeyResultUpdate{ProjectID: projectID, Quarter: "2024-Q3", KeyResultID: "KR1", Value: 45, Measured: true, MessageID: "m2", At: at.Add(time.Hour)}
+	earlier := domain.KeyResultUpdate{ProjectID: projectID, Quarter: "2024-Q3", KeyResultID: "KR1", Value: 30, Measured: true, MessageID: "m1", At: at}
+	require.NoError(t, store.Record(ctx, later))
+	require.NoError(t, store.Record(ctx, earlier))
+	require.NoError(t, store.Record(ctx, domain.KeyResultUpdate{ProjectID: projectID, Quarter: "2024-Q4", KeyResultID: "KR1", MessageID: "m3", At: at}))
+	require.NoError(t, store.Record(ctx, domain.KeyResultUpdate{ProjectID: common.GenerateID(), Quarter: "2024-Q3", KeyResultID: "KR1", MessageID: "m4", At: at}))
+	assert.Error(t, store.Record(ctx, domain.KeyResultUpdate{ProjectID: projectID, Quarter: "2024-Q3"}))
+
+	updates, err := store.List(ctx, projectID, "2024-Q3")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.KeyResultUpdate{earlier, later}, updates)
+
+	// Recording the update of a message again replaces it
+	corrected := earlier
+	corrected.Value = 35
+	require.NoError(t, store.Record(ctx, corrected))
+	updates, err = store.List(ctx, projectID, "2024-Q3")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.KeyResultUpdate{corrected, later}, updates)
+}
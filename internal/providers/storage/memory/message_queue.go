@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// DefaultQueueCapacity is how many messages the in-memory queue holds before Publish blocks
+const DefaultQueueCapacity = 1000
+
+// MessageQueue implements the ports.MessageQueue interface in memory, for running ingestion and
+// workers in one process. Messages the handler fails on are not delivered again, the message
+// tracker keeps them as failed.
+type MessageQueue struct {
+	messages chan domain.QueuedMessageDTO
+}
+
+// NewMessageQueue creates an in-memory message queue, zero capacity uses DefaultQueueCapacity
+func NewMessageQueue(capacity int) *MessageQueue {
+	if capacity <= 0 {
+		capacity = DefaultQueueCapacity
+	}
+	return &MessageQueue{messages: make(chan domain.QueuedMessageDTO, capacity)}
+}
+
+// Publish enqueues a message, waiting while the queue is full
+func (q *MessageQueue) Publish(ctx context.Context, msg *domain.QueuedMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	select {
+	case q.messages <- msg.ToDTO():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Depth returns how many messages wait in the queue
+func (q *MessageQueue) Depth(ctx context.Context) (int, error) {
+	return len(q.messages), nil
+}
+
+// Consume passes queued messages to handle until ctx is canceled
+func (q *MessageQueue) Consume(ctx context.Context, handle func(ctx context.Context, msg *domain.QueuedMessage) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case dto := <-q.messages:
+			msg, err := domain.QueuedMessageFromDTO(dto)
+			if err != nil {
+				log.Printf("Dropping malformed queued message: %v", err)
+				continue
+			}
+			if err := handle(ctx, msg); err != nil {
+				log.Printf("Failed to process queued message %s: %v", dto.Message.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageQueue(t *testing.T) {
+	queue := NewMessageQueue(2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var published []string
+	for _, text := range []string{"first", "second"} {
+		queued, err := domain.NewQueuedMessage(newTestMessage(t, common.GenerateID(), text), map[string]string{"ts": text})
+		require.NoError(t, err)
+		require.NoError(t, queue.Publish(ctx, queued))
+		published = append(published, queued.Message().ID().String())
+	}
+
+	depth, err := queue.Depth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, depth)
+
+	// The queue is full, publishing waits for room until the context ends
+	full, err := domain.NewQueuedMessage(newTestMessage(t, common.GenerateID(), "third"), nil)
+	require.NoError(t, err)
+	short, stop := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer stop()
+	assert.ErrorIs(t, queue.Publish(short, full), context.DeadlineExceeded)
+
+	var consumed []string
+	err = queue.Consume(ctx, func(ctx context.Context, msg *domain.QueuedMessage) error {
+		consumed = append(consumed, msg.Message().ID().String())
+		assert.Equal(t, msg.Message().Content().Text(), msg.Route()["ts"])
+		if len(consumed) == len(published) {
+			cancel()
+		}
+		return errors.New("failures are not delivered again")
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, published, consumed)
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// MessageRepository implements the ports.MessageRepository interface in memory.
+// Messages are stored as DTOs so state changes are only persisted by Update.
+type MessageRepository struct {
+	mu       sync.RWMutex
+	messages map[string]domain.MessageDTO
+	// senders are the pseudonyms of the senders of the messages by message ID, so messages are found by sender
+	// without decrypting them
+	senders map[string]string
+	// cipher encrypts the content and sender of stored messages, nil stores them in plaintext
+	cipher domain.FieldCipher
+}
+
+// NewMessageRepository creates a new in-memory message repository
+func NewMessageRepository() *MessageRepository {
+	return &MessageRepository{
+		messages: make(map[string]domain.MessageDTO),
+		senders:  make(map[string]string),
+	}
+}
+
+// NewEncryptedMessageRepository creates a new in-memory message repository storing the content and sender
+// of messages encrypted with the cipher
+func NewEncryptedMessageRepository(cipher domain.FieldCipher) *MessageRepository {
+	if cipher == nil {
+		panic("field cipher cannot be nil")
+	}
+	repo := NewMessageRepository()
+	repo.cipher = cipher
+	return repo
+}
+
+// Save persists a message
+func (r *MessageRepository) Save(ctx context.Context, message *domain.Message) error {
+	if message == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	dto, err := r.encrypt(message.ToDTO())
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages[message.ID().String()] = dto
+	r.senders[message.ID().String()] = domain.Pseudonym(message.Sender())
+	return nil
+}
+
+// FindByID retrieves a message by ID
+func (r *MessageRepository) FindByID(ctx context.Context, id string) (*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dto, ok := r.messages[id]
+	if !ok {
+		return nil, fmt.Errorf("message %s: %w", id, ports.ErrNotFound)
+	}
+	return r.restore(dto)
+}
+
+// FindByThread retrieves messages in a thread ordered by timestamp
+func (r *MessageRepository) FindByThread(ctx context.Context, threadID string) ([]*domain.Message, error) {
+	return r.find(func(dto domain.MessageDTO) bool {
+		return dto.ThreadID == threadID
+	})
+}
+
+// Update persists changes to a stored message
+func (r *MessageRepository) Update(ctx context.Context, message *domain.Message) error {
+	if message == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	dto, err := r.encrypt(message.ToDTO())
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.messages[message.ID().String()]; !ok {
+		return fmt.Errorf("message %s: %w", message.ID(), ports.ErrNotFound)
+	}
+	r.messages[message.ID().String()] = dto
+	r.senders[message.ID().String()] = domain.Pseudonym(message.Sender())
+	return nil
+}
+
+// FindByState retrieves messages in the given processing states ordered by timestamp
+func (r *MessageRepository) FindByState(ctx context.Context, states ...domain.MessageState) ([]*domain.Message, error) {
+	return r.find(func(dto domain.MessageDTO) bool {
+		if len(states) == 0 {
+			return true
+		}
+		current := domain.MessageStatePending
+		if len(dto.States) > 0 {
+			current = domain.MessageState(dto.States[len(dto.States)-1].State)
+		}
+		for _, state := range states {
+			if state == current {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FindBySender retrieves the messages a person sent ordered by timestamp
+func (r *MessageRepository) FindBySender(ctx context.Context, sender string) ([]*domain.Message, error) {
+	key := domain.Pseudonym(sender)
+	return r.find(func(dto domain.MessageDTO) bool {
+		return r.senders[dto.ID] == key
+	})
+}
+
+// Delete removes a message
+func (r *MessageRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.messages, id)
+	delete(r.senders, id)
+	return nil
+}
+
+// find restores the messages matching the predicate ordered by timestamp
+func (r *MessageRepository) find(match func(domain.MessageDTO) bool) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var msgs []*domain.Message
+	for _, dto := range r.messages {
+		if !match(dto) {
+			continue
+		}
+		msg, err := r.restore(dto)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Timestamp().Before(msgs[j].Timestamp())
+	})
+	return msgs, nil
+}
+
+// Reencrypt encrypts every stored message again with the cipher's current key, after a key rotation or when
+// encryption was turned on for messages stored in plaintext. It returns how many messages it encrypted.
+func (r *MessageRepository) Reencrypt(ctx context.Context) (int, error) {
+	if r.cipher == nil {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, dto := range r.messages {
+		decrypted, err := dto.Decrypted(r.cipher)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decrypt message %s: %w", id, err)
+		}
+		if r.messages[id], err = decrypted.Encrypted(r.cipher); err != nil {
+			return 0, fmt.Errorf("failed to encrypt message %s: %w", id, err)
+		}
+	}
+	return len(r.messages), nil
+}
+
+// encrypt encrypts the fields of a message about to be stored
+func (r *MessageRepository) encrypt(dto domain.MessageDTO) (domain.MessageDTO, error) {
+	if r.cipher == nil {
+		return dto, nil
+	}
+	encrypted, err := dto.Encrypted(r.cipher)
+	if err != nil {
+		return domain.MessageDTO{}, fmt.Errorf("failed to encrypt message %s: %w", dto.ID, err)
+	}
+	return encrypted, nil
+}
+
+// restore decrypts a stored message and rebuilds it
+func (r *MessageRepository) restore(dto domain.MessageDTO) (*domain.Message, error) {
+	if r.cipher != nil {
+		decrypted, err := dto.Decrypted(r.cipher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt message %s: %w", dto.ID, err)
+		}
+		dto = decrypted
+	}
+	return domain.MessageFromDTO(dto)
+}
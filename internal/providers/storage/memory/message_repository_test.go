@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/providers/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMessage(t *testing.T, threadID common.ID, text string) *domain.Message {
+	t.Helper()
+	msg, err := domain.NewMessage(threadID, "jane", domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestMessageRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMessageRepository()
+	threadID := common.GenerateID()
+
+	first := newTestMessage(t, threadID, "first")
+	second := newTestMessage(t, threadID, "second")
+	other := newTestMessage(t, common.GenerateID(), "other")
+	for _, msg := range []*domain.Message{first, second, other} {
+		require.NoError(t, repo.Save(ctx, msg))
+	}
+
+	found, err := repo.FindByID(ctx, first.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, first.ToDTO(), found.ToDTO())
+
+	thread, err := repo.FindByThread(ctx, threadID.String())
+	require.NoError(t, err)
+	require.Len(t, thread, 2)
+	assert.Equal(t, "first", thread[0].Content().Text())
+
+	_, err = repo.FindByID(ctx, common.GenerateID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	require.NoError(t, repo.Delete(ctx, first.ID().String()))
+	_, err = repo.FindByID(ctx, first.ID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.NoError(t, repo.Delete(ctx, first.ID().String()))
+}
+
+func TestMessageRepository_FindBySender(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := encryption.NewKeyring(encryption.NewConfig("2024", bytes.Repeat([]byte{1}, 32)))
+	require.NoError(t, err)
+	repo := NewEncryptedMessageRepository(cipher)
+
+	sent := newTestMessage(t, common.GenerateID(), "We will use Postgres")
+	other, err := domain.NewMessage(common.GenerateID(), "bob", domain.MustNewMessageContent("Release train"), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, sent))
+	require.NoError(t, repo.Save(ctx, other))
+
+	found, err := repo.FindBySender(ctx, "jane")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, sent.ID(), found[0].ID())
+
+	sent.Pseudonymize(domain.Pseudonym("jane"))
+	require.NoError(t, repo.Update(ctx, sent))
+	found, err = repo.FindBySender(ctx, "jane")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	require.NoError(t, repo.Delete(ctx, other.ID().String()))
+	found, err = repo.FindBySender(ctx, "bob")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestMessageRepository_State(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMessageRepository()
+
+	msg := newTestMessage(t, common.GenerateID(), "We will use Postgres")
+	require.NoError(t, repo.Save(ctx, msg))
+
+	// State changes are only visible once the message is updated
+	require.NoError(t, msg.StartAnalysis())
+	pending, err := repo.FindByState(ctx, domain.MessageStatePending)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	require.NoError(t, repo.Update(ctx, msg))
+	analyzing, err := repo.FindByState(ctx, domain.MessageStateAnalyzing, domain.MessageStateFailed)
+	require.NoError(t, err)
+	require.Len(t, analyzing, 1)
+	assert.Equal(t, domain.MessageStateAnalyzing, analyzing[0].State())
+
+	all, err := repo.FindByState(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	assert.ErrorIs(t, repo.Update(ctx, newTestMessage(t, common.GenerateID(), "unsaved")), ports.ErrNotFound)
+}
+
+func TestMessageRepository_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	oldSecret, newSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	old, err := encryption.NewKeyring(encryption.NewConfig("2023", oldSecret))
+	require.NoError(t, err)
+	repo := NewEncryptedMessageRepository(old)
+
+	msg := newTestMessage(t, common.GenerateID(), "We will use Postgres")
+	require.NoError(t, repo.Save(ctx, msg))
+
+	stored := repo.messages[msg.ID().String()]
+	assert.NotContains(t, stored.Content, "Postgres")
+	assert.NotEqual(t, "jane", stored.Sender)
+	found, err := repo.FindByID(ctx, msg.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, msg.ToDTO(), found.ToDTO())
+
+	// After a rotation the old key still decrypts, until the messages are encrypted again
+	rotated, err := encryption.NewKeyring(&encryption.Config{
+		Keys:        []encryption.Key{{ID: "2023", Secret: oldSecret}, {ID: "2024", Secret: newSecret}},
+		ActiveKeyID: "2024",
+	})
+	require.NoError(t, err)
+	repo.cipher = rotated
+	thread, err := repo.FindByThread(ctx, msg.ThreadID().String())
+	require.NoError(t, err)
+	require.Len(t, thread, 1)
+	assert.Equal(t, "We will use Postgres", thread[0].Content().Text())
+
+	reencrypted, err := repo.Reencrypt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reencrypted)
+	assert.True(t, strings.HasPrefix(repo.messages[msg.ID().String()].Content, "enc:v1:2024:"))
+	_, err = old.Decrypt(repo.messages[msg.ID().String()].Content)
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+}
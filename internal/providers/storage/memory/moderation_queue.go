@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// ModerationQueue implements the ports.ModerationQueue interface in memory
+type ModerationQueue struct {
+	mu   sync.RWMutex
+	held []*domain.HeldMessage
+}
+
+// NewModerationQueue creates a new in-memory moderation queue
+func NewModerationQueue() *ModerationQueue {
+	return &ModerationQueue{}
+}
+
+// Hold adds a message to the queue, replacing the earlier entry of the same message
+func (q *ModerationQueue) Hold(ctx context.Context, held *domain.HeldMessage) error {
+	if held == nil {
+		return fmt.Errorf("held message cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.remove(held.Message().ID().String())
+	q.held = append(q.held, held)
+	return nil
+}
+
+// List returns the held messages in the order they were held
+func (q *ModerationQueue) List(ctx context.Context) ([]*domain.HeldMessage, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	held := make([]*domain.HeldMessage, len(q.held))
+	copy(held, q.held)
+	return held, nil
+}
+
+// Release removes a message from the queue and returns it
+func (q *ModerationQueue) Release(ctx context.Context, messageID string) (*domain.HeldMessage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	held := q.remove(messageID)
+	if held == nil {
+		return nil, fmt.Errorf("held message %s: %w", messageID, ports.ErrNotFound)
+	}
+	return held, nil
+}
+
+// remove takes a message out of the queue, the caller holds the lock
+func (q *ModerationQueue) remove(messageID string) *domain.HeldMessage {
+	for i, held := range q.held {
+		if held.Message().ID().String() == messageID {
+			q.held = append(q.held[:i], q.held[i+1:]...)
+			return held
+		}
+	}
+	return nil
+}
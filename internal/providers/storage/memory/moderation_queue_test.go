@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModerationQueue_HoldListRelease(t *testing.T) {
+	ctx := context.Background()
+	queue := NewModerationQueue()
+	now := time.Now()
+
+	hold := func(text string) *domain.HeldMessage {
+		held, err := domain.NewHeldMessage(newTestMessage(t, common.GenerateID(), text), "contains \"Acme\"", now)
+		require.NoError(t, err)
+		require.NoError(t, queue.Hold(ctx, held))
+		return held
+	}
+	first := hold("Acme asked for a discount")
+	second := hold("Acme renewed for two years")
+	assert.Error(t, queue.Hold(ctx, nil))
+
+	// Holding a message again replaces it
+	again, err := domain.NewHeldMessage(first.Message(), "rated harassment (0.50)", now)
+	require.NoError(t, err)
+	require.NoError(t, queue.Hold(ctx, again))
+
+	held, err := queue.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.HeldMessage{second, again}, held)
+
+	released, err := queue.Release(ctx, second.Message().ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, second, released)
+
+	_, err = queue.Release(ctx, second.Message().ID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	held, err = queue.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.HeldMessage{again}, held)
+}
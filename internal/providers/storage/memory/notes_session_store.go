@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// NotesSessionStore implements the ports.NotesSessionStore interface in memory
+type NotesSessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*domain.NotesSession
+}
+
+// NewNotesSessionStore creates a new in-memory notes session store
+func NewNotesSessionStore() *NotesSessionStore {
+	return &NotesSessionStore{sessions: make(map[string]*domain.NotesSession)}
+}
+
+// Save stores the notes session of a thread, replacing the earlier one
+func (s *NotesSessionStore) Save(ctx context.Context, session *domain.NotesSession) error {
+	if session == nil {
+		return fmt.Errorf("notes session cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[session.ThreadID()] = session
+	return nil
+}
+
+// Find returns the latest notes session of a thread
+func (s *NotesSessionStore) Find(ctx context.Context, threadID string) (*domain.NotesSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[threadID]
+	if !ok {
+		return nil, fmt.Errorf("notes session of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return session, nil
+}
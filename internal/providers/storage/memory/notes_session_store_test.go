@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotesSessionStore_SaveFind(t *testing.T) {
+	ctx := context.Background()
+	store := NewNotesSessionStore()
+	now := time.Now()
+
+	session, err := domain.NewNotesSession("T1", "C1", "Billing sync", "alice", now)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, session))
+	assert.Error(t, store.Save(ctx, nil))
+
+	// New notes of the thread replace the closed ones
+	require.NoError(t, session.Close("bob", now.Add(time.Hour)))
+	again, err := domain.NewNotesSession("T1", "C1", "Billing sync follow-up", "bob", now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, again))
+
+	found, err := store.Find(ctx, "T1")
+	require.NoError(t, err)
+	assert.Equal(t, again, found)
+	_, err = store.Find(ctx, "T2")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// PendingDuplicateStore implements the ports.PendingDuplicateStore interface in memory
+type PendingDuplicateStore struct {
+	mu      sync.Mutex
+	pending map[string]*domain.PendingDuplicate
+}
+
+// NewPendingDuplicateStore creates a new in-memory store of pending duplicate choices
+func NewPendingDuplicateStore() *PendingDuplicateStore {
+	return &PendingDuplicateStore{pending: make(map[string]*domain.PendingDuplicate)}
+}
+
+// Put keeps the choice of a thread, replacing the earlier one
+func (s *PendingDuplicateStore) Put(ctx context.Context, pending *domain.PendingDuplicate) error {
+	if pending == nil {
+		return fmt.Errorf("pending duplicate cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[pending.ThreadID()] = pending
+	return nil
+}
+
+// Find returns the choice waited for in a thread at the time
+func (s *PendingDuplicateStore) Find(ctx context.Context, threadID string, at time.Time) (*domain.PendingDuplicate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, ok := s.pending[threadID]
+	if !ok || pending.IsExpired(at) {
+		return nil, fmt.Errorf("pending duplicate of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return pending, nil
+}
+
+// Remove takes the choice of a thread out of the store
+func (s *PendingDuplicateStore) Remove(ctx context.Context, threadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pending[threadID]; !ok {
+		return fmt.Errorf("pending duplicate of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	delete(s.pending, threadID)
+	return nil
+}
+
+// Purge removes the choices expired at the time
+func (s *PendingDuplicateStore) Purge(ctx context.Context, at time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for threadID, pending := range s.pending {
+		if pending.IsExpired(at) {
+			delete(s.pending, threadID)
+			purged++
+		}
+	}
+	return purged, nil
+}
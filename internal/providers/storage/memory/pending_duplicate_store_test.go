@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingDuplicateStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewPendingDuplicateStore()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+
+	first, err := domain.NewPendingDuplicate("T1", "M1", "alice", []string{"docs/a.md"}, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, first))
+	assert.Error(t, store.Put(ctx, nil))
+
+	// Offering the choice again in the thread replaces it
+	again, err := domain.NewPendingDuplicate("T1", "M2", "alice", []string{"docs/b.md"}, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, again))
+	expired, err := domain.NewPendingDuplicate("T2", "M3", "bob", []string{"docs/a.md"}, now)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, expired))
+
+	found, err := store.Find(ctx, "T1", now)
+	require.NoError(t, err)
+	assert.Equal(t, "M2", found.MessageID())
+	_, err = store.Find(ctx, "T2", now)
+	assert.ErrorIs(t, err, ports.ErrNotFound, "expired choices are not found")
+
+	purged, err := store.Purge(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.ErrorIs(t, store.Remove(ctx, "T2"), ports.ErrNotFound)
+
+	require.NoError(t, store.Remove(ctx, "T1"))
+	assert.ErrorIs(t, store.Remove(ctx, "T1"), ports.ErrNotFound, "a choice is removed once")
+	_, err = store.Find(ctx, "T1", now)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
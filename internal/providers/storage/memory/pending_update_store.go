@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// PendingUpdateStore implements the ports.PendingUpdateStore interface in memory
+type PendingUpdateStore struct {
+	mu      sync.Mutex
+	pending map[string]*domain.PendingUpdate
+}
+
+// NewPendingUpdateStore creates a new in-memory store of updates waiting for their diff to be applied
+func NewPendingUpdateStore() *PendingUpdateStore {
+	return &PendingUpdateStore{pending: make(map[string]*domain.PendingUpdate)}
+}
+
+// Put keeps the update waiting in a thread, replacing the earlier one
+func (s *PendingUpdateStore) Put(ctx context.Context, pending *domain.PendingUpdate) error {
+	if pending == nil {
+		return fmt.Errorf("pending update cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[pending.ThreadID()] = pending
+	return nil
+}
+
+// Find returns the update waiting in a thread at the time
+func (s *PendingUpdateStore) Find(ctx context.Context, threadID string, at time.Time) (*domain.PendingUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, ok := s.pending[threadID]
+	if !ok || pending.IsExpired(at) {
+		return nil, fmt.Errorf("pending update of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return pending, nil
+}
+
+// List returns the updates waiting at the time, oldest to expire first
+func (s *PendingUpdateStore) List(ctx context.Context, at time.Time) ([]*domain.PendingUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var waiting []*domain.PendingUpdate
+	for _, pending := range s.pending {
+		if !pending.IsExpired(at) {
+			waiting = append(waiting, pending)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool {
+		if !waiting[i].ExpiresAt().Equal(waiting[j].ExpiresAt()) {
+			return waiting[i].ExpiresAt().Before(waiting[j].ExpiresAt())
+		}
+		return waiting[i].ThreadID() < waiting[j].ThreadID()
+	})
+	return waiting, nil
+}
+
+// Remove takes the update of a thread out of the store
+func (s *PendingUpdateStore) Remove(ctx context.Context, threadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pending[threadID]; !ok {
+		return fmt.Errorf("pending update of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	delete(s.pending, threadID)
+	return nil
+}
+
+// Purge removes the updates expired at the time
+func (s *PendingUpdateStore) Purge(ctx context.Context, at time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for threadID, pending := range s.pending {
+		if pending.IsExpired(at) {
+			delete(s.pending, threadID)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// ProjectRepository implements the ports.ProjectRepository interface in memory.
+// Projects are stored as DTOs so changes to a loaded project are only persisted by Update.
+type ProjectRepository struct {
+	mu       sync.RWMutex
+	projects map[string]domain.ProjectDTO
+}
+
+// NewProjectRepository creates a new in-memory project repository
+func NewProjectRepository() *ProjectRepository {
+	return &ProjectRepository{
+		projects: make(map[string]domain.ProjectDTO),
+	}
+}
+
+// Save persists a project
+func (r *ProjectRepository) Save(ctx context.Context, project *domain.Project) error {
+	if project == nil {
+		return fmt.Errorf("project cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.projects[project.ID().String()] = project.ToDTO()
+	return nil
+}
+
+// FindByID retrieves a project by ID
+func (r *ProjectRepository) FindByID(ctx context.Context, id common.ID) (*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dto, ok := r.projects[id.String()]
+	if !ok {
+		return nil, fmt.Errorf("project %s: %w", id, ports.ErrNotFound)
+	}
+	return domain.ProjectFromDTO(dto)
+}
+
+// Update updates project information
+func (r *ProjectRepository) Update(ctx context.Context, project *domain.Project) error {
+	if project == nil {
+		return fmt.Errorf("project cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[project.ID().String()]; !ok {
+		return fmt.Errorf("project %s: %w", project.ID(), ports.ErrNotFound)
+	}
+	r.projects[project.ID().String()] = project.ToDTO()
+	return nil
+}
+
+// Delete removes a project
+func (r *ProjectRepository) Delete(ctx context.Context, id common.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.projects, id.String())
+	return nil
+}
+
+// FindByChannel retrieves the project bound to a chat channel
+func (r *ProjectRepository) FindByChannel(ctx context.Context, channelID string) (*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, dto := range r.projects {
+		for _, c := range dto.Channels {
+			if c == channelID {
+				return domain.ProjectFromDTO(dto)
+			}
+		}
+	}
+	return nil, fmt.Errorf("project for channel %s: %w", channelID, ports.ErrNotFound)
+}
+
+// List retrieves all projects ordered by name
+func (r *ProjectRepository) List(ctx context.Context) ([]*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make([]*domain.Project, 0, len(r.projects))
+	for _, dto := range r.projects {
+		project, err := domain.ProjectFromDTO(dto)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name() != projects[j].Name() {
+			return projects[i].Name() < projects[j].Name()
+		}
+		return projects[i].ID().String() < projects[j].ID().String()
+	})
+	return projects, nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewProjectRepository()
+
+	project := domain.MustNewProject("Billing", "Billing rewrite", []string{"Ship v2"})
+	require.NoError(t, project.BindChannel("C0001"))
+	require.NoError(t, repo.Save(ctx, project))
+
+	found, err := repo.FindByID(ctx, project.ID())
+	require.NoError(t, err)
+	assert.Equal(t, project, found)
+
+	found, err = repo.FindByChannel(ctx, "C0001")
+	require.NoError(t, err)
+	assert.Equal(t, project.ID(), found.ID())
+
+	_, err = repo.FindByChannel(ctx, "C9999")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	analytics := domain.MustNewProject("Analytics", "Usage dashboards", []string{"Track usage"})
+	require.NoError(t, repo.Save(ctx, analytics))
+	projects, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "Analytics", projects[0].Name())
+	assert.Equal(t, project.ID(), projects[1].ID())
+
+	require.NoError(t, repo.Delete(ctx, project.ID()))
+	_, err = repo.FindByID(ctx, project.ID())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.ErrorIs(t, repo.Update(ctx, project), ports.ErrNotFound)
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// ReplyOutbox implements the ports.ReplyOutbox interface in memory
+type ReplyOutbox struct {
+	mu      sync.Mutex
+	batches map[string]*domain.ReplyBatch
+	details map[string]replyDetails
+}
+
+// replyDetails is the confirmation kept for a message acknowledged with a reaction
+type replyDetails struct {
+	reply string
+	at    time.Time
+}
+
+// NewReplyOutbox creates a new in-memory reply outbox
+func NewReplyOutbox() *ReplyOutbox {
+	return &ReplyOutbox{batches: make(map[string]*domain.ReplyBatch), details: make(map[string]replyDetails)}
+}
+
+// Add keeps a batch, or appends its replies to the pending batch with the same key
+func (o *ReplyOutbox) Add(ctx context.Context, batch *domain.ReplyBatch) (bool, error) {
+	if batch == nil {
+		return false, fmt.Errorf("reply batch cannot be nil")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	pending, ok := o.batches[batch.Key()]
+	if !ok {
+		o.batches[batch.Key()] = copyReplyBatch(batch, batch.DueAt())
+		return true, nil
+	}
+	for _, reply := range batch.Replies() {
+		if err := pending.Add(reply); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// Due returns the batches due by now, the earliest first
+func (o *ReplyOutbox) Due(ctx context.Context, now time.Time) ([]*domain.ReplyBatch, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var due []*domain.ReplyBatch
+	for _, batch := range o.batches {
+		if batch.IsDue(now) {
+			due = append(due, copyReplyBatch(batch, batch.DueAt()))
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].DueAt().Equal(due[j].DueAt()) {
+			return due[i].DueAt().Before(due[j].DueAt())
+		}
+		return due[i].Key() < due[j].Key()
+	})
+	return due, nil
+}
+
+// Postpone moves the time a batch is due to
+func (o *ReplyOutbox) Postpone(ctx context.Context, key string, dueAt time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	batch, ok := o.batches[key]
+	if !ok {
+		return fmt.Errorf("reply batch %s: %w", key, ports.ErrNotFound)
+	}
+	o.batches[key] = copyReplyBatch(batch, dueAt)
+	return nil
+}
+
+// Remove takes a batch out of the outbox
+func (o *ReplyOutbox) Remove(ctx context.Context, key string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.batches[key]; !ok {
+		return fmt.Errorf("reply batch %s: %w", key, ports.ErrNotFound)
+	}
+	delete(o.batches, key)
+	return nil
+}
+
+// KeepDetails keeps the confirmation of a message acknowledged with a reaction, replacing the earlier one
+func (o *ReplyOutbox) KeepDetails(ctx context.Context, messageID, reply string, at time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.details[messageID] = replyDetails{reply: reply, at: at}
+	return nil
+}
+
+// Details returns the confirmation kept for a message
+func (o *ReplyOutbox) Details(ctx context.Context, messageID string) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	details, ok := o.details[messageID]
+	if !ok {
+		return "", fmt.Errorf("no details for message %s: %w", messageID, ports.ErrNotFound)
+	}
+	return details.reply, nil
+}
+
+// ForgetDetails removes the confirmations kept before the time
+func (o *ReplyOutbox) ForgetDetails(ctx context.Context, before time.Time) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	forgotten := 0
+	for messageID, details := range o.details {
+		if details.at.Before(before) {
+			delete(o.details, messageID)
+			forgotten++
+		}
+	}
+	return forgotten, nil
+}
+
+// copyReplyBatch copies a batch due at dueAt, so callers cannot change the kept one
+func copyReplyBatch(batch *domain.ReplyBatch, dueAt time.Time) *domain.ReplyBatch {
+	copied, _ := domain.RestoreReplyBatch(batch.Key(), batch.MessageID(), batch.ChannelID(), batch.Mode(), batch.Replies(), batch.Route(), dueAt)
+	return copied
+}
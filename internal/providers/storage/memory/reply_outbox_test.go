@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplyOutbox(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewReplyOutbox()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+
+	first, err := domain.NewReplyBatch("T1", "M1", "C0001", domain.ReplyModeThread, "📝 Captured idea", now.Add(time.Minute))
+	require.NoError(t, err)
+	added, err := outbox.Add(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// Replies of the same thread join the pending batch, which stays due when it was
+	more, err := domain.NewReplyBatch("T1", "M2", "C0001", domain.ReplyModeThread, "✅ Recorded decision", now.Add(time.Hour))
+	require.NoError(t, err)
+	added, err = outbox.Add(ctx, more)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	other, err := domain.NewReplyBatch("T2", "M3", "C0002", domain.ReplyModeThread, "📝 Captured idea", now)
+	require.NoError(t, err)
+	_, err = outbox.Add(ctx, other)
+	require.NoError(t, err)
+
+	due, err := outbox.Due(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "T2", due[0].Key())
+
+	due, err = outbox.Due(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "T2", due[0].Key(), "the earliest first")
+	assert.Equal(t, "M1", due[1].MessageID())
+	assert.Equal(t, []string{"📝 Captured idea", "✅ Recorded decision"}, due[1].Replies())
+
+	require.NoError(t, outbox.Postpone(ctx, "T1", now.Add(2*time.Hour)))
+	due, err = outbox.Due(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	require.NoError(t, outbox.Remove(ctx, "T2"))
+	assert.ErrorIs(t, outbox.Remove(ctx, "T2"), ports.ErrNotFound, "a batch is removed once")
+	assert.ErrorIs(t, outbox.Postpone(ctx, "T2", now), ports.ErrNotFound)
+	_, err = outbox.Add(ctx, nil)
+	assert.Error(t, err)
+}
+
+func TestReplyOutbox_Details(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewReplyOutbox()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, outbox.KeepDetails(ctx, "M1", "📝 Captured idea", now.Add(-time.Hour)))
+	require.NoError(t, outbox.KeepDetails(ctx, "M2", "📝 Captured idea", now))
+	require.NoError(t, outbox.KeepDetails(ctx, "M2", "✅ Recorded decision", now))
+
+	details, err := outbox.Details(ctx, "M2")
+	require.NoError(t, err)
+	assert.Equal(t, "✅ Recorded decision", details)
+
+	forgotten, err := outbox.ForgetDetails(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, forgotten)
+	_, err = outbox.Details(ctx, "M1")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	_, err = outbox.Details(ctx, "M2")
+	assert.NoError(t, err)
+}
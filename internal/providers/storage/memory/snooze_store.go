@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// SnoozeStore implements the ports.SnoozeStore interface in memory
+type SnoozeStore struct {
+	mu      sync.RWMutex
+	snoozes map[string]*domain.ThreadSnooze
+}
+
+// NewSnoozeStore creates a new in-memory snooze store
+func NewSnoozeStore() *SnoozeStore {
+	return &SnoozeStore{snoozes: make(map[string]*domain.ThreadSnooze)}
+}
+
+// Snooze stores the snooze of a thread, replacing the earlier one
+func (s *SnoozeStore) Snooze(ctx context.Context, snooze *domain.ThreadSnooze) error {
+	if snooze == nil {
+		return fmt.Errorf("snooze cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snoozes[snooze.ThreadID()] = snooze
+	return nil
+}
+
+// Find returns the snooze of a thread
+func (s *SnoozeStore) Find(ctx context.Context, threadID string) (*domain.ThreadSnooze, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snooze, ok := s.snoozes[threadID]
+	if !ok {
+		return nil, fmt.Errorf("snooze of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return snooze, nil
+}
+
+// Resume removes the snooze of a thread
+func (s *SnoozeStore) Resume(ctx context.Context, threadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.snoozes[threadID]; !ok {
+		return fmt.Errorf("snooze of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	delete(s.snoozes, threadID)
+	return nil
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnoozeStore_SnoozeFindResume(t *testing.T) {
+	ctx := context.Background()
+	store := NewSnoozeStore()
+	now := time.Now()
+
+	snooze, err := domain.NewThreadSnooze("T1", "alice", now, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.Snooze(ctx, snooze))
+	assert.Error(t, store.Snooze(ctx, nil))
+
+	// Snoozing a thread again replaces the snooze
+	again, err := domain.NewThreadSnooze("T1", "bob", now, 0)
+	require.NoError(t, err)
+	require.NoError(t, store.Snooze(ctx, again))
+
+	found, err := store.Find(ctx, "T1")
+	require.NoError(t, err)
+	assert.Equal(t, again, found)
+	_, err = store.Find(ctx, "T2")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	require.NoError(t, store.Resume(ctx, "T1"))
+	_, err = store.Find(ctx, "T1")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.ErrorIs(t, store.Resume(ctx, "T1"), ports.ErrNotFound)
+}
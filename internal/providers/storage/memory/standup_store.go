@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// StandupStore implements the ports.StandupStore interface in memory
+type StandupStore struct {
+	mu       sync.RWMutex
+	standups map[string]*domain.Standup
+}
+
+// NewStandupStore creates a new in-memory standup store
+func NewStandupStore() *StandupStore {
+	return &StandupStore{standups: make(map[string]*domain.Standup)}
+}
+
+// Save stores the standup of a project and day, replacing the earlier one
+func (s *StandupStore) Save(ctx context.Context, standup *domain.Standup) error {
+	if standup == nil {
+		return fmt.Errorf("standup cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.standups[standupKey(standup.ProjectID(), standup.Date())] = standup
+	return nil
+}
+
+// Find returns the standup of a project on a day
+func (s *StandupStore) Find(ctx context.Context, projectID common.ID, date string) (*domain.Standup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	standup, ok := s.standups[standupKey(projectID, date)]
+	if !ok {
+		return nil, fmt.Errorf("standup of project %s on %s: %w", projectID, date, ports.ErrNotFound)
+	}
+	return standup, nil
+}
+
+// ListOpen returns the standups not compiled yet, oldest first
+func (s *StandupStore) ListOpen(ctx context.Context) ([]*domain.Standup, error) {
+	return s.list(func(standup *domain.Standup) bool {
+		return !standup.Compiled()
+	}), nil
+}
+
+// List returns every standup, oldest first
+func (s *StandupStore) List(ctx context.Context) ([]*domain.Standup, error) {
+	return s.list(func(*domain.Standup) bool {
+		return true
+	}), nil
+}
+
+// list returns the standups matching the predicate, oldest first
+func (s *StandupStore) list(match func(*domain.Standup) bool) []*domain.Standup {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var standups []*domain.Standup
+	for _, standup := range s.standups {
+		if match(standup) {
+			standups = append(standups, standup)
+		}
+	}
+	sort.Slice(standups, func(i, j int) bool {
+		return standups[i].DueAt().Before(standups[j].DueAt())
+	})
+	return standups
+}
+
+func standupKey(projectID common.ID, date string) string {
+	return projectID.String() + "/" + date
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandupStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStandupStore()
+	projectID := common.GenerateID()
+	monday := time.Date(2024, 6, 10, 9, 30, 0, 0, time.UTC)
+
+	first, err := domain.NewStandup(projectID, "Billing", domain.DefaultStandupQuestions, monday, monday.Add(2*time.Hour))
+	require.NoError(t, err)
+	second, err := domain.NewStandup(projectID, "Billing", domain.DefaultStandupQuestions, monday.Add(24*time.Hour), monday.Add(26*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, second))
+	require.NoError(t, store.Save(ctx, first))
+	assert.Error(t, store.Save(ctx, nil))
+
+	found, err := store.Find(ctx, projectID, "2024-06-10")
+	require.NoError(t, err)
+	assert.Equal(t, first, found)
+	_, err = store.Find(ctx, projectID, "2024-06-12")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	open, err := store.ListOpen(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Standup{first, second}, open)
+
+	require.NoError(t, first.Compile(monday.Add(2*time.Hour)))
+	require.NoError(t, store.Save(ctx, first))
+	open, err = store.ListOpen(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Standup{second}, open)
+	all, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Standup{first, second}, all)
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// ThreadMappingCache implements the ports.ThreadMappingCache interface in memory
+type ThreadMappingCache struct {
+	mu       sync.RWMutex
+	mappings map[string]common.ID
+}
+
+// NewThreadMappingCache creates a new in-memory thread mapping cache
+func NewThreadMappingCache() *ThreadMappingCache {
+	return &ThreadMappingCache{mappings: make(map[string]common.ID)}
+}
+
+// Get returns the ID of our thread a chat thread maps to
+func (c *ThreadMappingCache) Get(ctx context.Context, channelID, externalID string) (common.ID, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	id, ok := c.mappings[channelID+":"+externalID]
+	if !ok {
+		return common.ID{}, fmt.Errorf("thread %s of %s: %w", externalID, channelID, ports.ErrNotFound)
+	}
+	return id, nil
+}
+
+// Map maps a chat thread to id unless it is mapped already, and returns the ID it maps to
+func (c *ThreadMappingCache) Map(ctx context.Context, channelID, externalID string, id common.ID) (common.ID, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := channelID + ":" + externalID
+	if mapped, ok := c.mappings[key]; ok {
+		return mapped, nil
+	}
+	c.mappings[key] = id
+	return id, nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// ThreadRepository implements the ports.ThreadRepository interface in memory
+type ThreadRepository struct {
+	mu      sync.RWMutex
+	threads map[string]*domain.Thread
+}
+
+// NewThreadRepository creates a new in-memory thread repository
+func NewThreadRepository() *ThreadRepository {
+	return &ThreadRepository{threads: make(map[string]*domain.Thread)}
+}
+
+// Save stores a thread, replacing the earlier version of it
+func (r *ThreadRepository) Save(ctx context.Context, thread *domain.Thread) error {
+	if thread == nil {
+		return fmt.Errorf("thread cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.threads[thread.ID().String()] = thread
+	return nil
+}
+
+// FindByID returns a thread by ID
+func (r *ThreadRepository) FindByID(ctx context.Context, id common.ID) (*domain.Thread, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	thread, ok := r.threads[id.String()]
+	if !ok {
+		return nil, fmt.Errorf("thread %s: %w", id, ports.ErrNotFound)
+	}
+	return thread, nil
+}
+
+// FindByChannel returns the threads of a channel, oldest first
+func (r *ThreadRepository) FindByChannel(ctx context.Context, channelID string) ([]*domain.Thread, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var threads []*domain.Thread
+	for _, thread := range r.threads {
+		if thread.ChannelID() == channelID {
+			threads = append(threads, thread)
+		}
+	}
+	sort.Slice(threads, func(i, j int) bool {
+		return threads[i].CreatedAt().Before(threads[j].CreatedAt())
+	})
+	return threads, nil
+}
+
+// AppendMessage adds a message to a stored thread
+func (r *ThreadRepository) AppendMessage(ctx context.Context, threadID common.ID, msg *domain.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	thread, ok := r.threads[threadID.String()]
+	if !ok {
+		return fmt.Errorf("thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return thread.AddMessage(msg)
+}
+
+// Delete removes a thread
+func (r *ThreadRepository) Delete(ctx context.Context, id common.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.threads[id.String()]; !ok {
+		return fmt.Errorf("thread %s: %w", id, ports.ErrNotFound)
+	}
+	delete(r.threads, id.String())
+	return nil
+}
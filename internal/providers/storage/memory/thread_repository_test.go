@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadRepository_SaveFindDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := NewThreadRepository()
+	id := common.GenerateID()
+
+	thread, err := domain.NewThread(id, "C0001", "Billing database")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, thread))
+	assert.Error(t, repo.Save(ctx, nil))
+
+	msg, err := domain.NewMessage(id, "alice", domain.MustNewMessageContent("Which database for billing?"), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	require.NoError(t, thread.AddMessage(msg))
+	require.NoError(t, repo.Save(ctx, thread))
+
+	found, err := repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Billing database", found.Title())
+	assert.Equal(t, 1, found.MessageCount())
+	_, err = repo.FindByID(ctx, common.GenerateID())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	require.NoError(t, repo.Delete(ctx, id))
+	_, err = repo.FindByID(ctx, id)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, id), ports.ErrNotFound)
+}
+
+func TestThreadRepository_FindByChannelAppendMessage(t *testing.T) {
+	ctx := context.Background()
+	repo := NewThreadRepository()
+
+	first, err := domain.RestoreThread(common.GenerateID(), "C0001", "1700000000.000100", "Billing database", nil,
+		time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	second, err := domain.RestoreThread(common.GenerateID(), "C0001", "1700000000.000200", "Invoice numbering", nil,
+		time.Date(2024, 7, 2, 9, 0, 0, 0, time.UTC), time.Date(2024, 7, 2, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	other, err := domain.NewThread(common.GenerateID(), "C0002", "Release train")
+	require.NoError(t, err)
+	for _, thread := range []*domain.Thread{second, other, first} {
+		require.NoError(t, repo.Save(ctx, thread))
+	}
+
+	threads, err := repo.FindByChannel(ctx, "C0001")
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Thread{first, second}, threads)
+	none, err := repo.FindByChannel(ctx, "C0003")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	msg, err := domain.NewMessage(first.ID(), "alice", domain.MustNewMessageContent("Postgres"), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.AppendMessage(ctx, first.ID(), msg))
+	require.NoError(t, repo.AppendMessage(ctx, first.ID(), msg))
+	found, err := repo.FindByID(ctx, first.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.MessageCount())
+	assert.ErrorIs(t, repo.AppendMessage(ctx, common.GenerateID(), msg), ports.ErrNotFound)
+}
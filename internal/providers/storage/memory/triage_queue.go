@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// TriageQueue implements the ports.TriageQueue interface in memory
+type TriageQueue struct {
+	mu    sync.RWMutex
+	items []*domain.TriageItem
+}
+
+// NewTriageQueue creates a new in-memory triage queue
+func NewTriageQueue() *TriageQueue {
+	return &TriageQueue{}
+}
+
+// Add adds a message to the queue, replacing the earlier entry of the same message
+func (q *TriageQueue) Add(ctx context.Context, item *domain.TriageItem) error {
+	if item == nil {
+		return fmt.Errorf("triage item cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.remove(item.Message().ID().String())
+	q.items = append(q.items, item)
+	return nil
+}
+
+// List returns the queued messages in the order they were queued
+func (q *TriageQueue) List(ctx context.Context) ([]*domain.TriageItem, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	items := make([]*domain.TriageItem, len(q.items))
+	copy(items, q.items)
+	return items, nil
+}
+
+// Take removes a message from the queue and returns it
+func (q *TriageQueue) Take(ctx context.Context, messageID string) (*domain.TriageItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item := q.remove(messageID)
+	if item == nil {
+		return nil, fmt.Errorf("triage item %s: %w", messageID, ports.ErrNotFound)
+	}
+	return item, nil
+}
+
+// remove takes a message out of the queue, the caller holds the lock
+func (q *TriageQueue) remove(messageID string) *domain.TriageItem {
+	for i, item := range q.items {
+		if item.Message().ID().String() == messageID {
+			q.items = append(q.items[:i], q.items[i+1:]...)
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriageQueue_AddListTake(t *testing.T) {
+	ctx := context.Background()
+	queue := NewTriageQueue()
+	now := time.Now()
+
+	add := func(msg *domain.Message) *domain.TriageItem {
+		item, err := domain.NewTriageItem(msg, now)
+		require.NoError(t, err)
+		require.NoError(t, queue.Add(ctx, item))
+		return item
+	}
+	first := add(newTestMessage(t, common.GenerateID(), "Maybe we move billing to Postgres"))
+	second := add(newTestMessage(t, common.GenerateID(), "Perhaps ship the beta on Friday"))
+	assert.Error(t, queue.Add(ctx, nil))
+
+	// Queuing a message again replaces it
+	again := add(first.Message())
+
+	items, err := queue.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.TriageItem{second, again}, items)
+
+	taken, err := queue.Take(ctx, second.Message().ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, second, taken)
+
+	_, err = queue.Take(ctx, second.Message().ID().String())
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	items, err = queue.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.TriageItem{again}, items)
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// DefaultProfileTTL is how long a UserProfileCache keeps profiles unless told otherwise
+const DefaultProfileTTL = time.Hour
+
+// cachedProfile is a user profile with when it expires
+type cachedProfile struct {
+	profile   domain.UserProfile
+	expiresAt time.Time
+}
+
+// UserProfileCache implements the ports.UserProfileCache interface in memory
+type UserProfileCache struct {
+	mu       sync.RWMutex
+	ttl      time.Duration
+	profiles map[string]cachedProfile
+	now      func() time.Time
+}
+
+// NewUserProfileCache creates a new in-memory user profile cache keeping profiles for ttl (0 uses
+// DefaultProfileTTL)
+func NewUserProfileCache(ttl time.Duration) *UserProfileCache {
+	if ttl <= 0 {
+		ttl = DefaultProfileTTL
+	}
+	return &UserProfileCache{ttl: ttl, profiles: make(map[string]cachedProfile), now: time.Now}
+}
+
+// Get returns the cached profile of a chat user
+func (c *UserProfileCache) Get(ctx context.Context, userID string) (domain.UserProfile, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cached, ok := c.profiles[userID]
+	if !ok || !c.now().Before(cached.expiresAt) {
+		return domain.UserProfile{}, fmt.Errorf("profile of user %s: %w", userID, ports.ErrNotFound)
+	}
+	return cached.profile, nil
+}
+
+// Put caches the profile of a chat user, replacing the earlier one
+func (c *UserProfileCache) Put(ctx context.Context, profile domain.UserProfile) error {
+	if profile.ID == "" {
+		return fmt.Errorf("profile must have a user ID")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.profiles[profile.ID] = cachedProfile{profile: profile, expiresAt: c.now().Add(c.ttl)}
+	return nil
+}
+
+// Delete removes the cached profile of a chat user
+func (c *UserProfileCache) Delete(ctx context.Context, userID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.profiles[userID]; !ok {
+		return fmt.Errorf("profile of user %s: %w", userID, ports.ErrNotFound)
+	}
+	delete(c.profiles, userID)
+	return nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserProfileCache_PutGet(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cache := NewUserProfileCache(time.Hour)
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.Get(ctx, "U1")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	profile := domain.UserProfile{ID: "U1", Name: "alice", RealName: "Alice Liddell"}
+	require.NoError(t, cache.Put(ctx, profile))
+	got, err := cache.Get(ctx, "U1")
+	require.NoError(t, err)
+	assert.Equal(t, profile, got)
+
+	now = now.Add(time.Hour)
+	_, err = cache.Get(ctx, "U1")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	require.NoError(t, cache.Put(ctx, profile))
+	require.NoError(t, cache.Delete(ctx, "U1"))
+	_, err = cache.Get(ctx, "U1")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.ErrorIs(t, cache.Delete(ctx, "U1"), ports.ErrNotFound)
+
+	assert.Error(t, cache.Put(ctx, domain.UserProfile{Name: "bob"}))
+}
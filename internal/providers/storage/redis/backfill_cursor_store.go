@@ -0,0 +1,34 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+)
+
+// BackfillCursorStore implements the ports.BackfillCursorStore interface on Redis, so a restarted replica
+// fetches the messages missed since the newest one processed. The cursors are kept in one hash and do not expire.
+type BackfillCursorStore struct {
+	client *Client
+}
+
+// NewBackfillCursorStore creates a backfill cursor store on the client's server
+func NewBackfillCursorStore(client *Client) *BackfillCursorStore {
+	return &BackfillCursorStore{client: client}
+}
+
+// Set records the newest message processed in a channel
+func (s *BackfillCursorStore) Set(ctx context.Context, channelID, messageID string) error {
+	if err := s.client.rdb.HSet(ctx, s.client.key("backfill"), channelID, messageID).Err(); err != nil {
+		return fmt.Errorf("failed to record the newest message processed in %s: %w", channelID, err)
+	}
+	return nil
+}
+
+// List returns the newest message processed in every channel, by channel ID
+func (s *BackfillCursorStore) List(ctx context.Context) (map[string]string, error) {
+	cursors, err := s.client.rdb.HGetAll(ctx, s.client.key("backfill")).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the newest messages processed: %w", err)
+	}
+	return cursors, nil
+}
@@ -0,0 +1,32 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillCursorStore_SetList(t *testing.T) {
+	forEachServer(t, func(t *testing.T, client *Client) {
+		ctx := context.Background()
+		store := NewBackfillCursorStore(client)
+
+		cursors, err := store.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, cursors)
+
+		require.NoError(t, store.Set(ctx, "C1", "1718000000.000100"))
+		require.NoError(t, store.Set(ctx, "C1", "1718000000.000200"))
+		require.NoError(t, store.Set(ctx, "C2", "1718000000.000300"))
+
+		// A restarted replica reads what the earlier one processed
+		cursors, err = NewBackfillCursorStore(client).List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"C1": "1718000000.000200", "C2": "1718000000.000300"}, cursors)
+
+		assert.Equal(t, time.Duration(-1), ttl(t, client, client.key("backfill")), "cursors do not expire")
+	})
+}
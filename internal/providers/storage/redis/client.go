@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Client is a pool of connections to the Redis server the caches of this package are kept in, on go-redis. It
+// is safe for concurrent use.
+type Client struct {
+	config *Config
+	rdb    *goredis.Client
+}
+
+// NewClient creates a client, connections are opened when commands need them
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrMissingURL
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	address, err := parseURL(config.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &goredis.Options{
+		Addr:         address.host,
+		Username:     address.username,
+		Password:     address.password,
+		DB:           address.database,
+		DialTimeout:  config.DialTimeout,
+		MaxIdleConns: config.PoolSize,
+	}
+	if options.DialTimeout == 0 {
+		options.DialTimeout = DefaultDialTimeout
+	}
+	if options.MaxIdleConns == 0 {
+		options.MaxIdleConns = DefaultPoolSize
+	}
+	if address.tls {
+		host, _, _ := net.SplitHostPort(address.host)
+		options.TLSConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	}
+	return &Client{config: config, rdb: goredis.NewClient(options)}, nil
+}
+
+// Ping checks the server can be reached and accepts the credentials
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.rdb.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to reach Redis: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connections, commands fail afterwards
+func (c *Client) Close() error {
+	return c.rdb.Close()
+}
+
+// key returns the key of an entry of a cache, starting with the configured prefix
+func (c *Client) key(parts ...string) string {
+	key := c.config.KeyPrefix
+	for i, part := range parts {
+		if i > 0 {
+			key += ":"
+		}
+		key += part
+	}
+	return key
+}
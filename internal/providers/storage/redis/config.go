@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingURL      = errors.New("Redis server URL is required")
+	ErrInvalidURL      = errors.New("Redis server URL must look like redis://host:6379/0 or rediss://host:6379/0")
+	ErrInvalidTimeout  = errors.New("dial timeout and TTLs cannot be negative")
+	ErrInvalidPoolSize = errors.New("pool size cannot be negative")
+)
+
+const (
+	// DefaultKeyPrefix starts the keys of every cache, so the bot can share a Redis database
+	DefaultKeyPrefix = "quill:"
+	// DefaultDialTimeout limits connecting to the server
+	DefaultDialTimeout = 5 * time.Second
+	// DefaultPoolSize is how many idle connections are kept for reuse
+	DefaultPoolSize = 8
+	// DefaultDedupTTL is how long the keys of handled events are remembered, longer than chats retry deliveries
+	DefaultDedupTTL = 24 * time.Hour
+	// DefaultProfileTTL is how long user profiles are cached
+	DefaultProfileTTL = time.Hour
+)
+
+// Config contains the settings of the Redis server the caches are kept in
+type Config struct {
+	// URL is the server to connect to, like redis://:password@host:6379/0, credentials and the database number
+	// are optional; rediss:// connects over TLS
+	URL string
+
+	// KeyPrefix starts the keys of every cache (default: quill:)
+	KeyPrefix string
+
+	// DialTimeout limits connecting to the server (optional, default: 5s)
+	DialTimeout time.Duration
+
+	// PoolSize is how many idle connections are kept for reuse (optional, default: 8)
+	PoolSize int
+
+	// DedupTTL is how long the keys of handled events are remembered (optional, default: 24h)
+	DedupTTL time.Duration
+
+	// ProfileTTL is how long user profiles are cached (optional, default: 1h). Thread mappings do not expire.
+	ProfileTTL time.Duration
+}
+
+// NewDefaultConfig creates a Config with default values
+func NewDefaultConfig(serverURL string) *Config {
+	return &Config{
+		URL:         serverURL,
+		KeyPrefix:   DefaultKeyPrefix,
+		DialTimeout: DefaultDialTimeout,
+		PoolSize:    DefaultPoolSize,
+		DedupTTL:    DefaultDedupTTL,
+		ProfileTTL:  DefaultProfileTTL,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.URL) == "" {
+		return ErrMissingURL
+	}
+
+	if _, err := parseURL(c.URL); err != nil {
+		return err
+	}
+
+	if c.DialTimeout < 0 || c.DedupTTL < 0 || c.ProfileTTL < 0 {
+		return ErrInvalidTimeout
+	}
+
+	if c.PoolSize < 0 {
+		return ErrInvalidPoolSize
+	}
+
+	return nil
+}
+
+// serverAddress is where the server is and how to log in to it
+type serverAddress struct {
+	host     string
+	tls      bool
+	username string
+	password string
+	database int
+}
+
+// parseURL reads a redis:// or rediss:// URL
+func parseURL(serverURL string) (serverAddress, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Hostname() == "" {
+		return serverAddress{}, ErrInvalidURL
+	}
+
+	address := serverAddress{host: u.Host, tls: u.Scheme == "rediss"}
+	if u.Port() == "" {
+		address.host = u.Host + ":6379"
+	}
+	if u.User != nil {
+		address.username = u.User.Username()
+		address.password, _ = u.User.Password()
+	}
+	if database := strings.TrimPrefix(u.Path, "/"); database != "" {
+		if address.database, err = strconv.Atoi(database); err != nil || address.database < 0 {
+			return serverAddress{}, ErrInvalidURL
+		}
+	}
+	return address, nil
+}
@@ -0,0 +1,33 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DedupStore implements the ports.DedupStore interface on Redis, so replicas receiving the same event handle
+// it once. Keys are remembered for Config.DedupTTL.
+type DedupStore struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewDedupStore creates a dedup store on the client's server
+func NewDedupStore(client *Client) *DedupStore {
+	ttl := client.config.DedupTTL
+	if ttl <= 0 {
+		ttl = DefaultDedupTTL
+	}
+	return &DedupStore{client: client, ttl: ttl}
+}
+
+// MarkSeen records a key and reports whether it was new. The check and the record are one command, so of the
+// replicas recording the same key at once only one sees it as new.
+func (s *DedupStore) MarkSeen(ctx context.Context, key string) (bool, error) {
+	isNew, err := s.client.rdb.SetNX(ctx, s.client.key("dedup", key), "1", s.ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark %s as seen: %w", key, err)
+	}
+	return isNew, nil
+}
@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupStore_MarkSeen(t *testing.T) {
+	forEachServer(t, func(t *testing.T, client *Client) {
+		ctx := context.Background()
+		first, second := NewDedupStore(client), NewDedupStore(client)
+
+		isNew, err := first.MarkSeen(ctx, "C1:1718000000.000100")
+		require.NoError(t, err)
+		assert.True(t, isNew)
+
+		// Another replica sees the same key as handled
+		isNew, err = second.MarkSeen(ctx, "C1:1718000000.000100")
+		require.NoError(t, err)
+		assert.False(t, isNew)
+
+		assert.InDelta(t, DefaultDedupTTL, ttl(t, client, client.key("dedup", "C1:1718000000.000100")), float64(time.Second))
+	})
+}
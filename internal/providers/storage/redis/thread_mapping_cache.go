@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ThreadMappingCache implements the ports.ThreadMappingCache interface on Redis, so a thread started on one
+// replica continues on the others. Mappings do not expire.
+type ThreadMappingCache struct {
+	client *Client
+}
+
+// NewThreadMappingCache creates a thread mapping cache on the client's server
+func NewThreadMappingCache(client *Client) *ThreadMappingCache {
+	return &ThreadMappingCache{client: client}
+}
+
+// Get returns the ID of our thread a chat thread maps to
+func (c *ThreadMappingCache) Get(ctx context.Context, channelID, externalID string) (common.ID, error) {
+	value, err := c.client.rdb.Get(ctx, c.client.key("thread", channelID, externalID)).Result()
+	if errors.Is(err, goredis.Nil) {
+		return common.ID{}, fmt.Errorf("thread %s of %s: %w", externalID, channelID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return common.ID{}, fmt.Errorf("failed to get thread %s of %s: %w", externalID, channelID, err)
+	}
+	id, err := common.NewID(value)
+	if err != nil {
+		return common.ID{}, fmt.Errorf("invalid ID of thread %s of %s: %w", externalID, channelID, err)
+	}
+	return id, nil
+}
+
+// Map maps a chat thread to id unless it is mapped already, and returns the ID it maps to
+func (c *ThreadMappingCache) Map(ctx context.Context, channelID, externalID string, id common.ID) (common.ID, error) {
+	mapped, err := c.client.rdb.SetNX(ctx, c.client.key("thread", channelID, externalID), id.String(), 0).Result()
+	if err != nil {
+		return common.ID{}, fmt.Errorf("failed to map thread %s of %s: %w", externalID, channelID, err)
+	}
+	if mapped {
+		return id, nil
+	}
+	// Mapped already, by this or another replica
+	return c.Get(ctx, channelID, externalID)
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadMappingCache_MapGet(t *testing.T) {
+	forEachServer(t, func(t *testing.T, client *Client) {
+		ctx := context.Background()
+		cache := NewThreadMappingCache(client)
+
+		_, err := cache.Get(ctx, "C1", "1718000000.000100")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+
+		first := common.GenerateID()
+		mapped, err := cache.Map(ctx, "C1", "1718000000.000100", first)
+		require.NoError(t, err)
+		assert.Equal(t, first, mapped)
+
+		// A second replica creating the same thread gets the first ID
+		mapped, err = cache.Map(ctx, "C1", "1718000000.000100", common.GenerateID())
+		require.NoError(t, err)
+		assert.Equal(t, first, mapped)
+
+		got, err := cache.Get(ctx, "C1", "1718000000.000100")
+		require.NoError(t, err)
+		assert.Equal(t, first, got)
+
+		assert.Equal(t, time.Duration(-1), ttl(t, client, client.key("thread", "C1", "1718000000.000100")), "mappings do not expire")
+	})
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// profileDTO is how a user profile is kept in Redis
+type profileDTO struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"realName,omitempty"`
+}
+
+// UserProfileCache implements the ports.UserProfileCache interface on Redis, so replicas share the profiles they
+// fetched. Profiles expire after Config.ProfileTTL.
+type UserProfileCache struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewUserProfileCache creates a user profile cache on the client's server
+func NewUserProfileCache(client *Client) *UserProfileCache {
+	ttl := client.config.ProfileTTL
+	if ttl <= 0 {
+		ttl = DefaultProfileTTL
+	}
+	return &UserProfileCache{client: client, ttl: ttl}
+}
+
+// Get returns the cached profile of a chat user
+func (c *UserProfileCache) Get(ctx context.Context, userID string) (domain.UserProfile, error) {
+	data, err := c.client.rdb.Get(ctx, c.client.key("profile", userID)).Result()
+	if errors.Is(err, goredis.Nil) {
+		return domain.UserProfile{}, fmt.Errorf("profile of user %s: %w", userID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return domain.UserProfile{}, fmt.Errorf("failed to get profile of user %s: %w", userID, err)
+	}
+
+	var dto profileDTO
+	if err := json.Unmarshal([]byte(data), &dto); err != nil {
+		return domain.UserProfile{}, fmt.Errorf("failed to decode profile of user %s: %w", userID, err)
+	}
+	return domain.UserProfile{ID: dto.ID, Name: dto.Name, RealName: dto.RealName}, nil
+}
+
+// Put caches the profile of a chat user, replacing the earlier one
+func (c *UserProfileCache) Put(ctx context.Context, profile domain.UserProfile) error {
+	if profile.ID == "" {
+		return fmt.Errorf("profile must have a user ID")
+	}
+
+	data, err := json.Marshal(profileDTO{ID: profile.ID, Name: profile.Name, RealName: profile.RealName})
+	if err != nil {
+		return fmt.Errorf("failed to encode profile of user %s: %w", profile.ID, err)
+	}
+	if err := c.client.rdb.Set(ctx, c.client.key("profile", profile.ID), data, c.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache profile of user %s: %w", profile.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the cached profile of a chat user
+func (c *UserProfileCache) Delete(ctx context.Context, userID string) error {
+	deleted, err := c.client.rdb.Del(ctx, c.client.key("profile", userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete profile of user %s: %w", userID, err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("profile of user %s: %w", userID, ports.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserProfileCache_PutGet(t *testing.T) {
+	forEachServer(t, func(t *testing.T, client *Client) {
+		ctx := context.Background()
+		cache := NewUserProfileCache(client)
+
+		_, err := cache.Get(ctx, "U1")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+
+		profile := domain.UserProfile{ID: "U1", Name: "alice", RealName: "Alice Liddell"}
+		require.NoError(t, cache.Put(ctx, profile))
+		got, err := cache.Get(ctx, "U1")
+		require.NoError(t, err)
+		assert.Equal(t, profile, got)
+
+		assert.InDelta(t, DefaultProfileTTL, ttl(t, client, client.key("profile", "U1")), float64(time.Second))
+
+		require.NoError(t, cache.Delete(ctx, "U1"))
+		_, err = cache.Get(ctx, "U1")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+		assert.ErrorIs(t, cache.Delete(ctx, "U1"), ports.ErrNotFound)
+
+		assert.Error(t, cache.Put(ctx, domain.UserProfile{Name: "bob"}))
+	})
+}
@@ -0,0 +1,196 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	upsertApprovalRequestQuery = `INSERT INTO approval_requests (thread_id, message_id, requested_at, request) VALUES (?, ?, ?, ?)
+ON CONFLICT (thread_id) DO UPDATE SET message_id = excluded.message_id, requested_at = excluded.requested_at,
+request = excluded.request`
+	selectApprovalRequestQuery     = `SELECT request FROM approval_requests WHERE thread_id = ?`
+	selectApprovalRequestsQuery    = `SELECT request FROM approval_requests ORDER BY requested_at, thread_id`
+	deleteApprovalRequestQuery     = `DELETE FROM approval_requests WHERE thread_id = ?`
+	selectApprovalRequestPageQuery = `SELECT thread_id, request FROM approval_requests WHERE thread_id > ? ORDER BY thread_id LIMIT 100`
+	// reencryptApprovalRequestQuery leaves out requests replaced since they were read
+	reencryptApprovalRequestQuery = `UPDATE approval_requests SET request = ? WHERE thread_id = ? AND request = ?`
+)
+
+// ApprovalRequestStore implements the ports.ApprovalRequestStore interface on a SQL database, so the approvals
+// given to the decisions waiting for them are kept across restarts, and count on any replica
+type ApprovalRequestStore struct {
+	db      *sql.DB
+	dialect Dialect
+	// cipher encrypts the content and sender of the decisions, nil stores them in plaintext
+	cipher domain.FieldCipher
+}
+
+// NewApprovalRequestStore creates a store of approval requests, call Migrate to create its table
+func NewApprovalRequestStore(db *sql.DB, dialect Dialect) (*ApprovalRequestStore, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &ApprovalRequestStore{db: db, dialect: dialect}, nil
+}
+
+// NewEncryptedApprovalRequestStore creates a store of approval requests keeping the content and sender of the
+// decisions encrypted with the cipher, call Migrate to create its table
+func NewEncryptedApprovalRequestStore(db *sql.DB, dialect Dialect, cipher domain.FieldCipher) (*ApprovalRequestStore, error) {
+	if cipher == nil {
+		return nil, ErrNilCipher
+	}
+	store, err := NewApprovalRequestStore(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	store.cipher = cipher
+	return store, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the store
+func (s *ApprovalRequestStore) Migrate(ctx context.Context) error {
+	return migrate(ctx, s.db, s.dialect)
+}
+
+// Put keeps the request of the thread of its decision, replacing the earlier one
+func (s *ApprovalRequestStore) Put(ctx context.Context, request *domain.ApprovalRequest) error {
+	if request == nil {
+		return fmt.Errorf("approval request cannot be nil")
+	}
+
+	msg := request.Message()
+	encoded, err := s.encode(request.ToDTO())
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.rebind(upsertApprovalRequestQuery), msg.ThreadID().String(), msg.ID().String(),
+		request.RequestedAt().UnixMicro(), encoded); err != nil {
+		return fmt.Errorf("failed to save approval request of thread %s: %w", msg.ThreadID(), err)
+	}
+	return nil
+}
+
+// Find returns the request waiting in a thread
+func (s *ApprovalRequestStore) Find(ctx context.Context, threadID string) (*domain.ApprovalRequest, error) {
+	request, err := s.scan(s.db.QueryRowContext(ctx, s.dialect.rebind(selectApprovalRequestQuery), threadID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("approval request of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// List returns the requests waiting in every thread, oldest first
+func (s *ApprovalRequestStore) List(ctx context.Context) ([]*domain.ApprovalRequest, error) {
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(selectApprovalRequestsQuery))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval requests: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var requests []*domain.ApprovalRequest
+	for rows.Next() {
+		request, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list approval requests: %w", err)
+	}
+	return requests, nil
+}
+
+// Remove takes the request of a thread out of the store
+func (s *ApprovalRequestStore) Remove(ctx context.Context, threadID string) error {
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind(deleteApprovalRequestQuery), threadID)
+	if err != nil {
+		return fmt.Errorf("failed to remove approval request of thread %s: %w", threadID, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("approval request of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// Reencrypt encrypts every approval request again with the cipher's current key, after a key rotation or when
+// encryption was turned on for requests stored in plaintext. It returns how many it encrypted.
+func (s *ApprovalRequestStore) Reencrypt(ctx context.Context) (int, error) {
+	if s.cipher == nil {
+		return 0, nil
+	}
+	return reencryptRows(ctx, s.db, s.dialect, selectApprovalRequestPageQuery, reencryptApprovalRequestQuery, func(request string) (string, error) {
+		dto, err := s.decode(request)
+		if err != nil {
+			return "", err
+		}
+		return s.encode(dto)
+	})
+}
+
+// scan reads a row of the approval_requests table, sql.ErrNoRows when there is none
+func (s *ApprovalRequestStore) scan(row interface{ Scan(...interface{}) error }) (*domain.ApprovalRequest, error) {
+	var encoded string
+	if err := row.Scan(&encoded); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read approval request: %w", err)
+	}
+
+	dto, err := s.decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	request, err := domain.ApprovalRequestFromDTO(dto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore approval request of message %s: %w", dto.Message.ID, err)
+	}
+	return request, nil
+}
+
+// encode encodes a request to store, with the fields of its decision encrypted when the store has a cipher
+func (s *ApprovalRequestStore) encode(dto domain.ApprovalRequestDTO) (string, error) {
+	if s.cipher != nil {
+		encrypted, err := dto.Message.Encrypted(s.cipher)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt approval request of message %s: %w", dto.Message.ID, err)
+		}
+		dto.Message = encrypted
+	}
+	encoded, err := json.Marshal(dto)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode approval request of message %s: %w", dto.Message.ID, err)
+	}
+	return string(encoded), nil
+}
+
+// decode decodes a stored request, and decrypts the fields of its decision when the store has a cipher
+func (s *ApprovalRequestStore) decode(encoded string) (domain.ApprovalRequestDTO, error) {
+	var dto domain.ApprovalRequestDTO
+	if err := json.Unmarshal([]byte(encoded), &dto); err != nil {
+		return domain.ApprovalRequestDTO{}, fmt.Errorf("failed to decode approval request: %w", err)
+	}
+	if s.cipher == nil {
+		return dto, nil
+	}
+	decrypted, err := dto.Message.Decrypted(s.cipher)
+	if err != nil {
+		return domain.ApprovalRequestDTO{}, fmt.Errorf("failed to decrypt approval request of message %s: %w", dto.Message.ID, err)
+	}
+	dto.Message = decrypted
+	return dto, nil
+}
@@ -0,0 +1,55 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalRequestStore(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect).ApprovalRequests()
+		now := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+		config := domain.DocumentationConfig{
+			ApprovalPolicies: []domain.ApprovalPolicy{{Approvals: 2}, {Role: "architect"}},
+			Roles:            map[string][]string{"architect": {"carol"}},
+		}
+
+		later := domain.NewApprovalRequest(newMessage(t, common.GenerateID(), "We will shard invoices"), config, now.Add(time.Hour))
+		require.NoError(t, store.Put(ctx, later))
+		first := domain.NewApprovalRequest(newMessage(t, common.GenerateID(), "We will use Postgres"), config, now)
+		require.NoError(t, store.Put(ctx, first))
+		assert.Error(t, store.Put(ctx, nil))
+
+		// Approvals are kept once the request is put back
+		require.NoError(t, first.Approve("carol"))
+		require.NoError(t, store.Put(ctx, first))
+		threadID := first.Message().ThreadID().String()
+		found, err := store.Find(ctx, threadID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"carol"}, found.Approvers())
+		assert.Equal(t, "1 approval", found.Missing(), "the roles are kept")
+		assert.Equal(t, first.Message().Content(), found.Message().Content())
+		assert.Equal(t, now, found.RequestedAt().UTC())
+
+		waiting, err := store.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, waiting, 2)
+		assert.Equal(t, threadID, waiting[0].Message().ThreadID().String())
+		assert.Equal(t, later.Message().ID(), waiting[1].Message().ID())
+
+		require.NoError(t, store.Remove(ctx, threadID))
+		assert.ErrorIs(t, store.Remove(ctx, threadID), ports.ErrNotFound, "a request is removed once")
+		_, err = store.Find(ctx, threadID)
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+	})
+	_, err := NewApprovalRequestStore(nil, SQLite)
+	assert.ErrorIs(t, err, ErrNilDatabase)
+}
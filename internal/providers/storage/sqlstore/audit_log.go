@@ -0,0 +1,96 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+)
+
+const (
+	insertAuditEntryQuery  = `INSERT INTO audit_entries (id, action, actor, subject, from_value, to_value, at, correlation_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	selectAuditEntryQuery  = `SELECT id, action, actor, subject, from_value, to_value, at, correlation_id FROM audit_entries ORDER BY at, id`
+	replaceAuditActorQuery = `UPDATE audit_entries SET actor = ? WHERE actor = ?`
+)
+
+// AuditLog implements the ports.AuditLog interface on a SQL database
+type AuditLog struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewAuditLog creates an audit log, call Migrate to create its table
+func NewAuditLog(db *sql.DB, dialect Dialect) (*AuditLog, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &AuditLog{db: db, dialect: dialect}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the audit log
+func (l *AuditLog) Migrate(ctx context.Context) error {
+	return migrate(ctx, l.db, l.dialect)
+}
+
+// Record appends an entry to the log
+func (l *AuditLog) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry cannot be nil")
+	}
+	if _, err := l.db.ExecContext(ctx, l.dialect.rebind(insertAuditEntryQuery), entry.ID().String(), entry.Action().String(),
+		entry.Actor(), entry.Subject(), entry.From(), entry.To(), entry.At().UnixMicro(), entry.CorrelationID().String()); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns all entries, oldest first
+func (l *AuditLog) List(ctx context.Context) ([]*domain.AuditEntry, error) {
+	rows, err := l.db.QueryContext(ctx, l.dialect.rebind(selectAuditEntryQuery))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		var id, action, actor, subject, from, to, correlationID string
+		var at int64
+		if err := rows.Scan(&id, &action, &actor, &subject, &from, &to, &at, &correlationID); err != nil {
+			return nil, fmt.Errorf("failed to read audit entry: %w", err)
+		}
+		entryID, err := common.NewID(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored audit entry ID %q: %w", id, err)
+		}
+		entry, err := domain.RestoreAuditEntry(entryID, domain.AuditAction(action), actor, subject, from, to, time.UnixMicro(at).UTC())
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore audit entry %s: %w", id, err)
+		}
+		entry.SetCorrelationID(domain.CorrelationID(correlationID))
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// ReplaceActor replaces the actor of the entries made by actor, and returns how many it changed
+func (l *AuditLog) ReplaceActor(ctx context.Context, actor, replacement string) (int, error) {
+	result, err := l.db.ExecContext(ctx, l.dialect.rebind(replaceAuditActorQuery), replacement, actor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace audit actor: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace audit actor: %w", err)
+	}
+	return int(affected), nil
+}
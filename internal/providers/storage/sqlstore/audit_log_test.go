@@ -0,0 +1,47 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+		log := store.AuditLog()
+
+		var recorded []*domain.AuditEntry
+		for _, actor := range []string{"U0001", "U0002", "U0001"} {
+			entry, err := domain.NewAuditEntry(domain.AuditActionRecategorize, actor, "docs/other/pricing.md", "other", "product")
+			require.NoError(t, err)
+			require.NoError(t, log.Record(ctx, entry))
+			recorded = append(recorded, entry)
+		}
+		assert.Error(t, log.Record(ctx, nil))
+
+		entries, err := log.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		for i, entry := range entries {
+			assert.Equal(t, recorded[i].ID(), entry.ID())
+			assert.Equal(t, domain.AuditActionRecategorize, entry.Action())
+			assert.Equal(t, recorded[i].Actor(), entry.Actor())
+			assert.Equal(t, "docs/other/pricing.md", entry.Subject())
+			assert.Equal(t, "other", entry.From())
+			assert.Equal(t, "product", entry.To())
+		}
+
+		changed, err := log.ReplaceActor(ctx, "U0001", "person-1a2b")
+		require.NoError(t, err)
+		assert.Equal(t, 2, changed)
+		entries, err = log.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "person-1a2b", entries[0].Actor())
+		assert.Equal(t, "U0002", entries[1].Actor())
+	})
+}
@@ -0,0 +1,196 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	upsertDeadLetterQuery = `INSERT INTO dead_letters (message_id, error, failed_at, queued) VALUES (?, ?, ?, ?)
+ON CONFLICT (message_id) DO UPDATE SET error = excluded.error, failed_at = excluded.failed_at, queued = excluded.queued`
+	selectDeadLetterQuery     = `SELECT error, failed_at, queued FROM dead_letters WHERE message_id = ?`
+	selectDeadLettersQuery    = `SELECT error, failed_at, queued FROM dead_letters ORDER BY failed_at, message_id`
+	deleteDeadLetterQuery     = `DELETE FROM dead_letters WHERE message_id = ?`
+	selectDeadLetterPageQuery = `SELECT message_id, queued FROM dead_letters WHERE message_id > ? ORDER BY message_id LIMIT 100`
+	// reencryptDeadLetterQuery leaves out dead letters replaced since they were read
+	reencryptDeadLetterQuery = `UPDATE dead_letters SET queued = ? WHERE message_id = ? AND queued = ?`
+)
+
+// DeadLetterQueue implements the ports.DeadLetterQueue interface on a SQL database, so failed messages wait for
+// an operator across restarts
+type DeadLetterQueue struct {
+	db      *sql.DB
+	dialect Dialect
+	// cipher encrypts the content and sender of the failed messages, nil stores them in plaintext
+	cipher domain.FieldCipher
+}
+
+// NewDeadLetterQueue creates a dead-letter queue, call Migrate to create its table
+func NewDeadLetterQueue(db *sql.DB, dialect Dialect) (*DeadLetterQueue, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &DeadLetterQueue{db: db, dialect: dialect}, nil
+}
+
+// NewEncryptedDeadLetterQueue creates a dead-letter queue storing the content and sender of the failed messages
+// encrypted with the cipher, call Migrate to create its table
+func NewEncryptedDeadLetterQueue(db *sql.DB, dialect Dialect, cipher domain.FieldCipher) (*DeadLetterQueue, error) {
+	if cipher == nil {
+		return nil, ErrNilCipher
+	}
+	queue, err := NewDeadLetterQueue(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	queue.cipher = cipher
+	return queue, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the queue
+func (q *DeadLetterQueue) Migrate(ctx context.Context) error {
+	return migrate(ctx, q.db, q.dialect)
+}
+
+// Add keeps a failed message, replacing the earlier failure of the same message
+func (q *DeadLetterQueue) Add(ctx context.Context, letter *domain.DeadLetter) error {
+	if letter == nil {
+		return fmt.Errorf("dead letter cannot be nil")
+	}
+
+	queued, err := q.encode(letter.Queued().ToDTO())
+	if err != nil {
+		return err
+	}
+	if _, err := q.db.ExecContext(ctx, q.dialect.rebind(upsertDeadLetterQuery), letter.ID(), letter.Error(),
+		letter.FailedAt().UnixMicro(), queued); err != nil {
+		return fmt.Errorf("failed to add dead letter %s: %w", letter.ID(), err)
+	}
+	return nil
+}
+
+// List returns the dead letters, the oldest failure first
+func (q *DeadLetterQueue) List(ctx context.Context) ([]*domain.DeadLetter, error) {
+	rows, err := q.db.QueryContext(ctx, q.dialect.rebind(selectDeadLettersQuery))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []*domain.DeadLetter
+	for rows.Next() {
+		letter, err := q.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// Get returns the dead letter of a message, ports.ErrNotFound when the message has none
+func (q *DeadLetterQueue) Get(ctx context.Context, messageID string) (*domain.DeadLetter, error) {
+	letter, err := q.scan(q.db.QueryRowContext(ctx, q.dialect.rebind(selectDeadLetterQuery), messageID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("dead letter %s: %w", messageID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return letter, nil
+}
+
+// Remove deletes the dead letter of a message, ports.ErrNotFound when the message has none
+func (q *DeadLetterQueue) Remove(ctx context.Context, messageID string) error {
+	result, err := q.db.ExecContext(ctx, q.dialect.rebind(deleteDeadLetterQuery), messageID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dead letter %s: %w", messageID, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("dead letter %s: %w", messageID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// Reencrypt encrypts every dead letter again with the cipher's current key, after a key rotation or when
+// encryption was turned on for dead letters stored in plaintext. It returns how many it encrypted.
+func (q *DeadLetterQueue) Reencrypt(ctx context.Context) (int, error) {
+	if q.cipher == nil {
+		return 0, nil
+	}
+	return reencryptRows(ctx, q.db, q.dialect, selectDeadLetterPageQuery, reencryptDeadLetterQuery, func(queued string) (string, error) {
+		dto, err := q.decode(queued)
+		if err != nil {
+			return "", err
+		}
+		return q.encode(dto)
+	})
+}
+
+// scan reads a row of the dead_letters table, sql.ErrNoRows when there is none
+func (q *DeadLetterQueue) scan(row interface{ Scan(...interface{}) error }) (*domain.DeadLetter, error) {
+	var cause, queued string
+	var failedAt int64
+	if err := row.Scan(&cause, &failedAt, &queued); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read dead letter: %w", err)
+	}
+
+	dto, err := q.decode(queued)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := domain.QueuedMessageFromDTO(dto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore dead letter %s: %w", dto.Message.ID, err)
+	}
+	return domain.NewDeadLetter(msg, cause, time.UnixMicro(failedAt))
+}
+
+// encode encodes a failed message to store, with its fields encrypted when the queue has a cipher
+func (q *DeadLetterQueue) encode(dto domain.QueuedMessageDTO) (string, error) {
+	if q.cipher != nil {
+		encrypted, err := dto.Message.Encrypted(q.cipher)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt dead letter %s: %w", dto.Message.ID, err)
+		}
+		dto.Message = encrypted
+	}
+	queued, err := json.Marshal(dto)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode dead letter %s: %w", dto.Message.ID, err)
+	}
+	return string(queued), nil
+}
+
+// decode decodes a stored failed message, and decrypts its fields when the queue has a cipher
+func (q *DeadLetterQueue) decode(queued string) (domain.QueuedMessageDTO, error) {
+	var dto domain.QueuedMessageDTO
+	if err := json.Unmarshal([]byte(queued), &dto); err != nil {
+		return domain.QueuedMessageDTO{}, fmt.Errorf("failed to decode dead letter: %w", err)
+	}
+	if q.cipher == nil {
+		return dto, nil
+	}
+	decrypted, err := dto.Message.Decrypted(q.cipher)
+	if err != nil {
+		return domain.QueuedMessageDTO{}, fmt.Errorf("failed to decrypt dead letter %s: %w", dto.Message.ID, err)
+	}
+	dto.Message = decrypted
+	return dto, nil
+}
@@ -0,0 +1,57 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+		queue := store.DeadLetters()
+		now := time.Now()
+
+		add := func(queued *domain.QueuedMessage, failedAt time.Time) *domain.DeadLetter {
+			letter, err := domain.NewDeadLetter(queued, "analysis timed out", failedAt)
+			require.NoError(t, err)
+			require.NoError(t, queue.Add(ctx, letter))
+			return letter
+		}
+		postgres, err := domain.NewQueuedMessage(newMessage(t, common.GenerateID(), "We will use Postgres"), map[string]string{"channel": "C0001"})
+		require.NoError(t, err)
+		beta, err := domain.NewQueuedMessage(newMessage(t, common.GenerateID(), "Ship the beta on Friday"), nil)
+		require.NoError(t, err)
+		first := add(postgres, now)
+		second := add(beta, now.Add(time.Second))
+		assert.Error(t, queue.Add(ctx, nil))
+
+		// A message failing again after a retry replaces its earlier failure
+		again := add(first.Queued().Retry(), now.Add(2*time.Second))
+
+		letters, err := queue.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, letters, 2)
+		assert.Equal(t, second.ID(), letters[0].ID())
+		assert.Equal(t, again.ID(), letters[1].ID())
+		assert.Equal(t, 2, letters[1].Attempts())
+		assert.Equal(t, map[string]string{"channel": "C0001"}, letters[1].Queued().Route())
+
+		found, err := queue.Get(ctx, second.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "analysis timed out", found.Error())
+		assert.Equal(t, beta.Message().Content(), found.Queued().Message().Content())
+
+		require.NoError(t, queue.Remove(ctx, second.ID()))
+		_, err = queue.Get(ctx, second.ID())
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+		assert.ErrorIs(t, queue.Remove(ctx, second.ID()), ports.ErrNotFound)
+	})
+}
@@ -0,0 +1,86 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultDedupTTL is how long the keys of handled events are remembered, longer than chats retry deliveries
+const DefaultDedupTTL = 24 * time.Hour
+
+const (
+	// markSeenQuery records a key, or renews an expired one; it changes no row for a key seen within the TTL
+	markSeenQuery = `INSERT INTO dedup_keys (dedup_key, seen_at) VALUES (?, ?)
+ON CONFLICT (dedup_key) DO UPDATE SET seen_at = excluded.seen_at WHERE dedup_keys.seen_at < ?`
+	purgeDedupKeysQuery = `DELETE FROM dedup_keys WHERE seen_at < ?`
+)
+
+// DedupStore implements the ports.DedupStore interface on a SQL database, so an event delivered again after a
+// restart, or to another replica, is handled once. Keys are remembered for the TTL, expired ones are purged as
+// new keys are recorded.
+type DedupStore struct {
+	db      *sql.DB
+	dialect Dialect
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu         sync.Mutex
+	lastPurged time.Time
+}
+
+// NewDedupStore creates a dedup store remembering keys for ttl (0 uses DefaultDedupTTL), call Migrate to create
+// its table
+func NewDedupStore(db *sql.DB, dialect Dialect, ttl time.Duration) (*DedupStore, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultDedupTTL
+	}
+	return &DedupStore{db: db, dialect: dialect, ttl: ttl, now: time.Now}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the store
+func (s *DedupStore) Migrate(ctx context.Context) error {
+	return migrate(ctx, s.db, s.dialect)
+}
+
+// MarkSeen records a key and reports whether it was new. The check and the record are one statement, so of the
+// replicas recording the same key at once only one sees it as new.
+func (s *DedupStore) MarkSeen(ctx context.Context, key string) (bool, error) {
+	now := s.now()
+	expired := now.Add(-s.ttl).UnixMicro()
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind(markSeenQuery), key, now.UnixMicro(), expired)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark %s as seen: %w", key, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark %s as seen: %w", key, err)
+	}
+
+	if s.purgeDue(now) {
+		if _, err := s.db.ExecContext(ctx, s.dialect.rebind(purgeDedupKeysQuery), expired); err != nil {
+			return false, fmt.Errorf("failed to purge expired dedup keys: %w", err)
+		}
+	}
+	return affected > 0, nil
+}
+
+// purgeDue checks if the expired keys were last purged longer ago than a tenth of the TTL, and records a purge
+func (s *DedupStore) purgeDue(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastPurged) < s.ttl/10 {
+		return false
+	}
+	s.lastPurged = now
+	return true
+}
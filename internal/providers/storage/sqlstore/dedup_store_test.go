@@ -0,0 +1,44 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupStore_MarkSeen(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		db := openTestDatabase(t, dialect)
+		store, err := NewDedupStore(db, dialect, time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, store.Migrate(ctx))
+		now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+		store.now = func() time.Time { return now }
+
+		for _, key := range []string{"C1:1", "C1:2"} {
+			isNew, err := store.MarkSeen(ctx, key)
+			require.NoError(t, err)
+			assert.True(t, isNew, key)
+		}
+		isNew, err := store.MarkSeen(ctx, "C1:1")
+		require.NoError(t, err)
+		assert.False(t, isNew)
+
+		// Keys are new again once their TTL passed, the expired ones are purged
+		now = now.Add(90 * time.Minute)
+		isNew, err = store.MarkSeen(ctx, "C1:1")
+		require.NoError(t, err)
+		assert.True(t, isNew)
+		isNew, err = store.MarkSeen(ctx, "C1:1")
+		require.NoError(t, err)
+		assert.False(t, isNew)
+		assert.Equal(t, 1, countRows(t, db, "dedup_keys"))
+	})
+
+	_, err := NewDedupStore(nil, SQLite, 0)
+	assert.ErrorIs(t, err, ErrNilDatabase)
+}
@@ -0,0 +1,62 @@
+package sqlstore
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNilDatabase    = errors.New("database cannot be nil")
+	ErrUnknownDialect = errors.New("unknown SQL dialect")
+	ErrNilCipher      = errors.New("field cipher cannot be nil")
+)
+
+// Dialect is the SQL database the stores run on. Queries are written with ? placeholders and rewritten for
+// the databases numbering theirs.
+type Dialect string
+
+const (
+	// Postgres numbers its placeholders $1, $2...
+	Postgres Dialect = "postgres"
+	// SQLite takes ? placeholders
+	SQLite Dialect = "sqlite"
+)
+
+// ParseDialect returns the dialect named in configuration
+func ParseDialect(name string) (Dialect, error) {
+	switch dialect := Dialect(strings.ToLower(strings.TrimSpace(name))); dialect {
+	case Postgres, SQLite:
+		return dialect, nil
+	default:
+		return "", ErrUnknownDialect
+	}
+}
+
+// rebind rewrites the ? placeholders of a query for the dialect
+func (d Dialect) rebind(query string) string {
+	if d != Postgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// forUpdate locks the rows a query selects until the end of its transaction. SQLite has no row locks, the
+// transactions of the stores take its write lock when they start instead.
+func (d Dialect) forUpdate(query string) string {
+	if d != Postgres {
+		return query
+	}
+	return query + " FOR UPDATE"
+}
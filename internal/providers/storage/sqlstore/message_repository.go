@@ -0,0 +1,250 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	upsertMessageQuery = `INSERT INTO messages (id, thread_id, state, posted_at, data, sender_key) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET thread_id = excluded.thread_id, state = excluded.state,
+posted_at = excluded.posted_at, data = excluded.data, sender_key = excluded.sender_key`
+	updateMessageQuery        = `UPDATE messages SET thread_id = ?, state = ?, posted_at = ?, data = ?, sender_key = ? WHERE id = ?`
+	selectMessageQuery        = `SELECT data FROM messages WHERE id = ?`
+	selectThreadMessagesQuery = `SELECT data FROM messages WHERE thread_id = ? ORDER BY posted_at, id`
+	selectMessagesQuery       = `SELECT data FROM messages ORDER BY posted_at, id`
+	selectStateMessagesQuery  = `SELECT data FROM messages WHERE state IN (%s) ORDER BY posted_at, id`
+	// selectSenderMessagesQuery also reads the messages stored before their sender key, their senders are compared
+	selectSenderMessagesQuery = `SELECT data FROM messages WHERE sender_key IN (?, '') ORDER BY posted_at, id`
+	deleteMessageQuery        = `DELETE FROM messages WHERE id = ?`
+	selectMessagePageQuery    = `SELECT id, data FROM messages WHERE id > ? ORDER BY id LIMIT 100`
+	// reencryptMessageQuery leaves out messages updated since they were read, they are encrypted with the current key
+	reencryptMessageQuery = `UPDATE messages SET data = ? WHERE id = ? AND data = ?`
+)
+
+// MessageRepository implements the ports.MessageRepository interface on a SQL database, so the messages and
+// their processing states survive restarts
+type MessageRepository struct {
+	db      *sql.DB
+	dialect Dialect
+	// cipher encrypts the content and sender of stored messages, nil stores them in plaintext
+	cipher domain.FieldCipher
+}
+
+// NewMessageRepository creates a message repository, call Migrate to create its table
+func NewMessageRepository(db *sql.DB, dialect Dialect) (*MessageRepository, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &MessageRepository{db: db, dialect: dialect}, nil
+}
+
+// NewEncryptedMessageRepository creates a message repository storing the content and sender of messages
+// encrypted with the cipher, call Migrate to create its table
+func NewEncryptedMessageRepository(db *sql.DB, dialect Dialect, cipher domain.FieldCipher) (*MessageRepository, error) {
+	if cipher == nil {
+		return nil, ErrNilCipher
+	}
+	repo, err := NewMessageRepository(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	repo.cipher = cipher
+	return repo, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the repository
+func (r *MessageRepository) Migrate(ctx context.Context) error {
+	return migrate(ctx, r.db, r.dialect)
+}
+
+// Save persists a message, replacing the earlier version of it
+func (r *MessageRepository) Save(ctx context.Context, message *domain.Message) error {
+	if message == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	data, err := r.encode(message.ToDTO())
+	if err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, r.dialect.rebind(upsertMessageQuery), message.ID().String(), message.ThreadID().String(),
+		message.State().String(), message.Timestamp().UnixMicro(), data, domain.Pseudonym(message.Sender())); err != nil {
+		return fmt.Errorf("failed to save message %s: %w", message.ID(), err)
+	}
+	return nil
+}
+
+// FindByID retrieves a message by ID
+func (r *MessageRepository) FindByID(ctx context.Context, id string) (*domain.Message, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx, r.dialect.rebind(selectMessageQuery), id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message %s: %w", id, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message %s: %w", id, err)
+	}
+	return r.restore(data)
+}
+
+// FindByThread retrieves messages in a thread ordered by timestamp
+func (r *MessageRepository) FindByThread(ctx context.Context, threadID string) ([]*domain.Message, error) {
+	return r.find(ctx, selectThreadMessagesQuery, threadID)
+}
+
+// Update persists changes to a stored message
+func (r *MessageRepository) Update(ctx context.Context, message *domain.Message) error {
+	if message == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	data, err := r.encode(message.ToDTO())
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind(updateMessageQuery), message.ThreadID().String(), message.State().String(),
+		message.Timestamp().UnixMicro(), data, domain.Pseudonym(message.Sender()), message.ID().String())
+	if err != nil {
+		return fmt.Errorf("failed to update message %s: %w", message.ID(), err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("message %s: %w", message.ID(), ports.ErrNotFound)
+	}
+	return nil
+}
+
+// FindByState retrieves messages in the given processing states ordered by timestamp, all of them when no
+// state is given
+func (r *MessageRepository) FindByState(ctx context.Context, states ...domain.MessageState) ([]*domain.Message, error) {
+	if len(states) == 0 {
+		return r.find(ctx, selectMessagesQuery)
+	}
+
+	placeholders := make([]string, len(states))
+	args := make([]interface{}, len(states))
+	for i, state := range states {
+		placeholders[i] = "?"
+		args[i] = state.String()
+	}
+	return r.find(ctx, fmt.Sprintf(selectStateMessagesQuery, strings.Join(placeholders, ", ")), args...)
+}
+
+// FindBySender retrieves the messages a person sent ordered by timestamp
+func (r *MessageRepository) FindBySender(ctx context.Context, sender string) ([]*domain.Message, error) {
+	candidates, err := r.find(ctx, selectSenderMessagesQuery, domain.Pseudonym(sender))
+	if err != nil {
+		return nil, err
+	}
+	var messages []*domain.Message
+	for _, msg := range candidates {
+		if msg.Sender() == sender {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+// Delete removes a message, deleting a missing message is not an error
+func (r *MessageRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, r.dialect.rebind(deleteMessageQuery), id); err != nil {
+		return fmt.Errorf("failed to delete message %s: %w", id, err)
+	}
+	return nil
+}
+
+// find restores the messages a query returns
+func (r *MessageRepository) find(ctx context.Context, query string, args ...interface{}) ([]*domain.Message, error) {
+	rows, err := r.db.QueryContext(ctx, r.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*domain.Message
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read message: %w", err)
+		}
+		msg, err := r.restore(data)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find messages: %w", err)
+	}
+	return messages, nil
+}
+
+// Reencrypt encrypts every stored message again with the cipher's current key, after a key rotation or when
+// encryption was turned on for messages stored in plaintext. It returns how many messages it encrypted.
+func (r *MessageRepository) Reencrypt(ctx context.Context) (int, error) {
+	if r.cipher == nil {
+		return 0, nil
+	}
+	return reencryptRows(ctx, r.db, r.dialect, selectMessagePageQuery, reencryptMessageQuery, func(data string) (string, error) {
+		dto, err := r.decode(data)
+		if err != nil {
+			return "", err
+		}
+		return r.encode(dto)
+	})
+}
+
+// encode encodes a message to store, with its fields encrypted when the repository has a cipher
+func (r *MessageRepository) encode(dto domain.MessageDTO) (string, error) {
+	if r.cipher != nil {
+		encrypted, err := dto.Encrypted(r.cipher)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt message %s: %w", dto.ID, err)
+		}
+		dto = encrypted
+	}
+	data, err := json.Marshal(dto)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode message %s: %w", dto.ID, err)
+	}
+	return string(data), nil
+}
+
+// decode decodes a stored message, and decrypts its fields when the repository has a cipher
+func (r *MessageRepository) decode(data string) (domain.MessageDTO, error) {
+	var dto domain.MessageDTO
+	if err := json.Unmarshal([]byte(data), &dto); err != nil {
+		return domain.MessageDTO{}, fmt.Errorf("failed to decode stored message: %w", err)
+	}
+	if r.cipher == nil {
+		return dto, nil
+	}
+	decrypted, err := dto.Decrypted(r.cipher)
+	if err != nil {
+		return domain.MessageDTO{}, fmt.Errorf("failed to decrypt message %s: %w", dto.ID, err)
+	}
+	return decrypted, nil
+}
+
+// restore decodes and rebuilds a stored message
+func (r *MessageRepository) restore(data string) (*domain.Message, error) {
+	dto, err := r.decode(data)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := domain.MessageFromDTO(dto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore message %s: %w", dto.ID, err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,116 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageRepository(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+		repo := store.Messages()
+		threadID := common.GenerateID()
+
+		first := newMessage(t, threadID, "Which database for billing?")
+		time.Sleep(time.Millisecond)
+		second := newMessage(t, threadID, "Postgres")
+		for _, msg := range []*domain.Message{second, first, newMessage(t, common.GenerateID(), "Release train")} {
+			require.NoError(t, repo.Save(ctx, msg))
+		}
+		assert.Error(t, repo.Save(ctx, nil))
+
+		found, err := repo.FindByID(ctx, first.ID().String())
+		require.NoError(t, err)
+		assert.Equal(t, first.ID(), found.ID())
+		assert.Equal(t, first.Content(), found.Content())
+		assert.Equal(t, domain.MessageTypeDecision, found.Type())
+		_, err = repo.FindByID(ctx, common.GenerateID().String())
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+
+		thread, err := repo.FindByThread(ctx, threadID.String())
+		require.NoError(t, err)
+		require.Len(t, thread, 2)
+		assert.Equal(t, first.ID(), thread[0].ID())
+		assert.Equal(t, second.ID(), thread[1].ID())
+
+		require.NoError(t, repo.Delete(ctx, first.ID().String()))
+		require.NoError(t, repo.Delete(ctx, first.ID().String()), "deleting a missing message is not an error")
+		_, err = repo.FindByID(ctx, first.ID().String())
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+	})
+}
+
+func TestMessageRepository_FindBySender(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+		repo := store.Messages()
+
+		sent := newMessage(t, common.GenerateID(), "We will use Postgres")
+		require.NoError(t, repo.Save(ctx, sent))
+		other, err := domain.NewMessage(common.GenerateID(), "bob", domain.MustNewMessageContent("Release train"), domain.MessageTypeStatus, domain.CategoryOperations, nil)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(ctx, other))
+		// Messages stored before their sender key are found too
+		legacy := newMessage(t, common.GenerateID(), "Postgres it is")
+		require.NoError(t, repo.Save(ctx, legacy))
+		_, err = store.db.ExecContext(ctx, store.dialect.rebind("UPDATE messages SET sender_key = '' WHERE id = ?"), legacy.ID().String())
+		require.NoError(t, err)
+
+		found, err := repo.FindBySender(ctx, sent.Sender())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{sent.ID().String(), legacy.ID().String()}, messageIDs(found))
+
+		// A pseudonymized message is no longer found by the sender
+		sent.Pseudonymize(domain.Pseudonym(sent.Sender()))
+		require.NoError(t, repo.Update(ctx, sent))
+		found, err = repo.FindBySender(ctx, legacy.Sender())
+		require.NoError(t, err)
+		assert.Equal(t, []string{legacy.ID().String()}, messageIDs(found))
+	})
+}
+
+func messageIDs(messages []*domain.Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID().String())
+	}
+	return ids
+}
+
+func TestMessageRepository_State(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+		repo := store.Messages()
+
+		msg := newMessage(t, common.GenerateID(), "We will use Postgres")
+		require.NoError(t, repo.Save(ctx, msg))
+
+		// State changes are only visible once the message is updated
+		require.NoError(t, msg.StartAnalysis())
+		pending, err := repo.FindByState(ctx, domain.MessageStatePending)
+		require.NoError(t, err)
+		assert.Len(t, pending, 1)
+
+		require.NoError(t, repo.Update(ctx, msg))
+		analyzing, err := repo.FindByState(ctx, domain.MessageStateAnalyzing, domain.MessageStateFailed)
+		require.NoError(t, err)
+		require.Len(t, analyzing, 1)
+		assert.Equal(t, domain.MessageStateAnalyzing, analyzing[0].State())
+
+		all, err := repo.FindByState(ctx)
+		require.NoError(t, err)
+		assert.Len(t, all, 1)
+
+		assert.ErrorIs(t, repo.Update(ctx, newMessage(t, common.GenerateID(), "unsaved")), ports.ErrNotFound)
+	})
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS thread_messages;
+DROP INDEX IF EXISTS threads_by_channel;
+DROP TABLE IF EXISTS threads;
@@ -0,0 +1,17 @@
+-- Threads and the IDs of their messages. Times are stored as Unix microseconds, which every dialect compares and
+-- sorts the same way. IF NOT EXISTS adopts the tables created before the schema was versioned.
+CREATE TABLE IF NOT EXISTS threads (
+	id TEXT PRIMARY KEY,
+	channel_id TEXT NOT NULL,
+	external_id TEXT NOT NULL DEFAULT '',
+	title TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS threads_by_channel ON threads (channel_id, created_at);
+CREATE TABLE IF NOT EXISTS thread_messages (
+	thread_id TEXT NOT NULL,
+	message_id TEXT NOT NULL,
+	position INTEGER NOT NULL,
+	PRIMARY KEY (thread_id, message_id)
+);
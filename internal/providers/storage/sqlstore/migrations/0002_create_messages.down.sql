@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS messages_by_state;
+DROP INDEX IF EXISTS messages_by_thread;
+DROP TABLE IF EXISTS messages;
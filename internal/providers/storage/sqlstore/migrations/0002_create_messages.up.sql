@@ -0,0 +1,10 @@
+-- Messages are stored as their JSON DTO, with the columns they are looked up by next to it
+CREATE TABLE IF NOT EXISTS messages (
+	id TEXT PRIMARY KEY,
+	thread_id TEXT NOT NULL,
+	state TEXT NOT NULL,
+	posted_at BIGINT NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS messages_by_thread ON messages (thread_id, posted_at);
+CREATE INDEX IF NOT EXISTS messages_by_state ON messages (state, posted_at);
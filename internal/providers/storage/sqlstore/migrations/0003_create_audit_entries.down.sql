@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS audit_entries_by_actor;
+DROP TABLE IF EXISTS audit_entries;
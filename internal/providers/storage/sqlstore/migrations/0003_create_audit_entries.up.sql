@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS audit_entries (
+	id TEXT PRIMARY KEY,
+	action TEXT NOT NULL,
+	actor TEXT NOT NULL,
+	subject TEXT NOT NULL,
+	from_value TEXT NOT NULL,
+	to_value TEXT NOT NULL,
+	at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_entries_by_actor ON audit_entries (actor);
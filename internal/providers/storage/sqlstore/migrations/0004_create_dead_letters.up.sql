@@ -0,0 +1,7 @@
+-- Failed messages are stored as the JSON of the queued message, with the chat provider's route to it
+CREATE TABLE IF NOT EXISTS dead_letters (
+	message_id TEXT PRIMARY KEY,
+	error TEXT NOT NULL,
+	failed_at BIGINT NOT NULL,
+	queued TEXT NOT NULL
+);
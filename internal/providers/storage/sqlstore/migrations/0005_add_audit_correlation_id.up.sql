@@ -0,0 +1,2 @@
+-- The correlation ID of the message or API request that led to an entry, empty for entries made before it
+ALTER TABLE audit_entries ADD COLUMN correlation_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Which of our threads each chat thread is, named by its channel and the chat's own thread ID
+CREATE TABLE IF NOT EXISTS thread_mappings (
+	channel_id TEXT NOT NULL,
+	external_id TEXT NOT NULL,
+	thread_id TEXT NOT NULL,
+	PRIMARY KEY (channel_id, external_id)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS dedup_keys_by_seen_at;
+DROP TABLE IF EXISTS dedup_keys;
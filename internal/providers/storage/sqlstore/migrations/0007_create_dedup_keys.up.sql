@@ -0,0 +1,6 @@
+-- The keys of the chat events already handled, with when they were last seen so expired ones can be purged
+CREATE TABLE IF NOT EXISTS dedup_keys (
+	dedup_key TEXT PRIMARY KEY,
+	seen_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS dedup_keys_by_seen_at ON dedup_keys (seen_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS reply_outbox_by_due_at;
+DROP TABLE IF EXISTS reply_outbox;
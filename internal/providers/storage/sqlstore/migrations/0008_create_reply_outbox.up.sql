@@ -0,0 +1,12 @@
+-- The capture confirmations waiting to be posted, with the replies of a batch as a JSON array of strings and the
+-- chat provider's route to its first message as a JSON object
+CREATE TABLE IF NOT EXISTS reply_outbox (
+	batch_key TEXT PRIMARY KEY,
+	message_id TEXT NOT NULL,
+	channel_id TEXT NOT NULL,
+	mode TEXT NOT NULL,
+	replies TEXT NOT NULL,
+	route TEXT NOT NULL,
+	due_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS reply_outbox_by_due_at ON reply_outbox (due_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pending_duplicates_by_expires_at;
+DROP TABLE IF EXISTS pending_duplicates;
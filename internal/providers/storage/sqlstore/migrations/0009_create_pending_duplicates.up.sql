@@ -0,0 +1,10 @@
+-- The ideas waiting in their thread for their author to merge them into a similar document or document them
+-- separately, with the paths of the similar documents as a JSON array of strings
+CREATE TABLE IF NOT EXISTS pending_duplicates (
+	thread_id TEXT PRIMARY KEY,
+	message_id TEXT NOT NULL,
+	author TEXT NOT NULL,
+	candidates TEXT NOT NULL,
+	expires_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS pending_duplicates_by_expires_at ON pending_duplicates (expires_at);
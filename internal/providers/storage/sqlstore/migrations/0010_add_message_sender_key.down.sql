@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS messages_by_sender;
+ALTER TABLE messages DROP COLUMN sender_key;
@@ -0,0 +1,4 @@
+-- The pseudonym of the sender, so the messages of a person are found without decrypting them. Messages stored
+-- before it have an empty key and are checked one by one.
+ALTER TABLE messages ADD COLUMN sender_key TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS messages_by_sender ON messages (sender_key, posted_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pending_updates_by_expires_at;
+DROP TABLE IF EXISTS pending_updates;
@@ -0,0 +1,13 @@
+-- The merges of ideas waiting in their thread for their author to apply or discard the diff posted there, with the
+-- merged content of the document and the files attached to it as a JSON object of base64 contents by path
+CREATE TABLE IF NOT EXISTS pending_updates (
+	thread_id TEXT PRIMARY KEY,
+	message_id TEXT NOT NULL,
+	author TEXT NOT NULL,
+	path TEXT NOT NULL,
+	content TEXT NOT NULL,
+	base_revision TEXT NOT NULL,
+	attached TEXT NOT NULL,
+	expires_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS pending_updates_by_expires_at ON pending_updates (expires_at);
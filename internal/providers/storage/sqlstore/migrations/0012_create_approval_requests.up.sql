@@ -0,0 +1,8 @@
+-- The decisions waiting in their thread for the approvals their project's policies require, with the decision,
+-- the policies, the roles and the approvals given so far as a JSON object
+CREATE TABLE IF NOT EXISTS approval_requests (
+	thread_id TEXT PRIMARY KEY,
+	message_id TEXT NOT NULL,
+	requested_at BIGINT NOT NULL,
+	request TEXT NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS reply_details_by_kept_at;
+DROP TABLE IF EXISTS reply_details;
@@ -0,0 +1,7 @@
+-- The confirmations of the messages acknowledged with a reaction, shown when someone asks for the details
+CREATE TABLE IF NOT EXISTS reply_details (
+	message_id TEXT PRIMARY KEY,
+	reply TEXT NOT NULL,
+	kept_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS reply_details_by_kept_at ON reply_details (kept_at);
@@ -0,0 +1,255 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	golangmigrate "github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+var ErrInvalidMigrationSteps = errors.New("migration steps must be positive")
+
+// migrationFiles are the versioned changes of the schema of every store, named <version>_<name>.up.sql and
+// <version>_<name>.down.sql as golang-migrate expects them
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrator applies and rolls back the versioned migrations of the schema of the stores with golang-migrate,
+// which records the version of the schema in a schema_migrations table. On SQLite each migration runs in a
+// transaction, on Postgres under an advisory lock, so replicas starting together apply it once. A migration
+// failing halfway leaves the schema dirty, and nothing is migrated until it is repaired by hand.
+type Migrator struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewMigrator creates a migrator of the schema the stores of the package use
+func NewMigrator(db *sql.DB, dialect Dialect) (*Migrator, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect}, nil
+}
+
+// Status lists every migration, oldest first, with whether it was applied
+func (m *Migrator) Status(ctx context.Context) ([]domain.SchemaMigration, error) {
+	var status []domain.SchemaMigration
+	err := m.run(ctx, func(runner *golangmigrate.Migrate, migrations []domain.SchemaMigration) error {
+		version, dirty, err := schemaVersion(runner)
+		if err != nil {
+			return err
+		}
+		status = markApplied(migrations, version, dirty)
+		return nil
+	})
+	return status, err
+}
+
+// Up applies the pending migrations in order, and returns the ones it applied
+func (m *Migrator) Up(ctx context.Context) ([]domain.SchemaMigration, error) {
+	var applied []domain.SchemaMigration
+	err := m.run(ctx, func(runner *golangmigrate.Migrate, migrations []domain.SchemaMigration) error {
+		from, _, err := schemaVersion(runner)
+		if err != nil {
+			return err
+		}
+		upErr := runner.Up()
+		if errors.Is(upErr, golangmigrate.ErrNoChange) {
+			upErr = nil
+		}
+		to, dirty, err := schemaVersion(runner)
+		if err != nil {
+			return err
+		}
+		applied = between(migrations, from, to)
+		if dirty && len(applied) > 0 {
+			// The migrations before the one failing stay applied
+			applied = applied[:len(applied)-1]
+		}
+		if upErr != nil {
+			return fmt.Errorf("failed to apply migrations: %w", upErr)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the steps last applied migrations, newest first, and returns the ones it rolled back. Rolling
+// back more steps than applied rolls back everything.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]domain.SchemaMigration, error) {
+	if steps <= 0 {
+		return nil, ErrInvalidMigrationSteps
+	}
+
+	var rolledBack []domain.SchemaMigration
+	err := m.run(ctx, func(runner *golangmigrate.Migrate, migrations []domain.SchemaMigration) error {
+		from, _, err := schemaVersion(runner)
+		if err != nil {
+			return err
+		}
+		if applied := len(between(migrations, 0, from)); steps > applied {
+			steps = applied
+		}
+		if steps == 0 {
+			return nil
+		}
+		downErr := runner.Steps(-steps)
+		to, dirty, err := schemaVersion(runner)
+		if err != nil {
+			return err
+		}
+		done := between(migrations, to, from)
+		if dirty && len(done) > 0 {
+			// The schema is dirty at the version before the migration failing to roll back
+			done = done[1:]
+		}
+		for i := len(done) - 1; i >= 0; i-- {
+			rolledBack = append(rolledBack, done[i])
+		}
+		if downErr != nil {
+			return fmt.Errorf("failed to roll back migrations: %w", downErr)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// run runs golang-migrate on the database of the migrator, with the migrations of the embedded files. A
+// canceled ctx stops it after the migration in progress.
+func (m *Migrator) run(ctx context.Context, fn func(*golangmigrate.Migrate, []domain.SchemaMigration) error) error {
+	files, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+	defer files.Close()
+	migrations, err := listMigrations(files)
+	if err != nil {
+		return err
+	}
+
+	// The drivers of golang-migrate close the database they are given when they are done, so Postgres migrates
+	// on a connection borrowed from the pool and the SQLite driver is never closed
+	var driver database.Driver
+	if m.dialect == Postgres {
+		conn, err := m.db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the database: %w", err)
+		}
+		defer conn.Close()
+		driver, err = postgres.WithConnection(ctx, conn, &postgres.Config{})
+		if err != nil {
+			return fmt.Errorf("failed to open migrations: %w", err)
+		}
+	} else {
+		driver, err = sqlite.WithInstance(m.db, &sqlite.Config{})
+		if err != nil {
+			return fmt.Errorf("failed to open migrations: %w", err)
+		}
+	}
+	runner, err := golangmigrate.NewWithInstance("iofs", files, string(m.dialect), driver)
+	if err != nil {
+		return fmt.Errorf("failed to open migrations: %w", err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			runner.GracefulStop <- true
+		case <-stop:
+		}
+	}()
+	if err := fn(runner, migrations); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("migrations stopped: %w", err)
+	}
+	return nil
+}
+
+// listMigrations lists the migrations of the files, oldest first
+func listMigrations(files source.Driver) ([]domain.SchemaMigration, error) {
+	version, err := files.First()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	var migrations []domain.SchemaMigration
+	for {
+		r, name, err := files.ReadUp(version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %d: %w", version, err)
+		}
+		_ = r.Close()
+		migrations = append(migrations, domain.SchemaMigration{Version: int(version), Name: name})
+
+		version, err = files.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return migrations, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list migrations: %w", err)
+		}
+	}
+}
+
+// schemaVersion returns the version of the last applied migration, 0 when none is, and whether it failed halfway
+func schemaVersion(runner *golangmigrate.Migrate) (int, bool, error) {
+	version, dirty, err := runner.Version()
+	if errors.Is(err, golangmigrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read the schema version: %w", err)
+	}
+	return int(version), dirty, nil
+}
+
+// markApplied marks the migrations up to version as applied, and the one at version as dirty when it is
+func markApplied(migrations []domain.SchemaMigration, version int, dirty bool) []domain.SchemaMigration {
+	status := make([]domain.SchemaMigration, len(migrations))
+	for i, migration := range migrations {
+		migration.Applied = migration.Version < version || (migration.Version == version && !dirty)
+		migration.Dirty = dirty && migration.Version == version
+		status[i] = migration
+	}
+	return status
+}
+
+// between returns the migrations after version from up to version to, marked as applied
+func between(migrations []domain.SchemaMigration, from, to int) []domain.SchemaMigration {
+	var selected []domain.SchemaMigration
+	for _, migration := range migrations {
+		if migration.Version > from && migration.Version <= to {
+			migration.Applied = true
+			selected = append(selected, migration)
+		}
+	}
+	return selected
+}
+
+// migrate applies the pending migrations, for the Migrate methods of the stores
+func migrate(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	migrator, err := NewMigrator(db, dialect)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(ctx)
+	return err
+}
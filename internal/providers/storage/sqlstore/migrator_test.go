@@ -0,0 +1,104 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func migrationNames(migrations []domain.SchemaMigration) []string {
+	names := make([]string, len(migrations))
+	for i, migration := range migrations {
+		names[i] = migration.Name
+	}
+	return names
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		db := openTestDatabase(t, dialect)
+		migrator, err := NewMigrator(db, dialect)
+		require.NoError(t, err)
+
+		status, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, status, 13)
+		for i, migration := range status {
+			assert.Equal(t, i+1, migration.Version)
+			assert.False(t, migration.Applied)
+		}
+
+		applied, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create_threads", "create_messages", "create_audit_entries", "create_dead_letters",
+			"add_audit_correlation_id", "create_thread_mappings", "create_dedup_keys", "create_reply_outbox",
+			"create_pending_duplicates", "add_message_sender_key", "create_pending_updates", "create_approval_requests",
+			"create_reply_details"}, migrationNames(applied))
+		applied, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, applied, "applied migrations are not applied again")
+
+		rolledBack, err := migrator.Down(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create_reply_details", "create_approval_requests", "create_pending_updates", "add_message_sender_key",
+			"create_pending_duplicates", "create_reply_outbox", "create_dedup_keys"}, migrationNames(rolledBack))
+		status, err = migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.True(t, status[5].Applied)
+		assert.False(t, status[6].Applied)
+
+		// Rolling back more steps than applied rolls back everything, newest first
+		rolledBack, err = migrator.Down(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create_thread_mappings", "add_audit_correlation_id", "create_dead_letters", "create_audit_entries",
+			"create_messages", "create_threads"}, migrationNames(rolledBack))
+		status, err = migrator.Status(ctx)
+		require.NoError(t, err)
+		for _, migration := range status {
+			assert.False(t, migration.Applied, migration.Name)
+		}
+
+		// The rolled back schema applies again
+		applied, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Len(t, applied, 13)
+
+		_, err = migrator.Down(ctx, 0)
+		assert.ErrorIs(t, err, ErrInvalidMigrationSteps)
+	})
+	_, err := NewMigrator(nil, SQLite)
+	assert.ErrorIs(t, err, ErrNilDatabase)
+}
+
+func TestMigrator_Dirty(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		db := openTestDatabase(t, dialect)
+		migrator, err := NewMigrator(db, dialect)
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+
+		// A migration failing halfway leaves the schema dirty at its version
+		_, err = db.ExecContext(ctx, "UPDATE schema_migrations SET dirty = true")
+		require.NoError(t, err)
+
+		status, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		last := status[len(status)-1]
+		assert.True(t, last.Dirty)
+		assert.False(t, last.Applied)
+		assert.True(t, status[len(status)-2].Applied)
+
+		applied, err := migrator.Up(ctx)
+		assert.Error(t, err, "a dirty schema is not migrated")
+		assert.Empty(t, applied)
+		rolledBack, err := migrator.Down(ctx, 1)
+		assert.Error(t, err)
+		assert.Empty(t, rolledBack)
+	})
+}
@@ -0,0 +1,57 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	// The drivers of the dialects are bundled, so a deployment only needs a DSN
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteDefaults are the settings of SQLite connections unless the DSN sets its own: wait up to 5 seconds for
+// the locks of other connections rather than failing, and take the write lock when a transaction starts, so
+// transactions reading before they write do not fail on a busy database.
+var sqliteDefaults = []struct{ setting, value string }{
+	{setting: "busy_timeout", value: "_pragma=busy_timeout(5000)"},
+	{setting: "_txlock", value: "_txlock=immediate"},
+}
+
+// driverName returns the name of the bundled driver of the dialect
+func (d Dialect) driverName() string {
+	if d == Postgres {
+		return "pgx"
+	}
+	return "sqlite"
+}
+
+// Open opens a database of the dialect with its bundled driver: pgx for Postgres, with a DSN like
+// postgres://quill:secret@db:5432/quill, and modernc.org/sqlite for SQLite, which needs no C compiler, with the
+// path of the database file as DSN.
+func Open(dialect Dialect, dsn string) (*sql.DB, error) {
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(dsn) == "" {
+		return nil, fmt.Errorf("missing %s DSN", dialect)
+	}
+
+	if dialect == SQLite {
+		for _, d := range sqliteDefaults {
+			if strings.Contains(dsn, d.setting) {
+				continue
+			}
+			if strings.Contains(dsn, "?") {
+				dsn += "&" + d.value
+			} else {
+				dsn += "?" + d.value
+			}
+		}
+	}
+	db, err := sql.Open(dialect.driverName(), dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", dialect, err)
+	}
+	return db, nil
+}
@@ -0,0 +1,112 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	upsertPendingDuplicateQuery = `INSERT INTO pending_duplicates (thread_id, message_id, author, candidates, expires_at)
+VALUES (?, ?, ?, ?, ?) ON CONFLICT (thread_id) DO UPDATE SET message_id = excluded.message_id, author = excluded.author,
+candidates = excluded.candidates, expires_at = excluded.expires_at`
+	selectPendingDuplicateQuery = `SELECT message_id, author, candidates, expires_at FROM pending_duplicates
+WHERE thread_id = ? AND expires_at > ?`
+	deletePendingDuplicateQuery        = `DELETE FROM pending_duplicates WHERE thread_id = ?`
+	deleteExpiredPendingDuplicateQuery = `DELETE FROM pending_duplicates WHERE expires_at <= ?`
+)
+
+// PendingDuplicateStore implements the ports.PendingDuplicateStore interface on a SQL database, so the ideas
+// waiting for their author's choice can still be merged after a restart
+type PendingDuplicateStore struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewPendingDuplicateStore creates a store of pending duplicate choices, call Migrate to create its table
+func NewPendingDuplicateStore(db *sql.DB, dialect Dialect) (*PendingDuplicateStore, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &PendingDuplicateStore{db: db, dialect: dialect}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the store
+func (s *PendingDuplicateStore) Migrate(ctx context.Context) error {
+	return migrate(ctx, s.db, s.dialect)
+}
+
+// Put keeps the choice of a thread, replacing the earlier one
+func (s *PendingDuplicateStore) Put(ctx context.Context, pending *domain.PendingDuplicate) error {
+	if pending == nil {
+		return fmt.Errorf("pending duplicate cannot be nil")
+	}
+	candidates, err := json.Marshal(pending.Candidates())
+	if err != nil {
+		return fmt.Errorf("failed to encode pending duplicate of thread %s: %w", pending.ThreadID(), err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, s.dialect.rebind(upsertPendingDuplicateQuery), pending.ThreadID(), pending.MessageID(),
+		pending.Author(), string(candidates), pending.ExpiresAt().UnixMicro()); err != nil {
+		return fmt.Errorf("failed to save pending duplicate of thread %s: %w", pending.ThreadID(), err)
+	}
+	return nil
+}
+
+// Find returns the choice waited for in a thread at the time
+func (s *PendingDuplicateStore) Find(ctx context.Context, threadID string, at time.Time) (*domain.PendingDuplicate, error) {
+	var messageID, author, candidates string
+	var expiresAt int64
+	err := s.db.QueryRowContext(ctx, s.dialect.rebind(selectPendingDuplicateQuery), threadID, at.UnixMicro()).
+		Scan(&messageID, &author, &candidates, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("pending duplicate of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending duplicate of thread %s: %w", threadID, err)
+	}
+
+	var paths []string
+	if err := json.Unmarshal([]byte(candidates), &paths); err != nil {
+		return nil, fmt.Errorf("failed to decode pending duplicate of thread %s: %w", threadID, err)
+	}
+	pending, err := domain.NewPendingDuplicate(threadID, messageID, author, paths, time.UnixMicro(expiresAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore pending duplicate of thread %s: %w", threadID, err)
+	}
+	return pending, nil
+}
+
+// Remove takes the choice of a thread out of the store
+func (s *PendingDuplicateStore) Remove(ctx context.Context, threadID string) error {
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind(deletePendingDuplicateQuery), threadID)
+	if err != nil {
+		return fmt.Errorf("failed to remove pending duplicate of thread %s: %w", threadID, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("pending duplicate of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// Purge removes the choices expired at the time
+func (s *PendingDuplicateStore) Purge(ctx context.Context, at time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind(deleteExpiredPendingDuplicateQuery), at.UnixMicro())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge pending duplicates: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge pending duplicates: %w", err)
+	}
+	return int(purged), nil
+}
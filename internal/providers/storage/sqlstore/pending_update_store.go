@@ -0,0 +1,145 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	upsertPendingUpdateQuery = `INSERT INTO pending_updates (thread_id, message_id, author, path, content, base_revision, attached, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (thread_id) DO UPDATE SET message_id = excluded.message_id, author = excluded.author,
+path = excluded.path, content = excluded.content, base_revision = excluded.base_revision, attached = excluded.attached,
+expires_at = excluded.expires_at`
+	selectPendingUpdateQuery = `SELECT thread_id, message_id, author, path, content, base_revision, attached, expires_at
+FROM pending_updates`
+	findPendingUpdateQuery          = selectPendingUpdateQuery + ` WHERE thread_id = ? AND expires_at > ?`
+	listPendingUpdatesQuery         = selectPendingUpdateQuery + ` WHERE expires_at > ? ORDER BY expires_at, thread_id`
+	deletePendingUpdateQuery        = `DELETE FROM pending_updates WHERE thread_id = ?`
+	deleteExpiredPendingUpdateQuery = `DELETE FROM pending_updates WHERE expires_at <= ?`
+)
+
+// PendingUpdateStore implements the ports.PendingUpdateStore interface on a SQL database, so the merges waiting
+// for their author to apply their diff can still be applied after a restart, and on any replica
+type PendingUpdateStore struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewPendingUpdateStore creates a store of pending updates, call Migrate to create its table
+func NewPendingUpdateStore(db *sql.DB, dialect Dialect) (*PendingUpdateStore, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &PendingUpdateStore{db: db, dialect: dialect}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the store
+func (s *PendingUpdateStore) Migrate(ctx context.Context) error {
+	return migrate(ctx, s.db, s.dialect)
+}
+
+// Put keeps the update waiting in a thread, replacing the earlier one
+func (s *PendingUpdateStore) Put(ctx context.Context, pending *domain.PendingUpdate) error {
+	if pending == nil {
+		return fmt.Errorf("pending update cannot be nil")
+	}
+	attached, err := json.Marshal(pending.Attached())
+	if err != nil {
+		return fmt.Errorf("failed to encode pending update of thread %s: %w", pending.ThreadID(), err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, s.dialect.rebind(upsertPendingUpdateQuery), pending.ThreadID(), pending.MessageID(),
+		pending.Author(), pending.Path(), pending.Content(), pending.BaseRevision(), string(attached),
+		pending.ExpiresAt().UnixMicro()); err != nil {
+		return fmt.Errorf("failed to save pending update of thread %s: %w", pending.ThreadID(), err)
+	}
+	return nil
+}
+
+// Find returns the update waiting in a thread at the time
+func (s *PendingUpdateStore) Find(ctx context.Context, threadID string, at time.Time) (*domain.PendingUpdate, error) {
+	pending, err := scanPendingUpdate(s.db.QueryRowContext(ctx, s.dialect.rebind(findPendingUpdateQuery), threadID, at.UnixMicro()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("pending update of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending update of thread %s: %w", threadID, err)
+	}
+	return pending, nil
+}
+
+// List returns the updates waiting at the time, oldest to expire first
+func (s *PendingUpdateStore) List(ctx context.Context, at time.Time) ([]*domain.PendingUpdate, error) {
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(listPendingUpdatesQuery), at.UnixMicro())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending updates: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var waiting []*domain.PendingUpdate
+	for rows.Next() {
+		pending, err := scanPendingUpdate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pending updates: %w", err)
+		}
+		waiting = append(waiting, pending)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list pending updates: %w", err)
+	}
+	return waiting, nil
+}
+
+// Remove takes the update of a thread out of the store
+func (s *PendingUpdateStore) Remove(ctx context.Context, threadID string) error {
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind(deletePendingUpdateQuery), threadID)
+	if err != nil {
+		return fmt.Errorf("failed to remove pending update of thread %s: %w", threadID, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("pending update of thread %s: %w", threadID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// Purge removes the updates expired at the time
+func (s *PendingUpdateStore) Purge(ctx context.Context, at time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind(deleteExpiredPendingUpdateQuery), at.UnixMicro())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge pending updates: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge pending updates: %w", err)
+	}
+	return int(purged), nil
+}
+
+// scanPendingUpdate reads a pending update from a row of selectPendingUpdateQuery
+func scanPendingUpdate(row interface{ Scan(...interface{}) error }) (*domain.PendingUpdate, error) {
+	var threadID, messageID, author, path, content, baseRevision, attached string
+	var expiresAt int64
+	if err := row.Scan(&threadID, &messageID, &author, &path, &content, &baseRevision, &attached, &expiresAt); err != nil {
+		return nil, err
+	}
+
+	var files map[string][]byte
+	if err := json.Unmarshal([]byte(attached), &files); err != nil {
+		return nil, fmt.Errorf("failed to decode pending update of thread %s: %w", threadID, err)
+	}
+	pending, err := domain.NewPendingUpdate(threadID, messageID, author, path, content, baseRevision, files, time.UnixMicro(expiresAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore pending update of thread %s: %w", threadID, err)
+	}
+	return pending, nil
+}
@@ -0,0 +1,65 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingUpdateStore(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect).PendingUpdates()
+		now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+
+		first, err := domain.NewPendingUpdate("T1", "M1", "alice", "docs/a.md", "# A", "", nil, now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, first))
+		assert.Error(t, store.Put(ctx, nil))
+
+		// Merging again in the thread replaces the update
+		attached := map[string][]byte{"docs/assets/b.png": []byte("png")}
+		again, err := domain.NewPendingUpdate("T1", "M2", "alice", "docs/b.md", "# B\n\n## Addendum", "abc123", attached, now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, again))
+		sooner, err := domain.NewPendingUpdate("T3", "M4", "carol", "docs/c.md", "# C", "", nil, now.Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, sooner))
+		expired, err := domain.NewPendingUpdate("T2", "M3", "bob", "docs/a.md", "# A", "", nil, now)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, expired))
+
+		found, err := store.Find(ctx, "T1", now)
+		require.NoError(t, err)
+		assert.Equal(t, "M2", found.MessageID())
+		assert.Equal(t, "alice", found.Author())
+		assert.Equal(t, "docs/b.md", found.Path())
+		assert.Equal(t, "# B\n\n## Addendum", found.Content())
+		assert.Equal(t, "abc123", found.BaseRevision())
+		assert.Equal(t, attached, found.Attached())
+		assert.Equal(t, now.Add(2*time.Hour), found.ExpiresAt())
+		_, err = store.Find(ctx, "T2", now)
+		assert.ErrorIs(t, err, ports.ErrNotFound, "expired updates are not found")
+		waiting, err := store.List(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, waiting, 2)
+		assert.Equal(t, "T3", waiting[0].ThreadID())
+		assert.Equal(t, "T1", waiting[1].ThreadID())
+
+		purged, err := store.Purge(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.ErrorIs(t, store.Remove(ctx, "T2"), ports.ErrNotFound)
+
+		require.NoError(t, store.Remove(ctx, "T1"))
+		assert.ErrorIs(t, store.Remove(ctx, "T1"), ports.ErrNotFound, "an update is removed once")
+	})
+
+	_, err := NewPendingUpdateStore(nil, SQLite)
+	assert.ErrorIs(t, err, ErrNilDatabase)
+}
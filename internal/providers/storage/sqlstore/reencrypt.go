@@ -0,0 +1,64 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// encryptedRow is the key of a row and the value of its encrypted column
+type encryptedRow struct {
+	key   string
+	value string
+}
+
+// reencryptRows encrypts a column of every row of a table again, a page at a time. pageQuery selects the key and
+// the column of the rows after a key, in key order, and updateQuery sets the column of the row with a key while it
+// holds the value read; rows changed in between were written with the current key already. It returns how many
+// rows it encrypted.
+func reencryptRows(ctx context.Context, db *sql.DB, dialect Dialect, pageQuery, updateQuery string, reencrypt func(string) (string, error)) (int, error) {
+	count := 0
+	after := ""
+	for {
+		page, err := readEncryptedRows(ctx, db, dialect.rebind(pageQuery), after)
+		if err != nil || len(page) == 0 {
+			return count, err
+		}
+		for _, row := range page {
+			value, err := reencrypt(row.value)
+			if err != nil {
+				return count, err
+			}
+			result, err := db.ExecContext(ctx, dialect.rebind(updateQuery), value, row.key, row.value)
+			if err != nil {
+				return count, fmt.Errorf("failed to store %s encrypted again: %w", row.key, err)
+			}
+			if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+				count++
+			}
+			after = row.key
+		}
+	}
+}
+
+// readEncryptedRows reads a page of the rows to encrypt again
+func readEncryptedRows(ctx context.Context, db *sql.DB, query, after string) ([]encryptedRow, error) {
+	rows, err := db.QueryContext(ctx, query, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rows to encrypt again: %w", err)
+	}
+	defer rows.Close()
+
+	var page []encryptedRow
+	for rows.Next() {
+		var row encryptedRow
+		if err := rows.Scan(&row.key, &row.value); err != nil {
+			return nil, fmt.Errorf("failed to read row to encrypt again: %w", err)
+		}
+		page = append(page, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows to encrypt again: %w", err)
+	}
+	return page, nil
+}
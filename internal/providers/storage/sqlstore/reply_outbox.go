@@ -0,0 +1,215 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	insertReplyBatchQuery = `INSERT INTO reply_outbox (batch_key, message_id, channel_id, mode, replies, route, due_at)
+VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT DO NOTHING`
+	selectReplyBatchRepliesQuery = `SELECT replies FROM reply_outbox WHERE batch_key = ?`
+	updateReplyBatchRepliesQuery = `UPDATE reply_outbox SET replies = ? WHERE batch_key = ?`
+	selectDueReplyBatchesQuery   = `SELECT batch_key, message_id, channel_id, mode, replies, route, due_at FROM reply_outbox
+WHERE due_at <= ? ORDER BY due_at, batch_key`
+	postponeReplyBatchQuery = `UPDATE reply_outbox SET due_at = ? WHERE batch_key = ?`
+	deleteReplyBatchQuery   = `DELETE FROM reply_outbox WHERE batch_key = ?`
+	upsertReplyDetailsQuery = `INSERT INTO reply_details (message_id, reply, kept_at) VALUES (?, ?, ?)
+ON CONFLICT (message_id) DO UPDATE SET reply = excluded.reply, kept_at = excluded.kept_at`
+	selectReplyDetailsQuery = `SELECT reply FROM reply_details WHERE message_id = ?`
+	deleteReplyDetailsQuery = `DELETE FROM reply_details WHERE kept_at < ?`
+)
+
+// ReplyOutbox implements the ports.ReplyOutbox interface on a SQL database, so the confirmations waiting for
+// their batch window or the end of quiet hours are posted after a restart, and the confirmations of messages
+// acknowledged with a reaction are shown by any replica
+type ReplyOutbox struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewReplyOutbox creates a reply outbox, call Migrate to create its table
+func NewReplyOutbox(db *sql.DB, dialect Dialect) (*ReplyOutbox, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &ReplyOutbox{db: db, dialect: dialect}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the outbox
+func (o *ReplyOutbox) Migrate(ctx context.Context) error {
+	return migrate(ctx, o.db, o.dialect)
+}
+
+// Add keeps a batch, or appends its replies to the pending batch with the same key
+func (o *ReplyOutbox) Add(ctx context.Context, batch *domain.ReplyBatch) (bool, error) {
+	if batch == nil {
+		return false, fmt.Errorf("reply batch cannot be nil")
+	}
+	replies, err := json.Marshal(batch.Replies())
+	if err != nil {
+		return false, fmt.Errorf("failed to encode reply batch %s: %w", batch.Key(), err)
+	}
+	route, err := json.Marshal(batch.Route())
+	if err != nil {
+		return false, fmt.Errorf("failed to encode reply batch %s: %w", batch.Key(), err)
+	}
+
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to add reply batch %s: %w", batch.Key(), err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, o.dialect.rebind(insertReplyBatchQuery), batch.Key(), batch.MessageID(), batch.ChannelID(),
+		batch.Mode().String(), string(replies), string(route), batch.DueAt().UnixMicro())
+	if err != nil {
+		return false, fmt.Errorf("failed to add reply batch %s: %w", batch.Key(), err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add reply batch %s: %w", batch.Key(), err)
+	}
+	if affected == 0 {
+		// The thread has a pending batch, the replies join it under its lock
+		if err := o.appendReplies(ctx, tx, batch); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to add reply batch %s: %w", batch.Key(), err)
+	}
+	return affected > 0, nil
+}
+
+// appendReplies adds the replies of batch to the pending batch with its key, within the transaction adding it
+func (o *ReplyOutbox) appendReplies(ctx context.Context, tx *sql.Tx, batch *domain.ReplyBatch) error {
+	var stored string
+	if err := tx.QueryRowContext(ctx, o.dialect.rebind(o.dialect.forUpdate(selectReplyBatchRepliesQuery)), batch.Key()).Scan(&stored); err != nil {
+		return fmt.Errorf("failed to read reply batch %s: %w", batch.Key(), err)
+	}
+	var replies []string
+	if err := json.Unmarshal([]byte(stored), &replies); err != nil {
+		return fmt.Errorf("failed to decode reply batch %s: %w", batch.Key(), err)
+	}
+	data, err := json.Marshal(append(replies, batch.Replies()...))
+	if err != nil {
+		return fmt.Errorf("failed to encode reply batch %s: %w", batch.Key(), err)
+	}
+	if _, err := tx.ExecContext(ctx, o.dialect.rebind(updateReplyBatchRepliesQuery), string(data), batch.Key()); err != nil {
+		return fmt.Errorf("failed to add to reply batch %s: %w", batch.Key(), err)
+	}
+	return nil
+}
+
+// Due returns the batches due by now, the earliest first
+func (o *ReplyOutbox) Due(ctx context.Context, now time.Time) ([]*domain.ReplyBatch, error) {
+	rows, err := o.db.QueryContext(ctx, o.dialect.rebind(selectDueReplyBatchesQuery), now.UnixMicro())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due reply batches: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []*domain.ReplyBatch
+	for rows.Next() {
+		var key, messageID, channelID, mode, replies, route string
+		var dueAt int64
+		if err := rows.Scan(&key, &messageID, &channelID, &mode, &replies, &route, &dueAt); err != nil {
+			return nil, fmt.Errorf("failed to read reply batch: %w", err)
+		}
+		batch, err := restoreReplyBatch(key, messageID, channelID, mode, replies, route, dueAt)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list due reply batches: %w", err)
+	}
+	return batches, nil
+}
+
+// Postpone moves the time a batch is due to
+func (o *ReplyOutbox) Postpone(ctx context.Context, key string, dueAt time.Time) error {
+	result, err := o.db.ExecContext(ctx, o.dialect.rebind(postponeReplyBatchQuery), dueAt.UnixMicro(), key)
+	if err != nil {
+		return fmt.Errorf("failed to postpone reply batch %s: %w", key, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("reply batch %s: %w", key, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// Remove takes a batch out of the outbox
+func (o *ReplyOutbox) Remove(ctx context.Context, key string) error {
+	result, err := o.db.ExecContext(ctx, o.dialect.rebind(deleteReplyBatchQuery), key)
+	if err != nil {
+		return fmt.Errorf("failed to remove reply batch %s: %w", key, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("reply batch %s: %w", key, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// KeepDetails keeps the confirmation of a message acknowledged with a reaction, replacing the earlier one
+func (o *ReplyOutbox) KeepDetails(ctx context.Context, messageID, reply string, at time.Time) error {
+	if _, err := o.db.ExecContext(ctx, o.dialect.rebind(upsertReplyDetailsQuery), messageID, reply, at.UnixMicro()); err != nil {
+		return fmt.Errorf("failed to keep details of message %s: %w", messageID, err)
+	}
+	return nil
+}
+
+// Details returns the confirmation kept for a message
+func (o *ReplyOutbox) Details(ctx context.Context, messageID string) (string, error) {
+	var reply string
+	err := o.db.QueryRowContext(ctx, o.dialect.rebind(selectReplyDetailsQuery), messageID).Scan(&reply)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("no details for message %s: %w", messageID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read details of message %s: %w", messageID, err)
+	}
+	return reply, nil
+}
+
+// ForgetDetails removes the confirmations kept before the time
+func (o *ReplyOutbox) ForgetDetails(ctx context.Context, before time.Time) (int, error) {
+	result, err := o.db.ExecContext(ctx, o.dialect.rebind(deleteReplyDetailsQuery), before.UnixMicro())
+	if err != nil {
+		return 0, fmt.Errorf("failed to forget reply details: %w", err)
+	}
+	forgotten, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to forget reply details: %w", err)
+	}
+	return int(forgotten), nil
+}
+
+// restoreReplyBatch recreates a row of the reply_outbox table
+func restoreReplyBatch(key, messageID, channelID, mode, replies, route string, dueAt int64) (*domain.ReplyBatch, error) {
+	var batchReplies []string
+	if err := json.Unmarshal([]byte(replies), &batchReplies); err != nil {
+		return nil, fmt.Errorf("failed to decode reply batch %s: %w", key, err)
+	}
+	var batchRoute map[string]string
+	if err := json.Unmarshal([]byte(route), &batchRoute); err != nil {
+		return nil, fmt.Errorf("failed to decode reply batch %s: %w", key, err)
+	}
+	batch, err := domain.RestoreReplyBatch(key, messageID, channelID, domain.ReplyMode(mode), batchReplies, batchRoute, time.UnixMicro(dueAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore reply batch %s: %w", key, err)
+	}
+	return batch, nil
+}
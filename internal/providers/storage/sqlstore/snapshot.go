@@ -0,0 +1,229 @@
+package sqlstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// SnapshotVersion is the version of the snapshot format Backup writes and Restore reads
+const SnapshotVersion = 1
+
+// Snapshot is the content of every table of a state store, in a single JSON file. It does not depend on the
+// dialect, so a snapshot of a SQLite store can be restored on Postgres and back.
+type Snapshot struct {
+	Version   int             `json:"version"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Tables    []SnapshotTable `json:"tables"`
+}
+
+// SnapshotTable is the content of a table, each row holds the values of the columns in order
+type SnapshotTable struct {
+	Name    string          `json:"name"`
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// snapshotTable is a table of the state store, copied by backups
+type snapshotTable struct {
+	name    string
+	columns []string
+	// added are the values of the last columns, added to the table after snapshots were first written, that
+	// rows of older snapshots without them restore with
+	added []interface{}
+}
+
+// snapshotTables are the tables of the state store, in the order snapshots list them
+var snapshotTables = []snapshotTable{
+	{name: "messages", columns: []string{"id", "thread_id", "state", "posted_at", "data", "sender_key"}, added: []interface{}{""}},
+	{name: "threads", columns: []string{"id", "channel_id", "external_id", "title", "created_at", "updated_at"}},
+	{name: "thread_messages", columns: []string{"thread_id", "message_id", "position"}},
+	{name: "audit_entries", columns: []string{"id", "action", "actor", "subject", "from_value", "to_value", "at", "correlation_id"}, added: []interface{}{""}},
+	{name: "dead_letters", columns: []string{"message_id", "error", "failed_at", "queued"}},
+	{name: "thread_mappings", columns: []string{"channel_id", "external_id", "thread_id"}},
+	{name: "dedup_keys", columns: []string{"dedup_key", "seen_at"}},
+	{name: "reply_outbox", columns: []string{"batch_key", "message_id", "channel_id", "mode", "replies", "route", "due_at"}},
+	{name: "pending_duplicates", columns: []string{"thread_id", "message_id", "author", "candidates", "expires_at"}},
+	{name: "pending_updates", columns: []string{"thread_id", "message_id", "author", "path", "content", "base_revision", "attached", "expires_at"}},
+	{name: "approval_requests", columns: []string{"thread_id", "message_id", "requested_at", "request"}},
+	{name: "reply_details", columns: []string{"message_id", "reply", "kept_at"}},
+}
+
+func (t snapshotTable) selectQuery() string {
+	columns := strings.Join(t.columns, ", ")
+	return "SELECT " + columns + " FROM " + t.name + " ORDER BY " + columns
+}
+
+func (t snapshotTable) deleteQuery() string {
+	return "DELETE FROM " + t.name
+}
+
+func (t snapshotTable) insertQuery() string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(t.columns)), ", ")
+	return "INSERT INTO " + t.name + " (" + strings.Join(t.columns, ", ") + ") VALUES (" + placeholders + ")"
+}
+
+// Snapshot reads every table of the store in one transaction
+func (s *StateStore) Snapshot(ctx context.Context) (*Snapshot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start backup: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	snapshot := &Snapshot{Version: SnapshotVersion, CreatedAt: time.Now().UTC()}
+	for _, table := range snapshotTables {
+		rows, err := tx.QueryContext(ctx, s.dialect.rebind(table.selectQuery()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", table.name, err)
+		}
+
+		content := SnapshotTable{Name: table.name, Columns: table.columns, Rows: [][]interface{}{}}
+		for rows.Next() {
+			values := make([]interface{}, len(table.columns))
+			targets := make([]interface{}, len(values))
+			for i := range values {
+				targets[i] = &values[i]
+			}
+			if err := rows.Scan(targets...); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("failed to back up %s: %w", table.name, err)
+			}
+			for i, value := range values {
+				// Some drivers return text columns as bytes, which JSON would encode in base64
+				if b, ok := value.([]byte); ok {
+					values[i] = string(b)
+				}
+			}
+			content.Rows = append(content.Rows, values)
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", table.name, err)
+		}
+		snapshot.Tables = append(snapshot.Tables, content)
+	}
+	return snapshot, nil
+}
+
+// Backup writes a snapshot of the store
+func (s *StateStore) Backup(ctx context.Context, w io.Writer) error {
+	snapshot, err := s.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the content of the store with a snapshot written by Backup, and returns how many rows it
+// restored per table. Tables the snapshot leaves out are emptied. Nothing changes when the snapshot is invalid,
+// the error wraps domain.ErrInvalidSnapshot then.
+func (s *StateStore) Restore(ctx context.Context, r io.Reader) (map[string]int, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var snapshot Snapshot
+	if err := decoder.Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSnapshot, err)
+	}
+	return s.RestoreSnapshot(ctx, &snapshot)
+}
+
+// RestoreSnapshot replaces the content of the store with a snapshot, see Restore
+func (s *StateStore) RestoreSnapshot(ctx context.Context, snapshot *Snapshot) (map[string]int, error) {
+	contents, err := snapshotContents(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start restore: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	restored := make(map[string]int, len(snapshotTables))
+	for _, table := range snapshotTables {
+		if _, err := tx.ExecContext(ctx, s.dialect.rebind(table.deleteQuery())); err != nil {
+			return nil, fmt.Errorf("failed to empty %s: %w", table.name, err)
+		}
+		insert := s.dialect.rebind(table.insertQuery())
+		for _, row := range contents[table.name] {
+			if _, err := tx.ExecContext(ctx, insert, row...); err != nil {
+				return nil, fmt.Errorf("failed to restore %s: %w", table.name, err)
+			}
+		}
+		restored[table.name] = len(contents[table.name])
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to restore: %w", err)
+	}
+	return restored, nil
+}
+
+// snapshotContents checks a snapshot against the tables of the store, and returns the rows of each table with
+// the numbers JSON decoded back to integers
+func snapshotContents(snapshot *Snapshot) (map[string][][]interface{}, error) {
+	if snapshot == nil {
+		return nil, fmt.Errorf("%w: snapshot cannot be nil", domain.ErrInvalidSnapshot)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d, expected %d", domain.ErrInvalidSnapshot, snapshot.Version, SnapshotVersion)
+	}
+
+	known := make(map[string]snapshotTable, len(snapshotTables))
+	for _, table := range snapshotTables {
+		known[table.name] = table
+	}
+	contents := make(map[string][][]interface{}, len(snapshot.Tables))
+	for _, content := range snapshot.Tables {
+		table, ok := known[content.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown table %q", domain.ErrInvalidSnapshot, content.Name)
+		}
+		if _, ok := contents[content.Name]; ok {
+			return nil, fmt.Errorf("%w: table %s is listed twice", domain.ErrInvalidSnapshot, content.Name)
+		}
+		// Snapshots written before columns were added leave them out, their rows get the values of added
+		missing := len(table.columns) - len(content.Columns)
+		if missing < 0 || missing > len(table.added) ||
+			strings.Join(content.Columns, ",") != strings.Join(table.columns[:len(content.Columns)], ",") {
+			return nil, fmt.Errorf("%w: table %s has columns %v, expected %v", domain.ErrInvalidSnapshot, content.Name, content.Columns, table.columns)
+		}
+
+		rows := make([][]interface{}, 0, len(content.Rows))
+		for i, row := range content.Rows {
+			if len(row) != len(content.Columns) {
+				return nil, fmt.Errorf("%w: row %d of %s has %d values, expected %d", domain.ErrInvalidSnapshot, i+1, content.Name, len(row), len(content.Columns))
+			}
+			values := make([]interface{}, len(row), len(table.columns))
+			for j, value := range row {
+				switch v := value.(type) {
+				case json.Number:
+					n, err := v.Int64()
+					if err != nil {
+						return nil, fmt.Errorf("%w: %s of row %d of %s is not an integer", domain.ErrInvalidSnapshot, table.columns[j], i+1, content.Name)
+					}
+					values[j] = n
+				case float64:
+					values[j] = int64(v)
+				default:
+					values[j] = v
+				}
+			}
+			values = append(values, table.added[len(table.added)-missing:]...)
+			rows = append(rows, values)
+		}
+		contents[content.Name] = rows
+	}
+	return contents, nil
+}
@@ -0,0 +1,195 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/massimo-ua/quill/internal/domain"
+)
+
+// StateStore keeps the processing state of a deployment in one database: the messages and their processing
+// states, the threads with the chat threads they map to, the dead letters of the message queue, the audit log,
+// the keys of handled events, the confirmations waiting to be posted, the ideas waiting for their author to
+// choose whether they are merged, the merges waiting for their author to apply their diff and the decisions
+// waiting for approval. On SQLite the whole state is a single file, and Backup and Restore move it around as a
+// single snapshot, which suits small deployments without a database server.
+type StateStore struct {
+	db          *sql.DB
+	dialect     Dialect
+	messages    *MessageRepository
+	threads     *ThreadRepository
+	audit       *AuditLog
+	deadLetters *DeadLetterQueue
+	mappings    *ThreadMappingCache
+	dedup       *DedupStore
+	outbox      *ReplyOutbox
+	duplicates  *PendingDuplicateStore
+	updates     *PendingUpdateStore
+	approvals   *ApprovalRequestStore
+	migrator    *Migrator
+}
+
+// NewStateStore creates a state store, call Migrate to create its tables. Open opens the database with the
+// bundled driver of the dialect.
+func NewStateStore(db *sql.DB, dialect Dialect) (*StateStore, error) {
+	messages, err := NewMessageRepository(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	deadLetters, err := NewDeadLetterQueue(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	approvals, err := NewApprovalRequestStore(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return newStateStore(db, dialect, messages, deadLetters, approvals)
+}
+
+// NewEncryptedStateStore creates a state store keeping the content and sender of messages, of the failed ones in
+// the dead-letter queue and of the decisions waiting for approval encrypted with the cipher. Call Migrate to
+// create its tables.
+func NewEncryptedStateStore(db *sql.DB, dialect Dialect, cipher domain.FieldCipher) (*StateStore, error) {
+	messages, err := NewEncryptedMessageRepository(db, dialect, cipher)
+	if err != nil {
+		return nil, err
+	}
+	deadLetters, err := NewEncryptedDeadLetterQueue(db, dialect, cipher)
+	if err != nil {
+		return nil, err
+	}
+	approvals, err := NewEncryptedApprovalRequestStore(db, dialect, cipher)
+	if err != nil {
+		return nil, err
+	}
+	return newStateStore(db, dialect, messages, deadLetters, approvals)
+}
+
+// newStateStore creates the other stores of a state store next to its messages, dead letters and approval requests
+func newStateStore(db *sql.DB, dialect Dialect, messages *MessageRepository, deadLetters *DeadLetterQueue, approvals *ApprovalRequestStore) (*StateStore, error) {
+	threads, err := NewThreadRepository(db, dialect, messages)
+	if err != nil {
+		return nil, err
+	}
+	audit, err := NewAuditLog(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := NewThreadMappingCache(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	dedup, err := NewDedupStore(db, dialect, DefaultDedupTTL)
+	if err != nil {
+		return nil, err
+	}
+	outbox, err := NewReplyOutbox(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	duplicates, err := NewPendingDuplicateStore(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	updates, err := NewPendingUpdateStore(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := NewMigrator(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &StateStore{
+		db:          db,
+		dialect:     dialect,
+		messages:    messages,
+		threads:     threads,
+		audit:       audit,
+		deadLetters: deadLetters,
+		mappings:    mappings,
+		dedup:       dedup,
+		outbox:      outbox,
+		duplicates:  duplicates,
+		updates:     updates,
+		approvals:   approvals,
+		migrator:    migrator,
+	}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the tables of every store
+func (s *StateStore) Migrate(ctx context.Context) error {
+	_, err := s.migrator.Up(ctx)
+	return err
+}
+
+// Reencrypt encrypts the stored messages, dead letters and approval requests again with the cipher's current
+// key, after a key rotation or when encryption was turned on for a store kept in plaintext. It returns how many
+// records it encrypted, none without a cipher.
+func (s *StateStore) Reencrypt(ctx context.Context) (int, error) {
+	messages, err := s.messages.Reencrypt(ctx)
+	if err != nil {
+		return messages, err
+	}
+	letters, err := s.deadLetters.Reencrypt(ctx)
+	if err != nil {
+		return messages + letters, err
+	}
+	requests, err := s.approvals.Reencrypt(ctx)
+	return messages + letters + requests, err
+}
+
+// Migrator returns the migrator of the schema of the store
+func (s *StateStore) Migrator() *Migrator {
+	return s.migrator
+}
+
+// Messages returns the message repository of the store
+func (s *StateStore) Messages() *MessageRepository {
+	return s.messages
+}
+
+// Threads returns the thread repository of the store, threads load their messages from Messages
+func (s *StateStore) Threads() *ThreadRepository {
+	return s.threads
+}
+
+// AuditLog returns the audit log of the store
+func (s *StateStore) AuditLog() *AuditLog {
+	return s.audit
+}
+
+// DeadLetters returns the dead-letter queue of the store
+func (s *StateStore) DeadLetters() *DeadLetterQueue {
+	return s.deadLetters
+}
+
+// ThreadMappings returns the cache of the threads chat threads map to
+func (s *StateStore) ThreadMappings() *ThreadMappingCache {
+	return s.mappings
+}
+
+// Dedup returns the store of the keys of handled events
+func (s *StateStore) Dedup() *DedupStore {
+	return s.dedup
+}
+
+// ReplyOutbox returns the outbox of the confirmations waiting to be posted
+func (s *StateStore) ReplyOutbox() *ReplyOutbox {
+	return s.outbox
+}
+
+// PendingDuplicates returns the store of the ideas waiting for their author to choose whether they are merged
+func (s *StateStore) PendingDuplicates() *PendingDuplicateStore {
+	return s.duplicates
+}
+
+// PendingUpdates returns the store of the merges waiting for their author to apply or discard their diff
+func (s *StateStore) PendingUpdates() *PendingUpdateStore {
+	return s.updates
+}
+
+// ApprovalRequests returns the store of the decisions waiting for approval
+func (s *StateStore) ApprovalRequests() *ApprovalRequestStore {
+	return s.approvals
+}
@@ -0,0 +1,280 @@
+package sqlstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/providers/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStateStore(t *testing.T, dialect Dialect) *StateStore {
+	t.Helper()
+
+	store, err := NewStateStore(openTestDatabase(t, dialect), dialect)
+	require.NoError(t, err)
+	require.NoError(t, store.Migrate(context.Background()))
+	return store
+}
+
+func newMessage(t *testing.T, threadID common.ID, text string) *domain.Message {
+	t.Helper()
+
+	msg, err := domain.NewMessage(threadID, "alice", domain.MustNewMessageContent(text), domain.MessageTypeDecision, domain.CategoryDevelopment, nil)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestNewStateStore(t *testing.T) {
+	_, err := NewStateStore(nil, SQLite)
+	assert.ErrorIs(t, err, ErrNilDatabase)
+	_, err = NewStateStore(openTestDatabase(t, SQLite), "mysql")
+	assert.ErrorIs(t, err, ErrUnknownDialect)
+}
+
+func TestStateStore_BackupRestore(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+
+		threadID := common.GenerateID()
+		msg := newMessage(t, threadID, "We decided to use Postgres for billing")
+		require.NoError(t, store.Messages().Save(ctx, msg))
+		thread, err := domain.NewThread(threadID, "C0001", "Billing database")
+		require.NoError(t, err)
+		require.NoError(t, thread.AddMessage(msg))
+		require.NoError(t, store.Threads().Save(ctx, thread))
+		entry, err := domain.NewAuditEntry(domain.AuditActionRecategorize, "U0001", "docs/other/pricing.md", "other", "product")
+		require.NoError(t, err)
+		entry.SetCorrelationID(domain.NewCorrelationID())
+		require.NoError(t, store.AuditLog().Record(ctx, entry))
+		queued, err := domain.NewQueuedMessage(newMessage(t, common.GenerateID(), "Ship the beta on Friday"), map[string]string{"channel": "C0002"})
+		require.NoError(t, err)
+		letter, err := domain.NewDeadLetter(queued, "analysis timed out", time.Now())
+		require.NoError(t, err)
+		require.NoError(t, store.DeadLetters().Add(ctx, letter))
+		_, err = store.ThreadMappings().Map(ctx, "C0001", "1700000000.000100", threadID)
+		require.NoError(t, err)
+		_, err = store.Dedup().MarkSeen(ctx, "slack:Ev0001")
+		require.NoError(t, err)
+		batch, err := domain.NewReplyBatch("1700000000.000100", msg.ID().String(), "C0001", domain.ReplyModeThread, "📝 Captured decision", time.Now())
+		require.NoError(t, err)
+		batch.SetRoute(map[string]string{"channel": "C0001"})
+		_, err = store.ReplyOutbox().Add(ctx, batch)
+		require.NoError(t, err)
+		require.NoError(t, store.ReplyOutbox().KeepDetails(ctx, msg.ID().String(), "💡 Captured idea", time.Now()))
+		pending, err := domain.NewPendingDuplicate("1700000000.000100", msg.ID().String(), "alice", []string{"docs/ideas/dark-mode.md"}, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.PendingDuplicates().Put(ctx, pending))
+		update, err := domain.NewPendingUpdate("1700000000.000100", msg.ID().String(), "alice", "docs/ideas/dark-mode.md", "# Dark mode",
+			"abc123", map[string][]byte{"docs/ideas/assets/dark-mode.png": []byte("png")}, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.PendingUpdates().Put(ctx, update))
+		request := domain.NewApprovalRequest(msg, domain.DocumentationConfig{ApprovalPolicies: []domain.ApprovalPolicy{{Approvals: 2}}}, time.Now())
+		require.NoError(t, request.Approve("bob"))
+		require.NoError(t, store.ApprovalRequests().Put(ctx, request))
+
+		var backup bytes.Buffer
+		require.NoError(t, store.Backup(ctx, &backup))
+
+		// The snapshot restores into an empty store, and replaces what a store held
+		restoredStore := newTestStateStore(t, dialect)
+		other := newMessage(t, common.GenerateID(), "Replaced by the restore")
+		require.NoError(t, restoredStore.Messages().Save(ctx, other))
+
+		restored, err := restoredStore.Restore(ctx, bytes.NewReader(backup.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"messages": 1, "threads": 1, "thread_messages": 1, "audit_entries": 1, "dead_letters": 1,
+			"thread_mappings": 1, "dedup_keys": 1, "reply_outbox": 1, "pending_duplicates": 1, "pending_updates": 1,
+			"approval_requests": 1, "reply_details": 1}, restored)
+
+		found, err := restoredStore.Threads().FindByID(ctx, threadID)
+		require.NoError(t, err)
+		assert.Equal(t, "C0001", found.ChannelID())
+		require.Equal(t, 1, found.MessageCount())
+		assert.Equal(t, msg.Content(), found.Messages()[0].Content())
+		_, err = restoredStore.Messages().FindByID(ctx, other.ID().String())
+		assert.Error(t, err)
+
+		entries, err := restoredStore.AuditLog().List(ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, entry.ID(), entries[0].ID())
+		assert.Equal(t, entry.At().Truncate(time.Microsecond), entries[0].At())
+		assert.Equal(t, entry.CorrelationID(), entries[0].CorrelationID())
+
+		letters, err := restoredStore.DeadLetters().List(ctx)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, letter.ID(), letters[0].ID())
+		assert.Equal(t, map[string]string{"channel": "C0002"}, letters[0].Queued().Route())
+
+		mapped, err := restoredStore.ThreadMappings().Get(ctx, "C0001", "1700000000.000100")
+		require.NoError(t, err)
+		assert.Equal(t, threadID, mapped)
+		seen, err := restoredStore.Dedup().MarkSeen(ctx, "slack:Ev0001")
+		require.NoError(t, err)
+		assert.False(t, seen, "handled events stay handled")
+		due, err := restoredStore.ReplyOutbox().Due(ctx, time.Now())
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, []string{"📝 Captured decision"}, due[0].Replies())
+		assert.Equal(t, map[string]string{"channel": "C0001"}, due[0].Route())
+		details, err := restoredStore.ReplyOutbox().Details(ctx, msg.ID().String())
+		require.NoError(t, err)
+		assert.Equal(t, "💡 Captured idea", details)
+		pendingChoice, err := restoredStore.PendingDuplicates().Find(ctx, "1700000000.000100", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docs/ideas/dark-mode.md"}, pendingChoice.Candidates())
+		pendingUpdate, err := restoredStore.PendingUpdates().Find(ctx, "1700000000.000100", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "# Dark mode", pendingUpdate.Content())
+		assert.Equal(t, map[string][]byte{"docs/ideas/assets/dark-mode.png": []byte("png")}, pendingUpdate.Attached())
+		waiting, err := restoredStore.ApprovalRequests().Find(ctx, threadID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bob"}, waiting.Approvers())
+		assert.Equal(t, "1 approval", waiting.Missing())
+	})
+}
+
+func TestStateStore_RestoreInvalidSnapshot(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+		msg := newMessage(t, common.GenerateID(), "We decided to use Postgres for billing")
+		require.NoError(t, store.Messages().Save(ctx, msg))
+
+		snapshot, err := store.Snapshot(ctx)
+		require.NoError(t, err)
+		valid, err := json.Marshal(snapshot)
+		require.NoError(t, err)
+
+		for name, snapshot := range map[string]string{
+			"not JSON":        "backup",
+			"newer version":   `{"version": 2, "tables": []}`,
+			"unknown table":   `{"version": 1, "tables": [{"name": "users", "columns": ["id"], "rows": []}]}`,
+			"renamed columns": `{"version": 1, "tables": [{"name": "dead_letters", "columns": ["id", "error", "failed_at", "queued"], "rows": []}]}`,
+			"short row":       `{"version": 1, "tables": [{"name": "thread_messages", "columns": ["thread_id", "message_id", "position"], "rows": [["t", "m"]]}]}`,
+		} {
+			_, err := store.Restore(ctx, bytes.NewReader([]byte(snapshot)))
+			assert.ErrorIs(t, err, domain.ErrInvalidSnapshot, name)
+		}
+
+		// Nothing changed
+		_, err = store.Messages().FindByID(ctx, msg.ID().String())
+		require.NoError(t, err)
+		_, err = store.Restore(ctx, bytes.NewReader(valid))
+		require.NoError(t, err)
+	})
+}
+
+func TestStateStore_RestoreSnapshotWithoutAddedColumns(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		store := newTestStateStore(t, dialect)
+		entryID := common.GenerateID()
+
+		// Snapshots written before audit entries had a correlation ID
+		snapshot := `{"version": 1, "tables": [{"name": "audit_entries",
+			"columns": ["id", "action", "actor", "subject", "from_value", "to_value", "at"],
+			"rows": [["` + entryID.String() + `", "recategorize", "U0001", "docs/other/pricing.md", "other", "product", 1700000000000000]]}]}`
+		restored, err := store.Restore(ctx, bytes.NewReader([]byte(snapshot)))
+		require.NoError(t, err)
+		assert.Equal(t, 1, restored["audit_entries"])
+
+		entries, err := store.AuditLog().List(ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, entryID, entries[0].ID())
+		assert.Empty(t, entries[0].CorrelationID())
+	})
+}
+
+func TestStateStore_Encrypted(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		db := openTestDatabase(t, dialect)
+		oldSecret, newSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+		old, err := encryption.NewKeyring(encryption.NewConfig("2023", oldSecret))
+		require.NoError(t, err)
+		store, err := NewEncryptedStateStore(db, dialect, old)
+		require.NoError(t, err)
+		require.NoError(t, store.Migrate(ctx))
+
+		msg := newMessage(t, common.GenerateID(), "We will use Postgres")
+		require.NoError(t, store.Messages().Save(ctx, msg))
+		queued, err := domain.NewQueuedMessage(newMessage(t, common.GenerateID(), "Ship the beta on Friday"), nil)
+		require.NoError(t, err)
+		letter, err := domain.NewDeadLetter(queued, "analysis timed out", time.Now())
+		require.NoError(t, err)
+		require.NoError(t, store.DeadLetters().Add(ctx, letter))
+		request := domain.NewApprovalRequest(msg, domain.DocumentationConfig{ApprovalPolicies: []domain.ApprovalPolicy{{}}}, time.Now())
+		require.NoError(t, store.ApprovalRequests().Put(ctx, request))
+
+		// Neither the content nor the sender are stored in plaintext
+		var data, stored, held string
+		require.NoError(t, db.QueryRowContext(ctx, dialect.rebind(selectMessageQuery), msg.ID().String()).Scan(&data))
+		require.NoError(t, db.QueryRowContext(ctx, dialect.rebind("SELECT queued FROM dead_letters WHERE message_id = ?"), letter.ID()).Scan(&stored))
+		require.NoError(t, db.QueryRowContext(ctx, dialect.rebind(selectApprovalRequestQuery), msg.ThreadID().String()).Scan(&held))
+		for _, value := range []string{data, stored, held} {
+			assert.NotContains(t, value, "Postgres")
+			assert.NotContains(t, value, "Friday")
+			assert.NotContains(t, value, `"alice"`)
+			assert.Contains(t, value, "enc:v1:2023:")
+		}
+		found, err := store.Messages().FindByID(ctx, msg.ID().String())
+		require.NoError(t, err)
+		assert.Equal(t, msg.Content(), found.Content())
+		assert.Equal(t, "alice", found.Sender())
+		failed, err := store.DeadLetters().Get(ctx, letter.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Ship the beta on Friday", failed.Queued().Message().Content().Text())
+		waiting, err := store.ApprovalRequests().Find(ctx, msg.ThreadID().String())
+		require.NoError(t, err)
+		assert.Equal(t, "alice", waiting.Message().Sender())
+
+		// After a rotation the old key still decrypts, until the store is encrypted again
+		rotated, err := encryption.NewKeyring(&encryption.Config{
+			Keys:        []encryption.Key{{ID: "2023", Secret: oldSecret}, {ID: "2024", Secret: newSecret}},
+			ActiveKeyID: "2024",
+		})
+		require.NoError(t, err)
+		store, err = NewEncryptedStateStore(db, dialect, rotated)
+		require.NoError(t, err)
+		reencrypted, err := store.Reencrypt(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, reencrypted)
+
+		current, err := encryption.NewKeyring(encryption.NewConfig("2024", newSecret))
+		require.NoError(t, err)
+		store, err = NewEncryptedStateStore(db, dialect, current)
+		require.NoError(t, err)
+		found, err = store.Messages().FindByID(ctx, msg.ID().String())
+		require.NoError(t, err)
+		assert.Equal(t, msg.Content(), found.Content())
+		_, err = store.DeadLetters().Get(ctx, letter.ID())
+		require.NoError(t, err, "the old key is no longer needed")
+		_, err = store.ApprovalRequests().Find(ctx, msg.ThreadID().String())
+		require.NoError(t, err)
+
+		// Messages stored before encryption was turned on are read, and encrypted by Reencrypt
+		plain, err := NewStateStore(db, dialect)
+		require.NoError(t, err)
+		legacy := newMessage(t, common.GenerateID(), "Stored in plaintext")
+		require.NoError(t, plain.Messages().Save(ctx, legacy))
+		reencrypted, err = store.Reencrypt(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 4, reencrypted)
+		require.NoError(t, db.QueryRowContext(ctx, dialect.rebind(selectMessageQuery), legacy.ID().String()).Scan(&data))
+		assert.NotContains(t, data, "plaintext")
+
+		_, err = NewEncryptedStateStore(db, dialect, nil)
+		assert.ErrorIs(t, err, ErrNilCipher)
+	})
+}
@@ -0,0 +1,64 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	insertThreadMappingQuery = `INSERT INTO thread_mappings (channel_id, external_id, thread_id) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`
+	selectThreadMappingQuery = `SELECT thread_id FROM thread_mappings WHERE channel_id = ? AND external_id = ?`
+)
+
+// ThreadMappingCache implements the ports.ThreadMappingCache interface on a SQL database, so chat threads keep
+// mapping to the same threads across restarts
+type ThreadMappingCache struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewThreadMappingCache creates a thread mapping cache, call Migrate to create its table
+func NewThreadMappingCache(db *sql.DB, dialect Dialect) (*ThreadMappingCache, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	return &ThreadMappingCache{db: db, dialect: dialect}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the table of the cache
+func (c *ThreadMappingCache) Migrate(ctx context.Context) error {
+	return migrate(ctx, c.db, c.dialect)
+}
+
+// Get returns the ID of our thread a chat thread maps to
+func (c *ThreadMappingCache) Get(ctx context.Context, channelID, externalID string) (common.ID, error) {
+	var stored string
+	err := c.db.QueryRowContext(ctx, c.dialect.rebind(selectThreadMappingQuery), channelID, externalID).Scan(&stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.ID{}, fmt.Errorf("thread %s of %s: %w", externalID, channelID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return common.ID{}, fmt.Errorf("failed to find thread %s of %s: %w", externalID, channelID, err)
+	}
+	id, err := common.NewID(stored)
+	if err != nil {
+		return common.ID{}, fmt.Errorf("invalid stored thread ID %q: %w", stored, err)
+	}
+	return id, nil
+}
+
+// Map maps a chat thread to id unless it is mapped already, and returns the ID it maps to
+func (c *ThreadMappingCache) Map(ctx context.Context, channelID, externalID string, id common.ID) (common.ID, error) {
+	if _, err := c.db.ExecContext(ctx, c.dialect.rebind(insertThreadMappingQuery), channelID, externalID, id.String()); err != nil {
+		return common.ID{}, fmt.Errorf("failed to map thread %s of %s: %w", externalID, channelID, err)
+	}
+	return c.Get(ctx, channelID, externalID)
+}
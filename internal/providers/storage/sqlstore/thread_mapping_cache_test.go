@@ -0,0 +1,41 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadMappingCache_MapGet(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		cache := newTestStateStore(t, dialect).ThreadMappings()
+
+		_, err := cache.Get(ctx, "C1", "1700000000.000100")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+
+		first := common.GenerateID()
+		mapped, err := cache.Map(ctx, "C1", "1700000000.000100", first)
+		require.NoError(t, err)
+		assert.Equal(t, first, mapped)
+
+		// A second replica creating the same thread gets the first ID
+		mapped, err = cache.Map(ctx, "C1", "1700000000.000100", common.GenerateID())
+		require.NoError(t, err)
+		assert.Equal(t, first, mapped)
+
+		got, err := cache.Get(ctx, "C1", "1700000000.000100")
+		require.NoError(t, err)
+		assert.Equal(t, first, got)
+
+		_, err = cache.Get(ctx, "C2", "1700000000.000100")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+	})
+
+	_, err := NewThreadMappingCache(nil, SQLite)
+	assert.ErrorIs(t, err, ErrNilDatabase)
+}
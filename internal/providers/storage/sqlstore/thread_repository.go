@@ -0,0 +1,248 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+const (
+	upsertThreadQuery = `INSERT INTO threads (id, channel_id, external_id, title, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET channel_id = excluded.channel_id, external_id = excluded.external_id,
+title = excluded.title, updated_at = excluded.updated_at`
+	touchThreadQuery         = `UPDATE threads SET updated_at = ? WHERE id = ?`
+	selectThreadQuery        = `SELECT id, channel_id, external_id, title, created_at, updated_at FROM threads WHERE id = ?`
+	selectChannelThreadQuery = `SELECT id, channel_id, external_id, title, created_at, updated_at FROM threads WHERE channel_id = ? ORDER BY created_at, id`
+	deleteThreadQuery        = `DELETE FROM threads WHERE id = ?`
+	insertThreadMessageQuery = `INSERT INTO thread_messages (thread_id, message_id, position) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`
+	appendThreadMessageQuery = `INSERT INTO thread_messages (thread_id, message_id, position)
+SELECT ?, ?, COALESCE(MAX(position), -1) + 1 FROM thread_messages WHERE thread_id = ? ON CONFLICT DO NOTHING`
+	selectThreadMessageQuery = `SELECT message_id FROM thread_messages WHERE thread_id = ? ORDER BY position`
+	deleteThreadMessageQuery = `DELETE FROM thread_messages WHERE thread_id = ?`
+)
+
+// ThreadRepository implements the ports.ThreadRepository interface on a SQL database, so threads and the Slack
+// threads they map to survive restarts and can be queried. Threads keep the IDs of their messages, which are
+// loaded from the message repository; messages it no longer holds, like purged ones, are left out.
+//
+// The database must use a driver registered by the deployment, e.g. pgx's stdlib package for Postgres or
+// modernc.org/sqlite for SQLite.
+type ThreadRepository struct {
+	db       *sql.DB
+	dialect  Dialect
+	messages ports.MessageRepository
+}
+
+// NewThreadRepository creates a thread repository, call Migrate to create its tables
+func NewThreadRepository(db *sql.DB, dialect Dialect, messages ports.MessageRepository) (*ThreadRepository, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	if messages == nil {
+		return nil, fmt.Errorf("message repository cannot be nil")
+	}
+	return &ThreadRepository{db: db, dialect: dialect, messages: messages}, nil
+}
+
+// Migrate applies the pending schema migrations, which create the tables of the repository
+func (r *ThreadRepository) Migrate(ctx context.Context) error {
+	return migrate(ctx, r.db, r.dialect)
+}
+
+// Save stores a thread with the IDs of its messages, replacing the earlier version of it
+func (r *ThreadRepository) Save(ctx context.Context, thread *domain.Thread) error {
+	if thread == nil {
+		return fmt.Errorf("thread cannot be nil")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save thread: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	id := thread.ID().String()
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind(upsertThreadQuery), id, thread.ChannelID(), thread.ExternalID(),
+		thread.Title(), thread.CreatedAt().UnixMicro(), thread.UpdatedAt().UnixMicro()); err != nil {
+		return fmt.Errorf("failed to save thread: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind(deleteThreadMessageQuery), id); err != nil {
+		return fmt.Errorf("failed to save thread messages: %w", err)
+	}
+	for i, msg := range thread.Messages() {
+		if _, err := tx.ExecContext(ctx, r.dialect.rebind(insertThreadMessageQuery), id, msg.ID().String(), i); err != nil {
+			return fmt.Errorf("failed to save thread messages: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save thread: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns a thread by ID
+func (r *ThreadRepository) FindByID(ctx context.Context, id common.ID) (*domain.Thread, error) {
+	row := r.db.QueryRowContext(ctx, r.dialect.rebind(selectThreadQuery), id.String())
+	thread, err := r.scanThread(ctx, row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("thread %s: %w", id, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find thread %s: %w", id, err)
+	}
+	return thread, nil
+}
+
+// FindByChannel returns the threads of a channel, oldest first
+func (r *ThreadRepository) FindByChannel(ctx context.Context, channelID string) ([]*domain.Thread, error) {
+	rows, err := r.db.QueryContext(ctx, r.dialect.rebind(selectChannelThreadQuery), channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find threads of channel %s: %w", channelID, err)
+	}
+	defer rows.Close()
+
+	// Rows are read before the messages are loaded, as some drivers hold one query per connection
+	var stored []storedThread
+	for rows.Next() {
+		var t storedThread
+		if err := rows.Scan(&t.id, &t.channelID, &t.externalID, &t.title, &t.createdAt, &t.updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to read thread: %w", err)
+		}
+		stored = append(stored, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find threads of channel %s: %w", channelID, err)
+	}
+	_ = rows.Close()
+
+	threads := make([]*domain.Thread, 0, len(stored))
+	for _, t := range stored {
+		thread, err := r.restore(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
+// AppendMessage adds a message after the others of a stored thread
+func (r *ThreadRepository) AppendMessage(ctx context.Context, threadID common.ID, msg *domain.Message) error {
+	if msg == nil {
+		return domain.ErrInvalidMessages
+	}
+
+	id := threadID.String()
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind(touchThreadQuery), time.Now().UnixMicro(), id)
+	if err != nil {
+		return fmt.Errorf("failed to add message to thread %s: %w", id, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("thread %s: %w", id, ports.ErrNotFound)
+	}
+	if _, err := r.db.ExecContext(ctx, r.dialect.rebind(appendThreadMessageQuery), id, msg.ID().String(), id); err != nil {
+		return fmt.Errorf("failed to add message to thread %s: %w", id, err)
+	}
+	return nil
+}
+
+// Delete removes a thread
+func (r *ThreadRepository) Delete(ctx context.Context, id common.ID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete thread: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind(deleteThreadMessageQuery), id.String()); err != nil {
+		return fmt.Errorf("failed to delete thread messages: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, r.dialect.rebind(deleteThreadQuery), id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete thread: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("thread %s: %w", id, ports.ErrNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete thread: %w", err)
+	}
+	return nil
+}
+
+// storedThread is a row of the threads table
+type storedThread struct {
+	id         string
+	channelID  string
+	externalID string
+	title      string
+	createdAt  int64
+	updatedAt  int64
+}
+
+func (r *ThreadRepository) scanThread(ctx context.Context, row *sql.Row) (*domain.Thread, error) {
+	var t storedThread
+	if err := row.Scan(&t.id, &t.channelID, &t.externalID, &t.title, &t.createdAt, &t.updatedAt); err != nil {
+		return nil, err
+	}
+	return r.restore(ctx, t)
+}
+
+// restore loads the messages of a stored thread and recreates it
+func (r *ThreadRepository) restore(ctx context.Context, t storedThread) (*domain.Thread, error) {
+	id, err := common.NewID(t.id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored thread ID %q: %w", t.id, err)
+	}
+	messages, err := r.threadMessages(ctx, t.id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.RestoreThread(id, t.channelID, t.externalID, t.title, messages,
+		time.UnixMicro(t.createdAt).UTC(), time.UnixMicro(t.updatedAt).UTC())
+}
+
+func (r *ThreadRepository) threadMessages(ctx context.Context, threadID string) ([]*domain.Message, error) {
+	rows, err := r.db.QueryContext(ctx, r.dialect.rebind(selectThreadMessageQuery), threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find messages of thread %s: %w", threadID, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to read thread message: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find messages of thread %s: %w", threadID, err)
+	}
+	_ = rows.Close()
+
+	messages := make([]*domain.Message, 0, len(ids))
+	for _, id := range ids {
+		msg, err := r.messages.FindByID(ctx, id)
+		if errors.Is(err, ports.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load thread message %s: %w", id, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
@@ -0,0 +1,151 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/domain/common"
+	"github.com/massimo-ua/quill/internal/domain/ports"
+	"github.com/massimo-ua/quill/internal/providers/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepository(t *testing.T, dialect Dialect) (*ThreadRepository, *memory.MessageRepository, *sql.DB) {
+	t.Helper()
+
+	db := openTestDatabase(t, dialect)
+	messages := memory.NewMessageRepository()
+	repo, err := NewThreadRepository(db, dialect, messages)
+	require.NoError(t, err)
+	require.NoError(t, repo.Migrate(context.Background()))
+	return repo, messages, db
+}
+
+func storedMessage(t *testing.T, messages *memory.MessageRepository, threadID common.ID, text string) *domain.Message {
+	t.Helper()
+
+	msg, err := domain.NewMessage(threadID, "alice", domain.MustNewMessageContent(text), domain.MessageTypeUnknown, domain.CategoryUnknown, nil)
+	require.NoError(t, err)
+	require.NoError(t, messages.Save(context.Background(), msg))
+	return msg
+}
+
+func TestNewThreadRepository(t *testing.T) {
+	db := openTestDatabase(t, SQLite)
+	messages := memory.NewMessageRepository()
+
+	_, err := NewThreadRepository(nil, SQLite, messages)
+	assert.ErrorIs(t, err, ErrNilDatabase)
+	_, err = NewThreadRepository(db, "mysql", messages)
+	assert.ErrorIs(t, err, ErrUnknownDialect)
+	_, err = NewThreadRepository(db, Postgres, nil)
+	assert.Error(t, err)
+}
+
+func TestThreadRepository_SaveFindByID(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		repo, messages, _ := newTestRepository(t, dialect)
+		id := common.GenerateID()
+		first := storedMessage(t, messages, id, "Which database for billing?")
+		second := storedMessage(t, messages, id, "Postgres")
+
+		createdAt := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+		thread, err := domain.RestoreThread(id, "C0001", "1700000000.000100", "Billing database", []*domain.Message{first, second}, createdAt, createdAt)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(ctx, thread))
+
+		found, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID())
+		assert.Equal(t, "C0001", found.ChannelID())
+		assert.Equal(t, "1700000000.000100", found.ExternalID())
+		assert.Equal(t, "Billing database", found.Title())
+		assert.Equal(t, createdAt, found.CreatedAt())
+		require.Equal(t, 2, found.MessageCount())
+		assert.Equal(t, first.ID(), found.Messages()[0].ID())
+		assert.Equal(t, second.ID(), found.Messages()[1].ID())
+
+		// Saving again replaces the thread, purged messages are left out
+		require.NoError(t, thread.Retitle("Adopt Postgres"))
+		require.NoError(t, repo.Save(ctx, thread))
+		require.NoError(t, messages.Delete(ctx, first.ID().String()))
+		found, err = repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Adopt Postgres", found.Title())
+		assert.Equal(t, 1, found.MessageCount())
+
+		_, err = repo.FindByID(ctx, common.GenerateID())
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+		assert.Error(t, repo.Save(ctx, nil))
+	})
+}
+
+func TestThreadRepository_FindByChannelAppendMessage(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		repo, messages, _ := newTestRepository(t, dialect)
+
+		older, err := domain.RestoreThread(common.GenerateID(), "C0001", "1700000000.000100", "Billing database", nil,
+			time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		newer, err := domain.RestoreThread(common.GenerateID(), "C0001", "1700000000.000200", "Invoice numbering", nil,
+			time.Date(2024, 7, 2, 9, 0, 0, 0, time.UTC), time.Date(2024, 7, 2, 9, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		other, err := domain.NewThread(common.GenerateID(), "C0002", "Release train")
+		require.NoError(t, err)
+		for _, thread := range []*domain.Thread{newer, other, older} {
+			require.NoError(t, repo.Save(ctx, thread))
+		}
+
+		msg := storedMessage(t, messages, older.ID(), "Postgres")
+		require.NoError(t, repo.AppendMessage(ctx, older.ID(), msg))
+		require.NoError(t, repo.AppendMessage(ctx, older.ID(), msg), "a message already in the thread is not added again")
+		assert.ErrorIs(t, repo.AppendMessage(ctx, common.GenerateID(), msg), ports.ErrNotFound)
+
+		threads, err := repo.FindByChannel(ctx, "C0001")
+		require.NoError(t, err)
+		require.Len(t, threads, 2)
+		assert.Equal(t, older.ID(), threads[0].ID())
+		assert.Equal(t, 1, threads[0].MessageCount())
+		assert.True(t, threads[0].UpdatedAt().After(older.UpdatedAt()))
+		assert.Equal(t, newer.ID(), threads[1].ID())
+
+		none, err := repo.FindByChannel(ctx, "C0003")
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestThreadRepository_Delete(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, dialect Dialect) {
+		ctx := context.Background()
+		repo, messages, db := newTestRepository(t, dialect)
+		id := common.GenerateID()
+		thread, err := domain.NewThread(id, "C0001", "Billing database")
+		require.NoError(t, err)
+		require.NoError(t, thread.AddMessage(storedMessage(t, messages, id, "Postgres")))
+		require.NoError(t, repo.Save(ctx, thread))
+
+		require.NoError(t, repo.Delete(ctx, id))
+		_, err = repo.FindByID(ctx, id)
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+		assert.Zero(t, countRows(t, db, "thread_messages"))
+		assert.ErrorIs(t, repo.Delete(ctx, id), ports.ErrNotFound)
+	})
+}
+
+func TestDialect(t *testing.T) {
+	dialect, err := ParseDialect(" Postgres ")
+	require.NoError(t, err)
+	assert.Equal(t, Postgres, dialect)
+	_, err = ParseDialect("mysql")
+	assert.ErrorIs(t, err, ErrUnknownDialect)
+
+	assert.Equal(t, "SELECT a FROM t WHERE b = $1 AND c = $2", Postgres.rebind("SELECT a FROM t WHERE b = ? AND c = ?"))
+	assert.Equal(t, "SELECT a FROM t WHERE b = ?", SQLite.rebind("SELECT a FROM t WHERE b = ?"))
+}
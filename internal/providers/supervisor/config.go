@@ -0,0 +1,48 @@
+package supervisor
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidBackoff = errors.New("backoff must be positive and the maximum at least the initial backoff")
+	ErrInvalidJitter  = errors.New("jitter must be between 0 and 1")
+)
+
+// Config controls how a supervisor restarts the goroutine it watches
+type Config struct {
+	// InitialBackoff is the wait before the first restart (default: 1s)
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between restarts, which doubles after every failure (default: 1m)
+	MaxBackoff time.Duration
+
+	// StableAfter resets the backoff once a run lasted this long (default: 5m)
+	StableAfter time.Duration
+
+	// Jitter randomizes each wait by up to this fraction of the backoff, so restarts of many
+	// instances do not hit the remote service at once (default: 0.2)
+	Jitter float64
+}
+
+// NewDefaultConfig creates a Config with default values
+func NewDefaultConfig() *Config {
+	return &Config{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		StableAfter:    5 * time.Minute,
+		Jitter:         0.2,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.InitialBackoff <= 0 || c.MaxBackoff < c.InitialBackoff || c.StableAfter < 0 {
+		return ErrInvalidBackoff
+	}
+	if c.Jitter < 0 || c.Jitter > 1 {
+		return ErrInvalidJitter
+	}
+	return nil
+}
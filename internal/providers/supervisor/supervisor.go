@@ -0,0 +1,183 @@
+package supervisor
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Health is a snapshot of a supervised goroutine, published under the "supervisors" expvar
+type Health struct {
+	Name        string    `json:"name"`
+	Running     bool      `json:"running"`
+	Restarts    int       `json:"restarts"`
+	Panics      int       `json:"panics"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastFailure time.Time `json:"lastFailure,omitempty"`
+}
+
+// Supervisor keeps a long running goroutine alive. Panics are recovered and logged with their
+// stack trace, and the goroutine is restarted with exponential backoff until its context ends.
+type Supervisor struct {
+	name   string
+	config *Config
+
+	mu     sync.Mutex
+	health Health
+}
+
+// New creates a supervisor and registers its health for publishing, a nil config uses the defaults
+func New(name string, config *Config) (*Supervisor, error) {
+	if config == nil {
+		config = NewDefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := &Supervisor{
+		name:   name,
+		config: config,
+		health: Health{Name: name},
+	}
+	register(s)
+	return s, nil
+}
+
+// Run calls fn until ctx is canceled. Returning, with or without an error, and panicking all count
+// as failures: fn is expected to run for as long as ctx lives.
+func (s *Supervisor) Run(ctx context.Context, fn func(ctx context.Context) error) {
+	backoff := s.config.InitialBackoff
+	for ctx.Err() == nil {
+		s.setRunning(true)
+		started := time.Now()
+		err := s.runOnce(ctx, fn)
+		s.setRunning(false)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			err = fmt.Errorf("%s stopped", s.name)
+		}
+		s.recordFailure(err)
+
+		if s.config.StableAfter > 0 && time.Since(started) >= s.config.StableAfter {
+			backoff = s.config.InitialBackoff
+		}
+		wait := s.jittered(backoff)
+		log.Printf("Restarting %s in %s after failure: %v", s.name, wait.Round(time.Millisecond), err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > s.config.MaxBackoff {
+			backoff = s.config.MaxBackoff
+		}
+	}
+}
+
+// jittered spreads a backoff by up to the configured jitter in either direction
+func (s *Supervisor) jittered(backoff time.Duration) time.Duration {
+	if s.config.Jitter == 0 {
+		return backoff
+	}
+	spread := s.config.Jitter * float64(backoff)
+	return backoff + time.Duration(spread*(2*rand.Float64()-1))
+}
+
+// Health returns the current health of the supervised goroutine
+func (s *Supervisor) Health() Health {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.health
+}
+
+// runOnce calls fn, turning a panic into an error
+func (s *Supervisor) runOnce(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.mu.Lock()
+			s.health.Panics++
+			s.mu.Unlock()
+			log.Printf("Recovered panic in %s: %v\n%s", s.name, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+func (s *Supervisor) setRunning(running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health.Running = running
+}
+
+func (s *Supervisor) recordFailure(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health.Restarts++
+	s.health.LastError = err.Error()
+	s.health.LastFailure = time.Now()
+}
+
+// Go runs fn in a goroutine that logs panics with their stack trace instead of crashing the process.
+// It suits short-lived work like handling a single event, which is not restarted.
+func Go(name string, fn func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Recovered panic in %s: %v\n%s", name, r, debug.Stack())
+			}
+		}()
+		fn()
+	}()
+}
+
+// registry holds the supervisors whose health is published, keyed by name
+var registry = struct {
+	sync.Mutex
+	supervisors map[string]*Supervisor
+}{supervisors: make(map[string]*Supervisor)}
+
+func init() {
+	expvar.Publish("supervisors", expvar.Func(func() interface{} {
+		return Snapshot()
+	}))
+}
+
+// register publishes the health of a supervisor, replacing an earlier supervisor with the same name
+func register(s *Supervisor) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.supervisors[s.name] = s
+}
+
+// Snapshot returns the health of every registered supervisor, ordered by name
+func Snapshot() []Health {
+	registry.Lock()
+	supervisors := make([]*Supervisor, 0, len(registry.supervisors))
+	for _, s := range registry.supervisors {
+		supervisors = append(supervisors, s)
+	}
+	registry.Unlock()
+
+	health := make([]Health, 0, len(supervisors))
+	for _, s := range supervisors {
+		health = append(health, s.Health())
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].Name < health[j].Name })
+	return health
+}
@@ -0,0 +1,132 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() *Config {
+	return &Config{InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr error
+	}{
+		{name: "defaults", config: NewDefaultConfig()},
+		{name: "zero initial backoff", config: &Config{MaxBackoff: time.Second}, wantErr: ErrInvalidBackoff},
+		{name: "max below initial", config: &Config{InitialBackoff: time.Second, MaxBackoff: time.Millisecond}, wantErr: ErrInvalidBackoff},
+		{name: "negative stable after", config: &Config{InitialBackoff: time.Second, MaxBackoff: time.Second, StableAfter: -1}, wantErr: ErrInvalidBackoff},
+		{name: "jitter above one", config: &Config{InitialBackoff: time.Second, MaxBackoff: time.Second, Jitter: 1.5}, wantErr: ErrInvalidJitter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSupervisor_RestartsAfterPanicsAndErrors(t *testing.T) {
+	s, err := New("test-restarts", testConfig())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx, func(ctx context.Context) error {
+			switch atomic.AddInt32(&runs, 1) {
+			case 1:
+				panic("socket exploded")
+			case 2:
+				return errors.New("connection reset")
+			default:
+				<-ctx.Done()
+				return ctx.Err()
+			}
+		})
+	}()
+
+	require.Eventually(t, func() bool { return s.Health().Running && atomic.LoadInt32(&runs) == 3 }, time.Second, time.Millisecond)
+	health := s.Health()
+	assert.Equal(t, 2, health.Restarts)
+	assert.Equal(t, 1, health.Panics)
+	assert.Equal(t, "connection reset", health.LastError)
+
+	cancel()
+	<-done
+	assert.False(t, s.Health().Running)
+	assert.Equal(t, 2, s.Health().Restarts, "stopping is not a failure")
+}
+
+func TestSupervisor_RestartsWhenFunctionReturns(t *testing.T) {
+	s, err := New("test-returns", testConfig())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs int32
+	s.Run(ctx, func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) == 3 {
+			cancel()
+		}
+		return nil
+	})
+
+	assert.Equal(t, int32(3), runs)
+	assert.Equal(t, 2, s.Health().Restarts)
+	assert.Equal(t, "test-returns stopped", s.Health().LastError)
+}
+
+func TestSupervisor_Jittered(t *testing.T) {
+	s, err := New("test-jitter", &Config{InitialBackoff: time.Second, MaxBackoff: time.Second, Jitter: 0.5})
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		wait := s.jittered(time.Second)
+		assert.GreaterOrEqual(t, wait, 500*time.Millisecond)
+		assert.LessOrEqual(t, wait, 1500*time.Millisecond)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	_, err := New("test-snapshot", nil)
+	require.NoError(t, err)
+
+	var names []string
+	for _, health := range Snapshot() {
+		names = append(names, health.Name)
+	}
+	assert.Contains(t, names, "test-snapshot")
+	assert.IsIncreasing(t, names)
+}
+
+func TestGo_RecoversPanics(t *testing.T) {
+	done := make(chan struct{})
+	Go("test-go", func() {
+		defer close(done)
+		panic("boom")
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("goroutine did not finish")
+	}
+}
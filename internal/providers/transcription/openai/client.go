@@ -0,0 +1,153 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid OpenAI transcription configuration")
+	ErrAudioTooLarge = errors.New("recording is too large to transcribe")
+)
+
+const (
+	// DefaultAPIURL is the default OpenAI API URL
+	DefaultAPIURL = "https://api.openai.com/v1"
+	// DefaultTimeout is the default timeout for HTTP requests, long recordings take minutes to transcribe
+	DefaultTimeout = 5 * time.Minute
+)
+
+// transcriptionResponse is the JSON response of the transcription endpoint
+type transcriptionResponse struct {
+	Text string `json:"text"`
+}
+
+// Client implements the TranscriptionProvider interface with the OpenAI audio transcription API
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	baseURL    string
+	maxBytes   int64
+}
+
+// NewClient creates a new OpenAI transcription client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	baseURL := DefaultAPIURL
+	if strings.TrimSpace(config.BaseURL) != "" {
+		baseURL = strings.TrimRight(config.BaseURL, "/")
+	}
+
+	maxBytes := config.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = MaxAudioBytes
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		maxBytes:   maxBytes,
+	}, nil
+}
+
+// Transcribe uploads a recording and returns its transcript. Recordings over the size limit fail with
+// ErrAudioTooLarge without being uploaded.
+func (c *Client) Transcribe(ctx context.Context, name string, audio io.Reader) (string, error) {
+	body, contentType, err := c.form(name, audio)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	if c.config.Organization != "" {
+		req.Header.Set("OpenAI-Organization", c.config.Organization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+
+	var result transcriptionResponse
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// form encodes the recording and the transcription options as a multipart form
+func (c *Client) form(name string, audio io.Reader) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", path.Base(name))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create form: %w", err)
+	}
+	n, err := io.Copy(part, io.LimitReader(audio, c.maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read recording: %w", err)
+	}
+	if n > c.maxBytes {
+		return nil, "", fmt.Errorf("%w: over %d bytes", ErrAudioTooLarge, c.maxBytes)
+	}
+
+	fields := [][2]string{
+		{"model", c.config.Model},
+		{"response_format", "json"},
+		{"language", c.config.Language},
+		{"prompt", c.config.Prompt},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return nil, "", fmt.Errorf("failed to create form: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to create form: %w", err)
+	}
+	return body, writer.FormDataContentType(), nil
+}
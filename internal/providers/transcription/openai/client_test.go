@@ -0,0 +1,69 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/transcriptions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test123", r.Header.Get("Authorization"))
+
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "whisper-1", r.FormValue("model"))
+		assert.Equal(t, "en", r.FormValue("language"))
+		assert.Empty(t, r.FormValue("prompt"))
+
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+		audio, _ := io.ReadAll(file)
+		assert.Equal(t, "clip.m4a", header.Filename)
+		assert.Equal(t, "audio-bytes", string(audio))
+
+		w.Write([]byte(`{"text": " We decided to ship on Friday. "}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig("sk-test123")
+	config.BaseURL = server.URL
+	config.Language = "en"
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	text, err := client.Transcribe(context.Background(), "clip.m4a", strings.NewReader("audio-bytes"))
+	require.NoError(t, err)
+	assert.Equal(t, "We decided to ship on Friday.", text)
+}
+
+func TestClient_Transcribe_Errors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"message": "Invalid file format."}}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig("sk-test123")
+	config.BaseURL = server.URL
+	config.MaxBytes = 8
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	_, err = client.Transcribe(context.Background(), "huddle.mp4", strings.NewReader("longer than eight bytes"))
+	assert.ErrorIs(t, err, ErrAudioTooLarge)
+	assert.Zero(t, requests)
+
+	_, err = client.Transcribe(context.Background(), "clip.txt", strings.NewReader("short"))
+	assert.ErrorContains(t, err, "unexpected status code: 400")
+	assert.Equal(t, 1, requests)
+}
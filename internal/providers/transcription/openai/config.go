@@ -0,0 +1,92 @@
+package openai
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingAPIKey     = errors.New("OpenAI API key is required")
+	ErrMissingModelName  = errors.New("model name is required")
+	ErrInvalidBaseURL    = errors.New("base URL must be an absolute http or https URL")
+	ErrInvalidMaxBytes   = errors.New("max audio size cannot be negative")
+	ErrMaxBytesOverLimit = errors.New("max audio size exceeds the API limit of 25MB")
+)
+
+const (
+	// DefaultModel is the transcription model used when none is configured
+	DefaultModel = "whisper-1"
+	// MaxAudioBytes is the largest upload the transcription API accepts
+	MaxAudioBytes = 25 << 20
+)
+
+// Config contains the settings of the OpenAI transcription API
+type Config struct {
+	// APIKey is the OpenAI API key
+	APIKey string
+
+	// BaseURL is the custom API endpoint (optional, uses OpenAI default if empty)
+	BaseURL string
+
+	// Model is the transcription model, like "whisper-1" or "gpt-4o-transcribe" (default: DefaultModel)
+	Model string
+
+	// Language is the ISO-639-1 code of the spoken language, it improves accuracy and latency (optional)
+	Language string
+
+	// Prompt guides the spelling of names and jargon, like product and team names (optional)
+	Prompt string
+
+	// Organization is the OpenAI organization ID (optional)
+	Organization string
+
+	// MaxBytes bounds the size of uploaded recordings (default: MaxAudioBytes)
+	MaxBytes int64
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewDefaultConfig creates a Config with default values
+func NewDefaultConfig(apiKey string) *Config {
+	return &Config{
+		APIKey: apiKey,
+		Model:  DefaultModel,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.APIKey) == "" {
+		return ErrMissingAPIKey
+	}
+
+	if strings.TrimSpace(c.Model) == "" {
+		return ErrMissingModelName
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidBaseURL
+		}
+	}
+
+	if c.MaxBytes < 0 {
+		return ErrInvalidMaxBytes
+	}
+	if c.MaxBytes > MaxAudioBytes {
+		return ErrMaxBytesOverLimit
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "custom endpoint", mutate: func(c *Config) { c.BaseURL = "https://proxy.example.com/v1" }},
+		{name: "missing API key", mutate: func(c *Config) { c.APIKey = "" }, wantErr: ErrMissingAPIKey},
+		{name: "missing model", mutate: func(c *Config) { c.Model = " " }, wantErr: ErrMissingModelName},
+		{name: "relative base URL", mutate: func(c *Config) { c.BaseURL = "api.openai.com" }, wantErr: ErrInvalidBaseURL},
+		{name: "negative max size", mutate: func(c *Config) { c.MaxBytes = -1 }, wantErr: ErrInvalidMaxBytes},
+		{name: "max size over limit", mutate: func(c *Config) { c.MaxBytes = MaxAudioBytes + 1 }, wantErr: ErrMaxBytesOverLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewDefaultConfig("sk-test123")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
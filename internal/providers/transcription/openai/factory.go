@@ -0,0 +1,22 @@
+package openai
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures OpenAI transcription clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new OpenAI transcription client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateTranscriptionProvider creates a new OpenAI transcription client that implements the TranscriptionProvider interface
+func (f *Factory) CreateTranscriptionProvider() (ports.TranscriptionProvider, error) {
+	return NewClient(f.config)
+}
@@ -0,0 +1,145 @@
+package whispercpp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid whisper.cpp configuration")
+	ErrAudioTooLarge = errors.New("recording is too large to transcribe")
+)
+
+// DefaultTimeout is the default timeout for HTTP requests, transcribing on a CPU takes a while
+const DefaultTimeout = 10 * time.Minute
+
+// inferenceResponse is the JSON response of the inference endpoint, failures carry an error instead
+type inferenceResponse struct {
+	Text  string `json:"text"`
+	Error string `json:"error"`
+}
+
+// Client implements the TranscriptionProvider interface with a self-hosted whisper.cpp server,
+// so recordings never leave the team's infrastructure
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	baseURL    string
+	maxBytes   int64
+}
+
+// NewClient creates a new whisper.cpp client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	maxBytes := config.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBytes
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		maxBytes:   maxBytes,
+	}, nil
+}
+
+// Transcribe uploads a recording to the server's inference endpoint and returns its transcript.
+// Recordings over the size limit fail with ErrAudioTooLarge without being uploaded.
+func (c *Client) Transcribe(ctx context.Context, name string, audio io.Reader) (string, error) {
+	body, contentType, err := c.form(name, audio)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/inference", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+
+	var result inferenceResponse
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("failed to transcribe recording: %s", result.Error)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// form encodes the recording and the transcription options as a multipart form
+func (c *Client) form(name string, audio io.Reader) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", path.Base(name))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create form: %w", err)
+	}
+	n, err := io.Copy(part, io.LimitReader(audio, c.maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read recording: %w", err)
+	}
+	if n > c.maxBytes {
+		return nil, "", fmt.Errorf("%w: over %d bytes", ErrAudioTooLarge, c.maxBytes)
+	}
+
+	fields := [][2]string{
+		{"response_format", "json"},
+		{"temperature", "0.0"},
+		{"language", c.config.Language},
+		{"prompt", c.config.Prompt},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return nil, "", fmt.Errorf("failed to create form: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to create form: %w", err)
+	}
+	return body, writer.FormDataContentType(), nil
+}
@@ -0,0 +1,62 @@
+package whispercpp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/inference", r.URL.Path)
+
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "json", r.FormValue("response_format"))
+		assert.Equal(t, "Quill, Postgres", r.FormValue("prompt"))
+
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+		audio, _ := io.ReadAll(file)
+		assert.Equal(t, "clip.wav", header.Filename)
+		assert.Equal(t, "audio-bytes", string(audio))
+
+		w.Write([]byte(`{"text": "\n We move billing to Postgres.\n"}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig()
+	config.BaseURL = server.URL
+	config.Prompt = "Quill, Postgres"
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	text, err := client.Transcribe(context.Background(), "clip.wav", strings.NewReader("audio-bytes"))
+	require.NoError(t, err)
+	assert.Equal(t, "We move billing to Postgres.", text)
+}
+
+func TestClient_Transcribe_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error": "failed to read WAV file"}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig()
+	config.BaseURL = server.URL
+	config.MaxBytes = 8
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	_, err = client.Transcribe(context.Background(), "huddle.mp4", strings.NewReader("longer than eight bytes"))
+	assert.ErrorIs(t, err, ErrAudioTooLarge)
+
+	_, err = client.Transcribe(context.Background(), "clip.m4a", strings.NewReader("short"))
+	assert.ErrorContains(t, err, "failed to read WAV file")
+}
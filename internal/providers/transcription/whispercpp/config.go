@@ -0,0 +1,71 @@
+package whispercpp
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingBaseURL  = errors.New("whisper.cpp server URL is required")
+	ErrInvalidBaseURL  = errors.New("base URL must be an absolute http or https URL")
+	ErrInvalidMaxBytes = errors.New("max audio size cannot be negative")
+)
+
+const (
+	// DefaultBaseURL is the address whisper.cpp's server listens on by default
+	DefaultBaseURL = "http://127.0.0.1:8080"
+	// DefaultMaxBytes bounds uploads when no limit is configured, about an hour of compressed speech
+	DefaultMaxBytes = 100 << 20
+)
+
+// Config contains the settings of a whisper.cpp server
+type Config struct {
+	// BaseURL is the address of the whisper.cpp server
+	BaseURL string
+
+	// Language is the ISO-639-1 code of the spoken language, or "auto" to detect it (optional, server default)
+	Language string
+
+	// Prompt guides the spelling of names and jargon, like product and team names (optional)
+	Prompt string
+
+	// MaxBytes bounds the size of uploaded recordings (default: DefaultMaxBytes)
+	MaxBytes int64
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewDefaultConfig creates a Config for a server running on this host
+func NewDefaultConfig() *Config {
+	return &Config{
+		BaseURL: DefaultBaseURL,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.BaseURL) == "" {
+		return ErrMissingBaseURL
+	}
+
+	u, err := url.Parse(c.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidBaseURL
+	}
+
+	if c.MaxBytes < 0 {
+		return ErrInvalidMaxBytes
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package whispercpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "missing URL", mutate: func(c *Config) { c.BaseURL = "" }, wantErr: ErrMissingBaseURL},
+		{name: "relative URL", mutate: func(c *Config) { c.BaseURL = "whisper:8080" }, wantErr: ErrInvalidBaseURL},
+		{name: "negative max size", mutate: func(c *Config) { c.MaxBytes = -1 }, wantErr: ErrInvalidMaxBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewDefaultConfig()
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
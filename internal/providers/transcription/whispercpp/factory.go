@@ -0,0 +1,22 @@
+package whispercpp
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures whisper.cpp transcription clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new whisper.cpp transcription client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateTranscriptionProvider creates a new whisper.cpp transcription client that implements the TranscriptionProvider interface
+func (f *Factory) CreateTranscriptionProvider() (ports.TranscriptionProvider, error) {
+	return NewClient(f.config)
+}
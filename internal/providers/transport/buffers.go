@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize keeps buffers grown by unusually large responses out of the pool,
+// so a single large file does not pin its memory for the life of the process
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool recycles the buffers response bodies are read into.
+// Request bodies are not pooled: the HTTP transport may still read them after Do returns.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// GetBuffer returns an empty buffer from the pool
+func GetBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// PutBuffer returns a buffer to the pool, its content must not be used afterwards
+func PutBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// ReadBody reads a response body into a pooled buffer.
+// Release the buffer with PutBuffer once its content was decoded or copied.
+func ReadBody(r io.Reader) (*bytes.Buffer, error) {
+	buf := GetBuffer()
+	if _, err := buf.ReadFrom(r); err != nil {
+		PutBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
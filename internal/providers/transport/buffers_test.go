@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestReadBody(t *testing.T) {
+	buf, err := ReadBody(strings.NewReader(`{"ok":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, buf.String())
+	PutBuffer(buf)
+
+	// Pooled buffers come back empty
+	reused := GetBuffer()
+	assert.Zero(t, reused.Len())
+	PutBuffer(reused)
+}
+
+func TestReadBody_Error(t *testing.T) {
+	buf, err := ReadBody(failingReader{})
+	assert.Error(t, err)
+	assert.Nil(t, buf)
+}
+
+func TestPutBuffer_DropsLargeBuffers(t *testing.T) {
+	assert.NotPanics(t, func() {
+		PutBuffer(nil)
+		PutBuffer(bytes.NewBuffer(make([]byte, 0, maxPooledBufferSize+1)))
+	})
+}
+
+func BenchmarkReadBody(b *testing.B) {
+	body := strings.Repeat(`{"content":"documentation"}`, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, err := ReadBody(strings.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+		PutBuffer(buf)
+	}
+}
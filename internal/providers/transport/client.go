@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCABundle = errors.New("CA bundle contains no valid certificates")
+)
+
+// NewHTTPClient creates an HTTP client from the configuration.
+// A nil configuration uses the defaults; defaultTimeout applies when no timeout is configured.
+func NewHTTPClient(cfg *Config, defaultTimeout time.Duration) (*http.Client, error) {
+	if cfg == nil {
+		cfg = NewDefaultConfig()
+	}
+
+	rt, err := NewTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := defaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+
+	return &http.Client{
+		Transport: rt,
+		Timeout:   timeout,
+	}, nil
+}
+
+// NewTransport creates the round tripper described by the configuration
+func NewTransport(cfg *Config) (http.RoundTripper, error) {
+	if cfg == nil {
+		cfg = NewDefaultConfig()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	rt, err := newBaseTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Recording != nil {
+		return NewRecorder(*cfg.Recording, rt)
+	}
+	return rt, nil
+}
+
+// newBaseTransport creates the network transport, or returns the injected one
+func newBaseTransport(cfg *Config) (http.RoundTripper, error) {
+	if cfg.RoundTripper != nil {
+		return cfg.RoundTripper, nil
+	}
+
+	defaults := NewDefaultConfig()
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        orDefault(cfg.MaxIdleConns, defaults.MaxIdleConns),
+		MaxIdleConnsPerHost: orDefault(cfg.MaxIdleConnsPerHost, defaults.MaxIdleConnsPerHost),
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		IdleConnTimeout:     orDefault(cfg.IdleConnTimeout, defaults.IdleConnTimeout),
+		TLSHandshakeTimeout: orDefault(cfg.TLSHandshakeTimeout, defaults.TLSHandshakeTimeout),
+	}
+
+	if strings.TrimSpace(cfg.ProxyURL) != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, ErrInvalidProxyURL
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	t.TLSClientConfig = tlsConfig
+
+	return t, nil
+}
+
+// newTLSConfig builds the TLS settings, trusting the system roots plus any configured CA bundle
+func newTLSConfig(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	pem := cfg.CABundlePEM
+	if strings.TrimSpace(cfg.CABundleFile) != "" {
+		data, err := os.ReadFile(cfg.CABundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pem = data
+	}
+	if len(pem) == 0 {
+		return tlsConfig, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, ErrInvalidCABundle
+	}
+	tlsConfig.RootCAs = pool
+
+	return tlsConfig, nil
+}
+
+func orDefault[T int | time.Duration](value, fallback T) T {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr error
+	}{
+		{name: "defaults", config: NewDefaultConfig()},
+		{name: "http proxy", config: &Config{ProxyURL: "http://proxy.internal:3128"}},
+		{name: "proxy without scheme", config: &Config{ProxyURL: "proxy.internal:3128"}, wantErr: ErrInvalidProxyURL},
+		{name: "negative timeout", config: &Config{Timeout: -time.Second}, wantErr: ErrInvalidTimeout},
+		{name: "negative pool size", config: &Config{MaxIdleConns: -1}, wantErr: ErrInvalidPoolSize},
+		{
+			name:    "two CA bundles",
+			config:  &Config{CABundleFile: "ca.pem", CABundlePEM: []byte("pem")},
+			wantErr: ErrConflictingCABundle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.config.Validate())
+		})
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("nil config uses provider default timeout", func(t *testing.T) {
+		client, err := NewHTTPClient(nil, 30*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, client.Timeout)
+
+		rt, ok := client.Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.Equal(t, 10, rt.MaxIdleConnsPerHost)
+	})
+
+	t.Run("configured timeout and pool limits", func(t *testing.T) {
+		client, err := NewHTTPClient(&Config{
+			Timeout:         5 * time.Second,
+			MaxConnsPerHost: 4,
+			ProxyURL:        "http://proxy.internal:3128",
+		}, 30*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, client.Timeout)
+
+		rt := client.Transport.(*http.Transport)
+		assert.Equal(t, 4, rt.MaxConnsPerHost)
+
+		req := httptest.NewRequest(http.MethodGet, "https://api.github.com", nil)
+		proxy, err := rt.Proxy(req)
+		require.NoError(t, err)
+		assert.Equal(t, "proxy.internal:3128", proxy.Host)
+	})
+
+	t.Run("injected round tripper", func(t *testing.T) {
+		called := false
+		client, err := NewHTTPClient(&Config{
+			RoundTripper: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				called = true
+				return &http.Response{StatusCode: http.StatusTeapot, Body: http.NoBody, Request: req}, nil
+			}),
+		}, time.Second)
+		require.NoError(t, err)
+
+		resp, err := client.Get("https://example.com")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.True(t, called)
+		assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	})
+
+	t.Run("invalid CA bundle", func(t *testing.T) {
+		_, err := NewHTTPClient(&Config{CABundlePEM: []byte("not a certificate")}, time.Second)
+		assert.ErrorIs(t, err, ErrInvalidCABundle)
+	})
+
+	t.Run("custom CA bundle trusts the server", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		caPEM := encodeCertificate(server.Certificate().Raw)
+		client, err := NewHTTPClient(&Config{CABundlePEM: caPEM}, time.Second)
+		require.NoError(t, err)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
+
+func encodeCertificate(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
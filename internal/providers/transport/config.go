@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidProxyURL     = errors.New("proxy URL must be an absolute http, https or socks5 URL")
+	ErrInvalidTimeout      = errors.New("timeout cannot be negative")
+	ErrInvalidPoolSize     = errors.New("connection pool limits cannot be negative")
+	ErrConflictingCABundle = errors.New("only one of CABundleFile and CABundlePEM can be set")
+)
+
+// Config contains the HTTP transport settings shared by all provider clients
+type Config struct {
+	// ProxyURL routes requests through a proxy (optional, defaults to the HTTP(S)_PROXY environment)
+	ProxyURL string
+
+	// CABundleFile is a PEM file with additional trusted certificate authorities (optional)
+	CABundleFile string
+
+	// CABundlePEM holds additional trusted certificate authorities in PEM format (optional)
+	CABundlePEM []byte
+
+	// InsecureSkipVerify disables TLS certificate verification; only for on-prem testing
+	InsecureSkipVerify bool
+
+	// Timeout is the overall request timeout (optional, 0 uses the provider default)
+	Timeout time.Duration
+
+	// MaxIdleConns limits idle connections across all hosts (default: 100)
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits idle connections per host (default: 10)
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits the total connections per host (optional, 0 means no limit)
+	MaxConnsPerHost int
+
+	// IdleConnTimeout closes idle connections after this duration (default: 90s)
+	IdleConnTimeout time.Duration
+
+	// TLSHandshakeTimeout limits the TLS handshake (default: 10s)
+	TLSHandshakeTimeout time.Duration
+
+	// RoundTripper replaces the transport entirely, e.g. with a stub in tests (optional)
+	RoundTripper http.RoundTripper
+
+	// Recording logs, records or replays the HTTP exchanges for debugging and tests (optional)
+	Recording *RecorderConfig
+}
+
+// NewDefaultConfig creates a Config with default values
+func NewDefaultConfig() *Config {
+	return &Config{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.ProxyURL) != "" {
+		u, err := url.Parse(c.ProxyURL)
+		if err != nil || u.Host == "" {
+			return ErrInvalidProxyURL
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return ErrInvalidProxyURL
+		}
+	}
+
+	if c.Timeout < 0 || c.IdleConnTimeout < 0 || c.TLSHandshakeTimeout < 0 {
+		return ErrInvalidTimeout
+	}
+
+	if c.MaxIdleConns < 0 || c.MaxIdleConnsPerHost < 0 || c.MaxConnsPerHost < 0 {
+		return ErrInvalidPoolSize
+	}
+
+	if strings.TrimSpace(c.CABundleFile) != "" && len(c.CABundlePEM) > 0 {
+		return ErrConflictingCABundle
+	}
+
+	if c.Recording != nil {
+		if err := c.Recording.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordMode selects what the recording transport does with HTTP exchanges
+type RecordMode string
+
+const (
+	// RecordModeLog logs sanitized exchanges without storing them
+	RecordModeLog RecordMode = "log"
+	// RecordModeRecord forwards requests and appends the sanitized exchanges to the cassette
+	RecordModeRecord RecordMode = "record"
+	// RecordModeReplay serves responses from the cassette without touching the network
+	RecordModeReplay RecordMode = "replay"
+
+	redacted = "REDACTED"
+)
+
+var (
+	ErrInvalidRecordMode   = errors.New("record mode must be log, record or replay")
+	ErrMissingCassettePath = errors.New("cassette path is required to record or replay")
+	ErrNoRecordedResponse  = errors.New("no recorded response matches the request")
+)
+
+// defaultRedactedHeaders are never written to logs or cassettes
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Goog-Api-Key", "Openai-Organization"}
+
+// defaultRedactedParams are query parameters that commonly carry credentials
+var defaultRedactedParams = []string{"token", "access_token", "api_key", "key"}
+
+// RecorderConfig contains the recording transport settings
+type RecorderConfig struct {
+	// Mode selects logging, recording or replaying
+	Mode RecordMode
+
+	// CassettePath is the JSON file exchanges are recorded to or replayed from
+	CassettePath string
+
+	// RedactHeaders lists additional headers to hide
+	RedactHeaders []string
+}
+
+// Validate checks if the configuration is valid
+func (c *RecorderConfig) Validate() error {
+	switch c.Mode {
+	case RecordModeLog:
+		return nil
+	case RecordModeRecord, RecordModeReplay:
+		if strings.TrimSpace(c.CassettePath) == "" {
+			return ErrMissingCassettePath
+		}
+		return nil
+	default:
+		return ErrInvalidRecordMode
+	}
+}
+
+// RecordedRequest is the sanitized request of an exchange
+type RecordedRequest struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// RecordedResponse is the sanitized response of an exchange
+type RecordedResponse struct {
+	StatusCode int                 `json:"status_code"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+}
+
+// Interaction is a recorded HTTP exchange
+type Interaction struct {
+	Request    RecordedRequest  `json:"request"`
+	Response   RecordedResponse `json:"response"`
+	DurationMs int64            `json:"duration_ms"`
+	RecordedAt time.Time        `json:"recorded_at"`
+}
+
+// Cassette is the on-disk collection of recorded exchanges
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that logs, records or replays sanitized HTTP exchanges
+type Recorder struct {
+	config   RecorderConfig
+	next     http.RoundTripper
+	redact   map[string]bool
+	mu       sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// NewRecorder creates a recording transport in front of next.
+// In replay mode the cassette is loaded immediately and next is never called.
+func NewRecorder(cfg RecorderConfig, next http.RoundTripper) (*Recorder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	redact := make(map[string]bool)
+	for _, h := range append(defaultRedactedHeaders, cfg.RedactHeaders...) {
+		redact[http.CanonicalHeaderKey(h)] = true
+	}
+
+	r := &Recorder{
+		config:   cfg,
+		next:     next,
+		redact:   redact,
+		cassette: &Cassette{},
+	}
+
+	if cfg.Mode == RecordModeReplay {
+		cassette, err := LoadCassette(cfg.CassettePath)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.replayed = make([]bool, len(cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// LoadCassette reads recorded exchanges from disk
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+	return &cassette, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	recordedReq := RecordedRequest{
+		Method:  req.Method,
+		URL:     r.sanitizeURL(req.URL),
+		Headers: r.sanitizeHeaders(req.Header),
+		Body:    string(reqBody),
+	}
+
+	if r.config.Mode == RecordModeReplay {
+		return r.replay(req, recordedReq)
+	}
+
+	start := time.Now()
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		log.Printf("HTTP %s %s failed: %v", recordedReq.Method, recordedReq.URL, err)
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: recordedReq,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    r.sanitizeHeaders(resp.Header),
+			Body:       string(respBody),
+		},
+		DurationMs: time.Since(start).Milliseconds(),
+		RecordedAt: time.Now().UTC(),
+	}
+
+	if r.config.Mode == RecordModeLog {
+		log.Printf("HTTP %s %s -> %d (%dms)\nrequest: %s\nresponse: %s",
+			recordedReq.Method, recordedReq.URL, resp.StatusCode, interaction.DurationMs,
+			recordedReq.Body, interaction.Response.Body)
+		return resp, nil
+	}
+
+	if err := r.record(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Interactions returns the exchanges recorded or loaded so far
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interactions := make([]Interaction, len(r.cassette.Interactions))
+	copy(interactions, r.cassette.Interactions)
+	return interactions
+}
+
+// record appends the exchange and rewrites the cassette so nothing is lost if the process dies
+func (r *Recorder) record(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.config.CassettePath), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.config.CassettePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// replay returns the first unused exchange matching the method, URL and body
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] {
+			continue
+		}
+		if interaction.Request.Method != recorded.Method ||
+			interaction.Request.URL != recorded.URL ||
+			interaction.Request.Body != recorded.Body {
+			continue
+		}
+		r.replayed[i] = true
+
+		header := make(http.Header)
+		for k, v := range interaction.Response.Headers {
+			header[k] = append([]string(nil), v...)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s %s: %w", recorded.Method, recorded.URL, ErrNoRecordedResponse)
+}
+
+func (r *Recorder) sanitizeHeaders(h http.Header) map[string][]string {
+	if len(h) == 0 {
+		return nil
+	}
+
+	headers := make(map[string][]string, len(h))
+	for k, v := range h {
+		if r.redact[http.CanonicalHeaderKey(k)] {
+			headers[k] = []string{redacted}
+			continue
+		}
+		headers[k] = append([]string(nil), v...)
+	}
+	return headers
+}
+
+func (r *Recorder) sanitizeURL(u *url.URL) string {
+	sanitized := *u
+	sanitized.User = nil
+
+	query := sanitized.Query()
+	changed := false
+	for _, param := range defaultRedactedParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		sanitized.RawQuery = query.Encode()
+	}
+	return sanitized.String()
+}
+
+// readBody reads a body and replaces it so it can still be consumed
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
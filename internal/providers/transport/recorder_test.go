@@ -0,0 +1,128 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		_, _ = w.Write([]byte("echo: " + string(body)))
+	}))
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "fixtures", "echo.json")
+
+	recording, err := NewHTTPClient(&Config{
+		Recording: &RecorderConfig{Mode: RecordModeRecord, CassettePath: cassette},
+	}, time.Second)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/chat?token=abc", strings.NewReader("hello"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer sk-secret")
+
+	resp, err := recording.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "echo: hello", string(body))
+
+	data, err := os.ReadFile(cassette)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "sk-secret")
+	assert.NotContains(t, string(data), "session=secret")
+	assert.NotContains(t, string(data), "token=abc")
+
+	// The server is gone, so the response can only come from the cassette
+	server.Close()
+
+	replaying, err := NewHTTPClient(&Config{
+		Recording: &RecorderConfig{Mode: RecordModeReplay, CassettePath: cassette},
+	}, time.Second)
+	require.NoError(t, err)
+
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/chat?token=other", strings.NewReader("hello"))
+	require.NoError(t, err)
+	resp, err = replaying.Do(req)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "echo: hello", string(body))
+
+	// Each recorded exchange is replayed once
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/chat?token=abc", strings.NewReader("hello"))
+	require.NoError(t, err)
+	_, err = replaying.Do(req)
+	assert.ErrorIs(t, err, ErrNoRecordedResponse)
+}
+
+func TestRecorder_RedactsAPIKeyHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "keys.json")
+	recording, err := NewHTTPClient(&Config{
+		Recording: &RecorderConfig{Mode: RecordModeRecord, CassettePath: cassette},
+	}, time.Second)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{name: "gemini key", header: "x-goog-api-key", value: "gemini-secret"},
+		{name: "generic key", header: "X-Api-Key", value: "generic-secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/generate", strings.NewReader("{}"))
+			require.NoError(t, err)
+			req.Header.Set(tt.header, tt.value)
+
+			resp, err := recording.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.value, req.Header.Get(tt.header), "the request sent keeps its key")
+
+			data, err := os.ReadFile(cassette)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), tt.value)
+			assert.Contains(t, string(data), redacted)
+		})
+	}
+}
+
+func TestRecorderConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  RecorderConfig
+		wantErr error
+	}{
+		{name: "log", config: RecorderConfig{Mode: RecordModeLog}},
+		{name: "record", config: RecorderConfig{Mode: RecordModeRecord, CassettePath: "c.json"}},
+		{name: "replay without cassette", config: RecorderConfig{Mode: RecordModeReplay}, wantErr: ErrMissingCassettePath},
+		{name: "unknown mode", config: RecorderConfig{Mode: "tape"}, wantErr: ErrInvalidRecordMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.config.Validate())
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid Gemini configuration")
+)
+
+// DefaultTimeout is the default timeout for HTTP requests
+const DefaultTimeout = 60 * time.Second
+
+// describeImagePrompt is the instruction for reading images shared with messages
+const describeImagePrompt = `You are an image reader for a knowledge management system. The image was shared in a team conversation, like a screenshot, a diagram, or a photo of a whiteboard. Describe it so it can be documented as text.
+
+Rules:
+1. Transcribe all legible text verbatim, keeping its structure like lists and tables
+2. Describe diagrams by their elements and connections, like "API -> queue -> worker", and what they show
+3. Summarize charts by their axes, trends and notable values
+4. Do not guess at illegible text or describe decoration, and keep the description concise`
+
+// Client implements the ImageDescriber interface with Gemini's multimodal models
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient creates a new Gemini client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		baseURL:    baseURL,
+	}, nil
+}
+
+// DataLocation reports that Gemini processes images in Google's cloud
+func (c *Client) DataLocation() domain.DataLocation {
+	return domain.DataLocationCloud
+}
+
+// DescribeImage returns the text in an image and a description of its diagrams and charts
+func (c *Client) DescribeImage(ctx context.Context, mimeType string, image []byte) (string, error) {
+	if len(image) == 0 {
+		return "", fmt.Errorf("image cannot be empty")
+	}
+
+	request := generateContentRequest{
+		SystemInstruction: &content{Parts: []part{{Text: describeImagePrompt}}},
+		Contents: []content{{
+			Role: "user",
+			Parts: []part{{InlineData: &inlineData{
+				MimeType: mimeType,
+				Data:     base64.StdEncoding.EncodeToString(image),
+			}}},
+		}},
+	}
+	if c.config.MaxOutputTokens > 0 {
+		request.GenerationConfig = &generationConfig{MaxOutputTokens: c.config.MaxOutputTokens}
+	}
+
+	var response generateContentResponse
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", c.baseURL, url.PathEscape(c.config.Model))
+	if err := c.postJSON(ctx, endpoint, request, &response); err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	if reason := response.PromptFeedback.BlockReason; reason != "" {
+		return "", fmt.Errorf("image was blocked: %s", reason)
+	}
+	if len(response.Candidates) == 0 {
+		return "", fmt.Errorf("no candidates returned")
+	}
+
+	var b strings.Builder
+	for _, p := range response.Candidates[0].Content.Parts {
+		b.WriteString(p.Text)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// postJSON sends a JSON request and decodes the JSON response
+func (c *Client) postJSON(ctx context.Context, endpoint string, request interface{}, response interface{}) error {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.config.APIKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+	if err := json.Unmarshal(buf.Bytes(), response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DescribeImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/models/gemini-2.0-flash:generateContent", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-goog-api-key"))
+
+		var request generateContentRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		require.Len(t, request.Contents, 1)
+		require.NotNil(t, request.Contents[0].Parts[0].InlineData)
+		assert.Equal(t, "image/jpeg", request.Contents[0].Parts[0].InlineData.MimeType)
+		assert.Equal(t, "anBn", request.Contents[0].Parts[0].InlineData.Data)
+
+		w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "Text: Q3 goals\n"}, {"text": "- Ship billing"}]}}]}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig("test-key")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	description, err := client.DescribeImage(context.Background(), "image/jpeg", []byte("jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "Text: Q3 goals\n- Ship billing", description)
+}
+
+func TestClient_DescribeImage_Blocked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"promptFeedback": {"blockReason": "SAFETY"}}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig("test-key")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	_, err = client.DescribeImage(context.Background(), "image/png", []byte("png"))
+	assert.ErrorContains(t, err, "image was blocked: SAFETY")
+}
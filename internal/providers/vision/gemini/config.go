@@ -0,0 +1,75 @@
+package gemini
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingAPIKey    = errors.New("Gemini API key is required")
+	ErrMissingModelName = errors.New("model name is required")
+	ErrInvalidBaseURL   = errors.New("base URL must be an absolute http or https URL")
+)
+
+const (
+	// DefaultBaseURL is the address of the Gemini API
+	DefaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	// DefaultModel is a fast multimodal model, good at reading screenshots and diagrams
+	DefaultModel = "gemini-2.0-flash"
+)
+
+// Config contains the settings of the Gemini API
+type Config struct {
+	// APIKey is a Gemini API key from Google AI Studio
+	APIKey string
+
+	// Model is the multimodal model reading images (default: DefaultModel)
+	Model string
+
+	// BaseURL is the API endpoint (optional, defaults to DefaultBaseURL)
+	BaseURL string
+
+	// MaxOutputTokens bounds the length of a description (optional, model default)
+	MaxOutputTokens int
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewDefaultConfig creates a Config with default values
+func NewDefaultConfig(apiKey string) *Config {
+	return &Config{
+		APIKey:  apiKey,
+		Model:   DefaultModel,
+		BaseURL: DefaultBaseURL,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.APIKey) == "" {
+		return ErrMissingAPIKey
+	}
+
+	if strings.TrimSpace(c.Model) == "" {
+		return ErrMissingModelName
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidBaseURL
+		}
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
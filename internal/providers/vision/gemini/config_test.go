@@ -0,0 +1,35 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "default endpoint", mutate: func(c *Config) { c.BaseURL = "" }},
+		{name: "missing API key", mutate: func(c *Config) { c.APIKey = " " }, wantErr: ErrMissingAPIKey},
+		{name: "missing model", mutate: func(c *Config) { c.Model = "" }, wantErr: ErrMissingModelName},
+		{name: "relative base URL", mutate: func(c *Config) { c.BaseURL = "generativelanguage.googleapis.com" }, wantErr: ErrInvalidBaseURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewDefaultConfig("test-key")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
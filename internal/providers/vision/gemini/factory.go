@@ -0,0 +1,22 @@
+package gemini
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures Gemini clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new Gemini client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateImageDescriber creates a new Gemini client that implements the ImageDescriber interface
+func (f *Factory) CreateImageDescriber() (ports.ImageDescriber, error) {
+	return NewClient(f.config)
+}
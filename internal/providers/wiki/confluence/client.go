@@ -0,0 +1,164 @@
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid Confluence configuration")
+)
+
+const (
+	// DefaultTimeout is the default timeout for HTTP requests
+	DefaultTimeout = 30 * time.Second
+	// pageSize is the number of pages read per request, Confluence Cloud caps expanded bodies at 50
+	pageSize = 50
+	// maxRequests bounds the requests for one space, enough for 10000 pages
+	maxRequests = 200
+	// expand asks for the fields of a page the import keeps
+	expand = "body.storage,ancestors,version,history,metadata.labels"
+)
+
+// Client implements the Wiki interface for Confluence, reading the current pages of a space
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient creates a new Confluence client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+	}, nil
+}
+
+// Source returns the kind of knowledge base the client reads
+func (c *Client) Source() domain.WikiSource {
+	return domain.WikiSourceConfluence
+}
+
+// Pages returns the current pages of the space with their content converted to Markdown. Blog posts, drafts and
+// archived pages are not imported.
+func (c *Client) Pages(ctx context.Context) ([]*domain.WikiPage, error) {
+	var pages []*domain.WikiPage
+	for request, start := 0, 0; request < maxRequests; request++ {
+		params := url.Values{
+			"spaceKey": {c.config.SpaceKey},
+			"type":     {"page"},
+			"status":   {"current"},
+			"expand":   {expand},
+			"start":    {strconv.Itoa(start)},
+			"limit":    {strconv.Itoa(pageSize)},
+		}
+
+		var resp contentList
+		if err := c.get(ctx, "/rest/api/content", params, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list the pages of space %s: %w", c.config.SpaceKey, err)
+		}
+		for _, result := range resp.Results {
+			page, err := c.page(result, resp.Links.Base)
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, page)
+		}
+
+		if resp.Links.Next == "" || len(resp.Results) == 0 {
+			return pages, nil
+		}
+		start += len(resp.Results)
+	}
+	return pages, nil
+}
+
+// page converts a page of the space
+func (c *Client) page(result content, base string) (*domain.WikiPage, error) {
+	if base == "" {
+		base = c.baseURL
+	}
+	body, err := renderer{baseURL: base, pageID: result.ID}.markdown(result.Body.Storage.Value)
+	if err != nil {
+		return nil, fmt.Errorf("page %s: %w", result.ID, err)
+	}
+
+	page, err := domain.NewWikiPage(domain.WikiSourceConfluence, result.ID, result.parentID(), result.Title, body)
+	if err != nil {
+		return nil, err
+	}
+	if result.Links.WebUI != "" {
+		page.SetLink(base + result.Links.WebUI)
+	}
+	page.SetAuthor(result.History.CreatedBy.DisplayName)
+	page.SetTimes(parseTime(result.History.CreatedDate), parseTime(result.Version.When))
+	page.SetLabels(result.labels())
+	return page, nil
+}
+
+// get sends a GET request to the Confluence REST API and decodes the response
+func (c *Client) get(ctx context.Context, path string, params url.Values, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.config.PersonalAccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.PersonalAccessToken)
+	} else {
+		req.SetBasicAuth(c.config.Email, c.config.APIToken)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+	if err := json.Unmarshal(buf.Bytes(), response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// parseTime parses a time of the API, zero when it is missing
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
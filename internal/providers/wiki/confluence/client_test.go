@@ -0,0 +1,94 @@
+package confluence
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Pages(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"results":[
+			{"id":"100","title":"Engineering","ancestors":[],
+				"body":{"storage":{"value":"<p>Start here.</p>"}},
+				"version":{"when":"2024-05-02T10:30:00.000Z"},
+				"history":{"createdDate":"2023-03-01T09:00:00.000Z","createdBy":{"displayName":"Alice"}},
+				"metadata":{"labels":{"results":[{"name":"handbook"}]}},
+				"_links":{"webui":"/spaces/ENG/pages/100/Engineering"}}
+		],"start":0,"limit":1,"size":1,"_links":{"base":"https://acme.atlassian.net/wiki","next":"/rest/api/content?start=1"}}`,
+		"1": `{"results":[
+			{"id":"101","title":"Release Process","ancestors":[{"id":"99"},{"id":"100"}],
+				"body":{"storage":{"value":"<h2>Steps</h2><p><ac:image><ri:attachment ri:filename=\"flow.png\" /></ac:image></p>"}},
+				"version":{"when":"2024-06-01T08:00:00.000Z"},
+				"history":{"createdDate":"2023-04-01T09:00:00.000Z","createdBy":{"displayName":"Bob"}},
+				"_links":{"webui":"/spaces/ENG/pages/101/Release+Process"}}
+		],"start":1,"limit":1,"size":1,"_links":{"base":"https://acme.atlassian.net/wiki"}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "alice@example.com", user)
+		assert.Equal(t, "token", password)
+		assert.Equal(t, "/wiki/rest/api/content", r.URL.Path)
+		query := r.URL.Query()
+		assert.Equal(t, "ENG", query.Get("spaceKey"))
+		assert.Equal(t, "page", query.Get("type"))
+		assert.Equal(t, expand, query.Get("expand"))
+
+		body, ok := responses[query.Get("start")]
+		require.True(t, ok)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(NewConfig(server.URL+"/wiki", "ENG", "alice@example.com", "token"))
+	require.NoError(t, err)
+
+	pages, err := client.Pages(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, pages, 2)
+	assert.Equal(t, "100", pages[0].ID())
+	assert.Empty(t, pages[0].ParentID())
+	assert.Equal(t, "Engineering", pages[0].Title())
+	assert.Equal(t, "Start here.", pages[0].Body())
+	assert.Equal(t, "Alice", pages[0].Author())
+	assert.Equal(t, "https://acme.atlassian.net/wiki/spaces/ENG/pages/100/Engineering", pages[0].Link())
+	assert.True(t, pages[0].CreatedAt().Equal(time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, pages[0].UpdatedAt().Equal(time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "handbook", pages[0].Labels()[0].String())
+
+	assert.Equal(t, "101", pages[1].ID())
+	assert.Equal(t, "100", pages[1].ParentID())
+	assert.Equal(t, "### Steps\n\n![flow.png](https://acme.atlassian.net/wiki/download/attachments/101/flow.png)", pages[1].Body())
+}
+
+func TestClient_Pages_PersonalAccessToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"results":[],"_links":{}}`))
+	}))
+	defer server.Close()
+
+	config := NewConfig(server.URL, "ENG", "", "")
+	config.PersonalAccessToken = "pat"
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	pages, err := client.Pages(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pages)
+
+	config.PersonalAccessToken = "expired"
+	_, err = client.Pages(context.Background())
+	assert.ErrorContains(t, err, "unexpected status code: 401")
+}
@@ -0,0 +1,75 @@
+package confluence
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingBaseURL     = errors.New("base URL is required")
+	ErrInvalidBaseURL     = errors.New("base URL must be an absolute http or https URL")
+	ErrMissingSpaceKey    = errors.New("space key is required")
+	ErrMissingCredentials = errors.New("an email and API token, or a personal access token, is required")
+)
+
+// Config contains the settings of a Confluence space to import pages from
+type Config struct {
+	// BaseURL is the address of the Confluence site, like https://acme.atlassian.net/wiki for Confluence Cloud
+	// or https://confluence.example.com for Confluence Data Center
+	BaseURL string
+
+	// SpaceKey is the key of the space whose pages are imported, like ENG
+	SpaceKey string
+
+	// Email and APIToken authenticate to Confluence Cloud
+	Email    string
+	APIToken string
+
+	// PersonalAccessToken authenticates to Confluence Data Center, it takes precedence over Email and APIToken
+	// (optional)
+	PersonalAccessToken string
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewConfig creates a new Confluence Cloud configuration importing a space
+func NewConfig(baseURL, spaceKey, email, apiToken string) *Config {
+	return &Config{
+		BaseURL:  baseURL,
+		SpaceKey: spaceKey,
+		Email:    email,
+		APIToken: apiToken,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.BaseURL) == "" {
+		return ErrMissingBaseURL
+	}
+	u, err := url.Parse(c.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidBaseURL
+	}
+
+	if strings.TrimSpace(c.SpaceKey) == "" {
+		return ErrMissingSpaceKey
+	}
+
+	basic := strings.TrimSpace(c.Email) != "" && strings.TrimSpace(c.APIToken) != ""
+	if !basic && strings.TrimSpace(c.PersonalAccessToken) == "" {
+		return ErrMissingCredentials
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
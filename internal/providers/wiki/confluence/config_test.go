@@ -0,0 +1,42 @@
+package confluence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{
+			name: "personal access token",
+			mutate: func(c *Config) {
+				c.Email, c.APIToken = "", ""
+				c.PersonalAccessToken = "pat"
+			},
+		},
+		{name: "missing base URL", mutate: func(c *Config) { c.BaseURL = "" }, wantErr: ErrMissingBaseURL},
+		{name: "relative base URL", mutate: func(c *Config) { c.BaseURL = "acme.atlassian.net/wiki" }, wantErr: ErrInvalidBaseURL},
+		{name: "missing space", mutate: func(c *Config) { c.SpaceKey = " " }, wantErr: ErrMissingSpaceKey},
+		{name: "missing API token", mutate: func(c *Config) { c.APIToken = "" }, wantErr: ErrMissingCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig("https://acme.atlassian.net/wiki", "ENG", "alice@example.com", "token")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package confluence
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures Confluence clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new Confluence client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateWiki creates a new Confluence client that implements the Wiki interface
+func (f *Factory) CreateWiki() (ports.Wiki, error) {
+	return NewClient(f.config)
+}
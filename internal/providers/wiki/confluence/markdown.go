@@ -0,0 +1,512 @@
+package confluence
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// node is an element or a run of text of a page in the Confluence storage format
+type node struct {
+	// name is the element's name with its prefix, like p or ac:structured-macro, empty for text
+	name     string
+	attrs    map[string]string
+	children []*node
+	text     string
+}
+
+// voidElements are the HTML elements without content that pages written by other tools may leave open
+var voidElements = []string{"br", "hr", "img", "col", "wbr"}
+
+// parseStorage parses a page in the storage format, XHTML with Confluence's ac: and ri: elements
+func parseStorage(storage string) (*node, error) {
+	dec := xml.NewDecoder(strings.NewReader("<storage>" + storage + "</storage>"))
+	dec.Strict = false
+	// Not xml.HTMLAutoClose, it would close ac:link and ac:parameter as if they were HTML's void elements
+	dec.AutoClose = voidElements
+	dec.Entity = xml.HTMLEntity
+
+	// The first token starts the wrapping element, root stands for it
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("failed to parse page content: %w", err)
+	}
+	root := &node{name: "storage"}
+	stack := []*node{root}
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return root, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse page content: %w", err)
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			el := &node{name: qualified(t.Name), attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				el.attrs[qualified(attr.Name)] = attr.Value
+			}
+			parent.children = append(parent.children, el)
+			stack = append(stack, el)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			parent.children = append(parent.children, &node{text: string(t)})
+		}
+	}
+}
+
+// qualified returns a name with its prefix, undeclared prefixes like ac: are kept as the name's space
+func qualified(name xml.Name) string {
+	if name.Space == "" {
+		return strings.ToLower(name.Local)
+	}
+	return name.Space + ":" + name.Local
+}
+
+// child returns the first child element with the name
+func (n *node) child(name string) *node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// textContent returns the text of a node and all its descendants
+func (n *node) textContent() string {
+	if n.name == "" {
+		return n.text
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(c.textContent())
+	}
+	return b.String()
+}
+
+// parameter returns the value of a macro parameter
+func (n *node) parameter(name string) string {
+	for _, c := range n.children {
+		if c.name == "ac:parameter" && c.attrs["ac:name"] == name {
+			return strings.TrimSpace(c.textContent())
+		}
+	}
+	return ""
+}
+
+// renderer converts the storage format of a page to Markdown. Attachments link to where Confluence serves them.
+type renderer struct {
+	baseURL string
+	pageID  string
+}
+
+// part is a rendered block, lists are told apart as they nest in list items without a blank line
+type part struct {
+	text string
+	list bool
+}
+
+// markdown converts a page in the storage format to Markdown. Headings move one level down, as the page's title is
+// the document's first heading.
+func (r renderer) markdown(storage string) (string, error) {
+	root, err := parseStorage(storage)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(join(r.blocks(root, ""))), nil
+}
+
+// blocks renders the children of an element as blocks, runs of text and inline elements become paragraphs
+func (r renderer) blocks(n *node, indent string) []part {
+	var parts []part
+	var inline strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(inline.String()); text != "" {
+			parts = append(parts, part{text: indent + strings.ReplaceAll(text, "\n", "\n"+indent)})
+		}
+		inline.Reset()
+	}
+
+	for _, c := range n.children {
+		if !isBlock(c) {
+			inline.WriteString(r.inline(c))
+			continue
+		}
+		flush()
+		parts = append(parts, r.block(c, indent)...)
+	}
+	flush()
+	return parts
+}
+
+// block renders a block element
+func (r renderer) block(n *node, indent string) []part {
+	switch n.name {
+	case "p":
+		return paragraph(indent, r.inlineChildren(n))
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(n.name[1:])
+		return paragraph(indent, strings.Repeat("#", min(level+1, 6))+" "+strings.TrimSpace(r.inlineChildren(n)))
+	case "ul", "ol":
+		return []part{{text: r.list(n, indent, n.name == "ol"), list: true}}
+	case "ac:task-list":
+		return []part{{text: r.tasks(n, indent), list: true}}
+	case "pre":
+		return []part{{text: fence(indent, "", n.textContent())}}
+	case "blockquote":
+		return []part{{text: quote(indent, join(r.blocks(n, "")))}}
+	case "hr":
+		return []part{{text: indent + "---"}}
+	case "table":
+		return []part{{text: r.table(n, indent)}}
+	case "ac:structured-macro":
+		return r.macro(n, indent)
+	}
+	return r.blocks(n, indent)
+}
+
+// macro renders the macros that hold content, macros listing other content are rendered by Confluence and left out
+func (r renderer) macro(n *node, indent string) []part {
+	switch n.attrs["ac:name"] {
+	case "code", "noformat":
+		body := ""
+		if plain := n.child("ac:plain-text-body"); plain != nil {
+			body = plain.textContent()
+		}
+		return []part{{text: fence(indent, n.parameter("language"), body)}}
+	case "info", "note", "tip", "warning", "panel":
+		content := r.richTextBody(n)
+		if title := n.parameter("title"); title != "" {
+			content = strings.TrimSpace("**" + title + "**\n\n" + content)
+		}
+		if content == "" {
+			return nil
+		}
+		return []part{{text: quote(indent, content)}}
+	case "expand":
+		var parts []part
+		if title := n.parameter("title"); title != "" {
+			parts = paragraph(indent, "**"+title+"**")
+		}
+		if body := n.child("ac:rich-text-body"); body != nil {
+			parts = append(parts, r.blocks(body, indent)...)
+		}
+		return parts
+	case "toc", "children", "pagetree", "recently-updated", "contentbylabel", "jira":
+		return nil
+	}
+	if body := n.child("ac:rich-text-body"); body != nil {
+		return r.blocks(body, indent)
+	}
+	return nil
+}
+
+// richTextBody renders the content of a macro
+func (r renderer) richTextBody(n *node) string {
+	body := n.child("ac:rich-text-body")
+	if body == nil {
+		return ""
+	}
+	return join(r.blocks(body, ""))
+}
+
+// list renders a list, its items' nested lists are indented under them
+func (r renderer) list(n *node, indent string, ordered bool) string {
+	marker := "- "
+	if ordered {
+		marker = "1. "
+	}
+	var items []string
+	for _, li := range n.children {
+		if li.name != "li" {
+			continue
+		}
+		items = append(items, item(indent, marker, r.blocks(li, indent+strings.Repeat(" ", len(marker)))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// tasks renders a task list as a list of checkboxes
+func (r renderer) tasks(n *node, indent string) string {
+	var items []string
+	for _, task := range n.children {
+		if task.name != "ac:task" {
+			continue
+		}
+		marker := "- [ ] "
+		if status := task.child("ac:task-status"); status != nil && strings.TrimSpace(status.textContent()) == "complete" {
+			marker = "- [x] "
+		}
+		var parts []part
+		if body := task.child("ac:task-body"); body != nil {
+			parts = r.blocks(body, indent+strings.Repeat(" ", len(marker)))
+		}
+		items = append(items, item(indent, marker, parts))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders a table, its first row is the header
+func (r renderer) table(n *node, indent string) string {
+	var rows [][]string
+	var collect func(n *node)
+	collect = func(n *node) {
+		for _, c := range n.children {
+			switch c.name {
+			case "tr":
+				var cells []string
+				for _, cell := range c.children {
+					if cell.name != "th" && cell.name != "td" {
+						continue
+					}
+					text := strings.Join(strings.Fields(join(r.blocks(cell, ""))), " ")
+					cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+				}
+				rows = append(rows, cells)
+			case "thead", "tbody", "tfoot":
+				collect(c)
+			}
+		}
+	}
+	collect(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	var b strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + "|")
+		for col := 0; col < width; col++ {
+			cell := ""
+			if col < len(row) {
+				cell = row[col]
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		if i == 0 {
+			b.WriteString("\n" + indent + "|" + strings.Repeat(" --- |", width))
+		}
+	}
+	return b.String()
+}
+
+// inline renders an inline element or a run of text
+func (r renderer) inline(n *node) string {
+	switch n.name {
+	case "":
+		return collapseSpace(n.text)
+	case "strong", "b":
+		return wrap("**", r.inlineChildren(n))
+	case "em", "i":
+		return wrap("_", r.inlineChildren(n))
+	case "s", "del", "strike":
+		return wrap("~~", r.inlineChildren(n))
+	case "code":
+		return wrap("`", n.textContent())
+	case "br":
+		return "\\\n"
+	case "a":
+		text := strings.TrimSpace(r.inlineChildren(n))
+		href := n.attrs["href"]
+		if href == "" {
+			return text
+		}
+		if text == "" {
+			text = href
+		}
+		return "[" + text + "](" + href + ")"
+	case "time":
+		return n.attrs["datetime"]
+	case "ac:image":
+		return r.image(n)
+	case "ac:link":
+		return r.link(n)
+	case "ac:emoticon":
+		return n.attrs["ac:emoji-fallback"]
+	case "ac:structured-macro":
+		if n.attrs["ac:name"] == "status" {
+			return wrap("`", n.parameter("title"))
+		}
+		return ""
+	}
+	return r.inlineChildren(n)
+}
+
+// inlineChildren renders the children of an element as inline content
+func (r renderer) inlineChildren(n *node) string {
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(r.inline(c))
+	}
+	return b.String()
+}
+
+// image renders an image, attached to the page or hosted elsewhere
+func (r renderer) image(n *node) string {
+	var src, name string
+	if attachment := n.child("ri:attachment"); attachment != nil {
+		name = attachment.attrs["ri:filename"]
+		src = r.attachmentURL(name)
+	} else if external := n.child("ri:url"); external != nil {
+		src = external.attrs["ri:value"]
+	}
+	if src == "" {
+		return ""
+	}
+	alt := n.attrs["ac:alt"]
+	if alt == "" {
+		alt = name
+	}
+	return "![" + alt + "](" + src + ")"
+}
+
+// link renders a link to another page or an attachment. Links to pages keep their text, the page they point to
+// may not be imported.
+func (r renderer) link(n *node) string {
+	text := ""
+	if body := n.child("ac:link-body"); body != nil {
+		text = strings.TrimSpace(r.inlineChildren(body))
+	} else if body := n.child("ac:plain-text-link-body"); body != nil {
+		text = strings.TrimSpace(body.textContent())
+	}
+
+	if attachment := n.child("ri:attachment"); attachment != nil {
+		name := attachment.attrs["ri:filename"]
+		if text == "" {
+			text = name
+		}
+		return "[" + text + "](" + r.attachmentURL(name) + ")"
+	}
+	if text == "" {
+		if page := n.child("ri:page"); page != nil {
+			text = page.attrs["ri:content-title"]
+		}
+	}
+	return text
+}
+
+// attachmentURL returns where Confluence serves a file attached to the page
+func (r renderer) attachmentURL(name string) string {
+	return strings.TrimRight(r.baseURL, "/") + "/download/attachments/" + url.PathEscape(r.pageID) + "/" + url.PathEscape(name)
+}
+
+// isBlock checks if a node is rendered as a block of its own
+func isBlock(n *node) bool {
+	switch n.name {
+	case "p", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "pre", "blockquote", "hr", "table", "div", "section",
+		"ac:task-list", "ac:layout", "ac:layout-section", "ac:layout-cell", "ac:rich-text-body":
+		return true
+	case "ac:structured-macro":
+		return n.attrs["ac:name"] != "status"
+	}
+	return false
+}
+
+// paragraph returns a paragraph, none when it has no text
+func paragraph(indent, text string) []part {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.Trim(text, "#* ") == "" {
+		return nil
+	}
+	return []part{{text: indent + strings.ReplaceAll(text, "\n", "\n"+indent)}}
+}
+
+// item renders a list item from the parts of its content, rendered indented under the marker
+func item(indent, marker string, parts []part) string {
+	pad := indent + strings.Repeat(" ", len(marker))
+	if len(parts) == 0 {
+		return strings.TrimRight(indent+marker, " ")
+	}
+	first := parts[0]
+	rendered := indent + marker + strings.TrimPrefix(first.text, pad)
+	if first.list {
+		rendered = strings.TrimRight(indent+marker, " ") + "\n" + first.text
+	}
+	for _, p := range parts[1:] {
+		if p.list {
+			rendered += "\n" + p.text
+		} else {
+			// Paragraphs of an item are set apart, they would run on the previous one otherwise
+			rendered += "\n\n" + p.text
+		}
+	}
+	return rendered
+}
+
+// join separates blocks with blank lines
+func join(parts []part) string {
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p.text != "" {
+			texts = append(texts, p.text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// fence renders a fenced code block
+func fence(indent, language, code string) string {
+	code = strings.Trim(code, "\n")
+	block := "```" + language + "\n" + code + "\n```"
+	return indent + strings.ReplaceAll(block, "\n", "\n"+indent)
+}
+
+// quote renders a block quote
+func quote(indent, content string) string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(indent+"> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap puts formatting markers around text, outside its leading and trailing spaces which Markdown would not
+// format
+func wrap(marker, text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, trimmed)]
+	trail := text[len(lead)+len(trimmed):]
+	return lead + marker + trimmed + marker + trail
+}
+
+// collapseSpace turns runs of whitespace, like the line breaks of the XHTML source, into single spaces
+func collapseSpace(text string) string {
+	if strings.TrimSpace(text) == "" {
+		if text == "" {
+			return ""
+		}
+		return " "
+	}
+	collapsed := strings.Join(strings.Fields(text), " ")
+	if strings.TrimLeft(text, " \t\r\n") != text {
+		collapsed = " " + collapsed
+	}
+	if strings.TrimRight(text, " \t\r\n") != text {
+		collapsed += " "
+	}
+	return collapsed
+}
@@ -0,0 +1,63 @@
+package confluence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_Markdown(t *testing.T) {
+	storage := `<h1>Release&nbsp;process</h1>
+<p>Releases go out on <strong>Tuesdays</strong>, see <a href="https://example.com/calendar">the calendar</a>
+and <ac:link><ri:page ri:content-title="Hotfixes" /></ac:link>.<br />Ask in <code>#releases</code>.</p>
+<ul>
+  <li>Freeze the branch
+    <ul><li>Tag it</li></ul>
+  </li>
+  <li><p>Run the pipeline</p><p>It takes <em>an hour</em>.</p></li>
+</ul>
+<ac:task-list>
+  <ac:task><ac:task-status>complete</ac:task-status><ac:task-body>Update the changelog</ac:task-body></ac:task>
+  <ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body>Announce it</ac:task-body></ac:task>
+</ac:task-list>
+<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">bash</ac:parameter>
+<ac:plain-text-body><![CDATA[make release
+make publish]]></ac:plain-text-body></ac:structured-macro>
+<ac:structured-macro ac:name="warning"><ac:parameter ac:name="title">Careful</ac:parameter>
+<ac:rich-text-body><p>Never release on Fridays.</p></ac:rich-text-body></ac:structured-macro>
+<ac:structured-macro ac:name="toc" />
+<table><tbody>
+  <tr><th>Step</th><th>Owner</th></tr>
+  <tr><td><p>Build</p></td><td>CI | CD</td></tr>
+</tbody></table>
+<p><ac:image ac:alt="Pipeline"><ri:attachment ri:filename="pipeline diagram.png" /></ac:image></p>
+<p>Status: <ac:structured-macro ac:name="status"><ac:parameter ac:name="title">DONE</ac:parameter></ac:structured-macro></p>
+<p>&nbsp;</p>`
+
+	md, err := renderer{baseURL: "https://acme.atlassian.net/wiki", pageID: "65537"}.markdown(storage)
+	require.NoError(t, err)
+
+	want := "## Release process\n\n" +
+		"Releases go out on **Tuesdays**, see [the calendar](https://example.com/calendar) and Hotfixes.\\\n" +
+		"Ask in `#releases`.\n\n" +
+		"- Freeze the branch\n" +
+		"  - Tag it\n" +
+		"- Run the pipeline\n\n" +
+		"  It takes _an hour_.\n\n" +
+		"- [x] Update the changelog\n" +
+		"- [ ] Announce it\n\n" +
+		"```bash\nmake release\nmake publish\n```\n\n" +
+		"> **Careful**\n>\n> Never release on Fridays.\n\n" +
+		"| Step | Owner |\n| --- | --- |\n| Build | CI \\| CD |\n\n" +
+		"![Pipeline](https://acme.atlassian.net/wiki/download/attachments/65537/pipeline%20diagram.png)\n\n" +
+		"Status: `DONE`"
+	assert.Equal(t, want, md)
+}
+
+func TestRenderer_Markdown_ToleratesHTML(t *testing.T) {
+	md, err := renderer{}.markdown(`<p>Line one<br>line two</p><P>Upper &amp; <b>case</b></P>`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Line one\\\nline two\n\nUpper & **case**", md)
+}
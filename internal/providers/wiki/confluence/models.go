@@ -0,0 +1,74 @@
+package confluence
+
+// contentList is a page of the content search response
+type contentList struct {
+	Results []content `json:"results"`
+	Start   int       `json:"start"`
+	Limit   int       `json:"limit"`
+	Size    int       `json:"size"`
+	Links   links     `json:"_links"`
+}
+
+// content is a page of the space, with the expanded fields the client asks for
+type content struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Ancestors []ancestor `json:"ancestors"`
+	Body      struct {
+		Storage struct {
+			Value string `json:"value"`
+		} `json:"storage"`
+	} `json:"body"`
+	Version struct {
+		When string `json:"when"`
+	} `json:"version"`
+	History struct {
+		CreatedDate string `json:"createdDate"`
+		CreatedBy   person `json:"createdBy"`
+	} `json:"history"`
+	Metadata struct {
+		Labels struct {
+			Results []label `json:"results"`
+		} `json:"labels"`
+	} `json:"metadata"`
+	Links links `json:"_links"`
+}
+
+// ancestor is a page a page is nested in, the last one is its parent
+type ancestor struct {
+	ID string `json:"id"`
+}
+
+// person is a user of the site
+type person struct {
+	DisplayName string `json:"displayName"`
+}
+
+// label is a label of a page
+type label struct {
+	Name string `json:"name"`
+}
+
+// links are the links of a response, relative to the site's base URL
+type links struct {
+	Base  string `json:"base"`
+	WebUI string `json:"webui"`
+	Next  string `json:"next"`
+}
+
+// parentID returns the ID of the page a page is nested in, empty for the top-level pages of the space
+func (c content) parentID() string {
+	if len(c.Ancestors) == 0 {
+		return ""
+	}
+	return c.Ancestors[len(c.Ancestors)-1].ID
+}
+
+// labels returns the names of the page's labels
+func (c content) labels() []string {
+	names := make([]string, len(c.Metadata.Labels.Results))
+	for i, l := range c.Metadata.Labels.Results {
+		names[i] = l.Name
+	}
+	return names
+}
@@ -0,0 +1,228 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/massimo-ua/quill/internal/domain"
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid Notion configuration")
+)
+
+const (
+	// DefaultTimeout is the default timeout for HTTP requests
+	DefaultTimeout = 30 * time.Second
+	// maxBlockPages bounds the pages of children read for one block, a page has rarely more than a few hundred blocks
+	maxBlockPages = 20
+)
+
+// Client implements the Wiki interface for Notion, reading pages only
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient creates a new Notion client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	httpClient, err := transport.NewHTTPClient(config.HTTP, DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		baseURL:    baseURL,
+	}, nil
+}
+
+// Source returns the kind of knowledge base the client reads
+func (c *Client) Source() domain.WikiSource {
+	return domain.WikiSourceNotion
+}
+
+// Pages returns the root pages and every page nested in them, with their blocks converted to Markdown.
+// Archived pages and pages in the trash are skipped along with the pages nested in them.
+func (c *Client) Pages(ctx context.Context) ([]*domain.WikiPage, error) {
+	var pages []*domain.WikiPage
+	authors := make(map[string]string)
+	seen := make(map[string]bool)
+
+	var read func(id, parentID string) error
+	read = func(id, parentID string) error {
+		if seen[id] {
+			return nil
+		}
+		seen[id] = true
+
+		var meta page
+		if err := c.get(ctx, "/pages/"+url.PathEscape(id), nil, &meta); err != nil {
+			return fmt.Errorf("failed to read page %s: %w", id, err)
+		}
+		if meta.Archived || meta.InTrash {
+			return nil
+		}
+		blocks, err := c.children(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to read the content of page %s: %w", id, err)
+		}
+
+		wikiPage, err := domain.NewWikiPage(domain.WikiSourceNotion, meta.ID, parentID, meta.title(), markdown(blocks))
+		if err != nil {
+			return err
+		}
+		wikiPage.SetLink(meta.URL)
+		wikiPage.SetAuthor(c.author(ctx, meta.CreatedBy, authors))
+		wikiPage.SetTimes(parseTime(meta.CreatedTime), parseTime(meta.LastEditedTime))
+		wikiPage.SetLabels(meta.labels())
+		pages = append(pages, wikiPage)
+
+		for _, child := range childPages(blocks) {
+			if err := read(child, meta.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, root := range c.config.RootPageIDs {
+		if root = strings.TrimSpace(root); root == "" {
+			continue
+		}
+		if err := read(root, ""); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// children reads the blocks nested in a block, and the blocks nested in those but for nested pages
+func (c *Client) children(ctx context.Context, id string) ([]block, error) {
+	var blocks []block
+	cursor := ""
+	for page := 0; page < maxBlockPages; page++ {
+		params := url.Values{"page_size": {"100"}}
+		if cursor != "" {
+			params.Set("start_cursor", cursor)
+		}
+
+		var resp blockList
+		if err := c.get(ctx, "/blocks/"+url.PathEscape(id)+"/children", params, &resp); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	for i := range blocks {
+		if !blocks[i].HasChildren || blocks[i].Type == "child_page" || blocks[i].Type == "child_database" {
+			continue
+		}
+		nested, err := c.children(ctx, blocks[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		blocks[i].children = nested
+	}
+	return blocks, nil
+}
+
+// author returns the name of the person who created a page. Integrations without the capability to read user
+// information only get the ID of people, the page is then imported without an author.
+func (c *Client) author(ctx context.Context, creator user, names map[string]string) string {
+	if creator.Name != "" || creator.ID == "" {
+		return creator.Name
+	}
+	if name, ok := names[creator.ID]; ok {
+		return name
+	}
+
+	name := ""
+	var resp user
+	if err := c.get(ctx, "/users/"+url.PathEscape(creator.ID), nil, &resp); err == nil {
+		name = resp.Name
+	}
+	names[creator.ID] = name
+	return name
+}
+
+// get sends a GET request to the Notion API and decodes the response
+func (c *Client) get(ctx context.Context, path string, params url.Values, response interface{}) error {
+	endpoint := c.baseURL + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	req.Header.Set("Notion-Version", APIVersion)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := transport.ReadBody(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	defer transport.PutBuffer(buf)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, buf.Bytes())
+	}
+	if err := json.Unmarshal(buf.Bytes(), response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// childPages returns the IDs of the pages nested in the blocks, in the order they appear
+func childPages(blocks []block) []string {
+	var ids []string
+	for _, blk := range blocks {
+		if blk.Type == "child_page" {
+			ids = append(ids, blk.ID)
+		}
+		ids = append(ids, childPages(blk.children)...)
+	}
+	return ids
+}
+
+// parseTime parses a time of the API, zero when it is missing
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,103 @@
+package notion
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Pages(t *testing.T) {
+	responses := map[string]string{
+		"/pages/handbook": `{"id":"handbook","url":"https://www.notion.so/Handbook-handbook",
+			"created_time":"2023-01-10T09:00:00.000Z","last_edited_time":"2024-02-01T10:00:00.000Z",
+			"created_by":{"object":"user","id":"u1"},
+			"properties":{"title":{"type":"title","title":[{"plain_text":"Handbook"}]}}}`,
+		"/blocks/handbook/children": `{"results":[
+			{"id":"b1","type":"paragraph","has_children":false,"paragraph":{"rich_text":[{"plain_text":"Welcome aboard."}]}},
+			{"id":"setup","type":"child_page","has_children":true,"child_page":{"title":"Setup"}}
+		],"has_more":true,"next_cursor":"c2"}`,
+		"/blocks/handbook/children?c2": `{"results":[
+			{"id":"old","type":"child_page","has_children":false,"child_page":{"title":"Old"}}
+		],"has_more":false}`,
+		"/pages/setup": `{"id":"setup","url":"https://www.notion.so/Setup-setup",
+			"created_time":"2023-01-11T09:00:00.000Z","last_edited_time":"2023-01-12T09:00:00.000Z",
+			"created_by":{"object":"user","id":"u1"},
+			"properties":{"Name":{"type":"title","title":[{"plain_text":"Setup"}]},
+				"Tags":{"type":"multi_select","multi_select":[{"name":"Onboarding"}]}}}`,
+		"/blocks/setup/children": `{"results":[
+			{"id":"b2","type":"bulleted_list_item","has_children":true,"bulleted_list_item":{"rich_text":[{"plain_text":"Laptop"}]}}
+		],"has_more":false}`,
+		"/blocks/b2/children": `{"results":[
+			{"id":"b3","type":"paragraph","has_children":false,"paragraph":{"rich_text":[{"plain_text":"Ask IT."}]}}
+		],"has_more":false}`,
+		"/pages/old": `{"id":"old","archived":true,"properties":{}}`,
+		"/users/u1":  `{"object":"user","id":"u1","name":"Alice"}`,
+	}
+	usersRead := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, APIVersion, r.Header.Get("Notion-Version"))
+
+		key := r.URL.Path
+		if cursor := r.URL.Query().Get("start_cursor"); cursor != "" {
+			key += "?" + cursor
+		}
+		if r.URL.Path == "/users/u1" {
+			usersRead++
+		}
+		body, ok := responses[key]
+		if !ok {
+			t.Errorf("unexpected request %s", key)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	config := NewConfig("secret", "handbook")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	pages, err := client.Pages(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, pages, 2)
+	assert.Equal(t, "handbook", pages[0].ID())
+	assert.Empty(t, pages[0].ParentID())
+	assert.Equal(t, "Handbook", pages[0].Title())
+	assert.Equal(t, "Welcome aboard.", pages[0].Body())
+	assert.Equal(t, "Alice", pages[0].Author())
+	assert.Equal(t, "https://www.notion.so/Handbook-handbook", pages[0].Link())
+	assert.True(t, pages[0].UpdatedAt().Equal(time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, "setup", pages[1].ID())
+	assert.Equal(t, "handbook", pages[1].ParentID())
+	assert.Equal(t, "- Laptop\n\n  Ask IT.", pages[1].Body())
+	assert.Equal(t, "onboarding", pages[1].Labels()[0].String())
+	assert.Equal(t, "Alice", pages[1].Author())
+	assert.Equal(t, 1, usersRead)
+}
+
+func TestClient_Pages_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"object":"error","code":"object_not_found"}`))
+	}))
+	defer server.Close()
+
+	config := NewConfig("secret", "handbook")
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	_, err = client.Pages(context.Background())
+	assert.ErrorContains(t, err, "unexpected status code: 404")
+}
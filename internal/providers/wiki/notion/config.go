@@ -0,0 +1,78 @@
+package notion
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/massimo-ua/quill/internal/providers/transport"
+)
+
+var (
+	ErrMissingToken     = errors.New("integration token is required")
+	ErrMissingRootPages = errors.New("at least one root page is required")
+	ErrInvalidBaseURL   = errors.New("base URL must be an absolute http or https URL")
+)
+
+const (
+	// DefaultBaseURL is the address of the Notion API
+	DefaultBaseURL = "https://api.notion.com/v1"
+	// APIVersion is the version of the Notion API the client speaks
+	APIVersion = "2022-06-28"
+)
+
+// Config contains the settings of a Notion workspace to import pages from
+type Config struct {
+	// Token is the secret of an internal integration the root pages are shared with
+	Token string
+
+	// RootPageIDs are the pages imported with all the pages nested in them
+	RootPageIDs []string
+
+	// BaseURL is the API endpoint (optional, defaults to DefaultBaseURL)
+	BaseURL string
+
+	// HTTP configures the HTTP transport (optional)
+	HTTP *transport.Config
+}
+
+// NewConfig creates a new Notion configuration importing the root pages
+func NewConfig(token string, rootPageIDs ...string) *Config {
+	return &Config{
+		Token:       token,
+		RootPageIDs: rootPageIDs,
+		BaseURL:     DefaultBaseURL,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.Token) == "" {
+		return ErrMissingToken
+	}
+
+	roots := 0
+	for _, id := range c.RootPageIDs {
+		if strings.TrimSpace(id) != "" {
+			roots++
+		}
+	}
+	if roots == 0 {
+		return ErrMissingRootPages
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidBaseURL
+		}
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
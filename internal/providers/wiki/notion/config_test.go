@@ -0,0 +1,35 @@
+package notion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr error
+	}{
+		{name: "valid config", mutate: func(c *Config) {}},
+		{name: "missing token", mutate: func(c *Config) { c.Token = " " }, wantErr: ErrMissingToken},
+		{name: "missing root pages", mutate: func(c *Config) { c.RootPageIDs = nil }, wantErr: ErrMissingRootPages},
+		{name: "blank root page", mutate: func(c *Config) { c.RootPageIDs = []string{" "} }, wantErr: ErrMissingRootPages},
+		{name: "relative base URL", mutate: func(c *Config) { c.BaseURL = "api.notion.com" }, wantErr: ErrInvalidBaseURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig("secret", "handbook")
+			tt.mutate(config)
+
+			err := config.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package notion
+
+import (
+	"github.com/massimo-ua/quill/internal/domain/ports"
+)
+
+// Factory creates and configures Notion clients
+type Factory struct {
+	config *Config
+}
+
+// NewFactory creates a new Notion client factory
+func NewFactory(config *Config) *Factory {
+	return &Factory{
+		config: config,
+	}
+}
+
+// CreateWiki creates a new Notion client that implements the Wiki interface
+func (f *Factory) CreateWiki() (ports.Wiki, error) {
+	return NewClient(f.config)
+}
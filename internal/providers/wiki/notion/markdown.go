@@ -0,0 +1,243 @@
+package notion
+
+import (
+	"strings"
+)
+
+// markdown renders the blocks of a page as Markdown. Headings move one level down, as the page's title is the
+// document's first heading, and nested pages are left out, they become documents of their own.
+func markdown(blocks []block) string {
+	return strings.TrimSpace(renderBlocks(blocks, ""))
+}
+
+// renderBlocks renders sibling blocks, consecutive list items are kept together
+func renderBlocks(blocks []block, indent string) string {
+	var b strings.Builder
+	previousItem := false
+	for _, blk := range blocks {
+		rendered := renderBlock(blk, indent)
+		if rendered == "" {
+			continue
+		}
+		item := isListItem(blk)
+		if b.Len() > 0 {
+			if item && previousItem {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(rendered)
+		previousItem = item
+	}
+	return b.String()
+}
+
+// renderBlock renders a block with its children, an empty string for blocks that have no Markdown equivalent
+func renderBlock(blk block, indent string) string {
+	text := blk.text()
+	if text == nil {
+		text = &textBlock{}
+	}
+	var rendered string
+	switch blk.Type {
+	case "paragraph":
+		rendered = indent + renderText(text.RichText)
+	case "heading_1":
+		rendered = indent + "## " + renderText(text.RichText)
+	case "heading_2":
+		rendered = indent + "### " + renderText(text.RichText)
+	case "heading_3":
+		rendered = indent + "#### " + renderText(text.RichText)
+	case "bulleted_list_item", "toggle":
+		return listItem(indent, "- ", text.RichText, blk.children)
+	case "numbered_list_item":
+		return listItem(indent, "1. ", text.RichText, blk.children)
+	case "to_do":
+		marker := "- [ ] "
+		if text.Checked {
+			marker = "- [x] "
+		}
+		return listItem(indent, marker, text.RichText, blk.children)
+	case "quote", "callout":
+		return quoted(indent, renderText(text.RichText), renderBlocks(blk.children, ""))
+	case "code":
+		code := "```" + codeLanguage(text.Language) + "\n" + plainText(text.RichText) + "\n```"
+		rendered = indent + strings.ReplaceAll(code, "\n", "\n"+indent)
+	case "divider":
+		rendered = indent + "---"
+	case "image":
+		rendered = indent + renderImage(blk.Image)
+	case "bookmark":
+		if blk.Bookmark == nil || blk.Bookmark.URL == "" {
+			return ""
+		}
+		label := plainText(blk.Bookmark.Caption)
+		if label == "" {
+			label = blk.Bookmark.URL
+		}
+		rendered = indent + "[" + label + "](" + blk.Bookmark.URL + ")"
+	case "table":
+		return renderTable(blk, indent)
+	default:
+		// Nested pages become documents of their own, databases and embeds have no Markdown equivalent
+		return ""
+	}
+
+	children := renderBlocks(blk.children, indent)
+	switch {
+	case strings.TrimSpace(rendered) == "":
+		// Empty paragraphs are spacing in Notion, Markdown separates blocks with blank lines anyway
+		return children
+	case children != "":
+		return rendered + "\n\n" + children
+	}
+	return rendered
+}
+
+// listItem renders a list item, its children are nested in it
+func listItem(indent, marker string, text []richText, children []block) string {
+	rendered := indent + marker + renderText(text)
+	nested := renderBlocks(children, indent+strings.Repeat(" ", len(marker)))
+	switch {
+	case nested == "":
+	case isListItem(children[0]):
+		rendered += "\n" + nested
+	default:
+		// Paragraphs of an item are set apart, they would run on the item's text otherwise
+		rendered += "\n\n" + nested
+	}
+	return rendered
+}
+
+// quoted renders a block quote, with the blocks nested in it
+func quoted(indent, text, children string) string {
+	content := strings.TrimSpace(text + "\n\n" + children)
+	if content == "" {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(indent+"> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderImage renders an image, captioned with its alternative text
+func renderImage(image *fileBlock) string {
+	if image == nil {
+		return ""
+	}
+	var src string
+	switch {
+	case image.External != nil:
+		src = image.External.URL
+	case image.File != nil:
+		src = image.File.URL
+	}
+	if src == "" {
+		return ""
+	}
+	return "![" + plainText(image.Caption) + "](" + src + ")"
+}
+
+// renderTable renders a table block and its rows, the first row is the header of tables without one
+func renderTable(blk block, indent string) string {
+	var rows [][]string
+	for _, child := range blk.children {
+		if child.Type != "table_row" || child.TableRow == nil {
+			continue
+		}
+		cells := make([]string, len(child.TableRow.Cells))
+		for i, cell := range child.TableRow.Cells {
+			cells[i] = strings.ReplaceAll(renderText(cell), "|", `\|`)
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	var b strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + "|")
+		for col := 0; col < width; col++ {
+			cell := ""
+			if col < len(row) {
+				cell = row[col]
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		if i == 0 {
+			b.WriteString("\n" + indent + "|" + strings.Repeat(" --- |", width))
+		}
+	}
+	return b.String()
+}
+
+// renderText renders rich text with its formatting and links
+func renderText(runs []richText) string {
+	var b strings.Builder
+	for _, run := range runs {
+		text := run.PlainText
+		// Markers go around the words, Markdown ignores them next to spaces
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" {
+			b.WriteString(text)
+			continue
+		}
+		lead := text[:strings.Index(text, trimmed)]
+		trail := text[len(lead)+len(trimmed):]
+
+		if run.Annotations.Code {
+			trimmed = "`" + trimmed + "`"
+		}
+		if run.Annotations.Bold {
+			trimmed = "**" + trimmed + "**"
+		}
+		if run.Annotations.Italic {
+			trimmed = "_" + trimmed + "_"
+		}
+		if run.Annotations.Strikethrough {
+			trimmed = "~~" + trimmed + "~~"
+		}
+		if run.Href != "" {
+			trimmed = "[" + trimmed + "](" + run.Href + ")"
+		}
+		b.WriteString(lead + trimmed + trail)
+	}
+	return b.String()
+}
+
+// plainText returns the text of rich text without its formatting
+func plainText(runs []richText) string {
+	var b strings.Builder
+	for _, run := range runs {
+		b.WriteString(run.PlainText)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// codeLanguage returns the language of a code block as Markdown fences name it
+func codeLanguage(language string) string {
+	if language == "plain text" {
+		return ""
+	}
+	return strings.ReplaceAll(language, " ", "")
+}
+
+// isListItem checks if a block is rendered as an item of a list
+func isListItem(blk block) bool {
+	switch blk.Type {
+	case "bulleted_list_item", "numbered_list_item", "to_do", "toggle":
+		return true
+	}
+	return false
+}
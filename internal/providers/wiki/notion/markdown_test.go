@@ -0,0 +1,68 @@
+package notion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func plain(text string) []richText {
+	return []richText{{PlainText: text}}
+}
+
+func TestMarkdown(t *testing.T) {
+	blocks := []block{
+		{Type: "heading_1", Heading1: &textBlock{RichText: plain("Getting started")}},
+		{Type: "paragraph", Paragraph: &textBlock{RichText: []richText{
+			{PlainText: "Run "},
+			{PlainText: "make setup ", Annotations: annotations{Code: true}},
+			{PlainText: "first", Annotations: annotations{Bold: true}},
+			{PlainText: ", then read "},
+			{PlainText: "the guide", Href: "https://example.com/guide", Annotations: annotations{Italic: true}},
+			{PlainText: "."},
+		}}},
+		{Type: "paragraph", Paragraph: &textBlock{}},
+		{Type: "bulleted_list_item", BulletedListItem: &textBlock{RichText: plain("Laptop")}, children: []block{
+			{Type: "to_do", ToDo: &textBlock{RichText: plain("Install Go"), Checked: true}},
+			{Type: "to_do", ToDo: &textBlock{RichText: plain("Install Docker")}},
+		}},
+		{Type: "numbered_list_item", NumberedListItem: &textBlock{RichText: plain("Accounts")}},
+		{Type: "child_page", ChildPage: &titled{Title: "Laptop setup"}},
+		{Type: "code", Code: &textBlock{RichText: plain("make setup\nmake test"), Language: "shell"}},
+		{Type: "callout", Callout: &textBlock{RichText: plain("Ask in #help")}, children: []block{
+			{Type: "paragraph", Paragraph: &textBlock{RichText: plain("Someone answers within the hour.")}},
+		}},
+		{Type: "divider"},
+		{Type: "image", Image: &fileBlock{Type: "external", External: &fileURL{URL: "https://example.com/arch.png"}, Caption: plain("Architecture")}},
+		{Type: "bookmark", Bookmark: &linkBlock{URL: "https://go.dev"}},
+		{Type: "table", Table: &table{HasColumnHeader: true}, children: []block{
+			{Type: "table_row", TableRow: &tableRow{Cells: [][]richText{plain("Service"), plain("Owner")}}},
+			{Type: "table_row", TableRow: &tableRow{Cells: [][]richText{plain("billing"), plain("Payments | Core")}}},
+		}},
+		{Type: "child_database"},
+	}
+
+	want := "## Getting started\n\n" +
+		"Run `make setup` **first**, then read [_the guide_](https://example.com/guide).\n\n" +
+		"- Laptop\n" +
+		"  - [x] Install Go\n" +
+		"  - [ ] Install Docker\n" +
+		"1. Accounts\n\n" +
+		"```shell\nmake setup\nmake test\n```\n\n" +
+		"> Ask in #help\n>\n> Someone answers within the hour.\n\n" +
+		"---\n\n" +
+		"![Architecture](https://example.com/arch.png)\n\n" +
+		"[https://go.dev](https://go.dev)\n\n" +
+		"| Service | Owner |\n| --- | --- |\n| billing | Payments \\| Core |"
+	assert.Equal(t, want, markdown(blocks))
+}
+
+func TestChildPages(t *testing.T) {
+	blocks := []block{
+		{ID: "setup", Type: "child_page"},
+		{ID: "toggle", Type: "toggle", children: []block{{ID: "faq", Type: "child_page"}}},
+		{ID: "text", Type: "paragraph"},
+	}
+
+	assert.Equal(t, []string{"setup", "faq"}, childPages(blocks))
+}
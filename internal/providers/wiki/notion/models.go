@@ -0,0 +1,181 @@
+package notion
+
+import (
+	"sort"
+)
+
+// page is a Notion page, as the pages endpoint returns it
+type page struct {
+	ID             string              `json:"id"`
+	URL            string              `json:"url"`
+	CreatedTime    string              `json:"created_time"`
+	LastEditedTime string              `json:"last_edited_time"`
+	CreatedBy      user                `json:"created_by"`
+	Archived       bool                `json:"archived"`
+	InTrash        bool                `json:"in_trash"`
+	Properties     map[string]property `json:"properties"`
+}
+
+// property is a property of a page: the title of every page, and the properties of pages in a database
+type property struct {
+	Type        string     `json:"type"`
+	Title       []richText `json:"title"`
+	MultiSelect []option   `json:"multi_select"`
+}
+
+// option is a value of a select property
+type option struct {
+	Name string `json:"name"`
+}
+
+// user is a person or bot of the workspace, the name is only set when the integration can read user information
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// blockList is a page of the children of a block
+type blockList struct {
+	Results    []block `json:"results"`
+	NextCursor string  `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// block is a piece of a page's content, each type keeps its content in the field named after it
+type block struct {
+	ID               string     `json:"id"`
+	Type             string     `json:"type"`
+	HasChildren      bool       `json:"has_children"`
+	Paragraph        *textBlock `json:"paragraph,omitempty"`
+	Heading1         *textBlock `json:"heading_1,omitempty"`
+	Heading2         *textBlock `json:"heading_2,omitempty"`
+	Heading3         *textBlock `json:"heading_3,omitempty"`
+	BulletedListItem *textBlock `json:"bulleted_list_item,omitempty"`
+	NumberedListItem *textBlock `json:"numbered_list_item,omitempty"`
+	ToDo             *textBlock `json:"to_do,omitempty"`
+	Toggle           *textBlock `json:"toggle,omitempty"`
+	Quote            *textBlock `json:"quote,omitempty"`
+	Callout          *textBlock `json:"callout,omitempty"`
+	Code             *textBlock `json:"code,omitempty"`
+	Image            *fileBlock `json:"image,omitempty"`
+	Bookmark         *linkBlock `json:"bookmark,omitempty"`
+	ChildPage        *titled    `json:"child_page,omitempty"`
+	Table            *table     `json:"table,omitempty"`
+	TableRow         *tableRow  `json:"table_row,omitempty"`
+
+	// children are the nested blocks, read separately when HasChildren is set
+	children []block
+}
+
+// textBlock is the content of the blocks holding text
+type textBlock struct {
+	RichText []richText `json:"rich_text"`
+	Checked  bool       `json:"checked"`
+	Language string     `json:"language"`
+}
+
+// fileBlock is an image, hosted by Notion or elsewhere
+type fileBlock struct {
+	Type     string     `json:"type"`
+	External *fileURL   `json:"external,omitempty"`
+	File     *fileURL   `json:"file,omitempty"`
+	Caption  []richText `json:"caption"`
+}
+
+// fileURL is where a file can be downloaded, the URLs of files Notion hosts expire after an hour
+type fileURL struct {
+	URL string `json:"url"`
+}
+
+// linkBlock is a bookmark of a web page
+type linkBlock struct {
+	URL     string     `json:"url"`
+	Caption []richText `json:"caption"`
+}
+
+// titled is a block naming another page
+type titled struct {
+	Title string `json:"title"`
+}
+
+// table is a table block, its rows are its children
+type table struct {
+	HasColumnHeader bool `json:"has_column_header"`
+}
+
+// tableRow is a row of a table, with the text of each cell
+type tableRow struct {
+	Cells [][]richText `json:"cells"`
+}
+
+// richText is a run of text with the same formatting
+type richText struct {
+	PlainText   string      `json:"plain_text"`
+	Href        string      `json:"href"`
+	Annotations annotations `json:"annotations"`
+}
+
+// annotations is the formatting of a run of text
+type annotations struct {
+	Bold          bool `json:"bold"`
+	Italic        bool `json:"italic"`
+	Strikethrough bool `json:"strikethrough"`
+	Code          bool `json:"code"`
+}
+
+// text returns the content of a block holding text, nil for other blocks
+func (b block) text() *textBlock {
+	switch b.Type {
+	case "paragraph":
+		return b.Paragraph
+	case "heading_1":
+		return b.Heading1
+	case "heading_2":
+		return b.Heading2
+	case "heading_3":
+		return b.Heading3
+	case "bulleted_list_item":
+		return b.BulletedListItem
+	case "numbered_list_item":
+		return b.NumberedListItem
+	case "to_do":
+		return b.ToDo
+	case "toggle":
+		return b.Toggle
+	case "quote":
+		return b.Quote
+	case "callout":
+		return b.Callout
+	case "code":
+		return b.Code
+	}
+	return nil
+}
+
+// title returns the title of a page
+func (p page) title() string {
+	for _, prop := range p.Properties {
+		if prop.Type == "title" {
+			return plainText(prop.Title)
+		}
+	}
+	return ""
+}
+
+// labels returns the values of the multi-select properties of a page in a database, like its tags
+func (p page) labels() []string {
+	// Sorted by property so the labels keep their order when the page is imported again
+	names := make([]string, 0, len(p.Properties))
+	for name := range p.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var labels []string
+	for _, name := range names {
+		for _, opt := range p.Properties[name].MultiSelect {
+			labels = append(labels, opt.Name)
+		}
+	}
+	return labels
+}